@@ -14,11 +14,20 @@
 
 package alibabacloudlogserviceexporter
 
-import "go.opentelemetry.io/collector/config/configmodels"
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
 
 // Config defines configuration for AlibabaCloud Log Service exporter.
 type Config struct {
-	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	configmodels.ExporterSettings  `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
+
 	// LogService's Endpoint, https://www.alibabacloud.com/help/doc-detail/29008.htm
 	// for AlibabaCloud Kubernetes(or ECS), set {region-id}-intranet.log.aliyuncs.com, eg cn-hangzhou-intranet.log.aliyuncs.com;
 	//  others set {region-id}.log.aliyuncs.com, eg cn-hangzhou.log.aliyuncs.com
@@ -36,3 +45,16 @@ type Config struct {
 	// Set Token File Path if you are using ACK
 	TokenFilePath string `mapstructure:"token_file_path"`
 }
+
+func (c *Config) validate() error {
+	if c.Endpoint == "" || c.Project == "" || c.Logstore == "" {
+		return errors.New("missing logservice params: Endpoint, Project, Logstore")
+	}
+	if c.ECSRamRole != "" || c.TokenFilePath != "" {
+		return nil
+	}
+	if c.AccessKeyID == "" || c.AccessKeySecret == "" {
+		return errors.New("missing logservice credentials: AccessKeyID and AccessKeySecret, or ECSRamRole or TokenFilePath")
+	}
+	return nil
+}
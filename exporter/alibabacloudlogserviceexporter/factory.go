@@ -44,6 +44,9 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
+		TimeoutSettings: exporterhelper.DefaultTimeoutSettings(),
+		QueueSettings:   exporterhelper.DefaultQueueSettings(),
+		RetrySettings:   exporterhelper.DefaultRetrySettings(),
 	}
 }
 
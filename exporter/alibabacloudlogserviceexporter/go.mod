@@ -6,6 +6,7 @@ require (
 	github.com/aliyun/aliyun-log-go-sdk v0.1.18
 	github.com/census-instrumentation/opencensus-proto v0.3.0
 	github.com/gogo/protobuf v1.3.2
+	github.com/pierrec/lz4 v2.5.2+incompatible
 	github.com/stretchr/testify v1.6.1
 	go.opentelemetry.io/collector v0.18.0
 	go.uber.org/zap v1.16.0
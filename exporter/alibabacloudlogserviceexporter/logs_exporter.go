@@ -31,15 +31,22 @@ func newLogsExporter(logger *zap.Logger, cfg configmodels.Exporter) (component.L
 		logger: logger,
 	}
 
+	c := cfg.(*Config)
 	var err error
-	if l.client, err = NewLogServiceClient(cfg.(*Config), logger); err != nil {
+	if l.client, err = NewLogServiceClient(c, logger); err != nil {
 		return nil, err
 	}
 
 	return exporterhelper.NewLogsExporter(
 		cfg,
 		logger,
-		l.pushLogsData)
+		l.pushLogsData,
+		exporterhelper.WithTimeout(c.TimeoutSettings),
+		exporterhelper.WithRetry(c.RetrySettings),
+		exporterhelper.WithQueue(c.QueueSettings),
+		exporterhelper.WithShutdown(func(context.Context) error {
+			return l.client.Close()
+		}))
 }
 
 type logServiceLogsSender struct {
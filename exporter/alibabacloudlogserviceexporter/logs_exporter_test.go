@@ -16,12 +16,14 @@ package alibabacloudlogserviceexporter
 
 import (
 	"context"
+	"net/http"
 	"path"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
@@ -53,19 +55,24 @@ func createSimpleLogData(numberOfLogs int) pdata.Logs {
 }
 
 func TestNewLogsExporter(t *testing.T) {
-	got, err := newLogsExporter(zap.NewNop(), &Config{
-		Endpoint: "us-west-1.log.aliyuncs.com",
-		Project:  "demo-project",
-		Logstore: "demo-logstore",
-	})
+	capture := newLogServiceCapture(t, http.StatusOK, "")
+	got, err := newLogsExporter(zap.NewNop(), capture.config())
 	assert.NoError(t, err)
 	require.NotNil(t, got)
 
-	// This will put trace data to send buffer and return success.
 	err = got.ConsumeLogs(context.Background(), createSimpleLogData(3))
-	// a
 	assert.NoError(t, err)
-	time.Sleep(time.Second * 4)
+	assert.Equal(t, 3, capture.logCount())
+	assert.NoError(t, got.Shutdown(context.Background()))
+}
+
+func TestNewLogsExporterPermanentError(t *testing.T) {
+	capture := newLogServiceCapture(t, http.StatusNotFound, `{"errorCode": "ProjectNotExist", "errorMessage": "project does not exist"}`)
+	got, err := newLogsExporter(zap.NewNop(), capture.config())
+	require.NoError(t, err)
+
+	err = got.ConsumeLogs(context.Background(), createSimpleLogData(3))
+	assert.True(t, consumererror.IsPermanent(err))
 }
 
 func TestSTSTokenExporter(t *testing.T) {
@@ -75,8 +82,8 @@ func TestSTSTokenExporter(t *testing.T) {
 		Logstore:      "demo-logstore",
 		TokenFilePath: path.Join(".", "testdata", "config.yaml"),
 	})
-	assert.NoError(t, err)
-	require.NotNil(t, got)
+	assert.Error(t, err)
+	require.Nil(t, got)
 }
 
 func TestNewFailsWithEmptyLogsExporterName(t *testing.T) {
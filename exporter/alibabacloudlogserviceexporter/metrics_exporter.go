@@ -17,6 +17,7 @@ package alibabacloudlogserviceexporter
 import (
 	"context"
 
+	sls "github.com/aliyun/aliyun-log-go-sdk"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/pdata"
@@ -32,15 +33,22 @@ func newMetricsExporter(logger *zap.Logger, cfg configmodels.Exporter) (componen
 		logger: logger,
 	}
 
+	c := cfg.(*Config)
 	var err error
-	if l.client, err = NewLogServiceClient(cfg.(*Config), logger); err != nil {
+	if l.client, err = NewLogServiceClient(c, logger); err != nil {
 		return nil, err
 	}
 
 	return exporterhelper.NewMetricsExporter(
 		cfg,
 		logger,
-		l.pushMetricsData)
+		l.pushMetricsData,
+		exporterhelper.WithTimeout(c.TimeoutSettings),
+		exporterhelper.WithRetry(c.RetrySettings),
+		exporterhelper.WithQueue(c.QueueSettings),
+		exporterhelper.WithShutdown(func(context.Context) error {
+			return l.client.Close()
+		}))
 }
 
 type logServiceMetricsSender struct {
@@ -52,13 +60,15 @@ func (s *logServiceMetricsSender) pushMetricsData(
 	_ context.Context,
 	md pdata.Metrics,
 ) (droppedTimeSeries int, err error) {
+	var slsLogs []*sls.Log
 	ocmds := internaldata.MetricsToOC(md)
 	for _, ocmd := range ocmds {
 		logs, dts := metricsDataToLogServiceData(s.logger, ocmd)
-		if len(logs) > 0 {
-			err = s.client.SendLogs(logs)
-		}
+		slsLogs = append(slsLogs, logs...)
 		droppedTimeSeries += dts
 	}
+	if len(slsLogs) > 0 {
+		err = s.client.SendLogs(slsLogs)
+	}
 	return droppedTimeSeries, err
 }
@@ -16,6 +16,7 @@ package alibabacloudlogserviceexporter
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -29,11 +30,8 @@ import (
 )
 
 func TestNewMetricsExporter(t *testing.T) {
-	got, err := newMetricsExporter(zap.NewNop(), &Config{
-		Endpoint: "us-west-1.log.aliyuncs.com",
-		Project:  "demo-project",
-		Logstore: "demo-logstore",
-	})
+	capture := newLogServiceCapture(t, http.StatusOK, "")
+	got, err := newMetricsExporter(zap.NewNop(), capture.config())
 	assert.NoError(t, err)
 	require.NotNil(t, got)
 
@@ -44,14 +42,13 @@ func TestNewMetricsExporter(t *testing.T) {
 	doubleVal := 1234.5678
 	doublePt := metricstestutil.Double(tsUnix, doubleVal)
 
-	// This will put trace data to send buffer and return success.
 	err = got.ConsumeMetrics(context.Background(), internaldata.OCToMetrics(consumerdata.MetricsData{
 		Metrics: []*metricspb.Metric{
 			metricstestutil.Gauge("gauge_double_with_dims", nil, metricstestutil.Timeseries(tsUnix, nil, doublePt)),
 		},
 	}))
-	// a
 	assert.NoError(t, err)
+	assert.Equal(t, 1, capture.logCount())
 }
 
 func TestNewFailsWithEmptyMetricsExporterName(t *testing.T) {
@@ -16,6 +16,7 @@ package alibabacloudlogserviceexporter
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,11 +27,8 @@ import (
 
 func TestNewTraceExporter(t *testing.T) {
 
-	got, err := newTraceExporter(zap.NewNop(), &Config{
-		Endpoint: "cn-hangzhou.log.aliyuncs.com",
-		Project:  "demo-project",
-		Logstore: "demo-logstore",
-	})
+	capture := newLogServiceCapture(t, http.StatusOK, "")
+	got, err := newTraceExporter(zap.NewNop(), capture.config())
 	assert.NoError(t, err)
 	require.NotNil(t, got)
 
@@ -41,9 +39,9 @@ func TestNewTraceExporter(t *testing.T) {
 	ils := rs.InstrumentationLibrarySpans().At(0)
 	ils.Spans().Resize(1)
 
-	// This will put trace data to send buffer and return success.
 	err = got.ConsumeTraces(context.Background(), traces)
 	assert.NoError(t, err)
+	assert.Equal(t, 1, capture.logCount())
 	assert.Nil(t, got.Shutdown(context.Background()))
 }
 
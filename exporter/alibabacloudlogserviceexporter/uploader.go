@@ -16,28 +16,47 @@ package alibabacloudlogserviceexporter
 
 import (
 	"errors"
+	"fmt"
 	"net"
+	"net/http"
 	"os"
 
 	sls "github.com/aliyun/aliyun-log-go-sdk"
-	"github.com/aliyun/aliyun-log-go-sdk/producer"
 	slsutil "github.com/aliyun/aliyun-log-go-sdk/util"
+	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.uber.org/zap"
 )
 
+const (
+	// maxLogGroupSize and maxLogGroupCount are the limits of LogService on the LogGroup of a PutLogs request.
+	maxLogGroupSize  = 3 * 1024 * 1024
+	maxLogGroupCount = 4096
+)
+
+// retryableErrorCodes are the LogService error codes returned with a 403 status when the write quota of the project
+// or of a shard is exceeded, on which the request is retried.
+var retryableErrorCodes = map[string]bool{
+	"WriteQuotaExceed":      true,
+	"ShardWriteQuotaExceed": true,
+}
+
 // LogServiceClient log Service's client wrapper
 type LogServiceClient interface {
 	// SendLogs send message to LogService
 	SendLogs(logs []*sls.Log) error
+	// Close stops the client
+	Close() error
 }
 
 type logServiceClientImpl struct {
-	clientInstance *producer.Producer
+	clientInstance sls.ClientInterface
 	project        string
 	logstore       string
 	topic          string
 	source         string
 	logger         *zap.Logger
+	tokenShutdown  chan struct{}
 }
 
 func getIPAddress() (ipAddress string, err error) {
@@ -54,27 +73,35 @@ func getIPAddress() (ipAddress string, err error) {
 
 // NewLogServiceClient Create Log Service client
 func NewLogServiceClient(config *Config, logger *zap.Logger) (LogServiceClient, error) {
-	if config == nil || config.Endpoint == "" || config.Project == "" || config.Logstore == "" {
+	if config == nil {
 		return nil, errors.New("missing logservice params: Endpoint, Project, Logstore")
 	}
-
-	producerConfig := producer.GetDefaultProducerConfig()
-	producerConfig.Endpoint = config.Endpoint
-	producerConfig.AccessKeyID = config.AccessKeyID
-	producerConfig.AccessKeySecret = config.AccessKeySecret
-	if config.ECSRamRole != "" || config.TokenFilePath != "" {
-		tokenUpdateFunc, _ := slsutil.NewTokenUpdateFunc(config.ECSRamRole, config.TokenFilePath)
-		producerConfig.UpdateStsToken = tokenUpdateFunc
-		producerConfig.StsTokenShutDown = make(chan struct{})
+	if err := config.validate(); err != nil {
+		return nil, err
 	}
 
 	c := &logServiceClientImpl{
-		project:        config.Project,
-		logstore:       config.Logstore,
-		clientInstance: producer.InitProducer(producerConfig),
-		logger:         logger,
+		project:  config.Project,
+		logstore: config.Logstore,
+		logger:   logger,
+	}
+	if config.ECSRamRole != "" || config.TokenFilePath != "" {
+		tokenUpdateFunc, _ := slsutil.NewTokenUpdateFunc(config.ECSRamRole, config.TokenFilePath)
+		c.tokenShutdown = make(chan struct{})
+		client, err := sls.CreateTokenAutoUpdateClient(config.Endpoint, tokenUpdateFunc, c.tokenShutdown)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the logservice STS token: %w", err)
+		}
+		c.clientInstance = client
+	} else {
+		c.clientInstance = &sls.Client{
+			Endpoint:        config.Endpoint,
+			AccessKeyID:     config.AccessKeyID,
+			AccessKeySecret: config.AccessKeySecret,
+			RequestTimeOut:  config.Timeout,
+			RetryTimeOut:    config.Timeout,
+		}
 	}
-	c.clientInstance.Start()
 	// do not return error if get hostname or ip address fail
 	c.topic, _ = os.Hostname()
 	c.source, _ = getIPAddress()
@@ -82,20 +109,72 @@ func NewLogServiceClient(config *Config, logger *zap.Logger) (LogServiceClient,
 	return c, nil
 }
 
-// SendLogs send message to LogService
+// SendLogs send message to LogService, in as many LogGroups as required by the limits of LogService.
+// Sending stops at the first LogGroup which fails, the whole logs being sent again when the error is retried.
 func (c *logServiceClientImpl) SendLogs(logs []*sls.Log) error {
-	return c.clientInstance.SendLogListWithCallBack(c.project, c.logstore, c.topic, c.source, logs, c)
+	groupSize := (&sls.LogGroup{Topic: proto.String(c.topic), Source: proto.String(c.source)}).Size()
+	group := make([]*sls.Log, 0, len(logs))
+	size := groupSize
+	for _, log := range logs {
+		logSize := log.Size()
+		logSize += 1 + proto.SizeVarint(uint64(logSize))
+		if groupSize+logSize > maxLogGroupSize {
+			c.logger.Warn("Drop log larger than the LogService limit", zap.Int("size", logSize))
+			continue
+		}
+		if len(group) == maxLogGroupCount || size+logSize > maxLogGroupSize {
+			if err := c.putLogs(group); err != nil {
+				return err
+			}
+			group = make([]*sls.Log, 0, len(logs))
+			size = groupSize
+		}
+		group = append(group, log)
+		size += logSize
+	}
+	if len(group) == 0 {
+		return nil
+	}
+	return c.putLogs(group)
 }
 
-// Success is impl of producer.CallBack
-func (c *logServiceClientImpl) Success(*producer.Result) {}
-
-// Fail is impl of producer.CallBack
-func (c *logServiceClientImpl) Fail(result *producer.Result) {
+func (c *logServiceClientImpl) putLogs(logs []*sls.Log) error {
+	err := c.clientInstance.PutLogs(c.project, c.logstore, &sls.LogGroup{
+		Topic:  proto.String(c.topic),
+		Source: proto.String(c.source),
+		Logs:   logs,
+	})
+	if err == nil {
+		return nil
+	}
 	c.logger.Warn("Send to LogService failed",
 		zap.String("project", c.project),
 		zap.String("store", c.logstore),
-		zap.String("code", result.GetErrorCode()),
-		zap.String("error_message", result.GetErrorMessage()),
-		zap.String("request_id", result.GetRequestId()))
+		zap.Error(err))
+	return classifyError(err)
+}
+
+// Close stops the refresh of the STS token.
+func (c *logServiceClientImpl) Close() error {
+	if c.tokenShutdown != nil {
+		close(c.tokenShutdown)
+	}
+	return c.clientInstance.Close()
+}
+
+// classifyError marks the errors which aren't worth retrying as permanent: the errors of the client and of the network,
+// the throttling and the server errors can be retried, the other errors returned by LogService can't.
+func classifyError(err error) error {
+	slsErr, ok := err.(*sls.Error)
+	if !ok {
+		return err
+	}
+	switch {
+	case slsErr.HTTPCode == -1,
+		slsErr.HTTPCode == http.StatusTooManyRequests,
+		slsErr.HTTPCode >= http.StatusInternalServerError,
+		retryableErrorCodes[slsErr.Code]:
+		return err
+	}
+	return consumererror.Permanent(err)
 }
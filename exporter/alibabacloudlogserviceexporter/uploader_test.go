@@ -0,0 +1,288 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabacloudlogserviceexporter
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pierrec/lz4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+// logServiceCapture is a fake LogService endpoint recording the LogGroups put in the logstores.
+type logServiceCapture struct {
+	sync.Mutex
+	server     *httptest.Server
+	status     int
+	body       string
+	logstores  []string
+	logGroups  []*sls.LogGroup
+	accessKeys []string
+}
+
+func newLogServiceCapture(t *testing.T, status int, body string) *logServiceCapture {
+	c := &logServiceCapture{status: status, body: body}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		if r.Header.Get("x-log-compresstype") == "lz4" {
+			rawSize, err := strconv.Atoi(r.Header.Get("x-log-bodyrawsize"))
+			require.NoError(t, err)
+			raw := make([]byte, rawSize)
+			_, err = lz4.UncompressBlock(data, raw)
+			require.NoError(t, err)
+			data = raw
+		}
+		logGroup := &sls.LogGroup{}
+		require.NoError(t, proto.Unmarshal(data, logGroup))
+
+		c.Lock()
+		c.logstores = append(c.logstores, r.URL.Path)
+		c.logGroups = append(c.logGroups, logGroup)
+		c.accessKeys = append(c.accessKeys, strings.SplitN(strings.TrimPrefix(r.Header.Get("Authorization"), "SLS "), ":", 2)[0])
+		c.Unlock()
+
+		w.WriteHeader(c.status)
+		_, _ = w.Write([]byte(c.body))
+	}))
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+func (c *logServiceCapture) config() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = c.server.URL
+	cfg.Project = "demo-project"
+	cfg.Logstore = "demo-logstore"
+	cfg.AccessKeyID = "test-id"
+	cfg.AccessKeySecret = "test-secret"
+	// Send the requests synchronously, with their errors being returned.
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+	return cfg
+}
+
+func (c *logServiceCapture) logCount() int {
+	c.Lock()
+	defer c.Unlock()
+	count := 0
+	for _, logGroup := range c.logGroups {
+		count += len(logGroup.Logs)
+	}
+	return count
+}
+
+func newTestLog(contentSize int) *sls.Log {
+	return &sls.Log{
+		Time: proto.Uint32(1574092046),
+		Contents: []*sls.LogContent{
+			{Key: proto.String("content"), Value: proto.String(strings.Repeat("a", contentSize))},
+		},
+	}
+}
+
+func TestSendLogs(t *testing.T) {
+	capture := newLogServiceCapture(t, http.StatusOK, "")
+	client, err := NewLogServiceClient(capture.config(), zap.NewNop())
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.SendLogs([]*sls.Log{newTestLog(10), newTestLog(20)}))
+
+	require.Len(t, capture.logGroups, 1)
+	assert.Equal(t, []string{"/logstores/demo-logstore"}, capture.logstores)
+	assert.Equal(t, []string{"test-id"}, capture.accessKeys)
+	logGroup := capture.logGroups[0]
+	require.Len(t, logGroup.Logs, 2)
+	assert.Equal(t, strings.Repeat("a", 20), logGroup.Logs[1].Contents[0].GetValue())
+	hostname, _ := os.Hostname()
+	assert.Equal(t, hostname, logGroup.GetTopic())
+}
+
+func TestSendLogsSplitsLogGroups(t *testing.T) {
+	tests := []struct {
+		name        string
+		logs        []*sls.Log
+		groupCounts []int
+	}{
+		{
+			name:        "count",
+			logs:        repeatLog(newTestLog(1), maxLogGroupCount*2+1),
+			groupCounts: []int{maxLogGroupCount, maxLogGroupCount, 1},
+		},
+		{
+			name:        "size",
+			logs:        repeatLog(newTestLog(1024*1024), 7),
+			groupCounts: []int{2, 2, 2, 1},
+		},
+		{
+			name:        "too large",
+			logs:        []*sls.Log{newTestLog(10), newTestLog(maxLogGroupSize), newTestLog(10)},
+			groupCounts: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := newLogServiceCapture(t, http.StatusOK, "")
+			client, err := NewLogServiceClient(capture.config(), zap.NewNop())
+			require.NoError(t, err)
+			defer client.Close()
+
+			require.NoError(t, client.SendLogs(tt.logs))
+
+			var groupCounts []int
+			for _, logGroup := range capture.logGroups {
+				assert.LessOrEqual(t, logGroup.Size(), maxLogGroupSize)
+				groupCounts = append(groupCounts, len(logGroup.Logs))
+			}
+			assert.Equal(t, tt.groupCounts, groupCounts)
+		})
+	}
+}
+
+func repeatLog(log *sls.Log, count int) []*sls.Log {
+	logs := make([]*sls.Log, count)
+	for i := range logs {
+		logs[i] = log
+	}
+	return logs
+}
+
+func TestSendLogsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		permanent bool
+	}{
+		{
+			name:      "unauthorized",
+			status:    http.StatusUnauthorized,
+			body:      `{"errorCode": "Unauthorized", "errorMessage": "denied"}`,
+			permanent: true,
+		},
+		{
+			name:      "missing logstore",
+			status:    http.StatusNotFound,
+			body:      `{"errorCode": "LogStoreNotExist", "errorMessage": "logstore demo-logstore does not exist"}`,
+			permanent: true,
+		},
+		{
+			name:   "write quota exceeded",
+			status: http.StatusForbidden,
+			body:   `{"errorCode": "WriteQuotaExceed", "errorMessage": "write quota is exceeded"}`,
+		},
+		{
+			name:   "throttled",
+			status: http.StatusTooManyRequests,
+			body:   "too many requests",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := newLogServiceCapture(t, tt.status, tt.body)
+			client, err := NewLogServiceClient(capture.config(), zap.NewNop())
+			require.NoError(t, err)
+			defer client.Close()
+
+			err = client.SendLogs([]*sls.Log{newTestLog(10)})
+			require.Error(t, err)
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{
+			name: "client error",
+			err:  sls.NewClientError(errors.New("connection refused")),
+		},
+		{
+			name: "server error",
+			err:  &sls.Error{HTTPCode: http.StatusServiceUnavailable, Code: "ServerBusy"},
+		},
+		{
+			name: "shard write quota exceeded",
+			err:  &sls.Error{HTTPCode: http.StatusForbidden, Code: "ShardWriteQuotaExceed"},
+		},
+		{
+			name:      "post body too large",
+			err:       &sls.Error{HTTPCode: http.StatusRequestEntityTooLarge, Code: "PostBodyTooLarge"},
+			permanent: true,
+		},
+		{
+			name: "other error",
+			err:  errors.New("unexpected"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+func TestNewLogServiceClientErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "missing logstore",
+			modify: func(cfg *Config) { cfg.Logstore = "" },
+			err:    "missing logservice params: Endpoint, Project, Logstore",
+		},
+		{
+			name:   "missing access key secret",
+			modify: func(cfg *Config) { cfg.AccessKeySecret = "" },
+			err:    "missing logservice credentials: AccessKeyID and AccessKeySecret, or ECSRamRole or TokenFilePath",
+		},
+		{
+			name:   "invalid token file",
+			modify: func(cfg *Config) { cfg.TokenFilePath = path.Join(".", "testdata", "config.yaml") },
+			err:    "failed to get the logservice STS token: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := (&logServiceCapture{server: &httptest.Server{URL: "cn-hangzhou.log.aliyuncs.com"}}).config()
+			tt.modify(cfg)
+			_, err := NewLogServiceClient(cfg, zap.NewNop())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
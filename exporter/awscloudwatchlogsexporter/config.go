@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// validRetentionDays are the retention periods, in days, accepted by PutRetentionPolicy.
+var validRetentionDays = []int64{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1827, 3653}
+
+// Config defines configuration for the AWS CloudWatch Logs exporter.
+type Config struct {
+	configmodels.ExporterSettings  `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
+
+	AWS AWSSettings `mapstructure:"aws"`
+
+	// LogGroupName is the name of the log group the events are put to. The {attribute} placeholders are replaced
+	// with the values of the resource attributes of the log records, e.g. /apps/{k8s.namespace.name}.
+	LogGroupName string `mapstructure:"log_group_name"`
+	// LogStreamName is the name of the log stream the events are put to, with the same placeholders as the log group.
+	LogStreamName string `mapstructure:"log_stream_name"`
+	// LogRetention is the number of days the events of the log groups created by the exporter are retained. The
+	// events never expire when not set.
+	LogRetention int64 `mapstructure:"log_retention"`
+}
+
+// AWSSettings defines the region of the log groups, and how to authenticate to it.
+type AWSSettings struct {
+	// Region is the region of the log groups. The region is read from the environment when not set.
+	Region string `mapstructure:"region"`
+	// Endpoint overrides the endpoint of the CloudWatch Logs API, e.g. for a VPC endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+	// RoleARN is the ARN of a role to assume, the credentials of the default chain being used when not set.
+	RoleARN string `mapstructure:"role_arn"`
+}
+
+func (cfg *Config) validate() error {
+	if cfg.LogGroupName == "" {
+		return fmt.Errorf("log_group_name must be specified")
+	}
+	if cfg.LogStreamName == "" {
+		return fmt.Errorf("log_stream_name must be specified")
+	}
+	if cfg.LogRetention != 0 && !isValidRetention(cfg.LogRetention) {
+		return fmt.Errorf("invalid log_retention %d, must be one of %v", cfg.LogRetention, validRetentionDays)
+	}
+	return nil
+}
+
+func isValidRetention(days int64) bool {
+	for _, d := range validRetentionDays {
+		if d == days {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[configmodels.Type(typeStr)] = factory
+	cfg, err := configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), factories,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, len(cfg.Exporters), 2)
+
+	r0 := cfg.Exporters["awscloudwatchlogs"]
+	assert.Equal(t, r0, factory.CreateDefaultConfig())
+
+	r1 := cfg.Exporters["awscloudwatchlogs/customname"].(*Config)
+	queueSettings := exporterhelper.DefaultQueueSettings()
+	queueSettings.QueueSize = 1000
+	assert.Equal(t, r1, &Config{
+		ExporterSettings: configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "awscloudwatchlogs/customname"},
+		TimeoutSettings:  exporterhelper.TimeoutSettings{Timeout: 10 * time.Second},
+		QueueSettings:    queueSettings,
+		RetrySettings: exporterhelper.RetrySettings{
+			Enabled:         true,
+			InitialInterval: 5 * time.Second,
+			MaxInterval:     30 * time.Second,
+			MaxElapsedTime:  time.Minute,
+		},
+		AWS: AWSSettings{
+			Region:   "us-east-1",
+			Endpoint: "https://logs.vpce.example.com",
+			RoleARN:  "arn:aws:iam::123456789012:role/cloudwatch-logs-writer",
+		},
+		LogGroupName:  "/apps/{k8s.namespace.name}",
+		LogStreamName: "{k8s.pod.name}",
+		LogRetention:  30,
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "missing log group name",
+			modify: func(cfg *Config) { cfg.LogGroupName = "" },
+			err:    "log_group_name must be specified",
+		},
+		{
+			name:   "missing log stream name",
+			modify: func(cfg *Config) { cfg.LogStreamName = "" },
+			err:    "log_stream_name must be specified",
+		},
+		{
+			name:   "invalid retention",
+			modify: func(cfg *Config) { cfg.LogRetention = 10 },
+			err:    "invalid log_retention 10, must be one of [1 3 5 7 14 30 60 90 120 150 180 365 400 545 731 1827 3653]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.LogGroupName = "test-group"
+			cfg.LogStreamName = "test-stream"
+			tt.modify(cfg)
+			assert.EqualError(t, cfg.validate(), tt.err)
+		})
+	}
+}
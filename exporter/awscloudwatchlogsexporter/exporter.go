@@ -0,0 +1,274 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// eventHeaderSize is the size added to the message of each event to compute the size of a request.
+	eventHeaderSize = 26
+	// maxEventSize is the maximum size of an event, including its header.
+	maxEventSize = 256 << 10
+	// maxRequestSize is the maximum size of the events of a PutLogEvents request.
+	maxRequestSize = 1 << 20
+	// maxRequestEvents is the maximum number of events of a PutLogEvents request.
+	maxRequestEvents = 10000
+	// maxRequestSpan is the maximum duration between the first and the last events of a PutLogEvents request.
+	maxRequestSpan = 24 * time.Hour
+
+	// unknownPlaceholderValue replaces the placeholders of the resource attributes which aren't set.
+	unknownPlaceholderValue = "undefined"
+)
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+	errEmptyMessage = errors.New("the log record has no body")
+)
+
+// cloudWatchLogsAPI is the part of the CloudWatch Logs API used by the exporter.
+type cloudWatchLogsAPI interface {
+	PutLogEventsWithContext(ctx aws.Context, input *cloudwatchlogs.PutLogEventsInput, opts ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error)
+	CreateLogGroupWithContext(ctx aws.Context, input *cloudwatchlogs.CreateLogGroupInput, opts ...request.Option) (*cloudwatchlogs.CreateLogGroupOutput, error)
+	CreateLogStreamWithContext(ctx aws.Context, input *cloudwatchlogs.CreateLogStreamInput, opts ...request.Option) (*cloudwatchlogs.CreateLogStreamOutput, error)
+	PutRetentionPolicyWithContext(ctx aws.Context, input *cloudwatchlogs.PutRetentionPolicyInput, opts ...request.Option) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
+}
+
+// cloudWatchLogsExporter puts the log records as events of CloudWatch Logs streams.
+type cloudWatchLogsExporter struct {
+	client        cloudWatchLogsAPI
+	logGroupName  string
+	logStreamName string
+	logRetention  int64
+	streams       *streams
+	logger        *zap.Logger
+}
+
+// event is an event to put, along with the log record it was built from to be retried.
+type event struct {
+	input *cloudwatchlogs.InputLogEvent
+	// resource and library are the indexes of the resource and the instrumentation library of the record.
+	resource, library int
+	record            pdata.LogRecord
+}
+
+func newExporter(cfg *Config, logger *zap.Logger) (*cloudWatchLogsExporter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	awsConfig := aws.NewConfig()
+	if cfg.AWS.Region != "" {
+		awsConfig.WithRegion(cfg.AWS.Region)
+	}
+	if cfg.AWS.Endpoint != "" {
+		awsConfig.WithEndpoint(cfg.AWS.Endpoint)
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AWS.RoleARN != "" {
+		awsConfig = awsConfig.Copy().WithCredentials(stscreds.NewCredentials(sess, cfg.AWS.RoleARN))
+	}
+
+	return &cloudWatchLogsExporter{
+		client:        cloudwatchlogs.New(sess, awsConfig),
+		logGroupName:  cfg.LogGroupName,
+		logStreamName: cfg.LogStreamName,
+		logRetention:  cfg.LogRetention,
+		streams:       newStreams(),
+		logger:        logger,
+	}, nil
+}
+
+// pushLogs puts the log records to their streams, in as few requests as the limits of PutLogEvents allow. The
+// records of the requests which failed and can be retried are returned in a partial error.
+func (e *cloudWatchLogsExporter) pushLogs(ctx context.Context, ld pdata.Logs) (int, error) {
+	events, dropped := e.events(ld)
+
+	var errs []error
+	seenErrs := map[string]bool{}
+	var failed []event
+	for _, key := range sortedStreamKeys(events) {
+		streamEvents := events[key]
+		sort.SliceStable(streamEvents, func(i, j int) bool {
+			return *streamEvents[i].input.Timestamp < *streamEvents[j].input.Timestamp
+		})
+		for _, batch := range requestEvents(streamEvents) {
+			err := e.putLogEvents(ctx, key, batch)
+			if err == nil {
+				continue
+			}
+			if !seenErrs[err.Error()] {
+				seenErrs[err.Error()] = true
+				errs = append(errs, err)
+			}
+			if consumererror.IsPermanent(err) {
+				dropped += len(batch)
+			} else {
+				failed = append(failed, batch...)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return dropped, nil
+	}
+	err := componenterror.CombineErrors(errs)
+	if len(failed) == 0 {
+		return dropped, consumererror.Permanent(err)
+	}
+	return dropped + len(failed), consumererror.PartialLogsError(err, failedLogs(ld, failed))
+}
+
+// events returns the events of the log records, by stream, along with the number of records dropped because they
+// have no message.
+func (e *cloudWatchLogsExporter) events(ld pdata.Logs) (map[streamKey][]event, int) {
+	events := map[streamKey][]event{}
+	dropped := 0
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		attrs := rl.Resource().Attributes()
+		key := streamKey{
+			group:  expandName(e.logGroupName, attrs),
+			stream: expandName(e.logStreamName, attrs),
+		}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				record := logs.At(k)
+				input, err := inputLogEvent(record)
+				if err != nil {
+					e.logger.Debug("Dropping log record", zap.Error(err))
+					dropped++
+					continue
+				}
+				events[key] = append(events[key], event{input: input, resource: i, library: j, record: record})
+			}
+		}
+	}
+	return events, dropped
+}
+
+// inputLogEvent returns the event of the log record, whose message is the body of the record, truncated to the
+// maximum size of an event. The records without timestamp are timestamped with the current time.
+func inputLogEvent(record pdata.LogRecord) (*cloudwatchlogs.InputLogEvent, error) {
+	message := tracetranslator.AttributeValueToString(record.Body(), false)
+	if message == "" {
+		return nil, errEmptyMessage
+	}
+	if len(message) > maxEventSize-eventHeaderSize {
+		message = truncate(message, maxEventSize-eventHeaderSize)
+	}
+
+	timestamp := time.Now()
+	if record.Timestamp() != 0 {
+		timestamp = time.Unix(0, int64(record.Timestamp()))
+	}
+	return &cloudwatchlogs.InputLogEvent{
+		Message:   aws.String(message),
+		Timestamp: aws.Int64(timestamp.UnixNano() / int64(time.Millisecond)),
+	}, nil
+}
+
+// truncate truncates s to at most size bytes, without splitting a rune.
+func truncate(s string, size int) string {
+	for size > 0 && !utf8.RuneStart(s[size]) {
+		size--
+	}
+	return s[:size]
+}
+
+// expandName replaces the placeholders of the name with the values of the resource attributes, or with
+// "undefined" for the attributes which aren't set.
+func expandName(name string, attrs pdata.AttributeMap) string {
+	return placeholderPattern.ReplaceAllStringFunc(name, func(placeholder string) string {
+		if v, ok := attrs.Get(placeholder[1 : len(placeholder)-1]); ok {
+			if s := tracetranslator.AttributeValueToString(v, false); s != "" {
+				return s
+			}
+		}
+		return unknownPlaceholderValue
+	})
+}
+
+// requestEvents splits the events, sorted by timestamp, in batches fitting in the limits of PutLogEvents.
+func requestEvents(events []event) [][]event {
+	var batches [][]event
+	start, size := 0, 0
+	for i, ev := range events {
+		evSize := len(*ev.input.Message) + eventHeaderSize
+		span := time.Duration(*ev.input.Timestamp-*events[start].input.Timestamp) * time.Millisecond
+		if i > start && (i-start == maxRequestEvents || size+evSize > maxRequestSize || span >= maxRequestSpan) {
+			batches = append(batches, events[start:i])
+			start, size = i, 0
+		}
+		size += evSize
+	}
+	if start < len(events) {
+		batches = append(batches, events[start:])
+	}
+	return batches
+}
+
+// failedLogs returns the log records of the events, under copies of their resources and instrumentation libraries.
+func failedLogs(ld pdata.Logs, events []event) pdata.Logs {
+	failed := pdata.NewLogs()
+	resources := map[int]pdata.ResourceLogs{}
+	libraries := map[[2]int]pdata.LogSlice{}
+	for _, ev := range events {
+		logs, ok := libraries[[2]int{ev.resource, ev.library}]
+		if !ok {
+			rl, ok := resources[ev.resource]
+			if !ok {
+				rls := failed.ResourceLogs()
+				rls.Resize(rls.Len() + 1)
+				rl = rls.At(rls.Len() - 1)
+				ld.ResourceLogs().At(ev.resource).Resource().CopyTo(rl.Resource())
+				resources[ev.resource] = rl
+			}
+			ills := rl.InstrumentationLibraryLogs()
+			ills.Resize(ills.Len() + 1)
+			ill := ills.At(ills.Len() - 1)
+			ld.ResourceLogs().At(ev.resource).InstrumentationLibraryLogs().At(ev.library).InstrumentationLibrary().CopyTo(ill.InstrumentationLibrary())
+			logs = ill.Logs()
+			libraries[[2]int{ev.resource, ev.library}] = logs
+		}
+		logs.Resize(logs.Len() + 1)
+		ev.record.CopyTo(logs.At(logs.Len() - 1))
+	}
+	return failed
+}
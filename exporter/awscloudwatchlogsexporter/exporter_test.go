@@ -0,0 +1,284 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// logsMock records the requests, and answers them with the put function or with the next sequence token.
+type logsMock struct {
+	mu        sync.Mutex
+	put       func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error)
+	putInputs []cloudwatchlogs.PutLogEventsInput
+
+	createStreamErrs []error
+	createGroupErr   error
+	streams          []string
+	groups           []string
+	retentions       map[string]int64
+}
+
+func (m *logsMock) PutLogEventsWithContext(_ aws.Context, input *cloudwatchlogs.PutLogEventsInput, _ ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.putInputs = append(m.putInputs, *input)
+	if m.put != nil {
+		return m.put(input)
+	}
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String("next")}, nil
+}
+
+func (m *logsMock) CreateLogGroupWithContext(_ aws.Context, input *cloudwatchlogs.CreateLogGroupInput, _ ...request.Option) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groups = append(m.groups, *input.LogGroupName)
+	return &cloudwatchlogs.CreateLogGroupOutput{}, m.createGroupErr
+}
+
+func (m *logsMock) CreateLogStreamWithContext(_ aws.Context, input *cloudwatchlogs.CreateLogStreamInput, _ ...request.Option) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streams = append(m.streams, *input.LogGroupName+":"+*input.LogStreamName)
+	var err error
+	if len(m.createStreamErrs) > 0 {
+		err, m.createStreamErrs = m.createStreamErrs[0], m.createStreamErrs[1:]
+	}
+	return &cloudwatchlogs.CreateLogStreamOutput{}, err
+}
+
+func (m *logsMock) PutRetentionPolicyWithContext(_ aws.Context, input *cloudwatchlogs.PutRetentionPolicyInput, _ ...request.Option) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.retentions == nil {
+		m.retentions = map[string]int64{}
+	}
+	m.retentions[*input.LogGroupName] = *input.RetentionInDays
+	return &cloudwatchlogs.PutRetentionPolicyOutput{}, nil
+}
+
+func newTestExporter(client cloudWatchLogsAPI) *cloudWatchLogsExporter {
+	return &cloudWatchLogsExporter{
+		client:        client,
+		logGroupName:  "/apps/{k8s.namespace.name}",
+		logStreamName: "{k8s.pod.name}",
+		streams:       newStreams(),
+		logger:        zap.NewNop(),
+	}
+}
+
+// resourceLogs appends a resource with the namespace and pod attributes, when set, and records with the bodies
+// and timestamps in seconds.
+func resourceLogs(ld pdata.Logs, namespace, pod string, records map[string]int64) {
+	rls := ld.ResourceLogs()
+	rls.Resize(rls.Len() + 1)
+	rl := rls.At(rls.Len() - 1)
+	if namespace != "" {
+		rl.Resource().Attributes().InsertString("k8s.namespace.name", namespace)
+	}
+	if pod != "" {
+		rl.Resource().Attributes().InsertString("k8s.pod.name", pod)
+	}
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	for body, seconds := range records {
+		logs.Resize(logs.Len() + 1)
+		record := logs.At(logs.Len() - 1)
+		record.Body().SetStringVal(body)
+		record.SetTimestamp(pdata.TimestampUnixNano(seconds * int64(time.Second)))
+	}
+}
+
+func messages(input cloudwatchlogs.PutLogEventsInput) []string {
+	var messages []string
+	for _, ev := range input.LogEvents {
+		messages = append(messages, *ev.Message)
+	}
+	return messages
+}
+
+func TestPushLogsByStream(t *testing.T) {
+	mock := &logsMock{}
+	exp := newTestExporter(mock)
+
+	ld := pdata.NewLogs()
+	resourceLogs(ld, "prod", "api-0", map[string]int64{"third": 30, "first": 10, "second": 20})
+	resourceLogs(ld, "prod", "api-1", map[string]int64{"other": 15})
+	resourceLogs(ld, "", "", map[string]int64{"unknown": 5})
+
+	dropped, err := exp.pushLogs(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	require.Len(t, mock.putInputs, 3)
+	assert.Equal(t, "/apps/prod", *mock.putInputs[0].LogGroupName)
+	assert.Equal(t, "api-0", *mock.putInputs[0].LogStreamName)
+	assert.Equal(t, []string{"first", "second", "third"}, messages(mock.putInputs[0]))
+	assert.Equal(t, int64(10000), *mock.putInputs[0].LogEvents[0].Timestamp)
+	assert.Equal(t, "api-1", *mock.putInputs[1].LogStreamName)
+	assert.Equal(t, []string{"other"}, messages(mock.putInputs[1]))
+	assert.Equal(t, "/apps/undefined", *mock.putInputs[2].LogGroupName)
+	assert.Equal(t, "undefined", *mock.putInputs[2].LogStreamName)
+}
+
+func TestPushLogsDropsEmptyRecords(t *testing.T) {
+	mock := &logsMock{}
+	exp := newTestExporter(mock)
+
+	ld := pdata.NewLogs()
+	resourceLogs(ld, "prod", "api-0", map[string]int64{"message": 10})
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+
+	dropped, err := exp.pushLogs(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+	require.Len(t, mock.putInputs, 1)
+	assert.Equal(t, []string{"message"}, messages(mock.putInputs[0]))
+}
+
+func TestPushLogsPartialFailure(t *testing.T) {
+	mock := &logsMock{
+		put: func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+			switch *input.LogStreamName {
+			case "api-0":
+				return nil, awserr.New(errCodeThrottlingException, "Rate exceeded", nil)
+			case "api-1":
+				return nil, awserr.New(cloudwatchlogs.ErrCodeInvalidParameterException, "invalid", nil)
+			}
+			return &cloudwatchlogs.PutLogEventsOutput{}, nil
+		},
+	}
+	exp := newTestExporter(mock)
+
+	ld := pdata.NewLogs()
+	resourceLogs(ld, "prod", "api-0", map[string]int64{"throttled": 10, "throttled too": 20})
+	resourceLogs(ld, "prod", "api-1", map[string]int64{"invalid": 10})
+	resourceLogs(ld, "prod", "api-2", map[string]int64{"put": 10})
+
+	dropped, err := exp.pushLogs(context.Background(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 3, dropped)
+
+	var partial consumererror.PartialError
+	require.True(t, errors.As(err, &partial))
+	failed := partial.GetLogs()
+	assert.Equal(t, 2, failed.LogRecordCount())
+	require.Equal(t, 1, failed.ResourceLogs().Len())
+	pod, _ := failed.ResourceLogs().At(0).Resource().Attributes().Get("k8s.pod.name")
+	assert.Equal(t, "api-0", pod.StringVal())
+}
+
+func TestPushLogsPermanentFailure(t *testing.T) {
+	mock := &logsMock{
+		put: func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+			return nil, awserr.New("AccessDeniedException", "denied", nil)
+		},
+	}
+	exp := newTestExporter(mock)
+
+	ld := pdata.NewLogs()
+	resourceLogs(ld, "prod", "api-0", map[string]int64{"message": 10})
+
+	dropped, err := exp.pushLogs(context.Background(), ld)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, dropped)
+}
+
+func TestInputLogEvent(t *testing.T) {
+	record := pdata.NewLogRecord()
+	record.Body().SetStringVal("message")
+	record.SetTimestamp(pdata.TimestampUnixNano(1500 * time.Millisecond))
+	input, err := inputLogEvent(record)
+	require.NoError(t, err)
+	assert.Equal(t, "message", *input.Message)
+	assert.Equal(t, int64(1500), *input.Timestamp)
+
+	record.SetTimestamp(0)
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+	input, err = inputLogEvent(record)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, *input.Timestamp, before)
+
+	record.Body().SetStringVal(strings.Repeat("é", maxEventSize))
+	input, err = inputLogEvent(record)
+	require.NoError(t, err)
+	assert.Equal(t, maxEventSize-eventHeaderSize, len(*input.Message))
+	assert.True(t, strings.HasSuffix(*input.Message, "é"))
+
+	record.Body().SetStringVal("")
+	_, err = inputLogEvent(record)
+	assert.Equal(t, errEmptyMessage, err)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "ab", truncate("abc", 2))
+	assert.Equal(t, "a", truncate("aé", 2))
+	assert.Equal(t, "aé", truncate("aéb", 3))
+}
+
+func TestExpandName(t *testing.T) {
+	attrs := pdata.NewAttributeMap()
+	attrs.InsertString("k8s.namespace.name", "prod")
+	attrs.InsertString("empty", "")
+	attrs.InsertInt("k8s.pod.uid", 42)
+
+	assert.Equal(t, "/apps/prod", expandName("/apps/{k8s.namespace.name}", attrs))
+	assert.Equal(t, "prod-42", expandName("{k8s.namespace.name}-{k8s.pod.uid}", attrs))
+	assert.Equal(t, "undefined/undefined", expandName("{empty}/{missing}", attrs))
+	assert.Equal(t, "static", expandName("static", attrs))
+}
+
+func TestRequestEvents(t *testing.T) {
+	newEvents := func(n int, size int, step time.Duration) []event {
+		events := make([]event, n)
+		for i := range events {
+			events[i].input = &cloudwatchlogs.InputLogEvent{
+				Message:   aws.String(strings.Repeat("a", size)),
+				Timestamp: aws.Int64(int64(time.Duration(i) * step / time.Millisecond)),
+			}
+		}
+		return events
+	}
+	sizes := func(batches [][]event) []int {
+		var sizes []int
+		for _, b := range batches {
+			sizes = append(sizes, len(b))
+		}
+		return sizes
+	}
+
+	assert.Nil(t, requestEvents(nil))
+	assert.Equal(t, []int{maxRequestEvents, 5}, sizes(requestEvents(newEvents(maxRequestEvents+5, 1, time.Millisecond))))
+	// 1000 bytes with the header, so that 1048 events fit in a request.
+	assert.Equal(t, []int{1048, 952}, sizes(requestEvents(newEvents(2000, 1000-eventHeaderSize, time.Millisecond))))
+	assert.Equal(t, []int{3, 3, 1}, sizes(requestEvents(newEvents(7, 1, 8*time.Hour))))
+}
@@ -0,0 +1,10 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter
+
+go 1.14
+
+require (
+	github.com/aws/aws-sdk-go v1.36.28
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+)
@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxPutAttempts is the maximum number of PutLogEvents requests sent for a batch, the sequence token of the
+	// stream being corrected, or the stream being created, between the attempts.
+	maxPutAttempts = 4
+
+	// errCodeThrottlingException is the code of the throttling errors, which have no type in the SDK.
+	errCodeThrottlingException = "ThrottlingException"
+)
+
+// streamKey identifies a log stream.
+type streamKey struct {
+	group, stream string
+}
+
+// stream holds the sequence token of a log stream. The lock serializes the requests to the stream, each request
+// requiring the token returned by the previous one.
+type stream struct {
+	sync.Mutex
+	token *string
+}
+
+// streams are the log streams the exporter puts events to.
+type streams struct {
+	sync.Mutex
+	byKey map[streamKey]*stream
+}
+
+func newStreams() *streams {
+	return &streams{byKey: map[streamKey]*stream{}}
+}
+
+func (s *streams) get(key streamKey) *stream {
+	s.Lock()
+	defer s.Unlock()
+	st, ok := s.byKey[key]
+	if !ok {
+		st = &stream{}
+		s.byKey[key] = st
+	}
+	return st
+}
+
+func sortedStreamKeys(events map[streamKey][]event) []streamKey {
+	keys := make([]streamKey, 0, len(events))
+	for key := range events {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].group != keys[j].group {
+			return keys[i].group < keys[j].group
+		}
+		return keys[i].stream < keys[j].stream
+	})
+	return keys
+}
+
+// putLogEvents puts a batch of events to the stream. The sequence token of the stream is corrected from the
+// InvalidSequenceTokenException and DataAlreadyAcceptedException errors, the batch being sent again in the former
+// case and considered as put in the latter, and the log group and stream are created when they don't exist.
+func (e *cloudWatchLogsExporter) putLogEvents(ctx context.Context, key streamKey, batch []event) error {
+	st := e.streams.get(key)
+	st.Lock()
+	defer st.Unlock()
+
+	input := &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(key.group),
+		LogStreamName: aws.String(key.stream),
+		LogEvents:     make([]*cloudwatchlogs.InputLogEvent, len(batch)),
+	}
+	for i, ev := range batch {
+		input.LogEvents[i] = ev.input
+	}
+
+	var err error
+	for attempt := 0; attempt < maxPutAttempts; attempt++ {
+		input.SequenceToken = st.token
+		var output *cloudwatchlogs.PutLogEventsOutput
+		output, err = e.client.PutLogEventsWithContext(ctx, input)
+		if err == nil {
+			st.token = output.NextSequenceToken
+			e.logRejected(key, output.RejectedLogEventsInfo)
+			return nil
+		}
+
+		var invalidToken *cloudwatchlogs.InvalidSequenceTokenException
+		var alreadyAccepted *cloudwatchlogs.DataAlreadyAcceptedException
+		var notFound *cloudwatchlogs.ResourceNotFoundException
+		switch {
+		case errors.As(err, &invalidToken):
+			e.logger.Debug("Correcting the sequence token of the log stream", zap.String("log_group", key.group), zap.String("log_stream", key.stream))
+			st.token = invalidToken.ExpectedSequenceToken
+		case errors.As(err, &alreadyAccepted):
+			e.logger.Debug("The log events were already accepted", zap.String("log_group", key.group), zap.String("log_stream", key.stream))
+			st.token = alreadyAccepted.ExpectedSequenceToken
+			return nil
+		case errors.As(err, &notFound):
+			if cerr := e.createStream(ctx, key); cerr != nil {
+				return classifyError(cerr)
+			}
+			st.token = nil
+		default:
+			return classifyError(err)
+		}
+	}
+	return fmt.Errorf("failed to put the log events after %d attempts: %w", maxPutAttempts, err)
+}
+
+// createStream creates the log stream, and its log group when it doesn't exist either, along with the retention
+// policy of the group when configured.
+func (e *cloudWatchLogsExporter) createStream(ctx context.Context, key streamKey) error {
+	e.logger.Info("Creating the log stream", zap.String("log_group", key.group), zap.String("log_stream", key.stream))
+	streamInput := &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(key.group),
+		LogStreamName: aws.String(key.stream),
+	}
+	_, err := e.client.CreateLogStreamWithContext(ctx, streamInput)
+	if isErrorCode(err, cloudwatchlogs.ErrCodeResourceNotFoundException) {
+		if err = e.createGroup(ctx, key.group); err != nil {
+			return err
+		}
+		_, err = e.client.CreateLogStreamWithContext(ctx, streamInput)
+	}
+	if err != nil && !isErrorCode(err, cloudwatchlogs.ErrCodeResourceAlreadyExistsException) {
+		return err
+	}
+	return nil
+}
+
+func (e *cloudWatchLogsExporter) createGroup(ctx context.Context, group string) error {
+	e.logger.Info("Creating the log group", zap.String("log_group", group))
+	_, err := e.client.CreateLogGroupWithContext(ctx, &cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String(group)})
+	if isErrorCode(err, cloudwatchlogs.ErrCodeResourceAlreadyExistsException) {
+		return nil
+	}
+	if err != nil || e.logRetention == 0 {
+		return err
+	}
+	_, err = e.client.PutRetentionPolicyWithContext(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName:    aws.String(group),
+		RetentionInDays: aws.Int64(e.logRetention),
+	})
+	return err
+}
+
+func (e *cloudWatchLogsExporter) logRejected(key streamKey, info *cloudwatchlogs.RejectedLogEventsInfo) {
+	if info == nil {
+		return
+	}
+	fields := []zap.Field{zap.String("log_group", key.group), zap.String("log_stream", key.stream)}
+	if info.TooOldLogEventEndIndex != nil {
+		fields = append(fields, zap.Int64("too_old_end_index", *info.TooOldLogEventEndIndex))
+	}
+	if info.ExpiredLogEventEndIndex != nil {
+		fields = append(fields, zap.Int64("expired_end_index", *info.ExpiredLogEventEndIndex))
+	}
+	if info.TooNewLogEventStartIndex != nil {
+		fields = append(fields, zap.Int64("too_new_start_index", *info.TooNewLogEventStartIndex))
+	}
+	e.logger.Warn("Some log events were rejected because of their timestamp", fields...)
+}
+
+func isErrorCode(err error, code string) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == code
+}
+
+// classifyError returns whether a failure of the CloudWatch Logs API can be retried: the throttling of the requests,
+// the errors of the service and the network errors are transient, the other errors, e.g. an invalid name or denied
+// access, are permanent.
+func classifyError(err error) error {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return err
+	}
+	switch aerr.Code() {
+	case cloudwatchlogs.ErrCodeServiceUnavailableException,
+		cloudwatchlogs.ErrCodeOperationAbortedException,
+		errCodeThrottlingException,
+		request.CanceledErrorCode:
+		return err
+	}
+	if request.IsErrorRetryable(err) || request.IsErrorThrottle(err) {
+		return err
+	}
+	return consumererror.Permanent(err)
+}
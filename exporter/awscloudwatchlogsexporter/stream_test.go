@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+var testKey = streamKey{group: "group", stream: "stream"}
+
+func testBatch() []event {
+	return []event{{input: &cloudwatchlogs.InputLogEvent{Message: aws.String("message"), Timestamp: aws.Int64(1)}}}
+}
+
+func tokens(inputs []cloudwatchlogs.PutLogEventsInput) []*string {
+	var tokens []*string
+	for _, input := range inputs {
+		tokens = append(tokens, input.SequenceToken)
+	}
+	return tokens
+}
+
+func TestPutLogEventsSequenceToken(t *testing.T) {
+	mock := &logsMock{}
+	exp := newTestExporter(mock)
+
+	require.NoError(t, exp.putLogEvents(context.Background(), testKey, testBatch()))
+	require.NoError(t, exp.putLogEvents(context.Background(), testKey, testBatch()))
+	require.NoError(t, exp.putLogEvents(context.Background(), streamKey{group: "group", stream: "other"}, testBatch()))
+	assert.Equal(t, []*string{nil, aws.String("next"), nil}, tokens(mock.putInputs))
+}
+
+func TestPutLogEventsInvalidSequenceToken(t *testing.T) {
+	mock := &logsMock{
+		put: func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+			if input.SequenceToken == nil || *input.SequenceToken != "expected" {
+				return nil, &cloudwatchlogs.InvalidSequenceTokenException{
+					Message_:              aws.String("invalid token"),
+					ExpectedSequenceToken: aws.String("expected"),
+				}
+			}
+			return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String("next")}, nil
+		},
+	}
+	exp := newTestExporter(mock)
+
+	require.NoError(t, exp.putLogEvents(context.Background(), testKey, testBatch()))
+	assert.Equal(t, []*string{nil, aws.String("expected")}, tokens(mock.putInputs))
+	assert.Equal(t, aws.String("next"), exp.streams.get(testKey).token)
+}
+
+func TestPutLogEventsDataAlreadyAccepted(t *testing.T) {
+	mock := &logsMock{
+		put: func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+			return nil, &cloudwatchlogs.DataAlreadyAcceptedException{
+				Message_:              aws.String("already accepted"),
+				ExpectedSequenceToken: aws.String("expected"),
+			}
+		},
+	}
+	exp := newTestExporter(mock)
+
+	require.NoError(t, exp.putLogEvents(context.Background(), testKey, testBatch()))
+	assert.Len(t, mock.putInputs, 1)
+	assert.Equal(t, aws.String("expected"), exp.streams.get(testKey).token)
+}
+
+func TestPutLogEventsCreatesStream(t *testing.T) {
+	created := false
+	mock := &logsMock{}
+	mock.put = func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+		if len(mock.streams) == 0 {
+			return nil, &cloudwatchlogs.ResourceNotFoundException{Message_: aws.String("The specified log stream does not exist.")}
+		}
+		created = true
+		return &cloudwatchlogs.PutLogEventsOutput{}, nil
+	}
+	exp := newTestExporter(mock)
+
+	require.NoError(t, exp.putLogEvents(context.Background(), testKey, testBatch()))
+	assert.True(t, created)
+	assert.Equal(t, []string{"group:stream"}, mock.streams)
+	assert.Empty(t, mock.groups)
+}
+
+func TestPutLogEventsCreatesGroup(t *testing.T) {
+	mock := &logsMock{
+		createStreamErrs: []error{awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "The specified log group does not exist.", nil)},
+	}
+	mock.put = func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+		if len(mock.streams) < 2 {
+			return nil, &cloudwatchlogs.ResourceNotFoundException{Message_: aws.String("The specified log group does not exist.")}
+		}
+		return &cloudwatchlogs.PutLogEventsOutput{}, nil
+	}
+	exp := newTestExporter(mock)
+	exp.logRetention = 30
+
+	require.NoError(t, exp.putLogEvents(context.Background(), testKey, testBatch()))
+	assert.Equal(t, []string{"group:stream", "group:stream"}, mock.streams)
+	assert.Equal(t, []string{"group"}, mock.groups)
+	assert.Equal(t, map[string]int64{"group": 30}, mock.retentions)
+}
+
+func TestPutLogEventsStreamAlreadyExists(t *testing.T) {
+	mock := &logsMock{
+		createStreamErrs: []error{awserr.New(cloudwatchlogs.ErrCodeResourceAlreadyExistsException, "The specified log stream already exists", nil)},
+	}
+	calls := 0
+	mock.put = func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+		calls++
+		if calls == 1 {
+			return nil, &cloudwatchlogs.ResourceNotFoundException{Message_: aws.String("The specified log stream does not exist.")}
+		}
+		return &cloudwatchlogs.PutLogEventsOutput{}, nil
+	}
+	exp := newTestExporter(mock)
+
+	require.NoError(t, exp.putLogEvents(context.Background(), testKey, testBatch()))
+	assert.Equal(t, 2, calls)
+}
+
+func TestPutLogEventsAttemptsExhausted(t *testing.T) {
+	mock := &logsMock{
+		put: func(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+			return nil, &cloudwatchlogs.InvalidSequenceTokenException{
+				Message_:              aws.String("invalid token"),
+				ExpectedSequenceToken: aws.String("expected"),
+			}
+		},
+	}
+	exp := newTestExporter(mock)
+
+	err := exp.putLogEvents(context.Background(), testKey, testBatch())
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Len(t, mock.putInputs, maxPutAttempts)
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err       error
+		permanent bool
+	}{
+		{err: errors.New("connection reset"), permanent: false},
+		{err: awserr.New(errCodeThrottlingException, "Rate exceeded", nil), permanent: false},
+		{err: &cloudwatchlogs.ServiceUnavailableException{Message_: aws.String("unavailable")}, permanent: false},
+		{err: &cloudwatchlogs.OperationAbortedException{Message_: aws.String("aborted")}, permanent: false},
+		{err: awserr.New(request.ErrCodeRequestError, "send request failed", nil), permanent: false},
+		{err: &cloudwatchlogs.InvalidParameterException{Message_: aws.String("invalid")}, permanent: true},
+		{err: awserr.New("AccessDeniedException", "denied", nil), permanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(classifyError(tt.err)))
+		})
+	}
+}
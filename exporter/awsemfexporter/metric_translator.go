@@ -209,7 +209,7 @@ func getCWMetrics(metric *pdata.Metric, namespace string, instrumentationLibName
 	// metric measure data from OT
 	metricMeasure := make(map[string]string)
 	metricMeasure["Name"] = metric.Name()
-	metricMeasure["Unit"] = metric.Unit()
+	metricMeasure["Unit"] = cloudWatchUnit(metric.Unit())
 	// metric measure slice could include multiple metric measures
 	metricSlice := []map[string]string{metricMeasure}
 
@@ -0,0 +1,98 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsemfexporter
+
+import "strings"
+
+const (
+	// noneUnit is the CloudWatch unit of the metrics without a unit, or with a unit CloudWatch doesn't support
+	noneUnit = "None"
+	// countUnit is the CloudWatch unit of the metrics counting things, e.g. {requests} in UCUM
+	countUnit = "Count"
+	// the suffixes of the units of the rates, in UCUM and CloudWatch
+	ucumPerSecondSuffix       = "/s"
+	cloudWatchPerSecondSuffix = "/Second"
+)
+
+// cloudWatchUnits are the units supported by CloudWatch, see
+// https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_MetricDatum.html
+var cloudWatchUnits = map[string]bool{
+	"Seconds": true, "Microseconds": true, "Milliseconds": true,
+	"Bytes": true, "Kilobytes": true, "Megabytes": true, "Gigabytes": true, "Terabytes": true,
+	"Bits": true, "Kilobits": true, "Megabits": true, "Gigabits": true, "Terabits": true,
+	"Percent": true, "Count": true, "None": true,
+	"Bytes/Second": true, "Kilobytes/Second": true, "Megabytes/Second": true, "Gigabytes/Second": true,
+	"Terabytes/Second": true, "Bits/Second": true, "Kilobits/Second": true, "Megabits/Second": true,
+	"Gigabits/Second": true, "Terabits/Second": true, "Count/Second": true,
+}
+
+// ucumUnits maps the UCUM units used by OpenTelemetry to CloudWatch units, the rates being mapped from the
+// units of their numerator
+var ucumUnits = map[string]string{
+	"s":    "Seconds",
+	"ms":   "Milliseconds",
+	"us":   "Microseconds",
+	"By":   "Bytes",
+	"kBy":  "Kilobytes",
+	"KBy":  "Kilobytes",
+	"KiBy": "Kilobytes",
+	"MBy":  "Megabytes",
+	"MiBy": "Megabytes",
+	"GBy":  "Gigabytes",
+	"GiBy": "Gigabytes",
+	"TBy":  "Terabytes",
+	"TiBy": "Terabytes",
+	"bit":  "Bits",
+	"kbit": "Kilobits",
+	"Kbit": "Kilobits",
+	"Mbit": "Megabits",
+	"Gbit": "Gigabits",
+	"Tbit": "Terabits",
+	"%":    "Percent",
+	"1":    noneUnit,
+}
+
+// cloudWatchUnit returns the CloudWatch unit of a metric with the given OpenTelemetry unit. The CloudWatch units are
+// kept, the UCUM annotations, e.g. {requests}, are counts, and the units CloudWatch doesn't support are mapped to
+// None. An empty unit is kept empty, CloudWatch defaulting to None.
+func cloudWatchUnit(unit string) string {
+	if unit == "" || cloudWatchUnits[unit] {
+		return unit
+	}
+
+	if numerator := strings.TrimSuffix(unit, ucumPerSecondSuffix); numerator != unit {
+		if numerator == "1" || isAnnotation(numerator) {
+			return countUnit + cloudWatchPerSecondSuffix
+		}
+		// CloudWatch only supports the rates of the counts, bytes and bits
+		if cwUnit, ok := ucumUnits[numerator]; ok && cloudWatchUnits[cwUnit+cloudWatchPerSecondSuffix] {
+			return cwUnit + cloudWatchPerSecondSuffix
+		}
+		return noneUnit
+	}
+
+	if isAnnotation(unit) {
+		return countUnit
+	}
+	if cwUnit, ok := ucumUnits[unit]; ok {
+		return cwUnit
+	}
+	return noneUnit
+}
+
+// isAnnotation returns whether the UCUM unit is an annotation, e.g. {requests}
+func isAnnotation(unit string) bool {
+	return strings.HasPrefix(unit, "{") && strings.HasSuffix(unit, "}")
+}
@@ -0,0 +1,56 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsemfexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudWatchUnit(t *testing.T) {
+	testCases := []struct {
+		unit     string
+		expected string
+	}{
+		{"", ""},
+		{"Seconds", "Seconds"},
+		{"Count/Second", "Count/Second"},
+		{"s", "Seconds"},
+		{"ms", "Milliseconds"},
+		{"us", "Microseconds"},
+		{"By", "Bytes"},
+		{"KiBy", "Kilobytes"},
+		{"MBy", "Megabytes"},
+		{"bit", "Bits"},
+		{"Gbit", "Gigabits"},
+		{"%", "Percent"},
+		{"1", "None"},
+		{"{requests}", "Count"},
+		{"By/s", "Bytes/Second"},
+		{"Mbit/s", "Megabits/Second"},
+		{"{requests}/s", "Count/Second"},
+		{"1/s", "Count/Second"},
+		{"ms/s", "None"},
+		{"h", "None"},
+		{"Cel", "None"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.unit, func(t *testing.T) {
+			assert.Equal(t, tc.expected, cloudWatchUnit(tc.unit))
+		})
+	}
+}
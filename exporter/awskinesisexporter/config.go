@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskinesisexporter
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	otlpProtoEncoding = "otlp_proto"
+	otlpJSONEncoding  = "otlp_json"
+
+	noCompression   = "none"
+	gzipCompression = "gzip"
+)
+
+// Config defines configuration for the AWS Kinesis exporter.
+type Config struct {
+	configmodels.ExporterSettings  `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
+
+	AWS AWSSettings `mapstructure:"aws"`
+
+	// Encoding is the encoding of the data of the records, either otlp_proto or otlp_json.
+	Encoding string `mapstructure:"encoding"`
+	// Compression is the compression of the data of the records, either none or gzip.
+	Compression string `mapstructure:"compression"`
+	// PartitionKeyAttribute is the resource attribute whose value is the partition key of the records, so that the
+	// data of a resource is sent to the same shard. When not set, or for the resources without the attribute, the
+	// spans and the log records are partitioned by their trace ID, and the metrics randomly.
+	PartitionKeyAttribute string `mapstructure:"partition_key_attribute"`
+}
+
+// AWSSettings defines the stream the records are put to, and how to authenticate to it.
+type AWSSettings struct {
+	// StreamName is the name of the Kinesis data stream.
+	StreamName string `mapstructure:"stream_name"`
+	// Region is the region of the stream. The region is read from the environment when not set.
+	Region string `mapstructure:"region"`
+	// Endpoint overrides the endpoint of the Kinesis API, e.g. for a VPC endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+	// RoleARN is the ARN of a role to assume, the credentials of the default chain being used when not set.
+	RoleARN string `mapstructure:"role_arn"`
+}
+
+func (cfg *Config) validate() error {
+	if cfg.AWS.StreamName == "" {
+		return fmt.Errorf("aws.stream_name must be specified")
+	}
+	switch cfg.Encoding {
+	case otlpProtoEncoding, otlpJSONEncoding:
+	default:
+		return fmt.Errorf("unsupported encoding %q, must be either %q or %q", cfg.Encoding, otlpProtoEncoding, otlpJSONEncoding)
+	}
+	switch cfg.Compression {
+	case noCompression, gzipCompression:
+	default:
+		return fmt.Errorf("unsupported compression %q, must be either %q or %q", cfg.Compression, noCompression, gzipCompression)
+	}
+	return nil
+}
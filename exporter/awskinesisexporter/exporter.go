@@ -0,0 +1,326 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskinesisexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxRecordSize is the maximum size of the data and partition key of a record.
+	maxRecordSize = 1 << 20
+	// maxRequestSize is the maximum size of the records of a PutRecords request.
+	maxRequestSize = 5 << 20
+	// maxRequestRecords is the maximum number of records of a PutRecords request.
+	maxRequestRecords = 500
+	// maxPartitionKeyLength is the maximum number of characters of a partition key.
+	maxPartitionKeyLength = 256
+)
+
+var errRecordTooLarge = errors.New("the data exceeds the maximum size of a Kinesis record")
+
+// putRecordsAPI is the part of the Kinesis API used by the exporter.
+type putRecordsAPI interface {
+	PutRecordsWithContext(ctx aws.Context, input *kinesis.PutRecordsInput, opts ...request.Option) (*kinesis.PutRecordsOutput, error)
+}
+
+// kinesisExporter puts the data as records of a Kinesis data stream.
+type kinesisExporter struct {
+	client                putRecordsAPI
+	streamName            string
+	encoding              string
+	compression           string
+	partitionKeyAttribute string
+	logger                *zap.Logger
+}
+
+// record is a record to put, along with the data it holds to be retried.
+type record struct {
+	entry   *kinesis.PutRecordsRequestEntry
+	payload payload
+}
+
+func newExporter(cfg *Config, logger *zap.Logger) (*kinesisExporter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	awsConfig := aws.NewConfig()
+	if cfg.AWS.Region != "" {
+		awsConfig.WithRegion(cfg.AWS.Region)
+	}
+	if cfg.AWS.Endpoint != "" {
+		awsConfig.WithEndpoint(cfg.AWS.Endpoint)
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AWS.RoleARN != "" {
+		awsConfig = awsConfig.Copy().WithCredentials(stscreds.NewCredentials(sess, cfg.AWS.RoleARN))
+	}
+
+	return &kinesisExporter{
+		client:                kinesis.New(sess, awsConfig),
+		streamName:            cfg.AWS.StreamName,
+		encoding:              cfg.Encoding,
+		compression:           cfg.Compression,
+		partitionKeyAttribute: cfg.PartitionKeyAttribute,
+		logger:                logger,
+	}, nil
+}
+
+func (e *kinesisExporter) pushTraces(ctx context.Context, td pdata.Traces) (int, error) {
+	failed, dropped, err := e.put(ctx, tracesGroups(td, e.partitionKeyAttribute))
+	if err == nil || len(failed) == 0 {
+		return dropped, err
+	}
+	retry := pdata.NewTraces()
+	for _, p := range failed {
+		rss := pdata.Traces(p.(tracesPayload)).ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			retry.ResourceSpans().Append(rss.At(i))
+		}
+	}
+	return dropped + retry.SpanCount(), consumererror.PartialTracesError(err, retry)
+}
+
+func (e *kinesisExporter) pushMetrics(ctx context.Context, md pdata.Metrics) (int, error) {
+	failed, dropped, err := e.put(ctx, metricsGroups(md, e.partitionKeyAttribute))
+	if err == nil || len(failed) == 0 {
+		return dropped, err
+	}
+	retry := pdata.NewMetrics()
+	for _, p := range failed {
+		rms := pdata.Metrics(p.(metricsPayload)).ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			retry.ResourceMetrics().Append(rms.At(i))
+		}
+	}
+	_, points := retry.MetricAndDataPointCount()
+	return dropped + points, consumererror.PartialMetricsError(err, retry)
+}
+
+func (e *kinesisExporter) pushLogs(ctx context.Context, ld pdata.Logs) (int, error) {
+	failed, dropped, err := e.put(ctx, logsGroups(ld, e.partitionKeyAttribute))
+	if err == nil || len(failed) == 0 {
+		return dropped, err
+	}
+	retry := pdata.NewLogs()
+	for _, p := range failed {
+		rls := pdata.Logs(p.(logsPayload)).ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			retry.ResourceLogs().Append(rls.At(i))
+		}
+	}
+	return dropped + retry.LogRecordCount(), consumererror.PartialLogsError(err, retry)
+}
+
+// put puts the records of the groups, in as few requests as the limits of PutRecords allow. It returns the data of
+// the records which failed and can be retried, and the number of items dropped because of permanent errors. The
+// returned error is permanent when no record can be retried.
+func (e *kinesisExporter) put(ctx context.Context, groups []group) ([]payload, int, error) {
+	var errs []error
+	seenErrs := map[string]bool{}
+	addError := func(err error) {
+		if !seenErrs[err.Error()] {
+			seenErrs[err.Error()] = true
+			errs = append(errs, err)
+		}
+	}
+
+	var records []record
+	dropped := 0
+	for _, g := range groups {
+		groupRecords, groupDropped, err := e.records(partitionKey(g.key), g.payload)
+		records = append(records, groupRecords...)
+		dropped += groupDropped
+		if err != nil {
+			addError(err)
+		}
+	}
+
+	var failed []payload
+	for len(records) > 0 {
+		batch := requestRecords(records)
+		records = records[len(batch):]
+
+		batchFailed, err := e.putRecords(ctx, batch)
+		if err != nil {
+			addError(err)
+			if consumererror.IsPermanent(err) {
+				for _, r := range batch {
+					dropped += r.payload.count()
+				}
+				continue
+			}
+		}
+		failed = append(failed, batchFailed...)
+	}
+
+	if len(errs) == 0 {
+		return nil, dropped, nil
+	}
+	err := componenterror.CombineErrors(errs)
+	if len(failed) == 0 {
+		return nil, dropped, consumererror.Permanent(err)
+	}
+	return failed, dropped, err
+}
+
+// records returns the records of the payload, split until each record fits in the maximum size, along with the
+// number of items dropped because they can't, or can't be encoded.
+func (e *kinesisExporter) records(key string, p payload) ([]record, int, error) {
+	data, err := e.encode(p)
+	if err != nil {
+		return nil, p.count(), err
+	}
+	if len(data)+len(key) <= maxRecordSize {
+		return []record{{
+			entry:   &kinesis.PutRecordsRequestEntry{Data: data, PartitionKey: aws.String(key)},
+			payload: p,
+		}}, 0, nil
+	}
+	if p.items() <= 1 {
+		return nil, p.count(), errRecordTooLarge
+	}
+
+	first, second := p.split()
+	records, dropped, err := e.records(key, first)
+	secondRecords, secondDropped, secondErr := e.records(key, second)
+	if err == nil {
+		err = secondErr
+	}
+	return append(records, secondRecords...), dropped + secondDropped, err
+}
+
+func (e *kinesisExporter) encode(p payload) ([]byte, error) {
+	data, err := p.marshal(e.encoding)
+	if err != nil || e.compression != gzipCompression {
+		return data, err
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// putRecords puts a batch of records, and returns the data of the records which failed. The records rejected in
+// the response, e.g. because the throughput of their shard was exceeded, are retried individually.
+func (e *kinesisExporter) putRecords(ctx context.Context, batch []record) ([]payload, error) {
+	entries := make([]*kinesis.PutRecordsRequestEntry, len(batch))
+	for i, r := range batch {
+		entries[i] = r.entry
+	}
+
+	out, err := e.client.PutRecordsWithContext(ctx, &kinesis.PutRecordsInput{
+		StreamName: aws.String(e.streamName),
+		Records:    entries,
+	})
+	if err != nil {
+		failed := make([]payload, len(batch))
+		for i, r := range batch {
+			failed[i] = r.payload
+		}
+		return failed, classifyError(err)
+	}
+	if aws.Int64Value(out.FailedRecordCount) == 0 {
+		return nil, nil
+	}
+
+	var failed []payload
+	codes := map[string]int{}
+	var firstErr *kinesis.PutRecordsResultEntry
+	for i, result := range out.Records {
+		if result.ErrorCode == nil || i >= len(batch) {
+			continue
+		}
+		failed = append(failed, batch[i].payload)
+		codes[aws.StringValue(result.ErrorCode)]++
+		if firstErr == nil {
+			firstErr = result
+		}
+	}
+	if firstErr == nil {
+		return nil, nil
+	}
+	e.logger.Debug("records rejected by Kinesis", zap.Int("failed", len(failed)), zap.Any("codes", codes))
+	return failed, fmt.Errorf("%d records failed to be put, e.g. %s: %s",
+		len(failed), aws.StringValue(firstErr.ErrorCode), aws.StringValue(firstErr.ErrorMessage))
+}
+
+// requestRecords returns the first records fitting in a PutRecords request.
+func requestRecords(records []record) []record {
+	size := 0
+	for i, r := range records {
+		size += len(r.entry.Data) + len(aws.StringValue(r.entry.PartitionKey))
+		if i == maxRequestRecords || size > maxRequestSize {
+			return records[:i]
+		}
+	}
+	return records
+}
+
+// classifyError returns whether the failure of a PutRecords request can be retried: the throughput of the stream,
+// or the throttling of the KMS requests, being exceeded, the errors of the service and the network errors are
+// transient, the other errors, e.g. an unknown stream or denied access, are permanent.
+func classifyError(err error) error {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return err
+	}
+	switch aerr.Code() {
+	case kinesis.ErrCodeProvisionedThroughputExceededException,
+		kinesis.ErrCodeKMSThrottlingException,
+		kinesis.ErrCodeLimitExceededException,
+		kinesis.ErrCodeInternalFailureException,
+		request.CanceledErrorCode:
+		return err
+	}
+	if request.IsErrorRetryable(err) || request.IsErrorThrottle(err) {
+		return err
+	}
+	return consumererror.Permanent(err)
+}
+
+// partitionKey truncates the key to the maximum length of a partition key.
+func partitionKey(key string) string {
+	if utf8.RuneCountInString(key) <= maxPartitionKeyLength {
+		return key
+	}
+	return string([]rune(key)[:maxPartitionKeyLength])
+}
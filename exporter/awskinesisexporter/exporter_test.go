@@ -0,0 +1,350 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskinesisexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	otlptrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// kinesisMock records the PutRecords requests, replying with the responses returned by respond.
+type kinesisMock struct {
+	requests []*kinesis.PutRecordsInput
+	respond  func(input *kinesis.PutRecordsInput) (*kinesis.PutRecordsOutput, error)
+}
+
+func (m *kinesisMock) PutRecordsWithContext(_ aws.Context, input *kinesis.PutRecordsInput, _ ...request.Option) (*kinesis.PutRecordsOutput, error) {
+	m.requests = append(m.requests, input)
+	if m.respond != nil {
+		return m.respond(input)
+	}
+	return &kinesis.PutRecordsOutput{FailedRecordCount: aws.Int64(0)}, nil
+}
+
+func (m *kinesisMock) records() []*kinesis.PutRecordsRequestEntry {
+	var records []*kinesis.PutRecordsRequestEntry
+	for _, r := range m.requests {
+		records = append(records, r.Records...)
+	}
+	return records
+}
+
+func newTestExporter(client putRecordsAPI) *kinesisExporter {
+	return &kinesisExporter{
+		client:      client,
+		streamName:  "test-stream",
+		encoding:    otlpProtoEncoding,
+		compression: noCompression,
+		logger:      zap.NewNop(),
+	}
+}
+
+// newTraces returns a span for each given trace ID, in a resource with the given service name.
+func newTraces(service string, traceIDs ...byte) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString("service.name", service)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(len(traceIDs))
+	for i, id := range traceIDs {
+		spans.At(i).SetName("span")
+		spans.At(i).SetTraceID(pdata.NewTraceID([16]byte{id}))
+		spans.At(i).SetSpanID(pdata.NewSpanID([8]byte{byte(i + 1)}))
+	}
+	return td
+}
+
+func decodeTraces(t *testing.T, data []byte) pdata.Traces {
+	td := pdata.NewTraces()
+	require.NoError(t, td.FromOtlpProtoBytes(data))
+	return td
+}
+
+func TestPushTracesPartitionedByTraceID(t *testing.T) {
+	client := &kinesisMock{}
+	exp := newTestExporter(client)
+
+	td := newTraces("frontend", 1, 2, 1)
+	dropped, err := exp.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	require.Len(t, client.requests, 1)
+	assert.Equal(t, "test-stream", aws.StringValue(client.requests[0].StreamName))
+	records := client.records()
+	require.Len(t, records, 2)
+	assert.Equal(t, "01000000000000000000000000000000", aws.StringValue(records[0].PartitionKey))
+	assert.Equal(t, 2, decodeTraces(t, records[0].Data).SpanCount())
+	assert.Equal(t, "02000000000000000000000000000000", aws.StringValue(records[1].PartitionKey))
+	assert.Equal(t, 1, decodeTraces(t, records[1].Data).SpanCount())
+}
+
+func TestPushTracesPartitionedByAttribute(t *testing.T) {
+	client := &kinesisMock{}
+	exp := newTestExporter(client)
+	exp.partitionKeyAttribute = "service.name"
+
+	td := newTraces("frontend", 1, 2)
+	newTraces("backend", 3).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	newTraces("frontend", 4).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	_, err := exp.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	records := client.records()
+	require.Len(t, records, 2)
+	assert.Equal(t, "frontend", aws.StringValue(records[0].PartitionKey))
+	assert.Equal(t, 3, decodeTraces(t, records[0].Data).SpanCount())
+	assert.Equal(t, "backend", aws.StringValue(records[1].PartitionKey))
+	assert.Equal(t, 1, decodeTraces(t, records[1].Data).SpanCount())
+}
+
+func TestPushMetricsPartitionedByAttribute(t *testing.T) {
+	client := &kinesisMock{}
+	exp := newTestExporter(client)
+	exp.partitionKeyAttribute = "host.name"
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(3)
+	for i, host := range []string{"host-1", "", "host-1"} {
+		rm := md.ResourceMetrics().At(i)
+		if host != "" {
+			rm.Resource().Attributes().InsertString("host.name", host)
+		}
+		rm.InstrumentationLibraryMetrics().Resize(1)
+		rm.InstrumentationLibraryMetrics().At(0).Metrics().Resize(1)
+		metric := rm.InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+		metric.SetName("requests")
+		metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		metric.IntGauge().DataPoints().Resize(2)
+	}
+
+	_, err := exp.pushMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	records := client.records()
+	require.Len(t, records, 2)
+	assert.Equal(t, "host-1", aws.StringValue(records[0].PartitionKey))
+	decoded := pdata.NewMetrics()
+	require.NoError(t, decoded.FromOtlpProtoBytes(records[0].Data))
+	assert.Equal(t, 2, decoded.MetricCount())
+	assert.NotEmpty(t, aws.StringValue(records[1].PartitionKey))
+}
+
+func TestPushLogsPartitionedByTraceID(t *testing.T) {
+	client := &kinesisMock{}
+	exp := newTestExporter(client)
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString("service.name", "frontend")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(3)
+	logs.At(0).SetTraceID(pdata.NewTraceID([16]byte{1}))
+	logs.At(2).SetTraceID(pdata.NewTraceID([16]byte{1}))
+
+	_, err := exp.pushLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	records := client.records()
+	require.Len(t, records, 2)
+	assert.Equal(t, "01000000000000000000000000000000", aws.StringValue(records[0].PartitionKey))
+	decoded := pdata.NewLogs()
+	require.NoError(t, decoded.FromOtlpProtoBytes(records[0].Data))
+	assert.Equal(t, 2, decoded.LogRecordCount())
+	service, _ := decoded.ResourceLogs().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "frontend", service.StringVal())
+	assert.NotEmpty(t, aws.StringValue(records[1].PartitionKey))
+}
+
+func TestPushTracesJSONGzip(t *testing.T) {
+	client := &kinesisMock{}
+	exp := newTestExporter(client)
+	exp.encoding = otlpJSONEncoding
+	exp.compression = gzipCompression
+
+	_, err := exp.pushTraces(context.Background(), newTraces("frontend", 1))
+	require.NoError(t, err)
+
+	records := client.records()
+	require.Len(t, records, 1)
+	r, err := gzip.NewReader(bytes.NewReader(records[0].Data))
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+
+	var request otlptrace.ExportTraceServiceRequest
+	require.NoError(t, protojson.Unmarshal(data, &request))
+	require.Len(t, request.ResourceSpans, 1)
+	assert.Equal(t, "span", request.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans[0].Name)
+}
+
+func TestRecordsSplitToMaximumSize(t *testing.T) {
+	client := &kinesisMock{}
+	exp := newTestExporter(client)
+
+	// a trace of 5 spans of 300KB, and a span larger than a record
+	td := newTraces("frontend", 1, 1, 1, 1, 1, 2)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < 5; i++ {
+		spans.At(i).Attributes().InsertString("payload", strings.Repeat("x", 300<<10))
+	}
+	spans.At(5).Attributes().InsertString("payload", strings.Repeat("x", maxRecordSize))
+
+	dropped, err := exp.pushTraces(context.Background(), td)
+	assert.Equal(t, 1, dropped)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.EqualError(t, err, "Permanent error: "+errRecordTooLarge.Error())
+
+	spanCount := 0
+	for _, r := range client.records() {
+		assert.LessOrEqual(t, len(r.Data)+len(aws.StringValue(r.PartitionKey)), maxRecordSize)
+		assert.Equal(t, "01000000000000000000000000000000", aws.StringValue(r.PartitionKey))
+		spanCount += decodeTraces(t, r.Data).SpanCount()
+	}
+	assert.Equal(t, 5, spanCount)
+	assert.Greater(t, len(client.records()), 1)
+}
+
+func TestRequestLimits(t *testing.T) {
+	client := &kinesisMock{}
+	exp := newTestExporter(client)
+
+	ids := make([]byte, 250)
+	for i := range ids {
+		ids[i] = byte(i)
+	}
+	td := newTraces("frontend", ids...)
+	newTraces("frontend", ids...).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	newTraces("frontend", 250, 251, 252).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+
+	_, err := exp.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	// the spans of the two first resources with the same trace ID are grouped in the same record
+	require.Len(t, client.requests, 1)
+	assert.Len(t, client.requests[0].Records, 253)
+
+	// 6 records of 900KB don't fit in a single request
+	client.requests = nil
+	td = newTraces("frontend", 1, 2, 3, 4, 5, 6)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).Attributes().InsertString("payload", strings.Repeat("x", 900<<10))
+	}
+	_, err = exp.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	require.Len(t, client.requests, 2)
+	assert.Len(t, client.requests[0].Records, 5)
+	assert.Len(t, client.requests[1].Records, 1)
+}
+
+func TestRequestRecordsCount(t *testing.T) {
+	records := make([]record, 1200)
+	for i := range records {
+		records[i] = record{entry: &kinesis.PutRecordsRequestEntry{Data: []byte("data"), PartitionKey: aws.String("key")}}
+	}
+	assert.Len(t, requestRecords(records), maxRequestRecords)
+	assert.Len(t, requestRecords(records[1000:]), 200)
+}
+
+func TestPushTracesThroughputExceeded(t *testing.T) {
+	client := &kinesisMock{respond: func(*kinesis.PutRecordsInput) (*kinesis.PutRecordsOutput, error) {
+		return nil, awserr.New(kinesis.ErrCodeProvisionedThroughputExceededException, "Rate exceeded for shard", nil)
+	}}
+	exp := newTestExporter(client)
+
+	td := newTraces("frontend", 1, 2)
+	_, err := exp.pushTraces(context.Background(), td)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	var partialErr consumererror.PartialError
+	require.True(t, errors.As(err, &partialErr))
+	assert.Equal(t, 2, partialErr.GetTraces().SpanCount())
+}
+
+func TestPushTracesFailedRecords(t *testing.T) {
+	client := &kinesisMock{respond: func(input *kinesis.PutRecordsInput) (*kinesis.PutRecordsOutput, error) {
+		out := &kinesis.PutRecordsOutput{FailedRecordCount: aws.Int64(1)}
+		for i := range input.Records {
+			result := &kinesis.PutRecordsResultEntry{SequenceNumber: aws.String("1"), ShardId: aws.String("shardId-000000000000")}
+			if i == 1 {
+				result = &kinesis.PutRecordsResultEntry{
+					ErrorCode:    aws.String(kinesis.ErrCodeProvisionedThroughputExceededException),
+					ErrorMessage: aws.String("Rate exceeded for shard shardId-000000000001"),
+				}
+			}
+			out.Records = append(out.Records, result)
+		}
+		return out, nil
+	}}
+	exp := newTestExporter(client)
+
+	td := newTraces("frontend", 1, 2, 3)
+	_, err := exp.pushTraces(context.Background(), td)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.EqualError(t, err, "1 records failed to be put, e.g. ProvisionedThroughputExceededException: Rate exceeded for shard shardId-000000000001")
+	var partialErr consumererror.PartialError
+	require.True(t, errors.As(err, &partialErr))
+	retry := partialErr.GetTraces()
+	require.Equal(t, 1, retry.SpanCount())
+	assert.Equal(t, pdata.NewTraceID([16]byte{2}), retry.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err       error
+		permanent bool
+	}{
+		{err: awserr.New(kinesis.ErrCodeProvisionedThroughputExceededException, "Rate exceeded", nil)},
+		{err: awserr.New(kinesis.ErrCodeKMSThrottlingException, "Throttled", nil)},
+		{err: awserr.New(request.CanceledErrorCode, "request context canceled", context.DeadlineExceeded)},
+		{err: awserr.New("RequestError", "send request failed", errors.New("connection reset"))},
+		{err: errors.New("connection reset")},
+		{err: awserr.New(kinesis.ErrCodeResourceNotFoundException, "Stream test-stream not found", nil), permanent: true},
+		{err: awserr.New("AccessDeniedException", "not authorized", nil), permanent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(classifyError(tt.err)))
+		})
+	}
+}
+
+func TestPartitionKey(t *testing.T) {
+	assert.Equal(t, "frontend", partitionKey("frontend"))
+	assert.Equal(t, strings.Repeat("é", maxPartitionKeyLength), partitionKey(strings.Repeat("é", 300)))
+}
@@ -0,0 +1,15 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awskinesisexporter
+
+go 1.14
+
+require (
+	github.com/aws/aws-sdk-go v1.36.28
+	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpertrace v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.18.0
+	go.opentelemetry.io/proto/otlp v0.7.0
+	go.uber.org/zap v1.16.0
+	google.golang.org/protobuf v1.25.0
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpertrace => ../../pkg/batchpertrace
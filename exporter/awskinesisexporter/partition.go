@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskinesisexporter
+
+import (
+	"math/rand"
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpertrace"
+)
+
+// group is the data sharing a partition key.
+type group struct {
+	key     string
+	payload payload
+}
+
+// tracesGroups groups the spans by the value of the partition key attribute of their resource, or by their trace ID.
+func tracesGroups(td pdata.Traces, attribute string) []group {
+	var keys []string
+	byKey := map[string]pdata.Traces{}
+	add := func(key string, rs pdata.ResourceSpans) {
+		traces, found := byKey[key]
+		if !found {
+			traces = pdata.NewTraces()
+			byKey[key] = traces
+			keys = append(keys, key)
+		}
+		traces.ResourceSpans().Append(rs)
+	}
+
+	rest := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if key := resourceKey(rs.Resource(), attribute); key != "" {
+			add(key, rs)
+		} else {
+			rest.ResourceSpans().Append(rs)
+		}
+	}
+	for _, trace := range batchpertrace.Split(rest) {
+		rs := trace.ResourceSpans().At(0)
+		add(rs.InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID().HexString(), rs)
+	}
+
+	groups := make([]group, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, group{key: key, payload: tracesPayload(byKey[key])})
+	}
+	return groups
+}
+
+// metricsGroups groups the metrics by the value of the partition key attribute of their resource, the metrics of
+// each of the other resources having a random key.
+func metricsGroups(md pdata.Metrics, attribute string) []group {
+	var keys []string
+	byKey := map[string]pdata.Metrics{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		key := resourceKey(rm.Resource(), attribute)
+		if key == "" {
+			key = randomKey()
+		}
+		metrics, found := byKey[key]
+		if !found {
+			metrics = pdata.NewMetrics()
+			byKey[key] = metrics
+			keys = append(keys, key)
+		}
+		metrics.ResourceMetrics().Append(rm)
+	}
+
+	groups := make([]group, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, group{key: key, payload: metricsPayload(byKey[key])})
+	}
+	return groups
+}
+
+// logsGroups groups the log records by the value of the partition key attribute of their resource, or by their
+// trace ID, the log records of each resource without a trace ID having a random key.
+func logsGroups(ld pdata.Logs, attribute string) []group {
+	var keys []string
+	byKey := map[string]pdata.Logs{}
+	add := func(key string, rl pdata.ResourceLogs) {
+		logs, found := byKey[key]
+		if !found {
+			logs = pdata.NewLogs()
+			byKey[key] = logs
+			keys = append(keys, key)
+		}
+		logs.ResourceLogs().Append(rl)
+	}
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if key := resourceKey(rl.Resource(), attribute); key != "" {
+			add(key, rl)
+			continue
+		}
+
+		untracedKey := ""
+		rlByKey := map[string]pdata.ResourceLogs{}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			illByKey := map[string]pdata.InstrumentationLibraryLogs{}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				log := logs.At(k)
+				var key string
+				if log.TraceID().IsValid() {
+					key = log.TraceID().HexString()
+				} else {
+					if untracedKey == "" {
+						untracedKey = randomKey()
+					}
+					key = untracedKey
+				}
+
+				keyILL, found := illByKey[key]
+				if !found {
+					keyRL, found := rlByKey[key]
+					if !found {
+						keyRL = pdata.NewResourceLogs()
+						rl.Resource().CopyTo(keyRL.Resource())
+						rlByKey[key] = keyRL
+						add(key, keyRL)
+					}
+					keyILL = pdata.NewInstrumentationLibraryLogs()
+					ill.InstrumentationLibrary().CopyTo(keyILL.InstrumentationLibrary())
+					keyRL.InstrumentationLibraryLogs().Append(keyILL)
+					illByKey[key] = keyILL
+				}
+				keyILL.Logs().Append(log)
+			}
+		}
+	}
+
+	groups := make([]group, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, group{key: key, payload: logsPayload(byKey[key])})
+	}
+	return groups
+}
+
+// resourceKey returns the value of the partition key attribute of the resource, empty when it's not set.
+func resourceKey(resource pdata.Resource, attribute string) string {
+	if attribute == "" {
+		return ""
+	}
+	if v, ok := resource.Attributes().Get(attribute); ok {
+		return tracetranslator.AttributeValueToString(v, false)
+	}
+	return ""
+}
+
+func randomKey() string {
+	return strconv.FormatUint(rand.Uint64(), 16)
+}
@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskinesisexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	otlplogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	otlpmetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	otlptrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// payload is the data of a record, the spans, metrics or log records sharing a partition key.
+type payload interface {
+	// items returns the number of spans, metrics or log records, the units the payload is split in.
+	items() int
+	// count returns the number of spans, metric data points or log records, as reported when they are dropped.
+	count() int
+	marshal(encoding string) ([]byte, error)
+	// split splits the payload in two halves, for their records not to exceed the maximum size.
+	split() (payload, payload)
+}
+
+type tracesPayload pdata.Traces
+
+func (p tracesPayload) items() int {
+	return pdata.Traces(p).SpanCount()
+}
+
+func (p tracesPayload) count() int {
+	return p.items()
+}
+
+func (p tracesPayload) marshal(encoding string) ([]byte, error) {
+	return marshalOTLP(pdata.Traces(p).ToOtlpProtoBytes, &otlptrace.ExportTraceServiceRequest{}, encoding)
+}
+
+func (p tracesPayload) split() (payload, payload) {
+	td := pdata.Traces(p)
+	half := td.SpanCount() / 2
+	first, second := pdata.NewTraces(), pdata.NewTraces()
+
+	index := 0
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		firstRS, secondRS := pdata.NewResourceSpans(), pdata.NewResourceSpans()
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			firstILS, secondILS := pdata.NewInstrumentationLibrarySpans(), pdata.NewInstrumentationLibrarySpans()
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if index < half {
+					firstILS.Spans().Append(spans.At(k))
+				} else {
+					secondILS.Spans().Append(spans.At(k))
+				}
+				index++
+			}
+			if firstILS.Spans().Len() > 0 {
+				ils.InstrumentationLibrary().CopyTo(firstILS.InstrumentationLibrary())
+				firstRS.InstrumentationLibrarySpans().Append(firstILS)
+			}
+			if secondILS.Spans().Len() > 0 {
+				ils.InstrumentationLibrary().CopyTo(secondILS.InstrumentationLibrary())
+				secondRS.InstrumentationLibrarySpans().Append(secondILS)
+			}
+		}
+		if firstRS.InstrumentationLibrarySpans().Len() > 0 {
+			rs.Resource().CopyTo(firstRS.Resource())
+			first.ResourceSpans().Append(firstRS)
+		}
+		if secondRS.InstrumentationLibrarySpans().Len() > 0 {
+			rs.Resource().CopyTo(secondRS.Resource())
+			second.ResourceSpans().Append(secondRS)
+		}
+	}
+	return tracesPayload(first), tracesPayload(second)
+}
+
+type metricsPayload pdata.Metrics
+
+func (p metricsPayload) items() int {
+	return pdata.Metrics(p).MetricCount()
+}
+
+func (p metricsPayload) count() int {
+	_, points := pdata.Metrics(p).MetricAndDataPointCount()
+	return points
+}
+
+func (p metricsPayload) marshal(encoding string) ([]byte, error) {
+	return marshalOTLP(pdata.Metrics(p).ToOtlpProtoBytes, &otlpmetrics.ExportMetricsServiceRequest{}, encoding)
+}
+
+func (p metricsPayload) split() (payload, payload) {
+	md := pdata.Metrics(p)
+	half := md.MetricCount() / 2
+	first, second := pdata.NewMetrics(), pdata.NewMetrics()
+
+	index := 0
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		firstRM, secondRM := pdata.NewResourceMetrics(), pdata.NewResourceMetrics()
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			firstILM, secondILM := pdata.NewInstrumentationLibraryMetrics(), pdata.NewInstrumentationLibraryMetrics()
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				if index < half {
+					firstILM.Metrics().Append(metrics.At(k))
+				} else {
+					secondILM.Metrics().Append(metrics.At(k))
+				}
+				index++
+			}
+			if firstILM.Metrics().Len() > 0 {
+				ilm.InstrumentationLibrary().CopyTo(firstILM.InstrumentationLibrary())
+				firstRM.InstrumentationLibraryMetrics().Append(firstILM)
+			}
+			if secondILM.Metrics().Len() > 0 {
+				ilm.InstrumentationLibrary().CopyTo(secondILM.InstrumentationLibrary())
+				secondRM.InstrumentationLibraryMetrics().Append(secondILM)
+			}
+		}
+		if firstRM.InstrumentationLibraryMetrics().Len() > 0 {
+			rm.Resource().CopyTo(firstRM.Resource())
+			first.ResourceMetrics().Append(firstRM)
+		}
+		if secondRM.InstrumentationLibraryMetrics().Len() > 0 {
+			rm.Resource().CopyTo(secondRM.Resource())
+			second.ResourceMetrics().Append(secondRM)
+		}
+	}
+	return metricsPayload(first), metricsPayload(second)
+}
+
+type logsPayload pdata.Logs
+
+func (p logsPayload) items() int {
+	return pdata.Logs(p).LogRecordCount()
+}
+
+func (p logsPayload) count() int {
+	return p.items()
+}
+
+func (p logsPayload) marshal(encoding string) ([]byte, error) {
+	return marshalOTLP(pdata.Logs(p).ToOtlpProtoBytes, &otlplogs.ExportLogsServiceRequest{}, encoding)
+}
+
+func (p logsPayload) split() (payload, payload) {
+	ld := pdata.Logs(p)
+	half := ld.LogRecordCount() / 2
+	first, second := pdata.NewLogs(), pdata.NewLogs()
+
+	index := 0
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		firstRL, secondRL := pdata.NewResourceLogs(), pdata.NewResourceLogs()
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			firstILL, secondILL := pdata.NewInstrumentationLibraryLogs(), pdata.NewInstrumentationLibraryLogs()
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				if index < half {
+					firstILL.Logs().Append(logs.At(k))
+				} else {
+					secondILL.Logs().Append(logs.At(k))
+				}
+				index++
+			}
+			if firstILL.Logs().Len() > 0 {
+				ill.InstrumentationLibrary().CopyTo(firstILL.InstrumentationLibrary())
+				firstRL.InstrumentationLibraryLogs().Append(firstILL)
+			}
+			if secondILL.Logs().Len() > 0 {
+				ill.InstrumentationLibrary().CopyTo(secondILL.InstrumentationLibrary())
+				secondRL.InstrumentationLibraryLogs().Append(secondILL)
+			}
+		}
+		if firstRL.InstrumentationLibraryLogs().Len() > 0 {
+			rl.Resource().CopyTo(firstRL.Resource())
+			first.ResourceLogs().Append(firstRL)
+		}
+		if secondRL.InstrumentationLibraryLogs().Len() > 0 {
+			rl.Resource().CopyTo(secondRL.Resource())
+			second.ResourceLogs().Append(secondRL)
+		}
+	}
+	return logsPayload(first), logsPayload(second)
+}
+
+// marshalOTLP returns the OTLP export request of the data as protobuf, or as JSON once decoded in the given message.
+func marshalOTLP(toProto func() ([]byte, error), message proto.Message, encoding string) ([]byte, error) {
+	data, err := toProto()
+	if err != nil || encoding == otlpProtoEncoding {
+		return data, err
+	}
+	if err = proto.Unmarshal(data, message); err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(message)
+}
@@ -18,46 +18,65 @@ package awsprometheusremotewriteexporter
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
 )
 
+// defaultService is the service signed for when none is configured, Amazon Managed Service for Prometheus.
+const defaultService = "aps"
+
+// expiryWindow is how long before their expiry the assumed role credentials are refreshed, so that they don't expire
+// while a request is being sent.
+const expiryWindow = time.Minute
+
 // signingRoundTripper is a Custom RoundTripper that performs AWS Sig V4
 type signingRoundTripper struct {
 	transport http.RoundTripper
 	signer    *v4.Signer
+	creds     *credentials.Credentials
 	region    string
 	service   string
 }
 
-// RoundTrip signs each outgoing request
+// RoundTrip signs each outgoing request. The request is signed once its body is compressed, and is sent again with
+// refreshed credentials when they were rejected as expired.
 func (si *signingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	reqBody, err := req.GetBody()
+	content, err := readBody(req)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get the body
-	content, err := ioutil.ReadAll(reqBody)
-	if err != nil {
-		return nil, err
+	resp, err := si.signAndSend(req, content)
+	if err != nil || !isExpiredCredentials(resp) {
+		return resp, err
 	}
+	resp.Body.Close()
+	si.creds.Expire()
+	return si.signAndSend(req, content)
+}
 
+func (si *signingRoundTripper) signAndSend(req *http.Request, content []byte) (*http.Response, error) {
 	body := bytes.NewReader(content)
 
 	// Clone request to ensure thread safety
 	req2 := cloneRequest(req)
 
 	// Sign the request
-	_, err = si.signer.Sign(req2, body, si.service, si.region, time.Now())
-	if err != nil {
-		return nil, err
+	if _, err := si.signer.Sign(req2, body, si.service, si.region, time.Now()); err != nil {
+		// The errors returned by the RoundTripper are permanent for the exporter which would drop the data, while
+		// failing to retrieve the credentials is usually transient: report it as an unavailable service for the
+		// request to be retried.
+		return unavailableResponse(req, fmt.Errorf("failed to sign the request: %w", err)), nil
 	}
 
 	// Send the request to Prometheus Remote Write Backend
@@ -69,6 +88,51 @@ func (si *signingRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 	return resp, err
 }
 
+func readBody(req *http.Request) ([]byte, error) {
+	if req.GetBody != nil {
+		reqBody, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadAll(reqBody)
+	}
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return ioutil.ReadAll(req.Body)
+}
+
+// isExpiredCredentials tells whether the request was rejected because its security token expired. The response body is
+// left to be read.
+func isExpiredCredentials(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	if strings.HasPrefix(resp.Header.Get("X-Amzn-Errortype"), "ExpiredTokenException") {
+		return true
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(content), resp.Body), resp.Body}
+	return err == nil && bytes.Contains(content, []byte("security token included in the request is expired"))
+}
+
+func unavailableResponse(req *http.Request, err error) *http.Response {
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable)),
+		StatusCode: http.StatusServiceUnavailable,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(err.Error())),
+		Request:    req,
+	}
+}
+
 func newSigningRoundTripper(auth AuthConfig, next http.RoundTripper) (http.RoundTripper, error) {
 	sess, err := session.NewSession(&aws.Config{
 		Region: aws.String(auth.Region)},
@@ -77,12 +141,17 @@ func newSigningRoundTripper(auth AuthConfig, next http.RoundTripper) (http.Round
 		return nil, err
 	}
 
-	if _, err = sess.Config.Credentials.Get(); err != nil {
-		return nil, err
-	}
-
 	// Get Credentials, either from ./aws or from environmental variables
 	creds := sess.Config.Credentials
+	if auth.RoleArn != "" {
+		creds = stscreds.NewCredentials(sess, auth.RoleArn, func(p *stscreds.AssumeRoleProvider) {
+			p.ExpiryWindow = expiryWindow
+		})
+	}
+
+	if _, err = creds.Get(); err != nil {
+		return nil, err
+	}
 
 	return createSigningRoundTripperWithCredentials(auth, creds, next)
 }
@@ -98,11 +167,16 @@ func createSigningRoundTripperWithCredentials(auth AuthConfig, creds *credential
 
 	signer := v4.NewSigner(creds)
 
+	service := auth.Service
+	if service == "" {
+		service = defaultService
+	}
 	rt := signingRoundTripper{
 		transport: next,
 		signer:    signer,
+		creds:     creds,
 		region:    auth.Region,
-		service:   auth.Service,
+		service:   service,
 	}
 
 	// return a RoundTripper
@@ -110,9 +184,8 @@ func createSigningRoundTripperWithCredentials(auth AuthConfig, creds *credential
 }
 
 func isValidAuth(params AuthConfig) bool {
-	return params.Region != "" && params.Service != ""
+	return params.Region != ""
 }
-
 func cloneRequest(r *http.Request) *http.Request {
 	// shallow copy of the struct
 	r2 := new(http.Request)
@@ -16,16 +16,21 @@
 package awsprometheusremotewriteexporter
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
 )
@@ -178,6 +183,146 @@ func TestCreateSigningRoundTripperWithCredentials(t *testing.T) {
 	}
 }
 
+func TestRoundTripSignsBody(t *testing.T) {
+	awsCreds := fetchMockCredentials()
+	content := []byte("compressed\x00content")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, content, body)
+
+		// Signing the received request again gives the same signature when the body hash matches.
+		signTime, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+		require.NoError(t, err)
+		expected, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.String(), nil)
+		require.NoError(t, err)
+		expected.Header.Set("Content-Encoding", r.Header.Get("Content-Encoding"))
+		_, err = v4.NewSigner(awsCreds).Sign(expected, bytes.NewReader(body), "aps", "region", signTime)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Header.Get("Authorization"), r.Header.Get("Authorization"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/region/aps/aws4_request")
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	rt, err := createSigningRoundTripperWithCredentials(AuthConfig{Region: "region"}, awsCreds, http.DefaultTransport)
+	require.NoError(t, err)
+	req, err := http.NewRequest("POST", server.URL, bytes.NewReader(content))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "snappy")
+	res, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+}
+
+type countingProvider struct {
+	retrieved int
+	err       error
+}
+
+func (p *countingProvider) Retrieve() (credentials.Value, error) {
+	p.retrieved++
+	if p.err != nil {
+		return credentials.Value{}, p.err
+	}
+	return credentials.Value{
+		AccessKeyID:     fmt.Sprintf("MOCK_AWS_ACCESS_KEY_%d", p.retrieved),
+		SecretAccessKey: "MOCK_AWS_SECRET_ACCESS_KEY",
+		SessionToken:    "MOCK_TOKEN",
+	}, nil
+}
+
+func (p *countingProvider) IsExpired() bool {
+	return false
+}
+
+func TestRoundTripRefreshesExpiredCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		body     string
+		requests int
+	}{
+		{
+			name:     "expired token header",
+			header:   "ExpiredTokenException:http://internal.amazon.com/coral/com.amazon.coral.service/",
+			requests: 2,
+		},
+		{
+			name:     "expired token message",
+			body:     `{"message":"The security token included in the request is expired"}`,
+			requests: 2,
+		},
+		{
+			name:     "access denied",
+			body:     `{"message":"User is not authorized to perform: aps:RemoteWrite"}`,
+			requests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accessKeys []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				accessKeys = append(accessKeys, strings.Split(strings.Split(r.Header.Get("Authorization"), "Credential=")[1], "/")[0])
+				if len(accessKeys) > 1 {
+					w.WriteHeader(200)
+					return
+				}
+				if tt.header != "" {
+					w.Header().Set("X-Amzn-Errortype", tt.header)
+				}
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := &countingProvider{}
+			rt, err := createSigningRoundTripperWithCredentials(AuthConfig{Region: "region"}, credentials.NewCredentials(provider), http.DefaultTransport)
+			require.NoError(t, err)
+			req, err := http.NewRequest("POST", server.URL, strings.NewReader("content"))
+			require.NoError(t, err)
+			res, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			require.Len(t, accessKeys, tt.requests)
+			assert.Equal(t, tt.requests, provider.retrieved)
+			if tt.requests == 1 {
+				assert.Equal(t, http.StatusForbidden, res.StatusCode)
+				body, err := ioutil.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+				return
+			}
+			assert.Equal(t, []string{"MOCK_AWS_ACCESS_KEY_1", "MOCK_AWS_ACCESS_KEY_2"}, accessKeys)
+			assert.Equal(t, 200, res.StatusCode)
+		})
+	}
+}
+
+func TestRoundTripCredentialsError(t *testing.T) {
+	provider := &countingProvider{err: errors.New("no credentials")}
+	rt, err := createSigningRoundTripperWithCredentials(AuthConfig{Region: "region"}, credentials.NewCredentials(provider), &ErrorRoundTripper{})
+	require.NoError(t, err)
+	req, err := http.NewRequest("POST", "http://localhost", strings.NewReader("content"))
+	require.NoError(t, err)
+
+	// The request is reported as failed with a retryable status rather than an error, dropping the data.
+	res, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "failed to sign the request: no credentials")
+}
+
+func TestDefaultService(t *testing.T) {
+	rt, err := createSigningRoundTripperWithCredentials(AuthConfig{Region: "region"}, fetchMockCredentials(), http.DefaultTransport)
+	require.NoError(t, err)
+	assert.Equal(t, "aps", rt.(*signingRoundTripper).service)
+}
+
 func TestCloneRequest(t *testing.T) {
 	req1, err := http.NewRequest("GET", "https://example.com", nil)
 	assert.NoError(t, err)
@@ -32,6 +32,9 @@ type Config struct {
 type AuthConfig struct {
 	// Region is the AWS region for AWS Sig v4.
 	Region string `mapstructure:"region"`
-	// Service is the service name for AWS Sig v4
+	// Service is the service name for AWS Sig v4, "aps" when not set.
 	Service string `mapstructure:"service"`
+	// RoleArn is the ARN of the role assumed through STS to sign the requests, the credentials of the default chain
+	// being used directly when not set.
+	RoleArn string `mapstructure:"role_arn"`
 }
@@ -95,6 +95,7 @@ func TestLoadConfig(t *testing.T) {
 		AuthConfig: AuthConfig{
 			Region:  "us-west-2",
 			Service: "service-name",
+			RoleArn: "arn:aws:iam::123456789012:role/prometheus-remote-write",
 		},
 	}
 	// testing function equality is not supported in Go hence these will be ignored for this test
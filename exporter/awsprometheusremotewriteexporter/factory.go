@@ -69,5 +69,5 @@ func (af *awsFactory) CreateDefaultConfig() configmodels.Exporter {
 }
 
 func isAuthConfigValid(params AuthConfig) bool {
-	return !(params.Region != "" && params.Service == "" || params.Region == "" && params.Service != "")
+	return params.Region != "" || params.Service == "" && params.RoleArn == ""
 }
@@ -57,6 +57,9 @@ func TestCreateMetricsExporter(t *testing.T) {
 	invalidConfigWithAuth := af.CreateDefaultConfig().(*Config)
 	invalidConfigWithAuth.AuthConfig = AuthConfig{Region: "", Service: "service"}
 
+	invalidRoleConfig := af.CreateDefaultConfig().(*Config)
+	invalidRoleConfig.AuthConfig = AuthConfig{RoleArn: "arn:aws:iam::123456789012:role/prometheus"}
+
 	invalidConfig := af.CreateDefaultConfig().(*Config)
 	invalidConfig.HTTPClientSettings = confighttp.HTTPClientSettings{}
 
@@ -92,6 +95,11 @@ func TestCreateMetricsExporter(t *testing.T) {
 			component.ExporterCreateParams{Logger: zap.NewNop()},
 			true,
 		},
+		{"invalid_role_case",
+			invalidRoleConfig,
+			component.ExporterCreateParams{Logger: zap.NewNop()},
+			true,
+		},
 		{"invalid_config_case",
 			invalidConfig,
 			component.ExporterCreateParams{Logger: zap.NewNop()},
@@ -26,7 +26,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter/translator"
+	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/awsxray"
 )
 
 const (
@@ -45,6 +45,8 @@ func newTraceExporter(
 		return nil, err
 	}
 	xrayClient := newXRay(logger, awsConfig, params.ApplicationStartInfo, session)
+	indexer := translator.NewAttributeIndexer(config.(*Config).IndexedAttributes,
+		config.(*Config).IndexedResourceAttributes, config.(*Config).IndexAllAttributes)
 	return exporterhelper.NewTraceExporter(
 		config,
 		logger,
@@ -58,8 +60,7 @@ func newTraceExporter(
 				for j := 0; j < rspans.InstrumentationLibrarySpans().Len(); j++ {
 					spans := rspans.InstrumentationLibrarySpans().At(j).Spans()
 					for k := 0; k < spans.Len(); k++ {
-						document, localErr := translator.MakeSegmentDocumentString(spans.At(k), resource,
-							config.(*Config).IndexedAttributes, config.(*Config).IndexAllAttributes)
+						document, localErr := translator.MakeSegmentDocumentString(spans.At(k), resource, indexer)
 						if localErr != nil {
 							logger.Debug("Error translating span.", zap.Error(localErr))
 							totalDroppedSpans++
@@ -41,8 +41,12 @@ type Config struct {
 	RoleARN string `mapstructure:"role_arn"`
 	// By default, OpenTelemetry attributes are converted to X-Ray metadata, which are not indexed.
 	// Specify a list of attribute names to be converted to X-Ray annotations instead, which will be indexed.
+	// A name ending with .* matches all the names with this prefix, e.g. telemetry.*, and * matches all the names.
 	// See annotation vs. metadata: https://docs.aws.amazon.com/xray/latest/devguide/xray-concepts.html#xray-concepts-annotations
 	IndexedAttributes []string `mapstructure:"indexed_attributes"`
+	// Specify a list of resource attribute names to be converted to X-Ray annotations, with the same wildcards as
+	// IndexedAttributes. The resource attributes are only stored in the segments, not in the subsegments.
+	IndexedResourceAttributes []string `mapstructure:"indexed_resource_attributes"`
 	// Set to true to convert all OpenTelemetry attributes to X-Ray annotation (indexed) ignoring the IndexedAttributes option.
 	// Default value: false
 	IndexAllAttributes bool `mapstructure:"index_all_attributes"`
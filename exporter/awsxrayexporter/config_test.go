@@ -46,18 +46,19 @@ func TestLoadConfig(t *testing.T) {
 	r1 := cfg.Exporters["awsxray/customname"].(*Config)
 	assert.Equal(t, r1,
 		&Config{
-			ExporterSettings:      configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "awsxray/customname"},
-			NumberOfWorkers:       8,
-			Endpoint:              "",
-			RequestTimeoutSeconds: 30,
-			MaxRetries:            2,
-			NoVerifySSL:           false,
-			ProxyAddress:          "",
-			Region:                "eu-west-1",
-			LocalMode:             false,
-			ResourceARN:           "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u",
-			RoleARN:               "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
-			IndexedAttributes:     []string{"indexed_attr_0", "indexed_attr_1"},
-			IndexAllAttributes:    false,
+			ExporterSettings:          configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "awsxray/customname"},
+			NumberOfWorkers:           8,
+			Endpoint:                  "",
+			RequestTimeoutSeconds:     30,
+			MaxRetries:                2,
+			NoVerifySSL:               false,
+			ProxyAddress:              "",
+			Region:                    "eu-west-1",
+			LocalMode:                 false,
+			ResourceARN:               "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u",
+			RoleARN:                   "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
+			IndexedAttributes:         []string{"indexed_attr_0", "indexed_attr_1", "telemetry.*"},
+			IndexedResourceAttributes: []string{"service.name", "k8s.*"},
+			IndexAllAttributes:        false,
 		})
 }
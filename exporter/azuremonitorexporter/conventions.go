@@ -31,6 +31,7 @@ const (
 	attributeOtelStatusCode           string = "otel.status_code"
 	attributeOtelStatusDeprecatedCode string = "otel.status_deprecatedcode"
 	attributeOtelStatusDescription    string = "otel.status_description"
+	attributeOtelBodyTruncated        string = "otel.body_truncated"
 )
 
 // NetworkAttributes is the set of known network attributes
@@ -65,3 +65,38 @@ func TestCreateTraceExporterUsingBadConfig(t *testing.T) {
 	assert.Nil(t, exporter)
 	assert.NotNil(t, err)
 }
+
+func TestCreateLogsExporterUsingSpecificTransportChannel(t *testing.T) {
+	// mock transport channel creation
+	f := factory{tChannel: &mockTransportChannel{}}
+	ctx := context.Background()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := f.createLogsExporter(ctx, params, createDefaultConfig())
+	assert.NotNil(t, exporter)
+	assert.Nil(t, err)
+}
+
+func TestCreateLogsExporterSharesTransportChannel(t *testing.T) {
+	// The trace and logs exporters send through the same transport channel
+	f := factory{}
+	ctx := context.Background()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	_, err := f.createTraceExporter(ctx, params, createDefaultConfig())
+	assert.Nil(t, err)
+	tChannel := f.tChannel
+
+	exporter, err := f.createLogsExporter(ctx, params, createDefaultConfig())
+	assert.NotNil(t, exporter)
+	assert.Nil(t, err)
+	assert.Same(t, tChannel, f.tChannel)
+}
+
+func TestCreateLogsExporterUsingBadConfig(t *testing.T) {
+	f := factory{}
+	ctx := context.Background()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	exporter, err := f.createLogsExporter(ctx, params, &badConfig{})
+	assert.Nil(t, exporter)
+	assert.NotNil(t, err)
+}
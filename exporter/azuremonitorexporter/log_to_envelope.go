@@ -0,0 +1,123 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+// The maximum length of MessageData.Message, longer messages being truncated
+const maxMessageLength = 32768
+
+// Transforms a tuple of pdata.Resource, pdata.InstrumentationLibrary, pdata.LogRecord into an AppInsights
+// contracts.Envelope holding a MessageData, i.e. a trace telemetry item
+func logRecordToEnvelope(
+	resource pdata.Resource,
+	instrumentationLibrary pdata.InstrumentationLibrary,
+	logRecord pdata.LogRecord,
+	logger *zap.Logger) *contracts.Envelope {
+
+	envelope := contracts.NewEnvelope()
+	envelope.Tags = make(map[string]string)
+	envelope.Time = toTime(logRecord.Timestamp()).Format(time.RFC3339Nano)
+
+	// Correlate the log record with the request or dependency of its span
+	if traceID := logRecord.TraceID(); !traceID.IsEmpty() {
+		envelope.Tags[contracts.OperationId] = traceID.HexString()
+	}
+	if spanID := logRecord.SpanID(); !spanID.IsEmpty() {
+		envelope.Tags[contracts.OperationParentId] = spanID.HexString()
+	}
+
+	data := contracts.NewData()
+	messageData := contracts.NewMessageData()
+	messageData.SeverityLevel = severityNumberToSeverityLevel(logRecord.SeverityNumber())
+	messageData.Properties = make(map[string]string)
+
+	message := tracetranslator.AttributeValueToString(logRecord.Body(), false)
+	if len(message) > maxMessageLength {
+		message = truncate(message, maxMessageLength)
+		messageData.Properties[attributeOtelBodyTruncated] = "true"
+	}
+	messageData.Message = message
+
+	// Copy all the resource and log record attributes into the properties, the latter taking precedence
+	resourceAttributes := resource.Attributes()
+	copyAttributesAsProperties(resourceAttributes, messageData.Properties)
+	copyAttributesAsProperties(logRecord.Attributes(), messageData.Properties)
+
+	// Copy the instrumentation properties
+	if instrumentationLibrary.Name() != "" {
+		messageData.Properties[instrumentationLibraryName] = instrumentationLibrary.Name()
+	}
+
+	if instrumentationLibrary.Version() != "" {
+		messageData.Properties[instrumentationLibraryVersion] = instrumentationLibrary.Version()
+	}
+
+	envelope.Name = messageData.EnvelopeName("")
+	data.BaseData = messageData
+	data.BaseType = messageData.BaseType()
+	envelope.Data = data
+
+	applyCloudTagsToEnvelope(envelope, resourceAttributes)
+
+	// Sanitize the base data, the envelope and envelope tags
+	sanitize(func() []string { return messageData.Sanitize() }, logger)
+	sanitize(func() []string { return envelope.Sanitize() }, logger)
+	sanitize(func() []string { return contracts.SanitizeTags(envelope.Tags) }, logger)
+
+	return envelope
+}
+
+// Maps the SeverityNumber ranges defined by the log data model to an AppInsights SeverityLevel
+// https://github.com/open-telemetry/opentelemetry-specification/blob/master/specification/logs/data-model.md#severity-fields
+func severityNumberToSeverityLevel(severityNumber pdata.SeverityNumber) contracts.SeverityLevel {
+	switch {
+	case severityNumber == pdata.SeverityNumberUNDEFINED:
+		return contracts.Information
+	case severityNumber < pdata.SeverityNumberINFO:
+		return contracts.Verbose
+	case severityNumber < pdata.SeverityNumberWARN:
+		return contracts.Information
+	case severityNumber < pdata.SeverityNumberERROR:
+		return contracts.Warning
+	case severityNumber < pdata.SeverityNumberFATAL:
+		return contracts.Error
+	default:
+		return contracts.Critical
+	}
+}
+
+// Copies all attributes to properties, as there are no measurements on MessageData
+func copyAttributesAsProperties(attributeMap pdata.AttributeMap, properties map[string]string) {
+	attributeMap.ForEach(func(k string, v pdata.AttributeValue) {
+		properties[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+}
+
+// Truncates s to at most maxLength bytes without splitting a UTF-8 encoded rune
+func truncate(s string, maxLength int) string {
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+	return s[:maxLength]
+}
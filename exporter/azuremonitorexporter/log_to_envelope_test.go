@@ -0,0 +1,130 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+var (
+	defaultLogTraceID = pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	defaultLogSpanID  = pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+)
+
+func TestLogRecordToEnvelope(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 6, time.UTC)
+	logRecord := getDefaultLogRecord(ts)
+
+	envelope := logRecordToEnvelope(getResource(), getInstrumentationLibrary(), logRecord, zap.NewNop())
+
+	assert.Equal(t, "Microsoft.ApplicationInsights.Message", envelope.Name)
+	assert.Equal(t, ts.Format(time.RFC3339Nano), envelope.Time)
+	assert.Equal(t, defaultLogTraceID.HexString(), envelope.Tags[contracts.OperationId])
+	assert.Equal(t, defaultLogSpanID.HexString(), envelope.Tags[contracts.OperationParentId])
+	assert.Equal(t, defaultServiceNamespace+"."+defaultServiceName, envelope.Tags[contracts.CloudRole])
+	assert.Equal(t, defaultServiceInstance, envelope.Tags[contracts.CloudRoleInstance])
+
+	data := envelope.Data.(*contracts.Data)
+	assert.Equal(t, "MessageData", data.BaseType)
+	messageData := data.BaseData.(*contracts.MessageData)
+	assert.Equal(t, "something happened", messageData.Message)
+	assert.Equal(t, contracts.Warning, messageData.SeverityLevel)
+
+	assert.Equal(t, "bar", messageData.Properties["foo"])
+	assert.Equal(t, "42", messageData.Properties["count"])
+	assert.Equal(t, defaultServiceName, messageData.Properties[conventions.AttributeServiceName])
+	assert.Equal(t, defaultInstrumentationLibraryName, messageData.Properties[instrumentationLibraryName])
+	assert.Equal(t, defaultInstrumentationLibraryVersion, messageData.Properties[instrumentationLibraryVersion])
+	assert.NotContains(t, messageData.Properties, attributeOtelBodyTruncated)
+}
+
+func TestLogRecordToEnvelopeWithoutTraceContext(t *testing.T) {
+	logRecord := pdata.NewLogRecord()
+	logRecord.Body().SetStringVal("no trace")
+
+	envelope := logRecordToEnvelope(pdata.NewResource(), pdata.NewInstrumentationLibrary(), logRecord, zap.NewNop())
+
+	assert.NotContains(t, envelope.Tags, contracts.OperationId)
+	assert.NotContains(t, envelope.Tags, contracts.OperationParentId)
+	messageData := envelope.Data.(*contracts.Data).BaseData.(*contracts.MessageData)
+	assert.Equal(t, contracts.Information, messageData.SeverityLevel)
+}
+
+func TestLogRecordToEnvelopeTruncatesBody(t *testing.T) {
+	logRecord := pdata.NewLogRecord()
+	// The multi-byte runes make the limit fall in the middle of one
+	logRecord.Body().SetStringVal("a" + strings.Repeat("é", maxMessageLength))
+
+	envelope := logRecordToEnvelope(pdata.NewResource(), pdata.NewInstrumentationLibrary(), logRecord, zap.NewNop())
+
+	messageData := envelope.Data.(*contracts.Data).BaseData.(*contracts.MessageData)
+	assert.Equal(t, "a"+strings.Repeat("é", (maxMessageLength-2)/2), messageData.Message)
+	assert.Equal(t, "true", messageData.Properties[attributeOtelBodyTruncated])
+}
+
+func TestSeverityNumberToSeverityLevel(t *testing.T) {
+	tests := []struct {
+		severityNumber pdata.SeverityNumber
+		want           contracts.SeverityLevel
+	}{
+		{pdata.SeverityNumberUNDEFINED, contracts.Information},
+		{pdata.SeverityNumberTRACE, contracts.Verbose},
+		{pdata.SeverityNumberDEBUG4, contracts.Verbose},
+		{pdata.SeverityNumberINFO, contracts.Information},
+		{pdata.SeverityNumberINFO4, contracts.Information},
+		{pdata.SeverityNumberWARN, contracts.Warning},
+		{pdata.SeverityNumberERROR2, contracts.Error},
+		{pdata.SeverityNumberFATAL, contracts.Critical},
+		{pdata.SeverityNumberFATAL4, contracts.Critical},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, severityNumberToSeverityLevel(tt.severityNumber), "severity number %d", tt.severityNumber)
+	}
+}
+
+func getDefaultLogRecord(ts time.Time) pdata.LogRecord {
+	logRecord := pdata.NewLogRecord()
+	logRecord.SetTimestamp(pdata.TimestampUnixNano(ts.UnixNano()))
+	logRecord.SetTraceID(defaultLogTraceID)
+	logRecord.SetSpanID(defaultLogSpanID)
+	logRecord.SetSeverityNumber(pdata.SeverityNumberWARN)
+	logRecord.Body().SetStringVal("something happened")
+	logRecord.Attributes().InsertString("foo", "bar")
+	logRecord.Attributes().InsertInt("count", 42)
+	return logRecord
+}
+
+func getTestLogs(logRecords ...pdata.LogRecord) pdata.Logs {
+	logs := pdata.NewLogs()
+	logs.ResourceLogs().Resize(1)
+	rl := logs.ResourceLogs().At(0)
+	getResource().CopyTo(rl.Resource())
+	rl.InstrumentationLibraryLogs().Resize(1)
+	ill := rl.InstrumentationLibraryLogs().At(0)
+	getInstrumentationLibrary().CopyTo(ill.InstrumentationLibrary())
+	ill.Logs().Resize(len(logRecords))
+	for i, logRecord := range logRecords {
+		logRecord.CopyTo(ill.Logs().At(i))
+	}
+	return logs
+}
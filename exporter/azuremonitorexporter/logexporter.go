@@ -0,0 +1,70 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+)
+
+type logExporter struct {
+	config           *Config
+	transportChannel transportChannel
+	logger           *zap.Logger
+}
+
+func (exporter *logExporter) onLogData(context context.Context, logData pdata.Logs) (droppedLogs int, err error) {
+	resourceLogs := logData.ResourceLogs()
+
+	for i := 0; i < resourceLogs.Len(); i++ {
+		rl := resourceLogs.At(i)
+		resource := rl.Resource()
+		instrumentationLibraryLogsSlice := rl.InstrumentationLibraryLogs()
+
+		for j := 0; j < instrumentationLibraryLogsSlice.Len(); j++ {
+			instrumentationLibraryLogs := instrumentationLibraryLogsSlice.At(j)
+			instrumentationLibrary := instrumentationLibraryLogs.InstrumentationLibrary()
+			logs := instrumentationLibraryLogs.Logs()
+
+			for k := 0; k < logs.Len(); k++ {
+				envelope := logRecordToEnvelope(resource, instrumentationLibrary, logs.At(k), exporter.logger)
+
+				// apply the instrumentation key to the envelope
+				envelope.IKey = exporter.config.InstrumentationKey
+
+				// This is a fire and forget operation
+				exporter.transportChannel.Send(envelope)
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+// Returns a new instance of the log exporter
+func newLogsExporter(config *Config, transportChannel transportChannel, logger *zap.Logger) (component.LogsExporter, error) {
+
+	exporter := &logExporter{
+		config:           config,
+		transportChannel: transportChannel,
+		logger:           logger,
+	}
+
+	return exporterhelper.NewLogsExporter(config, logger, exporter.onLogData)
+}
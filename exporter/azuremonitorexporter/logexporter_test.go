@@ -0,0 +1,66 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// Tests the export onLogData callback with no log records
+func TestExporterLogDataCallbackNoLogs(t *testing.T) {
+	mockTransportChannel := getMockTransportChannel()
+	exporter := getLogExporter(defaultConfig, mockTransportChannel)
+
+	droppedLogs, err := exporter.onLogData(context.Background(), pdata.NewLogs())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, droppedLogs)
+
+	mockTransportChannel.AssertNumberOfCalls(t, "Send", 0)
+}
+
+// Tests the export onLogData callback with several log records
+func TestExporterLogDataCallbackMultipleLogs(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.InstrumentationKey = "abcdefg"
+	mockTransportChannel := getMockTransportChannel()
+	exporter := getLogExporter(config, mockTransportChannel)
+
+	logs := getTestLogs(getDefaultLogRecord(time.Now()), getDefaultLogRecord(time.Now()))
+
+	droppedLogs, err := exporter.onLogData(context.Background(), logs)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, droppedLogs)
+
+	mockTransportChannel.AssertNumberOfCalls(t, "Send", 2)
+	mockTransportChannel.AssertCalled(t, "Send", mock.MatchedBy(func(envelope *contracts.Envelope) bool {
+		return envelope.IKey == "abcdefg"
+	}))
+}
+
+func getLogExporter(config *Config, transportChannel transportChannel) *logExporter {
+	return &logExporter{
+		config,
+		transportChannel,
+		zap.NewNop(),
+	}
+}
@@ -128,8 +128,19 @@ func spanToEnvelope(
 		dataProperties[instrumentationLibraryVersion] = instrumentationLibrary.Version()
 	}
 
-	// Extract key service.* labels from the Resource labels and construct CloudRole and CloudRoleInstance envelope tags
-	// https://github.com/open-telemetry/opentelemetry-specification/tree/master/specification/resource/semantic_conventions
+	applyCloudTagsToEnvelope(envelope, resourceAttributes)
+
+	// Sanitize the base data, the envelope and envelope tags
+	sanitize(dataSanitizeFunc, logger)
+	sanitize(func() []string { return envelope.Sanitize() }, logger)
+	sanitize(func() []string { return contracts.SanitizeTags(envelope.Tags) }, logger)
+
+	return envelope, nil
+}
+
+// Extracts key service.* labels from the Resource labels and constructs CloudRole and CloudRoleInstance envelope tags
+// https://github.com/open-telemetry/opentelemetry-specification/tree/master/specification/resource/semantic_conventions
+func applyCloudTagsToEnvelope(envelope *contracts.Envelope, resourceAttributes pdata.AttributeMap) {
 	if serviceName, serviceNameExists := resourceAttributes.Get(conventions.AttributeServiceName); serviceNameExists {
 		cloudRole := serviceName.StringVal()
 
@@ -143,13 +154,6 @@ func spanToEnvelope(
 	if serviceInstance, exists := resourceAttributes.Get(conventions.AttributeServiceInstance); exists {
 		envelope.Tags[contracts.CloudRoleInstance] = serviceInstance.StringVal()
 	}
-
-	// Sanitize the base data, the envelope and envelope tags
-	sanitize(dataSanitizeFunc, logger)
-	sanitize(func() []string { return envelope.Sanitize() }, logger)
-	sanitize(func() []string { return contracts.SanitizeTags(envelope.Tags) }, logger)
-
-	return envelope, nil
 }
 
 // Maps Server/Consumer Span to AppInsights RequestData
@@ -48,6 +48,8 @@ func newCarbonExporter(cfg *Config, params component.ExporterCreateParams) (comp
 		&cfg.ExporterSettings,
 		params.Logger,
 		sender.pushMetricsData,
+		exporterhelper.WithRetry(cfg.RetrySettings),
+		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithShutdown(sender.Shutdown))
 }
 
@@ -74,15 +76,20 @@ func (cs *carbonSender) Shutdown(context.Context) error {
 	return nil
 }
 
+// maxIdleConns is the maximum number of idle connections kept by connPool.
+const maxIdleConns = 4
+
 // connPool is a very simple implementation of a pool of net.TCPConn instances.
 // The implementation hides the pool and exposes a Write and Close methods.
 // It leverages the prior art from SignalFx Gateway (see
 // https://github.com/signalfx/gateway/blob/master/protocol/carbon/conn_pool.go
 // but not its implementation).
 //
-// It keeps a unbounded "stack" of TCPConn instances always "popping" the most
-// recently returned to the pool. There is no accounting to terminating old
-// unused connections as that was the case on the prior art mentioned above.
+// It keeps a "stack" of at most maxIdleConns TCPConn instances always
+// "popping" the most recently returned to the pool. There is no accounting to
+// terminating old unused connections as that was the case on the prior art
+// mentioned above. A connection is closed on any error, so the next write
+// uses a new one.
 type connPool struct {
 	mtx      sync.Mutex
 	conns    []*net.TCPConn
@@ -101,36 +108,62 @@ func newTCPConnPool(
 }
 
 func (cp *connPool) Write(bytes []byte) (int, error) {
-	var conn *net.TCPConn
-	var err error
-
-	// The deferred function below is what puts back connections on the pool.
-	defer func() {
-		if err == nil {
-			cp.mtx.Lock()
-			cp.conns = append(cp.conns, conn)
-			cp.mtx.Unlock()
-		} else {
-			if conn != nil {
-				conn.Close()
-			}
+	conn, pooled, err := cp.getConn()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := cp.write(conn, bytes)
+	if err != nil && pooled && n == 0 {
+		// The server may have closed the pooled connection since it was last
+		// used, reconnect once before giving up on the data.
+		conn.Close()
+		if conn, err = cp.createTCPConn(); err != nil {
+			return 0, err
 		}
-	}()
+		n, err = cp.write(conn, bytes)
+	}
+
+	if err != nil {
+		conn.Close()
+		return n, err
+	}
 
-	start := time.Now()
+	cp.putConn(conn)
+	return n, nil
+}
+
+// getConn pops the most recently used connection from the pool, or creates
+// a new one if the pool is empty. It reports if the connection was pooled.
+func (cp *connPool) getConn() (*net.TCPConn, bool, error) {
 	cp.mtx.Lock()
 	lastIdx := len(cp.conns) - 1
 	if lastIdx >= 0 {
-		conn = cp.conns[lastIdx]
+		conn := cp.conns[lastIdx]
 		cp.conns = cp.conns[0:lastIdx]
+		cp.mtx.Unlock()
+		return conn, true, nil
 	}
 	cp.mtx.Unlock()
-	if conn == nil {
-		if conn, err = cp.createTCPConn(); err != nil {
-			return 0, err
-		}
+
+	conn, err := cp.createTCPConn()
+	return conn, false, err
+}
+
+// putConn puts back the connection on the pool, closing it instead if the
+// pool already has maxIdleConns connections.
+func (cp *connPool) putConn(conn *net.TCPConn) {
+	cp.mtx.Lock()
+	defer cp.mtx.Unlock()
+
+	if len(cp.conns) >= maxIdleConns {
+		conn.Close()
+		return
 	}
+	cp.conns = append(cp.conns, conn)
+}
 
+func (cp *connPool) write(conn *net.TCPConn, bytes []byte) (int, error) {
 	// There is no way to do a call equivalent to recvfrom with an empty buffer
 	// to check if the connection was terminated (if the size of the buffer is
 	// 0 the Read call doesn't call lower level). So due to buffer sizes it is
@@ -150,13 +183,11 @@ func (cp *connPool) Write(bytes []byte) (int, error) {
 	// needed in some scenarios the workaround should be validated on other
 	// platforms and offered as a configuration setting.
 
-	if err = conn.SetWriteDeadline(start.Add(cp.timeout)); err != nil {
+	if err := conn.SetWriteDeadline(time.Now().Add(cp.timeout)); err != nil {
 		return 0, err
 	}
 
-	var n int
-	n, err = conn.Write(bytes)
-	return n, err
+	return conn.Write(bytes)
 }
 
 func (cp *connPool) Close() {
@@ -282,6 +282,63 @@ func Test_connPool_Concurrency(t *testing.T) {
 	recvWG.Wait()
 }
 
+func Test_connPool_Reconnect(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	ln, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err == nil {
+					lines <- line
+				}
+			}(conn)
+		}
+	}()
+
+	cp := newTCPConnPool(addr, 500*time.Millisecond)
+	defer cp.Close()
+
+	_, err = cp.Write([]byte("first 1 1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first 1 1\n", <-lines)
+
+	// Break the pooled connection, the next write must reconnect.
+	require.Len(t, cp.conns, 1)
+	cp.conns[0].Close()
+
+	_, err = cp.Write([]byte("second 2 2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "second 2 2\n", <-lines)
+	assert.Len(t, cp.conns, 1)
+}
+
+func Test_connPool_MaxIdleConns(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	ln, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	cp := newTCPConnPool(addr, 500*time.Millisecond)
+	defer cp.Close()
+
+	for i := 0; i < maxIdleConns+2; i++ {
+		conn, err := cp.createTCPConn()
+		require.NoError(t, err)
+		cp.putConn(conn)
+	}
+	assert.Len(t, cp.conns, maxIdleConns)
+}
+
 func generateLargeBatch() pdata.Metrics {
 	md := consumerdata.MetricsData{
 		Node: &commonpb.Node{
@@ -41,8 +41,10 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		Endpoint: DefaultEndpoint,
-		Timeout:  DefaultSendTimeout,
+		Endpoint:      DefaultEndpoint,
+		Timeout:       DefaultSendTimeout,
+		QueueSettings: exporterhelper.DefaultQueueSettings(),
+		RetrySettings: exporterhelper.DefaultRetrySettings(),
 	}
 }
 
@@ -70,7 +70,8 @@ const (
 // or at the end of the path.
 //
 // <tag> is of the form "key=val", where key can contain any char except ";!^=" and
-// val can contain any char except ";~".
+// val can contain any char except ";~". Neither can contain whitespaces, which
+// separate the <path> from the <value>.
 //
 // The <value> is the textual representation of the metric value.
 //
@@ -93,7 +94,7 @@ func metricDataToPlaintext(mds []consumerdata.MetricsData) (string, int, int) {
 		for _, metric := range md.Metrics {
 			totalTimeseries++
 			descriptor := metric.MetricDescriptor
-			name := descriptor.GetName()
+			name := sanitizeMetricName(descriptor.GetName())
 			if name == "" {
 				numTimeseriesDropped += len(metric.Timeseries)
 				// TODO: observability for this, debug logging.
@@ -345,12 +346,28 @@ func buildLine(path, value, timestamp string) string {
 	return path + " " + value + " " + timestamp + "\n"
 }
 
+// sanitizeMetricName removes any invalid character from the metric name, the
+// invalid characters are ";" and the whitespaces, which would terminate the
+// name or the <path>.
+func sanitizeMetricName(name string) string {
+	mapRune := func(r rune) rune {
+		switch r {
+		case ';', ' ', '\t', '\n', '\r':
+			return sanitizedRune
+		default:
+			return r
+		}
+	}
+
+	return strings.Map(mapRune, name)
+}
+
 // sanitizeTagKey removes any invalid character from the tag key, the invalid
-// characters are ";!^=".
+// characters are ";!^=" and the whitespaces, which would terminate the <path>.
 func sanitizeTagKey(key string) string {
 	mapRune := func(r rune) rune {
 		switch r {
-		case ';', '!', '^', '=':
+		case ';', '!', '^', '=', ' ', '\t', '\n', '\r':
 			return sanitizedRune
 		default:
 			return r
@@ -361,11 +378,11 @@ func sanitizeTagKey(key string) string {
 }
 
 // sanitizeTagValue removes any invalid character from the tag value, the invalid
-// characters are ";~".
+// characters are ";~" and the whitespaces, which would terminate the <path>.
 func sanitizeTagValue(value string) string {
 	mapRune := func(r rune) rune {
 		switch r {
-		case ';', '~':
+		case ';', '~', ' ', '\t', '\n', '\r':
 			return sanitizedRune
 		default:
 			return r
@@ -35,14 +35,19 @@ func Test_sanitizeTagKey(t *testing.T) {
 	}{
 		{
 			name: "no_changes",
-			key:  "a valid tag key",
-			want: "a valid tag key",
+			key:  "a_valid.tag-key",
+			want: "a_valid.tag-key",
 		},
 		{
 			name: "remove_tag_set",
 			key:  "a" + tagKeyValueSeparator + "c",
 			want: "a" + string(sanitizedRune) + "c",
 		},
+		{
+			name: "replace_whitespaces",
+			key:  "a b\tc",
+			want: "a" + string(sanitizedRune) + "b" + string(sanitizedRune) + "c",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -60,8 +65,8 @@ func Test_sanitizeTagValue(t *testing.T) {
 	}{
 		{
 			name:  "no_changes",
-			value: "a valid tag value",
-			want:  "a valid tag value",
+			value: "a_valid.tag-value=1",
+			want:  "a_valid.tag-value=1",
 		},
 		{
 			name:  "replace_tilde",
@@ -73,6 +78,11 @@ func Test_sanitizeTagValue(t *testing.T) {
 			value: "a;c",
 			want:  "a" + string(sanitizedRune) + "c",
 		},
+		{
+			name:  "replace_whitespaces",
+			value: "a b\nc",
+			want:  "a" + string(sanitizedRune) + "b" + string(sanitizedRune) + "c",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -82,6 +92,36 @@ func Test_sanitizeTagValue(t *testing.T) {
 	}
 }
 
+func Test_sanitizeMetricName(t *testing.T) {
+	tests := []struct {
+		name       string
+		metricName string
+		want       string
+	}{
+		{
+			name:       "no_changes",
+			metricName: "a.valid_metric-name",
+			want:       "a.valid_metric-name",
+		},
+		{
+			name:       "replace_semicol",
+			metricName: "a;c",
+			want:       "a" + string(sanitizedRune) + "c",
+		},
+		{
+			name:       "replace_whitespaces",
+			metricName: "a b\rc",
+			want:       "a" + string(sanitizedRune) + "b" + string(sanitizedRune) + "c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeMetricName(tt.metricName)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_buildPath(t *testing.T) {
 	type args struct {
 		name        string
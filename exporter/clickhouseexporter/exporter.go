@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	// createDatabaseSQL creates the database of the tables.
+	createDatabaseSQL = "CREATE DATABASE IF NOT EXISTS %s"
+
+	// ttlSQL is the TTL clause of the tables, deleting the rows older than the number of days.
+	ttlSQL = "TTL toDateTime(Timestamp) + toIntervalDay(%d)"
+)
+
+// permanentExceptionCodes are the codes of the exceptions thrown by the server which retrying the insert can't fix,
+// the schema of the table not matching the inserted rows or the user not being allowed to insert them.
+var permanentExceptionCodes = map[int32]struct{}{
+	10:  {}, // NOT_FOUND_COLUMN_IN_BLOCK
+	16:  {}, // NO_SUCH_COLUMN_IN_TABLE
+	20:  {}, // NUMBER_OF_COLUMNS_DOESNT_MATCH
+	44:  {}, // ILLEGAL_COLUMN
+	47:  {}, // UNKNOWN_IDENTIFIER
+	50:  {}, // UNKNOWN_TYPE
+	53:  {}, // TYPE_MISMATCH
+	60:  {}, // UNKNOWN_TABLE
+	62:  {}, // SYNTAX_ERROR
+	70:  {}, // CANNOT_CONVERT_TYPE
+	81:  {}, // UNKNOWN_DATABASE
+	115: {}, // UNKNOWN_SETTING
+	497: {}, // ACCESS_DENIED
+	516: {}, // AUTHENTICATION_FAILED
+}
+
+// newDB opens a pool of connections to the server, which are established when the queries are run.
+func newDB(config *Config) (*sql.DB, error) {
+	opts, err := config.options()
+	if err != nil {
+		return nil, err
+	}
+	return clickhouse.OpenDB(opts), nil
+}
+
+// createTable creates the database and the table with the given query, formatted with the name of the table and
+// its TTL clause.
+func createTable(ctx context.Context, db *sql.DB, config *Config, createTableSQL string, table string) error {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(createDatabaseSQL, config.Database)); err != nil {
+		return fmt.Errorf("failed to create database %s: %w", config.Database, err)
+	}
+
+	var ttl string
+	if config.TTLDays > 0 {
+		ttl = fmt.Sprintf(ttlSQL, config.TTLDays)
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(createTableSQL, tableName(config.Database, table), ttl)); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", table, err)
+	}
+	return nil
+}
+
+// insert inserts a batch of rows with the given query, the rows being appended to the prepared statement by
+// appendRows and sent to the server in a single block when the transaction is committed.
+func insert(ctx context.Context, db *sql.DB, insertSQL string, appendRows func(stmt *sql.Stmt) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return classifyError(err)
+	}
+
+	if err := appendBatch(ctx, tx, insertSQL, appendRows); err != nil {
+		_ = tx.Rollback()
+		return classifyError(err)
+	}
+
+	return classifyError(tx.Commit())
+}
+
+func appendBatch(ctx context.Context, tx *sql.Tx, insertSQL string, appendRows func(stmt *sql.Stmt) error) error {
+	stmt, err := tx.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	return appendRows(stmt)
+}
+
+// classifyError marks the errors which retrying the insert can't fix as permanent, the others, e.g. the network
+// errors or the server being overloaded, being retried.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var exception *clickhouse.Exception
+	if errors.As(err, &exception) {
+		if _, ok := permanentExceptionCodes[exception.Code]; ok {
+			return consumererror.Permanent(err)
+		}
+		return err
+	}
+
+	var converterErr *column.ColumnConverterError
+	var unsupportedErr *column.UnsupportedColumnTypeError
+	if errors.As(err, &converterErr) || errors.As(err, &unsupportedErr) {
+		return consumererror.Permanent(err)
+	}
+
+	return err
+}
+
+func tableName(database string, table string) string {
+	return database + "." + table
+}
+
+func timestampTime(ts pdata.TimestampUnixNano) time.Time {
+	return time.Unix(0, int64(ts)).UTC()
+}
+
+// attributesToMap converts the attributes to the values of the Map(String, String) columns.
+func attributesToMap(attributes pdata.AttributeMap) map[string]string {
+	m := make(map[string]string, attributes.Len())
+	attributes.ForEach(func(k string, v pdata.AttributeValue) {
+		m[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+	return m
+}
+
+func serviceName(resource pdata.Resource) string {
+	if v, ok := resource.Attributes().Get(conventions.AttributeServiceName); ok {
+		return v.StringVal()
+	}
+	return ""
+}
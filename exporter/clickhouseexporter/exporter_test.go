@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// passthroughConverter passes the arguments to the mock like the ClickHouse driver does, e.g. the maps of the
+// attributes not being valid database/sql values.
+type passthroughConverter struct{}
+
+func (passthroughConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	return v, nil
+}
+
+// equalArg matches the arguments equal to the expected value, the arguments not being valid driver values being
+// compared too.
+type equalArg struct {
+	expected interface{}
+}
+
+func (a equalArg) Match(v driver.Value) bool {
+	return assert.ObjectsAreEqual(a.expected, v)
+}
+
+// expectedArgs returns the matchers of the arguments of an insert.
+func expectedArgs(values ...interface{}) []driver.Value {
+	args := make([]driver.Value, len(values))
+	for i, v := range values {
+		args[i] = equalArg{expected: v}
+	}
+	return args
+}
+
+func newMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(passthroughConverter{}))
+	require.NoError(t, err)
+	return db, mock
+}
+
+func TestCreateTable(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE DATABASE IF NOT EXISTS otel")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS otel.otel_logs (") + "(?s).*" +
+		regexp.QuoteMeta("TTL toDateTime(Timestamp) + toIntervalDay(7)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.TTLDays = 7
+	require.NoError(t, createTable(context.Background(), db, cfg, createLogsTableSQL, cfg.LogsTableName))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTableWithoutTTL(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE DATABASE IF NOT EXISTS otel")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("ORDER BY (ServiceName, SpanName, toUnixTimestamp(Timestamp), TraceId)\n\nSETTINGS")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, createTable(context.Background(), db, cfg, createTracesTableSQL, cfg.TracesTableName))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTableError(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE DATABASE IF NOT EXISTS otel")).
+		WillReturnError(&clickhouse.Exception{Code: 497, Message: "otel: Not enough privileges"})
+
+	cfg := createDefaultConfig().(*Config)
+	err := createTable(context.Background(), db, cfg, createLogsTableSQL, cfg.LogsTableName)
+	assert.EqualError(t, err, "failed to create database otel: code: 497, message: otel: Not enough privileges")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertRollback(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO otel.otel_logs").WillReturnError(&clickhouse.Exception{Code: 60, Message: "Table otel.otel_logs doesn't exist"})
+	mock.ExpectRollback()
+
+	err := insert(context.Background(), db, "INSERT INTO otel.otel_logs (Body) VALUES (?)", func(*sql.Stmt) error {
+		t.Fatal("no rows must be appended when the insert can't be prepared")
+		return nil
+	})
+	assert.True(t, consumererror.IsPermanent(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{
+			name:      "unknown table",
+			err:       &clickhouse.Exception{Code: 60, Message: "Table otel.otel_logs doesn't exist"},
+			permanent: true,
+		},
+		{
+			name:      "missing column",
+			err:       &clickhouse.Exception{Code: 16, Message: "No such column TraceFlags in table otel.otel_logs"},
+			permanent: true,
+		},
+		{
+			name:      "converter error",
+			err:       &column.ColumnConverterError{Op: "Append", From: "string", To: "Int32"},
+			permanent: true,
+		},
+		{
+			name: "too many parts",
+			err:  &clickhouse.Exception{Code: 252, Message: "Too many parts"},
+		},
+		{
+			name: "network error",
+			err:  errors.New("read tcp 127.0.0.1:9000: i/o timeout"),
+		},
+		{
+			name: "bad connection",
+			err:  driver.ErrBadConn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(err))
+			assert.Contains(t, err.Error(), tt.err.Error())
+		})
+	}
+
+	assert.NoError(t, classifyError(nil))
+}
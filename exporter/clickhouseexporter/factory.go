@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	typeStr = "clickhouse"
+
+	defaultDatabase        = "otel"
+	defaultLogsTableName   = "otel_logs"
+	defaultTracesTableName = "otel_traces"
+)
+
+// NewFactory creates a factory for ClickHouse exporter.
+func NewFactory() component.ExporterFactory {
+	return exporterhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		exporterhelper.WithTraces(createTraceExporter),
+		exporterhelper.WithLogs(createLogsExporter),
+	)
+}
+
+func createDefaultConfig() configmodels.Exporter {
+	return &Config{
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: configmodels.Type(typeStr),
+			NameVal: typeStr,
+		},
+		TimeoutSettings:    exporterhelper.TimeoutSettings{Timeout: 10 * time.Second},
+		RetrySettings:      exporterhelper.DefaultRetrySettings(),
+		QueueSettings:      exporterhelper.DefaultQueueSettings(),
+		Database:           defaultDatabase,
+		LogsTableName:      defaultLogsTableName,
+		TracesTableName:    defaultTracesTableName,
+		WaitForAsyncInsert: true,
+	}
+}
+
+func createTraceExporter(_ context.Context, params component.ExporterCreateParams, config configmodels.Exporter) (component.TracesExporter, error) {
+	expCfg := config.(*Config)
+
+	exp, err := newTracesExporter(expCfg, params.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewTraceExporter(
+		expCfg,
+		params.Logger,
+		exp.pushTraceData,
+		exporterhelper.WithTimeout(expCfg.TimeoutSettings),
+		exporterhelper.WithRetry(expCfg.RetrySettings),
+		exporterhelper.WithQueue(expCfg.QueueSettings),
+		exporterhelper.WithStart(exp.start),
+		exporterhelper.WithShutdown(exp.shutdown),
+	)
+}
+
+func createLogsExporter(_ context.Context, params component.ExporterCreateParams, config configmodels.Exporter) (component.LogsExporter, error) {
+	expCfg := config.(*Config)
+
+	exp, err := newLogsExporter(expCfg, params.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewLogsExporter(
+		expCfg,
+		params.Logger,
+		exp.pushLogsData,
+		exporterhelper.WithTimeout(expCfg.TimeoutSettings),
+		exporterhelper.WithRetry(expCfg.RetrySettings),
+		exporterhelper.WithQueue(expCfg.QueueSettings),
+		exporterhelper.WithStart(exp.start),
+		exporterhelper.WithShutdown(exp.shutdown),
+	)
+}
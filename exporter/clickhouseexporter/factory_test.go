@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	ocfg, ok := factory.CreateDefaultConfig().(*Config)
+	assert.True(t, ok)
+	assert.Equal(t, "", ocfg.DSN)
+	assert.Equal(t, 10*time.Second, ocfg.TimeoutSettings.Timeout, "default timeout is 10 seconds")
+	assert.Equal(t, true, ocfg.RetrySettings.Enabled, "default retry is enabled")
+	assert.Equal(t, true, ocfg.QueueSettings.Enabled, "default sending queue is enabled")
+	assert.Equal(t, "otel", ocfg.Database)
+	assert.Equal(t, "otel_logs", ocfg.LogsTableName)
+	assert.Equal(t, "otel_traces", ocfg.TracesTableName)
+	assert.False(t, ocfg.CreateSchema)
+	assert.False(t, ocfg.AsyncInsert)
+}
+
+func TestCreateExporters(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.DSN = "clickhouse://localhost:9000"
+
+	creationParams := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	te, err := factory.CreateTracesExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, te)
+	require.NoError(t, te.Shutdown(context.Background()))
+
+	le, err := factory.CreateLogsExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, le)
+	require.NoError(t, le.Shutdown(context.Background()))
+}
+
+func TestCreateExporterInvalidConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	creationParams := component.ExporterCreateParams{Logger: zap.NewNop()}
+	_, err := factory.CreateLogsExporter(context.Background(), creationParams, cfg)
+	assert.EqualError(t, err, "dsn must be specified")
+}
@@ -0,0 +1,11 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter
+
+go 1.14
+
+require (
+	github.com/ClickHouse/clickhouse-go/v2 v2.0.12
+	github.com/DATA-DOG/go-sqlmock v1.3.3
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+)
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// createLogsTableSQL creates the logs table, formatted with its name and TTL clause.
+	createLogsTableSQL = `CREATE TABLE IF NOT EXISTS %s (
+	Timestamp DateTime64(9) CODEC(Delta, ZSTD(1)),
+	TraceId String CODEC(ZSTD(1)),
+	SpanId String CODEC(ZSTD(1)),
+	TraceFlags UInt32 CODEC(ZSTD(1)),
+	SeverityText LowCardinality(String) CODEC(ZSTD(1)),
+	SeverityNumber Int32 CODEC(ZSTD(1)),
+	ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+	Body String CODEC(ZSTD(1)),
+	ResourceAttributes Map(String, String) CODEC(ZSTD(1)),
+	LogAttributes Map(String, String) CODEC(ZSTD(1))
+) ENGINE = MergeTree()
+PARTITION BY toDate(Timestamp)
+ORDER BY (ServiceName, SeverityText, toUnixTimestamp(Timestamp), TraceId)
+%s
+SETTINGS index_granularity = 8192, ttl_only_drop_parts = 1`
+
+	// insertLogsSQL inserts a log record.
+	insertLogsSQL = `INSERT INTO %s (
+	Timestamp,
+	TraceId,
+	SpanId,
+	TraceFlags,
+	SeverityText,
+	SeverityNumber,
+	ServiceName,
+	Body,
+	ResourceAttributes,
+	LogAttributes
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+)
+
+type logsExporter struct {
+	config    *Config
+	logger    *zap.Logger
+	db        *sql.DB
+	insertSQL string
+}
+
+func newLogsExporter(config *Config, logger *zap.Logger) (*logsExporter, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	db, err := newDB(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logsExporter{
+		config:    config,
+		logger:    logger,
+		db:        db,
+		insertSQL: fmt.Sprintf(insertLogsSQL, tableName(config.Database, config.LogsTableName)),
+	}, nil
+}
+
+// start creates the logs table when the schema is managed by the exporter.
+func (e *logsExporter) start(ctx context.Context, _ component.Host) error {
+	if !e.config.CreateSchema {
+		return nil
+	}
+	return createTable(ctx, e.db, e.config, createLogsTableSQL, e.config.LogsTableName)
+}
+
+// shutdown closes the connections to the server.
+func (e *logsExporter) shutdown(context.Context) error {
+	return e.db.Close()
+}
+
+// pushLogsData inserts the log records into the logs table, in a single batch.
+func (e *logsExporter) pushLogsData(ctx context.Context, ld pdata.Logs) (int, error) {
+	err := insert(ctx, e.db, e.insertSQL, func(stmt *sql.Stmt) error {
+		rls := ld.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			rl := rls.At(i)
+			resourceAttributes := attributesToMap(rl.Resource().Attributes())
+			service := serviceName(rl.Resource())
+
+			ills := rl.InstrumentationLibraryLogs()
+			for j := 0; j < ills.Len(); j++ {
+				logs := ills.At(j).Logs()
+				for k := 0; k < logs.Len(); k++ {
+					r := logs.At(k)
+					_, err := stmt.ExecContext(ctx,
+						timestampTime(r.Timestamp()),
+						r.TraceID().HexString(),
+						r.SpanID().HexString(),
+						r.Flags(),
+						r.SeverityText(),
+						int32(r.SeverityNumber()),
+						service,
+						tracetranslator.AttributeValueToString(r.Body(), false),
+						resourceAttributes,
+						attributesToMap(r.Attributes()),
+					)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return 0, nil
+}
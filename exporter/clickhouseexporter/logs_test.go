@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+func newTestLogsExporter(t *testing.T) (*logsExporter, sqlmock.Sqlmock) {
+	db, mock := newMockDB(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.DSN = "clickhouse://localhost:9000"
+	return &logsExporter{
+		config:    cfg,
+		logger:    zap.NewNop(),
+		db:        db,
+		insertSQL: "INSERT INTO otel.otel_logs",
+	}, mock
+}
+
+func testLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+
+	logs.At(0).SetTimestamp(pdata.TimestampUnixNano(1614160000000000001))
+	logs.At(0).SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	logs.At(0).SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	logs.At(0).SetFlags(1)
+	logs.At(0).SetSeverityText("ERROR")
+	logs.At(0).SetSeverityNumber(pdata.SeverityNumberERROR)
+	logs.At(0).Body().SetStringVal("payment failed")
+	logs.At(0).Attributes().InsertInt("http.status_code", 502)
+
+	logs.At(1).Body().SetStringVal("retrying")
+	return ld
+}
+
+func TestPushLogsData(t *testing.T) {
+	exp, mock := newTestLogsExporter(t)
+
+	resourceAttributes := map[string]string{conventions.AttributeServiceName: "checkout"}
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(regexp.QuoteMeta(exp.insertSQL))
+	prep.ExpectExec().WithArgs(expectedArgs(
+		time.Unix(0, 1614160000000000001).UTC(),
+		"0102030405060708090a0b0c0d0e0f10",
+		"0102030405060708",
+		uint32(1),
+		"ERROR",
+		int32(pdata.SeverityNumberERROR),
+		"checkout",
+		"payment failed",
+		resourceAttributes,
+		map[string]string{"http.status_code": "502"},
+	)...).WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WithArgs(expectedArgs(
+		time.Unix(0, 0).UTC(),
+		"",
+		"",
+		uint32(0),
+		"",
+		int32(0),
+		"checkout",
+		"retrying",
+		resourceAttributes,
+		map[string]string{},
+	)...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	dropped, err := exp.pushLogsData(context.Background(), testLogs())
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPushLogsDataCommitError(t *testing.T) {
+	exp, mock := newTestLogsExporter(t)
+
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(regexp.QuoteMeta(exp.insertSQL))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(errors.New("write tcp 127.0.0.1:9000: broken pipe"))
+
+	dropped, err := exp.pushLogsData(context.Background(), testLogs())
+	assert.EqualError(t, err, "write tcp 127.0.0.1:9000: broken pipe")
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 2, dropped)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLogsExporterStart(t *testing.T) {
+	exp, mock := newTestLogsExporter(t)
+
+	// the schema isn't created by default
+	require.NoError(t, exp.start(context.Background(), nil))
+
+	exp.config.CreateSchema = true
+	mock.ExpectExec(regexp.QuoteMeta("CREATE DATABASE IF NOT EXISTS otel")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS otel.otel_logs (")).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, exp.start(context.Background(), nil))
+
+	mock.ExpectClose()
+	require.NoError(t, exp.shutdown(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+const (
+	// createTracesTableSQL creates the traces table, formatted with its name and TTL clause.
+	createTracesTableSQL = `CREATE TABLE IF NOT EXISTS %s (
+	Timestamp DateTime64(9) CODEC(Delta, ZSTD(1)),
+	TraceId String CODEC(ZSTD(1)),
+	SpanId String CODEC(ZSTD(1)),
+	ParentSpanId String CODEC(ZSTD(1)),
+	TraceState String CODEC(ZSTD(1)),
+	SpanName LowCardinality(String) CODEC(ZSTD(1)),
+	SpanKind LowCardinality(String) CODEC(ZSTD(1)),
+	ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+	ResourceAttributes Map(String, String) CODEC(ZSTD(1)),
+	SpanAttributes Map(String, String) CODEC(ZSTD(1)),
+	Duration Int64 CODEC(ZSTD(1)),
+	StatusCode LowCardinality(String) CODEC(ZSTD(1)),
+	StatusMessage String CODEC(ZSTD(1))
+) ENGINE = MergeTree()
+PARTITION BY toDate(Timestamp)
+ORDER BY (ServiceName, SpanName, toUnixTimestamp(Timestamp), TraceId)
+%s
+SETTINGS index_granularity = 8192, ttl_only_drop_parts = 1`
+
+	// insertTracesSQL inserts a span.
+	insertTracesSQL = `INSERT INTO %s (
+	Timestamp,
+	TraceId,
+	SpanId,
+	ParentSpanId,
+	TraceState,
+	SpanName,
+	SpanKind,
+	ServiceName,
+	ResourceAttributes,
+	SpanAttributes,
+	Duration,
+	StatusCode,
+	StatusMessage
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+)
+
+type tracesExporter struct {
+	config    *Config
+	logger    *zap.Logger
+	db        *sql.DB
+	insertSQL string
+}
+
+func newTracesExporter(config *Config, logger *zap.Logger) (*tracesExporter, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	db, err := newDB(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tracesExporter{
+		config:    config,
+		logger:    logger,
+		db:        db,
+		insertSQL: fmt.Sprintf(insertTracesSQL, tableName(config.Database, config.TracesTableName)),
+	}, nil
+}
+
+// start creates the traces table when the schema is managed by the exporter.
+func (e *tracesExporter) start(ctx context.Context, _ component.Host) error {
+	if !e.config.CreateSchema {
+		return nil
+	}
+	return createTable(ctx, e.db, e.config, createTracesTableSQL, e.config.TracesTableName)
+}
+
+// shutdown closes the connections to the server.
+func (e *tracesExporter) shutdown(context.Context) error {
+	return e.db.Close()
+}
+
+// pushTraceData inserts the spans into the traces table, in a single batch.
+func (e *tracesExporter) pushTraceData(ctx context.Context, td pdata.Traces) (int, error) {
+	err := insert(ctx, e.db, e.insertSQL, func(stmt *sql.Stmt) error {
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rs := rss.At(i)
+			resourceAttributes := attributesToMap(rs.Resource().Attributes())
+			service := serviceName(rs.Resource())
+
+			ilss := rs.InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					span := spans.At(k)
+					_, err := stmt.ExecContext(ctx,
+						timestampTime(span.StartTime()),
+						span.TraceID().HexString(),
+						span.SpanID().HexString(),
+						span.ParentSpanID().HexString(),
+						string(span.TraceState()),
+						span.Name(),
+						span.Kind().String(),
+						service,
+						resourceAttributes,
+						attributesToMap(span.Attributes()),
+						int64(span.EndTime())-int64(span.StartTime()),
+						span.Status().Code().String(),
+						span.Status().Message(),
+					)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return td.SpanCount(), err
+	}
+	return 0, nil
+}
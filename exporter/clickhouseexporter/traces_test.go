@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+func newTestTracesExporter(t *testing.T) (*tracesExporter, sqlmock.Sqlmock) {
+	db, mock := newMockDB(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.DSN = "clickhouse://localhost:9000"
+	return &tracesExporter{
+		config:    cfg,
+		logger:    zap.NewNop(),
+		db:        db,
+		insertSQL: "INSERT INTO otel.otel_traces",
+	}, mock
+}
+
+func testTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(1)
+
+	span := spans.At(0)
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.SetParentSpanID(pdata.NewSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}))
+	span.SetTraceState("vendor=value")
+	span.SetName("GET /cart")
+	span.SetKind(pdata.SpanKindSERVER)
+	span.SetStartTime(pdata.TimestampUnixNano(1614160000000000000))
+	span.SetEndTime(pdata.TimestampUnixNano(1614160000250000000))
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Status().SetMessage("upstream unavailable")
+	span.Attributes().InsertString("http.method", "GET")
+	return td
+}
+
+func TestPushTraceData(t *testing.T) {
+	exp, mock := newTestTracesExporter(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(regexp.QuoteMeta(exp.insertSQL)).ExpectExec().WithArgs(expectedArgs(
+		time.Unix(0, 1614160000000000000).UTC(),
+		"0102030405060708090a0b0c0d0e0f10",
+		"0102030405060708",
+		"0807060504030201",
+		"vendor=value",
+		"GET /cart",
+		"SPAN_KIND_SERVER",
+		"checkout",
+		map[string]string{conventions.AttributeServiceName: "checkout"},
+		map[string]string{"http.method": "GET"},
+		int64(250*time.Millisecond),
+		"STATUS_CODE_ERROR",
+		"upstream unavailable",
+	)...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	dropped, err := exp.pushTraceData(context.Background(), testTraces())
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPushTraceDataSchemaError(t *testing.T) {
+	exp, mock := newTestTracesExporter(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(regexp.QuoteMeta(exp.insertSQL)).ExpectExec().
+		WillReturnError(&clickhouse.Exception{Code: 53, Message: "Type mismatch in IN or VALUES section"})
+	mock.ExpectRollback()
+
+	dropped, err := exp.pushTraceData(context.Background(), testTraces())
+	assert.EqualError(t, err, "Permanent error: code: 53, message: Type mismatch in IN or VALUES section")
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, dropped)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
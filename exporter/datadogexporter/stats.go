@@ -19,6 +19,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/trace/exportable/pb"
 	"github.com/DataDog/datadog-agent/pkg/trace/exportable/stats"
+	"github.com/DataDog/datadog-agent/pkg/trace/exportable/traceutil"
 )
 
 const (
@@ -52,11 +53,12 @@ func computeAPMStats(tracePayload *pb.TracePayload, calculator *sublayerCalculat
 
 			// Use weight 1, as sampling in opentelemetry would occur upstream in a processor.
 			// Generally we want to ship 100% of traces to the backend where more accurate tail based sampling can be performed.
-			// TopLevel is always "true" since we only compute stats for top-level spans.
+			// The analyzed spans include the spans marked as analyzed by the tracer, which aren't necessarily top-level:
+			// only the ones flagged by computeSublayerMetrics contribute to the top-level hits.
 			weightedSpan := &stats.WeightedSpan{
 				Span:     span,
 				Weight:   1,
-				TopLevel: true,
+				TopLevel: traceutil.HasTopLevel(span),
 			}
 			statsRawBucket.HandleSpan(weightedSpan, tracePayload.Env, []string{versionAggregationTag}, sublayers)
 		}
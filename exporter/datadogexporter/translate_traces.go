@@ -123,7 +123,8 @@ func resourceSpansToDatadogSpans(rs pdata.ResourceSpans, calculator *sublayerCal
 
 	// specification states that the resource level deployment.environment should be used for passing env, so defer to that
 	// https://github.com/open-telemetry/opentelemetry-specification/blob/master/specification/resource/semantic_conventions/deployment_environment.md#deployment
-	if resourceEnv, ok := datadogTags[conventions.AttributeDeploymentEnvironment]; ok {
+	// an empty env is ignored, since the stats of a payload can't be aggregated without one
+	if resourceEnv, ok := datadogTags[conventions.AttributeDeploymentEnvironment]; ok && resourceEnv != "" {
 		payload.Env = resourceEnv
 	}
 
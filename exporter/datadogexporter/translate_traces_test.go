@@ -790,3 +790,93 @@ func TestStatsAggregations(t *testing.T) {
 
 	assert.Equal(t, "test-version", statsVersionTag.Value)
 }
+
+func newStatsTestTrace() pb.Trace {
+	return pb.Trace{
+		// root span
+		{TraceID: 1, SpanID: 1, ParentID: 0, Service: "frontend", Name: "web.request", Resource: "GET /", Duration: 300, Error: 1},
+		// child of the same service
+		{TraceID: 1, SpanID: 2, ParentID: 1, Service: "frontend", Name: "web.render", Resource: "render", Duration: 100},
+		// child of another service
+		{TraceID: 1, SpanID: 3, ParentID: 1, Service: "backend", Name: "grpc.server", Resource: "Get", Duration: 100},
+		// child of an unknown parent
+		{TraceID: 1, SpanID: 4, ParentID: 42, Service: "backend", Name: "grpc.server", Resource: "Get", Duration: 50, Error: 1},
+	}
+}
+
+// ensure that only the top-level spans are analyzed
+func TestGetAnalyzedSpans(t *testing.T) {
+	trace := newStatsTestTrace()
+
+	analyzed := getAnalyzedSpans(trace)
+
+	var spanIDs []uint64
+	for _, span := range analyzed {
+		spanIDs = append(spanIDs, span.SpanID)
+	}
+	assert.Equal(t, []uint64{1, 3, 4}, spanIDs)
+}
+
+// ensure that the hits, errors and durations of the top-level spans are counted per service and resource
+func TestStatsCounts(t *testing.T) {
+	calculator := newSublayerCalculator()
+	trace := newStatsTestTrace()
+	computeSublayerMetrics(calculator, trace)
+
+	payload := pb.TracePayload{
+		HostName: "testhostname",
+		Env:      "test-env",
+		Traces:   []*pb.APITrace{{TraceID: 1, Spans: trace}},
+	}
+
+	statsOutput := computeAPMStats(&payload, calculator, time.Now().UTC().UnixNano())
+	assert.Equal(t, "test-env", statsOutput.Env)
+	assert.Len(t, statsOutput.Stats, 1)
+
+	counts := map[string]stats.Count{}
+	for _, count := range statsOutput.Stats[0].Counts {
+		// skip the sublayer counts
+		if count.TagSet.Get("sublayer_type").Value != "" {
+			continue
+		}
+		counts[count.TagSet.Get("service").Value+"|"+count.Measure] = count
+	}
+
+	assert.Equal(t, float64(1), counts["frontend|hits"].Value)
+	assert.Equal(t, float64(1), counts["frontend|hits"].TopLevel)
+	assert.Equal(t, float64(1), counts["frontend|errors"].Value)
+	assert.Equal(t, float64(300), counts["frontend|duration"].Value)
+	assert.Equal(t, float64(2), counts["backend|hits"].Value)
+	assert.Equal(t, float64(2), counts["backend|hits"].TopLevel)
+	assert.Equal(t, float64(1), counts["backend|errors"].Value)
+	assert.Equal(t, float64(150), counts["backend|duration"].Value)
+
+	var distributions []string
+	for _, distribution := range statsOutput.Stats[0].Distributions {
+		if distribution.TagSet.Get("sublayer_type").Value == "" {
+			distributions = append(distributions, distribution.TagSet.Get("service").Value+"|"+distribution.Measure)
+		}
+	}
+	assert.ElementsMatch(t, []string{"frontend|duration", "backend|duration"}, distributions)
+}
+
+// ensure that an empty deployment.environment doesn't override the configured env
+func TestTracesTranslationEmptyResourceEnv(t *testing.T) {
+	hostname := "testhostname"
+	calculator := newSublayerCalculator()
+
+	mockTraceID := [16]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}
+	mockSpanID := [8]byte{0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8}
+	mockParentSpanID := [8]byte{0xEF, 0xEE, 0xED, 0xEC, 0xEB, 0xEA, 0xE9, 0xE8}
+
+	rs := NewResourceSpansData(mockTraceID, mockSpanID, mockParentSpanID, pdata.StatusCodeOk, true)
+	rs.Resource().Attributes().UpsertString(conventions.AttributeDeploymentEnvironment, "")
+
+	cfg := config.Config{TagsConfig: config.TagsConfig{Env: "none"}}
+
+	datadogPayload := resourceSpansToDatadogSpans(rs, calculator, hostname, &cfg)
+	assert.Equal(t, "none", datadogPayload.Env)
+
+	statsOutput := computeAPMStats(&datadogPayload, calculator, time.Now().UTC().UnixNano())
+	assert.Equal(t, "none", statsOutput.Env)
+}
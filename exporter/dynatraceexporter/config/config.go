@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -39,32 +40,70 @@ type Config struct {
 	// Tags will be added to all exported metrics
 	Tags []string `mapstructure:"tags"`
 
+	// DefaultDimensions will be added to all exported metrics, unless overridden by the labels of the data points
+	DefaultDimensions map[string]string `mapstructure:"default_dimensions"`
+
 	// String to prefix all metric names
 	Prefix string `mapstructure:"prefix"`
+
+	// CumulativeToDelta configures the cache of the previous values of the cumulative sums,
+	// from which the deltas of the Dynatrace counts are computed
+	CumulativeToDelta CumulativeToDeltaSettings `mapstructure:"cumulative_to_delta"`
+}
+
+// CumulativeToDeltaSettings bounds the cache of the previous values of the cumulative sums
+type CumulativeToDeltaSettings struct {
+	// MaxStreams is the maximum number of cumulative series, the least recently seen ones being evicted first.
+	// Defaults to DefaultMaxStreams.
+	MaxStreams int `mapstructure:"max_streams"`
+
+	// MaxStaleness is the time after which a series which hasn't been seen is forgotten. Zero disables the expiry.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
 }
 
-// Sanitize ensures an API token has been provided
+const (
+	// OneAgentEndpoint is the metrics ingest endpoint of the local OneAgent, used when no endpoint is configured
+	OneAgentEndpoint = "http://localhost:14499/metrics/ingest"
+
+	// DefaultMaxStreams is the default maximum number of cumulative series kept track of
+	DefaultMaxStreams = 10000
+)
+
+// Sanitize ensures an API token has been provided for the configured endpoint,
+// defaulting to the endpoint of the local OneAgent which requires none
 func (c *Config) Sanitize() error {
 	c.APIToken = strings.TrimSpace(c.APIToken)
 
-	if c.APIToken == "" {
-		return errors.New("missing api_token")
-	}
-
 	if c.Endpoint == "" {
-		return errors.New("missing endpoint")
+		c.Endpoint = OneAgentEndpoint
+	} else if c.APIToken == "" {
+		return errors.New("missing api_token")
 	}
 
 	if !(strings.HasPrefix(c.Endpoint, "http://") || strings.HasPrefix(c.Endpoint, "https://")) {
 		return errors.New("endpoint must start with https:// or http://")
 	}
 
+	if c.CumulativeToDelta.MaxStreams < 0 {
+		return errors.New("cumulative_to_delta.max_streams can't be negative")
+	}
+
+	if c.CumulativeToDelta.MaxStreams == 0 {
+		c.CumulativeToDelta.MaxStreams = DefaultMaxStreams
+	}
+
+	if c.CumulativeToDelta.MaxStaleness < 0 {
+		return errors.New("cumulative_to_delta.max_staleness can't be negative")
+	}
+
 	if c.HTTPClientSettings.Headers == nil {
 		c.HTTPClientSettings.Headers = make(map[string]string)
 	}
 
 	c.HTTPClientSettings.Headers["Content-Type"] = "text/plain; charset=UTF-8"
-	c.HTTPClientSettings.Headers["Authorization"] = fmt.Sprintf("Api-Token %s", c.APIToken)
+	if c.APIToken != "" {
+		c.HTTPClientSettings.Headers["Authorization"] = fmt.Sprintf("Api-Token %s", c.APIToken)
+	}
 	c.HTTPClientSettings.Headers["User-Agent"] = "opentelemetry-collector"
 
 	return nil
@@ -23,11 +23,12 @@ import (
 
 func TestConfig_Sanitize(t *testing.T) {
 	type fields struct {
-		ExporterSettings configmodels.ExporterSettings
-		APIToken         string
-		Endpoint         string
-		Tags             []string
-		Prefix           string
+		ExporterSettings  configmodels.ExporterSettings
+		APIToken          string
+		Endpoint          string
+		Tags              []string
+		Prefix            string
+		CumulativeToDelta CumulativeToDeltaSettings
 	}
 	tests := []struct {
 		name    string
@@ -45,8 +46,18 @@ func TestConfig_Sanitize(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "Missing Endpoint",
-			fields:  fields{APIToken: "t", Endpoint: ""},
+			name:    "Missing Endpoint defaults to the OneAgent",
+			fields:  fields{APIToken: "", Endpoint: ""},
+			wantErr: false,
+		},
+		{
+			name:    "Negative max streams",
+			fields:  fields{APIToken: "t", Endpoint: "http://example.com", CumulativeToDelta: CumulativeToDeltaSettings{MaxStreams: -1}},
+			wantErr: true,
+		},
+		{
+			name:    "Negative max staleness",
+			fields:  fields{APIToken: "t", Endpoint: "http://example.com", CumulativeToDelta: CumulativeToDeltaSettings{MaxStaleness: -1}},
 			wantErr: true,
 		},
 		{
@@ -63,6 +74,7 @@ func TestConfig_Sanitize(t *testing.T) {
 				HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: tt.fields.Endpoint},
 				Tags:               tt.fields.Tags,
 				Prefix:             tt.fields.Prefix,
+				CumulativeToDelta:  tt.fields.CumulativeToDelta,
 			}
 			if err := c.Sanitize(); (err != nil) != tt.wantErr {
 				t.Errorf("Config.Sanitize() error = %v, wantErr %v", err, tt.wantErr)
@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynatraceexporter
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// seriesKeySeparator separates the keys and values within the sections of the series keys
+	seriesKeySeparator = string(byte(0))
+
+	// seriesKeySectionSeparator separates the metric name, the resource attributes and the labels in the series keys
+	seriesKeySectionSeparator = string(byte(1))
+)
+
+// cumulativeValue is the last observation of a cumulative series
+type cumulativeValue struct {
+	startTime   pdata.TimestampUnixNano
+	intValue    int64
+	doubleValue float64
+
+	// lastSeen is the exporter's time of the observation, used for the expiry of the stale series
+	lastSeen time.Time
+}
+
+// deltaCache converts the values of the cumulative sums to the deltas since their previous observation
+type deltaCache struct {
+	logger       *zap.Logger
+	maxStaleness time.Duration
+	now          func() time.Time
+
+	// the lock serializes the conversions, as each one depends on the observation left by the previous one
+	lock sync.Mutex
+
+	// values holds the last observation of each series, keyed by the series identity,
+	// with the least recently seen series being the first ones to be evicted
+	values *lru.Cache
+}
+
+func newDeltaCache(logger *zap.Logger, maxSeries int, maxStaleness time.Duration) (*deltaCache, error) {
+	values, err := lru.New(maxSeries)
+	if err != nil {
+		return nil, err
+	}
+	return &deltaCache{
+		logger:       logger,
+		maxStaleness: maxStaleness,
+		now:          time.Now,
+		values:       values,
+	}, nil
+}
+
+// intDelta returns the delta of the integer data point since the previous observation of its series, and false when
+// there's none to compute it from. After a reset of the series, the value is the delta since the reset.
+func (c *deltaCache) intDelta(metricKey string, p pdata.IntDataPoint) (int64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	prev, found := c.swap(metricKey+seriesKeySectionSeparator+labelsKey(p.LabelsMap()), &cumulativeValue{
+		startTime: p.StartTime(),
+		intValue:  p.Value(),
+	})
+	switch {
+	case !found:
+		return 0, false
+	case isReset(prev, p.StartTime(), p.Value() < prev.intValue):
+		return p.Value(), true
+	default:
+		return p.Value() - prev.intValue, true
+	}
+}
+
+// doubleDelta returns the delta of the double data point since the previous observation of its series, and false
+// when there's none to compute it from. After a reset of the series, the value is the delta since the reset.
+func (c *deltaCache) doubleDelta(metricKey string, p pdata.DoubleDataPoint) (float64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	prev, found := c.swap(metricKey+seriesKeySectionSeparator+labelsKey(p.LabelsMap()), &cumulativeValue{
+		startTime:   p.StartTime(),
+		doubleValue: p.Value(),
+	})
+	switch {
+	case !found:
+		return 0, false
+	case isReset(prev, p.StartTime(), p.Value() < prev.doubleValue):
+		return p.Value(), true
+	default:
+		return p.Value() - prev.doubleValue, true
+	}
+}
+
+// swap records the given observation for the series with the given key, returning the previous one, if any
+func (c *deltaCache) swap(key string, value *cumulativeValue) (*cumulativeValue, bool) {
+	now := c.now()
+	c.expireStaleSeries(now)
+	value.lastSeen = now
+
+	prev, found := c.values.Get(key)
+	if evicted := c.values.Add(key, value); evicted {
+		c.logger.Debug("cumulative series evicted, its next observation will be handled as the first one: " +
+			"in order to avoid this in the future, adjust cumulative_to_delta.max_streams")
+	}
+	if !found {
+		return nil, false
+	}
+	return prev.(*cumulativeValue), true
+}
+
+// expireStaleSeries forgets the series that haven't been seen for longer than the max staleness. As the series
+// are updated on each observation, the stale ones are always the least recently used.
+func (c *deltaCache) expireStaleSeries(now time.Time) {
+	if c.maxStaleness == 0 {
+		return
+	}
+
+	for {
+		_, oldest, ok := c.values.GetOldest()
+		if !ok || now.Sub(oldest.(*cumulativeValue).lastSeen) <= c.maxStaleness {
+			return
+		}
+		c.values.RemoveOldest()
+	}
+}
+
+// isReset returns whether the series has been reset since its previous observation, either because
+// its value decreased or because it has a different start time
+func isReset(prev *cumulativeValue, startTime pdata.TimestampUnixNano, decreased bool) bool {
+	return decreased || (startTime != 0 && startTime != prev.startTime)
+}
+
+// attributesKey returns a key identifying the given attributes, regardless of their order
+func attributesKey(attrs pdata.AttributeMap) string {
+	kvs := make([]string, 0, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		kvs = append(kvs, k+seriesKeySeparator+tracetranslator.AttributeValueToString(v, false))
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, seriesKeySeparator)
+}
+
+// labelsKey returns a key identifying the given labels, regardless of their order
+func labelsKey(labels pdata.StringMap) string {
+	kvs := make([]string, 0, labels.Len())
+	labels.ForEach(func(k string, v string) {
+		kvs = append(kvs, k+seriesKeySeparator+v)
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, seriesKeySeparator)
+}
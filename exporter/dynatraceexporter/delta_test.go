@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynatraceexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func newIntPoint(value int64, startTime pdata.TimestampUnixNano) pdata.IntDataPoint {
+	p := pdata.NewIntDataPoint()
+	p.SetValue(value)
+	p.SetStartTime(startTime)
+	p.LabelsMap().Insert("key", "value")
+	return p
+}
+
+func TestDeltaCacheIntDelta(t *testing.T) {
+	cache, err := newDeltaCache(zap.NewNop(), 10, 0)
+	require.NoError(t, err)
+
+	_, ok := cache.intDelta("metric", newIntPoint(10, 1))
+	assert.False(t, ok, "the first observation is the baseline")
+
+	delta, ok := cache.intDelta("metric", newIntPoint(15, 1))
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), delta)
+
+	delta, ok = cache.intDelta("metric", newIntPoint(3, 1))
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), delta, "a decreasing value is a reset")
+
+	delta, ok = cache.intDelta("metric", newIntPoint(7, 2))
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), delta, "a new start time is a reset")
+
+	_, ok = cache.intDelta("other", newIntPoint(20, 1))
+	assert.False(t, ok, "the series are keyed by metric")
+}
+
+func TestDeltaCacheDoubleDelta(t *testing.T) {
+	cache, err := newDeltaCache(zap.NewNop(), 10, 0)
+	require.NoError(t, err)
+
+	p := pdata.NewDoubleDataPoint()
+	p.SetValue(1.5)
+	_, ok := cache.doubleDelta("metric", p)
+	assert.False(t, ok)
+
+	p.SetValue(4)
+	delta, ok := cache.doubleDelta("metric", p)
+	assert.True(t, ok)
+	assert.Equal(t, 2.5, delta)
+
+	p.LabelsMap().Insert("key", "value")
+	_, ok = cache.doubleDelta("metric", p)
+	assert.False(t, ok, "the series are keyed by labels")
+}
+
+func TestDeltaCacheMaxStreams(t *testing.T) {
+	cache, err := newDeltaCache(zap.NewNop(), 1, 0)
+	require.NoError(t, err)
+
+	cache.intDelta("first", newIntPoint(1, 1))
+	cache.intDelta("second", newIntPoint(1, 1))
+	_, ok := cache.intDelta("first", newIntPoint(2, 1))
+	assert.False(t, ok, "the least recently seen series is evicted")
+}
+
+func TestDeltaCacheMaxStaleness(t *testing.T) {
+	cache, err := newDeltaCache(zap.NewNop(), 10, time.Minute)
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	cache.now = func() time.Time { return now }
+
+	cache.intDelta("stale", newIntPoint(1, 1))
+	cache.intDelta("fresh", newIntPoint(1, 1))
+	now = now.Add(40 * time.Second)
+	cache.intDelta("fresh", newIntPoint(2, 1))
+	now = now.Add(40 * time.Second)
+
+	delta, ok := cache.intDelta("fresh", newIntPoint(3, 1))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), delta)
+	_, ok = cache.intDelta("stale", newIntPoint(2, 1))
+	assert.False(t, ok, "the series not seen for longer than the max staleness are forgotten")
+}
@@ -16,6 +16,7 @@ package dynatraceexporter
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -56,6 +57,11 @@ func createDefaultConfig() configmodels.Exporter {
 		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ""},
 
 		Tags: []string{},
+
+		CumulativeToDelta: config.CumulativeToDeltaSettings{
+			MaxStreams:   config.DefaultMaxStreams,
+			MaxStaleness: 5 * time.Minute,
+		},
 	}
 }
 
@@ -18,6 +18,7 @@ import (
 	"context"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,6 +51,11 @@ func TestCreateDefaultConfig(t *testing.T) {
 		},
 
 		Tags: []string{},
+
+		CumulativeToDelta: config.CumulativeToDeltaSettings{
+			MaxStreams:   config.DefaultMaxStreams,
+			MaxStaleness: 5 * time.Minute,
+		},
 	}, cfg, "failed to create default config")
 
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
@@ -92,8 +98,24 @@ func TestLoadConfig(t *testing.T) {
 		Prefix: "myprefix",
 
 		Tags: []string{"example=tag"},
+
+		DefaultDimensions: map[string]string{"dimension_example": "dimension_value"},
+
+		CumulativeToDelta: config.CumulativeToDeltaSettings{
+			MaxStreams:   1000,
+			MaxStaleness: 10 * time.Minute,
+		},
 	}, apiConfig)
 
+	oneAgentConfig := cfg.Exporters["dynatrace/oneagent"].(*config.Config)
+	err = oneAgentConfig.Sanitize()
+
+	require.NoError(t, err)
+	assert.Equal(t, config.OneAgentEndpoint, oneAgentConfig.Endpoint)
+	assert.Equal(t, map[string]string{
+		"Content-Type": "text/plain; charset=UTF-8",
+		"User-Agent":   "opentelemetry-collector"}, oneAgentConfig.Headers)
+
 	invalidConfig2 := cfg.Exporters["dynatrace/invalid"].(*config.Config)
 	err = invalidConfig2.Sanitize()
 	require.Error(t, err)
@@ -38,19 +38,31 @@ func newMetricsExporter(params component.ExporterCreateParams, cfg *config.Confi
 	if err != nil {
 		return nil, err
 	}
+	defaultDimensions, err := serialization.NormalizeDimensions(cfg.DefaultDimensions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default_dimensions: %w", err)
+	}
+	deltas, err := newDeltaCache(params.Logger, cfg.CumulativeToDelta.MaxStreams, cfg.CumulativeToDelta.MaxStaleness)
+	if err != nil {
+		return nil, err
+	}
 	return &exporter{
-		logger: params.Logger,
-		cfg:    cfg,
-		client: client,
+		logger:            params.Logger,
+		cfg:               cfg,
+		client:            client,
+		defaultDimensions: defaultDimensions,
+		deltas:            deltas,
 	}, nil
 }
 
 // exporter forwards metrics to a Dynatrace agent
 type exporter struct {
-	logger     *zap.Logger
-	cfg        *config.Config
-	client     *http.Client
-	isDisabled bool
+	logger            *zap.Logger
+	cfg               *config.Config
+	client            *http.Client
+	isDisabled        bool
+	defaultDimensions map[string]string
+	deltas            *deltaCache
 }
 
 const (
@@ -82,11 +94,13 @@ func (e *exporter) PushMetricsData(ctx context.Context, md pdata.Metrics) (int,
 func (e *exporter) serializeMetrics(md pdata.Metrics) ([]string, int) {
 	lines := make([]string, 0)
 	dropped := 0
+	dims := serialization.Dimensions{Defaults: e.defaultDimensions, Tags: e.cfg.Tags}
 
 	resourceMetrics := md.ResourceMetrics()
 
 	for i := 0; i < resourceMetrics.Len(); i++ {
 		resourceMetric := resourceMetrics.At(i)
+		resourceKey := attributesKey(resourceMetric.Resource().Attributes())
 		libraryMetrics := resourceMetric.InstrumentationLibraryMetrics()
 		for j := 0; j < libraryMetrics.Len(); j++ {
 			libraryMetric := libraryMetrics.At(j)
@@ -102,22 +116,30 @@ func (e *exporter) serializeMetrics(md pdata.Metrics) ([]string, int) {
 
 				e.logger.Debug("Exporting type " + metric.DataType().String())
 
+				var metricLines []string
+				var metricDropped int
 				switch metric.DataType() {
 				case pdata.MetricDataTypeNone:
 					continue
 				case pdata.MetricDataTypeIntGauge:
-					lines = append(lines, serialization.SerializeIntDataPoints(name, metric.IntGauge().DataPoints(), e.cfg.Tags))
+					metricLines, metricDropped = serialization.SerializeIntDataPoints(name, metric.IntGauge().DataPoints(), dims)
 				case pdata.MetricDataTypeDoubleGauge:
-					lines = append(lines, serialization.SerializeDoubleDataPoints(name, metric.DoubleGauge().DataPoints(), e.cfg.Tags))
+					metricLines, metricDropped = serialization.SerializeDoubleDataPoints(name, metric.DoubleGauge().DataPoints(), dims)
 				case pdata.MetricDataTypeIntSum:
-					lines = append(lines, serialization.SerializeIntDataPoints(name, metric.IntSum().DataPoints(), e.cfg.Tags))
+					metricLines, metricDropped = e.serializeIntSum(name, name+seriesKeySectionSeparator+resourceKey, metric.IntSum(), dims)
 				case pdata.MetricDataTypeDoubleSum:
-					lines = append(lines, serialization.SerializeDoubleDataPoints(name, metric.DoubleSum().DataPoints(), e.cfg.Tags))
+					metricLines, metricDropped = e.serializeDoubleSum(name, name+seriesKeySectionSeparator+resourceKey, metric.DoubleSum(), dims)
 				case pdata.MetricDataTypeIntHistogram:
-					lines = append(lines, serialization.SerializeIntHistogramMetrics(name, metric.IntHistogram().DataPoints(), e.cfg.Tags))
+					metricLines, metricDropped = serialization.SerializeIntHistogramMetrics(name, metric.IntHistogram().DataPoints(), dims)
 				case pdata.MetricDataTypeDoubleHistogram:
-					lines = append(lines, serialization.SerializeDoubleHistogramMetrics(name, metric.DoubleHistogram().DataPoints(), e.cfg.Tags))
+					metricLines, metricDropped = serialization.SerializeDoubleHistogramMetrics(name, metric.DoubleHistogram().DataPoints(), dims)
+				}
+
+				if metricDropped > 0 {
+					e.logger.Debug(fmt.Sprintf("Dropped %d data points of %s exceeding the limits of a line", metricDropped, name))
 				}
+				lines = append(lines, metricLines...)
+				dropped += metricDropped
 			}
 		}
 	}
@@ -125,6 +147,68 @@ func (e *exporter) serializeMetrics(md pdata.Metrics) ([]string, int) {
 	return lines, dropped
 }
 
+// serializeIntSum serializes the data points of delta sums, and the deltas of the monotonic cumulative sums
+// since their previous observation, to Dynatrace counts. The first observation of a cumulative series only
+// serves as the baseline of the next ones. The non-monotonic cumulative sums are serialized to gauges.
+func (e *exporter) serializeIntSum(name, metricKey string, sum pdata.IntSum, dims serialization.Dimensions) ([]string, int) {
+	temporality := sum.AggregationTemporality()
+	if temporality != pdata.AggregationTemporalityDelta && !(temporality == pdata.AggregationTemporalityCumulative && sum.IsMonotonic()) {
+		return serialization.SerializeIntDataPoints(name, sum.DataPoints(), dims)
+	}
+
+	lines := make([]string, 0)
+	dropped := 0
+	dps := sum.DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		p := dps.At(i)
+		delta := p.Value()
+		if temporality == pdata.AggregationTemporalityCumulative {
+			var ok bool
+			if delta, ok = e.deltas.intDelta(metricKey, p); !ok {
+				continue
+			}
+		}
+		line, ok := serialization.SerializeIntCount(name, p, delta, dims)
+		if !ok {
+			dropped++
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, dropped
+}
+
+// serializeDoubleSum serializes the data points of delta sums, and the deltas of the monotonic cumulative sums
+// since their previous observation, to Dynatrace counts. The first observation of a cumulative series only
+// serves as the baseline of the next ones. The non-monotonic cumulative sums are serialized to gauges.
+func (e *exporter) serializeDoubleSum(name, metricKey string, sum pdata.DoubleSum, dims serialization.Dimensions) ([]string, int) {
+	temporality := sum.AggregationTemporality()
+	if temporality != pdata.AggregationTemporalityDelta && !(temporality == pdata.AggregationTemporalityCumulative && sum.IsMonotonic()) {
+		return serialization.SerializeDoubleDataPoints(name, sum.DataPoints(), dims)
+	}
+
+	lines := make([]string, 0)
+	dropped := 0
+	dps := sum.DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		p := dps.At(i)
+		delta := p.Value()
+		if temporality == pdata.AggregationTemporalityCumulative {
+			var ok bool
+			if delta, ok = e.deltas.doubleDelta(metricKey, p); !ok {
+				continue
+			}
+		}
+		line, ok := serialization.SerializeDoubleCount(name, p, delta, dims)
+		if !ok {
+			dropped++
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, dropped
+}
+
 // send sends a serialized metric batch to Dynatrace.
 // Returns the number of lines rejected by Dynatrace.
 // An error indicates all lines were dropped regardless of the returned number.
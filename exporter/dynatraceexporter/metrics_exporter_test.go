@@ -20,8 +20,10 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
@@ -181,6 +183,82 @@ func Test_exporter_PushMetricsData(t *testing.T) {
 	}
 }
 
+func Test_exporter_PushMetricsData_Sums(t *testing.T) {
+	sent := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := ioutil.ReadAll(r.Body)
+		sent = append(sent, string(bodyBytes))
+		body, _ := json.Marshal(metricsResponse{})
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	newMetrics := func(cumulative int64, delta float64) pdata.Metrics {
+		md := pdata.NewMetrics()
+		md.ResourceMetrics().Resize(1)
+		ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+		ilms.Resize(1)
+		metrics := ilms.At(0).Metrics()
+		metrics.Resize(2)
+
+		cumulativeMetric := metrics.At(0)
+		cumulativeMetric.SetDataType(pdata.MetricDataTypeIntSum)
+		cumulativeMetric.SetName("cumulative_sum")
+		cumulativeMetric.IntSum().SetIsMonotonic(true)
+		cumulativeMetric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		cumulativeMetric.IntSum().DataPoints().Resize(1)
+		cumulativePoint := cumulativeMetric.IntSum().DataPoints().At(0)
+		cumulativePoint.SetValue(cumulative)
+		cumulativePoint.SetTimestamp(pdata.TimestampUnixNano(100_000_000))
+		cumulativePoint.LabelsMap().Insert("zone", "us")
+
+		deltaMetric := metrics.At(1)
+		deltaMetric.SetDataType(pdata.MetricDataTypeDoubleSum)
+		deltaMetric.SetName("delta_sum")
+		deltaMetric.DoubleSum().SetIsMonotonic(true)
+		deltaMetric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+		deltaMetric.DoubleSum().DataPoints().Resize(1)
+		deltaPoint := deltaMetric.DoubleSum().DataPoints().At(0)
+		deltaPoint.SetValue(delta)
+		deltaPoint.SetTimestamp(pdata.TimestampUnixNano(100_000_000))
+		return md
+	}
+
+	cfg := &config.Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+		DefaultDimensions:  map[string]string{"Zone": "eu", "env": "prod"},
+		CumulativeToDelta:  config.CumulativeToDeltaSettings{MaxStreams: 10},
+	}
+	e, err := newMetricsExporter(component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	if err != nil {
+		t.Fatalf("newMetricsExporter() error = %v", err)
+	}
+
+	for _, md := range []pdata.Metrics{newMetrics(10, 1.5), newMetrics(25, 2)} {
+		if _, err := e.PushMetricsData(context.Background(), md); err != nil {
+			t.Fatalf("exporter.PushMetricsData() error = %v", err)
+		}
+	}
+
+	want := []string{
+		"delta_sum,env=\"prod\",zone=\"eu\" count,delta=1.5 100",
+		"cumulative_sum,env=\"prod\",zone=\"us\" count,delta=15 100\ndelta_sum,env=\"prod\",zone=\"eu\" count,delta=2 100",
+	}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("exporter.PushMetricsData():ResponseBody = %#v, want %#v", sent, want)
+	}
+}
+
+func Test_newMetricsExporter_InvalidDefaultDimensions(t *testing.T) {
+	cfg := &config.Config{
+		DefaultDimensions: map[string]string{"_": "value"},
+		CumulativeToDelta: config.CumulativeToDeltaSettings{MaxStreams: 10},
+	}
+	if _, err := newMetricsExporter(component.ExporterCreateParams{Logger: zap.NewNop()}, cfg); err == nil {
+		t.Error("newMetricsExporter() should fail on invalid default dimensions")
+	}
+}
+
 func Test_exporter_PushMetricsData_EmptyPayload(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Fatal("Server should not be called")
@@ -17,8 +17,10 @@ package serialization
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
@@ -28,81 +30,142 @@ var (
 )
 
 const (
-	maxDimKeyLen = 100
+	maxDimKeyLen   = 100
+	maxDimValueLen = 250
+
+	// MaxLineLength is the maximum length of a line accepted by Dynatrace
+	MaxLineLength = 50000
+	// MaxDimensions is the maximum number of dimensions of a line accepted by Dynatrace
+	MaxDimensions = 50
 )
 
-// SerializeIntDataPoints serializes a slice of integer datapoints to a Dynatrace gauge.
-func SerializeIntDataPoints(name string, data pdata.IntDataPointSlice, tags []string) string {
+// Dimensions are the dimensions added to every serialized line
+type Dimensions struct {
+	// Defaults are the default dimensions by normalized key, which the labels of the data points override
+	Defaults map[string]string
+	// Tags are dimensions in the key=value format of the ingestion protocol, added as they are
+	Tags []string
+}
+
+// SerializeIntDataPoints serializes a slice of integer datapoints to Dynatrace gauges.
+// It returns the lines along with the number of data points dropped because their line exceeds the limits.
+func SerializeIntDataPoints(name string, data pdata.IntDataPointSlice, dims Dimensions) ([]string, int) {
 	// {name} {value} {timestamp}
-	output := ""
+	lines := newLines()
 	for i := 0; i < data.Len(); i++ {
 		p := data.At(i)
-		tagline := serializeTags(p.LabelsMap(), tags)
-		valueLine := strconv.FormatInt(p.Value(), 10)
-
-		output += serializeLine(name, tagline, valueLine, p.Timestamp())
+		lines.add(name, serializeTags(p.LabelsMap(), dims), strconv.FormatInt(p.Value(), 10), p.Timestamp())
 	}
 
-	return output
+	return lines.lines, lines.dropped
 }
 
-// SerializeDoubleDataPoints serializes a slice of double datapoints to a Dynatrace gauge.
-func SerializeDoubleDataPoints(name string, data pdata.DoubleDataPointSlice, tags []string) string {
+// SerializeDoubleDataPoints serializes a slice of double datapoints to Dynatrace gauges.
+// It returns the lines along with the number of data points dropped because their line exceeds the limits.
+func SerializeDoubleDataPoints(name string, data pdata.DoubleDataPointSlice, dims Dimensions) ([]string, int) {
 	// {name} {value} {timestamp}
-	output := ""
+	lines := newLines()
 	for i := 0; i < data.Len(); i++ {
 		p := data.At(i)
-		output += serializeLine(name, serializeTags(p.LabelsMap(), tags), serializeFloat64(p.Value()), p.Timestamp())
+		lines.add(name, serializeTags(p.LabelsMap(), dims), serializeFloat64(p.Value()), p.Timestamp())
 	}
 
-	return output
+	return lines.lines, lines.dropped
+}
+
+// SerializeIntCount serializes the delta of an integer datapoint to a Dynatrace count.
+// It returns false when the line exceeds the limits.
+func SerializeIntCount(name string, p pdata.IntDataPoint, delta int64, dims Dimensions) (string, bool) {
+	// {name} count,delta={delta} {timestamp}
+	lines := newLines()
+	lines.add(name, serializeTags(p.LabelsMap(), dims), "count,delta="+strconv.FormatInt(delta, 10), p.Timestamp())
+	return lines.single()
 }
 
-// SerializeDoubleHistogramMetrics serializes a slice of double histogram datapoints to a Dynatrace gauge.
+// SerializeDoubleCount serializes the delta of a double datapoint to a Dynatrace count.
+// It returns false when the line exceeds the limits.
+func SerializeDoubleCount(name string, p pdata.DoubleDataPoint, delta float64, dims Dimensions) (string, bool) {
+	// {name} count,delta={delta} {timestamp}
+	lines := newLines()
+	lines.add(name, serializeTags(p.LabelsMap(), dims), "count,delta="+serializeFloat64(delta), p.Timestamp())
+	return lines.single()
+}
+
+// SerializeDoubleHistogramMetrics serializes a slice of double histogram datapoints to Dynatrace gauges.
+// It returns the lines along with the number of data points dropped because their line exceeds the limits.
 //
 // IMPORTANT: Min and max are required by Dynatrace but not provided by histogram so they are assumed to be the average.
-func SerializeDoubleHistogramMetrics(name string, data pdata.DoubleHistogramDataPointSlice, tags []string) string {
+func SerializeDoubleHistogramMetrics(name string, data pdata.DoubleHistogramDataPointSlice, dims Dimensions) ([]string, int) {
 	// {name} gauge,min=9.75,max=9.75,sum=19.5,count=2 {timestamp_unix_ms}
-	output := ""
+	lines := newLines()
 	for i := 0; i < data.Len(); i++ {
 		p := data.At(i)
-		tagline := serializeTags(p.LabelsMap(), tags)
 		if p.Count() == 0 {
-			return ""
+			continue
 		}
 		avg := p.Sum() / float64(p.Count())
 
 		valueLine := fmt.Sprintf("gauge,min=%[1]s,max=%[1]s,sum=%s,count=%d", serializeFloat64(avg), serializeFloat64(p.Sum()), p.Count())
 
-		output += serializeLine(name, tagline, valueLine, p.Timestamp())
+		lines.add(name, serializeTags(p.LabelsMap(), dims), valueLine, p.Timestamp())
 	}
 
-	return output
+	return lines.lines, lines.dropped
 }
 
-// SerializeIntHistogramMetrics serializes a slice of integer histogram datapoints to a Dynatrace gauge.
+// SerializeIntHistogramMetrics serializes a slice of integer histogram datapoints to Dynatrace gauges.
+// It returns the lines along with the number of data points dropped because their line exceeds the limits.
 //
 // IMPORTANT: Min and max are required by Dynatrace but not provided by histogram so they are assumed to be the average.
-func SerializeIntHistogramMetrics(name string, data pdata.IntHistogramDataPointSlice, tags []string) string {
+func SerializeIntHistogramMetrics(name string, data pdata.IntHistogramDataPointSlice, dims Dimensions) ([]string, int) {
 	// {name} gauge,min=9.5,max=9.5,sum=19,count=2 {timestamp_unix_ms}
-	output := ""
+	lines := newLines()
 	for i := 0; i < data.Len(); i++ {
 		p := data.At(i)
-		tagline := serializeTags(p.LabelsMap(), tags)
 		count := p.Count()
 
 		if count == 0 {
-			return ""
+			continue
 		}
 
 		avg := float64(p.Sum()) / float64(count)
 
 		valueLine := fmt.Sprintf("gauge,min=%[1]s,max=%[1]s,sum=%d,count=%d", serializeFloat64(avg), p.Sum(), count)
 
-		output += serializeLine(name, tagline, valueLine, p.Timestamp())
+		lines.add(name, serializeTags(p.LabelsMap(), dims), valueLine, p.Timestamp())
 	}
 
-	return output
+	return lines.lines, lines.dropped
+}
+
+// lineBuilder collects the lines within the limits of Dynatrace, counting the other ones
+type lineBuilder struct {
+	lines   []string
+	dropped int
+}
+
+func newLines() *lineBuilder {
+	return &lineBuilder{lines: []string{}}
+}
+
+func (b *lineBuilder) add(name string, tags []string, valueline string, timestamp pdata.TimestampUnixNano) {
+	if len(tags) > MaxDimensions {
+		b.dropped++
+		return
+	}
+	line := serializeLine(name, strings.Join(tags, ","), valueline, timestamp)
+	if len(line) > MaxLineLength {
+		b.dropped++
+		return
+	}
+	b.lines = append(b.lines, line)
+}
+
+func (b *lineBuilder) single() (string, bool) {
+	if len(b.lines) == 0 {
+		return "", false
+	}
+	return b.lines[0], true
 }
 
 func serializeLine(name, tagline, valueline string, timestamp pdata.TimestampUnixNano) string {
@@ -120,33 +183,61 @@ func serializeLine(name, tagline, valueline string, timestamp pdata.TimestampUni
 	return output
 }
 
-func serializeTags(labels pdata.StringMap, exporterTags []string) string {
-	tags := append([]string{}, exporterTags...)
+// serializeTags returns the tags, followed by the default dimensions which aren't overridden by the labels,
+// ordered by key, and by the labels
+func serializeTags(labels pdata.StringMap, dims Dimensions) []string {
+	tags := append([]string{}, dims.Tags...)
+
+	labelTags := []string{}
+	labelKeys := map[string]bool{}
 	labels.ForEach(func(k string, v string) {
 		key, err := NormalizeString(strings.ToLower(k), maxDimKeyLen)
 		if err != nil {
 			return
 		}
-		value := escapeDimension(v)
-		tag := key + "=" + value
-		tags = append(tags, tag)
+		labelKeys[key] = true
+		labelTags = append(labelTags, key+"="+escapeDimension(v))
 	})
 
-	tagline := ""
-
-	for _, tag := range tags {
-		if tagline != "" {
-			tagline += ","
+	defaultKeys := make([]string, 0, len(dims.Defaults))
+	for key := range dims.Defaults {
+		if !labelKeys[key] {
+			defaultKeys = append(defaultKeys, key)
 		}
-		tagline += tag
+	}
+	sort.Strings(defaultKeys)
+	for _, key := range defaultKeys {
+		tags = append(tags, key+"="+escapeDimension(dims.Defaults[key]))
 	}
 
-	return tagline
+	return append(tags, labelTags...)
+}
+
+// NormalizeDimensions normalizes the keys of the given dimensions as the keys of the labels are. The dimensions
+// whose key can't be normalized are returned as errors.
+func NormalizeDimensions(dimensions map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(dimensions))
+	for k, v := range dimensions {
+		key, err := NormalizeString(strings.ToLower(k), maxDimKeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dimension key %q: %w", k, err)
+		}
+		normalized[key] = v
+	}
+	return normalized, nil
 }
 
 // Escape dimension values based on the specification at https://www.dynatrace.com/support/help/shortlink/metric-ingestion-protocol#dimension-optional
+// The values are truncated to the maximum length of a dimension value beforehand.
 func escapeDimension(dim string) string {
-	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(strings.ReplaceAll(dim, "\"", "\\\""), "\\", "\\\\"))
+	if len(dim) > maxDimValueLen {
+		end := maxDimValueLen
+		for end > 0 && !utf8.RuneStart(dim[end]) {
+			end--
+		}
+		dim = dim[:end]
+	}
+	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(strings.ReplaceAll(dim, "\\", "\\\\"), "\"", "\\\""))
 }
 
 // NormalizeString replaces all non-alphanumerical characters to underscore
@@ -171,8 +262,10 @@ func NormalizeString(str string, max int) (normalizedString string, err error) {
 }
 
 func serializeFloat64(n float64) string {
-	str := strings.TrimRight(strconv.FormatFloat(n, 'f', 6, 64), "0.")
-	if str == "" {
+	str := strconv.FormatFloat(n, 'f', 6, 64)
+	// trim the trailing zeros of the decimals, and the decimal point when they are all zeros
+	str = strings.TrimRight(strings.TrimRight(str, "0"), ".")
+	if str == "" || str == "-0" {
 		// if everything was trimmed away, number was 0.000000
 		return "0"
 	}
@@ -15,6 +15,9 @@
 package serialization
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
@@ -75,7 +78,8 @@ func TestSerializeIntDataPoints(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := SerializeIntDataPoints(tt.args.name, tt.args.data, tt.args.tags); got != tt.want {
+			if lines, _ := SerializeIntDataPoints(tt.args.name, tt.args.data, Dimensions{Tags: tt.args.tags}); strings.Join(lines, "\n") != tt.want {
+				got := strings.Join(lines, "\n")
 				t.Errorf("SerializeIntDataPoints() = %#v, want %#v", got, tt.want)
 			}
 		})
@@ -136,7 +140,8 @@ func TestSerializeDoubleDataPoints(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := SerializeDoubleDataPoints(tt.args.name, tt.args.data, tt.args.tags); got != tt.want {
+			if lines, _ := SerializeDoubleDataPoints(tt.args.name, tt.args.data, Dimensions{Tags: tt.args.tags}); strings.Join(lines, "\n") != tt.want {
+				got := strings.Join(lines, "\n")
 				t.Errorf("SerializeDoubleDataPoints() = %v, want %v", got, tt.want)
 			}
 		})
@@ -215,7 +220,8 @@ func TestSerializeDoubleHistogramMetrics(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := SerializeDoubleHistogramMetrics(tt.args.name, tt.args.data, tt.args.tags); got != tt.want {
+			if lines, _ := SerializeDoubleHistogramMetrics(tt.args.name, tt.args.data, Dimensions{Tags: tt.args.tags}); strings.Join(lines, "\n") != tt.want {
+				got := strings.Join(lines, "\n")
 				t.Errorf("SerializeDoubleHistogramMetrics() = %v, want %v", got, tt.want)
 			}
 		})
@@ -294,7 +300,8 @@ func TestSerializeIntHistogramMetrics(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := SerializeIntHistogramMetrics(tt.args.name, tt.args.data, tt.args.tags); got != tt.want {
+			if lines, _ := SerializeIntHistogramMetrics(tt.args.name, tt.args.data, Dimensions{Tags: tt.args.tags}); strings.Join(lines, "\n") != tt.want {
+				got := strings.Join(lines, "\n")
 				t.Errorf("SerializeIntHistogramMetrics() = %v, want %v", got, tt.want)
 			}
 		})
@@ -371,7 +378,7 @@ func Test_serializeTags(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := serializeTags(tt.args.labels, tt.args.exporterTags); got != tt.want {
+			if got := strings.Join(serializeTags(tt.args.labels, Dimensions{Tags: tt.args.exporterTags}), ","); got != tt.want {
 				t.Errorf("serializeTags() = %v, want %v", got, tt.want)
 			}
 		})
@@ -475,6 +482,16 @@ func Test_serializeFloat64(t *testing.T) {
 			args: args{n: 1.0000000000000001},
 			want: "1",
 		},
+		{
+			name: "Serialize 10.0 to 10",
+			args: args{n: 10.0},
+			want: "10",
+		},
+		{
+			name: "Serialize negative numbers",
+			args: args{n: -100.5},
+			want: "-100.5",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -484,3 +501,100 @@ func Test_serializeFloat64(t *testing.T) {
 		})
 	}
 }
+
+func TestSerializeMultipleDataPoints(t *testing.T) {
+	intSlice := pdata.NewIntDataPointSlice()
+	intSlice.Resize(2)
+	intSlice.At(0).SetValue(13)
+	intSlice.At(0).SetTimestamp(pdata.TimestampUnixNano(100_000_000))
+	intSlice.At(1).SetValue(14)
+	intSlice.At(1).SetTimestamp(pdata.TimestampUnixNano(200_000_000))
+
+	lines, dropped := SerializeIntDataPoints("my_int_gauge", intSlice, Dimensions{})
+	if want := []string{"my_int_gauge 13 100", "my_int_gauge 14 200"}; !reflect.DeepEqual(lines, want) || dropped != 0 {
+		t.Errorf("SerializeIntDataPoints() = %#v, %d, want %#v, 0", lines, dropped, want)
+	}
+}
+
+func TestSerializeCounts(t *testing.T) {
+	intPoint := pdata.NewIntDataPoint()
+	intPoint.SetTimestamp(pdata.TimestampUnixNano(100_000_000))
+	intPoint.LabelsMap().Insert("labelKey", "labelValue")
+	if got, ok := SerializeIntCount("my_int_count", intPoint, 10, Dimensions{}); !ok || got != "my_int_count,labelkey=\"labelValue\" count,delta=10 100" {
+		t.Errorf("SerializeIntCount() = %v, %v", got, ok)
+	}
+
+	doublePoint := pdata.NewDoubleDataPoint()
+	doublePoint.SetTimestamp(pdata.TimestampUnixNano(100_000_000))
+	if got, ok := SerializeDoubleCount("my_double_count", doublePoint, 2.5, Dimensions{Tags: []string{"tag=value"}}); !ok || got != "my_double_count,tag=value count,delta=2.5 100" {
+		t.Errorf("SerializeDoubleCount() = %v, %v", got, ok)
+	}
+}
+
+func TestSerializeLineLimits(t *testing.T) {
+	tooManyLabels := pdata.NewIntDataPoint()
+	for i := 0; i <= MaxDimensions; i++ {
+		tooManyLabels.LabelsMap().Insert(fmt.Sprintf("key%d", i), "value")
+	}
+	if _, ok := SerializeIntCount("my_int_count", tooManyLabels, 1, Dimensions{}); ok {
+		t.Error("SerializeIntCount() should drop lines with too many dimensions")
+	}
+
+	longSlice := pdata.NewDoubleDataPointSlice()
+	longSlice.Resize(2)
+	longSlice.At(1).LabelsMap().Insert("key", "value")
+	tags := []string{"tag=" + strings.Repeat("a", MaxLineLength)}
+	lines, dropped := SerializeDoubleDataPoints("my_double_gauge", longSlice, Dimensions{Tags: tags})
+	if len(lines) != 0 || dropped != 2 {
+		t.Errorf("SerializeDoubleDataPoints() = %d lines, %d dropped, want 0 lines, 2 dropped", len(lines), dropped)
+	}
+}
+
+func Test_serializeTagsDefaultDimensions(t *testing.T) {
+	labels := pdata.NewStringMap().InitFromMap(map[string]string{"Overridden": "label"})
+	dims := Dimensions{
+		Defaults: map[string]string{"overridden": "default", "zone": "eu", "host": "h1"},
+		Tags:     []string{"tag=value"},
+	}
+	want := "tag=value,host=\"h1\",zone=\"eu\",overridden=\"label\""
+	if got := strings.Join(serializeTags(labels, dims), ","); got != want {
+		t.Errorf("serializeTags() = %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeDimensions(t *testing.T) {
+	got, err := NormalizeDimensions(map[string]string{"Dim Key": "value"})
+	if err != nil || !reflect.DeepEqual(got, map[string]string{"dim_key": "value"}) {
+		t.Errorf("NormalizeDimensions() = %v, %v", got, err)
+	}
+
+	if _, err := NormalizeDimensions(map[string]string{"0_": "value"}); err == nil {
+		t.Error("NormalizeDimensions() should fail on keys normalized to nothing")
+	}
+}
+
+func Test_escapeDimension(t *testing.T) {
+	tests := []struct {
+		name string
+		dim  string
+		want string
+	}{
+		{
+			name: "Quotes and backslashes are escaped",
+			dim:  `a"b\c`,
+			want: `"a\"b\\c"`,
+		},
+		{
+			name: "Long values are truncated",
+			dim:  strings.Repeat("é", 200),
+			want: `"` + strings.Repeat("é", 125) + `"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeDimension(tt.dim); got != tt.want {
+				t.Errorf("escapeDimension() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
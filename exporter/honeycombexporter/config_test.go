@@ -17,12 +17,14 @@ package honeycombexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -46,14 +48,25 @@ func TestLoadConfig(t *testing.T) {
 	r1 := cfg.Exporters["honeycomb/customname"].(*Config)
 	assert.Equal(t, r1, &Config{
 		ExporterSettings: configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "honeycomb/customname"},
-		APIKey:           "test-apikey",
-		Dataset:          "test-dataset",
-		APIURL:           "https://api.testhost.io",
+		TimeoutSettings:  exporterhelper.DefaultTimeoutSettings(),
+		QueueSettings:    exporterhelper.DefaultQueueSettings(),
+		RetrySettings: exporterhelper.RetrySettings{
+			Enabled:         true,
+			InitialInterval: 5 * time.Second,
+			MaxInterval:     30 * time.Second,
+			MaxElapsedTime:  time.Minute,
+		},
+		APIKey:  "test-apikey",
+		Dataset: "test-dataset",
+		APIURL:  "https://api.testhost.io",
 	})
 
 	r2 := cfg.Exporters["honeycomb/sample_rate"].(*Config)
 	assert.Equal(t, r2, &Config{
 		ExporterSettings:    configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "honeycomb/sample_rate"},
+		TimeoutSettings:     exporterhelper.DefaultTimeoutSettings(),
+		QueueSettings:       exporterhelper.DefaultQueueSettings(),
+		RetrySettings:       exporterhelper.DefaultRetrySettings(),
 		APIURL:              "https://api.honeycomb.io",
 		SampleRate:          5,
 		SampleRateAttribute: "custom.sample_rate",
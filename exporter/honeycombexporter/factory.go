@@ -41,6 +41,9 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
+		TimeoutSettings:     exporterhelper.DefaultTimeoutSettings(),
+		QueueSettings:       exporterhelper.DefaultQueueSettings(),
+		RetrySettings:       exporterhelper.DefaultRetrySettings(),
 		APIKey:              "",
 		Dataset:             "",
 		APIURL:              "https://api.honeycomb.io",
@@ -64,5 +67,8 @@ func createTraceExporter(
 		cfg,
 		params.Logger,
 		exporter.pushTraceData,
+		exporterhelper.WithTimeout(eCfg.TimeoutSettings),
+		exporterhelper.WithRetry(eCfg.RetrySettings),
+		exporterhelper.WithQueue(eCfg.QueueSettings),
 		exporterhelper.WithShutdown(exporter.Shutdown))
 }
@@ -16,11 +16,15 @@ package honeycombexporter
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"net/http"
 	"time"
 
 	"github.com/honeycombio/libhoney-go"
 	"github.com/honeycombio/libhoney-go/transmission"
 	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 )
@@ -32,10 +36,12 @@ const (
 
 // honeycombExporter is the object that sends events to honeycomb.
 type honeycombExporter struct {
+	client              *libhoney.Client
 	builder             *libhoney.Builder
 	onError             func(error)
 	logger              *zap.Logger
 	sampleRateAttribute string
+	cancel              context.CancelFunc
 }
 
 // event represents a honeycomb event.
@@ -68,27 +74,52 @@ type link struct {
 	AnnotationType string `json:"meta.annotation_type"`
 }
 
+// spanIndex is the position of a span in the traces being pushed.
+type spanIndex struct {
+	resource int
+	library  int
+	span     int
+}
+
+// eventMetadata is attached to the events sent by pushTraceData, so that their
+// responses are routed back to it.
+type eventMetadata struct {
+	responses chan<- transmission.Response
+	span      spanIndex
+}
+
 // newHoneycombTraceExporter creates and returns a new honeycombExporter. It
 // wraps the exporter in the component.TraceExporterOld helper method.
 func newHoneycombTraceExporter(cfg *Config, logger *zap.Logger) (*honeycombExporter, error) {
-	libhoneyConfig := libhoney.Config{
-		WriteKey: cfg.APIKey,
-		Dataset:  cfg.Dataset,
-		APIHost:  cfg.APIURL,
+	libhoneyConfig := libhoney.ClientConfig{
+		APIKey:     cfg.APIKey,
+		Dataset:    cfg.Dataset,
+		APIHost:    cfg.APIURL,
+		SampleRate: cfg.SampleRate,
+	}
+	tx := &transmission.Honeycomb{
+		MaxBatchSize:         libhoney.DefaultMaxBatchSize,
+		BatchTimeout:         libhoney.DefaultBatchTimeout,
+		MaxConcurrentBatches: libhoney.DefaultMaxConcurrentBatches,
+		PendingWorkCapacity:  libhoney.DefaultPendingWorkCapacity,
+		UserAgentAddition:    oTelCollectorUserAgentStr,
+		// The responses are awaited by pushTraceData, they mustn't be dropped.
+		BlockOnResponse: true,
 	}
-	userAgent := oTelCollectorUserAgentStr
-	libhoney.UserAgentAddition = userAgent
 
 	if cfg.Debug {
 		libhoneyConfig.Logger = &libhoney.DefaultLogger{}
+		tx.Logger = libhoneyConfig.Logger
 	}
+	libhoneyConfig.Transmission = tx
 
-	if err := libhoney.Init(libhoneyConfig); err != nil {
+	client, err := libhoney.NewClient(libhoneyConfig)
+	if err != nil {
 		return nil, err
 	}
-	builder := libhoney.NewBuilder()
 	exporter := &honeycombExporter{
-		builder: builder,
+		client:  client,
+		builder: client.NewBuilder(),
 		logger:  logger,
 		onError: func(err error) {
 			logger.Warn(err.Error())
@@ -96,20 +127,46 @@ func newHoneycombTraceExporter(cfg *Config, logger *zap.Logger) (*honeycombExpor
 		sampleRateAttribute: cfg.SampleRateAttribute,
 	}
 
+	// Run the error logger. This listens for messages in the response queue,
+	// routes them to the pushTraceData call which sent their event, and writes
+	// out the others using the logger.
+	var ctx context.Context
+	ctx, exporter.cancel = context.WithCancel(context.Background())
+	go exporter.RunErrorLogger(ctx, client.TxResponses())
+
 	return exporter, nil
 }
 
 // pushTraceData is the method called when trace data is available. It will be
-// responsible for sending a batch of events.
+// responsible for sending a batch of events, and waits for their responses:
+// the spans rejected because of rate limiting or a server error, or which
+// couldn't be sent, are returned to be retried, the other failures are permanent.
 func (e *honeycombExporter) pushTraceData(ctx context.Context, td pdata.Traces) (int, error) {
+	responses := make(chan transmission.Response, eventCount(td))
+	sent := 0
+	dropped := map[spanIndex]bool{}
+	retry := map[spanIndex]bool{}
 	var errs []error
-	goodSpans := 0
-
-	// Run the error logger. This just listens for messages in the error
-	// response queue and writes them out using the logger.
-	ctx, cancel := context.WithCancel(ctx)
-	go e.RunErrorLogger(ctx, libhoney.TxResponses())
-	defer cancel()
+	seenErrs := map[string]bool{}
+	addError := func(index spanIndex, err error) {
+		if consumererror.IsPermanent(err) {
+			dropped[index] = true
+		} else {
+			retry[index] = true
+		}
+		if !seenErrs[err.Error()] {
+			seenErrs[err.Error()] = true
+			errs = append(errs, err)
+		}
+	}
+	send := func(ev *libhoney.Event, index spanIndex) {
+		ev.Metadata = eventMetadata{responses: responses, span: index}
+		if err := ev.SendPresampled(); err != nil {
+			addError(index, consumererror.Permanent(err))
+		} else {
+			sent++
+		}
+	}
 
 	rs := td.ResourceSpans()
 	for i := 0; i < rs.Len(); i++ {
@@ -124,6 +181,7 @@ func (e *honeycombExporter) pushTraceData(ctx context.Context, td pdata.Traces)
 			spans := ilsSpan.Spans()
 			for k := 0; k < spans.Len(); k++ {
 				span := spans.At(k)
+				index := spanIndex{resource: i, library: j, span: k}
 				ev := e.builder.NewEvent()
 
 				for k, v := range resourceAttrs {
@@ -158,23 +216,111 @@ func (e *honeycombExporter) pushTraceData(ctx context.Context, td pdata.Traces)
 					DurationMilli: float64(endTime.Sub(startTime)) / float64(time.Millisecond),
 				})
 
-				e.sendMessageEvents(span, resourceAttrs)
-				e.sendSpanLinks(span)
+				// The span events and links are sampled along with their span.
+				e.sendMessageEvents(span, resourceAttrs, ev.SampleRate, func(linked *libhoney.Event) {
+					send(linked, index)
+				})
+				e.sendSpanLinks(span, ev.SampleRate, func(linked *libhoney.Event) {
+					send(linked, index)
+				})
 
 				ev.AddField("span_kind", getSpanKind(span.Kind()))
 				ev.AddField("status.code", getStatusCode(span.Status()))
 				ev.AddField("status.message", getStatusMessage(span.Status()))
 
-				if err := ev.SendPresampled(); err != nil {
-					errs = append(errs, err)
-				} else {
-					goodSpans++
+				send(ev, index)
+			}
+		}
+	}
+
+	for ; sent > 0; sent-- {
+		select {
+		case r := <-responses:
+			if err := responseError(r); err != nil {
+				addError(r.Metadata.(eventMetadata).span, err)
+			}
+		case <-ctx.Done():
+			return td.SpanCount(), ctx.Err()
+		}
+	}
+
+	for index := range retry {
+		delete(dropped, index)
+	}
+	if len(errs) == 0 {
+		return 0, nil
+	}
+	err := componenterror.CombineErrors(errs)
+	if len(retry) == 0 {
+		return len(dropped), consumererror.Permanent(err)
+	}
+	return len(dropped) + len(retry), consumererror.PartialTracesError(err, spansToRetry(td, retry))
+}
+
+// eventCount returns the number of events sent for the spans of td, including
+// their span events and links.
+func eventCount(td pdata.Traces) int {
+	count := 0
+	rs := td.ResourceSpans()
+	for i := 0; i < rs.Len(); i++ {
+		ils := rs.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ils.Len(); j++ {
+			spans := ils.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				count += 1 + span.Events().Len() + span.Links().Len()
+			}
+		}
+	}
+	return count
+}
+
+// spansToRetry returns the traces made of the spans of td at the given indexes.
+func spansToRetry(td pdata.Traces, indexes map[spanIndex]bool) pdata.Traces {
+	retry := pdata.NewTraces()
+	rs := td.ResourceSpans()
+	for i := 0; i < rs.Len(); i++ {
+		rsSpan := rs.At(i)
+		retryRS := pdata.NewResourceSpans()
+		ils := rsSpan.InstrumentationLibrarySpans()
+		for j := 0; j < ils.Len(); j++ {
+			ilsSpan := ils.At(j)
+			retryILS := pdata.NewInstrumentationLibrarySpans()
+			spans := ilsSpan.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if indexes[spanIndex{resource: i, library: j, span: k}] {
+					retryILS.Spans().Append(spans.At(k))
 				}
 			}
+			if retryILS.Spans().Len() > 0 {
+				ilsSpan.InstrumentationLibrary().CopyTo(retryILS.InstrumentationLibrary())
+				retryRS.InstrumentationLibrarySpans().Append(retryILS)
+			}
+		}
+		if retryRS.InstrumentationLibrarySpans().Len() > 0 {
+			rsSpan.Resource().CopyTo(retryRS.Resource())
+			retry.ResourceSpans().Append(retryRS)
 		}
 	}
+	return retry
+}
 
-	return td.SpanCount() - goodSpans, componenterror.CombineErrors(errs)
+// responseError returns the error of the response to an event. The events
+// rejected because of rate limiting or a server error, dropped because the
+// queue of the transmission overflowed or which couldn't be sent can be
+// retried, the other errors, e.g. an invalid API key, are permanent.
+func responseError(r transmission.Response) error {
+	if r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300 {
+		return nil
+	}
+	err := r.Err
+	if err == nil {
+		err = fmt.Errorf("got unexpected HTTP status %d: %s", r.StatusCode, http.StatusText(r.StatusCode))
+	}
+	if r.StatusCode == 0 || r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= http.StatusInternalServerError {
+		return err
+	}
+	return consumererror.Permanent(err)
 }
 
 func getSpanKind(kind pdata.SpanKind) string {
@@ -198,13 +344,14 @@ func getSpanKind(kind pdata.SpanKind) string {
 
 // sendSpanLinks gets the list of links associated with this span and sends them as
 // separate events to Honeycomb, with a span type "link".
-func (e *honeycombExporter) sendSpanLinks(span pdata.Span) {
+func (e *honeycombExporter) sendSpanLinks(span pdata.Span, sampleRate uint, send func(*libhoney.Event)) {
 	links := span.Links()
 
 	for i := 0; i < links.Len(); i++ {
 		l := links.At(i)
 
 		ev := e.builder.NewEvent()
+		ev.SampleRate = sampleRate
 		ev.Add(link{
 			TraceID:        getHoneycombTraceID(span.TraceID()),
 			ParentID:       getHoneycombSpanID(span.SpanID()),
@@ -218,15 +365,13 @@ func (e *honeycombExporter) sendSpanLinks(span pdata.Span) {
 		}
 		e.addSampleRate(ev, attrs)
 
-		if err := ev.SendPresampled(); err != nil {
-			e.onError(err)
-		}
+		send(ev)
 	}
 }
 
 // sendMessageEvents gets the list of timeevents from the span and sends them as
 // separate events to Honeycomb, with a span type "span_event".
-func (e *honeycombExporter) sendMessageEvents(span pdata.Span, resourceAttrs map[string]interface{}) {
+func (e *honeycombExporter) sendMessageEvents(span pdata.Span, resourceAttrs map[string]interface{}, sampleRate uint, send func(*libhoney.Event)) {
 	timeEvents := span.Events()
 
 	for i := 0; i < timeEvents.Len(); i++ {
@@ -238,6 +383,7 @@ func (e *honeycombExporter) sendMessageEvents(span pdata.Span, resourceAttrs map
 
 		// treat trace level fields as underlays with same keyed span attributes taking precedence.
 		ev := e.builder.NewEvent()
+		ev.SampleRate = sampleRate
 		for k, v := range resourceAttrs {
 			ev.AddField(k, v)
 		}
@@ -255,9 +401,7 @@ func (e *honeycombExporter) sendMessageEvents(span pdata.Span, resourceAttrs map
 			ParentName:     span.Name(),
 			AnnotationType: "span_event",
 		})
-		if err := ev.SendPresampled(); err != nil {
-			e.onError(err)
-		}
+		send(ev)
 	}
 }
 
@@ -265,12 +409,14 @@ func (e *honeycombExporter) sendMessageEvents(span pdata.Span, resourceAttrs map
 // this case, we close the honeycomb sdk which flushes any events still in the
 // queue and closes any open channels between queues.
 func (e *honeycombExporter) Shutdown(context.Context) error {
-	libhoney.Close()
+	e.client.Close()
+	e.cancel()
 	return nil
 }
 
-// RunErrorLogger consumes from the response queue, calling the onError callback
-// when errors are encountered.
+// RunErrorLogger consumes from the response queue, routing the responses to
+// the events sent by pushTraceData back to it, and calling the onError callback
+// when errors are encountered on the other ones.
 //
 // This method will block until the passed context.Context is canceled, or until
 // exporter.Close is called.
@@ -281,7 +427,9 @@ func (e *honeycombExporter) RunErrorLogger(ctx context.Context, responses chan t
 			if !ok {
 				return
 			}
-			if r.Err != nil {
+			if metadata, ok := r.Metadata.(eventMetadata); ok {
+				metadata.responses <- r
+			} else if r.Err != nil {
 				e.onError(r.Err)
 			}
 		case <-ctx.Done():
@@ -290,12 +438,20 @@ func (e *honeycombExporter) RunErrorLogger(ctx context.Context, responses chan t
 	}
 }
 
+// addSampleRate sets the sample rate of the event from the sample rate
+// attribute, when it's a positive number.
 func (e *honeycombExporter) addSampleRate(event *libhoney.Event, attrs map[string]interface{}) {
 	if e.sampleRateAttribute != "" && attrs != nil {
 		if value, ok := attrs[e.sampleRateAttribute]; ok {
 			switch v := value.(type) {
 			case int64:
-				event.SampleRate = uint(v)
+				if v > 0 {
+					event.SampleRate = uint(v)
+				}
+			case float64:
+				if v >= 1 {
+					event.SampleRate = uint(math.Round(v))
+				}
 			default:
 				return
 			}
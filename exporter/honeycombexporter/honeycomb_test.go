@@ -34,6 +34,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
@@ -44,7 +45,13 @@ import (
 )
 
 type honeycombData struct {
-	Data map[string]interface{} `json:"data"`
+	Data       map[string]interface{} `json:"data"`
+	SampleRate uint                   `json:"samplerate,omitempty"`
+}
+
+type honeycombResponse struct {
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
 func testingServer(callback func(data []honeycombData)) *httptest.Server {
@@ -68,7 +75,11 @@ func testingServer(callback func(data []honeycombData)) *httptest.Server {
 			return
 		}
 		callback(data)
-		rw.Write([]byte(`OK`))
+		responses := make([]honeycombResponse, len(data))
+		for i := range responses {
+			responses[i].Status = http.StatusAccepted
+		}
+		json.NewEncoder(rw).Encode(responses)
 	}))
 }
 
@@ -444,6 +455,8 @@ func TestSampleRateAttribute(t *testing.T) {
 				"opencensus.same_process_as_parent_span": true,
 				"some_attribute":                         "A value",
 			},
+
+			SampleRate: 13,
 		},
 		{
 			Data: map[string]interface{}{
@@ -478,6 +491,121 @@ func TestSampleRateAttribute(t *testing.T) {
 	}
 }
 
+func TestSampleRate(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	ils := td.ResourceSpans().At(0).InstrumentationLibrarySpans()
+	ils.Resize(1)
+	spans := ils.At(0).Spans()
+	spans.Resize(2)
+	sampled := spans.At(0)
+	sampled.SetName("sampled")
+	sampled.Attributes().InsertDouble("sampleRate", 4)
+	sampled.Events().Resize(1)
+	sampled.Events().At(0).SetName("event")
+	sampled.Links().Resize(1)
+	spans.At(1).SetName("default")
+
+	cfg := baseConfig()
+	cfg.SampleRate = 2
+	cfg.SampleRateAttribute = "sampleRate"
+
+	got := testTraceExporter(td, t, cfg)
+
+	sampleRates := map[string]uint{}
+	for _, data := range got {
+		name, _ := data.Data["name"].(string)
+		if annotationType, ok := data.Data["meta.annotation_type"].(string); ok {
+			name = annotationType
+		}
+		sampleRates[name] = data.SampleRate
+	}
+	assert.Equal(t, map[string]uint{
+		"sampled":    4,
+		"span_event": 4,
+		"link":       4,
+		"default":    2,
+	}, sampleRates)
+}
+
+func TestExporterErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		responses []honeycombResponse
+		permanent bool
+		retried   int
+	}{
+		{
+			name:      "unauthorized",
+			status:    http.StatusUnauthorized,
+			permanent: true,
+		},
+		{
+			name:    "rate limited",
+			status:  http.StatusTooManyRequests,
+			retried: 2,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusServiceUnavailable,
+			retried: 2,
+		},
+		{
+			name:   "rate limited events",
+			status: http.StatusOK,
+			responses: []honeycombResponse{
+				{Status: http.StatusAccepted},
+				{Status: http.StatusTooManyRequests, Error: "event dropped due to rate limiting"},
+			},
+			retried: 1,
+		},
+		{
+			name:   "invalid events",
+			status: http.StatusOK,
+			responses: []honeycombResponse{
+				{Status: http.StatusAccepted},
+				{Status: http.StatusBadRequest, Error: "invalid event"},
+			},
+			permanent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(tt.status)
+				if tt.responses != nil {
+					json.NewEncoder(rw).Encode(tt.responses)
+				}
+			}))
+			defer server.Close()
+
+			cfg := baseConfig()
+			cfg.APIURL = server.URL
+			exporter, err := newHoneycombTraceExporter(cfg, zap.NewNop())
+			require.NoError(t, err)
+			defer exporter.Shutdown(context.Background())
+
+			td := pdata.NewTraces()
+			td.ResourceSpans().Resize(1)
+			ils := td.ResourceSpans().At(0).InstrumentationLibrarySpans()
+			ils.Resize(1)
+			ils.At(0).Spans().Resize(2)
+
+			dropped, err := exporter.pushTraceData(context.Background(), td)
+			require.Error(t, err)
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(err))
+			if tt.permanent {
+				return
+			}
+			assert.Equal(t, tt.retried, dropped)
+			var partialErr consumererror.PartialError
+			require.True(t, errors.As(err, &partialErr))
+			assert.Equal(t, tt.retried, partialErr.GetTraces().SpanCount())
+		})
+	}
+}
+
 func TestEmptyNode(t *testing.T) {
 	td := consumerdata.TraceData{
 		Node: nil,
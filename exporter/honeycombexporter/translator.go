@@ -18,14 +18,22 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 // spanAttributesToMap converts an opencensus proto Span_Attributes object into a map
-// of strings to generic types usable for sending events to honeycomb.
+// of strings to generic types usable for sending events to honeycomb. The map
+// attributes are flattened, their values being added with the keys joined by
+// dots, and the arrays are added as JSON strings.
 func spanAttributesToMap(spanAttrs pdata.AttributeMap) map[string]interface{} {
 	var attrs = make(map[string]interface{}, spanAttrs.Len())
+	addAttributes(attrs, "", spanAttrs)
+	return attrs
+}
 
+func addAttributes(attrs map[string]interface{}, prefix string, spanAttrs pdata.AttributeMap) {
 	spanAttrs.ForEach(func(key string, value pdata.AttributeValue) {
+		key = prefix + key
 		switch value.Type() {
 		case pdata.AttributeValueSTRING:
 			attrs[key] = value.StringVal()
@@ -35,10 +43,12 @@ func spanAttributesToMap(spanAttrs pdata.AttributeMap) map[string]interface{} {
 			attrs[key] = value.IntVal()
 		case pdata.AttributeValueDOUBLE:
 			attrs[key] = value.DoubleVal()
+		case pdata.AttributeValueMAP:
+			addAttributes(attrs, key+".", value.MapVal())
+		case pdata.AttributeValueARRAY:
+			attrs[key] = tracetranslator.AttributeValueToString(value, false)
 		}
 	})
-
-	return attrs
 }
 
 // timestampToTime converts a protobuf timestamp into a time.Time.
@@ -61,6 +61,25 @@ func TestSpanAttributesToMap(t *testing.T) {
 	}
 }
 
+func TestSpanAttributesToMapFlattens(t *testing.T) {
+	nested := pdata.NewAttributeValueMap()
+	nested.MapVal().InsertString("name", "value")
+	nested.MapVal().InsertInt("count", 2)
+	array := pdata.NewAttributeValueArray()
+	array.ArrayVal().Append(pdata.NewAttributeValueString("a"))
+	array.ArrayVal().Append(pdata.NewAttributeValueString("b"))
+	attrs := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"map":   nested,
+		"array": array,
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"map.name":  "value",
+		"map.count": int64(2),
+		"array":     `["a","b"]`,
+	}, spanAttributesToMap(attrs))
+}
+
 func TestTimestampToTime(t *testing.T) {
 	var t1 time.Time
 	emptyTime := timestampToTime(pdata.TimestampUnixNano(0))
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// Config defines configuration for InfluxDB exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	confighttp.HTTPClientSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings  `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings  `mapstructure:"retry_on_failure"`
+
+	// Org is the name of the organization owning the bucket.
+	Org string `mapstructure:"org"`
+
+	// Bucket is the name of the bucket the points are written to.
+	Bucket string `mapstructure:"bucket"`
+
+	// Token is the API token sent in the Authorization header. No token is sent when empty.
+	Token string `mapstructure:"token"`
+
+	// ResourceTags are the resource attributes written as tags of the points.
+	ResourceTags []string `mapstructure:"resource_tags"`
+
+	// MaxTags is the maximum number of tags coming from attributes written per point, the other attribute tags
+	// being dropped. Zero means no limit.
+	MaxTags int `mapstructure:"max_tags"`
+
+	// HistogramSchema is how the histograms and summaries are written, either "fields", with one point per data
+	// point holding the buckets or quantiles as fields, or "series", with one point per bucket or quantile.
+	HistogramSchema string `mapstructure:"histogram_schema"`
+
+	// MaxRequestBodySize is the maximum size in bytes of the body of the write requests, the points of a batch
+	// being split into several requests when needed.
+	MaxRequestBodySize int `mapstructure:"max_request_body_size"`
+}
+
+const (
+	histogramSchemaFields = "fields"
+	histogramSchemaSeries = "series"
+
+	writePath = "/api/v2/write"
+)
+
+func (c *Config) validate() error {
+	if _, err := url.Parse(c.Endpoint); c.Endpoint == "" || err != nil {
+		return errors.New("endpoint must be a valid URL")
+	}
+
+	if c.Org == "" {
+		return errors.New("org must be specified")
+	}
+
+	if c.Bucket == "" {
+		return errors.New("bucket must be specified")
+	}
+
+	if c.MaxTags < 0 {
+		return fmt.Errorf("max_tags can't be negative: %d", c.MaxTags)
+	}
+
+	switch c.HistogramSchema {
+	case histogramSchemaFields, histogramSchemaSeries:
+	default:
+		return fmt.Errorf("unsupported histogram_schema %q, must be either %q or %q", c.HistogramSchema, histogramSchemaFields, histogramSchemaSeries)
+	}
+
+	if c.MaxRequestBodySize <= 0 {
+		return fmt.Errorf("max_request_body_size must be positive: %d", c.MaxRequestBodySize)
+	}
+
+	return nil
+}
+
+// writeURL returns the URL of the write API, with the org, bucket and nanosecond precision of the points.
+func (c *Config) writeURL() (string, error) {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + writePath
+	q := u.Query()
+	q.Set("org", c.Org)
+	q.Set("bucket", c.Bucket)
+	q.Set("precision", "ns")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[configmodels.Type(typeStr)] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, 2, len(cfg.Exporters))
+
+	defaultCfg := factory.CreateDefaultConfig().(*Config)
+	defaultCfg.Endpoint = "http://influxdb:8086"
+	defaultCfg.Org = "my-org"
+	defaultCfg.Bucket = "my-bucket"
+	assert.Equal(t, defaultCfg, cfg.Exporters["influxdb"])
+
+	actualCfg := cfg.Exporters["influxdb/allsettings"].(*Config)
+	expectedCfg := &Config{
+		ExporterSettings: configmodels.ExporterSettings{TypeVal: typeStr, NameVal: "influxdb/allsettings"},
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: "https://influxdb:8086",
+			Timeout:  10 * time.Second,
+			Headers:  map[string]string{},
+		},
+		RetrySettings: exporterhelper.RetrySettings{
+			Enabled:         true,
+			InitialInterval: 10 * time.Second,
+			MaxInterval:     1 * time.Minute,
+			MaxElapsedTime:  10 * time.Minute,
+		},
+		QueueSettings: exporterhelper.QueueSettings{
+			Enabled:      true,
+			NumConsumers: 2,
+			QueueSize:    10,
+		},
+		Org:                "my-org",
+		Bucket:             "my-bucket",
+		Token:              "my-token",
+		ResourceTags:       []string{conventions.AttributeServiceName, conventions.AttributeHostName},
+		MaxTags:            10,
+		HistogramSchema:    histogramSchemaSeries,
+		MaxRequestBodySize: 65536,
+	}
+	assert.Equal(t, expectedCfg, actualCfg)
+	assert.NoError(t, actualCfg.validate())
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "missing endpoint",
+			modify: func(cfg *Config) { cfg.Endpoint = "" },
+			err:    "endpoint must be a valid URL",
+		},
+		{
+			name:   "invalid endpoint",
+			modify: func(cfg *Config) { cfg.Endpoint = "http://influxdb:8086/%zz" },
+			err:    "endpoint must be a valid URL",
+		},
+		{
+			name:   "missing org",
+			modify: func(cfg *Config) { cfg.Org = "" },
+			err:    "org must be specified",
+		},
+		{
+			name:   "missing bucket",
+			modify: func(cfg *Config) { cfg.Bucket = "" },
+			err:    "bucket must be specified",
+		},
+		{
+			name:   "negative max tags",
+			modify: func(cfg *Config) { cfg.MaxTags = -1 },
+			err:    "max_tags can't be negative: -1",
+		},
+		{
+			name:   "invalid histogram schema",
+			modify: func(cfg *Config) { cfg.HistogramSchema = "buckets" },
+			err:    `unsupported histogram_schema "buckets", must be either "fields" or "series"`,
+		},
+		{
+			name:   "invalid max request body size",
+			modify: func(cfg *Config) { cfg.MaxRequestBodySize = 0 },
+			err:    "max_request_body_size must be positive: 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "http://influxdb:8086"
+			cfg.Org = "my-org"
+			cfg.Bucket = "my-bucket"
+			tt.modify(cfg)
+
+			assert.EqualError(t, cfg.validate(), tt.err)
+		})
+	}
+}
+
+func TestConfigWriteURL(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "https://influxdb:8086/influx/"
+	cfg.Org = "my org"
+	cfg.Bucket = "my-bucket"
+
+	writeURL, err := cfg.writeURL()
+	require.NoError(t, err)
+	assert.Equal(t, "https://influxdb:8086/influx/api/v2/write?bucket=my-bucket&org=my+org&precision=ns", writeURL)
+}
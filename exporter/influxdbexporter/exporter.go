@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+// maxErrorBodySize is the size of the response body of InfluxDB included in the errors.
+const maxErrorBodySize = 1024
+
+type influxExporter struct {
+	config   *Config
+	client   *http.Client
+	logger   *zap.Logger
+	writeURL string
+	encoder  lineEncoder
+}
+
+func newExporter(config *Config, logger *zap.Logger) (*influxExporter, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	writeURL, err := config.writeURL()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := config.HTTPClientSettings.ToClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &influxExporter{
+		config:   config,
+		client:   client,
+		logger:   logger,
+		writeURL: writeURL,
+		encoder:  lineEncoder{maxTags: config.MaxTags},
+	}, nil
+}
+
+func (e *influxExporter) pushMetricsData(ctx context.Context, md pdata.Metrics) (droppedTimeSeries int, err error) {
+	if err := e.writePoints(ctx, e.metricsToPoints(md)); err != nil {
+		_, numPoints := md.MetricAndDataPointCount()
+		return numPoints, err
+	}
+	return 0, nil
+}
+
+func (e *influxExporter) pushTraceData(ctx context.Context, td pdata.Traces) (droppedSpans int, err error) {
+	if err := e.writePoints(ctx, e.tracesToPoints(td)); err != nil {
+		return td.SpanCount(), err
+	}
+	return 0, nil
+}
+
+func (e *influxExporter) pushLogData(ctx context.Context, ld pdata.Logs) (numDroppedLogs int, err error) {
+	if err := e.writePoints(ctx, e.logsToPoints(ld)); err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return 0, nil
+}
+
+// writePoints encodes the points and writes them in requests of at most max_request_body_size bytes. As InfluxDB
+// overwrites the points with the same series and timestamp, the whole batch is retried when one of the requests
+// fails.
+func (e *influxExporter) writePoints(ctx context.Context, points []*point) error {
+	var (
+		body        strings.Builder
+		errs        []error
+		droppedTags int
+	)
+
+	for _, p := range points {
+		line, dropped := e.encoder.encode(p)
+		droppedTags += dropped
+		if line == "" {
+			continue
+		}
+
+		if body.Len() > 0 && body.Len()+len(line)+1 > e.config.MaxRequestBodySize {
+			if err := e.write(ctx, body.String()); err != nil {
+				errs = append(errs, err)
+			}
+			body.Reset()
+		}
+
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+
+	if body.Len() > 0 {
+		if err := e.write(ctx, body.String()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if droppedTags > 0 {
+		e.logger.Debug("Dropped the tags exceeding max_tags", zap.Int("dropped_tags", droppedTags))
+	}
+
+	return componenterror.CombineErrors(errs)
+}
+
+// write sends the lines to the write API of InfluxDB.
+func (e *influxExporter) write(ctx context.Context, lines string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.writeURL, strings.NewReader(lines))
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if e.config.Token != "" {
+		req.Header.Set("Authorization", "Token "+e.config.Token)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("HTTP %d %q: %s", resp.StatusCode, http.StatusText(resp.StatusCode), bytes.TrimSpace(msg))
+	// InfluxDB rejects the invalid points, e.g. with a field type conflict, and the unauthorized writes with a 4XX
+	// code: retrying them wouldn't succeed.
+	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+		return consumererror.Permanent(err)
+	}
+	return err
+}
+
+// resourceTags returns the resource attributes configured in resource_tags.
+func (e *influxExporter) resourceTags(resource pdata.Resource) map[string]string {
+	tags := make(map[string]string, len(e.config.ResourceTags))
+	attributes := resource.Attributes()
+	for _, k := range e.config.ResourceTags {
+		if v, ok := attributes.Get(k); ok {
+			tags[k] = tracetranslator.AttributeValueToString(v, false)
+		}
+	}
+	return tags
+}
+
+func (e *influxExporter) stop(context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// influxCapture is a fake InfluxDB recording the write requests.
+type influxCapture struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (c *influxCapture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, r)
+	c.bodies = append(c.bodies, string(body))
+
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+		w.Write([]byte(`{"code":"invalid","message":"failure writing points"}`))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newCaptureExporter(t *testing.T, status int, modify func(cfg *Config)) (*influxExporter, *influxCapture) {
+	capture := &influxCapture{status: status}
+	srv := httptest.NewServer(capture)
+	t.Cleanup(srv.Close)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = srv.URL
+	cfg.Org = "my-org"
+	cfg.Bucket = "my-bucket"
+	cfg.Token = "my-token"
+	if modify != nil {
+		modify(cfg)
+	}
+
+	exp, err := newExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	return exp, capture
+}
+
+func testGauges(n int) pdata.Metrics {
+	md, ms := testMetrics()
+	ms.Resize(1)
+	ms.At(0).SetName("gauge")
+	ms.At(0).SetDataType(pdata.MetricDataTypeDoubleGauge)
+	dps := ms.At(0).DoubleGauge().DataPoints()
+	dps.Resize(n)
+	for i := 0; i < n; i++ {
+		dps.At(i).SetValue(float64(i))
+		dps.At(i).SetTimestamp(1000)
+	}
+	return md
+}
+
+func TestPushMetricsData(t *testing.T) {
+	exp, capture := newCaptureExporter(t, 0, func(cfg *Config) {
+		cfg.ResourceTags = []string{"service.name"}
+	})
+
+	dropped, err := exp.pushMetricsData(context.Background(), testGauges(2))
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	require.Len(t, capture.requests, 1)
+	req := capture.requests[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/api/v2/write", req.URL.Path)
+	assert.Equal(t, "my-org", req.URL.Query().Get("org"))
+	assert.Equal(t, "my-bucket", req.URL.Query().Get("bucket"))
+	assert.Equal(t, "ns", req.URL.Query().Get("precision"))
+	assert.Equal(t, "Token my-token", req.Header.Get("Authorization"))
+	assert.Equal(t, "text/plain; charset=utf-8", req.Header.Get("Content-Type"))
+	assert.Equal(t, "gauge,service.name=checkout gauge=0 1000\ngauge,service.name=checkout gauge=1 1000\n", capture.bodies[0])
+}
+
+func TestPushSplitsRequests(t *testing.T) {
+	// Each line is 19 bytes long with its newline, so that 4 lines fit in a request.
+	exp, capture := newCaptureExporter(t, 0, func(cfg *Config) {
+		cfg.MaxRequestBodySize = 80
+	})
+
+	_, err := exp.pushMetricsData(context.Background(), testGauges(7))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"gauge gauge=0 1000\ngauge gauge=1 1000\ngauge gauge=2 1000\ngauge gauge=3 1000\n",
+		"gauge gauge=4 1000\ngauge gauge=5 1000\ngauge gauge=6 1000\n",
+	}, capture.bodies)
+}
+
+func TestPushPermanentError(t *testing.T) {
+	exp, _ := newCaptureExporter(t, http.StatusBadRequest, nil)
+
+	dropped, err := exp.pushMetricsData(context.Background(), testGauges(2))
+	require.Error(t, err)
+	assert.Equal(t, 2, dropped)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Contains(t, err.Error(), `HTTP 400 "Bad Request": {"code":"invalid","message":"failure writing points"}`)
+}
+
+func TestPushRetryableErrors(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		exp, _ := newCaptureExporter(t, status, nil)
+
+		_, err := exp.pushTraceData(context.Background(), testTraces())
+		require.Error(t, err)
+		assert.False(t, consumererror.IsPermanent(err), "status %d must be retried", status)
+	}
+}
+
+func TestPushLogData(t *testing.T) {
+	exp, capture := newCaptureExporter(t, 0, nil)
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Body().SetStringVal("hello")
+
+	_, err := exp.pushLogData(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"logs body=\"hello\"\n"}, capture.bodies)
+}
+
+func TestPushWithoutPoints(t *testing.T) {
+	exp, capture := newCaptureExporter(t, 0, nil)
+
+	_, err := exp.pushMetricsData(context.Background(), pdata.NewMetrics())
+	require.NoError(t, err)
+	assert.Empty(t, capture.requests)
+}
+
+func testTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().Resize(1)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1}))
+	span.SetName("span")
+	return td
+}
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	ocfg, ok := factory.CreateDefaultConfig().(*Config)
+	assert.True(t, ok)
+	assert.Equal(t, "", ocfg.HTTPClientSettings.Endpoint)
+	assert.Equal(t, 5*time.Second, ocfg.HTTPClientSettings.Timeout, "default timeout is 5 seconds")
+	assert.Equal(t, true, ocfg.RetrySettings.Enabled, "default retry is enabled")
+	assert.Equal(t, true, ocfg.QueueSettings.Enabled, "default sending queue is enabled")
+	assert.Equal(t, 32, ocfg.MaxTags)
+	assert.Equal(t, histogramSchemaFields, ocfg.HistogramSchema)
+	assert.Equal(t, 1024*1024, ocfg.MaxRequestBodySize)
+}
+
+func TestCreateExporters(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = "http://" + testutil.GetAvailableLocalAddress(t)
+	cfg.Org = "my-org"
+	cfg.Bucket = "my-bucket"
+
+	creationParams := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	me, err := factory.CreateMetricsExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, me)
+
+	te, err := factory.CreateTracesExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, te)
+
+	le, err := factory.CreateLogsExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, le)
+}
+
+func TestCreateExporterInvalidConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = "http://" + testutil.GetAvailableLocalAddress(t)
+
+	creationParams := component.ExporterCreateParams{Logger: zap.NewNop()}
+	_, err := factory.CreateMetricsExporter(context.Background(), creationParams, cfg)
+	assert.EqualError(t, err, "org must be specified")
+}
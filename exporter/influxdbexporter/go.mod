@@ -0,0 +1,9 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/influxdbexporter
+
+go 1.14
+
+require (
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+)
@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// point is a point written to InfluxDB, see
+// https://docs.influxdata.com/influxdb/v2.0/reference/syntax/line-protocol/.
+type point struct {
+	measurement string
+	// tags are the tags set by the exporter itself, e.g. the "le" tag of the histogram buckets, which are always
+	// written.
+	tags map[string]string
+	// attributeTags are the tags coming from attributes, limited to max_tags per point.
+	attributeTags map[string]string
+	// fields must hold at least one field of type float64, int64, uint64, bool or string.
+	fields    map[string]interface{}
+	timestamp pdata.TimestampUnixNano
+}
+
+func newPoint(measurement string, attributeTags map[string]string, timestamp pdata.TimestampUnixNano) *point {
+	return &point{
+		measurement:   measurement,
+		tags:          map[string]string{},
+		attributeTags: attributeTags,
+		fields:        map[string]interface{}{},
+		timestamp:     timestamp,
+	}
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	keyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	stringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// escapeMeasurement escapes the characters delimiting the measurement: commas and spaces. The line delimiters are
+// escaped as well, as they can't be part of the measurement or the tags.
+func escapeMeasurement(s string) string {
+	return escapeTrailingBackslash(measurementEscaper.Replace(s))
+}
+
+// escapeKey escapes the tag keys, tag values and field keys, which are delimited by commas, equal signs and spaces.
+func escapeKey(s string) string {
+	return escapeTrailingBackslash(keyEscaper.Replace(s))
+}
+
+// escapeTrailingBackslash doubles a trailing backslash, which would otherwise escape the following delimiter.
+func escapeTrailingBackslash(s string) string {
+	if strings.HasSuffix(s, `\`) && !strings.HasSuffix(s, `\\`) {
+		return s + `\`
+	}
+	return s
+}
+
+// escapeString quotes a string field value, escaping its double quotes and backslashes.
+func escapeString(s string) string {
+	return `"` + stringEscaper.Replace(s) + `"`
+}
+
+// lineEncoder encodes the points to line protocol.
+type lineEncoder struct {
+	maxTags int
+}
+
+// encode returns the line of the point, with the number of attribute tags dropped to respect max_tags. The line is
+// empty when the point has no valid field, as InfluxDB requires at least one field per point.
+func (e lineEncoder) encode(p *point) (line string, droppedTags int) {
+	fieldKeys := make([]string, 0, len(p.fields))
+	for k, v := range p.fields {
+		if f, ok := v.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			// InfluxDB doesn't support NaN and infinite values.
+			continue
+		}
+		fieldKeys = append(fieldKeys, k)
+	}
+	if len(fieldKeys) == 0 {
+		return "", 0
+	}
+	sort.Strings(fieldKeys)
+
+	tags, droppedTags := e.tags(p)
+
+	var b strings.Builder
+	b.WriteString(escapeMeasurement(p.measurement))
+
+	for _, k := range sortedKeys(tags) {
+		b.WriteByte(',')
+		b.WriteString(escapeKey(k))
+		b.WriteByte('=')
+		b.WriteString(escapeKey(tags[k]))
+	}
+
+	for i, k := range fieldKeys {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(escapeKey(k))
+		b.WriteByte('=')
+		b.WriteString(fieldValue(p.fields[k]))
+	}
+
+	if p.timestamp != 0 {
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatUint(uint64(p.timestamp), 10))
+	}
+
+	return b.String(), droppedTags
+}
+
+// tags returns the tags of the point, keeping the attribute tags with the lowest keys when there are more than
+// max_tags of them. The tags set by the exporter take precedence over the attribute tags with the same key, and the
+// tags with an empty key or value are dropped, as InfluxDB doesn't support them.
+func (e lineEncoder) tags(p *point) (tags map[string]string, droppedTags int) {
+	tags = make(map[string]string, len(p.tags)+len(p.attributeTags))
+
+	attributeKeys := make([]string, 0, len(p.attributeTags))
+	for k, v := range p.attributeTags {
+		if _, ok := p.tags[k]; ok || k == "" || v == "" {
+			continue
+		}
+		attributeKeys = append(attributeKeys, k)
+	}
+	sort.Strings(attributeKeys)
+
+	if e.maxTags > 0 && len(attributeKeys) > e.maxTags {
+		droppedTags = len(attributeKeys) - e.maxTags
+		attributeKeys = attributeKeys[:e.maxTags]
+	}
+	for _, k := range attributeKeys {
+		tags[k] = p.attributeTags[k]
+	}
+
+	for k, v := range p.tags {
+		if k != "" && v != "" {
+			tags[k] = v
+		}
+	}
+
+	return tags, droppedTags
+}
+
+// fieldValue returns the line protocol representation of a field value.
+func fieldValue(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10) + "i"
+	case uint64:
+		return strconv.FormatUint(v, 10) + "u"
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return escapeString(v)
+	default:
+		return escapeString("")
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// attributeTags returns the attributes as tag values.
+func attributeTags(attributes pdata.AttributeMap) map[string]string {
+	tags := make(map[string]string, attributes.Len())
+	attributes.ForEach(func(k string, v pdata.AttributeValue) {
+		tags[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+	return tags
+}
+
+// attributeFieldValue returns the attribute value as a field value, the maps and arrays being written as JSON.
+func attributeFieldValue(v pdata.AttributeValue) interface{} {
+	switch v.Type() {
+	case pdata.AttributeValueINT:
+		return v.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return v.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return v.BoolVal()
+	default:
+		return tracetranslator.AttributeValueToString(v, false)
+	}
+}
+
+// addAttributeFields adds the attributes as fields, the fields already set by the exporter taking precedence.
+func addAttributeFields(p *point, attributes pdata.AttributeMap) {
+	attributes.ForEach(func(k string, v pdata.AttributeValue) {
+		if _, ok := p.fields[k]; ok || k == "" {
+			return
+		}
+		p.fields[k] = attributeFieldValue(v)
+	})
+}
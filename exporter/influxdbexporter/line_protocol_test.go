@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestEscapeMeasurement(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{in: "cpu.usage", expected: "cpu.usage"},
+		{in: "cpu usage,total", expected: `cpu\ usage\,total`},
+		{in: "cpu=usage", expected: "cpu=usage"},
+		{in: "multi\nline", expected: `multi\nline`},
+		{in: `trailing\`, expected: `trailing\\`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, escapeMeasurement(tt.in), tt.in)
+	}
+}
+
+func TestEscapeKey(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{in: "host.name", expected: "host.name"},
+		{in: "key with spaces", expected: `key\ with\ spaces`},
+		{in: "a,b=c", expected: `a\,b\=c`},
+		{in: "tab\tand\r\nnewline", expected: `tab\tand\r\nnewline`},
+		{in: `C:\`, expected: `C:\\`},
+		{in: `C:\\`, expected: `C:\\`},
+		{in: `"quoted"`, expected: `"quoted"`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, escapeKey(tt.in), tt.in)
+	}
+}
+
+func TestEscapeString(t *testing.T) {
+	assert.Equal(t, `"plain"`, escapeString("plain"))
+	assert.Equal(t, `"say \"hi\", a=b"`, escapeString(`say "hi", a=b`))
+	assert.Equal(t, `"C:\\Windows\\"`, escapeString(`C:\Windows\`))
+	assert.Equal(t, "\"multi\nline\"", escapeString("multi\nline"))
+}
+
+func TestEncode(t *testing.T) {
+	p := newPoint("my measurement", map[string]string{"host name": "server,1", "region": "us=east"}, 1600000000000000000)
+	p.tags["le"] = "0.5"
+	p.fields["float"] = 1.5
+	p.fields["int"] = int64(-3)
+	p.fields["uint"] = uint64(3)
+	p.fields["bool"] = true
+	p.fields["string"] = `say "hi"`
+	p.fields["field key"] = 1e21
+
+	line, dropped := lineEncoder{}.encode(p)
+	assert.Equal(t, `my\ measurement,host\ name=server\,1,le=0.5,region=us\=east bool=true,field\ key=1e+21,float=1.5,int=-3i,string="say \"hi\"",uint=3u 1600000000000000000`, line)
+	assert.Equal(t, 0, dropped)
+}
+
+func TestEncodeWithoutTagsAndTimestamp(t *testing.T) {
+	p := newPoint("cpu", map[string]string{}, 0)
+	p.fields["gauge"] = 0.5
+
+	line, _ := lineEncoder{}.encode(p)
+	assert.Equal(t, "cpu gauge=0.5", line)
+}
+
+func TestEncodeSkipsInvalidValues(t *testing.T) {
+	p := newPoint("cpu", map[string]string{"empty": "", "": "empty key", "host": "a"}, 1)
+	p.fields["nan"] = math.NaN()
+	p.fields["inf"] = math.Inf(-1)
+	p.fields["gauge"] = 0.5
+
+	line, _ := lineEncoder{}.encode(p)
+	assert.Equal(t, "cpu,host=a gauge=0.5 1", line)
+
+	p = newPoint("cpu", map[string]string{"host": "a"}, 1)
+	p.fields["nan"] = math.NaN()
+
+	line, _ = lineEncoder{}.encode(p)
+	assert.Equal(t, "", line, "a point without valid field is skipped")
+}
+
+func TestEncodeMaxTags(t *testing.T) {
+	p := newPoint("http_requests", map[string]string{
+		"d":    "4",
+		"b":    "2",
+		"a":    "1",
+		"c":    "3",
+		"span": "overridden",
+	}, 1)
+	p.tags["span"] = "kept"
+	p.tags["z"] = "26"
+	p.fields["counter"] = 1.0
+
+	line, dropped := lineEncoder{maxTags: 2}.encode(p)
+	assert.Equal(t, "http_requests,a=1,b=2,span=kept,z=26 counter=1 1", line)
+	assert.Equal(t, 2, dropped)
+
+	line, dropped = lineEncoder{maxTags: 0}.encode(p)
+	assert.Equal(t, "http_requests,a=1,b=2,c=3,d=4,span=kept,z=26 counter=1 1", line)
+	assert.Equal(t, 0, dropped)
+}
+
+func TestAddAttributeFields(t *testing.T) {
+	p := newPoint("spans", map[string]string{}, 1)
+	p.fields["name"] = "GET /"
+
+	attributes := pdata.NewAttributeMap()
+	attributes.InsertString("name", "ignored")
+	attributes.InsertString("http.method", "GET")
+	attributes.InsertInt("http.status_code", 200)
+	attributes.InsertDouble("ratio", 0.5)
+	attributes.InsertBool("error", false)
+	array := pdata.NewAttributeValueArray()
+	array.ArrayVal().Append(pdata.NewAttributeValueString("a"))
+	attributes.Insert("array", array)
+	addAttributeFields(p, attributes)
+
+	assert.Equal(t, map[string]interface{}{
+		"name":             "GET /",
+		"http.method":      "GET",
+		"http.status_code": int64(200),
+		"ratio":            0.5,
+		"error":            false,
+		"array":            `["a"]`,
+	}, p.fields)
+}
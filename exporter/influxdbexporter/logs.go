@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	measurementLogs = "logs"
+
+	fieldBody           = "body"
+	fieldSeverityNumber = "severity_number"
+	fieldSeverityText   = "severity_text"
+	fieldTraceID        = "trace_id"
+	fieldSpanID         = "span_id"
+)
+
+// logsToPoints converts the log records to points of the logs measurement, with their body, severity, trace
+// context and attributes as fields. The records without timestamp are timestamped by InfluxDB.
+func (e *influxExporter) logsToPoints(ld pdata.Logs) []*point {
+	points := make([]*point, 0, ld.LogRecordCount())
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceTags := e.resourceTags(rl.Resource())
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				points = append(points, logToPoint(logs.At(k), resourceTags))
+			}
+		}
+	}
+
+	return points
+}
+
+func logToPoint(lr pdata.LogRecord, resourceTags map[string]string) *point {
+	p := newPoint(measurementLogs, resourceTags, lr.Timestamp())
+
+	p.fields[fieldBody] = tracetranslator.AttributeValueToString(lr.Body(), false)
+	if lr.SeverityNumber() != pdata.SeverityNumberUNDEFINED {
+		p.fields[fieldSeverityNumber] = int64(lr.SeverityNumber())
+	}
+	if lr.SeverityText() != "" {
+		p.fields[fieldSeverityText] = lr.SeverityText()
+	}
+	if lr.Name() != "" {
+		p.fields[fieldName] = lr.Name()
+	}
+	if !lr.TraceID().IsEmpty() {
+		p.fields[fieldTraceID] = lr.TraceID().HexString()
+	}
+	if !lr.SpanID().IsEmpty() {
+		p.fields[fieldSpanID] = lr.SpanID().HexString()
+	}
+
+	addAttributeFields(p, lr.Attributes())
+
+	return p
+}
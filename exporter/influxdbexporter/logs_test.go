@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestLogsToPoints(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+
+	lr := logs.At(0)
+	lr.SetTimestamp(1000)
+	lr.Body().SetStringVal("payment \"failed\"\ntraceback")
+	lr.SetSeverityNumber(pdata.SeverityNumberERROR)
+	lr.SetSeverityText("ERROR")
+	lr.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	lr.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	lr.Attributes().InsertString("user id", "42")
+
+	// Without timestamp, the point is timestamped by InfluxDB.
+	logs.At(1).Body().SetStringVal("started")
+
+	lines := encodePoints(newTestExporter(histogramSchemaFields).logsToPoints(ld))
+	assert.Equal(t, []string{
+		"logs,service.name=checkout body=\"payment \\\"failed\\\"\ntraceback\",severity_number=17i,severity_text=\"ERROR\"," +
+			`span_id="0102030405060708",trace_id="0102030405060708090a0b0c0d0e0f10",user\ id="42" 1000`,
+		`logs,service.name=checkout body="started"`,
+	}, lines)
+}
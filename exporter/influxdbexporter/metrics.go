@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"math"
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	fieldGauge   = "gauge"
+	fieldCounter = "counter"
+	fieldCount   = "count"
+	fieldSum     = "sum"
+
+	tagLe       = "le"
+	tagQuantile = "quantile"
+
+	suffixBucket = "_bucket"
+	suffixCount  = "_count"
+	suffixSum    = "_sum"
+)
+
+// metricsToPoints converts the data points of the metrics to points, with the metric name as measurement and the
+// labels of the data points as tags.
+func (e *influxExporter) metricsToPoints(md pdata.Metrics) []*point {
+	var points []*point
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceTags := e.resourceTags(rm.Resource())
+
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ms := ilms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				points = append(points, e.metricToPoints(ms.At(k), resourceTags)...)
+			}
+		}
+	}
+
+	return points
+}
+
+func (e *influxExporter) metricToPoints(m pdata.Metric, resourceTags map[string]string) []*point {
+	var points []*point
+	name := m.Name()
+
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		dps := m.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			points = append(points, valuePoint(name, fieldGauge, float64(dp.Value()), labelTags(resourceTags, dp.LabelsMap()), dp.Timestamp()))
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		dps := m.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			points = append(points, valuePoint(name, fieldGauge, dp.Value(), labelTags(resourceTags, dp.LabelsMap()), dp.Timestamp()))
+		}
+	case pdata.MetricDataTypeIntSum:
+		field := sumField(m.IntSum().IsMonotonic())
+		dps := m.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			points = append(points, valuePoint(name, field, float64(dp.Value()), labelTags(resourceTags, dp.LabelsMap()), dp.Timestamp()))
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		field := sumField(m.DoubleSum().IsMonotonic())
+		dps := m.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			points = append(points, valuePoint(name, field, dp.Value(), labelTags(resourceTags, dp.LabelsMap()), dp.Timestamp()))
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		dps := m.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			h := histogram{
+				tags:           labelTags(resourceTags, dp.LabelsMap()),
+				timestamp:      dp.Timestamp(),
+				count:          dp.Count(),
+				sum:            float64(dp.Sum()),
+				bucketCounts:   dp.BucketCounts(),
+				explicitBounds: dp.ExplicitBounds(),
+			}
+			points = append(points, e.histogramToPoints(name, h)...)
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		dps := m.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			h := histogram{
+				tags:           labelTags(resourceTags, dp.LabelsMap()),
+				timestamp:      dp.Timestamp(),
+				count:          dp.Count(),
+				sum:            dp.Sum(),
+				bucketCounts:   dp.BucketCounts(),
+				explicitBounds: dp.ExplicitBounds(),
+			}
+			points = append(points, e.histogramToPoints(name, h)...)
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		dps := m.DoubleSummary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			points = append(points, e.summaryToPoints(name, dps.At(i), resourceTags)...)
+		}
+	}
+
+	return points
+}
+
+// histogram holds the values of the int and double histogram data points.
+type histogram struct {
+	tags           map[string]string
+	timestamp      pdata.TimestampUnixNano
+	count          uint64
+	sum            float64
+	bucketCounts   []uint64
+	explicitBounds []float64
+}
+
+// histogramToPoints converts a histogram data point, with its cumulative bucket counts. With the "fields" schema,
+// it's a single point with the count, sum and bucket counts as fields, the upper bounds of the buckets being their
+// field keys. With the "series" schema, the count, sum and each bucket are separate points, the upper bounds of the
+// buckets being their "le" tag.
+func (e *influxExporter) histogramToPoints(name string, h histogram) []*point {
+	// The bucket counts don't match the bounds, so we only write the count and sum.
+	if len(h.bucketCounts) != len(h.explicitBounds)+1 {
+		h.bucketCounts = nil
+		h.explicitBounds = nil
+	}
+
+	bounds := make([]string, 0, len(h.bucketCounts))
+	for _, bound := range h.explicitBounds {
+		bounds = append(bounds, formatBound(bound))
+	}
+	bounds = append(bounds, formatBound(math.Inf(1)))
+
+	if e.config.HistogramSchema == histogramSchemaFields {
+		p := newPoint(name, h.tags, h.timestamp)
+		p.fields[fieldCount] = float64(h.count)
+		p.fields[fieldSum] = h.sum
+		var cumulative uint64
+		for i, count := range h.bucketCounts {
+			cumulative += count
+			p.fields[bounds[i]] = float64(cumulative)
+		}
+		return []*point{p}
+	}
+
+	points := []*point{
+		valuePoint(name+suffixCount, fieldCounter, float64(h.count), h.tags, h.timestamp),
+		valuePoint(name+suffixSum, fieldCounter, h.sum, h.tags, h.timestamp),
+	}
+	var cumulative uint64
+	for i, count := range h.bucketCounts {
+		cumulative += count
+		p := valuePoint(name+suffixBucket, fieldCounter, float64(cumulative), h.tags, h.timestamp)
+		p.tags[tagLe] = bounds[i]
+		points = append(points, p)
+	}
+	return points
+}
+
+// summaryToPoints converts a summary data point like histogramToPoints, the quantiles being the field keys with the
+// "fields" schema and the "quantile" tag with the "series" schema.
+func (e *influxExporter) summaryToPoints(name string, dp pdata.DoubleSummaryDataPoint, resourceTags map[string]string) []*point {
+	tags := labelTags(resourceTags, dp.LabelsMap())
+	qs := dp.QuantileValues()
+
+	if e.config.HistogramSchema == histogramSchemaFields {
+		p := newPoint(name, tags, dp.Timestamp())
+		p.fields[fieldCount] = float64(dp.Count())
+		p.fields[fieldSum] = dp.Sum()
+		for i := 0; i < qs.Len(); i++ {
+			p.fields[formatBound(qs.At(i).Quantile())] = qs.At(i).Value()
+		}
+		return []*point{p}
+	}
+
+	points := []*point{
+		valuePoint(name+suffixCount, fieldCounter, float64(dp.Count()), tags, dp.Timestamp()),
+		valuePoint(name+suffixSum, fieldCounter, dp.Sum(), tags, dp.Timestamp()),
+	}
+	for i := 0; i < qs.Len(); i++ {
+		p := valuePoint(name, fieldGauge, qs.At(i).Value(), tags, dp.Timestamp())
+		p.tags[tagQuantile] = formatBound(qs.At(i).Quantile())
+		points = append(points, p)
+	}
+	return points
+}
+
+func valuePoint(measurement string, field string, value float64, tags map[string]string, timestamp pdata.TimestampUnixNano) *point {
+	p := newPoint(measurement, tags, timestamp)
+	p.fields[field] = value
+	return p
+}
+
+// sumField returns the field of the sums, counter for the monotonic ones and gauge for the others.
+func sumField(monotonic bool) string {
+	if monotonic {
+		return fieldCounter
+	}
+	return fieldGauge
+}
+
+// formatBound formats the bounds and quantiles like Prometheus does, e.g. "0.5" or "+Inf".
+func formatBound(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// labelTags returns the resource tags merged with the labels, which take precedence.
+func labelTags(resourceTags map[string]string, labels pdata.StringMap) map[string]string {
+	tags := make(map[string]string, len(resourceTags)+labels.Len())
+	for k, v := range resourceTags {
+		tags[k] = v
+	}
+	labels.ForEach(func(k string, v string) {
+		tags[k] = v
+	})
+	return tags
+}
@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func newTestExporter(schema string) *influxExporter {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ResourceTags = []string{conventions.AttributeServiceName}
+	cfg.HistogramSchema = schema
+	return &influxExporter{
+		config:  cfg,
+		encoder: lineEncoder{maxTags: cfg.MaxTags},
+	}
+}
+
+func encodePoints(points []*point) []string {
+	lines := make([]string, 0, len(points))
+	for _, p := range points {
+		line, _ := lineEncoder{}.encode(p)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func testMetrics() (pdata.Metrics, pdata.MetricSlice) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	rm.Resource().Attributes().InsertString(conventions.AttributeHostName, "not a tag")
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	return md, rm.InstrumentationLibraryMetrics().At(0).Metrics()
+}
+
+func TestGaugesAndSumsToPoints(t *testing.T) {
+	md, ms := testMetrics()
+	ms.Resize(4)
+
+	ms.At(0).SetName("queue.size")
+	ms.At(0).SetDataType(pdata.MetricDataTypeIntGauge)
+	ms.At(0).IntGauge().DataPoints().Resize(1)
+	ms.At(0).IntGauge().DataPoints().At(0).SetValue(12)
+	ms.At(0).IntGauge().DataPoints().At(0).SetTimestamp(1000)
+	ms.At(0).IntGauge().DataPoints().At(0).LabelsMap().Insert("queue", "orders")
+
+	ms.At(1).SetName("cpu.utilization")
+	ms.At(1).SetDataType(pdata.MetricDataTypeDoubleGauge)
+	ms.At(1).DoubleGauge().DataPoints().Resize(1)
+	ms.At(1).DoubleGauge().DataPoints().At(0).SetValue(0.25)
+	ms.At(1).DoubleGauge().DataPoints().At(0).SetTimestamp(1000)
+	// The labels take precedence over the resource attributes.
+	ms.At(1).DoubleGauge().DataPoints().At(0).LabelsMap().Insert(conventions.AttributeServiceName, "cart")
+
+	ms.At(2).SetName("requests")
+	ms.At(2).SetDataType(pdata.MetricDataTypeIntSum)
+	ms.At(2).IntSum().SetIsMonotonic(true)
+	ms.At(2).IntSum().DataPoints().Resize(1)
+	ms.At(2).IntSum().DataPoints().At(0).SetValue(42)
+	ms.At(2).IntSum().DataPoints().At(0).SetTimestamp(1000)
+
+	ms.At(3).SetName("balance")
+	ms.At(3).SetDataType(pdata.MetricDataTypeDoubleSum)
+	ms.At(3).DoubleSum().SetIsMonotonic(false)
+	ms.At(3).DoubleSum().DataPoints().Resize(1)
+	ms.At(3).DoubleSum().DataPoints().At(0).SetValue(-3.5)
+	ms.At(3).DoubleSum().DataPoints().At(0).SetTimestamp(1000)
+
+	lines := encodePoints(newTestExporter(histogramSchemaFields).metricsToPoints(md))
+	assert.Equal(t, []string{
+		"queue.size,queue=orders,service.name=checkout gauge=12 1000",
+		"cpu.utilization,service.name=cart gauge=0.25 1000",
+		"requests,service.name=checkout counter=42 1000",
+		"balance,service.name=checkout gauge=-3.5 1000",
+	}, lines)
+}
+
+func testHistogramMetrics() pdata.Metrics {
+	md, ms := testMetrics()
+	ms.Resize(3)
+
+	ms.At(0).SetName("latency")
+	ms.At(0).SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	ms.At(0).DoubleHistogram().DataPoints().Resize(1)
+	dp := ms.At(0).DoubleHistogram().DataPoints().At(0)
+	dp.SetTimestamp(1000)
+	dp.SetCount(6)
+	dp.SetSum(2.5)
+	dp.SetExplicitBounds([]float64{0.1, 0.5})
+	dp.SetBucketCounts([]uint64{1, 2, 3})
+
+	ms.At(1).SetName("size")
+	ms.At(1).SetDataType(pdata.MetricDataTypeIntHistogram)
+	ms.At(1).IntHistogram().DataPoints().Resize(1)
+	idp := ms.At(1).IntHistogram().DataPoints().At(0)
+	idp.SetTimestamp(1000)
+	idp.SetCount(3)
+	idp.SetSum(300)
+	idp.SetExplicitBounds([]float64{100})
+	idp.SetBucketCounts([]uint64{1, 2})
+
+	ms.At(2).SetName("duration")
+	ms.At(2).SetDataType(pdata.MetricDataTypeDoubleSummary)
+	ms.At(2).DoubleSummary().DataPoints().Resize(1)
+	sdp := ms.At(2).DoubleSummary().DataPoints().At(0)
+	sdp.SetTimestamp(1000)
+	sdp.SetCount(10)
+	sdp.SetSum(5)
+	sdp.QuantileValues().Resize(2)
+	sdp.QuantileValues().At(0).SetQuantile(0.5)
+	sdp.QuantileValues().At(0).SetValue(0.4)
+	sdp.QuantileValues().At(1).SetQuantile(0.99)
+	sdp.QuantileValues().At(1).SetValue(1.2)
+
+	return md
+}
+
+func TestHistogramsToPointsFieldsSchema(t *testing.T) {
+	lines := encodePoints(newTestExporter(histogramSchemaFields).metricsToPoints(testHistogramMetrics()))
+	assert.Equal(t, []string{
+		`latency,service.name=checkout +Inf=6,0.1=1,0.5=3,count=6,sum=2.5 1000`,
+		`size,service.name=checkout +Inf=3,100=1,count=3,sum=300 1000`,
+		`duration,service.name=checkout 0.5=0.4,0.99=1.2,count=10,sum=5 1000`,
+	}, lines)
+}
+
+func TestHistogramsToPointsSeriesSchema(t *testing.T) {
+	lines := encodePoints(newTestExporter(histogramSchemaSeries).metricsToPoints(testHistogramMetrics()))
+	assert.Equal(t, []string{
+		`latency_count,service.name=checkout counter=6 1000`,
+		`latency_sum,service.name=checkout counter=2.5 1000`,
+		`latency_bucket,le=0.1,service.name=checkout counter=1 1000`,
+		`latency_bucket,le=0.5,service.name=checkout counter=3 1000`,
+		`latency_bucket,le=+Inf,service.name=checkout counter=6 1000`,
+		`size_count,service.name=checkout counter=3 1000`,
+		`size_sum,service.name=checkout counter=300 1000`,
+		`size_bucket,le=100,service.name=checkout counter=1 1000`,
+		`size_bucket,le=+Inf,service.name=checkout counter=3 1000`,
+		`duration_count,service.name=checkout counter=10 1000`,
+		`duration_sum,service.name=checkout counter=5 1000`,
+		`duration,quantile=0.5,service.name=checkout gauge=0.4 1000`,
+		`duration,quantile=0.99,service.name=checkout gauge=1.2 1000`,
+	}, lines)
+}
+
+func TestHistogramWithInvalidBuckets(t *testing.T) {
+	md, ms := testMetrics()
+	ms.Resize(1)
+	ms.At(0).SetName("latency")
+	ms.At(0).SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	ms.At(0).DoubleHistogram().DataPoints().Resize(1)
+	dp := ms.At(0).DoubleHistogram().DataPoints().At(0)
+	dp.SetTimestamp(1000)
+	dp.SetCount(6)
+	dp.SetSum(2.5)
+	dp.SetExplicitBounds([]float64{0.1, 0.5})
+	dp.SetBucketCounts([]uint64{6})
+
+	lines := encodePoints(newTestExporter(histogramSchemaFields).metricsToPoints(md))
+	assert.Equal(t, []string{`latency,service.name=checkout count=6,sum=2.5 1000`}, lines)
+}
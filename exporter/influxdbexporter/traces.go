@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	measurementSpans = "spans"
+
+	tagTraceID = "trace_id"
+	tagSpanID  = "span_id"
+
+	fieldParentSpanID  = "parent_span_id"
+	fieldName          = "name"
+	fieldKind          = "kind"
+	fieldDurationNano  = "duration_nano"
+	fieldStatusCode    = "status_code"
+	fieldStatusMessage = "status_message"
+)
+
+// tracesToPoints converts the spans to points of the spans measurement, with their trace and span IDs as tags and
+// their attributes as fields. The points are timestamped with the start time of the spans.
+func (e *influxExporter) tracesToPoints(td pdata.Traces) []*point {
+	points := make([]*point, 0, td.SpanCount())
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceTags := e.resourceTags(rs.Resource())
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				points = append(points, spanToPoint(spans.At(k), resourceTags))
+			}
+		}
+	}
+
+	return points
+}
+
+func spanToPoint(span pdata.Span, resourceTags map[string]string) *point {
+	p := newPoint(measurementSpans, resourceTags, span.StartTime())
+	p.tags[tagTraceID] = span.TraceID().HexString()
+	p.tags[tagSpanID] = span.SpanID().HexString()
+
+	if parentSpanID := span.ParentSpanID(); !parentSpanID.IsEmpty() {
+		p.fields[fieldParentSpanID] = parentSpanID.HexString()
+	}
+	p.fields[fieldName] = span.Name()
+	p.fields[fieldKind] = span.Kind().String()
+	if span.EndTime() >= span.StartTime() {
+		p.fields[fieldDurationNano] = int64(span.EndTime() - span.StartTime())
+	}
+	p.fields[fieldStatusCode] = span.Status().Code().String()
+	if msg := span.Status().Message(); msg != "" {
+		p.fields[fieldStatusMessage] = msg
+	}
+
+	addAttributeFields(p, span.Attributes())
+
+	return p
+}
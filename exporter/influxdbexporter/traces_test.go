@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestTracesToPoints(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(2)
+
+	span := spans.At(0)
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.SetName("GET /cart")
+	span.SetKind(pdata.SpanKindSERVER)
+	span.SetStartTime(1000)
+	span.SetEndTime(1500)
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Status().SetMessage("internal error")
+	span.Attributes().InsertString(conventions.AttributeHTTPMethod, "GET")
+	span.Attributes().InsertInt(conventions.AttributeHTTPStatusCode, 500)
+
+	child := spans.At(1)
+	child.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	child.SetSpanID(pdata.NewSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}))
+	child.SetParentSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	child.SetName("SELECT")
+	child.SetKind(pdata.SpanKindCLIENT)
+	child.SetStartTime(1100)
+	child.SetEndTime(1200)
+
+	lines := encodePoints(newTestExporter(histogramSchemaFields).tracesToPoints(td))
+	assert.Equal(t, []string{
+		`spans,service.name=checkout,span_id=0102030405060708,trace_id=0102030405060708090a0b0c0d0e0f10 ` +
+			`duration_nano=500i,http.method="GET",http.status_code=500i,kind="SPAN_KIND_SERVER",name="GET /cart",status_code="STATUS_CODE_ERROR",status_message="internal error" 1000`,
+		`spans,service.name=checkout,span_id=0807060504030201,trace_id=0102030405060708090a0b0c0d0e0f10 ` +
+			`duration_nano=100i,kind="SPAN_KIND_CLIENT",name="SELECT",parent_span_id="0102030405060708",status_code="STATUS_CODE_UNSET" 1100`,
+	}, lines)
+}
@@ -15,15 +15,30 @@
 package loadbalancingexporter
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
 
 // Config defines configuration for the exporter.
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"`
-	Protocol                      Protocol         `mapstructure:"protocol"`
-	Resolver                      ResolverSettings `mapstructure:"resolver"`
+	Protocol                      Protocol              `mapstructure:"protocol"`
+	Resolver                      ResolverSettings      `mapstructure:"resolver"`
+	RoutingKey                    string                `mapstructure:"routing_key"`
+	BackendHealth                 BackendHealthSettings `mapstructure:"backend_health"`
+}
+
+// BackendHealthSettings defines how the backends failing to receive the data are avoided
+type BackendHealthSettings struct {
+	// MaxConsecutiveFailures is the number of consecutive send failures after which a backend is marked unhealthy,
+	// its data being routed to the next backends of the ring. The backends are never marked unhealthy when it's 0
+	MaxConsecutiveFailures int `mapstructure:"max_consecutive_failures"`
+	// ProbeInterval is the interval at which a batch is sent to an unhealthy backend to check whether it recovered
+	ProbeInterval time.Duration `mapstructure:"probe_interval"`
 }
 
 // Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
@@ -35,6 +50,7 @@ type Protocol struct {
 type ResolverSettings struct {
 	Static *StaticResolver `mapstructure:"static"`
 	DNS    *DNSResolver    `mapstructure:"dns"`
+	K8sSvc *K8sSvcResolver `mapstructure:"k8s"`
 }
 
 // StaticResolver defines the configuration for the resolver providing a fixed list of backends
@@ -44,6 +60,19 @@ type StaticResolver struct {
 
 // DNSResolver defines the configuration for the DNS resolver
 type DNSResolver struct {
-	Hostname string `mapstructure:"hostname"`
-	Port     string `mapstructure:"port"`
+	Hostname string        `mapstructure:"hostname"`
+	Port     string        `mapstructure:"port"`
+	Interval time.Duration `mapstructure:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// K8sSvcResolver defines the configuration for the resolver watching the endpoints of a Kubernetes service,
+// typically a headless service
+type K8sSvcResolver struct {
+	k8sconfig.APIConfig `mapstructure:",squash"`
+
+	// Service is the name of the service, as name.namespace, the namespace being default when not specified
+	Service string `mapstructure:"service"`
+	// Port is the port to export the traces to, on the addresses of the endpoints
+	Port string `mapstructure:"port"`
 }
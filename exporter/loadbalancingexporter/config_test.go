@@ -17,6 +17,7 @@ package loadbalancingexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -33,4 +34,19 @@ func TestLoadConfig(t *testing.T) {
 	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
+
+	assert.Equal(t, traceIDRoutingKey, cfg.Exporters["loadbalancing"].(*Config).RoutingKey)
+	assert.Equal(t, svcRoutingKey, cfg.Exporters["loadbalancing/2"].(*Config).RoutingKey)
+	assert.Equal(t,
+		&DNSResolver{Hostname: "service-1", Port: "55690", Interval: 30 * time.Second, Timeout: 2 * time.Second},
+		cfg.Exporters["loadbalancing/3"].(*Config).Resolver.DNS)
+	assert.Equal(t,
+		BackendHealthSettings{MaxConsecutiveFailures: defaultMaxConsecutiveFailures, ProbeInterval: defaultProbeInterval},
+		cfg.Exporters["loadbalancing"].(*Config).BackendHealth)
+	assert.Equal(t,
+		BackendHealthSettings{MaxConsecutiveFailures: 3, ProbeInterval: time.Minute},
+		cfg.Exporters["loadbalancing/3"].(*Config).BackendHealth)
+	assert.Equal(t,
+		&K8sSvcResolver{Service: "lb-svc.observability", Port: "55690"},
+		cfg.Exporters["loadbalancing/4"].(*Config).Resolver.K8sSvc)
 }
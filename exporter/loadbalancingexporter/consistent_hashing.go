@@ -51,14 +51,46 @@ func newHashRing(endpoints []string) *hashRing {
 // endpointFor calculates which backend is responsible for the given traceID
 func (h *hashRing) endpointFor(traceID pdata.TraceID) string {
 	b := traceID.Bytes()
+	return h.endpointForKey(b[:])
+}
+
+// endpointForKey calculates which backend is responsible for the given routing key, e.g. a service name
+func (h *hashRing) endpointForKey(key []byte) string {
 	hasher := crc32.NewIEEE()
-	hasher.Write(b[:])
+	hasher.Write(key)
 	hash := hasher.Sum32()
 	pos := hash % maxPositions
 
 	return h.findEndpoint(position(pos))
 }
 
+// endpointForKeyWhere returns the first endpoint accepted by the given function, going through the ring from the
+// position of the given routing key: the endpoint responsible for the key if it's accepted, and otherwise the next
+// ones, so that the keys of the other endpoints aren't moved. It returns false when no endpoint is accepted.
+func (h *hashRing) endpointForKeyWhere(key []byte, accept func(endpoint string) bool) (string, bool) {
+	hasher := crc32.NewIEEE()
+	hasher.Write(key)
+	pos := position(hasher.Sum32() % maxPositions)
+
+	// the same item as the one returned by findEndpoint: the first one at or after the position, or the first one
+	start := sort.Search(len(h.items), func(i int) bool {
+		return h.items[i].pos >= pos
+	})
+
+	checked := map[string]bool{}
+	for i := 0; i < len(h.items); i++ {
+		endpoint := h.items[(start+i)%len(h.items)].endpoint
+		if checked[endpoint] {
+			continue
+		}
+		checked[endpoint] = true
+		if accept(endpoint) {
+			return endpoint, true
+		}
+	}
+	return "", false
+}
+
 // findEndpoint returns the "next" endpoint starting from the given position
 func (h *hashRing) findEndpoint(pos position) string {
 	ringSize := len(h.items)
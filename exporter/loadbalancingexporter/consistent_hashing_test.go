@@ -57,6 +57,49 @@ func TestEndpointFor(t *testing.T) {
 	}
 }
 
+func TestEndpointForKey(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	ring := newHashRing(endpoints)
+	traceID := pdata.NewTraceID([16]byte{128, 128, 0, 0})
+	b := traceID.Bytes()
+
+	// test
+	byTraceID := ring.endpointForKey(b[:])
+	byService := ring.endpointForKey([]byte("svc-1"))
+
+	// verify
+	assert.Equal(t, ring.endpointFor(traceID), byTraceID)
+	assert.Equal(t, byService, ring.endpointForKey([]byte("svc-1")))
+}
+
+func TestEndpointForKeyWhere(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	ring := newHashRing(endpoints)
+
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		owner := ring.endpointForKey(key)
+
+		// test
+		all, allFound := ring.endpointForKeyWhere(key, func(string) bool { return true })
+		other, otherFound := ring.endpointForKeyWhere(key, func(endpoint string) bool { return endpoint != "endpoint-2" })
+		_, noneFound := ring.endpointForKeyWhere(key, func(string) bool { return false })
+
+		// verify
+		assert.True(t, allFound)
+		assert.Equal(t, owner, all)
+		assert.True(t, otherFound)
+		assert.NotEqual(t, "endpoint-2", other)
+		if owner != "endpoint-2" {
+			// only the keys of the excluded endpoint are moved
+			assert.Equal(t, owner, other)
+		}
+		assert.False(t, noneFound)
+	}
+}
+
 func TestPositionsFor(t *testing.T) {
 	// prepare
 	endpoint := "host1"
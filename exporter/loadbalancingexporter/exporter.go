@@ -35,36 +35,51 @@ import (
 )
 
 var _ component.TracesExporter = (*exporterImp)(nil)
+var _ component.MetricsExporter = (*exporterImp)(nil)
+var _ component.LogsExporter = (*exporterImp)(nil)
 
 const (
 	defaultPort = "55680"
+
+	traceIDRoutingKey = "traceID"
+	svcRoutingKey     = "service"
 )
 
 var (
 	errNoResolver                = errors.New("no resolvers specified for the exporter")
 	errMultipleResolversProvided = errors.New("only one resolver should be specified")
 	errNoTracesInBatch           = errors.New("no traces were found in the batch")
+	errNoBackends                = errors.New("no backends are currently available")
 )
 
+// errUnsupportedRoutingKey is returned when the routing key is neither the trace ID nor the service
+func errUnsupportedRoutingKey(key string) error {
+	return fmt.Errorf("unsupported routing_key %q, must be either %q or %q", key, traceIDRoutingKey, svcRoutingKey)
+}
+
 type exporterImp struct {
-	logger *zap.Logger
-	config Config
-	host   component.Host
+	logger   *zap.Logger
+	config   Config
+	host     component.Host
+	dataType configmodels.DataType
 
-	res  resolver
-	ring *hashRing
+	res    resolver
+	ring   *hashRing
+	health *backendHealth
 
-	exporters            map[string]component.TracesExporter
+	// exporters holds the exporters of the backends, of the data type of this exporter
+	exporters            map[string]component.Exporter
 	exporterFactory      component.ExporterFactory
 	templateCreateParams component.ExporterCreateParams
 
-	stopped    bool
-	shutdownWg sync.WaitGroup
+	// changeLock serializes the updates of the backends, and updateLock protects the ring and exporters,
+	// the traces being sent while holding it for reading
+	changeLock sync.Mutex
 	updateLock sync.RWMutex
 }
 
-// Crete new exporter
-func newExporter(params component.ExporterCreateParams, cfg configmodels.Exporter) (*exporterImp, error) {
+// Crete new exporter, balancing the data of the given type
+func newExporter(params component.ExporterCreateParams, cfg configmodels.Exporter, dataType configmodels.DataType) (*exporterImp, error) {
 	oCfg := cfg.(*Config)
 
 	tmplParams := component.ExporterCreateParams{
@@ -72,10 +87,22 @@ func newExporter(params component.ExporterCreateParams, cfg configmodels.Exporte
 		ApplicationStartInfo: params.ApplicationStartInfo,
 	}
 
-	if oCfg.Resolver.DNS != nil && oCfg.Resolver.Static != nil {
+	numResolvers := 0
+	for _, configured := range []bool{oCfg.Resolver.Static != nil, oCfg.Resolver.DNS != nil, oCfg.Resolver.K8sSvc != nil} {
+		if configured {
+			numResolvers++
+		}
+	}
+	if numResolvers > 1 {
 		return nil, errMultipleResolversProvided
 	}
 
+	switch oCfg.RoutingKey {
+	case "", traceIDRoutingKey, svcRoutingKey:
+	default:
+		return nil, errUnsupportedRoutingKey(oCfg.RoutingKey)
+	}
+
 	var res resolver
 	if oCfg.Resolver.Static != nil {
 		var err error
@@ -88,7 +115,17 @@ func newExporter(params component.ExporterCreateParams, cfg configmodels.Exporte
 		dnsLogger := params.Logger.With(zap.String("resolver", "dns"))
 
 		var err error
-		res, err = newDNSResolver(dnsLogger, oCfg.Resolver.DNS.Hostname, oCfg.Resolver.DNS.Port)
+		res, err = newDNSResolver(dnsLogger, oCfg.Resolver.DNS.Hostname, oCfg.Resolver.DNS.Port,
+			oCfg.Resolver.DNS.Interval, oCfg.Resolver.DNS.Timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if oCfg.Resolver.K8sSvc != nil {
+		k8sLogger := params.Logger.With(zap.String("resolver", "k8s"))
+
+		var err error
+		res, err = newK8sResolver(k8sLogger, *oCfg.Resolver.K8sSvc)
 		if err != nil {
 			return nil, err
 		}
@@ -99,12 +136,14 @@ func newExporter(params component.ExporterCreateParams, cfg configmodels.Exporte
 	}
 
 	return &exporterImp{
-		logger: params.Logger,
-		config: *oCfg,
+		logger:   params.Logger,
+		config:   *oCfg,
+		dataType: dataType,
 
-		res: res,
+		res:    res,
+		health: newBackendHealth(oCfg.BackendHealth),
 
-		exporters:            map[string]component.TracesExporter{},
+		exporters:            map[string]component.Exporter{},
 		exporterFactory:      otlpexporter.NewFactory(),
 		templateCreateParams: tmplParams,
 	}, nil
@@ -120,41 +159,83 @@ func (e *exporterImp) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
+// onBackendChanges updates the ring without dropping the traces being sent: the exporters of the new backends
+// are started before being added to the ring, and the exporters of the removed backends are shut down once
+// the traces being sent to them are sent.
 func (e *exporterImp) onBackendChanges(resolved []string) {
+	e.changeLock.Lock()
+	defer e.changeLock.Unlock()
+
 	newRing := newHashRing(resolved)
 
-	if !newRing.equal(e.ring) {
-		e.updateLock.Lock()
-		defer e.updateLock.Unlock()
+	e.updateLock.RLock()
+	unchanged := newRing.equal(e.ring)
+	e.updateLock.RUnlock()
+	if unchanged {
+		return
+	}
 
-		e.ring = newRing
+	// TODO: set a timeout?
+	ctx := context.Background()
 
-		// TODO: set a timeout?
-		ctx := context.Background()
+	// the exporters are only added by this function, so they can be read without the lock
+	added := e.startMissingExporters(ctx, resolved)
 
-		// add the missing exporters first
-		e.addMissingExporters(ctx, resolved)
-		e.removeExtraExporters(ctx, resolved)
+	// the lock is acquired once the traces being sent are sent
+	e.updateLock.Lock()
+	for endpoint, exp := range added {
+		e.exporters[endpoint] = exp
+	}
+	removed := e.removeExtraExporters(resolved)
+	e.ring = newRing
+	e.updateLock.Unlock()
+
+	stats.Record(ctx, mNumRingUpdates.M(1))
+	e.recordHealthyBackends(ctx, newRing)
+	for endpoint, exp := range removed {
+		if err := exp.Shutdown(ctx); err != nil {
+			e.logger.Warn("failed to shut down the exporter of a removed endpoint", zap.String("endpoint", endpoint), zap.Error(err))
+		}
 	}
 }
 
-func (e *exporterImp) addMissingExporters(ctx context.Context, endpoints []string) {
+// startMissingExporters creates and starts the exporters of the endpoints which don't have one yet
+func (e *exporterImp) startMissingExporters(ctx context.Context, endpoints []string) map[string]component.Exporter {
+	started := map[string]component.Exporter{}
 	for _, endpoint := range endpoints {
 		endpoint = endpointWithPort(endpoint)
 
-		if _, exists := e.exporters[endpoint]; !exists {
-			cfg := e.buildExporterConfig(endpoint)
-			exp, err := e.exporterFactory.CreateTracesExporter(ctx, e.templateCreateParams, &cfg)
-			if err != nil {
-				e.logger.Error("failed to create new trace exporter for endpoint", zap.String("endpoint", endpoint), zap.Error(err))
-				continue
-			}
-			if err = exp.Start(ctx, e.host); err != nil {
-				e.logger.Error("failed to start new trace exporter for endpoint", zap.String("endpoint", endpoint), zap.Error(err))
-				continue
-			}
-			e.exporters[endpoint] = exp
+		if _, exists := e.exporters[endpoint]; exists {
+			continue
 		}
+		if _, exists := started[endpoint]; exists {
+			continue
+		}
+
+		cfg := e.buildExporterConfig(endpoint)
+		exp, err := e.createExporter(ctx, &cfg)
+		if err != nil {
+			e.logger.Error("failed to create new exporter for endpoint", zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		if err = exp.Start(ctx, e.host); err != nil {
+			e.logger.Error("failed to start new exporter for endpoint", zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		started[endpoint] = exp
+	}
+	return started
+}
+
+// createExporter creates an exporter of the data type of this exporter
+func (e *exporterImp) createExporter(ctx context.Context, cfg configmodels.Exporter) (component.Exporter, error) {
+	switch e.dataType {
+	case configmodels.MetricsDataType:
+		return e.exporterFactory.CreateMetricsExporter(ctx, e.templateCreateParams, cfg)
+	case configmodels.LogsDataType:
+		return e.exporterFactory.CreateLogsExporter(ctx, e.templateCreateParams, cfg)
+	default:
+		return e.exporterFactory.CreateTracesExporter(ctx, e.templateCreateParams, cfg)
 	}
 }
 
@@ -164,13 +245,22 @@ func (e *exporterImp) buildExporterConfig(endpoint string) otlpexporter.Config {
 	return oCfg
 }
 
-func (e *exporterImp) removeExtraExporters(ctx context.Context, endpoints []string) {
-	for existing := range e.exporters {
-		if !endpointFound(existing, endpoints) {
-			e.exporters[existing].Shutdown(ctx)
+// removeExtraExporters removes the exporters of the endpoints which aren't resolved anymore, and returns them
+// for them to be shut down
+func (e *exporterImp) removeExtraExporters(endpoints []string) map[string]component.Exporter {
+	endpointsWithPort := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		endpointsWithPort[i] = endpointWithPort(endpoint)
+	}
+
+	removed := map[string]component.Exporter{}
+	for existing, exp := range e.exporters {
+		if !endpointFound(existing, endpointsWithPort) {
+			removed[existing] = exp
 			delete(e.exporters, existing)
 		}
 	}
+	return removed
 }
 
 func endpointFound(endpoint string, endpoints []string) bool {
@@ -183,10 +273,28 @@ func endpointFound(endpoint string, endpoints []string) bool {
 	return false
 }
 
-func (e *exporterImp) Shutdown(context.Context) error {
-	e.stopped = true
-	e.shutdownWg.Wait()
-	return nil
+func (e *exporterImp) Shutdown(ctx context.Context) error {
+	err := e.res.shutdown(ctx)
+
+	// the changes of the backends are done, and the traces being sent are sent once the lock is acquired
+	e.changeLock.Lock()
+	defer e.changeLock.Unlock()
+	e.updateLock.Lock()
+	exporters := e.exporters
+	e.exporters = map[string]component.Exporter{}
+	e.ring = nil
+	e.updateLock.Unlock()
+
+	errs := []error{}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, exp := range exporters {
+		if err := exp.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
 }
 
 func (e *exporterImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
@@ -194,6 +302,17 @@ func (e *exporterImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error
 	defer e.updateLock.RUnlock()
 
 	var errors []error
+	if e.config.RoutingKey == svcRoutingKey {
+		// the spans without a service name are routed by their trace ID
+		var batches []serviceBatch
+		batches, td = splitByServiceName(td)
+		for _, batch := range batches {
+			if err := e.consumeTracesBatch(ctx, []byte(batch.service), batch.traces); err != nil {
+				errors = append(errors, err)
+			}
+		}
+	}
+
 	batches := batchpertrace.Split(td)
 	for _, batch := range batches {
 		if err := e.consumeTrace(ctx, batch); err != nil {
@@ -210,7 +329,88 @@ func (e *exporterImp) consumeTrace(ctx context.Context, td pdata.Traces) error {
 		return errNoTracesInBatch
 	}
 
-	endpoint := e.ring.endpointFor(traceID)
+	b := traceID.Bytes()
+	return e.consumeTracesBatch(ctx, b[:], td)
+}
+
+func (e *exporterImp) consumeTracesBatch(ctx context.Context, key []byte, td pdata.Traces) error {
+	return e.consumeBatch(ctx, key, func(exp component.Exporter) error {
+		return exp.(component.TracesExporter).ConsumeTraces(ctx, td)
+	})
+}
+
+func (e *exporterImp) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	e.updateLock.RLock()
+	defer e.updateLock.RUnlock()
+
+	var errors []error
+	for _, batch := range splitMetrics(md, e.config.RoutingKey == svcRoutingKey) {
+		metrics := batch.metrics
+		err := e.consumeBatch(ctx, []byte(batch.key), func(exp component.Exporter) error {
+			return exp.(component.MetricsExporter).ConsumeMetrics(ctx, metrics)
+		})
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return componenterror.CombineErrors(errors)
+}
+
+func (e *exporterImp) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	e.updateLock.RLock()
+	defer e.updateLock.RUnlock()
+
+	var errors []error
+	for _, batch := range splitLogs(ld) {
+		logs := batch.logs
+		err := e.consumeBatch(ctx, []byte(batch.key), func(exp component.Exporter) error {
+			return exp.(component.LogsExporter).ConsumeLogs(ctx, logs)
+		})
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return componenterror.CombineErrors(errors)
+}
+
+// consumeBatch sends a batch through the exporter of the backend responsible for the routing key. When this backend
+// is unhealthy, or becomes unhealthy because of this batch, the batch is sent to the next available backend of the ring.
+func (e *exporterImp) consumeBatch(ctx context.Context, key []byte, send func(exp component.Exporter) error) error {
+	if e.ring == nil || len(e.ring.items) == 0 {
+		return errNoBackends
+	}
+
+	tried := map[string]bool{}
+	endpoint, found := e.ring.endpointForKeyWhere(key, func(candidate string) bool {
+		return e.health.available(endpointWithPort(candidate))
+	})
+	if !found {
+		// all the backends are unhealthy, the data is sent as if they were healthy
+		found = true
+		endpoint = e.ring.endpointForKey(key)
+	}
+
+	var err error
+	for found {
+		endpoint = endpointWithPort(endpoint)
+		err = e.sendTo(ctx, endpoint, send)
+		if err == nil || e.health.isHealthy(endpoint) {
+			return err
+		}
+
+		tried[endpoint] = true
+		endpoint, found = e.ring.endpointForKeyWhere(key, func(candidate string) bool {
+			candidate = endpointWithPort(candidate)
+			return !tried[candidate] && e.health.available(candidate)
+		})
+	}
+	return err
+}
+
+// sendTo sends a batch through the exporter of the endpoint, recording the outcome
+func (e *exporterImp) sendTo(ctx context.Context, endpoint string, send func(exp component.Exporter) error) error {
 	exp, found := e.exporters[endpoint]
 	if !found {
 		// something is really wrong... how come we couldn't find the exporter??
@@ -218,21 +418,48 @@ func (e *exporterImp) consumeTrace(ctx context.Context, td pdata.Traces) error {
 	}
 
 	start := time.Now()
-	err := exp.ConsumeTraces(ctx, td)
+	err := send(exp)
 	duration := time.Since(start)
-	ctx, _ = tag.New(ctx, tag.Upsert(tag.MustNewKey("endpoint"), endpoint))
+	mCtx, _ := tag.New(ctx,
+		tag.Upsert(tag.MustNewKey("endpoint"), endpoint),
+		tag.Upsert(tag.MustNewKey("signal"), string(e.dataType)))
 
 	if err == nil {
-		sCtx, _ := tag.New(ctx, tag.Upsert(tag.MustNewKey("success"), "true"))
+		sCtx, _ := tag.New(mCtx, tag.Upsert(tag.MustNewKey("success"), "true"))
 		stats.Record(sCtx, mBackendLatency.M(duration.Milliseconds()))
 	} else {
-		fCtx, _ := tag.New(ctx, tag.Upsert(tag.MustNewKey("success"), "false"))
+		fCtx, _ := tag.New(mCtx, tag.Upsert(tag.MustNewKey("success"), "false"))
 		stats.Record(fCtx, mBackendLatency.M(duration.Milliseconds()))
 	}
 
+	if e.health.record(endpoint, err) {
+		if err == nil {
+			e.logger.Info("backend is healthy again", zap.String("endpoint", endpoint))
+		} else {
+			e.logger.Warn("backend marked unhealthy, its data is sent to the next backends of the ring",
+				zap.String("endpoint", endpoint), zap.Error(err))
+		}
+		e.recordHealthyBackends(ctx, e.ring)
+	}
+
 	return err
 }
 
+// recordHealthyBackends records the number of healthy backends of the ring
+func (e *exporterImp) recordHealthyBackends(ctx context.Context, ring *hashRing) {
+	var endpoints []string
+	seen := map[string]bool{}
+	for _, item := range ring.items {
+		endpoint := endpointWithPort(item.endpoint)
+		if !seen[endpoint] {
+			seen[endpoint] = true
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	mCtx, _ := tag.New(ctx, tag.Upsert(tag.MustNewKey("signal"), string(e.dataType)))
+	stats.Record(mCtx, mNumHealthy.M(int64(e.health.update(endpoints))))
+}
+
 func (e *exporterImp) GetCapabilities() component.ProcessorCapabilities {
 	return component.ProcessorCapabilities{MutatesConsumedData: false}
 }
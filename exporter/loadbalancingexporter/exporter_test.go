@@ -18,10 +18,13 @@ import (
 	"context"
 	"errors"
 	"path"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -40,7 +43,7 @@ func TestNewExporterNoResolver(t *testing.T) {
 	}
 
 	// test
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 
 	// verify
 	require.Nil(t, p)
@@ -59,7 +62,7 @@ func TestNewExporterInvalidStaticResolver(t *testing.T) {
 	}
 
 	// test
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 
 	// verify
 	require.Nil(t, p)
@@ -80,20 +83,57 @@ func TestNewExporterInvalidDNSResolver(t *testing.T) {
 	}
 
 	// test
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 
 	// verify
 	require.Nil(t, p)
 	require.Equal(t, errNoHostname, err)
 }
 
+func TestNewExporterInvalidK8sResolver(t *testing.T) {
+	// prepare
+	config := &Config{
+		Resolver: ResolverSettings{
+			K8sSvc: &K8sSvcResolver{
+				Service: "",
+			},
+		},
+	}
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+
+	// test
+	p, err := newExporter(params, config, configmodels.TracesDataType)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errNoSvc, err)
+}
+
+func TestNewExporterInvalidRoutingKey(t *testing.T) {
+	// prepare
+	config := simpleConfig()
+	config.RoutingKey = "span"
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+
+	// test
+	p, err := newExporter(params, config, configmodels.TracesDataType)
+
+	// verify
+	require.Nil(t, p)
+	require.EqualError(t, err, `unsupported routing_key "span", must be either "traceID" or "service"`)
+}
+
 func TestExporterCapabilities(t *testing.T) {
 	// prepare
 	config := simpleConfig()
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 
 	// test
 	caps := p.GetCapabilities()
@@ -110,7 +150,7 @@ func TestStart(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 	p.res = &mockResolver{}
@@ -133,7 +173,7 @@ func TestWithDNSResolver(t *testing.T) {
 				Hostname: "service-1",
 			},
 		},
-	})
+	}, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -160,7 +200,23 @@ func TestMultipleResolvers(t *testing.T) {
 				Hostname: "service-1",
 			},
 		},
-	})
+	}, configmodels.TracesDataType)
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errMultipleResolversProvided, err)
+
+	// test
+	p, err = newExporter(params, &Config{
+		Resolver: ResolverSettings{
+			DNS: &DNSResolver{
+				Hostname: "service-1",
+			},
+			K8sSvc: &K8sSvcResolver{
+				Service: "service-1.monitoring",
+			},
+		},
+	}, configmodels.TracesDataType)
 
 	// verify
 	assert.Nil(t, p)
@@ -173,7 +229,7 @@ func TestStartFailureStaticResolver(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -197,7 +253,7 @@ func TestShutdown(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -214,12 +270,12 @@ func TestConsumeTraces(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
 	// pre-load an exporter here, so that we don't use the actual OTLP exporter
-	p.exporters["endpoint-1"] = &componenttest.ExampleExporterConsumer{}
+	p.exporters["endpoint-1:55680"] = &componenttest.ExampleExporterConsumer{}
 	p.res = &mockResolver{
 		triggerCallbacks: true,
 		onResolve: func(ctx context.Context) ([]string, error) {
@@ -244,7 +300,7 @@ func TestOnBackendChanges(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -266,20 +322,22 @@ func TestRemoveExtraExporters(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
-	p.exporters["endpoint-1"] = &componenttest.ExampleExporterConsumer{}
-	p.exporters["endpoint-2"] = &componenttest.ExampleExporterConsumer{}
+	p.exporters["endpoint-1:55680"] = &componenttest.ExampleExporterConsumer{}
+	p.exporters["endpoint-2:55680"] = &componenttest.ExampleExporterConsumer{}
 	resolved := []string{"endpoint-1"}
 
 	// test
-	p.removeExtraExporters(context.Background(), resolved)
+	removed := p.removeExtraExporters(resolved)
 
 	// verify
 	assert.Len(t, p.exporters, 1)
-	assert.NotContains(t, p.exporters, "endpoint-2")
+	assert.NotContains(t, p.exporters, "endpoint-2:55680")
+	assert.Len(t, removed, 1)
+	assert.Contains(t, removed, "endpoint-2:55680")
 }
 
 func TestAddMissingExporters(t *testing.T) {
@@ -288,7 +346,7 @@ func TestAddMissingExporters(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -306,11 +364,13 @@ func TestAddMissingExporters(t *testing.T) {
 	resolved := []string{"endpoint-1", "endpoint-2"}
 
 	// test
-	p.addMissingExporters(context.Background(), resolved)
+	started := p.startMissingExporters(context.Background(), resolved)
 
 	// verify
-	assert.Len(t, p.exporters, 2)
-	assert.Contains(t, p.exporters, "endpoint-2:55680")
+	assert.Len(t, started, 1)
+	assert.Contains(t, started, "endpoint-2:55680")
+	// the exporters are added to the ring by onBackendChanges
+	assert.Len(t, p.exporters, 1)
 }
 
 func TestFailedToAddMissingExporters(t *testing.T) {
@@ -319,7 +379,7 @@ func TestFailedToAddMissingExporters(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -334,15 +394,14 @@ func TestFailedToAddMissingExporters(t *testing.T) {
 		return nil, expectedErr
 	}))
 
-	p.exporters["endpoint-1"] = &componenttest.ExampleExporterConsumer{}
+	p.exporters["endpoint-1:55680"] = &componenttest.ExampleExporterConsumer{}
 	resolved := []string{"endpoint-1", "endpoint-2"}
 
 	// test
-	p.addMissingExporters(context.Background(), resolved)
+	started := p.startMissingExporters(context.Background(), resolved)
 
 	// verify
-	assert.Len(t, p.exporters, 1)
-	assert.NotContains(t, p.exporters, "endpoint-2")
+	assert.Len(t, started, 0)
 }
 
 func TestEndpointFound(t *testing.T) {
@@ -398,7 +457,7 @@ func TestBuildExporterConfig(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	e, err := newExporter(params, cfg.Exporters["loadbalancing"])
+	e, err := newExporter(params, cfg.Exporters["loadbalancing"], configmodels.TracesDataType)
 	require.NotNil(t, e)
 	require.NoError(t, err)
 
@@ -423,7 +482,7 @@ func TestBatchWithTwoTraces(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -431,7 +490,7 @@ func TestBatchWithTwoTraces(t *testing.T) {
 	require.NoError(t, err)
 
 	sink := &componenttest.ExampleExporterConsumer{}
-	p.exporters["endpoint-1"] = sink
+	p.exporters["endpoint-1:55680"] = sink
 
 	first := simpleTraces()
 	second := simpleTraceWithID(pdata.NewTraceID([16]byte{2, 3, 4, 5}))
@@ -447,6 +506,184 @@ func TestBatchWithTwoTraces(t *testing.T) {
 	assert.Len(t, sink.Traces, 2)
 }
 
+func TestConsumeTracesServiceRouting(t *testing.T) {
+	// prepare
+	config := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		RoutingKey: svcRoutingKey,
+	}
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+	p, err := newExporter(params, config, configmodels.TracesDataType)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	sinks := map[string]*componenttest.ExampleExporterConsumer{
+		"endpoint-1:55680": {},
+		"endpoint-2:55680": {},
+	}
+	for endpoint, sink := range sinks {
+		p.exporters[endpoint] = sink
+	}
+
+	// the spans of the service have trace IDs reaching both endpoints -- see the consistent hashing tests
+	batch := pdata.NewTraces()
+	for _, id := range [][16]byte{{1, 2, 0, 0}, {128, 128, 0, 0}} {
+		named := simpleTraceWithID(pdata.NewTraceID(id)).ResourceSpans().At(0)
+		named.Resource().Attributes().InsertString("service.name", "svc-1")
+		batch.ResourceSpans().Append(named)
+	}
+	// the spans without a service name are routed by their trace ID
+	batch.ResourceSpans().Append(simpleTraceWithID(pdata.NewTraceID([16]byte{128, 128, 0, 0})).ResourceSpans().At(0))
+
+	// test
+	err = p.ConsumeTraces(context.Background(), batch)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(sinks["endpoint-1:55680"].Traces)+len(sinks["endpoint-2:55680"].Traces))
+
+	svcEndpoint := endpointWithPort(p.ring.endpointForKey([]byte("svc-1")))
+	svcBatch := sinks[svcEndpoint].Traces[0]
+	require.Equal(t, 2, svcBatch.ResourceSpans().Len(), "the spans of the service should be sent as a single batch")
+
+	unnamed := sinks["endpoint-2:55680"].Traces[len(sinks["endpoint-2:55680"].Traces)-1]
+	require.Equal(t, 1, unnamed.SpanCount())
+	_, found := unnamed.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	assert.False(t, found)
+}
+
+func TestConsumeMetrics(t *testing.T) {
+	// prepare
+	config := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+	}
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+	p, err := newExporter(params, config, configmodels.MetricsDataType)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	created := &mockExporters{}
+	p.exporterFactory = created.factory()
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	md := pdata.NewMetrics()
+	for _, host := range []string{"host-1", "host-2", "host-1"} {
+		md.ResourceMetrics().Append(resourceMetricsWithAttributes(map[string]string{"host.name": host}))
+	}
+
+	// test
+	err = p.ConsumeMetrics(context.Background(), md)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, 2, created.get("endpoint-1:55680").numBatches()+created.get("endpoint-2:55680").numBatches())
+}
+
+func TestConsumeLogs(t *testing.T) {
+	// prepare
+	config := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+	}
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+	p, err := newExporter(params, config, configmodels.LogsDataType)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	sinks := map[string]*componenttest.ExampleExporterConsumer{
+		"endpoint-1:55680": {},
+		"endpoint-2:55680": {},
+	}
+	for endpoint, sink := range sinks {
+		p.exporters[endpoint] = sink
+	}
+
+	// these trace IDs reach different endpoints -- see the consistent hashing tests for more info
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Append(resourceLogsWithTraceIDs("host-1",
+		pdata.NewTraceID([16]byte{1, 2, 0, 0}),
+		pdata.NewTraceID([16]byte{128, 128, 0, 0})))
+
+	// test
+	err = p.ConsumeLogs(context.Background(), ld)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, sinks["endpoint-1:55680"].Logs, 1)
+	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 0, 0}),
+		sinks["endpoint-1:55680"].Logs[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).TraceID())
+	require.Len(t, sinks["endpoint-2:55680"].Logs, 1)
+	assert.Equal(t, pdata.NewTraceID([16]byte{128, 128, 0, 0}),
+		sinks["endpoint-2:55680"].Logs[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).TraceID())
+}
+
+func TestBackendMetricsPerSignal(t *testing.T) {
+	// prepare
+	NewFactory() // registers the views
+
+	config := simpleConfig()
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+	p, err := newExporter(params, config, configmodels.LogsDataType)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	p.exporters["endpoint-1:55680"] = &componenttest.ExampleExporterConsumer{}
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Append(resourceLogsWithTraceIDs("host-1", pdata.InvalidTraceID()))
+	before := backendOutcomes(t, "endpoint-1:55680")
+
+	// test
+	err = p.ConsumeLogs(context.Background(), ld)
+
+	// verify
+	require.NoError(t, err)
+	after := backendOutcomes(t, "endpoint-1:55680")
+	assert.Equal(t, before["logs"]+1, after["logs"])
+	assert.Equal(t, before["traces"], after["traces"])
+}
+
+// backendOutcomes returns the number of successful sends to the endpoint, by signal
+func backendOutcomes(t *testing.T, endpoint string) map[string]int64 {
+	rows, err := view.RetrieveData("loadbalancer_backend_outcome")
+	require.NoError(t, err)
+
+	outcomes := map[string]int64{}
+	for _, row := range rows {
+		tags := map[string]string{}
+		for _, tg := range row.Tags {
+			tags[tg.Key.Name()] = tg.Value
+		}
+		if tags["endpoint"] == endpoint && tags["success"] == "true" {
+			outcomes[tags["signal"]] += row.Data.(*view.CountData).Value
+		}
+	}
+	return outcomes
+}
+
 func TestFailedExporterInRing(t *testing.T) {
 	// this test is based on the discussion in the original PR for this exporter:
 	// https://github.com/open-telemetry/opentelemetry-collector-contrib/pull/1542#discussion_r521268180
@@ -459,7 +696,7 @@ func TestFailedExporterInRing(t *testing.T) {
 	params := component.ExporterCreateParams{
 		Logger: zap.NewNop(),
 	}
-	p, err := newExporter(params, config)
+	p, err := newExporter(params, config, configmodels.TracesDataType)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -470,7 +707,7 @@ func TestFailedExporterInRing(t *testing.T) {
 	// this is a case that we are not even sure that might happen, so, this test case is here to document
 	// this behavior. As the solution would require more locks/syncs/checks, we should probably wait to see
 	// if this is really a problem in the real world
-	delete(p.exporters, "endpoint-2")
+	delete(p.exporters, "endpoint-2:55680")
 
 	// sanity check
 	require.Contains(t, p.res.(*staticResolver).endpoints, "endpoint-2")
@@ -484,6 +721,219 @@ func TestFailedExporterInRing(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestUnhealthyBackendFailover(t *testing.T) {
+	// prepare
+	NewFactory() // registers the views
+
+	config := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		BackendHealth: BackendHealthSettings{MaxConsecutiveFailures: 2, ProbeInterval: time.Minute},
+	}
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+	p, err := newExporter(params, config, configmodels.TracesDataType)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	now := time.Now()
+	p.health.now = func() time.Time { return now }
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	healthy := &failingExporter{}
+	failing := &failingExporter{err: errors.New("unavailable")}
+	p.exporters["endpoint-1:55680"] = healthy
+	p.exporters["endpoint-2:55680"] = failing
+
+	// this trace ID will reach the endpoint-2 -- see the consistent hashing tests for more info
+	traceForEndpoint2 := simpleTraceWithID(pdata.NewTraceID([16]byte{128, 128, 0, 0}))
+
+	// test and verify
+	assert.Error(t, p.ConsumeTraces(context.Background(), traceForEndpoint2))
+	assert.Equal(t, 0, healthy.batches)
+
+	// the second failure marks the endpoint unhealthy, and the batch is sent to the next endpoint
+	assert.NoError(t, p.ConsumeTraces(context.Background(), traceForEndpoint2))
+	assert.Equal(t, 1, healthy.batches)
+	assert.Equal(t, 2, failing.batches)
+	assert.Equal(t, int64(1), healthyBackends(t))
+
+	assert.NoError(t, p.ConsumeTraces(context.Background(), traceForEndpoint2))
+	assert.Equal(t, 2, healthy.batches)
+	assert.Equal(t, 2, failing.batches)
+
+	// once the probe interval elapsed, the endpoint receives a batch again, and is healthy once it succeeds
+	failing.err = nil
+	now = now.Add(time.Minute)
+	assert.NoError(t, p.ConsumeTraces(context.Background(), traceForEndpoint2))
+	assert.Equal(t, 2, healthy.batches)
+	assert.Equal(t, 3, failing.batches)
+	assert.True(t, p.health.isHealthy("endpoint-2:55680"))
+	assert.Equal(t, int64(2), healthyBackends(t))
+}
+
+func TestAllBackendsUnhealthy(t *testing.T) {
+	// prepare
+	config := simpleConfig()
+	config.BackendHealth = BackendHealthSettings{MaxConsecutiveFailures: 1, ProbeInterval: time.Minute}
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+	p, err := newExporter(params, config, configmodels.TracesDataType)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	failing := &failingExporter{err: errors.New("unavailable")}
+	p.exporters["endpoint-1:55680"] = failing
+
+	// test
+	for i := 0; i < 3; i++ {
+		assert.Error(t, p.ConsumeTraces(context.Background(), simpleTraces()))
+	}
+
+	// verify
+	assert.Equal(t, 3, failing.batches, "the data is still sent when no backend is healthy")
+}
+
+// healthyBackends returns the last number of healthy backends recorded for the traces
+func healthyBackends(t *testing.T) int64 {
+	rows, err := view.RetrieveData("loadbalancer_num_healthy_backends")
+	require.NoError(t, err)
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg.Key.Name() == "signal" && tg.Value == string(configmodels.TracesDataType) {
+				return int64(row.Data.(*view.LastValueData).Value)
+			}
+		}
+	}
+	return -1
+}
+
+func TestConsumeTracesNoBackends(t *testing.T) {
+	// prepare
+	config := simpleConfig()
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+	p, err := newExporter(params, config, configmodels.TracesDataType)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	p.res = &mockResolver{
+		triggerCallbacks: true,
+		onResolve: func(ctx context.Context) ([]string, error) {
+			return []string{}, nil
+		},
+	}
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	// test
+	err = p.ConsumeTraces(context.Background(), simpleTraces())
+
+	// verify
+	assert.Equal(t, errNoBackends, err)
+}
+
+func TestRingUpdateWaitsForPendingSends(t *testing.T) {
+	// prepare
+	config := simpleConfig()
+	params := component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}
+	p, err := newExporter(params, config, configmodels.TracesDataType)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	created := &mockExporters{}
+	p.exporterFactory = created.factory()
+	p.onBackendChanges([]string{"endpoint-1"})
+	first := created.get("endpoint-1:55680")
+	require.NotNil(t, first)
+	assert.True(t, first.isStarted())
+
+	// a send to the first endpoint is in progress
+	first.release = make(chan struct{})
+	sent := make(chan error)
+	go func() {
+		sent <- p.ConsumeTraces(context.Background(), simpleTraces())
+	}()
+	<-first.consuming
+
+	// test
+	changed := make(chan struct{})
+	go func() {
+		p.onBackendChanges([]string{"endpoint-2"})
+		close(changed)
+	}()
+
+	// verify
+	// the exporter of the new endpoint is started before the ring is updated
+	assert.Eventually(t, func() bool {
+		second := created.get("endpoint-2:55680")
+		return second != nil && second.isStarted()
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case <-changed:
+		t.Fatal("the ring was updated while a send was in progress")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.False(t, first.isShutdown())
+
+	close(first.release)
+	require.NoError(t, <-sent)
+	<-changed
+
+	assert.True(t, first.isShutdown())
+	assert.Equal(t, 1, first.numBatches())
+	assert.Len(t, p.exporters, 1)
+	assert.Contains(t, p.exporters, "endpoint-2:55680")
+}
+
+func TestShutdownStopsExporters(t *testing.T) {
+	for _, dataType := range []configmodels.DataType{
+		configmodels.TracesDataType,
+		configmodels.MetricsDataType,
+		configmodels.LogsDataType,
+	} {
+		t.Run(string(dataType), func(t *testing.T) {
+			// prepare
+			config := simpleConfig()
+			params := component.ExporterCreateParams{
+				Logger: zap.NewNop(),
+			}
+			p, err := newExporter(params, config, dataType)
+			require.NotNil(t, p)
+			require.NoError(t, err)
+
+			created := &mockExporters{}
+			p.exporterFactory = created.factory()
+			err = p.Start(context.Background(), componenttest.NewNopHost())
+			require.NoError(t, err)
+			exp := created.get("endpoint-1:55680")
+			require.NotNil(t, exp)
+			assert.Equal(t, dataType, exp.dataType)
+
+			// test
+			err = p.Shutdown(context.Background())
+
+			// verify
+			assert.NoError(t, err)
+			assert.True(t, exp.isShutdown())
+			assert.Len(t, p.exporters, 0)
+		})
+	}
+}
+
 func TestNoTracesInBatch(t *testing.T) {
 	for _, tt := range []struct {
 		desc  string
@@ -537,6 +987,139 @@ func simpleTraceWithID(id pdata.TraceID) pdata.Traces {
 	return traces
 }
 
+// mockExporter records the batches it receives, the sends blocking until release is closed when set
+type mockExporter struct {
+	sync.Mutex
+	dataType  configmodels.DataType
+	started   bool
+	shutdown  bool
+	traces    int
+	consuming chan struct{}
+	release   chan struct{}
+}
+
+func (e *mockExporter) Start(context.Context, component.Host) error {
+	e.Lock()
+	defer e.Unlock()
+	e.started = true
+	return nil
+}
+
+func (e *mockExporter) isStarted() bool {
+	e.Lock()
+	defer e.Unlock()
+	return e.started
+}
+
+func (e *mockExporter) Shutdown(context.Context) error {
+	e.Lock()
+	defer e.Unlock()
+	e.shutdown = true
+	return nil
+}
+
+func (e *mockExporter) ConsumeTraces(context.Context, pdata.Traces) error {
+	return e.consume()
+}
+
+func (e *mockExporter) ConsumeMetrics(context.Context, pdata.Metrics) error {
+	return e.consume()
+}
+
+func (e *mockExporter) ConsumeLogs(context.Context, pdata.Logs) error {
+	return e.consume()
+}
+
+func (e *mockExporter) consume() error {
+	e.consuming <- struct{}{}
+	if e.release != nil {
+		<-e.release
+	}
+
+	e.Lock()
+	defer e.Unlock()
+	e.traces++
+	return nil
+}
+
+func (e *mockExporter) isShutdown() bool {
+	e.Lock()
+	defer e.Unlock()
+	return e.shutdown
+}
+
+func (e *mockExporter) numBatches() int {
+	e.Lock()
+	defer e.Unlock()
+	return e.traces
+}
+
+// failingExporter counts the traces batches it receives, returning the given error
+type failingExporter struct {
+	err     error
+	batches int
+}
+
+func (e *failingExporter) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (e *failingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func (e *failingExporter) ConsumeTraces(context.Context, pdata.Traces) error {
+	e.batches++
+	return e.err
+}
+
+// mockExporters records the mock exporters created by its factory, by endpoint
+type mockExporters struct {
+	sync.Mutex
+	byEndpoint map[string]*mockExporter
+}
+
+func (m *mockExporters) get(endpoint string) *mockExporter {
+	m.Lock()
+	defer m.Unlock()
+	return m.byEndpoint[endpoint]
+}
+
+func (m *mockExporters) factory() component.ExporterFactory {
+	return exporterhelper.NewFactory("otlp", func() configmodels.Exporter {
+		return &otlpexporter.Config{}
+	}, exporterhelper.WithTraces(func(
+		_ context.Context,
+		_ component.ExporterCreateParams,
+		cfg configmodels.Exporter,
+	) (component.TracesExporter, error) {
+		return m.create(cfg, configmodels.TracesDataType), nil
+	}), exporterhelper.WithMetrics(func(
+		_ context.Context,
+		_ component.ExporterCreateParams,
+		cfg configmodels.Exporter,
+	) (component.MetricsExporter, error) {
+		return m.create(cfg, configmodels.MetricsDataType), nil
+	}), exporterhelper.WithLogs(func(
+		_ context.Context,
+		_ component.ExporterCreateParams,
+		cfg configmodels.Exporter,
+	) (component.LogsExporter, error) {
+		return m.create(cfg, configmodels.LogsDataType), nil
+	}))
+}
+
+func (m *mockExporters) create(cfg configmodels.Exporter, dataType configmodels.DataType) *mockExporter {
+	m.Lock()
+	defer m.Unlock()
+	if m.byEndpoint == nil {
+		m.byEndpoint = map[string]*mockExporter{}
+	}
+	exp := &mockExporter{dataType: dataType, consuming: make(chan struct{}, 10)}
+	m.byEndpoint[cfg.(*otlpexporter.Config).Endpoint] = exp
+	return exp
+}
+
 func simpleConfig() *Config {
 	return &Config{
 		Resolver: ResolverSettings{
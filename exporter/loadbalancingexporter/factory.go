@@ -37,6 +37,8 @@ func NewFactory() component.ExporterFactory {
 		typeStr,
 		createDefaultConfig,
 		exporterhelper.WithTraces(createTraceExporter),
+		exporterhelper.WithMetrics(createMetricsExporter),
+		exporterhelper.WithLogs(createLogsExporter),
 	)
 }
 
@@ -52,9 +54,22 @@ func createDefaultConfig() configmodels.Exporter {
 		Protocol: Protocol{
 			OTLP: *otlpDefaultCfg,
 		},
+		RoutingKey: traceIDRoutingKey,
+		BackendHealth: BackendHealthSettings{
+			MaxConsecutiveFailures: defaultMaxConsecutiveFailures,
+			ProbeInterval:          defaultProbeInterval,
+		},
 	}
 }
 
 func createTraceExporter(_ context.Context, params component.ExporterCreateParams, cfg configmodels.Exporter) (component.TracesExporter, error) {
-	return newExporter(params, cfg)
+	return newExporter(params, cfg, configmodels.TracesDataType)
+}
+
+func createMetricsExporter(_ context.Context, params component.ExporterCreateParams, cfg configmodels.Exporter) (component.MetricsExporter, error) {
+	return newExporter(params, cfg, configmodels.MetricsDataType)
+}
+
+func createLogsExporter(_ context.Context, params component.ExporterCreateParams, cfg configmodels.Exporter) (component.LogsExporter, error) {
+	return newExporter(params, cfg, configmodels.LogsDataType)
 }
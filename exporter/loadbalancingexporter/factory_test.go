@@ -44,4 +44,18 @@ func TestProcessorGetsCreatedWithValidConfiguration(t *testing.T) {
 	// verify
 	assert.Nil(t, err)
 	assert.NotNil(t, exp)
+
+	// test
+	metricsExp, err := factory.CreateMetricsExporter(context.Background(), creationParams, cfg)
+
+	// verify
+	assert.Nil(t, err)
+	assert.NotNil(t, metricsExp)
+
+	// test
+	logsExp, err := factory.CreateLogsExporter(context.Background(), creationParams, cfg)
+
+	// verify
+	assert.Nil(t, err)
+	assert.NotNil(t, logsExp)
 }
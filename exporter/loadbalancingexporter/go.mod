@@ -3,11 +3,17 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadba
 go 1.14
 
 require (
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig v0.0.0-00010101000000-000000000000
 	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpertrace v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.6.1
 	go.opencensus.io v0.22.5
 	go.opentelemetry.io/collector v0.18.0
 	go.uber.org/zap v1.16.0
+	k8s.io/api v0.20.1
+	k8s.io/apimachinery v0.20.1
+	k8s.io/client-go v0.20.1
 )
 
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig => ../../internal/k8sconfig
+
 replace github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpertrace => ../../pkg/batchpertrace
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxConsecutiveFailures = 5
+	defaultProbeInterval          = 10 * time.Second
+)
+
+// backendHealth tracks the consecutive send failures of the backends. A backend is unhealthy once it failed
+// maxFailures times in a row: its data is routed to the next backends of the ring, but for a batch sent to it
+// every probeInterval to check whether it recovered. A successful send makes it healthy again.
+type backendHealth struct {
+	maxFailures   int
+	probeInterval time.Duration
+	now           func() time.Time
+
+	lock     sync.Mutex
+	backends map[string]*backendState
+}
+
+type backendState struct {
+	failures  int
+	unhealthy bool
+	nextProbe time.Time
+}
+
+func newBackendHealth(settings BackendHealthSettings) *backendHealth {
+	probeInterval := settings.ProbeInterval
+	if probeInterval <= 0 {
+		probeInterval = defaultProbeInterval
+	}
+	return &backendHealth{
+		maxFailures:   settings.MaxConsecutiveFailures,
+		probeInterval: probeInterval,
+		now:           time.Now,
+		backends:      map[string]*backendState{},
+	}
+}
+
+// available returns whether data can be sent to the endpoint: when it's healthy, or when it's time to probe it, in
+// which case the next probe is scheduled, only one batch being sent to it in each interval
+func (h *backendHealth) available(endpoint string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	state, found := h.backends[endpoint]
+	if !found || !state.unhealthy {
+		return true
+	}
+	now := h.now()
+	if now.Before(state.nextProbe) {
+		return false
+	}
+	state.nextProbe = now.Add(h.probeInterval)
+	return true
+}
+
+// record records the outcome of a send to the endpoint, and returns whether the endpoint became healthy or unhealthy
+func (h *backendHealth) record(endpoint string, err error) bool {
+	if h.maxFailures <= 0 {
+		return false
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	state, found := h.backends[endpoint]
+	if !found {
+		state = &backendState{}
+		h.backends[endpoint] = state
+	}
+
+	if err == nil {
+		changed := state.unhealthy
+		state.failures = 0
+		state.unhealthy = false
+		return changed
+	}
+
+	state.failures++
+	if state.unhealthy || state.failures < h.maxFailures {
+		return false
+	}
+	state.unhealthy = true
+	state.nextProbe = h.now().Add(h.probeInterval)
+	return true
+}
+
+// isHealthy returns whether the endpoint isn't marked as unhealthy
+func (h *backendHealth) isHealthy(endpoint string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	state, found := h.backends[endpoint]
+	return !found || !state.unhealthy
+}
+
+// update forgets the state of the endpoints which aren't in the ring anymore, and returns the number of healthy
+// endpoints among the given ones
+func (h *backendHealth) update(endpoints []string) int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	current := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		current[endpoint] = true
+	}
+	for endpoint := range h.backends {
+		if !current[endpoint] {
+			delete(h.backends, endpoint)
+		}
+	}
+
+	healthy := 0
+	for endpoint := range current {
+		if state, found := h.backends[endpoint]; !found || !state.unhealthy {
+			healthy++
+		}
+	}
+	return healthy
+}
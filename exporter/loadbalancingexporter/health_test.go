@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackendHealth(t *testing.T) {
+	// prepare
+	now := time.Now()
+	h := newBackendHealth(BackendHealthSettings{MaxConsecutiveFailures: 2, ProbeInterval: time.Minute})
+	h.now = func() time.Time { return now }
+	errSend := errors.New("unavailable")
+
+	// test and verify
+	assert.False(t, h.record("endpoint-1:55680", errSend))
+	assert.True(t, h.available("endpoint-1:55680"))
+	assert.False(t, h.record("endpoint-1:55680", nil), "a success resets the failures")
+	assert.False(t, h.record("endpoint-1:55680", errSend))
+	assert.True(t, h.record("endpoint-1:55680", errSend), "the endpoint failed twice in a row")
+	assert.False(t, h.isHealthy("endpoint-1:55680"))
+	assert.False(t, h.available("endpoint-1:55680"))
+	assert.Equal(t, 1, h.update([]string{"endpoint-1:55680", "endpoint-2:55680"}))
+
+	now = now.Add(time.Minute)
+	assert.True(t, h.available("endpoint-1:55680"), "the endpoint is probed")
+	assert.False(t, h.available("endpoint-1:55680"), "only one batch probes the endpoint")
+	assert.False(t, h.record("endpoint-1:55680", errSend), "the probe failed")
+
+	now = now.Add(time.Minute)
+	assert.True(t, h.available("endpoint-1:55680"))
+	assert.True(t, h.record("endpoint-1:55680", nil), "the probe succeeded")
+	assert.True(t, h.isHealthy("endpoint-1:55680"))
+	assert.Equal(t, 2, h.update([]string{"endpoint-1:55680", "endpoint-2:55680"}))
+}
+
+func TestBackendHealthDisabled(t *testing.T) {
+	// prepare
+	h := newBackendHealth(BackendHealthSettings{})
+
+	// test
+	for i := 0; i < 10; i++ {
+		assert.False(t, h.record("endpoint-1:55680", errors.New("unavailable")))
+	}
+
+	// verify
+	assert.True(t, h.isHealthy("endpoint-1:55680"))
+	assert.True(t, h.available("endpoint-1:55680"))
+}
+
+func TestBackendHealthUpdateForgetsRemovedEndpoints(t *testing.T) {
+	// prepare
+	h := newBackendHealth(BackendHealthSettings{MaxConsecutiveFailures: 1})
+	h.record("endpoint-1:55680", errors.New("unavailable"))
+
+	// test
+	healthy := h.update([]string{"endpoint-2:55680"})
+
+	// verify
+	assert.Equal(t, 1, healthy)
+	assert.Empty(t, h.backends)
+}
@@ -23,7 +23,9 @@ import (
 var (
 	mNumResolutions = stats.Int64("loadbalancer_num_resolutions", "Number of times the resolver triggered a new resolutions", stats.UnitDimensionless)
 	mNumBackends    = stats.Int64("loadbalancer_num_backends", "Current number of backends in use", stats.UnitDimensionless)
+	mNumRingUpdates = stats.Int64("loadbalancer_num_ring_updates", "Number of times the ring was updated with a new list of backends", stats.UnitDimensionless)
 	mBackendLatency = stats.Int64("loadbalancer_backend_latency", "Response latency in ms for the backends", stats.UnitMilliseconds)
+	mNumHealthy     = stats.Int64("loadbalancer_num_healthy_backends", "Current number of backends not marked unhealthy", stats.UnitDimensionless)
 )
 
 // MetricViews return the metrics views according to given telemetry level.
@@ -63,6 +65,7 @@ func MetricViews() []*view.View {
 			Description: mBackendLatency.Description(),
 			TagKeys: []tag.Key{
 				tag.MustNewKey("endpoint"),
+				tag.MustNewKey("signal"),
 			},
 			Aggregation: view.Distribution(0, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000),
 		},
@@ -72,9 +75,25 @@ func MetricViews() []*view.View {
 			Description: "Number of success/failures for each endpoint",
 			TagKeys: []tag.Key{
 				tag.MustNewKey("endpoint"),
+				tag.MustNewKey("signal"),
 				tag.MustNewKey("success"),
 			},
 			Aggregation: view.Count(),
 		},
+		{
+			Name:        mNumRingUpdates.Name(),
+			Measure:     mNumRingUpdates,
+			Description: mNumRingUpdates.Description(),
+			Aggregation: view.Count(),
+		},
+		{
+			Name:        mNumHealthy.Name(),
+			Measure:     mNumHealthy,
+			Description: mNumHealthy.Description(),
+			Aggregation: view.LastValue(),
+			TagKeys: []tag.Key{
+				tag.MustNewKey("signal"),
+			},
+		},
 	}
 }
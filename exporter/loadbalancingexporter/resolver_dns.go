@@ -59,18 +59,24 @@ type netResolver interface {
 	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
 }
 
-func newDNSResolver(logger *zap.Logger, hostname string, port string) (*dnsResolver, error) {
+func newDNSResolver(logger *zap.Logger, hostname string, port string, interval time.Duration, timeout time.Duration) (*dnsResolver, error) {
 	if len(hostname) == 0 {
 		return nil, errNoHostname
 	}
+	if interval == 0 {
+		interval = defaultResInterval
+	}
+	if timeout == 0 {
+		timeout = defaultResTimeout
+	}
 
 	return &dnsResolver{
 		logger:      logger,
 		hostname:    hostname,
 		port:        port,
 		resolver:    &net.Resolver{},
-		resInterval: defaultResInterval,
-		resTimeout:  defaultResTimeout,
+		resInterval: interval,
+		resTimeout:  timeout,
 		stopCh:      make(chan struct{}),
 	}, nil
 }
@@ -29,7 +29,7 @@ import (
 
 func TestInitialDNSResolution(t *testing.T) {
 	// prepare
-	res, err := newDNSResolver(zap.NewNop(), "service-1", "")
+	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 0, 0)
 	require.NoError(t, err)
 
 	res.resolver = &mockDNSResolver{
@@ -59,7 +59,7 @@ func TestInitialDNSResolution(t *testing.T) {
 
 func TestInitialDNSResolutionWithPort(t *testing.T) {
 	// prepare
-	res, err := newDNSResolver(zap.NewNop(), "service-1", "55690")
+	res, err := newDNSResolver(zap.NewNop(), "service-1", "55690", 0, 0)
 	require.NoError(t, err)
 
 	res.resolver = &mockDNSResolver{
@@ -89,16 +89,34 @@ func TestInitialDNSResolutionWithPort(t *testing.T) {
 
 func TestErrNoHostname(t *testing.T) {
 	// test
-	res, err := newDNSResolver(zap.NewNop(), "", "")
+	res, err := newDNSResolver(zap.NewNop(), "", "", 0, 0)
 
 	// verify
 	assert.Nil(t, res)
 	assert.Equal(t, errNoHostname, err)
 }
 
+func TestDNSResolverIntervalAndTimeout(t *testing.T) {
+	// test
+	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 30*time.Second, 2*time.Second)
+	require.NoError(t, err)
+
+	// verify
+	assert.Equal(t, 30*time.Second, res.resInterval)
+	assert.Equal(t, 2*time.Second, res.resTimeout)
+
+	// test
+	res, err = newDNSResolver(zap.NewNop(), "service-1", "", 0, 0)
+	require.NoError(t, err)
+
+	// verify
+	assert.Equal(t, defaultResInterval, res.resInterval)
+	assert.Equal(t, defaultResTimeout, res.resTimeout)
+}
+
 func TestCantResolve(t *testing.T) {
 	// prepare
-	res, err := newDNSResolver(zap.NewNop(), "service-1", "")
+	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 0, 0)
 	require.NoError(t, err)
 
 	expectedErr := errors.New("some expected error")
@@ -117,7 +135,7 @@ func TestCantResolve(t *testing.T) {
 
 func TestOnChange(t *testing.T) {
 	// prepare
-	res, err := newDNSResolver(zap.NewNop(), "service-1", "")
+	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 0, 0)
 	require.NoError(t, err)
 
 	resolve := []net.IPAddr{
@@ -180,7 +198,7 @@ func TestEqualStringSlice(t *testing.T) {
 
 func TestPeriodicallyResolve(t *testing.T) {
 	// prepare
-	res, err := newDNSResolver(zap.NewNop(), "service-1", "")
+	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 0, 0)
 	require.NoError(t, err)
 
 	counter := 0
@@ -237,7 +255,7 @@ func TestPeriodicallyResolve(t *testing.T) {
 
 func TestPeriodicallyResolveFailure(t *testing.T) {
 	// prepare
-	res, err := newDNSResolver(zap.NewNop(), "service-1", "")
+	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 0, 0)
 	require.NoError(t, err)
 
 	expectedErr := errors.New("some expected error")
@@ -279,7 +297,7 @@ func TestPeriodicallyResolveFailure(t *testing.T) {
 
 func TestShutdownClearsCallbacks(t *testing.T) {
 	// prepare
-	res, err := newDNSResolver(zap.NewNop(), "service-1", "")
+	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 0, 0)
 	require.NoError(t, err)
 
 	res.resolver = &mockDNSResolver{}
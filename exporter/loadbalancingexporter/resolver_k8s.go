@@ -0,0 +1,211 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+)
+
+var _ resolver = (*k8sResolver)(nil)
+
+const (
+	defaultK8sNamespace   = "default"
+	defaultK8sSyncTimeout = 10 * time.Second
+)
+
+var errNoSvc = errors.New("no service specified to resolve the backends")
+
+// k8sResolver watches the endpoints of a Kubernetes service, the backends being the ready addresses of the
+// endpoints, e.g. the pods of a headless service
+type k8sResolver struct {
+	logger *zap.Logger
+
+	name        string
+	namespace   string
+	port        string
+	newClient   func() (kubernetes.Interface, error)
+	syncTimeout time.Duration
+
+	endpoints         []string
+	onChangeCallbacks []func([]string)
+
+	stopCh             chan struct{}
+	updateLock         sync.Mutex
+	changeCallbackLock sync.RWMutex
+}
+
+func newK8sResolver(logger *zap.Logger, cfg K8sSvcResolver) (*k8sResolver, error) {
+	if len(cfg.Service) == 0 {
+		return nil, errNoSvc
+	}
+
+	name, namespace := cfg.Service, defaultK8sNamespace
+	if i := strings.Index(cfg.Service, "."); i >= 0 {
+		name, namespace = cfg.Service[:i], cfg.Service[i+1:]
+	}
+
+	port := cfg.Port
+	if port == "" {
+		port = defaultPort
+	}
+
+	apiCfg := cfg.APIConfig
+	if apiCfg.AuthType == "" {
+		apiCfg.AuthType = k8sconfig.AuthTypeServiceAccount
+	}
+	if err := apiCfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &k8sResolver{
+		logger:      logger,
+		name:        name,
+		namespace:   namespace,
+		port:        port,
+		newClient:   func() (kubernetes.Interface, error) { return k8sconfig.MakeClient(apiCfg) },
+		syncTimeout: defaultK8sSyncTimeout,
+		stopCh:      make(chan struct{}),
+	}, nil
+}
+
+func (r *k8sResolver) start(ctx context.Context) error {
+	client, err := r.newClient()
+	if err != nil {
+		return err
+	}
+
+	// only the endpoints of the service are watched
+	selector := fields.OneTermEqualSelector("metadata.name", r.name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return client.CoreV1().Endpoints(r.namespace).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return client.CoreV1().Endpoints(r.namespace).Watch(context.Background(), options)
+		},
+	}
+	informer := cache.NewSharedInformer(lw, &corev1.Endpoints{}, 0)
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    r.handleEndpoints,
+		UpdateFunc: func(_, obj interface{}) { r.handleEndpoints(obj) },
+		DeleteFunc: func(interface{}) { r.update(nil) },
+	})
+	go informer.Run(r.stopCh)
+
+	syncCtx, cancel := context.WithTimeout(ctx, r.syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync the endpoints of the service %s.%s", r.name, r.namespace)
+	}
+
+	// the handlers are notified asynchronously, so the backends are resolved before starting the exporter
+	for _, obj := range informer.GetStore().List() {
+		r.handleEndpoints(obj)
+	}
+	return nil
+}
+
+func (r *k8sResolver) shutdown(ctx context.Context) error {
+	r.changeCallbackLock.Lock()
+	r.onChangeCallbacks = nil
+	r.changeCallbackLock.Unlock()
+
+	close(r.stopCh)
+	return nil
+}
+
+// resolve returns the backends of the last version of the endpoints, the endpoints being watched
+func (r *k8sResolver) resolve(context.Context) ([]string, error) {
+	r.updateLock.Lock()
+	defer r.updateLock.Unlock()
+	return r.endpoints, nil
+}
+
+func (r *k8sResolver) handleEndpoints(obj interface{}) {
+	endpoints, ok := obj.(*corev1.Endpoints)
+	if !ok || endpoints.Name != r.name {
+		return
+	}
+
+	// the addresses are listed in each subset of their ports, the port to use being the configured one
+	found := map[string]bool{}
+	var backends []string
+	for _, subset := range endpoints.Subsets {
+		for _, addr := range subset.Addresses {
+			backend := net.JoinHostPort(addr.IP, r.port)
+			if !found[backend] {
+				found[backend] = true
+				backends = append(backends, backend)
+			}
+		}
+	}
+
+	// keep it always in the same order
+	sort.Strings(backends)
+	r.update(backends)
+}
+
+func (r *k8sResolver) update(backends []string) {
+	// the context to use for all metrics in this function
+	mCtx, _ := tag.New(context.Background(), tag.Upsert(tag.MustNewKey("resolver"), "k8s"))
+	successCtx, _ := tag.New(mCtx, tag.Upsert(tag.MustNewKey("success"), "true"))
+	stats.Record(successCtx, mNumResolutions.M(1))
+
+	r.updateLock.Lock()
+	if equalStringSlice(r.endpoints, backends) {
+		r.updateLock.Unlock()
+		return
+	}
+	r.endpoints = backends
+	r.updateLock.Unlock()
+	stats.Record(mCtx, mNumBackends.M(int64(len(backends))))
+
+	r.logger.Debug("the endpoints of the service changed", zap.Strings("backends", backends))
+
+	// propagate the change
+	r.changeCallbackLock.RLock()
+	for _, callback := range r.onChangeCallbacks {
+		callback(backends)
+	}
+	r.changeCallbackLock.RUnlock()
+}
+
+func (r *k8sResolver) onChange(f func([]string)) {
+	r.changeCallbackLock.Lock()
+	defer r.changeCallbackLock.Unlock()
+	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
+}
@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestK8sResolver(t *testing.T, service string, port string, objects ...*corev1.Endpoints) (*k8sResolver, *fake.Clientset) {
+	client := fake.NewSimpleClientset()
+	for _, obj := range objects {
+		_, err := client.CoreV1().Endpoints(obj.Namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	res, err := newK8sResolver(zap.NewNop(), K8sSvcResolver{Service: service, Port: port})
+	require.NoError(t, err)
+	res.newClient = func() (kubernetes.Interface, error) {
+		return client, nil
+	}
+	return res, client
+}
+
+func testEndpoints(namespace string, name string, ready []string, notReady []string) *corev1.Endpoints {
+	subset := corev1.EndpointSubset{
+		Ports: []corev1.EndpointPort{{Name: "otlp", Port: 4317}},
+	}
+	for _, ip := range ready {
+		subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+	}
+	for _, ip := range notReady {
+		subset.NotReadyAddresses = append(subset.NotReadyAddresses, corev1.EndpointAddress{IP: ip})
+	}
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Subsets:    []corev1.EndpointSubset{subset},
+	}
+}
+
+// recordedBackends records the backends the resolver notifies of
+type recordedBackends struct {
+	sync.Mutex
+	updates [][]string
+}
+
+func (r *recordedBackends) onChange(backends []string) {
+	r.Lock()
+	defer r.Unlock()
+	r.updates = append(r.updates, backends)
+}
+
+func (r *recordedBackends) last() []string {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.updates) == 0 {
+		return nil
+	}
+	return r.updates[len(r.updates)-1]
+}
+
+func (r *recordedBackends) count() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.updates)
+}
+
+func TestInitialK8sResolution(t *testing.T) {
+	// prepare
+	res, _ := newTestK8sResolver(t, "lb.observability", "55690",
+		testEndpoints("observability", "lb", []string{"10.0.0.2", "10.0.0.1", "fd00::1"}, []string{"10.0.0.3"}),
+		testEndpoints("observability", "other", []string{"10.0.1.1"}, nil),
+		testEndpoints("default", "lb", []string{"10.0.2.1"}, nil),
+	)
+
+	// test
+	recorded := &recordedBackends{}
+	res.onChange(recorded.onChange)
+	require.NoError(t, res.start(context.Background()))
+	defer res.shutdown(context.Background())
+
+	// verify
+	expected := []string{"10.0.0.1:55690", "10.0.0.2:55690", "[fd00::1]:55690"}
+	assert.Equal(t, expected, recorded.last())
+	resolved, err := res.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, resolved)
+}
+
+func TestK8sResolutionDefaults(t *testing.T) {
+	// prepare
+	res, _ := newTestK8sResolver(t, "lb", "",
+		testEndpoints("default", "lb", []string{"10.0.0.1"}, nil),
+	)
+
+	// test
+	recorded := &recordedBackends{}
+	res.onChange(recorded.onChange)
+	require.NoError(t, res.start(context.Background()))
+	defer res.shutdown(context.Background())
+
+	// verify
+	assert.Equal(t, "default", res.namespace)
+	assert.Equal(t, []string{"10.0.0.1:55680"}, recorded.last())
+}
+
+func TestK8sResolutionUpdates(t *testing.T) {
+	// prepare
+	res, client := newTestK8sResolver(t, "lb.observability", "",
+		testEndpoints("observability", "lb", []string{"10.0.0.1"}, nil),
+	)
+	recorded := &recordedBackends{}
+	res.onChange(recorded.onChange)
+	require.NoError(t, res.start(context.Background()))
+	defer res.shutdown(context.Background())
+	require.Equal(t, 1, recorded.count())
+
+	// test
+	endpoints := client.CoreV1().Endpoints("observability")
+	_, err := endpoints.Update(context.Background(),
+		testEndpoints("observability", "lb", []string{"10.0.0.1", "10.0.0.2"}, nil), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"10.0.0.1:55680", "10.0.0.2:55680"}, recorded.last())
+	}, time.Second, 10*time.Millisecond)
+
+	// test
+	// a pod becoming not ready is removed from the backends
+	_, err = endpoints.Update(context.Background(),
+		testEndpoints("observability", "lb", []string{"10.0.0.2"}, []string{"10.0.0.1"}), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"10.0.0.2:55680"}, recorded.last())
+	}, time.Second, 10*time.Millisecond)
+
+	// test
+	err = endpoints.Delete(context.Background(), "lb", metav1.DeleteOptions{})
+	require.NoError(t, err)
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return recorded.count() == 4 && len(recorded.last()) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestK8sResolutionIgnoresUnchangedEndpoints(t *testing.T) {
+	// prepare
+	res, _ := newTestK8sResolver(t, "lb", "")
+	recorded := &recordedBackends{}
+	res.onChange(recorded.onChange)
+
+	// test
+	res.handleEndpoints(testEndpoints("default", "lb", []string{"10.0.0.1"}, nil))
+	res.handleEndpoints(testEndpoints("default", "lb", []string{"10.0.0.1"}, []string{"10.0.0.2"}))
+	res.handleEndpoints(testEndpoints("default", "other", []string{"10.0.0.3"}, nil))
+
+	// verify
+	assert.Equal(t, 1, recorded.count())
+	assert.Equal(t, []string{"10.0.0.1:55680"}, recorded.last())
+}
+
+func TestErrNoSvc(t *testing.T) {
+	// test
+	res, err := newK8sResolver(zap.NewNop(), K8sSvcResolver{})
+
+	// verify
+	assert.Nil(t, res)
+	assert.Equal(t, errNoSvc, err)
+}
+
+func TestK8sResolverInvalidAuthType(t *testing.T) {
+	// prepare
+	cfg := K8sSvcResolver{Service: "lb"}
+	cfg.AuthType = "unknown"
+
+	// test
+	res, err := newK8sResolver(zap.NewNop(), cfg)
+
+	// verify
+	assert.Nil(t, res)
+	assert.EqualError(t, err, "invalid authType for kubernetes: unknown")
+}
+
+func TestK8sShutdownClearsCallbacks(t *testing.T) {
+	// prepare
+	res, _ := newTestK8sResolver(t, "lb", "")
+	res.onChange(func(_ []string) {})
+	require.NoError(t, res.start(context.Background()))
+
+	// test
+	err := res.shutdown(context.Background())
+
+	// verify
+	assert.NoError(t, err)
+	assert.Len(t, res.onChangeCallbacks, 0)
+}
@@ -0,0 +1,208 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// serviceBatch holds the resource spans of a service
+type serviceBatch struct {
+	service string
+	traces  pdata.Traces
+}
+
+// metricsBatch holds the resource metrics routed with the same key
+type metricsBatch struct {
+	key     string
+	metrics pdata.Metrics
+}
+
+// logsBatch holds the log records routed with the same key
+type logsBatch struct {
+	key  string
+	logs pdata.Logs
+}
+
+// splitByServiceName groups the resource spans by the service name of their resource, in the order in which the
+// services are first seen, and returns the resource spans without a service name apart. The resource spans are
+// shared with the given batch instead of being copied.
+func splitByServiceName(td pdata.Traces) ([]serviceBatch, pdata.Traces) {
+	var batches []serviceBatch
+	indexes := map[string]int{}
+	unnamed := pdata.NewTraces()
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+
+		service := serviceName(rs.Resource())
+		if service == "" {
+			unnamed.ResourceSpans().Append(rs)
+			continue
+		}
+
+		idx, found := indexes[service]
+		if !found {
+			idx = len(batches)
+			indexes[service] = idx
+			batches = append(batches, serviceBatch{service: service, traces: pdata.NewTraces()})
+		}
+		batches[idx].traces.ResourceSpans().Append(rs)
+	}
+
+	return batches, unnamed
+}
+
+// splitMetrics groups the resource metrics by the hash of the attributes of their resource or, when routing by
+// service, by the service name of their resource when it's set. The resource metrics are shared with the given
+// batch instead of being copied.
+func splitMetrics(md pdata.Metrics, byService bool) []metricsBatch {
+	var batches []metricsBatch
+	indexes := map[string]int{}
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+
+		key := ""
+		if byService {
+			key = serviceName(rm.Resource())
+		}
+		if key == "" {
+			key = resourceKey(rm.Resource())
+		}
+
+		idx, found := indexes[key]
+		if !found {
+			idx = len(batches)
+			indexes[key] = idx
+			batches = append(batches, metricsBatch{key: key, metrics: pdata.NewMetrics()})
+		}
+		batches[idx].metrics.ResourceMetrics().Append(rm)
+	}
+
+	return batches
+}
+
+// splitLogs groups the log records by their trace ID, so that they are sent to the same backend as the spans of
+// the trace, and the log records without a trace ID by the hash of the attributes of their resource. The resource
+// logs without any trace ID are shared with the given batch, the others being split into new resource logs
+// sharing the log records.
+func splitLogs(ld pdata.Logs) []logsBatch {
+	var batches []logsBatch
+	indexes := map[string]int{}
+	batchFor := func(key string) pdata.Logs {
+		idx, found := indexes[key]
+		if !found {
+			idx = len(batches)
+			indexes[key] = idx
+			batches = append(batches, logsBatch{key: key, logs: pdata.NewLogs()})
+		}
+		return batches[idx].logs
+	}
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resKey := resourceKey(rl.Resource())
+
+		if !hasTraceID(rl) {
+			batchFor(resKey).ResourceLogs().Append(rl)
+			continue
+		}
+
+		resourceLogs := map[string]pdata.ResourceLogs{}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+
+			libraryLogs := map[string]pdata.InstrumentationLibraryLogs{}
+			for k := 0; k < ill.Logs().Len(); k++ {
+				lr := ill.Logs().At(k)
+
+				key := resKey
+				if traceID := lr.TraceID(); traceID.IsValid() {
+					b := traceID.Bytes()
+					key = string(b[:])
+				}
+
+				newILL, found := libraryLogs[key]
+				if !found {
+					newRL, found := resourceLogs[key]
+					if !found {
+						newRL = pdata.NewResourceLogs()
+						rl.Resource().CopyTo(newRL.Resource())
+						resourceLogs[key] = newRL
+						batchFor(key).ResourceLogs().Append(newRL)
+					}
+
+					newILL = pdata.NewInstrumentationLibraryLogs()
+					ill.InstrumentationLibrary().CopyTo(newILL.InstrumentationLibrary())
+					newRL.InstrumentationLibraryLogs().Append(newILL)
+					libraryLogs[key] = newILL
+				}
+				newILL.Logs().Append(lr)
+			}
+		}
+	}
+
+	return batches
+}
+
+func hasTraceID(rl pdata.ResourceLogs) bool {
+	ills := rl.InstrumentationLibraryLogs()
+	for i := 0; i < ills.Len(); i++ {
+		logs := ills.At(i).Logs()
+		for j := 0; j < logs.Len(); j++ {
+			if logs.At(j).TraceID().IsValid() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func serviceName(res pdata.Resource) string {
+	if attr, ok := res.Attributes().Get(conventions.AttributeServiceName); ok {
+		return attr.StringVal()
+	}
+	return ""
+}
+
+// resourceKey returns a key identifying the resource by its attributes, whatever their order
+func resourceKey(res pdata.Resource) string {
+	attrs := res.Attributes()
+	keys := make([]string, 0, attrs.Len())
+	attrs.ForEach(func(k string, _ pdata.AttributeValue) {
+		keys = append(keys, k)
+	})
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(tracetranslator.AttributeValueToString(v, false))
+		b.WriteByte(';')
+	}
+	return b.String()
+}
@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSplitByServiceName(t *testing.T) {
+	// prepare
+	batch := pdata.NewTraces()
+	for _, service := range []string{"svc-1", "svc-2", "", "svc-1"} {
+		rs := simpleTraces().ResourceSpans().At(0)
+		if service != "" {
+			rs.Resource().Attributes().InsertString("service.name", service)
+		}
+		batch.ResourceSpans().Append(rs)
+	}
+	batch.ResourceSpans().Append(simpleTraces().ResourceSpans().At(0))
+
+	// test
+	batches, unnamed := splitByServiceName(batch)
+
+	// verify
+	require.Len(t, batches, 2)
+	assert.Equal(t, "svc-1", batches[0].service)
+	assert.Equal(t, 2, batches[0].traces.ResourceSpans().Len())
+	assert.Equal(t, "svc-2", batches[1].service)
+	assert.Equal(t, 1, batches[1].traces.ResourceSpans().Len())
+	assert.Equal(t, 2, unnamed.ResourceSpans().Len())
+
+	// the resource spans are shared with the original batch, not copied
+	batches[1].traces.ResourceSpans().At(0).Resource().Attributes().InsertString("shared", "true")
+	_, found := batch.ResourceSpans().At(1).Resource().Attributes().Get("shared")
+	assert.True(t, found)
+}
+
+func TestSplitMetrics(t *testing.T) {
+	// prepare
+	md := pdata.NewMetrics()
+	for _, attrs := range []map[string]string{
+		{"service.name": "svc-1", "host.name": "host-1"},
+		{"service.name": "svc-1", "host.name": "host-2"},
+		{"host.name": "host-1", "service.name": "svc-1"},
+		{"host.name": "host-3"},
+	} {
+		md.ResourceMetrics().Append(resourceMetricsWithAttributes(attrs))
+	}
+
+	for _, tt := range []struct {
+		desc      string
+		byService bool
+		expected  []int
+	}{
+		{
+			desc:     "by resource",
+			expected: []int{2, 1, 1},
+		},
+		{
+			desc:      "by service",
+			byService: true,
+			expected:  []int{3, 1},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			batches := splitMetrics(md, tt.byService)
+
+			// verify
+			require.Len(t, batches, len(tt.expected))
+			for i, expected := range tt.expected {
+				assert.Equal(t, expected, batches[i].metrics.ResourceMetrics().Len())
+			}
+		})
+	}
+
+	// the resource metrics are shared with the original batch, not copied
+	batches := splitMetrics(md, false)
+	batches[2].metrics.ResourceMetrics().At(0).Resource().Attributes().InsertString("shared", "true")
+	_, found := md.ResourceMetrics().At(3).Resource().Attributes().Get("shared")
+	assert.True(t, found)
+}
+
+func TestSplitLogs(t *testing.T) {
+	// prepare
+	ld := pdata.NewLogs()
+
+	// the first resource logs has no trace ID
+	ld.ResourceLogs().Append(resourceLogsWithTraceIDs("host-1", pdata.InvalidTraceID(), pdata.InvalidTraceID()))
+
+	// the second one has two log records of the same trace, and one without a trace ID
+	ld.ResourceLogs().Append(resourceLogsWithTraceIDs("host-2",
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		pdata.InvalidTraceID(),
+		pdata.NewTraceID([16]byte{1, 2, 3, 4})))
+
+	// the third one has a log record of the same trace, from another resource
+	ld.ResourceLogs().Append(resourceLogsWithTraceIDs("host-3", pdata.NewTraceID([16]byte{1, 2, 3, 4})))
+
+	// test
+	batches := splitLogs(ld)
+
+	// verify
+	require.Len(t, batches, 3)
+
+	assert.Equal(t, resourceKey(ld.ResourceLogs().At(0).Resource()), batches[0].key)
+	assert.Equal(t, 2, batches[0].logs.LogRecordCount())
+	// the resource logs without trace ID are shared with the original batch, not copied
+	batches[0].logs.ResourceLogs().At(0).Resource().Attributes().InsertString("shared", "true")
+	_, found := ld.ResourceLogs().At(0).Resource().Attributes().Get("shared")
+	assert.True(t, found)
+
+	traceID := [16]byte{1, 2, 3, 4}
+	assert.Equal(t, string(traceID[:]), batches[1].key)
+	assert.Equal(t, 3, batches[1].logs.LogRecordCount())
+	assert.Equal(t, 2, batches[1].logs.ResourceLogs().Len())
+	host, _ := batches[1].logs.ResourceLogs().At(1).Resource().Attributes().Get("host.name")
+	assert.Equal(t, "host-3", host.StringVal())
+
+	assert.Equal(t, resourceKey(ld.ResourceLogs().At(1).Resource()), batches[2].key)
+	assert.Equal(t, 1, batches[2].logs.LogRecordCount())
+	library := batches[2].logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).InstrumentationLibrary()
+	assert.Equal(t, "library", library.Name())
+}
+
+func TestResourceKey(t *testing.T) {
+	// prepare
+	first := pdata.NewResource()
+	first.Attributes().InsertString("service.name", "svc-1")
+	first.Attributes().InsertInt("process.pid", 123)
+	second := pdata.NewResource()
+	second.Attributes().InsertInt("process.pid", 123)
+	second.Attributes().InsertString("service.name", "svc-1")
+	third := pdata.NewResource()
+	third.Attributes().InsertString("service.name", "svc-1")
+	third.Attributes().InsertInt("process.pid", 124)
+
+	// test and verify
+	assert.Equal(t, resourceKey(first), resourceKey(second))
+	assert.NotEqual(t, resourceKey(first), resourceKey(third))
+}
+
+func resourceMetricsWithAttributes(attrs map[string]string) pdata.ResourceMetrics {
+	rm := pdata.NewResourceMetrics()
+	for k, v := range attrs {
+		rm.Resource().Attributes().InsertString(k, v)
+	}
+	return rm
+}
+
+func resourceLogsWithTraceIDs(host string, traceIDs ...pdata.TraceID) pdata.ResourceLogs {
+	rl := pdata.NewResourceLogs()
+	rl.Resource().Attributes().InsertString("host.name", host)
+	ill := pdata.NewInstrumentationLibraryLogs()
+	ill.InstrumentationLibrary().SetName("library")
+	for _, traceID := range traceIDs {
+		lr := pdata.NewLogRecord()
+		lr.SetTraceID(traceID)
+		ill.Logs().Append(lr)
+	}
+	rl.InstrumentationLibraryLogs().Append(ill)
+	return rl
+}
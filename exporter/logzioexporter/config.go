@@ -16,22 +16,108 @@ package logzioexporter
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 // Config contains Logz.io specific configuration such as Account TracesToken, Region, etc.
 type Config struct {
-	configmodels.ExporterSettings `mapstructure:",squash"`
-	TracesToken                   string `mapstructure:"account_token"`   // Your Logz.io Account Token, can be found at https://app.logz.io/#/dashboard/settings/general
-	MetricsToken                  string `mapstructure:"metrics_token"`   // Your Logz.io Metrics Token, can be found at https://docs.logz.io/user-guide/accounts/finding-your-metrics-account-token/
-	Region                        string `mapstructure:"region"`          // Your Logz.io 2-letter region code, can be found at https://docs.logz.io/user-guide/accounts/account-region.html#available-regions
-	CustomEndpoint                string `mapstructure:"custom_endpoint"` // Custom endpoint to ship traces to. Use only for dev and tests.
+	configmodels.ExporterSettings  `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
+	TracesToken                    Token             `mapstructure:"account_token"`   // Your Logz.io Account Token, can be found at https://app.logz.io/#/dashboard/settings/general
+	LogsToken                      Token             `mapstructure:"logs_token"`      // Your Logz.io Logs Account Token, can be found at https://app.logz.io/#/dashboard/settings/manage-accounts
+	MetricsToken                   Token             `mapstructure:"metrics_token"`   // Your Logz.io Metrics Token, can be found at https://docs.logz.io/user-guide/accounts/finding-your-metrics-account-token/
+	Region                         string            `mapstructure:"region"`          // Your Logz.io 2-letter region code, can be found at https://docs.logz.io/user-guide/accounts/account-region.html#available-regions
+	CustomEndpoint                 string            `mapstructure:"custom_endpoint"` // Custom endpoint to ship traces and logs to. Use only for dev and tests.
+	LogType                        string            `mapstructure:"type"`            // The type of the logs, used by Logz.io to parse them.
+	ResourceFields                 map[string]string `mapstructure:"resource_fields"` // The resource attributes added as fields of the logs, from the attribute key to the field name.
+}
+
+// Token is a Logz.io shipping token, which is redacted when printed or marshalled, e.g. in the logs or the dumps of
+// the configuration.
+type Token string
+
+// String returns the token with all but its first and last 4 characters redacted.
+func (t Token) String() string {
+	return censorString(string(t), 4)
+}
+
+// GoString returns the redacted token, for the %#v verb.
+func (t Token) GoString() string {
+	return fmt.Sprintf("%q", t.String())
+}
+
+// MarshalText returns the redacted token.
+func (t Token) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func censorString(word string, n int) string {
+	if len(word) > 2*n {
+		return word[:n] + strings.Repeat("*", len(word)-(n*2)) + word[len(word)-n:]
+	}
+	return strings.Repeat("*", len(word))
+}
+
+const defaultRegion = "us"
+
+// listeners maps the region codes to the URL of their listener.
+var listeners = map[string]string{
+	"us": "https://listener.logz.io:8071",
+	"eu": "https://listener-eu.logz.io:8071",
+	"uk": "https://listener-uk.logz.io:8071",
+	"au": "https://listener-au.logz.io:8071",
+	"ca": "https://listener-ca.logz.io:8071",
+	"nl": "https://listener-nl.logz.io:8071",
+	"wa": "https://listener-wa.logz.io:8071",
 }
 
 func (c *Config) validate() error {
 	if c.TracesToken == "" {
 		return errors.New("`account_token` not specified")
 	}
+	return c.validateEndpoint()
+}
+
+func (c *Config) validateLogs() error {
+	if c.LogsToken == "" {
+		return errors.New("`logs_token` not specified")
+	}
+	return c.validateEndpoint()
+}
+
+func (c *Config) validateEndpoint() error {
+	if _, err := c.listenerURL(); err != nil {
+		return err
+	}
 	return nil
 }
+
+// listenerURL returns the URL of the listener the data is shipped to, either the custom endpoint or the listener of
+// the region, "us" by default.
+func (c *Config) listenerURL() (string, error) {
+	if c.CustomEndpoint != "" {
+		return strings.TrimSuffix(c.CustomEndpoint, "/"), nil
+	}
+
+	region := strings.ToLower(c.Region)
+	if region == "" {
+		region = defaultRegion
+	}
+	listener, ok := listeners[region]
+	if !ok {
+		regions := make([]string, 0, len(listeners))
+		for r := range listeners {
+			regions = append(regions, r)
+		}
+		sort.Strings(regions)
+		return "", fmt.Errorf("unsupported `region` %q, must be one of %s", c.Region, strings.Join(regions, ", "))
+	}
+	return listener, nil
+}
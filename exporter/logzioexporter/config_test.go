@@ -15,8 +15,10 @@
 package logzioexporter
 
 import (
+	"fmt"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,10 +43,63 @@ func TestLoadConfig(tester *testing.T) {
 	assert.Equal(tester, 2, len(cfg.Exporters))
 
 	config := cfg.Exporters["logzio/2"].(*Config)
-	assert.Equal(tester, &Config{
-		ExporterSettings: configmodels.ExporterSettings{TypeVal: typeStr, NameVal: "logzio/2"},
-		TracesToken:      "logzioTESTtoken",
-		Region:           "eu",
-		CustomEndpoint:   "https://some-url.com:8888",
-	}, config)
+	expected := factory.CreateDefaultConfig().(*Config)
+	expected.NameVal = "logzio/2"
+	expected.TracesToken = "logzioTESTtoken"
+	expected.LogsToken = "logzioLOGStoken"
+	expected.Region = "eu"
+	expected.CustomEndpoint = "https://some-url.com:8888"
+	expected.LogType = "otel"
+	expected.ResourceFields = map[string]string{
+		"service.name":       "service",
+		"k8s.namespace.name": "",
+	}
+	expected.TimeoutSettings.Timeout = 10 * time.Second
+	expected.QueueSettings.Enabled = false
+	expected.RetrySettings.InitialInterval = 10 * time.Second
+	assert.Equal(tester, expected, config)
+}
+
+func TestListenerURL(tester *testing.T) {
+	tests := []struct {
+		region         string
+		customEndpoint string
+		url            string
+		err            string
+	}{
+		{region: "", url: "https://listener.logz.io:8071"},
+		{region: "us", url: "https://listener.logz.io:8071"},
+		{region: "eu", url: "https://listener-eu.logz.io:8071"},
+		{region: "UK", url: "https://listener-uk.logz.io:8071"},
+		{region: "wa", url: "https://listener-wa.logz.io:8071"},
+		{region: "eu", customEndpoint: "http://localhost:8071/", url: "http://localhost:8071"},
+		{region: "mars", err: `unsupported ` + "`region`" + ` "mars", must be one of au, ca, eu, nl, uk, us, wa`},
+	}
+
+	for _, tt := range tests {
+		cfg := Config{Region: tt.region, CustomEndpoint: tt.customEndpoint}
+		url, err := cfg.listenerURL()
+		if tt.err != "" {
+			assert.EqualError(tester, err, tt.err)
+			continue
+		}
+		require.NoError(tester, err)
+		assert.Equal(tester, tt.url, url)
+	}
+}
+
+func TestTokenRedaction(tester *testing.T) {
+	cfg := Config{
+		TracesToken: "abcdefghijklmnop",
+		LogsToken:   "short",
+	}
+
+	assert.Equal(tester, "abcd********mnop", cfg.TracesToken.String())
+	assert.Equal(tester, "*****", cfg.LogsToken.String())
+	assert.NotContains(tester, fmt.Sprintf("%v %+v %#v", cfg, cfg, cfg), "efghijkl")
+	assert.NotContains(tester, fmt.Sprintf("%+v", cfg), "short")
+
+	text, err := cfg.TracesToken.MarshalText()
+	require.NoError(tester, err)
+	assert.Equal(tester, "abcd********mnop", string(text))
 }
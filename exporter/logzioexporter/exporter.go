@@ -16,78 +16,90 @@ package logzioexporter
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"fmt"
+	"time"
 
-	"github.com/hashicorp/go-hclog"
 	"github.com/jaegertracing/jaeger/model"
-	"github.com/logzio/jaeger-logzio/store"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/logzio/jaeger-logzio/store/objects"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/translator/trace/jaeger"
+	"go.uber.org/zap"
 )
 
 const (
 	loggerName = "logzio-exporter"
+
+	// serviceCacheSize and serviceCacheTTL bound the cache of the services already shipped, which Logz.io uses to
+	// list the services and operations of the Jaeger UI.
+	serviceCacheSize = 100000
+	serviceCacheTTL  = 24 * time.Hour
 )
 
 // logzioExporter implements an OpenTelemetry trace exporter that exports all spans to Logz.io
 type logzioExporter struct {
-	accountToken                 string
-	writer                       *store.LogzioSpanWriter
-	logger                       hclog.Logger
-	WriteSpanFunc                func(ctx context.Context, span *model.Span) error
+	config                       *Config
+	sender                       *sender
+	logger                       *zap.Logger
+	serviceCache                 cache.Cache
 	InternalTracesToJaegerTraces func(td pdata.Traces) ([]*model.Batch, error)
 }
 
-func newLogzioExporter(config *Config, params component.ExporterCreateParams) (*logzioExporter, error) {
-	logger := Hclog2ZapLogger{
-		Zap:  params.Logger,
-		name: loggerName,
-	}
-
+func newLogzioExporter(config *Config, token Token, params component.ExporterCreateParams) (*logzioExporter, error) {
 	if config == nil {
 		return nil, errors.New("exporter config can't be null")
 	}
-	writerConfig := store.LogzioConfig{
-		Region:            config.Region,
-		AccountToken:      config.TracesToken,
-		CustomListenerURL: config.CustomEndpoint,
-	}
 
-	spanWriter, err := store.NewLogzioSpanWriter(writerConfig, logger)
+	listenerURL, err := config.listenerURL()
 	if err != nil {
 		return nil, err
 	}
 
+	logger := params.Logger.Named(loggerName)
 	return &logzioExporter{
-		writer:                       spanWriter,
-		accountToken:                 config.TracesToken,
-		logger:                       logger,
+		config: config,
+		sender: newSender(listenerURL, token, logger),
+		logger: logger,
+		serviceCache: cache.NewLRUWithOptions(serviceCacheSize, &cache.Options{
+			TTL: serviceCacheTTL,
+		}),
 		InternalTracesToJaegerTraces: jaeger.InternalTracesToJaegerProto,
-		WriteSpanFunc:                spanWriter.WriteSpan,
 	}, nil
 }
 
 func newLogzioTraceExporter(config *Config, params component.ExporterCreateParams) (component.TracesExporter, error) {
-	exporter, err := newLogzioExporter(config, params)
-	if err != nil {
-		return nil, err
+	if config == nil {
+		return nil, errors.New("exporter config can't be null")
 	}
 	if err := config.validate(); err != nil {
 		return nil, err
 	}
+	exporter, err := newLogzioExporter(config, config.TracesToken, params)
+	if err != nil {
+		return nil, err
+	}
 
 	return exporterhelper.NewTraceExporter(
 		config,
 		params.Logger,
 		exporter.pushTraceData,
+		exporterhelper.WithTimeout(config.TimeoutSettings),
+		exporterhelper.WithRetry(config.RetrySettings),
+		exporterhelper.WithQueue(config.QueueSettings),
 		exporterhelper.WithShutdown(exporter.Shutdown))
 }
 
 func newLogzioMetricsExporter(config *Config, params component.ExporterCreateParams) (component.MetricsExporter, error) {
-	exporter, _ := newLogzioExporter(config, params)
+	if config == nil {
+		return nil, errors.New("exporter config can't be null")
+	}
+	exporter, err := newLogzioExporter(config, config.MetricsToken, params)
+	if err != nil {
+		return nil, err
+	}
 	return exporterhelper.NewMetricsExporter(
 		config,
 		params.Logger,
@@ -95,30 +107,79 @@ func newLogzioMetricsExporter(config *Config, params component.ExporterCreatePar
 		exporterhelper.WithShutdown(exporter.Shutdown))
 }
 
+// pushTraceData ships the spans in the Jaeger JSON format of Logz.io, along with the services and operations not
+// shipped recently.
 func (exporter *logzioExporter) pushTraceData(ctx context.Context, traces pdata.Traces) (droppedSpansCount int, err error) {
-	droppedSpans := 0
 	batches, err := exporter.InternalTracesToJaegerTraces(traces)
 	if err != nil {
 		return traces.SpanCount(), err
 	}
+
+	var lines [][]byte
+	var services []string
+	shipped := map[string]bool{}
+	droppedSpans := 0
 	for _, batch := range batches {
 		for _, span := range batch.Spans {
 			span.Process = batch.Process
-			if err := exporter.WriteSpanFunc(ctx, span); err != nil {
-				exporter.logger.Debug(fmt.Sprintf("dropped bad span: %s", span.String()))
+			span.Tags = exporter.dropEmptyTags(span.Tags)
+			span.Process.Tags = exporter.dropEmptyTags(span.Process.Tags)
+
+			spanBytes, err := objects.TransformToLogzioSpanBytes(span)
+			if err != nil {
+				exporter.logger.Debug("dropped bad span", zap.String("span", span.String()), zap.Error(err))
 				droppedSpans++
+				continue
+			}
+			lines = append(lines, spanBytes)
+
+			service := objects.NewLogzioService(span)
+			serviceHash, err := service.HashCode()
+			if err == nil && (shipped[serviceHash] || exporter.serviceCache.Get(serviceHash) != nil) {
+				continue
+			}
+			serviceBytes, marshalErr := json.Marshal(service)
+			if marshalErr != nil {
+				continue
+			}
+			lines = append(lines, serviceBytes)
+			if err == nil {
+				shipped[serviceHash] = true
+				services = append(services, serviceHash)
 			}
 		}
 	}
-	return droppedSpans, nil
+
+	// the services are added to the cache once shipped, to be shipped again with the next spans otherwise
+	dropped, err := exporter.sender.send(ctx, lines)
+	if err != nil {
+		return traces.SpanCount(), err
+	}
+	for _, serviceHash := range services {
+		exporter.serviceCache.Put(serviceHash, serviceHash)
+	}
+	return droppedSpans + dropped, nil
 }
 
 func (exporter *logzioExporter) pushMetricsData(ctx context.Context, md pdata.Metrics) (int, error) {
 	return 0, nil
 }
 
+// dropEmptyTags removes the tags without a key, which Logz.io rejects.
+func (exporter *logzioExporter) dropEmptyTags(tags []model.KeyValue) []model.KeyValue {
+	kept := tags[:0]
+	for _, tag := range tags {
+		if tag.Key == "" {
+			exporter.logger.Debug("Found tag with an empty key, dropping tag", zap.String("tag", tag.String()))
+			continue
+		}
+		kept = append(kept, tag)
+	}
+	return kept
+}
+
 func (exporter *logzioExporter) Shutdown(ctx context.Context) error {
 	exporter.logger.Info("Closing logzio exporter..")
-	exporter.writer.Close()
+	exporter.sender.close()
 	return nil
 }
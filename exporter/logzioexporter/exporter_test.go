@@ -15,6 +15,7 @@
 package logzioexporter
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
@@ -32,6 +33,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
@@ -82,7 +84,7 @@ func testMetricsExporter(md pdata.Metrics, t *testing.T, cfg *Config) {
 
 func TestNullExporterConfig(tester *testing.T) {
 	params := component.ExporterCreateParams{Logger: zap.NewNop()}
-	_, err := newLogzioExporter(nil, params)
+	_, err := newLogzioExporter(nil, "", params)
 	assert.Error(tester, err, "Null exporter config should produce error")
 }
 
@@ -107,24 +109,41 @@ func TestEmptyNode(tester *testing.T) {
 	testTraceExporter(internaldata.OCToTraceData(td), tester, &cfg)
 }
 
-func TestWriteSpanError(tester *testing.T) {
-	cfg := Config{
-		TracesToken: "test",
-		Region:      "eu",
+func TestPushTraceDataErrors(tester *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{status: http.StatusBadRequest, permanent: true},
+		{status: http.StatusUnauthorized, permanent: true},
+		{status: http.StatusRequestEntityTooLarge, permanent: true},
+		{status: http.StatusTooManyRequests, permanent: false},
+		{status: http.StatusInternalServerError, permanent: false},
+		{status: http.StatusServiceUnavailable, permanent: false},
 	}
+
 	td := consumerdata.TraceData{
 		Node:  nil,
 		Spans: testSpans,
 	}
-	params := component.ExporterCreateParams{Logger: zap.NewNop()}
-	exporter, _ := newLogzioExporter(&cfg, params)
-	oldFunc := exporter.WriteSpanFunc
-	defer func() { exporter.WriteSpanFunc = oldFunc }()
-	exporter.WriteSpanFunc = func(context.Context, *model.Span) error {
-		return errors.New("fail")
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(tt.status)
+		}))
+		cfg := Config{
+			TracesToken:    "test",
+			CustomEndpoint: server.URL,
+		}
+		params := component.ExporterCreateParams{Logger: zap.NewNop()}
+		exporter, err := newLogzioExporter(&cfg, cfg.TracesToken, params)
+		require.NoError(tester, err)
+
+		droppedSpans, err := exporter.pushTraceData(context.Background(), internaldata.OCToTraceData(td))
+		assert.Error(tester, err)
+		assert.Equal(tester, tt.permanent, consumererror.IsPermanent(err), "status %d", tt.status)
+		assert.Equal(tester, 1, droppedSpans)
+		server.Close()
 	}
-	droppedSpans, _ := exporter.pushTraceData(context.Background(), internaldata.OCToTraceData(td))
-	assert.Equal(tester, 1, droppedSpans)
 }
 
 func TestConversionTraceError(tester *testing.T) {
@@ -137,7 +156,7 @@ func TestConversionTraceError(tester *testing.T) {
 		Spans: testSpans,
 	}
 	params := component.ExporterCreateParams{Logger: zap.NewNop()}
-	exporter, _ := newLogzioExporter(&cfg, params)
+	exporter, _ := newLogzioExporter(&cfg, cfg.TracesToken, params)
 	oldFunc := exporter.InternalTracesToJaegerTraces
 	defer func() { exporter.InternalTracesToJaegerTraces = oldFunc }()
 	exporter.InternalTracesToJaegerTraces = func(td pdata.Traces) ([]*model.Batch, error) {
@@ -147,10 +166,21 @@ func TestConversionTraceError(tester *testing.T) {
 	assert.Error(tester, err)
 }
 
+// readGzipLines returns the lines of a gzip-compressed request body.
+func readGzipLines(tester *testing.T, req *http.Request) []string {
+	assert.Equal(tester, "gzip", req.Header.Get("Content-Encoding"))
+	gz, err := gzip.NewReader(req.Body)
+	require.NoError(tester, err)
+	body, err := ioutil.ReadAll(gz)
+	require.NoError(tester, err)
+	return strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+}
+
 func TestPushTraceData(tester *testing.T) {
-	var recordedRequests []byte
+	var requests [][]string
 	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		recordedRequests, _ = ioutil.ReadAll(req.Body)
+		assert.Equal(tester, "test", req.URL.Query().Get("token"))
+		requests = append(requests, readGzipLines(tester, req))
 		rw.WriteHeader(http.StatusOK)
 	}))
 	cfg := Config{
@@ -171,19 +201,32 @@ func TestPushTraceData(tester *testing.T) {
 		},
 		Spans: testSpans,
 	}
-	testTraceExporter(internaldata.OCToTraceData(td), tester, &cfg)
-	requests := strings.Split(string(recordedRequests), "\n")
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := newLogzioExporter(&cfg, cfg.TracesToken, params)
+	require.NoError(tester, err)
+	_, err = exporter.pushTraceData(context.Background(), internaldata.OCToTraceData(td))
+	require.NoError(tester, err)
+
+	require.Len(tester, requests, 1)
+	require.Len(tester, requests[0], 2)
 	var logzioSpan objects.LogzioSpan
-	assert.NoError(tester, json.Unmarshal([]byte(requests[0]), &logzioSpan))
+	assert.NoError(tester, json.Unmarshal([]byte(requests[0][0]), &logzioSpan))
 	assert.Equal(tester, testOperation, logzioSpan.OperationName)
 	assert.Equal(tester, testService, logzioSpan.Process.ServiceName)
 
 	var logzioService objects.LogzioService
-	assert.NoError(tester, json.Unmarshal([]byte(requests[1]), &logzioService))
+	assert.NoError(tester, json.Unmarshal([]byte(requests[0][1]), &logzioService))
 
 	assert.Equal(tester, testOperation, logzioService.OperationName)
 	assert.Equal(tester, testService, logzioService.ServiceName)
 
+	// the service is shipped once
+	_, err = exporter.pushTraceData(context.Background(), internaldata.OCToTraceData(td))
+	require.NoError(tester, err)
+	require.Len(tester, requests, 2)
+	require.Len(tester, requests[1], 1)
+	assert.NoError(tester, json.Unmarshal([]byte(requests[1][0]), &logzioSpan))
+	assert.Equal(tester, testOperation, logzioSpan.OperationName)
 }
 
 func TestPushMetricsData(tester *testing.T) {
@@ -29,7 +29,8 @@ func NewFactory() component.ExporterFactory {
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		exporterhelper.WithTraces(createTraceExporter))
+		exporterhelper.WithTraces(createTraceExporter),
+		exporterhelper.WithLogs(createLogsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -38,8 +39,11 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		Region:      "",
-		TracesToken: "",
+		TimeoutSettings: exporterhelper.DefaultTimeoutSettings(),
+		RetrySettings:   exporterhelper.DefaultRetrySettings(),
+		QueueSettings:   exporterhelper.DefaultQueueSettings(),
+		Region:          "",
+		TracesToken:     "",
 	}
 }
 
@@ -52,3 +56,8 @@ func createMetricsExporter(_ context.Context, params component.ExporterCreatePar
 	config := cfg.(*Config)
 	return newLogzioMetricsExporter(config, params)
 }
+
+func createLogsExporter(_ context.Context, params component.ExporterCreateParams, cfg configmodels.Exporter) (component.LogsExporter, error) {
+	config := cfg.(*Config)
+	return newLogzioLogsExporter(config, params)
+}
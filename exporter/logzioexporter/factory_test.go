@@ -51,3 +51,29 @@ func TestCreateTraceExporter(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, exporter)
 }
+
+func TestCreateLogsExporter(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[configmodels.Type(typeStr)] = factory
+	cfg, err := configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), factories,
+	)
+	require.NoError(t, err)
+
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := factory.CreateLogsExporter(context.Background(), params, cfg.Exporters["logzio/2"])
+	assert.Nil(t, err)
+	assert.NotNil(t, exporter)
+}
+
+func TestCreateExporterInvalidRegion(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TracesToken = "test"
+	cfg.Region = "mars"
+
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	_, err := NewFactory().CreateTracesExporter(context.Background(), params, cfg)
+	assert.EqualError(t, err, "unsupported `region` \"mars\", must be one of au, ca, eu, nl, uk, us, wa")
+}
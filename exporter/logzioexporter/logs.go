@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logzioexporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+// The fields of the log lines set by the exporter, which the attributes don't override.
+const (
+	fieldTimestamp      = "@timestamp"
+	fieldMessage        = "message"
+	fieldLevel          = "level"
+	fieldSeverityNumber = "severity_number"
+	fieldName           = "name"
+	fieldTraceID        = "traceID"
+	fieldSpanID         = "spanID"
+	fieldType           = "type"
+)
+
+func newLogzioLogsExporter(config *Config, params component.ExporterCreateParams) (component.LogsExporter, error) {
+	if config == nil {
+		return nil, errors.New("exporter config can't be null")
+	}
+	if err := config.validateLogs(); err != nil {
+		return nil, err
+	}
+	exporter, err := newLogzioExporter(config, config.LogsToken, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewLogsExporter(
+		config,
+		params.Logger,
+		exporter.pushLogData,
+		exporterhelper.WithTimeout(config.TimeoutSettings),
+		exporterhelper.WithRetry(config.RetrySettings),
+		exporterhelper.WithQueue(config.QueueSettings),
+		exporterhelper.WithShutdown(exporter.Shutdown))
+}
+
+// pushLogData ships the log records as JSON lines.
+func (exporter *logzioExporter) pushLogData(ctx context.Context, ld pdata.Logs) (int, error) {
+	var lines [][]byte
+	droppedLogs := 0
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceFields := exporter.resourceFields(rl.Resource())
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				line, err := json.Marshal(logRecordToFields(logs.At(k), resourceFields))
+				if err != nil {
+					exporter.logger.Debug("dropped bad log record", zap.Error(err))
+					droppedLogs++
+					continue
+				}
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	dropped, err := exporter.sender.send(ctx, lines)
+	if err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return droppedLogs + dropped, nil
+}
+
+// resourceFields returns the fields added to the logs of a resource: the type of the logs, and the resource
+// attributes configured as fields, which may override the type.
+func (exporter *logzioExporter) resourceFields(resource pdata.Resource) map[string]string {
+	fields := make(map[string]string, len(exporter.config.ResourceFields)+1)
+	if exporter.config.LogType != "" {
+		fields[fieldType] = exporter.config.LogType
+	}
+	for key, field := range exporter.config.ResourceFields {
+		if field == "" {
+			field = key
+		}
+		if v, ok := resource.Attributes().Get(key); ok {
+			fields[field] = tracetranslator.AttributeValueToString(v, false)
+		}
+	}
+	return fields
+}
+
+// logRecordToFields converts a log record to the fields of its JSON line, with the attributes of the record and the
+// fields of its resource.
+func logRecordToFields(lr pdata.LogRecord, resourceFields map[string]string) map[string]interface{} {
+	fields := make(map[string]interface{}, lr.Attributes().Len()+len(resourceFields)+7)
+	lr.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		fields[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+	for field, value := range resourceFields {
+		fields[field] = value
+	}
+
+	if lr.Timestamp() != 0 {
+		fields[fieldTimestamp] = time.Unix(0, int64(lr.Timestamp())).UTC().Format(time.RFC3339Nano)
+	}
+	fields[fieldMessage] = tracetranslator.AttributeValueToString(lr.Body(), false)
+	if lr.SeverityText() != "" {
+		fields[fieldLevel] = lr.SeverityText()
+	}
+	if lr.SeverityNumber() != pdata.SeverityNumberUNDEFINED {
+		fields[fieldSeverityNumber] = int32(lr.SeverityNumber())
+	}
+	if lr.Name() != "" {
+		fields[fieldName] = lr.Name()
+	}
+	if !lr.TraceID().IsEmpty() {
+		fields[fieldTraceID] = lr.TraceID().HexString()
+	}
+	if !lr.SpanID().IsEmpty() {
+		fields[fieldSpanID] = lr.SpanID().HexString()
+	}
+	return fields
+}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logzioexporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func testLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString("service.name", testService)
+	rl.Resource().Attributes().InsertString("k8s.namespace.name", "shop")
+	rl.Resource().Attributes().InsertString("host.name", testHost)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+
+	logs.At(0).SetTimestamp(pdata.TimestampUnixNano(1614160000123456789))
+	logs.At(0).SetTraceID(pdata.NewTraceID([16]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}))
+	logs.At(0).SetSpanID(pdata.NewSpanID([8]byte{0, 0, 0, 0, 0, 0, 0, 2}))
+	logs.At(0).SetSeverityText("ERROR")
+	logs.At(0).SetSeverityNumber(pdata.SeverityNumberERROR)
+	logs.At(0).Body().SetStringVal("payment failed")
+	logs.At(0).Attributes().InsertInt("http.status_code", 502)
+	// the attributes don't override the fields set by the exporter
+	logs.At(0).Attributes().InsertString("message", "overridden")
+
+	logs.At(1).Body().SetStringVal("retrying")
+	return ld
+}
+
+func TestPushLogData(tester *testing.T) {
+	var lines []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(tester, "logsToken", req.URL.Query().Get("token"))
+		lines = append(lines, readGzipLines(tester, req)...)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.LogsToken = "logsToken"
+	cfg.CustomEndpoint = server.URL
+	cfg.LogType = "otel"
+	cfg.ResourceFields = map[string]string{
+		"service.name":       "service",
+		"k8s.namespace.name": "",
+	}
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := newLogzioExporter(cfg, cfg.LogsToken, params)
+	require.NoError(tester, err)
+
+	dropped, err := exporter.pushLogData(context.Background(), testLogs())
+	require.NoError(tester, err)
+	assert.Equal(tester, 0, dropped)
+
+	require.Len(tester, lines, 2)
+	var fields map[string]interface{}
+	require.NoError(tester, json.Unmarshal([]byte(lines[0]), &fields))
+	assert.Equal(tester, map[string]interface{}{
+		"@timestamp":         "2021-02-24T09:46:40.123456789Z",
+		"message":            "payment failed",
+		"level":              "ERROR",
+		"severity_number":    float64(pdata.SeverityNumberERROR),
+		"traceID":            "00000000000000000000000000000001",
+		"spanID":             "0000000000000002",
+		"http.status_code":   "502",
+		"type":               "otel",
+		"service":            testService,
+		"k8s.namespace.name": "shop",
+	}, fields)
+
+	fields = nil
+	require.NoError(tester, json.Unmarshal([]byte(lines[1]), &fields))
+	assert.Equal(tester, map[string]interface{}{
+		"message":            "retrying",
+		"type":               "otel",
+		"service":            testService,
+		"k8s.namespace.name": "shop",
+	}, fields)
+}
+
+func TestResourceFieldsOverrideType(tester *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.LogsToken = "logsToken"
+	cfg.LogType = "otel"
+	cfg.ResourceFields = map[string]string{"service.name": "type"}
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := newLogzioExporter(cfg, cfg.LogsToken, params)
+	require.NoError(tester, err)
+
+	resource := pdata.NewResource()
+	assert.Equal(tester, map[string]string{"type": "otel"}, exporter.resourceFields(resource))
+
+	resource.Attributes().InsertString("service.name", testService)
+	assert.Equal(tester, map[string]string{"type": testService}, exporter.resourceFields(resource))
+}
+
+func TestPushLogDataError(tester *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+		_, _ = rw.Write([]byte("invalid token"))
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.LogsToken = "logsToken"
+	cfg.CustomEndpoint = server.URL
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := newLogzioExporter(cfg, cfg.LogsToken, params)
+	require.NoError(tester, err)
+
+	dropped, err := exporter.pushLogData(context.Background(), testLogs())
+	assert.EqualError(tester, err, `Permanent error: HTTP 401 "Unauthorized": invalid token`)
+	assert.True(tester, consumererror.IsPermanent(err))
+	assert.Equal(tester, 2, dropped)
+}
+
+func TestNullLogsTokenConfig(tester *testing.T) {
+	cfg := Config{
+		TracesToken: "test",
+		Region:      "eu",
+	}
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	_, err := createLogsExporter(context.Background(), params, &cfg)
+	assert.EqualError(tester, err, "`logs_token` not specified")
+}
@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logzioexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxRequestSize is the maximum size of the uncompressed body of a bulk request accepted by the listeners.
+	maxRequestSize = 10 * 1024 * 1024
+
+	// maxLineSize is the maximum size of a log line, or a span, accepted by the listeners.
+	maxLineSize = 500000
+
+	// maxErrorBodySize is the maximum number of bytes of the body of an error response included in the error.
+	maxErrorBodySize = 1024
+)
+
+// sender ships JSON lines to a Logz.io listener, in gzip-compressed bulk requests.
+type sender struct {
+	client *http.Client
+	url    string
+	token  Token
+	logger *zap.Logger
+}
+
+func newSender(listenerURL string, token Token, logger *zap.Logger) *sender {
+	return &sender{
+		client: &http.Client{},
+		url:    listenerURL + "/?token=" + url.QueryEscape(string(token)),
+		token:  token,
+		logger: logger,
+	}
+}
+
+// send ships the lines in as few requests as the size limit of the listener allows, sending them in order and
+// stopping at the first failed request. The lines over the limit of the listener are dropped, their number being
+// returned.
+func (s *sender) send(ctx context.Context, lines [][]byte) (int, error) {
+	dropped := 0
+	var body bytes.Buffer
+	for _, line := range lines {
+		if len(line) > maxLineSize {
+			s.logger.Debug("Dropping line exceeding the size limit of Logz.io", zap.Int("size", len(line)))
+			dropped++
+			continue
+		}
+		if body.Len() > 0 && body.Len()+len(line)+1 > maxRequestSize {
+			if err := s.post(ctx, body.Bytes()); err != nil {
+				return dropped, err
+			}
+			body.Reset()
+		}
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+
+	if body.Len() > 0 {
+		if err := s.post(ctx, body.Bytes()); err != nil {
+			return dropped, err
+		}
+	}
+	return dropped, nil
+}
+
+// post sends a bulk request, the 4xx responses other than 429 being permanent errors.
+func (s *sender) post(ctx context.Context, body []byte) error {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(body); err != nil {
+		return consumererror.Permanent(err)
+	}
+	if err := gz.Close(); err != nil {
+		return consumererror.Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &compressed)
+	if err != nil {
+		return consumererror.Permanent(s.redactError(err))
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return s.redactError(err)
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("HTTP %d %q: %s", resp.StatusCode, http.StatusText(resp.StatusCode), strings.TrimSpace(string(respBody)))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return consumererror.Permanent(err)
+	}
+	return err
+}
+
+// redactError removes the token from the error, e.g. in the URL of the request.
+func (s *sender) redactError(err error) error {
+	if s.token == "" {
+		return err
+	}
+	msg := err.Error()
+	redacted := strings.NewReplacer(
+		url.QueryEscape(string(s.token)), s.token.String(),
+		string(s.token), s.token.String(),
+	).Replace(msg)
+	if redacted != msg {
+		return errors.New(redacted)
+	}
+	return err
+}
+
+func (s *sender) close() {
+	s.client.CloseIdleConnections()
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logzioexporter
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSenderChunks(tester *testing.T) {
+	var requests [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, readGzipLines(tester, req))
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 30 lines of 400KB don't fit in a single request of 10MB, which holds 26 of them with their newlines
+	line := bytes.Repeat([]byte("a"), 400000)
+	lines := make([][]byte, 30)
+	for i := range lines {
+		lines[i] = line
+	}
+	// the lines over the limit of the listener are dropped
+	lines = append(lines, bytes.Repeat([]byte("b"), maxLineSize+1))
+
+	s := newSender(server.URL, "test", zap.NewNop())
+	dropped, err := s.send(context.Background(), lines)
+	require.NoError(tester, err)
+	assert.Equal(tester, 1, dropped)
+
+	require.Len(tester, requests, 2)
+	assert.Len(tester, requests[0], 26)
+	assert.Len(tester, requests[1], 4)
+}
+
+func TestSenderNoLines(tester *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		tester.Error("no request must be sent without lines")
+	}))
+	defer server.Close()
+
+	s := newSender(server.URL, "test", zap.NewNop())
+	dropped, err := s.send(context.Background(), nil)
+	require.NoError(tester, err)
+	assert.Equal(tester, 0, dropped)
+}
+
+func TestSenderErrorRedaction(tester *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(tester, err)
+	address := listener.Addr().String()
+	require.NoError(tester, listener.Close())
+
+	s := newSender("http://"+address, "secretTOKENvalue", zap.NewNop())
+	_, err = s.send(context.Background(), [][]byte{[]byte(`{"message":"hello"}`)})
+	require.Error(tester, err)
+	assert.NotContains(tester, err.Error(), "secretTOKENvalue")
+	assert.Contains(tester, err.Error(), "token=secr********alue")
+}
@@ -15,6 +15,11 @@
 package lokiexporter
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -27,6 +32,90 @@ type Config struct {
 	exporterhelper.QueueSettings  `mapstructure:"sending_queue"`
 	exporterhelper.RetrySettings  `mapstructure:"retry_on_failure"`
 
-	// The attributes that are allowed to be added as labels on the log stream sent to Loki.
-	AttributesForLabels []string `mapstructure:"attributes_for_labels"`
+	// Labels defines how the attributes of the logs are mapped to the labels of the log streams sent to Loki.
+	Labels LabelsConfig `mapstructure:"labels"`
+
+	// Tenant defines how the tenant of the logs is determined. When not set, no tenant is sent by the exporter.
+	Tenant *TenantConfig `mapstructure:"tenant"`
+
+	// Format is the format of the log lines, either "body" or "json".
+	Format string `mapstructure:"format"`
+}
+
+// LabelsConfig defines the attributes promoted to labels, each map going from an attribute key to the name of its
+// label. An empty name uses the attribute key, sanitized to Loki's charset.
+type LabelsConfig struct {
+	// Attributes are the attributes of the log records promoted to labels.
+	Attributes map[string]string `mapstructure:"attributes"`
+
+	// ResourceAttributes are the attributes of the resources promoted to labels.
+	ResourceAttributes map[string]string `mapstructure:"resource"`
+
+	// RecordAttributes are the fields of the log records promoted to labels, among "trace_id", "span_id" and
+	// "severity".
+	RecordAttributes map[string]string `mapstructure:"record"`
+}
+
+// TenantConfig defines the source of the tenant sent in the X-Scope-OrgID header.
+type TenantConfig struct {
+	// Source is either "static", with Value being the tenant, or "resource", with Value being the resource attribute
+	// holding the tenant.
+	Source string `mapstructure:"source"`
+
+	// Value is the tenant or the resource attribute, depending on Source.
+	Value string `mapstructure:"value"`
+}
+
+const (
+	formatBody = "body"
+	formatJSON = "json"
+
+	tenantSourceStatic   = "static"
+	tenantSourceResource = "resource"
+
+	recordTraceID  = "trace_id"
+	recordSpanID   = "span_id"
+	recordSeverity = "severity"
+
+	tenantHeader = "X-Scope-OrgID"
+)
+
+func (c *Config) validate() error {
+	if _, err := url.Parse(c.Endpoint); c.Endpoint == "" || err != nil {
+		return errors.New("endpoint must be a valid URL")
+	}
+
+	if len(c.Labels.Attributes)+len(c.Labels.ResourceAttributes)+len(c.Labels.RecordAttributes) == 0 {
+		return errors.New("labels must have at least one attribute, resource attribute or record field")
+	}
+
+	for field := range c.Labels.RecordAttributes {
+		switch field {
+		case recordTraceID, recordSpanID, recordSeverity:
+		default:
+			return fmt.Errorf("unsupported labels.record field %q, must be one of %q, %q or %q", field, recordTraceID, recordSpanID, recordSeverity)
+		}
+	}
+
+	switch c.Format {
+	case formatBody, formatJSON:
+	default:
+		return fmt.Errorf("unsupported format %q, must be either %q or %q", c.Format, formatBody, formatJSON)
+	}
+
+	if c.Tenant != nil {
+		if c.Tenant.Source != tenantSourceStatic && c.Tenant.Source != tenantSourceResource {
+			return fmt.Errorf("unsupported tenant.source %q, must be either %q or %q", c.Tenant.Source, tenantSourceStatic, tenantSourceResource)
+		}
+		if c.Tenant.Value == "" {
+			return errors.New("tenant.value must be specified")
+		}
+		for k := range c.Headers {
+			if strings.EqualFold(k, tenantHeader) {
+				return fmt.Errorf("tenant and the %s header can't both be set", tenantHeader)
+			}
+		}
+	}
+
+	return nil
 }
@@ -48,7 +48,7 @@ func TestLoadConfig(t *testing.T) {
 		ExporterSettings: configmodels.ExporterSettings{TypeVal: typeStr, NameVal: "loki/allsettings"},
 		HTTPClientSettings: confighttp.HTTPClientSettings{
 			Headers: map[string]string{
-				"x-scope-orgid": "example",
+				"x-custom-header": "example",
 			},
 			Endpoint: "https://loki:3100/loki/api/v1/push",
 			TLSSetting: configtls.TLSClientSetting{
@@ -74,7 +74,101 @@ func TestLoadConfig(t *testing.T) {
 			NumConsumers: 2,
 			QueueSize:    10,
 		},
-		AttributesForLabels: []string{conventions.AttributeContainerName, conventions.AttributeK8sCluster, "severity"},
+		Labels: LabelsConfig{
+			Attributes: map[string]string{
+				conventions.AttributeContainerName: "container_name",
+			},
+			ResourceAttributes: map[string]string{
+				conventions.AttributeK8sCluster: "k8s_cluster_name",
+			},
+			RecordAttributes: map[string]string{
+				"severity": "level",
+			},
+		},
+		Tenant: &TenantConfig{
+			Source: "resource",
+			Value:  "tenant.id",
+		},
+		Format: "json",
 	}
 	assert.Equal(t, &expectedCfg, actualCfg)
 }
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "empty endpoint",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = ""
+			},
+			err: "endpoint must be a valid URL",
+		},
+		{
+			name: "no labels",
+			modify: func(cfg *Config) {
+				cfg.Labels.Attributes = nil
+			},
+			err: "labels must have at least one attribute, resource attribute or record field",
+		},
+		{
+			name: "invalid record field",
+			modify: func(cfg *Config) {
+				cfg.Labels.RecordAttributes = map[string]string{"name": ""}
+			},
+			err: `unsupported labels.record field "name", must be one of "trace_id", "span_id" or "severity"`,
+		},
+		{
+			name: "invalid format",
+			modify: func(cfg *Config) {
+				cfg.Format = "logfmt"
+			},
+			err: `unsupported format "logfmt", must be either "body" or "json"`,
+		},
+		{
+			name: "invalid tenant source",
+			modify: func(cfg *Config) {
+				cfg.Tenant = &TenantConfig{Source: "header", Value: "tenant"}
+			},
+			err: `unsupported tenant.source "header", must be either "static" or "resource"`,
+		},
+		{
+			name: "empty tenant value",
+			modify: func(cfg *Config) {
+				cfg.Tenant = &TenantConfig{Source: "static"}
+			},
+			err: "tenant.value must be specified",
+		},
+		{
+			name: "tenant and header",
+			modify: func(cfg *Config) {
+				cfg.Tenant = &TenantConfig{Source: "static", Value: "tenant"}
+				cfg.Headers = map[string]string{"x-scope-orgid": "tenant"}
+			},
+			err: "tenant and the X-Scope-OrgID header can't both be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = validEndpoint
+			cfg.Labels.Attributes = map[string]string{"app": ""}
+			tt.modify(cfg)
+
+			err := cfg.validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -48,9 +48,14 @@ func createDefaultConfig() configmodels.Exporter {
 			// We almost read 0 bytes, so no need to tune ReadBufferSize.
 			WriteBufferSize: 512 * 1024,
 		},
-		RetrySettings:       exporterhelper.DefaultRetrySettings(),
-		QueueSettings:       exporterhelper.DefaultQueueSettings(),
-		AttributesForLabels: []string{},
+		RetrySettings: exporterhelper.DefaultRetrySettings(),
+		QueueSettings: exporterhelper.DefaultQueueSettings(),
+		Labels: LabelsConfig{
+			Attributes:         map[string]string{},
+			ResourceAttributes: map[string]string{},
+			RecordAttributes:   map[string]string{},
+		},
+		Format: formatBody,
 	}
 }
 
@@ -70,7 +75,6 @@ func createLogsExporter(_ context.Context, params component.ExporterCreateParams
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(expCfg.RetrySettings),
 		exporterhelper.WithQueue(expCfg.QueueSettings),
-		exporterhelper.WithStart(exp.start),
 		exporterhelper.WithShutdown(exp.stop),
 	)
 }
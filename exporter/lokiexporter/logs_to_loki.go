@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// pushRequest is the JSON body of a request to the push API of Loki.
+type pushRequest struct {
+	Streams []*stream `json:"streams"`
+}
+
+// stream is a log stream, made of the entries sharing the same labels as pairs of timestamp and log line.
+type stream struct {
+	Labels map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// jsonLine is a log line in the json format, made of the fields of a log record which aren't promoted to labels.
+type jsonLine struct {
+	Name       string                 `json:"name,omitempty"`
+	Body       interface{}            `json:"body,omitempty"`
+	TraceID    string                 `json:"traceid,omitempty"`
+	SpanID     string                 `json:"spanid,omitempty"`
+	Severity   string                 `json:"severity,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Resources  map[string]interface{} `json:"resources,omitempty"`
+}
+
+// labelNames returns the label names of the mapping, the empty ones defaulting to the key. The names are sanitized
+// to Loki's charset.
+func labelNames(mapping map[string]string) map[string]string {
+	names := make(map[string]string, len(mapping))
+	for key, name := range mapping {
+		if name == "" {
+			name = key
+		}
+		names[key] = sanitizeLabelName(name)
+	}
+	return names
+}
+
+// sanitizeLabelName replaces the characters not allowed in a label name, i.e. not matching [a-zA-Z_][a-zA-Z0-9_]*,
+// with underscores.
+func sanitizeLabelName(name string) string {
+	if name == "" {
+		return "_"
+	}
+	sanitized := strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	if sanitized[0] >= '0' && sanitized[0] <= '9' {
+		sanitized = "_" + sanitized
+	}
+	return sanitized
+}
+
+// logsToPushRequest converts the logs to a push request, grouping the log records in streams by labels. It returns
+// the number of log records dropped because none of their attributes is promoted to a label, as Loki requires at
+// least one.
+func (e *lokiExporter) logsToPushRequest(ld pdata.Logs) (*pushRequest, int) {
+	var (
+		req            = &pushRequest{}
+		streams        = map[string]*stream{}
+		numDroppedLogs int
+	)
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceAttrs := rl.Resource().Attributes()
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				labels := e.labels(lr, resourceAttrs)
+				if len(labels) == 0 {
+					numDroppedLogs++
+					continue
+				}
+
+				key := streamKey(labels)
+				s, ok := streams[key]
+				if !ok {
+					s = &stream{Labels: labels}
+					streams[key] = s
+					req.Streams = append(req.Streams, s)
+				}
+				s.Values = append(s.Values, [2]string{timestamp(lr), e.line(lr, resourceAttrs)})
+			}
+		}
+	}
+
+	return req, numDroppedLogs
+}
+
+// labels returns the labels of the log record, from its promoted attributes and fields and the promoted attributes
+// of its resource.
+func (e *lokiExporter) labels(lr pdata.LogRecord, resourceAttrs pdata.AttributeMap) map[string]string {
+	labels := map[string]string{}
+	addAttributeLabels(labels, resourceAttrs, e.resourceLabels)
+	addAttributeLabels(labels, lr.Attributes(), e.attributeLabels)
+	for field, name := range e.recordLabels {
+		if value := recordField(lr, field); value != "" {
+			labels[name] = value
+		}
+	}
+	return labels
+}
+
+func addAttributeLabels(labels map[string]string, attrs pdata.AttributeMap, names map[string]string) {
+	for key, name := range names {
+		if v, ok := attrs.Get(key); ok {
+			if value := tracetranslator.AttributeValueToString(v, false); value != "" {
+				labels[name] = value
+			}
+		}
+	}
+}
+
+func recordField(lr pdata.LogRecord, field string) string {
+	switch field {
+	case recordTraceID:
+		if !lr.TraceID().IsEmpty() {
+			return lr.TraceID().HexString()
+		}
+	case recordSpanID:
+		if !lr.SpanID().IsEmpty() {
+			return lr.SpanID().HexString()
+		}
+	case recordSeverity:
+		return lr.SeverityText()
+	}
+	return ""
+}
+
+// line returns the log line of the log record, in the configured format.
+func (e *lokiExporter) line(lr pdata.LogRecord, resourceAttrs pdata.AttributeMap) string {
+	if e.config.Format != formatJSON {
+		return tracetranslator.AttributeValueToString(lr.Body(), false)
+	}
+
+	line := jsonLine{
+		Name:       lr.Name(),
+		Body:       attributeValue(lr.Body()),
+		Attributes: unpromotedAttributes(lr.Attributes(), e.attributeLabels),
+		Resources:  unpromotedAttributes(resourceAttrs, e.resourceLabels),
+	}
+	if _, ok := e.recordLabels[recordTraceID]; !ok {
+		line.TraceID = recordField(lr, recordTraceID)
+	}
+	if _, ok := e.recordLabels[recordSpanID]; !ok {
+		line.SpanID = recordField(lr, recordSpanID)
+	}
+	if _, ok := e.recordLabels[recordSeverity]; !ok {
+		line.Severity = recordField(lr, recordSeverity)
+	}
+
+	// The line can't fail to be marshaled, being made of strings, maps and slices only.
+	b, _ := json.Marshal(line)
+	return string(b)
+}
+
+func unpromotedAttributes(attrs pdata.AttributeMap, promoted map[string]string) map[string]interface{} {
+	m := tracetranslator.AttributeMapToMap(attrs)
+	for key := range promoted {
+		delete(m, key)
+	}
+	return m
+}
+
+func attributeValue(v pdata.AttributeValue) interface{} {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		return v.StringVal()
+	case pdata.AttributeValueINT:
+		return v.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return v.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return v.BoolVal()
+	case pdata.AttributeValueMAP:
+		return tracetranslator.AttributeMapToMap(v.MapVal())
+	case pdata.AttributeValueARRAY:
+		return tracetranslator.AttributeArrayToSlice(v.ArrayVal())
+	}
+	return nil
+}
+
+// timestamp returns the timestamp of the log record in nanoseconds, Loki rejecting the entries without one.
+func timestamp(lr pdata.LogRecord) string {
+	ts := int64(lr.Timestamp())
+	if ts == 0 {
+		ts = time.Now().UnixNano()
+	}
+	return strconv.FormatInt(ts, 10)
+}
+
+// streamKey returns a key identifying the stream of the labels.
+func streamKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+strconv.Quote(value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
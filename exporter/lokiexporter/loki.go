@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//      http://www.apache.org/licenses/LICENSE-2.0
+//       http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -15,52 +15,172 @@
 package lokiexporter
 
 import (
+	"bytes"
 	"context"
-	"errors"
-	"net/url"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 
-	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
+// maxErrorBodySize is the size of the response body of Loki included in the errors.
+const maxErrorBodySize = 1024
+
 type lokiExporter struct {
-	pushLogData func(ctx context.Context, ld pdata.Logs) (numDroppedLogs int, err error)
-	stop        func(ctx context.Context) (err error)
-	start       func(ctx context.Context, host component.Host) (err error)
+	config *Config
+	client *http.Client
+
+	attributeLabels map[string]string
+	resourceLabels  map[string]string
+	recordLabels    map[string]string
+}
+
+// tenantLogs are the logs of a tenant, sent to Loki in the same request.
+type tenantLogs struct {
+	tenant string
+	logs   pdata.Logs
 }
 
 func newExporter(config *Config) (*lokiExporter, error) {
-	if _, err := url.Parse(config.Endpoint); config.Endpoint == "" || err != nil {
-		return nil, errors.New("endpoint must be a valid URL")
+	if err := config.validate(); err != nil {
+		return nil, err
 	}
 
-	_, err := config.HTTPClientSettings.ToClient()
+	client, err := config.HTTPClientSettings.ToClient()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(config.AttributesForLabels) == 0 {
-		return nil, errors.New("attributes_for_labels must have a least one label")
-	}
-
 	return &lokiExporter{
-		pushLogData: pushLogData,
-		stop:        stop,
-		start:       start,
+		config:          config,
+		client:          client,
+		attributeLabels: labelNames(config.Labels.Attributes),
+		resourceLabels:  labelNames(config.Labels.ResourceAttributes),
+		recordLabels:    labelNames(config.Labels.RecordAttributes),
 	}, nil
 }
 
-// TODO: Implement in second PR
-func pushLogData(ctx context.Context, ld pdata.Logs) (numDroppedLogs int, err error) {
-	return 0, nil
+func (e *lokiExporter) pushLogData(ctx context.Context, ld pdata.Logs) (numDroppedLogs int, err error) {
+	var (
+		errs      []error
+		failed    = pdata.NewLogs()
+		permanent = true
+	)
+
+	for _, tl := range e.logsByTenant(ld) {
+		req, dropped := e.logsToPushRequest(tl.logs)
+		numDroppedLogs += dropped
+		if len(req.Streams) == 0 {
+			continue
+		}
+
+		if err := e.send(ctx, tl.tenant, req); err != nil {
+			errs = append(errs, err)
+			numDroppedLogs += tl.logs.LogRecordCount() - dropped
+			if !consumererror.IsPermanent(err) {
+				permanent = false
+				appendResourceLogs(failed, tl.logs)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return numDroppedLogs, nil
+	}
+	err = componenterror.CombineErrors(errs)
+	if permanent {
+		return numDroppedLogs, consumererror.Permanent(err)
+	}
+	// Only the logs of the tenants whose request failed with a retryable error are retried.
+	return numDroppedLogs, consumererror.PartialLogsError(err, failed)
 }
 
-// TODO: Implement in second PR
-func start(ctx context.Context, host component.Host) (err error) {
-	return nil
+// logsByTenant groups the logs by tenant: the tenant of a resource is read from its attributes when configured so,
+// otherwise all the logs belong to the same tenant.
+func (e *lokiExporter) logsByTenant(ld pdata.Logs) []tenantLogs {
+	switch {
+	case e.config.Tenant == nil:
+		return []tenantLogs{{logs: ld}}
+	case e.config.Tenant.Source == tenantSourceStatic:
+		return []tenantLogs{{tenant: e.config.Tenant.Value, logs: ld}}
+	}
+
+	var (
+		groups  []tenantLogs
+		indexes = map[string]int{}
+	)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		var tenant string
+		if v, ok := rl.Resource().Attributes().Get(e.config.Tenant.Value); ok {
+			tenant = v.StringVal()
+		}
+
+		index, ok := indexes[tenant]
+		if !ok {
+			index = len(groups)
+			indexes[tenant] = index
+			groups = append(groups, tenantLogs{tenant: tenant, logs: pdata.NewLogs()})
+		}
+		groupRls := groups[index].logs.ResourceLogs()
+		groupRls.Resize(groupRls.Len() + 1)
+		rl.CopyTo(groupRls.At(groupRls.Len() - 1))
+	}
+	return groups
+}
+
+func appendResourceLogs(dest pdata.Logs, ld pdata.Logs) {
+	rls := ld.ResourceLogs()
+	destRls := dest.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		destRls.Resize(destRls.Len() + 1)
+		rls.At(i).CopyTo(destRls.At(destRls.Len() - 1))
+	}
+}
+
+// send sends the push request to Loki, with the tenant in the X-Scope-OrgID header when not empty.
+func (e *lokiExporter) send(ctx context.Context, tenant string, pr *pushRequest) error {
+	body, err := json.Marshal(pr)
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if tenant != "" {
+		req.Header.Set(tenantHeader, tenant)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("HTTP %d %q: %s", resp.StatusCode, http.StatusText(resp.StatusCode), bytes.TrimSpace(msg))
+	// Loki rejects the invalid entries, e.g. out of order ones, with a 4XX code: retrying them wouldn't succeed.
+	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+		return consumererror.Permanent(err)
+	}
+	return err
 }
 
-// TODO: Implement in second PR
-func stop(ctx context.Context) (err error) {
+func (e *lokiExporter) stop(context.Context) error {
+	e.client.CloseIdleConnections()
 	return nil
 }
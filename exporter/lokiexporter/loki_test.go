@@ -16,14 +16,19 @@ package lokiexporter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
@@ -33,10 +38,6 @@ const (
 	validEndpoint = "https://loki:3100/loki/api/v1/push"
 )
 
-var (
-	validLabels = []string{"app", "level"}
-)
-
 func createLogData(numberOfLogs int) pdata.Logs {
 	logs := pdata.NewLogs()
 	logs.ResourceLogs().Resize(1)
@@ -78,7 +79,7 @@ func TestNoAllowedLabelsReturnsError(t *testing.T) {
 		HTTPClientSettings: confighttp.HTTPClientSettings{
 			Endpoint: validEndpoint,
 		},
-		AttributesForLabels: nil,
+		Format: formatBody,
 	}
 	f := NewFactory()
 	params := component.ExporterCreateParams{Logger: zap.NewNop()}
@@ -86,40 +87,234 @@ func TestNoAllowedLabelsReturnsError(t *testing.T) {
 	require.Error(t, err)
 }
 
-func TestPushLogDataReturnsWithoutErrorWhileNotImplemented(t *testing.T) {
-	config := &Config{
-		HTTPClientSettings: confighttp.HTTPClientSettings{
-			Endpoint: validEndpoint,
-		},
-		AttributesForLabels: validLabels,
+// lokiCapture is a Loki server capturing the push requests, answering with the status code of their tenant, or
+// 204 by default.
+type lokiCapture struct {
+	mu       sync.Mutex
+	requests map[string]*pushRequest
+	statuses map[string]int
+}
+
+func newLokiCapture(t *testing.T, statuses map[string]int) (*lokiCapture, *httptest.Server) {
+	c := &lokiCapture{requests: map[string]*pushRequest{}, statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var req pushRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		tenant := r.Header.Get(tenantHeader)
+		c.mu.Lock()
+		c.requests[tenant] = &req
+		c.mu.Unlock()
+
+		if status, ok := c.statuses[tenant]; ok {
+			http.Error(w, "rejected", status)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return c, server
+}
+
+func newTestExporter(t *testing.T, endpoint string, modify func(cfg *Config)) *lokiExporter {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint
+	cfg.Labels.Attributes = map[string]string{
+		conventions.AttributeContainerName: "",
 	}
-	e, err := newExporter(config)
-	assert.NoError(t, err)
+	if modify != nil {
+		modify(cfg)
+	}
+	e, err := newExporter(cfg)
+	require.NoError(t, err)
+	return e
+}
+
+func TestPushLogData(t *testing.T) {
+	capture, server := newLokiCapture(t, nil)
+	e := newTestExporter(t, server.URL, func(cfg *Config) {
+		cfg.Labels.ResourceAttributes = map[string]string{"k8s.cluster.name": "cluster"}
+	})
 
-	_, err = e.pushLogData(context.Background(), createLogData(1))
-	assert.NoError(t, err)
+	ld := createLogData(2)
+	ld.ResourceLogs().At(0).Resource().Attributes().InsertString("k8s.cluster.name", "prod")
+	numDroppedLogs, err := e.pushLogData(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 0, numDroppedLogs)
+
+	require.Contains(t, capture.requests, "")
+	req := capture.requests[""]
+	require.Len(t, req.Streams, 1)
+	assert.Equal(t, map[string]string{"container_name": "api", "cluster": "prod"}, req.Streams[0].Labels)
+	require.Len(t, req.Streams[0].Values, 2)
+	// The first log record has no timestamp, so it gets the current time.
+	assert.NotEqual(t, "0", req.Streams[0].Values[0][0])
+	assert.Equal(t, [2]string{"1000000", "mylog"}, req.Streams[0].Values[1])
 }
 
-func TestExporterStartAlwaysReturnsNil(t *testing.T) {
-	config := &Config{
-		HTTPClientSettings: confighttp.HTTPClientSettings{
-			Endpoint: validEndpoint,
+func TestPushLogDataGroupsStreamsByLabels(t *testing.T) {
+	capture, server := newLokiCapture(t, nil)
+	e := newTestExporter(t, server.URL, nil)
+
+	ld := createLogData(3)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(1).Attributes().
+		UpdateString(conventions.AttributeContainerName, "db")
+	_, err := e.pushLogData(context.Background(), ld)
+	require.NoError(t, err)
+
+	req := capture.requests[""]
+	require.Len(t, req.Streams, 2)
+	assert.Equal(t, map[string]string{"container_name": "api"}, req.Streams[0].Labels)
+	assert.Len(t, req.Streams[0].Values, 2)
+	assert.Equal(t, map[string]string{"container_name": "db"}, req.Streams[1].Labels)
+	assert.Len(t, req.Streams[1].Values, 1)
+}
+
+func TestPushLogDataDropsLogsWithoutLabels(t *testing.T) {
+	capture, server := newLokiCapture(t, nil)
+	e := newTestExporter(t, server.URL, nil)
+
+	ld := createLogData(2)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Attributes().
+		Delete(conventions.AttributeContainerName)
+	numDroppedLogs, err := e.pushLogData(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 1, numDroppedLogs)
+
+	req := capture.requests[""]
+	require.Len(t, req.Streams, 1)
+	assert.Len(t, req.Streams[0].Values, 1)
+}
+
+func TestPushLogDataJSONFormat(t *testing.T) {
+	capture, server := newLokiCapture(t, nil)
+	e := newTestExporter(t, server.URL, func(cfg *Config) {
+		cfg.Format = formatJSON
+		cfg.Labels.RecordAttributes = map[string]string{recordSeverity: "level"}
+	})
+
+	ld := createLogData(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString("k8s.cluster.name", "prod")
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	lr.SetSeverityText("error")
+	lr.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	_, err := e.pushLogData(context.Background(), ld)
+	require.NoError(t, err)
+
+	req := capture.requests[""]
+	require.Len(t, req.Streams, 1)
+	assert.Equal(t, map[string]string{"container_name": "api", "level": "error"}, req.Streams[0].Labels)
+	require.Len(t, req.Streams[0].Values, 1)
+	assert.JSONEq(t, `{
+		"body": "mylog",
+		"traceid": "0102030405060708090a0b0c0d0e0f10",
+		"attributes": {
+			"service.name": "myapi",
+			"container.image.name": "nginx",
+			"host.name": "myhost",
+			"custom": "custom"
 		},
-		AttributesForLabels: validLabels,
+		"resources": {
+			"k8s.cluster.name": "prod"
+		}
+	}`, req.Streams[0].Values[0][1])
+}
+
+func TestPushLogDataStaticTenant(t *testing.T) {
+	capture, server := newLokiCapture(t, nil)
+	e := newTestExporter(t, server.URL, func(cfg *Config) {
+		cfg.Tenant = &TenantConfig{Source: tenantSourceStatic, Value: "acme"}
+	})
+
+	_, err := e.pushLogData(context.Background(), createLogData(1))
+	require.NoError(t, err)
+	assert.Len(t, capture.requests, 1)
+	assert.Contains(t, capture.requests, "acme")
+}
+
+func TestPushLogDataResourceTenant(t *testing.T) {
+	capture, server := newLokiCapture(t, map[string]int{"b": http.StatusServiceUnavailable})
+	e := newTestExporter(t, server.URL, func(cfg *Config) {
+		cfg.Tenant = &TenantConfig{Source: tenantSourceResource, Value: "tenant.id"}
+	})
+
+	ld := pdata.NewLogs()
+	for _, tenant := range []string{"a", "b", "a"} {
+		logs := createLogData(1)
+		logs.ResourceLogs().At(0).Resource().Attributes().InsertString("tenant.id", tenant)
+		appendResourceLogs(ld, logs)
 	}
-	e, err := newExporter(config)
-	assert.NoError(t, err)
-	assert.NoError(t, e.start(context.Background(), componenttest.NewNopHost()))
+
+	numDroppedLogs, err := e.pushLogData(context.Background(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, numDroppedLogs)
+
+	require.Len(t, capture.requests, 2)
+	require.Len(t, capture.requests["a"].Streams, 1)
+	assert.Len(t, capture.requests["a"].Streams[0].Values, 2)
+
+	// Only the logs of the tenant whose request failed are retried.
+	var partialErr consumererror.PartialError
+	require.True(t, errors.As(err, &partialErr))
+	failed := partialErr.GetLogs()
+	require.Equal(t, 1, failed.ResourceLogs().Len())
+	tenant, _ := failed.ResourceLogs().At(0).Resource().Attributes().Get("tenant.id")
+	assert.Equal(t, "b", tenant.StringVal())
 }
 
-func TestExporterStopAlwaysReturnsNil(t *testing.T) {
-	config := &Config{
-		HTTPClientSettings: confighttp.HTTPClientSettings{
-			Endpoint: validEndpoint,
-		},
-		AttributesForLabels: validLabels,
+func TestPushLogDataPermanentError(t *testing.T) {
+	_, server := newLokiCapture(t, map[string]int{"": http.StatusBadRequest})
+	e := newTestExporter(t, server.URL, nil)
+
+	numDroppedLogs, err := e.pushLogData(context.Background(), createLogData(2))
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Contains(t, err.Error(), "rejected")
+	assert.Equal(t, 2, numDroppedLogs)
+}
+
+func TestPushLogDataTooManyRequestsIsRetryable(t *testing.T) {
+	_, server := newLokiCapture(t, map[string]int{"": http.StatusTooManyRequests})
+	e := newTestExporter(t, server.URL, nil)
+
+	_, err := e.pushLogData(context.Background(), createLogData(1))
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+}
+
+func TestSanitizeLabelName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "level", expected: "level"},
+		{name: "container.name", expected: "container_name"},
+		{name: "k8s-pod/name", expected: "k8s_pod_name"},
+		{name: "0day", expected: "_0day"},
+		{name: "café", expected: "caf_"},
+		{name: "", expected: "_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeLabelName(tt.name))
+		})
 	}
-	e, err := newExporter(config)
-	assert.NoError(t, err)
+}
+
+func TestLabelNames(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"container.name": "container_name",
+		"k8s.pod.name":   "pod",
+	}, labelNames(map[string]string{
+		"container.name": "",
+		"k8s.pod.name":   "pod",
+	}))
+}
+
+func TestExporterStopAlwaysReturnsNil(t *testing.T) {
+	e := newTestExporter(t, validEndpoint, nil)
 	assert.NoError(t, e.stop(context.Background()))
 }
@@ -21,4 +21,6 @@ type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 	// DSN to report transaction to Sentry. If the DSN is not set, no trace will be sent to Sentry.
 	DSN string `mapstructure:"dsn"`
+	// Environment is set on the transactions and errors sent to Sentry, e.g. "production". Not set by default.
+	Environment string `mapstructure:"environment"`
 }
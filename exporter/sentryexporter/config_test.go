@@ -47,6 +47,7 @@ func TestLoadConfig(t *testing.T) {
 			NameVal: "sentry/2",
 			TypeVal: "sentry",
 		},
-		DSN: "https://key@host/path/42",
+		DSN:         "https://key@host/path/42",
+		Environment: "production",
 	})
 }
@@ -34,17 +34,25 @@ const (
 	otelSentryExporterName    = "sentry.opentelemetry"
 )
 
-// canonicalCodes maps OpenTelemetry span codes to Sentry's span status.
+// canonicalCodes maps OpenTelemetry span codes to Sentry's span status. The error code is mapped to
+// internal_error so that Sentry counts the transactions of failed root spans as failed transactions.
 // See numeric codes in https://github.com/open-telemetry/opentelemetry-proto/blob/6cf77b2f544f6bc7fe1e4b4a8a52e5a42cb50ead/opentelemetry/proto/trace/v1/trace.proto#L303
 var canonicalCodes = [...]string{
 	"unknown",
 	"ok",
-	"unknown",
+	"internal_error",
 }
 
 // SentryExporter defines the Sentry Exporter.
 type SentryExporter struct {
-	transport transport
+	transport   transport
+	environment string
+}
+
+// exceptionEvent is a Sentry error event created from an exception event of a span.
+type exceptionEvent struct {
+	spanID string
+	event  *sentry.Event
 }
 
 // pushTraceData takes an incoming OpenTelemetry trace, converts them into Sentry spans and transactions
@@ -56,6 +64,7 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 	}
 
 	maybeOrphanSpans := make([]*sentry.Span, 0, td.SpanCount())
+	var exceptionEvents []exceptionEvent
 
 	// Maps all child span ids to their root span.
 	idMap := make(map[string]string)
@@ -73,7 +82,12 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 
 			spans := ils.Spans()
 			for k := 0; k < spans.Len(); k++ {
-				sentrySpan := convertToSentrySpan(spans.At(k), library, resourceTags)
+				otelSpan := spans.At(k)
+				sentrySpan := convertToSentrySpan(otelSpan, library, resourceTags)
+
+				for _, event := range convertExceptionEvents(otelSpan.Events(), sentrySpan) {
+					exceptionEvents = append(exceptionEvents, exceptionEvent{spanID: sentrySpan.SpanID, event: event})
+				}
 
 				// If a span is a root span, we consider it the start of a Sentry transaction.
 				// We should then create a new transaction for that root span, and keep track of it.
@@ -95,7 +109,7 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 		}
 	}
 
-	if len(transactionMap) == 0 {
+	if len(transactionMap) == 0 && len(maybeOrphanSpans) == 0 {
 		return 0, nil
 	}
 
@@ -103,29 +117,71 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 	// the spans with a transaction. As such, we must classify the remaining spans as orphans or not.
 	orphanSpans := classifyAsOrphanSpans(maybeOrphanSpans, len(maybeOrphanSpans)+1, idMap, transactionMap)
 
-	transactions := generateTransactions(transactionMap, orphanSpans)
+	// The parents of the remaining orphan spans are not part of this batch, so we group them under
+	// transactions of their own.
+	groupOrphanSpans(orphanSpans, idMap, transactionMap)
+
+	events := generateTransactions(transactionMap)
 
-	s.transport.SendTransactions(transactions)
+	for _, e := range exceptionEvents {
+		if transaction, ok := transactionMap[idMap[e.spanID]]; ok {
+			e.event.Transaction = transaction.Transaction
+		}
+		events = append(events, e.event)
+	}
+
+	for _, event := range events {
+		event.Environment = s.environment
+	}
+
+	s.transport.SendEvents(events)
 
 	return 0, nil
 }
 
-// generateTransactions creates a set of Sentry transactions from a transaction map and orphan spans.
-func generateTransactions(transactionMap map[string]*sentry.Event, orphanSpans []*sentry.Span) []*sentry.Event {
-	transactions := make([]*sentry.Event, 0, len(transactionMap)+len(orphanSpans))
+// generateTransactions creates a set of Sentry transactions from a transaction map.
+func generateTransactions(transactionMap map[string]*sentry.Event) []*sentry.Event {
+	transactions := make([]*sentry.Event, 0, len(transactionMap))
 
 	for _, t := range transactionMap {
 		transactions = append(transactions, t)
 	}
 
-	for _, orphanSpan := range orphanSpans {
-		t := transactionFromSpan(orphanSpan)
-		transactions = append(transactions, t)
-	}
-
 	return transactions
 }
 
+// groupOrphanSpans creates transactions out of orphan spans, which could not be associated with a
+// transaction as their parent is not part of the batch. An orphan span whose parent is not an orphan
+// span either becomes the root of a new transaction, and its descendants are then associated with it,
+// so that an orphan subtree of a trace is sent as a single transaction.
+func groupOrphanSpans(orphanSpans []*sentry.Span, idMap map[string]string, transactionMap map[string]*sentry.Event) {
+	for len(orphanSpans) > 0 {
+		orphanSpanIDs := make(map[string]bool, len(orphanSpans))
+		for _, orphanSpan := range orphanSpans {
+			orphanSpanIDs[orphanSpan.SpanID] = true
+		}
+
+		remainingSpans := make([]*sentry.Span, 0, len(orphanSpans))
+		for _, orphanSpan := range orphanSpans {
+			if orphanSpanIDs[orphanSpan.ParentSpanID] {
+				remainingSpans = append(remainingSpans, orphanSpan)
+				continue
+			}
+			transactionMap[orphanSpan.SpanID] = transactionFromSpan(orphanSpan)
+			idMap[orphanSpan.SpanID] = orphanSpan.SpanID
+		}
+
+		// Only spans whose ids form a cycle are left, which we break by making the first one a root.
+		if len(remainingSpans) == len(orphanSpans) {
+			transactionMap[remainingSpans[0].SpanID] = transactionFromSpan(remainingSpans[0])
+			idMap[remainingSpans[0].SpanID] = remainingSpans[0].SpanID
+			remainingSpans = remainingSpans[1:]
+		}
+
+		orphanSpans = classifyAsOrphanSpans(remainingSpans, len(remainingSpans)+1, idMap, transactionMap)
+	}
+}
+
 // classifyAsOrphanSpans iterates through a list of possible orphan spans and tries to associate them
 // with a transaction. As the order of the spans is not guaranteed, we have to recursively call
 // classifyAsOrphanSpans to make sure that we did not leave any spans out of the transaction they belong to.
@@ -193,6 +249,70 @@ func convertToSentrySpan(span pdata.Span, library pdata.InstrumentationLibrary,
 	return sentrySpan
 }
 
+// convertExceptionEvents converts the exception events of a span into Sentry error events, with the
+// trace context of the span so that Sentry associates them with its transaction.
+//
+// See https://github.com/open-telemetry/opentelemetry-specification/blob/5b78ee1/specification/trace/semantic_conventions/exceptions.md
+// for more details about the exception events.
+func convertExceptionEvents(spanEvents pdata.SpanEventSlice, sentrySpan *sentry.Span) []*sentry.Event {
+	var events []*sentry.Event
+
+	for i := 0; i < spanEvents.Len(); i++ {
+		spanEvent := spanEvents.At(i)
+		if spanEvent.Name() != conventions.AttributeExceptionEventName {
+			continue
+		}
+
+		attrs := spanEvent.Attributes()
+		var exceptionType, message string
+		if v, ok := attrs.Get(conventions.AttributeExceptionType); ok {
+			exceptionType = v.StringVal()
+		}
+		if v, ok := attrs.Get(conventions.AttributeExceptionMessage); ok {
+			message = v.StringVal()
+		}
+		// The specification requires one of the type or message to be set.
+		if exceptionType == "" && message == "" {
+			continue
+		}
+
+		event := sentry.NewEvent()
+		event.EventID = generateEventID()
+		event.Level = sentry.LevelError
+		event.Platform = "other"
+
+		event.Sdk.Name = otelSentryExporterName
+		event.Sdk.Version = otelSentryExporterVersion
+
+		event.Contexts["trace"] = sentry.TraceContext{
+			TraceID: sentrySpan.TraceID,
+			SpanID:  sentrySpan.SpanID,
+			Op:      sentrySpan.Op,
+			Status:  sentrySpan.Status,
+		}
+
+		for k, v := range sentrySpan.Tags {
+			event.Tags[k] = v
+		}
+
+		// Stack traces are formatted by each language, so we keep them as they are instead of
+		// parsing them into frames.
+		if v, ok := attrs.Get(conventions.AttributeExceptionStacktrace); ok && v.StringVal() != "" {
+			event.Extra[conventions.AttributeExceptionStacktrace] = v.StringVal()
+		}
+
+		event.Exception = []sentry.Exception{{
+			Type:  exceptionType,
+			Value: message,
+		}}
+		event.Timestamp = unixNanoToTime(spanEvent.Timestamp())
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
 // generateSpanDescriptors generates generate span descriptors (op and description)
 // from the name, attributes and SpanKind of an otel span based onSemantic Conventions
 // described by the open telemetry specification.
@@ -333,7 +453,8 @@ func CreateSentryExporter(config *Config, params component.ExporterCreateParams)
 	})
 
 	s := &SentryExporter{
-		transport: transport,
+		transport:   transport,
+		environment: config.Environment,
 	}
 
 	return exporterhelper.NewTraceExporter(
@@ -21,6 +21,7 @@ import (
 	"github.com/getsentry/sentry-go"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 )
@@ -368,7 +369,7 @@ func TestStatusFromSpanStatus(t *testing.T) {
 
 				return spanStatus
 			}(),
-			status:  "unknown",
+			status:  "internal_error",
 			message: "message",
 		},
 		{
@@ -466,22 +467,84 @@ func TestClassifyOrphanSpans(t *testing.T) {
 	}
 }
 
+func TestGroupOrphanSpans(t *testing.T) {
+	t.Run("with orphan subtrees", func(t *testing.T) {
+		idMap := make(map[string]string)
+		transactionMap := make(map[string]*sentry.Event)
+
+		// childSpan1 is the parent of childChildSpan1, and the parents of childSpan1 and orphanSpan1
+		// are not part of the batch.
+		groupOrphanSpans(generateOrphanSpansFromSpans(childChildSpan1, orphanSpan1, childSpan1), idMap, transactionMap)
+
+		assert.Len(t, transactionMap, 2)
+		assert.Equal(t, []*sentry.Span{childChildSpan1}, transactionMap[childSpan1.SpanID].Spans)
+		assert.Empty(t, transactionMap[orphanSpan1.SpanID].Spans)
+		assert.Equal(t, childSpan1.SpanID, idMap[childChildSpan1.SpanID])
+	})
+
+	t.Run("with span id cycle", func(t *testing.T) {
+		idMap := make(map[string]string)
+		transactionMap := make(map[string]*sentry.Event)
+
+		span1 := &sentry.Span{SpanID: "1", ParentSpanID: "2"}
+		span2 := &sentry.Span{SpanID: "2", ParentSpanID: "1"}
+		groupOrphanSpans(generateOrphanSpansFromSpans(span1, span2), idMap, transactionMap)
+
+		assert.Len(t, transactionMap, 1)
+		assert.Equal(t, []*sentry.Span{span2}, transactionMap[span1.SpanID].Spans)
+	})
+}
+
 func TestGenerateTransactions(t *testing.T) {
 	transactionMap := generateEmptyTransactionMap(rootSpan1, rootSpan2)
-	orphanSpans := generateOrphanSpansFromSpans(orphanSpan1, childSpan1)
 
-	transactions := generateTransactions(transactionMap, orphanSpans)
+	transactions := generateTransactions(transactionMap)
 
-	assert.Len(t, transactions, 4)
+	assert.Len(t, transactions, 2)
+}
+
+func TestConvertExceptionEvents(t *testing.T) {
+	events := pdata.NewSpanEventSlice()
+	events.Resize(3)
+
+	exception := events.At(0)
+	exception.SetName(conventions.AttributeExceptionEventName)
+	exception.SetTimestamp(7)
+	exception.Attributes().InsertString(conventions.AttributeExceptionType, "ZeroDivisionError")
+	exception.Attributes().InsertString(conventions.AttributeExceptionMessage, "division by zero")
+	exception.Attributes().InsertString(conventions.AttributeExceptionStacktrace, "Traceback (most recent call last): ...")
+
+	// Exception events without type nor message are ignored, as well as the other events.
+	events.At(1).SetName(conventions.AttributeExceptionEventName)
+	events.At(2).SetName("message")
+	events.At(2).Attributes().InsertString(conventions.AttributeExceptionMessage, "not an exception")
+
+	actual := convertExceptionEvents(events, childSpan1)
+	require.Len(t, actual, 1)
+
+	event := actual[0]
+	assert.Len(t, event.EventID, 32)
+	assert.Equal(t, sentry.LevelError, event.Level)
+	assert.Equal(t, otelSentryExporterName, event.Sdk.Name)
+	assert.Equal(t, []sentry.Exception{{Type: "ZeroDivisionError", Value: "division by zero"}}, event.Exception)
+	assert.Equal(t, "Traceback (most recent call last): ...", event.Extra[conventions.AttributeExceptionStacktrace])
+	assert.Equal(t, childSpan1.Tags, event.Tags)
+	assert.Equal(t, unixNanoToTime(7), event.Timestamp)
+	assert.Equal(t, sentry.TraceContext{
+		TraceID: childSpan1.TraceID,
+		SpanID:  childSpan1.SpanID,
+		Op:      childSpan1.Op,
+		Status:  childSpan1.Status,
+	}, event.Contexts["trace"])
 }
 
 type mockTransport struct {
-	called       bool
-	transactions []*sentry.Event
+	called bool
+	events []*sentry.Event
 }
 
-func (t *mockTransport) SendTransactions(transactions []*sentry.Event) {
-	t.transactions = transactions
+func (t *mockTransport) SendEvents(events []*sentry.Event) {
+	t.events = events
 	t.called = true
 }
 
@@ -554,3 +617,43 @@ func TestPushTraceData(t *testing.T) {
 		})
 	}
 }
+
+func TestPushTraceDataWithOrphanErrorSpan(t *testing.T) {
+	traces := pdata.NewTraces()
+	resourceSpans := traces.ResourceSpans()
+	resourceSpans.Resize(1)
+	resourceSpans.At(0).InstrumentationLibrarySpans().Resize(1)
+	spans := resourceSpans.At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(1)
+
+	// The parent of the span is not part of the batch.
+	span := spans.At(0)
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 8, 7, 6, 5, 4, 3, 2, 1}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.SetParentSpanID(pdata.NewSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}))
+	span.SetName("process_payment")
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Events().Resize(1)
+	span.Events().At(0).SetName(conventions.AttributeExceptionEventName)
+	span.Events().At(0).Attributes().InsertString(conventions.AttributeExceptionType, "PaymentError")
+
+	transport := &mockTransport{}
+	s := &SentryExporter{
+		transport:   transport,
+		environment: "production",
+	}
+
+	_, err := s.pushTraceData(context.Background(), traces)
+	require.NoError(t, err)
+	require.Len(t, transport.events, 2)
+
+	transaction, event := transport.events[0], transport.events[1]
+	assert.Equal(t, "transaction", transaction.Type)
+	assert.Equal(t, "process_payment", transaction.Transaction)
+	assert.Equal(t, "internal_error", transaction.Contexts["trace"].(sentry.TraceContext).Status)
+	assert.Equal(t, "production", transaction.Environment)
+
+	assert.Equal(t, []sentry.Exception{{Type: "PaymentError"}}, event.Exception)
+	assert.Equal(t, "process_payment", event.Transaction)
+	assert.Equal(t, "production", event.Environment)
+}
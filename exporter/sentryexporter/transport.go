@@ -23,7 +23,7 @@ import (
 
 // transport is used by exporter to send events to Sentry
 type transport interface {
-	SendTransactions(transactions []*sentry.Event)
+	SendEvents(events []*sentry.Event)
 	Configure(options sentry.ClientOptions)
 	Flush(ctx context.Context) bool
 }
@@ -52,18 +52,20 @@ func (t *sentryTransport) Flush(ctx context.Context) bool {
 	return t.httpTransport.Flush(time.Second)
 }
 
-// sendTransactions uses a Sentry HTTPTransport to send transaction events to Sentry
-func (t *sentryTransport) SendTransactions(transactions []*sentry.Event) {
+// SendEvents uses a Sentry HTTPTransport to send transaction and error events to Sentry.
+// While Sentry rate limits the project, i.e. until the time given by the Retry-After header of
+// its last 429 response, the HTTPTransport drops the events instead of sending them.
+func (t *sentryTransport) SendEvents(events []*sentry.Event) {
 	bufferCounter := 0
-	for _, transaction := range transactions {
-		// We should flush all events when we send transactions equal to the transport
-		// buffer size so we don't drop transactions.
+	for _, event := range events {
+		// We should flush all events when we send events equal to the transport
+		// buffer size so we don't drop events.
 		if bufferCounter == t.httpTransport.BufferSize {
 			t.httpTransport.Flush(time.Second)
 			bufferCounter = 0
 		}
 
-		t.httpTransport.SendEvent(transaction)
+		t.httpTransport.SendEvent(event)
 		bufferCounter++
 	}
 }
@@ -15,8 +15,11 @@
 package sentryexporter
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
+	"github.com/getsentry/sentry-go"
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
@@ -25,3 +28,12 @@ import (
 func unixNanoToTime(u pdata.TimestampUnixNano) time.Time {
 	return time.Unix(0, int64(u)).UTC()
 }
+
+// generateEventID returns a random Sentry event id, which is a UUID4 without dashes.
+func generateEventID() sentry.EventID {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return sentry.EventID(hex.EncodeToString(id))
+}
@@ -42,6 +42,7 @@ type Tracker struct {
 
 type correlationContext struct {
 	correlations.CorrelationClient
+	ctx    context.Context
 	cancel context.CancelFunc
 }
 
@@ -83,6 +84,7 @@ func newCorrelationClient(cfg *Config, accessToken string, params component.Expo
 
 	return &correlationContext{
 		CorrelationClient: client,
+		ctx:               ctx,
 		cancel:            cancel,
 	}, nil
 }
@@ -143,6 +145,7 @@ func (cor *Tracker) AddSpans(ctx context.Context, traces pdata.Traces) (dropped
 func (cor *Tracker) start() {
 	if cor != nil && cor.correlation != nil {
 		cor.correlation.Start()
+		go newInternalMetricsRecorder(cor.correlation).run(cor.correlation.ctx, internalMetricsInterval)
 	}
 }
 
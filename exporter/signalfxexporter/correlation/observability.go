@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package correlation
+
+import (
+	"context"
+	"time"
+
+	"github.com/signalfx/golib/v3/datapoint"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// internalMetricsInterval is how often the internal metrics of the
+// correlation client are recorded.
+const internalMetricsInterval = 10 * time.Second
+
+func init() {
+	view.Register(
+		viewCorrelationUpdates,
+	)
+}
+
+var (
+	tagResult, _ = tag.NewKey("result")
+
+	mCorrelationUpdates = stats.Int64("otelcol/signalfx/correlation_updates",
+		"Number of requests sent to the correlation API, by result", "1")
+)
+
+var viewCorrelationUpdates = &view.View{
+	Name:        mCorrelationUpdates.Name(),
+	Description: mCorrelationUpdates.Description(),
+	Measure:     mCorrelationUpdates,
+	TagKeys:     []tag.Key{tagResult},
+	Aggregation: view.Sum(),
+}
+
+// internalMetricResults maps the cumulative internal metrics of the
+// correlation client to the result they're recorded with.
+var internalMetricResults = map[string]string{
+	"sfxagent.dim_updates_completed":             "success",
+	"sfxagent.dim_updates_failed":                "failure",
+	"sfxagent.correlation_updates_retries":       "retried",
+	"sfxagent.correlation_updates_client_errors": "client_error",
+	"sfxagent.correlation_updates_invalid":       "invalid",
+}
+
+type internalMetricsSource interface {
+	InternalMetrics() []*datapoint.Datapoint
+}
+
+// internalMetricsRecorder records the increases of the cumulative internal
+// metrics of the correlation client, which only exposes them as datapoints.
+type internalMetricsRecorder struct {
+	source internalMetricsSource
+	last   map[string]int64
+}
+
+func newInternalMetricsRecorder(source internalMetricsSource) *internalMetricsRecorder {
+	return &internalMetricsRecorder{
+		source: source,
+		last:   map[string]int64{},
+	}
+}
+
+func (r *internalMetricsRecorder) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.record()
+		}
+	}
+}
+
+func (r *internalMetricsRecorder) record() {
+	for _, dp := range r.source.InternalMetrics() {
+		result, ok := internalMetricResults[dp.Metric]
+		if !ok {
+			continue
+		}
+		value, ok := dp.Value.(datapoint.IntValue)
+		if !ok {
+			continue
+		}
+
+		current := value.Int()
+		if delta := current - r.last[dp.Metric]; delta > 0 {
+			_ = stats.RecordWithTags(context.Background(),
+				[]tag.Mutator{tag.Upsert(tagResult, result)}, mCorrelationUpdates.M(delta))
+		}
+		r.last[dp.Metric] = current
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package correlation
+
+import (
+	"testing"
+
+	"github.com/signalfx/golib/v3/datapoint"
+	"github.com/signalfx/golib/v3/sfxclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+)
+
+type fakeInternalMetrics struct {
+	retries      int64
+	clientErrors int64
+}
+
+func (f *fakeInternalMetrics) InternalMetrics() []*datapoint.Datapoint {
+	return []*datapoint.Datapoint{
+		sfxclient.CumulativeP("sfxagent.correlation_updates_retries", nil, &f.retries),
+		sfxclient.CumulativeP("sfxagent.correlation_updates_client_errors", nil, &f.clientErrors),
+		sfxclient.Gauge("sfxagent.dim_request_senders", nil, 3),
+	}
+}
+
+func correlationUpdates(t *testing.T, result string) int64 {
+	rows, err := view.RetrieveData(viewCorrelationUpdates.Name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		if len(row.Tags) == 1 && row.Tags[0].Value == result {
+			return int64(row.Data.(*view.SumData).Value)
+		}
+	}
+	return 0
+}
+
+func TestInternalMetricsRecorder(t *testing.T) {
+	source := &fakeInternalMetrics{retries: 2}
+	recorder := newInternalMetricsRecorder(source)
+
+	retries := correlationUpdates(t, "retried")
+	clientErrors := correlationUpdates(t, "client_error")
+
+	recorder.record()
+	assert.Equal(t, retries+2, correlationUpdates(t, "retried"))
+	assert.Equal(t, clientErrors, correlationUpdates(t, "client_error"))
+
+	// only the increases since the previous recording are recorded
+	source.retries = 5
+	source.clientErrors = 1
+	recorder.record()
+	assert.Equal(t, retries+5, correlationUpdates(t, "retried"))
+	assert.Equal(t, clientErrors+1, correlationUpdates(t, "client_error"))
+
+	recorder.record()
+	assert.Equal(t, retries+5, correlationUpdates(t, "retried"))
+	assert.Equal(t, clientErrors+1, correlationUpdates(t, "client_error"))
+}
@@ -57,6 +57,11 @@ type DimensionClient struct {
 	// Queue of dimensions to update.  The ordering should never change once
 	// put in the queue so no need for heap/priority queue.
 	delayedQueue chan *queuedDimension
+	// Number of consecutive failed attempts of the dimensions being retried,
+	// used to back off exponentially from retryDelay up to maxRetryDelay.
+	retries       map[DimensionKey]int
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
 	// For easier unit testing
 	now func() time.Time
 
@@ -75,6 +80,11 @@ type DimensionClient struct {
 	metricTranslator             *translation.MetricTranslator
 }
 
+const (
+	defaultRetryDelay    = 5 * time.Second
+	defaultMaxRetryDelay = 5 * time.Minute
+)
+
 type queuedDimension struct {
 	*DimensionUpdate
 	TimeToSend time.Time
@@ -119,6 +129,9 @@ func NewDimensionClient(ctx context.Context, options DimensionClientOptions) *Di
 		delayedQueue:     make(chan *queuedDimension, options.PropertiesMaxBuffered),
 		requestSender:    sender,
 		client:           client,
+		retries:          make(map[DimensionKey]int),
+		retryDelay:       defaultRetryDelay,
+		maxRetryDelay:    defaultMaxRetryDelay,
 		now:              time.Now,
 		logger:           options.Logger,
 		logUpdates:       options.LogUpdates,
@@ -158,6 +171,7 @@ func (dc *DimensionClient) acceptDimension(dimUpdate *DimensionUpdate) error {
 		default:
 			dc.TotalDimensionsDropped++
 			atomic.AddInt64(&dc.DimensionsCurrentlyDelayed, int64(-1))
+			recordDimensionUpdate(updateResultDropped)
 			return errors.New("dropped dimension update, propertiesMaxBuffered exceeded")
 		}
 	}
@@ -238,6 +252,8 @@ func (dc *DimensionClient) handleDimensionUpdate(dimUpdate *DimensionUpdate) err
 		context.WithValue(req.Context(), RequestFailedCallbackKey, RequestFailedCallback(func(statusCode int, err error) {
 			if statusCode >= 400 && statusCode < 500 && statusCode != 404 {
 				atomic.AddInt64(&dc.TotalClientError4xxResponses, int64(1))
+				recordDimensionUpdate(updateResultClientError)
+				dc.resetRetries(dimUpdate)
 				dc.logger.Error(
 					"Unable to update dimension, not retrying",
 					zap.Error(err),
@@ -253,29 +269,40 @@ func (dc *DimensionClient) handleDimensionUpdate(dimUpdate *DimensionUpdate) err
 				return
 			}
 
+			delay := dc.nextRetryDelay(dimUpdate)
 			dc.logger.Error(
 				"Unable to update dimension, retrying",
 				zap.Error(err),
 				zap.String("URL", req.URL.String()),
 				zap.String("dimensionUpdate", dimUpdate.String()),
+				zap.Duration("retryDelay", delay),
 			)
 			atomic.AddInt64(&dc.TotalRetriedUpdates, int64(1))
+			recordDimensionUpdate(updateResultRetried)
 			// The retry is meant to provide some measure of robustness against
 			// temporary API failures.  If the API is down for significant
 			// periods of time, dimension updates will probably eventually back
 			// up beyond PropertiesMaxBuffered and start dropping.
-			if err := dc.acceptDimension(dimUpdate); err != nil {
-				dc.logger.Error(
-					"Failed to retry dimension update",
-					zap.Error(err),
-					zap.String("URL", req.URL.String()),
-					zap.String("dimensionUpdate", dimUpdate.String()),
-				)
-			}
+			time.AfterFunc(delay, func() {
+				if dc.ctx.Err() != nil {
+					return
+				}
+				if err := dc.acceptDimension(dimUpdate); err != nil {
+					dc.logger.Error(
+						"Failed to retry dimension update",
+						zap.Error(err),
+						zap.String("URL", req.URL.String()),
+						zap.String("dimensionUpdate", dimUpdate.String()),
+					)
+				}
+			})
 		})))
 
 	req = req.WithContext(
 		context.WithValue(req.Context(), RequestSuccessCallbackKey, RequestSuccessCallback(func([]byte) {
+			atomic.AddInt64(&dc.TotalSuccessfulUpdates, int64(1))
+			recordDimensionUpdate(updateResultSuccess)
+			dc.resetRetries(dimUpdate)
 			if dc.logUpdates {
 				dc.logger.Info(
 					"Updated dimension",
@@ -289,6 +316,32 @@ func (dc *DimensionClient) handleDimensionUpdate(dimUpdate *DimensionUpdate) err
 	return nil
 }
 
+// nextRetryDelay returns how long to wait before retrying a failed update of
+// a dimension, doubling with each consecutive failure of this dimension.
+func (dc *DimensionClient) nextRetryDelay(dimUpdate *DimensionUpdate) time.Duration {
+	dc.Lock()
+	defer dc.Unlock()
+
+	retries := dc.retries[dimUpdate.Key()]
+	dc.retries[dimUpdate.Key()] = retries + 1
+
+	delay := dc.retryDelay
+	for i := 0; i < retries && delay < dc.maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > dc.maxRetryDelay {
+		delay = dc.maxRetryDelay
+	}
+	return delay
+}
+
+func (dc *DimensionClient) resetRetries(dimUpdate *DimensionUpdate) {
+	dc.Lock()
+	defer dc.Unlock()
+
+	delete(dc.retries, dimUpdate.Key())
+}
+
 func (dc *DimensionClient) makeDimURL(key, value string) (*url.URL, error) {
 	url, err := dc.APIURL.Parse(fmt.Sprintf("/v2/dimension/%s/%s", url.PathEscape(key), url.PathEscape(value)))
 	if err != nil {
@@ -27,7 +27,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
 )
 
@@ -120,6 +122,8 @@ func setup(t *testing.T) (*DimensionClient, chan dim, *atomic.Value, context.Can
 		SendDelay:             1,
 		PropertiesMaxBuffered: 10,
 	})
+	client.retryDelay = 100 * time.Millisecond
+	client.maxRetryDelay = 500 * time.Millisecond
 	client.Start()
 
 	return client, dimCh, &forcedResp, cancel
@@ -401,3 +405,58 @@ func newString(s string) *string {
 	out := s
 	return &out
 }
+
+func TestRetryBackoff(t *testing.T) {
+	client := NewDimensionClient(context.Background(), DimensionClientOptions{
+		Logger:                zap.NewNop(),
+		PropertiesMaxBuffered: 10,
+	})
+	client.retryDelay = time.Second
+	client.maxRetryDelay = 5 * time.Second
+
+	update := &DimensionUpdate{Name: "host", Value: "test-box"}
+	other := &DimensionUpdate{Name: "host", Value: "other-box"}
+
+	var delays []time.Duration
+	for i := 0; i < 5; i++ {
+		delays = append(delays, client.nextRetryDelay(update))
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+
+	// the retries are counted per dimension
+	assert.Equal(t, time.Second, client.nextRetryDelay(other))
+
+	client.resetRetries(update)
+	assert.Equal(t, time.Second, client.nextRetryDelay(update))
+}
+
+func dimensionUpdates(t *testing.T, result string) int64 {
+	rows, err := view.RetrieveData(viewDimensionUpdates.Name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		if len(row.Tags) == 1 && row.Tags[0].Value == result {
+			return int64(row.Data.(*view.SumData).Value)
+		}
+	}
+	return 0
+}
+
+func TestDimensionUpdatesMetric(t *testing.T) {
+	client, dimCh, forcedResp, cancel := setup(t)
+	defer cancel()
+
+	successes := dimensionUpdates(t, updateResultSuccess)
+	clientErrors := dimensionUpdates(t, updateResultClientError)
+
+	require.NoError(t, client.acceptDimension(&DimensionUpdate{Name: "host", Value: "metric-box"}))
+	require.Len(t, waitForDims(dimCh, 1, 3), 1)
+	assert.Eventually(t, func() bool {
+		return dimensionUpdates(t, updateResultSuccess) == successes+1
+	}, 3*time.Second, 10*time.Millisecond)
+
+	forcedResp.Store(400)
+	require.NoError(t, client.acceptDimension(&DimensionUpdate{Name: "host", Value: "other-box"}))
+	assert.Eventually(t, func() bool {
+		return dimensionUpdates(t, updateResultClientError) == clientErrors+1
+	}, 3*time.Second, 10*time.Millisecond)
+}
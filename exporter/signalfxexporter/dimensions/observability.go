@@ -0,0 +1,55 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dimensions
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	updateResultSuccess     = "success"
+	updateResultRetried     = "retried"
+	updateResultClientError = "client_error"
+	updateResultDropped     = "dropped"
+)
+
+func init() {
+	view.Register(
+		viewDimensionUpdates,
+	)
+}
+
+var (
+	tagResult, _ = tag.NewKey("result")
+
+	mDimensionUpdates = stats.Int64("otelcol/signalfx/dimension_updates",
+		"Number of dimension updates sent to the dimensions API, by result", "1")
+)
+
+var viewDimensionUpdates = &view.View{
+	Name:        mDimensionUpdates.Name(),
+	Description: mDimensionUpdates.Description(),
+	Measure:     mDimensionUpdates,
+	TagKeys:     []tag.Key{tagResult},
+	Aggregation: view.Sum(),
+}
+
+func recordDimensionUpdate(result string) {
+	_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Upsert(tagResult, result)}, mDimensionUpdates.M(1))
+}
@@ -11,8 +11,11 @@ require (
 	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchperresourceattr v0.0.0-00010101000000-000000000000
 	github.com/shirou/gopsutil v3.20.12+incompatible
 	github.com/signalfx/com_signalfx_metrics_protobuf v0.0.2
+	github.com/signalfx/golib/v3 v3.3.13
+	github.com/signalfx/golib/v3 v3.3.13
 	github.com/signalfx/signalfx-agent/pkg/apm v0.0.0-20201202163743-65b4fa925fc8
 	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.5
 	go.opentelemetry.io/collector v0.18.0
 	go.uber.org/multierr v1.6.0 // indirect
 	go.uber.org/zap v1.16.0
@@ -16,6 +16,7 @@ package hostmetadata
 
 import (
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
@@ -25,11 +26,19 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
 )
 
+// syncInterval is how often the host metadata is synchronized again, to keep
+// the properties of the host dimension up to date.
+const syncInterval = time.Hour
+
 // Syncer is a config structure for host metadata syncer.
 type Syncer struct {
 	logger    *zap.Logger
 	dimClient dimensions.MetadataUpdateClient
-	once      sync.Once
+	mu        sync.Mutex
+	lastSync  time.Time
+	interval  time.Duration
+	// For easier unit testing
+	now func() time.Time
 }
 
 // NewSyncer creates new instance of host metadata syncer.
@@ -37,17 +46,29 @@ func NewSyncer(logger *zap.Logger, dimClient dimensions.MetadataUpdateClient) *S
 	return &Syncer{
 		logger:    logger,
 		dimClient: dimClient,
+		interval:  syncInterval,
+		now:       time.Now,
 	}
 }
 
+// Sync synchronizes the host metadata on the first metrics data, and again
+// on the first one received once the sync interval has elapsed.
 func (s *Syncer) Sync(md pdata.Metrics) {
-	// skip if already synced or if metrics data is empty
+	// skip if metrics data is empty or if synced recently
 	if md.ResourceMetrics().Len() == 0 {
 		return
 	}
-	s.once.Do(func() {
-		s.syncOnResource(md.ResourceMetrics().At(0).Resource())
-	})
+
+	s.mu.Lock()
+	now := s.now()
+	if !s.lastSync.IsZero() && now.Sub(s.lastSync) < s.interval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSync = now
+	s.mu.Unlock()
+
+	s.syncOnResource(md.ResourceMetrics().At(0).Resource())
 }
 
 func (s *Syncer) syncOnResource(res pdata.Resource) {
@@ -57,7 +78,7 @@ func (s *Syncer) syncOnResource(res pdata.Resource) {
 	hostID, ok := splunk.ResourceToHostID(res)
 	if !ok {
 		// if no attributes found, we assume that resourcedetection is not enabled or
-		// it doesn't set right attributes, and we do not retry.
+		// it doesn't set right attributes, and we do not retry before the next sync.
 		s.logger.Error("Not found any host attributes. Host metadata synchronization skipped. " +
 			"Make sure that \"resourcedetection\" processor is enabled in the pipeline with one of " +
 			"the cloud provider detectors or environment variable detector setting \"host.name\" attribute")
@@ -66,7 +87,7 @@ func (s *Syncer) syncOnResource(res pdata.Resource) {
 
 	props := s.scrapeHostProperties()
 	if len(props) == 0 {
-		// do not retry if scraping failed.
+		// do not retry before the next sync if scraping failed.
 		s.logger.Error("Failed to fetch system properties. Host metadata synchronization skipped")
 		return
 	}
@@ -20,6 +20,7 @@ import (
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/host"
@@ -283,6 +284,42 @@ func TestSyncMetadata(t *testing.T) {
 	}
 }
 
+func TestSyncMetadataPeriodically(t *testing.T) {
+	dimClient := &fakeDimClient{}
+	syncer := NewSyncer(zap.NewNop(), dimClient)
+	now := time.Unix(1000, 0)
+	syncer.now = func() time.Time { return now }
+
+	os.Setenv("HOST_ETC", ".")
+	defer os.Unsetenv("HOST_ETC")
+	cpuInfo = func(context.Context) ([]cpu.InfoStat, error) {
+		return []cpu.InfoStat{{Cores: 4}}, nil
+	}
+	cpuCounts = func(context.Context, bool) (int, error) { return 1, nil }
+	memVirtualMemory = func() (*mem.VirtualMemoryStat, error) {
+		return &mem.VirtualMemoryStat{Total: 2048}, nil
+	}
+	hostInfo = func() (*host.InfoStat, error) {
+		return &host.InfoStat{OS: "linux"}, nil
+	}
+	mockSyscallUname()
+
+	md := generateSampleMetricsData(map[string]string{conventions.AttributeHostName: "host1"})
+
+	syncer.Sync(md)
+	require.Equal(t, 1, len(dimClient.getMetadataUpdates()))
+
+	// not synced again before the sync interval has elapsed
+	now = now.Add(syncInterval - time.Second)
+	syncer.Sync(md)
+	require.Equal(t, 1, len(dimClient.getMetadataUpdates()))
+
+	now = now.Add(time.Second)
+	syncer.Sync(md)
+	require.Equal(t, 2, len(dimClient.getMetadataUpdates()))
+	assert.Equal(t, dimClient.getMetadataUpdates()[0], dimClient.getMetadataUpdates()[1])
+}
+
 type fakeDimClient struct {
 	sync.Mutex
 	fail            bool
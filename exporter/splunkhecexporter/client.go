@@ -57,42 +57,14 @@ func (c *client) pushMetricsData(
 		return numDroppedTimeseries, nil
 	}
 
-	body, compressed, err := encodeBody(&c.zippers, splunkDataPoints, c.config.DisableCompression)
-	if err != nil {
-		return numMetricPoint(md), consumererror.Permanent(err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.url.String(), body)
-	if err != nil {
-		return numMetricPoint(md), consumererror.Permanent(err)
-	}
-
-	for k, v := range c.headers {
-		req.Header.Set(k, v)
-	}
-
-	if compressed {
-		req.Header.Set("Content-Encoding", "gzip")
-	}
-
-	resp, err := c.client.Do(req)
+	// A data point can be converted into several events, so the metrics are retried entirely when sending one
+	// of the batches fails.
+	numDroppedEvents, _, err := c.sendSplunkEvents(ctx, splunkDataPoints)
 	if err != nil {
 		return numMetricPoint(md), err
 	}
 
-	io.Copy(ioutil.Discard, resp.Body)
-	resp.Body.Close()
-
-	// Splunk accepts all 2XX codes.
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		err = fmt.Errorf(
-			"HTTP %d %q",
-			resp.StatusCode,
-			http.StatusText(resp.StatusCode))
-		return numMetricPoint(md), err
-	}
-
-	return numDroppedTimeseries, nil
+	return numDroppedTimeseries + numDroppedEvents, nil
 }
 
 func (c *client) pushTraceData(
@@ -107,16 +79,62 @@ func (c *client) pushTraceData(
 		return numDroppedSpans, nil
 	}
 
-	err = c.sendSplunkEvents(ctx, splunkEvents)
+	numDroppedEvents, firstUnsent, err := c.sendSplunkEvents(ctx, splunkEvents)
 	if err != nil {
+		if firstUnsent > 0 && !consumererror.IsPermanent(err) {
+			return td.SpanCount() - firstUnsent, consumererror.PartialTracesError(err, subTraces(td, firstUnsent))
+		}
 		return td.SpanCount(), err
 	}
 
-	return numDroppedSpans, nil
+	return numDroppedSpans + numDroppedEvents, nil
+}
+
+// sendSplunkEvents sends the events in batches of at most max_content_length bytes, dropping the events which
+// exceed it on their own. It returns the number of dropped events and, when sending a batch fails, the index of the
+// first event of this batch.
+func (c *client) sendSplunkEvents(ctx context.Context, splunkEvents []*splunk.Event) (numDroppedEvents int, firstUnsent int, err error) {
+	buf := new(bytes.Buffer)
+	maxContentLength := int(c.config.MaxContentLength)
+	for i, event := range splunkEvents {
+		b, err := encodeEvent(event)
+		if err != nil {
+			return numDroppedEvents, firstUnsent, consumererror.Permanent(err)
+		}
+
+		if maxContentLength > 0 && len(b) > maxContentLength {
+			c.logger.Warn(
+				"Dropped event exceeding max_content_length",
+				zap.Int("size", len(b)),
+				zap.Int("max_content_length", maxContentLength))
+			recordOversizedEvents(1)
+			numDroppedEvents++
+			continue
+		}
+
+		if maxContentLength > 0 && buf.Len()+len(b) > maxContentLength {
+			if err := c.postEvents(ctx, buf); err != nil {
+				return numDroppedEvents, firstUnsent, err
+			}
+			buf.Reset()
+		}
+
+		if buf.Len() == 0 {
+			firstUnsent = i
+		}
+		buf.Write(b)
+	}
+
+	if buf.Len() > 0 {
+		if err := c.postEvents(ctx, buf); err != nil {
+			return numDroppedEvents, firstUnsent, err
+		}
+	}
+	return numDroppedEvents, len(splunkEvents), nil
 }
 
-func (c *client) sendSplunkEvents(ctx context.Context, splunkEvents []*splunk.Event) error {
-	body, compressed, err := encodeBodyEvents(&c.zippers, splunkEvents, c.config.DisableCompression)
+func (c *client) postEvents(ctx context.Context, events *bytes.Buffer) error {
+	body, compressed, err := getReader(&c.zippers, events, c.config.DisableCompression, c.config.MinContentLengthCompression)
 	if err != nil {
 		return consumererror.Permanent(err)
 	}
@@ -162,44 +180,110 @@ func (c *client) pushLogData(ctx context.Context, ld pdata.Logs) (numDroppedLogs
 		return 0, nil
 	}
 
-	err = c.sendSplunkEvents(ctx, splunkEvents)
+	numDroppedEvents, firstUnsent, err := c.sendSplunkEvents(ctx, splunkEvents)
 	if err != nil {
+		if firstUnsent > 0 && !consumererror.IsPermanent(err) {
+			return ld.LogRecordCount() - firstUnsent, consumererror.PartialLogsError(err, subLogs(ld, firstUnsent))
+		}
 		return ld.LogRecordCount(), err
 	}
 
-	return 0, nil
+	return numDroppedEvents, nil
 }
 
-func encodeBodyEvents(zippers *sync.Pool, evs []*splunk.Event, disableCompression bool) (bodyReader io.Reader, compressed bool, err error) {
-	buf := new(bytes.Buffer)
-	encoder := json.NewEncoder(buf)
-	for _, e := range evs {
-		err := encoder.Encode(e)
-		if err != nil {
-			return nil, false, err
+// subLogs returns the log records of ld from the one at index from, in the order they're converted into events.
+func subLogs(ld pdata.Logs, from int) pdata.Logs {
+	sub := pdata.NewLogs()
+	index := 0
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		ills := rl.InstrumentationLibraryLogs()
+		var subRl pdata.ResourceLogs
+		copied := false
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			logs := ill.Logs()
+			skipped := from - index
+			index += logs.Len()
+			if skipped >= logs.Len() {
+				continue
+			}
+			if skipped < 0 {
+				skipped = 0
+			}
+
+			if !copied {
+				sub.ResourceLogs().Resize(sub.ResourceLogs().Len() + 1)
+				subRl = sub.ResourceLogs().At(sub.ResourceLogs().Len() - 1)
+				rl.Resource().CopyTo(subRl.Resource())
+				copied = true
+			}
+			subRl.InstrumentationLibraryLogs().Resize(subRl.InstrumentationLibraryLogs().Len() + 1)
+			subIll := subRl.InstrumentationLibraryLogs().At(subRl.InstrumentationLibraryLogs().Len() - 1)
+			ill.InstrumentationLibrary().CopyTo(subIll.InstrumentationLibrary())
+			subIll.Logs().Resize(logs.Len() - skipped)
+			for k := skipped; k < logs.Len(); k++ {
+				logs.At(k).CopyTo(subIll.Logs().At(k - skipped))
+			}
 		}
-		buf.WriteString("\r\n\r\n")
 	}
-	return getReader(zippers, buf, disableCompression)
+	return sub
 }
 
-func encodeBody(zippers *sync.Pool, dps []*splunk.Event, disableCompression bool) (bodyReader io.Reader, compressed bool, err error) {
-	buf := new(bytes.Buffer)
-	encoder := json.NewEncoder(buf)
-	for _, e := range dps {
-		err := encoder.Encode(e)
-		if err != nil {
-			return nil, false, err
+// subTraces returns the spans of td from the one at index from, in the order they're converted into events.
+func subTraces(td pdata.Traces, from int) pdata.Traces {
+	sub := pdata.NewTraces()
+	index := 0
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		ilss := rs.InstrumentationLibrarySpans()
+		var subRs pdata.ResourceSpans
+		copied := false
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			spans := ils.Spans()
+			skipped := from - index
+			index += spans.Len()
+			if skipped >= spans.Len() {
+				continue
+			}
+			if skipped < 0 {
+				skipped = 0
+			}
+
+			if !copied {
+				sub.ResourceSpans().Resize(sub.ResourceSpans().Len() + 1)
+				subRs = sub.ResourceSpans().At(sub.ResourceSpans().Len() - 1)
+				rs.Resource().CopyTo(subRs.Resource())
+				copied = true
+			}
+			subRs.InstrumentationLibrarySpans().Resize(subRs.InstrumentationLibrarySpans().Len() + 1)
+			subIls := subRs.InstrumentationLibrarySpans().At(subRs.InstrumentationLibrarySpans().Len() - 1)
+			ils.InstrumentationLibrary().CopyTo(subIls.InstrumentationLibrary())
+			subIls.Spans().Resize(spans.Len() - skipped)
+			for k := skipped; k < spans.Len(); k++ {
+				spans.At(k).CopyTo(subIls.Spans().At(k - skipped))
+			}
 		}
-		buf.WriteString("\r\n\r\n")
 	}
-	return getReader(zippers, buf, disableCompression)
+	return sub
+}
+
+// encodeEvent encodes an event followed by the separator of the events of a request body.
+func encodeEvent(ev *splunk.Event) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(ev); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\r\n\r\n")
+	return buf.Bytes(), nil
 }
 
-// avoid attempting to compress things that fit into a single ethernet frame
-func getReader(zippers *sync.Pool, b *bytes.Buffer, disableCompression bool) (io.Reader, bool, error) {
+func getReader(zippers *sync.Pool, b *bytes.Buffer, disableCompression bool, minContentLengthCompression uint) (io.Reader, bool, error) {
 	var err error
-	if !disableCompression && b.Len() > 1500 {
+	if !disableCompression && uint(b.Len()) > minContentLengthCompression {
 		buf := new(bytes.Buffer)
 		w := zippers.Get().(*gzip.Writer)
 		defer zippers.Put(w)
@@ -14,21 +14,27 @@
 package splunkhecexporter
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"math"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
@@ -396,17 +402,8 @@ func TestInvalidJson(t *testing.T) {
 	badEvent := badJSON{
 		Foo: math.Inf(1),
 	}
-	syncPool := sync.Pool{New: func() interface{} {
-		return gzip.NewWriter(nil)
-	}}
-	evs := []*splunk.Event{
-		{
-			Event: badEvent,
-		},
-		nil,
-	}
-	reader, _, err := encodeBodyEvents(&syncPool, evs, false)
-	assert.Error(t, err, reader)
+	_, err := encodeEvent(&splunk.Event{Event: badEvent})
+	assert.Error(t, err)
 }
 
 func TestStartAlwaysReturnsNil(t *testing.T) {
@@ -432,7 +429,7 @@ func TestInvalidJsonClient(t *testing.T) {
 		}},
 		config: &Config{},
 	}
-	err := c.sendSplunkEvents(context.Background(), evs)
+	_, _, err := c.sendSplunkEvents(context.Background(), evs)
 	assert.EqualError(t, err, "Permanent error: json: unsupported value: +Inf")
 }
 
@@ -444,6 +441,225 @@ func TestInvalidURLClient(t *testing.T) {
 		}},
 		config: &Config{},
 	}
-	err := c.sendSplunkEvents(context.Background(), []*splunk.Event{})
+	_, _, err := c.sendSplunkEvents(context.Background(), []*splunk.Event{{Event: "mylog"}})
 	assert.EqualError(t, err, "Permanent error: parse \"//in%20va%20lid\": invalid URL escape \"%20\"")
 }
+
+// batchCapture records the bodies of the requests it receives, failing the ones whose index is in failures.
+type batchCapture struct {
+	mu       sync.Mutex
+	bodies   []string
+	failures map[int]bool
+}
+
+func (c *batchCapture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		panic(err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures[len(c.bodies)] {
+		c.bodies = append(c.bodies, "")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	c.bodies = append(c.bodies, string(body))
+	w.WriteHeader(http.StatusOK)
+}
+
+func newBatchClient(t *testing.T, capture *batchCapture, maxContentLength uint) *client {
+	server := httptest.NewServer(capture)
+	t.Cleanup(server.Close)
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL + "/services/collector"
+	cfg.Token = "1234-1234"
+	cfg.DisableCompression = true
+	cfg.MaxContentLength = maxContentLength
+
+	options, err := cfg.getOptionsFromConfig()
+	require.NoError(t, err)
+	return buildClient(options, cfg, zap.NewNop())
+}
+
+// encodedLogs returns the encoded events of ld and the size of the largest one.
+func encodedLogs(t *testing.T, ld pdata.Logs) ([]string, int) {
+	var events []string
+	maxSize := 0
+	for _, event := range logDataToSplunk(zap.NewNop(), ld, &Config{}) {
+		b, err := encodeEvent(event)
+		require.NoError(t, err)
+		events = append(events, string(b))
+		if len(b) > maxSize {
+			maxSize = len(b)
+		}
+	}
+	return events, maxSize
+}
+
+func TestSendBatches(t *testing.T) {
+	ld := createLogData(5)
+	events, maxSize := encodedLogs(t, ld)
+
+	capture := &batchCapture{}
+	c := newBatchClient(t, capture, uint(2*maxSize))
+
+	numDroppedLogs, err := c.pushLogData(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 0, numDroppedLogs)
+
+	assert.Equal(t, []string{
+		events[0] + events[1],
+		events[2] + events[3],
+		events[4],
+	}, capture.bodies)
+}
+
+func TestSendBatchesWithoutMaxContentLength(t *testing.T) {
+	ld := createLogData(5)
+	events, _ := encodedLogs(t, ld)
+
+	capture := &batchCapture{}
+	c := newBatchClient(t, capture, 0)
+
+	_, err := c.pushLogData(context.Background(), ld)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{strings.Join(events, "")}, capture.bodies)
+}
+
+func TestDropOversizedEvents(t *testing.T) {
+	ld := createLogData(3)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(1).Body().SetStringVal(strings.Repeat("a", 1000))
+	events, _ := encodedLogs(t, ld)
+
+	capture := &batchCapture{}
+	c := newBatchClient(t, capture, 500)
+
+	numDroppedLogs, err := c.pushLogData(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 1, numDroppedLogs)
+
+	assert.Equal(t, []string{events[0] + events[2]}, capture.bodies)
+}
+
+func TestPartialLogsErrorOnFailedBatch(t *testing.T) {
+	ld := createLogData(5)
+	_, maxSize := encodedLogs(t, ld)
+
+	capture := &batchCapture{failures: map[int]bool{1: true}}
+	c := newBatchClient(t, capture, uint(2*maxSize))
+
+	numDroppedLogs, err := c.pushLogData(context.Background(), ld)
+	assert.EqualError(t, err, "HTTP 503 \"Service Unavailable\"")
+	assert.Equal(t, 3, numDroppedLogs)
+
+	partialErr, ok := err.(consumererror.PartialError)
+	require.True(t, ok)
+	assert.Equal(t, subLogs(ld, 2), partialErr.GetLogs())
+	assert.Len(t, capture.bodies, 2)
+}
+
+func TestPartialTracesErrorOnFailedBatch(t *testing.T) {
+	td := createTraceData(4)
+	events, _ := traceDataToSplunk(zap.NewNop(), td, &Config{})
+	b, err := encodeEvent(events[0])
+	require.NoError(t, err)
+
+	capture := &batchCapture{failures: map[int]bool{1: true}}
+	c := newBatchClient(t, capture, uint(len(b)+1))
+
+	numDroppedSpans, err := c.pushTraceData(context.Background(), td)
+	assert.EqualError(t, err, "HTTP 503 \"Service Unavailable\"")
+	assert.Equal(t, 3, numDroppedSpans)
+
+	partialErr, ok := err.(consumererror.PartialError)
+	require.True(t, ok)
+	assert.Equal(t, 3, partialErr.GetTraces().SpanCount())
+}
+
+func TestErrorOnFirstBatchIsNotPartial(t *testing.T) {
+	ld := createLogData(5)
+	_, maxSize := encodedLogs(t, ld)
+
+	capture := &batchCapture{failures: map[int]bool{0: true}}
+	c := newBatchClient(t, capture, uint(2*maxSize))
+
+	numDroppedLogs, err := c.pushLogData(context.Background(), ld)
+	assert.EqualError(t, err, "HTTP 503 \"Service Unavailable\"")
+	assert.Equal(t, 5, numDroppedLogs)
+	_, ok := err.(consumererror.PartialError)
+	assert.False(t, ok)
+}
+
+func TestSubLogs(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(2)
+	for i := 0; i < 2; i++ {
+		rl := ld.ResourceLogs().At(i)
+		rl.Resource().Attributes().InsertInt("resource", int64(i))
+		rl.InstrumentationLibraryLogs().Resize(2)
+		for j := 0; j < 2; j++ {
+			ill := rl.InstrumentationLibraryLogs().At(j)
+			ill.InstrumentationLibrary().SetName(fmt.Sprintf("il%d%d", i, j))
+			ill.Logs().Resize(2)
+			for k := 0; k < 2; k++ {
+				ill.Logs().At(k).SetName(fmt.Sprintf("log%d%d%d", i, j, k))
+			}
+		}
+	}
+
+	var names []string
+	sub := subLogs(ld, 3)
+	for i := 0; i < sub.ResourceLogs().Len(); i++ {
+		rl := sub.ResourceLogs().At(i)
+		resource, _ := rl.Resource().Attributes().Get("resource")
+		for j := 0; j < rl.InstrumentationLibraryLogs().Len(); j++ {
+			ill := rl.InstrumentationLibraryLogs().At(j)
+			for k := 0; k < ill.Logs().Len(); k++ {
+				names = append(names, fmt.Sprintf("%d/%s/%s", resource.IntVal(), ill.InstrumentationLibrary().Name(), ill.Logs().At(k).Name()))
+			}
+		}
+	}
+	assert.Equal(t, []string{"0/il01/log011", "1/il10/log100", "1/il10/log101", "1/il11/log110", "1/il11/log111"}, names)
+	assert.Equal(t, 0, subLogs(ld, 8).LogRecordCount())
+	assert.Equal(t, ld, subLogs(ld, 0))
+}
+
+func TestSubTraces(t *testing.T) {
+	td := createTraceData(4)
+
+	sub := subTraces(td, 1)
+	require.Equal(t, 3, sub.SpanCount())
+	resource, _ := sub.ResourceSpans().At(0).Resource().Attributes().Get("resource")
+	assert.Equal(t, "R1", resource.StringVal())
+	spans := sub.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		assert.Equal(t, pdata.TimestampUnixNano((i+2)*1e9), spans.At(i).StartTime())
+	}
+}
+
+func TestMinContentLengthCompression(t *testing.T) {
+	zippers := sync.Pool{New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}}
+	body := strings.Repeat("a", 100)
+
+	_, compressed, err := getReader(&zippers, bytes.NewBufferString(body), false, 100)
+	require.NoError(t, err)
+	assert.False(t, compressed)
+
+	reader, compressed, err := getReader(&zippers, bytes.NewBufferString(body), false, 99)
+	require.NoError(t, err)
+	assert.True(t, compressed)
+	zr, err := gzip.NewReader(reader)
+	require.NoError(t, err)
+	uncompressed, err := ioutil.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(uncompressed))
+
+	_, compressed, err = getReader(&zippers, bytes.NewBufferString(body), true, 0)
+	require.NoError(t, err)
+	assert.False(t, compressed)
+}
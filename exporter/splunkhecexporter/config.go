@@ -58,6 +58,15 @@ type Config struct {
 	// Disable GZip compression. Defaults to false.
 	DisableCompression bool `mapstructure:"disable_compression"`
 
+	// MinContentLengthCompression is the minimum size in bytes of a request body to compress, so that the bodies fitting
+	// into a single ethernet frame aren't compressed. Defaults to 1500.
+	MinContentLengthCompression uint `mapstructure:"min_content_length_compression"`
+
+	// MaxContentLength is the maximum size in bytes of the uncompressed body of a request, which should be at most the
+	// max_content_length setting of the HEC. The events are split across as many requests as needed, and the events
+	// exceeding it on their own are dropped. Set to 0 to send all the events in a single request. Defaults to 2 MiB.
+	MaxContentLength uint `mapstructure:"max_content_length"`
+
 	// insecure_skip_verify skips checking the certificate of the HEC endpoint when sending data over HTTPS. Defaults to false.
 	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
 }
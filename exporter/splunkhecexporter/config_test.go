@@ -58,12 +58,14 @@ func TestLoadConfig(t *testing.T) {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: expectedName,
 		},
-		Token:          "00000000-0000-0000-0000-0000000000000",
-		Endpoint:       "https://splunk:8088/services/collector",
-		Source:         "otel",
-		SourceType:     "otel",
-		Index:          "metrics",
-		MaxConnections: 100,
+		Token:                       "00000000-0000-0000-0000-0000000000000",
+		Endpoint:                    "https://splunk:8088/services/collector",
+		Source:                      "otel",
+		SourceType:                  "otel",
+		Index:                       "metrics",
+		MaxConnections:              100,
+		MinContentLengthCompression: 4096,
+		MaxContentLength:            800 * 1024,
 		TimeoutSettings: exporterhelper.TimeoutSettings{
 			Timeout: 10 * time.Second,
 		},
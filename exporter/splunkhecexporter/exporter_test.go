@@ -143,10 +143,11 @@ func TestConsumeMetricsData(t *testing.T) {
 				token: "1234",
 			}
 			config := &Config{
-				Source:     "test",
-				SourceType: "test_type",
-				Token:      "1234",
-				Index:      "test_index",
+				Source:                      "test",
+				SourceType:                  "test_type",
+				Token:                       "1234",
+				Index:                       "test_index",
+				MinContentLengthCompression: defaultMinContentLengthCompression,
 			}
 			sender := buildClient(options, config, zap.NewNop())
 
@@ -291,10 +292,11 @@ func TestConsumeLogsData(t *testing.T) {
 				token: "1234",
 			}
 			config := &Config{
-				Source:     "test",
-				SourceType: "test_type",
-				Token:      "1234",
-				Index:      "test_index",
+				Source:                      "test",
+				SourceType:                  "test_type",
+				Token:                       "1234",
+				Index:                       "test_index",
+				MinContentLengthCompression: defaultMinContentLengthCompression,
 			}
 			sender := buildClient(options, config, zap.NewNop())
 
@@ -29,6 +29,9 @@ const (
 	typeStr            = "splunk_hec"
 	defaultMaxIdleCons = 100
 	defaultHTTPTimeout = 10 * time.Second
+	// avoid attempting to compress things that fit into a single ethernet frame
+	defaultMinContentLengthCompression = 1500
+	defaultMaxContentLength            = 2 * 1024 * 1024
 )
 
 // NewFactory creates a factory for Splunk HEC exporter.
@@ -50,10 +53,12 @@ func createDefaultConfig() configmodels.Exporter {
 		TimeoutSettings: exporterhelper.TimeoutSettings{
 			Timeout: defaultHTTPTimeout,
 		},
-		RetrySettings:      exporterhelper.DefaultRetrySettings(),
-		QueueSettings:      exporterhelper.DefaultQueueSettings(),
-		DisableCompression: false,
-		MaxConnections:     defaultMaxIdleCons,
+		RetrySettings:               exporterhelper.DefaultRetrySettings(),
+		QueueSettings:               exporterhelper.DefaultQueueSettings(),
+		DisableCompression:          false,
+		MinContentLengthCompression: defaultMinContentLengthCompression,
+		MaxContentLength:            defaultMaxContentLength,
+		MaxConnections:              defaultMaxIdleCons,
 	}
 }
 
@@ -28,11 +28,12 @@ func logDataToSplunk(logger *zap.Logger, ld pdata.Logs, config *Config) []*splun
 	var splunkEvents []*splunk.Event
 	rls := ld.ResourceLogs()
 	for i := 0; i < rls.Len(); i++ {
+		res := rls.At(i).Resource()
 		ills := rls.At(i).InstrumentationLibraryLogs()
 		for j := 0; j < ills.Len(); j++ {
 			logs := ills.At(j).Logs()
 			for k := 0; k < logs.Len(); k++ {
-				splunkEvents = append(splunkEvents, mapLogRecordToSplunkEvent(logs.At(k), config, logger))
+				splunkEvents = append(splunkEvents, mapLogRecordToSplunkEvent(res, logs.At(k), config, logger))
 			}
 		}
 	}
@@ -40,22 +41,20 @@ func logDataToSplunk(logger *zap.Logger, ld pdata.Logs, config *Config) []*splun
 	return splunkEvents
 }
 
-func mapLogRecordToSplunkEvent(lr pdata.LogRecord, config *Config, logger *zap.Logger) *splunk.Event {
-	host := unknownHostName
-	source := config.Source
-	sourcetype := config.SourceType
-	index := config.Index
+func mapLogRecordToSplunkEvent(res pdata.Resource, lr pdata.LogRecord, config *Config, logger *zap.Logger) *splunk.Event {
+	// the attributes of the record override the ones of its resource
+	attributeMaps := []pdata.AttributeMap{lr.Attributes(), res.Attributes()}
+	host := lookupString(attributeMaps, conventions.AttributeHostName, unknownHostName)
+	source := lookupString(attributeMaps, splunk.SourceLabel,
+		lookupString(attributeMaps, conventions.AttributeServiceName, config.Source))
+	sourcetype := lookupString(attributeMaps, splunk.SourcetypeLabel, config.SourceType)
+	index := lookupString(attributeMaps, splunk.IndexLabel, config.Index)
+
 	fields := map[string]interface{}{}
 	lr.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
-		if k == conventions.AttributeHostName {
-			host = v.StringVal()
-		} else if k == conventions.AttributeServiceName {
-			source = v.StringVal()
-		} else if k == splunk.SourcetypeLabel {
-			sourcetype = v.StringVal()
-		} else if k == splunk.IndexLabel {
-			index = v.StringVal()
-		} else {
+		switch k {
+		case conventions.AttributeHostName, conventions.AttributeServiceName, splunk.SourceLabel, splunk.SourcetypeLabel, splunk.IndexLabel:
+		default:
 			fields[k] = convertAttributeValue(v, logger)
 		}
 	})
@@ -72,6 +71,16 @@ func mapLogRecordToSplunkEvent(lr pdata.LogRecord, config *Config, logger *zap.L
 	}
 }
 
+// lookupString returns the value of key in the first of the attribute maps containing it, or else defaultValue.
+func lookupString(attributeMaps []pdata.AttributeMap, key string, defaultValue string) string {
+	for _, attributes := range attributeMaps {
+		if v, ok := attributes.Get(key); ok {
+			return v.StringVal()
+		}
+	}
+	return defaultValue
+}
+
 func convertAttributeValue(value pdata.AttributeValue, logger *zap.Logger) interface{} {
 	switch value.Type() {
 	case pdata.AttributeValueINT:
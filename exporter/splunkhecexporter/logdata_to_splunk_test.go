@@ -99,6 +99,62 @@ func Test_logDataToSplunk(t *testing.T) {
 				commonLogSplunkEvent("mylog", ts, map[string]interface{}{"custom": "custom"}, "unknown", "source", "sourcetype"),
 			},
 		},
+		{
+			name: "with resource attributes",
+			logDataFn: func() pdata.Logs {
+				logRecord := pdata.NewLogRecord()
+				logRecord.Body().SetStringVal("mylog")
+				logRecord.Attributes().InsertString(splunk.IndexLabel, "myindex")
+				logRecord.Attributes().InsertString("custom", "custom")
+				logRecord.SetTimestamp(ts)
+				logs := makeLog(logRecord)
+				res := logs.ResourceLogs().At(0).Resource()
+				res.Attributes().InsertString(conventions.AttributeHostName, "myhost")
+				res.Attributes().InsertString(conventions.AttributeServiceName, "myapp")
+				res.Attributes().InsertString(splunk.SourcetypeLabel, "myapp-type")
+				res.Attributes().InsertString(splunk.IndexLabel, "resourceindex")
+				return logs
+			},
+			configDataFn: func() *Config {
+				return &Config{
+					Source:     "source",
+					SourceType: "sourcetype",
+					Index:      "index",
+				}
+			},
+			wantSplunkEvents: []*splunk.Event{
+				{
+					Time:       nanoTimestampToEpochMilliseconds(ts),
+					Host:       "myhost",
+					Source:     "myapp",
+					SourceType: "myapp-type",
+					Index:      "myindex",
+					Event:      "mylog",
+					Fields:     map[string]interface{}{"custom": "custom"},
+				},
+			},
+		},
+		{
+			name: "with splunk source",
+			logDataFn: func() pdata.Logs {
+				logRecord := pdata.NewLogRecord()
+				logRecord.Body().SetStringVal("mylog")
+				logRecord.Attributes().InsertString(conventions.AttributeServiceName, "myapp")
+				logRecord.SetTimestamp(ts)
+				logs := makeLog(logRecord)
+				logs.ResourceLogs().At(0).Resource().Attributes().InsertString(splunk.SourceLabel, "mysource")
+				return logs
+			},
+			configDataFn: func() *Config {
+				return &Config{
+					Source:     "source",
+					SourceType: "sourcetype",
+				}
+			},
+			wantSplunkEvents: []*splunk.Event{
+				commonLogSplunkEvent("mylog", ts, map[string]interface{}{}, "unknown", "mysource", "sourcetype"),
+			},
+		},
 		{
 			name: "log_is_empty",
 			logDataFn: func() pdata.Logs {
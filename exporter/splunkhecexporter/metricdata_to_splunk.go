@@ -59,6 +59,9 @@ func metricDataToSplunk(logger *zap.Logger, data pdata.Metrics, config *Config)
 		if sourceSet, isSet := attributes.Get(conventions.AttributeServiceName); isSet {
 			source = sourceSet.StringVal()
 		}
+		if sourceSet, isSet := attributes.Get(splunk.SourceLabel); isSet {
+			source = sourceSet.StringVal()
+		}
 		if sourcetypeSet, isSet := attributes.Get(splunk.SourcetypeLabel); isSet {
 			sourceType = sourcetypeSet.StringVal()
 		}
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecexporter
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func init() {
+	view.Register(
+		viewOversizedEvents,
+	)
+}
+
+var (
+	mOversizedEvents = stats.Int64("otelcol/splunkhec/oversized_events",
+		"Number of events dropped because they exceed max_content_length on their own", "1")
+)
+
+var viewOversizedEvents = &view.View{
+	Name:        mOversizedEvents.Name(),
+	Description: mOversizedEvents.Description(),
+	Measure:     mOversizedEvents,
+	Aggregation: view.Sum(),
+}
+
+func recordOversizedEvents(n int) {
+	stats.Record(context.Background(), mOversizedEvents.M(int64(n)))
+}
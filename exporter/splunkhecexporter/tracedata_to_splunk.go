@@ -78,6 +78,9 @@ func traceDataToSplunk(logger *zap.Logger, data pdata.Traces, config *Config) ([
 		if sourceSet, isSet := attributes.Get(conventions.AttributeServiceName); isSet {
 			source = sourceSet.StringVal()
 		}
+		if sourceSet, isSet := attributes.Get(splunk.SourceLabel); isSet {
+			source = sourceSet.StringVal()
+		}
 		if sourcetypeSet, isSet := attributes.Get(splunk.SourcetypeLabel); isSet {
 			sourceType = sourcetypeSet.StringVal()
 		}
@@ -88,12 +91,6 @@ func traceDataToSplunk(logger *zap.Logger, data pdata.Traces, config *Config) ([
 			commonFields[k] = tracetranslator.AttributeValueToString(v, false)
 		})
 
-		if sourceSet, isSet := rs.Resource().Attributes().Get(conventions.AttributeServiceName); isSet {
-			source = sourceSet.StringVal()
-		}
-		if sourcetypeSet, isSet := rs.Resource().Attributes().Get(splunk.SourcetypeLabel); isSet {
-			sourceType = sourcetypeSet.StringVal()
-		}
 		rs.Resource().Attributes().ForEach(func(k string, v pdata.AttributeValue) {
 			commonFields[k] = tracetranslator.AttributeValueToString(v, false)
 		})
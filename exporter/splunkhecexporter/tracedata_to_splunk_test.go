@@ -56,6 +56,32 @@ func Test_traceDataToSplunk(t *testing.T) {
 			},
 			wantNumDroppedSpans: 0,
 		},
+		{
+			name: "with splunk source",
+			traceDataFn: func() pdata.Traces {
+				traces := pdata.NewTraces()
+				traces.ResourceSpans().Resize(1)
+				rs := traces.ResourceSpans().At(0)
+				rs.Resource().Attributes().InsertString("service.name", "myservice")
+				rs.Resource().Attributes().InsertString("host.name", "myhost")
+				rs.Resource().Attributes().InsertString("com.splunk.source", "mysource")
+				rs.Resource().Attributes().InsertString("com.splunk.sourcetype", "mysourcetype")
+				rs.Resource().Attributes().InsertString("com.splunk.index", "myindex")
+				rs.InstrumentationLibrarySpans().Resize(1)
+				ils := rs.InstrumentationLibrarySpans().At(0)
+				ils.Spans().Append(makeSpan("myspan", &ts))
+				return traces
+			},
+			wantSplunkEvents: []*splunk.Event{
+				func() *splunk.Event {
+					event := commonSplunkEvent("myspan", ts)
+					event.Source = "mysource"
+					event.Fields["com.splunk.source"] = "mysource"
+					return event
+				}(),
+			},
+			wantNumDroppedSpans: 0,
+		},
 		{
 			name: "empty_rs",
 			traceDataFn: func() pdata.Traces {
@@ -55,6 +55,13 @@ type TraceConfig struct {
 type MetricConfig struct {
 	Prefix                     string `mapstructure:"prefix"`
 	SkipCreateMetricDescriptor bool   `mapstructure:"skip_create_descriptor"`
+	// ResourceFilters selects the resource labels copied into the metric labels.
+	ResourceFilters []ResourceFilter `mapstructure:"resource_filters"`
+}
+
+// ResourceFilter selects the resource labels whose key starts with Prefix.
+type ResourceFilter struct {
+	Prefix string `mapstructure:"prefix"`
 }
 
 // ResourceMapping defines mapping of resources from source (OpenCensus) to target (Stackdriver).
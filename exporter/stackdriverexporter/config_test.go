@@ -89,6 +89,10 @@ func TestLoadConfig(t *testing.T) {
 			MetricConfig: MetricConfig{
 				Prefix:                     "prefix",
 				SkipCreateMetricDescriptor: true,
+				ResourceFilters: []ResourceFilter{
+					{Prefix: "cloud"},
+					{Prefix: "k8s.deployment."},
+				},
 			},
 		})
 }
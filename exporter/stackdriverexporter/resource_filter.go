@@ -0,0 +1,118 @@
+// Copyright 2019 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdriverexporter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// resourceLabel is a resource label copied into the metric labels.
+type resourceLabel struct {
+	key   string
+	value string
+}
+
+// filterResourceLabels returns the resource labels matching any of the filters, with their keys sanitized the way
+// the Stackdriver exporter sanitizes the metric label keys. When several resource labels have the same sanitized key,
+// the one with the lowest original key wins, so the result doesn't depend on the map iteration order.
+func filterResourceLabels(labels map[string]string, filters []ResourceFilter) []resourceLabel {
+	if len(filters) == 0 || len(labels) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		if matchesResourceFilters(k, filters) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var result []resourceLabel
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		key := sanitizeLabelKey(k)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, resourceLabel{key: key, value: labels[k]})
+	}
+	return result
+}
+
+func matchesResourceFilters(key string, filters []ResourceFilter) bool {
+	for _, filter := range filters {
+		if strings.HasPrefix(key, filter.Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// addResourceLabels adds the resource labels to the labels of the metrics. A metric label takes precedence over a
+// resource label with the same sanitized key, which isn't added to that metric.
+func addResourceLabels(metrics []*metricspb.Metric, resourceLabels []resourceLabel) {
+	if len(resourceLabels) == 0 {
+		return
+	}
+
+	for _, metric := range metrics {
+		descriptor := metric.GetMetricDescriptor()
+		if descriptor == nil {
+			continue
+		}
+
+		existing := make(map[string]bool, len(descriptor.LabelKeys))
+		for _, labelKey := range descriptor.LabelKeys {
+			existing[sanitizeLabelKey(labelKey.Key)] = true
+		}
+
+		for _, rl := range resourceLabels {
+			if existing[rl.key] {
+				continue
+			}
+			descriptor.LabelKeys = append(descriptor.LabelKeys, &metricspb.LabelKey{Key: rl.key})
+			for _, ts := range metric.Timeseries {
+				ts.LabelValues = append(ts.LabelValues, &metricspb.LabelValue{Value: rl.value, HasValue: true})
+			}
+		}
+	}
+}
+
+// sanitizeLabelKey sanitizes the label key like the Stackdriver exporter: anything that is not a letter or digit
+// becomes an underscore, and the keys starting with a digit or an underscore are prefixed with "key".
+func sanitizeLabelKey(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, s)
+	if unicode.IsDigit(rune(s[0])) {
+		s = "key_" + s
+	}
+	if s[0] == '_' {
+		s = "key" + s
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdriverexporter
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterResourceLabels(t *testing.T) {
+	labels := map[string]string{
+		"cloud.region":       "region1",
+		"cloud.zone":         "zone1",
+		"cloud_zone":         "zone2",
+		"k8s.pod.name":       "pod1",
+		"k8s.namespace.name": "ns1",
+		"host.name":          "host1",
+	}
+
+	tests := []struct {
+		name    string
+		filters []ResourceFilter
+		want    []resourceLabel
+	}{
+		{
+			name: "No filters",
+		},
+		{
+			name:    "Prefix filters",
+			filters: []ResourceFilter{{Prefix: "k8s.pod."}, {Prefix: "host."}},
+			want: []resourceLabel{
+				{key: "host_name", value: "host1"},
+				{key: "k8s_pod_name", value: "pod1"},
+			},
+		},
+		{
+			// cloud.zone and cloud_zone are both sanitized to cloud_zone, the lowest key wins
+			name:    "Conflicting sanitized keys",
+			filters: []ResourceFilter{{Prefix: "cloud"}},
+			want: []resourceLabel{
+				{key: "cloud_region", value: "region1"},
+				{key: "cloud_zone", value: "zone1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, filterResourceLabels(labels, tt.filters))
+		})
+	}
+}
+
+func TestAddResourceLabels(t *testing.T) {
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      "metric1",
+			LabelKeys: []*metricspb.LabelKey{{Key: "k8s.pod.name"}, {Key: "method"}},
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{LabelValues: []*metricspb.LabelValue{{Value: "pod2", HasValue: true}, {Value: "GET", HasValue: true}}},
+			{LabelValues: []*metricspb.LabelValue{{Value: "pod3", HasValue: true}, {Value: "POST", HasValue: true}}},
+		},
+	}
+
+	addResourceLabels([]*metricspb.Metric{metric, {}}, []resourceLabel{
+		{key: "k8s_pod_name", value: "pod1"},
+		{key: "host_name", value: "host1"},
+	})
+
+	// The k8s.pod.name metric label takes precedence over the resource label
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "k8s.pod.name"}, {Key: "method"}, {Key: "host_name"}}, metric.MetricDescriptor.LabelKeys)
+	for _, ts := range metric.Timeseries {
+		assert.Len(t, ts.LabelValues, 3)
+		assert.Equal(t, &metricspb.LabelValue{Value: "host1", HasValue: true}, ts.LabelValues[2])
+	}
+	assert.Equal(t, "pod2", metric.Timeseries[0].LabelValues[0].Value)
+}
+
+func TestSanitizeLabelKey(t *testing.T) {
+	assert.Equal(t, "", sanitizeLabelKey(""))
+	assert.Equal(t, "k8s_pod_name", sanitizeLabelKey("k8s.pod.name"))
+	assert.Equal(t, "key_0day", sanitizeLabelKey("0day"))
+	assert.Equal(t, "key_private", sanitizeLabelKey("_private"))
+}
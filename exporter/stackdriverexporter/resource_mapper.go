@@ -17,9 +17,73 @@ package stackdriverexporter
 import (
 	"contrib.go.opencensus.io/exporter/stackdriver"
 	"go.opencensus.io/resource"
+	"go.opentelemetry.io/collector/translator/conventions"
 	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
 )
 
+const (
+	// Resource labels which aren't part of the collector conventions yet.
+	attributeK8sNode               = "k8s.node.name"
+	attributeCloudAvailabilityZone = "cloud.availability_zone"
+
+	// Resource label set by the OpenCensus exporter to the configured project ID.
+	stackdriverProjectID = "contrib.opencensus.io/exporter/stackdriver/project_id"
+)
+
+// inferredResourceType is a monitored resource type inferred from the presence of resource labels.
+type inferredResourceType struct {
+	resourceType string
+	// labels maps each monitored resource label to the resource labels it's read from, the first found being used.
+	labels map[string][]string
+	// matches reports if the resource should be mapped to this type, before checking its labels.
+	matches func(labels map[string]string) bool
+}
+
+var (
+	locationLabels = []string{attributeCloudAvailabilityZone, conventions.AttributeCloudZone, conventions.AttributeCloudRegion}
+
+	// Inferred monitored resource types, in priority order (first match wins).
+	inferredResourceTypes = []inferredResourceType{
+		{
+			resourceType: "k8s_container",
+			labels: map[string][]string{
+				"location":       locationLabels,
+				"cluster_name":   {conventions.AttributeK8sCluster},
+				"namespace_name": {conventions.AttributeK8sNamespace},
+				"pod_name":       {conventions.AttributeK8sPod},
+				"container_name": {conventions.AttributeK8sContainer, conventions.AttributeContainerName},
+			},
+		},
+		{
+			resourceType: "k8s_pod",
+			labels: map[string][]string{
+				"location":       locationLabels,
+				"cluster_name":   {conventions.AttributeK8sCluster},
+				"namespace_name": {conventions.AttributeK8sNamespace},
+				"pod_name":       {conventions.AttributeK8sPod},
+			},
+		},
+		{
+			resourceType: "k8s_node",
+			labels: map[string][]string{
+				"location":     locationLabels,
+				"cluster_name": {conventions.AttributeK8sCluster},
+				"node_name":    {attributeK8sNode, conventions.AttributeHostName},
+			},
+		},
+		{
+			resourceType: "gce_instance",
+			labels: map[string][]string{
+				"zone":        {attributeCloudAvailabilityZone, conventions.AttributeCloudZone},
+				"instance_id": {conventions.AttributeHostID},
+			},
+			matches: func(labels map[string]string) bool {
+				return labels[conventions.AttributeCloudProvider] == conventions.AttributeCloudProviderGCP
+			},
+		},
+	}
+)
+
 type resourceMapper struct {
 	mappings []ResourceMapping
 }
@@ -45,10 +109,61 @@ func (mr *resourceMapper) mapResource(res *resource.Resource) *monitoredrespb.Mo
 		return result
 	}
 
+	if result := inferMonitoredResource(res); result != nil {
+		return result
+	}
+
 	// Keep original behavior by default
 	return stackdriver.DefaultMapResource(res)
 }
 
+// inferMonitoredResource maps the resource to the first inferred monitored resource type whose labels are all found
+// in the resource, the project_id label being optional. Returns nil if there is none.
+func inferMonitoredResource(res *resource.Resource) *monitoredrespb.MonitoredResource {
+	if res == nil {
+		return nil
+	}
+
+	for _, inferred := range inferredResourceTypes {
+		if inferred.matches != nil && !inferred.matches(res.Labels) {
+			continue
+		}
+
+		if labels, ok := inferLabels(inferred.labels, res.Labels); ok {
+			return &monitoredrespb.MonitoredResource{
+				Type:   inferred.resourceType,
+				Labels: labels,
+			}
+		}
+	}
+
+	return nil
+}
+
+// inferLabels reads the monitored resource labels from the resource labels.
+// Returns false if any of them is missing.
+func inferLabels(labelSources map[string][]string, input map[string]string) (map[string]string, bool) {
+	output := make(map[string]string, len(labelSources)+1)
+	for label, sources := range labelSources {
+		found := false
+		for _, source := range sources {
+			if v, ok := input[source]; ok && v != "" {
+				output[label] = v
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	if v, ok := input[stackdriverProjectID]; ok {
+		output["project_id"] = v
+	}
+	return output, true
+}
+
 // transformLabels transforms labels according to the configured mappings.
 // Returns true if all required labels in match are found.
 func transformLabels(labelMappings []LabelMapping, input map[string]string) (map[string]string, bool) {
@@ -163,3 +163,179 @@ func TestResourceMapper(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceMapperInference(t *testing.T) {
+	rm := resourceMapper{
+		mappings: []ResourceMapping{
+			{
+				SourceType: "source.resource1",
+				TargetType: "target_resource_1",
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		sourceResource *resource.Resource
+		wantResource   *monitoredres.MonitoredResource
+	}{
+		{
+			name: "Configured mapping takes precedence",
+			sourceResource: &resource.Resource{
+				Type: "source.resource1",
+				Labels: map[string]string{
+					"k8s.cluster.name":   "cluster1",
+					"k8s.namespace.name": "ns1",
+					"k8s.pod.name":       "pod1",
+					"cloud.zone":         "zone1",
+				},
+			},
+			wantResource: &monitoredres.MonitoredResource{
+				Type:   "target_resource_1",
+				Labels: map[string]string{},
+			},
+		},
+		{
+			name: "Kubernetes container",
+			sourceResource: &resource.Resource{
+				Labels: map[string]string{
+					"k8s.cluster.name":        "cluster1",
+					"k8s.namespace.name":      "ns1",
+					"k8s.pod.name":            "pod1",
+					"k8s.container.name":      "container1",
+					"container.name":          "ignored",
+					"cloud.availability_zone": "zone1",
+					"cloud.region":            "region1",
+					"contrib.opencensus.io/exporter/stackdriver/project_id": "123",
+				},
+			},
+			wantResource: &monitoredres.MonitoredResource{
+				Type: "k8s_container",
+				Labels: map[string]string{
+					"project_id":     "123",
+					"location":       "zone1",
+					"cluster_name":   "cluster1",
+					"namespace_name": "ns1",
+					"pod_name":       "pod1",
+					"container_name": "container1",
+				},
+			},
+		},
+		{
+			name: "Kubernetes container with OpenCensus container name",
+			sourceResource: &resource.Resource{
+				Type: "container",
+				Labels: map[string]string{
+					"k8s.cluster.name":   "cluster1",
+					"k8s.namespace.name": "ns1",
+					"k8s.pod.name":       "pod1",
+					"container.name":     "container1",
+					"cloud.region":       "region1",
+				},
+			},
+			wantResource: &monitoredres.MonitoredResource{
+				Type: "k8s_container",
+				Labels: map[string]string{
+					"location":       "region1",
+					"cluster_name":   "cluster1",
+					"namespace_name": "ns1",
+					"pod_name":       "pod1",
+					"container_name": "container1",
+				},
+			},
+		},
+		{
+			name: "Kubernetes pod",
+			sourceResource: &resource.Resource{
+				Labels: map[string]string{
+					"k8s.cluster.name":   "cluster1",
+					"k8s.namespace.name": "ns1",
+					"k8s.pod.name":       "pod1",
+					"cloud.zone":         "zone1",
+				},
+			},
+			wantResource: &monitoredres.MonitoredResource{
+				Type: "k8s_pod",
+				Labels: map[string]string{
+					"location":       "zone1",
+					"cluster_name":   "cluster1",
+					"namespace_name": "ns1",
+					"pod_name":       "pod1",
+				},
+			},
+		},
+		{
+			name: "Kubernetes node",
+			sourceResource: &resource.Resource{
+				Labels: map[string]string{
+					"k8s.cluster.name": "cluster1",
+					"host.name":        "node1",
+					"cloud.zone":       "zone1",
+				},
+			},
+			wantResource: &monitoredres.MonitoredResource{
+				Type: "k8s_node",
+				Labels: map[string]string{
+					"location":     "zone1",
+					"cluster_name": "cluster1",
+					"node_name":    "node1",
+				},
+			},
+		},
+		{
+			name: "GCE instance",
+			sourceResource: &resource.Resource{
+				Labels: map[string]string{
+					"cloud.provider":          "gcp",
+					"cloud.availability_zone": "zone1",
+					"host.id":                 "1234",
+					"host.name":               "instance1",
+				},
+			},
+			wantResource: &monitoredres.MonitoredResource{
+				Type: "gce_instance",
+				Labels: map[string]string{
+					"zone":        "zone1",
+					"instance_id": "1234",
+				},
+			},
+		},
+		{
+			name: "Not a GCE instance",
+			sourceResource: &resource.Resource{
+				Labels: map[string]string{
+					"cloud.provider": "azure",
+					"cloud.zone":     "zone1",
+					"host.id":        "1234",
+				},
+			},
+			// Resource without inferred type should be converted via default implementation
+			wantResource: &monitoredres.MonitoredResource{
+				Type: "global",
+			},
+		},
+		{
+			name: "Kubernetes pod without location",
+			sourceResource: &resource.Resource{
+				Labels: map[string]string{
+					"k8s.cluster.name":   "cluster1",
+					"k8s.namespace.name": "ns1",
+					"k8s.pod.name":       "pod1",
+				},
+			},
+			// A missing required label falls back to the default implementation
+			wantResource: &monitoredres.MonitoredResource{
+				Type: "global",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rm.mapResource(tt.sourceResource)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantResource.Type, result.Type)
+			assert.EqualValues(t, tt.wantResource.Labels, result.Labels)
+		})
+	}
+}
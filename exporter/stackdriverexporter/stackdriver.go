@@ -44,7 +44,8 @@ type traceExporter struct {
 
 // metricsExporter is a wrapper struct of OC stackdriver exporter
 type metricsExporter struct {
-	mexporter *stackdriver.Exporter
+	mexporter       *stackdriver.Exporter
+	resourceFilters []ResourceFilter
 }
 
 func (*traceExporter) Name() string {
@@ -226,18 +227,19 @@ func newStackdriverMetricsExporter(cfg *Config, params component.ExporterCreateP
 	if cfg.MetricConfig.SkipCreateMetricDescriptor {
 		options.SkipCMD = true
 	}
-	if len(cfg.ResourceMappings) > 0 {
-		rm := resourceMapper{
-			mappings: cfg.ResourceMappings,
-		}
-		options.MapResource = rm.mapResource
+	rm := resourceMapper{
+		mappings: cfg.ResourceMappings,
 	}
+	options.MapResource = rm.mapResource
 
 	sde, serr := stackdriver.NewExporter(options)
 	if serr != nil {
 		return nil, fmt.Errorf("cannot configure Stackdriver metric exporter: %w", serr)
 	}
-	mExp := &metricsExporter{mexporter: sde}
+	mExp := &metricsExporter{
+		mexporter:       sde,
+		resourceFilters: cfg.MetricConfig.ResourceFilters,
+	}
 
 	return exporterhelper.NewMetricsExporter(
 		cfg,
@@ -260,6 +262,8 @@ func (me *metricsExporter) pushMetrics(ctx context.Context, m pdata.Metrics) (in
 			continue
 		}
 
+		addResourceLabels(md.Metrics, filterResourceLabels(md.Resource.GetLabels(), me.resourceFilters))
+
 		points := numPoints(md)
 		dropped, err := me.mexporter.PushMetricsProto(ctx, md.Node, md.Resource, md.Metrics)
 		recordPointCount(ctx, points-dropped, dropped, err)
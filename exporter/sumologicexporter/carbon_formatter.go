@@ -0,0 +1,268 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	carbon2MetricTag string = "metric"
+	carbon2UnitTag   string = "unit"
+)
+
+// carbon2TagString returns attributes and labels as space separated carbon2 tags,
+// followed by the metric name and its unit
+func carbon2TagString(name string, unit string, attributes pdata.AttributeMap, labels pdata.StringMap) string {
+	mergedAttributes := pdata.NewAttributeMap()
+	attributes.CopyTo(mergedAttributes)
+	labels.ForEach(func(k string, v string) {
+		mergedAttributes.UpsertString(k, v)
+	})
+
+	returnValue := make([]string, 0, mergedAttributes.Len()+2)
+	mergedAttributes.ForEach(func(k string, v pdata.AttributeValue) {
+		if k == carbon2MetricTag || k == carbon2UnitTag {
+			k = "_" + k
+		}
+		returnValue = append(
+			returnValue,
+			fmt.Sprintf(
+				"%s=%s",
+				sanitizeCarbonString(k),
+				sanitizeCarbonString(tracetranslator.AttributeValueToString(v, false)),
+			),
+		)
+	})
+
+	returnValue = append(returnValue, fmt.Sprintf("%s=%s", carbon2MetricTag, sanitizeCarbonString(name)))
+	if unit != "" {
+		returnValue = append(returnValue, fmt.Sprintf("%s=%s", carbon2UnitTag, sanitizeCarbonString(unit)))
+	}
+
+	return strings.Join(returnValue, " ")
+}
+
+// sanitizeCarbonString returns sanitized string performing the following substitutions,
+// as the key=value pairs of carbon2 tags are separated by spaces:
+// `=` -> `:`
+// ` ` -> `_`
+func sanitizeCarbonString(text string) string {
+	return strings.NewReplacer("=", ":", " ", "_").Replace(text)
+}
+
+// carbon2Line builds carbon2 line with the intrinsic tags, the value and the timestamp in seconds
+func carbon2Line(tags string, value string, timestamp pdata.TimestampUnixNano) string {
+	return fmt.Sprintf("%s  %s %d", tags, value, timestamp/pdata.TimestampUnixNano(time.Second))
+}
+
+// carbon2DoubleValue formats float64 value the same way as prometheusFormatter
+func carbon2DoubleValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// carbon2IntDataPoints converts IntDataPointSlice to a list of carbon2 lines (one per dataPoint)
+func carbon2IntDataPoints(record metricPair, dps pdata.IntDataPointSlice) []string {
+	lines := make([]string, 0, dps.Len())
+
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		lines = append(lines, carbon2Line(
+			carbon2TagString(record.metric.Name(), record.metric.Unit(), record.attributes, dp.LabelsMap()),
+			strconv.FormatInt(dp.Value(), 10),
+			dp.Timestamp(),
+		))
+	}
+
+	return lines
+}
+
+// carbon2DoubleDataPoints converts DoubleDataPointSlice to a list of carbon2 lines (one per dataPoint)
+func carbon2DoubleDataPoints(record metricPair, dps pdata.DoubleDataPointSlice) []string {
+	lines := make([]string, 0, dps.Len())
+
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		lines = append(lines, carbon2Line(
+			carbon2TagString(record.metric.Name(), record.metric.Unit(), record.attributes, dp.LabelsMap()),
+			carbon2DoubleValue(dp.Value()),
+			dp.Timestamp(),
+		))
+	}
+
+	return lines
+}
+
+// carbon2DoubleSummary converts DoubleSummary record to a list of carbon2 lines,
+// n+2 where n is number of quantiles and 2 stands for sum and count metrics per each data point
+func carbon2DoubleSummary(record metricPair) []string {
+	dps := record.metric.DoubleSummary().DataPoints()
+	var lines []string
+
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		labels := pdata.NewStringMap()
+		dp.LabelsMap().CopyTo(labels)
+
+		qs := dp.QuantileValues()
+		for j := 0; j < qs.Len(); j++ {
+			q := qs.At(j)
+			labels.Upsert(prometheusQuantileTag, carbon2DoubleValue(q.Quantile()))
+
+			lines = append(lines, carbon2Line(
+				carbon2TagString(record.metric.Name(), record.metric.Unit(), record.attributes, labels),
+				carbon2DoubleValue(q.Value()),
+				dp.Timestamp(),
+			))
+		}
+
+		lines = append(
+			lines,
+			carbon2Line(
+				carbon2TagString(record.metric.Name()+"_sum", record.metric.Unit(), record.attributes, dp.LabelsMap()),
+				carbon2DoubleValue(dp.Sum()),
+				dp.Timestamp(),
+			),
+			carbon2Line(
+				carbon2TagString(record.metric.Name()+"_count", "", record.attributes, dp.LabelsMap()),
+				strconv.FormatUint(dp.Count(), 10),
+				dp.Timestamp(),
+			),
+		)
+	}
+
+	return lines
+}
+
+// carbon2Histogram returns the carbon2 lines of a histogram data point,
+// n+3 where n is number of bounds, plus the +Inf bucket, the sum and the count
+func carbon2Histogram(
+	record metricPair,
+	labels pdata.StringMap,
+	explicitBounds []float64,
+	bucketCounts []uint64,
+	sum string,
+	count uint64,
+	timestamp pdata.TimestampUnixNano,
+) []string {
+	lines := make([]string, 0, len(explicitBounds)+3)
+	bucketLabels := pdata.NewStringMap()
+	labels.CopyTo(bucketLabels)
+
+	var cumulative uint64
+	for i, bound := range explicitBounds {
+		cumulative += bucketCounts[i]
+		bucketLabels.Upsert(prometheusLeTag, carbon2DoubleValue(bound))
+
+		lines = append(lines, carbon2Line(
+			carbon2TagString(record.metric.Name(), "", record.attributes, bucketLabels),
+			strconv.FormatUint(cumulative, 10),
+			timestamp,
+		))
+	}
+
+	cumulative += bucketCounts[len(explicitBounds)]
+	bucketLabels.Upsert(prometheusLeTag, prometheusInfValue)
+
+	return append(
+		lines,
+		carbon2Line(
+			carbon2TagString(record.metric.Name(), "", record.attributes, bucketLabels),
+			strconv.FormatUint(cumulative, 10),
+			timestamp,
+		),
+		carbon2Line(
+			carbon2TagString(record.metric.Name()+"_sum", record.metric.Unit(), record.attributes, labels),
+			sum,
+			timestamp,
+		),
+		carbon2Line(
+			carbon2TagString(record.metric.Name()+"_count", "", record.attributes, labels),
+			strconv.FormatUint(count, 10),
+			timestamp,
+		),
+	)
+}
+
+// carbon2IntHistogram converts IntHistogram record to a list of carbon2 lines
+func carbon2IntHistogram(record metricPair) []string {
+	dps := record.metric.IntHistogram().DataPoints()
+	var lines []string
+
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		lines = append(lines, carbon2Histogram(
+			record,
+			dp.LabelsMap(),
+			dp.ExplicitBounds(),
+			dp.BucketCounts(),
+			strconv.FormatInt(dp.Sum(), 10),
+			dp.Count(),
+			dp.Timestamp(),
+		)...)
+	}
+
+	return lines
+}
+
+// carbon2DoubleHistogram converts DoubleHistogram record to a list of carbon2 lines
+func carbon2DoubleHistogram(record metricPair) []string {
+	dps := record.metric.DoubleHistogram().DataPoints()
+	var lines []string
+
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		lines = append(lines, carbon2Histogram(
+			record,
+			dp.LabelsMap(),
+			dp.ExplicitBounds(),
+			dp.BucketCounts(),
+			carbon2DoubleValue(dp.Sum()),
+			dp.Count(),
+			dp.Timestamp(),
+		)...)
+	}
+
+	return lines
+}
+
+// carbon2Metric2String returns stringified metricPair in carbon2 format
+func carbon2Metric2String(record metricPair) string {
+	var lines []string
+
+	switch record.metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		lines = carbon2IntDataPoints(record, record.metric.IntGauge().DataPoints())
+	case pdata.MetricDataTypeDoubleGauge:
+		lines = carbon2DoubleDataPoints(record, record.metric.DoubleGauge().DataPoints())
+	case pdata.MetricDataTypeIntSum:
+		lines = carbon2IntDataPoints(record, record.metric.IntSum().DataPoints())
+	case pdata.MetricDataTypeDoubleSum:
+		lines = carbon2DoubleDataPoints(record, record.metric.DoubleSum().DataPoints())
+	case pdata.MetricDataTypeDoubleSummary:
+		lines = carbon2DoubleSummary(record)
+	case pdata.MetricDataTypeIntHistogram:
+		lines = carbon2IntHistogram(record)
+	case pdata.MetricDataTypeDoubleHistogram:
+		lines = carbon2DoubleHistogram(record)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSanitizeCarbonString(t *testing.T) {
+	assert.Equal(t, "key:value_with_spaces", sanitizeCarbonString("key=value with spaces"))
+}
+
+func TestCarbon2TagString(t *testing.T) {
+	mp := exampleIntMetric()
+	labels := pdata.NewStringMap()
+	labels.Insert("test", "overridden value")
+	labels.Insert("metric", "label")
+
+	result := carbon2TagString(mp.metric.Name(), mp.metric.Unit(), mp.attributes, labels)
+	assert.Equal(t, "test=overridden_value test2=second_value _metric=label metric=test.metric.data unit=bytes", result)
+}
+
+func TestCarbon2TagStringNoAttributes(t *testing.T) {
+	result := carbon2TagString("test_metric", "", pdata.NewAttributeMap(), pdata.NewStringMap())
+	assert.Equal(t, "metric=test_metric", result)
+}
+
+func TestCarbonMetricDataTypeIntGauge(t *testing.T) {
+	result := carbon2Metric2String(exampleIntGaugeMetric())
+	expected := `foo=bar remote_name=156920 url=http://example_url metric=gauge_metric_name  124 1608124661
+foo=bar remote_name=156955 url=http://another_url metric=gauge_metric_name  245 1608124662`
+	assert.Equal(t, expected, result)
+}
+
+func TestCarbonMetricDataTypeDoubleGauge(t *testing.T) {
+	result := carbon2Metric2String(exampleDoubleGaugeMetric())
+	expected := `foo=bar local_name=156720 endpoint=http://example_url metric=gauge_metric_name_double_test  33.4 1608124661
+foo=bar local_name=156155 endpoint=http://another_url metric=gauge_metric_name_double_test  56.8 1608124662`
+	assert.Equal(t, expected, result)
+}
+
+func TestCarbonMetricDataTypeIntSum(t *testing.T) {
+	result := carbon2Metric2String(exampleIntSumMetric())
+	expected := `foo=bar name=156720 address=http://example_url metric=sum_metric_int_test  45 1608124444
+foo=bar name=156155 address=http://another_url metric=sum_metric_int_test  1238 1608124699`
+	assert.Equal(t, expected, result)
+}
+
+func TestCarbonMetricDataTypeDoubleSum(t *testing.T) {
+	result := carbon2Metric2String(exampleDoubleSumMetric())
+	expected := `foo=bar pod_name=lorem namespace=default metric=sum_metric_double_test  45.6 1618124444
+foo=bar pod_name=opsum namespace=kube-config metric=sum_metric_double_test  1238.1 1608424699`
+	assert.Equal(t, expected, result)
+}
+
+func TestCarbonMetricDataTypeDoubleSummary(t *testing.T) {
+	result := carbon2Metric2String(exampleDoubleSummaryMetric())
+	expected := `foo=bar pod_name=dolor namespace=sumologic quantile=0.6 metric=summary_metric_double_test  0.7 1618124444
+foo=bar pod_name=dolor namespace=sumologic quantile=2.6 metric=summary_metric_double_test  4 1618124444
+foo=bar pod_name=dolor namespace=sumologic metric=summary_metric_double_test_sum  45.6 1618124444
+foo=bar pod_name=dolor namespace=sumologic metric=summary_metric_double_test_count  3 1618124444
+foo=bar pod_name=sit namespace=main metric=summary_metric_double_test_sum  1238.1 1608424699
+foo=bar pod_name=sit namespace=main metric=summary_metric_double_test_count  7 1608424699`
+	assert.Equal(t, expected, result)
+}
+
+func TestCarbonMetricDataTypeIntHistogram(t *testing.T) {
+	result := carbon2Metric2String(exampleIntHistogramMetric())
+	expected := `foo=bar pod_name=dolor namespace=sumologic le=0.1 metric=histogram_metric_int_test  0 1618124444
+foo=bar pod_name=dolor namespace=sumologic le=0.2 metric=histogram_metric_int_test  12 1618124444
+foo=bar pod_name=dolor namespace=sumologic le=0.5 metric=histogram_metric_int_test  19 1618124444
+foo=bar pod_name=dolor namespace=sumologic le=0.8 metric=histogram_metric_int_test  24 1618124444
+foo=bar pod_name=dolor namespace=sumologic le=1 metric=histogram_metric_int_test  32 1618124444
+foo=bar pod_name=dolor namespace=sumologic le=+Inf metric=histogram_metric_int_test  45 1618124444
+foo=bar pod_name=dolor namespace=sumologic metric=histogram_metric_int_test_sum  45 1618124444
+foo=bar pod_name=dolor namespace=sumologic metric=histogram_metric_int_test_count  3 1618124444
+foo=bar pod_name=sit namespace=main le=0.1 metric=histogram_metric_int_test  0 1608424699
+foo=bar pod_name=sit namespace=main le=0.2 metric=histogram_metric_int_test  10 1608424699
+foo=bar pod_name=sit namespace=main le=0.5 metric=histogram_metric_int_test  11 1608424699
+foo=bar pod_name=sit namespace=main le=0.8 metric=histogram_metric_int_test  12 1608424699
+foo=bar pod_name=sit namespace=main le=1 metric=histogram_metric_int_test  16 1608424699
+foo=bar pod_name=sit namespace=main le=+Inf metric=histogram_metric_int_test  22 1608424699
+foo=bar pod_name=sit namespace=main metric=histogram_metric_int_test_sum  54 1608424699
+foo=bar pod_name=sit namespace=main metric=histogram_metric_int_test_count  5 1608424699`
+	assert.Equal(t, expected, result)
+}
+
+func TestCarbonMetricDataTypeDoubleHistogram(t *testing.T) {
+	mp := exampleDoubleHistogramMetric()
+	mp.metric.DoubleHistogram().DataPoints().Resize(1)
+
+	result := carbon2Metric2String(mp)
+	expected := `bar=foo container=dolor branch=sumologic le=0.1 metric=histogram_metric_double_test  0 1618124444
+bar=foo container=dolor branch=sumologic le=0.2 metric=histogram_metric_double_test  12 1618124444
+bar=foo container=dolor branch=sumologic le=0.5 metric=histogram_metric_double_test  19 1618124444
+bar=foo container=dolor branch=sumologic le=0.8 metric=histogram_metric_double_test  24 1618124444
+bar=foo container=dolor branch=sumologic le=1 metric=histogram_metric_double_test  32 1618124444
+bar=foo container=dolor branch=sumologic le=+Inf metric=histogram_metric_double_test  45 1618124444
+bar=foo container=dolor branch=sumologic metric=histogram_metric_double_test_sum  45.6 1618124444
+bar=foo container=dolor branch=sumologic metric=histogram_metric_double_test_count  7 1618124444`
+	assert.Equal(t, expected, result)
+}
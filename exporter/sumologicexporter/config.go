@@ -43,7 +43,7 @@ type Config struct {
 	LogFormat LogFormatType `mapstructure:"log_format"`
 
 	// Metrics related configuration
-	// The format of metrics you will be sending, either graphite or carbon2 or prometheus (Default is carbon2)
+	// The format of metrics you will be sending, either carbon2 or prometheus (Default is carbon2)
 	MetricFormat MetricFormatType `mapstructure:"metric_format"`
 
 	// List of regexes for attributes which should be send as metadata
@@ -87,11 +87,9 @@ const (
 	TextFormat LogFormatType = "text"
 	// JSONFormat represents log_format: json
 	JSONFormat LogFormatType = "json"
-	// GraphiteFormat represents metric_format: text
-	GraphiteFormat MetricFormatType = "graphite"
-	// Carbon2Format represents metric_format: json
+	// Carbon2Format represents metric_format: carbon2
 	Carbon2Format MetricFormatType = "carbon2"
-	// PrometheusFormat represents metric_format: json
+	// PrometheusFormat represents metric_format: prometheus
 	PrometheusFormat MetricFormatType = "prometheus"
 	// GZIPCompression represents compress_encoding: gzip
 	GZIPCompression CompressEncodingType = "gzip"
@@ -43,7 +43,6 @@ func initExporter(cfg *Config) (*sumologicexporter, error) {
 	}
 
 	switch cfg.MetricFormat {
-	case GraphiteFormat:
 	case Carbon2Format:
 	case PrometheusFormat:
 	default:
@@ -108,6 +107,27 @@ func newLogsExporter(
 	)
 }
 
+func newMetricsExporter(
+	cfg *Config,
+	params component.ExporterCreateParams,
+) (component.MetricsExporter, error) {
+	se, err := initExporter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the metrics exporter: %w", err)
+	}
+
+	return exporterhelper.NewMetricsExporter(
+		cfg,
+		params.Logger,
+		se.pushMetricsData,
+		// Disable exporterhelper Timeout, since we are using a custom mechanism
+		// within exporter itself
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
+		exporterhelper.WithRetry(cfg.RetrySettings),
+		exporterhelper.WithQueue(cfg.QueueSettings),
+	)
+}
+
 // pushLogsData groups data with common metadata and sends them as separate batched requests.
 // It returns the number of unsent logs and an error which contains a list of dropped records
 // so they can be handled by OTC retry mechanism
@@ -202,3 +222,96 @@ func (se *sumologicexporter) pushLogsData(ctx context.Context, ld pdata.Logs) (i
 
 	return 0, nil
 }
+
+// pushMetricsData groups data with common metadata and sends them as separate batched requests.
+// It returns the number of unsent metrics and an error which contains a list of dropped metrics
+// so they can be handled by OTC retry mechanism
+func (se *sumologicexporter) pushMetricsData(ctx context.Context, md pdata.Metrics) (int, error) {
+	var (
+		currentMetadata  fields
+		previousMetadata fields
+		errs             []error
+		droppedRecords   []metricPair
+		attributes       pdata.AttributeMap
+	)
+
+	c, err := newCompressor(se.config.CompressEncoding)
+	if err != nil {
+		return 0, consumererror.PartialMetricsError(fmt.Errorf("failed to initialize compressor: %w", err), md)
+	}
+	sdr := newSender(se.config, se.client, se.filter, se.sources, c)
+
+	// Iterate over ResourceMetrics
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+
+		attributes = rm.Resource().Attributes()
+
+		// iterate over InstrumentationLibraryMetrics
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+
+			// iterate over Metrics
+			ms := ilm.Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				mp := metricPair{
+					metric:     m,
+					attributes: attributes,
+				}
+
+				currentMetadata = sdr.filter.filterIn(attributes)
+
+				// If metadata differs from currently buffered, flush the buffer
+				if currentMetadata.string() != previousMetadata.string() && previousMetadata.string() != "" {
+					var dropped []metricPair
+					dropped, err = sdr.sendMetrics(ctx, previousMetadata)
+					if err != nil {
+						errs = append(errs, err)
+						droppedRecords = append(droppedRecords, dropped...)
+					}
+					sdr.cleanMetricBuffer()
+				}
+
+				// assign metadata
+				previousMetadata = currentMetadata
+				var dropped []metricPair
+				// add metric to the buffer
+				dropped, err = sdr.batchMetric(ctx, mp, currentMetadata)
+				if err != nil {
+					droppedRecords = append(droppedRecords, dropped...)
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	// Flush pending metrics
+	dropped, err := sdr.sendMetrics(ctx, previousMetadata)
+	if err != nil {
+		droppedRecords = append(droppedRecords, dropped...)
+		errs = append(errs, err)
+	}
+
+	if len(droppedRecords) > 0 {
+		// Move all dropped records to Metrics
+		droppedMetrics := pdata.NewMetrics()
+		rms := droppedMetrics.ResourceMetrics()
+		rms.Resize(len(droppedRecords))
+		for num, record := range droppedRecords {
+			rm := rms.At(num)
+			record.attributes.CopyTo(rm.Resource().Attributes())
+
+			ilms := rm.InstrumentationLibraryMetrics()
+			ilms.Resize(1)
+			ilms.At(0).Metrics().Resize(1)
+			record.metric.CopyTo(ilms.At(0).Metrics().At(0))
+		}
+
+		return len(droppedRecords), consumererror.PartialMetricsError(componenterror.CombineErrors(errs), droppedMetrics)
+	}
+
+	return 0, nil
+}
@@ -40,6 +40,18 @@ func LogRecordsToLogs(records []pdata.LogRecord) pdata.Logs {
 	return logs
 }
 
+func metricPairsToMetrics(mps []metricPair) pdata.Metrics {
+	metrics := pdata.NewMetrics()
+	metrics.ResourceMetrics().Resize(len(mps))
+	for num, record := range mps {
+		record.attributes.CopyTo(metrics.ResourceMetrics().At(num).Resource().Attributes())
+		metrics.ResourceMetrics().At(num).InstrumentationLibraryMetrics().Resize(1)
+		metrics.ResourceMetrics().At(num).InstrumentationLibraryMetrics().At(0).Metrics().Append(record.metric)
+	}
+
+	return metrics
+}
+
 func TestInitExporter(t *testing.T) {
 	_, err := initExporter(&Config{
 		LogFormat:        "json",
@@ -287,3 +299,66 @@ func TestPushFailedBatch(t *testing.T) {
 	assert.EqualError(t, err, "error during sending data: 500 Internal Server Error")
 	assert.Equal(t, maxBufferSize, count)
 }
+
+func TestAllMetricsSuccess(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			expected := `test=test_value test2=second_value metric=test.metric.data unit=bytes  14500 1605534165
+foo=bar remote_name=156920 url=http://example_url metric=gauge_metric_name  124 1608124661
+foo=bar remote_name=156955 url=http://another_url metric=gauge_metric_name  245 1608124662`
+			assert.Equal(t, expected, body)
+			assert.Equal(t, "application/vnd.sumologic.carbon2", req.Header.Get("Content-Type"))
+		},
+	})
+	defer func() { test.srv.Close() }()
+
+	metrics := metricPairsToMetrics([]metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	})
+
+	_, err := test.exp.pushMetricsData(context.Background(), metrics)
+	assert.NoError(t, err)
+}
+
+func TestMetricsPartiallyFailed(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(429)
+
+			body := extractBody(t, req)
+			expected := `test=test_value test2=second_value metric=test.metric.data unit=bytes  14500 1605534165`
+			assert.Equal(t, expected, body)
+			assert.Equal(t, "Test source name/test_value", req.Header.Get("X-Sumo-Name"))
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			expected := `foo=bar remote_name=156920 url=http://example_url metric=gauge_metric_name  124 1608124661
+foo=bar remote_name=156955 url=http://another_url metric=gauge_metric_name  245 1608124662`
+			assert.Equal(t, expected, body)
+			assert.Equal(t, "Test source name/undefined", req.Header.Get("X-Sumo-Name"))
+		},
+	})
+	defer func() { test.srv.Close() }()
+
+	f, err := newFilter([]string{`test`, `foo`})
+	require.NoError(t, err)
+	test.exp.filter = f
+	test.exp.sources.name = getTestSourceFormat(t, "Test source name/%{test}")
+
+	records := []metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	}
+	metrics := metricPairsToMetrics(records)
+	expected := metricPairsToMetrics(records[:1])
+
+	dropped, err := test.exp.pushMetricsData(context.Background(), metrics)
+	assert.EqualError(t, err, "error during sending data: 429 Too Many Requests")
+	assert.Equal(t, 1, dropped)
+
+	partial, ok := err.(consumererror.PartialError)
+	require.True(t, ok)
+	assert.Equal(t, expected, partial.GetMetrics())
+}
@@ -34,6 +34,7 @@ func NewFactory() component.ExporterFactory {
 		typeStr,
 		createDefaultConfig,
 		exporterhelper.WithLogs(createLogsExporter),
+		exporterhelper.WithMetrics(createMetricsExporter),
 	)
 }
 
@@ -74,3 +75,16 @@ func createLogsExporter(
 
 	return exp, nil
 }
+
+func createMetricsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.MetricsExporter, error) {
+	exp, err := newMetricsExporter(cfg.(*Config), params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the metrics exporter: %w", err)
+	}
+
+	return exp, nil
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
 
@@ -42,17 +43,19 @@ type metricPair struct {
 }
 
 type sender struct {
-	buffer     []pdata.LogRecord
-	config     *Config
-	client     *http.Client
-	filter     filter
-	sources    sourceFormats
-	compressor compressor
+	buffer              []pdata.LogRecord
+	metricBuffer        []metricPair
+	config              *Config
+	client              *http.Client
+	filter              filter
+	sources             sourceFormats
+	compressor          compressor
+	prometheusFormatter prometheusFormatter
 }
 
 const (
 	logKey string = "log"
-	// maxBufferSize defines size of the buffer (maximum number of pdata.LogRecord entries)
+	// maxBufferSize defines size of the buffers (maximum number of pdata.LogRecord or metricPair entries)
 	maxBufferSize int = 1024 * 1024
 )
 
@@ -70,11 +73,12 @@ func newSender(
 	c compressor,
 ) *sender {
 	return &sender{
-		config:     cfg,
-		client:     cl,
-		filter:     f,
-		sources:    s,
-		compressor: c,
+		config:              cfg,
+		client:              cl,
+		filter:              f,
+		sources:             s,
+		compressor:          c,
+		prometheusFormatter: newPrometheusFormatter(),
 	}
 }
 
@@ -119,8 +123,14 @@ func (s *sender) send(ctx context.Context, pipeline PipelineType, body io.Reader
 		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		req.Header.Add("X-Sumo-Fields", flds.string())
 	case MetricsPipeline:
-		// ToDo: Implement metrics pipeline
-		return errors.New("current sender version doesn't support metrics")
+		switch s.config.MetricFormat {
+		case PrometheusFormat:
+			req.Header.Add("Content-Type", "application/vnd.sumologic.prometheus")
+		case Carbon2Format:
+			req.Header.Add("Content-Type", "application/vnd.sumologic.carbon2")
+		default:
+			return fmt.Errorf("unsupported metrics format: %s", s.config.MetricFormat)
+		}
 	default:
 		return errors.New("unexpected pipeline")
 	}
@@ -129,6 +139,12 @@ func (s *sender) send(ctx context.Context, pipeline PipelineType, body io.Reader
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	// Any error is retried by the exporterhelper, which is what Sumo Logic
+	// expects for throttling (429) and unavailability (503) responses
 	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
 		return fmt.Errorf("error during sending data: %s", resp.Status)
 	}
@@ -217,6 +233,77 @@ func (s *sender) sendLogs(ctx context.Context, flds fields) ([]pdata.LogRecord,
 	return droppedRecords, nil
 }
 
+// sendMetrics sends metrics from the buffer formatted according
+// to configured MetricFormat and as the result of execution
+// returns array of records which has not been sent correctly and error
+func (s *sender) sendMetrics(ctx context.Context, flds fields) ([]metricPair, error) {
+	var (
+		body           strings.Builder
+		errs           []error
+		droppedRecords []metricPair
+		currentRecords []metricPair
+	)
+
+	for _, record := range s.metricBuffer {
+		var formattedLine string
+		var err error
+
+		switch s.config.MetricFormat {
+		case PrometheusFormat:
+			formattedLine = s.prometheusFormatter.metric2String(record)
+		case Carbon2Format:
+			formattedLine = carbon2Metric2String(record)
+		default:
+			err = fmt.Errorf("unexpected metric format: %s", s.config.MetricFormat)
+		}
+
+		if err != nil {
+			droppedRecords = append(droppedRecords, record)
+			errs = append(errs, err)
+			continue
+		}
+
+		// Metrics without data points are skipped
+		if formattedLine == "" {
+			continue
+		}
+
+		ar, err := s.appendAndSend(ctx, formattedLine, MetricsPipeline, &body, flds)
+		if err != nil {
+			errs = append(errs, err)
+			if ar.sent {
+				droppedRecords = append(droppedRecords, currentRecords...)
+			}
+
+			if !ar.appended {
+				droppedRecords = append(droppedRecords, record)
+			}
+		}
+
+		// If data was sent, cleanup the currentTimeSeries counter
+		if ar.sent {
+			currentRecords = currentRecords[:0]
+		}
+
+		// If metric has been appended to body, increment the currentTimeSeries
+		if ar.appended {
+			currentRecords = append(currentRecords, record)
+		}
+	}
+
+	if body.Len() > 0 {
+		if err := s.send(ctx, MetricsPipeline, strings.NewReader(body.String()), flds); err != nil {
+			errs = append(errs, err)
+			droppedRecords = append(droppedRecords, currentRecords...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return droppedRecords, componenterror.CombineErrors(errs)
+	}
+	return droppedRecords, nil
+}
+
 // appendAndSend appends line to the request body that will be sent and sends
 // the accumulated data if the internal buffer has been filled (with maxBufferSize elements).
 // It returns appendResponse
@@ -283,3 +370,27 @@ func (s *sender) batch(ctx context.Context, log pdata.LogRecord, metadata fields
 func (s *sender) count() int {
 	return len(s.buffer)
 }
+
+// cleanMetricBuffer zeroes metricBuffer
+func (s *sender) cleanMetricBuffer() {
+	s.metricBuffer = (s.metricBuffer)[:0]
+}
+
+// batchMetric adds metric to the metricBuffer and flushes them if metricBuffer is full to avoid overflow
+// returns list of metric records which were not sent successfully
+func (s *sender) batchMetric(ctx context.Context, metric metricPair, metadata fields) ([]metricPair, error) {
+	s.metricBuffer = append(s.metricBuffer, metric)
+
+	if s.countMetrics() >= maxBufferSize {
+		dropped, err := s.sendMetrics(ctx, metadata)
+		s.cleanMetricBuffer()
+		return dropped, err
+	}
+
+	return nil, nil
+}
+
+// countMetrics returns number of metrics in metricBuffer
+func (s *sender) countMetrics() int {
+	return len(s.metricBuffer)
+}
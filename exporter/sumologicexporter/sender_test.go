@@ -427,12 +427,153 @@ func TestBufferOverflow(t *testing.T) {
 	assert.Equal(t, 0, test.s.count())
 }
 
-func TestMetricsPipeline(t *testing.T) {
+func TestInvalidMetricFormat(t *testing.T) {
 	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){})
 	defer func() { test.srv.Close() }()
 
+	test.s.config.MetricFormat = "invalid"
+
 	err := test.s.send(context.Background(), MetricsPipeline, strings.NewReader(""), fields{})
-	assert.EqualError(t, err, `current sender version doesn't support metrics`)
+	assert.EqualError(t, err, `unsupported metrics format: invalid`)
+}
+
+func TestSendCarbon2Metrics(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			expected := `test=test_value test2=second_value metric=test.metric.data unit=bytes  14500 1605534165
+foo=bar remote_name=156920 url=http://example_url metric=gauge_metric_name  124 1608124661
+foo=bar remote_name=156955 url=http://another_url metric=gauge_metric_name  245 1608124662`
+			assert.Equal(t, expected, body)
+			assert.Equal(t, "otelcol", req.Header.Get("X-Sumo-Client"))
+			assert.Equal(t, "application/vnd.sumologic.carbon2", req.Header.Get("Content-Type"))
+		},
+	})
+	defer func() { test.srv.Close() }()
+
+	test.s.metricBuffer = []metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	}
+
+	_, err := test.s.sendMetrics(context.Background(), fields{})
+	assert.NoError(t, err)
+}
+
+func TestSendPrometheusMetrics(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			expected := `test_metric_data{test="test_value",test2="second_value"} 14500 1605534165000
+gauge_metric_name{foo="bar",remote_name="156920",url="http://example_url"} 124 1608124661166
+gauge_metric_name{foo="bar",remote_name="156955",url="http://another_url"} 245 1608124662166`
+			assert.Equal(t, expected, body)
+			assert.Equal(t, "application/vnd.sumologic.prometheus", req.Header.Get("Content-Type"))
+		},
+	})
+	defer func() { test.srv.Close() }()
+
+	test.s.config.MetricFormat = PrometheusFormat
+	test.s.metricBuffer = []metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	}
+
+	_, err := test.s.sendMetrics(context.Background(), fields{})
+	assert.NoError(t, err)
+}
+
+func TestSendMetricsSplit(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			expected := `test=test_value test2=second_value metric=test.metric.data unit=bytes  14500 1605534165`
+			assert.Equal(t, expected, body)
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			expected := `foo=bar remote_name=156920 url=http://example_url metric=gauge_metric_name  124 1608124661
+foo=bar remote_name=156955 url=http://another_url metric=gauge_metric_name  245 1608124662`
+			assert.Equal(t, expected, body)
+		},
+	})
+	defer func() { test.srv.Close() }()
+
+	test.s.config.MaxRequestBodySize = 10
+	test.s.metricBuffer = []metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	}
+
+	_, err := test.s.sendMetrics(context.Background(), fields{})
+	assert.NoError(t, err)
+}
+
+func TestSendMetricsSplitFailedOne(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(503)
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			expected := `foo=bar remote_name=156920 url=http://example_url metric=gauge_metric_name  124 1608124661
+foo=bar remote_name=156955 url=http://another_url metric=gauge_metric_name  245 1608124662`
+			assert.Equal(t, expected, body)
+		},
+	})
+	defer func() { test.srv.Close() }()
+
+	test.s.config.MaxRequestBodySize = 10
+	test.s.metricBuffer = []metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	}
+
+	dropped, err := test.s.sendMetrics(context.Background(), fields{})
+	assert.EqualError(t, err, "error during sending data: 503 Service Unavailable")
+	assert.Equal(t, test.s.metricBuffer[0:1], dropped)
+}
+
+func TestSendMetricsUnexpectedFormat(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){})
+	defer func() { test.srv.Close() }()
+
+	test.s.config.MetricFormat = "invalid"
+	metrics := []metricPair{
+		exampleIntMetric(),
+	}
+	test.s.metricBuffer = metrics
+
+	dropped, err := test.s.sendMetrics(context.Background(), fields{})
+	assert.EqualError(t, err, "unexpected metric format: invalid")
+	assert.Equal(t, metrics, dropped)
+}
+
+func TestMetricsBuffer(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){})
+	defer func() { test.srv.Close() }()
+
+	assert.Equal(t, test.s.countMetrics(), 0)
+	metrics := []metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	}
+
+	droppedMetrics, err := test.s.batchMetric(context.Background(), metrics[0], fields{})
+	require.NoError(t, err)
+	assert.Nil(t, droppedMetrics)
+	assert.Equal(t, 1, test.s.countMetrics())
+	assert.Equal(t, metrics[0:1], test.s.metricBuffer)
+
+	droppedMetrics, err = test.s.batchMetric(context.Background(), metrics[1], fields{})
+	require.NoError(t, err)
+	assert.Nil(t, droppedMetrics)
+	assert.Equal(t, 2, test.s.countMetrics())
+	assert.Equal(t, metrics, test.s.metricBuffer)
+
+	test.s.cleanMetricBuffer()
+	assert.Equal(t, 0, test.s.countMetrics())
+	assert.Equal(t, []metricPair{}, test.s.metricBuffer)
 }
 
 func TestInvalidPipeline(t *testing.T) {
@@ -30,7 +30,11 @@ type sourceFormat struct {
 	template string
 }
 
-const sourceRegex = `\%\{([\w\.]+)\}`
+const (
+	sourceRegex = `\%\{([\w\.]+)\}`
+	// undefinedSourceValue is put into templates in place of the fields which are not set
+	undefinedSourceValue = "undefined"
+)
 
 // newSourceFormat builds sourceFormat basing on the regex and given text.
 // Regex is basing on the `sourceRegex` const
@@ -69,12 +73,17 @@ func newSourceFormats(cfg *Config) (sourceFormats, error) {
 }
 
 // format converts sourceFormat to string.
-// Takes fields and put into template (%s placeholders) in order defined by matches
+// Takes fields and put into template (%s placeholders) in order defined by matches.
+// Fields which are not set are replaced by `undefined`
 func (s *sourceFormat) format(f fields) string {
 	labels := make([]interface{}, 0, len(s.matches))
 
 	for _, matchset := range s.matches {
-		labels = append(labels, f[matchset])
+		v, ok := f[matchset]
+		if !ok {
+			v = undefinedSourceValue
+		}
+		labels = append(labels, v)
 	}
 
 	return fmt.Sprintf(s.template, labels...)
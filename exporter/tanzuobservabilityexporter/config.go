@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tanzuobservabilityexporter
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// Config defines configuration options for the Tanzu Observability exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings  `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings  `mapstructure:"retry_on_failure"`
+
+	// Endpoint is the host:port of the traces listener of the Wavefront proxy,
+	// accepting spans in the Wavefront format.
+	Endpoint string `mapstructure:"endpoint"`
+	// DefaultApplication is the application tag of the spans whose resource has
+	// no application attribute.
+	DefaultApplication string `mapstructure:"default_application"`
+	// DefaultService is the service tag of the spans whose resource has no
+	// service.name attribute.
+	DefaultService string `mapstructure:"default_service"`
+}
+
+// hostPort returns the host and port of the endpoint.
+func (cfg *Config) hostPort() (string, int, error) {
+	host, portStr, err := net.SplitHostPort(cfg.Endpoint)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid endpoint %q: %w", cfg.Endpoint, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid endpoint %q: invalid port %q", cfg.Endpoint, portStr)
+	}
+	return host, port, nil
+}
+
+func (cfg *Config) validate() error {
+	if _, _, err := cfg.hostPort(); err != nil {
+		return err
+	}
+	if cfg.DefaultApplication == "" || cfg.DefaultService == "" {
+		return fmt.Errorf("default_application and default_service must be set")
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tanzuobservabilityexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// spanSender is the part of the Wavefront sender used by the exporter.
+type spanSender interface {
+	SendSpan(name string, startMillis, durationMillis int64, source, traceID, spanID string, parents, followsFrom []string, tags []senders.SpanTag, spanLogs []senders.SpanLog) error
+	Flush() error
+	Close()
+}
+
+// tracesExporter sends spans to the traces listener of a Wavefront proxy.
+type tracesExporter struct {
+	sender             spanSender
+	logger             *zap.Logger
+	defaultApplication string
+	defaultService     string
+}
+
+// spanIndex is the position of a span in the traces being pushed.
+type spanIndex struct {
+	resource int
+	library  int
+	span     int
+}
+
+func newTracesExporter(cfg *Config, logger *zap.Logger) (*tracesExporter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	host, port, _ := cfg.hostPort()
+	sender, err := senders.NewProxySender(&senders.ProxyConfiguration{
+		Host:        host,
+		TracingPort: port,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tracesExporter{
+		sender:             sender,
+		logger:             logger,
+		defaultApplication: cfg.DefaultApplication,
+		defaultService:     cfg.DefaultService,
+	}, nil
+}
+
+// pushTraceData sends the spans of td to the proxy and flushes the connection.
+// The spans which can't be represented in the Wavefront format are dropped,
+// the others are retried when the proxy can't be reached.
+func (e *tracesExporter) pushTraceData(_ context.Context, td pdata.Traces) (int, error) {
+	dropped := map[spanIndex]bool{}
+	var errs []error
+	var sendErr error
+
+	rs := td.ResourceSpans()
+	for i := 0; i < rs.Len(); i++ {
+		resourceAttrs := rs.At(i).Resource().Attributes()
+		source := resourceSource(resourceAttrs)
+		resourceTags := e.resourceTags(resourceAttrs)
+
+		ils := rs.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ils.Len(); j++ {
+			spans := ils.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				index := spanIndex{resource: i, library: j, span: k}
+				ws, err := spanToWavefront(spans.At(k), source, resourceTags)
+				if err != nil {
+					dropped[index] = true
+					errs = append(errs, err)
+					continue
+				}
+				// Once the connection failed, the remaining spans are only
+				// checked, to be retried along with the ones already sent.
+				if sendErr == nil {
+					sendErr = e.sender.SendSpan(ws.Name, ws.StartMillis, ws.DurationMillis, ws.Source, ws.TraceID, ws.SpanID, ws.Parents, nil, ws.Tags, ws.SpanLogs)
+				}
+			}
+		}
+	}
+
+	if sendErr == nil {
+		sendErr = e.sender.Flush()
+	}
+	if sendErr != nil {
+		// The lines buffered for the connection are lost along with it, the
+		// whole batch but the invalid spans has to be sent again.
+		errs = append(errs, sendErr)
+		retry := spansExcept(td, dropped)
+		return td.SpanCount(), consumererror.PartialTracesError(componenterror.CombineErrors(errs), retry)
+	}
+	if len(errs) > 0 {
+		return len(dropped), consumererror.Permanent(componenterror.CombineErrors(errs))
+	}
+	return 0, nil
+}
+
+func (e *tracesExporter) shutdown(context.Context) error {
+	e.sender.Close()
+	return nil
+}
+
+// wavefrontSpan holds the arguments of a span sent to the proxy.
+type wavefrontSpan struct {
+	Name           string
+	StartMillis    int64
+	DurationMillis int64
+	Source         string
+	TraceID        string
+	SpanID         string
+	Parents        []string
+	Tags           []senders.SpanTag
+	SpanLogs       []senders.SpanLog
+}
+
+var (
+	errEmptySpanName = errors.New("span has no name")
+	errInvalidIDs    = errors.New("span has an invalid trace or span ID")
+)
+
+// spanToWavefront converts a span to the Wavefront format, returning an error
+// if the span can't be accepted by the proxy.
+func spanToWavefront(span pdata.Span, source string, resourceTags map[string]string) (wavefrontSpan, error) {
+	if span.Name() == "" {
+		return wavefrontSpan{}, errEmptySpanName
+	}
+	if !span.TraceID().IsValid() || !span.SpanID().IsValid() {
+		return wavefrontSpan{}, fmt.Errorf("%w: %q", errInvalidIDs, span.Name())
+	}
+
+	ws := wavefrontSpan{
+		Name:           span.Name(),
+		StartMillis:    int64(span.StartTime()) / int64(1e6),
+		DurationMillis: (int64(span.EndTime()) - int64(span.StartTime())) / int64(1e6),
+		Source:         source,
+		TraceID:        traceIDToUUID(span.TraceID()),
+		SpanID:         spanIDToUUID(span.SpanID()),
+		Tags:           spanTags(span, resourceTags),
+		SpanLogs:       spanLogs(span.Events()),
+	}
+	if ws.DurationMillis < 0 {
+		ws.DurationMillis = 0
+	}
+	if span.ParentSpanID().IsValid() {
+		ws.Parents = []string{spanIDToUUID(span.ParentSpanID())}
+	}
+	return ws, nil
+}
+
+// spansExcept returns the traces made of the spans of td but the ones at the
+// given indexes.
+func spansExcept(td pdata.Traces, indexes map[spanIndex]bool) pdata.Traces {
+	if len(indexes) == 0 {
+		return td
+	}
+	kept := pdata.NewTraces()
+	rs := td.ResourceSpans()
+	for i := 0; i < rs.Len(); i++ {
+		rsSpan := rs.At(i)
+		keptRS := pdata.NewResourceSpans()
+		ils := rsSpan.InstrumentationLibrarySpans()
+		for j := 0; j < ils.Len(); j++ {
+			ilsSpan := ils.At(j)
+			keptILS := pdata.NewInstrumentationLibrarySpans()
+			spans := ilsSpan.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if !indexes[spanIndex{resource: i, library: j, span: k}] {
+					keptILS.Spans().Append(spans.At(k))
+				}
+			}
+			if keptILS.Spans().Len() > 0 {
+				ilsSpan.InstrumentationLibrary().CopyTo(keptILS.InstrumentationLibrary())
+				keptRS.InstrumentationLibrarySpans().Append(keptILS)
+			}
+		}
+		if keptRS.InstrumentationLibrarySpans().Len() > 0 {
+			rsSpan.Resource().CopyTo(keptRS.Resource())
+			kept.ResourceSpans().Append(keptRS)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tanzuobservabilityexporter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func newTestTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"host.name":    pdata.NewAttributeValueString("host-1"),
+		"service.name": pdata.NewAttributeValueString("frontend"),
+		"application":  pdata.NewAttributeValueString("shop"),
+		"region":       pdata.NewAttributeValueString("us-west"),
+	})
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(2)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	root := spans.At(0)
+	root.SetName("GET /cart")
+	root.SetTraceID(pdata.NewTraceID([16]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}))
+	root.SetSpanID(pdata.NewSpanID([8]byte{0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18}))
+	root.SetKind(pdata.SpanKindSERVER)
+	root.SetStartTime(pdata.TimestampUnixNano(start.UnixNano()))
+	root.SetEndTime(pdata.TimestampUnixNano(start.Add(1500 * time.Millisecond).UnixNano()))
+	root.Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"http.method": pdata.NewAttributeValueString("GET"),
+		"region":      pdata.NewAttributeValueString("eu-west"),
+		"service":     pdata.NewAttributeValueString("ignored"),
+	})
+	root.Status().SetCode(pdata.StatusCodeError)
+	root.Status().SetMessage("timed out")
+	root.Events().Resize(1)
+	event := root.Events().At(0)
+	event.SetName("retry")
+	event.SetTimestamp(pdata.TimestampUnixNano(start.Add(time.Second).UnixNano()))
+	event.Attributes().InsertInt("attempt", 2)
+
+	child := spans.At(1)
+	child.SetName("SELECT")
+	child.SetTraceID(root.TraceID())
+	child.SetSpanID(pdata.NewSpanID([8]byte{0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28}))
+	child.SetParentSpanID(root.SpanID())
+	child.SetStartTime(pdata.TimestampUnixNano(start.Add(100 * time.Millisecond).UnixNano()))
+	child.SetEndTime(pdata.TimestampUnixNano(start.Add(300 * time.Millisecond).UnixNano()))
+	return td
+}
+
+func TestPushTraceData(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = ln.Addr().String()
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+	exp, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), nil))
+	defer exp.Shutdown(context.Background())
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), newTestTraces()))
+
+	expected := []string{
+		`"GET /cart" source="host-1" traceId=01020304-0506-0708-090a-0b0c0d0e0f10 spanId=00000000-0000-0000-1112-131415161718 "_spanLogs"="true" "application"="shop" "error"="true" "host.name"="host-1" "http.method"="GET" "region"="eu-west" "service"="frontend" "service.name"="frontend" "span.kind"="server" "status.message"="timed out" 1577836800000 1500`,
+		`{"traceId":"01020304-0506-0708-090a-0b0c0d0e0f10","spanId":"00000000-0000-0000-1112-131415161718","logs":[{"timestamp":1577836801000000,"fields":{"attempt":"2","name":"retry"}}]}`,
+		`"SELECT" source="host-1" traceId=01020304-0506-0708-090a-0b0c0d0e0f10 spanId=00000000-0000-0000-2122-232425262728 parent=00000000-0000-0000-1112-131415161718 "application"="shop" "host.name"="host-1" "region"="us-west" "service"="frontend" "service.name"="frontend" 1577836800100 200`,
+	}
+	for _, want := range expected {
+		select {
+		case line := <-lines:
+			assert.Equal(t, want, line)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+}
+
+type fakeSender struct {
+	spans    []string
+	sendErr  error
+	flushErr error
+}
+
+func (s *fakeSender) SendSpan(name string, _, _ int64, _, _, _ string, _, _ []string, _ []senders.SpanTag, _ []senders.SpanLog) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.spans = append(s.spans, name)
+	return nil
+}
+
+func (s *fakeSender) Flush() error {
+	return s.flushErr
+}
+
+func (s *fakeSender) Close() {}
+
+func TestPushTraceDataErrors(t *testing.T) {
+	// The second span, without a name, can't be sent.
+	newTraces := func() pdata.Traces {
+		td := newTestTraces()
+		td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(1).SetName("")
+		return td
+	}
+
+	t.Run("invalid span", func(t *testing.T) {
+		sender := &fakeSender{}
+		exp := &tracesExporter{sender: sender, logger: zap.NewNop(), defaultApplication: "app", defaultService: "svc"}
+		dropped, err := exp.pushTraceData(context.Background(), newTraces())
+		assert.Equal(t, 1, dropped)
+		assert.True(t, consumererror.IsPermanent(err))
+		assert.EqualError(t, err, "Permanent error: span has no name")
+		assert.Equal(t, []string{"GET /cart"}, sender.spans)
+	})
+
+	for _, sender := range []*fakeSender{{sendErr: errors.New("connection refused")}, {flushErr: errors.New("connection refused")}} {
+		exp := &tracesExporter{sender: sender, logger: zap.NewNop(), defaultApplication: "app", defaultService: "svc"}
+		_, err := exp.pushTraceData(context.Background(), newTraces())
+		require.Error(t, err)
+		assert.False(t, consumererror.IsPermanent(err))
+		var partialErr consumererror.PartialError
+		require.True(t, errors.As(err, &partialErr))
+		retry := partialErr.GetTraces()
+		require.Equal(t, 1, retry.SpanCount())
+		assert.Equal(t, "GET /cart", retry.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+	}
+}
+
+func TestPushTraceDataDefaultTags(t *testing.T) {
+	td := newTestTraces()
+	td.ResourceSpans().At(0).Resource().Attributes().InitFromMap(map[string]pdata.AttributeValue{})
+
+	exp := &tracesExporter{defaultApplication: "app", defaultService: "svc"}
+	rs := td.ResourceSpans().At(0)
+	ws, err := spanToWavefront(
+		rs.InstrumentationLibrarySpans().At(0).Spans().At(1),
+		resourceSource(rs.Resource().Attributes()),
+		exp.resourceTags(rs.Resource().Attributes()))
+	require.NoError(t, err)
+	assert.Equal(t, "", ws.Source)
+	assert.Equal(t, []senders.SpanTag{{Key: "application", Value: "app"}, {Key: "service", Value: "svc"}}, ws.Tags)
+	assert.Nil(t, ws.SpanLogs)
+}
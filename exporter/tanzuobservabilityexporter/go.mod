@@ -0,0 +1,10 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/tanzuobservabilityexporter
+
+go 1.14
+
+require (
+	github.com/stretchr/testify v1.6.1
+	github.com/wavefronthq/wavefront-sdk-go v0.9.7
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+)
@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tanzuobservabilityexporter
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	// Tags required by Wavefront on every span.
+	tagApplication = "application"
+	tagService     = "service"
+
+	// labelEventName is the field of a span log holding the name of its event.
+	labelEventName = "name"
+
+	// maxTagLength is the maximum length of the key and value of a tag
+	// accepted by Wavefront.
+	maxTagLength = 254
+)
+
+// resourceSource returns the source of the spans of a resource: its host name,
+// or its service name. The Wavefront sender falls back to the name of the
+// collector's host when both are missing.
+func resourceSource(attrs pdata.AttributeMap) string {
+	for _, key := range []string{conventions.AttributeHostName, conventions.AttributeServiceName} {
+		if v, ok := attrs.Get(key); ok {
+			if s := tracetranslator.AttributeValueToString(v, false); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// resourceTags returns the tags shared by the spans of a resource: its
+// attributes, with the application and service tags taken from the
+// application and service.name attributes or defaulted.
+func (e *tracesExporter) resourceTags(attrs pdata.AttributeMap) map[string]string {
+	tags := attributesToTags(attrs)
+	if tags[tagApplication] == "" {
+		tags[tagApplication] = e.defaultApplication
+	}
+	tags[tagService] = e.defaultService
+	if service := tags[conventions.AttributeServiceName]; service != "" {
+		tags[tagService] = service
+	}
+	return tags
+}
+
+// spanTags returns the tags of a span: the resource tags overridden by the span
+// attributes, but for the application and service tags, along with its kind
+// and whether it failed.
+func spanTags(span pdata.Span, resourceTags map[string]string) []senders.SpanTag {
+	tags := make(map[string]string, len(resourceTags)+span.Attributes().Len()+3)
+	for k, v := range attributesToTags(span.Attributes()) {
+		tags[k] = v
+	}
+	for k, v := range resourceTags {
+		if _, ok := tags[k]; !ok || k == tagApplication || k == tagService {
+			tags[k] = v
+		}
+	}
+	if kind := spanKind(span.Kind()); kind != "" {
+		tags[tracetranslator.TagSpanKind] = kind
+	}
+	if span.Status().Code() == pdata.StatusCodeError {
+		tags[tracetranslator.TagError] = "true"
+		if msg := span.Status().Message(); msg != "" {
+			tags[tracetranslator.TagStatusMsg] = msg
+		}
+	}
+
+	spanTags := make([]senders.SpanTag, 0, len(tags))
+	for k, v := range tags {
+		if tag, ok := sanitizeTag(k, v); ok {
+			spanTags = append(spanTags, tag)
+		}
+	}
+	sort.Slice(spanTags, func(i, j int) bool {
+		return spanTags[i].Key < spanTags[j].Key
+	})
+	return spanTags
+}
+
+// spanLogs converts the events of a span to span logs, their name and
+// attributes being the fields of the logs.
+func spanLogs(events pdata.SpanEventSlice) []senders.SpanLog {
+	if events.Len() == 0 {
+		return nil
+	}
+	logs := make([]senders.SpanLog, 0, events.Len())
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		fields := attributesToTags(event.Attributes())
+		if event.Name() != "" {
+			fields[labelEventName] = event.Name()
+		}
+		logs = append(logs, senders.SpanLog{
+			Timestamp: int64(event.Timestamp()) / 1e3,
+			Fields:    fields,
+		})
+	}
+	return logs
+}
+
+func attributesToTags(attrs pdata.AttributeMap) map[string]string {
+	tags := make(map[string]string, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		tags[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+	return tags
+}
+
+// sanitizeTag trims the value of a tag and truncates it to the length
+// accepted by Wavefront, reporting false for tags with an empty key or value.
+// The characters of the key which aren't allowed by Wavefront are replaced by
+// the sender, which also escapes the value.
+func sanitizeTag(key, value string) (senders.SpanTag, bool) {
+	if key == "" || len(key) >= maxTagLength {
+		return senders.SpanTag{}, false
+	}
+	value = strings.TrimSpace(value)
+	if max := maxTagLength - len(key); len(value) > max {
+		for max > 0 && !utf8.RuneStart(value[max]) {
+			max--
+		}
+		value = strings.TrimSpace(value[:max])
+	}
+	return senders.SpanTag{Key: key, Value: value}, value != ""
+}
+
+func spanKind(kind pdata.SpanKind) string {
+	switch kind {
+	case pdata.SpanKindCLIENT:
+		return "client"
+	case pdata.SpanKindSERVER:
+		return "server"
+	case pdata.SpanKindPRODUCER:
+		return "producer"
+	case pdata.SpanKindCONSUMER:
+		return "consumer"
+	case pdata.SpanKindINTERNAL:
+		return "internal"
+	default:
+		return ""
+	}
+}
+
+// traceIDToUUID formats a trace ID as the UUID expected by Wavefront.
+func traceIDToUUID(id pdata.TraceID) string {
+	b := id.Bytes()
+	return formatUUID(b[:])
+}
+
+// spanIDToUUID formats a span ID as the UUID expected by Wavefront, its bytes
+// being the lowest ones of the UUID.
+func spanIDToUUID(id pdata.SpanID) string {
+	var b [16]byte
+	spanID := id.Bytes()
+	copy(b[8:], spanID[:])
+	return formatUUID(b[:])
+}
+
+func formatUUID(b []byte) string {
+	s := hex.EncodeToString(b)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
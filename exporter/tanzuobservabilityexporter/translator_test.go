@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tanzuobservabilityexporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSanitizeTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		tag   senders.SpanTag
+		ok    bool
+	}{
+		{name: "valid", key: "http.method", value: "GET", tag: senders.SpanTag{Key: "http.method", Value: "GET"}, ok: true},
+		{name: "trimmed", key: "k", value: " v\n", tag: senders.SpanTag{Key: "k", Value: "v"}, ok: true},
+		{name: "empty key", key: "", value: "v"},
+		{name: "empty value", key: "k", value: "  "},
+		{name: "long key", key: strings.Repeat("k", maxTagLength), value: "v"},
+		{
+			name:  "truncated",
+			key:   "key",
+			value: strings.Repeat("v", 300),
+			tag:   senders.SpanTag{Key: "key", Value: strings.Repeat("v", maxTagLength-3)},
+			ok:    true,
+		},
+		{
+			name:  "truncated at rune",
+			key:   "ke",
+			value: strings.Repeat("é", 200),
+			tag:   senders.SpanTag{Key: "ke", Value: strings.Repeat("é", 126)},
+			ok:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, ok := sanitizeTag(tt.key, tt.value)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.tag, tag)
+			}
+		})
+	}
+}
+
+func TestResourceSource(t *testing.T) {
+	attrs := pdata.NewAttributeMap()
+	assert.Equal(t, "", resourceSource(attrs))
+	attrs.InsertString("service.name", "frontend")
+	assert.Equal(t, "frontend", resourceSource(attrs))
+	attrs.InsertString("host.name", "host-1")
+	assert.Equal(t, "host-1", resourceSource(attrs))
+}
+
+func TestSpanTagsAttributeValues(t *testing.T) {
+	span := pdata.NewSpan()
+	span.Attributes().InsertBool("cached", true)
+	span.Attributes().InsertDouble("ratio", 0.5)
+	arr := pdata.NewAttributeValueArray()
+	arr.ArrayVal().Append(pdata.NewAttributeValueString("a"))
+	span.Attributes().Insert("list", arr)
+
+	tags := spanTags(span, map[string]string{"application": "app", "service": "svc"})
+	assert.Equal(t, []senders.SpanTag{
+		{Key: "application", Value: "app"},
+		{Key: "cached", Value: "true"},
+		{Key: "list", Value: `["a"]`},
+		{Key: "ratio", Value: "0.5"},
+		{Key: "service", Value: "svc"},
+	}, tags)
+}
@@ -19,8 +19,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
@@ -75,9 +77,11 @@ func (h *httpForwarder) forwardRequest(writer http.ResponseWriter, request *http
 	// Add "Via" header for tracking purposes on both the outgoing requests and responses.
 	// See https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Via.
 	addViaHeader(forwarderRequest.Header, request.Proto, request.Host)
+	addForwardedForHeader(forwarderRequest.Header, request.RemoteAddr)
 
 	response, err := h.httpClient.Do(forwarderRequest)
 	if err != nil {
+		recordForwardedRequest(request.Context(), 0)
 		http.Error(writer, err.Error(), http.StatusBadGateway)
 	}
 
@@ -85,6 +89,7 @@ func (h *httpForwarder) forwardRequest(writer http.ResponseWriter, request *http
 		return
 	}
 	defer response.Body.Close()
+	recordForwardedRequest(request.Context(), response.StatusCode)
 
 	// Copy over response from the final destination.
 	for k := range response.Header {
@@ -107,6 +112,20 @@ func addViaHeader(header http.Header, protocol string, host string) {
 	header.Add("Via", fmt.Sprintf("%s %s", protocol, host))
 }
 
+// addForwardedForHeader appends the address of the client to the "X-Forwarded-For" header, as the other proxies
+// between the client and the extension did.
+// See https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-Forwarded-For.
+func addForwardedForHeader(header http.Header, remoteAddr string) {
+	clientIP, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return
+	}
+	if prior := header.Values("X-Forwarded-For"); len(prior) > 0 {
+		clientIP = strings.Join(prior, ", ") + ", " + clientIP
+	}
+	header.Set("X-Forwarded-For", clientIP)
+}
+
 func newHTTPForwarder(config *Config, logger *zap.Logger) (component.ServiceExtension, error) {
 	if config.Egress.Endpoint == "" {
 		return nil, errors.New("'egress.endpoint' config option cannot be empty")
@@ -27,6 +27,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/testutil"
@@ -235,6 +236,66 @@ func TestExtension(t *testing.T) {
 	}
 }
 
+func TestExtensionForwardedForAndMetrics(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "10.0.0.1, 127.0.0.1", r.Header.Get("X-Forwarded-For"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	listenAt := testutil.GetAvailableLocalAddress(t)
+	hf, err := newHTTPForwarder(&Config{
+		Ingress: confighttp.HTTPServerSettings{Endpoint: listenAt},
+		Egress:  confighttp.HTTPClientSettings{Endpoint: backend.URL},
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, hf.Start(ctx, componenttest.NewNopHost()))
+	defer func() { require.NoError(t, hf.Shutdown(ctx)) }()
+
+	requestsBefore := viewCounts(t, viewForwardedRequests.Name)
+	errorsBefore := viewCounts(t, viewUpstreamErrors.Name)
+
+	httpClient := http.Client{}
+	for _, path := range []string{"/ok", "/fail"} {
+		response, err := httpClient.Do(httpRequest(t, clientRequestArgs{
+			method:  "GET",
+			url:     fmt.Sprintf("http://%s%s", listenAt, path),
+			headers: map[string]string{"X-Forwarded-For": "10.0.0.1"},
+		}))
+		require.NoError(t, err)
+		response.Body.Close()
+	}
+
+	requestsAfter := viewCounts(t, viewForwardedRequests.Name)
+	assert.Equal(t, float64(1), requestsAfter["200"]-requestsBefore["200"])
+	assert.Equal(t, float64(1), requestsAfter["503"]-requestsBefore["503"])
+	errorsAfter := viewCounts(t, viewUpstreamErrors.Name)
+	assert.Equal(t, float64(1), errorsAfter[errorTypeServer]-errorsBefore[errorTypeServer])
+	assert.Equal(t, float64(0), errorsAfter[errorTypeRequest]-errorsBefore[errorTypeRequest])
+}
+
+// viewCounts returns the sums recorded by a view, by the value of their single tag, empty when the tag isn't set.
+func viewCounts(t *testing.T, name string) map[string]float64 {
+	rows, err := view.RetrieveData(name)
+	require.NoError(t, err)
+
+	counts := map[string]float64{}
+	for _, row := range rows {
+		value := ""
+		if len(row.Tags) > 0 {
+			value = row.Tags[0].Value
+		}
+		counts[value] = row.Data.(*view.SumData).Value
+	}
+	return counts
+}
+
 func httpRequest(t *testing.T, args clientRequestArgs) *http.Request {
 	r, err := http.NewRequest(args.method, args.url, ioutil.NopCloser(strings.NewReader(args.body)))
 	require.NoError(t, err)
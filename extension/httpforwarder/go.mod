@@ -4,6 +4,7 @@ go 1.14
 
 require (
 	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.5
 	go.opentelemetry.io/collector v0.18.0
 	go.uber.org/zap v1.16.0
 )
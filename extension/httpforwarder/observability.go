@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpforwarder
+
+import (
+	"context"
+	"strconv"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	view.Register(
+		viewForwardedRequests,
+		viewUpstreamErrors,
+	)
+}
+
+var (
+	mForwardedRequests = stats.Int64("otelcol/http_forwarder/forwarded_requests", "Number of requests forwarded to the egress endpoint", "1")
+	mUpstreamErrors    = stats.Int64("otelcol/http_forwarder/upstream_errors", "Number of forwarded requests which failed or got a 5xx response", "1")
+
+	tagStatusCode, _ = tag.NewKey("status_code")
+	tagErrorType, _  = tag.NewKey("error_type")
+)
+
+const (
+	// errorTypeRequest is the error type of the requests which couldn't be sent, or got no response.
+	errorTypeRequest = "request"
+	// errorTypeServer is the error type of the requests which got a 5xx response.
+	errorTypeServer = "server"
+)
+
+var viewForwardedRequests = &view.View{
+	Name:        mForwardedRequests.Name(),
+	Description: mForwardedRequests.Description(),
+	Measure:     mForwardedRequests,
+	TagKeys:     []tag.Key{tagStatusCode},
+	Aggregation: view.Sum(),
+}
+
+var viewUpstreamErrors = &view.View{
+	Name:        mUpstreamErrors.Name(),
+	Description: mUpstreamErrors.Description(),
+	Measure:     mUpstreamErrors,
+	TagKeys:     []tag.Key{tagErrorType},
+	Aggregation: view.Sum(),
+}
+
+// recordForwardedRequest records a forwarded request, along with its error, if any. The status code is the one of
+// the response of the egress endpoint, or zero when there's none.
+func recordForwardedRequest(ctx context.Context, statusCode int) {
+	status := ""
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagStatusCode, status)}, mForwardedRequests.M(1))
+
+	switch {
+	case statusCode == 0:
+		_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagErrorType, errorTypeRequest)}, mUpstreamErrors.M(1))
+	case statusCode >= 500:
+		_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagErrorType, errorTypeServer)}, mUpstreamErrors.M(1))
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines the configuration of the extension.
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// HTTPServerSettings holds the settings of the HTTP server serving the `/sampling` endpoint. The server is
+	// disabled when its endpoint is empty.
+	HTTPServerSettings confighttp.HTTPServerSettings `mapstructure:"http"`
+
+	// GRPCServerSettings holds the settings of the gRPC server serving the SamplingManager service. The server is
+	// disabled when its endpoint is empty.
+	GRPCServerSettings configgrpc.GRPCServerSettings `mapstructure:"grpc"`
+
+	// Source is where the sampling strategies are read from.
+	Source Source `mapstructure:"source"`
+}
+
+// Source defines where the sampling strategies are read from. Exactly one of File and Remote must be set.
+type Source struct {
+	// File is the path of the JSON file holding the sampling strategies, in the format of the Jaeger collector.
+	File string `mapstructure:"file"`
+
+	// Remote holds the settings of the gRPC endpoint serving the sampling strategies, e.g. a Jaeger collector.
+	Remote *configgrpc.GRPCClientSettings `mapstructure:"remote"`
+
+	// ReloadInterval is the interval at which the file is reloaded, or the duration for which the strategies of the
+	// remote endpoint are cached. Zero disables the reloads of the file and the cache.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
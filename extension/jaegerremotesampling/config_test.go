@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	require.Len(t, cfg.Extensions, 3)
+
+	ext0 := cfg.Extensions["jaegerremotesampling"]
+	assert.Equal(t, factory.CreateDefaultConfig(), ext0)
+
+	ext1 := cfg.Extensions["jaegerremotesampling/file"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "jaegerremotesampling",
+				NameVal: "jaegerremotesampling/file",
+			},
+			HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: "0.0.0.0:5778"},
+			GRPCServerSettings: configgrpc.GRPCServerSettings{
+				NetAddr: confignet.NetAddr{Endpoint: "", Transport: "tcp"},
+			},
+			Source: Source{
+				File:           "/etc/otel/sampling_strategies.json",
+				ReloadInterval: 30 * time.Second,
+			},
+		},
+		ext1)
+
+	ext2 := cfg.Extensions["jaegerremotesampling/remote"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "jaegerremotesampling",
+				NameVal: "jaegerremotesampling/remote",
+			},
+			HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: defaultHTTPEndpoint},
+			GRPCServerSettings: configgrpc.GRPCServerSettings{
+				NetAddr: confignet.NetAddr{Endpoint: defaultGRPCEndpoint, Transport: "tcp"},
+			},
+			Source: Source{
+				Remote: &configgrpc.GRPCClientSettings{
+					Endpoint: "jaeger-collector:14250",
+					TLSSetting: configtls.TLSClientSetting{
+						Insecure: true,
+					},
+				},
+				ReloadInterval: time.Minute,
+			},
+		},
+		ext2)
+}
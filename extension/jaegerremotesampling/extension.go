@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/plugin/sampling/strategystore/static"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type jaegerRemoteSampling struct {
+	config *Config
+	logger *zap.Logger
+
+	store      strategystore.StrategyStore
+	closeStore func()
+	httpServer *http.Server
+	grpcServer *grpc.Server
+}
+
+var _ component.ServiceExtension = (*jaegerRemoteSampling)(nil)
+
+func (j *jaegerRemoteSampling) Start(_ context.Context, host component.Host) error {
+	source := j.config.Source
+	if source.File != "" {
+		// The static store of the Jaeger collector validates the file, returns the default strategy to the unknown
+		// services and keeps the previous strategies when a reload fails.
+		store, err := static.NewStrategyStore(static.Options{
+			StrategiesFile: source.File,
+			ReloadInterval: source.ReloadInterval,
+		}, j.logger)
+		if err != nil {
+			return fmt.Errorf("failed to load the sampling strategies from %s: %w", source.File, err)
+		}
+		j.store = store
+		if closer, ok := store.(interface{ Close() }); ok {
+			j.closeStore = closer.Close
+		}
+	} else {
+		store, err := newRemoteStrategyStore(source.Remote, source.ReloadInterval, j.logger)
+		if err != nil {
+			return err
+		}
+		j.store = store
+		j.closeStore = store.Close
+	}
+
+	handler := &samplingHandler{store: j.store, logger: j.logger}
+
+	if j.config.HTTPServerSettings.Endpoint != "" {
+		listener, err := j.config.HTTPServerSettings.ToListener()
+		if err != nil {
+			return fmt.Errorf("failed to bind to address %s: %w", j.config.HTTPServerSettings.Endpoint, err)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/sampling", handler.serveHTTP)
+		j.httpServer = j.config.HTTPServerSettings.ToServer(mux)
+		go func() {
+			if err := j.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+				host.ReportFatalError(err)
+			}
+		}()
+	}
+
+	if j.config.GRPCServerSettings.NetAddr.Endpoint != "" {
+		opts, err := j.config.GRPCServerSettings.ToServerOption()
+		if err != nil {
+			return fmt.Errorf("failed to create the gRPC server options: %w", err)
+		}
+		listener, err := j.config.GRPCServerSettings.ToListener()
+		if err != nil {
+			return fmt.Errorf("failed to bind to address %s: %w", j.config.GRPCServerSettings.NetAddr.Endpoint, err)
+		}
+		j.grpcServer = grpc.NewServer(opts...)
+		api_v2.RegisterSamplingManagerServer(j.grpcServer, handler)
+		go func() {
+			if err := j.grpcServer.Serve(listener); err != nil {
+				host.ReportFatalError(err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+func (j *jaegerRemoteSampling) Shutdown(_ context.Context) error {
+	var err error
+	if j.httpServer != nil {
+		err = j.httpServer.Close()
+	}
+	if j.grpcServer != nil {
+		j.grpcServer.Stop()
+	}
+	if j.closeStore != nil {
+		j.closeStore()
+	}
+	return err
+}
+
+func newExtension(config *Config, logger *zap.Logger) (component.ServiceExtension, error) {
+	source := config.Source
+	if (source.File == "") == (source.Remote == nil) {
+		return nil, errors.New("exactly one of 'source.file' and 'source.remote' must be set")
+	}
+	if source.Remote != nil && source.Remote.Endpoint == "" {
+		return nil, errors.New("'source.remote.endpoint' config option cannot be empty")
+	}
+	if source.ReloadInterval < 0 {
+		return nil, fmt.Errorf("'source.reload_interval' can't be negative: %v", source.ReloadInterval)
+	}
+	if config.HTTPServerSettings.Endpoint == "" && config.GRPCServerSettings.NetAddr.Endpoint == "" {
+		return nil, errors.New("at least one of 'http.endpoint' and 'grpc.endpoint' must be set")
+	}
+
+	return &jaegerRemoteSampling{
+		config: config,
+		logger: logger,
+	}, nil
+}
@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+func TestExtensionFileSource(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.HTTPServerSettings.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.GRPCServerSettings.NetAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.Source.File = path.Join(".", "testdata", "strategies.json")
+
+	ext, err := newExtension(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, ext.Shutdown(context.Background())) }()
+	initialCounts := requestCounts(t, "foo", "unknown", "bar")
+
+	strategy, status := httpStrategy(t, cfg.HTTPServerSettings.Endpoint, "foo")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, sampling.SamplingStrategyType_PROBABILISTIC, strategy.StrategyType)
+	assert.Equal(t, 0.8, strategy.ProbabilisticSampling.SamplingRate)
+	require.NotNil(t, strategy.OperationSampling)
+	require.Len(t, strategy.OperationSampling.PerOperationStrategies, 1)
+	assert.Equal(t, "op1", strategy.OperationSampling.PerOperationStrategies[0].Operation)
+
+	// The services without a strategy get the default one.
+	strategy, status = httpStrategy(t, cfg.HTTPServerSettings.Endpoint, "unknown")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 0.5, strategy.ProbabilisticSampling.SamplingRate)
+
+	resp, err := http.Get("http://" + cfg.HTTPServerSettings.Endpoint + "/sampling")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	conn, err := grpc.Dial(cfg.GRPCServerSettings.NetAddr.Endpoint, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+	grpcResponse, err := api_v2.NewSamplingManagerClient(conn).GetSamplingStrategy(
+		context.Background(), &api_v2.SamplingStrategyParameters{ServiceName: "bar"})
+	require.NoError(t, err)
+	assert.Equal(t, api_v2.SamplingStrategyType_RATE_LIMITING, grpcResponse.StrategyType)
+	assert.Equal(t, int32(5), grpcResponse.RateLimitingSampling.MaxTracesPerSecond)
+
+	counts := requestCounts(t, "foo", "unknown", "bar")
+	for key, count := range initialCounts {
+		counts[key] -= count
+	}
+	assert.Equal(t, map[[2]string]float64{
+		{"foo", transportHTTP}:     1,
+		{"unknown", transportHTTP}: 1,
+		{"bar", transportGRPC}:     1,
+	}, counts)
+}
+
+func TestExtensionFileReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jaegerremotesampling")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "strategies.json")
+	writeStrategies := func(rate float64) {
+		encoded, err := json.Marshal(map[string]interface{}{
+			"default_strategy": map[string]interface{}{"type": "probabilistic", "param": rate},
+		})
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(file, encoded, 0600))
+	}
+	writeStrategies(0.1)
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.HTTPServerSettings.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.GRPCServerSettings.NetAddr.Endpoint = ""
+	cfg.Source.File = file
+	cfg.Source.ReloadInterval = 10 * time.Millisecond
+
+	ext, err := newExtension(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, ext.Shutdown(context.Background())) }()
+
+	strategy, _ := httpStrategy(t, cfg.HTTPServerSettings.Endpoint, "reloaded")
+	assert.Equal(t, 0.1, strategy.ProbabilisticSampling.SamplingRate)
+
+	writeStrategies(0.2)
+	assert.Eventually(t, func() bool {
+		strategy, _ := httpStrategy(t, cfg.HTTPServerSettings.Endpoint, "reloaded")
+		return strategy.ProbabilisticSampling.SamplingRate == 0.2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// An invalid file is ignored, the previous strategies being kept.
+	require.NoError(t, ioutil.WriteFile(file, []byte("invalid"), 0600))
+	time.Sleep(50 * time.Millisecond)
+	strategy, _ = httpStrategy(t, cfg.HTTPServerSettings.Endpoint, "reloaded")
+	assert.Equal(t, 0.2, strategy.ProbabilisticSampling.SamplingRate)
+}
+
+func TestExtensionInvalidFile(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Source.File = path.Join(".", "testdata", "config.yaml")
+
+	ext, err := newExtension(cfg, zap.NewNop())
+	require.NoError(t, err)
+	err = ext.Start(context.Background(), componenttest.NewNopHost())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load the sampling strategies from testdata/config.yaml")
+}
+
+func httpStrategy(t *testing.T, endpoint string, service string) (*sampling.SamplingStrategyResponse, int) {
+	resp, err := http.Get("http://" + endpoint + "/sampling?service=" + service)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var strategy sampling.SamplingStrategyResponse
+	if resp.StatusCode == http.StatusOK {
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&strategy))
+	}
+	return &strategy, resp.StatusCode
+}
+
+// requestCounts returns the number of requests recorded for the services, by service and transport.
+func requestCounts(t *testing.T, services ...string) map[[2]string]float64 {
+	rows, err := view.RetrieveData(viewRequests.Name)
+	require.NoError(t, err)
+
+	counts := map[[2]string]float64{}
+	for _, row := range rows {
+		var key [2]string
+		for _, tag := range row.Tags {
+			switch tag.Key {
+			case tagService:
+				key[0] = tag.Value
+			case tagTransport:
+				key[1] = tag.Value
+			}
+		}
+		for _, service := range services {
+			if key[0] == service {
+				counts[key] = row.Data.(*view.SumData).Value
+			}
+		}
+	}
+	return counts
+}
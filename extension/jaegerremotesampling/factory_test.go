@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	f := NewFactory()
+	require.Equal(t, configmodels.Type("jaegerremotesampling"), f.Type())
+
+	cfg := f.CreateDefaultConfig().(*Config)
+	assert.Equal(t, "jaegerremotesampling", cfg.Name())
+	assert.Equal(t, "0.0.0.0:5778", cfg.HTTPServerSettings.Endpoint)
+	assert.Equal(t, "0.0.0.0:14250", cfg.GRPCServerSettings.NetAddr.Endpoint)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateExtensionInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "no source",
+			modify: func(cfg *Config) {},
+			err:    "exactly one of 'source.file' and 'source.remote' must be set",
+		},
+		{
+			name: "both sources",
+			modify: func(cfg *Config) {
+				cfg.Source.File = "strategies.json"
+				cfg.Source.Remote = &configgrpc.GRPCClientSettings{Endpoint: "localhost:14250"}
+			},
+			err: "exactly one of 'source.file' and 'source.remote' must be set",
+		},
+		{
+			name: "missing remote endpoint",
+			modify: func(cfg *Config) {
+				cfg.Source.Remote = &configgrpc.GRPCClientSettings{}
+			},
+			err: "'source.remote.endpoint' config option cannot be empty",
+		},
+		{
+			name: "negative reload interval",
+			modify: func(cfg *Config) {
+				cfg.Source.File = "strategies.json"
+				cfg.Source.ReloadInterval = -time.Second
+			},
+			err: "'source.reload_interval' can't be negative: -1s",
+		},
+		{
+			name: "no server",
+			modify: func(cfg *Config) {
+				cfg.Source.File = "strategies.json"
+				cfg.HTTPServerSettings.Endpoint = ""
+				cfg.GRPCServerSettings.NetAddr.Endpoint = ""
+			},
+			err: "at least one of 'http.endpoint' and 'grpc.endpoint' must be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			tt.modify(cfg)
+
+			params := component.ExtensionCreateParams{Logger: zap.NewNop()}
+			_, err := factory.CreateExtension(context.Background(), params, cfg)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
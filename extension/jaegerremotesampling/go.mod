@@ -0,0 +1,12 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/extension/jaegerremotesampling
+
+go 1.14
+
+require (
+	github.com/jaegertracing/jaeger v1.21.0
+	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.5
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+	google.golang.org/grpc v1.34.1
+)
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	thriftconverter "github.com/jaegertracing/jaeger/model/converter/thrift/jaeger"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"go.uber.org/zap"
+)
+
+// samplingHandler serves the sampling strategies of the store over HTTP, like the Jaeger agent, and over gRPC, like
+// the Jaeger collector.
+type samplingHandler struct {
+	store  strategystore.StrategyStore
+	logger *zap.Logger
+}
+
+var _ api_v2.SamplingManagerServer = (*samplingHandler)(nil)
+
+func (h *samplingHandler) GetSamplingStrategy(
+	ctx context.Context,
+	params *api_v2.SamplingStrategyParameters,
+) (*api_v2.SamplingStrategyResponse, error) {
+	recordRequest(ctx, params.ServiceName, transportGRPC)
+	response, err := h.store.GetSamplingStrategy(ctx, params.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	return thriftconverter.ConvertSamplingResponseToDomain(response)
+}
+
+// serveHTTP writes the strategy of the service in the JSON encoding of the Thrift structures, which is the one
+// expected by the Jaeger clients.
+func (h *samplingHandler) serveHTTP(writer http.ResponseWriter, request *http.Request) {
+	services := request.URL.Query()["service"]
+	if len(services) != 1 {
+		http.Error(writer, "'service' parameter must be provided once", http.StatusBadRequest)
+		return
+	}
+	recordRequest(request.Context(), services[0], transportHTTP)
+
+	response, err := h.store.GetSamplingStrategy(request.Context(), services[0])
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	if _, err := writer.Write(encoded); err != nil {
+		h.logger.Warn("Error writing HTTP response message", zap.Error(err))
+	}
+}
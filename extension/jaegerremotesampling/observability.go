@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	view.Register(viewRequests)
+}
+
+var (
+	mRequests = stats.Int64("otelcol/jaegerremotesampling/requests", "Number of requests for sampling strategies", "1")
+
+	tagService, _   = tag.NewKey("service")
+	tagTransport, _ = tag.NewKey("transport")
+)
+
+const (
+	transportHTTP = "http"
+	transportGRPC = "grpc"
+)
+
+var viewRequests = &view.View{
+	Name:        mRequests.Name(),
+	Description: mRequests.Description(),
+	Measure:     mRequests,
+	TagKeys:     []tag.Key{tagService, tagTransport},
+	Aggregation: view.Sum(),
+}
+
+// recordRequest records a request for the sampling strategy of the service, received over the transport.
+func recordRequest(ctx context.Context, service string, transport string) {
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagService, service), tag.Upsert(tagTransport, transport)},
+		mRequests.M(1))
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	thriftconverter "github.com/jaegertracing/jaeger/model/converter/thrift/jaeger"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// remoteStrategyStore proxies the requests for sampling strategies to a remote SamplingManager service, caching its
+// responses per service.
+type remoteStrategyStore struct {
+	conn          *grpc.ClientConn
+	client        api_v2.SamplingManagerClient
+	cacheDuration time.Duration
+	logger        *zap.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedStrategy
+}
+
+type cachedStrategy struct {
+	response *sampling.SamplingStrategyResponse
+	expiry   time.Time
+}
+
+func newRemoteStrategyStore(
+	settings *configgrpc.GRPCClientSettings,
+	cacheDuration time.Duration,
+	logger *zap.Logger,
+) (*remoteStrategyStore, error) {
+	opts, err := settings.ToDialOptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the gRPC dial options of the remote endpoint: %w", err)
+	}
+	conn, err := grpc.Dial(settings.Endpoint, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the remote endpoint %s: %w", settings.Endpoint, err)
+	}
+
+	return &remoteStrategyStore{
+		conn:          conn,
+		client:        api_v2.NewSamplingManagerClient(conn),
+		cacheDuration: cacheDuration,
+		logger:        logger,
+		cache:         map[string]cachedStrategy{},
+	}, nil
+}
+
+// GetSamplingStrategy returns the cached strategy of the service, or the one of the remote endpoint when it's missing
+// or expired. The expired strategy is still returned when the remote endpoint fails.
+func (s *remoteStrategyStore) GetSamplingStrategy(
+	ctx context.Context,
+	serviceName string,
+) (*sampling.SamplingStrategyResponse, error) {
+	s.mu.Lock()
+	cached, found := s.cache[serviceName]
+	s.mu.Unlock()
+	if found && time.Now().Before(cached.expiry) {
+		return cached.response, nil
+	}
+
+	remoteResponse, err := s.client.GetSamplingStrategy(ctx, &api_v2.SamplingStrategyParameters{ServiceName: serviceName})
+	if err != nil {
+		if found {
+			s.logger.Warn("Failed to refresh the sampling strategy, returning the cached one",
+				zap.String("service", serviceName), zap.Error(err))
+			return cached.response, nil
+		}
+		return nil, fmt.Errorf("failed to get the sampling strategy of %q from the remote endpoint: %w", serviceName, err)
+	}
+
+	response, err := thriftconverter.ConvertSamplingResponseFromDomain(remoteResponse)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sampling strategy of %q returned by the remote endpoint: %w", serviceName, err)
+	}
+
+	if s.cacheDuration > 0 {
+		s.mu.Lock()
+		s.cache[serviceName] = cachedStrategy{response: response, expiry: time.Now().Add(s.cacheDuration)}
+		s.mu.Unlock()
+	}
+	return response, nil
+}
+
+func (s *remoteStrategyStore) Close() {
+	_ = s.conn.Close()
+}
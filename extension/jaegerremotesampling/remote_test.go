@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerremotesampling
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// fakeSamplingManager returns a probabilistic strategy whose rate is the number of requests received.
+type fakeSamplingManager struct {
+	mu       sync.Mutex
+	requests int
+	err      error
+}
+
+func (f *fakeSamplingManager) GetSamplingStrategy(
+	_ context.Context,
+	_ *api_v2.SamplingStrategyParameters,
+) (*api_v2.SamplingStrategyResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.requests++
+	return &api_v2.SamplingStrategyResponse{
+		StrategyType:          api_v2.SamplingStrategyType_PROBABILISTIC,
+		ProbabilisticSampling: &api_v2.ProbabilisticSamplingStrategy{SamplingRate: float64(f.requests)},
+	}, nil
+}
+
+func (f *fakeSamplingManager) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func startFakeSamplingManager(t *testing.T) (*fakeSamplingManager, string, func()) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	fake := &fakeSamplingManager{}
+	server := grpc.NewServer()
+	api_v2.RegisterSamplingManagerServer(server, fake)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return fake, listener.Addr().String(), server.Stop
+}
+
+func newTestRemoteStrategyStore(t *testing.T, endpoint string, cacheDuration time.Duration) *remoteStrategyStore {
+	store, err := newRemoteStrategyStore(&configgrpc.GRPCClientSettings{
+		Endpoint:   endpoint,
+		TLSSetting: configtls.TLSClientSetting{Insecure: true},
+	}, cacheDuration, zap.NewNop())
+	require.NoError(t, err)
+	return store
+}
+
+func samplingRate(t *testing.T, store *remoteStrategyStore, service string) float64 {
+	strategy, err := store.GetSamplingStrategy(context.Background(), service)
+	require.NoError(t, err)
+	assert.Equal(t, sampling.SamplingStrategyType_PROBABILISTIC, strategy.StrategyType)
+	return strategy.ProbabilisticSampling.SamplingRate
+}
+
+func TestRemoteStrategyStoreCache(t *testing.T) {
+	_, endpoint, stop := startFakeSamplingManager(t)
+	defer stop()
+
+	store := newTestRemoteStrategyStore(t, endpoint, time.Hour)
+	defer store.Close()
+
+	assert.Equal(t, 1.0, samplingRate(t, store, "foo"))
+	assert.Equal(t, 1.0, samplingRate(t, store, "foo"))
+	// The strategies are cached per service.
+	assert.Equal(t, 2.0, samplingRate(t, store, "bar"))
+}
+
+func TestRemoteStrategyStoreWithoutCache(t *testing.T) {
+	_, endpoint, stop := startFakeSamplingManager(t)
+	defer stop()
+
+	store := newTestRemoteStrategyStore(t, endpoint, 0)
+	defer store.Close()
+
+	assert.Equal(t, 1.0, samplingRate(t, store, "foo"))
+	assert.Equal(t, 2.0, samplingRate(t, store, "foo"))
+}
+
+func TestRemoteStrategyStoreExpiredCache(t *testing.T) {
+	fake, endpoint, stop := startFakeSamplingManager(t)
+	defer stop()
+
+	store := newTestRemoteStrategyStore(t, endpoint, time.Millisecond)
+	defer store.Close()
+
+	assert.Equal(t, 1.0, samplingRate(t, store, "foo"))
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, 2.0, samplingRate(t, store, "foo"))
+
+	// The expired strategy is returned when the remote endpoint fails.
+	fake.setErr(errors.New("unavailable"))
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, 2.0, samplingRate(t, store, "foo"))
+
+	_, err := store.GetSamplingStrategy(context.Background(), "bar")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to get the sampling strategy of "bar" from the remote endpoint`)
+}
+
+func TestExtensionRemoteSource(t *testing.T) {
+	_, endpoint, stop := startFakeSamplingManager(t)
+	defer stop()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.HTTPServerSettings.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.GRPCServerSettings.NetAddr.Endpoint = ""
+	cfg.Source.Remote = &configgrpc.GRPCClientSettings{
+		Endpoint:   endpoint,
+		TLSSetting: configtls.TLSClientSetting{Insecure: true},
+	}
+	cfg.Source.ReloadInterval = time.Hour
+
+	ext, err := newExtension(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, ext.Shutdown(context.Background())) }()
+
+	strategy, status := httpStrategy(t, cfg.HTTPServerSettings.Endpoint, "remote")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1.0, strategy.ProbabilisticSampling.SamplingRate)
+}
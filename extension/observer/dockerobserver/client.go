@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
+	docker "github.com/docker/docker/client"
+)
+
+const (
+	dockerAPIVersion = "v1.22"
+	userAgent        = "OpenTelemetry-Collector Docker Observer/v0.0.1"
+)
+
+// dockerClient is the subset of the docker API used by the observer.
+type dockerClient interface {
+	ContainerList(ctx context.Context, options dtypes.ContainerListOptions) ([]dtypes.Container, error)
+	ContainerInspect(ctx context.Context, containerID string) (dtypes.ContainerJSON, error)
+	Events(ctx context.Context, options dtypes.EventsOptions) (<-chan devents.Message, <-chan error)
+}
+
+var _ dockerClient = (*docker.Client)(nil)
+
+func newDockerClient(config *Config) (*docker.Client, error) {
+	var opts []docker.Opt
+	if config.TLSSetting != nil {
+		tlsConfig, err := config.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("could not load docker client TLS config: %w", err)
+		}
+		// the client needs to be set before the host, so that the transport is configured for it
+		opts = append(opts, docker.WithHTTPClient(&http.Client{
+			Transport:     &http.Transport{TLSClientConfig: tlsConfig},
+			CheckRedirect: docker.CheckRedirect,
+		}))
+	}
+	opts = append(opts,
+		docker.WithHost(config.Endpoint),
+		docker.WithVersion(dockerAPIVersion),
+		docker.WithHTTPHeaders(map[string]string{"User-Agent": userAgent}),
+	)
+
+	client, err := docker.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create docker client: %w", err)
+	}
+	return client, nil
+}
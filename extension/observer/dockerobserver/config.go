@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// Config defines configuration for docker observer
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// Endpoint is the URL of the docker daemon. Default is "unix:///var/run/docker.sock".
+	Endpoint string `mapstructure:"endpoint"`
+
+	// TLSSetting holds the TLS settings to use when connecting to a docker daemon over TCP. Not set by default, the
+	// connection being unencrypted.
+	TLSSetting *configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// Timeout is the maximum amount of time to wait for docker API responses. Default is 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// ExcludedImages is a list of globs matching the images of the containers to ignore.
+	ExcludedImages []string `mapstructure:"excluded_images"`
+
+	// UseHostBindings makes the endpoints target the ports of the host the container ports are published on, rather
+	// than the container ports. The container ports which aren't published are then ignored.
+	UseHostBindings bool `mapstructure:"use_host_bindings"`
+
+	// DebounceInterval is the time to wait after an event of a container before updating its endpoints, so that
+	// the containers restarting in a loop don't have their endpoints added and removed at each restart. Default is
+	// 1s.
+	DebounceInterval time.Duration `mapstructure:"debounce_interval"`
+}
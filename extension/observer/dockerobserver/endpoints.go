@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// containerEndpoints returns the endpoints of the ports exposed or published by the container, sorted by port.
+func containerEndpoints(observerName string, container *dtypes.ContainerJSON, useHostBindings bool) []observer.Endpoint {
+	if container.Config == nil {
+		return nil
+	}
+
+	ports := map[nat.Port][]nat.PortBinding{}
+	for port := range container.Config.ExposedPorts {
+		ports[port] = nil
+	}
+	containerIP := ""
+	if container.NetworkSettings != nil {
+		for port, bindings := range container.NetworkSettings.Ports {
+			ports[port] = bindings
+		}
+		containerIP = containerIPAddress(container.NetworkSettings)
+	}
+
+	name := strings.TrimPrefix(container.Name, "/")
+	endpoints := make([]observer.Endpoint, 0, len(ports))
+	for port, bindings := range ports {
+		hostIP, hostPort := hostBinding(bindings)
+		target := fmt.Sprintf("%s:%d", containerIP, port.Int())
+		if useHostBindings {
+			if hostPort == 0 {
+				continue
+			}
+			target = fmt.Sprintf("%s:%d", hostIP, hostPort)
+		} else if containerIP == "" {
+			// The containers of the host network, or without network, can't be reached at their own address.
+			continue
+		}
+
+		endpoints = append(endpoints, observer.Endpoint{
+			ID:     observer.EndpointID(fmt.Sprintf("(%s)%s:%s", observerName, container.ID, port)),
+			Target: target,
+			Details: &observer.ContainerPort{
+				Name:        name,
+				Image:       container.Config.Image,
+				ContainerID: container.ID,
+				Labels:      container.Config.Labels,
+				Port:        uint16(port.Int()),
+				HostPort:    hostPort,
+				Transport:   transport(port.Proto()),
+			},
+		})
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].ID < endpoints[j].ID
+	})
+	return endpoints
+}
+
+// containerIPAddress returns the address of the container on the default bridge network, or on the first of its
+// other networks.
+func containerIPAddress(settings *dtypes.NetworkSettings) string {
+	if settings.IPAddress != "" {
+		return settings.IPAddress
+	}
+	names := make([]string, 0, len(settings.Networks))
+	for name := range settings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if network := settings.Networks[name]; network != nil && network.IPAddress != "" {
+			return network.IPAddress
+		}
+	}
+	return ""
+}
+
+// hostBinding returns the address and port of the first host binding of the IPv4 addresses, or of any address when
+// the port is only published on IPv6 ones.
+func hostBinding(bindings []nat.PortBinding) (string, uint16) {
+	var selected *nat.PortBinding
+	for i := range bindings {
+		binding := &bindings[i]
+		if binding.HostPort == "" {
+			continue
+		}
+		if selected == nil || (strings.Contains(selected.HostIP, ":") && !strings.Contains(binding.HostIP, ":")) {
+			selected = binding
+		}
+	}
+	if selected == nil {
+		return "", 0
+	}
+
+	port, err := nat.ParsePort(selected.HostPort)
+	if err != nil || port <= 0 {
+		return "", 0
+	}
+	hostIP := selected.HostIP
+	switch hostIP {
+	case "", "0.0.0.0":
+		hostIP = "127.0.0.1"
+	case "::":
+		hostIP = "[::1]"
+	default:
+		if strings.Contains(hostIP, ":") {
+			hostIP = "[" + hostIP + "]"
+		}
+	}
+	return hostIP, uint16(port)
+}
+
+func transport(protocol string) observer.Transport {
+	switch strings.ToLower(protocol) {
+	case "tcp":
+		return observer.ProtocolTCP
+	case "udp":
+		return observer.ProtocolUDP
+	}
+	return observer.ProtocolUnknown
+}
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"testing"
+
+	dtypes "github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	dnetwork "github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func newContainer(id, image, ip string, ports nat.PortMap) *dtypes.ContainerJSON {
+	exposed := nat.PortSet{}
+	for port := range ports {
+		exposed[port] = struct{}{}
+	}
+	return &dtypes.ContainerJSON{
+		ContainerJSONBase: &dtypes.ContainerJSONBase{
+			ID:    id,
+			Name:  "/" + id + "-name",
+			State: &dtypes.ContainerState{Running: true},
+		},
+		Config: &dcontainer.Config{
+			Image:        image,
+			Labels:       map[string]string{"app": id},
+			ExposedPorts: exposed,
+		},
+		NetworkSettings: &dtypes.NetworkSettings{
+			DefaultNetworkSettings: dtypes.DefaultNetworkSettings{IPAddress: ip},
+			NetworkSettingsBase:    dtypes.NetworkSettingsBase{Ports: ports},
+		},
+	}
+}
+
+func TestContainerEndpoints(t *testing.T) {
+	container := newContainer("abc", "redis:6", "172.17.0.2", nat.PortMap{
+		"6379/tcp": {{HostIP: "0.0.0.0", HostPort: "16379"}, {HostIP: "::", HostPort: "16379"}},
+		"9121/tcp": nil,
+		"53/udp":   {{HostIP: "10.0.0.1", HostPort: "5353"}},
+	})
+
+	details := func(port, hostPort uint16, transport observer.Transport) *observer.ContainerPort {
+		return &observer.ContainerPort{
+			Name:        "abc-name",
+			Image:       "redis:6",
+			ContainerID: "abc",
+			Labels:      map[string]string{"app": "abc"},
+			Port:        port,
+			HostPort:    hostPort,
+			Transport:   transport,
+		}
+	}
+
+	assert.Equal(t, []observer.Endpoint{
+		{ID: "(docker_observer)abc:53/udp", Target: "172.17.0.2:53", Details: details(53, 5353, observer.ProtocolUDP)},
+		{ID: "(docker_observer)abc:6379/tcp", Target: "172.17.0.2:6379", Details: details(6379, 16379, observer.ProtocolTCP)},
+		{ID: "(docker_observer)abc:9121/tcp", Target: "172.17.0.2:9121", Details: details(9121, 0, observer.ProtocolTCP)},
+	}, containerEndpoints("docker_observer", container, false))
+
+	assert.Equal(t, []observer.Endpoint{
+		{ID: "(docker_observer)abc:53/udp", Target: "10.0.0.1:5353", Details: details(53, 5353, observer.ProtocolUDP)},
+		{ID: "(docker_observer)abc:6379/tcp", Target: "127.0.0.1:16379", Details: details(6379, 16379, observer.ProtocolTCP)},
+	}, containerEndpoints("docker_observer", container, true))
+}
+
+func TestContainerEndpointsWithoutAddress(t *testing.T) {
+	container := newContainer("abc", "redis:6", "", nat.PortMap{"6379/tcp": nil})
+	assert.Empty(t, containerEndpoints("docker_observer", container, false))
+
+	container.NetworkSettings.Networks = map[string]*dnetwork.EndpointSettings{
+		"backend":  {IPAddress: "10.1.0.2"},
+		"frontend": {IPAddress: "10.2.0.2"},
+	}
+	endpoints := containerEndpoints("docker_observer", container, false)
+	assert.Len(t, endpoints, 1)
+	assert.Equal(t, "10.1.0.2:6379", endpoints[0].Target)
+}
+
+func TestHostBinding(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindings []nat.PortBinding
+		ip       string
+		port     uint16
+	}{
+		{name: "none"},
+		{name: "any ipv4", bindings: []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "8080"}}, ip: "127.0.0.1", port: 8080},
+		{name: "any ipv6", bindings: []nat.PortBinding{{HostIP: "::", HostPort: "8080"}}, ip: "[::1]", port: 8080},
+		{name: "ipv6", bindings: []nat.PortBinding{{HostIP: "fe80::1", HostPort: "8080"}}, ip: "[fe80::1]", port: 8080},
+		{
+			name:     "ipv4 preferred",
+			bindings: []nat.PortBinding{{HostIP: "::", HostPort: "8081"}, {HostIP: "10.0.0.1", HostPort: "8080"}},
+			ip:       "10.0.0.1",
+			port:     8080,
+		},
+		{name: "unpublished", bindings: []nat.PortBinding{{HostIP: "0.0.0.0"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, port := hostBinding(tt.bindings)
+			assert.Equal(t, tt.ip, ip)
+			assert.Equal(t, tt.port, port)
+		})
+	}
+}
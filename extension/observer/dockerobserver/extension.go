@@ -0,0 +1,330 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
+	dfilters "github.com/docker/docker/api/types/filters"
+	docker "github.com/docker/docker/client"
+	"github.com/gobwas/glob"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// defaultReconnectDelay is the time to wait before reconnecting to the docker daemon after a failure.
+const defaultReconnectDelay = 3 * time.Second
+
+type dockerObserver struct {
+	logger         *zap.Logger
+	config         *Config
+	client         dockerClient
+	excludedImages []glob.Glob
+	reconnectDelay time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu sync.Mutex
+	// endpoints holds the endpoints of each container, by container id.
+	endpoints map[string][]observer.Endpoint
+	listeners []observer.Notify
+	// pending holds the timers of the debounced updates, by container id.
+	pending map[string]*time.Timer
+}
+
+var _ component.ServiceExtension = (*dockerObserver)(nil)
+var _ observer.Observable = (*dockerObserver)(nil)
+
+func newObserver(logger *zap.Logger, config *Config, client dockerClient) (component.ServiceExtension, error) {
+	excludedImages := make([]glob.Glob, 0, len(config.ExcludedImages))
+	for _, pattern := range config.ExcludedImages {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid excluded image %q: %w", pattern, err)
+		}
+		excludedImages = append(excludedImages, g)
+	}
+
+	return &dockerObserver{
+		logger:         logger,
+		config:         config,
+		client:         client,
+		excludedImages: excludedImages,
+		reconnectDelay: defaultReconnectDelay,
+		endpoints:      map[string][]observer.Endpoint{},
+		pending:        map[string]*time.Timer{},
+	}, nil
+}
+
+func (d *dockerObserver) Start(context.Context, component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go func() {
+		defer close(d.done)
+		d.watch(ctx)
+	}()
+	return nil
+}
+
+func (d *dockerObserver) Shutdown(context.Context) error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+	<-d.done
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for id, timer := range d.pending {
+		timer.Stop()
+		delete(d.pending, id)
+	}
+	return nil
+}
+
+// ListAndWatch notifies the listener with the current endpoints and their subsequent changes.
+func (d *dockerObserver) ListAndWatch(listener observer.Notify) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, listener)
+
+	var current []observer.Endpoint
+	for _, endpoints := range d.endpoints {
+		current = append(current, endpoints...)
+	}
+	if len(current) > 0 {
+		listener.OnAdd(current)
+	}
+}
+
+// watch lists the containers and follows their events until the context is cancelled. The daemon is reconnected to
+// when the listing or the stream of events fail, e.g. when it restarts, and the containers are listed again so that
+// the events missed in between are accounted for.
+func (d *dockerObserver) watch(ctx context.Context) {
+	for {
+		err := d.listAndFollowEvents(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		d.logger.Error("Error watching docker container events, reconnecting", zap.Error(err))
+
+		select {
+		case <-time.After(d.reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *dockerObserver) listAndFollowEvents(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The stream of events is opened before listing the containers, so that no event is missed in between.
+	filters := dfilters.NewArgs([]dfilters.KeyValuePair{
+		{Key: "type", Value: "container"},
+		{Key: "event", Value: "destroy"},
+		{Key: "event", Value: "die"},
+		{Key: "event", Value: "pause"},
+		{Key: "event", Value: "rename"},
+		{Key: "event", Value: "stop"},
+		{Key: "event", Value: "start"},
+		{Key: "event", Value: "unpause"},
+		{Key: "event", Value: "update"},
+	}...)
+	eventCh, errCh := d.client.Events(ctx, dtypes.EventsOptions{Filters: filters})
+
+	if err := d.syncContainers(ctx); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-eventCh:
+			d.logger.Debug("Docker container event",
+				zap.String("id", event.ID), zap.String("action", event.Action))
+			d.scheduleUpdate(ctx, event)
+		case err := <-errCh:
+			return err
+		}
+	}
+}
+
+// syncContainers replaces the endpoints with the ones of the running containers.
+func (d *dockerObserver) syncContainers(ctx context.Context) error {
+	listCtx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+	containers, err := d.client.ContainerList(listCtx, dtypes.ContainerListOptions{
+		Filters: dfilters.NewArgs(dfilters.Arg("status", "running")),
+	})
+	if err != nil {
+		return fmt.Errorf("could not list docker containers: %w", err)
+	}
+
+	running := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		running[container.ID] = true
+		if d.isExcluded(container.Image) {
+			continue
+		}
+		d.updateContainer(ctx, container.ID)
+	}
+
+	d.mu.Lock()
+	var removed []string
+	for id := range d.endpoints {
+		if !running[id] {
+			removed = append(removed, id)
+		}
+	}
+	d.mu.Unlock()
+	for _, id := range removed {
+		d.setEndpoints(id, nil)
+	}
+	return nil
+}
+
+// scheduleUpdate updates the endpoints of the container of the event once no other event was received for the
+// container during the debounce interval.
+func (d *dockerObserver) scheduleUpdate(ctx context.Context, event devents.Message) {
+	if event.Action == "destroy" {
+		d.mu.Lock()
+		if timer, ok := d.pending[event.ID]; ok {
+			timer.Stop()
+			delete(d.pending, event.ID)
+		}
+		d.mu.Unlock()
+		d.setEndpoints(event.ID, nil)
+		return
+	}
+
+	if d.config.DebounceInterval <= 0 {
+		d.updateContainer(ctx, event.ID)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if timer, ok := d.pending[event.ID]; ok {
+		timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(d.config.DebounceInterval, func() {
+		d.mu.Lock()
+		if d.pending[event.ID] != timer {
+			// A later event rescheduled the update.
+			d.mu.Unlock()
+			return
+		}
+		delete(d.pending, event.ID)
+		d.mu.Unlock()
+
+		if ctx.Err() == nil {
+			d.updateContainer(ctx, event.ID)
+		}
+	})
+	d.pending[event.ID] = timer
+}
+
+// updateContainer inspects the container and updates its endpoints, which are removed when the container isn't
+// running anymore.
+func (d *dockerObserver) updateContainer(ctx context.Context, containerID string) {
+	inspectCtx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+	container, err := d.client.ContainerInspect(inspectCtx, containerID)
+	if err != nil {
+		if docker.IsErrNotFound(err) {
+			d.setEndpoints(containerID, nil)
+			return
+		}
+		// The containers are listed again when the daemon is reconnected to.
+		d.logger.Error("Could not inspect docker container", zap.String("id", containerID), zap.Error(err))
+		return
+	}
+
+	if container.State == nil || !container.State.Running || container.State.Paused ||
+		container.Config == nil || d.isExcluded(container.Config.Image) {
+		d.setEndpoints(containerID, nil)
+		return
+	}
+	d.setEndpoints(containerID, containerEndpoints(d.config.Name(), &container, d.config.UseHostBindings))
+}
+
+// setEndpoints replaces the endpoints of the container, and notifies the listeners of the differences.
+func (d *dockerObserver) setEndpoints(containerID string, endpoints []observer.Endpoint) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	existing := make(map[observer.EndpointID]observer.Endpoint, len(d.endpoints[containerID]))
+	for _, e := range d.endpoints[containerID] {
+		existing[e.ID] = e
+	}
+
+	var added, changed, removed []observer.Endpoint
+	for _, e := range endpoints {
+		previous, ok := existing[e.ID]
+		switch {
+		case !ok:
+			added = append(added, e)
+		case !reflect.DeepEqual(previous, e):
+			changed = append(changed, e)
+		}
+		delete(existing, e.ID)
+	}
+	for _, e := range d.endpoints[containerID] {
+		if _, ok := existing[e.ID]; ok {
+			removed = append(removed, e)
+		}
+	}
+
+	if len(endpoints) > 0 {
+		d.endpoints[containerID] = endpoints
+	} else {
+		delete(d.endpoints, containerID)
+	}
+
+	for _, listener := range d.listeners {
+		if len(removed) > 0 {
+			listener.OnRemove(removed)
+		}
+		if len(added) > 0 {
+			listener.OnAdd(added)
+		}
+		if len(changed) > 0 {
+			listener.OnChange(changed)
+		}
+	}
+}
+
+func (d *dockerObserver) isExcluded(image string) bool {
+	for _, g := range d.excludedImages {
+		if g.Match(image) {
+			return true
+		}
+	}
+	return false
+}
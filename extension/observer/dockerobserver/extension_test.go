@@ -0,0 +1,327 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string  { return "no such container" }
+func (notFoundError) NotFound() bool { return true }
+
+// fakeClient serves the containers it holds, and the events sent to the stream of the last call to Events.
+type fakeClient struct {
+	sync.Mutex
+	containers map[string]*dtypes.ContainerJSON
+	inspected  int
+	eventCh    chan devents.Message
+	errCh      chan error
+	streams    int
+}
+
+func newFakeClient(containers ...*dtypes.ContainerJSON) *fakeClient {
+	c := &fakeClient{containers: map[string]*dtypes.ContainerJSON{}}
+	for _, container := range containers {
+		c.containers[container.ID] = container
+	}
+	return c
+}
+
+func (c *fakeClient) ContainerList(context.Context, dtypes.ContainerListOptions) ([]dtypes.Container, error) {
+	c.Lock()
+	defer c.Unlock()
+	var containers []dtypes.Container
+	for id, container := range c.containers {
+		if container.State.Running {
+			containers = append(containers, dtypes.Container{ID: id, Image: container.Config.Image})
+		}
+	}
+	return containers, nil
+}
+
+func (c *fakeClient) ContainerInspect(_ context.Context, containerID string) (dtypes.ContainerJSON, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.inspected++
+	container, ok := c.containers[containerID]
+	if !ok {
+		return dtypes.ContainerJSON{}, notFoundError{}
+	}
+	return *container, nil
+}
+
+func (c *fakeClient) Events(context.Context, dtypes.EventsOptions) (<-chan devents.Message, <-chan error) {
+	c.Lock()
+	defer c.Unlock()
+	c.eventCh = make(chan devents.Message)
+	c.errCh = make(chan error, 1)
+	c.streams++
+	return c.eventCh, c.errCh
+}
+
+func (c *fakeClient) setContainer(container *dtypes.ContainerJSON) {
+	c.Lock()
+	defer c.Unlock()
+	c.containers[container.ID] = container
+}
+
+func (c *fakeClient) removeContainer(id string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.containers, id)
+}
+
+func (c *fakeClient) send(t *testing.T, id, action string) {
+	c.Lock()
+	eventCh := c.eventCh
+	c.Unlock()
+	select {
+	case eventCh <- devents.Message{ID: id, Action: action, Type: "container"}:
+	case <-time.After(time.Second):
+		t.Fatalf("event %s of container %s wasn't received", action, id)
+	}
+}
+
+func (c *fakeClient) fail(err error) {
+	c.Lock()
+	defer c.Unlock()
+	c.errCh <- err
+}
+
+func (c *fakeClient) streamCount() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.streams
+}
+
+type endpointSink struct {
+	sync.Mutex
+	endpoints map[observer.EndpointID]observer.Endpoint
+	added     int
+	removed   int
+	changed   int
+}
+
+func newEndpointSink() *endpointSink {
+	return &endpointSink{endpoints: map[observer.EndpointID]observer.Endpoint{}}
+}
+
+func (e *endpointSink) OnAdd(added []observer.Endpoint) {
+	e.Lock()
+	defer e.Unlock()
+	for _, endpoint := range added {
+		e.endpoints[endpoint.ID] = endpoint
+	}
+	e.added += len(added)
+}
+
+func (e *endpointSink) OnRemove(removed []observer.Endpoint) {
+	e.Lock()
+	defer e.Unlock()
+	for _, endpoint := range removed {
+		delete(e.endpoints, endpoint.ID)
+	}
+	e.removed += len(removed)
+}
+
+func (e *endpointSink) OnChange(changed []observer.Endpoint) {
+	e.Lock()
+	defer e.Unlock()
+	for _, endpoint := range changed {
+		e.endpoints[endpoint.ID] = endpoint
+	}
+	e.changed += len(changed)
+}
+
+func (e *endpointSink) targets() []string {
+	e.Lock()
+	defer e.Unlock()
+	var targets []string
+	for _, endpoint := range e.endpoints {
+		targets = append(targets, endpoint.Target)
+	}
+	return targets
+}
+
+var _ observer.Notify = (*endpointSink)(nil)
+
+func startObserver(t *testing.T, client *fakeClient, modify func(cfg *Config)) (*dockerObserver, *endpointSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DebounceInterval = 0
+	if modify != nil {
+		modify(cfg)
+	}
+	ext, err := newObserver(zap.NewNop(), cfg, client)
+	require.NoError(t, err)
+	d := ext.(*dockerObserver)
+	d.reconnectDelay = 10 * time.Millisecond
+
+	require.NoError(t, d.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, d.Shutdown(context.Background()))
+	})
+	// The stream of events is opened once the containers are listed.
+	require.Eventually(t, func() bool { return client.streamCount() > 0 }, time.Second, time.Millisecond)
+
+	sink := newEndpointSink()
+	d.ListAndWatch(sink)
+	return d, sink
+}
+
+func assertTargets(t *testing.T, sink *endpointSink, targets ...string) {
+	sort.Strings(targets)
+	assert.Eventually(t, func() bool {
+		current := sink.targets()
+		sort.Strings(current)
+		return strings.Join(current, ",") == strings.Join(targets, ",")
+	}, time.Second, time.Millisecond, "expected targets %v, got %v", targets, sink.targets())
+}
+
+func TestObserverListsRunningContainers(t *testing.T) {
+	stopped := newContainer("stopped", "nginx", "172.17.0.3", nat.PortMap{"80/tcp": nil})
+	stopped.State.Running = false
+	client := newFakeClient(
+		newContainer("redis", "redis:6", "172.17.0.2", nat.PortMap{"6379/tcp": nil}),
+		stopped,
+	)
+
+	_, sink := startObserver(t, client, nil)
+	assertTargets(t, sink, "172.17.0.2:6379")
+}
+
+func TestObserverFollowsContainerEvents(t *testing.T) {
+	client := newFakeClient()
+	_, sink := startObserver(t, client, nil)
+	assertTargets(t, sink)
+
+	client.setContainer(newContainer("redis", "redis:6", "172.17.0.2", nat.PortMap{"6379/tcp": nil}))
+	client.send(t, "redis", "start")
+	assertTargets(t, sink, "172.17.0.2:6379")
+
+	client.setContainer(newContainer("redis", "redis:6", "172.17.0.2",
+		nat.PortMap{"6379/tcp": {{HostIP: "0.0.0.0", HostPort: "6379"}}}))
+	client.send(t, "redis", "update")
+	assert.Eventually(t, func() bool {
+		sink.Lock()
+		defer sink.Unlock()
+		return sink.changed == 1
+	}, time.Second, time.Millisecond)
+
+	paused := newContainer("redis", "redis:6", "172.17.0.2", nat.PortMap{"6379/tcp": nil})
+	paused.State.Paused = true
+	client.setContainer(paused)
+	client.send(t, "redis", "pause")
+	assertTargets(t, sink)
+
+	client.setContainer(newContainer("redis", "redis:6", "172.17.0.2", nat.PortMap{"6379/tcp": nil}))
+	client.send(t, "redis", "unpause")
+	assertTargets(t, sink, "172.17.0.2:6379")
+
+	client.removeContainer("redis")
+	client.send(t, "redis", "destroy")
+	assertTargets(t, sink)
+}
+
+func TestObserverReconnects(t *testing.T) {
+	client := newFakeClient(
+		newContainer("redis", "redis:6", "172.17.0.2", nat.PortMap{"6379/tcp": nil}),
+		newContainer("nginx", "nginx", "172.17.0.3", nat.PortMap{"80/tcp": nil}),
+	)
+	_, sink := startObserver(t, client, nil)
+	assertTargets(t, sink, "172.17.0.2:6379", "172.17.0.3:80")
+
+	// The events missed while disconnected are accounted for by listing the containers again.
+	client.removeContainer("redis")
+	client.setContainer(newContainer("mysql", "mysql", "172.17.0.4", nat.PortMap{"3306/tcp": nil}))
+	client.fail(errors.New("daemon restarted"))
+
+	require.Eventually(t, func() bool { return client.streamCount() == 2 }, time.Second, time.Millisecond)
+	assertTargets(t, sink, "172.17.0.3:80", "172.17.0.4:3306")
+
+	client.removeContainer("nginx")
+	client.send(t, "nginx", "die")
+	assertTargets(t, sink, "172.17.0.4:3306")
+}
+
+func TestObserverDebouncesEvents(t *testing.T) {
+	client := newFakeClient()
+	_, sink := startObserver(t, client, func(cfg *Config) {
+		cfg.DebounceInterval = 100 * time.Millisecond
+	})
+
+	// A container in a restart loop isn't added and removed on each restart.
+	for i := 0; i < 5; i++ {
+		client.setContainer(newContainer("crashing", "app", "172.17.0.2", nat.PortMap{"8080/tcp": nil}))
+		client.send(t, "crashing", "start")
+		client.removeContainer("crashing")
+		client.send(t, "crashing", "die")
+	}
+	client.setContainer(newContainer("crashing", "app", "172.17.0.2", nat.PortMap{"8080/tcp": nil}))
+	client.send(t, "crashing", "start")
+
+	assertTargets(t, sink, "172.17.0.2:8080")
+	sink.Lock()
+	defer sink.Unlock()
+	assert.Equal(t, 1, sink.added)
+	assert.Equal(t, 0, sink.removed)
+}
+
+func TestObserverExcludesImages(t *testing.T) {
+	client := newFakeClient(
+		newContainer("redis", "redis:6", "172.17.0.2", nat.PortMap{"6379/tcp": nil}),
+		newContainer("pause", "k8s.gcr.io/pause:3.2", "172.17.0.3", nat.PortMap{"80/tcp": nil}),
+	)
+	_, sink := startObserver(t, client, func(cfg *Config) {
+		cfg.ExcludedImages = []string{"*/pause:*"}
+	})
+	assertTargets(t, sink, "172.17.0.2:6379")
+
+	client.setContainer(newContainer("other-pause", "k8s.gcr.io/pause:3.2", "172.17.0.4", nat.PortMap{"80/tcp": nil}))
+	client.send(t, "other-pause", "start")
+	client.setContainer(newContainer("nginx", "nginx", "172.17.0.5", nat.PortMap{"80/tcp": nil}))
+	client.send(t, "nginx", "start")
+	assertTargets(t, sink, "172.17.0.2:6379", "172.17.0.5:80")
+}
+
+func TestObserverUsesHostBindings(t *testing.T) {
+	client := newFakeClient(
+		newContainer("redis", "redis:6", "172.17.0.2", nat.PortMap{
+			"6379/tcp": {{HostIP: "0.0.0.0", HostPort: "16379"}},
+			"9121/tcp": nil,
+		}),
+	)
+	_, sink := startObserver(t, client, func(cfg *Config) {
+		cfg.UseHostBindings = true
+	})
+	assertTargets(t, sink, "127.0.0.1:16379")
+}
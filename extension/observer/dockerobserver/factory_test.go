@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/zap"
+)
+
+func TestFactory_Type(t *testing.T) {
+	factory := NewFactory()
+	require.Equal(t, typeStr, factory.Type())
+}
+
+func TestFactory_CreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{
+		ExtensionSettings: configmodels.ExtensionSettings{
+			TypeVal: typeStr,
+			NameVal: string(typeStr),
+		},
+		Endpoint:         "unix:///var/run/docker.sock",
+		Timeout:          5 * time.Second,
+		DebounceInterval: time.Second,
+	},
+		cfg)
+
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestFactory_CreateExtension(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	ext, err := factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+}
+
+func TestFactory_CreateExtensionInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name: "invalid excluded image",
+			modify: func(cfg *Config) {
+				cfg.ExcludedImages = []string{"redis:[6"}
+			},
+			err: `invalid excluded image "redis:[6": unexpected end of input`,
+		},
+		{
+			name: "invalid tls",
+			modify: func(cfg *Config) {
+				cfg.TLSSetting = &configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{CAFile: "/does/not/exist.pem"},
+				}
+			},
+			err: "could not load docker client TLS config: " +
+				"failed to load TLS config: failed to load CA CertPool: failed to load CA /does/not/exist.pem: " +
+				"open /does/not/exist.pem: no such file or directory",
+		},
+		{
+			name: "invalid endpoint",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "not a host"
+			},
+			err: "could not create docker client: unable to parse docker host `not a host`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			tt.modify(cfg)
+
+			_, err := factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the ECS observer.
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// ClusterName is the name of the ECS cluster whose tasks are discovered.
+	ClusterName string `mapstructure:"cluster_name"`
+	// ClusterRegion is the region of the cluster. The region is read from the environment when not set.
+	ClusterRegion string `mapstructure:"cluster_region"`
+	// RoleARN is the ARN of a role to assume, the credentials of the default chain being used when not set.
+	RoleARN string `mapstructure:"role_arn"`
+	// RefreshInterval is the interval at which the ECS APIs are polled.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	// ResultFile is the path of the file the targets are written to, in the format of the Prometheus file_sd_configs.
+	ResultFile string `mapstructure:"result_file"`
+
+	// Services matches the tasks by the name of their service.
+	Services []ServiceConfig `mapstructure:"services"`
+	// TaskDefinitions matches the tasks by the ARN of their task definition.
+	TaskDefinitions []TaskDefinitionConfig `mapstructure:"task_definitions"`
+	// DockerLabels matches the containers by their docker labels.
+	DockerLabels []DockerLabelConfig `mapstructure:"docker_labels"`
+}
+
+// TargetSettings defines the targets exported by the containers matched.
+type TargetSettings struct {
+	// JobName is the value of the job label of the targets, the job of the scrape config being used when not set.
+	JobName string `mapstructure:"job_name"`
+	// MetricsPath is the path of the metrics, /metrics when not set.
+	MetricsPath string `mapstructure:"metrics_path"`
+	// MetricsPorts are the container ports exporting metrics.
+	MetricsPorts []int `mapstructure:"metrics_ports"`
+}
+
+// ServiceConfig matches the containers of the tasks of the services whose name matches NamePattern.
+type ServiceConfig struct {
+	TargetSettings `mapstructure:",squash"`
+
+	// NamePattern is a regular expression matched against the name of the service of the tasks.
+	NamePattern string `mapstructure:"name_pattern"`
+	// ContainerNamePattern restricts the containers of the tasks matched to the ones whose name matches this
+	// regular expression. All the containers having one of the MetricsPorts are matched when not set.
+	ContainerNamePattern string `mapstructure:"container_name_pattern"`
+}
+
+// TaskDefinitionConfig matches the containers of the tasks whose task definition ARN matches ArnPattern.
+type TaskDefinitionConfig struct {
+	TargetSettings `mapstructure:",squash"`
+
+	// ArnPattern is a regular expression matched against the ARN of the task definition of the tasks.
+	ArnPattern string `mapstructure:"arn_pattern"`
+	// ContainerNamePattern restricts the containers of the tasks matched to the ones whose name matches this
+	// regular expression. All the containers having one of the MetricsPorts are matched when not set.
+	ContainerNamePattern string `mapstructure:"container_name_pattern"`
+}
+
+// DockerLabelConfig matches the containers having the PortLabel docker label.
+type DockerLabelConfig struct {
+	// PortLabel is the docker label whose value is the container port exporting metrics.
+	PortLabel string `mapstructure:"port_label"`
+	// JobNameLabel is the docker label whose value overrides the job label of the targets.
+	JobNameLabel string `mapstructure:"job_name_label"`
+	// MetricsPathLabel is the docker label whose value overrides the path of the metrics, /metrics by default.
+	MetricsPathLabel string `mapstructure:"metrics_path_label"`
+}
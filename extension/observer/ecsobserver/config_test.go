@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	require.Len(t, cfg.Extensions, 2)
+
+	ext0 := cfg.Extensions["ecs_observer"]
+	assert.Equal(t, factory.CreateDefaultConfig(), ext0)
+
+	ext1 := cfg.Extensions["ecs_observer/all_settings"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "ecs_observer",
+				NameVal: "ecs_observer/all_settings",
+			},
+			ClusterName:     "ecs-cluster-1",
+			ClusterRegion:   "us-west-2",
+			RoleARN:         "arn:aws:iam::123456789012:role/ecs-observer",
+			RefreshInterval: 15 * time.Second,
+			ResultFile:      "/etc/ecs_sd_targets.json",
+			Services: []ServiceConfig{{
+				TargetSettings: TargetSettings{
+					JobName:      "retail",
+					MetricsPorts: []int{8080},
+				},
+				NamePattern:          "^retail-.*$",
+				ContainerNamePattern: "^java-api$",
+			}},
+			TaskDefinitions: []TaskDefinitionConfig{{
+				TargetSettings: TargetSettings{
+					MetricsPath:  "/stats/metrics",
+					MetricsPorts: []int{9113},
+				},
+				ArnPattern: ".*:task-definition/nginx:[0-9]+",
+			}},
+			DockerLabels: []DockerLabelConfig{{
+				PortLabel:        "ECS_PROMETHEUS_EXPORTER_PORT",
+				JobNameLabel:     "ECS_PROMETHEUS_JOB_NAME",
+				MetricsPathLabel: "ECS_PROMETHEUS_METRICS_PATH",
+			}},
+		},
+		ext1)
+}
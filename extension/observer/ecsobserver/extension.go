@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+// maxThrottleBackoff is the maximum factor the refresh interval is multiplied by while the APIs keep being throttled.
+const maxThrottleBackoff = 8
+
+type ecsObserver struct {
+	logger     *zap.Logger
+	config     *Config
+	fetcher    *taskFetcher
+	matchers   []matcher
+	lastResult []byte
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ component.ServiceExtension = (*ecsObserver)(nil)
+
+func newObserver(logger *zap.Logger, config *Config) (*ecsObserver, error) {
+	if config.ClusterName == "" {
+		return nil, errors.New("cluster_name must be specified")
+	}
+	if config.ResultFile == "" {
+		return nil, errors.New("result_file must be specified")
+	}
+	if config.RefreshInterval <= 0 {
+		return nil, fmt.Errorf("refresh_interval must be positive: %v", config.RefreshInterval)
+	}
+	matchers, err := newMatchers(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(matchers) == 0 {
+		return nil, errors.New("at least one of services, task_definitions or docker_labels must be configured")
+	}
+
+	awsConfig := aws.NewConfig()
+	if config.ClusterRegion != "" {
+		awsConfig.WithRegion(config.ClusterRegion)
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if config.RoleARN != "" {
+		awsConfig = awsConfig.Copy().WithCredentials(stscreds.NewCredentials(sess, config.RoleARN))
+	}
+
+	return &ecsObserver{
+		logger:   logger,
+		config:   config,
+		fetcher:  newTaskFetcher(config.ClusterName, ecs.New(sess, awsConfig), ec2.New(sess, awsConfig), logger),
+		matchers: matchers,
+	}, nil
+}
+
+func (o *ecsObserver) Start(context.Context, component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	o.cancel = cancel
+	o.done = make(chan struct{})
+	go o.run(ctx)
+	return nil
+}
+
+func (o *ecsObserver) Shutdown(context.Context) error {
+	if o.cancel != nil {
+		o.cancel()
+		<-o.done
+	}
+	return nil
+}
+
+// run refreshes the targets until the context is canceled, the first time immediately so that the tasks are scraped
+// ASAP.
+func (o *ecsObserver) run(ctx context.Context) {
+	defer close(o.done)
+
+	delay := o.config.RefreshInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		err := o.refresh(ctx)
+		if err != nil && ctx.Err() == nil {
+			o.logger.Error("Failed to refresh the ECS targets, keeping the previous ones", zap.Error(err))
+		}
+		delay = nextDelay(delay, o.config.RefreshInterval, err)
+		timer.Reset(delay)
+	}
+}
+
+// refresh writes the targets of the running tasks to the result file, when they changed.
+func (o *ecsObserver) refresh(ctx context.Context) error {
+	tasks, err := o.fetcher.fetchTasks(ctx)
+	if err != nil {
+		return err
+	}
+	content, err := marshalTargets(buildTargets(o.config.ClusterName, tasks, o.matchers))
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, o.lastResult) {
+		return nil
+	}
+	if err := writeFile(o.config.ResultFile, content); err != nil {
+		return err
+	}
+	o.lastResult = content
+	return nil
+}
+
+// nextDelay returns the delay until the next refresh: the refresh interval, doubled after each refresh failing
+// because of the throttling of the APIs, up to maxThrottleBackoff times the interval.
+func nextDelay(delay, interval time.Duration, err error) time.Duration {
+	var awsErr awserr.Error
+	if err == nil || !errors.As(err, &awsErr) || !request.IsErrorThrottle(awsErr) {
+		return interval
+	}
+	delay *= 2
+	if delay > maxThrottleBackoff*interval {
+		delay = maxThrottleBackoff * interval
+	}
+	return delay
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+)
+
+func newTestObserver(t *testing.T, resultFile string) (*ecsObserver, *ecsMock) {
+	cfg := validConfig()
+	cfg.ResultFile = resultFile
+	cfg.RefreshInterval = 10 * time.Millisecond
+	o, err := newObserver(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	ecsClient, ec2Client := newClusterMocks()
+	o.fetcher = newTaskFetcher(cfg.ClusterName, ecsClient, ec2Client, zap.NewNop())
+	return o, ecsClient
+}
+
+func TestObserverWritesTargets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecsobserver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "targets.json")
+
+	o, _ := newTestObserver(t, path)
+	require.NoError(t, o.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		content, err := ioutil.ReadFile(path)
+		return err == nil && len(content) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, o.Shutdown(context.Background()))
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	expected, err := marshalTargets(buildTargets("ecs-cluster-1", fetchClusterTasks(t), o.matchers))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(content))
+}
+
+func TestObserverRefresh(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecsobserver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "targets.json")
+
+	o, ecsClient := newTestObserver(t, path)
+	require.NoError(t, o.refresh(context.Background()))
+	first, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(first), "10.0.1.1:32768")
+
+	// The file isn't rewritten while the targets don't change.
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, o.refresh(context.Background()))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// The targets of the stopped tasks are removed.
+	delete(ecsClient.tasks, nginxTaskArn)
+	require.NoError(t, o.refresh(context.Background()))
+	second, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(second), "10.0.1.1:32768")
+
+	// The previous targets are kept when the APIs fail.
+	ecsClient.err = errors.New("access denied")
+	assert.Error(t, o.refresh(context.Background()))
+	third, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, second, third)
+}
+
+func TestNextDelay(t *testing.T) {
+	interval := 10 * time.Second
+	throttled := fmt.Errorf("failed to list the tasks: %w", awserr.New("ThrottlingException", "Rate exceeded", nil))
+
+	assert.Equal(t, interval, nextDelay(interval, interval, nil))
+	assert.Equal(t, interval, nextDelay(4*interval, interval, errors.New("access denied")))
+	assert.Equal(t, 2*interval, nextDelay(interval, interval, throttled))
+	assert.Equal(t, 4*interval, nextDelay(2*interval, interval, throttled))
+	assert.Equal(t, 8*interval, nextDelay(8*interval, interval, throttled))
+	assert.Equal(t, interval, nextDelay(8*interval, interval, nil))
+}
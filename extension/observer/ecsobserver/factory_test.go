@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.uber.org/zap"
+)
+
+func TestValidConfig(t *testing.T) {
+	err := configcheck.ValidateConfig(createDefaultConfig())
+	require.NoError(t, err)
+}
+
+func validConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClusterName = "ecs-cluster-1"
+	cfg.ClusterRegion = "us-west-2"
+	cfg.ResultFile = "/etc/ecs_sd_targets.json"
+	cfg.DockerLabels = []DockerLabelConfig{{PortLabel: "ECS_PROMETHEUS_EXPORTER_PORT"}}
+	return cfg
+}
+
+func TestCreateExtension(t *testing.T) {
+	params := component.ExtensionCreateParams{Logger: zap.NewNop()}
+	ecsObserver, err := createExtension(context.Background(), params, validConfig())
+	require.NoError(t, err)
+	require.NotNil(t, ecsObserver)
+}
+
+func TestCreateExtensionInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "missing cluster name",
+			modify: func(cfg *Config) { cfg.ClusterName = "" },
+			err:    "cluster_name must be specified",
+		},
+		{
+			name:   "missing result file",
+			modify: func(cfg *Config) { cfg.ResultFile = "" },
+			err:    "result_file must be specified",
+		},
+		{
+			name:   "zero refresh interval",
+			modify: func(cfg *Config) { cfg.RefreshInterval = 0 },
+			err:    "refresh_interval must be positive: 0s",
+		},
+		{
+			name:   "no matchers",
+			modify: func(cfg *Config) { cfg.DockerLabels = nil },
+			err:    "at least one of services, task_definitions or docker_labels must be configured",
+		},
+		{
+			name: "missing service name pattern",
+			modify: func(cfg *Config) {
+				cfg.Services = []ServiceConfig{{TargetSettings: TargetSettings{MetricsPorts: []int{8080}}}}
+			},
+			err: "services[0].name_pattern must be specified",
+		},
+		{
+			name: "invalid service name pattern",
+			modify: func(cfg *Config) {
+				cfg.Services = []ServiceConfig{{TargetSettings: TargetSettings{MetricsPorts: []int{8080}}, NamePattern: "("}}
+			},
+			err: "invalid services[0].name_pattern \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "invalid container name pattern",
+			modify: func(cfg *Config) {
+				cfg.TaskDefinitions = []TaskDefinitionConfig{{
+					TargetSettings:       TargetSettings{MetricsPorts: []int{8080}},
+					ArnPattern:           "nginx",
+					ContainerNamePattern: "[",
+				}}
+			},
+			err: "invalid task_definitions[0].container_name_pattern \"[\": error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name: "missing metrics ports",
+			modify: func(cfg *Config) {
+				cfg.TaskDefinitions = []TaskDefinitionConfig{{ArnPattern: "nginx"}}
+			},
+			err: "task_definitions[0].metrics_ports must be specified",
+		},
+		{
+			name: "invalid metrics port",
+			modify: func(cfg *Config) {
+				cfg.Services = []ServiceConfig{{TargetSettings: TargetSettings{MetricsPorts: []int{70000}}, NamePattern: "api"}}
+			},
+			err: "invalid services[0].metrics_ports 70000, must be between 1 and 65535",
+		},
+		{
+			name:   "missing port label",
+			modify: func(cfg *Config) { cfg.DockerLabels = []DockerLabelConfig{{JobNameLabel: "job"}} },
+			err:    "docker_labels[0].port_label must be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			params := component.ExtensionCreateParams{Logger: zap.NewNop()}
+			_, err := createExtension(context.Background(), params, cfg)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"go.uber.org/zap"
+)
+
+// describeBatchSize is the maximum number of resources described by DescribeTasks and DescribeContainerInstances.
+const describeBatchSize = 100
+
+// ecsAPI is the subset of the ECS API used by the observer.
+type ecsAPI interface {
+	ListTasksPagesWithContext(ctx aws.Context, input *ecs.ListTasksInput, fn func(*ecs.ListTasksOutput, bool) bool, opts ...request.Option) error
+	DescribeTasksWithContext(ctx aws.Context, input *ecs.DescribeTasksInput, opts ...request.Option) (*ecs.DescribeTasksOutput, error)
+	DescribeTaskDefinitionWithContext(ctx aws.Context, input *ecs.DescribeTaskDefinitionInput, opts ...request.Option) (*ecs.DescribeTaskDefinitionOutput, error)
+	DescribeContainerInstancesWithContext(ctx aws.Context, input *ecs.DescribeContainerInstancesInput, opts ...request.Option) (*ecs.DescribeContainerInstancesOutput, error)
+}
+
+// ec2API is the subset of the EC2 API used by the observer.
+type ec2API interface {
+	DescribeInstancesPagesWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, opts ...request.Option) error
+}
+
+// task is a running task, with its task definition and, for the tasks running on EC2, its instance.
+type task struct {
+	task       *ecs.Task
+	definition *ecs.TaskDefinition
+	instance   *ec2.Instance
+}
+
+// serviceName returns the name of the service of the task, false when the task wasn't started by a service.
+func (t *task) serviceName() (string, bool) {
+	group := aws.StringValue(t.task.Group)
+	if !strings.HasPrefix(group, "service:") {
+		return "", false
+	}
+	return strings.TrimPrefix(group, "service:"), true
+}
+
+// taskFetcher lists the running tasks of a cluster.
+type taskFetcher struct {
+	cluster string
+	ecs     ecsAPI
+	ec2     ec2API
+	logger  *zap.Logger
+
+	// definitions caches the task definitions by ARN, the task definitions being immutable.
+	definitions map[string]*ecs.TaskDefinition
+	// instances caches the EC2 instances by container instance ARN.
+	instances map[string]*ec2.Instance
+}
+
+func newTaskFetcher(cluster string, ecsClient ecsAPI, ec2Client ec2API, logger *zap.Logger) *taskFetcher {
+	return &taskFetcher{
+		cluster:     cluster,
+		ecs:         ecsClient,
+		ec2:         ec2Client,
+		logger:      logger,
+		definitions: map[string]*ecs.TaskDefinition{},
+		instances:   map[string]*ec2.Instance{},
+	}
+}
+
+// fetchTasks returns the tasks of the cluster which are running. The tasks stopped while they were listed are
+// skipped, and the caches are pruned of the task definitions and the instances no longer used.
+func (f *taskFetcher) fetchTasks(ctx context.Context) ([]*task, error) {
+	arns, err := f.listTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ecsTasks, err := f.describeTasks(ctx, arns)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := map[string]*ecs.TaskDefinition{}
+	tasks := make([]*task, 0, len(ecsTasks))
+	for _, ecsTask := range ecsTasks {
+		if aws.StringValue(ecsTask.LastStatus) != ecs.DesiredStatusRunning {
+			continue
+		}
+		arn := aws.StringValue(ecsTask.TaskDefinitionArn)
+		definition, ok := definitions[arn]
+		if !ok {
+			if definition, err = f.taskDefinition(ctx, arn); err != nil {
+				return nil, err
+			}
+			definitions[arn] = definition
+		}
+		tasks = append(tasks, &task{task: ecsTask, definition: definition})
+	}
+	f.definitions = definitions
+
+	if err := f.attachInstances(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (f *taskFetcher) listTasks(ctx context.Context) ([]*string, error) {
+	input := &ecs.ListTasksInput{
+		Cluster:       aws.String(f.cluster),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	}
+	var arns []*string
+	err := f.ecs.ListTasksPagesWithContext(ctx, input, func(output *ecs.ListTasksOutput, _ bool) bool {
+		arns = append(arns, output.TaskArns...)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the tasks of cluster %q: %w", f.cluster, err)
+	}
+	return arns, nil
+}
+
+func (f *taskFetcher) describeTasks(ctx context.Context, arns []*string) ([]*ecs.Task, error) {
+	var tasks []*ecs.Task
+	for start := 0; start < len(arns); start += describeBatchSize {
+		end := min(start+describeBatchSize, len(arns))
+		output, err := f.ecs.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
+			Cluster: aws.String(f.cluster),
+			Tasks:   arns[start:end],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe the tasks of cluster %q: %w", f.cluster, err)
+		}
+		for _, failure := range output.Failures {
+			// The tasks stopped since they were listed are reported as missing.
+			f.logger.Debug("Skipping task which couldn't be described",
+				zap.String("task", aws.StringValue(failure.Arn)), zap.String("reason", aws.StringValue(failure.Reason)))
+		}
+		tasks = append(tasks, output.Tasks...)
+	}
+	return tasks, nil
+}
+
+func (f *taskFetcher) taskDefinition(ctx context.Context, arn string) (*ecs.TaskDefinition, error) {
+	if definition, ok := f.definitions[arn]; ok {
+		return definition, nil
+	}
+	output, err := f.ecs.DescribeTaskDefinitionWithContext(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(arn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe task definition %q: %w", arn, err)
+	}
+	return output.TaskDefinition, nil
+}
+
+// attachInstances sets the EC2 instances of the tasks running on container instances. The tasks whose instance can't
+// be found, e.g. because it was deregistered since the tasks were listed, are left without an instance.
+func (f *taskFetcher) attachInstances(ctx context.Context, tasks []*task) error {
+	instances := map[string]*ec2.Instance{}
+	var missing []*string
+	for _, t := range tasks {
+		arn := aws.StringValue(t.task.ContainerInstanceArn)
+		if arn == "" {
+			continue
+		}
+		if _, ok := instances[arn]; ok {
+			continue
+		}
+		instance, ok := f.instances[arn]
+		if !ok {
+			missing = append(missing, t.task.ContainerInstanceArn)
+		}
+		instances[arn] = instance
+	}
+
+	resolved, err := f.describeInstances(ctx, missing)
+	if err != nil {
+		return err
+	}
+	for arn, instance := range resolved {
+		instances[arn] = instance
+	}
+
+	f.instances = map[string]*ec2.Instance{}
+	for arn, instance := range instances {
+		if instance != nil {
+			f.instances[arn] = instance
+		}
+	}
+	for _, t := range tasks {
+		t.instance = f.instances[aws.StringValue(t.task.ContainerInstanceArn)]
+	}
+	return nil
+}
+
+// describeInstances returns the EC2 instances by container instance ARN.
+func (f *taskFetcher) describeInstances(ctx context.Context, arns []*string) (map[string]*ec2.Instance, error) {
+	instanceIDs := map[string]string{}
+	var ids []*string
+	for start := 0; start < len(arns); start += describeBatchSize {
+		end := min(start+describeBatchSize, len(arns))
+		output, err := f.ecs.DescribeContainerInstancesWithContext(ctx, &ecs.DescribeContainerInstancesInput{
+			Cluster:            aws.String(f.cluster),
+			ContainerInstances: arns[start:end],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe the container instances of cluster %q: %w", f.cluster, err)
+		}
+		for _, failure := range output.Failures {
+			f.logger.Debug("Skipping container instance which couldn't be described",
+				zap.String("container_instance", aws.StringValue(failure.Arn)), zap.String("reason", aws.StringValue(failure.Reason)))
+		}
+		for _, containerInstance := range output.ContainerInstances {
+			instanceIDs[aws.StringValue(containerInstance.Ec2InstanceId)] = aws.StringValue(containerInstance.ContainerInstanceArn)
+			ids = append(ids, containerInstance.Ec2InstanceId)
+		}
+	}
+
+	instances := map[string]*ec2.Instance{}
+	for start := 0; start < len(ids); start += describeBatchSize {
+		end := min(start+describeBatchSize, len(ids))
+		err := f.ec2.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{
+			InstanceIds: ids[start:end],
+		}, func(output *ec2.DescribeInstancesOutput, _ bool) bool {
+			for _, reservation := range output.Reservations {
+				for _, instance := range reservation.Instances {
+					if arn, ok := instanceIDs[aws.StringValue(instance.InstanceId)]; ok {
+						instances[arn] = instance
+					}
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe the EC2 instances of cluster %q: %w", f.cluster, err)
+		}
+	}
+	return instances, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFetchTasks(t *testing.T) {
+	ecsClient, ec2Client := newClusterMocks()
+	fetcher := newTaskFetcher("ecs-cluster-1", ecsClient, ec2Client, zap.NewNop())
+
+	tasks, err := fetcher.fetchTasks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, retailTaskArn, aws.StringValue(tasks[0].task.TaskArn))
+	assert.Equal(t, "retail", aws.StringValue(tasks[0].definition.Family))
+	assert.Nil(t, tasks[0].instance)
+	assert.Equal(t, nginxTaskArn, aws.StringValue(tasks[1].task.TaskArn))
+	assert.Equal(t, "nginx", aws.StringValue(tasks[1].definition.Family))
+	assert.Equal(t, "10.0.1.1", aws.StringValue(tasks[1].instance.PrivateIpAddress))
+	assert.Equal(t, 2, ecsClient.listCalls)
+
+	// The task definitions and the instances are cached.
+	tasks, err = fetcher.fetchTasks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "10.0.1.1", aws.StringValue(tasks[1].instance.PrivateIpAddress))
+	assert.Equal(t, 2, ecsClient.describeDefinitionCalls)
+	assert.Equal(t, 1, ecsClient.describeInstancesCalls)
+	assert.Equal(t, 1, ec2Client.calls)
+
+	// The caches are pruned once the tasks stopped.
+	delete(ecsClient.tasks, nginxTaskArn)
+	tasks, err = fetcher.fetchTasks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Len(t, fetcher.definitions, 1)
+	assert.Empty(t, fetcher.instances)
+}
+
+func TestFetchTasksInBatches(t *testing.T) {
+	ecsClient := &ecsMock{
+		pageSize:    100,
+		tasks:       map[string]*ecs.Task{},
+		definitions: map[string]*ecs.TaskDefinition{"definition": {}},
+	}
+	for i := 0; i < 250; i++ {
+		arn := fmt.Sprintf("task-%d", i)
+		ecsClient.taskArns = append(ecsClient.taskArns, arn)
+		ecsClient.tasks[arn] = &ecs.Task{
+			TaskArn:           aws.String(arn),
+			TaskDefinitionArn: aws.String("definition"),
+			LastStatus:        aws.String(ecs.DesiredStatusRunning),
+		}
+	}
+	fetcher := newTaskFetcher("ecs-cluster-1", ecsClient, &ec2Mock{}, zap.NewNop())
+
+	tasks, err := fetcher.fetchTasks(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tasks, 250)
+	assert.Equal(t, 3, ecsClient.listCalls)
+	assert.Equal(t, 3, ecsClient.describeTasksCalls)
+	assert.Equal(t, 1, ecsClient.describeDefinitionCalls)
+}
+
+func TestFetchTasksDeregisteredInstance(t *testing.T) {
+	ecsClient, ec2Client := newClusterMocks()
+	delete(ecsClient.containerInstances, containerInstArn)
+	fetcher := newTaskFetcher("ecs-cluster-1", ecsClient, ec2Client, zap.NewNop())
+
+	tasks, err := fetcher.fetchTasks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Nil(t, tasks[1].instance)
+	assert.Empty(t, fetcher.instances)
+}
+
+func TestFetchTasksError(t *testing.T) {
+	ecsClient, ec2Client := newClusterMocks()
+	ecsClient.err = errors.New("access denied")
+	fetcher := newTaskFetcher("ecs-cluster-1", ecsClient, ec2Client, zap.NewNop())
+
+	_, err := fetcher.fetchTasks(context.Background())
+	assert.EqualError(t, err, `failed to list the tasks of cluster "ecs-cluster-1": access denied`)
+}
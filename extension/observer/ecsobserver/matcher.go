@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const defaultMetricsPath = "/metrics"
+
+// matchedPort is a container port exporting metrics.
+type matchedPort struct {
+	port        int
+	jobName     string
+	metricsPath string
+}
+
+// matcher selects the ports exporting metrics of the containers of the tasks.
+type matcher interface {
+	// matchPorts returns the ports of the container exporting metrics, nil when the container isn't matched.
+	matchPorts(t *task, container *ecs.ContainerDefinition) []matchedPort
+}
+
+// taskMatcher matches the containers of the tasks selected by matchTask.
+type taskMatcher struct {
+	matchTask     func(t *task) bool
+	containerName *regexp.Regexp
+	settings      TargetSettings
+}
+
+func (m *taskMatcher) matchPorts(t *task, container *ecs.ContainerDefinition) []matchedPort {
+	if !m.matchTask(t) {
+		return nil
+	}
+	if m.containerName != nil && !m.containerName.MatchString(aws.StringValue(container.Name)) {
+		return nil
+	}
+
+	metricsPath := m.settings.MetricsPath
+	if metricsPath == "" {
+		metricsPath = defaultMetricsPath
+	}
+	var ports []matchedPort
+	for _, port := range m.settings.MetricsPorts {
+		if hasContainerPort(container, port) {
+			ports = append(ports, matchedPort{port: port, jobName: m.settings.JobName, metricsPath: metricsPath})
+		}
+	}
+	return ports
+}
+
+// dockerLabelMatcher matches the containers having the port label.
+type dockerLabelMatcher struct {
+	config DockerLabelConfig
+}
+
+func (m *dockerLabelMatcher) matchPorts(_ *task, container *ecs.ContainerDefinition) []matchedPort {
+	value, ok := container.DockerLabels[m.config.PortLabel]
+	if !ok {
+		return nil
+	}
+	port, err := strconv.Atoi(aws.StringValue(value))
+	if err != nil || !hasContainerPort(container, port) {
+		return nil
+	}
+
+	matched := matchedPort{port: port, metricsPath: defaultMetricsPath}
+	if m.config.JobNameLabel != "" {
+		matched.jobName = aws.StringValue(container.DockerLabels[m.config.JobNameLabel])
+	}
+	if m.config.MetricsPathLabel != "" {
+		if path := aws.StringValue(container.DockerLabels[m.config.MetricsPathLabel]); path != "" {
+			matched.metricsPath = path
+		}
+	}
+	return []matchedPort{matched}
+}
+
+func hasContainerPort(container *ecs.ContainerDefinition, port int) bool {
+	for _, mapping := range container.PortMappings {
+		if aws.Int64Value(mapping.ContainerPort) == int64(port) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMatchers validates the matchers of the config, returning them in the order they take precedence: the services,
+// then the task definitions and then the docker labels ones.
+func newMatchers(cfg *Config) ([]matcher, error) {
+	var matchers []matcher
+	for i, service := range cfg.Services {
+		name, err := compilePattern(fmt.Sprintf("services[%d].name_pattern", i), service.NamePattern, true)
+		if err != nil {
+			return nil, err
+		}
+		m, err := newTaskMatcher(fmt.Sprintf("services[%d]", i), service.TargetSettings, service.ContainerNamePattern)
+		if err != nil {
+			return nil, err
+		}
+		m.matchTask = func(t *task) bool {
+			serviceName, ok := t.serviceName()
+			return ok && name.MatchString(serviceName)
+		}
+		matchers = append(matchers, m)
+	}
+
+	for i, definition := range cfg.TaskDefinitions {
+		arn, err := compilePattern(fmt.Sprintf("task_definitions[%d].arn_pattern", i), definition.ArnPattern, true)
+		if err != nil {
+			return nil, err
+		}
+		m, err := newTaskMatcher(fmt.Sprintf("task_definitions[%d]", i), definition.TargetSettings, definition.ContainerNamePattern)
+		if err != nil {
+			return nil, err
+		}
+		m.matchTask = func(t *task) bool {
+			return arn.MatchString(aws.StringValue(t.task.TaskDefinitionArn))
+		}
+		matchers = append(matchers, m)
+	}
+
+	for i, label := range cfg.DockerLabels {
+		if label.PortLabel == "" {
+			return nil, fmt.Errorf("docker_labels[%d].port_label must be specified", i)
+		}
+		matchers = append(matchers, &dockerLabelMatcher{config: label})
+	}
+	return matchers, nil
+}
+
+func newTaskMatcher(name string, settings TargetSettings, containerNamePattern string) (*taskMatcher, error) {
+	if len(settings.MetricsPorts) == 0 {
+		return nil, fmt.Errorf("%s.metrics_ports must be specified", name)
+	}
+	for _, port := range settings.MetricsPorts {
+		if port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid %s.metrics_ports %d, must be between 1 and 65535", name, port)
+		}
+	}
+	containerName, err := compilePattern(name+".container_name_pattern", containerNamePattern, false)
+	if err != nil {
+		return nil, err
+	}
+	return &taskMatcher{containerName: containerName, settings: settings}, nil
+}
+
+// compilePattern compiles the regular expression of the setting, returning nil when it's optional and not set.
+func compilePattern(setting, pattern string, required bool) (*regexp.Regexp, error) {
+	if pattern == "" {
+		if required {
+			return nil, fmt.Errorf("%s must be specified", setting)
+		}
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %v", setting, pattern, err)
+	}
+	return re, nil
+}
@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// ecsMock serves the tasks, the task definitions and the container instances of a cluster.
+type ecsMock struct {
+	// taskArns are the tasks listed, pageSize at a time.
+	taskArns []string
+	pageSize int
+	// tasks are the tasks described, the listed tasks missing being reported as failures.
+	tasks              map[string]*ecs.Task
+	definitions        map[string]*ecs.TaskDefinition
+	containerInstances map[string]*ecs.ContainerInstance
+	err                error
+
+	listCalls, describeTasksCalls, describeDefinitionCalls, describeInstancesCalls int
+}
+
+func (m *ecsMock) ListTasksPagesWithContext(_ aws.Context, _ *ecs.ListTasksInput, fn func(*ecs.ListTasksOutput, bool) bool, _ ...request.Option) error {
+	if m.err != nil {
+		return m.err
+	}
+	for start := 0; start < len(m.taskArns); start += m.pageSize {
+		m.listCalls++
+		end := min(start+m.pageSize, len(m.taskArns))
+		if !fn(&ecs.ListTasksOutput{TaskArns: aws.StringSlice(m.taskArns[start:end])}, end == len(m.taskArns)) {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *ecsMock) DescribeTasksWithContext(_ aws.Context, input *ecs.DescribeTasksInput, _ ...request.Option) (*ecs.DescribeTasksOutput, error) {
+	m.describeTasksCalls++
+	if len(input.Tasks) > describeBatchSize {
+		return nil, errors.New("too many tasks")
+	}
+	output := &ecs.DescribeTasksOutput{}
+	for _, arn := range input.Tasks {
+		if t, ok := m.tasks[aws.StringValue(arn)]; ok {
+			output.Tasks = append(output.Tasks, t)
+		} else {
+			output.Failures = append(output.Failures, &ecs.Failure{Arn: arn, Reason: aws.String("MISSING")})
+		}
+	}
+	return output, nil
+}
+
+func (m *ecsMock) DescribeTaskDefinitionWithContext(_ aws.Context, input *ecs.DescribeTaskDefinitionInput, _ ...request.Option) (*ecs.DescribeTaskDefinitionOutput, error) {
+	m.describeDefinitionCalls++
+	definition, ok := m.definitions[aws.StringValue(input.TaskDefinition)]
+	if !ok {
+		return nil, errors.New("task definition not found")
+	}
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: definition}, nil
+}
+
+func (m *ecsMock) DescribeContainerInstancesWithContext(_ aws.Context, input *ecs.DescribeContainerInstancesInput, _ ...request.Option) (*ecs.DescribeContainerInstancesOutput, error) {
+	m.describeInstancesCalls++
+	output := &ecs.DescribeContainerInstancesOutput{}
+	for _, arn := range input.ContainerInstances {
+		if ci, ok := m.containerInstances[aws.StringValue(arn)]; ok {
+			output.ContainerInstances = append(output.ContainerInstances, ci)
+		} else {
+			output.Failures = append(output.Failures, &ecs.Failure{Arn: arn, Reason: aws.String("MISSING")})
+		}
+	}
+	return output, nil
+}
+
+// ec2Mock serves the EC2 instances, one per page.
+type ec2Mock struct {
+	instances map[string]*ec2.Instance
+	calls     int
+}
+
+func (m *ec2Mock) DescribeInstancesPagesWithContext(_ aws.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, _ ...request.Option) error {
+	m.calls++
+	for i, id := range input.InstanceIds {
+		instance, ok := m.instances[aws.StringValue(id)]
+		if !ok {
+			continue
+		}
+		output := &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{instance}}}}
+		if !fn(output, i == len(input.InstanceIds)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+const (
+	retailTaskArn    = "arn:aws:ecs:us-west-2:123456789012:task/ecs-cluster-1/retail"
+	nginxTaskArn     = "arn:aws:ecs:us-west-2:123456789012:task/ecs-cluster-1/nginx"
+	stoppedTaskArn   = "arn:aws:ecs:us-west-2:123456789012:task/ecs-cluster-1/stopped"
+	missingTaskArn   = "arn:aws:ecs:us-west-2:123456789012:task/ecs-cluster-1/missing"
+	retailDefinition = "arn:aws:ecs:us-west-2:123456789012:task-definition/retail:1"
+	nginxDefinition  = "arn:aws:ecs:us-west-2:123456789012:task-definition/nginx:3"
+	containerInstArn = "arn:aws:ecs:us-west-2:123456789012:container-instance/ecs-cluster-1/instance-1"
+)
+
+// newClusterMocks returns a cluster running a Fargate task of the retail-api service and an EC2 task of the nginx
+// task definition. A stopped task and a task stopped since it was listed are listed too.
+func newClusterMocks() (*ecsMock, *ec2Mock) {
+	ecsClient := &ecsMock{
+		taskArns: []string{retailTaskArn, nginxTaskArn, stoppedTaskArn, missingTaskArn},
+		pageSize: 2,
+		tasks: map[string]*ecs.Task{
+			retailTaskArn: {
+				TaskArn:           aws.String(retailTaskArn),
+				TaskDefinitionArn: aws.String(retailDefinition),
+				Group:             aws.String("service:retail-api"),
+				LaunchType:        aws.String(ecs.LaunchTypeFargate),
+				LastStatus:        aws.String(ecs.DesiredStatusRunning),
+				Containers: []*ecs.Container{
+					{Name: aws.String("java-api"), NetworkInterfaces: []*ecs.NetworkInterface{{PrivateIpv4Address: aws.String("10.0.0.1")}}},
+					{Name: aws.String("envoy"), NetworkInterfaces: []*ecs.NetworkInterface{{PrivateIpv4Address: aws.String("10.0.0.1")}}},
+				},
+			},
+			nginxTaskArn: {
+				TaskArn:              aws.String(nginxTaskArn),
+				TaskDefinitionArn:    aws.String(nginxDefinition),
+				Group:                aws.String("family:nginx"),
+				LaunchType:           aws.String(ecs.LaunchTypeEc2),
+				LastStatus:           aws.String(ecs.DesiredStatusRunning),
+				ContainerInstanceArn: aws.String(containerInstArn),
+				Containers: []*ecs.Container{{
+					Name: aws.String("nginx"),
+					NetworkBindings: []*ecs.NetworkBinding{
+						{ContainerPort: aws.Int64(80), HostPort: aws.Int64(80)},
+						{ContainerPort: aws.Int64(9113), HostPort: aws.Int64(32768)},
+					},
+				}},
+			},
+			stoppedTaskArn: {
+				TaskArn:           aws.String(stoppedTaskArn),
+				TaskDefinitionArn: aws.String(retailDefinition),
+				LastStatus:        aws.String(ecs.DesiredStatusStopped),
+			},
+		},
+		definitions: map[string]*ecs.TaskDefinition{
+			retailDefinition: {
+				TaskDefinitionArn: aws.String(retailDefinition),
+				Family:            aws.String("retail"),
+				Revision:          aws.Int64(1),
+				NetworkMode:       aws.String(ecs.NetworkModeAwsvpc),
+				ContainerDefinitions: []*ecs.ContainerDefinition{
+					{Name: aws.String("java-api"), PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(8080)}}},
+					{Name: aws.String("envoy"), PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(9901)}}},
+				},
+			},
+			nginxDefinition: {
+				TaskDefinitionArn: aws.String(nginxDefinition),
+				Family:            aws.String("nginx"),
+				Revision:          aws.Int64(3),
+				NetworkMode:       aws.String(ecs.NetworkModeBridge),
+				ContainerDefinitions: []*ecs.ContainerDefinition{{
+					Name: aws.String("nginx"),
+					PortMappings: []*ecs.PortMapping{
+						{ContainerPort: aws.Int64(80)},
+						{ContainerPort: aws.Int64(9113)},
+					},
+					DockerLabels: map[string]*string{
+						"ECS_PROMETHEUS_EXPORTER_PORT": aws.String("9113"),
+						"app.tier":                     aws.String("frontend"),
+					},
+				}},
+			},
+		},
+		containerInstances: map[string]*ecs.ContainerInstance{
+			containerInstArn: {ContainerInstanceArn: aws.String(containerInstArn), Ec2InstanceId: aws.String("i-1")},
+		},
+	}
+	ec2Client := &ec2Mock{
+		instances: map[string]*ec2.Instance{
+			"i-1": {InstanceId: aws.String("i-1"), PrivateIpAddress: aws.String("10.0.1.1")},
+		},
+	}
+	return ecsClient, ec2Client
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const (
+	labelMetricsPath                = "__metrics_path__"
+	labelJob                        = "job"
+	labelClusterName                = "ecs_cluster_name"
+	labelTaskDefinitionFamily       = "ecs_task_definition_family"
+	labelTaskDefinitionRevision     = "ecs_task_definition_revision"
+	labelTaskLaunchType             = "ecs_task_launch_type"
+	labelServiceName                = "ecs_service_name"
+	labelContainerName              = "ecs_container_name"
+	labelEC2InstanceID              = "ecs_ec2_instance_id"
+	labelContainerDockerLabelPrefix = "ecs_container_label_"
+)
+
+// invalidLabelChars are the characters which can't be used in the Prometheus label names.
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// fileSDTarget is a group of targets of the Prometheus file_sd_configs.
+type fileSDTarget struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// buildTargets returns the targets of the ports exporting metrics of the containers of the tasks, sorted by address
+// and metrics path. When several matchers match the same port, the first one takes precedence.
+func buildTargets(cluster string, tasks []*task, matchers []matcher) []fileSDTarget {
+	targets := []fileSDTarget{}
+	for _, t := range tasks {
+		for _, container := range t.definition.ContainerDefinitions {
+			ports := map[int]bool{}
+			for _, m := range matchers {
+				for _, matched := range m.matchPorts(t, container) {
+					if ports[matched.port] {
+						continue
+					}
+					ports[matched.port] = true
+
+					address, ok := t.address(container, matched.port)
+					if !ok {
+						continue
+					}
+					targets = append(targets, fileSDTarget{
+						Targets: []string{address},
+						Labels:  targetLabels(cluster, t, container, matched),
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Targets[0] != targets[j].Targets[0] {
+			return targets[i].Targets[0] < targets[j].Targets[0]
+		}
+		return targets[i].Labels[labelMetricsPath] < targets[j].Labels[labelMetricsPath]
+	})
+	return targets
+}
+
+// address returns the address the container port can be scraped at, false when it's unknown, e.g. because the
+// instance of the task couldn't be found.
+func (t *task) address(container *ecs.ContainerDefinition, port int) (string, bool) {
+	name := aws.StringValue(container.Name)
+	var taskContainer *ecs.Container
+	for _, c := range t.task.Containers {
+		if aws.StringValue(c.Name) == name {
+			taskContainer = c
+			break
+		}
+	}
+
+	switch aws.StringValue(t.definition.NetworkMode) {
+	case ecs.NetworkModeAwsvpc:
+		// The containers of the tasks using the awsvpc mode share the network interface of the task.
+		if taskContainer == nil || len(taskContainer.NetworkInterfaces) == 0 {
+			return "", false
+		}
+		ip := aws.StringValue(taskContainer.NetworkInterfaces[0].PrivateIpv4Address)
+		if ip == "" {
+			return "", false
+		}
+		return ip + ":" + strconv.Itoa(port), true
+	case ecs.NetworkModeHost:
+		if t.instance == nil || t.instance.PrivateIpAddress == nil {
+			return "", false
+		}
+		return aws.StringValue(t.instance.PrivateIpAddress) + ":" + strconv.Itoa(port), true
+	case ecs.NetworkModeNone:
+		return "", false
+	default:
+		// The bridge mode, where the container ports are mapped to ports of the instance.
+		if t.instance == nil || t.instance.PrivateIpAddress == nil || taskContainer == nil {
+			return "", false
+		}
+		for _, binding := range taskContainer.NetworkBindings {
+			if aws.Int64Value(binding.ContainerPort) == int64(port) {
+				return aws.StringValue(t.instance.PrivateIpAddress) + ":" + strconv.FormatInt(aws.Int64Value(binding.HostPort), 10), true
+			}
+		}
+		return "", false
+	}
+}
+
+func targetLabels(cluster string, t *task, container *ecs.ContainerDefinition, matched matchedPort) map[string]string {
+	labels := map[string]string{
+		labelMetricsPath:            matched.metricsPath,
+		labelClusterName:            cluster,
+		labelTaskDefinitionFamily:   aws.StringValue(t.definition.Family),
+		labelTaskDefinitionRevision: strconv.FormatInt(aws.Int64Value(t.definition.Revision), 10),
+		labelTaskLaunchType:         aws.StringValue(t.task.LaunchType),
+		labelContainerName:          aws.StringValue(container.Name),
+	}
+	if matched.jobName != "" {
+		labels[labelJob] = matched.jobName
+	}
+	if serviceName, ok := t.serviceName(); ok {
+		labels[labelServiceName] = serviceName
+	}
+	if t.instance != nil {
+		labels[labelEC2InstanceID] = aws.StringValue(t.instance.InstanceId)
+	}
+	for k, v := range container.DockerLabels {
+		labels[labelContainerDockerLabelPrefix+invalidLabelChars.ReplaceAllString(k, "_")] = aws.StringValue(v)
+	}
+	return labels
+}
+
+// marshalTargets returns the content of the file_sd file of the targets.
+func marshalTargets(targets []fileSDTarget) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(targets); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFile replaces the content of the file, writing it to a temporary file renamed over it so that it's never read
+// partially written.
+func writeFile(path string, content []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create the result file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write the result file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write the result file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write the result file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write the result file: %w", err)
+	}
+	return nil
+}
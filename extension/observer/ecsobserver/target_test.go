@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func fetchClusterTasks(t *testing.T) []*task {
+	ecsClient, ec2Client := newClusterMocks()
+	tasks, err := newTaskFetcher("ecs-cluster-1", ecsClient, ec2Client, zap.NewNop()).fetchTasks(context.Background())
+	require.NoError(t, err)
+	return tasks
+}
+
+func TestBuildTargets(t *testing.T) {
+	matchers, err := newMatchers(&Config{
+		Services: []ServiceConfig{{
+			TargetSettings:       TargetSettings{JobName: "retail", MetricsPorts: []int{8080, 9901}},
+			NamePattern:          "^retail-.*$",
+			ContainerNamePattern: "^java-api$",
+		}},
+		TaskDefinitions: []TaskDefinitionConfig{{
+			TargetSettings: TargetSettings{MetricsPath: "/stub_status", MetricsPorts: []int{80}},
+			ArnPattern:     ":task-definition/nginx:[0-9]+$",
+		}},
+		DockerLabels: []DockerLabelConfig{{PortLabel: "ECS_PROMETHEUS_EXPORTER_PORT"}},
+	})
+	require.NoError(t, err)
+
+	targets := buildTargets("ecs-cluster-1", fetchClusterTasks(t), matchers)
+	assert.Equal(t, []fileSDTarget{
+		{
+			Targets: []string{"10.0.0.1:8080"},
+			Labels: map[string]string{
+				"__metrics_path__":             "/metrics",
+				"job":                          "retail",
+				"ecs_cluster_name":             "ecs-cluster-1",
+				"ecs_task_definition_family":   "retail",
+				"ecs_task_definition_revision": "1",
+				"ecs_task_launch_type":         "FARGATE",
+				"ecs_service_name":             "retail-api",
+				"ecs_container_name":           "java-api",
+			},
+		},
+		{
+			Targets: []string{"10.0.1.1:32768"},
+			Labels: map[string]string{
+				"__metrics_path__":                                 "/metrics",
+				"ecs_cluster_name":                                 "ecs-cluster-1",
+				"ecs_task_definition_family":                       "nginx",
+				"ecs_task_definition_revision":                     "3",
+				"ecs_task_launch_type":                             "EC2",
+				"ecs_container_name":                               "nginx",
+				"ecs_ec2_instance_id":                              "i-1",
+				"ecs_container_label_ECS_PROMETHEUS_EXPORTER_PORT": "9113",
+				"ecs_container_label_app_tier":                     "frontend",
+			},
+		},
+		{
+			Targets: []string{"10.0.1.1:80"},
+			Labels: map[string]string{
+				"__metrics_path__":                                 "/stub_status",
+				"ecs_cluster_name":                                 "ecs-cluster-1",
+				"ecs_task_definition_family":                       "nginx",
+				"ecs_task_definition_revision":                     "3",
+				"ecs_task_launch_type":                             "EC2",
+				"ecs_container_name":                               "nginx",
+				"ecs_ec2_instance_id":                              "i-1",
+				"ecs_container_label_ECS_PROMETHEUS_EXPORTER_PORT": "9113",
+				"ecs_container_label_app_tier":                     "frontend",
+			},
+		},
+	}, targets)
+}
+
+func TestBuildTargetsPrecedence(t *testing.T) {
+	matchers, err := newMatchers(&Config{
+		TaskDefinitions: []TaskDefinitionConfig{{
+			TargetSettings: TargetSettings{JobName: "nginx", MetricsPorts: []int{9113}},
+			ArnPattern:     "nginx",
+		}},
+		DockerLabels: []DockerLabelConfig{{PortLabel: "ECS_PROMETHEUS_EXPORTER_PORT"}},
+	})
+	require.NoError(t, err)
+
+	targets := buildTargets("ecs-cluster-1", fetchClusterTasks(t), matchers)
+	require.Len(t, targets, 1)
+	assert.Equal(t, "nginx", targets[0].Labels["job"])
+}
+
+func TestTaskAddress(t *testing.T) {
+	container := &ecs.ContainerDefinition{Name: aws.String("app")}
+	tests := []struct {
+		name    string
+		task    *task
+		address string
+	}{
+		{
+			name: "host network",
+			task: &task{
+				task:       &ecs.Task{},
+				definition: &ecs.TaskDefinition{NetworkMode: aws.String(ecs.NetworkModeHost)},
+				instance:   fetchClusterTasks(t)[1].instance,
+			},
+			address: "10.0.1.1:8080",
+		},
+		{
+			name: "bridge network without instance",
+			task: &task{
+				task: &ecs.Task{Containers: []*ecs.Container{{
+					Name:            aws.String("app"),
+					NetworkBindings: []*ecs.NetworkBinding{{ContainerPort: aws.Int64(8080), HostPort: aws.Int64(8080)}},
+				}}},
+				definition: &ecs.TaskDefinition{NetworkMode: aws.String(ecs.NetworkModeBridge)},
+			},
+		},
+		{
+			name: "awsvpc network without interface",
+			task: &task{
+				task:       &ecs.Task{Containers: []*ecs.Container{{Name: aws.String("app")}}},
+				definition: &ecs.TaskDefinition{NetworkMode: aws.String(ecs.NetworkModeAwsvpc)},
+			},
+		},
+		{
+			name: "no network",
+			task: &task{
+				task:       &ecs.Task{},
+				definition: &ecs.TaskDefinition{NetworkMode: aws.String(ecs.NetworkModeNone)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, ok := tt.task.address(container, 8080)
+			assert.Equal(t, tt.address != "", ok)
+			assert.Equal(t, tt.address, address)
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecsobserver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "targets.json")
+	content, err := marshalTargets([]fileSDTarget{{
+		Targets: []string{"10.0.0.1:8080"},
+		Labels:  map[string]string{"job": "retail"},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, writeFile(path, content))
+
+	written, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"targets": ["10.0.0.1:8080"], "labels": {"job": "retail"}}]`, string(written))
+
+	// No temporary file is left behind.
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
@@ -35,19 +35,23 @@ const (
 	PodType EndpointType = "pod"
 	// HostPortType is a hostport endpoint.
 	HostPortType EndpointType = "hostport"
+	// ContainerType is a container endpoint.
+	ContainerType EndpointType = "container"
 )
 
 var (
 	// EndpointTypes is a map of all known endpoint types.
 	EndpointTypes = map[EndpointType]bool{
-		PortType:     true,
-		PodType:      true,
-		HostPortType: true,
+		PortType:      true,
+		PodType:       true,
+		HostPortType:  true,
+		ContainerType: true,
 	}
 
 	_ EndpointDetails = (*Pod)(nil)
 	_ EndpointDetails = (*Port)(nil)
 	_ EndpointDetails = (*HostPort)(nil)
+	_ EndpointDetails = (*ContainerPort)(nil)
 )
 
 // EndpointDetails provides additional context about an endpoint such as a Pod or Port.
@@ -111,12 +115,22 @@ func (p *Pod) Type() EndpointType {
 	return PodType
 }
 
+// Container is a container running in a k8s pod.
+type Container struct {
+	// Name of the container.
+	Name string
+	// Image is the image the container is running.
+	Image string
+}
+
 // Port is an endpoint that has a target as well as a port.
 type Port struct {
 	// Name is the name of the container port.
 	Name string
 	// Pod is the k8s pod in which the container is running.
 	Pod Pod
+	// Container is the container exposing the port.
+	Container Container
 	// Port number of the endpoint.
 	Port uint16
 	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
@@ -132,7 +146,12 @@ func (p *Port) Env() EndpointEnv {
 			"labels":      p.Pod.Labels,
 			"annotations": p.Pod.Annotations,
 		},
-		"transport": p.Transport,
+		"container": map[string]interface{}{
+			"name":  p.Container.Name,
+			"image": p.Container.Image,
+		},
+		// Use string instead of Transport for rule evaluation.
+		"transport": string(p.Transport),
 	}
 }
 
@@ -162,10 +181,44 @@ func (h *HostPort) Env() EndpointEnv {
 		"command":   h.Command,
 		"is_ipv6":   h.IsIPv6,
 		"port":      h.Port,
-		"transport": h.Transport,
+		"transport": string(h.Transport),
 	}
 }
 
 func (h *HostPort) Type() EndpointType {
 	return HostPortType
 }
+
+// ContainerPort is a port exposed by a container running outside of k8s, e.g. discovered by the docker observer.
+type ContainerPort struct {
+	// Name of the container.
+	Name string
+	// Image is the image the container is running.
+	Image string
+	// ContainerID is the id of the container.
+	ContainerID string
+	// Labels is a map of user-specified metadata.
+	Labels map[string]string
+	// Port number exposed by the container.
+	Port uint16
+	// HostPort is the port of the host the container port is published on, or zero when it isn't published.
+	HostPort uint16
+	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
+	Transport Transport
+}
+
+func (c *ContainerPort) Env() EndpointEnv {
+	return map[string]interface{}{
+		"name":         c.Name,
+		"image":        c.Image,
+		"container_id": c.ContainerID,
+		"labels":       c.Labels,
+		"port":         c.Port,
+		"host_port":    c.HostPort,
+		"transport":    string(c.Transport),
+	}
+}
+
+func (c *ContainerPort) Type() EndpointType {
+	return ContainerType
+}
@@ -43,9 +43,10 @@ func TestEndpointEnv(t *testing.T) {
 			},
 			want: EndpointEnv{
 				"type": map[string]bool{
-					"pod":      true,
-					"hostport": false,
-					"port":     false,
+					"pod":       true,
+					"hostport":  false,
+					"port":      false,
+					"container": false,
 				},
 				"endpoint": "192.68.73.2",
 				"name":     "pod_name",
@@ -74,15 +75,20 @@ func TestEndpointEnv(t *testing.T) {
 							"annotation_1": "value_1",
 						},
 					},
+					Container: Container{
+						Name:  "container_name",
+						Image: "container_image",
+					},
 					Port:      2379,
 					Transport: ProtocolTCP,
 				},
 			},
 			want: EndpointEnv{
 				"type": map[string]bool{
-					"pod":      false,
-					"hostport": false,
-					"port":     true,
+					"pod":       false,
+					"hostport":  false,
+					"port":      true,
+					"container": false,
 				},
 				"endpoint": "192.68.73.2",
 				"name":     "port_name",
@@ -96,7 +102,11 @@ func TestEndpointEnv(t *testing.T) {
 						"annotation_1": "value_1",
 					},
 				},
-				"transport": ProtocolTCP,
+				"container": map[string]interface{}{
+					"name":  "container_name",
+					"image": "container_image",
+				},
+				"transport": string(ProtocolTCP),
 			},
 			wantErr: false,
 		},
@@ -115,16 +125,54 @@ func TestEndpointEnv(t *testing.T) {
 			},
 			want: EndpointEnv{
 				"type": map[string]bool{
-					"hostport": true,
-					"pod":      false,
-					"port":     false,
+					"hostport":  true,
+					"pod":       false,
+					"port":      false,
+					"container": false,
 				},
 				"endpoint":  "127.0.0.1",
 				"name":      "process_name",
 				"command":   "./cmd --config config.yaml",
 				"is_ipv6":   true,
 				"port":      uint16(2379),
-				"transport": ProtocolUDP,
+				"transport": string(ProtocolUDP),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Container port",
+			endpoint: Endpoint{
+				ID:     EndpointID("container_port_id"),
+				Target: "127.0.0.1:8080",
+				Details: &ContainerPort{
+					Name:        "container_name",
+					Image:       "redis:6",
+					ContainerID: "container_id",
+					Labels: map[string]string{
+						"label_key": "label_val",
+					},
+					Port:      6379,
+					HostPort:  8080,
+					Transport: ProtocolTCP,
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]bool{
+					"container": true,
+					"hostport":  false,
+					"pod":       false,
+					"port":      false,
+				},
+				"endpoint":     "127.0.0.1:8080",
+				"name":         "container_name",
+				"image":        "redis:6",
+				"container_id": "container_id",
+				"labels": map[string]string{
+					"label_key": "label_val",
+				},
+				"port":      uint16(6379),
+				"host_port": uint16(8080),
+				"transport": string(ProtocolTCP),
 			},
 			wantErr: false,
 		},
@@ -43,8 +43,14 @@ func (h *handler) OnAdd(obj interface{}) {
 
 // convertPodToEndpoints converts a pod instance into a slice of endpoints. The endpoints
 // include the pod itself as well as an endpoint for each container port that is mapped
-// to a container that is in a running state.
+// to a container that is in a running state. The pods whose containers all terminated
+// have no endpoints, so that they're removed as soon as the pods exit instead of when
+// they're deleted.
 func (h *handler) convertPodToEndpoints(pod *v1.Pod) []observer.Endpoint {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return nil
+	}
+
 	podID := observer.EndpointID(fmt.Sprintf("%s/%s", h.idNamespace, pod.UID))
 	podIP := pod.Status.PodIP
 
@@ -85,7 +91,11 @@ func (h *handler) convertPodToEndpoints(pod *v1.Pod) []observer.Endpoint {
 				ID:     endpointID,
 				Target: fmt.Sprintf("%s:%d", podIP, port.ContainerPort),
 				Details: &observer.Port{
-					Pod:       podDetails,
+					Pod: podDetails,
+					Container: observer.Container{
+						Name:  container.Name,
+						Image: container.Image,
+					},
 					Name:      port.Name,
 					Port:      uint16(port.ContainerPort),
 					Transport: getTransport(port.Protocol),
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
@@ -46,6 +47,10 @@ func TestEndpointsAdded(t *testing.T) {
 					Name:   "pod-2",
 					Labels: map[string]string{"env": "prod"},
 				},
+				Container: observer.Container{
+					Name:  "container-2",
+					Image: "container-image-2",
+				},
 				Port:      443,
 				Transport: observer.ProtocolTCP,
 			},
@@ -78,6 +83,10 @@ func TestEndpointsRemoved(t *testing.T) {
 					Name:   "pod-2",
 					Labels: map[string]string{"env": "prod"},
 				},
+				Container: observer.Container{
+					Name:  "container-2",
+					Image: "container-image-2",
+				},
 				Port:      443,
 				Transport: observer.ProtocolTCP,
 			},
@@ -130,7 +139,30 @@ func TestEndpointsChanged(t *testing.T) {
 				Name: "https", Pod: observer.Pod{
 					Name:   "pod-2",
 					Labels: map[string]string{"env": "prod", "updated-label": "true"}},
+				Container: observer.Container{Name: "container-2", Image: "container-image-2"},
 				Port:      443,
 				Transport: observer.ProtocolTCP}},
 	}, sink.changed)
 }
+
+func TestEndpointsRemovedWhenPodExits(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	exitedPod := podWithNamedPorts.DeepCopy()
+	exitedPod.Status.Phase = v1.PodSucceeded
+	h.OnUpdate(podWithNamedPorts, exitedPod)
+
+	assert.Nil(t, sink.added)
+	assert.Nil(t, sink.changed)
+	assert.ElementsMatch(t,
+		[]observer.EndpointID{"test-1/pod-2-UID", "test-1/pod-2-UID/https(443)"},
+		[]observer.EndpointID{sink.removed[0].ID, sink.removed[1].ID})
+
+	// Deleting the exited pod doesn't remove its endpoints again.
+	sink = endpointSink{}
+	h.OnDelete(exitedPod)
+	assert.Empty(t, sink.removed)
+}
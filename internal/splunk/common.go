@@ -21,6 +21,7 @@ const (
 	SFxAccessTokenLabel   = "com.splunk.signalfx.access_token" // #nosec
 	SFxEventCategoryKey   = "com.splunk.signalfx.event_category"
 	SFxEventPropertiesKey = "com.splunk.signalfx.event_properties"
+	SourceLabel           = "com.splunk.source"
 	SourcetypeLabel       = "com.splunk.sourcetype"
 	IndexLabel            = "com.splunk.index"
 	HECTokenHeader        = "Splunk"
@@ -0,0 +1,146 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxray
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// maxAnnotations is the maximum number of annotations of a segment allowed by X-Ray, the attributes overflowing
+	// it being converted to metadata
+	maxAnnotations = 50
+
+	// indexAll indexes all the attributes when used as the key of an indexed attribute
+	indexAll = "*"
+	// wildcardSuffix is the suffix of the keys of the indexed attributes matching all the keys with their prefix,
+	// e.g. telemetry.* matches telemetry.sdk.name and telemetry.sdk.language
+	wildcardSuffix = ".*"
+)
+
+// AttributeIndexer selects the attributes converted to X-Ray annotations, which are indexed, the other attributes
+// being converted to metadata. A nil AttributeIndexer indexes no attribute.
+type AttributeIndexer struct {
+	spanKeys     keyMatcher
+	resourceKeys keyMatcher
+}
+
+// keyMatcher matches attribute keys against a list of keys and prefixes
+type keyMatcher struct {
+	all      bool
+	keys     map[string]bool
+	prefixes []string
+}
+
+// NewAttributeIndexer creates an AttributeIndexer indexing the span attributes with the given keys and the resource
+// attributes with the given keys, or all the attributes when indexAllAttrs is set. A key ending with .* matches all
+// the keys with this prefix, and * matches all the keys. For compatibility, the resource attributes can also be
+// indexed with their key prefixed by otel.resource. in the span keys, which * doesn't match.
+func NewAttributeIndexer(indexedAttrs []string, indexedResourceAttrs []string, indexAllAttrs bool) *AttributeIndexer {
+	indexer := &AttributeIndexer{
+		spanKeys:     newKeyMatcher(indexedAttrs),
+		resourceKeys: newKeyMatcher(indexedResourceAttrs),
+	}
+	if indexAllAttrs {
+		indexer.spanKeys.all = true
+		indexer.resourceKeys.all = true
+	}
+	return indexer
+}
+
+func newKeyMatcher(keys []string) keyMatcher {
+	matcher := keyMatcher{keys: map[string]bool{}}
+	for _, key := range keys {
+		switch {
+		case key == indexAll:
+			matcher.all = true
+		case strings.HasSuffix(key, wildcardSuffix):
+			// the prefix keeps the dot, so that telemetry.* doesn't match telemetryfoo
+			matcher.prefixes = append(matcher.prefixes, strings.TrimSuffix(key, "*"))
+		default:
+			matcher.keys[key] = true
+		}
+	}
+	return matcher
+}
+
+func (m keyMatcher) matches(key string) bool {
+	return m.all || m.matchesKey(key)
+}
+
+// matchesKey returns whether the key matches one of the keys or prefixes, ignoring *
+func (m keyMatcher) matchesKey(key string) bool {
+	if m.keys[key] {
+		return true
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexesSpanAttribute returns whether the span attribute with the given key is indexed
+func (i *AttributeIndexer) indexesSpanAttribute(key string) bool {
+	return i != nil && i.spanKeys.matches(key)
+}
+
+// indexesResourceAttribute returns whether the resource attribute with the given key is indexed
+func (i *AttributeIndexer) indexesResourceAttribute(key string) bool {
+	return i != nil && (i.resourceKeys.matches(key) || i.spanKeys.matchesKey(resourceAttributePrefix+key))
+}
+
+// fixAnnotationKey removes any invalid characters from the annotaiton key.  AWS X-Ray defines
+// the list of valid characters here:
+// https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html
+func fixAnnotationKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case '0' <= r && r <= '9':
+			fallthrough
+		case 'A' <= r && r <= 'Z':
+			fallthrough
+		case 'a' <= r && r <= 'z':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+func addAnnotations(annos map[string]interface{}, attrs *pdata.AttributeMap) {
+	for k, v := range annos {
+		switch t := v.(type) {
+		case int:
+			attrs.UpsertInt(k, int64(t))
+		case int32:
+			attrs.UpsertInt(k, int64(t))
+		case int64:
+			attrs.UpsertInt(k, t)
+		case string:
+			attrs.UpsertString(k, t)
+		case bool:
+			attrs.UpsertBool(k, t)
+		case float32:
+			attrs.UpsertDouble(k, float64(t))
+		case float64:
+			attrs.UpsertDouble(k, t)
+		default:
+		}
+	}
+}
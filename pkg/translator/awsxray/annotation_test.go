@@ -0,0 +1,118 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxray
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestAttributeIndexer(t *testing.T) {
+	tests := []struct {
+		name             string
+		indexer          *AttributeIndexer
+		spanKey          string
+		resourceKey      string
+		expectedSpan     bool
+		expectedResource bool
+	}{
+		{
+			name:    "nil",
+			spanKey: "attr", resourceKey: "attr",
+		},
+		{
+			name:    "keys",
+			indexer: NewAttributeIndexer([]string{"attr"}, []string{"res"}, false),
+			spanKey: "attr", resourceKey: "res",
+			expectedSpan: true, expectedResource: true,
+		},
+		{
+			name:    "other keys",
+			indexer: NewAttributeIndexer([]string{"attr"}, []string{"res"}, false),
+			spanKey: "res", resourceKey: "attr",
+		},
+		{
+			name:    "wildcard",
+			indexer: NewAttributeIndexer([]string{"telemetry.*"}, []string{"k8s.*"}, false),
+			spanKey: "telemetry.sdk.name", resourceKey: "k8s.pod.name",
+			expectedSpan: true, expectedResource: true,
+		},
+		{
+			name:    "wildcard without the dot",
+			indexer: NewAttributeIndexer([]string{"telemetry.*"}, []string{"k8s.*"}, false),
+			spanKey: "telemetrysdk", resourceKey: "k8s",
+		},
+		{
+			name:    "all span attributes",
+			indexer: NewAttributeIndexer([]string{"*"}, nil, false),
+			spanKey: "attr", resourceKey: "res",
+			expectedSpan: true,
+		},
+		{
+			name:    "all resource attributes",
+			indexer: NewAttributeIndexer(nil, []string{"*"}, false),
+			spanKey: "attr", resourceKey: "res",
+			expectedResource: true,
+		},
+		{
+			name:    "index all attributes",
+			indexer: NewAttributeIndexer(nil, nil, true),
+			spanKey: "attr", resourceKey: "res",
+			expectedSpan: true, expectedResource: true,
+		},
+		{
+			name:    "prefixed resource attribute",
+			indexer: NewAttributeIndexer([]string{"otel.resource.res"}, nil, false),
+			spanKey: "attr", resourceKey: "res",
+			expectedResource: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedSpan, tt.indexer.indexesSpanAttribute(tt.spanKey))
+			assert.Equal(t, tt.expectedResource, tt.indexer.indexesResourceAttribute(tt.resourceKey))
+		})
+	}
+}
+
+func TestAddAnnotations(t *testing.T) {
+	input := make(map[string]interface{})
+	input["int"] = 0
+	input["int32"] = int32(1)
+	input["int64"] = int64(2)
+	input["bool"] = false
+	input["float32"] = float32(4.5)
+	input["float64"] = 5.5
+
+	attrMap := pdata.NewAttributeMap()
+	attrMap.InitEmptyWithCapacity(initAttrCapacity)
+	addAnnotations(input, &attrMap)
+
+	expectedAttrMap := pdata.NewAttributeMap()
+	expectedAttrMap.InitFromMap(
+		map[string]pdata.AttributeValue{
+			"int":     pdata.NewAttributeValueInt(int64(0)),
+			"int32":   pdata.NewAttributeValueInt(int64(1)),
+			"int64":   pdata.NewAttributeValueInt(int64(2)),
+			"bool":    pdata.NewAttributeValueBool(false),
+			"float32": pdata.NewAttributeValueDouble(4.5),
+			"float64": pdata.NewAttributeValueDouble(5.5),
+		},
+	)
+	assert.Equal(t, expectedAttrMap.Sort(), attrMap.Sort(), "attribute maps differ")
+}
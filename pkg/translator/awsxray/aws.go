@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"bytes"
@@ -272,3 +272,58 @@ func parseLogGroup(arn string) string {
 
 	return arn
 }
+
+func addAWSToResource(aws *awsxray.AWSData, attrs *pdata.AttributeMap) {
+	if aws == nil {
+		// https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/c615d2db351929b99e46f7b427f39c12afe15b54/exporter/awsxrayexporter/translator/aws.go#L121
+		// this implies that the current segment being processed is not generated
+		// by an AWS entity.
+		attrs.UpsertString(semconventions.AttributeCloudProvider, "unknown")
+		return
+	}
+
+	attrs.UpsertString(semconventions.AttributeCloudProvider, semconventions.AttributeCloudProviderAWS)
+	addString(aws.AccountID, semconventions.AttributeCloudAccount, attrs)
+
+	// based on https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html#api-segmentdocuments-aws
+	// it's possible to have all ec2, ecs and beanstalk fields at the same time.
+	if ec2 := aws.EC2; ec2 != nil {
+		addString(ec2.AvailabilityZone, semconventions.AttributeCloudZone, attrs)
+		addString(ec2.InstanceID, semconventions.AttributeHostID, attrs)
+		addString(ec2.InstanceSize, semconventions.AttributeHostType, attrs)
+		addString(ec2.AmiID, semconventions.AttributeHostImageID, attrs)
+	}
+
+	if ecs := aws.ECS; ecs != nil {
+		addString(ecs.ContainerName, semconventions.AttributeContainerName, attrs)
+		addString(ecs.AvailabilityZone, semconventions.AttributeCloudZone, attrs)
+		addString(ecs.ContainerID, semconventions.AttributeContainerID, attrs)
+	}
+
+	if bs := aws.Beanstalk; bs != nil {
+		addString(bs.Environment, semconventions.AttributeServiceNamespace, attrs)
+		if bs.DeploymentID != nil {
+			attrs.UpsertString(semconventions.AttributeServiceInstance, strconv.FormatInt(*bs.DeploymentID, 10))
+		}
+		addString(bs.VersionLabel, semconventions.AttributeServiceVersion, attrs)
+	}
+
+	if eks := aws.EKS; eks != nil {
+		addString(eks.ContainerID, semconventions.AttributeContainerID, attrs)
+		addString(eks.ClusterName, semconventions.AttributeK8sCluster, attrs)
+		addString(eks.Pod, semconventions.AttributeK8sPod, attrs)
+
+	}
+}
+
+func addAWSToSpan(aws *awsxray.AWSData, attrs *pdata.AttributeMap) {
+	if aws != nil {
+		addString(aws.AccountID, awsxray.AWSAccountAttribute, attrs)
+		addString(aws.Operation, awsxray.AWSOperationAttribute, attrs)
+		addString(aws.RemoteRegion, awsxray.AWSRegionAttribute, attrs)
+		addString(aws.RequestID, awsxray.AWSRequestIDAttribute, attrs)
+		addString(aws.QueueURL, awsxray.AWSQueueURLAttribute, attrs)
+		addString(aws.TableName, awsxray.AWSTableNameAttribute, attrs)
+		addInt64(aws.Retries, awsxray.AWSXrayRetriesAttribute, attrs)
+	}
+}
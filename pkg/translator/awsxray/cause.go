@@ -12,11 +12,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"bufio"
 	"net/textproto"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -58,26 +59,32 @@ func makeCause(span pdata.Span, attributes map[string]string, resource pdata.Res
 		exceptions := make([]awsxray.Exception, 0)
 		for i := 0; i < span.Events().Len(); i++ {
 			event := span.Events().At(i)
-			if event.Name() == semconventions.AttributeExceptionEventName {
-				exceptionType := ""
-				message = ""
-				stacktrace := ""
-
-				if val, ok := event.Attributes().Get(semconventions.AttributeExceptionType); ok {
-					exceptionType = val.StringVal()
-				}
+			if event.Name() != semconventions.AttributeExceptionEventName {
+				continue
+			}
+			if _, ok := event.Attributes().Get(awsxray.AWSXrayExceptionIDAttribute); ok {
+				// The event was translated from an X-Ray exception by the receiver, restore it as is.
+				exceptions = append(exceptions, makeXRayException(event.Attributes()))
+				continue
+			}
+			exceptionType := ""
+			message = ""
+			stacktrace := ""
 
-				if val, ok := event.Attributes().Get(semconventions.AttributeExceptionMessage); ok {
-					message = val.StringVal()
-				}
+			if val, ok := event.Attributes().Get(semconventions.AttributeExceptionType); ok {
+				exceptionType = val.StringVal()
+			}
 
-				if val, ok := event.Attributes().Get(semconventions.AttributeExceptionStacktrace); ok {
-					stacktrace = val.StringVal()
-				}
+			if val, ok := event.Attributes().Get(semconventions.AttributeExceptionMessage); ok {
+				message = val.StringVal()
+			}
 
-				parsed := parseException(exceptionType, message, stacktrace, language)
-				exceptions = append(exceptions, parsed...)
+			if val, ok := event.Attributes().Get(semconventions.AttributeExceptionStacktrace); ok {
+				stacktrace = val.StringVal()
 			}
+
+			parsed := parseException(exceptionType, message, stacktrace, language)
+			exceptions = append(exceptions, parsed...)
 		}
 		cause = &awsxray.CauseData{
 			Type: awsxray.CauseTypeObject,
@@ -134,6 +141,58 @@ func makeCause(span pdata.Span, attributes map[string]string, resource pdata.Res
 	return isError, isFault, filtered, cause
 }
 
+// makeXRayException rebuilds an X-Ray exception from the attributes of an exception event created by addCause.
+func makeXRayException(attrs pdata.AttributeMap) awsxray.Exception {
+	exception := awsxray.Exception{}
+	if val, ok := attrs.Get(awsxray.AWSXrayExceptionIDAttribute); ok {
+		exception.ID = aws.String(val.StringVal())
+	}
+	if val, ok := attrs.Get(semconventions.AttributeExceptionMessage); ok {
+		exception.Message = aws.String(val.StringVal())
+	}
+	if val, ok := attrs.Get(semconventions.AttributeExceptionType); ok {
+		exception.Type = aws.String(val.StringVal())
+	}
+	if val, ok := attrs.Get(awsxray.AWSXrayExceptionRemoteAttribute); ok {
+		exception.Remote = aws.Bool(val.BoolVal())
+	}
+	if val, ok := attrs.Get(awsxray.AWSXrayExceptionTruncatedAttribute); ok {
+		exception.Truncated = aws.Int64(val.IntVal())
+	}
+	if val, ok := attrs.Get(awsxray.AWSXrayExceptionSkippedAttribute); ok {
+		exception.Skipped = aws.Int64(val.IntVal())
+	}
+	if val, ok := attrs.Get(awsxray.AWSXrayExceptionCauseAttribute); ok {
+		exception.Cause = aws.String(val.StringVal())
+	}
+	if val, ok := attrs.Get(semconventions.AttributeExceptionStacktrace); ok {
+		exception.Stack = parseXRayStackFrames(val.StringVal())
+	}
+	return exception
+}
+
+// xrayStackFrameRegexp matches the frames written by convertStackFramesToStackTraceStr. The label stops at the first
+// parenthesis and the path at the last colon, so that paths containing parentheses or colons are preserved.
+var xrayStackFrameRegexp = regexp.MustCompile(`^\tat (.*?)\((.*): (-?\d+)\)$`)
+
+func parseXRayStackFrames(stacktrace string) []awsxray.StackFrame {
+	var frames []awsxray.StackFrame
+	for _, line := range strings.Split(stacktrace, "\n") {
+		matches := xrayStackFrameRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			// The first line holds the type and message of the exception, which may span several lines.
+			continue
+		}
+		lineNumber, _ := strconv.Atoi(matches[3])
+		frames = append(frames, awsxray.StackFrame{
+			Label: aws.String(matches[1]),
+			Path:  aws.String(matches[2]),
+			Line:  aws.Int(lineNumber),
+		})
+	}
+	return frames
+}
+
 func parseException(exceptionType string, message string, stacktrace string, language string) []awsxray.Exception {
 	exceptions := make([]awsxray.Exception, 0, 1)
 	exceptions = append(exceptions, awsxray.Exception{
@@ -416,3 +475,83 @@ func indexOf(str string, c byte, pos int) int {
 	}
 	return -1
 }
+
+func addCause(seg *awsxray.Segment, span *pdata.Span) {
+	if seg.Cause == nil {
+		return
+	}
+
+	// In the `rawExpectedSegmentForInstrumentedApp` X-Ray segment example in
+	// awsxray/tracesegment_test.go, you can see that sometimes the HTTP Response is not
+	// set but the Cause field is set for the root segment. And the actual HTTP status
+	// can only be found in one of the (nested) subsegmets. So we need to signal that
+	// in this case, the status of the span is not otlptrace.Status_Ok by
+	// temporarily setting the status to otlptrace.Status_UnknownError. This will be
+	// updated to a more specific error in the `segToSpans()` in translator.go once
+	// we traverse through all the subsegments.
+	if span.Status().Code() == pdata.StatusCodeUnset {
+		// StatusCodeUnset is the default value for the span.Status().
+		span.Status().SetCode(pdata.StatusCodeError)
+	}
+
+	switch seg.Cause.Type {
+	case awsxray.CauseTypeExceptionID:
+		// Right now the X-Ray exporter does not support the case where
+		// 1) CauseData is just a 16-char exception ID,
+		// 2) `WorkingDirectory` and `Paths`
+		// https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/c615d2db351929b99e46f7b427f39c12afe15b54/exporter/awsxrayexporter/translator/cause.go#L107
+
+		// so we can only pass the cause exceptionID as the status message as a fallback mechanism
+		span.Status().SetMessage(*seg.Cause.ExceptionID)
+	case awsxray.CauseTypeObject:
+		evts := span.Events()
+		// not sure whether there are existing events, so
+		// append new empty events instead
+		exceptionEventStartIndex := evts.Len()
+		evts.Resize(exceptionEventStartIndex + len(seg.Cause.Exceptions))
+
+		for i, excp := range seg.Cause.Exceptions {
+			evt := evts.At(exceptionEventStartIndex + i)
+			evt.SetName(semconventions.AttributeExceptionEventName)
+			attrs := evt.Attributes()
+			attrs.InitEmptyWithCapacity(8)
+
+			// ID is a required field
+			attrs.UpsertString(awsxray.AWSXrayExceptionIDAttribute, *excp.ID)
+			addString(excp.Message, semconventions.AttributeExceptionMessage, &attrs)
+			addString(excp.Type, semconventions.AttributeExceptionType, &attrs)
+			addBool(excp.Remote, awsxray.AWSXrayExceptionRemoteAttribute, &attrs)
+			addInt64(excp.Truncated, awsxray.AWSXrayExceptionTruncatedAttribute, &attrs)
+			addInt64(excp.Skipped, awsxray.AWSXrayExceptionSkippedAttribute, &attrs)
+			addString(excp.Cause, awsxray.AWSXrayExceptionCauseAttribute, &attrs)
+
+			if len(excp.Stack) > 0 {
+				stackTrace := convertStackFramesToStackTraceStr(excp)
+				attrs.UpsertString(semconventions.AttributeExceptionStacktrace, stackTrace)
+			}
+		}
+	}
+}
+
+func convertStackFramesToStackTraceStr(excp awsxray.Exception) string {
+	// resulting stacktrace looks like:
+	// "<*excp.Type>: <*excp.Message>\n" +
+	// "\tat <*frameN.Label>(<*frameN.Path>: <*frameN.Line>)\n"
+	// with the missing fields written as empty strings and zero lines.
+	var b strings.Builder
+	b.WriteString(aws.StringValue(excp.Type))
+	b.WriteString(": ")
+	b.WriteString(aws.StringValue(excp.Message))
+	b.WriteString("\n")
+	for _, frame := range excp.Stack {
+		b.WriteString("\tat ")
+		b.WriteString(aws.StringValue(frame.Label))
+		b.WriteString("(")
+		b.WriteString(aws.StringValue(frame.Path))
+		b.WriteString(": ")
+		b.WriteString(strconv.Itoa(aws.IntValue(frame.Line)))
+		b.WriteString(")")
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -12,16 +12,20 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	semconventions "go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 )
 
 func TestCauseWithExceptions(t *testing.T) {
@@ -704,3 +708,85 @@ func TestParseExceptionWithJavaScriptStactracekMalformedLines(t *testing.T) {
 	assert.Equal(t, "/home/gbusey/file.js", *exceptions[0].Stack[0].Path)
 	assert.Equal(t, 0, *exceptions[0].Stack[0].Line)
 }
+
+func TestConvertStackFramesToStackTraceStr(t *testing.T) {
+	excp := awsxray.Exception{
+		Type:    awsxray.String("exceptionType"),
+		Message: awsxray.String("exceptionMessage"),
+		Stack: []awsxray.StackFrame{
+			{
+				Path:  awsxray.String("path0"),
+				Line:  aws.Int(10),
+				Label: awsxray.String("label0"),
+			},
+			{
+				Path:  awsxray.String("path1"),
+				Line:  aws.Int(11),
+				Label: awsxray.String("label1"),
+			},
+		},
+	}
+	actual := convertStackFramesToStackTraceStr(excp)
+	assert.Equal(t, actual, "exceptionType: exceptionMessage\n\tat label0(path0: 10)\n\tat label1(path1: 11)\n")
+}
+
+func TestConvertStackFramesToStackTraceStrWithMissingFields(t *testing.T) {
+	excp := awsxray.Exception{
+		Stack: []awsxray.StackFrame{
+			{
+				Path: awsxray.String("path0"),
+			},
+		},
+	}
+	actual := convertStackFramesToStackTraceStr(excp)
+	assert.Equal(t, ": \n\tat (path0: 0)\n", actual)
+}
+
+func TestCauseWithXRayExceptions(t *testing.T) {
+	event := pdata.NewSpanEvent()
+	event.SetName(semconventions.AttributeExceptionEventName)
+	attributes := pdata.NewAttributeMap()
+	attributes.InsertString(awsxray.AWSXrayExceptionIDAttribute, "0123456789abcdef")
+	attributes.InsertString(semconventions.AttributeExceptionType, "SomeError")
+	attributes.InsertString(semconventions.AttributeExceptionMessage, "something failed")
+	attributes.InsertBool(awsxray.AWSXrayExceptionRemoteAttribute, true)
+	attributes.InsertInt(awsxray.AWSXrayExceptionTruncatedAttribute, 2)
+	attributes.InsertInt(awsxray.AWSXrayExceptionSkippedAttribute, 3)
+	attributes.InsertString(awsxray.AWSXrayExceptionCauseAttribute, "fedcba9876543210")
+	attributes.InsertString(semconventions.AttributeExceptionStacktrace, "SomeError: something failed\n"+
+		"\tat main.handle(main.go: 42)\n"+
+		"\tat Object.<anonymous>(C:\\Program Files (x86)\\app.js: 7)\n")
+	attributes.CopyTo(event.Attributes())
+
+	span := constructExceptionServerSpan(make(map[string]interface{}), pdata.StatusCodeError)
+	span.Events().Append(event)
+	filtered, _ := makeHTTP(span)
+
+	// The exceptions converted from X-Ray don't depend on the language of the SDK.
+	_, _, _, cause := makeCause(span, filtered, pdata.NewResource())
+
+	require.NotNil(t, cause)
+	assert.Equal(t, []awsxray.Exception{
+		{
+			ID:        awsxray.String("0123456789abcdef"),
+			Type:      awsxray.String("SomeError"),
+			Message:   awsxray.String("something failed"),
+			Remote:    aws.Bool(true),
+			Truncated: aws.Int64(2),
+			Skipped:   aws.Int64(3),
+			Cause:     awsxray.String("fedcba9876543210"),
+			Stack: []awsxray.StackFrame{
+				{
+					Label: awsxray.String("main.handle"),
+					Path:  awsxray.String("main.go"),
+					Line:  aws.Int(42),
+				},
+				{
+					Label: awsxray.String("Object.<anonymous>"),
+					Path:  awsxray.String(`C:\Program Files (x86)\app.js`),
+					Line:  aws.Int(7),
+				},
+			},
+		},
+	}, cause.Exceptions)
+}
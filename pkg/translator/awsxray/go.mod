@@ -0,0 +1,14 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/awsxray
+
+go 1.14
+
+require (
+	github.com/aws/aws-sdk-go v1.36.24
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.5
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray => ../../../internal/awsxray
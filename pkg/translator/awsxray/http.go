@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"strconv"
@@ -235,3 +235,40 @@ func constructServerURL(urlParts map[string]string) string {
 	}
 	return url
 }
+
+func addHTTP(seg *awsxray.Segment, span *pdata.Span) {
+	if seg.HTTP == nil {
+		return
+	}
+
+	attrs := span.Attributes()
+	if req := seg.HTTP.Request; req != nil {
+		// https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html#api-segmentdocuments-http
+		addString(req.Method, semconventions.AttributeHTTPMethod, &attrs)
+
+		if req.ClientIP != nil {
+			// since the ClientIP is not nil, this means that this segment is generated
+			// by a server serving an incoming request
+			attrs.UpsertString(semconventions.AttributeHTTPClientIP, *req.ClientIP)
+		}
+
+		addString(req.UserAgent, semconventions.AttributeHTTPUserAgent, &attrs)
+		addString(req.URL, semconventions.AttributeHTTPURL, &attrs)
+		addBool(req.XForwardedFor, awsxray.AWSXRayXForwardedForAttribute, &attrs)
+	}
+
+	if resp := seg.HTTP.Response; resp != nil {
+		if resp.Status != nil {
+			otStatus := tracetranslator.StatusCodeFromHTTP(int(*resp.Status))
+			// in X-Ray exporter, the segment status is set:
+			// first via the span attribute, semconventions.AttributeHTTPStatusCode
+			// then the span status. Since we are also setting the span attribute
+			// below, the span status code here will not be actually used
+			span.Status().SetCode(otStatus)
+			attrs.UpsertInt(semconventions.AttributeHTTPStatusCode, *resp.Status)
+		}
+
+		addInt64(resp.ContentLength, semconventions.AttributeHTTPResponseContentLength, &attrs)
+	}
+
+}
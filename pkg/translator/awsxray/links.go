@@ -0,0 +1,140 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxray
+
+import (
+	"encoding/json"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// otelMetadataNamespace is the metadata namespace holding the span fields which have no equivalent in the X-Ray
+	// segments.
+	otelMetadataNamespace = "otel"
+	linksMetadataKey      = "links"
+
+	linkTraceIDKey    = "trace_id"
+	linkSpanIDKey     = "id"
+	linkTraceStateKey = "trace_state"
+	linkAttributesKey = "attributes"
+)
+
+// makeLinks converts the links of the span to the list stored in the "otel" metadata namespace of the segment.
+func makeLinks(span pdata.Span) []interface{} {
+	links := span.Links()
+	if links.Len() == 0 {
+		return nil
+	}
+
+	converted := make([]interface{}, 0, links.Len())
+	for i := 0; i < links.Len(); i++ {
+		link := links.At(i)
+		// Unlike the trace id of the segment, the one of a link isn't checked by X-Ray so any trace can be linked.
+		value := map[string]interface{}{
+			linkTraceIDKey: formatAmazonTraceID(link.TraceID()),
+			linkSpanIDKey:  link.SpanID().HexString(),
+		}
+		if link.TraceState() != pdata.TraceStateEmpty {
+			value[linkTraceStateKey] = string(link.TraceState())
+		}
+		if link.Attributes().Len() > 0 {
+			attributes := map[string]interface{}{}
+			link.Attributes().ForEach(func(key string, value pdata.AttributeValue) {
+				if metaVal := metadataValue(value); metaVal != nil {
+					attributes[key] = metaVal
+				}
+			})
+			value[linkAttributesKey] = attributes
+		}
+		converted = append(converted, value)
+	}
+	return converted
+}
+
+// addLinks converts the links stored in the "otel" metadata namespace of the segment back to span links, and returns
+// the metadata without them.
+func addLinks(meta map[string]map[string]interface{}, span *pdata.Span) (map[string]map[string]interface{}, error) {
+	otelMeta, ok := meta[otelMetadataNamespace]
+	if !ok {
+		return meta, nil
+	}
+	rawLinks, ok := otelMeta[linksMetadataKey].([]interface{})
+	if !ok {
+		return meta, nil
+	}
+
+	links := span.Links()
+	links.Resize(len(rawLinks))
+	for i, rawLink := range rawLinks {
+		value, _ := rawLink.(map[string]interface{})
+		link := links.At(i)
+
+		traceID, _ := value[linkTraceIDKey].(string)
+		traceIDBytes, err := decodeXRayTraceID(&traceID)
+		if err != nil {
+			return nil, err
+		}
+		spanID, _ := value[linkSpanIDKey].(string)
+		spanIDBytes, err := decodeXRaySpanID(&spanID)
+		if err != nil {
+			return nil, err
+		}
+		link.SetTraceID(pdata.NewTraceID(traceIDBytes))
+		link.SetSpanID(pdata.NewSpanID(spanIDBytes))
+		if traceState, ok := value[linkTraceStateKey].(string); ok {
+			link.SetTraceState(pdata.TraceState(traceState))
+		}
+
+		attributes, _ := value[linkAttributesKey].(map[string]interface{})
+		attrs := link.Attributes()
+		attrs.InitEmptyWithCapacity(len(attributes))
+		for key, attribute := range attributes {
+			switch v := attribute.(type) {
+			case string:
+				attrs.UpsertString(key, v)
+			case bool:
+				attrs.UpsertBool(key, v)
+			case float64:
+				attrs.UpsertDouble(key, v)
+			default:
+				// The maps and arrays are kept as their JSON encoding.
+				encoded, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				attrs.UpsertString(key, string(encoded))
+			}
+		}
+	}
+
+	// Copy the metadata rather than modify the segment.
+	filtered := make(map[string]map[string]interface{}, len(meta))
+	for namespace, values := range meta {
+		filtered[namespace] = values
+	}
+	otelFiltered := make(map[string]interface{}, len(otelMeta))
+	for key, value := range otelMeta {
+		if key != linksMetadataKey {
+			otelFiltered[key] = value
+		}
+	}
+	if len(otelFiltered) > 0 {
+		filtered[otelMetadataNamespace] = otelFiltered
+	} else {
+		delete(filtered, otelMetadataNamespace)
+	}
+	return filtered, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxray
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSpanWithLinks(t *testing.T) {
+	span := constructServerSpan(newSegmentID(), "/api/locations", pdata.StatusCodeUnset, "OK", nil)
+	span.Links().Resize(1)
+	link := span.Links().At(0)
+	link.SetTraceID(pdata.NewTraceID([16]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}))
+	link.SetSpanID(pdata.NewSpanID([8]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}))
+	link.SetTraceState("congo=t61rcWkgMzE")
+	link.Attributes().InsertString("string", "value")
+	link.Attributes().InsertBool("bool", true)
+
+	segment, err := MakeSegment(span, pdata.NewResource(), nil)
+	require.NoError(t, err)
+
+	// The trace id of a link is kept whatever its time.
+	assert.Equal(t, map[string]interface{}{
+		linksMetadataKey: []interface{}{
+			map[string]interface{}{
+				linkTraceIDKey:    "1-01020304-05060708090a0b0c0d0e0f10",
+				linkSpanIDKey:     "0102030405060708",
+				linkTraceStateKey: "congo=t61rcWkgMzE",
+				linkAttributesKey: map[string]interface{}{
+					"string": "value",
+					"bool":   true,
+				},
+			},
+		},
+	}, segment.Metadata[otelMetadataNamespace])
+}
+
+func TestSpanWithoutLinks(t *testing.T) {
+	span := constructServerSpan(newSegmentID(), "/api/locations", pdata.StatusCodeUnset, "OK", nil)
+
+	segment, err := MakeSegment(span, pdata.NewResource(), nil)
+	require.NoError(t, err)
+
+	assert.NotContains(t, segment.Metadata, otelMetadataNamespace)
+}
+
+func TestAddLinks(t *testing.T) {
+	var meta map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"otel": {
+			"links": [
+				{
+					"trace_id": "1-01020304-05060708090a0b0c0d0e0f10",
+					"id": "0102030405060708",
+					"attributes": {"string": "value", "bool": true, "double": 1.5, "array": [1, 2]}
+				}
+			],
+			"other": "kept"
+		},
+		"default": {"key": "value"}
+	}`), &meta))
+
+	span := pdata.NewSpan()
+	filtered, err := addLinks(meta, &span)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]map[string]interface{}{
+		"otel":    {"other": "kept"},
+		"default": {"key": "value"},
+	}, filtered)
+	// the metadata of the segment is left as is
+	assert.Contains(t, meta["otel"], linksMetadataKey)
+
+	require.Equal(t, 1, span.Links().Len())
+	link := span.Links().At(0)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", link.TraceID().HexString())
+	assert.Equal(t, "0102030405060708", link.SpanID().HexString())
+	assert.Equal(t, pdata.TraceStateEmpty, link.TraceState())
+
+	expectedAttrs := pdata.NewAttributeMap()
+	expectedAttrs.InsertString("string", "value")
+	expectedAttrs.InsertBool("bool", true)
+	expectedAttrs.InsertDouble("double", 1.5)
+	expectedAttrs.InsertString("array", "[1,2]")
+	assert.Equal(t, expectedAttrs.Sort(), link.Attributes().Sort())
+}
+
+func TestAddLinksWithInvalidTraceID(t *testing.T) {
+	meta := map[string]map[string]interface{}{
+		"otel": {
+			"links": []interface{}{
+				map[string]interface{}{
+					"trace_id": "invalid",
+					"id":       "0102030405060708",
+				},
+			},
+		},
+	}
+
+	span := pdata.NewSpan()
+	_, err := addLinks(meta, &span)
+	assert.EqualError(t, err, "traceID length is wrong")
+}
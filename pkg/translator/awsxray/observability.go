@@ -0,0 +1,44 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxray
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func init() {
+	view.Register(
+		viewOverflowedAnnotations,
+	)
+}
+
+var (
+	mOverflowedAnnotations = stats.Int64("otelcol/awsxray/overflowed_annotations",
+		"Number of indexed attributes converted to metadata because of the limit of annotations of a segment", "1")
+)
+
+var viewOverflowedAnnotations = &view.View{
+	Name:        mOverflowedAnnotations.Name(),
+	Description: mOverflowedAnnotations.Description(),
+	Measure:     mOverflowedAnnotations,
+	Aggregation: view.Sum(),
+}
+
+func recordOverflowedAnnotations(n int) {
+	stats.Record(context.Background(), mOverflowedAnnotations.M(int64(n)))
+}
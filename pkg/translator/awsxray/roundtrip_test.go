@@ -0,0 +1,201 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxray
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+// roundTripSegment is a segment generated by testing/quick with only the fields supported by both the conversion to
+// spans and the conversion back to segments.
+type roundTripSegment struct {
+	seg awsxray.Segment
+}
+
+func (roundTripSegment) Generate(r *rand.Rand, _ int) reflect.Value {
+	// The trace id must be recent, or it would be rejected when converted back to a segment.
+	startTime := time.Now().Add(-time.Duration(r.Int63n(int64(24 * time.Hour)))).Truncate(time.Microsecond)
+	seg := awsxray.Segment{
+		ID:        aws.String(randomHex(r, 8)),
+		TraceID:   aws.String(fmt.Sprintf("1-%08x-%s", startTime.Unix(), randomHex(r, 12))),
+		Name:      aws.String(randomWord(r)),
+		StartTime: aws.Float64(float64(startTime.UnixNano()) / float64(time.Second)),
+		EndTime:   aws.Float64(float64(startTime.Add(time.Duration(r.Int63n(int64(time.Minute)))).UnixNano()) / float64(time.Second)),
+	}
+
+	if r.Intn(2) == 0 {
+		seg.ParentID = aws.String(randomHex(r, 8))
+		seg.Type = aws.String("subsegment")
+		seg.Namespace = aws.String(validRemoteNamespace)
+	}
+
+	if r.Intn(2) == 0 {
+		exceptions := make([]awsxray.Exception, 1+r.Intn(3))
+		for i := range exceptions {
+			exceptions[i] = randomException(r)
+		}
+		seg.Cause = &awsxray.CauseData{
+			Type:        awsxray.CauseTypeObject,
+			CauseObject: awsxray.CauseObject{Exceptions: exceptions},
+		}
+	}
+
+	if r.Intn(2) == 0 {
+		host := randomWord(r) + ".example.com"
+		db := randomWord(r)
+		seg.SQL = &awsxray.SQLData{
+			URL:          aws.String(fmt.Sprintf("jdbc:postgresql://%s:%d/%s", host, 1+r.Intn(65535), db)),
+			DatabaseType: aws.String([]string{"PostgreSQL", "MySQL", "oracle"}[r.Intn(3)]),
+		}
+		if r.Intn(2) == 0 {
+			seg.SQL.User = aws.String(randomWord(r))
+		}
+		if r.Intn(2) == 0 {
+			seg.SQL.SanitizedQuery = aws.String("SELECT * FROM " + randomWord(r) + " WHERE id = ?")
+		}
+		// The segments of the database queries are named <db name>@<db host>.
+		seg.Name = aws.String(db + "@" + host)
+	}
+
+	if r.Intn(2) == 0 {
+		links := make([]interface{}, 1+r.Intn(3))
+		for i := range links {
+			link := map[string]interface{}{
+				linkTraceIDKey: fmt.Sprintf("1-%s-%s", randomHex(r, 4), randomHex(r, 12)),
+				linkSpanIDKey:  randomHex(r, 8),
+			}
+			if r.Intn(2) == 0 {
+				link[linkTraceStateKey] = "congo=" + randomWord(r)
+			}
+			if r.Intn(2) == 0 {
+				link[linkAttributesKey] = map[string]interface{}{
+					"string": randomWord(r),
+					"bool":   r.Intn(2) == 0,
+				}
+			}
+			links[i] = link
+		}
+		seg.Metadata = map[string]map[string]interface{}{
+			otelMetadataNamespace: {linksMetadataKey: links},
+		}
+	}
+
+	return reflect.ValueOf(roundTripSegment{seg: seg})
+}
+
+func randomException(r *rand.Rand) awsxray.Exception {
+	exception := awsxray.Exception{
+		ID:      aws.String(randomHex(r, 8)),
+		Type:    aws.String(randomWord(r)),
+		Message: aws.String(randomWord(r) + " " + randomWord(r)),
+	}
+	if r.Intn(2) == 0 {
+		exception.Remote = aws.Bool(r.Intn(2) == 0)
+	}
+	if r.Intn(2) == 0 {
+		exception.Truncated = aws.Int64(r.Int63n(100))
+		exception.Skipped = aws.Int64(r.Int63n(100))
+	}
+	if r.Intn(2) == 0 {
+		exception.Cause = aws.String(randomHex(r, 8))
+	}
+	for i := r.Intn(4); i > 0; i-- {
+		exception.Stack = append(exception.Stack, awsxray.StackFrame{
+			Label: aws.String(randomWord(r) + "." + randomWord(r)),
+			// The paths may hold colons and parentheses, e.g. on Windows.
+			Path: aws.String([]string{"main.go", `C:\Program Files (x86)\app.js`, "/srv/app:v1/index.py"}[r.Intn(3)]),
+			Line: aws.Int(r.Intn(1000)),
+		})
+	}
+	return exception
+}
+
+func randomHex(r *rand.Rand, n int) string {
+	b := make([]byte, n)
+	r.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func randomWord(r *rand.Rand) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	b := make([]byte, 1+r.Intn(10))
+	for i := range b {
+		b[i] = letters[r.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// supportedSubset normalizes the segment through its JSON encoding, like it's sent to X-Ray, and keeps the fields
+// covered by the round trip only.
+func supportedSubset(t *testing.T, seg *awsxray.Segment) awsxray.Segment {
+	encoded, err := json.Marshal(seg)
+	require.NoError(t, err)
+	var normalized awsxray.Segment
+	require.NoError(t, json.Unmarshal(encoded, &normalized))
+
+	roundToMicroseconds := func(seconds *float64) *float64 {
+		return aws.Float64(math.Round(*seconds*1e6) / 1e6)
+	}
+	subset := awsxray.Segment{
+		ID:        normalized.ID,
+		TraceID:   normalized.TraceID,
+		ParentID:  normalized.ParentID,
+		Name:      normalized.Name,
+		Type:      normalized.Type,
+		Namespace: normalized.Namespace,
+		StartTime: roundToMicroseconds(normalized.StartTime),
+		EndTime:   roundToMicroseconds(normalized.EndTime),
+		Cause:     normalized.Cause,
+		SQL:       normalized.SQL,
+	}
+	if otelMeta, ok := normalized.Metadata[otelMetadataNamespace]; ok {
+		subset.Metadata = map[string]map[string]interface{}{otelMetadataNamespace: otelMeta}
+	}
+	return subset
+}
+
+func TestSegmentRoundTrip(t *testing.T) {
+	indexer := NewAttributeIndexer(nil, nil, false)
+	roundTrip := func(generated roundTripSegment) bool {
+		rawSeg, err := json.Marshal(generated.seg)
+		require.NoError(t, err)
+
+		traces, _, err := ToTraces(rawSeg)
+		require.NoError(t, err)
+		require.Equal(t, 1, traces.SpanCount())
+		rs := traces.ResourceSpans().At(0)
+		span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+
+		seg, err := MakeSegment(span, rs.Resource(), indexer)
+		require.NoError(t, err)
+
+		return assert.Equal(t, supportedSubset(t, &generated.seg), supportedSubset(t, seg), string(rawSeg))
+	}
+	assert.NoError(t, quick.Check(roundTrip, nil))
+}
@@ -12,13 +12,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"strings"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
-	"go.opentelemetry.io/collector/translator/conventions"
+	semconventions "go.opentelemetry.io/collector/translator/conventions"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 )
@@ -26,22 +26,15 @@ import (
 func addSdkToResource(seg *awsxray.Segment, attrs *pdata.AttributeMap) {
 	if seg.AWS != nil && seg.AWS.XRay != nil {
 		xr := seg.AWS.XRay
-		addString(xr.SDKVersion, conventions.AttributeTelemetrySDKVersion, attrs)
+		addString(xr.SDKVersion, semconventions.AttributeTelemetrySDKVersion, attrs)
 		if xr.SDK != nil {
-			attrs.UpsertString(conventions.AttributeTelemetrySDKName, *xr.SDK)
-			if seg.Cause != nil && len(seg.Cause.Exceptions) > 0 {
-				// https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/c615d2db351929b99e46f7b427f39c12afe15b54/exporter/awsxrayexporter/translator/cause.go#L150
-				// x-ray exporter only supports Java stack trace for now
-				// TODO: Update this once the exporter is more flexible
-				attrs.UpsertString(conventions.AttributeTelemetrySDKLanguage, "java")
-			} else {
-				// sample *xr.SDK: "X-Ray for Go"
-				sep := "for "
-				sdkStr := *xr.SDK
-				i := strings.Index(sdkStr, sep)
-				if i != -1 {
-					attrs.UpsertString(conventions.AttributeTelemetrySDKLanguage, sdkStr[i+len(sep):])
-				}
+			attrs.UpsertString(semconventions.AttributeTelemetrySDKName, *xr.SDK)
+			// sample *xr.SDK: "X-Ray for Go"
+			sep := "for "
+			sdkStr := *xr.SDK
+			i := strings.Index(sdkStr, sep)
+			if i != -1 {
+				attrs.UpsertString(semconventions.AttributeTelemetrySDKLanguage, sdkStr[i+len(sep):])
 			}
 		}
 	}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"encoding/binary"
@@ -21,6 +21,7 @@ import (
 	"math/rand"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -59,13 +60,16 @@ const (
 	identifierOffset = 11 // offset of identifier within traceID
 )
 
+// resourceAttributePrefix prefixes the keys of the resource attributes converted to annotations and metadata
+const resourceAttributePrefix = "otel.resource."
+
 var (
 	writers = newWriterPool(2048)
 )
 
 // MakeSegmentDocumentString converts an OpenTelemetry Span to an X-Ray Segment and then serialzies to JSON
-func MakeSegmentDocumentString(span pdata.Span, resource pdata.Resource, indexedAttrs []string, indexAllAttrs bool) (string, error) {
-	segment, err := MakeSegment(span, resource, indexedAttrs, indexAllAttrs)
+func MakeSegmentDocumentString(span pdata.Span, resource pdata.Resource, indexer *AttributeIndexer) (string, error) {
+	segment, err := MakeSegment(span, resource, indexer)
 	if err != nil {
 		return "", err
 	}
@@ -78,8 +82,9 @@ func MakeSegmentDocumentString(span pdata.Span, resource pdata.Resource, indexed
 	return jsonStr, nil
 }
 
-// MakeSegment converts an OpenTelemetry Span to an X-Ray Segment
-func MakeSegment(span pdata.Span, resource pdata.Resource, indexedAttrs []string, indexAllAttrs bool) (*awsxray.Segment, error) {
+// MakeSegment converts an OpenTelemetry Span to an X-Ray Segment, the attributes selected by the indexer being
+// converted to annotations
+func MakeSegment(span pdata.Span, resource pdata.Resource, indexer *AttributeIndexer) (*awsxray.Segment, error) {
 	var segmentType string
 
 	storeResource := true
@@ -105,11 +110,18 @@ func MakeSegment(span pdata.Span, resource pdata.Resource, indexedAttrs []string
 		awsfiltered, aws                       = makeAws(causefiltered, resource)
 		service                                = makeService(resource)
 		sqlfiltered, sql                       = makeSQL(awsfiltered)
-		user, annotations, metadata            = makeXRayAttributes(sqlfiltered, resource, storeResource, indexedAttrs, indexAllAttrs)
+		user, annotations, metadata            = makeXRayAttributes(sqlfiltered, resource, storeResource, indexer)
 		name                                   string
 		namespace                              string
 	)
 
+	if links := makeLinks(span); links != nil {
+		if metadata == nil {
+			metadata = map[string]map[string]interface{}{}
+		}
+		metadata[otelMetadataNamespace] = map[string]interface{}{linksMetadataKey: links}
+	}
+
 	// X-Ray segment names are service names, unlike span names which are methods. Try to find a service name.
 
 	attributes := span.Attributes()
@@ -134,7 +146,8 @@ func MakeSegment(span pdata.Span, resource pdata.Resource, indexedAttrs []string
 			// For database queries, the segment name convention is <db name>@<db host>
 			name = dbInstance.StringVal()
 			if dbURL, ok := attributes.Get(semconventions.AttributeDBConnectionString); ok {
-				if parsed, _ := url.Parse(dbURL.StringVal()); parsed != nil {
+				// The JDBC URLs, e.g. jdbc:postgresql://host:5432, hold a regular URL after their prefix.
+				if parsed, _ := url.Parse(strings.TrimPrefix(dbURL.StringVal(), "jdbc:")); parsed != nil {
 					if parsed.Hostname() != "" {
 						name += "@" + parsed.Hostname()
 					}
@@ -274,13 +287,13 @@ func determineAwsOrigin(resource pdata.Resource) string {
 //
 // A trace ID unique identifier that connects all segments and subsegments
 // originating from a single client request.
-//  * A trace_id consists of three numbers separated by hyphens. For example,
-//    1-58406520-a006649127e371903a2de979. This includes:
-//  * The version number, that is, 1.
-//  * The time of the original request, in Unix epoch time, in 8 hexadecimal digits.
-//  * For example, 10:00AM December 2nd, 2016 PST in epoch time is 1480615200 seconds,
-//    or 58406520 in hexadecimal.
-//  * A 96-bit identifier for the trace, globally unique, in 24 hexadecimal digits.
+//   - A trace_id consists of three numbers separated by hyphens. For example,
+//     1-58406520-a006649127e371903a2de979. This includes:
+//   - The version number, that is, 1.
+//   - The time of the original request, in Unix epoch time, in 8 hexadecimal digits.
+//   - For example, 10:00AM December 2nd, 2016 PST in epoch time is 1480615200 seconds,
+//     or 58406520 in hexadecimal.
+//   - A 96-bit identifier for the trace, globally unique, in 24 hexadecimal digits.
 func convertToAmazonTraceID(traceID pdata.TraceID) (string, error) {
 	const (
 		// maxAge of 28 days.  AWS has a 30 day limit, let's be conservative rather than
@@ -292,11 +305,9 @@ func convertToAmazonTraceID(traceID pdata.TraceID) (string, error) {
 	)
 
 	var (
-		content      = [traceIDLength]byte{}
 		epochNow     = time.Now().Unix()
 		traceIDBytes = traceID.Bytes()
 		epoch        = int64(binary.BigEndian.Uint32(traceIDBytes[0:4]))
-		b            = [4]byte{}
 	)
 
 	// If AWS traceID originally came from AWS, no problem.  However, if oc generated
@@ -308,22 +319,30 @@ func convertToAmazonTraceID(traceID pdata.TraceID) (string, error) {
 		return "", fmt.Errorf("invalid xray traceid: %s", traceID.HexString())
 	}
 
-	binary.BigEndian.PutUint32(b[0:4], uint32(epoch))
+	return formatAmazonTraceID(traceID), nil
+}
+
+// formatAmazonTraceID formats the trace id in the X-Ray format, whatever the time of the trace.
+func formatAmazonTraceID(traceID pdata.TraceID) string {
+	var (
+		content      = [traceIDLength]byte{}
+		traceIDBytes = traceID.Bytes()
+	)
 
 	content[0] = '1'
 	content[1] = '-'
-	hex.Encode(content[2:10], b[0:4])
+	hex.Encode(content[2:10], traceIDBytes[0:4])
 	content[10] = '-'
 	hex.Encode(content[identifierOffset:], traceIDBytes[4:16]) // overwrite with identifier
 
-	return string(content[0:traceIDLength]), nil
+	return string(content[0:traceIDLength])
 }
 
 func timestampToFloatSeconds(ts pdata.TimestampUnixNano) float64 {
 	return float64(ts) / float64(time.Second)
 }
 
-func makeXRayAttributes(attributes map[string]string, resource pdata.Resource, storeResource bool, indexedAttrs []string, indexAllAttrs bool) (
+func makeXRayAttributes(attributes map[string]string, resource pdata.Resource, storeResource bool, indexer *AttributeIndexer) (
 	string, map[string]interface{}, map[string]map[string]interface{}) {
 	var (
 		annotations = map[string]interface{}{}
@@ -342,47 +361,53 @@ func makeXRayAttributes(attributes map[string]string, resource pdata.Resource, s
 	}
 
 	defaultMetadata := map[string]interface{}{}
-
-	indexedKeys := map[string]bool{}
-	if !indexAllAttrs {
-		for _, name := range indexedAttrs {
-			indexedKeys[name] = true
+	overflowed := 0
+
+	// the attributes are converted in the order of their keys, so that the indexed attributes overflowing the
+	// limit of annotations are always the same, the span attributes being converted before the resource ones
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := attributes[key]
+		if indexer.indexesSpanAttribute(key) {
+			if len(annotations) < maxAnnotations {
+				annotations[fixAnnotationKey(key)] = value
+				continue
+			}
+			overflowed++
 		}
+		defaultMetadata[key] = value
 	}
 
 	if storeResource {
-		resource.Attributes().ForEach(func(key string, value pdata.AttributeValue) {
-			key = "otel.resource." + key
-			annoVal := annotationValue(value)
-			indexed := indexAllAttrs || indexedKeys[key]
-			if annoVal != nil && indexed {
-				key = fixAnnotationKey(key)
-				annotations[key] = annoVal
-			} else {
-				metaVal := metadataValue(value)
-				if metaVal != nil {
-					defaultMetadata[key] = metaVal
+		resourceKeys := make([]string, 0, resource.Attributes().Len())
+		resource.Attributes().ForEach(func(key string, _ pdata.AttributeValue) {
+			resourceKeys = append(resourceKeys, key)
+		})
+		sort.Strings(resourceKeys)
+		for _, key := range resourceKeys {
+			value, _ := resource.Attributes().Get(key)
+			prefixedKey := resourceAttributePrefix + key
+			if annoVal := annotationValue(value); annoVal != nil && indexer.indexesResourceAttribute(key) {
+				if len(annotations) < maxAnnotations {
+					annotations[fixAnnotationKey(prefixedKey)] = annoVal
+					continue
 				}
+				overflowed++
 			}
-		})
-	}
-
-	if indexAllAttrs {
-		for key, value := range attributes {
-			key = fixAnnotationKey(key)
-			annotations[key] = value
-		}
-	} else {
-		for key, value := range attributes {
-			if indexedKeys[key] {
-				key = fixAnnotationKey(key)
-				annotations[key] = value
-			} else {
-				defaultMetadata[key] = value
+			if metaVal := metadataValue(value); metaVal != nil {
+				defaultMetadata[prefixedKey] = metaVal
 			}
 		}
 	}
 
+	if overflowed > 0 {
+		recordOverflowedAnnotations(overflowed)
+	}
+
 	if len(defaultMetadata) > 0 {
 		metadata["default"] = defaultMetadata
 	}
@@ -448,21 +473,3 @@ func fixSegmentName(name string) string {
 
 	return name
 }
-
-// fixAnnotationKey removes any invalid characters from the annotaiton key.  AWS X-Ray defines
-// the list of valid characters here:
-// https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html
-func fixAnnotationKey(key string) string {
-	return strings.Map(func(r rune) rune {
-		switch {
-		case '0' <= r && r <= '9':
-			fallthrough
-		case 'A' <= r && r <= 'Z':
-			fallthrough
-		case 'a' <= r && r <= 'z':
-			return r
-		default:
-			return '_'
-		}
-	}, key)
-}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"encoding/binary"
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	semconventions "go.opentelemetry.io/collector/translator/conventions"
 
@@ -59,12 +60,12 @@ func TestClientSpanWithAwsSdkClient(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, 0, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 	assert.Equal(t, "DynamoDB", *segment.Name)
 	assert.Equal(t, "aws", *segment.Namespace)
 	assert.Equal(t, "subsegment", *segment.Type)
 
-	jsonStr, err := MakeSegmentDocumentString(span, resource, nil, false)
+	jsonStr, err := MakeSegmentDocumentString(span, resource, nil)
 
 	assert.NotNil(t, jsonStr)
 	assert.Nil(t, err)
@@ -91,7 +92,7 @@ func TestClientSpanWithPeerService(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, 0, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 	assert.Equal(t, "cats-table", *segment.Name)
 }
 
@@ -115,7 +116,7 @@ func TestServerSpanWithInternalServerError(t *testing.T) {
 	timeEvents := constructTimedEventsWithSentMessageEvent(span.StartTime())
 	timeEvents.CopyTo(span.Events())
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.NotNil(t, segment.Cause)
@@ -129,7 +130,7 @@ func TestServerSpanNoParentId(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeOk, "OK", nil)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.Empty(t, segment.ParentID)
 }
@@ -144,7 +145,7 @@ func TestSpanNoParentId(t *testing.T) {
 	span.SetStartTime(pdata.TimestampUnixNano(time.Now().UnixNano()))
 	span.SetEndTime(pdata.TimestampUnixNano(time.Now().Add(10).UnixNano()))
 	resource := pdata.NewResource()
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.Empty(t, segment.ParentID)
 	assert.Nil(t, segment.Type)
@@ -160,7 +161,7 @@ func TestSpanWithNoStatus(t *testing.T) {
 	span.SetEndTime(pdata.TimestampUnixNano(time.Now().Add(10).UnixNano()))
 
 	resource := pdata.NewResource()
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 	assert.NotNil(t, segment)
 }
 
@@ -180,7 +181,7 @@ func TestClientSpanWithDbComponent(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, pdata.StatusCodeUnset, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.NotNil(t, segment.SQL)
@@ -221,7 +222,7 @@ func TestClientSpanWithHttpHost(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, pdata.StatusCodeUnset, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, "foo.com", *segment.Name)
@@ -240,7 +241,7 @@ func TestClientSpanWithoutHttpHost(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, pdata.StatusCodeUnset, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, "bar.com", *segment.Name)
@@ -260,7 +261,7 @@ func TestClientSpanWithRpcHost(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, pdata.StatusCodeUnset, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, "com.foo.AnimalService", *segment.Name)
@@ -283,7 +284,7 @@ func TestSpanWithInvalidTraceId(t *testing.T) {
 	traceID[0] = 0x11
 	span.SetTraceID(pdata.NewTraceID(traceID))
 
-	_, err := MakeSegmentDocumentString(span, resource, nil, false)
+	_, err := MakeSegmentDocumentString(span, resource, nil)
 
 	assert.NotNil(t, err)
 }
@@ -331,7 +332,7 @@ func TestServerSpanWithNilAttributes(t *testing.T) {
 	timeEvents.CopyTo(span.Events())
 	pdata.NewAttributeMap().CopyTo(span.Attributes())
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.NotNil(t, segment.Cause)
@@ -348,7 +349,7 @@ func TestSpanWithAttributesDefaultNotIndexed(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, 0, len(segment.Annotations))
@@ -375,7 +376,7 @@ func TestSpanWithResourceNotStoredIfSubsegment(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, pdata.StatusCodeError, "ERROR", attributes)
 
-	segment, _ := MakeSegment(span, resource, nil, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, 0, len(segment.Annotations))
@@ -398,7 +399,7 @@ func TestSpanWithAttributesPartlyIndexed(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{"attr1@1", "not_exist"}, false)
+	segment, _ := MakeSegment(span, resource, NewAttributeIndexer([]string{"attr1@1", "not_exist"}, nil, false))
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, 1, len(segment.Annotations))
@@ -415,7 +416,7 @@ func TestSpanWithAttributesAllIndexed(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeOk, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{"attr1@1", "not_exist"}, true)
+	segment, _ := MakeSegment(span, resource, NewAttributeIndexer([]string{"attr1@1", "not_exist"}, nil, true))
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, "val1", segment.Annotations["attr1_1"])
@@ -429,14 +430,14 @@ func TestResourceAttributesCanBeIndexed(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{
+	segment, _ := MakeSegment(span, resource, NewAttributeIndexer([]string{
 		"otel.resource.string.key",
 		"otel.resource.int.key",
 		"otel.resource.double.key",
 		"otel.resource.bool.key",
 		"otel.resource.map.key",
 		"otel.resource.array.key",
-	}, false)
+	}, nil, false))
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, 4, len(segment.Annotations))
@@ -461,20 +462,67 @@ func TestResourceAttributesNotIndexedIfSubsegment(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{
+	segment, _ := MakeSegment(span, resource, NewAttributeIndexer([]string{
 		"otel.resource.string.key",
 		"otel.resource.int.key",
 		"otel.resource.double.key",
 		"otel.resource.bool.key",
 		"otel.resource.map.key",
 		"otel.resource.array.key",
-	}, false)
+	}, nil, false))
 
 	assert.NotNil(t, segment)
 	assert.Empty(t, segment.Annotations)
 	assert.Empty(t, segment.Metadata)
 }
 
+func TestResourceAttributesIndexedByResourceKeys(t *testing.T) {
+	spanName := "/api/locations"
+	parentSpanID := newSegmentID()
+	attributes := make(map[string]interface{})
+	attributes["telemetry.sdk.name"] = "opentelemetry"
+	attributes["string.key"] = "span"
+	resource := constructDefaultResource()
+	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
+
+	segment, _ := MakeSegment(span, resource,
+		NewAttributeIndexer([]string{"telemetry.*"}, []string{"string.key", "k8s.*"}, false))
+
+	assert.NotNil(t, segment)
+	assert.Equal(t, 6, len(segment.Annotations))
+	assert.Equal(t, "opentelemetry", segment.Annotations["telemetry_sdk_name"])
+	assert.Equal(t, "string", segment.Annotations["otel_resource_string_key"])
+	assert.Equal(t, "production", segment.Annotations["otel_resource_k8s_cluster_name"])
+	assert.Equal(t, "default", segment.Annotations["otel_resource_k8s_namespace_name"])
+	assert.Equal(t, "signup_aggregator", segment.Annotations["otel_resource_k8s_deployment_name"])
+	assert.Equal(t, "signup_aggregator-x82ufje83", segment.Annotations["otel_resource_k8s_pod_name"])
+	assert.Equal(t, "span", segment.Metadata["default"]["string.key"])
+	assert.Equal(t, int64(10), segment.Metadata["default"]["otel.resource.int.key"])
+}
+
+func TestAnnotationsOverflowToMetadata(t *testing.T) {
+	spanName := "/api/locations"
+	parentSpanID := newSegmentID()
+	attributes := make(map[string]interface{})
+	for i := 0; i < maxAnnotations+10; i++ {
+		attributes[fmt.Sprintf("attr%02d", i)] = "value"
+	}
+	resource := constructDefaultResource()
+	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeOk, "OK", attributes)
+	before := overflowedAnnotations(t)
+
+	segment, _ := MakeSegment(span, resource, NewAttributeIndexer([]string{"*"}, []string{"string.key"}, false))
+
+	assert.NotNil(t, segment)
+	assert.Equal(t, maxAnnotations, len(segment.Annotations))
+	// the attributes are converted in the order of their keys, the span ones first
+	assert.Equal(t, "value", segment.Annotations["attr49"])
+	assert.Equal(t, "value", segment.Metadata["default"]["attr50"])
+	assert.Equal(t, "value", segment.Metadata["default"]["attr59"])
+	assert.Equal(t, "string", segment.Metadata["default"]["otel.resource.string.key"])
+	assert.Equal(t, before+11, overflowedAnnotations(t))
+}
+
 func TestOriginNotAws(t *testing.T) {
 	spanName := "/test"
 	parentSpanID := newSegmentID()
@@ -486,7 +534,7 @@ func TestOriginNotAws(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{}, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Nil(t, segment.Origin)
@@ -504,7 +552,7 @@ func TestOriginEc2(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{}, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginEC2, *segment.Origin)
@@ -523,7 +571,7 @@ func TestOriginEcs(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{}, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginECS, *segment.Origin)
@@ -543,7 +591,7 @@ func TestOriginEcsEc2(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{}, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginECSEC2, *segment.Origin)
@@ -563,7 +611,7 @@ func TestOriginEcsFargate(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{}, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginECSFargate, *segment.Origin)
@@ -582,7 +630,7 @@ func TestOriginEb(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{}, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginEB, *segment.Origin)
@@ -598,7 +646,7 @@ func TestOriginBlank(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{}, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Nil(t, segment.Origin)
@@ -619,7 +667,7 @@ func TestOriginPrefersInfraService(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, pdata.StatusCodeError, "OK", attributes)
 
-	segment, _ := MakeSegment(span, resource, []string{}, false)
+	segment, _ := MakeSegment(span, resource, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginEC2, *segment.Origin)
@@ -780,3 +828,12 @@ func newTraceID() pdata.TraceID {
 	}
 	return pdata.NewTraceID(r)
 }
+
+func overflowedAnnotations(t *testing.T) int64 {
+	rows, err := view.RetrieveData(viewOverflowedAnnotations.Name)
+	assert.NoError(t, err)
+	if len(rows) == 0 {
+		return 0
+	}
+	return int64(rows[0].Data.(*view.SumData).Value)
+}
@@ -12,9 +12,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
 	semconventions "go.opentelemetry.io/collector/translator/conventions"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
@@ -53,21 +58,28 @@ func makeSQL(attributes map[string]string) (map[string]string, *awsxray.SQLData)
 		return attributes, nil
 	}
 
-	if dbURL == "" {
-		dbURL = "localhost"
-	}
-	url := dbURL + "/" + dbInstance
 	sqlData = awsxray.SQLData{
-		URL:            awsxray.String(url),
-		DatabaseType:   awsxray.String(dbSystem),
-		User:           awsxray.String(dbUser),
-		SanitizedQuery: awsxray.String(dbStatement),
+		DatabaseType: awsxray.String(dbSystem),
+	}
+	if dbURL != "" || dbInstance != "" {
+		if dbURL == "" {
+			dbURL = "localhost"
+		}
+		sqlData.URL = awsxray.String(dbURL + "/" + dbInstance)
+	}
+	// The X-Ray segments don't expect empty fields, leave out the missing attributes.
+	if dbUser != "" {
+		sqlData.User = awsxray.String(dbUser)
+	}
+	if dbStatement != "" {
+		sqlData.SanitizedQuery = awsxray.String(dbStatement)
 	}
 	return filtered, &sqlData
 }
 
 func isSQL(system string) bool {
-	switch system {
+	// The X-Ray SDKs report the database type as the product name, e.g. "PostgreSQL".
+	switch strings.ToLower(system) {
 	case "db2":
 		fallthrough
 	case "derby":
@@ -94,3 +106,44 @@ func isSQL(system string) bool {
 	}
 	return false
 }
+
+func addSQLToSpan(sql *awsxray.SQLData, attrs *pdata.AttributeMap) error {
+	if sql == nil {
+		return nil
+	}
+
+	// https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/c615d2db351929b99e46f7b427f39c12afe15b54/exporter/awsxrayexporter/translator/sql.go#L60
+	if sql.URL != nil {
+		dbURL, dbName, err := splitSQLURL(*sql.URL)
+		if err != nil {
+			return err
+		}
+		attrs.UpsertString(semconventions.AttributeDBConnectionString, dbURL)
+		attrs.UpsertString(semconventions.AttributeDBName, dbName)
+	}
+	// not handling sql.ConnectionString for now because the X-Ray exporter
+	// does not support it
+	addString(sql.DatabaseType, semconventions.AttributeDBSystem, attrs)
+	addString(sql.SanitizedQuery, semconventions.AttributeDBStatement, attrs)
+	addString(sql.User, semconventions.AttributeDBUser, attrs)
+	return nil
+}
+
+// SQL URL is of the format: protocol+transport://host:port/dbName?queryParam
+var reSQLURL = regexp.MustCompile(`^(.+\/\/.+)\/([^\?]+)\??.*$`)
+
+const (
+	dbURLI  = 1
+	dbNameI = 2
+)
+
+func splitSQLURL(rawURL string) (string, string, error) {
+	m := reSQLURL.FindStringSubmatch(rawURL)
+	if len(m) == 0 {
+		return "", "", fmt.Errorf(
+			"failed to parse out the database name in the \"sql.url\" field, rawUrl: %s",
+			rawURL,
+		)
+	}
+	return m[dbURLI], m[dbNameI], nil
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"strings"
@@ -79,3 +79,56 @@ func TestClientSpanWithoutDBurlAttribute(t *testing.T) {
 
 	assert.Equal(t, "localhost/customers", *sqlData.URL)
 }
+
+func TestSQLURL(t *testing.T) {
+	raw := "jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432/ebdb"
+	url, dbName, err := splitSQLURL(raw)
+	assert.NoError(t, err, "should succeed")
+	assert.Equal(t,
+		"jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432",
+		url, "expected url to be the same")
+
+	assert.Equal(t,
+		"ebdb",
+		dbName, "expected db name to be the same")
+}
+func TestSQLURLQueryParameter(t *testing.T) {
+	raw := "jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432/ebdb?myInterceptor=foo"
+	url, dbName, err := splitSQLURL(raw)
+	assert.NoError(t, err, "should succeed")
+	assert.Equal(t,
+		"jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432",
+		url, "expected url to be the same")
+
+	assert.Equal(t,
+		"ebdb",
+		dbName, "expected db name to be the same")
+}
+
+func TestClientSpanWithCapitalizedDBSystem(t *testing.T) {
+	attributes := make(map[string]string)
+	attributes[semconventions.AttributeDBSystem] = "PostgreSQL"
+	attributes[semconventions.AttributeDBName] = "customers"
+	attributes[semconventions.AttributeDBConnectionString] = "jdbc:postgresql://db.example.com:5432"
+
+	filtered, sqlData := makeSQL(attributes)
+	assert.Empty(t, filtered)
+	assert.NotNil(t, sqlData)
+
+	assert.Equal(t, "PostgreSQL", *sqlData.DatabaseType)
+	assert.Equal(t, "jdbc:postgresql://db.example.com:5432/customers", *sqlData.URL)
+	assert.Nil(t, sqlData.User)
+	assert.Nil(t, sqlData.SanitizedQuery)
+}
+
+func TestClientSpanWithoutDBurlAndDBName(t *testing.T) {
+	attributes := make(map[string]string)
+	attributes[semconventions.AttributeDBSystem] = "mysql"
+	attributes[semconventions.AttributeDBStatement] = "SELECT 1"
+
+	_, sqlData := makeSQL(attributes)
+	assert.NotNil(t, sqlData)
+
+	assert.Nil(t, sqlData.URL)
+	assert.Equal(t, "SELECT 1", *sqlData.SanitizedQuery)
+}
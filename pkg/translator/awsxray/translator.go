@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"encoding/hex"
@@ -20,7 +20,7 @@ import (
 	"errors"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
-	"go.opentelemetry.io/collector/translator/conventions"
+	semconventions "go.opentelemetry.io/collector/translator/conventions"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 )
@@ -30,9 +30,6 @@ const (
 	initAttrCapacity = 15
 )
 
-// TODO: It might be nice to consolidate the `fromPdata` in x-ray exporter and
-// `toPdata` in this receiver to a common package later
-
 // ToTraces converts X-Ray segment (and its subsegments) to an OT ResourceSpans.
 func ToTraces(rawSeg []byte) (*pdata.Traces, int, error) {
 	var seg awsxray.Segment
@@ -183,7 +180,7 @@ func populateSpan(
 	addStartTime(seg.StartTime, span)
 	addEndTime(seg.EndTime, span)
 	addBool(seg.InProgress, awsxray.AWSXRayInProgressAttribute, &attrs)
-	addString(seg.User, conventions.AttributeEnduserID, &attrs)
+	addString(seg.User, semconventions.AttributeEnduserID, &attrs)
 
 	addHTTP(seg, span)
 	addCause(seg, span)
@@ -196,7 +193,11 @@ func populateSpan(
 	addBool(seg.Traced, awsxray.AWSXRayTracedAttribute, &attrs)
 
 	addAnnotations(seg.Annotations, &attrs)
-	addMetadata(seg.Metadata, &attrs)
+	metadata, err := addLinks(seg.Metadata, span)
+	if err != nil {
+		return err
+	}
+	addMetadata(metadata, &attrs)
 
 	return nil
 }
@@ -211,7 +212,7 @@ func populateResource(seg *awsxray.Segment, rs *pdata.Resource) {
 	if seg.Service != nil {
 		addString(
 			seg.Service.Version,
-			conventions.AttributeServiceVersion,
+			semconventions.AttributeServiceVersion,
 			&attrs)
 	}
 
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package awsxray
 
 import (
 	"encoding/json"
@@ -24,7 +24,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/consumer/pdata"
-	"go.opentelemetry.io/collector/translator/conventions"
+	semconventions "go.opentelemetry.io/collector/translator/conventions"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 )
@@ -55,17 +55,17 @@ type eventProps struct {
 func TestTranslation(t *testing.T) {
 	var defaultServerSpanAttrs = func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 		attrs := make(map[string]pdata.AttributeValue)
-		attrs[conventions.AttributeHTTPMethod] = pdata.NewAttributeValueString(
+		attrs[semconventions.AttributeHTTPMethod] = pdata.NewAttributeValueString(
 			*seg.HTTP.Request.Method)
-		attrs[conventions.AttributeHTTPClientIP] = pdata.NewAttributeValueString(
+		attrs[semconventions.AttributeHTTPClientIP] = pdata.NewAttributeValueString(
 			*seg.HTTP.Request.ClientIP)
-		attrs[conventions.AttributeHTTPUserAgent] = pdata.NewAttributeValueString(
+		attrs[semconventions.AttributeHTTPUserAgent] = pdata.NewAttributeValueString(
 			*seg.HTTP.Request.UserAgent)
 		attrs[awsxray.AWSXRayXForwardedForAttribute] = pdata.NewAttributeValueBool(
 			*seg.HTTP.Request.XForwardedFor)
-		attrs[conventions.AttributeHTTPStatusCode] = pdata.NewAttributeValueInt(
+		attrs[semconventions.AttributeHTTPStatusCode] = pdata.NewAttributeValueInt(
 			*seg.HTTP.Response.Status)
-		attrs[conventions.AttributeHTTPURL] = pdata.NewAttributeValueString(
+		attrs[semconventions.AttributeHTTPURL] = pdata.NewAttributeValueString(
 			*seg.HTTP.Request.URL)
 
 		return attrs
@@ -85,20 +85,20 @@ func TestTranslation(t *testing.T) {
 	}{
 		{
 			testCase:   "TranslateInstrumentedServerSegment",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "serverSample.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "serverSample.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeCloudProvider] = pdata.NewAttributeValueString(conventions.AttributeCloudProviderAWS)
-				attrs[conventions.AttributeTelemetrySDKVersion] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeCloudProvider] = pdata.NewAttributeValueString(semconventions.AttributeCloudProviderAWS)
+				attrs[semconventions.AttributeTelemetrySDKVersion] = pdata.NewAttributeValueString(
 					*seg.AWS.XRay.SDKVersion)
-				attrs[conventions.AttributeTelemetrySDKName] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeTelemetrySDKName] = pdata.NewAttributeValueString(
 					*seg.AWS.XRay.SDK)
-				attrs[conventions.AttributeTelemetrySDKLanguage] = pdata.NewAttributeValueString("Go")
-				attrs[conventions.AttributeK8sCluster] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeTelemetrySDKLanguage] = pdata.NewAttributeValueString("Go")
+				attrs[semconventions.AttributeK8sCluster] = pdata.NewAttributeValueString(
 					*seg.AWS.EKS.ClusterName)
-				attrs[conventions.AttributeK8sPod] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeK8sPod] = pdata.NewAttributeValueString(
 					*seg.AWS.EKS.Pod)
-				attrs[conventions.AttributeContainerID] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeContainerID] = pdata.NewAttributeValueString(
 					*seg.AWS.EKS.ContainerID)
 				return attrs
 			},
@@ -131,21 +131,21 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "TranslateInstrumentedClientSegment",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "ddbSample.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "ddbSample.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeCloudProvider] = pdata.NewAttributeValueString(conventions.AttributeCloudProviderAWS)
-				attrs[conventions.AttributeTelemetrySDKVersion] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeCloudProvider] = pdata.NewAttributeValueString(semconventions.AttributeCloudProviderAWS)
+				attrs[semconventions.AttributeTelemetrySDKVersion] = pdata.NewAttributeValueString(
 					*seg.AWS.XRay.SDKVersion)
-				attrs[conventions.AttributeTelemetrySDKName] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeTelemetrySDKName] = pdata.NewAttributeValueString(
 					*seg.AWS.XRay.SDK)
-				attrs[conventions.AttributeTelemetrySDKLanguage] = pdata.NewAttributeValueString("java")
+				attrs[semconventions.AttributeTelemetrySDKLanguage] = pdata.NewAttributeValueString("Go")
 
 				return attrs
 			},
 			propsPerSpan: func(testCase string, t *testing.T, seg *awsxray.Segment) []perSpanProperties {
 				rootSpanAttrs := make(map[string]pdata.AttributeValue)
-				rootSpanAttrs[conventions.AttributeEnduserID] = pdata.NewAttributeValueString(*seg.User)
+				rootSpanAttrs[semconventions.AttributeEnduserID] = pdata.NewAttributeValueString(*seg.User)
 				rootSpanEvts := initExceptionEvents(seg)
 				assert.Len(t, rootSpanEvts, 1, testCase+": rootSpanEvts has incorrect size")
 				rootSpan := perSpanProperties{
@@ -197,9 +197,9 @@ func TestTranslation(t *testing.T) {
 				childSpan7318Attrs := make(map[string]pdata.AttributeValue)
 				childSpan7318Attrs[awsxray.AWSServiceAttribute] = pdata.NewAttributeValueString(
 					*subseg7318.Name)
-				childSpan7318Attrs[conventions.AttributeHTTPStatusCode] = pdata.NewAttributeValueInt(
+				childSpan7318Attrs[semconventions.AttributeHTTPStatusCode] = pdata.NewAttributeValueInt(
 					*subseg7318.HTTP.Response.Status)
-				childSpan7318Attrs[conventions.AttributeHTTPResponseContentLength] = pdata.NewAttributeValueInt(
+				childSpan7318Attrs[semconventions.AttributeHTTPResponseContentLength] = pdata.NewAttributeValueInt(
 					*subseg7318.HTTP.Response.ContentLength)
 				childSpan7318Attrs[awsxray.AWSOperationAttribute] = pdata.NewAttributeValueString(
 					*subseg7318.AWS.Operation)
@@ -403,9 +403,9 @@ func TestTranslation(t *testing.T) {
 				childSpan7163Attrs := make(map[string]pdata.AttributeValue)
 				childSpan7163Attrs[awsxray.AWSServiceAttribute] = pdata.NewAttributeValueString(
 					*subseg7163.Name)
-				childSpan7163Attrs[conventions.AttributeHTTPStatusCode] = pdata.NewAttributeValueInt(
+				childSpan7163Attrs[semconventions.AttributeHTTPStatusCode] = pdata.NewAttributeValueInt(
 					*subseg7163.HTTP.Response.Status)
-				childSpan7163Attrs[conventions.AttributeHTTPResponseContentLength] = pdata.NewAttributeValueInt(
+				childSpan7163Attrs[semconventions.AttributeHTTPResponseContentLength] = pdata.NewAttributeValueInt(
 					*subseg7163.HTTP.Response.ContentLength)
 				childSpan7163Attrs[awsxray.AWSOperationAttribute] = pdata.NewAttributeValueString(
 					*subseg7163.AWS.Operation)
@@ -552,10 +552,10 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "[aws] TranslateMissingAWSFieldSegment",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "awsMissingAwsField.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "awsMissingAwsField.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeCloudProvider] = pdata.NewAttributeValueString("unknown")
+				attrs[semconventions.AttributeCloudProvider] = pdata.NewAttributeValueString("unknown")
 				return attrs
 			},
 			propsPerSpan: func(_ string, _ *testing.T, seg *awsxray.Segment) []perSpanProperties {
@@ -587,37 +587,37 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "[aws] TranslateEC2AWSFieldsSegment",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "awsValidAwsFields.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "awsValidAwsFields.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeCloudProvider] = pdata.NewAttributeValueString(conventions.AttributeCloudProviderAWS)
-				attrs[conventions.AttributeCloudAccount] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeCloudProvider] = pdata.NewAttributeValueString(semconventions.AttributeCloudProviderAWS)
+				attrs[semconventions.AttributeCloudAccount] = pdata.NewAttributeValueString(
 					*seg.AWS.AccountID)
-				attrs[conventions.AttributeCloudZone] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeCloudZone] = pdata.NewAttributeValueString(
 					*seg.AWS.EC2.AvailabilityZone)
-				attrs[conventions.AttributeHostID] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeHostID] = pdata.NewAttributeValueString(
 					*seg.AWS.EC2.InstanceID)
-				attrs[conventions.AttributeHostType] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeHostType] = pdata.NewAttributeValueString(
 					*seg.AWS.EC2.InstanceSize)
-				attrs[conventions.AttributeHostImageID] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeHostImageID] = pdata.NewAttributeValueString(
 					*seg.AWS.EC2.AmiID)
-				attrs[conventions.AttributeContainerName] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeContainerName] = pdata.NewAttributeValueString(
 					*seg.AWS.ECS.ContainerName)
-				attrs[conventions.AttributeContainerID] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeContainerID] = pdata.NewAttributeValueString(
 					*seg.AWS.ECS.ContainerID)
-				attrs[conventions.AttributeCloudZone] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeCloudZone] = pdata.NewAttributeValueString(
 					*seg.AWS.ECS.AvailabilityZone)
-				attrs[conventions.AttributeServiceNamespace] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeServiceNamespace] = pdata.NewAttributeValueString(
 					*seg.AWS.Beanstalk.Environment)
-				attrs[conventions.AttributeServiceInstance] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeServiceInstance] = pdata.NewAttributeValueString(
 					"32")
-				attrs[conventions.AttributeServiceVersion] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeServiceVersion] = pdata.NewAttributeValueString(
 					*seg.AWS.Beanstalk.VersionLabel)
-				attrs[conventions.AttributeTelemetrySDKVersion] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeTelemetrySDKVersion] = pdata.NewAttributeValueString(
 					*seg.AWS.XRay.SDKVersion)
-				attrs[conventions.AttributeTelemetrySDKName] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeTelemetrySDKName] = pdata.NewAttributeValueString(
 					*seg.AWS.XRay.SDK)
-				attrs[conventions.AttributeTelemetrySDKLanguage] = pdata.NewAttributeValueString("Go")
+				attrs[semconventions.AttributeTelemetrySDKLanguage] = pdata.NewAttributeValueString("Go")
 				return attrs
 			},
 			propsPerSpan: func(_ string, _ *testing.T, seg *awsxray.Segment) []perSpanProperties {
@@ -651,10 +651,10 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "TranslateCauseIsExceptionId",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "minCauseIsExceptionId.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "minCauseIsExceptionId.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeCloudProvider] = pdata.NewAttributeValueString("unknown")
+				attrs[semconventions.AttributeCloudProvider] = pdata.NewAttributeValueString("unknown")
 				return attrs
 			},
 			propsPerSpan: func(_ string, _ *testing.T, seg *awsxray.Segment) []perSpanProperties {
@@ -686,7 +686,7 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "TranslateInvalidNamespace",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "invalidNamespace.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "invalidNamespace.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				return nil
 			},
@@ -704,21 +704,21 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "TranslateIndepSubsegment",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "indepSubsegment.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "indepSubsegment.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeCloudProvider] = pdata.NewAttributeValueString("unknown")
+				attrs[semconventions.AttributeCloudProvider] = pdata.NewAttributeValueString("unknown")
 				return attrs
 			},
 			propsPerSpan: func(_ string, _ *testing.T, seg *awsxray.Segment) []perSpanProperties {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeHTTPMethod] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeHTTPMethod] = pdata.NewAttributeValueString(
 					*seg.HTTP.Request.Method)
-				attrs[conventions.AttributeHTTPStatusCode] = pdata.NewAttributeValueInt(
+				attrs[semconventions.AttributeHTTPStatusCode] = pdata.NewAttributeValueInt(
 					*seg.HTTP.Response.Status)
-				attrs[conventions.AttributeHTTPURL] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeHTTPURL] = pdata.NewAttributeValueString(
 					*seg.HTTP.Request.URL)
-				attrs[conventions.AttributeHTTPResponseContentLength] = pdata.NewAttributeValueInt(
+				attrs[semconventions.AttributeHTTPResponseContentLength] = pdata.NewAttributeValueInt(
 					*seg.HTTP.Response.ContentLength)
 				attrs[awsxray.AWSXRayTracedAttribute] = pdata.NewAttributeValueBool(true)
 				res := perSpanProperties{
@@ -749,22 +749,22 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "TranslateSql",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "indepSubsegmentWithSql.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "indepSubsegmentWithSql.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeCloudProvider] = pdata.NewAttributeValueString("unknown")
+				attrs[semconventions.AttributeCloudProvider] = pdata.NewAttributeValueString("unknown")
 				return attrs
 			},
 			propsPerSpan: func(_ string, _ *testing.T, seg *awsxray.Segment) []perSpanProperties {
 				attrs := make(map[string]pdata.AttributeValue)
-				attrs[conventions.AttributeDBConnectionString] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeDBConnectionString] = pdata.NewAttributeValueString(
 					"jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432")
-				attrs[conventions.AttributeDBName] = pdata.NewAttributeValueString("ebdb")
-				attrs[conventions.AttributeDBSystem] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeDBName] = pdata.NewAttributeValueString("ebdb")
+				attrs[semconventions.AttributeDBSystem] = pdata.NewAttributeValueString(
 					*seg.SQL.DatabaseType)
-				attrs[conventions.AttributeDBStatement] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeDBStatement] = pdata.NewAttributeValueString(
 					*seg.SQL.SanitizedQuery)
-				attrs[conventions.AttributeDBUser] = pdata.NewAttributeValueString(
+				attrs[semconventions.AttributeDBUser] = pdata.NewAttributeValueString(
 					*seg.SQL.User)
 				res := perSpanProperties{
 					traceID:      *seg.TraceID,
@@ -794,7 +794,7 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "TranslateInvalidSqlUrl",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "indepSubsegmentWithInvalidSqlUrl.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "indepSubsegmentWithInvalidSqlUrl.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				return nil
 			},
@@ -815,7 +815,7 @@ func TestTranslation(t *testing.T) {
 		{
 			testCase:                  "TranslateJsonUnmarshallFailed",
 			expectedUnmarshallFailure: true,
-			samplePath:                path.Join("../../../../internal/awsxray", "testdata", "minCauseIsInvalid.txt"),
+			samplePath:                path.Join("../../../internal/awsxray", "testdata", "minCauseIsInvalid.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				return nil
 			},
@@ -835,7 +835,7 @@ func TestTranslation(t *testing.T) {
 		},
 		{
 			testCase:   "TranslateRootSegValidationFailed",
-			samplePath: path.Join("../../../../internal/awsxray", "testdata", "segmentValidationFailed.txt"),
+			samplePath: path.Join("../../../internal/awsxray", "testdata", "segmentValidationFailed.txt"),
 			expectedResourceAttrs: func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 				return nil
 			},
@@ -891,12 +891,12 @@ func initExceptionEvents(expectedSeg *awsxray.Segment) []eventProps {
 		attrs[awsxray.AWSXrayExceptionIDAttribute] = pdata.NewAttributeValueString(
 			*excp.ID)
 		if excp.Message != nil {
-			attrs[conventions.AttributeExceptionMessage] = pdata.NewAttributeValueString(
+			attrs[semconventions.AttributeExceptionMessage] = pdata.NewAttributeValueString(
 				*excp.Message)
 		}
 
 		if excp.Type != nil {
-			attrs[conventions.AttributeExceptionType] = pdata.NewAttributeValueString(
+			attrs[semconventions.AttributeExceptionType] = pdata.NewAttributeValueString(
 				*excp.Type)
 		}
 
@@ -921,11 +921,11 @@ func initExceptionEvents(expectedSeg *awsxray.Segment) []eventProps {
 		}
 
 		if len(excp.Stack) > 0 {
-			attrs[conventions.AttributeExceptionStacktrace] = pdata.NewAttributeValueString(
+			attrs[semconventions.AttributeExceptionStacktrace] = pdata.NewAttributeValueString(
 				convertStackFramesToStackTraceStr(excp))
 		}
 		res = append(res, eventProps{
-			name:  conventions.AttributeExceptionEventName,
+			name:  semconventions.AttributeExceptionEventName,
 			attrs: attrs,
 		})
 	}
@@ -997,12 +997,14 @@ func initResourceSpans(expectedSeg *awsxray.Segment,
 // abbrev. as exp) and actual ResourceSpans (abbrev. as act):
 // 1. clears the resource attributes on both exp and act, after verifying
 // .  both sets are the same.
-// 2. clears the span attributes of all the
-//    spans on both exp and act, after going through all the spans
+//  2. clears the span attributes of all the
+//     spans on both exp and act, after going through all the spans
+//
 // .  on both exp and act and verify that all the attributes match.
-// 3. similarly, for all the events and their attributes within a span,
-//    this function performs the same equality verification, then clears
-//    up all the attribute.
+//  3. similarly, for all the events and their attributes within a span,
+//     this function performs the same equality verification, then clears
+//     up all the attribute.
+//
 // The reason for doing so is just to be able to use deep equal via assert.Equal()
 func compare2ResourceSpans(t *testing.T, testCase string, exp, act *pdata.ResourceSpans) {
 	assert.Equal(t, exp.InstrumentationLibrarySpans().Len(),
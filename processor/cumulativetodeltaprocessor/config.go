@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// MatchType is the type of matching used for the metric names: <strict|regexp>.
+type MatchType string
+
+const (
+	// StrictMatchType matches the metric names exactly.
+	StrictMatchType MatchType = "strict"
+
+	// RegexpMatchType matches the metric names against regular expressions.
+	RegexpMatchType MatchType = "regexp"
+)
+
+// InitialValue determines what happens with the first observation of a metric stream,
+// for which there's no previous value to compute the delta from: <drop|keep>.
+type InitialValue string
+
+const (
+	// DropInitialValue drops the first observation, using it only as the baseline for the next ones.
+	DropInitialValue InitialValue = "drop"
+
+	// KeepInitialValue emits the first observation as-is, as the delta since the start time of the stream.
+	KeepInitialValue InitialValue = "keep"
+)
+
+// MatchMetrics defines a set of metrics, by their names.
+type MatchMetrics struct {
+	// MatchType determines how the metric names are matched: <strict|regexp>. Defaults to strict.
+	MatchType MatchType `mapstructure:"match_type"`
+
+	// Metrics is the list of metric names, or of regular expressions for the regexp match type.
+	Metrics []string `mapstructure:"metrics"`
+}
+
+// Config defines the configuration for the processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Include limits the conversion to the matching metrics. When empty, all the cumulative sums are converted.
+	Include MatchMetrics `mapstructure:"include"`
+
+	// Exclude prevents the matching metrics from being converted, even when they are included.
+	Exclude MatchMetrics `mapstructure:"exclude"`
+
+	// InitialValue determines what happens with the first observation of a metric stream: <drop|keep>.
+	// Defaults to drop.
+	InitialValue InitialValue `mapstructure:"initial_value"`
+
+	// MaxStreams is the maximum number of metric streams, that is, of unique combinations of metric name,
+	// resource and labels, that are kept track of. The least recently seen streams are dropped first. Defaults to 10000.
+	MaxStreams int `mapstructure:"max_streams"`
+
+	// MaxStaleness is the time after which a metric stream that hasn't been seen is forgotten, its next observation
+	// being handled as the first one. Zero disables the expiry. Defaults to 5m.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+}
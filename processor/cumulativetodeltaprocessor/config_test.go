@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), config.Processors["cumulativetodelta"])
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "cumulativetodelta",
+				NameVal: "cumulativetodelta/custom",
+			},
+			Include: MatchMetrics{
+				MatchType: RegexpMatchType,
+				Metrics:   []string{`^system\.network\..*`, `^http\.requests$`},
+			},
+			Exclude: MatchMetrics{
+				Metrics: []string{"system.network.dropped"},
+			},
+			InitialValue: KeepInitialValue,
+			MaxStreams:   500,
+			MaxStaleness: time.Minute,
+		},
+		config.Processors["cumulativetodelta/custom"])
+}
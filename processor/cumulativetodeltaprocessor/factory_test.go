@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	assert.Equal(t, DropInitialValue, cfg.InitialValue)
+	assert.Equal(t, defaultMaxStreams, cfg.MaxStreams)
+	assert.Equal(t, defaultMaxStaleness, cfg.MaxStaleness)
+}
+
+func TestCreateMetricsProcessor(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), params, factory.CreateDefaultConfig(), consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, mp)
+	assert.True(t, mp.GetCapabilities().MutatesConsumedData)
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), params, factory.CreateDefaultConfig(), consumertest.NewTracesNop())
+	assert.Error(t, err)
+	assert.Nil(t, tp)
+}
+
+func TestCreateMetricsProcessorWithInvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{"no max streams", func(cfg *Config) { cfg.MaxStreams = 0 }},
+		{"negative max staleness", func(cfg *Config) { cfg.MaxStaleness = -1 }},
+		{"invalid initial value", func(cfg *Config) { cfg.InitialValue = "zero" }},
+		{"invalid match type", func(cfg *Config) { cfg.Include = MatchMetrics{MatchType: "glob", Metrics: []string{"*"}} }},
+		{"invalid regexp", func(cfg *Config) { cfg.Exclude = MatchMetrics{MatchType: RegexpMatchType, Metrics: []string{"("}} }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
+			assert.Error(t, err)
+			assert.Nil(t, mp)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// nameFilter matches metric names against a list of exact names or of regular expressions
+type nameFilter struct {
+	names   map[string]struct{}
+	regexps []*regexp.Regexp
+}
+
+// newNameFilter creates a filter for the given metrics, returning nil when there are no metric names to match
+func newNameFilter(mm MatchMetrics) (*nameFilter, error) {
+	if len(mm.Metrics) == 0 {
+		return nil, nil
+	}
+
+	f := &nameFilter{}
+	switch mm.MatchType {
+	case StrictMatchType, "":
+		f.names = make(map[string]struct{}, len(mm.Metrics))
+		for _, name := range mm.Metrics {
+			f.names[name] = struct{}{}
+		}
+	case RegexpMatchType:
+		for _, expr := range mm.Metrics {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp %q: %w", expr, err)
+			}
+			f.regexps = append(f.regexps, re)
+		}
+	default:
+		return nil, fmt.Errorf("invalid match_type %q, must be one of %q or %q", mm.MatchType, StrictMatchType, RegexpMatchType)
+	}
+
+	return f, nil
+}
+
+func (f *nameFilter) matches(name string) bool {
+	if _, ok := f.names[name]; ok {
+		return true
+	}
+	for _, re := range f.regexps {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
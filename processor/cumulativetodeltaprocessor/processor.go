@@ -0,0 +1,301 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// streamKeySeparator separates the keys and values within the sections of the stream keys
+	streamKeySeparator = string(byte(0))
+
+	// streamKeySectionSeparator separates the metric name, the resource attributes and the labels in the stream keys
+	streamKeySectionSeparator = string(byte(1))
+)
+
+var (
+	errInvalidMaxStreams   = errors.New("max_streams must be positive")
+	errInvalidMaxStaleness = errors.New("max_staleness can't be negative")
+)
+
+// streamState is the last observation of a metric stream
+type streamState struct {
+	startTime   pdata.TimestampUnixNano
+	timestamp   pdata.TimestampUnixNano
+	intValue    int64
+	doubleValue float64
+
+	// lastSeen is the processor's time of the observation, used for the expiry of the stale streams
+	lastSeen time.Time
+}
+
+type cumulativeToDeltaProcessor struct {
+	logger       *zap.Logger
+	include      *nameFilter
+	exclude      *nameFilter
+	initialValue InitialValue
+	maxStaleness time.Duration
+	now          func() time.Time
+
+	// the lock serializes the batches, as each conversion depends on the observations left by the previous ones
+	lock sync.Mutex
+
+	// streams holds the last observation of each metric stream, keyed by the stream identity,
+	// with the least recently seen streams being the first ones to be evicted
+	streams *lru.Cache
+}
+
+func newCumulativeToDeltaProcessor(logger *zap.Logger, config *Config) (*cumulativeToDeltaProcessor, error) {
+	if config.MaxStreams <= 0 {
+		return nil, errInvalidMaxStreams
+	}
+	if config.MaxStaleness < 0 {
+		return nil, errInvalidMaxStaleness
+	}
+
+	initialValue := config.InitialValue
+	switch initialValue {
+	case "":
+		initialValue = DropInitialValue
+	case DropInitialValue, KeepInitialValue:
+	default:
+		return nil, fmt.Errorf("invalid initial_value %q, must be one of %q or %q", initialValue, DropInitialValue, KeepInitialValue)
+	}
+
+	include, err := newNameFilter(config.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include: %w", err)
+	}
+	exclude, err := newNameFilter(config.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude: %w", err)
+	}
+
+	streams, err := lru.New(config.MaxStreams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cumulativeToDeltaProcessor{
+		logger:       logger,
+		include:      include,
+		exclude:      exclude,
+		initialValue: initialValue,
+		maxStaleness: config.MaxStaleness,
+		now:          time.Now,
+		streams:      streams,
+	}, nil
+}
+
+// ProcessMetrics converts the matching cumulative sums to delta sums. The metrics left without any data point,
+// as their first observations were dropped, are removed.
+func (ctdp *cumulativeToDeltaProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	ctdp.lock.Lock()
+	defer ctdp.lock.Unlock()
+
+	now := ctdp.now()
+	ctdp.expireStaleStreams(now)
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceKey := attributesKey(rm.Resource().Attributes())
+
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+
+			kept := pdata.NewMetricSlice()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if ctdp.shouldConvert(metric) {
+					metricKey := metric.Name() + streamKeySectionSeparator + resourceKey
+					if ctdp.convert(metric, metricKey, now) == 0 {
+						continue
+					}
+				}
+				kept.Append(metric)
+			}
+
+			metrics.Resize(0)
+			kept.MoveAndAppendTo(metrics)
+		}
+	}
+
+	return md, nil
+}
+
+// shouldConvert returns whether the given metric is a cumulative sum which is included and not excluded
+func (ctdp *cumulativeToDeltaProcessor) shouldConvert(metric pdata.Metric) bool {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		if metric.IntSum().AggregationTemporality() != pdata.AggregationTemporalityCumulative {
+			return false
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if metric.DoubleSum().AggregationTemporality() != pdata.AggregationTemporalityCumulative {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if ctdp.include != nil && !ctdp.include.matches(metric.Name()) {
+		return false
+	}
+	return ctdp.exclude == nil || !ctdp.exclude.matches(metric.Name())
+}
+
+// convert converts the given cumulative sum to a delta sum, returning the number of data points left
+func (ctdp *cumulativeToDeltaProcessor) convert(metric pdata.Metric, metricKey string, now time.Time) int {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		sum := metric.IntSum()
+		dps := sum.DataPoints()
+
+		kept := pdata.NewIntDataPointSlice()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			key := metricKey + streamKeySectionSeparator + labelsKey(dp.LabelsMap())
+
+			prev, found := ctdp.swapState(key, &streamState{
+				startTime: dp.StartTime(),
+				timestamp: dp.Timestamp(),
+				intValue:  dp.Value(),
+				lastSeen:  now,
+			})
+			switch {
+			case !found:
+				if ctdp.initialValue == DropInitialValue {
+					continue
+				}
+			case isReset(prev, dp.StartTime(), sum.IsMonotonic() && dp.Value() < prev.intValue):
+				// the value is the delta since the reset already
+			default:
+				dp.SetValue(dp.Value() - prev.intValue)
+				dp.SetStartTime(prev.timestamp)
+			}
+			kept.Append(dp)
+		}
+
+		dps.Resize(0)
+		kept.MoveAndAppendTo(dps)
+		sum.SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+		return dps.Len()
+
+	case pdata.MetricDataTypeDoubleSum:
+		sum := metric.DoubleSum()
+		dps := sum.DataPoints()
+
+		kept := pdata.NewDoubleDataPointSlice()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			key := metricKey + streamKeySectionSeparator + labelsKey(dp.LabelsMap())
+
+			prev, found := ctdp.swapState(key, &streamState{
+				startTime:   dp.StartTime(),
+				timestamp:   dp.Timestamp(),
+				doubleValue: dp.Value(),
+				lastSeen:    now,
+			})
+			switch {
+			case !found:
+				if ctdp.initialValue == DropInitialValue {
+					continue
+				}
+			case isReset(prev, dp.StartTime(), sum.IsMonotonic() && dp.Value() < prev.doubleValue):
+				// the value is the delta since the reset already
+			default:
+				dp.SetValue(dp.Value() - prev.doubleValue)
+				dp.SetStartTime(prev.timestamp)
+			}
+			kept.Append(dp)
+		}
+
+		dps.Resize(0)
+		kept.MoveAndAppendTo(dps)
+		sum.SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+		return dps.Len()
+	}
+
+	return 0
+}
+
+// swapState records the given observation for the stream with the given key, returning the previous one, if any
+func (ctdp *cumulativeToDeltaProcessor) swapState(key string, state *streamState) (*streamState, bool) {
+	prev, found := ctdp.streams.Get(key)
+	if evicted := ctdp.streams.Add(key, state); evicted {
+		ctdp.logger.Debug("metric stream evicted, its next observation will be handled as the first one: " +
+			"in order to avoid this in the future, adjust the maximum number of streams")
+	}
+	if !found {
+		return nil, false
+	}
+	return prev.(*streamState), true
+}
+
+// isReset returns whether the stream has been reset since its previous observation, either because
+// its value decreased or because it has a different start time
+func isReset(prev *streamState, startTime pdata.TimestampUnixNano, decreased bool) bool {
+	return decreased || (startTime != 0 && startTime != prev.startTime)
+}
+
+// expireStaleStreams forgets the streams that haven't been seen for longer than the max staleness. As the streams
+// are updated on each observation, the stale ones are always the least recently used.
+func (ctdp *cumulativeToDeltaProcessor) expireStaleStreams(now time.Time) {
+	if ctdp.maxStaleness == 0 {
+		return
+	}
+
+	for {
+		_, oldest, ok := ctdp.streams.GetOldest()
+		if !ok || now.Sub(oldest.(*streamState).lastSeen) <= ctdp.maxStaleness {
+			return
+		}
+		ctdp.streams.RemoveOldest()
+	}
+}
+
+// attributesKey returns a key identifying the given attributes, regardless of their order
+func attributesKey(attrs pdata.AttributeMap) string {
+	kvs := make([]string, 0, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		kvs = append(kvs, k+streamKeySeparator+tracetranslator.AttributeValueToString(v, false))
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, streamKeySeparator)
+}
+
+// labelsKey returns a key identifying the given labels, regardless of their order
+func labelsKey(labels pdata.StringMap) string {
+	kvs := make([]string, 0, labels.Len())
+	labels.ForEach(func(k string, v string) {
+		kvs = append(kvs, k+streamKeySeparator+v)
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, streamKeySeparator)
+}
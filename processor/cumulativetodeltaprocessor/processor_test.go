@@ -0,0 +1,324 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// testPoint is a cumulative data point, with start and end timestamps in seconds
+type testPoint struct {
+	labels    map[string]string
+	startTime int64
+	timestamp int64
+	value     float64
+}
+
+func TestConvertIntSum(t *testing.T) {
+	// prepare
+	ctdp := newTestProcessor(t, createDefaultConfig().(*Config))
+
+	// test
+	first := process(t, ctdp, intSumMetrics("requests", true, testPoint{startTime: 1, timestamp: 10, value: 10}))
+	second := process(t, ctdp, intSumMetrics("requests", true, testPoint{startTime: 1, timestamp: 20, value: 15}))
+	reset := process(t, ctdp, intSumMetrics("requests", true, testPoint{startTime: 1, timestamp: 30, value: 12}))
+	afterReset := process(t, ctdp, intSumMetrics("requests", true, testPoint{startTime: 1, timestamp: 40, value: 20}))
+
+	// verify
+	// the first observation is only the baseline, leaving the metric without data points
+	assert.Equal(t, 0, first.MetricCount())
+
+	metric := singleMetric(t, second)
+	require.Equal(t, pdata.MetricDataTypeIntSum, metric.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityDelta, metric.IntSum().AggregationTemporality())
+	assert.True(t, metric.IntSum().IsMonotonic())
+	require.Equal(t, 1, metric.IntSum().DataPoints().Len())
+	dp := metric.IntSum().DataPoints().At(0)
+	assert.EqualValues(t, 5, dp.Value())
+	assert.Equal(t, seconds(10), dp.StartTime())
+	assert.Equal(t, seconds(20), dp.Timestamp())
+
+	// on a counter reset, the new value is the delta and the new baseline
+	dp = singleMetric(t, reset).IntSum().DataPoints().At(0)
+	assert.EqualValues(t, 12, dp.Value())
+	assert.Equal(t, seconds(1), dp.StartTime())
+
+	dp = singleMetric(t, afterReset).IntSum().DataPoints().At(0)
+	assert.EqualValues(t, 8, dp.Value())
+	assert.Equal(t, seconds(30), dp.StartTime())
+}
+
+func TestConvertDoubleSumKeepingInitialValue(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.InitialValue = KeepInitialValue
+	ctdp := newTestProcessor(t, cfg)
+
+	// test
+	first := process(t, ctdp, doubleSumMetrics("bytes", true, testPoint{startTime: 1, timestamp: 10, value: 1.5}))
+	second := process(t, ctdp, doubleSumMetrics("bytes", true, testPoint{startTime: 1, timestamp: 20, value: 4}))
+
+	// verify
+	metric := singleMetric(t, first)
+	require.Equal(t, pdata.MetricDataTypeDoubleSum, metric.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityDelta, metric.DoubleSum().AggregationTemporality())
+	dp := metric.DoubleSum().DataPoints().At(0)
+	assert.Equal(t, 1.5, dp.Value())
+	assert.Equal(t, seconds(1), dp.StartTime())
+
+	dp = singleMetric(t, second).DoubleSum().DataPoints().At(0)
+	assert.Equal(t, 2.5, dp.Value())
+	assert.Equal(t, seconds(10), dp.StartTime())
+}
+
+func TestResets(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		monotonic bool
+		second    testPoint
+		want      float64
+		wantStart int64
+	}{
+		{
+			name:      "decreasing monotonic sum",
+			monotonic: true,
+			second:    testPoint{startTime: 1, timestamp: 20, value: 3},
+			want:      3,
+			wantStart: 1,
+		},
+		{
+			name:      "decreasing non-monotonic sum",
+			monotonic: false,
+			second:    testPoint{startTime: 1, timestamp: 20, value: 3},
+			want:      -7,
+			wantStart: 10,
+		},
+		{
+			name:      "new start time",
+			monotonic: true,
+			second:    testPoint{startTime: 15, timestamp: 20, value: 30},
+			want:      30,
+			wantStart: 15,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// prepare
+			ctdp := newTestProcessor(t, createDefaultConfig().(*Config))
+			process(t, ctdp, doubleSumMetrics("sum", tc.monotonic, testPoint{startTime: 1, timestamp: 10, value: 10}))
+
+			// test
+			md := process(t, ctdp, doubleSumMetrics("sum", tc.monotonic, tc.second))
+
+			// verify
+			dp := singleMetric(t, md).DoubleSum().DataPoints().At(0)
+			assert.Equal(t, tc.want, dp.Value())
+			assert.Equal(t, seconds(tc.wantStart), dp.StartTime())
+		})
+	}
+}
+
+func TestStreamsAreIdentifiedByNameResourceAndLabels(t *testing.T) {
+	// prepare
+	ctdp := newTestProcessor(t, createDefaultConfig().(*Config))
+
+	batch := func(value float64) pdata.Metrics {
+		md := pdata.NewMetrics()
+		for _, host := range []string{"host-a", "host-b"} {
+			for _, name := range []string{"requests", "errors"} {
+				rm := doubleSumMetrics(name, true,
+					testPoint{labels: map[string]string{"path": "/a", "method": "GET"}, startTime: 1, timestamp: 10, value: value},
+					testPoint{labels: map[string]string{"path": "/b", "method": "GET"}, startTime: 1, timestamp: 10, value: 2 * value},
+				).ResourceMetrics().At(0)
+				rm.Resource().Attributes().InsertString("host", host)
+				md.ResourceMetrics().Append(rm)
+			}
+		}
+		return md
+	}
+	process(t, ctdp, batch(1))
+
+	// test
+	md := process(t, ctdp, batch(3))
+
+	// verify
+	// each of the 8 streams has its own baseline
+	assert.Equal(t, 8, ctdp.streams.Len())
+	require.Equal(t, 4, md.ResourceMetrics().Len())
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		dps := md.ResourceMetrics().At(i).InstrumentationLibraryMetrics().At(0).Metrics().At(0).DoubleSum().DataPoints()
+		require.Equal(t, 2, dps.Len())
+		assert.Equal(t, 2.0, dps.At(0).Value())
+		assert.Equal(t, 4.0, dps.At(1).Value())
+	}
+}
+
+func TestIncludeAndExclude(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.InitialValue = KeepInitialValue
+	cfg.Include = MatchMetrics{MatchType: RegexpMatchType, Metrics: []string{`^system\.`}}
+	cfg.Exclude = MatchMetrics{Metrics: []string{"system.excluded"}}
+	ctdp := newTestProcessor(t, cfg)
+
+	md := pdata.NewMetrics()
+	for _, name := range []string{"system.included", "system.excluded", "other"} {
+		md.ResourceMetrics().Append(doubleSumMetrics(name, true, testPoint{value: 1}).ResourceMetrics().At(0))
+	}
+
+	// a delta sum and a gauge are never converted
+	deltaSum := doubleSumMetrics("system.delta", true, testPoint{value: 1})
+	deltaSum.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	md.ResourceMetrics().Append(deltaSum.ResourceMetrics().At(0))
+
+	gauge := doubleSumMetrics("system.gauge", true, testPoint{value: 1})
+	gauge.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).SetDataType(pdata.MetricDataTypeDoubleGauge)
+	md.ResourceMetrics().Append(gauge.ResourceMetrics().At(0))
+
+	// test
+	md = process(t, ctdp, md)
+
+	// verify
+	temporalities := map[string]pdata.AggregationTemporality{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		metric := md.ResourceMetrics().At(i).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+		if metric.DataType() == pdata.MetricDataTypeDoubleSum {
+			temporalities[metric.Name()] = metric.DoubleSum().AggregationTemporality()
+		}
+	}
+	assert.Equal(t, map[string]pdata.AggregationTemporality{
+		"system.included": pdata.AggregationTemporalityDelta,
+		"system.excluded": pdata.AggregationTemporalityCumulative,
+		"other":           pdata.AggregationTemporalityCumulative,
+		"system.delta":    pdata.AggregationTemporalityDelta,
+	}, temporalities)
+	assert.Equal(t, 1, ctdp.streams.Len())
+}
+
+func TestStaleStreamsAreExpired(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxStaleness = time.Minute
+	ctdp := newTestProcessor(t, cfg)
+
+	now := time.Unix(1000, 0)
+	ctdp.now = func() time.Time { return now }
+
+	process(t, ctdp, intSumMetrics("stale", true, testPoint{startTime: 1, timestamp: 10, value: 10}))
+	process(t, ctdp, intSumMetrics("fresh", true, testPoint{startTime: 1, timestamp: 10, value: 10}))
+
+	// test
+	now = now.Add(50 * time.Second)
+	process(t, ctdp, intSumMetrics("fresh", true, testPoint{startTime: 1, timestamp: 20, value: 20}))
+	now = now.Add(20 * time.Second)
+	md := process(t, ctdp, intSumMetrics("stale", true, testPoint{startTime: 1, timestamp: 30, value: 30}))
+
+	// verify
+	// the stale stream was forgotten, so that its observation is handled as the first one
+	assert.Equal(t, 0, md.MetricCount())
+	assert.Equal(t, 2, ctdp.streams.Len())
+}
+
+func TestMaxStreams(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxStreams = 1
+	ctdp := newTestProcessor(t, cfg)
+
+	// test
+	process(t, ctdp, intSumMetrics("first", true, testPoint{startTime: 1, timestamp: 10, value: 10}))
+	process(t, ctdp, intSumMetrics("second", true, testPoint{startTime: 1, timestamp: 10, value: 10}))
+	md := process(t, ctdp, intSumMetrics("first", true, testPoint{startTime: 1, timestamp: 20, value: 20}))
+
+	// verify
+	// the first stream was evicted to make room for the second one
+	assert.Equal(t, 0, md.MetricCount())
+	assert.Equal(t, 1, ctdp.streams.Len())
+}
+
+func newTestProcessor(t *testing.T, cfg *Config) *cumulativeToDeltaProcessor {
+	ctdp, err := newCumulativeToDeltaProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	return ctdp
+}
+
+func process(t *testing.T, ctdp *cumulativeToDeltaProcessor, md pdata.Metrics) pdata.Metrics {
+	md, err := ctdp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	return md
+}
+
+func singleMetric(t *testing.T, md pdata.Metrics) pdata.Metric {
+	require.Equal(t, 1, md.MetricCount())
+	return md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+}
+
+func seconds(s int64) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(s * int64(time.Second))
+}
+
+// intSumMetrics returns metrics with a single cumulative int sum, with the given points
+func intSumMetrics(name string, monotonic bool, points ...testPoint) pdata.Metrics {
+	md, metric := newSingleMetric(name)
+	metric.SetDataType(pdata.MetricDataTypeIntSum)
+	sum := metric.IntSum()
+	sum.SetIsMonotonic(monotonic)
+	sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	sum.DataPoints().Resize(len(points))
+	for i, p := range points {
+		dp := sum.DataPoints().At(i)
+		dp.LabelsMap().InitFromMap(p.labels)
+		dp.SetStartTime(seconds(p.startTime))
+		dp.SetTimestamp(seconds(p.timestamp))
+		dp.SetValue(int64(p.value))
+	}
+	return md
+}
+
+// doubleSumMetrics returns metrics with a single cumulative double sum, with the given points
+func doubleSumMetrics(name string, monotonic bool, points ...testPoint) pdata.Metrics {
+	md, metric := newSingleMetric(name)
+	metric.SetDataType(pdata.MetricDataTypeDoubleSum)
+	sum := metric.DoubleSum()
+	sum.SetIsMonotonic(monotonic)
+	sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	sum.DataPoints().Resize(len(points))
+	for i, p := range points {
+		dp := sum.DataPoints().At(i)
+		dp.LabelsMap().InitFromMap(p.labels)
+		dp.SetStartTime(seconds(p.startTime))
+		dp.SetTimestamp(seconds(p.timestamp))
+		dp.SetValue(p.value)
+	}
+	return md
+}
+
+func newSingleMetric(name string) (pdata.Metrics, pdata.Metric) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Resize(1)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(1)
+	metrics.At(0).SetName(name)
+	return md, metrics.At(0)
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatorateprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+var params = component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+func TestDefaultConfiguration(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	assert.Empty(t, c.Metrics)
+}
+
+func TestCreateMetricsProcessor(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics = []string{"requests"}
+
+	mp, err := createMetricsProcessor(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, mp)
+	assert.True(t, mp.GetCapabilities().MutatesConsumedData)
+}
+
+func TestNoMetrics(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics = []string{""}
+
+	mp, err := createMetricsProcessor(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	assert.Equal(t, errAtLeastOneMetricNeeded, err)
+	assert.Nil(t, mp)
+}
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatorateprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+var (
+	mNumDroppedDataPoints = stats.Int64("num_dropped_data_points", "Number of delta data points dropped as their interval is zero or missing", stats.UnitDimensionless)
+)
+
+// MetricViews return the metrics views according to given telemetry level.
+func MetricViews() []*view.View {
+	legacyViews := []*view.View{
+		{
+			Name:        mNumDroppedDataPoints.Name(),
+			Measure:     mNumDroppedDataPoints,
+			Description: mNumDroppedDataPoints.Description(),
+			Aggregation: view.Sum(),
+		},
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatorateprocessor
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type deltaToRateProcessor struct {
+	logger  *zap.Logger
+	metrics map[string]struct{}
+}
+
+func newDeltaToRateProcessor(logger *zap.Logger, metricNames []string) (*deltaToRateProcessor, error) {
+	metrics := make(map[string]struct{}, len(metricNames))
+	for _, name := range metricNames {
+		if name != "" {
+			metrics[name] = struct{}{}
+		}
+	}
+
+	if len(metrics) == 0 {
+		return nil, errAtLeastOneMetricNeeded
+	}
+
+	return &deltaToRateProcessor{logger: logger, metrics: metrics}, nil
+}
+
+// ProcessMetrics converts the configured delta sums to double gauges, holding the rate per second of each data point.
+// The metrics left without any data point, as their intervals were all invalid, are removed.
+func (dtrp *deltaToRateProcessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	var numDropped int64
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+
+			kept := pdata.NewMetricSlice()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if _, ok := dtrp.metrics[metric.Name()]; ok {
+					converted, dropped := dtrp.convert(metric)
+					numDropped += dropped
+					if converted && metric.DoubleGauge().DataPoints().Len() == 0 {
+						continue
+					}
+				}
+				kept.Append(metric)
+			}
+
+			metrics.Resize(0)
+			kept.MoveAndAppendTo(metrics)
+		}
+	}
+
+	if numDropped > 0 {
+		stats.Record(ctx, mNumDroppedDataPoints.M(numDropped))
+	}
+
+	return md, nil
+}
+
+// convert replaces the given delta sum by a double gauge holding the rates, returning whether the metric was
+// converted, along with the number of data points dropped as their interval was invalid
+func (dtrp *deltaToRateProcessor) convert(metric pdata.Metric) (bool, int64) {
+	rates := pdata.NewDoubleDataPointSlice()
+	var numDropped int64
+
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		if metric.IntSum().AggregationTemporality() != pdata.AggregationTemporalityDelta {
+			dtrp.logUnsupported(metric)
+			return false, 0
+		}
+
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			interval, ok := intervalSeconds(dp.StartTime(), dp.Timestamp())
+			if !ok {
+				numDropped++
+				continue
+			}
+
+			// the exemplars aren't kept, as they hold measurements rather than rates
+			rate := pdata.NewDoubleDataPoint()
+			dp.LabelsMap().CopyTo(rate.LabelsMap())
+			rate.SetStartTime(dp.StartTime())
+			rate.SetTimestamp(dp.Timestamp())
+			rate.SetValue(float64(dp.Value()) / interval)
+			rates.Append(rate)
+		}
+
+	case pdata.MetricDataTypeDoubleSum:
+		if metric.DoubleSum().AggregationTemporality() != pdata.AggregationTemporalityDelta {
+			dtrp.logUnsupported(metric)
+			return false, 0
+		}
+
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			interval, ok := intervalSeconds(dp.StartTime(), dp.Timestamp())
+			if !ok {
+				numDropped++
+				continue
+			}
+
+			// the data points of the sums and gauges are of the same type, so that they can be reused,
+			// only dropping the exemplars just like for the int sums
+			dp.Exemplars().Resize(0)
+			dp.SetValue(dp.Value() / interval)
+			rates.Append(dp)
+		}
+
+	default:
+		dtrp.logUnsupported(metric)
+		return false, 0
+	}
+
+	if numDropped > 0 {
+		dtrp.logger.Debug("data points dropped as their interval is zero or missing",
+			zap.String("metric", metric.Name()), zap.Int64("count", numDropped))
+	}
+
+	// changing the data type clears the sum, along with its temporality and monotonicity
+	metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	rates.MoveAndAppendTo(metric.DoubleGauge().DataPoints())
+	return true, numDropped
+}
+
+func (dtrp *deltaToRateProcessor) logUnsupported(metric pdata.Metric) {
+	dtrp.logger.Debug("metric is not a delta sum, leaving it unchanged",
+		zap.String("metric", metric.Name()), zap.String("type", metric.DataType().String()))
+}
+
+// intervalSeconds returns the interval between the given timestamps in seconds, or false when it's zero or missing
+func intervalSeconds(startTime, timestamp pdata.TimestampUnixNano) (float64, bool) {
+	if startTime == 0 || timestamp <= startTime {
+		return 0, false
+	}
+	return float64(timestamp-startTime) / float64(time.Second), true
+}
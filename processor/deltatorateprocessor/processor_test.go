@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatorateprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestConvertIntSum(t *testing.T) {
+	// prepare
+	dtrp, err := newDeltaToRateProcessor(zap.NewNop(), []string{"requests"})
+	require.NoError(t, err)
+
+	md, metric := singleMetric("requests")
+	metric.SetDataType(pdata.MetricDataTypeIntSum)
+	metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	metric.IntSum().SetIsMonotonic(true)
+	metric.IntSum().DataPoints().Resize(2)
+	dp := metric.IntSum().DataPoints().At(0)
+	dp.LabelsMap().Insert("path", "/a")
+	dp.SetStartTime(seconds(10))
+	dp.SetTimestamp(seconds(20))
+	dp.SetValue(25)
+	dp.Exemplars().Resize(1)
+	dp = metric.IntSum().DataPoints().At(1)
+	dp.LabelsMap().Insert("path", "/b")
+	dp.SetStartTime(seconds(10))
+	dp.SetTimestamp(seconds(14))
+	dp.SetValue(2)
+
+	// test
+	md, err = dtrp.ProcessMetrics(context.Background(), md)
+
+	// verify
+	require.NoError(t, err)
+	metric = md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, metric.DataType())
+	assert.Equal(t, "requests", metric.Name())
+
+	rates := metric.DoubleGauge().DataPoints()
+	require.Equal(t, 2, rates.Len())
+	assert.Equal(t, 2.5, rates.At(0).Value())
+	assert.Equal(t, seconds(10), rates.At(0).StartTime())
+	assert.Equal(t, seconds(20), rates.At(0).Timestamp())
+	assert.Equal(t, map[string]string{"path": "/a"}, labels(rates.At(0).LabelsMap()))
+	assert.Equal(t, 0, rates.At(0).Exemplars().Len())
+	assert.Equal(t, 0.5, rates.At(1).Value())
+	assert.Equal(t, map[string]string{"path": "/b"}, labels(rates.At(1).LabelsMap()))
+}
+
+func TestConvertDoubleSum(t *testing.T) {
+	// prepare
+	dtrp, err := newDeltaToRateProcessor(zap.NewNop(), []string{"bytes"})
+	require.NoError(t, err)
+
+	md, metric := singleMetric("bytes")
+	metric.SetDataType(pdata.MetricDataTypeDoubleSum)
+	metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	metric.DoubleSum().DataPoints().Resize(1)
+	dp := metric.DoubleSum().DataPoints().At(0)
+	dp.LabelsMap().Insert("direction", "in")
+	dp.SetStartTime(seconds(10))
+	dp.SetTimestamp(seconds(10) + pdata.TimestampUnixNano(500*time.Millisecond))
+	dp.SetValue(3)
+
+	// test
+	md, err = dtrp.ProcessMetrics(context.Background(), md)
+
+	// verify
+	require.NoError(t, err)
+	metric = md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, metric.DataType())
+	rates := metric.DoubleGauge().DataPoints()
+	require.Equal(t, 1, rates.Len())
+	assert.Equal(t, 6.0, rates.At(0).Value())
+	assert.Equal(t, map[string]string{"direction": "in"}, labels(rates.At(0).LabelsMap()))
+}
+
+func TestInvalidIntervalsAreDropped(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	dtrp, err := newDeltaToRateProcessor(zap.NewNop(), []string{"partial", "invalid"})
+	require.NoError(t, err)
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Resize(1)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(2)
+	for i, name := range []string{"partial", "invalid"} {
+		metric := metrics.At(i)
+		metric.SetName(name)
+		metric.SetDataType(pdata.MetricDataTypeDoubleSum)
+		metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+		metric.DoubleSum().DataPoints().Resize(2)
+
+		// missing start time
+		metric.DoubleSum().DataPoints().At(0).SetTimestamp(seconds(10))
+
+		// zero interval, unless the metric is the partial one
+		dp := metric.DoubleSum().DataPoints().At(1)
+		dp.SetStartTime(seconds(10))
+		dp.SetTimestamp(seconds(10))
+		if name == "partial" {
+			dp.SetTimestamp(seconds(12))
+			dp.SetValue(4)
+		}
+	}
+
+	// test
+	md, err = dtrp.ProcessMetrics(context.Background(), md)
+
+	// verify
+	require.NoError(t, err)
+
+	// the metric without any valid data point is removed
+	require.Equal(t, 1, md.MetricCount())
+	metric := metrics.At(0)
+	assert.Equal(t, "partial", metric.Name())
+	require.Equal(t, 1, metric.DoubleGauge().DataPoints().Len())
+	assert.Equal(t, 2.0, metric.DoubleGauge().DataPoints().At(0).Value())
+
+	viewData, err := view.RetrieveData("processor/deltatorate/" + mNumDroppedDataPoints.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 3, viewData[0].Data.(*view.SumData).Value)
+}
+
+func TestOtherMetricsAreUnchanged(t *testing.T) {
+	// prepare
+	dtrp, err := newDeltaToRateProcessor(zap.NewNop(), []string{"cumulative", "gauge"})
+	require.NoError(t, err)
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Resize(1)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(3)
+
+	metrics.At(0).SetName("cumulative")
+	metrics.At(0).SetDataType(pdata.MetricDataTypeIntSum)
+	metrics.At(0).IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	metrics.At(1).SetName("gauge")
+	metrics.At(1).SetDataType(pdata.MetricDataTypeIntGauge)
+	metrics.At(2).SetName("not-configured")
+	metrics.At(2).SetDataType(pdata.MetricDataTypeDoubleSum)
+	metrics.At(2).DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+
+	// test
+	md, err = dtrp.ProcessMetrics(context.Background(), md)
+
+	// verify
+	require.NoError(t, err)
+	require.Equal(t, 3, md.MetricCount())
+	assert.Equal(t, pdata.MetricDataTypeIntSum, metrics.At(0).DataType())
+	assert.Equal(t, pdata.MetricDataTypeIntGauge, metrics.At(1).DataType())
+	assert.Equal(t, pdata.MetricDataTypeDoubleSum, metrics.At(2).DataType())
+}
+
+func singleMetric(name string) (pdata.Metrics, pdata.Metric) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Resize(1)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(1)
+	metrics.At(0).SetName(name)
+	return md, metrics.At(0)
+}
+
+func seconds(s int64) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(s * int64(time.Second))
+}
+
+func labels(sm pdata.StringMap) map[string]string {
+	m := map[string]string{}
+	sm.ForEach(func(k, v string) {
+		m[k] = v
+	})
+	return m
+}
@@ -56,12 +56,66 @@ func matchingInstrumentationLibraryLogs(rl pdata.ResourceLogs, library pdata.Ins
 	return ill
 }
 
+// matchingInstrumentationLibraryMetrics searches for a pdata.InstrumentationLibraryMetrics instance matching
+// given InstrumentationLibrary. If nothing is found, it creates a new one
+func matchingInstrumentationLibraryMetrics(rm pdata.ResourceMetrics, library pdata.InstrumentationLibrary) pdata.InstrumentationLibraryMetrics {
+	ilms := rm.InstrumentationLibraryMetrics()
+	for i := 0; i < ilms.Len(); i++ {
+		ilm := ilms.At(i)
+		if instrumentationLibrariesEqual(ilm.InstrumentationLibrary(), library) {
+			return ilm
+		}
+	}
+
+	ilms.Resize(ilms.Len() + 1)
+	ilm := ilms.At(ilms.Len() - 1)
+	library.CopyTo(ilm.InstrumentationLibrary())
+	return ilm
+}
+
+// matchingMetric searches for a pdata.Metric instance with the same name and data type as the given metric.
+// If nothing is found, it creates a new one, copying the metric descriptor but none of the data points
+func matchingMetric(ilm pdata.InstrumentationLibraryMetrics, metric pdata.Metric) pdata.Metric {
+	metrics := ilm.Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		if m.Name() == metric.Name() && m.DataType() == metric.DataType() {
+			return m
+		}
+	}
+
+	metrics.Resize(metrics.Len() + 1)
+	m := metrics.At(metrics.Len() - 1)
+	m.SetName(metric.Name())
+	m.SetDescription(metric.Description())
+	m.SetUnit(metric.Unit())
+	m.SetDataType(metric.DataType())
+
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		m.IntSum().SetAggregationTemporality(metric.IntSum().AggregationTemporality())
+		m.IntSum().SetIsMonotonic(metric.IntSum().IsMonotonic())
+	case pdata.MetricDataTypeDoubleSum:
+		m.DoubleSum().SetAggregationTemporality(metric.DoubleSum().AggregationTemporality())
+		m.DoubleSum().SetIsMonotonic(metric.DoubleSum().IsMonotonic())
+	case pdata.MetricDataTypeIntHistogram:
+		m.IntHistogram().SetAggregationTemporality(metric.IntHistogram().AggregationTemporality())
+	case pdata.MetricDataTypeDoubleHistogram:
+		m.DoubleHistogram().SetAggregationTemporality(metric.DoubleHistogram().AggregationTemporality())
+	}
+
+	return m
+}
+
 // spansGroupedByAttrs keeps all found grouping attributes for spans, together with the matching records
 type spansGroupedByAttrs []pdata.ResourceSpans
 
 // logsGroupedByAttrs keeps all found grouping attributes for logs, together with the matching records
 type logsGroupedByAttrs []pdata.ResourceLogs
 
+// metricsGroupedByAttrs keeps all found grouping attributes for metrics, together with the matching data points
+type metricsGroupedByAttrs []pdata.ResourceMetrics
+
 func newLogsGroupedByAttrs() *logsGroupedByAttrs {
 	return &logsGroupedByAttrs{}
 }
@@ -70,6 +124,10 @@ func newSpansGroupedByAttrs() *spansGroupedByAttrs {
 	return &spansGroupedByAttrs{}
 }
 
+func newMetricsGroupedByAttrs() *metricsGroupedByAttrs {
+	return &metricsGroupedByAttrs{}
+}
+
 // findGroup searches for an existing pdata.ResourceLogs that contains both the grouped attributes
 // and base resource attributes. Returns the matching pdata.ResourceLogs and bool value which is set to true if found
 func (lgba logsGroupedByAttrs) findGroup(baseResource pdata.Resource, attrs pdata.AttributeMap) (pdata.ResourceLogs, bool) {
@@ -81,8 +139,8 @@ func (lgba logsGroupedByAttrs) findGroup(baseResource pdata.Resource, attrs pdat
 	return pdata.ResourceLogs{}, false
 }
 
-// findGroup searches for an existing pdata.ResourceLogs that contains both the grouped attributes
-// and base resource attributes. Returns the matching pdata.ResourceLogs and bool value which is set to true if found
+// findGroup searches for an existing pdata.ResourceSpans that contains both the grouped attributes
+// and base resource attributes. Returns the matching pdata.ResourceSpans and bool value which is set to true if found
 func (sgba spansGroupedByAttrs) findGroup(baseResource pdata.Resource, attrs pdata.AttributeMap) (pdata.ResourceSpans, bool) {
 	for i := 0; i < len(sgba); i++ {
 		if resourceMatches(sgba[i].Resource(), baseResource, attrs) {
@@ -92,6 +150,17 @@ func (sgba spansGroupedByAttrs) findGroup(baseResource pdata.Resource, attrs pda
 	return pdata.ResourceSpans{}, false
 }
 
+// findGroup searches for an existing pdata.ResourceMetrics that contains both the grouped attributes
+// and base resource attributes. Returns the matching pdata.ResourceMetrics and bool value which is set to true if found
+func (mgba metricsGroupedByAttrs) findGroup(baseResource pdata.Resource, attrs pdata.AttributeMap) (pdata.ResourceMetrics, bool) {
+	for i := 0; i < len(mgba); i++ {
+		if resourceMatches(mgba[i].Resource(), baseResource, attrs) {
+			return mgba[i], true
+		}
+	}
+	return pdata.ResourceMetrics{}, false
+}
+
 // resourceMatches verifies if given pdata.Resource matches a composition of another (base) resource and attributes
 func resourceMatches(res pdata.Resource, baseResource pdata.Resource, recordAttrs pdata.AttributeMap) bool {
 	baseAttrs := baseResource.Attributes()
@@ -182,3 +251,22 @@ func (lgba *logsGroupedByAttrs) attributeGroup(baseResource pdata.Resource, reco
 
 	return res
 }
+
+// attributeGroup searches for a group with matching attributes and returns it. If nothing is found, it is being created
+func (mgba *metricsGroupedByAttrs) attributeGroup(baseResource pdata.Resource, recordAttrs pdata.AttributeMap) pdata.ResourceMetrics {
+	res, found := mgba.findGroup(baseResource, recordAttrs)
+	if !found {
+		res = pdata.NewResourceMetrics()
+		baseResource.CopyTo(res.Resource())
+
+		// This prioritizes data point labels over resource attributes, if they overlap
+		attrs := res.Resource().Attributes()
+		recordAttrs.ForEach(func(k string, v pdata.AttributeValue) {
+			attrs.Upsert(k, v)
+		})
+
+		*mgba = append(*mgba, res)
+	}
+
+	return res
+}
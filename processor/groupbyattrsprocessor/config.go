@@ -23,6 +23,6 @@ type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
 
 	// GroupByKeys describes the attribute names that are going to be used for grouping.
-	// Must include at least one attribute name.
+	// When empty, the processor only merges the records with matching resources and instrumentation libraries.
 	GroupByKeys []string `mapstructure:"keys"`
 }
@@ -53,4 +53,14 @@ func TestLoadConfig(t *testing.T) {
 			},
 			GroupByKeys: []string{"key1", "key2"},
 		})
+
+	conf = config.Processors["groupbyattrs/compaction"]
+	assert.Equal(t, conf,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "groupbyattrs",
+				NameVal: "groupbyattrs/compaction",
+			},
+			GroupByKeys: []string{},
+		})
 }
@@ -16,7 +16,6 @@ package groupbyattrsprocessor
 
 import (
 	"context"
-	"fmt"
 
 	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
@@ -32,8 +31,7 @@ const (
 )
 
 var (
-	errAtLeastOneAttributeNeeded = fmt.Errorf("option 'groupByKeys' must include at least one non-empty attribute name")
-	processorCapabilities        = component.ProcessorCapabilities{MutatesConsumedData: true}
+	processorCapabilities = component.ProcessorCapabilities{MutatesConsumedData: true}
 )
 
 // NewFactory returns a new factory for the Filter processor.
@@ -45,7 +43,8 @@ func NewFactory() component.ProcessorFactory {
 		typeStr,
 		createDefaultConfig,
 		processorhelper.WithTraces(createTraceProcessor),
-		processorhelper.WithLogs(createLogsProcessor))
+		processorhelper.WithLogs(createLogsProcessor),
+		processorhelper.WithMetrics(createMetricsProcessor))
 }
 
 // createDefaultConfig creates the default configuration for the processor.
@@ -59,6 +58,8 @@ func createDefaultConfig() configmodels.Processor {
 	}
 }
 
+// createGroupByAttrsProcessor creates the processor for the given keys. When no keys are given, the processor
+// only merges the matching resources and instrumentation libraries together.
 func createGroupByAttrsProcessor(logger *zap.Logger, attributes []string) (*groupByAttrsProcessor, error) {
 	var nonEmptyAttributes []string
 	presentAttributes := make(map[string]struct{})
@@ -75,10 +76,6 @@ func createGroupByAttrsProcessor(logger *zap.Logger, attributes []string) (*grou
 		}
 	}
 
-	if len(nonEmptyAttributes) == 0 {
-		return nil, errAtLeastOneAttributeNeeded
-	}
-
 	return &groupByAttrsProcessor{logger: logger, groupByKeys: nonEmptyAttributes}, nil
 }
 
@@ -102,7 +99,7 @@ func createTraceProcessor(
 		processorhelper.WithCapabilities(processorCapabilities))
 }
 
-// createLogsProcessor creates a metrics processor based on this config.
+// createLogsProcessor creates a logs processor based on this config.
 func createLogsProcessor(
 	_ context.Context,
 	params component.ProcessorCreateParams,
@@ -121,3 +118,23 @@ func createLogsProcessor(
 		gap,
 		processorhelper.WithCapabilities(processorCapabilities))
 }
+
+// createMetricsProcessor creates a metrics processor based on this config.
+func createMetricsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.MetricsConsumer) (component.MetricsProcessor, error) {
+
+	oCfg := cfg.(*Config)
+	gap, err := createGroupByAttrsProcessor(params.Logger, oCfg.GroupByKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return processorhelper.NewMetricsProcessor(
+		cfg,
+		nextConsumer,
+		gap,
+		processorhelper.WithCapabilities(processorCapabilities))
+}
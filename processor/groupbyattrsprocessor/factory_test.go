@@ -51,12 +51,19 @@ func TestCreateTestProcessor(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, lp)
 	assert.Equal(t, true, lp.GetCapabilities().MutatesConsumedData)
+
+	mp, err := createMetricsProcessor(context.Background(), params, config, consumertest.NewMetricsNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, mp)
+	assert.Equal(t, true, mp.GetCapabilities().MutatesConsumedData)
 }
 
 func TestNoKeys(t *testing.T) {
+	// an empty list of keys is valid, the processor will only compact the data
 	gbap, err := createGroupByAttrsProcessor(logger, []string{})
-	assert.Error(t, err)
-	assert.Nil(t, gbap)
+	assert.NoError(t, err)
+	assert.NotNil(t, gbap)
+	assert.Empty(t, gbap.groupByKeys)
 }
 
 func TestDuplicateKeys(t *testing.T) {
@@ -17,6 +17,7 @@ package groupbyattrsprocessor
 import (
 	"context"
 
+	"go.opencensus.io/stats"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 )
@@ -27,9 +28,10 @@ type groupByAttrsProcessor struct {
 }
 
 // ProcessTraces process traces and groups traces by attribute.
-func (gap *groupByAttrsProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+func (gap *groupByAttrsProcessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
 	rss := td.ResourceSpans()
 	extractedGroups := newSpansGroupedByAttrs()
+	var numGrouped, numNonGrouped int64
 
 	for i := 0; i < rss.Len(); i++ {
 		rs := rss.At(i)
@@ -42,12 +44,12 @@ func (gap *groupByAttrsProcessor) ProcessTraces(_ context.Context, td pdata.Trac
 
 				groupedAnything, groupedAttrMap := gap.splitAttrMap(span.Attributes())
 				if groupedAnything {
-					mNumGroupedSpans.M(1)
+					numGrouped++
 					// Some attributes are going to be moved from span to resource level,
 					// so we can delete those on the record level
 					deleteAttributes(groupedAttrMap, span.Attributes())
 				} else {
-					mNumNonGroupedSpans.M(1)
+					numNonGrouped++
 				}
 
 				// Lets combine the base resource attributes + the extracted (grouped) attributes
@@ -64,14 +66,19 @@ func (gap *groupByAttrsProcessor) ProcessTraces(_ context.Context, td pdata.Trac
 	for _, eg := range *extractedGroups {
 		groupedResourceSpans.Append(eg)
 	}
-	mDistSpanGroups.M(int64(len(*extractedGroups)))
+
+	stats.Record(ctx,
+		mNumGroupedSpans.M(numGrouped),
+		mNumNonGroupedSpans.M(numNonGrouped),
+		mDistSpanGroups.M(int64(len(*extractedGroups))))
 
 	return groupedTraces, nil
 }
 
-func (gap *groupByAttrsProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+func (gap *groupByAttrsProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
 	rl := ld.ResourceLogs()
 	extractedGroups := newLogsGroupedByAttrs()
+	var numGrouped, numNonGrouped int64
 
 	for i := 0; i < rl.Len(); i++ {
 		ls := rl.At(i)
@@ -84,12 +91,12 @@ func (gap *groupByAttrsProcessor) ProcessLogs(_ context.Context, ld pdata.Logs)
 
 				groupedAnything, groupedAttrMap := gap.splitAttrMap(log.Attributes())
 				if groupedAnything {
-					mNumGroupedLogs.M(1)
+					numGrouped++
 					// Some attributes are going to be moved from log record to resource level,
 					// so we can delete those on the record level
 					deleteAttributes(groupedAttrMap, log.Attributes())
 				} else {
-					mNumNonGroupedLogs.M(1)
+					numNonGrouped++
 				}
 
 				// Lets combine the base resource attributes + the extracted (grouped) attributes
@@ -107,11 +114,115 @@ func (gap *groupByAttrsProcessor) ProcessLogs(_ context.Context, ld pdata.Logs)
 	for _, eg := range *extractedGroups {
 		groupedResourceLogs.Append(eg)
 	}
-	mDistLogGroups.M(int64(len(*extractedGroups)))
+
+	stats.Record(ctx,
+		mNumGroupedLogs.M(numGrouped),
+		mNumNonGroupedLogs.M(numNonGrouped),
+		mDistLogGroups.M(int64(len(*extractedGroups))))
 
 	return groupedLogs, nil
 }
 
+// ProcessMetrics process metrics and groups data points by label. As the labels are strings,
+// the grouped ones are moved to the resource as string attributes.
+func (gap *groupByAttrsProcessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	extractedGroups := newMetricsGroupedByAttrs()
+	var numGrouped, numNonGrouped int64
+
+	// groupedMetric moves the grouped labels of a data point to the resource level and returns the metric
+	// the data point should be appended to
+	groupedMetric := func(rm pdata.ResourceMetrics, ilm pdata.InstrumentationLibraryMetrics, metric pdata.Metric, labels pdata.StringMap) pdata.Metric {
+		groupedAnything, groupedAttrMap := gap.splitLabelsMap(labels)
+		if groupedAnything {
+			numGrouped++
+			// Some labels are going to be moved from data point to resource level,
+			// so we can delete those on the data point level
+			deleteLabels(groupedAttrMap, labels)
+		} else {
+			numNonGrouped++
+		}
+
+		groupedMetrics := extractedGroups.attributeGroup(rm.Resource(), groupedAttrMap)
+		groupedILM := matchingInstrumentationLibraryMetrics(groupedMetrics, ilm.InstrumentationLibrary())
+		return matchingMetric(groupedILM, metric)
+	}
+
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			for k := 0; k < ilm.Metrics().Len(); k++ {
+				metric := ilm.Metrics().At(k)
+
+				switch metric.DataType() {
+				case pdata.MetricDataTypeIntGauge:
+					dps := metric.IntGauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						groupedMetric(rm, ilm, metric, dp.LabelsMap()).IntGauge().DataPoints().Append(dp)
+					}
+				case pdata.MetricDataTypeDoubleGauge:
+					dps := metric.DoubleGauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						groupedMetric(rm, ilm, metric, dp.LabelsMap()).DoubleGauge().DataPoints().Append(dp)
+					}
+				case pdata.MetricDataTypeIntSum:
+					dps := metric.IntSum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						groupedMetric(rm, ilm, metric, dp.LabelsMap()).IntSum().DataPoints().Append(dp)
+					}
+				case pdata.MetricDataTypeDoubleSum:
+					dps := metric.DoubleSum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						groupedMetric(rm, ilm, metric, dp.LabelsMap()).DoubleSum().DataPoints().Append(dp)
+					}
+				case pdata.MetricDataTypeIntHistogram:
+					dps := metric.IntHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						groupedMetric(rm, ilm, metric, dp.LabelsMap()).IntHistogram().DataPoints().Append(dp)
+					}
+				case pdata.MetricDataTypeDoubleHistogram:
+					dps := metric.DoubleHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						groupedMetric(rm, ilm, metric, dp.LabelsMap()).DoubleHistogram().DataPoints().Append(dp)
+					}
+				case pdata.MetricDataTypeDoubleSummary:
+					dps := metric.DoubleSummary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						groupedMetric(rm, ilm, metric, dp.LabelsMap()).DoubleSummary().DataPoints().Append(dp)
+					}
+				default:
+					gap.logger.Debug("unsupported metric data type, dropping it", zap.String("metric", metric.Name()),
+						zap.String("type", metric.DataType().String()))
+				}
+			}
+		}
+	}
+
+	// Copy the grouped data into output
+	groupedMetrics := pdata.NewMetrics()
+	groupedResourceMetrics := groupedMetrics.ResourceMetrics()
+	for _, eg := range *extractedGroups {
+		groupedResourceMetrics.Append(eg)
+	}
+
+	stats.Record(ctx,
+		mNumGroupedMetrics.M(numGrouped),
+		mNumNonGroupedMetrics.M(numNonGrouped),
+		mDistMetricGroups.M(int64(len(*extractedGroups))))
+
+	return groupedMetrics, nil
+}
+
 func deleteAttributes(attrsForRemoval, targetAttrs pdata.AttributeMap) {
 	attrsForRemoval.ForEach(func(key string, _ pdata.AttributeValue) {
 		targetAttrs.Delete(key)
@@ -135,3 +246,26 @@ func (gap *groupByAttrsProcessor) splitAttrMap(attrMap pdata.AttributeMap) (bool
 
 	return groupedAnything, groupedAttrMap
 }
+
+func deleteLabels(labelsForRemoval pdata.AttributeMap, targetLabels pdata.StringMap) {
+	labelsForRemoval.ForEach(func(key string, _ pdata.AttributeValue) {
+		targetLabels.Delete(key)
+	})
+}
+
+// splitLabelsMap is the counterpart of splitAttrMap for the labels of metric data points, returning
+// the matching labels as string attributes
+func (gap *groupByAttrsProcessor) splitLabelsMap(labels pdata.StringMap) (bool, pdata.AttributeMap) {
+	groupedAttrMap := pdata.NewAttributeMap()
+	groupedAnything := false
+
+	for _, labelKey := range gap.groupByKeys {
+		labelVal, found := labels.Get(labelKey)
+		if found {
+			groupedAttrMap.InsertString(labelKey, labelVal)
+			groupedAnything = true
+		}
+	}
+
+	return groupedAnything, groupedAttrMap
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
@@ -300,6 +301,170 @@ func TestAttributeGrouping(t *testing.T) {
 	}
 }
 
+func TestMetricsAttributeGrouping(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	gap, err := createGroupByAttrsProcessor(logger, []string{"host"})
+	require.NoError(t, err)
+
+	// test
+	processedMetrics, err := gap.ProcessMetrics(context.Background(), someMetrics())
+
+	// verify
+	assert.NoError(t, err)
+
+	rms := processedMetrics.ResourceMetrics()
+	require.Equal(t, 3, rms.Len())
+
+	// the groups are kept in the order in which they were found
+	for i, expectedHost := range []string{"host-a", "host-b", ""} {
+		host, found := rms.At(i).Resource().Attributes().Get("host")
+		if expectedHost == "" {
+			assert.False(t, found)
+		} else {
+			require.True(t, found)
+			assert.Equal(t, expectedHost, host.StringVal())
+		}
+
+		// the original resource attributes are kept
+		service, found := rms.At(i).Resource().Attributes().Get("service.name")
+		require.True(t, found)
+		assert.Equal(t, "some-service", service.StringVal())
+
+		require.Equal(t, 1, rms.At(i).InstrumentationLibraryMetrics().Len())
+		assert.Equal(t, "some-library", rms.At(i).InstrumentationLibraryMetrics().At(0).InstrumentationLibrary().Name())
+	}
+
+	// host-a has data points for both metrics
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	requests := metrics.At(0)
+	assert.Equal(t, "requests", requests.Name())
+	assert.Equal(t, "1", requests.Unit())
+	require.Equal(t, pdata.MetricDataTypeIntSum, requests.DataType())
+	assert.True(t, requests.IntSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, requests.IntSum().AggregationTemporality())
+	require.Equal(t, 2, requests.IntSum().DataPoints().Len())
+	for i := 0; i < requests.IntSum().DataPoints().Len(); i++ {
+		labels := requests.IntSum().DataPoints().At(i).LabelsMap()
+		_, found := labels.Get("host")
+		assert.False(t, found)
+		_, found = labels.Get("path")
+		assert.True(t, found)
+	}
+
+	cpu := metrics.At(1)
+	assert.Equal(t, "cpu", cpu.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, cpu.DataType())
+	assert.Equal(t, 1, cpu.DoubleGauge().DataPoints().Len())
+
+	// host-b has only data points for the requests
+	metrics = rms.At(1).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, 1, metrics.At(0).IntSum().DataPoints().Len())
+
+	// the data point without the label is kept under the original resource
+	metrics = rms.At(2).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "cpu", metrics.At(0).Name())
+	assert.Equal(t, 1, metrics.At(0).DoubleGauge().DataPoints().Len())
+
+	viewData, err := view.RetrieveData("processor/groupbyattrs/" + mNumGroupedMetrics.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 4, viewData[0].Data.(*view.SumData).Value)
+
+	viewData, err = view.RetrieveData("processor/groupbyattrs/" + mNumNonGroupedMetrics.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 1, viewData[0].Data.(*view.SumData).Value)
+}
+
+func TestCompactionWithoutKeys(t *testing.T) {
+	// prepare
+	gap, err := createGroupByAttrsProcessor(logger, []string{})
+	require.NoError(t, err)
+
+	inputLogs := someComplexLogs(false, 3, 2)
+	inputTraces := someComplexTraces(false, 3, 2)
+
+	inputMetrics := pdata.NewMetrics()
+	inputMetrics.ResourceMetrics().Append(someMetrics().ResourceMetrics().At(0))
+	inputMetrics.ResourceMetrics().Append(someMetrics().ResourceMetrics().At(0))
+
+	// test
+	processedLogs, err := gap.ProcessLogs(context.Background(), inputLogs)
+	assert.NoError(t, err)
+	processedTraces, err := gap.ProcessTraces(context.Background(), inputTraces)
+	assert.NoError(t, err)
+	processedMetrics, err := gap.ProcessMetrics(context.Background(), inputMetrics)
+	assert.NoError(t, err)
+
+	// verify
+	// the identical resources and instrumentation libraries are merged together, with the records unchanged
+	require.Equal(t, 1, processedLogs.ResourceLogs().Len())
+	assert.Equal(t, 1, processedLogs.ResourceLogs().At(0).InstrumentationLibraryLogs().Len())
+	assert.Equal(t, 6, processedLogs.LogRecordCount())
+	assert.Equal(t, 2, processedLogs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Attributes().Len())
+
+	require.Equal(t, 1, processedTraces.ResourceSpans().Len())
+	assert.Equal(t, 1, processedTraces.ResourceSpans().At(0).InstrumentationLibrarySpans().Len())
+	assert.Equal(t, 6, processedTraces.SpanCount())
+
+	require.Equal(t, 1, processedMetrics.ResourceMetrics().Len())
+	metrics := processedMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, 6, metrics.At(0).IntSum().DataPoints().Len())
+	assert.Equal(t, 4, metrics.At(1).DoubleGauge().DataPoints().Len())
+	_, found := metrics.At(0).IntSum().DataPoints().At(0).LabelsMap().Get("host")
+	assert.True(t, found)
+}
+
+// someMetrics returns metrics with a single resource, holding a cumulative "requests" sum with data points for
+// host-a, host-b and host-a, and a "cpu" gauge with data points for host-a and without a host
+func someMetrics() pdata.Metrics {
+	metrics := pdata.NewMetrics()
+	metrics.ResourceMetrics().Resize(1)
+	rm := metrics.ResourceMetrics().At(0)
+	rm.Resource().Attributes().InsertString("service.name", "some-service")
+
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	ilm.InstrumentationLibrary().SetName("some-library")
+	ilm.Metrics().Resize(2)
+
+	requests := ilm.Metrics().At(0)
+	requests.SetName("requests")
+	requests.SetUnit("1")
+	requests.SetDataType(pdata.MetricDataTypeIntSum)
+	requests.IntSum().SetIsMonotonic(true)
+	requests.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	requests.IntSum().DataPoints().Resize(3)
+	for i, host := range []string{"host-a", "host-b", "host-a"} {
+		dp := requests.IntSum().DataPoints().At(i)
+		dp.LabelsMap().Insert("host", host)
+		dp.LabelsMap().Insert("path", fmt.Sprint("/path-", i))
+		dp.SetValue(int64(i))
+	}
+
+	cpu := ilm.Metrics().At(1)
+	cpu.SetName("cpu")
+	cpu.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	cpu.DoubleGauge().DataPoints().Resize(2)
+	cpu.DoubleGauge().DataPoints().At(0).LabelsMap().Insert("host", "host-a")
+	cpu.DoubleGauge().DataPoints().At(0).SetValue(0.5)
+	cpu.DoubleGauge().DataPoints().At(1).SetValue(0.7)
+
+	return metrics
+}
+
 func someSpans(attrs pdata.AttributeMap, count int) pdata.Traces {
 	ils := pdata.NewInstrumentationLibrarySpans()
 
@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import "time"
+
+// clock is the source of the current time and of the timers for the processor, so that tests can control
+// the passing of time
+type clock interface {
+	// now returns the current time
+	now() time.Time
+
+	// afterFunc calls the given function in its own goroutine once the duration has elapsed
+	afterFunc(time.Duration, func())
+}
+
+// systemClock is the clock backed by the system time
+type systemClock struct{}
+
+var _ clock = systemClock{}
+
+func (systemClock) now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) afterFunc(d time.Duration, f func()) {
+	time.AfterFunc(d, f)
+}
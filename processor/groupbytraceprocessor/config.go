@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 // Config is the configuration for the processor.
@@ -28,19 +29,159 @@ type Config struct {
 	// Default: 1_000_000.
 	NumTraces int `mapstructure:"num_traces"`
 
+	// WhenFull is what to do when NumTraces is reached and spans for a new trace arrive. Valid values are
+	// "evict", to evict the oldest trace and release it right away, and "refuse", to return an error to the previous component,
+	// so that it can retry later.
+	// Default: evict.
+	WhenFull string `mapstructure:"when_full"`
+
+	// MaxBytes is the max approximate size, in bytes, of the traces to keep in memory waiting for the duration.
+	// When this limit is reached, the oldest traces are released to the next consumer before their time.
+	// Only applicable to the memory storage.
+	// Default: 0, meaning no limit.
+	MaxBytes int `mapstructure:"max_bytes"`
+
+	// DedupSpans instructs the processor to drop the spans with the same span ID as a span already buffered
+	// for the same trace, such as the ones delivered twice by retried exports.
+	// Only applicable to the memory storage.
+	// Default: false.
+	DedupSpans bool `mapstructure:"dedup_spans"`
+
+	// MaxSpansPerTrace is the max number of spans to buffer for a single trace. Once a trace reaches it, the spans
+	// arriving for the trace are handled according to the TruncatedSpansPolicy, and the trace is released with the
+	// resource attribute "groupbytrace.truncated" set to true. The limit is checked before each batch of spans is
+	// added, so a trace might go over it by the spans of a single batch.
+	// Only applicable to the memory storage.
+	// Default: 0, meaning no limit.
+	MaxSpansPerTrace int `mapstructure:"max_spans_per_trace"`
+
+	// TruncatedSpansPolicy is what to do with the spans arriving for a trace that reached the MaxSpansPerTrace.
+	// Valid values are "drop", to discard them, and "forward", to release them right away without grouping them.
+	// Default: drop.
+	TruncatedSpansPolicy string `mapstructure:"truncated_spans_policy"`
+
+	// NumShards is the number of shards the in-memory storage is split into, each one with its own lock.
+	// A higher number of shards reduces the contention between concurrent operations on different traces.
+	// Only applicable to the memory storage.
+	// Default: 16.
+	NumShards int `mapstructure:"num_shards"`
+
+	// NumWorkers is the number of workers processing the traces, each one handling the events for a partition
+	// of the traces. A higher number of workers allows the processor to use more cores, at the cost of each worker
+	// holding only an equal part of NumTraces.
+	// Default: 1.
+	NumWorkers int `mapstructure:"num_workers"`
+
 	// WaitDuration tells the processor to wait for the specified duration for the trace to be complete.
 	// Default: 1s.
 	WaitDuration time.Duration `mapstructure:"wait_duration"`
 
+	// WaitDurationJitter is the maximum random offset added to the wait duration of each trace, so that the traces
+	// received at the same time, such as during a burst of traffic, aren't all released at the same time.
+	// Default: 0, meaning that the traces are released exactly after the wait duration.
+	WaitDurationJitter time.Duration `mapstructure:"wait_duration_jitter"`
+
+	// WaitDurationAttribute is the name of a span attribute holding, in milliseconds, for how long the trace should be
+	// kept instead of the WaitDuration. The override can only extend the time the trace is kept, never shorten it,
+	// and is counted from when the first spans for the trace arrived.
+	// Default: empty, meaning that the WaitDuration applies to all traces.
+	WaitDurationAttribute string `mapstructure:"wait_duration_attribute"`
+
+	// MaxWaitDuration is the maximum wait duration that can be set via the WaitDurationAttribute. Longer overrides
+	// are clamped to this value.
+	// Default: 5m.
+	MaxWaitDuration time.Duration `mapstructure:"max_wait_duration"`
+
+	// QuietPeriod enables the early release of traces: once the root span of a trace has been received, the trace
+	// is released as soon as no new spans arrived for it during this period, instead of waiting for the whole
+	// WaitDuration. The WaitDuration is still the upper bound for traces whose root span never arrives.
+	// Default: 0, meaning that traces are always held for the WaitDuration.
+	QuietPeriod time.Duration `mapstructure:"quiet_period"`
+
+	// MetricsFlushInterval is the interval in which the periodic metrics are recorded, such as the
+	// number of traces in memory and the number of events in the queue.
+	// Default: 1s.
+	MetricsFlushInterval time.Duration `mapstructure:"metrics_flush_interval"`
+
+	// GroupByKey is the name of an attribute to group the spans by, instead of the trace ID. The attribute
+	// is looked up in the span first, and then in the resource. Spans without the attribute are grouped by
+	// their trace ID. The wait duration is then counted per group key.
+	// Default: empty, meaning that spans are grouped by trace ID.
+	GroupByKey string `mapstructure:"group_by_key"`
+
+	// LateSpanGracePeriod is for how long the keys of the released traces are remembered, so that spans
+	// arriving for them afterwards are handled according to the LateSpanPolicy, instead of being treated
+	// as a brand new trace.
+	// Default: 0, meaning that late spans aren't detected.
+	LateSpanGracePeriod time.Duration `mapstructure:"late_span_grace_period"`
+
+	// LateSpanPolicy is what to do with the spans arriving within the grace period after their trace was
+	// released. Valid values are "forward", to release them right away with the attribute "groupbytrace.late"
+	// set to true, and "rebuffer", to hold them for the LateSpanWaitDuration before releasing them.
+	// Default: forward.
+	LateSpanPolicy string `mapstructure:"late_span_policy"`
+
+	// LateSpanWaitDuration is for how long the late spans are held when the LateSpanPolicy is "rebuffer".
+	// Default: 1s.
+	LateSpanWaitDuration time.Duration `mapstructure:"late_span_wait_duration"`
+
 	// DiscardOrphans instructs the processor to discard traces without the root span.
 	// This typically indicates that the trace is incomplete.
 	// Default: false.
 	// Not yet implemented, and an error will be returned when this option is used.
 	DiscardOrphans bool `mapstructure:"discard_orphans"`
 
-	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans to disk.
-	// Useful when the duration to wait for traces to complete is high.
+	// MarkCompleteness instructs the processor to set the resource attribute "groupbytrace.release_reason" on the
+	// released traces, telling whether the trace is believed to be complete. The possible values are "timeout",
+	// "root_detected", "evicted" and "shutdown_flush".
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
-	StoreOnDisk bool `mapstructure:"store_on_disk"`
+	MarkCompleteness bool `mapstructure:"mark_completeness"`
+
+	// MergeResourceSpans instructs the processor to coalesce the resource spans with equal resources
+	// when releasing a trace, so that the released trace has one resource spans per distinct resource.
+	// Default: true.
+	MergeResourceSpans bool `mapstructure:"merge_resource_spans"`
+
+	// StorageTimeout is the maximum duration of each operation against the storage, such as adding spans
+	// to a trace or retrieving a trace for its release.
+	// Default: 1s.
+	StorageTimeout time.Duration `mapstructure:"storage_timeout"`
+
+	// Storage is the kind of storage to use for the traces waiting for the duration. Valid values are "memory",
+	// "disk" and "redis". When "disk" is used, only the trace ID is kept in memory, with the trace spans being serialized to disk.
+	// Useful when the duration to wait for traces to complete is high, or when traces should survive a restart.
+	// When "redis" is used, the trace spans are kept in a Redis server, which can be shared by multiple collectors.
+	// Default: memory.
+	Storage string `mapstructure:"storage"`
+
+	// Directory is the directory where the disk storage keeps its files. Required when Storage is "disk".
+	Directory string `mapstructure:"directory"`
+
+	// Redis holds the settings for the Redis storage. Used only when Storage is "redis".
+	Redis RedisConfig `mapstructure:"redis"`
+}
+
+// RedisConfig is the configuration for the Redis storage.
+type RedisConfig struct {
+	// Endpoint is the address of the Redis server, in the host:port form.
+	// Default: localhost:6379.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Password is the optional password to use when connecting to the Redis server.
+	Password string `mapstructure:"password"`
+
+	// TLSSetting holds the TLS settings for the connection to the Redis server.
+	// Default: insecure, plain-text connection.
+	TLSSetting configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// KeyPrefix is prepended to the group key to form the key for each trace. Collectors sharing the same
+	// Redis server should use the same prefix.
+	// Default: "groupbytrace:".
+	KeyPrefix string `mapstructure:"key_prefix"`
+
+	// TTL is how long a trace is kept in Redis since the last span was appended to it, protecting against
+	// traces left behind by collectors that went away before releasing them. Should be slightly larger than
+	// the wait duration, or than the max wait duration when the WaitDurationAttribute is set, plus the jitter.
+	// Default: the longest wait duration plus the jitter plus 10s.
+	TTL time.Duration `mapstructure:"ttl"`
 }
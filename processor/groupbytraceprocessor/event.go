@@ -17,6 +17,7 @@ package groupbytraceprocessor
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opencensus.io/stats"
@@ -29,14 +30,23 @@ const (
 	// traces received from the previous processors
 	traceReceived eventType = iota
 
-	// traceID to be released
+	// key of the trace to be released
 	traceExpired
 
 	// released traces
 	traceReleased
 
-	// traceID to be removed
+	// key of the trace to be removed
 	traceRemoved
+
+	// trace evicted by the storage
+	traceEvicted
+
+	// logs received from the previous processors
+	logsReceived
+
+	// trace ID of the log group to be released
+	logGroupExpired
 )
 
 type eventType int
@@ -58,57 +68,113 @@ type eventMachine struct {
 
 	logger *zap.Logger
 
-	onBatchReceived func(pdata.Traces)
-	onTraceExpired  func(pdata.TraceID) error
-	onTraceReleased func([]pdata.ResourceSpans) error
-	onTraceRemoved  func(pdata.TraceID) error
+	// ctx is the context passed to the callbacks, canceled once the machine is shut down so that
+	// the operations still running can be aborted
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	onBatchReceived func(context.Context, pdata.Traces)
+	onTraceExpired  func(context.Context, string) error
+	onTraceReleased func(context.Context, []pdata.ResourceSpans) error
+	onTraceRemoved  func(context.Context, string) error
+	onTraceEvicted  func(context.Context, evictedTrace) error
+
+	onLogsReceived    func(context.Context, pdata.Logs)
+	onLogGroupExpired func(context.Context, string) error
+
+	// numEvents returns the number of events to report as the size of the queue. When nil, the size of
+	// the queue isn't reported by this machine.
+	numEvents func() int
 
 	onError func(event)
 
 	// shutdown sync
 	shutdownLock *sync.RWMutex
 	closed       bool
+	started      bool
+
+	// stopped is closed once the event loop has returned, after which no callbacks are running anymore
+	stopped chan struct{}
+
+	// metricsWG is marked as done once the periodic metrics goroutine has returned
+	metricsWG sync.WaitGroup
+
+	// pending is the number of events fired and not yet fully processed, including the one being processed,
+	// accessed atomically
+	pending int64
 }
 
 func newEventMachine(logger *zap.Logger, bufferSize int) *eventMachine {
+	ctx, cancel := context.WithCancel(context.Background())
 	em := &eventMachine{
 		logger:                    logger,
+		ctx:                       ctx,
+		cancel:                    cancel,
 		events:                    make(chan event, bufferSize),
 		close:                     make(chan struct{}),
 		shutdownLock:              &sync.RWMutex{},
+		stopped:                   make(chan struct{}),
 		metricsCollectionInterval: time.Second,
 		shutdownTimeout:           10 * time.Second,
 	}
+	em.numEvents = em.queueSize
 	return em
 }
 
 func (em *eventMachine) startInBackground() {
+	em.shutdownLock.Lock()
+	em.started = true
+	em.shutdownLock.Unlock()
+
 	go em.start()
+
+	em.metricsWG.Add(1)
 	go em.periodicMetrics()
 }
 
+// periodicMetrics records the size of the queue until the machine is shut down, when a final zero is recorded
 func (em *eventMachine) periodicMetrics() {
-	numEvents := len(em.events)
-	em.logger.Debug("recording current state of the queue", zap.Int("num-events", numEvents))
-	stats.Record(context.Background(), mNumEventsInQueue.M(int64(numEvents)))
+	defer em.metricsWG.Done()
 
-	em.shutdownLock.RLock()
-	closed := em.closed
-	em.shutdownLock.RUnlock()
-	if closed {
+	ticker := time.NewTicker(em.metricsCollectionInterval)
+	defer ticker.Stop()
+
+	em.recordMetrics()
+	for {
+		select {
+		case <-ticker.C:
+			em.recordMetrics()
+		case <-em.close:
+			if em.numEvents != nil {
+				// this machine isn't holding events anymore
+				stats.Record(context.Background(), mNumEventsInQueue.M(0))
+			}
+			return
+		}
+	}
+}
+
+func (em *eventMachine) recordMetrics() {
+	if em.numEvents == nil {
 		return
 	}
 
-	time.AfterFunc(em.metricsCollectionInterval, func() {
-		em.periodicMetrics()
-	})
+	numEvents := em.numEvents()
+	em.logger.Debug("recording current state of the queue", zap.Int("num-events", numEvents))
+	stats.Record(context.Background(), mNumEventsInQueue.M(int64(numEvents)))
+}
+
+func (em *eventMachine) queueSize() int {
+	return len(em.events)
 }
 
 func (em *eventMachine) start() {
+	defer close(em.stopped)
 	for {
 		select {
 		case e := <-em.events:
 			em.handleEvent(e)
+			atomic.AddInt64(&em.pending, -1)
 		case <-em.close:
 			return
 		}
@@ -130,8 +196,8 @@ func (em *eventMachine) handleEvent(e event) {
 			return
 		}
 
-		em.handleEventWithObservability("onBatchReceived", func() error {
-			em.onBatchReceived(payload)
+		em.handleEventWithObservability("onBatchReceived", func(ctx context.Context) error {
+			em.onBatchReceived(ctx, payload)
 			return nil
 		})
 	case traceExpired:
@@ -140,15 +206,15 @@ func (em *eventMachine) handleEvent(e event) {
 			em.callOnError(e)
 			return
 		}
-		payload, ok := e.payload.(pdata.TraceID)
+		payload, ok := e.payload.(string)
 		if !ok {
 			// the payload had an unexpected type!
 			em.callOnError(e)
 			return
 		}
 
-		em.handleEventWithObservability("onTraceExpired", func() error {
-			return em.onTraceExpired(payload)
+		em.handleEventWithObservability("onTraceExpired", func(ctx context.Context) error {
+			return em.onTraceExpired(ctx, payload)
 		})
 	case traceReleased:
 		if em.onTraceReleased == nil {
@@ -163,8 +229,8 @@ func (em *eventMachine) handleEvent(e event) {
 			return
 		}
 
-		em.handleEventWithObservability("onTraceReleased", func() error {
-			return em.onTraceReleased(payload)
+		em.handleEventWithObservability("onTraceReleased", func(ctx context.Context) error {
+			return em.onTraceReleased(ctx, payload)
 		})
 	case traceRemoved:
 		if em.onTraceRemoved == nil {
@@ -172,15 +238,64 @@ func (em *eventMachine) handleEvent(e event) {
 			em.callOnError(e)
 			return
 		}
-		payload, ok := e.payload.(pdata.TraceID)
+		payload, ok := e.payload.(string)
+		if !ok {
+			// the payload had an unexpected type!
+			em.callOnError(e)
+			return
+		}
+
+		em.handleEventWithObservability("onTraceRemoved", func(ctx context.Context) error {
+			return em.onTraceRemoved(ctx, payload)
+		})
+	case traceEvicted:
+		if em.onTraceEvicted == nil {
+			em.logger.Debug("onTraceEvicted not set, skipping event")
+			em.callOnError(e)
+			return
+		}
+		payload, ok := e.payload.(evictedTrace)
+		if !ok {
+			// the payload had an unexpected type!
+			em.callOnError(e)
+			return
+		}
+
+		em.handleEventWithObservability("onTraceEvicted", func(ctx context.Context) error {
+			return em.onTraceEvicted(ctx, payload)
+		})
+	case logsReceived:
+		if em.onLogsReceived == nil {
+			em.logger.Debug("onLogsReceived not set, skipping event")
+			em.callOnError(e)
+			return
+		}
+		payload, ok := e.payload.(pdata.Logs)
 		if !ok {
 			// the payload had an unexpected type!
 			em.callOnError(e)
 			return
 		}
 
-		em.handleEventWithObservability("onTraceRemoved", func() error {
-			return em.onTraceRemoved(payload)
+		em.handleEventWithObservability("onLogsReceived", func(ctx context.Context) error {
+			em.onLogsReceived(ctx, payload)
+			return nil
+		})
+	case logGroupExpired:
+		if em.onLogGroupExpired == nil {
+			em.logger.Debug("onLogGroupExpired not set, skipping event")
+			em.callOnError(e)
+			return
+		}
+		payload, ok := e.payload.(string)
+		if !ok {
+			// the payload had an unexpected type!
+			em.callOnError(e)
+			return
+		}
+
+		em.handleEventWithObservability("onLogGroupExpired", func(ctx context.Context) error {
+			return em.onLogGroupExpired(ctx, payload)
 		})
 	default:
 		em.logger.Info("unknown event type", zap.Any("event", e.typ))
@@ -189,24 +304,29 @@ func (em *eventMachine) handleEvent(e event) {
 	}
 }
 
-func (em *eventMachine) fire(events ...event) {
+// fire places the given events in the queue, returning false in case the events were discarded
+// because the machine has been shut down
+func (em *eventMachine) fire(events ...event) bool {
 	em.shutdownLock.RLock()
 	defer em.shutdownLock.RUnlock()
 
 	// we are not accepting new events
 	if em.closed {
-		return
+		return false
 	}
 
 	for _, e := range events {
+		atomic.AddInt64(&em.pending, 1)
 		em.events <- e
 	}
+	return true
 }
 
 func (em *eventMachine) shutdown() {
 	em.logger.Info("shutting down the event manager", zap.Int("pending-events", len(em.events)))
 	em.shutdownLock.Lock()
 	em.closed = true
+	started := em.started
 	em.shutdownLock.Unlock()
 
 	done := make(chan struct{})
@@ -214,7 +334,8 @@ func (em *eventMachine) shutdown() {
 	// we never return an error here
 	ok, _ := doWithTimeout(em.shutdownTimeout, func() error {
 		for {
-			if len(em.events) == 0 {
+			// the event being processed might still need the storage, so it's waited for as well
+			if atomic.LoadInt64(&em.pending) == 0 {
 				return nil
 			}
 			time.Sleep(100 * time.Millisecond)
@@ -231,8 +352,18 @@ func (em *eventMachine) shutdown() {
 
 	if !ok {
 		em.logger.Info("forcing the shutdown of the event manager", zap.Int("pending-events", len(em.events)))
+
+		// aborts the operations of the event being currently processed, if any
+		em.cancel()
 	}
 	close(em.close)
+
+	if started {
+		// wait for the event being currently processed, if any, and for the periodic metrics
+		<-em.stopped
+		em.metricsWG.Wait()
+	}
+	em.cancel()
 }
 
 func (em *eventMachine) callOnError(e event) {
@@ -242,14 +373,20 @@ func (em *eventMachine) callOnError(e event) {
 }
 
 // handleEventWithObservability uses the given function to process and event,
-// recording the event's latency and timing out if it doesn't finish within a reasonable duration
-func (em *eventMachine) handleEventWithObservability(event string, do func() error) {
+// recording the event's latency and timing out if it doesn't finish within a reasonable duration.
+// The context given to the function is canceled once it times out.
+func (em *eventMachine) handleEventWithObservability(event string, do func(context.Context) error) {
+	ctx, cancel := context.WithCancel(em.ctx)
+	defer cancel()
+
 	start := time.Now()
-	succeeded, err := doWithTimeout(time.Second, do)
+	succeeded, err := doWithTimeout(time.Second, func() error {
+		return do(ctx)
+	})
 	duration := time.Since(start)
 
-	ctx, _ := tag.New(context.Background(), tag.Upsert(tag.MustNewKey("event"), event))
-	stats.Record(ctx, mEventLatency.M(duration.Milliseconds()))
+	tagCtx, _ := tag.New(context.Background(), tag.Upsert(tag.MustNewKey("event"), event))
+	stats.Record(tagCtx, mEventLatency.M(duration.Milliseconds()))
 
 	logger := em.logger.With(zap.String("event", event))
 	if err != nil {
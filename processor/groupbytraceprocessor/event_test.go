@@ -15,6 +15,7 @@
 package groupbytraceprocessor
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/goleak"
 	"go.uber.org/zap"
 )
 
@@ -39,7 +41,7 @@ func TestEventCallback(t *testing.T) {
 			typ:      traceReceived,
 			payload:  pdata.NewTraces(),
 			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
-				em.onBatchReceived = func(expired pdata.Traces) {
+				em.onBatchReceived = func(_ context.Context, expired pdata.Traces) {
 					wg.Done()
 				}
 			},
@@ -47,11 +49,11 @@ func TestEventCallback(t *testing.T) {
 		{
 			casename: "onTraceExpired",
 			typ:      traceExpired,
-			payload:  pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+			payload:  pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
 			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
-				em.onTraceExpired = func(expired pdata.TraceID) error {
+				em.onTraceExpired = func(_ context.Context, expired string) error {
 					wg.Done()
-					assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}), expired)
+					assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), expired)
 					return nil
 				}
 			},
@@ -61,7 +63,7 @@ func TestEventCallback(t *testing.T) {
 			typ:      traceReleased,
 			payload:  []pdata.ResourceSpans{},
 			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
-				em.onTraceReleased = func(expired []pdata.ResourceSpans) error {
+				em.onTraceReleased = func(_ context.Context, expired []pdata.ResourceSpans) error {
 					wg.Done()
 					return nil
 				}
@@ -70,11 +72,45 @@ func TestEventCallback(t *testing.T) {
 		{
 			casename: "onTraceRemoved",
 			typ:      traceRemoved,
-			payload:  pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+			payload:  pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
 			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
-				em.onTraceRemoved = func(expired pdata.TraceID) error {
+				em.onTraceRemoved = func(_ context.Context, expired string) error {
 					wg.Done()
-					assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}), expired)
+					assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), expired)
+					return nil
+				}
+			},
+		},
+		{
+			casename: "onTraceEvicted",
+			typ:      traceEvicted,
+			payload:  evictedTrace{key: pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()},
+			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
+				em.onTraceEvicted = func(_ context.Context, evicted evictedTrace) error {
+					wg.Done()
+					assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), evicted.key)
+					return nil
+				}
+			},
+		},
+		{
+			casename: "onLogsReceived",
+			typ:      logsReceived,
+			payload:  pdata.NewLogs(),
+			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
+				em.onLogsReceived = func(_ context.Context, received pdata.Logs) {
+					wg.Done()
+				}
+			},
+		},
+		{
+			casename: "onLogGroupExpired",
+			typ:      logGroupExpired,
+			payload:  pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
+			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
+				em.onLogGroupExpired = func(_ context.Context, expired string) error {
+					wg.Done()
+					assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), expired)
 					return nil
 				}
 			},
@@ -126,6 +162,18 @@ func TestEventCallbackNotSet(t *testing.T) {
 			casename: "onTraceRemoved",
 			typ:      traceRemoved,
 		},
+		{
+			casename: "onTraceEvicted",
+			typ:      traceEvicted,
+		},
+		{
+			casename: "onLogsReceived",
+			typ:      logsReceived,
+		},
+		{
+			casename: "onLogGroupExpired",
+			typ:      logGroupExpired,
+		},
 	} {
 		t.Run(tt.casename, func(t *testing.T) {
 			// prepare
@@ -162,14 +210,14 @@ func TestEventInvalidPayload(t *testing.T) {
 			casename: "onTraceReceived",
 			typ:      traceReceived,
 			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
-				em.onBatchReceived = func(expired pdata.Traces) {}
+				em.onBatchReceived = func(_ context.Context, expired pdata.Traces) {}
 			},
 		},
 		{
 			casename: "onTraceExpired",
 			typ:      traceExpired,
 			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
-				em.onTraceExpired = func(expired pdata.TraceID) error {
+				em.onTraceExpired = func(_ context.Context, expired string) error {
 					return nil
 				}
 			},
@@ -178,7 +226,7 @@ func TestEventInvalidPayload(t *testing.T) {
 			casename: "onTraceReleased",
 			typ:      traceReleased,
 			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
-				em.onTraceReleased = func(expired []pdata.ResourceSpans) error {
+				em.onTraceReleased = func(_ context.Context, expired []pdata.ResourceSpans) error {
 					return nil
 				}
 			},
@@ -187,7 +235,32 @@ func TestEventInvalidPayload(t *testing.T) {
 			casename: "onTraceRemoved",
 			typ:      traceRemoved,
 			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
-				em.onTraceRemoved = func(expired pdata.TraceID) error {
+				em.onTraceRemoved = func(_ context.Context, expired string) error {
+					return nil
+				}
+			},
+		},
+		{
+			casename: "onTraceEvicted",
+			typ:      traceEvicted,
+			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
+				em.onTraceEvicted = func(_ context.Context, evicted evictedTrace) error {
+					return nil
+				}
+			},
+		},
+		{
+			casename: "onLogsReceived",
+			typ:      logsReceived,
+			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
+				em.onLogsReceived = func(_ context.Context, received pdata.Logs) {}
+			},
+		},
+		{
+			casename: "onLogGroupExpired",
+			typ:      logGroupExpired,
+			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
+				em.onLogGroupExpired = func(_ context.Context, expired string) error {
 					return nil
 				}
 			},
@@ -252,14 +325,14 @@ func TestEventShutdown(t *testing.T) {
 
 	traceReceivedFired, traceExpiredFired := false, false
 	em := newEventMachine(logger, 50)
-	em.onBatchReceived = func(pdata.Traces) {
+	em.onBatchReceived = func(context.Context, pdata.Traces) {
 		traceReceivedFired = true
 	}
-	em.onTraceExpired = func(pdata.TraceID) error {
+	em.onTraceExpired = func(context.Context, string) error {
 		traceExpiredFired = true
 		return nil
 	}
-	em.onTraceRemoved = func(pdata.TraceID) error {
+	em.onTraceRemoved = func(context.Context, string) error {
 		wg.Wait()
 		return nil
 	}
@@ -272,11 +345,11 @@ func TestEventShutdown(t *testing.T) {
 	})
 	em.fire(event{
 		typ:     traceRemoved,
-		payload: pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		payload: pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
 	})
 	em.fire(event{
 		typ:     traceRemoved,
-		payload: pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		payload: pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
 	})
 
 	time.Sleep(10 * time.Millisecond) // give it a bit of time to process the items
@@ -295,10 +368,11 @@ func TestEventShutdown(t *testing.T) {
 	assert.Len(t, em.events, 0)
 
 	// new events should *not* be processed
-	em.fire(event{
+	fired := em.fire(event{
 		typ:     traceExpired,
-		payload: pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		payload: pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
 	})
+	assert.False(t, fired)
 
 	// verify
 	assert.True(t, traceReceivedFired)
@@ -352,6 +426,7 @@ func TestPeriodicMetrics(t *testing.T) {
 	// test
 	em.fire(event{typ: traceReceived})
 	em.fire(event{typ: traceReceived}) // the first is consumed right away, the second is in the queue
+	em.metricsWG.Add(1)
 	go em.periodicMetrics()
 
 	// ensure our gauge is showing 1 item in the queue
@@ -364,11 +439,43 @@ func TestPeriodicMetrics(t *testing.T) {
 	// ensure our gauge is now showing no items in the queue
 	assertGauge(t, 0, mNumEventsInQueue)
 
-	// signal and wait for the recursive call to finish
-	em.shutdownLock.Lock()
-	em.closed = true
-	em.shutdownLock.Unlock()
-	time.Sleep(5 * time.Millisecond)
+	// signal and wait for the periodic metrics to finish
+	close(em.close)
+	em.metricsWG.Wait()
+}
+
+func TestShutdownStopsPeriodicMetrics(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	em := newEventMachine(logger, 50)
+	em.metricsCollectionInterval = time.Millisecond
+	em.numEvents = func() int {
+		// pretend that the queue is never empty, so that the final zero can be told apart
+		return 10
+	}
+	em.startInBackground()
+
+	// sanity check
+	assert.Eventually(t, func() bool {
+		viewData, err := view.RetrieveData("processor/groupbytrace/" + mNumEventsInQueue.Name())
+		return err == nil && len(viewData) == 1
+	}, time.Second, time.Millisecond)
+	assertGauge(t, 10, mNumEventsInQueue)
+
+	// test
+	em.shutdown()
+
+	// verify
+	// goleak checks that the periodic metrics goroutine is gone
+	assertGauge(t, 0, mNumEventsInQueue)
 }
 
 func TestForceShutdown(t *testing.T) {
@@ -390,6 +497,33 @@ func TestForceShutdown(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 }
 
+func TestShutdownCancelsRunningEvent(t *testing.T) {
+	// prepare
+	em := newEventMachine(logger, 50)
+	em.shutdownTimeout = 20 * time.Millisecond
+
+	started := make(chan struct{})
+	canceled := make(chan error, 1)
+	em.onTraceRemoved = func(ctx context.Context, _ string) error {
+		close(started)
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return ctx.Err()
+	}
+	em.startInBackground()
+	em.fire(event{
+		typ:     traceRemoved,
+		payload: pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
+	})
+	<-started
+
+	// test
+	em.shutdown()
+
+	// verify
+	assert.Equal(t, context.Canceled, <-canceled)
+}
+
 func TestDoWithTimeout(t *testing.T) {
 	// prepare
 	start := time.Now()
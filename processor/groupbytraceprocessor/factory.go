@@ -22,6 +22,7 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 )
@@ -29,16 +30,68 @@ import (
 const (
 	// typeStr is the value of "type" for this processor in the configuration.
 	typeStr configmodels.Type = "groupbytrace"
+
+	// the values accepted by the "storage" option
+	memoryStorageType = "memory"
+	diskStorageType   = "disk"
+	redisStorageType  = "redis"
+
+	// the values accepted by the "late_span_policy" option
+	forwardLateSpanPolicy  = "forward"
+	rebufferLateSpanPolicy = "rebuffer"
+
+	// the values accepted by the "truncated_spans_policy" option
+	dropTruncatedSpansPolicy    = "drop"
+	forwardTruncatedSpansPolicy = "forward"
+
+	// the values accepted by the "when_full" option
+	evictWhenFull  = "evict"
+	refuseWhenFull = "refuse"
+
+	// lateSpanAttribute is set to true on the late spans forwarded without being grouped
+	lateSpanAttribute = "groupbytrace.late"
+
+	// truncatedAttribute is set to true on the resources of the traces that reached "max_spans_per_trace"
+	truncatedAttribute = "groupbytrace.truncated"
+
+	// releaseReasonAttribute is set on the resources of the released traces when "mark_completeness" is enabled,
+	// with one of the release reasons below as its value
+	releaseReasonAttribute = "groupbytrace.release_reason"
+
+	releaseReasonTimeout       = "timeout"
+	releaseReasonRootDetected  = "root_detected"
+	releaseReasonEvicted       = "evicted"
+	releaseReasonShutdownFlush = "shutdown_flush"
 )
 
 var (
-	defaultWaitDuration   = time.Second
-	defaultNumTraces      = 1_000_000
-	defaultDiscardOrphans = false
-	defaultStoreOnDisk    = false
+	defaultWaitDuration         = time.Second
+	defaultMaxWaitDuration      = 5 * time.Minute
+	defaultMetricsFlushInterval = time.Second
+	defaultNumTraces            = 1_000_000
+	defaultNumShards            = 16
+	defaultNumWorkers           = 1
+	defaultWhenFull             = evictWhenFull
+	defaultDiscardOrphans       = false
+	defaultStorage              = memoryStorageType
+	defaultRedisEndpoint        = "localhost:6379"
+	defaultRedisKeyPrefix       = "groupbytrace:"
+	defaultRedisTTLMargin       = 10 * time.Second
+	defaultLateSpanPolicy       = forwardLateSpanPolicy
+	defaultTruncatedSpansPolicy = dropTruncatedSpansPolicy
+	defaultMergeResourceSpans   = true
+	defaultStorageTimeout       = time.Second
+	defaultLateSpanWaitDuration = time.Second
 
-	errDiskStorageNotSupported    = fmt.Errorf("option 'disk storage' not supported in this release")
 	errDiscardOrphansNotSupported = fmt.Errorf("option 'discard orphans' not supported in this release")
+	errDiskStorageNoDirectory     = fmt.Errorf("option 'directory' is required when using the disk storage")
+	errRedisTTLTooShort           = fmt.Errorf("option 'redis.ttl' should be larger than the wait duration")
+	errMaxSpansPerTraceReached    = fmt.Errorf("the trace reached the maximum number of spans")
+	errLogsOnlyInMemory           = fmt.Errorf("logs can only be grouped with the memory storage")
+	errOptionsOnlyInMemory        = fmt.Errorf("options 'max_bytes', 'dedup_spans' and 'max_spans_per_trace' are only supported with the memory storage")
+	errShuttingDown               = fmt.Errorf("the processor is shutting down")
+	errStorageStopped             = fmt.Errorf("the storage has been shut down")
+	errNumTracesReached           = fmt.Errorf("the maximum number of traces has been reached, try again later")
 )
 
 // NewFactory returns a new factory for the Filter processor.
@@ -49,7 +102,8 @@ func NewFactory() component.ProcessorFactory {
 	return processorhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		processorhelper.WithTraces(createTraceProcessor))
+		processorhelper.WithTraces(createTraceProcessor),
+		processorhelper.WithLogs(createLogsProcessor))
 }
 
 // createDefaultConfig creates the default configuration for the processor.
@@ -60,11 +114,34 @@ func createDefaultConfig() configmodels.Processor {
 			NameVal: string(typeStr),
 		},
 		NumTraces:    defaultNumTraces,
+		NumShards:    defaultNumShards,
+		NumWorkers:   defaultNumWorkers,
+		WhenFull:     defaultWhenFull,
 		WaitDuration: defaultWaitDuration,
 
+		MaxWaitDuration: defaultMaxWaitDuration,
+
+		TruncatedSpansPolicy: defaultTruncatedSpansPolicy,
+
+		MetricsFlushInterval: defaultMetricsFlushInterval,
+
+		LateSpanPolicy:       defaultLateSpanPolicy,
+		LateSpanWaitDuration: defaultLateSpanWaitDuration,
+
+		MergeResourceSpans: defaultMergeResourceSpans,
+
+		Storage:        defaultStorage,
+		StorageTimeout: defaultStorageTimeout,
+		Redis: RedisConfig{
+			Endpoint:  defaultRedisEndpoint,
+			KeyPrefix: defaultRedisKeyPrefix,
+			TLSSetting: configtls.TLSClientSetting{
+				Insecure: true,
+			},
+		},
+
 		// not supported for now
 		DiscardOrphans: defaultDiscardOrphans,
-		StoreOnDisk:    defaultStoreOnDisk,
 	}
 }
 
@@ -77,16 +154,98 @@ func createTraceProcessor(
 
 	oCfg := cfg.(*Config)
 
-	var st storage
-	if oCfg.StoreOnDisk {
-		return nil, errDiskStorageNotSupported
-	}
 	if oCfg.DiscardOrphans {
 		return nil, errDiscardOrphansNotSupported
 	}
 
-	// the only supported storage for now
-	st = newMemoryStorage()
+	switch oCfg.WhenFull {
+	case "", evictWhenFull, refuseWhenFull:
+	default:
+		return nil, fmt.Errorf("unknown when_full value %q", oCfg.WhenFull)
+	}
+
+	switch oCfg.LateSpanPolicy {
+	case "", forwardLateSpanPolicy, rebufferLateSpanPolicy:
+	default:
+		return nil, fmt.Errorf("unknown late span policy %q", oCfg.LateSpanPolicy)
+	}
+
+	switch oCfg.TruncatedSpansPolicy {
+	case "", dropTruncatedSpansPolicy, forwardTruncatedSpansPolicy:
+	default:
+		return nil, fmt.Errorf("unknown truncated spans policy %q", oCfg.TruncatedSpansPolicy)
+	}
+
+	if oCfg.Storage != "" && oCfg.Storage != memoryStorageType &&
+		(oCfg.MaxBytes > 0 || oCfg.DedupSpans || oCfg.MaxSpansPerTrace > 0) {
+		// these are enforced by the memory storage, and would be silently ignored by the other ones
+		return nil, errOptionsOnlyInMemory
+	}
+
+	var st storage
+	switch oCfg.Storage {
+	case "", memoryStorageType:
+		ms := newShardedMemoryStorage(oCfg.NumShards)
+		ms.maxBytes = oCfg.MaxBytes
+		ms.dedupSpans = oCfg.DedupSpans
+		ms.maxSpansPerTrace = oCfg.MaxSpansPerTrace
+		if oCfg.MetricsFlushInterval > 0 {
+			ms.metricsCollectionInterval = oCfg.MetricsFlushInterval
+		}
+		st = ms
+	case diskStorageType:
+		if oCfg.Directory == "" {
+			return nil, errDiskStorageNoDirectory
+		}
+		st = newDiskStorage(oCfg.Directory)
+	case redisStorageType:
+		// the traces with an overridden wait duration have to survive in Redis for longer
+		longestWait := oCfg.WaitDuration
+		if oCfg.WaitDurationAttribute != "" && oCfg.MaxWaitDuration > longestWait {
+			longestWait = oCfg.MaxWaitDuration
+		}
+		longestWait += oCfg.WaitDurationJitter
+
+		ttl := oCfg.Redis.TTL
+		if ttl == 0 {
+			ttl = longestWait + defaultRedisTTLMargin
+		}
+		if ttl <= longestWait {
+			return nil, errRedisTTLTooShort
+		}
+
+		rs, err := newRedisStorage(params.Logger, oCfg.Redis, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if oCfg.MetricsFlushInterval > 0 {
+			rs.metricsCollectionInterval = oCfg.MetricsFlushInterval
+		}
+		st = rs
+	default:
+		return nil, fmt.Errorf("unknown storage %q", oCfg.Storage)
+	}
 
 	return newGroupByTraceProcessor(params.Logger, st, nextConsumer, *oCfg), nil
 }
+
+// createLogsProcessor creates a logs processor based on this config.
+func createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.LogsConsumer) (component.LogsProcessor, error) {
+
+	oCfg := cfg.(*Config)
+
+	if oCfg.Storage != "" && oCfg.Storage != memoryStorageType {
+		return nil, errLogsOnlyInMemory
+	}
+
+	st := newMemoryLogsStorage()
+	if oCfg.MetricsFlushInterval > 0 {
+		st.metricsCollectionInterval = oCfg.MetricsFlushInterval
+	}
+
+	return newGroupByTraceLogsProcessor(params.Logger, st, nextConsumer, *oCfg), nil
+}
@@ -17,9 +17,11 @@ package groupbytraceprocessor
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 )
 
 func TestDefaultConfiguration(t *testing.T) {
@@ -28,9 +30,21 @@ func TestDefaultConfiguration(t *testing.T) {
 
 	// verify
 	assert.Equal(t, defaultNumTraces, c.NumTraces)
+	assert.Equal(t, defaultNumShards, c.NumShards)
+	assert.Equal(t, defaultNumWorkers, c.NumWorkers)
+	assert.Equal(t, defaultWhenFull, c.WhenFull)
 	assert.Equal(t, defaultWaitDuration, c.WaitDuration)
 	assert.Equal(t, defaultDiscardOrphans, c.DiscardOrphans)
-	assert.Equal(t, defaultStoreOnDisk, c.StoreOnDisk)
+	assert.Equal(t, defaultLateSpanPolicy, c.LateSpanPolicy)
+	assert.Equal(t, defaultLateSpanWaitDuration, c.LateSpanWaitDuration)
+	assert.Equal(t, defaultMaxWaitDuration, c.MaxWaitDuration)
+	assert.Equal(t, defaultTruncatedSpansPolicy, c.TruncatedSpansPolicy)
+	assert.Equal(t, defaultMergeResourceSpans, c.MergeResourceSpans)
+	assert.Equal(t, defaultStorage, c.Storage)
+	assert.Equal(t, defaultStorageTimeout, c.StorageTimeout)
+	assert.Equal(t, defaultRedisEndpoint, c.Redis.Endpoint)
+	assert.Equal(t, defaultRedisKeyPrefix, c.Redis.KeyPrefix)
+	assert.True(t, c.Redis.TLSSetting.Insecure)
 }
 
 func TestCreateTestProcessor(t *testing.T) {
@@ -68,17 +82,203 @@ func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
 			},
 			errDiscardOrphansNotSupported,
 		},
+	} {
+		p, err := f.CreateTracesProcessor(context.Background(), params, tt.config, next)
+
+		// verify
+		assert.Error(t, tt.expectedErr, err)
+		assert.Nil(t, p)
+	}
+}
+
+func TestCreateTestProcessorWithInvalidStorage(t *testing.T) {
+	// prepare
+	f := NewFactory()
+	params := component.ProcessorCreateParams{
+		Logger: logger,
+	}
+	next := &mockProcessor{}
+
+	// test
+	for _, tt := range []struct {
+		config      *Config
+		expectedErr error
+	}{
+		{
+			&Config{
+				Storage: diskStorageType,
+			},
+			errDiskStorageNoDirectory,
+		},
+		{
+			&Config{
+				Storage:      redisStorageType,
+				WaitDuration: time.Minute,
+				Redis: RedisConfig{
+					TTL: time.Second,
+				},
+			},
+			errRedisTTLTooShort,
+		},
+		{
+			&Config{
+				Storage:               redisStorageType,
+				WaitDuration:          time.Second,
+				WaitDurationAttribute: "groupbytrace.wait_ms",
+				MaxWaitDuration:       time.Minute,
+				Redis: RedisConfig{
+					TTL: 30 * time.Second,
+				},
+			},
+			errRedisTTLTooShort,
+		},
 		{
 			&Config{
-				StoreOnDisk: true,
+				Storage:          diskStorageType,
+				Directory:        "groupbytrace-storage",
+				MaxSpansPerTrace: 100,
 			},
-			errDiskStorageNotSupported,
+			errOptionsOnlyInMemory,
+		},
+		{
+			&Config{
+				Storage:      redisStorageType,
+				WaitDuration: time.Second,
+				MaxBytes:     1024,
+			},
+			errOptionsOnlyInMemory,
+		},
+		{
+			&Config{
+				Storage:      redisStorageType,
+				WaitDuration: time.Second,
+				DedupSpans:   true,
+			},
+			errOptionsOnlyInMemory,
+		},
+		{
+			&Config{
+				Storage: "invalid",
+			},
+			nil,
 		},
 	} {
 		p, err := f.CreateTracesProcessor(context.Background(), params, tt.config, next)
 
 		// verify
-		assert.Error(t, tt.expectedErr, err)
+		assert.Error(t, err)
+		if tt.expectedErr != nil {
+			assert.Equal(t, tt.expectedErr, err)
+		}
 		assert.Nil(t, p)
 	}
 }
+
+func TestCreateTestProcessorWithInvalidWhenFull(t *testing.T) {
+	// prepare
+	c := createDefaultConfig().(*Config)
+	c.WhenFull = "invalid"
+
+	params := component.ProcessorCreateParams{
+		Logger: logger,
+	}
+	next := &mockProcessor{}
+
+	// test
+	p, err := createTraceProcessor(context.Background(), params, c, next)
+
+	// verify
+	assert.Error(t, err)
+	assert.Nil(t, p)
+}
+
+func TestCreateTestProcessorWithInvalidLateSpanPolicy(t *testing.T) {
+	// prepare
+	c := createDefaultConfig().(*Config)
+	c.LateSpanPolicy = "invalid"
+
+	params := component.ProcessorCreateParams{
+		Logger: logger,
+	}
+	next := &mockProcessor{}
+
+	// test
+	p, err := createTraceProcessor(context.Background(), params, c, next)
+
+	// verify
+	assert.Error(t, err)
+	assert.Nil(t, p)
+}
+
+func TestCreateTestProcessorWithInvalidTruncatedSpansPolicy(t *testing.T) {
+	// prepare
+	c := createDefaultConfig().(*Config)
+	c.TruncatedSpansPolicy = "invalid"
+
+	params := component.ProcessorCreateParams{
+		Logger: logger,
+	}
+	next := &mockProcessor{}
+
+	// test
+	p, err := createTraceProcessor(context.Background(), params, c, next)
+
+	// verify
+	assert.Error(t, err)
+	assert.Nil(t, p)
+}
+
+func TestCreateTestProcessorWithDiskStorage(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	c.Storage = diskStorageType
+	// the directory is only used when the processor starts
+	c.Directory = "groupbytrace-storage"
+
+	params := component.ProcessorCreateParams{
+		Logger: logger,
+	}
+	next := &mockProcessor{}
+
+	// test
+	p, err := createTraceProcessor(context.Background(), params, c, next)
+
+	// verify
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+}
+
+func TestCreateLogsProcessor(t *testing.T) {
+	// prepare
+	f := NewFactory()
+	c := createDefaultConfig().(*Config)
+
+	params := component.ProcessorCreateParams{
+		Logger: logger,
+	}
+
+	// test
+	p, err := f.CreateLogsProcessor(context.Background(), params, c, consumertest.NewLogsNop())
+
+	// verify
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+}
+
+func TestCreateLogsProcessorWithUnsupportedStorage(t *testing.T) {
+	// prepare
+	f := NewFactory()
+	c := createDefaultConfig().(*Config)
+	c.Storage = diskStorageType
+	c.Directory = "groupbytrace-storage"
+
+	params := component.ProcessorCreateParams{
+		Logger: logger,
+	}
+
+	// test
+	p, err := f.CreateLogsProcessor(context.Background(), params, c, consumertest.NewLogsNop())
+
+	// verify
+	assert.Equal(t, errLogsOnlyInMemory, err)
+	assert.Nil(t, p)
+}
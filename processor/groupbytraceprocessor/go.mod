@@ -3,8 +3,12 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/processor/group
 go 1.14
 
 require (
+	github.com/alicebob/miniredis/v2 v2.14.1
+	github.com/dgraph-io/badger/v2 v2.2007.2
+	github.com/go-redis/redis/v7 v7.4.0
 	github.com/stretchr/testify v1.6.1
 	go.opencensus.io v0.22.5
 	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/goleak v1.1.10
 	go.uber.org/zap v1.16.0
 )
@@ -22,14 +22,35 @@ import (
 )
 
 var (
-	mNumTracesConf      = stats.Int64("processor_groupbytrace_conf_num_traces", "Maximum number of traces to hold in the internal storage", stats.UnitDimensionless)
-	mNumEventsInQueue   = stats.Int64("processor_groupbytrace_num_events_in_queue", "Number of events currently in the queue", stats.UnitDimensionless)
-	mNumTracesInMemory  = stats.Int64("processor_groupbytrace_num_traces_in_memory", "Number of traces currently in the in-memory storage", stats.UnitDimensionless)
-	mTracesEvicted      = stats.Int64("processor_groupbytrace_traces_evicted", "Traces evicted from the internal buffer", stats.UnitDimensionless)
-	mReleasedSpans      = stats.Int64("processor_groupbytrace_spans_released", "Spans released to the next consumer", stats.UnitDimensionless)
-	mReleasedTraces     = stats.Int64("processor_groupbytrace_traces_released", "Traces released to the next consumer", stats.UnitDimensionless)
-	mIncompleteReleases = stats.Int64("processor_groupbytrace_incomplete_releases", "Releases that are suspected to have been incomplete", stats.UnitDimensionless)
-	mEventLatency       = stats.Int64("processor_groupbytrace_event_latency", "How long the queue events are taking to be processed", stats.UnitMilliseconds)
+	mNumTracesConf            = stats.Int64("processor_groupbytrace_conf_num_traces", "Maximum number of traces to hold in the internal storage", stats.UnitDimensionless)
+	mNumEventsInQueue         = stats.Int64("processor_groupbytrace_num_events_in_queue", "Number of events currently in the queue", stats.UnitDimensionless)
+	mNumTracesInMemory        = stats.Int64("processor_groupbytrace_num_traces_in_memory", "Number of traces currently in the in-memory storage", stats.UnitDimensionless)
+	mTracesEvicted            = stats.Int64("processor_groupbytrace_traces_evicted", "Traces evicted from the internal buffer", stats.UnitDimensionless)
+	mRefusedSpans             = stats.Int64("processor_groupbytrace_refused_spans", "Spans refused because the maximum number of traces was reached", stats.UnitDimensionless)
+	mReleasedSpans            = stats.Int64("processor_groupbytrace_spans_released", "Spans released to the next consumer", stats.UnitDimensionless)
+	mReleasedTraces           = stats.Int64("processor_groupbytrace_traces_released", "Traces released to the next consumer", stats.UnitDimensionless)
+	mIncompleteReleases       = stats.Int64("processor_groupbytrace_incomplete_releases", "Releases that are suspected to have been incomplete", stats.UnitDimensionless)
+	mBytesInMemory            = stats.Int64("processor_groupbytrace_bytes_in_memory", "Approximate size of the traces currently in the in-memory storage", stats.UnitBytes)
+	mSpansInMemory            = stats.Int64("processor_groupbytrace_spans_in_memory", "Number of spans currently in the in-memory storage", stats.UnitDimensionless)
+	mEvictedTraces            = stats.Int64("processor_groupbytrace_evicted_traces", "Traces evicted from the in-memory storage due to the max bytes limit", stats.UnitDimensionless)
+	mEvictedBytes             = stats.Int64("processor_groupbytrace_evicted_bytes", "Approximate size of the traces evicted from the in-memory storage due to the max bytes limit", stats.UnitBytes)
+	mSpansPerTrace            = stats.Int64("processor_groupbytrace_spans_per_trace", "Number of spans in each trace released to the next consumer", stats.UnitDimensionless)
+	mSingleSpanReleases       = stats.Int64("processor_groupbytrace_single_span_traces_released", "Traces released to the next consumer with exactly one span", stats.UnitDimensionless)
+	mTracesDroppedOnShutdown  = stats.Int64("processor_groupbytrace_traces_dropped_on_shutdown", "Traces that couldn't be released to the next consumer before the shutdown deadline", stats.UnitDimensionless)
+	mLateSpans                = stats.Int64("processor_groupbytrace_late_spans", "Spans received within the grace period after their trace was released", stats.UnitDimensionless)
+	mWaitDurationOverrides    = stats.Int64("processor_groupbytrace_wait_duration_overrides", "Traces with their wait duration extended by a span attribute", stats.UnitDimensionless)
+	mReleaseTriggers          = stats.Int64("processor_groupbytrace_release_triggers", "Traces released on their own timers, by what triggered the release", stats.UnitDimensionless)
+	mStorageLatency           = stats.Float64("processor_groupbytrace_storage_latency", "How long the operations against the storage are taking", stats.UnitMilliseconds)
+	mStorageErrors            = stats.Int64("processor_groupbytrace_storage_errors", "Operations against the storage that failed", stats.UnitDimensionless)
+	mDuplicateSpansDropped    = stats.Int64("processor_groupbytrace_duplicate_spans_dropped", "Spans dropped for having the same span ID as a span already buffered for the trace", stats.UnitDimensionless)
+	mSpansTruncated           = stats.Int64("processor_groupbytrace_spans_truncated", "Spans not buffered as their trace reached the maximum number of spans", stats.UnitDimensionless)
+	mTracesReleasedByEviction = stats.Int64("processor_groupbytrace_traces_released_by_eviction", "Traces released to the next consumer before their time, as they had to be evicted", stats.UnitDimensionless)
+	mNumLogGroupsInMemory     = stats.Int64("processor_groupbytrace_num_log_groups_in_memory", "Number of groups of log records currently in the in-memory storage", stats.UnitDimensionless)
+	mReleasedLogRecords       = stats.Int64("processor_groupbytrace_log_records_released", "Log records released to the next consumer", stats.UnitDimensionless)
+	mReleasedLogGroups        = stats.Int64("processor_groupbytrace_log_groups_released", "Groups of log records released to the next consumer", stats.UnitDimensionless)
+	mLogGroupsEvicted         = stats.Int64("processor_groupbytrace_log_groups_evicted", "Groups of log records evicted from the internal buffer", stats.UnitDimensionless)
+	mSpansRefusedShutdown     = stats.Int64("processor_groupbytrace_spans_refused_shutdown", "Spans refused as the processor was shutting down", stats.UnitDimensionless)
+	mEventLatency             = stats.Int64("processor_groupbytrace_event_latency", "How long the queue events are taking to be processed", stats.UnitMilliseconds)
 )
 
 // MetricViews return the metrics views according to given telemetry level.
@@ -60,6 +81,12 @@ func MetricViews() []*view.View {
 			// sum allows us to start from 0, count will only show up if there's at least one eviction, which might take a while to happen (if ever!)
 			Aggregation: view.Sum(),
 		},
+		{
+			Name:        mRefusedSpans.Name(),
+			Measure:     mRefusedSpans,
+			Description: mRefusedSpans.Description(),
+			Aggregation: view.Sum(),
+		},
 		{
 			Name:        mReleasedSpans.Name(),
 			Measure:     mReleasedSpans,
@@ -78,6 +105,135 @@ func MetricViews() []*view.View {
 			Description: mIncompleteReleases.Description(),
 			Aggregation: view.Sum(),
 		},
+		{
+			Name:        mBytesInMemory.Name(),
+			Measure:     mBytesInMemory,
+			Description: mBytesInMemory.Description(),
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        mSpansInMemory.Name(),
+			Measure:     mSpansInMemory,
+			Description: mSpansInMemory.Description(),
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        mEvictedTraces.Name(),
+			Measure:     mEvictedTraces,
+			Description: mEvictedTraces.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mEvictedBytes.Name(),
+			Measure:     mEvictedBytes,
+			Description: mEvictedBytes.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mSpansPerTrace.Name(),
+			Measure:     mSpansPerTrace,
+			Description: mSpansPerTrace.Description(),
+			Aggregation: view.Distribution(1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000),
+		},
+		{
+			Name:        mSingleSpanReleases.Name(),
+			Measure:     mSingleSpanReleases,
+			Description: mSingleSpanReleases.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mTracesDroppedOnShutdown.Name(),
+			Measure:     mTracesDroppedOnShutdown,
+			Description: mTracesDroppedOnShutdown.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mLateSpans.Name(),
+			Measure:     mLateSpans,
+			Description: mLateSpans.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mWaitDurationOverrides.Name(),
+			Measure:     mWaitDurationOverrides,
+			Description: mWaitDurationOverrides.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mReleaseTriggers.Name(),
+			Measure:     mReleaseTriggers,
+			Description: mReleaseTriggers.Description(),
+			TagKeys: []tag.Key{
+				tag.MustNewKey("trigger"),
+			},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mStorageLatency.Name(),
+			Measure:     mStorageLatency,
+			Description: mStorageLatency.Description(),
+			TagKeys: []tag.Key{
+				tag.MustNewKey("operation"),
+			},
+			Aggregation: view.Distribution(0, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 50, 100, 500, 1000),
+		},
+		{
+			Name:        mStorageErrors.Name(),
+			Measure:     mStorageErrors,
+			Description: mStorageErrors.Description(),
+			TagKeys: []tag.Key{
+				tag.MustNewKey("operation"),
+			},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mDuplicateSpansDropped.Name(),
+			Measure:     mDuplicateSpansDropped,
+			Description: mDuplicateSpansDropped.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mSpansTruncated.Name(),
+			Measure:     mSpansTruncated,
+			Description: mSpansTruncated.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mTracesReleasedByEviction.Name(),
+			Measure:     mTracesReleasedByEviction,
+			Description: mTracesReleasedByEviction.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mNumLogGroupsInMemory.Name(),
+			Measure:     mNumLogGroupsInMemory,
+			Description: mNumLogGroupsInMemory.Description(),
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        mReleasedLogRecords.Name(),
+			Measure:     mReleasedLogRecords,
+			Description: mReleasedLogRecords.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mReleasedLogGroups.Name(),
+			Measure:     mReleasedLogGroups,
+			Description: mReleasedLogGroups.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mLogGroupsEvicted.Name(),
+			Measure:     mLogGroupsEvicted,
+			Description: mLogGroupsEvicted.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mSpansRefusedShutdown.Name(),
+			Measure:     mSpansRefusedShutdown,
+			Description: mSpansRefusedShutdown.Description(),
+			Aggregation: view.Sum(),
+		},
 		{
 			Name:        mEventLatency.Name(),
 			Measure:     mEventLatency,
@@ -26,9 +26,30 @@ func TestProcessorMetrics(t *testing.T) {
 		"processor/groupbytrace/processor_groupbytrace_num_events_in_queue",
 		"processor/groupbytrace/processor_groupbytrace_num_traces_in_memory",
 		"processor/groupbytrace/processor_groupbytrace_traces_evicted",
+		"processor/groupbytrace/processor_groupbytrace_refused_spans",
 		"processor/groupbytrace/processor_groupbytrace_spans_released",
 		"processor/groupbytrace/processor_groupbytrace_traces_released",
 		"processor/groupbytrace/processor_groupbytrace_incomplete_releases",
+		"processor/groupbytrace/processor_groupbytrace_bytes_in_memory",
+		"processor/groupbytrace/processor_groupbytrace_spans_in_memory",
+		"processor/groupbytrace/processor_groupbytrace_evicted_traces",
+		"processor/groupbytrace/processor_groupbytrace_evicted_bytes",
+		"processor/groupbytrace/processor_groupbytrace_spans_per_trace",
+		"processor/groupbytrace/processor_groupbytrace_single_span_traces_released",
+		"processor/groupbytrace/processor_groupbytrace_traces_dropped_on_shutdown",
+		"processor/groupbytrace/processor_groupbytrace_late_spans",
+		"processor/groupbytrace/processor_groupbytrace_wait_duration_overrides",
+		"processor/groupbytrace/processor_groupbytrace_release_triggers",
+		"processor/groupbytrace/processor_groupbytrace_storage_latency",
+		"processor/groupbytrace/processor_groupbytrace_storage_errors",
+		"processor/groupbytrace/processor_groupbytrace_duplicate_spans_dropped",
+		"processor/groupbytrace/processor_groupbytrace_spans_truncated",
+		"processor/groupbytrace/processor_groupbytrace_traces_released_by_eviction",
+		"processor/groupbytrace/processor_groupbytrace_num_log_groups_in_memory",
+		"processor/groupbytrace/processor_groupbytrace_log_records_released",
+		"processor/groupbytrace/processor_groupbytrace_log_groups_released",
+		"processor/groupbytrace/processor_groupbytrace_log_groups_evicted",
+		"processor/groupbytrace/processor_groupbytrace_spans_refused_shutdown",
 		"processor/groupbytrace/processor_groupbytrace_event_latency",
 	}
 
@@ -16,13 +16,23 @@ package groupbytraceprocessor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"go.uber.org/zap"
 )
 
@@ -35,227 +45,993 @@ import (
 // The typical data flow looks like this:
 // ConsumeTraces -> event(traceReceived) -> onBatchReceived -> AfterFunc(duration, event(traceExpired)) -> onTraceExpired
 // async markAsReleased -> event(traceReleased) -> onTraceReleased -> nextConsumer
-// This processor uses also a ring buffer to hold the in-flight trace IDs, so that we don't hold more than the given maximum number
-// of traces in memory/storage. Items that are evicted from the buffer are discarded without warning.
+// The spans are grouped by trace ID, unless a group-by attribute is configured, in which case the spans sharing the same
+// value for that attribute are grouped together, with the timer being per group key.
+// This processor uses also a ring buffer to hold the in-flight keys, so that we don't hold more than the given maximum number
+// of traces in memory/storage. Items that are evicted from the buffer are released to the next consumer right away, before
+// their time, being marked as evicted when the completeness is marked and counted as released by eviction.
+// The traces can be partitioned across a number of workers, each one with its own event machine and ring buffer. All the
+// events for a trace go to the same worker, based on the hash of the trace's key, so that they are still processed serially.
 type groupByTraceProcessor struct {
 	nextConsumer consumer.TracesConsumer
 	config       Config
 	logger       *zap.Logger
 
-	// the event machine handling all operations for this processor
+	// the workers, each one handling the operations for a partition of the traces
+	workers []*worker
+
+	// the trace storage
+	st storage
+
+	// the source of the current time and of the release timers
+	clock clock
+
+	// the releases that started but didn't get to the event machine, as well as the traces that
+	// couldn't be released because the event machine was shut down in the meantime
+	inFlightReleases sync.WaitGroup
+	pendingReleases  []string
+	pendingLock      sync.Mutex
+}
+
+// worker holds the state for a partition of the traces. Its fields are only touched by its event machine,
+// except when noted otherwise.
+type worker struct {
+	// the number of traces for which room has been reserved in the ring buffer, for the batches accepted by
+	// ConsumeTraces but not yet processed by the event machine. Accessed atomically, and only used when refusing
+	// spans when full.
+	reserved int64
+
+	// the event machine handling all operations for the traces in this partition
 	eventMachine *eventMachine
 
-	// the ring buffer holding the IDs for all the in-flight traces
+	// the ring buffer holding the keys for all the in-flight traces in this partition
 	ringBuffer *ringBuffer
 
-	// the trace storage
-	st storage
+	// the keys of the recently released traces, used to detect late spans. Nil when the grace period isn't set.
+	released *releasedKeys
+
+	// the release timers for the in-flight traces in this partition, so that they can be extended or brought
+	// forward. Nil when neither the wait duration attribute nor the quiet period are set.
+	releaseTimers map[string]*releaseTimer
+}
+
+// releaseTimer tracks when a trace is due to be released
+type releaseTimer struct {
+	// when the first spans for the trace arrived
+	receivedAt time.Time
+
+	// when the trace is due to be released, with timers firing before this being ignored
+	deadline time.Time
+
+	// whether the root span has been received, and when the latest spans arrived
+	rootReceived   bool
+	lastReceivedAt time.Time
+
+	// the number of timers that are still to fire for this key, which might outlive the trace itself
+	pending int
 }
 
 var _ component.TracesProcessor = (*groupByTraceProcessor)(nil)
 
 // newGroupByTraceProcessor returns a new processor.
 func newGroupByTraceProcessor(logger *zap.Logger, st storage, nextConsumer consumer.TracesConsumer, config Config) *groupByTraceProcessor {
-	// the event machine will buffer up to N concurrent events before blocking
-	eventMachine := newEventMachine(logger, 10000)
-
 	sp := &groupByTraceProcessor{
 		logger:       logger,
 		nextConsumer: nextConsumer,
 		config:       config,
-		eventMachine: eventMachine,
-		ringBuffer:   newRingBuffer(config.NumTraces),
 		st:           st,
+		clock:        systemClock{},
+	}
+
+	numWorkers := config.NumWorkers
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	// each worker holds an equal part of the traces, rounded up so that the limit isn't lowered
+	numTraces := (config.NumTraces + numWorkers - 1) / numWorkers
+
+	sp.workers = make([]*worker, numWorkers)
+	for i := range sp.workers {
+		// the event machine will buffer up to N concurrent events before blocking
+		eventMachine := newEventMachine(logger, 10000)
+		if config.MetricsFlushInterval > 0 {
+			eventMachine.metricsCollectionInterval = config.MetricsFlushInterval
+		}
+
+		// register the callbacks
+		eventMachine.onBatchReceived = sp.onBatchReceived
+		eventMachine.onTraceExpired = sp.onTraceExpired
+		eventMachine.onTraceReleased = sp.onTraceReleased
+		eventMachine.onTraceRemoved = sp.onTraceRemoved
+		eventMachine.onTraceEvicted = sp.onTraceEvicted
+
+		w := &worker{
+			eventMachine: eventMachine,
+			ringBuffer:   newRingBuffer(numTraces),
+		}
+		if config.LateSpanGracePeriod > 0 {
+			w.released = newReleasedKeys(numTraces)
+		}
+		if config.WaitDurationAttribute != "" || config.QuietPeriod > 0 {
+			w.releaseTimers = make(map[string]*releaseTimer)
+		}
+		sp.workers[i] = w
+	}
+
+	// only the first event machine records the size of the queue, as the sum for all the workers
+	sp.workers[0].eventMachine.numEvents = sp.numEventsInQueue
+	for _, w := range sp.workers[1:] {
+		w.eventMachine.numEvents = nil
+	}
+
+	if es, ok := st.(evictingStorage); ok {
+		es.setEvictionCallback(sp.onStorageEviction)
 	}
 
-	// register the callbacks
-	eventMachine.onBatchReceived = sp.onBatchReceived
-	eventMachine.onTraceExpired = sp.onTraceExpired
-	eventMachine.onTraceReleased = sp.onTraceReleased
-	eventMachine.onTraceRemoved = sp.onTraceRemoved
+	// all operations against the storage are measured, regardless of the backend
+	sp.st = newInstrumentedStorage(st)
 
 	return sp
 }
 
-func (sp *groupByTraceProcessor) ConsumeTraces(_ context.Context, td pdata.Traces) error {
-	sp.eventMachine.fire(event{
-		typ:     traceReceived,
-		payload: td,
-	})
+func (sp *groupByTraceProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if len(sp.workers) == 1 && sp.config.WhenFull != refuseWhenFull {
+		fired := sp.workers[0].eventMachine.fire(event{
+			typ:     traceReceived,
+			payload: td,
+		})
+		if !fired {
+			return sp.refuseOnShutdown(td.SpanCount())
+		}
+		return nil
+	}
+
+	// split the batch, so that each worker gets only the traces it's responsible for
+	batches := make([]pdata.Traces, len(sp.workers))
+	for i := range batches {
+		batches[i] = pdata.NewTraces()
+	}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		for _, batch := range splitByKey(td.ResourceSpans().At(i), sp.config.GroupByKey) {
+			batches[sp.workerIndex(batch.key)].ResourceSpans().Append(batch.rs)
+		}
+	}
+
+	if sp.config.WhenFull == refuseWhenFull && !sp.reserve(batches) {
+		stats.Record(ctx, mRefusedSpans.M(int64(td.SpanCount())))
+		return errNumTracesReached
+	}
+
+	refused := 0
+	for i, batch := range batches {
+		if batch.ResourceSpans().Len() == 0 {
+			continue
+		}
+		fired := sp.workers[i].eventMachine.fire(event{
+			typ:     traceReceived,
+			payload: batch,
+		})
+		if !fired {
+			sp.unreserve(sp.workers[i], batch.ResourceSpans().Len())
+			refused += batch.SpanCount()
+		}
+	}
+	if refused > 0 {
+		return sp.refuseOnShutdown(refused)
+	}
 	return nil
 }
 
+// reserve reserves room in the ring buffers for the traces of the given batches, one per worker, before they are
+// handed over to the event machines. This way, a full buffer is reported to the previous component, instead of the
+// spans being dropped once they get to the event machine. Nothing is reserved if any of the workers is out of room.
+// Each resource spans of a batch holds the spans of a single trace, and is counted as a new trace, as whether the
+// trace is in the buffer already is only known by the event machine.
+func (sp *groupByTraceProcessor) reserve(batches []pdata.Traces) bool {
+	for i, batch := range batches {
+		if batch.ResourceSpans().Len() == 0 || sp.workers[i].reserve(batch.ResourceSpans().Len()) {
+			continue
+		}
+
+		// the workers that had room get it back
+		for j := 0; j < i; j++ {
+			sp.unreserve(sp.workers[j], batches[j].ResourceSpans().Len())
+		}
+		return false
+	}
+	return true
+}
+
+// unreserve gives back the room reserved for the given number of traces
+func (sp *groupByTraceProcessor) unreserve(w *worker, numTraces int) {
+	if sp.config.WhenFull == refuseWhenFull {
+		atomic.AddInt64(&w.reserved, -int64(numTraces))
+	}
+}
+
+// reserve reserves room in the ring buffer for the given number of traces, returning false without reserving
+// anything when there isn't enough room. It's safe to be called from any goroutine.
+func (w *worker) reserve(numTraces int) bool {
+	for {
+		reserved := atomic.LoadInt64(&w.reserved)
+		if int64(w.ringBuffer.len())+reserved+int64(numTraces) > int64(w.ringBuffer.size) {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&w.reserved, reserved, reserved+int64(numTraces)) {
+			return true
+		}
+	}
+}
+
+// refuseOnShutdown records the spans that couldn't be accepted as the processor is shutting down, returning
+// the error for the previous component. Retrying won't help, so the error is permanent.
+func (sp *groupByTraceProcessor) refuseOnShutdown(numSpans int) error {
+	stats.Record(context.Background(), mSpansRefusedShutdown.M(int64(numSpans)))
+	return consumererror.Permanent(errShuttingDown)
+}
+
+// workerFor returns the worker responsible for the trace with the given key
+func (sp *groupByTraceProcessor) workerFor(key string) *worker {
+	return sp.workers[sp.workerIndex(key)]
+}
+
+func (sp *groupByTraceProcessor) workerIndex(key string) int {
+	if len(sp.workers) == 1 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	// the lower bits of FNV are poorly distributed, so we mix the higher bits in
+	sum := h.Sum32()
+	sum ^= sum >> 16
+	return int(sum % uint32(len(sp.workers)))
+}
+
+// numTracesInFlight returns the number of traces held by all the workers, as a whole. As each worker has its own part
+// of the num_traces, this isn't suitable for telling whether a given trace has room. It's safe to be called from any
+// goroutine.
+func (sp *groupByTraceProcessor) numTracesInFlight() int {
+	count := 0
+	for _, w := range sp.workers {
+		count += w.ringBuffer.len()
+	}
+	return count
+}
+
+func (sp *groupByTraceProcessor) numEventsInQueue() int {
+	count := 0
+	for _, w := range sp.workers {
+		count += len(w.eventMachine.events)
+	}
+	return count
+}
+
 func (sp *groupByTraceProcessor) GetCapabilities() component.ProcessorCapabilities {
 	return component.ProcessorCapabilities{MutatesConsumedData: true}
 }
 
 // Start is invoked during service startup.
-func (sp *groupByTraceProcessor) Start(context.Context, component.Host) error {
+func (sp *groupByTraceProcessor) Start(ctx context.Context, _ component.Host) error {
 	// start these metrics, as it might take a while for them to receive their first event
-	stats.Record(context.Background(), mTracesEvicted.M(0))
+	stats.Record(context.Background(), mTracesEvicted.M(0), mTracesReleasedByEviction.M(0))
 	stats.Record(context.Background(), mIncompleteReleases.M(0))
+	stats.Record(context.Background(), mEvictedTraces.M(0), mEvictedBytes.M(0))
+	stats.Record(context.Background(), mSingleSpanReleases.M(0))
+	stats.Record(context.Background(), mTracesDroppedOnShutdown.M(0), mSpansRefusedShutdown.M(0))
 	stats.Record(context.Background(), mNumTracesConf.M(int64(sp.config.NumTraces)))
 
-	sp.eventMachine.startInBackground()
-	return sp.st.start()
+	if err := sp.st.start(); err != nil {
+		return err
+	}
+
+	if err := sp.recoverTraces(ctx); err != nil {
+		return err
+	}
+
+	for _, w := range sp.workers {
+		w.eventMachine.startInBackground()
+	}
+	return nil
 }
 
-// Shutdown is invoked during service shutdown.
-func (sp *groupByTraceProcessor) Shutdown(_ context.Context) error {
-	sp.eventMachine.shutdown()
+// Shutdown is invoked during service shutdown. The traces still waiting in the storage are released
+// to the next consumer, as long as the given context isn't done.
+func (sp *groupByTraceProcessor) Shutdown(ctx context.Context) error {
+	// the workers are shut down in parallel, as each one might take a while to drain its queue
+	wg := sync.WaitGroup{}
+	for _, w := range sp.workers {
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			w.eventMachine.shutdown()
+		}(w)
+	}
+	wg.Wait()
+
+	sp.flush(ctx)
 	return sp.st.shutdown()
 }
 
-func (sp *groupByTraceProcessor) onBatchReceived(batch pdata.Traces) {
+// flush releases all the pending traces, without waiting for their duration to expire. The traces that
+// couldn't be released before the context is done are dropped. This is only called after the event machine
+// has been shut down, so that no other event can touch the ring buffer concurrently.
+func (sp *groupByTraceProcessor) flush(ctx context.Context) {
+	// wait for the releases that started before the shutdown
+	done := make(chan struct{})
+	go func() {
+		sp.inFlightReleases.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	sp.pendingLock.Lock()
+	var keys []string
+	for _, w := range sp.workers {
+		keys = append(keys, w.ringBuffer.all()...)
+	}
+	keys = append(keys, sp.pendingReleases...)
+	sp.pendingReleases = nil
+	sp.pendingLock.Unlock()
+
+	for i, key := range keys {
+		if ctx.Err() != nil {
+			dropped := len(keys) - i
+			stats.Record(context.Background(), mTracesDroppedOnShutdown.M(int64(dropped)))
+			sp.logger.Warn("couldn't release all the pending traces before the shutdown deadline",
+				zap.Int("dropped-traces", dropped))
+			return
+		}
+
+		sp.workerFor(key).ringBuffer.delete(key)
+		opCtx, cancel := sp.storageContext(ctx)
+		trace, err := sp.st.delete(opCtx, key)
+		cancel()
+		if err != nil {
+			sp.logger.Warn("couldn't retrieve trace from the storage during shutdown", zap.Error(err),
+				zap.String("key", key))
+			stats.Record(context.Background(), mTracesDroppedOnShutdown.M(1))
+			continue
+		}
+		if len(trace) == 0 {
+			// released by the event machine before the shutdown
+			continue
+		}
+
+		sp.markCompleteness(trace, releaseReasonShutdownFlush)
+		if err := sp.onTraceReleased(ctx, trace); err != nil {
+			sp.logger.Warn("couldn't release trace during shutdown", zap.Error(err),
+				zap.String("key", key))
+		}
+	}
+}
+
+func (sp *groupByTraceProcessor) onBatchReceived(ctx context.Context, batch pdata.Traces) {
 	for i := 0; i < batch.ResourceSpans().Len(); i++ {
-		sp.processResourceSpans(batch.ResourceSpans().At(i))
+		sp.processResourceSpans(ctx, batch.ResourceSpans().At(i))
 	}
 }
 
-func (sp *groupByTraceProcessor) processResourceSpans(rs pdata.ResourceSpans) {
-	for _, batch := range splitByTrace(rs) {
-		if err := sp.processBatch(batch); err != nil {
+func (sp *groupByTraceProcessor) processResourceSpans(ctx context.Context, rs pdata.ResourceSpans) {
+	for _, batch := range splitByKey(rs, sp.config.GroupByKey) {
+		if err := sp.processBatch(ctx, batch); err != nil {
 			sp.logger.Warn("failed to process batch", zap.Error(err),
-				zap.String("traceID", batch.traceID.HexString()))
+				zap.String("key", batch.key))
 		}
+
+		// the trace is in the ring buffer by now, if it needed room at all
+		sp.unreserve(sp.workerFor(batch.key), 1)
 	}
 }
 
-func (sp *groupByTraceProcessor) processBatch(batch *singleTraceBatch) error {
-	traceID := batch.traceID
-	if sp.ringBuffer.contains(traceID) {
+func (sp *groupByTraceProcessor) processBatch(ctx context.Context, batch *singleTraceBatch) error {
+	key := batch.key
+	w := sp.workerFor(key)
+	if w.ringBuffer.contains(key) {
 		// it exists in memory already, just append the spans to the trace in the storage
-		if err := sp.addSpans(traceID, batch.rs); err != nil {
+		if err := sp.addSpans(ctx, key, batch.rs); err != nil {
+			if errors.Is(err, errMaxSpansPerTraceReached) {
+				if err := sp.handleTruncatedSpans(ctx, key, batch.rs); err != nil {
+					return fmt.Errorf("couldn't forward spans for truncated trace: %w", err)
+				}
+				return nil
+			}
 			return fmt.Errorf("couldn't add spans to existing trace: %w", err)
 		}
 
+		// the new spans might ask for the trace to be held for longer, or complete it
+		sp.extendRelease(w, key, batch.rs)
+		sp.watchQuietPeriod(w, key, batch.rs)
+
 		// we are done with this trace, move on
 		return nil
 	}
 
+	waitDuration := sp.config.WaitDuration
+	if w.released != nil && w.released.releasedWithin(key, sp.clock.now(), sp.config.LateSpanGracePeriod) {
+		// the trace has been released already, and these spans arrived too late to be part of it
+		numSpans := batch.rs.InstrumentationLibrarySpans().At(0).Spans().Len()
+		stats.Record(context.Background(), mLateSpans.M(int64(numSpans)))
+
+		if sp.config.LateSpanPolicy != rebufferLateSpanPolicy {
+			if err := sp.forwardLateSpans(ctx, key, batch.rs); err != nil {
+				return fmt.Errorf("couldn't forward late spans: %w", err)
+			}
+			return nil
+		}
+
+		sp.logger.Debug("holding late spans for a short wait", zap.String("key", key))
+		waitDuration = sp.config.LateSpanWaitDuration
+	}
+
 	// at this point, we determined that we haven't seen the trace yet, so, record the
-	// traceID in the map and the spans to the storage
+	// key in the map and the spans to the storage
 
-	// place the trace ID in the buffer, and check if an item had to be evicted
-	evicted := sp.ringBuffer.put(traceID)
-	if !evicted.IsEmpty() {
-		// delete from the storage
-		sp.eventMachine.fire(event{
+	// when refusing spans when full, room has been reserved for the trace as its spans were accepted. Should the buffer
+	// be full anyway, the oldest trace is evicted, as the previous component has been told already that these spans
+	// were accepted.
+	evicted := ""
+	if sp.config.WhenFull != refuseWhenFull || !w.ringBuffer.putIfRoom(key) {
+		evicted = w.ringBuffer.put(key)
+	}
+	if evicted != "" {
+		// the trace ID was placed in the buffer, but an item had to be evicted
+		// release it from the storage before its time
+		delete(w.releaseTimers, evicted)
+		sp.recordReleased(evicted)
+		fired := w.eventMachine.fire(event{
 			typ:     traceRemoved,
 			payload: evicted,
 		})
+		if !fired {
+			// the processor is shutting down, and the trace is released along with the other pending ones
+			sp.addPendingRelease(evicted)
+		}
 
 		stats.Record(context.Background(), mTracesEvicted.M(1))
 
-		sp.logger.Info("trace evicted: in order to avoid this in the future, adjust the wait duration and/or number of traces to keep in memory",
-			zap.String("traceID", evicted.HexString()))
+		sp.logger.Info("trace evicted, releasing it before its time: in order to avoid this in the future, adjust the wait duration and/or number of traces to keep in memory",
+			zap.String("key", evicted))
 	}
 
-	// we have the traceID in the memory, place the spans in the storage too
-	if err := sp.addSpans(traceID, batch.rs); err != nil {
+	// we have the key in the memory, place the spans in the storage too
+	if err := sp.addSpans(ctx, key, batch.rs); err != nil {
 		return fmt.Errorf("couldn't add spans to new trace: %w", err)
 	}
 
-	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", sp.config.WaitDuration))
+	if override := sp.waitDurationOverride(batch.rs); override > waitDuration {
+		sp.logger.Debug("overriding the wait duration for trace",
+			zap.String("key", key), zap.Duration("duration", override))
+		stats.Record(context.Background(), mWaitDurationOverrides.M(1))
+		waitDuration = override
+	}
+	waitDuration += sp.releaseJitter()
+
+	if w.releaseTimers != nil {
+		now := sp.clock.now()
+		timer := &releaseTimer{receivedAt: now, deadline: now.Add(waitDuration)}
+		if previous, ok := w.releaseTimers[key]; ok {
+			// the timers for a previous trace with the same key are yet to fire
+			timer.pending = previous.pending
+		}
+		w.releaseTimers[key] = timer
+	}
+	sp.scheduleTrackedRelease(w, key, waitDuration)
+	sp.watchQuietPeriod(w, key, batch.rs)
+
+	return nil
+}
+
+// recoverTraces places the traces that survived in the storage since the last run into the ring buffer,
+// scheduling them to be released. This is called before the event machine is started, so that no other
+// event can touch the ring buffer concurrently.
+func (sp *groupByTraceProcessor) recoverTraces(ctx context.Context) error {
+	rs, ok := sp.st.(recoverableStorage)
+	if !ok {
+		return nil
+	}
+
+	keys, err := rs.keys()
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve the traces from the storage: %w", err)
+	}
 
-	time.AfterFunc(sp.config.WaitDuration, func() {
+	for _, key := range keys {
+		if evicted := sp.workerFor(key).ringBuffer.put(key); evicted != "" {
+			// the event machine isn't running yet, so we release the trace from the storage directly
+			stats.Record(context.Background(), mTracesEvicted.M(1))
+			if err := sp.onTraceRemoved(ctx, evicted); err != nil {
+				return err
+			}
+		}
+		sp.scheduleRelease(key, sp.config.WaitDuration+sp.releaseJitter())
+	}
+
+	if len(keys) > 0 {
+		sp.logger.Info("recovered traces from the storage", zap.Int("num-traces", len(keys)))
+	}
+
+	return nil
+}
+
+// releaseJitter returns a random offset between zero and the configured jitter, so that the traces received
+// at the same time are released at different times
+func (sp *groupByTraceProcessor) releaseJitter() time.Duration {
+	if sp.config.WaitDurationJitter <= 0 {
+		return 0
+	}
+	// the top-level functions from math/rand are safe to be called concurrently by the workers
+	return time.Duration(rand.Int63n(int64(sp.config.WaitDurationJitter) + 1))
+}
+
+func (sp *groupByTraceProcessor) scheduleRelease(key string, duration time.Duration) {
+	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", duration))
+
+	sp.clock.afterFunc(duration, func() {
 		// if the event machine has stopped, it will just discard the event
-		sp.eventMachine.fire(event{
+		sp.workerFor(key).eventMachine.fire(event{
 			typ:     traceExpired,
-			payload: traceID,
+			payload: key,
 		})
 	})
-
-	return nil
 }
 
-func (sp *groupByTraceProcessor) onTraceExpired(traceID pdata.TraceID) error {
-	sp.logger.Debug("processing expired", zap.String("traceID",
-		traceID.HexString()))
+func (sp *groupByTraceProcessor) onTraceExpired(_ context.Context, key string) error {
+	sp.logger.Debug("processing expired", zap.String("key", key))
+
+	w := sp.workerFor(key)
+	timer, tracked := w.releaseTimers[key]
+	if tracked {
+		timer.pending--
+	}
+
+	if !w.ringBuffer.contains(key) {
+		if tracked {
+			// one of the other timers for this key has released the trace already
+			if timer.pending <= 0 {
+				delete(w.releaseTimers, key)
+			}
+			return nil
+		}
 
-	if !sp.ringBuffer.contains(traceID) {
 		// we likely received multiple batches with spans for the same trace
 		// and released this trace already
 		sp.logger.Debug("skipping the processing of expired trace",
-			zap.String("traceID", traceID.HexString()))
+			zap.String("key", key))
 
 		stats.Record(context.Background(), mIncompleteReleases.M(1))
 		return nil
 	}
 
+	trigger, reason := "timeout", releaseReasonTimeout
+	if tracked {
+		now := sp.clock.now()
+		switch {
+		case timer.rootReceived && now.Sub(timer.lastReceivedAt) >= sp.config.QuietPeriod && now.Before(timer.deadline):
+			trigger, reason = "root_span", releaseReasonRootDetected
+		case now.Before(timer.deadline):
+			// the release has been postponed or the trace isn't quiet yet, and a later timer will fire for this trace
+			return nil
+		}
+
+		if timer.pending <= 0 {
+			delete(w.releaseTimers, key)
+		}
+	}
+
+	tagCtx, _ := tag.New(context.Background(), tag.Upsert(tag.MustNewKey("trigger"), trigger))
+	stats.Record(tagCtx, mReleaseTriggers.M(1))
+
 	// delete from the map and erase its memory entry
-	sp.ringBuffer.delete(traceID)
+	w.ringBuffer.delete(key)
+	sp.recordReleased(key)
 
 	// this might block, but we don't need to wait
 	sp.logger.Debug("marking the trace as released",
-		zap.String("traceID", traceID.HexString()))
-	go sp.markAsReleased(traceID)
+		zap.String("key", key))
+	sp.inFlightReleases.Add(1)
+	go func() {
+		defer sp.inFlightReleases.Done()
+		sp.markAsReleased(key, reason)
+	}()
 
 	return nil
 }
 
-func (sp *groupByTraceProcessor) markAsReleased(traceID pdata.TraceID) error {
-	// #get is a potentially blocking operation
-	trace, err := sp.st.get(traceID)
+func (sp *groupByTraceProcessor) markAsReleased(key string, reason string) error {
+	// this runs outside of the event machine, but is still bound to its lifecycle
+	eventMachine := sp.workerFor(key).eventMachine
+	ctx, cancel := sp.storageContext(eventMachine.ctx)
+	defer cancel()
+
+	// #delete is a potentially blocking operation, and hands the trace over to us without copying it
+	trace, err := sp.st.delete(ctx, key)
 	if err != nil {
-		return fmt.Errorf("couldn't retrieve trace %q from the storage: %w", traceID, err)
+		if eventMachine.ctx.Err() != nil {
+			// the removal was aborted by the shutdown, leave it to be released by the shutdown
+			sp.addPendingRelease(key)
+			return nil
+		}
+		return fmt.Errorf("couldn't retrieve trace %q from the storage: %w", key, err)
 	}
 
 	if trace == nil {
-		return fmt.Errorf("the trace %q couldn't be found at the storage", traceID)
+		return fmt.Errorf("the trace %q couldn't be found at the storage", key)
 	}
 
 	// signal that the trace is ready to be released
-	sp.logger.Debug("trace marked as released", zap.String("traceID", traceID.HexString()))
+	sp.logger.Debug("trace marked as released", zap.String("key", key))
+	sp.markCompleteness(trace, reason)
 
-	// atomically fire the two events, so that a concurrent shutdown won't leave
-	// an orphaned trace in the storage
-	sp.eventMachine.fire(event{
+	fired := eventMachine.fire(event{
 		typ:     traceReleased,
 		payload: trace,
-	}, event{
-		typ:     traceRemoved,
-		payload: traceID,
 	})
+
+	if !fired {
+		// the event machine has been shut down in the meantime, and the trace isn't in the storage anymore.
+		// The shutdown waits for this release before returning.
+		if err := sp.onTraceReleased(context.Background(), trace); err != nil {
+			return fmt.Errorf("couldn't release trace %q during shutdown: %w", key, err)
+		}
+	}
 	return nil
 }
 
-func (sp *groupByTraceProcessor) onTraceReleased(rss []pdata.ResourceSpans) error {
+func (sp *groupByTraceProcessor) addPendingRelease(key string) {
+	sp.pendingLock.Lock()
+	sp.pendingReleases = append(sp.pendingReleases, key)
+	sp.pendingLock.Unlock()
+}
+
+func (sp *groupByTraceProcessor) onTraceReleased(_ context.Context, rss []pdata.ResourceSpans) error {
+	if sp.config.MergeResourceSpans {
+		rss = mergeResourceSpans(rss)
+	}
+
 	trace := pdata.NewTraces()
 	for _, rs := range rss {
 		trace.ResourceSpans().Append(rs)
 	}
-	stats.Record(context.Background(), mReleasedSpans.M(int64(trace.SpanCount())))
+	spanCount := trace.SpanCount()
+	stats.Record(context.Background(), mReleasedSpans.M(int64(spanCount)))
 	stats.Record(context.Background(), mReleasedTraces.M(1))
+	stats.Record(context.Background(), mSpansPerTrace.M(int64(spanCount)))
+	if spanCount == 1 {
+		// might indicate that the wait duration is too short for the spans of the trace to arrive
+		stats.Record(context.Background(), mSingleSpanReleases.M(1))
+	}
 	return sp.nextConsumer.ConsumeTraces(context.Background(), trace)
 }
 
-func (sp *groupByTraceProcessor) onTraceRemoved(traceID pdata.TraceID) error {
-	trace, err := sp.st.delete(traceID)
+// onTraceRemoved releases the trace evicted from the buffer, earlier than planned. It's released right away instead
+// of via a new event, as this machine might be unable to take more events while processing this one.
+func (sp *groupByTraceProcessor) onTraceRemoved(ctx context.Context, key string) error {
+	opCtx, cancel := sp.storageContext(ctx)
+	defer cancel()
+
+	trace, err := sp.st.delete(opCtx, key)
 	if err != nil {
-		return fmt.Errorf("couldn't delete trace %q from the storage: %w", traceID.HexString(), err)
+		return fmt.Errorf("couldn't delete trace %q from the storage: %w", key, err)
 	}
 
 	if trace == nil {
-		return fmt.Errorf("trace %q not found at the storage", traceID.HexString())
+		return fmt.Errorf("trace %q not found at the storage", key)
 	}
 
-	return nil
+	stats.Record(context.Background(), mTracesReleasedByEviction.M(1))
+	sp.markCompleteness(trace, releaseReasonEvicted)
+	return sp.onTraceReleased(ctx, trace)
 }
 
-func (sp *groupByTraceProcessor) addSpans(traceID pdata.TraceID, trace pdata.ResourceSpans) error {
-	sp.logger.Debug("creating trace at the storage", zap.String("traceID", traceID.HexString()))
-	return sp.st.createOrAppend(traceID, trace)
+// markCompleteness sets the reason for the release on the resources of the given trace, when enabled. The resource
+// spans should have been taken out of the storage already, so that the attribute doesn't end up in the stored copies.
+func (sp *groupByTraceProcessor) markCompleteness(rss []pdata.ResourceSpans, reason string) {
+	if !sp.config.MarkCompleteness {
+		return
+	}
+	for _, rs := range rss {
+		rs.Resource().Attributes().UpsertString(releaseReasonAttribute, reason)
+	}
+}
+
+// mergeResourceSpans coalesces the resource spans with equal resources into the first one of them, which then
+// holds the instrumentation library spans from all of them. The order of the distinct resources is kept.
+func mergeResourceSpans(rss []pdata.ResourceSpans) []pdata.ResourceSpans {
+	if len(rss) < 2 {
+		return rss
+	}
+
+	var result []pdata.ResourceSpans
+	var keys resourceKeyBuilder
+	byResource := make(map[string]pdata.ResourceSpans)
+	for _, rs := range rss {
+		key := keys.build(rs.Resource())
+
+		// the conversion from the byte slice doesn't allocate when it's only used for the lookup
+		merged, ok := byResource[string(key)]
+		if !ok {
+			byResource[string(key)] = rs
+			result = append(result, rs)
+			continue
+		}
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for i := 0; i < ilss.Len(); i++ {
+			merged.InstrumentationLibrarySpans().Append(ilss.At(i))
+		}
+	}
+
+	return result
+}
+
+// resourceKeyBuilder builds a representation of the resources' attributes, sorted by their keys, so that
+// resources with equal attributes have the same representation regardless of the order of the attributes.
+// The buffers are reused across calls, keeping the allocations down for traces with many resource spans.
+type resourceKeyBuilder struct {
+	attrKeys []string
+	buf      []byte
+}
+
+// build returns the representation of the given resource, valid only until the next call
+func (b *resourceKeyBuilder) build(resource pdata.Resource) []byte {
+	attrs := resource.Attributes()
+
+	b.attrKeys = b.attrKeys[:0]
+	attrs.ForEach(func(k string, _ pdata.AttributeValue) {
+		b.attrKeys = append(b.attrKeys, k)
+	})
+	sort.Strings(b.attrKeys)
+
+	b.buf = b.buf[:0]
+	for _, k := range b.attrKeys {
+		v, _ := attrs.Get(k)
+
+		// the type is part of the key, so that a string "1" isn't taken as equal to an int 1
+		b.buf = append(b.buf, k...)
+		b.buf = append(b.buf, 0, byte(v.Type()))
+		switch v.Type() {
+		case pdata.AttributeValueSTRING:
+			b.buf = append(b.buf, v.StringVal()...)
+		case pdata.AttributeValueINT:
+			b.buf = strconv.AppendInt(b.buf, v.IntVal(), 10)
+		case pdata.AttributeValueDOUBLE:
+			b.buf = strconv.AppendFloat(b.buf, v.DoubleVal(), 'g', -1, 64)
+		case pdata.AttributeValueBOOL:
+			b.buf = strconv.AppendBool(b.buf, v.BoolVal())
+		default:
+			b.buf = append(b.buf, tracetranslator.AttributeValueToString(v, true)...)
+		}
+		b.buf = append(b.buf, 0)
+	}
+
+	return b.buf
+}
+
+// onStorageEviction is called by the storage when it had to evict a trace on its own, such as when the storage
+// is full. The trace is already gone from the storage at this point, and is handed over to the worker responsible
+// for it, which might not be the one that caused the eviction.
+func (sp *groupByTraceProcessor) onStorageEviction(key string, rss []pdata.ResourceSpans) {
+	fired := sp.workerFor(key).eventMachine.fire(event{
+		typ:     traceEvicted,
+		payload: evictedTrace{key: key, rss: rss},
+	})
+
+	if !fired {
+		// the workers have been shut down, and this is the only goroutine touching their state
+		if err := sp.onTraceEvicted(context.Background(), evictedTrace{key: key, rss: rss}); err != nil {
+			sp.logger.Warn("couldn't release evicted trace", zap.Error(err), zap.String("key", key))
+		}
+	}
+}
+
+// onTraceEvicted forwards the given evicted trace as it is to the next consumer
+func (sp *groupByTraceProcessor) onTraceEvicted(ctx context.Context, evicted evictedTrace) error {
+	w := sp.workerFor(evicted.key)
+	w.ringBuffer.delete(evicted.key)
+	delete(w.releaseTimers, evicted.key)
+	sp.recordReleased(evicted.key)
+
+	sp.logger.Info("trace evicted from the storage, releasing it before its time: in order to avoid this in the future, adjust the max bytes and/or the wait duration",
+		zap.String("key", evicted.key))
+
+	stats.Record(context.Background(), mTracesReleasedByEviction.M(1))
+	sp.markCompleteness(evicted.rss, releaseReasonEvicted)
+	return sp.onTraceReleased(ctx, evicted.rss)
+}
+
+// extendRelease postpones the release of an in-flight trace when the given spans ask for a longer wait
+// duration than the one already in place. The wait duration is counted from when the first spans
+// for the trace arrived.
+func (sp *groupByTraceProcessor) extendRelease(w *worker, key string, rs pdata.ResourceSpans) {
+	timer, ok := w.releaseTimers[key]
+	if !ok {
+		return
+	}
+
+	override := sp.waitDurationOverride(rs)
+	deadline := timer.receivedAt.Add(override)
+	if !deadline.After(timer.deadline) {
+		// the override never shortens the wait
+		return
+	}
+
+	sp.logger.Debug("extending the wait duration for trace",
+		zap.String("key", key), zap.Duration("duration", override))
+	stats.Record(context.Background(), mWaitDurationOverrides.M(1))
+
+	timer.deadline = deadline
+	sp.scheduleTrackedRelease(w, key, deadline.Sub(sp.clock.now()))
+}
+
+// watchQuietPeriod keeps track of the spans arriving for a trace, scheduling it to be released early once its
+// root span has been received and no new spans arrive for the quiet period
+func (sp *groupByTraceProcessor) watchQuietPeriod(w *worker, key string, rs pdata.ResourceSpans) {
+	if sp.config.QuietPeriod <= 0 {
+		return
+	}
+
+	timer, ok := w.releaseTimers[key]
+	if !ok {
+		return
+	}
+
+	timer.lastReceivedAt = sp.clock.now()
+	if !timer.rootReceived && !hasRootSpan(rs) {
+		return
+	}
+	timer.rootReceived = true
+
+	// each new batch restarts the quiet period, with the earlier timers finding out that the trace isn't quiet yet
+	sp.scheduleTrackedRelease(w, key, sp.config.QuietPeriod)
+}
+
+// scheduleTrackedRelease schedules the release of the trace, keeping count of the timers for it when
+// its release timer is tracked
+func (sp *groupByTraceProcessor) scheduleTrackedRelease(w *worker, key string, duration time.Duration) {
+	if timer, ok := w.releaseTimers[key]; ok {
+		timer.pending++
+	}
+	sp.scheduleRelease(key, duration)
+}
+
+// hasRootSpan returns whether any of the spans in the given resource spans has no parent
+func hasRootSpan(rs pdata.ResourceSpans) bool {
+	for i := 0; i < rs.InstrumentationLibrarySpans().Len(); i++ {
+		spans := rs.InstrumentationLibrarySpans().At(i).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			if spans.At(j).ParentSpanID().IsEmpty() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// waitDurationOverride returns the longest wait duration requested by the spans in the given resource spans,
+// clamped to the max wait duration, or zero when none of the spans has the wait duration attribute
+func (sp *groupByTraceProcessor) waitDurationOverride(rs pdata.ResourceSpans) time.Duration {
+	if sp.config.WaitDurationAttribute == "" {
+		return 0
+	}
+
+	var result time.Duration
+	for i := 0; i < rs.InstrumentationLibrarySpans().Len(); i++ {
+		spans := rs.InstrumentationLibrarySpans().At(i).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			v, ok := spans.At(j).Attributes().Get(sp.config.WaitDurationAttribute)
+			if !ok {
+				continue
+			}
+
+			millis, ok := attributeValueMillis(v)
+			if !ok {
+				sp.logger.Debug("ignoring invalid wait duration attribute",
+					zap.String("value", tracetranslator.AttributeValueToString(v, false)))
+				continue
+			}
+
+			if d := time.Duration(millis * float64(time.Millisecond)); d > result {
+				result = d
+			}
+		}
+	}
+
+	if sp.config.MaxWaitDuration > 0 && result > sp.config.MaxWaitDuration {
+		result = sp.config.MaxWaitDuration
+	}
+	return result
+}
+
+// attributeValueMillis returns the number of milliseconds held by the given attribute value, which can be
+// a number or a string with a number
+func attributeValueMillis(v pdata.AttributeValue) (float64, bool) {
+	switch v.Type() {
+	case pdata.AttributeValueINT:
+		return float64(v.IntVal()), true
+	case pdata.AttributeValueDOUBLE:
+		return v.DoubleVal(), true
+	case pdata.AttributeValueSTRING:
+		millis, err := strconv.ParseFloat(v.StringVal(), 64)
+		return millis, err == nil
+	}
+	return 0, false
+}
+
+// recordReleased remembers the key of a released trace, so that late spans for it can be detected
+func (sp *groupByTraceProcessor) recordReleased(key string) {
+	if released := sp.workerFor(key).released; released != nil {
+		released.record(key, sp.clock.now())
+	}
+}
+
+// handleTruncatedSpans drops or forwards, according to the truncated spans policy, the spans refused by the storage
+// for a trace that reached the max spans per trace. Like the late spans, the forwarded spans are released directly.
+func (sp *groupByTraceProcessor) handleTruncatedSpans(ctx context.Context, key string, rs pdata.ResourceSpans) error {
+	if sp.config.TruncatedSpansPolicy != forwardTruncatedSpansPolicy {
+		sp.logger.Debug("dropping spans for truncated trace", zap.String("key", key))
+		return nil
+	}
+
+	sp.logger.Debug("forwarding spans for truncated trace", zap.String("key", key))
+	rs.Resource().Attributes().UpsertBool(truncatedAttribute, true)
+	return sp.onTraceReleased(ctx, []pdata.ResourceSpans{rs})
+}
+
+// forwardLateSpans releases the given late spans right away, marking them as late. They are released directly
+// instead of via a new event, as this runs within the event machine, which might be unable to take more events
+// while processing this one, or be shut down already.
+func (sp *groupByTraceProcessor) forwardLateSpans(ctx context.Context, key string, rs pdata.ResourceSpans) error {
+	sp.logger.Debug("forwarding late spans", zap.String("key", key))
+
+	for i := 0; i < rs.InstrumentationLibrarySpans().Len(); i++ {
+		spans := rs.InstrumentationLibrarySpans().At(i).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			spans.At(j).Attributes().UpsertBool(lateSpanAttribute, true)
+		}
+	}
+
+	return sp.onTraceReleased(ctx, []pdata.ResourceSpans{rs})
+}
+
+func (sp *groupByTraceProcessor) addSpans(ctx context.Context, key string, trace pdata.ResourceSpans) error {
+	sp.logger.Debug("creating trace at the storage", zap.String("key", key))
+
+	opCtx, cancel := sp.storageContext(ctx)
+	defer cancel()
+	err := sp.st.createOrAppend(opCtx, key, trace)
+	if errors.Is(err, errStorageStopped) {
+		stats.Record(context.Background(), mSpansRefusedShutdown.M(int64(resourceSpansSpanCount(trace))))
+	}
+	return err
+}
+
+// storageContext returns the context for a single storage operation, bounded by the storage timeout
+func (sp *groupByTraceProcessor) storageContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if sp.config.StorageTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, sp.config.StorageTimeout)
 }
 
 type singleTraceBatch struct {
-	traceID pdata.TraceID
-	rs      pdata.ResourceSpans
+	key string
+	rs  pdata.ResourceSpans
 }
 
-func splitByTrace(rs pdata.ResourceSpans) []*singleTraceBatch {
-	// for each span in the resource spans, we group them into batches of rs/ils/traceID.
-	// if the same traceID exists in different ils, they land in different batches.
+// splitByKey groups the spans from the given resource spans into batches of rs/ils/key, where the key is
+// determined by groupKey. If the same key exists in different ils, they land in different batches.
+func splitByKey(rs pdata.ResourceSpans, groupByKey string) []*singleTraceBatch {
+	if key, ok := singleKey(rs, groupByKey); ok {
+		// the resource spans already hold a single trace, and can be handed over as they are
+		return []*singleTraceBatch{{key: key, rs: rs}}
+	}
+
 	var result []*singleTraceBatch
 
 	for i := 0; i < rs.InstrumentationLibrarySpans().Len(); i++ {
@@ -271,11 +1047,11 @@ func splitByTrace(rs pdata.ResourceSpans) []*singleTraceBatch {
 				continue
 			}
 
-			sTraceID := span.TraceID().HexString()
+			key := groupKey(groupByKey, rs.Resource(), span)
 
-			// for the first traceID in the ILS, initialize the map entry
+			// for the first key in the ILS, initialize the map entry
 			// and add the singleTraceBatch to the result list
-			if _, ok := batches[sTraceID]; !ok {
+			if _, ok := batches[key]; !ok {
 				newRS := pdata.NewResourceSpans()
 				// currently, the ResourceSpans implementation has only a Resource and an ILS. We'll copy the Resource
 				// and set our own ILS
@@ -288,17 +1064,61 @@ func splitByTrace(rs pdata.ResourceSpans) []*singleTraceBatch {
 				newRS.InstrumentationLibrarySpans().Append(newILS)
 
 				batch := &singleTraceBatch{
-					traceID: span.TraceID(),
-					rs:      newRS,
+					key: key,
+					rs:  newRS,
 				}
-				batches[sTraceID] = batch
+				batches[key] = batch
 				result = append(result, batch)
 			}
 
 			// there is only one instrumentation library per batch
-			batches[sTraceID].rs.InstrumentationLibrarySpans().At(0).Spans().Append(span)
+			batches[key].rs.InstrumentationLibrarySpans().At(0).Spans().Append(span)
 		}
 	}
 
 	return result
 }
+
+// singleKey returns the key shared by all the spans in the given resource spans, as long as there's a single
+// instrumentation library spans and none of the spans is missing the trace ID
+func singleKey(rs pdata.ResourceSpans, groupByKey string) (string, bool) {
+	if rs.InstrumentationLibrarySpans().Len() != 1 {
+		return "", false
+	}
+
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	if spans.Len() == 0 {
+		return "", false
+	}
+
+	key := ""
+	for i := 0; i < spans.Len(); i++ {
+		span := spans.At(i)
+		if span.TraceID().IsEmpty() {
+			return "", false
+		}
+
+		spanKey := groupKey(groupByKey, rs.Resource(), span)
+		if i > 0 && spanKey != key {
+			return "", false
+		}
+		key = spanKey
+	}
+
+	return key, true
+}
+
+// groupKey returns the key under which the given span is grouped. When groupByKey is set, the value of the
+// attribute with that name is used, looked up first in the span and then in the resource. Spans without
+// the attribute, or when groupByKey isn't set, are grouped by their trace ID.
+func groupKey(groupByKey string, resource pdata.Resource, span pdata.Span) string {
+	if groupByKey != "" {
+		if v, ok := span.Attributes().Get(groupByKey); ok {
+			return groupByKey + "=" + tracetranslator.AttributeValueToString(v, false)
+		}
+		if v, ok := resource.Attributes().Get(groupByKey); ok {
+			return groupByKey + "=" + tracetranslator.AttributeValueToString(v, false)
+		}
+	}
+	return span.TraceID().HexString()
+}
@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// groupByTraceLogsProcessor is the counterpart of the groupByTraceProcessor for logs: it groups the log records by
+// their trace ID, releasing each group to the next consumer after the wait duration, so that the logs for a trace
+// arrive at the next components within the same window as its spans. The log records without a trace ID are passed
+// through right away.
+// Just like for the traces, the operations are serialized by an event machine:
+// ConsumeLogs -> event(logsReceived) -> onLogsReceived -> AfterFunc(duration, event(logGroupExpired)) -> onLogGroupExpired
+type groupByTraceLogsProcessor struct {
+	nextConsumer consumer.LogsConsumer
+	config       Config
+	logger       *zap.Logger
+	clock        clock
+
+	// the event machine handling all operations for this processor
+	eventMachine *eventMachine
+
+	// the ring buffer holding the IDs of the traces of the log groups in the storage, limited to NumTraces
+	ringBuffer *ringBuffer
+
+	st *memoryLogsStorage
+}
+
+var _ component.LogsProcessor = (*groupByTraceLogsProcessor)(nil)
+
+// singleTraceLogsBatch holds the log records of a single trace, under the same resource and instrumentation library
+type singleTraceLogsBatch struct {
+	key string
+	rl  pdata.ResourceLogs
+}
+
+func newGroupByTraceLogsProcessor(logger *zap.Logger, st *memoryLogsStorage, nextConsumer consumer.LogsConsumer, config Config) *groupByTraceLogsProcessor {
+	// the event machine will buffer up to N concurrent events before blocking
+	eventMachine := newEventMachine(logger, 10000)
+
+	// the size of the queue is reported by the traces processor
+	eventMachine.numEvents = nil
+
+	lp := &groupByTraceLogsProcessor{
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		config:       config,
+		clock:        systemClock{},
+		eventMachine: eventMachine,
+		ringBuffer:   newRingBuffer(config.NumTraces),
+		st:           st,
+	}
+
+	// register the callbacks
+	eventMachine.onLogsReceived = lp.onLogsReceived
+	eventMachine.onLogGroupExpired = lp.onLogGroupExpired
+
+	return lp
+}
+
+func (lp *groupByTraceLogsProcessor) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
+	lp.eventMachine.fire(event{
+		typ:     logsReceived,
+		payload: ld,
+	})
+	return nil
+}
+
+func (lp *groupByTraceLogsProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (lp *groupByTraceLogsProcessor) Start(context.Context, component.Host) error {
+	// start these metrics, as it might take a while for them to receive their first event
+	stats.Record(context.Background(), mLogGroupsEvicted.M(0))
+
+	lp.st.start()
+	lp.eventMachine.startInBackground()
+	return nil
+}
+
+// Shutdown is invoked during service shutdown, releasing the log groups still in the storage.
+func (lp *groupByTraceLogsProcessor) Shutdown(ctx context.Context) error {
+	lp.eventMachine.shutdown()
+
+	for _, key := range lp.ringBuffer.all() {
+		lp.ringBuffer.delete(key)
+		if err := lp.release(ctx, lp.st.delete(key)); err != nil {
+			lp.logger.Warn("couldn't release log group during shutdown", zap.Error(err),
+				zap.String("key", key))
+		}
+	}
+
+	lp.st.shutdown()
+	return nil
+}
+
+func (lp *groupByTraceLogsProcessor) onLogsReceived(ctx context.Context, ld pdata.Logs) {
+	passthrough := pdata.NewLogs()
+
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		batches, withoutTraceID := splitLogsByTraceID(ld.ResourceLogs().At(i))
+		if withoutTraceID.InstrumentationLibraryLogs().Len() > 0 {
+			passthrough.ResourceLogs().Append(withoutTraceID)
+		}
+
+		for _, batch := range batches {
+			lp.processLogsBatch(ctx, batch)
+		}
+	}
+
+	if passthrough.ResourceLogs().Len() > 0 {
+		stats.Record(context.Background(), mReleasedLogRecords.M(int64(passthrough.LogRecordCount())))
+		if err := lp.nextConsumer.ConsumeLogs(ctx, passthrough); err != nil {
+			lp.logger.Warn("failed to pass through the log records without a trace ID", zap.Error(err))
+		}
+	}
+}
+
+func (lp *groupByTraceLogsProcessor) processLogsBatch(ctx context.Context, batch *singleTraceLogsBatch) {
+	if lp.ringBuffer.contains(batch.key) {
+		// the group exists already, just append the log records to it
+		lp.st.createOrAppend(batch.key, batch.rl)
+		return
+	}
+
+	if evicted := lp.ringBuffer.put(batch.key); evicted != "" {
+		// an older group had to be evicted to make room, release it before its time
+		stats.Record(context.Background(), mLogGroupsEvicted.M(1))
+		lp.logger.Info("log group evicted, releasing it before its time: in order to avoid this in the future, adjust the wait duration and/or number of traces to keep in memory",
+			zap.String("key", evicted))
+
+		if err := lp.release(ctx, lp.st.delete(evicted)); err != nil {
+			lp.logger.Warn("couldn't release evicted log group", zap.Error(err),
+				zap.String("key", evicted))
+		}
+	}
+
+	lp.st.createOrAppend(batch.key, batch.rl)
+
+	key := batch.key
+	lp.clock.afterFunc(lp.config.WaitDuration, func() {
+		// if the event machine has stopped, it will just discard the event
+		lp.eventMachine.fire(event{
+			typ:     logGroupExpired,
+			payload: key,
+		})
+	})
+}
+
+func (lp *groupByTraceLogsProcessor) onLogGroupExpired(ctx context.Context, key string) error {
+	if !lp.ringBuffer.contains(key) {
+		// released already, as it had to be evicted
+		return nil
+	}
+
+	lp.ringBuffer.delete(key)
+	return lp.release(ctx, lp.st.delete(key))
+}
+
+// release sends the given log group to the next consumer
+func (lp *groupByTraceLogsProcessor) release(ctx context.Context, rls []pdata.ResourceLogs) error {
+	if len(rls) == 0 {
+		return nil
+	}
+
+	ld := pdata.NewLogs()
+	for _, rl := range rls {
+		ld.ResourceLogs().Append(rl)
+	}
+
+	stats.Record(context.Background(), mReleasedLogRecords.M(int64(ld.LogRecordCount())), mReleasedLogGroups.M(1))
+	return lp.nextConsumer.ConsumeLogs(ctx, ld)
+}
+
+// splitLogsByTraceID splits the given resource logs into one batch for each trace ID, along with resource logs for
+// the log records without a trace ID
+func splitLogsByTraceID(rl pdata.ResourceLogs) ([]*singleTraceLogsBatch, pdata.ResourceLogs) {
+	var result []*singleTraceLogsBatch
+
+	withoutTraceID := pdata.NewResourceLogs()
+	rl.Resource().CopyTo(withoutTraceID.Resource())
+
+	for i := 0; i < rl.InstrumentationLibraryLogs().Len(); i++ {
+		ill := rl.InstrumentationLibraryLogs().At(i)
+
+		// the batches for this ILL, with the empty key holding the log records without a trace ID
+		batches := map[string]pdata.InstrumentationLibraryLogs{}
+		for j := 0; j < ill.Logs().Len(); j++ {
+			record := ill.Logs().At(j)
+
+			key := ""
+			if !record.TraceID().IsEmpty() {
+				key = record.TraceID().HexString()
+			}
+
+			newILL, ok := batches[key]
+			if !ok {
+				newILL = pdata.NewInstrumentationLibraryLogs()
+				ill.InstrumentationLibrary().CopyTo(newILL.InstrumentationLibrary())
+				batches[key] = newILL
+
+				if key == "" {
+					withoutTraceID.InstrumentationLibraryLogs().Append(newILL)
+				} else {
+					newRL := pdata.NewResourceLogs()
+					rl.Resource().CopyTo(newRL.Resource())
+					newRL.InstrumentationLibraryLogs().Append(newILL)
+					result = append(result, &singleTraceLogsBatch{key: key, rl: newRL})
+				}
+			}
+
+			newILL.Logs().Append(record)
+		}
+	}
+
+	return result, withoutTraceID
+}
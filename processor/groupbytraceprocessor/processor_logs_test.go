@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestLogsAreGroupedByTraceID(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	config := Config{
+		WaitDuration: 10 * time.Second,
+		NumTraces:    10,
+	}
+	next := &consumertest.LogsSink{}
+	clock := newFakeClock()
+
+	lp := newGroupByTraceLogsProcessor(logger, newMemoryLogsStorage(), next, config)
+	lp.clock = clock
+	require.NoError(t, lp.Start(context.Background(), nil))
+	defer lp.Shutdown(context.Background())
+
+	first := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	second := pdata.NewTraceID([16]byte{2, 3, 4, 5})
+
+	// test
+	require.NoError(t, lp.ConsumeLogs(context.Background(), simpleLogsWithIDs(first, second, pdata.NewTraceID([16]byte{}), first)))
+	require.NoError(t, lp.ConsumeLogs(context.Background(), simpleLogsWithIDs(first)))
+
+	// verify
+	// the log record without a trace ID is passed through right away
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt64(&lp.eventMachine.pending) == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 1, next.LogRecordsCount())
+	assert.Equal(t, 2, lp.st.count())
+	assert.True(t, next.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).TraceID().IsEmpty())
+
+	// the groups are released once the wait duration expires
+	clock.advance(config.WaitDuration)
+	assert.Eventually(t, func() bool {
+		return next.LogRecordsCount() == 5
+	}, time.Second, time.Millisecond)
+
+	recordsPerTrace := map[pdata.TraceID]int{}
+	for _, ld := range next.AllLogs()[1:] {
+		traceID := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).TraceID()
+		recordsPerTrace[traceID] += ld.LogRecordCount()
+	}
+	assert.Equal(t, map[pdata.TraceID]int{first: 3, second: 1}, recordsPerTrace)
+	assert.Len(t, next.AllLogs(), 3)
+
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mReleasedLogGroups.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 2, viewData[0].Data.(*view.SumData).Value)
+}
+
+func TestLogGroupIsReleasedWhenEvicted(t *testing.T) {
+	// prepare
+	config := Config{
+		// long enough so that the only way for log groups to be released before the shutdown is via the eviction
+		WaitDuration: time.Hour,
+		NumTraces:    1,
+	}
+	next := &consumertest.LogsSink{}
+
+	lp := newGroupByTraceLogsProcessor(logger, newMemoryLogsStorage(), next, config)
+	require.NoError(t, lp.Start(context.Background(), nil))
+	defer lp.Shutdown(context.Background())
+
+	first := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	second := pdata.NewTraceID([16]byte{2, 3, 4, 5})
+
+	// test
+	require.NoError(t, lp.ConsumeLogs(context.Background(), simpleLogsWithIDs(first)))
+	require.NoError(t, lp.ConsumeLogs(context.Background(), simpleLogsWithIDs(second)))
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return next.LogRecordsCount() == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, first, next.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).TraceID())
+}
+
+func TestLogGroupsAreReleasedOnShutdown(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+	}
+	next := &consumertest.LogsSink{}
+	st := newMemoryLogsStorage()
+
+	lp := newGroupByTraceLogsProcessor(logger, st, next, config)
+	require.NoError(t, lp.Start(context.Background(), nil))
+
+	first := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	second := pdata.NewTraceID([16]byte{2, 3, 4, 5})
+	require.NoError(t, lp.ConsumeLogs(context.Background(), simpleLogsWithIDs(first, second)))
+
+	// test
+	require.NoError(t, lp.Shutdown(context.Background()))
+
+	// verify
+	assert.Equal(t, 2, next.LogRecordsCount())
+	assert.Len(t, next.AllLogs(), 2)
+	assert.Equal(t, 0, st.count())
+}
+
+func TestSplitLogsByTraceID(t *testing.T) {
+	// prepare
+	first := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	second := pdata.NewTraceID([16]byte{2, 3, 4, 5})
+
+	rl := simpleLogsWithIDs(first, pdata.NewTraceID([16]byte{}), second, first).ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString("service.name", "some-service")
+
+	// test
+	batches, withoutTraceID := splitLogsByTraceID(rl)
+
+	// verify
+	require.Len(t, batches, 2)
+	assert.Equal(t, first.HexString(), batches[0].key)
+	assert.Equal(t, 2, batches[0].rl.InstrumentationLibraryLogs().At(0).Logs().Len())
+	assert.Equal(t, second.HexString(), batches[1].key)
+	assert.Equal(t, 1, batches[1].rl.InstrumentationLibraryLogs().At(0).Logs().Len())
+
+	require.Equal(t, 1, withoutTraceID.InstrumentationLibraryLogs().Len())
+	assert.Equal(t, 1, withoutTraceID.InstrumentationLibraryLogs().At(0).Logs().Len())
+
+	// the resource is kept for all of them
+	for _, resource := range []pdata.Resource{batches[0].rl.Resource(), batches[1].rl.Resource(), withoutTraceID.Resource()} {
+		serviceName, ok := resource.Attributes().Get("service.name")
+		require.True(t, ok)
+		assert.Equal(t, "some-service", serviceName.StringVal())
+	}
+}
+
+// simpleLogsWithIDs returns logs with a single resource, holding one log record for each given trace ID
+func simpleLogsWithIDs(traceIDs ...pdata.TraceID) pdata.Logs {
+	logs := pdata.NewLogs()
+	logs.ResourceLogs().Resize(1)
+	rl := logs.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	records := rl.InstrumentationLibraryLogs().At(0).Logs()
+	records.Resize(len(traceIDs))
+	for i, traceID := range traceIDs {
+		records.At(i).SetTraceID(traceID)
+	}
+	return logs
+}
@@ -17,14 +17,19 @@ package groupbytraceprocessor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/trace/jaeger"
 	"go.uber.org/zap"
 )
 
@@ -54,9 +59,9 @@ func TestTraceIsDispatchedAfterDuration(t *testing.T) {
 	st := &mockStorage{
 		onCreateOrAppend: backing.createOrAppend,
 		onGet:            backing.get,
-		onDelete: func(traceID pdata.TraceID) ([]pdata.ResourceSpans, error) {
+		onDelete: func(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
 			wgDeleted.Done()
-			return backing.delete(traceID)
+			return backing.delete(ctx, key)
 		},
 	}
 
@@ -88,7 +93,7 @@ func TestInternalCacheLimit(t *testing.T) {
 		NumTraces: 5,
 	}
 
-	wg.Add(5) // 5 traces are expected to be received
+	wg.Add(6) // all 6 traces are expected to be received, including the evicted one
 
 	var receivedTraceIDs []pdata.TraceID
 	mockProcessor := &mockProcessor{}
@@ -126,15 +131,275 @@ func TestInternalCacheLimit(t *testing.T) {
 	wg.Wait()
 
 	// verify
-	assert.Equal(t, 5, len(receivedTraceIDs))
+	assert.Equal(t, 6, len(receivedTraceIDs))
+
+	// the first trace should have been evicted, and released before the others
+	assert.Equal(t, pdata.NewTraceID(traceIDs[0]), receivedTraceIDs[0])
 
 	for i := 5; i > 0; i-- { // last 5 traces
 		traceID := pdata.NewTraceID(traceIDs[i])
 		assert.Contains(t, receivedTraceIDs, traceID)
 	}
+}
+
+func TestTracesEvictedByMaxBytesAreForwarded(t *testing.T) {
+	// prepare
+	wg := &sync.WaitGroup{}
+
+	config := Config{
+		// long enough so that the only way for traces to be released is via eviction
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+	}
+
+	var receivedTraceIDs []pdata.TraceID
+	mockProcessor := &mockProcessor{}
+	mockProcessor.onTraces = func(ctx context.Context, received pdata.Traces) error {
+		traceID := received.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+		receivedTraceIDs = append(receivedTraceIDs, traceID)
+		wg.Done()
+		return nil
+	}
+
+	first := simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+	second := simpleTracesWithID(pdata.NewTraceID([16]byte{2, 3, 4, 5}))
+
+	st := newShardedMemoryStorage(1)
+	// room for a single trace
+	st.maxBytes = resourceSpansSize(first.ResourceSpans().At(0)) + 1
+
+	p := newGroupByTraceProcessor(logger, st, mockProcessor, config)
+	ctx := context.Background()
+	p.Start(ctx, nil)
+
+	// test
+	wg.Add(1)
+	p.ConsumeTraces(ctx, first)
+	p.ConsumeTraces(ctx, second)
+	wg.Wait()
+
+	// verify
+	assert.Equal(t, []pdata.TraceID{pdata.NewTraceID([16]byte{1, 2, 3, 4})}, receivedTraceIDs)
+	assert.Equal(t, 1, st.count())
+
+	// the remaining trace is released on shutdown
+	wg.Add(1)
+	require.NoError(t, p.Shutdown(ctx))
+	wg.Wait()
+	assert.Len(t, receivedTraceIDs, 2)
+}
+
+func TestPendingTracesAreReleasedOnShutdown(t *testing.T) {
+	// prepare
+	config := Config{
+		// long enough so that the only way for traces to be released is via the shutdown
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+	}
+
+	var received []pdata.Traces
+	mockProcessor := &mockProcessor{
+		onTraces: func(ctx context.Context, td pdata.Traces) error {
+			received = append(received, td)
+			return nil
+		},
+	}
+
+	st := newMemoryStorage()
+	p := newGroupByTraceProcessor(logger, st, mockProcessor, config)
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, nil))
+
+	traces := simpleTraces()
+	require.NoError(t, p.ConsumeTraces(ctx, traces))
+
+	// test
+	require.NoError(t, p.Shutdown(ctx))
+
+	// verify
+	assert.Equal(t, []pdata.Traces{traces}, received)
+	assert.Equal(t, 0, st.count())
+}
+
+func TestWorkersReleaseAllTraces(t *testing.T) {
+	// prepare
+	config := Config{
+		// long enough so that the only way for traces to be released is via the shutdown
+		WaitDuration: time.Hour,
+		// each worker holds a quarter of this, with enough room for an uneven distribution of the traces
+		NumTraces:  400,
+		NumWorkers: 4,
+	}
+
+	mu := sync.Mutex{}
+	received := map[pdata.TraceID]int{}
+	mockProcessor := &mockProcessor{
+		onTraces: func(ctx context.Context, td pdata.Traces) error {
+			mu.Lock()
+			defer mu.Unlock()
+			traceID := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+			received[traceID] += td.SpanCount()
+			return nil
+		},
+	}
+
+	st := newMemoryStorage()
+	p := newGroupByTraceProcessor(logger, st, mockProcessor, config)
+	require.Len(t, p.workers, 4)
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, nil))
+
+	// each trace ends up in a single worker, even when its spans arrive in different batches
+	batch := pdata.NewTraces()
+	batch.ResourceSpans().Resize(1)
+	ils := batch.ResourceSpans().At(0).InstrumentationLibrarySpans()
+	ils.Resize(1)
+	ils.At(0).Spans().Resize(50)
+	for i := 0; i < 50; i++ {
+		ils.At(0).Spans().At(i).SetTraceID(pdata.NewTraceID([16]byte{byte(i + 1), 2, 3, 4}))
+	}
+	require.NoError(t, p.ConsumeTraces(ctx, batch))
+	require.NoError(t, p.ConsumeTraces(ctx, batch))
+
+	// test
+	require.NoError(t, p.Shutdown(ctx))
+
+	// verify
+	assert.Len(t, received, 50)
+	for traceID, numSpans := range received {
+		assert.Equal(t, 2, numSpans, "trace %s", traceID.HexString())
+	}
+	assert.Equal(t, 0, st.count())
+}
+
+func TestTracesAreDroppedWhenShutdownDeadlineIsReached(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	config := Config{
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+	}
+
+	mockProcessor := &mockProcessor{
+		onTraces: func(ctx context.Context, td pdata.Traces) error {
+			assert.Fail(t, "no traces should have been released")
+			return nil
+		},
+	}
+
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), mockProcessor, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))))
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(pdata.NewTraceID([16]byte{2, 3, 4, 5}))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// test
+	require.NoError(t, p.Shutdown(ctx))
+
+	// verify
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mTracesDroppedOnShutdown.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 2, viewData[0].Data.(*view.SumData).Value)
+}
+
+func TestSpansAreRefusedAfterShutdown(t *testing.T) {
+	for _, tt := range []struct {
+		casename   string
+		numWorkers int
+	}{
+		{
+			casename:   "single worker",
+			numWorkers: 1,
+		},
+		{
+			casename:   "multiple workers",
+			numWorkers: 4,
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			// prepare
+			views := MetricViews()
+
+			// ensure that we are starting with a clean state
+			view.Unregister(views...)
+			view.Register(views...)
+			defer view.Unregister(views...)
+
+			config := Config{
+				WaitDuration: time.Second,
+				NumTraces:    10,
+				NumWorkers:   tt.numWorkers,
+			}
+			p := newGroupByTraceProcessor(logger, newMemoryStorage(), &mockProcessor{}, config)
+			require.NoError(t, p.Start(context.Background(), nil))
+			require.NoError(t, p.Shutdown(context.Background()))
+
+			batch := simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+			batch.ResourceSpans().Append(simpleTracesWithID(pdata.NewTraceID([16]byte{2, 3, 4, 5})).ResourceSpans().At(0))
+
+			// test
+			err := p.ConsumeTraces(context.Background(), batch)
+
+			// verify
+			require.Error(t, err)
+			assert.True(t, consumererror.IsPermanent(err))
+			assert.Contains(t, err.Error(), errShuttingDown.Error())
+
+			viewData, err := view.RetrieveData("processor/groupbytrace/" + mSpansRefusedShutdown.Name())
+			require.NoError(t, err)
+			require.Len(t, viewData, 1)
+			assert.EqualValues(t, 2, viewData[0].Data.(*view.SumData).Value)
+		})
+	}
+}
+
+func TestSpansPerTraceIsRecordedOnRelease(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	config := Config{
+		WaitDuration: time.Nanosecond,
+		NumTraces:    10,
+	}
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), &mockProcessor{}, config)
+
+	singleSpan := simpleTraces().ResourceSpans().At(0)
+	threeSpans := pdata.NewResourceSpans()
+	singleSpan.CopyTo(threeSpans)
+	threeSpans.InstrumentationLibrarySpans().At(0).Spans().Resize(3)
+
+	// test
+	require.NoError(t, p.onTraceReleased(context.Background(), []pdata.ResourceSpans{singleSpan}))
+	require.NoError(t, p.onTraceReleased(context.Background(), []pdata.ResourceSpans{threeSpans, singleSpan}))
+
+	// verify
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mSpansPerTrace.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	distribution := viewData[0].Data.(*view.DistributionData)
+	assert.EqualValues(t, 2, distribution.Count)
+	assert.EqualValues(t, 1, distribution.Min)
+	assert.EqualValues(t, 4, distribution.Max)
 
-	// the first trace should have been evicted
-	assert.NotContains(t, receivedTraceIDs, traceIDs[0])
+	viewData, err = view.RetrieveData("processor/groupbytrace/" + mSingleSpanReleases.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 1, viewData[0].Data.(*view.SumData).Value)
 }
 
 func TestProcessorCapabilities(t *testing.T) {
@@ -180,7 +445,7 @@ func TestProcessBatchDoesntFail(t *testing.T) {
 	assert.NotNil(t, p)
 
 	// test
-	p.onBatchReceived(batch)
+	p.onBatchReceived(context.Background(), batch)
 }
 
 func TestTraceDisappearedFromStorageBeforeReleasing(t *testing.T) {
@@ -190,7 +455,7 @@ func TestTraceDisappearedFromStorageBeforeReleasing(t *testing.T) {
 		NumTraces:    5,
 	}
 	st := &mockStorage{
-		onGet: func(pdata.TraceID) ([]pdata.ResourceSpans, error) {
+		onDelete: func(context.Context, string) ([]pdata.ResourceSpans, error) {
 			return nil, nil
 		},
 	}
@@ -210,129 +475,1075 @@ func TestTraceDisappearedFromStorageBeforeReleasing(t *testing.T) {
 	require.NoError(t, err)
 
 	// test
-	// we trigger this manually, instead of waiting the whole duration
-	err = p.markAsReleased(traceID)
+	// we trigger this manually, instead of waiting the whole duration
+	err = p.markAsReleased(traceID.HexString(), releaseReasonTimeout)
+
+	// verify
+	assert.Error(t, err)
+}
+
+func TestTraceErrorFromStorageWhileReleasing(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Second, // we are not waiting for this whole time
+		NumTraces:    5,
+	}
+	expectedError := errors.New("some unexpected error")
+	st := &mockStorage{
+		onDelete: func(context.Context, string) ([]pdata.ResourceSpans, error) {
+			return nil, expectedError
+		},
+	}
+	next := &mockProcessor{}
+
+	p := newGroupByTraceProcessor(logger, st, next, config)
+	require.NotNil(t, p)
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	batch := simpleTracesWithID(traceID)
+
+	ctx := context.Background()
+	p.Start(ctx, nil)
+	defer p.Shutdown(ctx)
+
+	err := p.ConsumeTraces(context.Background(), batch)
+	require.NoError(t, err)
+
+	// test
+	// we trigger this manually, instead of waiting the whole duration
+	err = p.markAsReleased(traceID.HexString(), releaseReasonTimeout)
+
+	// verify
+	assert.True(t, errors.Is(err, expectedError))
+}
+
+func TestTraceErrorFromStorageWhileProcessingTrace(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Second, // we are not waiting for this whole time
+		NumTraces:    5,
+	}
+	expectedError := errors.New("some unexpected error")
+	st := &mockStorage{
+		onCreateOrAppend: func(context.Context, string, pdata.ResourceSpans) error {
+			return expectedError
+		},
+	}
+	next := &mockProcessor{}
+
+	p := newGroupByTraceProcessor(logger, st, next, config)
+	require.NotNil(t, p)
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	rs := pdata.NewResourceSpans()
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(traceID)
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4}))
+
+	batch := splitByKey(rs, "")
+
+	// test
+	err := p.processBatch(context.Background(), batch[0])
+
+	// verify
+	assert.True(t, errors.Is(err, expectedError))
+}
+
+func TestAddSpansToExistingTrace(t *testing.T) {
+	// prepare
+	wg := &sync.WaitGroup{}
+	config := Config{
+		WaitDuration: 50 * time.Millisecond,
+		NumTraces:    5,
+	}
+	st := newMemoryStorage()
+
+	var receivedTraces []pdata.ResourceSpans
+	next := &mockProcessor{
+		onTraces: func(ctx context.Context, traces pdata.Traces) error {
+			require.Equal(t, 2, traces.ResourceSpans().Len())
+			receivedTraces = append(receivedTraces, traces.ResourceSpans().At(0))
+			receivedTraces = append(receivedTraces, traces.ResourceSpans().At(1))
+			wg.Done()
+			return nil
+		},
+	}
+
+	p := newGroupByTraceProcessor(logger, st, next, config)
+	require.NotNil(t, p)
+
+	ctx := context.Background()
+	p.Start(ctx, nil)
+	defer p.Shutdown(ctx)
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	// test
+	first := simpleTracesWithID(traceID)
+	first.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetName("first-span")
+
+	second := simpleTracesWithID(traceID)
+	second.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetName("second-span")
+
+	wg.Add(1)
+
+	p.ConsumeTraces(context.Background(), first)
+	p.ConsumeTraces(context.Background(), second)
+
+	wg.Wait()
+
+	// verify
+	assert.Len(t, receivedTraces, 2)
+}
+
+func TestStorageOperationsHaveTimeout(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration:   time.Second,
+		NumTraces:      5,
+		StorageTimeout: time.Minute,
+	}
+
+	var deadline time.Time
+	var hasDeadline bool
+	st := &mockStorage{
+		onCreateOrAppend: func(ctx context.Context, _ string, _ pdata.ResourceSpans) error {
+			deadline, hasDeadline = ctx.Deadline()
+			return nil
+		},
+	}
+	p := newGroupByTraceProcessor(logger, st, &mockProcessor{}, config)
+
+	// test
+	p.onBatchReceived(context.Background(), simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4})))
+
+	// verify
+	require.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+}
+
+func TestWhenFullRefuse(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Minute,
+		NumTraces:    2,
+		WhenFull:     refuseWhenFull,
+	}
+
+	var released []pdata.TraceID
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			released = append(released, td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+			return nil
+		},
+	}
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+
+	traceIDs := []pdata.TraceID{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		pdata.NewTraceID([16]byte{2, 3, 4, 5}),
+	}
+	for _, traceID := range traceIDs {
+		require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	}
+	assert.Eventually(t, func() bool {
+		return p.numTracesInFlight() == 2
+	}, time.Second, time.Millisecond)
+
+	// test
+	err := p.ConsumeTraces(context.Background(), simpleTracesWithID(pdata.NewTraceID([16]byte{3, 4, 5, 6})))
+
+	// verify
+	assert.Equal(t, errNumTracesReached, err)
+
+	// the traces in the buffer are kept, and released on shutdown
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.ElementsMatch(t, traceIDs, released)
+}
+
+func TestWhenFullRefuseAccountsForQueuedBatches(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Minute,
+		NumTraces:    2,
+		WhenFull:     refuseWhenFull,
+	}
+
+	// the storage blocks the event machine, so that the batches pile up in the queue
+	unblock := make(chan struct{})
+	var lock sync.Mutex
+	var stored []string
+	st := &mockStorage{
+		onCreateOrAppend: func(_ context.Context, key string, _ pdata.ResourceSpans) error {
+			<-unblock
+			lock.Lock()
+			defer lock.Unlock()
+			stored = append(stored, key)
+			return nil
+		},
+	}
+	p := newGroupByTraceProcessor(logger, st, &mockProcessor{}, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
+
+	traceIDs := []pdata.TraceID{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		pdata.NewTraceID([16]byte{2, 3, 4, 5}),
+	}
+	for _, traceID := range traceIDs {
+		require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	}
+
+	// test
+	// at most one of the traces is in the ring buffer by now, but the ones in the queue have room reserved
+	err := p.ConsumeTraces(context.Background(), simpleTracesWithID(pdata.NewTraceID([16]byte{3, 4, 5, 6})))
+
+	// verify
+	assert.Equal(t, errNumTracesReached, err)
+
+	// the accepted traces make it to the storage
+	close(unblock)
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(stored) == 2
+	}, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt64(&p.workers[0].reserved) == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, errNumTracesReached, p.ConsumeTraces(context.Background(), simpleTracesWithID(pdata.NewTraceID([16]byte{3, 4, 5, 6}))))
+}
+
+func TestWhenFullRefuseChecksTheTargetWorker(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Minute,
+		NumTraces:    4,
+		NumWorkers:   2,
+		WhenFull:     refuseWhenFull,
+	}
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), &mockProcessor{}, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
+
+	// find the traces for each of the workers, holding two traces each
+	traceIDs := [][]pdata.TraceID{{}, {}}
+	for i := byte(1); len(traceIDs[0]) < 3 || len(traceIDs[1]) < 1; i++ {
+		traceID := pdata.NewTraceID([16]byte{i, 2, 3, 4})
+		w := p.workerIndex(traceID.HexString())
+		traceIDs[w] = append(traceIDs[w], traceID)
+	}
+	hot, cold := traceIDs[0], traceIDs[1]
+
+	for _, traceID := range hot[:2] {
+		require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	}
+
+	// test
+	// the hot worker is full, even though the processor as a whole holds only half of the traces
+	errHot := p.ConsumeTraces(context.Background(), simpleTracesWithID(hot[2]))
+
+	// a batch with a trace for each worker is refused as a whole
+	mixed := simpleTracesWithID(hot[2])
+	simpleTracesWithID(cold[0]).ResourceSpans().MoveAndAppendTo(mixed.ResourceSpans())
+	errMixed := p.ConsumeTraces(context.Background(), mixed)
+
+	// verify
+	assert.Equal(t, errNumTracesReached, errHot)
+	assert.Equal(t, errNumTracesReached, errMixed)
+	assert.EqualValues(t, 0, atomic.LoadInt64(&p.workers[1].reserved))
+
+	// the other worker still has room
+	assert.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(cold[0])))
+}
+
+func TestWhenFullEvict(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Minute,
+		NumTraces:    2,
+		WhenFull:     evictWhenFull,
+	}
+
+	var released []pdata.TraceID
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			released = append(released, td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+			return nil
+		},
+	}
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+
+	traceIDs := []pdata.TraceID{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		pdata.NewTraceID([16]byte{2, 3, 4, 5}),
+		pdata.NewTraceID([16]byte{3, 4, 5, 6}),
+	}
+
+	// test
+	for _, traceID := range traceIDs {
+		require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	}
+
+	// verify
+	// the oldest trace is evicted and released right away, and the other ones are released on shutdown
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.ElementsMatch(t, traceIDs, released)
+}
+
+func TestWhenFullEvictReleasesEvictedTrace(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	config := Config{
+		// long enough so that the only way for traces to be released before the shutdown is via the eviction
+		WaitDuration:     time.Hour,
+		NumTraces:        1,
+		WhenFull:         evictWhenFull,
+		MarkCompleteness: true,
+	}
+
+	received := make(chan pdata.Traces, 1)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
+		},
+	}
+	st := newMemoryStorage()
+	p := newGroupByTraceProcessor(logger, st, next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
+
+	first := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	second := pdata.NewTraceID([16]byte{2, 3, 4, 5})
+
+	// test
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(first)))
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(second)))
+
+	// verify
+	td := <-received
+	assert.Equal(t, first, td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+	assertReleaseReason(t, releaseReasonEvicted, td)
+
+	// only the second trace is left in the storage
+	assert.Eventually(t, func() bool {
+		return st.count() == 1
+	}, time.Second, time.Millisecond)
+
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mTracesReleasedByEviction.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 1, viewData[0].Data.(*view.SumData).Value)
+}
+
+func TestLateSpansAreForwarded(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration:        time.Millisecond,
+		NumTraces:           5,
+		LateSpanGracePeriod: time.Minute,
+		LateSpanPolicy:      forwardLateSpanPolicy,
+	}
+	st := newMemoryStorage()
+
+	received := make(chan pdata.Traces, 2)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
+		},
+	}
+
+	p := newGroupByTraceProcessor(logger, st, next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	first := <-received
+	assert.Equal(t, 1, first.SpanCount())
+
+	// test
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	// verify
+	late := <-received
+	require.Equal(t, 1, late.SpanCount())
+	attr, ok := late.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes().Get(lateSpanAttribute)
+	require.True(t, ok)
+	assert.True(t, attr.BoolVal())
+	assert.False(t, p.workerFor(traceID.HexString()).ringBuffer.contains(traceID.HexString()))
+}
+
+func TestLateSpansAreForwardedDuringShutdown(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration:        time.Minute,
+		NumTraces:           5,
+		LateSpanGracePeriod: time.Minute,
+		LateSpanPolicy:      forwardLateSpanPolicy,
+	}
+
+	var received []pdata.Traces
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received = append(received, td)
+			return nil
+		},
+	}
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, config)
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	p.recordReleased(traceID.HexString())
+
+	// the event machine isn't taking events anymore, as when the late spans are the last events in the queue
+	p.workers[0].eventMachine.shutdown()
+
+	// test
+	p.onBatchReceived(context.Background(), simpleTracesWithID(traceID))
+
+	// verify
+	require.Len(t, received, 1)
+	attr, ok := received[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes().Get(lateSpanAttribute)
+	require.True(t, ok)
+	assert.True(t, attr.BoolVal())
+}
+
+func TestLateSpansAreRebuffered(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration:         time.Millisecond,
+		NumTraces:            5,
+		LateSpanGracePeriod:  time.Minute,
+		LateSpanPolicy:       rebufferLateSpanPolicy,
+		LateSpanWaitDuration: 100 * time.Millisecond,
+	}
+	st := newMemoryStorage()
+
+	received := make(chan pdata.Traces, 2)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
+		},
+	}
+
+	p := newGroupByTraceProcessor(logger, st, next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	<-received
+
+	// test
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	// verify
+	late := <-received
+	assert.Equal(t, 2, late.SpanCount())
+	_, ok := late.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes().Get(lateSpanAttribute)
+	assert.False(t, ok)
+}
+
+func TestWaitDurationOverride(t *testing.T) {
+	for _, tt := range []struct {
+		casename        string
+		waitDuration    time.Duration
+		maxWaitDuration time.Duration
+		overrideInFirst bool
+		minWait         time.Duration
+	}{
+		{
+			casename:        "extends the wait duration",
+			waitDuration:    time.Millisecond,
+			overrideInFirst: true,
+			minWait:         200 * time.Millisecond,
+		},
+		{
+			casename: "extended by later spans",
+			// the first spans have to be held for long enough for the second batch to arrive
+			waitDuration:    50 * time.Millisecond,
+			overrideInFirst: false,
+			minWait:         200 * time.Millisecond,
+		},
+		{
+			casename:        "clamped to the max wait duration",
+			waitDuration:    time.Millisecond,
+			maxWaitDuration: 50 * time.Millisecond,
+			overrideInFirst: true,
+			minWait:         50 * time.Millisecond,
+		},
+		{
+			casename:        "never shortens the wait duration",
+			waitDuration:    400 * time.Millisecond,
+			overrideInFirst: true,
+			minWait:         400 * time.Millisecond,
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			// prepare
+			config := Config{
+				WaitDuration:          tt.waitDuration,
+				WaitDurationAttribute: "groupbytrace.wait_ms",
+				MaxWaitDuration:       tt.maxWaitDuration,
+				NumTraces:             5,
+			}
+			st := newMemoryStorage()
+
+			received := make(chan pdata.Traces, 1)
+			next := &mockProcessor{
+				onTraces: func(_ context.Context, td pdata.Traces) error {
+					received <- td
+					return nil
+				},
+			}
+
+			p := newGroupByTraceProcessor(logger, st, next, config)
+			require.NoError(t, p.Start(context.Background(), nil))
+			defer p.Shutdown(context.Background())
+
+			traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+			overridden := simpleTracesWithID(traceID)
+			overridden.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes().InsertInt("groupbytrace.wait_ms", 200)
+
+			first, second := overridden, simpleTracesWithID(traceID)
+			if !tt.overrideInFirst {
+				first, second = second, overridden
+			}
+
+			// test
+			start := time.Now()
+			require.NoError(t, p.ConsumeTraces(context.Background(), first))
+			require.NoError(t, p.ConsumeTraces(context.Background(), second))
+
+			// verify
+			td := <-received
+			assert.GreaterOrEqual(t, int64(time.Since(start)), int64(tt.minWait))
+			assert.Equal(t, 2, td.SpanCount())
+		})
+	}
+}
+
+func TestRootSpanReleasesTraceAfterQuietPeriod(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	config := Config{
+		WaitDuration: 300 * time.Millisecond,
+		QuietPeriod:  20 * time.Millisecond,
+		NumTraces:    5,
+	}
+
+	received := make(chan pdata.Traces, 1)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
+		},
+	}
+
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	// test
+	start := time.Now()
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleChildTracesWithID(traceID)))
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	// verify
+	td := <-received
+	assert.Less(t, int64(time.Since(start)), int64(config.WaitDuration))
+	assert.Equal(t, 2, td.SpanCount())
+
+	// the main timer still fires, but it shouldn't be seen as an incomplete release
+	time.Sleep(config.WaitDuration)
+	assertReleaseTriggers(t, map[string]int64{"root_span": 1})
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mIncompleteReleases.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 0, viewData[0].Data.(*view.SumData).Value)
+}
+
+func TestTraceWithoutRootSpanIsReleasedOnTimeout(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	config := Config{
+		WaitDuration: 100 * time.Millisecond,
+		QuietPeriod:  time.Millisecond,
+		NumTraces:    5,
+	}
+
+	received := make(chan pdata.Traces, 1)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
+		},
+	}
+
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
+
+	// test
+	start := time.Now()
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleChildTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))))
 
 	// verify
-	assert.Error(t, err)
+	<-received
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(config.WaitDuration))
+	assertReleaseTriggers(t, map[string]int64{"timeout": 1})
 }
 
-func TestTraceErrorFromStorageWhileReleasing(t *testing.T) {
+func TestNewSpansRestartQuietPeriod(t *testing.T) {
 	// prepare
 	config := Config{
-		WaitDuration: time.Second, // we are not waiting for this whole time
+		WaitDuration: time.Hour,
+		QuietPeriod:  200 * time.Millisecond,
 		NumTraces:    5,
 	}
-	expectedError := errors.New("some unexpected error")
-	st := &mockStorage{
-		onGet: func(pdata.TraceID) ([]pdata.ResourceSpans, error) {
-			return nil, expectedError
+
+	received := make(chan pdata.Traces, 1)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
 		},
 	}
-	next := &mockProcessor{}
 
-	p := newGroupByTraceProcessor(logger, st, next, config)
-	require.NotNil(t, p)
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
 
 	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
-	batch := simpleTracesWithID(traceID)
-
-	ctx := context.Background()
-	p.Start(ctx, nil)
-	defer p.Shutdown(ctx)
-
-	err := p.ConsumeTraces(context.Background(), batch)
-	require.NoError(t, err)
 
 	// test
-	// we trigger this manually, instead of waiting the whole duration
-	err = p.markAsReleased(traceID)
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	for i := 0; i < 3; i++ {
+		time.Sleep(config.QuietPeriod / 4)
+		require.NoError(t, p.ConsumeTraces(context.Background(), simpleChildTracesWithID(traceID)))
+	}
 
 	// verify
-	assert.True(t, errors.Is(err, expectedError))
+	td := <-received
+	assert.Equal(t, 4, td.SpanCount())
 }
 
-func TestTraceErrorFromStorageWhileProcessingTrace(t *testing.T) {
+func TestMarkCompleteness(t *testing.T) {
+	for _, tt := range []struct {
+		casename string
+		config   Config
+		traces   pdata.Traces
+		// whether the trace is released by shutting down the processor, instead of on its own
+		shutdown       bool
+		expectedReason string
+	}{
+		{
+			casename: "timeout",
+			config: Config{
+				WaitDuration:     time.Millisecond,
+				MarkCompleteness: true,
+			},
+			traces:         simpleChildTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4})),
+			expectedReason: releaseReasonTimeout,
+		},
+		{
+			casename: "root detected",
+			config: Config{
+				WaitDuration:     time.Hour,
+				QuietPeriod:      time.Millisecond,
+				MarkCompleteness: true,
+			},
+			traces:         simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4})),
+			expectedReason: releaseReasonRootDetected,
+		},
+		{
+			casename: "shutdown flush",
+			config: Config{
+				WaitDuration:     time.Hour,
+				MarkCompleteness: true,
+			},
+			traces:         simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4})),
+			shutdown:       true,
+			expectedReason: releaseReasonShutdownFlush,
+		},
+		{
+			casename: "disabled",
+			config: Config{
+				WaitDuration: time.Millisecond,
+			},
+			traces:         simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4})),
+			expectedReason: "",
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			// prepare
+			tt.config.NumTraces = 5
+
+			received := make(chan pdata.Traces, 1)
+			next := &mockProcessor{
+				onTraces: func(_ context.Context, td pdata.Traces) error {
+					received <- td
+					return nil
+				},
+			}
+
+			p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, tt.config)
+			require.NoError(t, p.Start(context.Background(), nil))
+
+			// test
+			require.NoError(t, p.ConsumeTraces(context.Background(), tt.traces))
+			if tt.shutdown {
+				require.NoError(t, p.Shutdown(context.Background()))
+			} else {
+				defer p.Shutdown(context.Background())
+			}
+
+			// verify
+			td := <-received
+			assertReleaseReason(t, tt.expectedReason, td)
+		})
+	}
+}
+
+func TestMarkCompletenessOnEviction(t *testing.T) {
 	// prepare
 	config := Config{
-		WaitDuration: time.Second, // we are not waiting for this whole time
-		NumTraces:    5,
+		// long enough so that the only way for traces to be released is via eviction
+		WaitDuration:     time.Hour,
+		NumTraces:        10,
+		MarkCompleteness: true,
 	}
-	expectedError := errors.New("some unexpected error")
-	st := &mockStorage{
-		onCreateOrAppend: func(pdata.TraceID, pdata.ResourceSpans) error {
-			return expectedError
+
+	received := make(chan pdata.Traces, 1)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
 		},
 	}
-	next := &mockProcessor{}
-
-	p := newGroupByTraceProcessor(logger, st, next, config)
-	require.NotNil(t, p)
 
-	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	first := simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+	second := simpleTracesWithID(pdata.NewTraceID([16]byte{2, 3, 4, 5}))
 
-	rs := pdata.NewResourceSpans()
-	rs.InstrumentationLibrarySpans().Resize(1)
-	ils := rs.InstrumentationLibrarySpans().At(0)
-	ils.Spans().Resize(1)
-	span := ils.Spans().At(0)
-	span.SetTraceID(traceID)
-	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4}))
+	st := newShardedMemoryStorage(1)
+	// room for a single trace
+	st.maxBytes = resourceSpansSize(first.ResourceSpans().At(0)) + 1
 
-	batch := splitByTrace(rs)
+	p := newGroupByTraceProcessor(logger, st, next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
 
 	// test
-	err := p.processBatch(batch[0])
+	require.NoError(t, p.ConsumeTraces(context.Background(), first))
+	require.NoError(t, p.ConsumeTraces(context.Background(), second))
 
 	// verify
-	assert.True(t, errors.Is(err, expectedError))
+	td := <-received
+	assertReleaseReason(t, releaseReasonEvicted, td)
 }
 
-func TestAddSpansToExistingTrace(t *testing.T) {
+func TestMaxSpansPerTrace(t *testing.T) {
+	for _, tt := range []struct {
+		casename      string
+		policy        string
+		expectedSpans int
+	}{
+		{
+			casename:      "drop",
+			policy:        dropTruncatedSpansPolicy,
+			expectedSpans: 1,
+		},
+		{
+			casename:      "forward",
+			policy:        forwardTruncatedSpansPolicy,
+			expectedSpans: 3,
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			// prepare
+			views := MetricViews()
+
+			// ensure that we are starting with a clean state
+			view.Unregister(views...)
+			view.Register(views...)
+			defer view.Unregister(views...)
+
+			config := Config{
+				WaitDuration:         50 * time.Millisecond,
+				NumTraces:            10,
+				MaxSpansPerTrace:     1,
+				TruncatedSpansPolicy: tt.policy,
+			}
+
+			var mu sync.Mutex
+			var received []pdata.Traces
+			next := &mockProcessor{
+				onTraces: func(_ context.Context, td pdata.Traces) error {
+					mu.Lock()
+					received = append(received, td)
+					mu.Unlock()
+					return nil
+				},
+			}
+			receivedSpans := func() int {
+				mu.Lock()
+				defer mu.Unlock()
+				count := 0
+				for _, td := range received {
+					count += td.SpanCount()
+				}
+				return count
+			}
+
+			st := newShardedMemoryStorage(1)
+			st.maxSpansPerTrace = config.MaxSpansPerTrace
+
+			p := newGroupByTraceProcessor(logger, st, next, config)
+			require.NoError(t, p.Start(context.Background(), nil))
+
+			traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+			// test
+			for i := 0; i < 3; i++ {
+				require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+			}
+
+			// verify
+			assert.Eventually(t, func() bool {
+				return receivedSpans() == tt.expectedSpans
+			}, time.Second, 10*time.Millisecond)
+
+			// the buffered trace is released only once
+			assert.Eventually(t, func() bool {
+				return p.numTracesInFlight() == 0
+			}, time.Second, 10*time.Millisecond)
+			require.NoError(t, p.Shutdown(context.Background()))
+			assert.Equal(t, tt.expectedSpans, receivedSpans())
+
+			for _, td := range received {
+				for i := 0; i < td.ResourceSpans().Len(); i++ {
+					truncated, ok := td.ResourceSpans().At(i).Resource().Attributes().Get(truncatedAttribute)
+					require.True(t, ok)
+					assert.True(t, truncated.BoolVal())
+				}
+			}
+
+			viewData, err := view.RetrieveData("processor/groupbytrace/" + mSpansTruncated.Name())
+			require.NoError(t, err)
+			require.Len(t, viewData, 1)
+			assert.EqualValues(t, 2, viewData[0].Data.(*view.SumData).Value)
+		})
+	}
+}
+
+func TestTruncatedSpansAreForwardedDuringShutdown(t *testing.T) {
 	// prepare
-	wg := &sync.WaitGroup{}
 	config := Config{
-		WaitDuration: 50 * time.Millisecond,
-		NumTraces:    5,
+		WaitDuration:         time.Minute,
+		NumTraces:            5,
+		MaxSpansPerTrace:     1,
+		TruncatedSpansPolicy: forwardTruncatedSpansPolicy,
 	}
-	st := newMemoryStorage()
 
-	var receivedTraces []pdata.ResourceSpans
+	var received []pdata.Traces
 	next := &mockProcessor{
-		onTraces: func(ctx context.Context, traces pdata.Traces) error {
-			require.Equal(t, 2, traces.ResourceSpans().Len())
-			receivedTraces = append(receivedTraces, traces.ResourceSpans().At(0))
-			receivedTraces = append(receivedTraces, traces.ResourceSpans().At(1))
-			wg.Done()
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received = append(received, td)
 			return nil
 		},
 	}
-
+	st := newMemoryStorage()
+	st.maxSpansPerTrace = config.MaxSpansPerTrace
 	p := newGroupByTraceProcessor(logger, st, next, config)
-	require.NotNil(t, p)
-
-	ctx := context.Background()
-	p.Start(ctx, nil)
-	defer p.Shutdown(ctx)
 
 	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	p.onBatchReceived(context.Background(), simpleTracesWithID(traceID))
 
-	// test
-	first := simpleTracesWithID(traceID)
-	first.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetName("first-span")
-
-	second := simpleTracesWithID(traceID)
-	second.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetName("second-span")
+	// the event machine isn't taking events anymore, as when the truncated spans are the last events in the queue
+	p.workers[0].eventMachine.shutdown()
 
-	wg.Add(1)
+	// test
+	p.onBatchReceived(context.Background(), simpleTracesWithID(traceID))
 
-	p.ConsumeTraces(context.Background(), first)
-	p.ConsumeTraces(context.Background(), second)
+	// verify
+	require.Len(t, received, 1)
+	assert.Equal(t, 1, received[0].SpanCount())
+	truncated, ok := received[0].ResourceSpans().At(0).Resource().Attributes().Get(truncatedAttribute)
+	require.True(t, ok)
+	assert.True(t, truncated.BoolVal())
+}
 
-	wg.Wait()
+func TestWaitDurationJitter(t *testing.T) {
+	for _, tt := range []struct {
+		casename string
+		jitter   time.Duration
+	}{
+		{
+			casename: "no jitter",
+		},
+		{
+			casename: "with jitter",
+			jitter:   5 * time.Second,
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			// prepare
+			config := Config{
+				WaitDuration:       10 * time.Second,
+				WaitDurationJitter: tt.jitter,
+				NumTraces:          200,
+			}
+
+			var released int64
+			next := &mockProcessor{
+				onTraces: func(context.Context, pdata.Traces) error {
+					atomic.AddInt64(&released, 1)
+					return nil
+				},
+			}
+
+			clock := newFakeClock()
+			p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, config)
+			p.clock = clock
+			require.NoError(t, p.Start(context.Background(), nil))
+			defer p.Shutdown(context.Background())
+
+			// test
+			for i := 0; i < 100; i++ {
+				require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(pdata.NewTraceID([16]byte{byte(i + 1)}))))
+			}
+			require.Eventually(t, func() bool {
+				return len(clock.durations()) == 100
+			}, time.Second, time.Millisecond)
+
+			// verify
+			distinct := map[time.Duration]struct{}{}
+			releasedOnTime := int64(0)
+			for _, d := range clock.durations() {
+				assert.GreaterOrEqual(t, int64(d), int64(config.WaitDuration))
+				assert.LessOrEqual(t, int64(d), int64(config.WaitDuration+tt.jitter))
+				distinct[d] = struct{}{}
+				if d == config.WaitDuration {
+					releasedOnTime++
+				}
+			}
+			if tt.jitter == 0 {
+				assert.Len(t, distinct, 1)
+			} else {
+				// the chances of two traces getting the same jitter are tiny
+				assert.Greater(t, len(distinct), 90)
+			}
+
+			// only the traces without a jitter are released once the wait duration is over
+			clock.advance(config.WaitDuration)
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt64(&released) == releasedOnTime
+			}, time.Second, time.Millisecond)
+
+			clock.advance(tt.jitter)
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt64(&released) == 100
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
 
-	// verify
-	assert.Len(t, receivedTraces, 2)
+func TestWaitDurationOverrideValues(t *testing.T) {
+	for _, tt := range []struct {
+		casename string
+		values   []pdata.AttributeValue
+		max      time.Duration
+		expected time.Duration
+	}{
+		{
+			casename: "no attribute",
+			expected: 0,
+		},
+		{
+			casename: "int",
+			values:   []pdata.AttributeValue{pdata.NewAttributeValueInt(1500)},
+			expected: 1500 * time.Millisecond,
+		},
+		{
+			casename: "double",
+			values:   []pdata.AttributeValue{pdata.NewAttributeValueDouble(1.5)},
+			expected: 1500 * time.Microsecond,
+		},
+		{
+			casename: "string",
+			values:   []pdata.AttributeValue{pdata.NewAttributeValueString("250")},
+			expected: 250 * time.Millisecond,
+		},
+		{
+			casename: "invalid string",
+			values:   []pdata.AttributeValue{pdata.NewAttributeValueString("a while")},
+			expected: 0,
+		},
+		{
+			casename: "longest wins",
+			values:   []pdata.AttributeValue{pdata.NewAttributeValueInt(100), pdata.NewAttributeValueInt(300), pdata.NewAttributeValueInt(200)},
+			expected: 300 * time.Millisecond,
+		},
+		{
+			casename: "clamped",
+			values:   []pdata.AttributeValue{pdata.NewAttributeValueInt(60_000)},
+			max:      time.Second,
+			expected: time.Second,
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			// prepare
+			config := Config{
+				WaitDurationAttribute: "groupbytrace.wait_ms",
+				MaxWaitDuration:       tt.max,
+			}
+			p := newGroupByTraceProcessor(logger, newMemoryStorage(), &mockProcessor{}, config)
+
+			rs := pdata.NewResourceSpans()
+			rs.InstrumentationLibrarySpans().Resize(1)
+			spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+			spans.Resize(len(tt.values) + 1)
+			for i, v := range tt.values {
+				spans.At(i).Attributes().Insert("groupbytrace.wait_ms", v)
+			}
+
+			// test
+			override := p.waitDurationOverride(rs)
+
+			// verify
+			assert.Equal(t, tt.expected, override)
+		})
+	}
 }
 
 func TestTraceErrorFromStorageWhileProcessingSecondTrace(t *testing.T) {
@@ -357,19 +1568,19 @@ func TestTraceErrorFromStorageWhileProcessingSecondTrace(t *testing.T) {
 	span.SetTraceID(traceID)
 	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4}))
 
-	batch := splitByTrace(rs)
+	batch := splitByKey(rs, "")
 
 	// test
-	err := p.processBatch(batch[0])
+	err := p.processBatch(context.Background(), batch[0])
 	assert.NoError(t, err)
 
 	expectedError := errors.New("some unexpected error")
-	st.onCreateOrAppend = func(pdata.TraceID, pdata.ResourceSpans) error {
+	st.onCreateOrAppend = func(context.Context, string, pdata.ResourceSpans) error {
 		return expectedError
 	}
 
 	// processing another batch for the same trace takes a slightly different code path
-	err = p.processBatch(batch[0])
+	err = p.processBatch(context.Background(), batch[0])
 
 	// verify
 	assert.True(t, errors.Is(err, expectedError))
@@ -383,7 +1594,7 @@ func TestErrorFromStorageWhileRemovingTrace(t *testing.T) {
 	}
 	expectedError := errors.New("some unexpected error")
 	st := &mockStorage{
-		onDelete: func(pdata.TraceID) ([]pdata.ResourceSpans, error) {
+		onDelete: func(context.Context, string) ([]pdata.ResourceSpans, error) {
 			return nil, expectedError
 		},
 	}
@@ -395,7 +1606,7 @@ func TestErrorFromStorageWhileRemovingTrace(t *testing.T) {
 	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
 
 	// test
-	err := p.onTraceRemoved(traceID)
+	err := p.onTraceRemoved(context.Background(), traceID.HexString())
 
 	// verify
 	assert.True(t, errors.Is(err, expectedError))
@@ -408,7 +1619,7 @@ func TestTraceNotFoundWhileRemovingTrace(t *testing.T) {
 		NumTraces:    5,
 	}
 	st := &mockStorage{
-		onDelete: func(pdata.TraceID) ([]pdata.ResourceSpans, error) {
+		onDelete: func(context.Context, string) ([]pdata.ResourceSpans, error) {
 			return nil, nil
 		},
 	}
@@ -420,7 +1631,7 @@ func TestTraceNotFoundWhileRemovingTrace(t *testing.T) {
 	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
 
 	// test
-	err := p.onTraceRemoved(traceID)
+	err := p.onTraceRemoved(context.Background(), traceID.HexString())
 
 	// verify
 	assert.Error(t, err)
@@ -471,8 +1682,8 @@ func TestTracesAreDispatchedInIndividualBatches(t *testing.T) {
 	// test
 	wg.Add(2)
 
-	p.processResourceSpans(firstResourceSpans)
-	p.processResourceSpans(secondResourceSpans)
+	p.processResourceSpans(context.Background(), firstResourceSpans)
+	p.processResourceSpans(context.Background(), secondResourceSpans)
 
 	wg.Wait()
 
@@ -509,19 +1720,19 @@ func TestSplitSameTraceIntoDifferentBatches(t *testing.T) {
 	thirdSpan.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
 
 	// test
-	batches := splitByTrace(input)
+	batches := splitByKey(input, "")
 
 	// verify
 	assert.Len(t, batches, 2)
 
 	// first batch
-	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}), batches[0].traceID)
+	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), batches[0].key)
 	assert.Equal(t, firstLibrary.Name(), batches[0].rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
 	assert.Equal(t, firstSpan.Name(), batches[0].rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
 	assert.Equal(t, secondSpan.Name(), batches[0].rs.InstrumentationLibrarySpans().At(0).Spans().At(1).Name())
 
 	// second batch
-	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}), batches[1].traceID)
+	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), batches[1].key)
 	assert.Equal(t, secondLibrary.Name(), batches[1].rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
 	assert.Equal(t, thirdSpan.Name(), batches[1].rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
 }
@@ -546,18 +1757,18 @@ func TestSplitDifferentTracesIntoDifferentBatches(t *testing.T) {
 	secondSpan.SetTraceID(pdata.NewTraceID([16]byte{2, 3, 4, 5}))
 
 	// test
-	batches := splitByTrace(input)
+	batches := splitByKey(input, "")
 
 	// verify
 	assert.Len(t, batches, 2)
 
 	// first batch
-	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}), batches[0].traceID)
+	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), batches[0].key)
 	assert.Equal(t, library.Name(), batches[0].rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
 	assert.Equal(t, firstSpan.Name(), batches[0].rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
 
 	// second batch
-	assert.Equal(t, pdata.NewTraceID([16]byte{2, 3, 4, 5}), batches[1].traceID)
+	assert.Equal(t, pdata.NewTraceID([16]byte{2, 3, 4, 5}).HexString(), batches[1].key)
 	assert.Equal(t, library.Name(), batches[1].rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
 	assert.Equal(t, secondSpan.Name(), batches[1].rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
 }
@@ -572,12 +1783,180 @@ func TestSplitByTraceWithNilTraceID(t *testing.T) {
 	firstSpan.SetTraceID(pdata.NewTraceID([16]byte{}))
 
 	// test
-	batches := splitByTrace(input)
+	batches := splitByKey(input, "")
 
 	// verify
 	assert.Len(t, batches, 0)
 }
 
+func TestMergeResourceSpans(t *testing.T) {
+	// prepare
+	first := pdata.NewResourceSpans()
+	first.Resource().Attributes().InsertString("service.name", "checkout")
+	first.Resource().Attributes().InsertString("host.name", "host-1")
+	first.InstrumentationLibrarySpans().Resize(1)
+	first.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+
+	// same resource as the first, with the attributes in a different order
+	second := pdata.NewResourceSpans()
+	second.Resource().Attributes().InsertString("host.name", "host-1")
+	second.Resource().Attributes().InsertString("service.name", "checkout")
+	second.InstrumentationLibrarySpans().Resize(1)
+	second.InstrumentationLibrarySpans().At(0).Spans().Resize(2)
+
+	// a different resource
+	third := pdata.NewResourceSpans()
+	third.Resource().Attributes().InsertString("service.name", "payment")
+	third.InstrumentationLibrarySpans().Resize(1)
+	third.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+
+	// the same value as the third, but with a different type
+	fourth := pdata.NewResourceSpans()
+	fourth.Resource().Attributes().InsertInt("service.name", 1)
+	fourth.InstrumentationLibrarySpans().Resize(1)
+	third.Resource().Attributes().UpdateString("service.name", "1")
+
+	// test
+	merged := mergeResourceSpans([]pdata.ResourceSpans{first, second, third, fourth})
+
+	// verify
+	require.Len(t, merged, 3)
+	assert.Equal(t, 2, merged[0].InstrumentationLibrarySpans().Len())
+	assert.Equal(t, 2, merged[0].InstrumentationLibrarySpans().At(1).Spans().Len())
+	assert.Equal(t, third, merged[1])
+	assert.Equal(t, fourth, merged[2])
+}
+
+func TestReleasedTraceHasOneResourceSpansPerResource(t *testing.T) {
+	// prepare
+	config := Config{
+		MergeResourceSpans: true,
+	}
+
+	var received pdata.Traces
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			received = td
+			return nil
+		},
+	}
+	p := newGroupByTraceProcessor(logger, newMemoryStorage(), next, config)
+
+	var rss []pdata.ResourceSpans
+	for i := 0; i < 3; i++ {
+		rs := simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4})).ResourceSpans().At(0)
+		rs.Resource().Attributes().InsertString("service.name", "checkout")
+		rss = append(rss, rs)
+	}
+
+	// test
+	require.NoError(t, p.onTraceReleased(context.Background(), rss))
+
+	// verify
+	assert.Equal(t, 1, received.ResourceSpans().Len())
+	assert.Equal(t, 3, received.SpanCount())
+}
+
+func TestSplitByGroupKey(t *testing.T) {
+	// prepare
+	input := pdata.NewResourceSpans()
+	input.Resource().Attributes().InsertString("session.id", "resource-session")
+	input.InstrumentationLibrarySpans().Resize(1)
+	ils := input.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(3)
+
+	// the first two spans belong to different traces, but share the same session
+	firstSpan := ils.Spans().At(0)
+	firstSpan.SetName("first-span")
+	firstSpan.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+	firstSpan.Attributes().InsertString("session.id", "span-session")
+	secondSpan := ils.Spans().At(1)
+	secondSpan.SetName("second-span")
+	secondSpan.SetTraceID(pdata.NewTraceID([16]byte{2, 3, 4, 5}))
+	secondSpan.Attributes().InsertString("session.id", "span-session")
+
+	// the third span doesn't have the attribute, so the resource's value is used
+	thirdSpan := ils.Spans().At(2)
+	thirdSpan.SetName("third-span")
+	thirdSpan.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+
+	// test
+	batches := splitByKey(input, "session.id")
+
+	// verify
+	require.Len(t, batches, 2)
+
+	assert.Equal(t, "session.id=span-session", batches[0].key)
+	assert.Equal(t, 2, batches[0].rs.InstrumentationLibrarySpans().At(0).Spans().Len())
+	assert.Equal(t, firstSpan.Name(), batches[0].rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, secondSpan.Name(), batches[0].rs.InstrumentationLibrarySpans().At(0).Spans().At(1).Name())
+
+	assert.Equal(t, "session.id=resource-session", batches[1].key)
+	assert.Equal(t, thirdSpan.Name(), batches[1].rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+}
+
+func TestSplitByGroupKeyFallsBackToTraceID(t *testing.T) {
+	// prepare
+	input := pdata.NewResourceSpans()
+	input.InstrumentationLibrarySpans().Resize(1)
+	ils := input.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+
+	// test
+	batches := splitByKey(input, "session.id")
+
+	// verify
+	require.Len(t, batches, 1)
+	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), batches[0].key)
+}
+
+func TestTracesWithSameGroupKeyAreReleasedTogether(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Millisecond,
+		NumTraces:    10,
+		GroupByKey:   "session.id",
+	}
+	st := newMemoryStorage()
+
+	var receivedSpans int
+	var mu sync.Mutex
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			mu.Lock()
+			receivedSpans = td.SpanCount()
+			mu.Unlock()
+			wg.Done()
+			return nil
+		},
+	}
+
+	p := newGroupByTraceProcessor(logger, st, next, config)
+	require.NoError(t, p.Start(context.Background(), nil))
+	defer p.Shutdown(context.Background())
+
+	first := simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+	first.ResourceSpans().At(0).Resource().Attributes().InsertString("session.id", "abc")
+	second := simpleTracesWithID(pdata.NewTraceID([16]byte{2, 3, 4, 5}))
+	second.ResourceSpans().At(0).Resource().Attributes().InsertString("session.id", "abc")
+
+	// test
+	batch := pdata.NewTraces()
+	batch.ResourceSpans().Append(first.ResourceSpans().At(0))
+	batch.ResourceSpans().Append(second.ResourceSpans().At(0))
+	require.NoError(t, p.ConsumeTraces(context.Background(), batch))
+
+	// verify
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, receivedSpans)
+}
+
 func TestErrorOnProcessResourceSpansContinuesProcessing(t *testing.T) {
 	// prepare
 	config := Config{
@@ -602,13 +1981,13 @@ func TestErrorOnProcessResourceSpansContinuesProcessing(t *testing.T) {
 
 	expectedError := errors.New("some unexpected error")
 	returnedError := false
-	st.onCreateOrAppend = func(pdata.TraceID, pdata.ResourceSpans) error {
+	st.onCreateOrAppend = func(context.Context, string, pdata.ResourceSpans) error {
 		returnedError = true
 		return expectedError
 	}
 
 	// test
-	p.processResourceSpans(rs)
+	p.processResourceSpans(context.Background(), rs)
 
 	// verify
 	assert.True(t, returnedError)
@@ -637,6 +2016,130 @@ func BenchmarkConsumeTracesCompleteOnFirstBatch(b *testing.B) {
 	}
 }
 
+func BenchmarkConsumeTracesWorkers(b *testing.B) {
+	for _, numWorkers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("%d workers", numWorkers), func(b *testing.B) {
+			// prepare
+			config := Config{
+				// long enough so that the traces are only released on shutdown
+				WaitDuration: time.Hour,
+				NumTraces:    b.N + 1,
+				NumWorkers:   numWorkers,
+			}
+			st := newMemoryStorage()
+			next := &mockProcessor{}
+
+			p := newGroupByTraceProcessor(zap.NewNop(), st, next, config)
+			require.NotNil(b, p)
+
+			ctx := context.Background()
+			p.Start(ctx, nil)
+			defer p.Shutdown(ctx)
+
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				traceID := pdata.NewTraceID([16]byte{byte(n), byte(n >> 8), byte(n >> 16), byte(n >> 24)})
+				p.ConsumeTraces(ctx, simpleTracesWithID(traceID))
+			}
+
+			// the batches are only processed once they leave the queues
+			for p.numEventsInQueue() > 0 {
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+}
+
+func BenchmarkConsumeAndReleaseTrace(b *testing.B) {
+	// prepare
+	config := Config{
+		WaitDuration: time.Nanosecond,
+		NumTraces:    10,
+	}
+
+	released := make(chan struct{})
+	next := &mockProcessor{
+		onTraces: func(context.Context, pdata.Traces) error {
+			released <- struct{}{}
+			return nil
+		},
+	}
+
+	p := newGroupByTraceProcessor(zap.NewNop(), newMemoryStorage(), next, config)
+	require.NotNil(b, p)
+
+	ctx := context.Background()
+	p.Start(ctx, nil)
+	defer p.Shutdown(ctx)
+
+	template := simpleTraces()
+	spans := template.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(10)
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+		spans.At(i).SetSpanID(pdata.NewSpanID([8]byte{byte(i + 1)}))
+		spans.At(i).SetName("some-span")
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		// the processor owns the consumed data, so each iteration gets its own copy
+		b.StopTimer()
+		td := pdata.NewTraces()
+		template.ResourceSpans().CopyTo(td.ResourceSpans())
+		b.StartTimer()
+
+		p.ConsumeTraces(ctx, td)
+		<-released
+	}
+}
+
+func BenchmarkReleaseUnmergedResourceSpans(b *testing.B) {
+	benchmarkRelease(b, false)
+}
+
+func BenchmarkReleaseMergedResourceSpans(b *testing.B) {
+	benchmarkRelease(b, true)
+}
+
+// benchmarkRelease releases a trace with 500 single-span resource spans from the same service, translating it
+// the way an exporter would
+func benchmarkRelease(b *testing.B, merge bool) {
+	config := Config{
+		MergeResourceSpans: merge,
+	}
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td pdata.Traces) error {
+			_, err := jaeger.InternalTracesToJaegerProto(td)
+			return err
+		},
+	}
+	p := newGroupByTraceProcessor(zap.NewNop(), newMemoryStorage(), next, config)
+
+	rss := make([]pdata.ResourceSpans, 500)
+	for i := range rss {
+		rss[i] = simpleTracesWithID(pdata.NewTraceID([16]byte{1, 2, 3, 4})).ResourceSpans().At(0)
+		rss[i].Resource().Attributes().InsertString("service.name", "checkout")
+		rss[i].InstrumentationLibrarySpans().At(0).Spans().At(0).SetSpanID(pdata.NewSpanID([8]byte{byte(i), byte(i >> 8), 1, 2}))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		// the release changes the resource spans, so each iteration gets its own copy
+		b.StopTimer()
+		trace := make([]pdata.ResourceSpans, len(rss))
+		for i, rs := range rss {
+			trace[i] = pdata.NewResourceSpans()
+			rs.CopyTo(trace[i])
+		}
+		b.StartTimer()
+
+		require.NoError(b, p.onTraceReleased(context.Background(), trace))
+	}
+}
+
 type mockProcessor struct {
 	onTraces func(context.Context, pdata.Traces) error
 }
@@ -660,30 +2163,30 @@ func (m *mockProcessor) Start(_ context.Context, _ component.Host) error {
 }
 
 type mockStorage struct {
-	onCreateOrAppend func(pdata.TraceID, pdata.ResourceSpans) error
-	onGet            func(pdata.TraceID) ([]pdata.ResourceSpans, error)
-	onDelete         func(pdata.TraceID) ([]pdata.ResourceSpans, error)
+	onCreateOrAppend func(context.Context, string, pdata.ResourceSpans) error
+	onGet            func(context.Context, string) ([]pdata.ResourceSpans, error)
+	onDelete         func(context.Context, string) ([]pdata.ResourceSpans, error)
 	onStart          func() error
 	onShutdown       func() error
 }
 
 var _ storage = (*mockStorage)(nil)
 
-func (st *mockStorage) createOrAppend(traceID pdata.TraceID, trace pdata.ResourceSpans) error {
+func (st *mockStorage) createOrAppend(ctx context.Context, key string, trace pdata.ResourceSpans) error {
 	if st.onCreateOrAppend != nil {
-		return st.onCreateOrAppend(traceID, trace)
+		return st.onCreateOrAppend(ctx, key, trace)
 	}
 	return nil
 }
-func (st *mockStorage) get(traceID pdata.TraceID) ([]pdata.ResourceSpans, error) {
+func (st *mockStorage) get(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
 	if st.onGet != nil {
-		return st.onGet(traceID)
+		return st.onGet(ctx, key)
 	}
 	return nil, nil
 }
-func (st *mockStorage) delete(traceID pdata.TraceID) ([]pdata.ResourceSpans, error) {
+func (st *mockStorage) delete(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
 	if st.onDelete != nil {
-		return st.onDelete(traceID)
+		return st.onDelete(ctx, key)
 	}
 	return nil, nil
 }
@@ -714,3 +2217,95 @@ func simpleTracesWithID(traceID pdata.TraceID) pdata.Traces {
 	ils.Spans().At(0).SetTraceID(traceID)
 	return traces
 }
+
+func simpleChildTracesWithID(traceID pdata.TraceID) pdata.Traces {
+	traces := simpleTracesWithID(traceID)
+	traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetParentSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4}))
+	return traces
+}
+
+func assertReleaseTriggers(t *testing.T, expected map[string]int64) {
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mReleaseTriggers.Name())
+	require.NoError(t, err)
+
+	actual := map[string]int64{}
+	for _, row := range viewData {
+		require.Len(t, row.Tags, 1)
+		actual[row.Tags[0].Value] = int64(row.Data.(*view.SumData).Value)
+	}
+	assert.Equal(t, expected, actual)
+}
+
+// assertReleaseReason checks that all the resources of the given traces have the expected release reason, with
+// an empty reason meaning that the attribute shouldn't be set
+func assertReleaseReason(t *testing.T, expected string, td pdata.Traces) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		reason, ok := td.ResourceSpans().At(i).Resource().Attributes().Get(releaseReasonAttribute)
+		if expected == "" {
+			assert.False(t, ok)
+			continue
+		}
+		require.True(t, ok)
+		assert.Equal(t, expected, reason.StringVal())
+	}
+}
+
+// fakeClock is a clock that only moves forward when told so, firing the timers that are due by then
+type fakeClock struct {
+	sync.Mutex
+	current time.Time
+	timers  []fakeTimer
+	all     []time.Duration
+}
+
+type fakeTimer struct {
+	at time.Time
+	f  func()
+}
+
+var _ clock = (*fakeClock)(nil)
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{current: time.Unix(1_600_000_000, 0)}
+}
+
+func (c *fakeClock) now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.current
+}
+
+func (c *fakeClock) afterFunc(d time.Duration, f func()) {
+	c.Lock()
+	defer c.Unlock()
+	c.timers = append(c.timers, fakeTimer{at: c.current.Add(d), f: f})
+	c.all = append(c.all, d)
+}
+
+// durations returns the durations of all the timers created so far
+func (c *fakeClock) durations() []time.Duration {
+	c.Lock()
+	defer c.Unlock()
+	return append([]time.Duration{}, c.all...)
+}
+
+// advance moves the clock forward, firing the timers that are due
+func (c *fakeClock) advance(d time.Duration) {
+	c.Lock()
+	c.current = c.current.Add(d)
+	var due []fakeTimer
+	var pending []fakeTimer
+	for _, timer := range c.timers {
+		if timer.at.After(c.current) {
+			pending = append(pending, timer)
+			continue
+		}
+		due = append(due, timer)
+	}
+	c.timers = pending
+	c.Unlock()
+
+	for _, timer := range due {
+		timer.f()
+	}
+}
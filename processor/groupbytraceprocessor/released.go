@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import "time"
+
+// releasedKeys remembers the keys of the traces that have been recently released, along with the moment in
+// which they were released, so that late spans for them can be detected. It holds up to a fixed number of
+// keys, forgetting the oldest ones to make room for new ones.
+type releasedKeys struct {
+	keys       *ringBuffer
+	releasedAt map[string]time.Time
+}
+
+func newReleasedKeys(size int) *releasedKeys {
+	return &releasedKeys{
+		keys:       newRingBuffer(size),
+		releasedAt: make(map[string]time.Time),
+	}
+}
+
+// record remembers that the trace with the given key has been released at the given time
+func (r *releasedKeys) record(key string, now time.Time) {
+	// a key that has been released before is moved to the newest position
+	r.keys.delete(key)
+	if evicted := r.keys.put(key); evicted != "" {
+		delete(r.releasedAt, evicted)
+	}
+	r.releasedAt[key] = now
+}
+
+// releasedWithin returns whether the trace with the given key has been released no longer than the given
+// period before now. Keys released before that are forgotten.
+func (r *releasedKeys) releasedWithin(key string, now time.Time, period time.Duration) bool {
+	releasedAt, found := r.releasedAt[key]
+	if !found {
+		return false
+	}
+
+	if now.Sub(releasedAt) > period {
+		r.forget(key)
+		return false
+	}
+
+	return true
+}
+
+func (r *releasedKeys) forget(key string) {
+	r.keys.delete(key)
+	delete(r.releasedAt, key)
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleasedWithinGracePeriod(t *testing.T) {
+	// prepare
+	released := newReleasedKeys(5)
+	now := time.Now()
+
+	// test
+	released.record("key", now)
+
+	// verify
+	assert.True(t, released.releasedWithin("key", now.Add(time.Second), time.Minute))
+	assert.False(t, released.releasedWithin("another-key", now.Add(time.Second), time.Minute))
+}
+
+func TestReleasedKeyIsForgottenAfterGracePeriod(t *testing.T) {
+	// prepare
+	released := newReleasedKeys(5)
+	now := time.Now()
+	released.record("key", now)
+
+	// test
+	late := released.releasedWithin("key", now.Add(2*time.Minute), time.Minute)
+
+	// verify
+	assert.False(t, late)
+	assert.NotContains(t, released.releasedAt, "key")
+	assert.False(t, released.keys.contains("key"))
+}
+
+func TestReleasedKeysCapacity(t *testing.T) {
+	// prepare
+	released := newReleasedKeys(2)
+	now := time.Now()
+
+	// test
+	released.record("first", now)
+	released.record("second", now)
+	released.record("first", now) // the newest now, making "second" the oldest
+	released.record("third", now)
+
+	// verify
+	assert.True(t, released.releasedWithin("first", now, time.Minute))
+	assert.False(t, released.releasedWithin("second", now, time.Minute))
+	assert.True(t, released.releasedWithin("third", now, time.Minute))
+	assert.Len(t, released.releasedAt, 2)
+}
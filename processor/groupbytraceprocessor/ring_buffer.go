@@ -14,56 +14,98 @@
 
 package groupbytraceprocessor
 
-import "go.opentelemetry.io/collector/consumer/pdata"
+import "sync/atomic"
 
-// ringBuffer keeps an in-memory bounded buffer with the in-flight trace IDs
+// ringBuffer keeps an in-memory bounded buffer with the keys of the in-flight traces.
+// It's not safe for concurrent use, except for len and the size, which never changes, that can be used from any
+// goroutine.
 type ringBuffer struct {
-	index     int
-	size      int
-	ids       []pdata.TraceID
-	idToIndex map[pdata.TraceID]int // key is traceID, value is the index on the 'ids' slice
+	count    int64 // the number of keys in the buffer, accessed atomically
+	index    int
+	size     int
+	keys     []string
+	keyToIdx map[string]int // value is the index on the 'keys' slice
 }
 
 func newRingBuffer(size int) *ringBuffer {
 	return &ringBuffer{
-		index:     -1, // the first span to be received will be placed at position '0'
-		size:      size,
-		ids:       make([]pdata.TraceID, size),
-		idToIndex: make(map[pdata.TraceID]int),
+		index:    -1, // the first span to be received will be placed at position '0'
+		size:     size,
+		keys:     make([]string, size),
+		keyToIdx: make(map[string]int),
 	}
 }
 
-func (r *ringBuffer) put(traceID pdata.TraceID) pdata.TraceID {
+// put places the key in the buffer, returning the key that had to be evicted to make room for it,
+// or an empty string if no key was evicted
+func (r *ringBuffer) put(key string) string {
 	// calculates the item in the ring that we'll store the trace
 	r.index = (r.index + 1) % r.size
 
 	// see if the ring has an item already
-	evicted := r.ids[r.index]
+	evicted := r.keys[r.index]
 
-	if !evicted.IsEmpty() {
+	if evicted != "" {
 		// clear space for the new item
 		r.delete(evicted)
 	}
 
-	// place the traceID in memory
-	r.ids[r.index] = traceID
-	r.idToIndex[traceID] = r.index
+	// place the key in memory
+	r.keys[r.index] = key
+	r.keyToIdx[key] = r.index
+	atomic.AddInt64(&r.count, 1)
 
 	return evicted
 }
 
-func (r *ringBuffer) contains(traceID pdata.TraceID) bool {
-	_, found := r.idToIndex[traceID]
+// putIfRoom places the key in the next free position of the buffer, returning false without placing it
+// in case the buffer is full
+func (r *ringBuffer) putIfRoom(key string) bool {
+	if r.len() >= r.size {
+		return false
+	}
+
+	for {
+		r.index = (r.index + 1) % r.size
+		if r.keys[r.index] == "" {
+			r.keys[r.index] = key
+			r.keyToIdx[key] = r.index
+			atomic.AddInt64(&r.count, 1)
+			return true
+		}
+	}
+}
+
+// len returns the number of keys in the buffer
+func (r *ringBuffer) len() int {
+	return int(atomic.LoadInt64(&r.count))
+}
+
+func (r *ringBuffer) contains(key string) bool {
+	_, found := r.keyToIdx[key]
 	return found
 }
 
-func (r *ringBuffer) delete(traceID pdata.TraceID) bool {
-	index, found := r.idToIndex[traceID]
+func (r *ringBuffer) delete(key string) bool {
+	index, found := r.keyToIdx[key]
 	if !found {
 		return false
 	}
 
-	delete(r.idToIndex, traceID)
-	r.ids[index] = pdata.InvalidTraceID()
+	delete(r.keyToIdx, key)
+	r.keys[index] = ""
+	atomic.AddInt64(&r.count, -1)
 	return true
 }
+
+// all returns the keys currently in the buffer, from the oldest to the newest
+func (r *ringBuffer) all() []string {
+	var result []string
+	for i := 1; i <= r.size; i++ {
+		key := r.keys[(r.index+i)%r.size]
+		if key != "" {
+			result = append(result, key)
+		}
+	}
+	return result
+}
@@ -26,13 +26,13 @@ func TestRingBufferCapacity(t *testing.T) {
 	buffer := newRingBuffer(5)
 
 	// test
-	traceIDs := []pdata.TraceID{
-		pdata.NewTraceID([16]byte{1, 2, 3, 4}),
-		pdata.NewTraceID([16]byte{2, 3, 4, 5}),
-		pdata.NewTraceID([16]byte{3, 4, 5, 6}),
-		pdata.NewTraceID([16]byte{4, 5, 6, 7}),
-		pdata.NewTraceID([16]byte{5, 6, 7, 8}),
-		pdata.NewTraceID([16]byte{6, 7, 8, 9}),
+	traceIDs := []string{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
+		pdata.NewTraceID([16]byte{2, 3, 4, 5}).HexString(),
+		pdata.NewTraceID([16]byte{3, 4, 5, 6}).HexString(),
+		pdata.NewTraceID([16]byte{4, 5, 6, 7}).HexString(),
+		pdata.NewTraceID([16]byte{5, 6, 7, 8}).HexString(),
+		pdata.NewTraceID([16]byte{6, 7, 8, 9}).HexString(),
 	}
 	for _, traceID := range traceIDs {
 		buffer.put(traceID)
@@ -51,7 +51,7 @@ func TestRingBufferCapacity(t *testing.T) {
 func TestDeleteFromBuffer(t *testing.T) {
 	// prepare
 	buffer := newRingBuffer(2)
-	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
 	buffer.put(traceID)
 
 	// test
@@ -65,7 +65,7 @@ func TestDeleteFromBuffer(t *testing.T) {
 func TestDeleteNonExistingFromBuffer(t *testing.T) {
 	// prepare
 	buffer := newRingBuffer(2)
-	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
 
 	// test
 	deleted := buffer.delete(traceID)
@@ -74,3 +74,64 @@ func TestDeleteNonExistingFromBuffer(t *testing.T) {
 	assert.False(t, deleted)
 	assert.False(t, buffer.contains(traceID))
 }
+
+func TestAllKeysFromBuffer(t *testing.T) {
+	// prepare
+	buffer := newRingBuffer(3)
+	traceIDs := []string{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(),
+		pdata.NewTraceID([16]byte{2, 3, 4, 5}).HexString(),
+		pdata.NewTraceID([16]byte{3, 4, 5, 6}).HexString(),
+		pdata.NewTraceID([16]byte{4, 5, 6, 7}).HexString(),
+	}
+	for _, traceID := range traceIDs {
+		buffer.put(traceID)
+	}
+	buffer.delete(traceIDs[2])
+
+	// test
+	retrieved := buffer.all()
+
+	// verify
+	assert.Equal(t, []string{traceIDs[1], traceIDs[3]}, retrieved)
+}
+
+func TestPutIfRoom(t *testing.T) {
+	// prepare
+	buffer := newRingBuffer(3)
+	keys := []string{"first", "second", "third"}
+	for _, key := range keys {
+		assert.True(t, buffer.putIfRoom(key))
+	}
+
+	// test
+	placed := buffer.putIfRoom("fourth")
+
+	// verify
+	assert.False(t, placed)
+	assert.Equal(t, 3, buffer.len())
+	for _, key := range keys {
+		assert.True(t, buffer.contains(key))
+	}
+
+	// the freed position is used, without evicting any other key
+	buffer.delete("second")
+	assert.True(t, buffer.putIfRoom("fourth"))
+	assert.True(t, buffer.contains("first"))
+	assert.True(t, buffer.contains("third"))
+	assert.True(t, buffer.contains("fourth"))
+}
+
+func TestRingBufferLen(t *testing.T) {
+	// prepare
+	buffer := newRingBuffer(2)
+
+	// test
+	buffer.put("first")
+	buffer.put("second")
+	buffer.put("third") // evicts the first
+	buffer.delete("second")
+
+	// verify
+	assert.Equal(t, 1, buffer.len())
+}
@@ -15,24 +15,33 @@
 package groupbytraceprocessor
 
 import (
+	"context"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 // storage is an abstraction for the span storage used by the groupbytrace processor.
-// Implementations should be safe for concurrent use.
+// Traces are stored under a group key, which is the hex representation of the trace ID unless the
+// processor is configured to group spans by another attribute.
+// Implementations should be safe for concurrent use, and should give up on the operations whose
+// context is done.
 type storage interface {
-	// createOrAppend will check whether the given trace ID is already in the storage and
+	// createOrAppend will check whether the given key is already in the storage and
 	// will either append the given spans to the existing record, or create a new trace with
-	// the given resource spans
-	createOrAppend(pdata.TraceID, pdata.ResourceSpans) error
+	// the given resource spans. The storage takes the ownership of the given resource spans,
+	// which shouldn't be changed by the caller afterwards. Storages limiting the number of spans per trace
+	// return errMaxSpansPerTraceReached instead, in which case the caller keeps the resource spans, and
+	// storages that have been shut down return errStorageStopped.
+	createOrAppend(context.Context, string, pdata.ResourceSpans) error
 
-	// get will retrieve the trace based on the given trace ID, returning nil in case a trace
+	// get will retrieve the trace based on the given key, returning nil in case a trace
 	// cannot be found
-	get(pdata.TraceID) ([]pdata.ResourceSpans, error)
+	get(context.Context, string) ([]pdata.ResourceSpans, error)
 
-	// delete will remove the trace based on the given trace ID, returning the trace that was removed,
-	// or nil in case a trace cannot be found
-	delete(pdata.TraceID) ([]pdata.ResourceSpans, error)
+	// delete will remove the trace based on the given key, returning the trace that was removed,
+	// or nil in case a trace cannot be found. The ownership of the returned resource spans is
+	// transferred to the caller, which is then free to change them.
+	delete(context.Context, string) ([]pdata.ResourceSpans, error)
 
 	// start gives the storage the opportunity to initialize any resources or procedures
 	start() error
@@ -40,3 +49,17 @@ type storage interface {
 	// shutdown signals the storage that the processor is shutting down
 	shutdown() error
 }
+
+// recoverableStorage is implemented by storages that are able to keep traces across restarts of the processor.
+type recoverableStorage interface {
+	// keys returns the keys of all the traces currently held by the storage. It's called right after
+	// the storage is started, so that the processor can schedule the release of the traces found there.
+	keys() ([]string, error)
+}
+
+// evictingStorage is implemented by storages that might evict traces on their own, such as when a size limit is reached.
+type evictingStorage interface {
+	// setEvictionCallback registers the function to call with the traces evicted by the storage. The callback
+	// is called after the trace has been removed from the storage.
+	setEvictionCallback(func(string, []pdata.ResourceSpans))
+}
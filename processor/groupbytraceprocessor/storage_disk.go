@@ -0,0 +1,281 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dgraph-io/badger/v2"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// the length of the sequence number suffixing each entry key, in hex digits
+const diskSequenceLength = 16
+
+var errDiskStorageNotStarted = errors.New("the disk storage hasn't been started")
+
+// diskStorage keeps the resource spans for each trace in a local badger database. Each batch of resource spans
+// appended to a trace is stored as its own entry, keyed by the trace's group key followed by a sequence number,
+// with the value being the serialized OTLP form of the batch. This way, appending to a trace doesn't require
+// rewriting what was stored for it so far, and the entries of a trace are read with a prefix scan, in the order
+// they were appended.
+type diskStorage struct {
+	directory string
+	db        *badger.DB
+
+	// the sequence number of the last entry written, accessed atomically
+	sequence uint64
+
+	// closing stopCh signals that the storage has been shut down, with the writes being refused from then on
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+var _ storage = (*diskStorage)(nil)
+var _ recoverableStorage = (*diskStorage)(nil)
+
+func newDiskStorage(directory string) *diskStorage {
+	return &diskStorage{
+		directory: directory,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+func (st *diskStorage) createOrAppend(ctx context.Context, key string, rs pdata.ResourceSpans) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case <-st.stopCh:
+		// the processor is done with this storage, and wouldn't release these spans anymore
+		return errStorageStopped
+	default:
+	}
+
+	if st.db == nil {
+		return errDiskStorageNotStarted
+	}
+
+	// the given resource spans are only read for the serialization
+	batch := pdata.NewTraces()
+	batch.ResourceSpans().Append(rs)
+	value, err := batch.ToOtlpProtoBytes()
+	if err != nil {
+		return fmt.Errorf("couldn't serialize trace %q: %w", key, err)
+	}
+
+	entryKey := diskEntryKey(key, atomic.AddUint64(&st.sequence, 1))
+	return st.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(entryKey, value)
+	})
+}
+
+func (st *diskStorage) get(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if st.db == nil {
+		return nil, errDiskStorageNotStarted
+	}
+
+	var result []pdata.ResourceSpans
+	err := st.db.View(func(txn *badger.Txn) error {
+		var err error
+		result, _, err = readTrace(txn, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// delete will return the resource spans that were stored for the given trace. As the returned objects
+// are deserialized from the disk, changes to them are not applied to the version in the storage.
+func (st *diskStorage) delete(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if st.db == nil {
+		return nil, errDiskStorageNotStarted
+	}
+
+	var result []pdata.ResourceSpans
+	err := st.db.Update(func(txn *badger.Txn) error {
+		var entryKeys [][]byte
+		var err error
+		result, entryKeys, err = readTrace(txn, key)
+		if err != nil {
+			return err
+		}
+		for _, entryKey := range entryKeys {
+			if err := txn.Delete(entryKey); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (st *diskStorage) start() error {
+	opts := badger.DefaultOptions(st.directory).WithLogger(nil)
+	db, err := badger.Open(opts)
+	if err != nil {
+		return fmt.Errorf("couldn't open the disk storage at %q: %w", st.directory, err)
+	}
+	st.db = db
+
+	// the new entries go after the ones found on disk, so that the order of the batches of a trace is kept
+	return st.db.View(func(txn *badger.Txn) error {
+		return iterateEntryKeys(txn, nil, func(_ string, sequence uint64) {
+			if sequence > st.sequence {
+				st.sequence = sequence
+			}
+		})
+	})
+}
+
+func (st *diskStorage) shutdown() error {
+	var err error
+	st.stopOnce.Do(func() {
+		// the writes after this point are refused with errStorageStopped, and the other operations fail with
+		// badger.ErrDBClosed
+		close(st.stopCh)
+		if st.db != nil {
+			err = st.db.Close()
+		}
+	})
+	return err
+}
+
+// keys returns the keys of all the traces currently found on disk
+func (st *diskStorage) keys() ([]string, error) {
+	if st.db == nil {
+		return nil, errDiskStorageNotStarted
+	}
+
+	var result []string
+	err := st.db.View(func(txn *badger.Txn) error {
+		// the entries are sorted by key, so the ones for the same trace come one after the other
+		return iterateEntryKeys(txn, nil, func(key string, _ uint64) {
+			if len(result) == 0 || result[len(result)-1] != key {
+				result = append(result, key)
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// readTrace returns the resource spans stored for the given trace, along with the keys of their entries, or nil in
+// case the trace doesn't exist
+func readTrace(txn *badger.Txn, key string) ([]pdata.ResourceSpans, [][]byte, error) {
+	var result []pdata.ResourceSpans
+	var entryKeys [][]byte
+
+	opts := badger.DefaultIteratorOptions
+	prefix := diskEntryPrefix(key)
+	opts.Prefix = prefix
+
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		item := it.Item()
+		if len(item.Key()) != len(prefix)+diskSequenceLength {
+			// an entry for another trace, whose key starts with this one's prefix
+			continue
+		}
+
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		batch := pdata.NewTraces()
+		if err := batch.FromOtlpProtoBytes(value); err != nil {
+			return nil, nil, fmt.Errorf("couldn't deserialize trace %q: %w", key, err)
+		}
+		for i := 0; i < batch.ResourceSpans().Len(); i++ {
+			result = append(result, batch.ResourceSpans().At(i))
+		}
+		entryKeys = append(entryKeys, item.KeyCopy(nil))
+	}
+
+	return result, entryKeys, nil
+}
+
+// iterateEntryKeys calls the given function with the trace key and sequence number of each entry starting with the
+// given prefix, in the order of their keys, without reading their values
+func iterateEntryKeys(txn *badger.Txn, prefix []byte, fn func(key string, sequence uint64)) error {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Prefix = prefix
+
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		key, sequence, ok := parseDiskEntryKey(it.Item().Key())
+		if !ok {
+			return fmt.Errorf("invalid entry key %q in the disk storage", it.Item().Key())
+		}
+		fn(key, sequence)
+	}
+	return nil
+}
+
+// diskEntryPrefix returns the prefix of the keys of the entries for the given trace
+func diskEntryPrefix(key string) []byte {
+	return []byte(key + "/")
+}
+
+// diskEntryKey returns the key of the entry with the given sequence number for the given trace. The sequence number
+// has a fixed width, so that the entries of a trace are sorted in the order they were written.
+func diskEntryKey(key string, sequence uint64) []byte {
+	return []byte(fmt.Sprintf("%s/%0*x", key, diskSequenceLength, sequence))
+}
+
+// parseDiskEntryKey returns the trace key and sequence number of the given entry key
+func parseDiskEntryKey(entryKey []byte) (string, uint64, bool) {
+	s := string(entryKey)
+	i := strings.LastIndexByte(s, '/')
+	if i < 0 || len(s)-i-1 != diskSequenceLength {
+		return "", 0, false
+	}
+	sequence, err := strconv.ParseUint(s[i+1:], 16, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return s[:i], sequence, true
+}
@@ -0,0 +1,363 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestDiskCreateAndGetTrace(t *testing.T) {
+	// prepare
+	st, cleanup := newStartedDiskStorage(t)
+	defer cleanup()
+
+	traceIDs := []pdata.TraceID{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		pdata.NewTraceID([16]byte{2, 3, 4, 5}),
+	}
+
+	baseTrace := pdata.NewResourceSpans()
+	baseTrace.InstrumentationLibrarySpans().Resize(1)
+	ils := baseTrace.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+
+	// test
+	for _, traceID := range traceIDs {
+		span.SetTraceID(traceID)
+		require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), baseTrace))
+	}
+
+	// verify
+	stored, err := st.keys()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{traceIDs[0].HexString(), traceIDs[1].HexString()}, stored)
+	for _, traceID := range traceIDs {
+		expected := pdata.NewResourceSpans()
+		baseTrace.CopyTo(expected)
+		expected.InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(traceID)
+
+		retrieved, err := st.get(context.Background(), traceID.HexString())
+		require.NoError(t, err)
+		assert.Equal(t, []pdata.ResourceSpans{expected}, retrieved)
+	}
+}
+
+func TestDiskGetNonExistingTrace(t *testing.T) {
+	// prepare
+	st, cleanup := newStartedDiskStorage(t)
+	defer cleanup()
+
+	// test
+	retrieved, err := st.get(context.Background(), pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString())
+
+	// verify
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+}
+
+func TestDiskDeleteTrace(t *testing.T) {
+	// prepare
+	st, cleanup := newStartedDiskStorage(t)
+	defer cleanup()
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	trace := pdata.NewResourceSpans()
+	trace.InstrumentationLibrarySpans().Resize(1)
+	ils := trace.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(traceID)
+
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), trace))
+
+	// test
+	deleted, err := st.delete(context.Background(), traceID.HexString())
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []pdata.ResourceSpans{trace}, deleted)
+
+	retrieved, err := st.get(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+
+	deleted, err = st.delete(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestDiskAppendSpans(t *testing.T) {
+	// prepare
+	st, cleanup := newStartedDiskStorage(t)
+	defer cleanup()
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	ils := batch.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(traceID)
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4}))
+
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), batch))
+
+	secondBatch := pdata.NewResourceSpans()
+	secondBatch.InstrumentationLibrarySpans().Resize(1)
+	secondIls := secondBatch.InstrumentationLibrarySpans().At(0)
+	secondIls.Spans().Resize(1)
+	secondSpan := secondIls.Spans().At(0)
+	secondSpan.SetName("second-name")
+	secondSpan.SetTraceID(traceID)
+	secondSpan.SetSpanID(pdata.NewSpanID([8]byte{5, 6, 7, 8}))
+
+	expected := []pdata.ResourceSpans{
+		pdata.NewResourceSpans(),
+		pdata.NewResourceSpans(),
+	}
+	batch.CopyTo(expected[0])
+	secondBatch.CopyTo(expected[1])
+
+	// test
+	err := st.createOrAppend(context.Background(), traceID.HexString(), secondBatch)
+	require.NoError(t, err)
+
+	// override something in the second span, to make sure we are storing a copy
+	secondSpan.SetName("changed-second-name")
+
+	// verify
+	retrieved, err := st.get(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+	assert.Equal(t, expected, retrieved)
+
+	deleted, err := st.delete(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+	assert.Equal(t, expected, deleted)
+}
+
+func TestDiskTracesSurviveRestart(t *testing.T) {
+	// prepare
+	dir, err := ioutil.TempDir("", "groupbytrace")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	trace := pdata.NewResourceSpans()
+	trace.InstrumentationLibrarySpans().Resize(1)
+	trace.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	trace.InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(traceID)
+
+	st := newDiskStorage(dir)
+	require.NoError(t, st.start())
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), trace))
+	require.NoError(t, st.shutdown())
+
+	// test
+	st = newDiskStorage(dir)
+	require.NoError(t, st.start())
+	defer st.shutdown()
+
+	// verify
+	stored, err := st.keys()
+	require.NoError(t, err)
+	assert.Equal(t, []string{traceID.HexString()}, stored)
+
+	retrieved, err := st.get(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+	assert.Equal(t, []pdata.ResourceSpans{trace}, retrieved)
+}
+
+func TestDiskAppendsKeepTheirOrderAcrossRestarts(t *testing.T) {
+	// prepare
+	dir, err := ioutil.TempDir("", "groupbytrace")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+	var expected []pdata.ResourceSpans
+	appendBatches := func(st *diskStorage, from, to int) {
+		for i := from; i < to; i++ {
+			batch := pdata.NewResourceSpans()
+			batch.InstrumentationLibrarySpans().Resize(1)
+			batch.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+			batch.InstrumentationLibrarySpans().At(0).Spans().At(0).SetName(fmt.Sprintf("span-%d", i))
+			require.NoError(t, st.createOrAppend(context.Background(), key, batch))
+			expected = append(expected, batch)
+		}
+	}
+
+	st := newDiskStorage(dir)
+	require.NoError(t, st.start())
+	appendBatches(st, 0, 20)
+	require.NoError(t, st.shutdown())
+
+	// test
+	st = newDiskStorage(dir)
+	require.NoError(t, st.start())
+	defer st.shutdown()
+	appendBatches(st, 20, 40)
+
+	// verify
+	stored, err := st.keys()
+	require.NoError(t, err)
+	assert.Equal(t, []string{key}, stored)
+
+	retrieved, err := st.get(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, expected, retrieved)
+}
+
+func TestDiskKeysSharingAPrefix(t *testing.T) {
+	// prepare
+	st, cleanup := newStartedDiskStorage(t)
+	defer cleanup()
+
+	keys := []string{"tenant=a", "tenant=a/b", "tenant=a/0000000000000001"}
+	for i, key := range keys {
+		batch := pdata.NewResourceSpans()
+		batch.InstrumentationLibrarySpans().Resize(1)
+		batch.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+		batch.InstrumentationLibrarySpans().At(0).Spans().At(0).SetName(fmt.Sprintf("span-%d", i))
+		require.NoError(t, st.createOrAppend(context.Background(), key, batch))
+	}
+
+	// test
+	deleted, err := st.delete(context.Background(), "tenant=a")
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, "span-0", deleted[0].InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+
+	stored, err := st.keys()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, keys[1:], stored)
+	for i, key := range keys[1:] {
+		retrieved, err := st.get(context.Background(), key)
+		require.NoError(t, err)
+		require.Len(t, retrieved, 1)
+		assert.Equal(t, fmt.Sprintf("span-%d", i+1), retrieved[0].InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+	}
+}
+
+func TestDiskStorageNotStarted(t *testing.T) {
+	// prepare
+	st := newDiskStorage("not-started")
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	// test and verify
+	assert.Equal(t, errDiskStorageNotStarted, st.createOrAppend(context.Background(), traceID.HexString(), pdata.NewResourceSpans()))
+
+	_, err := st.get(context.Background(), traceID.HexString())
+	assert.Equal(t, errDiskStorageNotStarted, err)
+
+	_, err = st.delete(context.Background(), traceID.HexString())
+	assert.Equal(t, errDiskStorageNotStarted, err)
+
+	_, err = st.keys()
+	assert.Equal(t, errDiskStorageNotStarted, err)
+
+	assert.NoError(t, st.shutdown())
+}
+
+func TestDiskRefusesWritesAfterShutdown(t *testing.T) {
+	// prepare
+	dir, err := ioutil.TempDir("", "groupbytrace")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st := newDiskStorage(dir)
+	require.NoError(t, st.start())
+	require.NoError(t, st.shutdown())
+
+	// test
+	err = st.createOrAppend(context.Background(), pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), simpleTraces().ResourceSpans().At(0))
+
+	// verify
+	assert.Equal(t, errStorageStopped, err)
+	assert.NoError(t, st.shutdown())
+
+	reopened := newDiskStorage(dir)
+	require.NoError(t, reopened.start())
+	defer reopened.shutdown()
+	stored, err := reopened.keys()
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestRecoveredTracesAreReleased(t *testing.T) {
+	// prepare
+	dir, err := ioutil.TempDir("", "groupbytrace")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	traces := simpleTraces()
+	traceID := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+
+	previous := newDiskStorage(dir)
+	require.NoError(t, previous.start())
+	require.NoError(t, previous.createOrAppend(context.Background(), traceID.HexString(), traces.ResourceSpans().At(0)))
+	require.NoError(t, previous.shutdown())
+
+	wg := &sync.WaitGroup{}
+	mockProcessor := &mockProcessor{
+		onTraces: func(ctx context.Context, received pdata.Traces) error {
+			assert.Equal(t, traces, received)
+			wg.Done()
+			return nil
+		},
+	}
+
+	config := Config{
+		WaitDuration: time.Millisecond,
+		NumTraces:    10,
+	}
+	p := newGroupByTraceProcessor(logger, newDiskStorage(dir), mockProcessor, config)
+
+	// test
+	wg.Add(1)
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, nil))
+	defer p.Shutdown(ctx)
+
+	// verify
+	wg.Wait()
+}
+
+func newStartedDiskStorage(t *testing.T) (*diskStorage, func()) {
+	dir, err := ioutil.TempDir("", "groupbytrace")
+	require.NoError(t, err)
+
+	st := newDiskStorage(dir)
+	require.NoError(t, st.start())
+
+	return st, func() {
+		st.shutdown()
+		os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// maxPendingMeasurements is how many latencies are buffered for each operation before they are recorded. Recording
+// them in batches keeps the cost of the instrumentation low, even for the in-memory storage.
+const maxPendingMeasurements = 64
+
+// instrumentedStorage wraps another storage, recording the latency and the errors of each operation,
+// tagged with the name of the operation
+type instrumentedStorage struct {
+	st storage
+
+	createOrAppendRecorder *operationRecorder
+	getRecorder            *operationRecorder
+	deleteRecorder         *operationRecorder
+
+	flushInterval time.Duration
+
+	// closing stopCh signals the periodic flush goroutine to stop, which then marks flushWG as done
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	flushWG  sync.WaitGroup
+}
+
+// operationRecorder buffers the latencies of a single operation
+type operationRecorder struct {
+	// the context holding the tag for the operation, created once so that recording is cheap
+	ctx context.Context
+
+	sync.Mutex
+	pending []stats.Measurement
+}
+
+var _ storage = (*instrumentedStorage)(nil)
+var _ recoverableStorage = (*instrumentedStorage)(nil)
+
+func newInstrumentedStorage(st storage) *instrumentedStorage {
+	return &instrumentedStorage{
+		st:                     st,
+		createOrAppendRecorder: newOperationRecorder("createOrAppend"),
+		getRecorder:            newOperationRecorder("get"),
+		deleteRecorder:         newOperationRecorder("delete"),
+		flushInterval:          defaultMetricsFlushInterval,
+		stopCh:                 make(chan struct{}),
+	}
+}
+
+func (st *instrumentedStorage) createOrAppend(ctx context.Context, key string, rs pdata.ResourceSpans) error {
+	start := time.Now()
+	err := st.st.createOrAppend(ctx, key, rs)
+	st.createOrAppendRecorder.record(start, err)
+	return err
+}
+
+func (st *instrumentedStorage) get(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
+	start := time.Now()
+	rss, err := st.st.get(ctx, key)
+	st.getRecorder.record(start, err)
+	return rss, err
+}
+
+func (st *instrumentedStorage) delete(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
+	start := time.Now()
+	rss, err := st.st.delete(ctx, key)
+	st.deleteRecorder.record(start, err)
+	return rss, err
+}
+
+func (st *instrumentedStorage) start() error {
+	if err := st.st.start(); err != nil {
+		return err
+	}
+
+	st.flushWG.Add(1)
+	go st.periodicFlush()
+	return nil
+}
+
+// shutdown records the buffered latencies before shutting down the wrapped storage
+func (st *instrumentedStorage) shutdown() error {
+	st.stopOnce.Do(func() {
+		close(st.stopCh)
+	})
+	st.flushWG.Wait()
+	st.flush()
+	return st.st.shutdown()
+}
+
+// keys returns the keys from the wrapped storage, or none when it isn't able to keep traces across restarts
+func (st *instrumentedStorage) keys() ([]string, error) {
+	rs, ok := st.st.(recoverableStorage)
+	if !ok {
+		return nil, nil
+	}
+	return rs.keys()
+}
+
+// periodicFlush makes sure that the latencies are recorded even when there's little traffic
+func (st *instrumentedStorage) periodicFlush() {
+	defer st.flushWG.Done()
+
+	ticker := time.NewTicker(st.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			st.flush()
+		case <-st.stopCh:
+			return
+		}
+	}
+}
+
+func (st *instrumentedStorage) flush() {
+	st.createOrAppendRecorder.flush()
+	st.getRecorder.flush()
+	st.deleteRecorder.flush()
+}
+
+func newOperationRecorder(operation string) *operationRecorder {
+	// the tag key is valid, so this never fails
+	ctx, _ := tag.New(context.Background(), tag.Upsert(tag.MustNewKey("operation"), operation))
+	return &operationRecorder{
+		ctx:     ctx,
+		pending: make([]stats.Measurement, 0, maxPendingMeasurements),
+	}
+}
+
+// record buffers the latency of an operation started at the given time, recording the whole batch once it's full.
+// Errors are recorded right away, except for the spans refused due to the max spans per trace or due to the shutdown,
+// which have their own metrics.
+func (r *operationRecorder) record(start time.Time, err error) {
+	if err != nil && !errors.Is(err, errMaxSpansPerTraceReached) && !errors.Is(err, errStorageStopped) {
+		stats.Record(r.ctx, mStorageErrors.M(1))
+	}
+
+	latency := float64(time.Since(start)) / float64(time.Millisecond)
+
+	r.Lock()
+	r.pending = append(r.pending, mStorageLatency.M(latency))
+	var batch []stats.Measurement
+	if len(r.pending) >= maxPendingMeasurements {
+		batch = r.pending
+		r.pending = make([]stats.Measurement, 0, maxPendingMeasurements)
+	}
+	r.Unlock()
+
+	if batch != nil {
+		stats.Record(r.ctx, batch...)
+	}
+}
+
+// flush records the buffered latencies
+func (r *operationRecorder) flush() {
+	r.Lock()
+	batch := r.pending
+	r.pending = make([]stats.Measurement, 0, maxPendingMeasurements)
+	r.Unlock()
+
+	if len(batch) > 0 {
+		stats.Record(r.ctx, batch...)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestInstrumentedStorageRecordsLatency(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	st := newInstrumentedStorage(newMemoryStorage())
+	key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+	ctx := context.Background()
+
+	// test
+	require.NoError(t, st.createOrAppend(ctx, key, simpleTraces().ResourceSpans().At(0)))
+	require.NoError(t, st.createOrAppend(ctx, key, simpleTraces().ResourceSpans().At(0)))
+	_, err := st.get(ctx, key)
+	require.NoError(t, err)
+	_, err = st.delete(ctx, key)
+	require.NoError(t, err)
+
+	// the latencies are buffered until then
+	st.flush()
+
+	// verify
+	assert.Equal(t, map[string]int64{"createOrAppend": 2, "get": 1, "delete": 1}, storageLatencyCounts(t))
+
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mStorageErrors.Name())
+	require.NoError(t, err)
+	assert.Len(t, viewData, 0)
+}
+
+func TestInstrumentedStorageRecordsErrors(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	expectedErr := errors.New("some unexpected error")
+	st := newInstrumentedStorage(&mockStorage{
+		onCreateOrAppend: func(context.Context, string, pdata.ResourceSpans) error {
+			return expectedErr
+		},
+		onGet: func(context.Context, string) ([]pdata.ResourceSpans, error) {
+			return nil, expectedErr
+		},
+	})
+	ctx := context.Background()
+
+	// test
+	err := st.createOrAppend(ctx, "some-key", simpleTraces().ResourceSpans().At(0))
+	assert.True(t, errors.Is(err, expectedErr))
+	_, err = st.get(ctx, "some-key")
+	assert.True(t, errors.Is(err, expectedErr))
+
+	// verify
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mStorageErrors.Name())
+	require.NoError(t, err)
+
+	errorsPerOperation := map[string]int64{}
+	for _, row := range viewData {
+		require.Len(t, row.Tags, 1)
+		errorsPerOperation[row.Tags[0].Value] = int64(row.Data.(*view.SumData).Value)
+	}
+	assert.Equal(t, map[string]int64{"createOrAppend": 1, "get": 1}, errorsPerOperation)
+}
+
+func TestInstrumentedStorageKeys(t *testing.T) {
+	// prepare
+	st := newInstrumentedStorage(&mockStorage{})
+
+	// test
+	keys, err := st.keys()
+
+	// verify
+	assert.NoError(t, err)
+	assert.Nil(t, keys)
+}
+
+func BenchmarkInstrumentedStorageCreateOrAppend(b *testing.B) {
+	views := MetricViews()
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	for _, tt := range []struct {
+		casename string
+		st       func() storage
+	}{
+		{
+			casename: "noop",
+			st:       func() storage { return &mockStorage{} },
+		},
+		{
+			casename: "instrumented noop",
+			st:       func() storage { return newInstrumentedStorage(&mockStorage{}) },
+		},
+		{
+			casename: "memory",
+			st:       func() storage { return newMemoryStorage() },
+		},
+		{
+			casename: "instrumented memory",
+			st:       func() storage { return newInstrumentedStorage(newMemoryStorage()) },
+		},
+	} {
+		b.Run(tt.casename, func(b *testing.B) {
+			st := tt.st()
+			rs := simpleTraces().ResourceSpans().At(0)
+			key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				_ = st.createOrAppend(ctx, key, rs)
+				if n%100 == 0 {
+					// keep the trace from growing indefinitely
+					_, _ = st.delete(ctx, key)
+				}
+			}
+		})
+	}
+}
+
+// storageLatencyCounts returns the number of recorded latencies for each storage operation
+func storageLatencyCounts(t *testing.T) map[string]int64 {
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mStorageLatency.Name())
+	require.NoError(t, err)
+
+	result := map[string]int64{}
+	for _, row := range viewData {
+		require.Len(t, row.Tags, 1)
+		result[row.Tags[0].Value] = row.Data.(*view.DistributionData).Count
+	}
+	return result
+}
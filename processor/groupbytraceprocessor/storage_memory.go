@@ -15,7 +15,9 @@
 package groupbytraceprocessor
 
 import (
+	"container/list"
 	"context"
+	"hash/fnv"
 	"sync"
 	"time"
 
@@ -23,46 +25,175 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
+// memoryStorage keeps the traces in memory, distributed across a number of shards based on the hash of the
+// key. Each shard has its own lock, so that operations on traces living in different shards don't block
+// each other.
 type memoryStorage struct {
-	sync.RWMutex
-	content                   map[string][]pdata.ResourceSpans
-	stopped                   bool
-	stoppedLock               sync.RWMutex
+	shards                    []*memoryShard
 	metricsCollectionInterval time.Duration
+
+	// closing stopCh signals the periodic metrics goroutine to stop, which then marks metricsWG as done
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	metricsWG sync.WaitGroup
+
+	// maxBytes is the maximum approximate size of the traces in the storage. Zero means no limit.
+	// Each shard enforces an equal part of this limit.
+	maxBytes int
+
+	// onEvicted is called with the traces that had to be evicted to honor maxBytes
+	onEvicted func(string, []pdata.ResourceSpans)
+
+	// dedupSpans tells whether the spans with an ID already seen for the trace should be dropped
+	dedupSpans bool
+
+	// maxSpansPerTrace is the number of spans a trace can reach before the new spans for it are refused. Zero means
+	// no limit.
+	maxSpansPerTrace int
+}
+
+// memoryShard holds a subset of the traces of the memory storage
+type memoryShard struct {
+	sync.RWMutex
+	content map[string][]pdata.ResourceSpans
+
+	// the approximate size of each trace, and of the whole shard
+	sizes     map[string]int
+	totalSize int
+
+	// the number of spans in each trace, and in the whole shard
+	numSpans      map[string]int
+	totalNumSpans int
+
+	// the keys, from the oldest to the newest, along with each trace's element in the list
+	order         *list.List
+	orderElements map[string]*list.Element
+
+	// the IDs of the spans stored for each trace, only kept when deduplicating spans
+	spanIDs map[string]map[pdata.SpanID]struct{}
+
+	// the traces that reached the max spans per trace, and were marked as truncated
+	truncated map[string]struct{}
 }
 
 var _ storage = (*memoryStorage)(nil)
+var _ evictingStorage = (*memoryStorage)(nil)
 
 func newMemoryStorage() *memoryStorage {
+	return newShardedMemoryStorage(defaultNumShards)
+}
+
+func newShardedMemoryStorage(numShards int) *memoryStorage {
+	if numShards < 1 {
+		numShards = 1
+	}
+
+	shards := make([]*memoryShard, numShards)
+	for i := range shards {
+		shards[i] = &memoryShard{
+			content:       make(map[string][]pdata.ResourceSpans),
+			sizes:         make(map[string]int),
+			numSpans:      make(map[string]int),
+			order:         list.New(),
+			orderElements: make(map[string]*list.Element),
+			spanIDs:       make(map[string]map[pdata.SpanID]struct{}),
+			truncated:     make(map[string]struct{}),
+		}
+	}
+
 	return &memoryStorage{
-		content:                   make(map[string][]pdata.ResourceSpans),
-		metricsCollectionInterval: time.Second,
+		shards:                    shards,
+		metricsCollectionInterval: defaultMetricsFlushInterval,
+		stopCh:                    make(chan struct{}),
 	}
 }
 
-func (st *memoryStorage) createOrAppend(traceID pdata.TraceID, rs pdata.ResourceSpans) error {
-	sTraceID := traceID.HexString()
+func (st *memoryStorage) setEvictionCallback(onEvicted func(string, []pdata.ResourceSpans)) {
+	st.onEvicted = onEvicted
+}
+
+func (st *memoryStorage) createOrAppend(ctx context.Context, key string, rs pdata.ResourceSpans) error {
+	// give up before waiting for the lock
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case <-st.stopCh:
+		// the processor is done with this storage, and wouldn't release these spans anymore
+		return errStorageStopped
+	default:
+	}
+
+	// the storage owns the given resource spans from now on, so there's no need to copy them
+	size := resourceSpansSize(rs)
+	numSpans := resourceSpansSpanCount(rs)
+
+	shard := st.shardFor(key)
+	shard.Lock()
 
-	st.Lock()
-	defer st.Unlock()
+	if st.maxSpansPerTrace > 0 && shard.numSpans[key] >= st.maxSpansPerTrace {
+		shard.markTruncated(key)
+		shard.Unlock()
+		stats.Record(context.Background(), mSpansTruncated.M(int64(numSpans)))
+		return errMaxSpansPerTraceReached
+	}
 
-	if _, ok := st.content[sTraceID]; !ok {
-		st.content[sTraceID] = []pdata.ResourceSpans{}
+	duplicates := 0
+	if st.dedupSpans {
+		duplicates = shard.dropDuplicates(key, rs)
+		if duplicates > 0 && duplicates == numSpans {
+			// nothing new for this trace
+			shard.Unlock()
+			stats.Record(context.Background(), mDuplicateSpansDropped.M(int64(duplicates)))
+			return nil
+		}
+		if duplicates > 0 {
+			size = resourceSpansSize(rs)
+			numSpans -= duplicates
+		}
 	}
 
-	newRS := pdata.NewResourceSpans()
-	rs.CopyTo(newRS)
-	st.content[sTraceID] = append(st.content[sTraceID], newRS)
+	if _, ok := shard.content[key]; !ok {
+		shard.content[key] = []pdata.ResourceSpans{}
+		shard.orderElements[key] = shard.order.PushBack(key)
+	}
+
+	shard.content[key] = append(shard.content[key], rs)
+	shard.sizes[key] += size
+	shard.totalSize += size
+	shard.numSpans[key] += numSpans
+	shard.totalNumSpans += numSpans
+
+	evicted := shard.evictOverLimit(st.maxBytesPerShard())
+	shard.Unlock()
+
+	if duplicates > 0 {
+		stats.Record(context.Background(), mDuplicateSpansDropped.M(int64(duplicates)))
+	}
+
+	// the callback is called outside of the lock, as it's free to use the storage
+	for _, e := range evicted {
+		stats.Record(context.Background(), mEvictedTraces.M(1), mEvictedBytes.M(int64(e.size)))
+		if st.onEvicted != nil {
+			st.onEvicted(e.key, e.rss)
+		}
+	}
 
 	return nil
 }
-func (st *memoryStorage) get(traceID pdata.TraceID) ([]pdata.ResourceSpans, error) {
-	sTraceID := traceID.HexString()
 
-	st.RLock()
-	defer st.RUnlock()
+func (st *memoryStorage) get(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
+	// give up before waiting for the lock
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	shard := st.shardFor(key)
+	shard.RLock()
+	defer shard.RUnlock()
 
-	rss, ok := st.content[sTraceID]
+	rss, ok := shard.content[key]
 	if !ok {
 		return nil, nil
 	}
@@ -77,56 +208,234 @@ func (st *memoryStorage) get(traceID pdata.TraceID) ([]pdata.ResourceSpans, erro
 	return result, nil
 }
 
-// delete will return a reference to a ResourceSpans. Changes to the returned object may not be applied
-// to the version in the storage.
-func (st *memoryStorage) delete(traceID pdata.TraceID) ([]pdata.ResourceSpans, error) {
-	sTraceID := traceID.HexString()
+// delete will return the resource spans that were stored for the given trace, without copying them, as they
+// aren't referenced by the storage anymore.
+func (st *memoryStorage) delete(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
+	// give up before waiting for the lock
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	st.Lock()
-	defer st.Unlock()
+	shard := st.shardFor(key)
+	shard.Lock()
+	defer shard.Unlock()
 
-	rss := st.content[sTraceID]
-	var result []pdata.ResourceSpans
-	for _, rs := range rss {
-		newRS := pdata.NewResourceSpans()
-		rs.CopyTo(newRS)
-		result = append(result, newRS)
-	}
-	delete(st.content, sTraceID)
+	rss := shard.content[key]
+	shard.remove(key)
 
-	return result, nil
+	return rss, nil
 }
 
 func (st *memoryStorage) start() error {
+	st.metricsWG.Add(1)
 	go st.periodicMetrics()
 	return nil
 }
 
+// shutdown stops the periodic metrics, returning only after the metrics goroutine has finished
 func (st *memoryStorage) shutdown() error {
-	st.stoppedLock.Lock()
-	defer st.stoppedLock.Unlock()
-	st.stopped = true
+	st.stopOnce.Do(func() {
+		close(st.stopCh)
+	})
+	st.metricsWG.Wait()
 	return nil
 }
 
 func (st *memoryStorage) periodicMetrics() {
-	numTraces := st.count()
-	stats.Record(context.Background(), mNumTracesInMemory.M(int64(numTraces)))
+	defer st.metricsWG.Done()
 
-	st.stoppedLock.RLock()
-	stopped := st.stopped
-	st.stoppedLock.RUnlock()
-	if stopped {
-		return
+	ticker := time.NewTicker(st.metricsCollectionInterval)
+	defer ticker.Stop()
+
+	st.recordMetrics()
+	for {
+		select {
+		case <-ticker.C:
+			st.recordMetrics()
+		case <-st.stopCh:
+			// this storage isn't holding traces for the processor anymore
+			stats.Record(context.Background(), mNumTracesInMemory.M(0), mBytesInMemory.M(0), mSpansInMemory.M(0))
+			return
+		}
 	}
+}
 
-	time.AfterFunc(st.metricsCollectionInterval, func() {
-		st.periodicMetrics()
-	})
+func (st *memoryStorage) recordMetrics() {
+	stats.Record(context.Background(),
+		mNumTracesInMemory.M(int64(st.count())),
+		mBytesInMemory.M(int64(st.bytes())),
+		mSpansInMemory.M(int64(st.spans())))
 }
 
 func (st *memoryStorage) count() int {
-	st.RLock()
-	defer st.RUnlock()
-	return len(st.content)
+	count := 0
+	for _, shard := range st.shards {
+		shard.RLock()
+		count += len(shard.content)
+		shard.RUnlock()
+	}
+	return count
+}
+
+func (st *memoryStorage) bytes() int {
+	bytes := 0
+	for _, shard := range st.shards {
+		shard.RLock()
+		bytes += shard.totalSize
+		shard.RUnlock()
+	}
+	return bytes
+}
+
+func (st *memoryStorage) spans() int {
+	spans := 0
+	for _, shard := range st.shards {
+		shard.RLock()
+		spans += shard.totalNumSpans
+		shard.RUnlock()
+	}
+	return spans
+}
+
+func (st *memoryStorage) shardFor(key string) *memoryShard {
+	if len(st.shards) == 1 {
+		return st.shards[0]
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	// the lower bits of FNV are poorly distributed, so we mix the higher bits in
+	sum := h.Sum32()
+	sum ^= sum >> 16
+	return st.shards[sum%uint32(len(st.shards))]
+}
+
+func (st *memoryStorage) maxBytesPerShard() int {
+	if st.maxBytes <= 0 {
+		return 0
+	}
+	// round up, so that a small limit doesn't end up being zero (no limit) for each shard
+	return (st.maxBytes + len(st.shards) - 1) / len(st.shards)
+}
+
+type evictedTrace struct {
+	key  string
+	rss  []pdata.ResourceSpans
+	size int
+}
+
+// evictOverLimit removes the oldest traces until the shard is within the given max bytes, returning
+// the evicted traces. The caller should hold the write lock.
+func (shard *memoryShard) evictOverLimit(maxBytes int) []evictedTrace {
+	if maxBytes <= 0 {
+		return nil
+	}
+
+	var result []evictedTrace
+	for shard.totalSize > maxBytes && shard.order.Len() > 0 {
+		key := shard.order.Front().Value.(string)
+
+		result = append(result, evictedTrace{
+			key:  key,
+			rss:  shard.content[key],
+			size: shard.sizes[key],
+		})
+		shard.remove(key)
+	}
+
+	return result
+}
+
+// remove erases all the information about the given trace. The caller should hold the write lock.
+func (shard *memoryShard) remove(key string) {
+	if el, ok := shard.orderElements[key]; ok {
+		shard.order.Remove(el)
+	}
+	shard.totalSize -= shard.sizes[key]
+	shard.totalNumSpans -= shard.numSpans[key]
+
+	delete(shard.orderElements, key)
+	delete(shard.sizes, key)
+	delete(shard.numSpans, key)
+	delete(shard.content, key)
+	delete(shard.spanIDs, key)
+	delete(shard.truncated, key)
+}
+
+// markTruncated sets the truncated attribute on the resources of the given trace, once. The caller should hold the
+// write lock.
+func (shard *memoryShard) markTruncated(key string) {
+	if _, ok := shard.truncated[key]; ok {
+		return
+	}
+	shard.truncated[key] = struct{}{}
+
+	for _, rs := range shard.content[key] {
+		rs.Resource().Attributes().UpsertBool(truncatedAttribute, true)
+	}
+}
+
+// dropDuplicates removes from the given resource spans the spans already stored for the trace, or repeated within
+// the resource spans themselves, returning the number of spans removed. Spans without an ID are always kept.
+// The caller should hold the write lock.
+func (shard *memoryShard) dropDuplicates(key string, rs pdata.ResourceSpans) int {
+	seen, ok := shard.spanIDs[key]
+	if !ok {
+		seen = make(map[pdata.SpanID]struct{})
+		shard.spanIDs[key] = seen
+	}
+
+	dropped := 0
+	for i := 0; i < rs.InstrumentationLibrarySpans().Len(); i++ {
+		spans := rs.InstrumentationLibrarySpans().At(i).Spans()
+
+		// the kept spans are only collected once the first duplicate is found
+		var kept pdata.SpanSlice
+		hasDuplicates := false
+		for j := 0; j < spans.Len(); j++ {
+			span := spans.At(j)
+			spanID := span.SpanID()
+
+			if _, duplicate := seen[spanID]; spanID.IsEmpty() || !duplicate {
+				seen[spanID] = struct{}{}
+				if hasDuplicates {
+					kept.Append(span)
+				}
+				continue
+			}
+
+			if !hasDuplicates {
+				hasDuplicates = true
+				kept = pdata.NewSpanSlice()
+				for k := 0; k < j; k++ {
+					kept.Append(spans.At(k))
+				}
+			}
+			dropped++
+		}
+
+		if hasDuplicates {
+			spans.Resize(0)
+			kept.MoveAndAppendTo(spans)
+		}
+	}
+
+	return dropped
+}
+
+// resourceSpansSize returns the approximate size of the given resource spans, based on its serialized form
+func resourceSpansSize(rs pdata.ResourceSpans) int {
+	trace := pdata.NewTraces()
+	trace.ResourceSpans().Append(rs)
+	return trace.Size()
+}
+
+// resourceSpansSpanCount returns the number of spans in the given resource spans
+func resourceSpansSpanCount(rs pdata.ResourceSpans) int {
+	count := 0
+	for i := 0; i < rs.InstrumentationLibrarySpans().Len(); i++ {
+		count += rs.InstrumentationLibrarySpans().At(i).Spans().Len()
+	}
+	return count
 }
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// memoryLogsStorage keeps the log records grouped by their trace ID in memory, until they are released
+type memoryLogsStorage struct {
+	sync.RWMutex
+	content map[string][]pdata.ResourceLogs
+
+	metricsCollectionInterval time.Duration
+
+	// closing stopCh signals the periodic metrics goroutine to stop, which then marks metricsWG as done
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	metricsWG sync.WaitGroup
+}
+
+func newMemoryLogsStorage() *memoryLogsStorage {
+	return &memoryLogsStorage{
+		content:                   make(map[string][]pdata.ResourceLogs),
+		metricsCollectionInterval: defaultMetricsFlushInterval,
+		stopCh:                    make(chan struct{}),
+	}
+}
+
+// createOrAppend adds the given resource logs to the log group with the given key, creating the group when needed.
+// The storage takes the ownership of the given resource logs, which shouldn't be changed by the caller afterwards.
+func (st *memoryLogsStorage) createOrAppend(key string, rl pdata.ResourceLogs) {
+	st.Lock()
+	defer st.Unlock()
+
+	st.content[key] = append(st.content[key], rl)
+}
+
+// delete removes the log group with the given key, returning its resource logs, or nil when it can't be found.
+// The ownership of the returned resource logs is transferred to the caller.
+func (st *memoryLogsStorage) delete(key string) []pdata.ResourceLogs {
+	st.Lock()
+	defer st.Unlock()
+
+	rls := st.content[key]
+	delete(st.content, key)
+	return rls
+}
+
+func (st *memoryLogsStorage) count() int {
+	st.RLock()
+	defer st.RUnlock()
+	return len(st.content)
+}
+
+func (st *memoryLogsStorage) start() {
+	st.metricsWG.Add(1)
+	go st.periodicMetrics()
+}
+
+// shutdown stops the periodic metrics, returning only after the metrics goroutine has finished
+func (st *memoryLogsStorage) shutdown() {
+	st.stopOnce.Do(func() {
+		close(st.stopCh)
+	})
+	st.metricsWG.Wait()
+}
+
+func (st *memoryLogsStorage) periodicMetrics() {
+	defer st.metricsWG.Done()
+
+	ticker := time.NewTicker(st.metricsCollectionInterval)
+	defer ticker.Stop()
+
+	stats.Record(context.Background(), mNumLogGroupsInMemory.M(int64(st.count())))
+	for {
+		select {
+		case <-ticker.C:
+			stats.Record(context.Background(), mNumLogGroupsInMemory.M(int64(st.count())))
+		case <-st.stopCh:
+			// this storage isn't holding log groups for the processor anymore
+			stats.Record(context.Background(), mNumLogGroupsInMemory.M(0))
+			return
+		}
+	}
+}
@@ -15,11 +15,17 @@
 package groupbytraceprocessor
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/goleak"
 )
 
 func TestMemoryCreateAndGetTrace(t *testing.T) {
@@ -40,7 +46,7 @@ func TestMemoryCreateAndGetTrace(t *testing.T) {
 	// test
 	for _, traceID := range traceIDs {
 		span.SetTraceID(traceID)
-		st.createOrAppend(traceID, baseTrace)
+		st.createOrAppend(context.Background(), traceID.HexString(), baseTrace)
 	}
 
 	// verify
@@ -49,8 +55,8 @@ func TestMemoryCreateAndGetTrace(t *testing.T) {
 		expected := []pdata.ResourceSpans{baseTrace}
 		expected[0].InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(traceID)
 
-		retrieved, err := st.get(traceID)
-		st.createOrAppend(traceID, expected[0])
+		retrieved, err := st.get(context.Background(), traceID.HexString())
+		st.createOrAppend(context.Background(), traceID.HexString(), expected[0])
 
 		require.NoError(t, err)
 		assert.Equal(t, expected, retrieved)
@@ -70,16 +76,16 @@ func TestMemoryDeleteTrace(t *testing.T) {
 	span := ils.Spans().At(0)
 	span.SetTraceID(traceID)
 
-	st.createOrAppend(traceID, trace)
+	st.createOrAppend(context.Background(), traceID.HexString(), trace)
 
 	// test
-	deleted, err := st.delete(traceID)
+	deleted, err := st.delete(context.Background(), traceID.HexString())
 
 	// verify
 	require.NoError(t, err)
 	assert.Equal(t, []pdata.ResourceSpans{trace}, deleted)
 
-	retrieved, err := st.get(traceID)
+	retrieved, err := st.get(context.Background(), traceID.HexString())
 	require.NoError(t, err)
 	assert.Nil(t, retrieved)
 }
@@ -98,7 +104,7 @@ func TestMemoryAppendSpans(t *testing.T) {
 	span.SetTraceID(traceID)
 	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4}))
 
-	st.createOrAppend(traceID, batch)
+	st.createOrAppend(context.Background(), traceID.HexString(), batch)
 
 	secondBatch := pdata.NewResourceSpans()
 	secondBatch.InstrumentationLibrarySpans().Resize(1)
@@ -117,24 +123,16 @@ func TestMemoryAppendSpans(t *testing.T) {
 	expected[1].InstrumentationLibrarySpans().Append(secondIls)
 
 	// test
-	err := st.createOrAppend(traceID, secondBatch)
+	err := st.createOrAppend(context.Background(), traceID.HexString(), secondBatch)
 	require.NoError(t, err)
 
-	// override something in the second span, to make sure we are storing a copy
-	secondSpan.SetName("changed-second-name")
-
 	// verify
-	retrieved, err := st.get(traceID)
+	retrieved, err := st.get(context.Background(), traceID.HexString())
 	require.NoError(t, err)
-	assert.Equal(t, "second-name", retrieved[1].InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
-
-	// now that we checked that the secondSpan change here didn't have an effect, revert
-	// so that we can compare the that everything else has the same value
-	secondSpan.SetName("second-name")
 	assert.Equal(t, expected, retrieved)
 }
 
-func TestMemoryTraceIsBeingCloned(t *testing.T) {
+func TestMemoryGetReturnsCopy(t *testing.T) {
 	// prepare
 	st := newMemoryStorage()
 	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
@@ -148,13 +146,509 @@ func TestMemoryTraceIsBeingCloned(t *testing.T) {
 	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4}))
 	span.SetName("should-not-be-changed")
 
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), batch))
+
 	// test
-	err := st.createOrAppend(traceID, batch)
+	retrieved, err := st.get(context.Background(), traceID.HexString())
 	require.NoError(t, err)
-	span.SetName("changed-trace")
+	retrieved[0].InstrumentationLibrarySpans().At(0).Spans().At(0).SetName("changed-trace")
 
 	// verify
-	retrieved, err := st.get(traceID)
+	retrieved, err = st.get(context.Background(), traceID.HexString())
 	require.NoError(t, err)
 	assert.Equal(t, "should-not-be-changed", retrieved[0].InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
 }
+
+func TestMemoryDeleteHandsOverStoredTrace(t *testing.T) {
+	// prepare
+	st := newMemoryStorage()
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	ils := batch.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	ils.Spans().At(0).SetTraceID(traceID)
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), batch))
+
+	// test
+	deleted, err := st.delete(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+
+	// verify
+	// the stored object is returned as it is, instead of a copy
+	require.Len(t, deleted, 1)
+	deleted[0].InstrumentationLibrarySpans().At(0).Spans().At(0).SetName("changed-by-the-new-owner")
+	assert.Equal(t, "changed-by-the-new-owner", batch.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+}
+
+func TestMemoryCanceledContext(t *testing.T) {
+	// prepare
+	st := newMemoryStorage()
+	key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+	require.NoError(t, st.createOrAppend(context.Background(), key, pdata.NewResourceSpans()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// test and verify
+	assert.Equal(t, context.Canceled, st.createOrAppend(ctx, key, pdata.NewResourceSpans()))
+
+	_, err := st.get(ctx, key)
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = st.delete(ctx, key)
+	assert.Equal(t, context.Canceled, err)
+
+	// the trace is left untouched
+	retrieved, err := st.get(context.Background(), key)
+	require.NoError(t, err)
+	assert.Len(t, retrieved, 1)
+}
+
+func TestMemoryMaxBytesEvictsOldestTraces(t *testing.T) {
+	// prepare
+	// a single shard, so that the limit applies to all traces at once
+	st := newShardedMemoryStorage(1)
+
+	var evicted []string
+	st.setEvictionCallback(func(key string, rss []pdata.ResourceSpans) {
+		require.Len(t, rss, 1)
+		assert.Equal(t, key, rss[0].InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID().HexString())
+		evicted = append(evicted, key)
+	})
+
+	traceIDs := []pdata.TraceID{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		pdata.NewTraceID([16]byte{2, 3, 4, 5}),
+		pdata.NewTraceID([16]byte{3, 4, 5, 6}),
+	}
+
+	newBatch := func(traceID pdata.TraceID) pdata.ResourceSpans {
+		batch := pdata.NewResourceSpans()
+		batch.InstrumentationLibrarySpans().Resize(1)
+		span := batch.InstrumentationLibrarySpans().At(0).Spans()
+		span.Resize(1)
+		span.At(0).SetTraceID(traceID)
+		return batch
+	}
+	size := resourceSpansSize(newBatch(traceIDs[0]))
+
+	// room for two traces
+	st.maxBytes = 2*size + 1
+
+	// test
+	for _, traceID := range traceIDs {
+		require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), newBatch(traceID)))
+	}
+
+	// verify
+	assert.Equal(t, []string{traceIDs[0].HexString()}, evicted)
+	assert.Equal(t, 2, st.count())
+	assert.Equal(t, 2*size, st.bytes())
+
+	retrieved, err := st.get(context.Background(), traceIDs[0].HexString())
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+
+	_, err = st.delete(context.Background(), traceIDs[1].HexString())
+	require.NoError(t, err)
+	assert.Equal(t, size, st.bytes())
+}
+
+func TestMemoryNoMaxBytes(t *testing.T) {
+	// prepare
+	st := newMemoryStorage()
+	st.setEvictionCallback(func(string, []pdata.ResourceSpans) {
+		assert.Fail(t, "no traces should have been evicted")
+	})
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+
+	// test
+	for i := byte(1); i <= 10; i++ {
+		traceID := pdata.NewTraceID([16]byte{i})
+		require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), batch))
+	}
+
+	// verify
+	assert.Equal(t, 10, st.count())
+	assert.Equal(t, 10*resourceSpansSize(batch), st.bytes())
+}
+
+func TestMemoryShardsAreUsed(t *testing.T) {
+	// prepare
+	st := newShardedMemoryStorage(4)
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+
+	// test
+	for i := byte(1); i <= 100; i++ {
+		traceID := pdata.NewTraceID([16]byte{i, i, i, i})
+		require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), batch))
+	}
+
+	// verify
+	assert.Equal(t, 100, st.count())
+	for _, shard := range st.shards {
+		assert.NotEmpty(t, shard.content)
+	}
+
+	for i := byte(1); i <= 100; i++ {
+		traceID := pdata.NewTraceID([16]byte{i, i, i, i})
+		deleted, err := st.delete(context.Background(), traceID.HexString())
+		require.NoError(t, err)
+		assert.Len(t, deleted, 1)
+	}
+	assert.Equal(t, 0, st.count())
+	assert.Equal(t, 0, st.bytes())
+}
+
+func TestMemoryInvalidNumberOfShards(t *testing.T) {
+	// test
+	st := newShardedMemoryStorage(0)
+
+	// verify
+	assert.Len(t, st.shards, 1)
+}
+
+func TestMemoryDedupSpans(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	st := newShardedMemoryStorage(1)
+	st.dedupSpans = true
+	key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+
+	newBatch := func(spanIDs ...byte) pdata.ResourceSpans {
+		batch := pdata.NewResourceSpans()
+		batch.InstrumentationLibrarySpans().Resize(1)
+		spans := batch.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(len(spanIDs))
+		for i, spanID := range spanIDs {
+			if spanID != 0 {
+				spans.At(i).SetSpanID(pdata.NewSpanID([8]byte{spanID}))
+			}
+		}
+		return batch
+	}
+
+	// test
+	require.NoError(t, st.createOrAppend(context.Background(), key, newBatch(1, 2, 2)))
+	require.NoError(t, st.createOrAppend(context.Background(), key, newBatch(2, 3, 1)))
+	// nothing new here
+	require.NoError(t, st.createOrAppend(context.Background(), key, newBatch(3)))
+	// the spans without an ID can't be told apart
+	require.NoError(t, st.createOrAppend(context.Background(), key, newBatch(0, 0)))
+
+	// verify
+	assert.Equal(t, 5, st.spans())
+
+	retrieved, err := st.delete(context.Background(), key)
+	require.NoError(t, err)
+	var spanIDs []pdata.SpanID
+	for _, rs := range retrieved {
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		for i := 0; i < spans.Len(); i++ {
+			spanIDs = append(spanIDs, spans.At(i).SpanID())
+		}
+	}
+	assert.Equal(t, []pdata.SpanID{
+		pdata.NewSpanID([8]byte{1}),
+		pdata.NewSpanID([8]byte{2}),
+		pdata.NewSpanID([8]byte{3}),
+		pdata.NewSpanID([8]byte{}),
+		pdata.NewSpanID([8]byte{}),
+	}, spanIDs)
+	assert.Equal(t, 0, st.spans())
+
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mDuplicateSpansDropped.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 4, viewData[0].Data.(*view.SumData).Value)
+}
+
+func TestMemoryDedupStateIsRemovedWithTrace(t *testing.T) {
+	// prepare
+	st := newShardedMemoryStorage(1)
+	st.dedupSpans = true
+	key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().At(0).SetSpanID(pdata.NewSpanID([8]byte{1}))
+	require.NoError(t, st.createOrAppend(context.Background(), key, batch))
+
+	// test
+	_, err := st.delete(context.Background(), key)
+	require.NoError(t, err)
+
+	// verify
+	assert.Len(t, st.shards[0].spanIDs, 0)
+
+	// the same span is accepted again for a new trace with the same key
+	again := pdata.NewResourceSpans()
+	batch.CopyTo(again)
+	require.NoError(t, st.createOrAppend(context.Background(), key, again))
+	assert.Equal(t, 1, st.spans())
+}
+
+func TestMemoryNoDedupByDefault(t *testing.T) {
+	// prepare
+	st := newShardedMemoryStorage(1)
+	key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(2)
+	batch.InstrumentationLibrarySpans().At(0).Spans().At(0).SetSpanID(pdata.NewSpanID([8]byte{1}))
+	batch.InstrumentationLibrarySpans().At(0).Spans().At(1).SetSpanID(pdata.NewSpanID([8]byte{1}))
+
+	// test
+	require.NoError(t, st.createOrAppend(context.Background(), key, batch))
+
+	// verify
+	assert.Equal(t, 2, st.spans())
+	assert.Len(t, st.shards[0].spanIDs, 0)
+}
+
+func TestMemoryMaxSpansPerTrace(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	st := newShardedMemoryStorage(1)
+	st.maxSpansPerTrace = 3
+	key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+
+	newBatch := func(numSpans int) pdata.ResourceSpans {
+		batch := pdata.NewResourceSpans()
+		batch.InstrumentationLibrarySpans().Resize(1)
+		batch.InstrumentationLibrarySpans().At(0).Spans().Resize(numSpans)
+		return batch
+	}
+
+	// test
+	require.NoError(t, st.createOrAppend(context.Background(), key, newBatch(2)))
+	// the limit is checked before the batch is added, so this one is still accepted
+	require.NoError(t, st.createOrAppend(context.Background(), key, newBatch(2)))
+	err := st.createOrAppend(context.Background(), key, newBatch(2))
+	assert.Equal(t, errMaxSpansPerTraceReached, err)
+	err = st.createOrAppend(context.Background(), key, newBatch(1))
+	assert.Equal(t, errMaxSpansPerTraceReached, err)
+
+	// verify
+	assert.Equal(t, 4, st.spans())
+
+	rss, err := st.delete(context.Background(), key)
+	require.NoError(t, err)
+	require.Len(t, rss, 2)
+	for _, rs := range rss {
+		truncated, ok := rs.Resource().Attributes().Get(truncatedAttribute)
+		require.True(t, ok)
+		assert.True(t, truncated.BoolVal())
+	}
+	assert.Len(t, st.shards[0].truncated, 0)
+
+	viewData, err := view.RetrieveData("processor/groupbytrace/" + mSpansTruncated.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.EqualValues(t, 3, viewData[0].Data.(*view.SumData).Value)
+
+	// a new trace with the same key starts from scratch
+	require.NoError(t, st.createOrAppend(context.Background(), key, newBatch(2)))
+	rss, err = st.get(context.Background(), key)
+	require.NoError(t, err)
+	require.Len(t, rss, 1)
+	_, ok := rss[0].Resource().Attributes().Get(truncatedAttribute)
+	assert.False(t, ok)
+}
+
+func TestMemorySpansInMemory(t *testing.T) {
+	// prepare
+	st := newShardedMemoryStorage(1)
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(2)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(2)
+	batch.InstrumentationLibrarySpans().At(1).Spans().Resize(1)
+
+	first := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+	second := pdata.NewTraceID([16]byte{2, 3, 4, 5}).HexString()
+
+	// test
+	require.NoError(t, st.createOrAppend(context.Background(), first, batch))
+	require.NoError(t, st.createOrAppend(context.Background(), first, batch))
+	require.NoError(t, st.createOrAppend(context.Background(), second, batch))
+
+	// verify
+	assert.Equal(t, 9, st.spans())
+
+	_, err := st.delete(context.Background(), first)
+	require.NoError(t, err)
+	assert.Equal(t, 3, st.spans())
+
+	// evicted traces don't count anymore
+	st.maxBytes = 1
+	require.NoError(t, st.createOrAppend(context.Background(), first, batch))
+	assert.Equal(t, 0, st.spans())
+}
+
+func TestMemorySpansInMemoryConcurrentAppendAndDelete(t *testing.T) {
+	// prepare
+	st := newShardedMemoryStorage(4)
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(2)
+
+	// test
+	wg := &sync.WaitGroup{}
+	for writer := byte(0); writer < 10; writer++ {
+		wg.Add(1)
+		go func(writer byte) {
+			defer wg.Done()
+			for i := byte(0); i < 100; i++ {
+				key := pdata.NewTraceID([16]byte{writer, i}).HexString()
+				assert.NoError(t, st.createOrAppend(context.Background(), key, batch))
+				assert.NoError(t, st.createOrAppend(context.Background(), key, batch))
+
+				// every other trace is deleted, while the other writers keep appending
+				if i%2 == 0 {
+					_, err := st.delete(context.Background(), key)
+					assert.NoError(t, err)
+				}
+			}
+		}(writer)
+	}
+	wg.Wait()
+
+	// verify
+	// each writer keeps 50 traces with 4 spans each
+	assert.Equal(t, 10*50*4, st.spans())
+	assert.Equal(t, 10*50, st.count())
+}
+
+func BenchmarkMemoryCreateOrAppendAndDelete(b *testing.B) {
+	st := newMemoryStorage()
+	key := pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString()
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = st.createOrAppend(context.Background(), key, batch)
+		_, _ = st.delete(context.Background(), key)
+	}
+}
+
+func BenchmarkMemoryConcurrentCreateOrAppendSingleShard(b *testing.B) {
+	benchmarkMemoryConcurrentCreateOrAppend(b, newShardedMemoryStorage(1))
+}
+
+func BenchmarkMemoryConcurrentCreateOrAppendSharded(b *testing.B) {
+	benchmarkMemoryConcurrentCreateOrAppend(b, newShardedMemoryStorage(defaultNumShards))
+}
+
+func benchmarkMemoryConcurrentCreateOrAppend(b *testing.B, st *memoryStorage) {
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+
+	var counter uint32
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		// each writer works on its own set of traces
+		writer := byte(atomic.AddUint32(&counter, 1))
+		i := 0
+		for pb.Next() {
+			traceID := pdata.NewTraceID([16]byte{writer, byte(i), byte(i >> 8), byte(i >> 16)})
+			_ = st.createOrAppend(context.Background(), traceID.HexString(), batch)
+			if i%10 == 9 {
+				_, _ = st.delete(context.Background(), traceID.HexString())
+			}
+			i++
+		}
+	})
+}
+
+func TestMemoryPeriodicMetrics(t *testing.T) {
+	// prepare
+	views := MetricViews()
+
+	// ensure that we are starting with a clean state
+	view.Unregister(views...)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	st := newMemoryStorage()
+	st.metricsCollectionInterval = time.Millisecond
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	batch.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	require.NoError(t, st.createOrAppend(context.Background(), pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), batch))
+
+	// test
+	require.NoError(t, st.start())
+
+	// verify
+	assert.Eventually(t, func() bool {
+		viewData, err := view.RetrieveData("processor/groupbytrace/" + mNumTracesInMemory.Name())
+		return err == nil && len(viewData) == 1 && viewData[0].Data.(*view.LastValueData).Value == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, st.shutdown())
+
+	// a final zero is recorded once the storage is stopped
+	assertGauge(t, 0, mNumTracesInMemory)
+	assertGauge(t, 0, mBytesInMemory)
+	assertGauge(t, 0, mSpansInMemory)
+}
+
+func TestMemoryRefusesWritesAfterShutdown(t *testing.T) {
+	// prepare
+	st := newMemoryStorage()
+	require.NoError(t, st.start())
+	require.NoError(t, st.shutdown())
+
+	// test
+	err := st.createOrAppend(context.Background(), pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), simpleTraces().ResourceSpans().At(0))
+
+	// verify
+	assert.Equal(t, errStorageStopped, err)
+	assert.Equal(t, 0, st.count())
+}
+
+func TestMemoryShutdownStopsPeriodicMetrics(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	// prepare
+	st := newMemoryStorage()
+	st.metricsCollectionInterval = time.Millisecond
+	require.NoError(t, st.start())
+
+	// test
+	require.NoError(t, st.shutdown())
+
+	// verify
+	// goleak checks that the periodic metrics goroutine is gone, and a second shutdown is a no-op
+	assert.NoError(t, st.shutdown())
+}
@@ -0,0 +1,202 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// redisStorage keeps the traces in a Redis server, allowing multiple instances of the processor to share
+// the state of the traces. Each trace is a list under the key "<prefix><group key>", where each item is
+// a serialized ResourceSpans.
+type redisStorage struct {
+	client *redis.Client
+	logger *zap.Logger
+
+	keyPrefix string
+	ttl       time.Duration
+
+	metricsCollectionInterval time.Duration
+
+	// closing stopCh signals the periodic metrics goroutine to stop, which then marks metricsWG as done
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	metricsWG sync.WaitGroup
+}
+
+var _ storage = (*redisStorage)(nil)
+
+func newRedisStorage(logger *zap.Logger, cfg RedisConfig, ttl time.Duration) (*redisStorage, error) {
+	tlsConfig, err := cfg.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load the TLS configuration for the redis storage: %w", err)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:      cfg.Endpoint,
+		Password:  cfg.Password,
+		TLSConfig: tlsConfig,
+	})
+
+	return &redisStorage{
+		client:                    client,
+		logger:                    logger,
+		keyPrefix:                 cfg.KeyPrefix,
+		ttl:                       ttl,
+		metricsCollectionInterval: defaultMetricsFlushInterval,
+		stopCh:                    make(chan struct{}),
+	}, nil
+}
+
+func (st *redisStorage) createOrAppend(ctx context.Context, key string, rs pdata.ResourceSpans) error {
+	select {
+	case <-st.stopCh:
+		// the processor is done with this storage, and wouldn't release these spans anymore
+		return errStorageStopped
+	default:
+	}
+
+	value, err := marshalResourceSpans(rs)
+	if err != nil {
+		return fmt.Errorf("couldn't serialize trace %q: %w", key, err)
+	}
+
+	redisKey := st.redisKey(key)
+	_, err = st.client.WithContext(ctx).TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.RPush(redisKey, value)
+		pipe.Expire(redisKey, st.ttl)
+		return nil
+	})
+	return err
+}
+
+func (st *redisStorage) get(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
+	values, err := st.client.WithContext(ctx).LRange(st.redisKey(key), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalResourceSpansList(key, values)
+}
+
+// delete will return the resource spans that were stored for the given trace. The retrieval and removal
+// happen atomically, so that spans appended concurrently by other instances are either returned here
+// or kept for a future trace.
+func (st *redisStorage) delete(ctx context.Context, key string) ([]pdata.ResourceSpans, error) {
+	redisKey := st.redisKey(key)
+
+	var lrange *redis.StringSliceCmd
+	_, err := st.client.WithContext(ctx).TxPipelined(func(pipe redis.Pipeliner) error {
+		lrange = pipe.LRange(redisKey, 0, -1)
+		pipe.Del(redisKey)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalResourceSpansList(key, lrange.Val())
+}
+
+func (st *redisStorage) start() error {
+	if err := st.client.Ping().Err(); err != nil {
+		return fmt.Errorf("couldn't connect to the redis storage: %w", err)
+	}
+
+	st.metricsWG.Add(1)
+	go st.periodicMetrics()
+	return nil
+}
+
+// shutdown stops the periodic metrics and closes the connection to the Redis server
+func (st *redisStorage) shutdown() error {
+	st.stopOnce.Do(func() {
+		close(st.stopCh)
+	})
+	st.metricsWG.Wait()
+	return st.client.Close()
+}
+
+func (st *redisStorage) periodicMetrics() {
+	defer st.metricsWG.Done()
+
+	ticker := time.NewTicker(st.metricsCollectionInterval)
+	defer ticker.Stop()
+
+	st.recordMetrics()
+	for {
+		select {
+		case <-ticker.C:
+			st.recordMetrics()
+		case <-st.stopCh:
+			// the traces might still be in Redis, but this instance isn't holding them anymore
+			stats.Record(context.Background(), mNumTracesInMemory.M(0))
+			return
+		}
+	}
+}
+
+func (st *redisStorage) recordMetrics() {
+	numTraces, err := st.count()
+	if err != nil {
+		st.logger.Debug("couldn't count the traces in the redis storage", zap.Error(err))
+		return
+	}
+	stats.Record(context.Background(), mNumTracesInMemory.M(int64(numTraces)))
+}
+
+// count returns the number of keys under the configured prefix
+func (st *redisStorage) count() (int, error) {
+	count := 0
+	it := st.client.Scan(0, st.keyPrefix+"*", 1000).Iterator()
+	for it.Next() {
+		count++
+	}
+	return count, it.Err()
+}
+
+func (st *redisStorage) redisKey(key string) string {
+	return st.keyPrefix + key
+}
+
+func marshalResourceSpans(rs pdata.ResourceSpans) ([]byte, error) {
+	trace := pdata.NewTraces()
+	trace.ResourceSpans().Append(rs)
+	return trace.ToOtlpProtoBytes()
+}
+
+// unmarshalResourceSpansList returns the resource spans from the given serialized values, or nil in case
+// there are no values
+func unmarshalResourceSpansList(key string, values []string) ([]pdata.ResourceSpans, error) {
+	var result []pdata.ResourceSpans
+	for _, value := range values {
+		trace := pdata.NewTraces()
+		if err := trace.FromOtlpProtoBytes([]byte(value)); err != nil {
+			return nil, fmt.Errorf("couldn't deserialize trace %q: %w", key, err)
+		}
+		for i := 0; i < trace.ResourceSpans().Len(); i++ {
+			result = append(result, trace.ResourceSpans().At(i))
+		}
+	}
+	return result, nil
+}
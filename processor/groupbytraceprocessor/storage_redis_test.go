@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/goleak"
+)
+
+func TestRedisCreateAndGetTrace(t *testing.T) {
+	// prepare
+	st, _, cleanup := newStartedRedisStorage(t)
+	defer cleanup()
+
+	traceIDs := []pdata.TraceID{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4}),
+		pdata.NewTraceID([16]byte{2, 3, 4, 5}),
+	}
+
+	baseTrace := pdata.NewResourceSpans()
+	baseTrace.InstrumentationLibrarySpans().Resize(1)
+	ils := baseTrace.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+
+	// test
+	for _, traceID := range traceIDs {
+		span.SetTraceID(traceID)
+		require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), baseTrace))
+	}
+
+	// verify
+	count, err := st.count()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	for _, traceID := range traceIDs {
+		expected := pdata.NewResourceSpans()
+		baseTrace.CopyTo(expected)
+		expected.InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(traceID)
+
+		retrieved, err := st.get(context.Background(), traceID.HexString())
+		require.NoError(t, err)
+		assert.Equal(t, []pdata.ResourceSpans{expected}, retrieved)
+	}
+}
+
+func TestRedisDeleteTrace(t *testing.T) {
+	// prepare
+	st, _, cleanup := newStartedRedisStorage(t)
+	defer cleanup()
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	trace := pdata.NewResourceSpans()
+	trace.InstrumentationLibrarySpans().Resize(1)
+	ils := trace.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(traceID)
+
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), trace))
+
+	// test
+	deleted, err := st.delete(context.Background(), traceID.HexString())
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []pdata.ResourceSpans{trace}, deleted)
+
+	retrieved, err := st.get(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+}
+
+func TestRedisAppendSpans(t *testing.T) {
+	// prepare
+	st, _, cleanup := newStartedRedisStorage(t)
+	defer cleanup()
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	batch := pdata.NewResourceSpans()
+	batch.InstrumentationLibrarySpans().Resize(1)
+	ils := batch.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(traceID)
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4}))
+
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), batch))
+
+	secondBatch := pdata.NewResourceSpans()
+	secondBatch.InstrumentationLibrarySpans().Resize(1)
+	secondIls := secondBatch.InstrumentationLibrarySpans().At(0)
+	secondIls.Spans().Resize(1)
+	secondSpan := secondIls.Spans().At(0)
+	secondSpan.SetName("second-name")
+	secondSpan.SetTraceID(traceID)
+	secondSpan.SetSpanID(pdata.NewSpanID([8]byte{5, 6, 7, 8}))
+
+	expected := []pdata.ResourceSpans{
+		pdata.NewResourceSpans(),
+		pdata.NewResourceSpans(),
+	}
+	batch.CopyTo(expected[0])
+	secondBatch.CopyTo(expected[1])
+
+	// test
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), secondBatch))
+
+	// override something in the second span, to make sure we are storing a copy
+	secondSpan.SetName("changed-second-name")
+
+	// verify
+	deleted, err := st.delete(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+	assert.Equal(t, expected, deleted)
+}
+
+func TestRedisKeyPrefixAndTTL(t *testing.T) {
+	// prepare
+	st, srv, cleanup := newStartedRedisStorage(t)
+	defer cleanup()
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	// test
+	require.NoError(t, st.createOrAppend(context.Background(), traceID.HexString(), pdata.NewResourceSpans()))
+
+	// verify
+	key := "test:" + traceID.HexString()
+	assert.True(t, srv.Exists(key))
+	assert.Equal(t, time.Minute, srv.TTL(key))
+
+	srv.FastForward(time.Minute)
+	retrieved, err := st.get(context.Background(), traceID.HexString())
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+}
+
+func TestRedisStartFailsWhenUnavailable(t *testing.T) {
+	// prepare
+	srv, err := miniredis.Run()
+	require.NoError(t, err)
+	addr := srv.Addr()
+	srv.Close()
+
+	st, err := newRedisStorage(logger, RedisConfig{
+		Endpoint:   addr,
+		TLSSetting: configtls.TLSClientSetting{Insecure: true},
+	}, time.Minute)
+	require.NoError(t, err)
+
+	// test
+	err = st.start()
+
+	// verify
+	assert.Error(t, err)
+}
+
+func TestRedisShutdownStopsPeriodicMetrics(t *testing.T) {
+	// prepare
+	srv, err := miniredis.Run()
+	require.NoError(t, err)
+	defer srv.Close()
+
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st, err := newRedisStorage(logger, RedisConfig{
+		Endpoint:   srv.Addr(),
+		TLSSetting: configtls.TLSClientSetting{Insecure: true},
+	}, time.Minute)
+	require.NoError(t, err)
+	st.metricsCollectionInterval = time.Millisecond
+	require.NoError(t, st.start())
+
+	// test and verify
+	assert.NoError(t, st.shutdown())
+}
+
+func TestRedisRefusesWritesAfterShutdown(t *testing.T) {
+	// prepare
+	st, srv, cleanup := newStartedRedisStorage(t)
+	defer cleanup()
+	require.NoError(t, st.shutdown())
+
+	// test
+	err := st.createOrAppend(context.Background(), pdata.NewTraceID([16]byte{1, 2, 3, 4}).HexString(), simpleTraces().ResourceSpans().At(0))
+
+	// verify
+	assert.Equal(t, errStorageStopped, err)
+	assert.Empty(t, srv.Keys())
+}
+
+func newStartedRedisStorage(t *testing.T) (*redisStorage, *miniredis.Miniredis, func()) {
+	srv, err := miniredis.Run()
+	require.NoError(t, err)
+
+	st, err := newRedisStorage(logger, RedisConfig{
+		Endpoint:   srv.Addr(),
+		KeyPrefix:  "test:",
+		TLSSetting: configtls.TLSClientSetting{Insecure: true},
+	}, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, st.start())
+
+	return st, srv, func() {
+		st.shutdown()
+		srv.Close()
+	}
+}
@@ -27,11 +27,12 @@ import (
 
 // fakeClient is used as a replacement for WatchClient in test cases.
 type fakeClient struct {
-	Pods     map[string]*kube.Pod
-	Rules    kube.ExtractionRules
-	Filters  kube.Filters
-	Informer cache.SharedInformer
-	StopCh   chan struct{}
+	Pods      map[string]*kube.Pod
+	PodsByUID map[string]*kube.Pod
+	Rules     kube.ExtractionRules
+	Filters   kube.Filters
+	Informer  cache.SharedInformer
+	StopCh    chan struct{}
 }
 
 func selectors() (labels.Selector, fields.Selector) {
@@ -45,11 +46,12 @@ func newFakeClient(_ *zap.Logger, apiCfg k8sconfig.APIConfig, rules kube.Extract
 
 	ls, fs := selectors()
 	return &fakeClient{
-		Pods:     map[string]*kube.Pod{},
-		Rules:    rules,
-		Filters:  filters,
-		Informer: kube.NewFakeInformer(cs, "", ls, fs),
-		StopCh:   make(chan struct{}),
+		Pods:      map[string]*kube.Pod{},
+		PodsByUID: map[string]*kube.Pod{},
+		Rules:     rules,
+		Filters:   filters,
+		Informer:  kube.NewFakeInformer(cs, "", ls, fs),
+		StopCh:    make(chan struct{}),
 	}, nil
 }
 
@@ -59,6 +61,12 @@ func (f *fakeClient) GetPodByIP(ip string) (*kube.Pod, bool) {
 	return p, ok
 }
 
+// GetPodByUID looks up FakeClient.PodsByUID map by the provided string.
+func (f *fakeClient) GetPodByUID(uid string) (*kube.Pod, bool) {
+	p, ok := f.PodsByUID[uid]
+	return p, ok
+}
+
 // Start is a noop for FakeClient.
 func (f *fakeClient) Start() {
 	if f.Informer != nil {
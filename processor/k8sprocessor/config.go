@@ -39,6 +39,26 @@ type Config struct {
 	// Filter section allows specifying filters to filter
 	// pods by labels, fields, namespaces, nodes, etc.
 	Filter FilterConfig `mapstructure:"filter"`
+
+	// Association section allows specifying the resource attributes used to
+	// associate the telemetry with a pod, tried in order. When none of them
+	// is found on a resource, the IP of the connection is used instead.
+	// When not specified, the pod IP is looked up in the well-known
+	// "k8s.pod.ip" and "ip" attributes, as well as "host.name" for metrics.
+	Association []PodAssociationConfig `mapstructure:"pod_association"`
+}
+
+// PodAssociationConfig allows specifying a resource attribute identifying the pod
+// that the telemetry comes from. This is required when the IP of the connection
+// isn't the pod's, for example when the telemetry is relayed through an agent
+// running on the node, or when the pods use the host network and so share the
+// IP of their node.
+type PodAssociationConfig struct {
+	// Name is the name of the resource attribute holding the identifier of the pod.
+	// The "k8s.pod.uid" attribute holds the pod UID, which also identifies the pods
+	// running in the host network mode; any other attribute must hold the pod IP,
+	// the values that are not valid IP addresses being skipped.
+	Name string `mapstructure:"name"`
 }
 
 // ExtractConfig section allows specifying extraction rules to extract
@@ -60,17 +80,18 @@ type ExtractConfig struct {
 	// documentation for more details.
 	Annotations []FieldExtractConfig `mapstructure:"annotations"`
 
-	// Annotations allows extracting data from pod labels and record it
+	// Labels allows extracting data from pod labels and record it
 	// as resource attributes.
 	// It is a list of FieldExtractConfig type. See FieldExtractConfig
 	// documentation for more details.
 	Labels []FieldExtractConfig `mapstructure:"labels"`
 }
 
-// FieldExtractConfig allows specifying an extraction rule to extract a value from exactly one field.
+// FieldExtractConfig allows specifying an extraction rule to extract a value from exactly one field,
+// or from all the fields whose key matches a regular expression.
 //
-// The field accepts a list FilterExtractConfig map. The map accepts three keys
-//     tag_name, key and regex
+// The field accepts a list FilterExtractConfig map. The map accepts four keys
+//     tag_name, key, key_regex and regex
 //
 // - tag_name represents the name of the tag that will be added to the span.
 //   When not specified a default tag name will be used of the format:
@@ -81,6 +102,18 @@ type ExtractConfig struct {
 //
 // - key represents the annotation name. This must exactly match an annotation name.
 //
+// - key_regex is a regular expression matched against the whole key of each annotation or label,
+//   every matching field being extracted. Exactly one of key and key_regex must be specified.
+//   When used, the tag_name can refer to the submatches of the key_regex, such as ${1}, escaped as
+//   $${1} in the configuration files so that they aren't expanded as environment variables, and
+//   it defaults to k8s.pod.annotations.<annotation key> or k8s.pod.labels.<label key> for each
+//   matching key. For example, the following rule extracts all the labels prefixed with
+//   app.kubernetes.io/, as tags named app.<rest of the key>:
+//
+//       labels:
+//         - tag_name: app.$${1}
+//           key_regex: app\.kubernetes\.io/(.*)
+//
 // - regex is an optional field used to extract a sub-string from a complex field value.
 //   The supplied regular expression must contain one named parameter with the string "value"
 //   as the name. For example, if your pod spec contains the following annotation,
@@ -102,9 +135,10 @@ type ExtractConfig struct {
 //
 //   this will add the `git.sha` and `ci.build` tags to the spans or metrics.
 type FieldExtractConfig struct {
-	TagName string `mapstructure:"tag_name"`
-	Key     string `mapstructure:"key"`
-	Regex   string `mapstructure:"regex"`
+	TagName  string `mapstructure:"tag_name"`
+	Key      string `mapstructure:"key"`
+	KeyRegex string `mapstructure:"key_regex"`
+	Regex    string `mapstructure:"regex"`
 }
 
 // FilterConfig section allows specifying filters to filter
@@ -70,10 +70,12 @@ func TestLoadConfig(t *testing.T) {
 				Annotations: []FieldExtractConfig{
 					{TagName: "a1", Key: "annotation-one"},
 					{TagName: "a2", Key: "annotation-two", Regex: "field=(?P<value>.+)"},
+					{TagName: "deploy.${1}", KeyRegex: "deploy/(.*)"},
 				},
 				Labels: []FieldExtractConfig{
 					{TagName: "l1", Key: "label1"},
 					{TagName: "l2", Key: "label2", Regex: "field=(?P<value>.+)"},
+					{KeyRegex: `app\.kubernetes\.io/.*`},
 				},
 			},
 			Filter: FilterConfig{
@@ -89,5 +91,10 @@ func TestLoadConfig(t *testing.T) {
 					{Key: "key2", Value: "value2", Op: "not-equals"},
 				},
 			},
+			Association: []PodAssociationConfig{
+				{Name: "k8s.pod.uid"},
+				{Name: "k8s.pod.ip"},
+				{Name: "host.name"},
+			},
 		})
 }
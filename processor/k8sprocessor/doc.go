@@ -105,9 +105,18 @@
 //
 // Host networking mode
 //
-// The processor cannot correct identify pods running in the host network mode and
-// enriching telemetry data generated by such pods is not supported at the moment, unless the attributes contain
-// information about the source IP.
+// The processor cannot correct identify pods running in the host network mode by their IP, as they share
+// the IP of their node. Such pods can still be associated with their telemetry data when the resources contain
+// the pod UID in the "k8s.pod.uid" attribute, with the following pod association:
+//
+//    k8s_tagger:
+//      pod_association:
+//        - name: k8s.pod.uid
+//        - name: k8s.pod.ip
+//
+// The pod associations are tried in order, the IP of the connection being used only when none of the
+// attributes is found. The resources that can't be associated with any pod are passed through unchanged,
+// and counted by the otelsvc/k8s/pod_association_miss metric.
 //
 // As a sidecar
 //
@@ -45,9 +45,11 @@ type WatchClient struct {
 	deleteQueue     []deleteRequest
 	stopCh          chan struct{}
 
-	Pods    map[string]*Pod
-	Rules   ExtractionRules
-	Filters Filters
+	// Pods holds the pods by their IP, and PodsByUID by their UID
+	Pods      map[string]*Pod
+	PodsByUID map[string]*Pod
+	Rules     ExtractionRules
+	Filters   Filters
 }
 
 // Extract deployment name from the pod name. Pod name is created using
@@ -60,6 +62,7 @@ func New(logger *zap.Logger, apiCfg k8sconfig.APIConfig, rules ExtractionRules,
 	go c.deleteLoop(time.Second*30, defaultPodDeleteGracePeriod)
 
 	c.Pods = map[string]*Pod{}
+	c.PodsByUID = map[string]*Pod{}
 	if newClientSet == nil {
 		newClientSet = k8sconfig.MakeClient
 	}
@@ -159,6 +162,9 @@ func (c *WatchClient) deleteLoop(interval time.Duration, gracePeriod time.Durati
 						delete(c.Pods, d.ip)
 					}
 				}
+				if d.uid != "" {
+					delete(c.PodsByUID, d.uid)
+				}
 			}
 			c.m.Unlock()
 
@@ -183,6 +189,21 @@ func (c *WatchClient) GetPodByIP(ip string) (*Pod, bool) {
 	return nil, false
 }
 
+// GetPodByUID takes a pod UID and returns the pod with this UID.
+func (c *WatchClient) GetPodByUID(uid string) (*Pod, bool) {
+	c.m.RLock()
+	pod, ok := c.PodsByUID[uid]
+	c.m.RUnlock()
+	if ok {
+		if pod.Ignore {
+			return nil, false
+		}
+		return pod, ok
+	}
+	observability.RecordUIDLookupMiss()
+	return nil, false
+}
+
 func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 	tags := map[string]string{}
 	if c.Rules.PodName {
@@ -224,18 +245,29 @@ func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 		}
 	}
 
-	for _, r := range c.Rules.Labels {
-		if v, ok := pod.Labels[r.Key]; ok {
-			tags[r.Name] = c.extractField(v, r)
+	c.extractFields(tags, pod.Labels, c.Rules.Labels)
+	c.extractFields(tags, pod.Annotations, c.Rules.Annotations)
+	return tags
+}
+
+// extractFields adds to the tags the fields matched by the given rules, so that only the matching subset of
+// the labels or annotations of a pod is kept in the cache.
+func (c *WatchClient) extractFields(tags map[string]string, fields map[string]string, rules []FieldExtractionRule) {
+	for _, r := range rules {
+		if r.KeyRegex == nil {
+			if v, ok := fields[r.Key]; ok {
+				tags[r.Name] = c.extractField(v, r)
+			}
+			continue
 		}
-	}
 
-	for _, r := range c.Rules.Annotations {
-		if v, ok := pod.Annotations[r.Key]; ok {
-			tags[r.Name] = c.extractField(v, r)
+		for k, v := range fields {
+			if match := r.KeyRegex.FindStringSubmatchIndex(k); match != nil {
+				name := string(r.KeyRegex.ExpandString(nil, r.Name, k, match))
+				tags[name] = c.extractField(v, r)
+			}
 		}
 	}
-	return tags
 }
 
 func (c *WatchClient) extractField(v string, r FieldExtractionRule) string {
@@ -253,12 +285,32 @@ func (c *WatchClient) extractField(v string, r FieldExtractionRule) string {
 }
 
 func (c *WatchClient) addOrUpdatePod(pod *api_v1.Pod) {
-	if pod.Status.PodIP == "" {
+	if pod.Status.PodIP == "" && pod.UID == "" {
 		return
 	}
 
+	newPod := &Pod{
+		Name:      pod.Name,
+		Address:   pod.Status.PodIP,
+		PodUID:    string(pod.UID),
+		StartTime: pod.Status.StartTime,
+	}
+
+	if c.shouldIgnorePod(pod) {
+		newPod.Ignore = true
+	} else {
+		newPod.Attributes = c.extractPodAttributes(pod)
+	}
+
 	c.m.Lock()
 	defer c.m.Unlock()
+	if newPod.PodUID != "" {
+		c.PodsByUID[newPod.PodUID] = newPod
+	}
+
+	if pod.Status.PodIP == "" {
+		return
+	}
 	// compare initial scheduled timestamp for existing pod and new pod with same IP
 	// and only replace old pod if scheduled time of new pod is newer? This should fix
 	// the case where scheduler has assigned the same IP to a new pod but update event for
@@ -268,49 +320,46 @@ func (c *WatchClient) addOrUpdatePod(pod *api_v1.Pod) {
 			return
 		}
 	}
-	newPod := &Pod{
-		Name:      pod.Name,
-		Address:   pod.Status.PodIP,
-		StartTime: pod.Status.StartTime,
-	}
 
-	if c.shouldIgnorePod(pod) {
-		newPod.Ignore = true
-	} else {
-		newPod.Attributes = c.extractPodAttributes(pod)
+	// Host network mode is not supported with IP based association, as all
+	// the pods in host network get the IP address of their node: such pods
+	// can only be associated by their UID.
+	if pod.Spec.HostNetwork {
+		ipPod := *newPod
+		ipPod.Ignore = true
+		ipPod.Attributes = nil
+		c.Pods[pod.Status.PodIP] = &ipPod
+		return
 	}
 	c.Pods[pod.Status.PodIP] = newPod
 }
 
 func (c *WatchClient) forgetPod(pod *api_v1.Pod) {
-	if pod.Status.PodIP == "" {
+	if pod.Status.PodIP == "" && pod.UID == "" {
 		return
 	}
+
+	request := deleteRequest{
+		name: pod.Name,
+		ts:   time.Now(),
+	}
 	c.m.RLock()
-	p, ok := c.GetPodByIP(pod.Status.PodIP)
+	if p, ok := c.Pods[pod.Status.PodIP]; ok && pod.Status.PodIP != "" && p.Name == pod.Name {
+		request.ip = pod.Status.PodIP
+	}
+	if _, ok := c.PodsByUID[string(pod.UID)]; ok && pod.UID != "" {
+		request.uid = string(pod.UID)
+	}
 	c.m.RUnlock()
 
-	if ok && p.Name == pod.Name {
+	if request.ip != "" || request.uid != "" {
 		c.deleteMut.Lock()
-		c.deleteQueue = append(c.deleteQueue, deleteRequest{
-			ip:   pod.Status.PodIP,
-			name: pod.Name,
-			ts:   time.Now(),
-		})
+		c.deleteQueue = append(c.deleteQueue, request)
 		c.deleteMut.Unlock()
 	}
 }
 
 func (c *WatchClient) shouldIgnorePod(pod *api_v1.Pod) bool {
-	// Host network mode is not supported right now with IP based
-	// tagging as all pods in host network get same IP addresses.
-	// Such pods are very rare and usually are used to monitor or control
-	// host traffic (e.g, linkerd, flannel) instead of service business needs.
-	// We plan to support host network pods in future.
-	if pod.Spec.HostNetwork {
-		return true
-	}
-
 	// Check if user requested the pod to be ignored through annotations
 	if v, ok := pod.Annotations[ignoreAnnotation]; ok {
 		if strings.ToLower(strings.TrimSpace(v)) == "true" {
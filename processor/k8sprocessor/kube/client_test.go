@@ -149,6 +149,59 @@ func TestPodHostNetwork(t *testing.T) {
 	assert.Equal(t, got.Address, "1.1.1.1")
 	assert.Equal(t, got.Name, "podA")
 	assert.True(t, got.Ignore)
+
+	// the pods in host network can still be associated by their UID
+	pod.UID = "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
+	c.handlePodAdd(pod)
+	got, ok := c.GetPodByUID("aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee")
+	require.True(t, ok)
+	assert.Equal(t, got.Name, "podA")
+	assert.False(t, got.Ignore)
+	_, ok = c.GetPodByIP("1.1.1.1")
+	assert.False(t, ok)
+}
+
+func TestPodUIDIndex(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	podA := &api_v1.Pod{}
+	podA.Name = "podA"
+	podA.UID = "uid-a"
+	podA.Status.PodIP = "1.1.1.1"
+	startTimeA := meta_v1.NewTime(time.Now().Add(-time.Minute))
+	podA.Status.StartTime = &startTimeA
+	c.handlePodAdd(podA)
+
+	got, ok := c.GetPodByUID("uid-a")
+	require.True(t, ok)
+	assert.Equal(t, "podA", got.Name)
+	assert.Equal(t, "1.1.1.1", got.Address)
+
+	// the IP is reused by a newer pod, while the older one is still known by its UID
+	podB := &api_v1.Pod{}
+	podB.Name = "podB"
+	podB.UID = "uid-b"
+	podB.Status.PodIP = "1.1.1.1"
+	startTimeB := meta_v1.NewTime(time.Now())
+	podB.Status.StartTime = &startTimeB
+	c.handlePodAdd(podB)
+
+	got, ok = c.GetPodByIP("1.1.1.1")
+	require.True(t, ok)
+	assert.Equal(t, "podB", got.Name)
+	got, ok = c.GetPodByUID("uid-a")
+	require.True(t, ok)
+	assert.Equal(t, "podA", got.Name)
+	assert.Len(t, c.PodsByUID, 2)
+
+	// deleting the older pod must not forget the IP of the newer one
+	c.handlePodDelete(podA)
+	require.Len(t, c.deleteQueue, 1)
+	assert.Equal(t, "", c.deleteQueue[0].ip)
+	assert.Equal(t, "uid-a", c.deleteQueue[0].uid)
+
+	_, ok = c.GetPodByUID("uid-unknown")
+	assert.False(t, ok)
 }
 
 func TestPodAddOutOfSync(t *testing.T) {
@@ -316,9 +369,13 @@ func TestExtractionRules(t *testing.T) {
 			Labels: map[string]string{
 				"label1": "lv1",
 				"label2": "k1=v1 k5=v5 extra!",
+
+				"app.kubernetes.io/name":    "auth-service",
+				"app.kubernetes.io/version": "1.2.3",
 			},
 			Annotations: map[string]string{
-				"annotation1": "av1",
+				"annotation1":       "av1",
+				"deploy/commit-sha": "58a1e39fd2c4b7",
 			},
 		},
 		Spec: api_v1.PodSpec{
@@ -388,6 +445,26 @@ func TestExtractionRules(t *testing.T) {
 			"l2": "v5",
 			"a1": "av1",
 		},
+	}, {
+		name: "key-regex",
+		rules: ExtractionRules{
+			Annotations: []FieldExtractionRule{{
+				Name:     "deploy.${1}",
+				KeyRegex: regexp.MustCompile(`^(?:deploy/(.*))$`),
+				Regex:    regexp.MustCompile(`^(?P<value>[0-9a-f]{7})`),
+			},
+			},
+			Labels: []FieldExtractionRule{{
+				Name:     "k8s.pod.labels.$0",
+				KeyRegex: regexp.MustCompile(`^(?:app\.kubernetes\.io/.*)$`),
+			},
+			},
+		},
+		attributes: map[string]string{
+			"k8s.pod.labels.app.kubernetes.io/name":    "auth-service",
+			"k8s.pod.labels.app.kubernetes.io/version": "1.2.3",
+			"deploy.commit-sha":                        "58a1e39",
+		},
 	},
 	}
 	for _, tc := range testCases {
@@ -480,7 +557,8 @@ func TestPodIgnorePatterns(t *testing.T) {
 		ignore: false,
 		pod:    api_v1.Pod{},
 	}, {
-		ignore: true,
+		// the pods in host network are only kept out of the IP index, see TestPodHostNetwork
+		ignore: false,
 		pod: api_v1.Pod{
 			Spec: api_v1.PodSpec{
 				HostNetwork: true,
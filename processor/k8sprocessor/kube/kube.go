@@ -47,6 +47,7 @@ var (
 // Client defines the main interface that allows querying pods by metadata.
 type Client interface {
 	GetPodByIP(string) (*Pod, bool)
+	GetPodByUID(string) (*Pod, bool)
 	Start()
 	Stop()
 }
@@ -62,6 +63,7 @@ type APIClientsetProvider func(config k8sconfig.APIConfig) (kubernetes.Interface
 type Pod struct {
 	Name       string
 	Address    string
+	PodUID     string
 	Attributes map[string]string
 	StartTime  *metav1.Time
 	Ignore     bool
@@ -71,6 +73,7 @@ type Pod struct {
 
 type deleteRequest struct {
 	ip   string
+	uid  string
 	name string
 	ts   time.Time
 }
@@ -117,10 +120,14 @@ type ExtractionRules struct {
 // FieldExtractionRule is used to specify which fields to extract from pod fields
 // and inject into spans as attributes.
 type FieldExtractionRule struct {
-	// Name is used to as the Span tag name.
+	// Name is used to as the Span tag name. For the rules with a KeyRegex, it is a template
+	// expanded with the submatches of the KeyRegex, as per regexp.Regexp.Expand.
 	Name string
 	// Key is used to lookup k8s pod fields.
 	Key string
+	// KeyRegex is a regular expression used to lookup all the k8s pod fields with a matching key,
+	// instead of the single field with Key.
+	KeyRegex *regexp.Regexp
 	// Regex is a regular expression used to extract a sub-part of a field value.
 	// Full value is extracted when no regexp is provided.
 	Regex *regexp.Regexp
@@ -30,6 +30,8 @@ func init() {
 		viewPodsAdded,
 		viewPodsDeleted,
 		viewIPLookupMiss,
+		viewUIDLookupMiss,
+		viewPodAssociationMiss,
 	)
 }
 
@@ -38,7 +40,10 @@ var (
 	mPodsAdded   = stats.Int64("otelsvc/k8s/pod_added", "Number of pod add events received", "1")
 	mPodsDeleted = stats.Int64("otelsvc/k8s/pod_deleted", "Number of pod delete events received", "1")
 
-	mIPLookupMiss = stats.Int64("otelsvc/k8s/ip_lookup_miss", "Number of times pod by IP lookup failed.", "1")
+	mIPLookupMiss  = stats.Int64("otelsvc/k8s/ip_lookup_miss", "Number of times pod by IP lookup failed.", "1")
+	mUIDLookupMiss = stats.Int64("otelsvc/k8s/uid_lookup_miss", "Number of times pod by UID lookup failed.", "1")
+
+	mPodAssociationMiss = stats.Int64("otelsvc/k8s/pod_association_miss", "Number of resources that couldn't be associated with a pod.", "1")
 )
 
 var viewPodsUpdated = &view.View{
@@ -69,6 +74,20 @@ var viewIPLookupMiss = &view.View{
 	Aggregation: view.Sum(),
 }
 
+var viewUIDLookupMiss = &view.View{
+	Name:        mUIDLookupMiss.Name(),
+	Description: mUIDLookupMiss.Description(),
+	Measure:     mUIDLookupMiss,
+	Aggregation: view.Sum(),
+}
+
+var viewPodAssociationMiss = &view.View{
+	Name:        mPodAssociationMiss.Name(),
+	Description: mPodAssociationMiss.Description(),
+	Measure:     mPodAssociationMiss,
+	Aggregation: view.Sum(),
+}
+
 // RecordPodUpdated increments the metric that records pod update events received.
 func RecordPodUpdated() {
 	stats.Record(context.Background(), mPodsUpdated.M(int64(1)))
@@ -88,3 +107,13 @@ func RecordPodDeleted() {
 func RecordIPLookupMiss() {
 	stats.Record(context.Background(), mIPLookupMiss.M(int64(1)))
 }
+
+// RecordUIDLookupMiss increments the metric that records Pod lookup by UID misses.
+func RecordUIDLookupMiss() {
+	stats.Record(context.Background(), mUIDLookupMiss.M(int64(1)))
+}
+
+// RecordPodAssociationMiss increments the metric that records the resources that couldn't be associated with a pod.
+func RecordPodAssociationMiss() {
+	stats.Record(context.Background(), mPodAssociationMiss.M(int64(1)))
+}
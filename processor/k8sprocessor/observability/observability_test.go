@@ -78,6 +78,14 @@ func TestMetrics(t *testing.T) {
 			"otelsvc/k8s/ip_lookup_miss",
 			RecordIPLookupMiss,
 		},
+		{
+			"otelsvc/k8s/uid_lookup_miss",
+			RecordUIDLookupMiss,
+		},
+		{
+			"otelsvc/k8s/pod_association_miss",
+			RecordPodAssociationMiss,
+		},
 	}
 
 	e := newExporter()
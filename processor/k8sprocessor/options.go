@@ -128,8 +128,23 @@ func WithExtractAnnotations(annotations ...FieldExtractConfig) Option {
 func extractFieldRules(fieldType string, fields ...FieldExtractConfig) ([]kube.FieldExtractionRule, error) {
 	rules := []kube.FieldExtractionRule{}
 	for _, a := range fields {
+		if (a.Key == "") == (a.KeyRegex == "") {
+			return rules, fmt.Errorf("exactly one of key or key_regex must be specified for the %s extraction rules", fieldType)
+		}
+
 		name := a.TagName
-		if name == "" {
+		var keyRegex *regexp.Regexp
+		if a.KeyRegex != "" {
+			var err error
+			// the whole key must match, the submatches being available to the tag name
+			keyRegex, err = regexp.Compile("^(?:" + a.KeyRegex + ")$")
+			if err != nil {
+				return rules, err
+			}
+			if name == "" {
+				name = fmt.Sprintf("k8s.pod.%s.$0", fieldType)
+			}
+		} else if name == "" {
 			name = fmt.Sprintf("k8s.pod.%s.%s", fieldType, a.Key)
 		}
 
@@ -147,7 +162,7 @@ func extractFieldRules(fieldType string, fields ...FieldExtractConfig) ([]kube.F
 		}
 
 		rules = append(rules, kube.FieldExtractionRule{
-			Name: name, Key: a.Key, Regex: r,
+			Name: name, Key: a.Key, KeyRegex: keyRegex, Regex: r,
 		})
 	}
 	return rules, nil
@@ -234,3 +249,16 @@ func WithFilterFields(filters ...FieldFilterConfig) Option {
 		return nil
 	}
 }
+
+// WithExtractPodAssociations allows specifying the resource attributes used to associate the telemetry with a pod.
+func WithExtractPodAssociations(podAssociations ...PodAssociationConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		for _, association := range podAssociations {
+			if association.Name == "" {
+				return fmt.Errorf("the name of the resource attribute is required for each pod association")
+			}
+		}
+		p.podAssociations = podAssociations
+		return nil
+	}
+}
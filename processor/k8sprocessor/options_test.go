@@ -221,6 +221,20 @@ func TestWithExtractMetadata(t *testing.T) {
 	assert.False(t, p.rules.Node)
 }
 
+func TestWithExtractPodAssociations(t *testing.T) {
+	p := &kubernetesprocessor{}
+	assert.NoError(t, WithExtractPodAssociations()(p))
+	assert.Empty(t, p.podAssociations)
+
+	associations := []PodAssociationConfig{{Name: "k8s.pod.uid"}, {Name: "host.name"}}
+	assert.NoError(t, WithExtractPodAssociations(associations...)(p))
+	assert.Equal(t, associations, p.podAssociations)
+
+	p = &kubernetesprocessor{}
+	err := WithExtractPodAssociations(PodAssociationConfig{Name: "k8s.pod.ip"}, PodAssociationConfig{})(p)
+	assert.EqualError(t, err, "the name of the resource attribute is required for each pod association")
+}
+
 func TestWithFilterLabels(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -550,6 +564,70 @@ func Test_extractFieldRules(t *testing.T) {
 			[]kube.FieldExtractionRule{},
 			true,
 		},
+		{
+			"key-regex-default",
+			args{"labels", []FieldExtractConfig{
+				{
+					KeyRegex: `app\.kubernetes\.io/.*`,
+				},
+			}},
+			[]kube.FieldExtractionRule{
+				{
+					Name:     "k8s.pod.labels.$0",
+					KeyRegex: regexp.MustCompile(`^(?:app\.kubernetes\.io/.*)$`),
+				},
+			},
+			false,
+		},
+		{
+			"key-regex-with-name",
+			args{"annotations", []FieldExtractConfig{
+				{
+					TagName:  "deploy.${1}",
+					KeyRegex: "deploy/(.*)",
+					Regex:    "^(?P<value>[0-9a-f]{7})",
+				},
+			}},
+			[]kube.FieldExtractionRule{
+				{
+					Name:     "deploy.${1}",
+					KeyRegex: regexp.MustCompile(`^(?:deploy/(.*))$`),
+					Regex:    regexp.MustCompile(`^(?P<value>[0-9a-f]{7})`),
+				},
+			},
+			false,
+		},
+		{
+			"bad-key-regex",
+			args{"labels", []FieldExtractConfig{
+				{
+					KeyRegex: "[",
+				},
+			}},
+			[]kube.FieldExtractionRule{},
+			true,
+		},
+		{
+			"key-and-key-regex",
+			args{"labels", []FieldExtractConfig{
+				{
+					Key:      "key",
+					KeyRegex: "key.*",
+				},
+			}},
+			[]kube.FieldExtractionRule{},
+			true,
+		},
+		{
+			"no-key",
+			args{"labels", []FieldExtractConfig{
+				{
+					TagName: "name",
+				},
+			}},
+			[]kube.FieldExtractionRule{},
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -16,14 +16,17 @@ package k8sprocessor
 
 import (
 	"context"
+	"net"
 
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/kube"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/observability"
 )
 
 const (
@@ -38,6 +41,13 @@ type kubernetesprocessor struct {
 	passthroughMode bool
 	rules           kube.ExtractionRules
 	filters         kube.Filters
+	podAssociations []PodAssociationConfig
+}
+
+// podIdentifier identifies a pod by either its UID or its IP
+type podIdentifier struct {
+	uid string
+	ip  string
 }
 
 func (kp *kubernetesprocessor) initKubeClient(logger *zap.Logger, kubeClient kube.ClientProvider) error {
@@ -99,28 +109,36 @@ func (kp *kubernetesprocessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (
 }
 
 func (kp *kubernetesprocessor) processResource(ctx context.Context, resource pdata.Resource, attributeExtractors ...ipExtractor) {
-	var podIP string
-
-	for _, extractor := range attributeExtractors {
-		podIP = extractor(resource.Attributes())
-		if podIP != "" {
-			break
+	var identifiers []podIdentifier
+	if len(kp.podAssociations) > 0 {
+		identifiers = podIdentifiersFromAssociations(resource.Attributes(), kp.podAssociations)
+	} else {
+		for _, extractor := range attributeExtractors {
+			if podIP := extractor(resource.Attributes()); podIP != "" {
+				identifiers = append(identifiers, podIdentifier{ip: podIP})
+				break
+			}
 		}
 	}
 
 	// Check if the receiver detected client IP.
-	if podIP == "" {
-		if c, ok := client.FromContext(ctx); ok {
-			podIP = c.IP
+	if len(identifiers) == 0 {
+		if c, ok := client.FromContext(ctx); ok && c.IP != "" {
+			identifiers = append(identifiers, podIdentifier{ip: c.IP})
 		}
 	}
 
-	// If IP is still not available by this point, nothing can be tagged here. Return.
-	if podIP == "" {
+	// If no identifier is available by this point, nothing can be tagged here. Return.
+	if len(identifiers) == 0 {
 		return
 	}
 
-	resource.Attributes().InsertString(k8sIPLabelName, podIP)
+	for _, identifier := range identifiers {
+		if identifier.ip != "" {
+			resource.Attributes().InsertString(k8sIPLabelName, identifier.ip)
+			break
+		}
+	}
 
 	// Don't invoke any k8s client functionality in passthrough mode.
 	// Just tag the IP and forward the batch.
@@ -129,21 +147,48 @@ func (kp *kubernetesprocessor) processResource(ctx context.Context, resource pda
 	}
 
 	// add k8s tags to resource
-	attrsToAdd := kp.getAttributesForPodIP(podIP)
-	if len(attrsToAdd) == 0 {
+	pod, ok := kp.getPod(identifiers)
+	if !ok {
+		observability.RecordPodAssociationMiss()
 		return
 	}
 
 	attrs := resource.Attributes()
-	for k, v := range attrsToAdd {
+	for k, v := range pod.Attributes {
 		attrs.InsertString(k, v)
 	}
 }
 
-func (kp *kubernetesprocessor) getAttributesForPodIP(ip string) map[string]string {
-	pod, ok := kp.kc.GetPodByIP(ip)
-	if !ok {
-		return nil
+// getPod returns the pod identified by the first of the given identifiers matching a known pod
+func (kp *kubernetesprocessor) getPod(identifiers []podIdentifier) (*kube.Pod, bool) {
+	for _, identifier := range identifiers {
+		var pod *kube.Pod
+		var ok bool
+		if identifier.uid != "" {
+			pod, ok = kp.kc.GetPodByUID(identifier.uid)
+		} else {
+			pod, ok = kp.kc.GetPodByIP(identifier.ip)
+		}
+		if ok {
+			return pod, true
+		}
+	}
+	return nil, false
+}
+
+// podIdentifiersFromAssociations returns the identifiers found in the given attributes, in the order of the associations
+func podIdentifiersFromAssociations(attrs pdata.AttributeMap, associations []PodAssociationConfig) []podIdentifier {
+	var identifiers []podIdentifier
+	for _, association := range associations {
+		value := stringAttributeFromMap(attrs, association.Name)
+		switch {
+		case value == "":
+			continue
+		case association.Name == conventions.AttributeK8sPodUID:
+			identifiers = append(identifiers, podIdentifier{uid: value})
+		case net.ParseIP(value) != nil:
+			identifiers = append(identifiers, podIdentifier{ip: value})
+		}
 	}
-	return pod.Attributes
+	return identifiers
 }
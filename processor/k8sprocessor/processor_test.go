@@ -631,6 +631,102 @@ func TestMetricsProcessorHostname(t *testing.T) {
 
 }
 
+func TestPodAssociation(t *testing.T) {
+	next := new(consumertest.TracesSink)
+	var kp *kubernetesprocessor
+	p, err := newTraceProcessor(
+		NewFactory().CreateDefaultConfig(),
+		next,
+		WithExtractPodAssociations(
+			PodAssociationConfig{Name: conventions.AttributeK8sPodUID},
+			PodAssociationConfig{Name: conventions.AttributeHostName},
+		),
+		withExtractKubernetesProcessorInto(&kp),
+	)
+	require.NoError(t, err)
+	kc := kp.kc.(*fakeClient)
+
+	kc.PodsByUID["aaaa-bbbb"] = &kube.Pod{
+		Name:       "PodA",
+		Attributes: map[string]string{"pod": "a"},
+	}
+	kc.Pods["2.2.2.2"] = &kube.Pod{
+		Name:       "PodB",
+		Attributes: map[string]string{"pod": "b"},
+	}
+	kc.Pods["3.3.3.3"] = &kube.Pod{
+		Name:       "PodC",
+		Attributes: map[string]string{"pod": "c"},
+	}
+
+	testCases := []struct {
+		name          string
+		attrs         map[string]string
+		contextIP     string
+		expectedAttrs map[string]string
+	}{
+		{
+			name:      "uid",
+			attrs:     map[string]string{conventions.AttributeK8sPodUID: "aaaa-bbbb", conventions.AttributeHostName: "2.2.2.2"},
+			contextIP: "3.3.3.3",
+			expectedAttrs: map[string]string{
+				conventions.AttributeK8sPodUID: "aaaa-bbbb",
+				conventions.AttributeHostName:  "2.2.2.2",
+				k8sIPLabelName:                 "2.2.2.2",
+				"pod":                          "a",
+			},
+		},
+		{
+			name:      "unknown uid falls back to the next association",
+			attrs:     map[string]string{conventions.AttributeK8sPodUID: "cccc-dddd", conventions.AttributeHostName: "2.2.2.2"},
+			contextIP: "3.3.3.3",
+			expectedAttrs: map[string]string{
+				conventions.AttributeK8sPodUID: "cccc-dddd",
+				conventions.AttributeHostName:  "2.2.2.2",
+				k8sIPLabelName:                 "2.2.2.2",
+				"pod":                          "b",
+			},
+		},
+		{
+			name:      "connection IP",
+			attrs:     map[string]string{conventions.AttributeHostName: "not-an-ip"},
+			contextIP: "3.3.3.3",
+			expectedAttrs: map[string]string{
+				conventions.AttributeHostName: "not-an-ip",
+				k8sIPLabelName:                "3.3.3.3",
+				"pod":                         "c",
+			},
+		},
+		{
+			name:      "unknown pod is passed through unchanged",
+			attrs:     map[string]string{conventions.AttributeK8sPodUID: "cccc-dddd"},
+			contextIP: "3.3.3.3",
+			expectedAttrs: map[string]string{
+				conventions.AttributeK8sPodUID: "cccc-dddd",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := client.NewContext(context.Background(), &client.Client{IP: tc.contextIP})
+			traces := generateTraces(func(res pdata.Resource) {
+				for k, v := range tc.attrs {
+					res.Attributes().InsertString(k, v)
+				}
+			})
+			assert.NoError(t, p.ConsumeTraces(ctx, traces))
+			require.Len(t, next.AllTraces(), i+1)
+
+			res := next.AllTraces()[i].ResourceSpans().At(0).Resource()
+			assert.Equal(t, len(tc.expectedAttrs), res.Attributes().Len())
+			for k, v := range tc.expectedAttrs {
+				assertResourceHasStringAttribute(t, res, k, v)
+			}
+		})
+	}
+}
+
 func TestPassthroughStart(t *testing.T) {
 	next := new(consumertest.TracesSink)
 	opts := []Option{WithPassthrough()}
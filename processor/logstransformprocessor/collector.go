@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/observiq/stanza/entry"
+	"github.com/observiq/stanza/operator/helper"
+	"go.uber.org/zap"
+)
+
+// entryCollector is the last operator of the stanza pipeline, collecting the entries emitted by the
+// other operators until they are flushed
+type entryCollector struct {
+	helper.OutputOperator
+
+	sync.Mutex
+	entries []*entry.Entry
+}
+
+func newEntryCollector(logger *zap.SugaredLogger) *entryCollector {
+	return &entryCollector{
+		OutputOperator: helper.OutputOperator{
+			BasicOperator: helper.BasicOperator{
+				OperatorID:    "entry_collector",
+				OperatorType:  "entry_collector",
+				SugaredLogger: logger,
+			},
+		},
+	}
+}
+
+// Process collects the given entry. A parser handling an error with on_error=send emits the same entry twice,
+// as both the error handler and the parser write it, so the entries already collected are skipped.
+func (c *entryCollector) Process(_ context.Context, ent *entry.Entry) error {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, collected := range c.entries {
+		if collected == ent {
+			return nil
+		}
+	}
+	c.entries = append(c.entries, ent)
+	return nil
+}
+
+// flush returns the entries collected since the previous flush
+func (c *entryCollector) flush() []*entry.Entry {
+	c.Lock()
+	defer c.Unlock()
+
+	entries := c.entries
+	c.entries = nil
+	return entries
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"github.com/observiq/stanza/pipeline"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"gopkg.in/yaml.v2"
+)
+
+// Config defines the configuration for the processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Operators is the list of stanza operators the log records are run through, configured just like for
+	// the stanza receiver. Must include at least one operator, the first one receiving the log records.
+	Operators OperatorConfig `mapstructure:"operators"`
+}
+
+// OperatorConfig holds the configuration of the stanza operators as generic maps, as the operators
+// are unmarshaled from YAML rather than by mapstructure
+type OperatorConfig []map[string]interface{}
+
+// IntoPipelineConfig converts the operators to the stanza pipeline configuration
+func (r OperatorConfig) IntoPipelineConfig() (pipeline.Config, error) {
+	yamlBytes, err := yaml.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg pipeline.Config
+	if err := yaml.Unmarshal(yamlBytes, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
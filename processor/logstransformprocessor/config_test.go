@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factories.Processors[typeStr] = NewFactory()
+
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "logstransform",
+				NameVal: "logstransform",
+			},
+			Operators: OperatorConfig{
+				{
+					"type": "json_parser",
+					"severity": map[interface{}]interface{}{
+						"parse_from": "level",
+					},
+				},
+				{
+					"type": "metadata",
+					"labels": map[interface{}]interface{}{
+						"parsed": "true",
+					},
+				},
+			},
+		},
+		config.Processors["logstransform"])
+
+	pipelineConfig, err := config.Processors["logstransform"].(*Config).Operators.IntoPipelineConfig()
+	require.NoError(t, err)
+	require.Len(t, pipelineConfig, 2)
+	assert.Equal(t, "json_parser", pipelineConfig[0].Type())
+	assert.Equal(t, "metadata", pipelineConfig[1].Type())
+}
@@ -0,0 +1,280 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// toEntry converts the given log record to a stanza entry, with the given resource attributes, as strings
+func toEntry(resource map[string]string, lr pdata.LogRecord) *entry.Entry {
+	ent := &entry.Entry{
+		Timestamp:    time.Unix(0, int64(lr.Timestamp())),
+		Severity:     toEntrySeverity(lr.SeverityNumber()),
+		SeverityText: lr.SeverityText(),
+		Labels:       attributesToStrings(lr.Attributes()),
+		Resource:     make(map[string]string, len(resource)),
+		Record:       toEntryValue(lr.Body()),
+	}
+	for k, v := range resource {
+		ent.Resource[k] = v
+	}
+	return ent
+}
+
+// applyEntry applies the changes made by the operators to the entry converted from the original log record to
+// the given destination, which is a copy of the original log record. Only the changed parts are converted back,
+// so that the attributes and body left untouched keep their original types.
+func applyEntry(ent *entry.Entry, original pdata.LogRecord, dest pdata.LogRecord) {
+	if !ent.Timestamp.Equal(time.Unix(0, int64(original.Timestamp()))) {
+		dest.SetTimestamp(pdata.TimestampUnixNano(ent.Timestamp.UnixNano()))
+	}
+
+	if ent.Severity != toEntrySeverity(original.SeverityNumber()) || ent.SeverityText != original.SeverityText() {
+		dest.SetSeverityNumber(toSeverityNumber(ent.Severity))
+		if ent.SeverityText != "" {
+			dest.SetSeverityText(ent.SeverityText)
+		} else {
+			dest.SetSeverityText(ent.Severity.String())
+		}
+	}
+
+	if !reflect.DeepEqual(ent.Record, toEntryValue(original.Body())) {
+		insertToAttributeVal(ent.Record, dest.Body())
+	}
+
+	applyStrings(attributesToStrings(original.Attributes()), ent.Labels, dest.Attributes())
+}
+
+// applyStrings applies the changes from the original to the given string values to the attributes
+func applyStrings(original, changed map[string]string, attrs pdata.AttributeMap) {
+	for k := range original {
+		if _, ok := changed[k]; !ok {
+			attrs.Delete(k)
+		}
+	}
+	for k, v := range changed {
+		if ov, ok := original[k]; !ok || ov != v {
+			attrs.UpsertString(k, v)
+		}
+	}
+}
+
+// stringsEqual returns whether the given string maps hold the same values, a nil map being equal to an empty one
+func stringsEqual(m1, m2 map[string]string) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+	for k, v1 := range m1 {
+		if v2, ok := m2[k]; !ok || v1 != v2 {
+			return false
+		}
+	}
+	return true
+}
+
+func attributesToStrings(attrs pdata.AttributeMap) map[string]string {
+	if attrs.Len() == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		m[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+	return m
+}
+
+// toEntryValue converts the given attribute value to the types used by the stanza operators
+func toEntryValue(value pdata.AttributeValue) interface{} {
+	switch value.Type() {
+	case pdata.AttributeValueSTRING:
+		return value.StringVal()
+	case pdata.AttributeValueINT:
+		return value.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return value.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return value.BoolVal()
+	case pdata.AttributeValueMAP:
+		m := make(map[string]interface{}, value.MapVal().Len())
+		value.MapVal().ForEach(func(k string, v pdata.AttributeValue) {
+			m[k] = toEntryValue(v)
+		})
+		return m
+	case pdata.AttributeValueARRAY:
+		arr := make([]interface{}, 0, value.ArrayVal().Len())
+		for i := 0; i < value.ArrayVal().Len(); i++ {
+			arr = append(arr, toEntryValue(value.ArrayVal().At(i)))
+		}
+		return arr
+	default:
+		return nil
+	}
+}
+
+func insertToAttributeVal(value interface{}, dest pdata.AttributeValue) {
+	switch t := value.(type) {
+	case nil:
+		pdata.NewAttributeValueNull().CopyTo(dest)
+	case bool:
+		dest.SetBoolVal(t)
+	case string:
+		dest.SetStringVal(t)
+	case []byte:
+		dest.SetStringVal(string(t))
+	case int64:
+		dest.SetIntVal(t)
+	case int32:
+		dest.SetIntVal(int64(t))
+	case int16:
+		dest.SetIntVal(int64(t))
+	case int8:
+		dest.SetIntVal(int64(t))
+	case int:
+		dest.SetIntVal(int64(t))
+	case uint64:
+		dest.SetIntVal(int64(t))
+	case uint32:
+		dest.SetIntVal(int64(t))
+	case uint16:
+		dest.SetIntVal(int64(t))
+	case uint8:
+		dest.SetIntVal(int64(t))
+	case uint:
+		dest.SetIntVal(int64(t))
+	case float64:
+		dest.SetDoubleVal(t)
+	case float32:
+		dest.SetDoubleVal(float64(t))
+	case map[string]interface{}:
+		attVal := pdata.NewAttributeValueMap()
+		attMap := attVal.MapVal()
+		attMap.InitEmptyWithCapacity(len(t))
+		for k, v := range t {
+			subVal := pdata.NewAttributeValueNull()
+			insertToAttributeVal(v, subVal)
+			attMap.Insert(k, subVal)
+		}
+		attVal.CopyTo(dest)
+	case []interface{}:
+		arrVal := pdata.NewAttributeValueArray()
+		arr := arrVal.ArrayVal()
+		for _, v := range t {
+			subVal := pdata.NewAttributeValueNull()
+			insertToAttributeVal(v, subVal)
+			arr.Append(subVal)
+		}
+		arrVal.CopyTo(dest)
+	default:
+		dest.SetStringVal(fmt.Sprintf("%v", t))
+	}
+}
+
+var entrySeverities = map[pdata.SeverityNumber]entry.Severity{
+	pdata.SeverityNumberUNDEFINED: entry.Default,
+	pdata.SeverityNumberTRACE:     entry.Trace,
+	pdata.SeverityNumberTRACE2:    entry.Trace2,
+	pdata.SeverityNumberTRACE3:    entry.Trace3,
+	pdata.SeverityNumberTRACE4:    entry.Trace4,
+	pdata.SeverityNumberDEBUG:     entry.Debug,
+	pdata.SeverityNumberDEBUG2:    entry.Debug2,
+	pdata.SeverityNumberDEBUG3:    entry.Debug3,
+	pdata.SeverityNumberDEBUG4:    entry.Debug4,
+	pdata.SeverityNumberINFO:      entry.Info,
+	pdata.SeverityNumberINFO2:     entry.Info2,
+	pdata.SeverityNumberINFO3:     entry.Info3,
+	pdata.SeverityNumberINFO4:     entry.Info4,
+	pdata.SeverityNumberWARN:      entry.Warning,
+	pdata.SeverityNumberWARN2:     entry.Warning2,
+	pdata.SeverityNumberWARN3:     entry.Warning3,
+	pdata.SeverityNumberWARN4:     entry.Warning4,
+	pdata.SeverityNumberERROR:     entry.Error,
+	pdata.SeverityNumberERROR2:    entry.Error2,
+	pdata.SeverityNumberERROR3:    entry.Error3,
+	pdata.SeverityNumberERROR4:    entry.Error4,
+	pdata.SeverityNumberFATAL:     entry.Emergency,
+	pdata.SeverityNumberFATAL2:    entry.Emergency2,
+	pdata.SeverityNumberFATAL3:    entry.Emergency3,
+	pdata.SeverityNumberFATAL4:    entry.Emergency4,
+}
+
+func toEntrySeverity(s pdata.SeverityNumber) entry.Severity {
+	return entrySeverities[s]
+}
+
+func toSeverityNumber(s entry.Severity) pdata.SeverityNumber {
+	switch {
+	// Handle the severity levels with a matching severity number
+	case s == entry.Default:
+		return pdata.SeverityNumberUNDEFINED
+	case s == entry.Trace2:
+		return pdata.SeverityNumberTRACE2
+	case s == entry.Trace3:
+		return pdata.SeverityNumberTRACE3
+	case s == entry.Trace4:
+		return pdata.SeverityNumberTRACE4
+	case s == entry.Debug2:
+		return pdata.SeverityNumberDEBUG2
+	case s == entry.Debug3:
+		return pdata.SeverityNumberDEBUG3
+	case s == entry.Debug4:
+		return pdata.SeverityNumberDEBUG4
+	case s == entry.Info2:
+		return pdata.SeverityNumberINFO2
+	case s == entry.Info3:
+		return pdata.SeverityNumberINFO3
+	case s == entry.Info4, s == entry.Notice:
+		return pdata.SeverityNumberINFO4
+	case s == entry.Warning2:
+		return pdata.SeverityNumberWARN2
+	case s == entry.Warning3:
+		return pdata.SeverityNumberWARN3
+	case s == entry.Warning4:
+		return pdata.SeverityNumberWARN4
+	case s == entry.Error2:
+		return pdata.SeverityNumberERROR2
+	case s == entry.Error3:
+		return pdata.SeverityNumberERROR3
+	case s == entry.Error4:
+		return pdata.SeverityNumberERROR4
+	case s == entry.Emergency2:
+		return pdata.SeverityNumberFATAL2
+	case s == entry.Emergency3:
+		return pdata.SeverityNumberFATAL3
+	case s == entry.Emergency4, s >= entry.Catastrophe:
+		return pdata.SeverityNumberFATAL4
+
+	// Handle the other severity levels by range
+	case s >= entry.Critical:
+		return pdata.SeverityNumberFATAL
+	case s >= entry.Error:
+		return pdata.SeverityNumberERROR
+	case s >= entry.Warning:
+		return pdata.SeverityNumberWARN
+	case s >= entry.Info:
+		return pdata.SeverityNumberINFO
+	case s >= entry.Debug:
+		return pdata.SeverityNumberDEBUG
+	default:
+		return pdata.SeverityNumberTRACE
+	}
+}
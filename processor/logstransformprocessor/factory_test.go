@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+var params = component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+func TestCreateLogsProcessor(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Operators = OperatorConfig{
+		{"type": "json_parser"},
+	}
+
+	lp, err := createLogsProcessor(context.Background(), params, cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, lp)
+	assert.True(t, lp.GetCapabilities().MutatesConsumedData)
+
+	require.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, lp.Shutdown(context.Background()))
+}
+
+func TestCreateLogsProcessorWithInvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		operators OperatorConfig
+	}{
+		{"no operators", nil},
+		{"unknown operator", OperatorConfig{{"type": "unknown"}}},
+		{"input operators aren't registered", OperatorConfig{{"type": "file_input", "include": []string{"*.log"}}}},
+		{"invalid operator", OperatorConfig{{"type": "regex_parser", "regex": "("}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Operators = tc.operators
+
+			lp, err := createLogsProcessor(context.Background(), params, cfg, consumertest.NewLogsNop())
+			assert.Error(t, err)
+			assert.Nil(t, lp)
+		})
+	}
+}
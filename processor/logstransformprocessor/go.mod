@@ -0,0 +1,11 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/processor/logstransformprocessor
+
+go 1.14
+
+require (
+	github.com/observiq/stanza v0.13.9
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+	gopkg.in/yaml.v2 v2.4.0
+)
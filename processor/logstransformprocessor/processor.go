@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/observiq/stanza/database"
+	"github.com/observiq/stanza/entry"
+	"github.com/observiq/stanza/operator"
+	"github.com/observiq/stanza/pipeline"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+var errNoOperators = errors.New("option 'operators' must include at least one operator")
+
+// logsTransformProcessor runs the log records through a stanza pipeline. Each log record is converted to an entry
+// and sent to the first operator: as the registered operators process the entries synchronously, the entries
+// reaching the end of the pipeline are known once the log record has been processed, and are converted back
+// under the resource and instrumentation library of the log record, preserving the order of the records.
+type logsTransformProcessor struct {
+	logger *zap.Logger
+
+	pipeline      *pipeline.DirectedPipeline
+	firstOperator operator.Operator
+	collector     *entryCollector
+
+	// the lock serializes the batches, so that the collected entries can't be mixed up between them
+	lock sync.Mutex
+}
+
+// resourceGroup holds the log records emitted with the same resource, as seen by the operators
+type resourceGroup struct {
+	resource map[string]string
+	rl       pdata.ResourceLogs
+
+	// ills holds the instrumentation libraries of the group, by index of the originating instrumentation library
+	ills map[int]pdata.InstrumentationLibraryLogs
+}
+
+func newLogsTransformProcessor(logger *zap.Logger, config *Config) (*logsTransformProcessor, error) {
+	if len(config.Operators) == 0 {
+		return nil, errNoOperators
+	}
+
+	pipelineConfig, err := config.Operators.IntoPipelineConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	collector := newEntryCollector(logger.Sugar())
+	buildContext := operator.NewBuildContext(database.NewStubDatabase(), logger.Sugar())
+	p, err := pipelineConfig.BuildPipeline(buildContext, collector)
+	if err != nil {
+		return nil, err
+	}
+
+	firstID := buildContext.PrependNamespace(pipelineConfig[0].ID())
+	var firstOperator operator.Operator
+	for _, op := range p.Operators() {
+		if op.ID() == firstID {
+			firstOperator = op
+		}
+	}
+	if firstOperator == nil || !firstOperator.CanProcess() {
+		return nil, fmt.Errorf("the first operator %q must be able to process entries", firstID)
+	}
+
+	return &logsTransformProcessor{
+		logger:        logger,
+		pipeline:      p,
+		firstOperator: firstOperator,
+		collector:     collector,
+	}, nil
+}
+
+// Start starts the operators of the stanza pipeline
+func (ltp *logsTransformProcessor) Start(context.Context, component.Host) error {
+	return ltp.pipeline.Start()
+}
+
+// Shutdown stops the operators of the stanza pipeline
+func (ltp *logsTransformProcessor) Shutdown(context.Context) error {
+	return ltp.pipeline.Stop()
+}
+
+// ProcessLogs runs each log record through the operators. The log records whose resource was changed by the
+// operators are moved to a new resource, right after the original one.
+func (ltp *logsTransformProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	ltp.lock.Lock()
+	defer ltp.lock.Unlock()
+
+	out := pdata.NewLogs()
+	var numErrors int
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resource := attributesToStrings(rl.Resource().Attributes())
+
+		var groups []*resourceGroup
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			for k := 0; k < ill.Logs().Len(); k++ {
+				record := ill.Logs().At(k)
+
+				// an error doesn't prevent the entry from being emitted, depending on the on_error setting
+				// of the operator, so that the entries are collected in any case
+				if err := ltp.firstOperator.Process(ctx, toEntry(resource, record)); err != nil {
+					numErrors++
+					ltp.logger.Debug("failed to process log record", zap.Error(err))
+				}
+
+				for _, ent := range ltp.collector.flush() {
+					group := groupForEntry(&groups, out, rl.Resource(), resource, ent)
+
+					outILL, ok := group.ills[j]
+					if !ok {
+						outILL = pdata.NewInstrumentationLibraryLogs()
+						ill.InstrumentationLibrary().CopyTo(outILL.InstrumentationLibrary())
+						group.rl.InstrumentationLibraryLogs().Append(outILL)
+						group.ills[j] = outILL
+					}
+
+					outRecord := pdata.NewLogRecord()
+					record.CopyTo(outRecord)
+					applyEntry(ent, record, outRecord)
+					outILL.Logs().Append(outRecord)
+				}
+			}
+		}
+	}
+
+	if numErrors > 0 {
+		ltp.logger.Warn("the operators failed to process some log records", zap.Int("count", numErrors))
+	}
+
+	return out, nil
+}
+
+// groupForEntry returns the group for the resource of the given entry, creating it when needed
+func groupForEntry(groups *[]*resourceGroup, out pdata.Logs, original pdata.Resource, resource map[string]string, ent *entry.Entry) *resourceGroup {
+	for _, group := range *groups {
+		if stringsEqual(group.resource, ent.Resource) {
+			return group
+		}
+	}
+
+	rl := pdata.NewResourceLogs()
+	original.CopyTo(rl.Resource())
+	applyStrings(resource, ent.Resource, rl.Resource().Attributes())
+	out.ResourceLogs().Append(rl)
+
+	group := &resourceGroup{
+		resource: ent.Resource,
+		rl:       rl,
+		ills:     map[int]pdata.InstrumentationLibraryLogs{},
+	}
+	*groups = append(*groups, group)
+	return group
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestParseJSONBodies(t *testing.T) {
+	// prepare
+	ltp := startedProcessor(t, OperatorConfig{
+		{
+			"type":     "json_parser",
+			"severity": map[string]interface{}{"parse_from": "level"},
+		},
+		{
+			"type": "restructure",
+			"ops": []interface{}{
+				map[string]interface{}{"move": map[string]interface{}{"from": "user", "to": "$labels.user"}},
+			},
+		},
+	})
+
+	ld := pdata.NewLogs()
+	rl := pdata.NewResourceLogs()
+	rl.Resource().Attributes().InsertString("service.name", "some-service")
+	ill := pdata.NewInstrumentationLibraryLogs()
+	ill.InstrumentationLibrary().SetName("some-library")
+	for _, body := range []string{
+		`{"message": "first", "level": "error", "user": "alice"}`,
+		`not json`,
+		`{"message": "third", "level": "info", "user": "bob"}`,
+	} {
+		lr := pdata.NewLogRecord()
+		lr.SetName("some-record")
+		lr.SetTimestamp(pdata.TimestampUnixNano(time.Second))
+		lr.Body().SetStringVal(body)
+		lr.Attributes().InsertInt("attempt", 1)
+		ill.Logs().Append(lr)
+	}
+	rl.InstrumentationLibraryLogs().Append(ill)
+	ld.ResourceLogs().Append(rl)
+
+	// test
+	out, err := ltp.ProcessLogs(context.Background(), ld)
+
+	// verify
+	require.NoError(t, err)
+	require.Equal(t, 1, out.ResourceLogs().Len())
+	resource := out.ResourceLogs().At(0).Resource()
+	serviceName, ok := resource.Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "some-service", serviceName.StringVal())
+
+	require.Equal(t, 1, out.ResourceLogs().At(0).InstrumentationLibraryLogs().Len())
+	outILL := out.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0)
+	assert.Equal(t, "some-library", outILL.InstrumentationLibrary().Name())
+
+	// the failing record is kept, in its original order
+	records := outILL.Logs()
+	require.Equal(t, 3, records.Len())
+
+	first := records.At(0)
+	assert.Equal(t, "some-record", first.Name())
+	assert.Equal(t, pdata.TimestampUnixNano(time.Second), first.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberERROR, first.SeverityNumber())
+	assert.Equal(t, "error", first.SeverityText())
+	require.Equal(t, pdata.AttributeValueMAP, first.Body().Type())
+	message, ok := first.Body().MapVal().Get("message")
+	require.True(t, ok)
+	assert.Equal(t, "first", message.StringVal())
+	_, ok = first.Body().MapVal().Get("user")
+	assert.False(t, ok)
+	user, ok := first.Attributes().Get("user")
+	require.True(t, ok)
+	assert.Equal(t, "alice", user.StringVal())
+
+	// the untouched attributes keep their types
+	attempt, ok := first.Attributes().Get("attempt")
+	require.True(t, ok)
+	assert.Equal(t, pdata.AttributeValueINT, attempt.Type())
+
+	second := records.At(1)
+	assert.Equal(t, "not json", second.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, second.SeverityNumber())
+	assert.Equal(t, 1, second.Attributes().Len())
+
+	third := records.At(2)
+	assert.Equal(t, pdata.SeverityNumberINFO, third.SeverityNumber())
+	user, ok = third.Attributes().Get("user")
+	require.True(t, ok)
+	assert.Equal(t, "bob", user.StringVal())
+}
+
+func TestChangedResourcesAndFilteredRecords(t *testing.T) {
+	// prepare
+	ltp := startedProcessor(t, OperatorConfig{
+		{
+			"type": "filter",
+			"expr": `$record == "drop"`,
+		},
+		{
+			"type": "router",
+			"routes": []interface{}{
+				map[string]interface{}{
+					"expr":   `$record == "prod"`,
+					"output": "prod",
+				},
+			},
+			"default": "noop",
+		},
+		{
+			"id":       "prod",
+			"type":     "metadata",
+			"resource": map[string]interface{}{"env": "prod"},
+		},
+		{
+			"type": "noop",
+		},
+	})
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertInt("pid", 123)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	for _, body := range []string{"first", "drop", "prod", "last"} {
+		lr := pdata.NewLogRecord()
+		lr.Body().SetStringVal(body)
+		rl.InstrumentationLibraryLogs().At(0).Logs().Append(lr)
+	}
+
+	// test
+	out, err := ltp.ProcessLogs(context.Background(), ld)
+
+	// verify
+	require.NoError(t, err)
+	require.Equal(t, 2, out.ResourceLogs().Len())
+
+	// the records with the original resource come first, keeping their relative order
+	original := out.ResourceLogs().At(0)
+	assert.Equal(t, 1, original.Resource().Attributes().Len())
+	pid, ok := original.Resource().Attributes().Get("pid")
+	require.True(t, ok)
+	assert.Equal(t, pdata.AttributeValueINT, pid.Type())
+	records := original.InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 2, records.Len())
+	assert.Equal(t, "first", records.At(0).Body().StringVal())
+	assert.Equal(t, "last", records.At(1).Body().StringVal())
+
+	changed := out.ResourceLogs().At(1)
+	assert.Equal(t, 2, changed.Resource().Attributes().Len())
+	env, ok := changed.Resource().Attributes().Get("env")
+	require.True(t, ok)
+	assert.Equal(t, "prod", env.StringVal())
+	records = changed.InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 1, records.Len())
+	assert.Equal(t, "prod", records.At(0).Body().StringVal())
+}
+
+func startedProcessor(t *testing.T, operators OperatorConfig) *logsTransformProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Operators = operators
+
+	ltp, err := newLogsTransformProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, ltp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, ltp.Shutdown(context.Background()))
+	})
+	return ltp
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// GenerationType is the way a new metric is generated: <calculate|scale>.
+type GenerationType string
+
+const (
+	// CalculateGenerationType generates the new metric by applying the operation to metric1 and metric2.
+	CalculateGenerationType GenerationType = "calculate"
+
+	// ScaleGenerationType generates the new metric by applying the operation to metric1 and the scale factor.
+	ScaleGenerationType GenerationType = "scale"
+)
+
+// OperationType is the arithmetic operation applied to generate the new metric:
+// <add|subtract|multiply|divide|percent>.
+type OperationType string
+
+const (
+	// AddOperation adds the operands.
+	AddOperation OperationType = "add"
+
+	// SubtractOperation subtracts the second operand from the first one.
+	SubtractOperation OperationType = "subtract"
+
+	// MultiplyOperation multiplies the operands.
+	MultiplyOperation OperationType = "multiply"
+
+	// DivideOperation divides the first operand by the second one.
+	DivideOperation OperationType = "divide"
+
+	// PercentOperation computes the first operand as a percentage of the second one.
+	PercentOperation OperationType = "percent"
+)
+
+// Rule defines how a new metric is generated.
+type Rule struct {
+	// Name is the name of the new metric.
+	Name string `mapstructure:"name"`
+
+	// Unit is the unit of the new metric.
+	Unit string `mapstructure:"unit"`
+
+	// Type is the way the new metric is generated: <calculate|scale>.
+	Type GenerationType `mapstructure:"type"`
+
+	// Metric1 is the name of the metric used as the first operand.
+	Metric1 string `mapstructure:"metric1"`
+
+	// Metric2 is the name of the metric used as the second operand, for the calculate type.
+	Metric2 string `mapstructure:"metric2"`
+
+	// ScaleBy is the second operand, for the scale type.
+	ScaleBy float64 `mapstructure:"scale_by"`
+
+	// Operation is the arithmetic operation applied to the operands: <add|subtract|multiply|divide|percent>.
+	Operation OperationType `mapstructure:"operation"`
+}
+
+// Config defines the configuration for the processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Rules is the list of the rules generating the new metrics, applied in order: a rule can use the metrics
+	// generated by the previous ones.
+	Rules []Rule `mapstructure:"rules"`
+}
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	assert.Empty(t, cfg.Rules)
+}
+
+func TestCreateMetricsProcessor(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Rules = []Rule{{Name: "new", Type: ScaleGenerationType, Metric1: "old", ScaleBy: 2, Operation: MultiplyOperation}}
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, mp)
+	assert.True(t, mp.GetCapabilities().MutatesConsumedData)
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+	assert.Error(t, err)
+	assert.Nil(t, tp)
+}
+
+func TestCreateMetricsProcessorWithInvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		rule *Rule
+	}{
+		{"no rules", nil},
+		{"no name", &Rule{Type: ScaleGenerationType, Metric1: "old", ScaleBy: 2, Operation: MultiplyOperation}},
+		{"no metric1", &Rule{Name: "new", Type: ScaleGenerationType, ScaleBy: 2, Operation: MultiplyOperation}},
+		{"no metric2", &Rule{Name: "new", Type: CalculateGenerationType, Metric1: "old", Operation: AddOperation}},
+		{"no scale factor", &Rule{Name: "new", Type: ScaleGenerationType, Metric1: "old", Operation: MultiplyOperation}},
+		{"invalid type", &Rule{Name: "new", Type: "copy", Metric1: "old", Operation: AddOperation}},
+		{"invalid operation", &Rule{Name: "new", Type: ScaleGenerationType, Metric1: "old", ScaleBy: 2, Operation: "modulo"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			if tc.rule != nil {
+				cfg.Rules = []Rule{*tc.rule}
+			}
+
+			mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
+			assert.Error(t, err)
+			assert.Nil(t, mp)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// labelsKeySeparator separates the keys and values in the labels keys
+const labelsKeySeparator = string(byte(0))
+
+var (
+	errAtLeastOneRuleNeeded = errors.New("option 'rules' must include at least one rule")
+	errMissingName          = errors.New("the name of the new metric is required")
+	errMissingMetric1       = errors.New("option 'metric1' is required")
+	errMissingMetric2       = errors.New("option 'metric2' is required for the calculate type")
+	errMissingScaleBy       = errors.New("option 'scale_by' is required for the scale type")
+)
+
+type metricsGenerationProcessor struct {
+	logger *zap.Logger
+	rules  []Rule
+}
+
+func newMetricsGenerationProcessor(logger *zap.Logger, config *Config) (*metricsGenerationProcessor, error) {
+	if len(config.Rules) == 0 {
+		return nil, errAtLeastOneRuleNeeded
+	}
+	for _, rule := range config.Rules {
+		if err := validateRule(rule); err != nil {
+			return nil, fmt.Errorf("invalid rule %q: %w", rule.Name, err)
+		}
+	}
+
+	return &metricsGenerationProcessor{
+		logger: logger,
+		rules:  config.Rules,
+	}, nil
+}
+
+func validateRule(rule Rule) error {
+	if rule.Name == "" {
+		return errMissingName
+	}
+	if rule.Metric1 == "" {
+		return errMissingMetric1
+	}
+
+	switch rule.Type {
+	case CalculateGenerationType:
+		if rule.Metric2 == "" {
+			return errMissingMetric2
+		}
+	case ScaleGenerationType:
+		if rule.ScaleBy == 0 {
+			return errMissingScaleBy
+		}
+	default:
+		return fmt.Errorf("invalid type %q, must be one of %q or %q", rule.Type, CalculateGenerationType, ScaleGenerationType)
+	}
+
+	switch rule.Operation {
+	case AddOperation, SubtractOperation, MultiplyOperation, DivideOperation, PercentOperation:
+	default:
+		return fmt.Errorf("invalid operation %q, must be one of %q, %q, %q, %q or %q", rule.Operation,
+			AddOperation, SubtractOperation, MultiplyOperation, DivideOperation, PercentOperation)
+	}
+	return nil
+}
+
+// ProcessMetrics applies the rules to each resource. The new metrics are appended to the instrumentation library
+// of each metric1 they are generated from, while the metric2 operands are looked up within the same resource.
+func (mgp *metricsGenerationProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		for _, rule := range mgp.rules {
+			mgp.applyRule(rule, rm)
+		}
+	}
+	return md, nil
+}
+
+// applyRule generates the new metric of the given rule for each metric1 of the given resource
+func (mgp *metricsGenerationProcessor) applyRule(rule Rule, rm pdata.ResourceMetrics) {
+	var operands map[string]float64
+	if rule.Type == CalculateGenerationType {
+		operands = findValues(rm, rule.Metric2)
+		if len(operands) == 0 {
+			return
+		}
+	}
+
+	ilms := rm.InstrumentationLibraryMetrics()
+	for i := 0; i < ilms.Len(); i++ {
+		metrics := ilms.At(i).Metrics()
+
+		// the generated metrics aren't candidates for the same rule
+		numMetrics := metrics.Len()
+		for j := 0; j < numMetrics; j++ {
+			metric := metrics.At(j)
+			if metric.Name() != rule.Metric1 {
+				continue
+			}
+
+			generated := mgp.generate(rule, metric, operands)
+			if generated.DoubleGauge().DataPoints().Len() > 0 {
+				metrics.Append(generated)
+			}
+		}
+	}
+}
+
+// generate returns the new metric of the given rule, generated from the given metric1. For the calculate type,
+// each data point is matched with the metric2 value having the same labels, the data points without any match
+// being skipped.
+func (mgp *metricsGenerationProcessor) generate(rule Rule, metric1 pdata.Metric, operands map[string]float64) pdata.Metric {
+	generated := pdata.NewMetric()
+	generated.SetName(rule.Name)
+	generated.SetUnit(rule.Unit)
+	generated.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	dps := generated.DoubleGauge().DataPoints()
+
+	forEachValue(metric1, func(labels pdata.StringMap, startTime, timestamp pdata.TimestampUnixNano, value float64) {
+		operand := rule.ScaleBy
+		if rule.Type == CalculateGenerationType {
+			var ok bool
+			if operand, ok = operands[labelsKey(labels)]; !ok {
+				return
+			}
+		}
+
+		result, ok := calculate(rule.Operation, value, operand)
+		if !ok {
+			mgp.logger.Debug("division by zero, dropping the data point", zap.String("metric", rule.Name))
+			return
+		}
+
+		dp := pdata.NewDoubleDataPoint()
+		labels.CopyTo(dp.LabelsMap())
+		dp.SetStartTime(startTime)
+		dp.SetTimestamp(timestamp)
+		dp.SetValue(result)
+		dps.Append(dp)
+	})
+
+	return generated
+}
+
+// calculate applies the given operation to the operands, returning false on a division by zero
+func calculate(operation OperationType, value1, value2 float64) (float64, bool) {
+	switch operation {
+	case AddOperation:
+		return value1 + value2, true
+	case SubtractOperation:
+		return value1 - value2, true
+	case MultiplyOperation:
+		return value1 * value2, true
+	case DivideOperation:
+		if value2 == 0 {
+			return 0, false
+		}
+		return value1 / value2, true
+	case PercentOperation:
+		if value2 == 0 {
+			return 0, false
+		}
+		return value1 / value2 * 100, true
+	}
+	return 0, false
+}
+
+// findValues returns the values of the data points of the metrics with the given name, keyed by their labels.
+// When several data points have the same labels, the first one is used.
+func findValues(rm pdata.ResourceMetrics, name string) map[string]float64 {
+	values := map[string]float64{}
+
+	ilms := rm.InstrumentationLibraryMetrics()
+	for i := 0; i < ilms.Len(); i++ {
+		metrics := ilms.At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			metric := metrics.At(j)
+			if metric.Name() != name {
+				continue
+			}
+
+			forEachValue(metric, func(labels pdata.StringMap, _, _ pdata.TimestampUnixNano, value float64) {
+				key := labelsKey(labels)
+				if _, ok := values[key]; !ok {
+					values[key] = value
+				}
+			})
+		}
+	}
+
+	return values
+}
+
+// forEachValue calls the given function for each data point of the given gauge or sum, the other types of metrics
+// not having a single value per data point
+func forEachValue(metric pdata.Metric, f func(labels pdata.StringMap, startTime, timestamp pdata.TimestampUnixNano, value float64)) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		dps := metric.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			f(dp.LabelsMap(), dp.StartTime(), dp.Timestamp(), float64(dp.Value()))
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		dps := metric.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			f(dp.LabelsMap(), dp.StartTime(), dp.Timestamp(), dp.Value())
+		}
+	case pdata.MetricDataTypeIntSum:
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			f(dp.LabelsMap(), dp.StartTime(), dp.Timestamp(), float64(dp.Value()))
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			f(dp.LabelsMap(), dp.StartTime(), dp.Timestamp(), dp.Value())
+		}
+	}
+}
+
+// labelsKey returns a key identifying the given labels, regardless of their order
+func labelsKey(labels pdata.StringMap) string {
+	kvs := make([]string, 0, labels.Len())
+	labels.ForEach(func(k string, v string) {
+		kvs = append(kvs, k+labelsKeySeparator+v)
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, labelsKeySeparator)
+}
@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// testPoint is a data point of a test metric, with its timestamp in seconds
+type testPoint struct {
+	labels    map[string]string
+	timestamp int64
+	value     float64
+}
+
+func TestCalculate(t *testing.T) {
+	for _, tc := range []struct {
+		operation OperationType
+		expected  float64
+	}{
+		{AddOperation, 50},
+		{SubtractOperation, 30},
+		{MultiplyOperation, 400},
+		{DivideOperation, 4},
+		{PercentOperation, 400},
+	} {
+		t.Run(string(tc.operation), func(t *testing.T) {
+			// prepare
+			mgp := newTestProcessor(t, Rule{
+				Name:      "pod.memory.utilization",
+				Unit:      "1",
+				Type:      CalculateGenerationType,
+				Metric1:   "pod.memory.usage",
+				Metric2:   "node.memory.allocatable",
+				Operation: tc.operation,
+			})
+
+			md := pdata.NewMetrics()
+			ilm := appendILM(md)
+			appendIntGauge(ilm, "pod.memory.usage", testPoint{timestamp: 10, value: 40})
+			appendDoubleGauge(ilm, "node.memory.allocatable", testPoint{timestamp: 10, value: 10})
+
+			// test
+			out, err := mgp.ProcessMetrics(context.Background(), md)
+
+			// verify
+			require.NoError(t, err)
+			metrics := out.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			require.Equal(t, 3, metrics.Len())
+			generated := metrics.At(2)
+			assert.Equal(t, "pod.memory.utilization", generated.Name())
+			assert.Equal(t, "1", generated.Unit())
+			require.Equal(t, pdata.MetricDataTypeDoubleGauge, generated.DataType())
+			require.Equal(t, 1, generated.DoubleGauge().DataPoints().Len())
+			dp := generated.DoubleGauge().DataPoints().At(0)
+			assert.Equal(t, tc.expected, dp.Value())
+			assert.Equal(t, seconds(10), dp.Timestamp())
+		})
+	}
+}
+
+func TestCalculateMatchesLabels(t *testing.T) {
+	// prepare
+	mgp := newTestProcessor(t, Rule{
+		Name:      "disk.utilization",
+		Type:      CalculateGenerationType,
+		Metric1:   "disk.used",
+		Metric2:   "disk.total",
+		Operation: PercentOperation,
+	})
+
+	md := pdata.NewMetrics()
+	ilm := appendILM(md)
+	appendDoubleGauge(ilm, "disk.used",
+		testPoint{labels: map[string]string{"device": "sda"}, timestamp: 10, value: 25},
+		testPoint{labels: map[string]string{"device": "sdb"}, timestamp: 10, value: 10},
+		testPoint{labels: map[string]string{"device": "sdc"}, timestamp: 10, value: 10},
+	)
+	appendDoubleGauge(ilm, "disk.total",
+		testPoint{labels: map[string]string{"device": "sdb"}, timestamp: 10, value: 0},
+		testPoint{labels: map[string]string{"device": "sda"}, timestamp: 10, value: 50},
+	)
+
+	// test
+	out, err := mgp.ProcessMetrics(context.Background(), md)
+
+	// verify
+	require.NoError(t, err)
+	metrics := out.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+
+	// the division by zero for sdb is dropped, and sdc has no matching total
+	dps := metrics.At(2).DoubleGauge().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	assert.Equal(t, 50.0, dps.At(0).Value())
+	device, ok := dps.At(0).LabelsMap().Get("device")
+	require.True(t, ok)
+	assert.Equal(t, "sda", device)
+}
+
+func TestScale(t *testing.T) {
+	// prepare
+	mgp := newTestProcessor(t,
+		Rule{
+			Name:      "memory.usage.megabytes",
+			Unit:      "MBy",
+			Type:      ScaleGenerationType,
+			Metric1:   "memory.usage",
+			ScaleBy:   1024 * 1024,
+			Operation: DivideOperation,
+		},
+		// rules can use the metrics generated by the previous ones
+		Rule{
+			Name:      "memory.usage.gigabytes",
+			Unit:      "GBy",
+			Type:      ScaleGenerationType,
+			Metric1:   "memory.usage.megabytes",
+			ScaleBy:   1024,
+			Operation: DivideOperation,
+		})
+
+	md := pdata.NewMetrics()
+	ilm := appendILM(md)
+	appendIntGauge(ilm, "memory.usage", testPoint{timestamp: 10, value: 2 * 1024 * 1024 * 1024})
+
+	// test
+	out, err := mgp.ProcessMetrics(context.Background(), md)
+
+	// verify
+	require.NoError(t, err)
+	metrics := out.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+	assert.Equal(t, "memory.usage.megabytes", metrics.At(1).Name())
+	assert.Equal(t, 2048.0, metrics.At(1).DoubleGauge().DataPoints().At(0).Value())
+	assert.Equal(t, "memory.usage.gigabytes", metrics.At(2).Name())
+	assert.Equal(t, "GBy", metrics.At(2).Unit())
+	assert.Equal(t, 2.0, metrics.At(2).DoubleGauge().DataPoints().At(0).Value())
+}
+
+func TestMetric2IsLookedUpWithinTheResource(t *testing.T) {
+	// prepare
+	mgp := newTestProcessor(t, Rule{
+		Name:      "pod.memory.utilization",
+		Type:      CalculateGenerationType,
+		Metric1:   "pod.memory.usage",
+		Metric2:   "node.memory.allocatable",
+		Operation: DivideOperation,
+	})
+
+	md := pdata.NewMetrics()
+	// the pod and the node metrics of different resources aren't matched
+	appendIntGauge(appendILM(md), "pod.memory.usage", testPoint{timestamp: 10, value: 40})
+	appendIntGauge(appendILM(md), "node.memory.allocatable", testPoint{timestamp: 10, value: 10})
+	ilm := appendILM(md)
+	appendIntGauge(ilm, "pod.memory.usage", testPoint{timestamp: 10, value: 40})
+	appendIntGauge(ilm, "node.memory.allocatable", testPoint{timestamp: 10, value: 20})
+
+	// test
+	out, err := mgp.ProcessMetrics(context.Background(), md)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, 5, out.MetricCount())
+	metrics := out.ResourceMetrics().At(2).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+	assert.Equal(t, 2.0, metrics.At(2).DoubleGauge().DataPoints().At(0).Value())
+}
+
+func newTestProcessor(t *testing.T, rules ...Rule) *metricsGenerationProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Rules = rules
+	mgp, err := newMetricsGenerationProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	return mgp
+}
+
+// appendILM appends a new resource to the given metrics, returning its instrumentation library
+func appendILM(md pdata.Metrics) pdata.InstrumentationLibraryMetrics {
+	rm := pdata.NewResourceMetrics()
+	ilm := pdata.NewInstrumentationLibraryMetrics()
+	rm.InstrumentationLibraryMetrics().Append(ilm)
+	md.ResourceMetrics().Append(rm)
+	return ilm
+}
+
+func appendIntGauge(ilm pdata.InstrumentationLibraryMetrics, name string, points ...testPoint) {
+	metric := pdata.NewMetric()
+	metric.SetName(name)
+	metric.SetDataType(pdata.MetricDataTypeIntGauge)
+	for _, point := range points {
+		dp := pdata.NewIntDataPoint()
+		dp.LabelsMap().InitFromMap(point.labels)
+		dp.SetTimestamp(seconds(point.timestamp))
+		dp.SetValue(int64(point.value))
+		metric.IntGauge().DataPoints().Append(dp)
+	}
+	ilm.Metrics().Append(metric)
+}
+
+func appendDoubleGauge(ilm pdata.InstrumentationLibraryMetrics, name string, points ...testPoint) {
+	metric := pdata.NewMetric()
+	metric.SetName(name)
+	metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	for _, point := range points {
+		dp := pdata.NewDoubleDataPoint()
+		dp.LabelsMap().InitFromMap(point.labels)
+		dp.SetTimestamp(seconds(point.timestamp))
+		dp.SetValue(point.value)
+		metric.DoubleGauge().DataPoints().Append(dp)
+	}
+	ilm.Metrics().Append(metric)
+}
+
+func seconds(s int64) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(s * 1e9)
+}
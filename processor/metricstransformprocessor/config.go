@@ -51,6 +51,9 @@ const (
 
 	// SubmatchCaseFieldName is the mapstructure field name for SubmatchCase field
 	SubmatchCaseFieldName = "submatch_case"
+
+	// ScaleFieldName is the mapstructure field name for Scale field
+	ScaleFieldName = "experimental_scale"
 )
 
 // Config defines configuration for Resource processor.
@@ -131,6 +134,9 @@ type Operation struct {
 
 	// LabelValue identifies the exact label value to operate on
 	LabelValue string `mapstructure:"label_value"`
+
+	// Scale is the factor to multiply the values of the data points by, when the operation is `ScaleValue`.
+	Scale float64 `mapstructure:"experimental_scale"`
 }
 
 // ValueAction renames label values.
@@ -194,9 +200,12 @@ const (
 	// AggregateLabelValues aggregates away the values in Operation.AggregatedValues
 	// by the method indicated by Operation.AggregationType.
 	AggregateLabelValues OperationAction = "aggregate_label_values"
+
+	// ScaleValue multiplies the values of the data points by Operation.Scale.
+	ScaleValue OperationAction = "experimental_scale_value"
 )
 
-var OperationActions = []OperationAction{AddLabel, UpdateLabel, DeleteLabelValue, ToggleScalarDataType, AggregateLabels, AggregateLabelValues}
+var OperationActions = []OperationAction{AddLabel, UpdateLabel, DeleteLabelValue, ToggleScalarDataType, AggregateLabels, AggregateLabelValues, ScaleValue}
 
 func (oa OperationAction) isValid() bool {
 	for _, operationAction := range OperationActions {
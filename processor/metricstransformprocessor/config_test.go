@@ -116,6 +116,10 @@ func TestLoadingFullConfig(t *testing.T) {
 								Label:      "my_label",
 								LabelValue: "delete_me",
 							},
+							{
+								Action: "experimental_scale_value",
+								Scale:  1000,
+							},
 						},
 					},
 					{
@@ -63,7 +63,7 @@ func (mtp *metricsTransformProcessor) groupPointsByTimestamp(timeseries []*metri
 	var startTimestamp *timestamppb.Timestamp
 	timestampToPoints := make(map[int64][]*metricspb.Point)
 	for _, ts := range timeseries {
-		if startTimestamp == nil {
+		if mtp.compareTimestamps(ts.StartTimestamp, startTimestamp) {
 			startTimestamp = ts.StartTimestamp
 		}
 		for _, p := range ts.Points {
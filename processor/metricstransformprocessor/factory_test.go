@@ -114,6 +114,11 @@ func TestCreateProcessors(t *testing.T) {
 			succeed:      false,
 			errorMessage: fmt.Sprintf("operation %v: %q must be in %q", 1, AggregationTypeFieldName, AggregationTypes),
 		},
+		{
+			configName:   "config_invalid_scale.yaml",
+			succeed:      false,
+			errorMessage: fmt.Sprintf("operation %v: %q must be positive while %q is %v", 1, ScaleFieldName, ActionFieldName, ScaleValue),
+		},
 		{
 			configName:   "config_invalid_submatchcase.yaml",
 			succeed:      false,
@@ -364,6 +364,8 @@ func (mtp *metricsTransformProcessor) update(match *match, transform internalTra
 			mtp.addLabelOp(match.metric, op)
 		case DeleteLabelValue:
 			mtp.deleteLabelValueOp(match.metric, op)
+		case ScaleValue:
+			mtp.scaleValueOp(match.metric, op)
 		}
 	}
 }
@@ -30,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestMetricsTransformProcessor(t *testing.T) {
@@ -148,6 +149,20 @@ func calculateSumOfSquaredDeviation(slice []float64) (sum float64, sumOfSquaredD
 	return
 }
 
+func TestGroupPointsByTimestampMinimumStartTimestamp(t *testing.T) {
+	p := newMetricsTransformProcessor(nil, nil)
+	timeseries := []*metricspb.TimeSeries{
+		{StartTimestamp: &timestamppb.Timestamp{Seconds: 1, Nanos: 500}},
+		{StartTimestamp: nil},
+		{StartTimestamp: &timestamppb.Timestamp{Seconds: 1, Nanos: 100}},
+		{StartTimestamp: &timestamppb.Timestamp{Seconds: 1, Nanos: 300}},
+	}
+
+	_, startTimestamp := p.groupPointsByTimestamp(timeseries)
+
+	assert.Same(t, timeseries[2].StartTimestamp, startTimestamp)
+}
+
 func TestExemplars(t *testing.T) {
 	p := newMetricsTransformProcessor(nil, nil)
 	exe1 := &metricspb.DistributionValue_Exemplar{Value: 1}
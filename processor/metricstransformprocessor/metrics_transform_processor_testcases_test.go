@@ -1219,6 +1219,97 @@ var (
 			},
 		},
 		// Toggle Data Type
+		{
+			name: "metric_experimental_scale_value_int64",
+			transforms: []internalTransform{
+				{
+					MetricIncludeFilter: internalFilterStrict{include: "metric1"},
+					Action:              Update,
+					Operations: []internalOperation{
+						{
+							configOperation: Operation{
+								Action: ScaleValue,
+								Scale:  0.001,
+							},
+						},
+					},
+				},
+			},
+			in: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setLabels([]string{"label1"}).setDataType(metricspb.MetricDescriptor_CUMULATIVE_INT64).
+					addTimeseries(1, []string{"value1"}).
+					addTimeseries(1, []string{"value2"}).
+					addInt64Point(0, 1500, 2).
+					addInt64Point(1, 1499, 2).
+					build(),
+			},
+			out: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setLabels([]string{"label1"}).setDataType(metricspb.MetricDescriptor_CUMULATIVE_INT64).
+					addTimeseries(1, []string{"value1"}).
+					addTimeseries(1, []string{"value2"}).
+					addInt64Point(0, 2, 2).
+					addInt64Point(1, 1, 2).
+					build(),
+			},
+		},
+		{
+			name: "metric_experimental_scale_value_double",
+			transforms: []internalTransform{
+				{
+					MetricIncludeFilter: internalFilterStrict{include: "metric1"},
+					Action:              Update,
+					Operations: []internalOperation{
+						{
+							configOperation: Operation{
+								Action: ScaleValue,
+								Scale:  1000,
+							},
+						},
+					},
+				},
+			},
+			in: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_GAUGE_DOUBLE).
+					addTimeseries(1, []string{}).
+					addDoublePoint(0, 0.25, 2).
+					build(),
+			},
+			out: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_GAUGE_DOUBLE).
+					addTimeseries(1, []string{}).
+					addDoublePoint(0, 250, 2).
+					build(),
+			},
+		},
+		{
+			name: "metric_experimental_scale_value_distribution",
+			transforms: []internalTransform{
+				{
+					MetricIncludeFilter: internalFilterStrict{include: "metric1"},
+					Action:              Update,
+					Operations: []internalOperation{
+						{
+							configOperation: Operation{
+								Action: ScaleValue,
+								Scale:  1000,
+							},
+						},
+					},
+				},
+			},
+			in: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, []string{}).
+					addDistributionPoints(0, 3, 0.6, []float64{0.1, 0.2, 0.3}, []int64{0, 1, 1, 1}).
+					build(),
+			},
+			out: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, []string{}).
+					addDistributionPoints(0, 3, 600, []float64{100, 200, 300}, []int64{0, 1, 1, 1}).
+					build(),
+			},
+		},
 		{
 			name: "metric_toggle_scalar_data_type_int64_to_double",
 			transforms: []internalTransform{
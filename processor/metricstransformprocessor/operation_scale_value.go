@@ -0,0 +1,65 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"math"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// scaleValueOp multiplies the values of all the data points by the scale of the operation,
+// rounding the int64 values to the nearest integer
+func (mtp *metricsTransformProcessor) scaleValueOp(metric *metricspb.Metric, mtpOp internalOperation) {
+	scale := mtpOp.configOperation.Scale
+	for _, ts := range metric.Timeseries {
+		for _, dp := range ts.Points {
+			switch metric.MetricDescriptor.Type {
+			case metricspb.MetricDescriptor_GAUGE_INT64, metricspb.MetricDescriptor_CUMULATIVE_INT64:
+				dp.Value = &metricspb.Point_Int64Value{Int64Value: int64(math.Round(float64(dp.GetInt64Value()) * scale))}
+			case metricspb.MetricDescriptor_GAUGE_DOUBLE, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+				dp.Value = &metricspb.Point_DoubleValue{DoubleValue: dp.GetDoubleValue() * scale}
+			case metricspb.MetricDescriptor_GAUGE_DISTRIBUTION, metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION:
+				mtp.scaleDistribution(dp.GetDistributionValue(), scale)
+			}
+		}
+	}
+}
+
+// scaleDistribution multiplies the sum, the bucket bounds and the exemplars of the distribution by the scale,
+// the counts being left as is
+func (mtp *metricsTransformProcessor) scaleDistribution(distVal *metricspb.DistributionValue, scale float64) {
+	if distVal == nil {
+		return
+	}
+
+	distVal.Sum *= scale
+	distVal.SumOfSquaredDeviation *= scale * scale
+
+	if explicit := distVal.BucketOptions.GetExplicit(); explicit != nil {
+		// the bounds may be shared with other distributions, so they are replaced rather than updated in place
+		bounds := make([]float64, len(explicit.Bounds))
+		for i, bound := range explicit.Bounds {
+			bounds[i] = bound * scale
+		}
+		explicit.Bounds = bounds
+	}
+
+	for _, bucket := range distVal.Buckets {
+		if bucket.Exemplar != nil {
+			bucket.Exemplar.Value *= scale
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// SummaryLevel determines which summary attributes are added to the redacted items: <debug|info|silent>.
+type SummaryLevel string
+
+const (
+	// DebugSummary adds the counts and the keys of the redacted and masked attributes, as well as the count of the
+	// ignored ones.
+	DebugSummary SummaryLevel = "debug"
+
+	// InfoSummary adds the counts of the redacted and masked attributes.
+	InfoSummary SummaryLevel = "info"
+
+	// SilentSummary doesn't add any summary attribute.
+	SilentSummary SummaryLevel = "silent"
+)
+
+// Config defines the configuration for the processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// AllowedKeys is the list of the attribute keys allowed to be emitted. The attributes with any other key,
+	// unless ignored, are removed.
+	AllowedKeys []string `mapstructure:"allowed_keys"`
+
+	// BlockedValues is the list of regular expressions matching the parts of the string values of the allowed
+	// attributes to be masked.
+	BlockedValues []string `mapstructure:"blocked_values"`
+
+	// IgnoredKeys is the list of the attribute keys which are neither removed nor masked.
+	IgnoredKeys []string `mapstructure:"ignored_keys"`
+
+	// Summary determines which summary attributes are added: <debug|info|silent>. Defaults to info.
+	Summary SummaryLevel `mapstructure:"summary"`
+}
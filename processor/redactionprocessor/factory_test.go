@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	assert.Equal(t, InfoSummary, cfg.Summary)
+}
+
+func TestCreateTraceProcessor(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), params, factory.CreateDefaultConfig(), consumertest.NewTracesNop())
+	require.NoError(t, err)
+	assert.NotNil(t, tp)
+	assert.True(t, tp.GetCapabilities().MutatesConsumedData)
+
+	lp, err := factory.CreateLogsProcessor(context.Background(), params, factory.CreateDefaultConfig(), consumertest.NewLogsNop())
+	assert.Error(t, err)
+	assert.Nil(t, lp)
+}
+
+func TestCreateTraceProcessorWithInvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{"invalid summary", func(cfg *Config) { cfg.Summary = "verbose" }},
+		{"invalid blocked value", func(cfg *Config) { cfg.BlockedValues = []string{"("} }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+			assert.Error(t, err)
+			assert.Nil(t, tp)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type redactionProcessor struct {
+	redactor *attributeRedactor
+}
+
+func newRedactionProcessor(config *Config) (*redactionProcessor, error) {
+	redactor, err := newAttributeRedactor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redactionProcessor{
+		redactor: redactor,
+	}, nil
+}
+
+// ProcessTraces redacts the attributes of the spans
+func (rp *redactionProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				rp.redactor.redact(spans.At(k).Attributes())
+			}
+		}
+	}
+	return td, nil
+}
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const visaNumber = "4111111111111111"
+
+func TestRedactSpanAttributes(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		summary  SummaryLevel
+		expected map[string]pdata.AttributeValue
+	}{
+		{
+			name:    "debug",
+			summary: DebugSummary,
+			expected: map[string]pdata.AttributeValue{
+				"http.method":          pdata.NewAttributeValueString("GET"),
+				"card":                 pdata.NewAttributeValueString("card ****, expires 12/30"),
+				"retries":              pdata.NewAttributeValueInt(3),
+				"trace.sampled":        pdata.NewAttributeValueString(visaNumber),
+				redactedCountAttribute: pdata.NewAttributeValueInt(2),
+				redactedKeysAttribute:  pdata.NewAttributeValueString("password,user"),
+				maskedCountAttribute:   pdata.NewAttributeValueInt(1),
+				maskedKeysAttribute:    pdata.NewAttributeValueString("card"),
+				ignoredCountAttribute:  pdata.NewAttributeValueInt(1),
+			},
+		},
+		{
+			name:    "info",
+			summary: InfoSummary,
+			expected: map[string]pdata.AttributeValue{
+				"http.method":          pdata.NewAttributeValueString("GET"),
+				"card":                 pdata.NewAttributeValueString("card ****, expires 12/30"),
+				"retries":              pdata.NewAttributeValueInt(3),
+				"trace.sampled":        pdata.NewAttributeValueString(visaNumber),
+				redactedCountAttribute: pdata.NewAttributeValueInt(2),
+				maskedCountAttribute:   pdata.NewAttributeValueInt(1),
+			},
+		},
+		{
+			name:    "silent",
+			summary: SilentSummary,
+			expected: map[string]pdata.AttributeValue{
+				"http.method":   pdata.NewAttributeValueString("GET"),
+				"card":          pdata.NewAttributeValueString("card ****, expires 12/30"),
+				"retries":       pdata.NewAttributeValueInt(3),
+				"trace.sampled": pdata.NewAttributeValueString(visaNumber),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// prepare
+			cfg := createDefaultConfig().(*Config)
+			cfg.AllowedKeys = []string{"http.method", "card", "retries"}
+			cfg.BlockedValues = []string{"4[0-9]{12}(?:[0-9]{3})?"}
+			cfg.IgnoredKeys = []string{"trace.sampled"}
+			cfg.Summary = tc.summary
+			rp, err := newRedactionProcessor(cfg)
+			require.NoError(t, err)
+
+			td := tracesWithAttributes(map[string]pdata.AttributeValue{
+				"http.method":   pdata.NewAttributeValueString("GET"),
+				"card":          pdata.NewAttributeValueString("card " + visaNumber + ", expires 12/30"),
+				"retries":       pdata.NewAttributeValueInt(3),
+				"trace.sampled": pdata.NewAttributeValueString(visaNumber),
+				"user":          pdata.NewAttributeValueString("alice"),
+				"password":      pdata.NewAttributeValueString("secret"),
+			})
+
+			// test
+			out, err := rp.ProcessTraces(context.Background(), td)
+
+			// verify
+			require.NoError(t, err)
+			attrs := out.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes()
+			assert.Equal(t, pdata.NewAttributeMap().InitFromMap(tc.expected).Sort(), attrs.Sort())
+		})
+	}
+}
+
+func TestNoSummaryForUntouchedAttributes(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.AllowedKeys = []string{"http.method"}
+	cfg.Summary = DebugSummary
+	rp, err := newRedactionProcessor(cfg)
+	require.NoError(t, err)
+
+	td := tracesWithAttributes(map[string]pdata.AttributeValue{
+		"http.method": pdata.NewAttributeValueString("GET"),
+	})
+
+	// test
+	out, err := rp.ProcessTraces(context.Background(), td)
+
+	// verify
+	require.NoError(t, err)
+	attrs := out.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes()
+	assert.Equal(t, 1, attrs.Len())
+}
+
+func tracesWithAttributes(attrs map[string]pdata.AttributeValue) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	ils.Spans().At(0).Attributes().InitFromMap(attrs)
+	return td
+}
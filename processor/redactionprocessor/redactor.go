@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// maskedValue replaces the parts of the values matching the blocked values
+	maskedValue = "****"
+
+	redactedKeysAttribute  = "redaction.redacted.keys"
+	redactedCountAttribute = "redaction.redacted.count"
+	maskedKeysAttribute    = "redaction.masked.keys"
+	maskedCountAttribute   = "redaction.masked.count"
+	ignoredCountAttribute  = "redaction.ignored.count"
+)
+
+// attributeRedactor applies the redaction rules to attribute maps, regardless of the kind of item they belong to
+type attributeRedactor struct {
+	allowedKeys   map[string]struct{}
+	ignoredKeys   map[string]struct{}
+	blockedValues []*regexp.Regexp
+	summary       SummaryLevel
+}
+
+// redactionResult describes the changes made to an attribute map
+type redactionResult struct {
+	redactedKeys []string
+	maskedKeys   []string
+	ignoredCount int
+}
+
+func newAttributeRedactor(config *Config) (*attributeRedactor, error) {
+	summary := config.Summary
+	switch summary {
+	case "":
+		summary = InfoSummary
+	case DebugSummary, InfoSummary, SilentSummary:
+	default:
+		return nil, fmt.Errorf("invalid summary %q, must be one of %q, %q or %q", summary, DebugSummary, InfoSummary, SilentSummary)
+	}
+
+	blockedValues := make([]*regexp.Regexp, 0, len(config.BlockedValues))
+	for _, expr := range config.BlockedValues {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocked value %q: %w", expr, err)
+		}
+		blockedValues = append(blockedValues, re)
+	}
+
+	return &attributeRedactor{
+		allowedKeys:   toSet(config.AllowedKeys),
+		ignoredKeys:   toSet(config.IgnoredKeys),
+		blockedValues: blockedValues,
+		summary:       summary,
+	}, nil
+}
+
+// redact removes the attributes which aren't allowed and masks the blocked values of the allowed ones,
+// then adds the summary of the changes to the attributes
+func (r *attributeRedactor) redact(attrs pdata.AttributeMap) {
+	var result redactionResult
+	masked := map[string]string{}
+
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		if _, ok := r.ignoredKeys[k]; ok {
+			result.ignoredCount++
+			return
+		}
+		if _, ok := r.allowedKeys[k]; !ok {
+			result.redactedKeys = append(result.redactedKeys, k)
+			return
+		}
+		if v.Type() != pdata.AttributeValueSTRING {
+			return
+		}
+		if value, ok := r.mask(v.StringVal()); ok {
+			masked[k] = value
+			result.maskedKeys = append(result.maskedKeys, k)
+		}
+	})
+
+	// the attribute map can't be changed while iterating over it
+	for _, k := range result.redactedKeys {
+		attrs.Delete(k)
+	}
+	for k, v := range masked {
+		attrs.UpdateString(k, v)
+	}
+
+	r.addSummary(result, attrs)
+}
+
+// mask replaces the parts of the given value matching the blocked values, returning whether any was found
+func (r *attributeRedactor) mask(value string) (string, bool) {
+	found := false
+	for _, re := range r.blockedValues {
+		if re.MatchString(value) {
+			value = re.ReplaceAllString(value, maskedValue)
+			found = true
+		}
+	}
+	return value, found
+}
+
+// addSummary adds the summary attributes of the given result, depending on the summary level. Nothing is added
+// for the attributes left untouched.
+func (r *attributeRedactor) addSummary(result redactionResult, attrs pdata.AttributeMap) {
+	if r.summary == SilentSummary {
+		return
+	}
+
+	if len(result.redactedKeys) > 0 {
+		attrs.UpsertInt(redactedCountAttribute, int64(len(result.redactedKeys)))
+		if r.summary == DebugSummary {
+			attrs.UpsertString(redactedKeysAttribute, joinSorted(result.redactedKeys))
+		}
+	}
+	if len(result.maskedKeys) > 0 {
+		attrs.UpsertInt(maskedCountAttribute, int64(len(result.maskedKeys)))
+		if r.summary == DebugSummary {
+			attrs.UpsertString(maskedKeysAttribute, joinSorted(result.maskedKeys))
+		}
+	}
+	if result.ignoredCount > 0 && r.summary == DebugSummary {
+		attrs.UpsertInt(ignoredCountAttribute, int64(result.ignoredCount))
+	}
+}
+
+// joinSorted returns the given keys as a comma-separated list, in alphabetical order
+func joinSorted(keys []string) string {
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func toSet(keys []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+	return set
+}
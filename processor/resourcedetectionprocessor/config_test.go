@@ -68,6 +68,17 @@ func TestLoadConfig(t *testing.T) {
 		Timeout:  2 * time.Second,
 		Override: false,
 	})
+
+	p4 := cfg.Processors["resourcedetection/ecs"]
+	assert.Equal(t, p4, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "resourcedetection",
+			NameVal: "resourcedetection/ecs",
+		},
+		Detectors: []string{"env", "ecs"},
+		Timeout:   2 * time.Second,
+		Override:  false,
+	})
 }
 
 func TestGetConfigFromType(t *testing.T) {
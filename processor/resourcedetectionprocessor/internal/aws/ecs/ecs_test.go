@@ -115,6 +115,7 @@ func Test_ecsDetectV4(t *testing.T) {
 	attr := want.Attributes()
 	attr.InsertString("cloud.provider", "aws")
 	attr.InsertString("cloud.infrastructure_service", "ECS")
+	attr.InsertString("cloud.platform", "aws_ecs")
 	attr.InsertString("aws.ecs.cluster.arn", "arn:aws:ecs:us-west-2:123456789123:cluster/my-cluster")
 	attr.InsertString("aws.ecs.task.arn", "arn:aws:ecs:us-west-2:123456789123:task/123")
 	attr.InsertString("aws.ecs.task.family", "family")
@@ -149,6 +150,7 @@ func Test_ecsDetectV3(t *testing.T) {
 	attr := want.Attributes()
 	attr.InsertString("cloud.provider", "aws")
 	attr.InsertString("cloud.infrastructure_service", "ECS")
+	attr.InsertString("cloud.platform", "aws_ecs")
 	attr.InsertString("aws.ecs.cluster.arn", "arn:aws:ecs:us-west-2:123456789123:cluster/my-cluster")
 	attr.InsertString("aws.ecs.task.arn", "arn:aws:ecs:us-west-2:123456789123:task/123")
 	attr.InsertString("aws.ecs.task.family", "family")
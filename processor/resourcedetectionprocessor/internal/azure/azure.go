@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package azure provides a detector that loads resource information from
+// the Azure Instance Metadata Service
+package azure
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+const (
+	TypeStr = "azure"
+
+	// TODO: Replace with the values defined in "conventions" once they exist
+	attributeCloudPlatform        = "cloud.platform"
+	attributeCloudPlatformAzureVM = "azure_vm"
+	attributeAzureVMSize          = "azure.vm.size"
+	attributeAzureResourceGroup   = "azure.resourcegroup.name"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+type Detector struct {
+	provider azureMetadataProvider
+	logger   *zap.Logger
+}
+
+func NewDetector(params component.ProcessorCreateParams, _ internal.DetectorConfig) (internal.Detector, error) {
+	return &Detector{provider: newAzureMetadataProvider(), logger: params.Logger}, nil
+}
+
+// Detect records the metadata retrieved from the Azure Instance Metadata Service as resource attributes.
+// An empty resource is returned when the service can't be reached, i.e. when not running on an Azure VM.
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+
+	compute, err := d.provider.metadata(ctx)
+	if err != nil {
+		d.logger.Debug("Azure Instance Metadata Service unavailable, not running on an Azure VM", zap.Error(err))
+		return res, nil
+	}
+
+	attr := res.Attributes()
+	attr.InsertString(conventions.AttributeCloudProvider, conventions.AttributeCloudProviderAzure)
+	attr.InsertString(attributeCloudPlatform, attributeCloudPlatformAzureVM)
+	attr.InsertString(conventions.AttributeCloudRegion, compute.Location)
+	attr.InsertString(conventions.AttributeCloudAccount, compute.SubscriptionID)
+	attr.InsertString(conventions.AttributeHostID, compute.VMID)
+	attr.InsertString(conventions.AttributeHostName, compute.Name)
+	attr.InsertString(attributeAzureVMSize, compute.VMSize)
+	attr.InsertString(attributeAzureResourceGroup, compute.ResourceGroupName)
+
+	return res, nil
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+type mockProvider struct {
+	compute *ComputeMetadata
+	err     error
+}
+
+func (m *mockProvider) metadata(context.Context) (*ComputeMetadata, error) {
+	return m.compute, m.err
+}
+
+func TestNewDetector(t *testing.T) {
+	d, err := NewDetector(component.ProcessorCreateParams{Logger: zap.NewNop()}, nil)
+	assert.NotNil(t, d)
+	assert.NoError(t, err)
+}
+
+func TestDetectAzureAvailable(t *testing.T) {
+	detector := &Detector{
+		provider: &mockProvider{compute: &ComputeMetadata{
+			Location:          "westeurope",
+			Name:              "name",
+			VMID:              "vmID",
+			VMSize:            "Standard_D2s_v3",
+			SubscriptionID:    "subscriptionID",
+			ResourceGroupName: "resourceGroup",
+		}},
+		logger: zap.NewNop(),
+	}
+	res, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+
+	expected := internal.NewResource(map[string]interface{}{
+		conventions.AttributeCloudProvider: conventions.AttributeCloudProviderAzure,
+		"cloud.platform":                   "azure_vm",
+		conventions.AttributeCloudRegion:   "westeurope",
+		conventions.AttributeCloudAccount:  "subscriptionID",
+		conventions.AttributeHostID:        "vmID",
+		conventions.AttributeHostName:      "name",
+		"azure.vm.size":                    "Standard_D2s_v3",
+		"azure.resourcegroup.name":         "resourceGroup",
+	})
+
+	res.Attributes().Sort()
+	expected.Attributes().Sort()
+	assert.Equal(t, expected, res)
+}
+
+func TestDetectAzureUnavailable(t *testing.T) {
+	detector := &Detector{
+		provider: &mockProvider{err: errors.New("connection refused")},
+		logger:   zap.NewNop(),
+	}
+	res, err := detector.Detect(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+}
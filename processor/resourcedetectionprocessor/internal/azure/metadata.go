@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// The Azure Instance Metadata Service is only reachable from the VMs, through a non-routable address
+	// See: https://docs.microsoft.com/en-us/azure/virtual-machines/windows/instance-metadata-service
+	metadataEndpoint = "http://169.254.169.254/metadata/instance/compute"
+	apiVersion       = "2020-09-01"
+
+	// The service answers right away on Azure, so this only bounds the wait off Azure
+	metadataTimeout = 2 * time.Second
+)
+
+// ComputeMetadata holds the compute metadata of the VM, as returned by the Azure Instance Metadata Service
+type ComputeMetadata struct {
+	Location          string `json:"location"`
+	Name              string `json:"name"`
+	VMID              string `json:"vmId"`
+	VMSize            string `json:"vmSize"`
+	SubscriptionID    string `json:"subscriptionId"`
+	ResourceGroupName string `json:"resourceGroupName"`
+}
+
+type azureMetadataProvider interface {
+	metadata(ctx context.Context) (*ComputeMetadata, error)
+}
+
+type azureMetadataProviderImpl struct {
+	endpoint string
+	client   *http.Client
+}
+
+var _ azureMetadataProvider = (*azureMetadataProviderImpl)(nil)
+
+func newAzureMetadataProvider() *azureMetadataProviderImpl {
+	return &azureMetadataProviderImpl{
+		endpoint: metadataEndpoint,
+		client:   &http.Client{Timeout: metadataTimeout},
+	}
+}
+
+// metadata queries the Azure Instance Metadata Service for the compute metadata of the VM
+func (p *azureMetadataProviderImpl) metadata(ctx context.Context) (*ComputeMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the header is required, to protect against server side request forgery
+	req.Header.Add("Metadata", "true")
+	q := req.URL.Query()
+	q.Add("format", "json")
+	q.Add("api-version", apiVersion)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the Azure Instance Metadata Service replied with status code %d", resp.StatusCode)
+	}
+
+	compute := &ComputeMetadata{}
+	if err := json.NewDecoder(resp.Body).Decode(compute); err != nil {
+		return nil, fmt.Errorf("failed to decode the response of the Azure Instance Metadata Service: %w", err)
+	}
+
+	return compute, nil
+}
@@ -34,6 +34,17 @@ type Config struct {
 	// Required.
 	FromAttribute string `mapstructure:"from_attribute"`
 
+	// AttributeSource defines where the FromAttribute is looked up: either "context", for the context propagated down
+	// from the previous components, or "resource", for the attributes of the resource of each ResourceSpans. With the
+	// "resource" source, a single batch is split into one batch per route when its resources have different values.
+	// Optional, defaults to "context".
+	AttributeSource string `mapstructure:"attribute_source"`
+
+	// DropRoutingResourceAttribute removes the FromAttribute from the resources once the route has been determined,
+	// so that it's not sent to the exporters. Only valid when the AttributeSource is "resource".
+	// Optional.
+	DropRoutingResourceAttribute bool `mapstructure:"drop_resource_routing_attribute"`
+
 	// Table contains the routing table for this processor.
 	// Required.
 	Table []RoutingTableItem `mapstructure:"table"`
@@ -43,6 +43,7 @@ func createDefaultConfig() configmodels.Processor {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
+		AttributeSource: contextAttributeSource,
 	}
 }
 
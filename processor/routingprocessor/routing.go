@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
@@ -32,6 +33,13 @@ var (
 	errNoTableItems           = errors.New("the routing table is empty")
 	errNoMissingFromAttribute = errors.New("the FromAttribute property is empty")
 	errExporterNotFound       = errors.New("exporter not found")
+	errInvalidAttributeSource = errors.New("the AttributeSource property must be either context or resource")
+	errDropRequiresResource   = errors.New("the routing attribute can only be dropped from the resources")
+)
+
+const (
+	contextAttributeSource  = "context"
+	resourceAttributeSource = "resource"
 )
 
 var _ component.TracesProcessor = (*processorImp)(nil)
@@ -67,6 +75,16 @@ func newProcessor(logger *zap.Logger, cfg configmodels.Exporter) (*processorImp,
 		return nil, fmt.Errorf("invalid attribute to read the route's value from: %w", errNoMissingFromAttribute)
 	}
 
+	switch oCfg.AttributeSource {
+	case "", contextAttributeSource:
+		if oCfg.DropRoutingResourceAttribute {
+			return nil, fmt.Errorf("invalid option to drop the routing attribute: %w", errDropRequiresResource)
+		}
+	case resourceAttributeSource:
+	default:
+		return nil, fmt.Errorf("invalid attribute source %q: %w", oCfg.AttributeSource, errInvalidAttributeSource)
+	}
+
 	return &processorImp{
 		logger:         logger,
 		config:         *oCfg,
@@ -130,6 +148,10 @@ func (e *processorImp) Shutdown(context.Context) error {
 }
 
 func (e *processorImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if e.config.AttributeSource == resourceAttributeSource {
+		return e.routeTracesByResource(ctx, td)
+	}
+
 	value := e.extractValueFromContext(ctx)
 	if len(value) == 0 {
 		// the attribute's value hasn't been found, send data to the default exporter
@@ -145,8 +167,47 @@ func (e *processorImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error
 	return e.pushDataToExporters(ctx, td, e.traceExporters[value])
 }
 
+// routeTracesByResource splits the traces into one batch per route, based on the attribute of each resource,
+// and sends each batch to the exporters of its route
+func (e *processorImp) routeTracesByResource(ctx context.Context, td pdata.Traces) error {
+	// the routes, in the order they were first seen, with the empty value for the default route
+	var routes []string
+	batches := map[string]pdata.Traces{}
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		route := e.extractValueFromResource(rs.Resource())
+		if _, ok := e.traceExporters[route]; !ok {
+			route = ""
+		}
+
+		batch, ok := batches[route]
+		if !ok {
+			batch = pdata.NewTraces()
+			batches[route] = batch
+			routes = append(routes, route)
+		}
+		batch.ResourceSpans().Append(rs)
+	}
+
+	// every route gets its batch, even when the exporters of another route fail
+	var errs []error
+	for _, route := range routes {
+		exporters := e.defaultTraceExporters
+		if route != "" {
+			exporters = e.traceExporters[route]
+		}
+		if err := e.pushDataToExporters(ctx, batches[route], exporters); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return componenterror.CombineErrors(errs)
+}
+
 func (e *processorImp) GetCapabilities() component.ProcessorCapabilities {
-	return component.ProcessorCapabilities{MutatesConsumedData: false}
+	return component.ProcessorCapabilities{MutatesConsumedData: e.config.DropRoutingResourceAttribute}
 }
 
 func (e *processorImp) pushDataToExporters(ctx context.Context, td pdata.Traces, exporters []component.TracesExporter) error {
@@ -180,3 +241,22 @@ func (e *processorImp) extractValueFromContext(ctx context.Context) string {
 
 	return values[0]
 }
+
+func (e *processorImp) extractValueFromResource(resource pdata.Resource) string {
+	attr, ok := resource.Attributes().Get(e.config.FromAttribute)
+	if !ok {
+		return ""
+	}
+
+	var value string
+	if attr.Type() == pdata.AttributeValueSTRING {
+		value = attr.StringVal()
+	} else {
+		e.logger.Debug("the routing attribute isn't a string, using the default route", zap.String("attribute", e.config.FromAttribute))
+	}
+
+	if e.config.DropRoutingResourceAttribute {
+		resource.Attributes().Delete(e.config.FromAttribute)
+	}
+	return value
+}
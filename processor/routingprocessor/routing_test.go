@@ -110,6 +110,163 @@ func TestDefaultRouteIsUsedWhenRouteCantBeDetermined(t *testing.T) {
 	}
 }
 
+func TestRoutesAreFoundForResourceAttributes(t *testing.T) {
+	// prepare
+	var acmeTraces, defaultTraces []pdata.Traces
+	exp := &processorImp{
+		config: Config{
+			FromAttribute:   "tenant.id",
+			AttributeSource: resourceAttributeSource,
+		},
+		logger: zap.NewNop(),
+		traceExporters: map[string][]component.TracesExporter{
+			"acme": {
+				&mockExporter{
+					ConsumeTracesFunc: func(_ context.Context, td pdata.Traces) error {
+						acmeTraces = append(acmeTraces, td)
+						return nil
+					},
+				},
+			},
+		},
+		defaultTraceExporters: []component.TracesExporter{
+			&mockExporter{
+				ConsumeTracesFunc: func(_ context.Context, td pdata.Traces) error {
+					defaultTraces = append(defaultTraces, td)
+					return nil
+				},
+			},
+		},
+	}
+	traces := tracesWithTenants("acme", "globex", "", "acme")
+
+	// test
+	err := exp.ConsumeTraces(context.Background(), traces)
+
+	// verify
+	assert.NoError(t, err)
+	require.Len(t, acmeTraces, 1)
+	assert.Equal(t, 2, acmeTraces[0].ResourceSpans().Len())
+	for i := 0; i < acmeTraces[0].ResourceSpans().Len(); i++ {
+		tenant, ok := acmeTraces[0].ResourceSpans().At(i).Resource().Attributes().Get("tenant.id")
+		require.True(t, ok)
+		assert.Equal(t, "acme", tenant.StringVal())
+	}
+
+	// the unknown route, as well as the missing attribute, go to the default exporters
+	require.Len(t, defaultTraces, 1)
+	assert.Equal(t, 2, defaultTraces[0].ResourceSpans().Len())
+}
+
+func TestFailedRouteDoesntAffectOtherRoutes(t *testing.T) {
+	// prepare
+	expectedErr := errors.New("some error")
+	var globexTraces []pdata.Traces
+	exp := &processorImp{
+		config: Config{
+			FromAttribute:   "tenant.id",
+			AttributeSource: resourceAttributeSource,
+		},
+		logger: zap.NewNop(),
+		traceExporters: map[string][]component.TracesExporter{
+			"acme": {
+				&mockExporter{
+					ConsumeTracesFunc: func(context.Context, pdata.Traces) error {
+						return expectedErr
+					},
+				},
+			},
+			"globex": {
+				&mockExporter{
+					ConsumeTracesFunc: func(_ context.Context, td pdata.Traces) error {
+						globexTraces = append(globexTraces, td)
+						return nil
+					},
+				},
+			},
+		},
+	}
+	traces := tracesWithTenants("acme", "globex")
+
+	// test
+	err := exp.ConsumeTraces(context.Background(), traces)
+
+	// verify
+	assert.Equal(t, expectedErr, err)
+
+	// the batch for the second route is sent, even though the first route failed
+	require.Len(t, globexTraces, 1)
+	assert.Equal(t, 1, globexTraces[0].ResourceSpans().Len())
+}
+
+func TestResourceRoutingAttributeIsDropped(t *testing.T) {
+	// prepare
+	exp, err := newProcessor(zap.NewNop(), &Config{
+		FromAttribute:                "tenant.id",
+		AttributeSource:              resourceAttributeSource,
+		DropRoutingResourceAttribute: true,
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var received []pdata.Traces
+	exp.traceExporters["acme"] = []component.TracesExporter{
+		&mockExporter{
+			ConsumeTracesFunc: func(_ context.Context, td pdata.Traces) error {
+				received = append(received, td)
+				return nil
+			},
+		},
+	}
+
+	// test
+	err = exp.ConsumeTraces(context.Background(), tracesWithTenants("acme"))
+
+	// verify
+	assert.NoError(t, err)
+	require.Len(t, received, 1)
+	_, ok := received[0].ResourceSpans().At(0).Resource().Attributes().Get("tenant.id")
+	assert.False(t, ok)
+	assert.True(t, exp.GetCapabilities().MutatesConsumedData)
+}
+
+func TestInvalidAttributeSource(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		config Config
+		err    error
+	}{
+		{
+			"unknown source",
+			Config{AttributeSource: "span"},
+			errInvalidAttributeSource,
+		},
+		{
+			"drop from context",
+			Config{AttributeSource: contextAttributeSource, DropRoutingResourceAttribute: true},
+			errDropRequiresResource,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			tt.config.FromAttribute = "tenant.id"
+			tt.config.Table = []RoutingTableItem{{Value: "acme", Exporters: []string{"otlp"}}}
+
+			// test
+			exp, err := newProcessor(zap.NewNop(), &tt.config)
+
+			// verify
+			assert.Nil(t, exp)
+			assert.True(t, errors.Is(err, tt.err))
+		})
+	}
+}
+
 func TestRegisterExportersForValidRoute(t *testing.T) {
 	//  prepare
 	exp, err := newProcessor(zap.NewNop(), &Config{
@@ -418,6 +575,18 @@ func TestProcessorCapabilities(t *testing.T) {
 	assert.Equal(t, false, caps.MutatesConsumedData)
 }
 
+// tracesWithTenants returns traces with one resource for each given tenant, without the attribute for the empty ones
+func tracesWithTenants(tenants ...string) pdata.Traces {
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(len(tenants))
+	for i, tenant := range tenants {
+		if tenant != "" {
+			traces.ResourceSpans().At(i).Resource().Attributes().InsertString("tenant.id", tenant)
+		}
+	}
+	return traces
+}
+
 type mockHost struct {
 	componenttest.NopHost
 	GetExportersFunc func() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
@@ -25,6 +25,16 @@ type Dimension struct {
 	Default *string `mapstructure:"default"`
 }
 
+// ExemplarsConfig defines the configuration of the exemplars recorded on the latency histogram.
+type ExemplarsConfig struct {
+	// Enabled turns on the recording of exemplars, which not all the backends accept. Defaults to false.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxPerBucket is the maximum number of exemplars kept for each bucket of each histogram data point
+	// between two metrics flushes. Defaults to 1.
+	MaxPerBucket int `mapstructure:"max_per_bucket"`
+}
+
 type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
 
@@ -40,7 +50,16 @@ type Config struct {
 	// - operation
 	// - span.kind
 	// - status.code
-	// The dimensions will be fetched from the span's attributes. Examples of some conventionally used attributes:
+	// The dimensions will be fetched from the span's attributes, falling back to the resource's attributes.
+	// Examples of some conventionally used attributes:
 	// https://github.com/open-telemetry/opentelemetry-collector/blob/master/translator/conventions/opentelemetry.go.
 	Dimensions []Dimension `mapstructure:"dimensions"`
+
+	// DimensionsCacheSize is the maximum number of metric series, that is, of unique sets of dimensions, that are
+	// kept track of. The least recently seen series are dropped first. Defaults to 1000.
+	DimensionsCacheSize int `mapstructure:"dimensions_cache_size"`
+
+	// Exemplars defines the recording of the trace and span IDs of some of the spans observed by each bucket
+	// of the latency histogram, so that the backends can link the metrics to example traces.
+	Exemplars ExemplarsConfig `mapstructure:"exemplars"`
 }
@@ -40,9 +40,11 @@ func TestLoadConfig(t *testing.T) {
 		wantMetricsExporter         string
 		wantLatencyHistogramBuckets []time.Duration
 		wantDimensions              []Dimension
+		wantDimensionsCacheSize     int
+		wantExemplars               ExemplarsConfig
 	}{
-		{configFile: "config-2-pipelines.yaml", wantMetricsExporter: "prometheus"},
-		{configFile: "config-3-pipelines.yaml", wantMetricsExporter: "otlp/spanmetrics"},
+		{configFile: "config-2-pipelines.yaml", wantMetricsExporter: "prometheus", wantDimensionsCacheSize: 1000, wantExemplars: ExemplarsConfig{MaxPerBucket: 1}},
+		{configFile: "config-3-pipelines.yaml", wantMetricsExporter: "otlp/spanmetrics", wantDimensionsCacheSize: 1000, wantExemplars: ExemplarsConfig{MaxPerBucket: 1}},
 		{
 			configFile:          "config-full.yaml",
 			wantMetricsExporter: "otlp/spanmetrics",
@@ -57,6 +59,8 @@ func TestLoadConfig(t *testing.T) {
 				{"http.method", &defaultMethod},
 				{"http.status_code", nil},
 			},
+			wantDimensionsCacheSize: 1500,
+			wantExemplars:           ExemplarsConfig{Enabled: true, MaxPerBucket: 2},
 		},
 	}
 	for _, tc := range testcases {
@@ -91,6 +95,8 @@ func TestLoadConfig(t *testing.T) {
 					MetricsExporter:         tc.wantMetricsExporter,
 					LatencyHistogramBuckets: tc.wantLatencyHistogramBuckets,
 					Dimensions:              tc.wantDimensions,
+					DimensionsCacheSize:     tc.wantDimensionsCacheSize,
+					Exemplars:               tc.wantExemplars,
 				},
 				cfg.Processors["spanmetrics"],
 			)
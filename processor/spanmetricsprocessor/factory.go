@@ -43,9 +43,13 @@ func createDefaultConfig() configmodels.Processor {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
+		DimensionsCacheSize: defaultDimensionsCacheSize,
+		Exemplars: ExemplarsConfig{
+			MaxPerBucket: defaultMaxExemplarsPerBucket,
+		},
 	}
 }
 
 func createTraceProcessor(_ context.Context, params component.ProcessorCreateParams, cfg configmodels.Processor, nextConsumer consumer.TracesConsumer) (component.TracesProcessor, error) {
-	return newProcessor(params.Logger, cfg, nextConsumer), nil
+	return newProcessor(params.Logger, cfg, nextConsumer)
 }
@@ -16,16 +16,42 @@ package spanmetricsprocessor
 
 import (
 	"context"
+	"fmt"
 	"math"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"go.uber.org/zap"
 )
 
+const (
+	serviceNameKey = conventions.AttributeServiceName
+	operationKey   = "operation"   // OpenTelemetry non-standard constant.
+	spanKindKey    = "span.kind"   // OpenTelemetry non-standard constant.
+	statusCodeKey  = "status.code" // OpenTelemetry non-standard constant.
+
+	metricKeySeparator = string(byte(0))
+
+	instrumentationLibraryName = "spanmetricsprocessor"
+	callsMetricName            = "calls_total"
+	latencyMetricName          = "latency"
+
+	defaultDimensionsCacheSize   = 1000
+	defaultMaxExemplarsPerBucket = 1
+
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
 var (
 	maxDuration   = time.Duration(math.MaxInt64)
 	maxDurationMs = float64(maxDuration.Milliseconds())
@@ -36,6 +62,7 @@ var (
 )
 
 type processorImp struct {
+	lock   sync.Mutex
 	logger *zap.Logger
 	config Config
 
@@ -45,6 +72,9 @@ type processorImp struct {
 	// Additional dimensions to add to metrics.
 	dimensions []Dimension
 
+	// The start time of the cumulative metrics.
+	startTime time.Time
+
 	// Call & Error counts.
 	callSum map[string]int64
 
@@ -53,9 +83,25 @@ type processorImp struct {
 	latencySum          map[string]float64
 	latencyBucketCounts map[string][]uint64
 	latencyBounds       []float64
+
+	// The exemplars of each bucket of the latency histograms, since the last metrics flush.
+	// Nil when the exemplars are disabled.
+	latencyExemplars map[string][][]exemplar
+
+	// The labels of each metric series, keyed by the metric key. Bounding its size bounds the cardinality of the
+	// dimensions: the series evicted from it are dropped from the accumulated metrics.
+	metricKeyToDimensions *lru.Cache
+}
+
+// exemplar is a latency observed for a span, recorded along with the IDs of the span
+type exemplar struct {
+	traceID   pdata.TraceID
+	spanID    pdata.SpanID
+	value     float64
+	timestamp pdata.TimestampUnixNano
 }
 
-func newProcessor(logger *zap.Logger, config configmodels.Exporter, nextConsumer consumer.TracesConsumer) *processorImp {
+func newProcessor(logger *zap.Logger, config configmodels.Exporter, nextConsumer consumer.TracesConsumer) (*processorImp, error) {
 	logger.Info("building spanmetricsprocessor")
 	pConfig := config.(*Config)
 
@@ -71,9 +117,18 @@ func newProcessor(logger *zap.Logger, config configmodels.Exporter, nextConsumer
 		}
 	}
 
-	return &processorImp{
+	cacheSize := pConfig.DimensionsCacheSize
+	if cacheSize == 0 {
+		cacheSize = defaultDimensionsCacheSize
+	}
+	if cacheSize < 0 {
+		return nil, fmt.Errorf("invalid dimensions_cache_size %d, it must be positive", cacheSize)
+	}
+
+	p := &processorImp{
 		logger:              logger,
 		config:              *pConfig,
+		startTime:           time.Now(),
 		callSum:             make(map[string]int64),
 		latencyBounds:       bounds,
 		latencySum:          make(map[string]float64),
@@ -82,6 +137,20 @@ func newProcessor(logger *zap.Logger, config configmodels.Exporter, nextConsumer
 		nextConsumer:        nextConsumer,
 		dimensions:          pConfig.Dimensions,
 	}
+
+	if pConfig.Exemplars.Enabled {
+		if pConfig.Exemplars.MaxPerBucket <= 0 {
+			return nil, fmt.Errorf("invalid exemplars max_per_bucket %d, it must be positive", pConfig.Exemplars.MaxPerBucket)
+		}
+		p.latencyExemplars = make(map[string][][]exemplar)
+	}
+
+	var err error
+	if p.metricKeyToDimensions, err = lru.NewWithEvict(cacheSize, p.onMetricKeyEvicted); err != nil {
+		return nil, err
+	}
+
+	return p, nil
 }
 
 func mapDurationsToMillis(vs []time.Duration, f func(duration time.Duration) float64) []float64 {
@@ -93,10 +162,28 @@ func mapDurationsToMillis(vs []time.Duration, f func(duration time.Duration) flo
 }
 
 // Start implements the component.Component interface.
+// It looks up the configured metrics exporter among the exporters of the metrics pipelines.
 func (p *processorImp) Start(ctx context.Context, host component.Host) error {
 	p.logger.Info("starting spanmetricsprocessor")
 
-	// TODO: Add implementation
+	var availableMetricsExporters []string
+	for cfg, exp := range host.GetExporters()[configmodels.MetricsDataType] {
+		availableMetricsExporters = append(availableMetricsExporters, cfg.Name())
+		if cfg.Name() != p.config.MetricsExporter {
+			continue
+		}
+
+		metricsExp, ok := exp.(component.MetricsExporter)
+		if !ok {
+			return fmt.Errorf("the exporter %q isn't a metrics exporter", cfg.Name())
+		}
+		p.metricsExporter = metricsExp
+	}
+
+	if p.metricsExporter == nil {
+		return fmt.Errorf("failed to find metrics exporter: %q; please configure metrics_exporter from one of: %+v",
+			p.config.MetricsExporter, availableMetricsExporters)
+	}
 
 	p.logger.Info("started spanmetricsprocessor")
 	return nil
@@ -119,11 +206,12 @@ func (p *processorImp) GetCapabilities() component.ProcessorCapabilities {
 // to the discovered metrics exporter.
 // The original input trace data will be forwarded to the next consumer, unmodified.
 func (p *processorImp) ConsumeTraces(ctx context.Context, traces pdata.Traces) error {
-	p.logger.Info("consuming trace data")
+	p.logger.Debug("consuming trace data")
 
+	p.lock.Lock()
 	p.aggregateMetrics(traces)
-
 	m := p.buildMetrics()
+	p.lock.Unlock()
 
 	// Firstly, export metrics to avoid being impacted by downstream trace processor errors/latency.
 	if err := p.metricsExporter.ConsumeMetrics(ctx, *m); err != nil {
@@ -141,15 +229,221 @@ func (p *processorImp) ConsumeTraces(ctx context.Context, traces pdata.Traces) e
 // buildMetrics collects the computed raw metrics data, builds the metrics object and
 // writes the raw metrics data into the metrics object.
 func (p *processorImp) buildMetrics() *pdata.Metrics {
-	// TODO: Add implementation
 	m := pdata.NewMetrics()
+	m.ResourceMetrics().Resize(1)
+	rm := m.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	ilm.InstrumentationLibrary().SetName(instrumentationLibraryName)
+
+	startTime := pdata.TimestampUnixNano(p.startTime.UnixNano())
+	now := pdata.TimestampUnixNano(time.Now().UnixNano())
+	p.collectCallMetrics(ilm, startTime, now)
+	p.collectLatencyMetrics(ilm, startTime, now)
+
+	if p.latencyExemplars != nil {
+		// the exemplars only reference the spans observed since the last flush
+		p.latencyExemplars = make(map[string][][]exemplar)
+	}
 	return &m
 }
 
+// collectCallMetrics writes the call counts of each metric series into the given instrumentation library metrics
+func (p *processorImp) collectCallMetrics(ilm pdata.InstrumentationLibraryMetrics, startTime, now pdata.TimestampUnixNano) {
+	ilm.Metrics().Resize(ilm.Metrics().Len() + 1)
+	mCalls := ilm.Metrics().At(ilm.Metrics().Len() - 1)
+	mCalls.SetName(callsMetricName)
+	mCalls.SetDataType(pdata.MetricDataTypeIntSum)
+	mCalls.IntSum().SetIsMonotonic(true)
+	mCalls.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	dps := mCalls.IntSum().DataPoints()
+	dps.Resize(len(p.callSum))
+	i := 0
+	for key, count := range p.callSum {
+		dp := dps.At(i)
+		dp.SetStartTime(startTime)
+		dp.SetTimestamp(now)
+		dp.SetValue(count)
+		p.setLabels(dp.LabelsMap(), key)
+		i++
+	}
+}
+
+// collectLatencyMetrics writes the latency histogram of each metric series into the given instrumentation library
+// metrics. The explicit bounds leave out the "catch-all" bound, counted by the extra bucket that follows the bounds.
+func (p *processorImp) collectLatencyMetrics(ilm pdata.InstrumentationLibraryMetrics, startTime, now pdata.TimestampUnixNano) {
+	ilm.Metrics().Resize(ilm.Metrics().Len() + 1)
+	mLatency := ilm.Metrics().At(ilm.Metrics().Len() - 1)
+	mLatency.SetName(latencyMetricName)
+	mLatency.SetUnit("ms")
+	mLatency.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	mLatency.DoubleHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	explicitBounds := p.latencyBounds[:len(p.latencyBounds)-1]
+	dps := mLatency.DoubleHistogram().DataPoints()
+	dps.Resize(len(p.latencyCount))
+	i := 0
+	for key, count := range p.latencyCount {
+		dp := dps.At(i)
+		dp.SetStartTime(startTime)
+		dp.SetTimestamp(now)
+		dp.SetExplicitBounds(explicitBounds)
+		dp.SetBucketCounts(append([]uint64(nil), p.latencyBucketCounts[key]...))
+		dp.SetCount(count)
+		dp.SetSum(p.latencySum[key])
+		p.setLabels(dp.LabelsMap(), key)
+		p.setExemplars(dp.Exemplars(), key)
+		i++
+	}
+}
+
+func (p *processorImp) setExemplars(exemplars pdata.DoubleExemplarSlice, key string) {
+	for _, bucketExemplars := range p.latencyExemplars[key] {
+		for _, e := range bucketExemplars {
+			de := pdata.NewDoubleExemplar()
+			de.SetValue(e.value)
+			de.SetTimestamp(e.timestamp)
+			de.FilteredLabels().InitFromMap(map[string]string{
+				traceIDKey: e.traceID.HexString(),
+				spanIDKey:  e.spanID.HexString(),
+			})
+			exemplars.Append(de)
+		}
+	}
+}
+
+func (p *processorImp) setLabels(labels pdata.StringMap, key string) {
+	// peeking doesn't update the recency of the series, only the spans do
+	if dimensions, ok := p.metricKeyToDimensions.Peek(key); ok {
+		labels.InitFromMap(dimensions.(map[string]string))
+	}
+}
+
 // aggregateMetrics aggregates the raw metrics from the input trace data.
 // Each metric is identified by a key that is built from the service name
 // and span metadata such as operation, kind, status_code and any additional
 // dimensions the user has configured.
 func (p *processorImp) aggregateMetrics(traces pdata.Traces) {
-	// TODO: Add implementation
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttrs := rs.Resource().Attributes()
+		serviceAttr, ok := resourceAttrs.Get(conventions.AttributeServiceName)
+		if !ok {
+			continue
+		}
+		serviceName := serviceAttr.StringVal()
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				p.aggregateMetricsForSpan(serviceName, spans.At(k), resourceAttrs)
+			}
+		}
+	}
+}
+
+func (p *processorImp) aggregateMetricsForSpan(serviceName string, span pdata.Span, resourceAttrs pdata.AttributeMap) {
+	latencyMs := float64(int64(span.EndTime())-int64(span.StartTime())) / float64(time.Millisecond)
+	// the bounds are sorted, so the bucket is the first one whose bound isn't lower than the latency
+	index := sort.SearchFloat64s(p.latencyBounds, latencyMs)
+	if index == len(p.latencyBounds) {
+		index--
+	}
+
+	key, dimensions := p.buildKey(serviceName, span, resourceAttrs)
+	if _, ok := p.metricKeyToDimensions.Get(key); !ok {
+		// this might evict the least recently seen series, dropping its metrics
+		p.metricKeyToDimensions.Add(key, dimensions)
+	}
+
+	p.callSum[key]++
+	p.latencyCount[key]++
+	p.latencySum[key] += latencyMs
+	if _, ok := p.latencyBucketCounts[key]; !ok {
+		p.latencyBucketCounts[key] = make([]uint64, len(p.latencyBounds))
+	}
+	p.latencyBucketCounts[key][index]++
+
+	if p.latencyExemplars != nil {
+		p.recordExemplar(key, index, span, latencyMs)
+	}
+}
+
+// recordExemplar keeps the given span as an exemplar of the given bucket, unless the bucket has enough of them already
+func (p *processorImp) recordExemplar(key string, index int, span pdata.Span, latencyMs float64) {
+	bucketsExemplars, ok := p.latencyExemplars[key]
+	if !ok {
+		bucketsExemplars = make([][]exemplar, len(p.latencyBounds))
+		p.latencyExemplars[key] = bucketsExemplars
+	}
+	if len(bucketsExemplars[index]) >= p.config.Exemplars.MaxPerBucket {
+		return
+	}
+	bucketsExemplars[index] = append(bucketsExemplars[index], exemplar{
+		traceID:   span.TraceID(),
+		spanID:    span.SpanID(),
+		value:     latencyMs,
+		timestamp: span.EndTime(),
+	})
+}
+
+// onMetricKeyEvicted drops the metrics accumulated for the series evicted from the dimensions cache
+func (p *processorImp) onMetricKeyEvicted(key interface{}, _ interface{}) {
+	k := key.(string)
+	delete(p.callSum, k)
+	delete(p.latencyCount, k)
+	delete(p.latencySum, k)
+	delete(p.latencyBucketCounts, k)
+	delete(p.latencyExemplars, k)
+}
+
+// buildKey returns the key identifying the metric series of the given span, along with its labels.
+// The additional dimensions are looked up in the span attributes, then in the resource attributes, and
+// fall back to their default value, being omitted when there's none.
+func (p *processorImp) buildKey(serviceName string, span pdata.Span, resourceAttrs pdata.AttributeMap) (string, map[string]string) {
+	dimensions := map[string]string{
+		serviceNameKey: serviceName,
+		operationKey:   span.Name(),
+		spanKindKey:    span.Kind().String(),
+		statusCodeKey:  span.Status().Code().String(),
+	}
+
+	var b strings.Builder
+	b.WriteString(serviceName)
+	for _, v := range []string{span.Name(), span.Kind().String(), span.Status().Code().String()} {
+		b.WriteString(metricKeySeparator)
+		b.WriteString(v)
+	}
+
+	for _, d := range p.dimensions {
+		v, ok := dimensionValue(d, span.Attributes(), resourceAttrs)
+		if !ok {
+			continue
+		}
+		dimensions[d.Name] = v
+
+		// the name is part of the key, so that the omitted dimensions can't be mistaken for the next ones
+		b.WriteString(metricKeySeparator)
+		b.WriteString(d.Name)
+		b.WriteString(metricKeySeparator)
+		b.WriteString(v)
+	}
+
+	return b.String(), dimensions
+}
+
+func dimensionValue(d Dimension, spanAttrs, resourceAttrs pdata.AttributeMap) (string, bool) {
+	if attr, ok := spanAttrs.Get(d.Name); ok {
+		return tracetranslator.AttributeValueToString(attr, false), true
+	}
+	if attr, ok := resourceAttrs.Get(d.Name); ok {
+		return tracetranslator.AttributeValueToString(attr, false), true
+	}
+	if d.Default != nil {
+		return *d.Default, true
+	}
+	return "", false
 }
@@ -17,13 +17,20 @@ package spanmetricsprocessor
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 
@@ -31,13 +38,45 @@ import (
 )
 
 func TestProcessorStart(t *testing.T) {
-	p := &processorImp{
-		logger: zap.NewNop(),
-	}
-	assert.NoError(t, p.Start(context.Background(), nil))
+	for _, tc := range []struct {
+		name            string
+		metricsExporter string
+		wantErr         string
+	}{
+		{name: "exporter found", metricsExporter: "otlp/spanmetrics"},
+		{
+			name:            "exporter not found",
+			metricsExporter: "prometheus",
+			wantErr:         `failed to find metrics exporter: "prometheus"; please configure metrics_exporter from one of: [otlp/spanmetrics]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Prepare
+			mexp := &mocks.MetricsExporter{}
+			host := &mockHost{exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{
+				configmodels.MetricsDataType: {
+					&configmodels.ExporterSettings{TypeVal: "otlp", NameVal: "otlp/spanmetrics"}: mexp,
+				},
+			}}
 
-	// Verify
-	// TODO: Add verification once processor logic is in place
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.MetricsExporter = tc.metricsExporter
+			p, err := newProcessor(zap.NewNop(), cfg, new(consumertest.TracesSink))
+			require.NoError(t, err)
+
+			// Test
+			err = p.Start(context.Background(), host)
+
+			// Verify
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, mexp, p.metricsExporter)
+			}
+		})
+	}
 }
 
 func TestProcessorShutdown(t *testing.T) {
@@ -47,8 +86,8 @@ func TestProcessorShutdown(t *testing.T) {
 
 	// Test
 	next := new(consumertest.TracesSink)
-	p := newProcessor(zap.NewNop(), cfg, next)
-	require.NotNil(t, p)
+	p, err := newProcessor(zap.NewNop(), cfg, next)
+	require.NoError(t, err)
 	// Verify
 	assert.NoError(t, p.Shutdown(context.Background()))
 }
@@ -60,8 +99,8 @@ func TestProcessorCapabilities(t *testing.T) {
 
 	// Test
 	next := new(consumertest.TracesSink)
-	p := newProcessor(zap.NewNop(), cfg, next)
-	require.NotNil(t, p)
+	p, err := newProcessor(zap.NewNop(), cfg, next)
+	require.NoError(t, err)
 	caps := p.GetCapabilities()
 
 	// Verify
@@ -97,16 +136,14 @@ func TestProcessorConsumeTraces(t *testing.T) {
 			}
 			tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(consumeTracesErr)
 
-			p := &processorImp{
-				logger:          zap.NewNop(),
-				metricsExporter: mexp,
-				nextConsumer:    tcon,
-			}
+			p, err := newProcessor(zap.NewNop(), NewFactory().CreateDefaultConfig(), tcon)
+			require.NoError(t, err)
+			p.metricsExporter = mexp
 			traces := pdata.NewTraces()
 
 			// Test
 			ctx := metadata.NewIncomingContext(context.Background(), nil)
-			err := p.ConsumeTraces(ctx, traces)
+			err = p.ConsumeTraces(ctx, traces)
 
 			// Verify
 			if tc.wantConsumeMetricsErr != "" {
@@ -119,3 +156,193 @@ func TestProcessorConsumeTraces(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessorMetrics(t *testing.T) {
+	// Prepare
+	defaultMethod := "GET"
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.LatencyHistogramBuckets = []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}
+	cfg.Dimensions = []Dimension{
+		{Name: "http.method", Default: &defaultMethod},
+		{Name: "http.status_code"},
+		{Name: "deployment.environment"},
+	}
+	p, err := newProcessor(zap.NewNop(), cfg, new(consumertest.TracesSink))
+	require.NoError(t, err)
+
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "frontend")
+	rs.Resource().Attributes().InsertString("deployment.environment", "production")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	for _, latency := range []time.Duration{5 * time.Millisecond, 50 * time.Millisecond, 500 * time.Millisecond} {
+		spans.Append(newSpan("/checkout", latency, map[string]pdata.AttributeValue{
+			"http.status_code": pdata.NewAttributeValueInt(200),
+		}))
+	}
+	spans.Append(newSpan("/checkout", 5*time.Millisecond, nil))
+
+	// Test
+	p.aggregateMetrics(traces)
+	m := p.buildMetrics()
+
+	// Verify
+	metrics := m.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	calls := metrics.At(0)
+	assert.Equal(t, callsMetricName, calls.Name())
+	callsByLabels := map[string]int64{}
+	for i := 0; i < calls.IntSum().DataPoints().Len(); i++ {
+		dp := calls.IntSum().DataPoints().At(i)
+		callsByLabels[labelsString(dp.LabelsMap())] = dp.Value()
+	}
+	withStatus := "deployment.environment=production,http.method=GET,http.status_code=200,operation=/checkout,service.name=frontend,span.kind=SPAN_KIND_SERVER,status.code=STATUS_CODE_UNSET"
+	withoutStatus := "deployment.environment=production,http.method=GET,operation=/checkout,service.name=frontend,span.kind=SPAN_KIND_SERVER,status.code=STATUS_CODE_UNSET"
+	assert.Equal(t, map[string]int64{withStatus: 3, withoutStatus: 1}, callsByLabels)
+
+	latency := metrics.At(1)
+	assert.Equal(t, latencyMetricName, latency.Name())
+	require.Equal(t, 2, latency.DoubleHistogram().DataPoints().Len())
+	for i := 0; i < latency.DoubleHistogram().DataPoints().Len(); i++ {
+		dp := latency.DoubleHistogram().DataPoints().At(i)
+		assert.Equal(t, []float64{10, 100}, dp.ExplicitBounds())
+		assert.Equal(t, 0, dp.Exemplars().Len(), "exemplars are disabled by default")
+		if labelsString(dp.LabelsMap()) == withStatus {
+			assert.Equal(t, []uint64{1, 1, 1}, dp.BucketCounts())
+			assert.EqualValues(t, 3, dp.Count())
+			assert.Equal(t, float64(555), dp.Sum())
+		} else {
+			assert.Equal(t, []uint64{1, 0, 0}, dp.BucketCounts())
+			assert.EqualValues(t, 1, dp.Count())
+		}
+	}
+}
+
+func TestProcessorDimensionsCacheEviction(t *testing.T) {
+	// Prepare
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.DimensionsCacheSize = 2
+	p, err := newProcessor(zap.NewNop(), cfg, new(consumertest.TracesSink))
+	require.NoError(t, err)
+
+	// Test
+	for _, operation := range []string{"/first", "/second", "/first", "/third"} {
+		traces := pdata.NewTraces()
+		traces.ResourceSpans().Resize(1)
+		rs := traces.ResourceSpans().At(0)
+		rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "frontend")
+		rs.InstrumentationLibrarySpans().Resize(1)
+		rs.InstrumentationLibrarySpans().At(0).Spans().Append(newSpan(operation, time.Millisecond, nil))
+		p.aggregateMetrics(traces)
+	}
+	m := p.buildMetrics()
+
+	// Verify: the least recently seen series was dropped
+	calls := m.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	callsByOperation := map[string]int64{}
+	for i := 0; i < calls.IntSum().DataPoints().Len(); i++ {
+		dp := calls.IntSum().DataPoints().At(i)
+		operation, _ := dp.LabelsMap().Get(operationKey)
+		callsByOperation[operation] = dp.Value()
+	}
+	assert.Equal(t, map[string]int64{"/first": 2, "/third": 1}, callsByOperation)
+	assert.Len(t, p.latencyBucketCounts, 2)
+}
+
+func TestProcessorExemplars(t *testing.T) {
+	// Prepare
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.LatencyHistogramBuckets = []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}
+	cfg.Exemplars.Enabled = true
+	p, err := newProcessor(zap.NewNop(), cfg, new(consumertest.TracesSink))
+	require.NoError(t, err)
+
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "frontend")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	for i, latency := range []time.Duration{5 * time.Millisecond, 6 * time.Millisecond, 50 * time.Millisecond} {
+		span := newSpan("/checkout", latency, nil)
+		span.SetTraceID(pdata.NewTraceID([16]byte{byte(i + 1)}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{byte(i + 1)}))
+		spans.Append(span)
+	}
+
+	// Test
+	p.aggregateMetrics(traces)
+	m := p.buildMetrics()
+
+	// Verify: only the first span of each bucket is kept, the last bucket having none
+	dp := m.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(1).DoubleHistogram().DataPoints().At(0)
+	require.Equal(t, 2, dp.Exemplars().Len())
+	for i, want := range []struct {
+		value   float64
+		traceID pdata.TraceID
+		spanID  pdata.SpanID
+	}{
+		{value: 5, traceID: pdata.NewTraceID([16]byte{1}), spanID: pdata.NewSpanID([8]byte{1})},
+		{value: 50, traceID: pdata.NewTraceID([16]byte{3}), spanID: pdata.NewSpanID([8]byte{3})},
+	} {
+		exemplar := dp.Exemplars().At(i)
+		assert.Equal(t, want.value, exemplar.Value())
+		assert.Equal(t, "span_id="+want.spanID.HexString()+",trace_id="+want.traceID.HexString(), labelsString(exemplar.FilteredLabels()))
+	}
+
+	// the exemplars are cleared with each flush, unlike the histogram itself
+	m = p.buildMetrics()
+	dp = m.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(1).DoubleHistogram().DataPoints().At(0)
+	assert.EqualValues(t, 3, dp.Count())
+	assert.Equal(t, 0, dp.Exemplars().Len())
+}
+
+func TestNewProcessorInvalidExemplarsMaxPerBucket(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Exemplars = ExemplarsConfig{Enabled: true, MaxPerBucket: 0}
+	_, err := newProcessor(zap.NewNop(), cfg, new(consumertest.TracesSink))
+	assert.Error(t, err)
+}
+
+func TestNewProcessorInvalidDimensionsCacheSize(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.DimensionsCacheSize = -1
+	_, err := newProcessor(zap.NewNop(), cfg, new(consumertest.TracesSink))
+	assert.Error(t, err)
+}
+
+type mockHost struct {
+	componenttest.NopHost
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (m *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return m.exporters
+}
+
+// newSpan returns a server span with the given name, latency and attributes
+func newSpan(name string, latency time.Duration, attrs map[string]pdata.AttributeValue) pdata.Span {
+	span := pdata.NewSpan()
+	span.SetName(name)
+	span.SetKind(pdata.SpanKindSERVER)
+	start := time.Now()
+	span.SetStartTime(pdata.TimestampUnixNano(start.UnixNano()))
+	span.SetEndTime(pdata.TimestampUnixNano(start.Add(latency).UnixNano()))
+	if attrs != nil {
+		span.Attributes().InitFromMap(attrs)
+	}
+	return span
+}
+
+// labelsString returns the given labels as a sorted, comma-separated list of key=value pairs
+func labelsString(labels pdata.StringMap) string {
+	var pairs []string
+	labels.ForEach(func(k, v string) {
+		pairs = append(pairs, k+"="+v)
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/sampling"
+)
+
+func TestAndPolicyEvaluator(t *testing.T) {
+	cfg := &PolicyCfg{
+		sharedPolicyCfg: sharedPolicyCfg{
+			Name: "and-policy",
+			Type: And,
+		},
+		AndCfg: AndCfg{
+			SubPolicyCfgs: []AndSubPolicyCfg{
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:               "checkout",
+						Type:               StringAttribute,
+						StringAttributeCfg: StringAttributeCfg{Key: "service.name", Values: []string{"checkout"}},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "always",
+						Type: AlwaysSample,
+					},
+				},
+			},
+		},
+	}
+
+	eval, err := getPolicyEvaluator(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	trace := &sampling.TraceData{ReceivedBatches: []pdata.Traces{newTraceWithServiceName("checkout")}}
+	decision, err := eval.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+	require.NoError(t, err)
+	assert.Equal(t, sampling.Sampled, decision)
+
+	trace = &sampling.TraceData{ReceivedBatches: []pdata.Traces{newTraceWithServiceName("cart")}}
+	decision, err = eval.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+	require.NoError(t, err)
+	assert.Equal(t, sampling.NotSampled, decision)
+}
+
+func TestAndPolicyEvaluatorInvalidConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  AndCfg
+	}{
+		{
+			name: "no sub-policies",
+			cfg:  AndCfg{},
+		},
+		{
+			name: "composite sub-policy",
+			cfg:  AndCfg{SubPolicyCfgs: []AndSubPolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "composite", Type: Composite}}}},
+		},
+		{
+			name: "nested and",
+			cfg:  AndCfg{SubPolicyCfgs: []AndSubPolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "nested", Type: And}}}},
+		},
+		{
+			name: "unknown sub-policy type",
+			cfg:  AndCfg{SubPolicyCfgs: []AndSubPolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "unknown", Type: "unknown"}}}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := getPolicyEvaluator(zap.NewNop(), &PolicyCfg{sharedPolicyCfg: sharedPolicyCfg{Name: "and-policy", Type: And}, AndCfg: tt.cfg})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func newTraceWithServiceName(serviceName string) pdata.Traces {
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString("service.name", serviceName)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	rs.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	return traces
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/sampling"
+)
+
+func getCompositePolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
+	compositeCfg := cfg.CompositeCfg
+	if len(compositeCfg.SubPolicyCfgs) == 0 {
+		return nil, fmt.Errorf("composite policy %s has no sub-policies", cfg.Name)
+	}
+	if compositeCfg.MaxTotalSpansPerSecond <= 0 {
+		// no trace would ever fit in the budget
+		return nil, fmt.Errorf("composite policy %s: max_total_spans_per_second must be positive, got %d",
+			cfg.Name, compositeCfg.MaxTotalSpansPerSecond)
+	}
+
+	var subPolicyParams []sampling.SubPolicyEvalParams
+	for i := range compositeCfg.SubPolicyCfgs {
+		subCfg := &compositeCfg.SubPolicyCfgs[i]
+		if subCfg.Type == Composite || subCfg.Type == Drop {
+			return nil, fmt.Errorf("composite policy %s: sub-policy %s can't be a %s policy", cfg.Name, subCfg.Name, subCfg.Type)
+		}
+		if subCfg.MaxSpansPerSecond <= 0 {
+			return nil, fmt.Errorf("composite policy %s: sub-policy %s: max_spans_per_second must be positive, got %d",
+				cfg.Name, subCfg.Name, subCfg.MaxSpansPerSecond)
+		}
+
+		var eval sampling.PolicyEvaluator
+		var err error
+		if subCfg.Type == And {
+			eval, err = getAndPolicyEvaluator(logger, subCfg.Name, &subCfg.AndCfg)
+		} else {
+			eval, err = getSharedPolicyEvaluator(logger, &subCfg.sharedPolicyCfg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("composite policy %s: %w", cfg.Name, err)
+		}
+
+		subPolicyParams = append(subPolicyParams, sampling.SubPolicyEvalParams{
+			Name:              subCfg.Name,
+			Evaluator:         eval,
+			MaxSpansPerSecond: subCfg.MaxSpansPerSecond,
+		})
+	}
+
+	policyName := cfg.Name
+	onDecision := func(subPolicy string, decision sampling.Decision) {
+		_ = stats.RecordWithTags(
+			context.Background(),
+			[]tag.Mutator{
+				tag.Upsert(tagPolicyKey, policyName),
+				tag.Upsert(tagSubPolicyKey, subPolicy),
+				tag.Upsert(tagSampledKey, strconv.FormatBool(decision == sampling.Sampled)),
+			},
+			statCountSubPolicyDecisions.M(int64(1)),
+		)
+	}
+
+	return sampling.NewComposite(logger, compositeCfg.MaxTotalSpansPerSecond, subPolicyParams, onDecision), nil
+}
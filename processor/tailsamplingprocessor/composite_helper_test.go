@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/sampling"
+)
+
+func TestCompositePolicyEvaluator(t *testing.T) {
+	views := SamplingProcessorMetricViews(configtelemetry.LevelNormal)
+	view.Unregister(views...)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	cfg := &PolicyCfg{
+		sharedPolicyCfg: sharedPolicyCfg{
+			Name: "composite-policy",
+			Type: Composite,
+		},
+		CompositeCfg: CompositeCfg{
+			MaxTotalSpansPerSecond: 100,
+			SubPolicyCfgs: []CompositeSubPolicyCfg{
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:               "errors",
+						Type:               StringAttribute,
+						StringAttributeCfg: StringAttributeCfg{Key: "error", Values: []string{"true"}},
+					},
+					MaxSpansPerSecond: 50,
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "everything-else",
+						Type: AlwaysSample,
+					},
+					MaxSpansPerSecond: 50,
+				},
+			},
+		},
+	}
+
+	eval, err := getPolicyEvaluator(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	decision, err := eval.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), &sampling.TraceData{SpanCount: 1})
+	require.NoError(t, err)
+	assert.Equal(t, sampling.Sampled, decision)
+
+	// the trace didn't match the first sub-policy, and was sampled by the second one
+	viewData, err := view.RetrieveData("processor/tail_sampling/" + statCountSubPolicyDecisions.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 2)
+
+	decisions := map[string]string{}
+	for _, row := range viewData {
+		var subPolicy, sampled string
+		for _, tg := range row.Tags {
+			switch tg.Key {
+			case tagSubPolicyKey:
+				subPolicy = tg.Value
+			case tagSampledKey:
+				sampled = tg.Value
+			}
+		}
+		assert.Contains(t, row.Tags, tag.Tag{Key: tagPolicyKey, Value: "composite-policy"})
+		decisions[subPolicy] = sampled
+	}
+	assert.Equal(t, map[string]string{"errors": "false", "everything-else": "true"}, decisions)
+}
+
+func TestCompositePolicyEvaluatorWithAndSubPolicy(t *testing.T) {
+	cfg := &PolicyCfg{
+		sharedPolicyCfg: sharedPolicyCfg{
+			Name: "composite-policy",
+			Type: Composite,
+		},
+		CompositeCfg: CompositeCfg{
+			MaxTotalSpansPerSecond: 100,
+			SubPolicyCfgs: []CompositeSubPolicyCfg{
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "checkout",
+						Type: And,
+					},
+					AndCfg: AndCfg{
+						SubPolicyCfgs: []AndSubPolicyCfg{
+							{
+								sharedPolicyCfg: sharedPolicyCfg{
+									Name:               "checkout-service",
+									Type:               StringAttribute,
+									StringAttributeCfg: StringAttributeCfg{Key: "service.name", Values: []string{"checkout"}},
+								},
+							},
+						},
+					},
+					MaxSpansPerSecond: 1,
+				},
+			},
+		},
+	}
+
+	eval, err := getPolicyEvaluator(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	trace := &sampling.TraceData{ReceivedBatches: []pdata.Traces{newTraceWithServiceName("checkout")}, SpanCount: 1}
+	decision, err := eval.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+	require.NoError(t, err)
+	assert.Equal(t, sampling.Sampled, decision)
+
+	// the budget for the sub-policy is exhausted
+	decision, err = eval.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+	require.NoError(t, err)
+	assert.Equal(t, sampling.NotSampled, decision)
+}
+
+func TestCompositePolicyEvaluatorInvalidConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  CompositeCfg
+		err  string
+	}{
+		{
+			name: "no sub-policies",
+			cfg:  CompositeCfg{MaxTotalSpansPerSecond: 100},
+		},
+		{
+			name: "nested composite",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 100,
+				SubPolicyCfgs: []CompositeSubPolicyCfg{
+					{sharedPolicyCfg: sharedPolicyCfg{Name: "nested", Type: Composite}, MaxSpansPerSecond: 100},
+				},
+			},
+		},
+		{
+			name: "unknown sub-policy type",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 100,
+				SubPolicyCfgs: []CompositeSubPolicyCfg{
+					{sharedPolicyCfg: sharedPolicyCfg{Name: "unknown", Type: "unknown"}, MaxSpansPerSecond: 100},
+				},
+			},
+		},
+		{
+			name: "missing max total spans per second",
+			cfg: CompositeCfg{
+				SubPolicyCfgs: []CompositeSubPolicyCfg{
+					{sharedPolicyCfg: sharedPolicyCfg{Name: "always", Type: AlwaysSample}, MaxSpansPerSecond: 100},
+				},
+			},
+			err: "composite policy composite-policy: max_total_spans_per_second must be positive, got 0",
+		},
+		{
+			name: "negative max total spans per second",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: -1,
+				SubPolicyCfgs: []CompositeSubPolicyCfg{
+					{sharedPolicyCfg: sharedPolicyCfg{Name: "always", Type: AlwaysSample}, MaxSpansPerSecond: 100},
+				},
+			},
+			err: "composite policy composite-policy: max_total_spans_per_second must be positive, got -1",
+		},
+		{
+			name: "missing sub-policy max spans per second",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 100,
+				SubPolicyCfgs: []CompositeSubPolicyCfg{
+					{sharedPolicyCfg: sharedPolicyCfg{Name: "always", Type: AlwaysSample}, MaxSpansPerSecond: 100},
+					{sharedPolicyCfg: sharedPolicyCfg{Name: "also-always", Type: AlwaysSample}},
+				},
+			},
+			err: "composite policy composite-policy: sub-policy also-always: max_spans_per_second must be positive, got 0",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := getPolicyEvaluator(zap.NewNop(), &PolicyCfg{sharedPolicyCfg: sharedPolicyCfg{Name: "composite-policy", Type: Composite}, CompositeCfg: tt.cfg})
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
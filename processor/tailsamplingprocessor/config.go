@@ -34,10 +34,40 @@ const (
 	StringAttribute PolicyType = "string_attribute"
 	// RateLimiting allows all traces until the specified limits are satisfied.
 	RateLimiting PolicyType = "rate_limiting"
+	// KeyedRateLimiting allows the traces with each distinct value of a given attribute until the
+	// specified limits are satisfied, e.g.: 10 traces per second for each "service.name".
+	KeyedRateLimiting PolicyType = "keyed_rate_limiting"
+	// BooleanAttribute sample traces that have a given boolean attribute set to a given value,
+	// e.g.: attribute "app.debug" = true.
+	BooleanAttribute PolicyType = "boolean_attribute"
+	// Probabilistic samples a given percentage of traces, based on the hash of their trace IDs,
+	// consistently with the probabilistic sampler processor.
+	Probabilistic PolicyType = "probabilistic"
+	// StatusCode sample traces that have a span with one of the given status codes,
+	// e.g.: "ERROR".
+	StatusCode PolicyType = "status_code"
+	// TraceState sample traces that have a span whose W3C tracestate has a given key set to one
+	// of the given values, e.g.: "ot" = "r:8".
+	TraceState PolicyType = "trace_state"
+	// Latency sample traces that lasted for a duration within a given range, e.g.: longer than 5 seconds.
+	Latency PolicyType = "latency"
+	// SpanCount sample traces that have a number of spans outside of a specified range,
+	// e.g.: fewer than 2 spans or more than 1000 spans.
+	SpanCount PolicyType = "span_count"
+	// Composite allows defining a composite policy, combining the other policies in one, with
+	// each of them allocated a share of the throughput.
+	Composite PolicyType = "composite"
+	// And allows defining a policy combining the other policies in one, sampling the traces sampled
+	// by all of them.
+	And PolicyType = "and"
+	// Drop allows defining a policy combining the other policies in one, dropping the traces sampled
+	// by all of them, even if they are sampled by other policies.
+	Drop PolicyType = "drop"
 )
 
-// PolicyCfg holds the common configuration to all policies.
-type PolicyCfg struct {
+// sharedPolicyCfg holds the configuration common to the policies that can be used both on their own and
+// as sub-policies of the composite and and policies.
+type sharedPolicyCfg struct {
 	// Name given to the instance of the policy to make easy to identify it in metrics and logs.
 	Name string `mapstructure:"name"`
 	// Type of the policy this will be used to match the proper configuration of the policy.
@@ -48,6 +78,31 @@ type PolicyCfg struct {
 	StringAttributeCfg StringAttributeCfg `mapstructure:"string_attribute"`
 	// Configs for rate limiting filter sampling policy evaluator.
 	RateLimitingCfg RateLimitingCfg `mapstructure:"rate_limiting"`
+	// Configs for keyed rate limiting filter sampling policy evaluator.
+	KeyedRateLimitingCfg KeyedRateLimitingCfg `mapstructure:"keyed_rate_limiting"`
+	// Configs for boolean attribute filter sampling policy evaluator.
+	BooleanAttributeCfg BooleanAttributeCfg `mapstructure:"boolean_attribute"`
+	// Configs for probabilistic sampling policy evaluator.
+	ProbabilisticCfg ProbabilisticCfg `mapstructure:"probabilistic"`
+	// Configs for status code filter sampling policy evaluator.
+	StatusCodeCfg StatusCodeCfg `mapstructure:"status_code"`
+	// Configs for span count filter sampling policy evaluator.
+	SpanCountCfg SpanCountCfg `mapstructure:"span_count"`
+	// Configs for trace state filter sampling policy evaluator.
+	TraceStateCfg TraceStateCfg `mapstructure:"trace_state"`
+	// Configs for latency filter sampling policy evaluator.
+	LatencyCfg LatencyCfg `mapstructure:"latency"`
+}
+
+// PolicyCfg holds the common configuration to all policies.
+type PolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+	// Configs for composite sampling policy evaluator.
+	CompositeCfg CompositeCfg `mapstructure:"composite"`
+	// Configs for and sampling policy evaluator.
+	AndCfg AndCfg `mapstructure:"and"`
+	// Configs for drop sampling policy evaluator.
+	DropCfg DropCfg `mapstructure:"drop"`
 }
 
 // NumericAttributeCfg holds the configurable settings to create a numeric attribute filter
@@ -68,6 +123,15 @@ type StringAttributeCfg struct {
 	Key string `mapstructure:"key"`
 	// Values is the set of attribute values that if any is equal to the actual attribute value to be considered a match.
 	Values []string `mapstructure:"values"`
+	// EnabledRegexMatching determines whether the values are matched as regular expressions
+	// instead of exactly against the actual attribute value.
+	EnabledRegexMatching bool `mapstructure:"enabled_regex_matching"`
+	// CacheMaxSize is the maximum number of attribute values whose regex matching results are kept,
+	// bounding the memory used for high-cardinality attributes. Only used when EnabledRegexMatching is set.
+	CacheMaxSize int `mapstructure:"cache_max_size"`
+	// InvertMatch inverts the decision: the traces with a matching attribute are not sampled,
+	// and the ones without a matching attribute are sampled.
+	InvertMatch bool `mapstructure:"invert_match"`
 }
 
 // RateLimitingCfg holds the configurable settings to create a rate limiting
@@ -77,6 +141,125 @@ type RateLimitingCfg struct {
 	SpansPerSecond int64 `mapstructure:"spans_per_second"`
 }
 
+// KeyedRateLimitingCfg holds the configurable settings to create a keyed rate limiting
+// sampling policy evaluator.
+type KeyedRateLimitingCfg struct {
+	// Key is the attribute whose values the traces are rate limited by, looked up on the resources first,
+	// and then on the spans.
+	Key string `mapstructure:"key"`
+	// TracesPerSecond sets the limit on the maximum number of traces that can be sampled each second,
+	// for each value of the attribute.
+	TracesPerSecond int64 `mapstructure:"traces_per_second"`
+	// MaxKeys is the maximum number of values of the attribute tracked at any time, the least recently
+	// seen being forgotten first. Defaults to 1000.
+	MaxKeys int `mapstructure:"max_keys"`
+}
+
+// BooleanAttributeCfg holds the configurable settings to create a boolean attribute filter
+// sampling policy evaluator.
+type BooleanAttributeCfg struct {
+	// Tag that the filter is going to be matching against.
+	Key string `mapstructure:"key"`
+	// Value is the value of the attribute to be considered a match.
+	Value bool `mapstructure:"value"`
+	// AllowStringValues determines whether the string attributes holding "true" or "false"
+	// are matched as well, for the instrumentation that sends all the attributes as strings.
+	AllowStringValues bool `mapstructure:"allow_string_values"`
+}
+
+// ProbabilisticCfg holds the configurable settings to create a probabilistic
+// sampling policy evaluator.
+type ProbabilisticCfg struct {
+	// HashSeed allows one to configure the hashing seed. Using the same seed and percentage as a probabilistic
+	// sampler processor leads to the same decisions for the same traces.
+	HashSeed uint32 `mapstructure:"hash_seed"`
+	// SamplingPercentage is the percentage rate at which traces are going to be sampled. Defaults to zero, i.e.: no sample.
+	// Values greater or equal 100 are treated as "sample all traces".
+	SamplingPercentage float32 `mapstructure:"sampling_percentage"`
+}
+
+// StatusCodeCfg holds the configurable settings to create a status code filter
+// sampling policy evaluator.
+type StatusCodeCfg struct {
+	// StatusCodes lists the span status codes to be considered a match: ERROR, OK and UNSET.
+	StatusCodes []string `mapstructure:"status_codes"`
+}
+
+// SpanCountCfg holds the configurable settings to create a span count filter
+// sampling policy evaluator. The traces with a number of spans outside of the
+// [MinSpans, MaxSpans] range are sampled.
+type SpanCountCfg struct {
+	// MinSpans is the minimum number of spans for a trace not to be sampled as too small.
+	MinSpans int64 `mapstructure:"min_spans"`
+	// MaxSpans is the maximum number of spans for a trace not to be sampled as too large.
+	// Zero means there's no maximum.
+	MaxSpans int64 `mapstructure:"max_spans"`
+}
+
+// TraceStateCfg holds the configurable settings to create a trace state filter sampling
+// policy evaluator.
+type TraceStateCfg struct {
+	// Key is the tracestate key to match on.
+	Key string `mapstructure:"key"`
+	// Values is the set of tracestate values to be considered a match for the key.
+	Values []string `mapstructure:"values"`
+}
+
+// LatencyCfg holds the configurable settings to create a latency filter sampling policy
+// evaluator. The traces lasting for a duration within the [ThresholdMs, UpperThresholdMs]
+// range are sampled, the duration going from the earliest span start to the latest span end.
+type LatencyCfg struct {
+	// ThresholdMs is the minimum duration, in milliseconds, for a trace to be sampled.
+	ThresholdMs int64 `mapstructure:"threshold_ms"`
+	// UpperThresholdMs is the maximum duration, in milliseconds, for a trace to be sampled.
+	// Zero means there's no maximum.
+	UpperThresholdMs int64 `mapstructure:"upper_threshold_ms"`
+}
+
+// CompositeSubPolicyCfg holds the configuration of a sub-policy of a composite policy, along with the
+// throughput allocated to it. The sub-policy can be of any type except composite and drop.
+type CompositeSubPolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+	// Configs for and sampling policy evaluator.
+	AndCfg AndCfg `mapstructure:"and"`
+	// MaxSpansPerSecond is the maximum number of spans per second that can be sampled by this sub-policy.
+	MaxSpansPerSecond int64 `mapstructure:"max_spans_per_second"`
+}
+
+// CompositeCfg holds the configurable settings to create a composite sampling policy evaluator.
+// The sub-policies are evaluated in order, and the trace is sampled only if the first matching
+// sub-policy still has budget left in the current second.
+type CompositeCfg struct {
+	// MaxTotalSpansPerSecond is the maximum number of spans per second that can be sampled by all the sub-policies.
+	MaxTotalSpansPerSecond int64 `mapstructure:"max_total_spans_per_second"`
+	// SubPolicyCfgs lists the sub-policies, in the order they are evaluated.
+	SubPolicyCfgs []CompositeSubPolicyCfg `mapstructure:"sub_policies"`
+}
+
+// AndCfg holds the configurable settings to create an and sampling policy evaluator.
+type AndCfg struct {
+	// SubPolicyCfgs lists the sub-policies, all of which need to sample a trace for it to be sampled.
+	SubPolicyCfgs []AndSubPolicyCfg `mapstructure:"sub_policies"`
+}
+
+// AndSubPolicyCfg holds the configuration of a sub-policy of an and policy. The sub-policy can be of
+// any type except composite, and and drop.
+type AndSubPolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+}
+
+// DropCfg holds the configurable settings to create a drop sampling policy evaluator.
+type DropCfg struct {
+	// SubPolicyCfgs lists the sub-policies, all of which need to sample a trace for it to be dropped.
+	SubPolicyCfgs []DropSubPolicyCfg `mapstructure:"sub_policies"`
+}
+
+// DropSubPolicyCfg holds the configuration of a sub-policy of a drop policy. The sub-policy can be of
+// any type except composite, and and drop.
+type DropSubPolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+}
+
 // Config holds the configuration for tail-based sampling.
 type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
@@ -89,6 +272,10 @@ type Config struct {
 	// ExpectedNewTracesPerSec sets the expected number of new traces sending to the tail sampling processor
 	// per second. This helps with allocating data structures with closer to actual usage size.
 	ExpectedNewTracesPerSec uint64 `mapstructure:"expected_new_traces_per_sec"`
+	// DecisionCacheSize is the number of traces whose final decisions are kept after the traces are removed
+	// from memory, so that their late spans are forwarded when the trace was sampled, or dropped otherwise,
+	// instead of starting a new trace. Zero disables the cache.
+	DecisionCacheSize int `mapstructure:"decision_cache_size"`
 	// PolicyCfgs sets the tail-based sampling policy which makes a sampling decision
 	// for a given trace when requested.
 	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
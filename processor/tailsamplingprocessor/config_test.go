@@ -46,25 +46,175 @@ func TestLoadConfig(t *testing.T) {
 			DecisionWait:            10 * time.Second,
 			NumTraces:               100,
 			ExpectedNewTracesPerSec: 10,
+			DecisionCacheSize:       1000,
 			PolicyCfgs: []PolicyCfg{
 				{
-					Name: "test-policy-1",
-					Type: AlwaysSample,
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-1",
+						Type: AlwaysSample,
+					},
 				},
 				{
-					Name:                "test-policy-2",
-					Type:                NumericAttribute,
-					NumericAttributeCfg: NumericAttributeCfg{Key: "key1", MinValue: 50, MaxValue: 100},
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:                "test-policy-2",
+						Type:                NumericAttribute,
+						NumericAttributeCfg: NumericAttributeCfg{Key: "key1", MinValue: 50, MaxValue: 100},
+					},
 				},
 				{
-					Name:               "test-policy-3",
-					Type:               StringAttribute,
-					StringAttributeCfg: StringAttributeCfg{Key: "key2", Values: []string{"value1", "value2"}},
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:               "test-policy-3",
+						Type:               StringAttribute,
+						StringAttributeCfg: StringAttributeCfg{Key: "key2", Values: []string{"value1", "value2"}},
+					},
 				},
 				{
-					Name:            "test-policy-4",
-					Type:            RateLimiting,
-					RateLimitingCfg: RateLimitingCfg{SpansPerSecond: 35},
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:            "test-policy-4",
+						Type:            RateLimiting,
+						RateLimitingCfg: RateLimitingCfg{SpansPerSecond: 35},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-5",
+						Type: Composite,
+					},
+					CompositeCfg: CompositeCfg{
+						MaxTotalSpansPerSecond: 1000,
+						SubPolicyCfgs: []CompositeSubPolicyCfg{
+							{
+								sharedPolicyCfg: sharedPolicyCfg{
+									Name:               "test-composite-policy-1",
+									Type:               StringAttribute,
+									StringAttributeCfg: StringAttributeCfg{Key: "error", Values: []string{"true"}},
+								},
+								MaxSpansPerSecond: 200,
+							},
+							{
+								sharedPolicyCfg: sharedPolicyCfg{
+									Name:                "test-composite-policy-2",
+									Type:                NumericAttribute,
+									NumericAttributeCfg: NumericAttributeCfg{Key: "duration_ms", MinValue: 2000, MaxValue: 100000},
+								},
+								MaxSpansPerSecond: 300,
+							},
+							{
+								sharedPolicyCfg: sharedPolicyCfg{
+									Name: "test-composite-policy-3",
+									Type: AlwaysSample,
+								},
+								MaxSpansPerSecond: 100,
+							},
+						},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-6",
+						Type: And,
+					},
+					AndCfg: AndCfg{
+						SubPolicyCfgs: []AndSubPolicyCfg{
+							{
+								sharedPolicyCfg: sharedPolicyCfg{
+									Name:               "test-and-policy-1",
+									Type:               StringAttribute,
+									StringAttributeCfg: StringAttributeCfg{Key: "service.name", Values: []string{"checkout"}},
+								},
+							},
+							{
+								sharedPolicyCfg: sharedPolicyCfg{
+									Name:                "test-and-policy-2",
+									Type:                NumericAttribute,
+									NumericAttributeCfg: NumericAttributeCfg{Key: "duration_ms", MinValue: 500, MaxValue: 100000},
+								},
+							},
+						},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-7",
+						Type: StringAttribute,
+						StringAttributeCfg: StringAttributeCfg{
+							Key:                  "http.url",
+							Values:               []string{"^/health$", "^/metrics$"},
+							EnabledRegexMatching: true,
+							CacheMaxSize:         10,
+							InvertMatch:          true,
+						},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:         "test-policy-8",
+						Type:         SpanCount,
+						SpanCountCfg: SpanCountCfg{MinSpans: 2, MaxSpans: 1000},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:                "test-policy-9",
+						Type:                BooleanAttribute,
+						BooleanAttributeCfg: BooleanAttributeCfg{Key: "app.debug", Value: true, AllowStringValues: true},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:          "test-policy-10",
+						Type:          StatusCode,
+						StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"ERROR", "UNSET"}},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:             "test-policy-11",
+						Type:             Probabilistic,
+						ProbabilisticCfg: ProbabilisticCfg{HashSeed: 22, SamplingPercentage: 15.3},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:                 "test-policy-12",
+						Type:                 KeyedRateLimiting,
+						KeyedRateLimitingCfg: KeyedRateLimitingCfg{Key: "service.name", TracesPerSecond: 10, MaxKeys: 500},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:       "test-policy-13",
+						Type:       Latency,
+						LatencyCfg: LatencyCfg{ThresholdMs: 5000, UpperThresholdMs: 10000},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-14",
+						Type: Drop,
+					},
+					DropCfg: DropCfg{
+						SubPolicyCfgs: []DropSubPolicyCfg{
+							{
+								sharedPolicyCfg: sharedPolicyCfg{
+									Name: "test-drop-policy-1",
+									Type: StringAttribute,
+									StringAttributeCfg: StringAttributeCfg{
+										Key:                  "http.url",
+										Values:               []string{"^/health$"},
+										EnabledRegexMatching: true,
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:          "test-policy-15",
+						Type:          TraceState,
+						TraceStateCfg: TraceStateCfg{Key: "ot", Values: []string{"r:8", "r:9"}},
+					},
 				},
 			},
 		})
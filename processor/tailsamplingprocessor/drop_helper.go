@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/sampling"
+)
+
+func getDropPolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
+	dropCfg := cfg.DropCfg
+	if len(dropCfg.SubPolicyCfgs) == 0 {
+		return nil, fmt.Errorf("drop policy %s has no sub-policies", cfg.Name)
+	}
+
+	var subPolicies []sampling.PolicyEvaluator
+	for i := range dropCfg.SubPolicyCfgs {
+		subCfg := &dropCfg.SubPolicyCfgs[i]
+		if subCfg.Type == Composite || subCfg.Type == And || subCfg.Type == Drop {
+			return nil, fmt.Errorf("drop policy %s: sub-policy %s can't be a %s policy", cfg.Name, subCfg.Name, subCfg.Type)
+		}
+
+		eval, err := getSharedPolicyEvaluator(logger, &subCfg.sharedPolicyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("drop policy %s: %w", cfg.Name, err)
+		}
+		subPolicies = append(subPolicies, eval)
+	}
+
+	return sampling.NewDrop(logger, subPolicies), nil
+}
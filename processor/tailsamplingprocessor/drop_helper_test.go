@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/sampling"
+)
+
+func TestDropPolicyEvaluator(t *testing.T) {
+	cfg := &PolicyCfg{
+		sharedPolicyCfg: sharedPolicyCfg{
+			Name: "drop-policy",
+			Type: Drop,
+		},
+		DropCfg: DropCfg{
+			SubPolicyCfgs: []DropSubPolicyCfg{
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:               "health-checks",
+						Type:               StringAttribute,
+						StringAttributeCfg: StringAttributeCfg{Key: "service.name", Values: []string{"health-checker"}},
+					},
+				},
+			},
+		},
+	}
+
+	eval, err := getPolicyEvaluator(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	trace := &sampling.TraceData{ReceivedBatches: []pdata.Traces{newTraceWithServiceName("health-checker")}}
+	decision, err := eval.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+	require.NoError(t, err)
+	assert.Equal(t, sampling.Dropped, decision)
+
+	trace = &sampling.TraceData{ReceivedBatches: []pdata.Traces{newTraceWithServiceName("checkout")}}
+	decision, err = eval.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+	require.NoError(t, err)
+	assert.Equal(t, sampling.NotSampled, decision)
+}
+
+func TestDropPolicyEvaluatorInvalidConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  DropCfg
+	}{
+		{
+			name: "no sub-policies",
+			cfg:  DropCfg{},
+		},
+		{
+			name: "composite sub-policy",
+			cfg:  DropCfg{SubPolicyCfgs: []DropSubPolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "composite", Type: Composite}}}},
+		},
+		{
+			name: "nested drop",
+			cfg:  DropCfg{SubPolicyCfgs: []DropSubPolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "nested", Type: Drop}}}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := getPolicyEvaluator(zap.NewNop(), &PolicyCfg{sharedPolicyCfg: sharedPolicyCfg{Name: "drop-policy", Type: Drop}, DropCfg: tt.cfg})
+			assert.Error(t, err)
+		})
+	}
+}
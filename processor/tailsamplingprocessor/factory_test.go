@@ -39,8 +39,10 @@ func TestCreateProcessor(t *testing.T) {
 	cfg.ExpectedNewTracesPerSec = 64
 	cfg.PolicyCfgs = []PolicyCfg{
 		{
-			Name: "test-policy",
-			Type: AlwaysSample,
+			sharedPolicyCfg: sharedPolicyCfg{
+				Name: "test-policy",
+				Type: AlwaysSample,
+			},
 		},
 	}
 
@@ -49,3 +51,51 @@ func TestCreateProcessor(t *testing.T) {
 	assert.NotNil(t, tp)
 	assert.NoError(t, err, "cannot create trace processor")
 }
+
+func TestCreateProcessorWithInvalidPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		policy sharedPolicyCfg
+	}{
+		{
+			name: "invalid regex",
+			policy: sharedPolicyCfg{
+				Name: "test-policy",
+				Type: StringAttribute,
+				StringAttributeCfg: StringAttributeCfg{
+					Key:                  "http.url",
+					Values:               []string{"(invalid"},
+					EnabledRegexMatching: true,
+				},
+			},
+		},
+		{
+			name: "unknown status code",
+			policy: sharedPolicyCfg{
+				Name:          "test-policy",
+				Type:          StatusCode,
+				StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"FAILED"}},
+			},
+		},
+		{
+			name: "upper latency threshold lower than threshold",
+			policy: sharedPolicyCfg{
+				Name:       "test-policy",
+				Type:       Latency,
+				LatencyCfg: LatencyCfg{ThresholdMs: 1000, UpperThresholdMs: 500},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.PolicyCfgs = []PolicyCfg{{sharedPolicyCfg: tt.policy}}
+
+			params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+			tp, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+			assert.Nil(t, tp)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -24,9 +24,11 @@ import (
 
 // Variables related to metrics specific to tail sampling.
 var (
-	tagPolicyKey, _    = tag.NewKey("policy")
-	tagSampledKey, _   = tag.NewKey("sampled")
-	tagSourceFormat, _ = tag.NewKey("source_format")
+	tagPolicyKey, _       = tag.NewKey("policy")
+	tagSampledKey, _      = tag.NewKey("sampled")
+	tagSourceFormat, _    = tag.NewKey("source_format")
+	tagSubPolicyKey, _    = tag.NewKey("sub_policy")
+	tagRateLimitingKey, _ = tag.NewKey("rate_limiting_key")
 
 	statDecisionLatencyMicroSec  = stats.Int64("sampling_decision_latency", "Latency (in microseconds) of a given sampling policy", "µs")
 	statOverallDecisionLatencyµs = stats.Int64("sampling_decision_timer_latency", "Latency (in microseconds) of each run of the sampling decision timer", "µs")
@@ -34,13 +36,18 @@ var (
 	statTraceRemovalAgeSec           = stats.Int64("sampling_trace_removal_age", "Time (in seconds) from arrival of a new trace until its removal from memory", "s")
 	statLateSpanArrivalAfterDecision = stats.Int64("sampling_late_span_age", "Time (in seconds) from the sampling decision was taken and the arrival of a late span", "s")
 
-	statPolicyEvaluationErrorCount = stats.Int64("sampling_policy_evaluation_error", "Count of sampling policy evaluation errors", stats.UnitDimensionless)
+	statPolicyEvaluationErrorCount = stats.Int64("sampling_policy_evaluation_error", "Count of sampling policy evaluation errors, per policy", stats.UnitDimensionless)
 
-	statCountTracesSampled = stats.Int64("count_traces_sampled", "Count of traces that were sampled or not", stats.UnitDimensionless)
+	statCountTracesSampled      = stats.Int64("count_traces_sampled", "Count of traces that were sampled or not", stats.UnitDimensionless)
+	statCountTracesDropped      = stats.Int64("count_traces_dropped", "Count of traces dropped by each drop policy, regardless of the other policies", stats.UnitDimensionless)
+	statCountTracesRateLimited  = stats.Int64("count_traces_rate_limited", "Count of traces not sampled by keyed rate limiting policies, per key prefix", stats.UnitDimensionless)
+	statCountSubPolicyDecisions = stats.Int64("count_sub_policy_decisions", "Count of traces that were sampled or not by each sub-policy of a composite policy", stats.UnitDimensionless)
 
 	statDroppedTooEarlyCount    = stats.Int64("sampling_trace_dropped_too_early", "Count of traces that needed to be dropped the configured wait time", stats.UnitDimensionless)
 	statNewTraceIDReceivedCount = stats.Int64("new_trace_id_received", "Counts the arrival of new traces", stats.UnitDimensionless)
 	statTracesOnMemoryGauge     = stats.Int64("sampling_traces_on_memory", "Tracks the number of traces current on memory", stats.UnitDimensionless)
+
+	statLateSpansOfRemovedTraces = stats.Int64("sampling_late_spans_of_removed_traces", "Count of spans arriving after their trace was removed from memory, forwarded when the trace was sampled and dropped otherwise", stats.UnitDimensionless)
 )
 
 // SamplingProcessorMetricViews return the metrics views according to given telemetry level.
@@ -85,6 +92,7 @@ func SamplingProcessorMetricViews(level configtelemetry.Level) []*view.View {
 		Name:        statPolicyEvaluationErrorCount.Name(),
 		Measure:     statPolicyEvaluationErrorCount,
 		Description: statPolicyEvaluationErrorCount.Description(),
+		TagKeys:     policyTagKeys,
 		Aggregation: view.Sum(),
 	}
 
@@ -96,6 +104,27 @@ func SamplingProcessorMetricViews(level configtelemetry.Level) []*view.View {
 		TagKeys:     sampledTagKeys,
 		Aggregation: view.Sum(),
 	}
+	countSubPolicyDecisionsView := &view.View{
+		Name:        statCountSubPolicyDecisions.Name(),
+		Measure:     statCountSubPolicyDecisions,
+		Description: statCountSubPolicyDecisions.Description(),
+		TagKeys:     []tag.Key{tagPolicyKey, tagSubPolicyKey, tagSampledKey},
+		Aggregation: view.Sum(),
+	}
+	countTracesDroppedView := &view.View{
+		Name:        statCountTracesDropped.Name(),
+		Measure:     statCountTracesDropped,
+		Description: statCountTracesDropped.Description(),
+		TagKeys:     []tag.Key{tagPolicyKey},
+		Aggregation: view.Sum(),
+	}
+	countTracesRateLimitedView := &view.View{
+		Name:        statCountTracesRateLimited.Name(),
+		Measure:     statCountTracesRateLimited,
+		Description: statCountTracesRateLimited.Description(),
+		TagKeys:     []tag.Key{tagPolicyKey, tagRateLimitingKey},
+		Aggregation: view.Sum(),
+	}
 
 	countTraceDroppedTooEarlyView := &view.View{
 		Name:        statDroppedTooEarlyCount.Name(),
@@ -116,6 +145,14 @@ func SamplingProcessorMetricViews(level configtelemetry.Level) []*view.View {
 		Aggregation: view.LastValue(),
 	}
 
+	countLateSpansOfRemovedTracesView := &view.View{
+		Name:        statLateSpansOfRemovedTraces.Name(),
+		Measure:     statLateSpansOfRemovedTraces,
+		Description: statLateSpansOfRemovedTraces.Description(),
+		TagKeys:     []tag.Key{tagSampledKey},
+		Aggregation: view.Sum(),
+	}
+
 	legacyViews := []*view.View{
 		decisionLatencyView,
 		overallDecisionLatencyView,
@@ -126,10 +163,15 @@ func SamplingProcessorMetricViews(level configtelemetry.Level) []*view.View {
 		countPolicyEvaluationErrorView,
 
 		countTracesSampledView,
+		countSubPolicyDecisionsView,
+		countTracesRateLimitedView,
+		countTracesDroppedView,
 
 		countTraceDroppedTooEarlyView,
 		countTraceIDArrivalView,
 		trackTracesOnMemorylView,
+
+		countLateSpansOfRemovedTracesView,
 	}
 
 	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/sampling"
+)
+
+func TestPolicyDecisionMetricsAreTaggedWithPolicyName(t *testing.T) {
+	views := SamplingProcessorMetricViews(configtelemetry.LevelNormal)
+	view.Unregister(views...)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	cfg := Config{
+		DecisionWait: time.Second,
+		NumTraces:    100,
+		PolicyCfgs: []PolicyCfg{
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "sampling-policy", Type: AlwaysSample}},
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "not-sampling-policy", Type: AlwaysSample}},
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "failing-policy", Type: AlwaysSample}},
+		},
+	}
+	msp := new(consumertest.TracesSink)
+	tp, err := newTraceProcessor(zap.NewNop(), msp, cfg)
+	require.NoError(t, err)
+
+	tsp := tp.(*tailSamplingSpanProcessor)
+	tsp.decisionBatcher = newSyncIDBatcher(1)
+	tsp.policyTicker = &manualTTicker{}
+	tsp.policies[1].Evaluator = &mockPolicyEvaluator{NextDecision: sampling.NotSampled}
+	tsp.policies[2].Evaluator = &mockPolicyEvaluator{NextError: errors.New("evaluation failed")}
+
+	_, batches := generateIdsAndBatches(1)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	assert.Equal(t, map[string]int64{"sampling-policy/true": 1, "not-sampling-policy/false": 1}, sumsByPolicy(t, statCountTracesSampled.Name(), &tagSampledKey))
+	assert.Equal(t, map[string]int64{"failing-policy": 1}, sumsByPolicy(t, statPolicyEvaluationErrorCount.Name(), nil))
+
+	latencyRows, err := view.RetrieveData("processor/tail_sampling/" + statDecisionLatencyMicroSec.Name())
+	require.NoError(t, err)
+	var policiesWithLatency []string
+	for _, row := range latencyRows {
+		policiesWithLatency = append(policiesWithLatency, tagValue(row.Tags, tagPolicyKey))
+	}
+	assert.ElementsMatch(t, []string{"sampling-policy", "not-sampling-policy", "failing-policy"}, policiesWithLatency)
+}
+
+func TestRateLimitedTracesAreTaggedWithKeyPrefix(t *testing.T) {
+	views := SamplingProcessorMetricViews(configtelemetry.LevelNormal)
+	view.Unregister(views...)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	eval, err := getPolicyEvaluator(zap.NewNop(), &PolicyCfg{
+		sharedPolicyCfg: sharedPolicyCfg{
+			Name:                 "per-service",
+			Type:                 KeyedRateLimiting,
+			KeyedRateLimitingCfg: KeyedRateLimitingCfg{Key: "service.name", TracesPerSecond: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	trace := &sampling.TraceData{ReceivedBatches: []pdata.Traces{newTraceWithServiceName("checkout")}}
+	for i := 0; i < 3; i++ {
+		_, err := eval.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]int64{"per-service/checkout": 2}, sumsByPolicy(t, statCountTracesRateLimited.Name(), &tagRateLimitingKey))
+}
+
+// sumsByPolicy returns the sums recorded for the given view, keyed by the policy name and,
+// when given, by the value of the extra tag as well
+func sumsByPolicy(t *testing.T, viewName string, extraTag *tag.Key) map[string]int64 {
+	rows, err := view.RetrieveData("processor/tail_sampling/" + viewName)
+	require.NoError(t, err)
+
+	sums := map[string]int64{}
+	for _, row := range rows {
+		key := tagValue(row.Tags, tagPolicyKey)
+		if extraTag != nil {
+			key += "/" + tagValue(row.Tags, *extraTag)
+		}
+		sums[key] += int64(row.Data.(*view.SumData).Value)
+	}
+	return sums
+}
+
+func tagValue(tags []tag.Tag, key tag.Key) string {
+	for _, tg := range tags {
+		if tg.Key == key {
+			return tg.Value
+		}
+	}
+	return ""
+}
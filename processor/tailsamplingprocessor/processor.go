@@ -18,10 +18,12 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/component"
@@ -45,16 +47,27 @@ type Policy struct {
 	ctx context.Context
 }
 
+// lifecycleEvaluator is implemented by the policy evaluators holding resources, like the ticker resetting the
+// budgets of a composite policy, that are started and stopped along with the processor.
+type lifecycleEvaluator interface {
+	Start()
+	Shutdown()
+}
+
 // tailSamplingSpanProcessor handles the incoming trace data and uses the given sampling
 // policy to sample traces.
 type tailSamplingSpanProcessor struct {
-	ctx             context.Context
-	nextConsumer    consumer.TracesConsumer
-	start           sync.Once
-	maxNumTraces    uint64
-	policies        []*Policy
-	logger          *zap.Logger
-	idToTrace       sync.Map
+	ctx          context.Context
+	nextConsumer consumer.TracesConsumer
+	start        sync.Once
+	maxNumTraces uint64
+	policies     []*Policy
+	logger       *zap.Logger
+	idToTrace    sync.Map
+	// decisionCache holds the final decisions for the recently decided traces, so that the spans
+	// arriving after their trace was removed from idToTrace don't start a new trace.
+	// It's nil when the cache is disabled.
+	decisionCache   *lru.Cache
 	policyTicker    tTicker
 	decisionBatcher idbatcher.Batcher
 	deleteChan      chan pdata.TraceID
@@ -63,6 +76,10 @@ type tailSamplingSpanProcessor struct {
 
 const (
 	sourceFormat = "tail_sampling"
+
+	// defaultKeyedRateLimitingMaxKeys is the number of attribute values tracked by the keyed rate limiting
+	// policies when none is configured.
+	defaultKeyedRateLimitingMaxKeys = 1000
 )
 
 // newTraceProcessor returns a processor.TraceProcessor that will perform tail sampling according to the given
@@ -107,6 +124,12 @@ func newTraceProcessor(logger *zap.Logger, nextConsumer consumer.TracesConsumer,
 		policies:        policies,
 	}
 
+	if cfg.DecisionCacheSize > 0 {
+		if tsp.decisionCache, err = lru.New(cfg.DecisionCacheSize); err != nil {
+			return nil, err
+		}
+	}
+
 	tsp.policyTicker = &policyTicker{onTick: tsp.samplingPolicyOnTick}
 	tsp.deleteChan = make(chan pdata.TraceID, cfg.NumTraces)
 
@@ -114,6 +137,20 @@ func newTraceProcessor(logger *zap.Logger, nextConsumer consumer.TracesConsumer,
 }
 
 func getPolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
+	switch cfg.Type {
+	case Composite:
+		return getCompositePolicyEvaluator(logger, cfg)
+	case And:
+		return getAndPolicyEvaluator(logger, cfg.Name, &cfg.AndCfg)
+	case Drop:
+		return getDropPolicyEvaluator(logger, cfg)
+	default:
+		return getSharedPolicyEvaluator(logger, &cfg.sharedPolicyCfg)
+	}
+}
+
+// getSharedPolicyEvaluator creates the evaluators for the policies that can also be used as sub-policies.
+func getSharedPolicyEvaluator(logger *zap.Logger, cfg *sharedPolicyCfg) (sampling.PolicyEvaluator, error) {
 	switch cfg.Type {
 	case AlwaysSample:
 		return sampling.NewAlwaysSample(logger), nil
@@ -122,17 +159,53 @@ func getPolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEval
 		return sampling.NewNumericAttributeFilter(logger, nafCfg.Key, nafCfg.MinValue, nafCfg.MaxValue), nil
 	case StringAttribute:
 		safCfg := cfg.StringAttributeCfg
-		return sampling.NewStringAttributeFilter(logger, safCfg.Key, safCfg.Values), nil
+		return sampling.NewStringAttributeFilter(logger, safCfg.Key, safCfg.Values, safCfg.EnabledRegexMatching, safCfg.CacheMaxSize, safCfg.InvertMatch)
 	case RateLimiting:
 		rlfCfg := cfg.RateLimitingCfg
 		return sampling.NewRateLimiting(logger, rlfCfg.SpansPerSecond), nil
+	case KeyedRateLimiting:
+		krlCfg := cfg.KeyedRateLimitingCfg
+		maxKeys := krlCfg.MaxKeys
+		if maxKeys == 0 {
+			maxKeys = defaultKeyedRateLimitingMaxKeys
+		}
+		return sampling.NewKeyedRateLimiting(logger, krlCfg.Key, krlCfg.TracesPerSecond, maxKeys, recordRateLimitedTrace(cfg.Name))
+	case BooleanAttribute:
+		bafCfg := cfg.BooleanAttributeCfg
+		return sampling.NewBooleanAttributeFilter(logger, bafCfg.Key, bafCfg.Value, bafCfg.AllowStringValues), nil
+	case Probabilistic:
+		pCfg := cfg.ProbabilisticCfg
+		return sampling.NewProbabilisticSampler(logger, pCfg.HashSeed, pCfg.SamplingPercentage), nil
+	case StatusCode:
+		scfCfg := cfg.StatusCodeCfg
+		return sampling.NewStatusCodeFilter(logger, scfCfg.StatusCodes)
+	case TraceState:
+		tsfCfg := cfg.TraceStateCfg
+		return sampling.NewTraceStateFilter(logger, tsfCfg.Key, tsfCfg.Values)
+	case Latency:
+		lCfg := cfg.LatencyCfg
+		return sampling.NewLatency(logger, lCfg.ThresholdMs, lCfg.UpperThresholdMs)
+	case SpanCount:
+		scCfg := cfg.SpanCountCfg
+		return sampling.NewSpanCount(logger, scCfg.MinSpans, scCfg.MaxSpans)
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
 	}
 }
 
+// recordRateLimitedTrace returns the function recording the traces rejected by the given keyed rate limiting policy
+func recordRateLimitedTrace(policyName string) sampling.RateLimitedFunc {
+	return func(keyPrefix string) {
+		_ = stats.RecordWithTags(
+			context.Background(),
+			[]tag.Mutator{tag.Upsert(tagPolicyKey, policyName), tag.Upsert(tagRateLimitingKey, keyPrefix)},
+			statCountTracesRateLimited.M(int64(1)),
+		)
+	}
+}
+
 type policyMetrics struct {
-	idNotFoundOnMapCount, evaluateErrorCount, decisionSampled, decisionNotSampled int64
+	idNotFoundOnMapCount, evaluateErrorCount, decisionSampled, decisionNotSampled, decisionDropped int64
 }
 
 func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
@@ -152,6 +225,9 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		trace.DecisionTime = time.Now()
 
 		decision, policy := tsp.makeDecision(id, trace, &metrics)
+		if tsp.decisionCache != nil {
+			tsp.decisionCache.Add(id, decision)
+		}
 
 		// Sampled or not, remove the batches
 		trace.Lock()
@@ -176,13 +252,13 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 	stats.Record(tsp.ctx,
 		statOverallDecisionLatencyµs.M(int64(time.Since(startTime)/time.Microsecond)),
 		statDroppedTooEarlyCount.M(metrics.idNotFoundOnMapCount),
-		statPolicyEvaluationErrorCount.M(metrics.evaluateErrorCount),
 		statTracesOnMemoryGauge.M(int64(atomic.LoadUint64(&tsp.numTracesOnMap))))
 
 	tsp.logger.Debug("Sampling policy evaluation completed",
 		zap.Int("batch.len", batchLen),
 		zap.Int64("sampled", metrics.decisionSampled),
 		zap.Int64("notSampled", metrics.decisionNotSampled),
+		zap.Int64("dropped", metrics.decisionDropped),
 		zap.Int64("droppedPriorToEvaluation", metrics.idNotFoundOnMapCount),
 		zap.Int64("policyEvaluationErrors", metrics.evaluateErrorCount),
 	)
@@ -191,6 +267,7 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 func (tsp *tailSamplingSpanProcessor) makeDecision(id pdata.TraceID, trace *sampling.TraceData, metrics *policyMetrics) (sampling.Decision, *Policy) {
 	finalDecision := sampling.NotSampled
 	var matchingPolicy *Policy = nil
+	dropped := false
 
 	for i, policy := range tsp.policies {
 		policyEvaluateStartTime := time.Now()
@@ -202,6 +279,7 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pdata.TraceID, trace *samp
 		if err != nil {
 			trace.Decisions[i] = sampling.NotSampled
 			metrics.evaluateErrorCount++
+			stats.Record(policy.ctx, statPolicyEvaluationErrorCount.M(int64(1)))
 			tsp.logger.Debug("Sampling policy error", zap.Error(err))
 		} else {
 			trace.Decisions[i] = decision
@@ -229,8 +307,25 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pdata.TraceID, trace *samp
 					statCountTracesSampled.M(int64(1)),
 				)
 				metrics.decisionNotSampled++
+
+			case sampling.Dropped:
+				// any single policy that decides to drop will cause the decision to be dropped,
+				// regardless of the policies deciding to sample
+				dropped = true
+				stats.Record(policy.ctx, statCountTracesDropped.M(int64(1)))
+				metrics.decisionDropped++
+			}
+		}
+	}
+
+	if dropped {
+		// the decisions to sample are overruled, so that the late arriving spans aren't forwarded either
+		for i := range trace.Decisions {
+			if trace.Decisions[i] == sampling.Sampled {
+				trace.Decisions[i] = sampling.NotSampled
 			}
 		}
+		return sampling.Dropped, nil
 	}
 
 	return finalDecision, matchingPolicy
@@ -269,6 +364,10 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans pdata.Resource
 	idToSpans := tsp.groupSpansByTraceKey(resourceSpans)
 	var newTraceIDs int64
 	for id, spans := range idToSpans {
+		if tsp.handleSpansOfRemovedTrace(id, resourceSpans, spans) {
+			continue
+		}
+
 		lenSpans := int64(len(spans))
 		lenPolicies := len(tsp.policies)
 		initialDecisions := make([]sampling.Decision, lenPolicies)
@@ -328,7 +427,7 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans pdata.Resource
 						zap.Error(err))
 				}
 				fallthrough // so OnLateArrivingSpans is also called for decision Sampled.
-			case sampling.NotSampled:
+			case sampling.NotSampled, sampling.Dropped:
 				policy.Evaluator.OnLateArrivingSpans(actualDecision, spans)
 				stats.Record(tsp.ctx, statLateSpanArrivalAfterDecision.M(int64(time.Since(actualData.DecisionTime)/time.Second)))
 
@@ -349,17 +448,57 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans pdata.Resource
 	stats.Record(tsp.ctx, statNewTraceIDReceivedCount.M(newTraceIDs))
 }
 
+// handleSpansOfRemovedTrace forwards or drops the spans of a trace already removed from memory, according to
+// the decision cached for it, returning false when the trace isn't in the cache and needs to be processed as usual.
+func (tsp *tailSamplingSpanProcessor) handleSpansOfRemovedTrace(id pdata.TraceID, resourceSpans pdata.ResourceSpans, spans []*pdata.Span) bool {
+	if tsp.decisionCache == nil {
+		return false
+	}
+	if _, ok := tsp.idToTrace.Load(id); ok {
+		// the late spans of the traces still in memory are handled along with the policies
+		return false
+	}
+	d, ok := tsp.decisionCache.Get(id)
+	if !ok {
+		return false
+	}
+
+	sampled := d.(sampling.Decision) == sampling.Sampled
+	_ = stats.RecordWithTags(
+		tsp.ctx,
+		[]tag.Mutator{tag.Insert(tagSampledKey, strconv.FormatBool(sampled))},
+		statLateSpansOfRemovedTraces.M(int64(len(spans))),
+	)
+
+	if sampled {
+		if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, prepareTraceBatch(resourceSpans, spans)); err != nil {
+			tsp.logger.Warn("Error sending late arrived spans of a removed trace to destination", zap.Error(err))
+		}
+	}
+	return true
+}
+
 func (tsp *tailSamplingSpanProcessor) GetCapabilities() component.ProcessorCapabilities {
 	return component.ProcessorCapabilities{MutatesConsumedData: false}
 }
 
 // Start is invoked during service startup.
 func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+	for _, policy := range tsp.policies {
+		if eval, ok := policy.Evaluator.(lifecycleEvaluator); ok {
+			eval.Start()
+		}
+	}
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
 func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+	for _, policy := range tsp.policies {
+		if eval, ok := policy.Evaluator.(lifecycleEvaluator); ok {
+			eval.Shutdown()
+		}
+	}
 	return nil
 }
 
@@ -22,7 +22,12 @@ import (
 	"testing"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	tracetranslator "go.opentelemetry.io/collector/translator/trace"
@@ -36,7 +41,7 @@ const (
 	defaultTestDecisionWait = 30 * time.Second
 )
 
-var testPolicy = []PolicyCfg{{Name: "test-policy", Type: AlwaysSample}}
+var testPolicy = []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "test-policy", Type: AlwaysSample}}}
 
 func TestSequentialTraceArrival(t *testing.T) {
 	traceIds, batches := generateIdsAndBatches(128)
@@ -395,6 +400,190 @@ func TestMultipleBatchesAreCombinedIntoOne(t *testing.T) {
 	}
 }
 
+func TestSpanCountPolicyCountsSpansFromAllBatches(t *testing.T) {
+	const maxSize = 100
+	const decisionWaitSeconds = 1
+	msp := new(consumertest.TracesSink)
+	mtt := &manualTTicker{}
+	eval, err := getPolicyEvaluator(zap.NewNop(), &PolicyCfg{
+		sharedPolicyCfg: sharedPolicyCfg{
+			Name:         "span-count-policy",
+			Type:         SpanCount,
+			SpanCountCfg: SpanCountCfg{MinSpans: 2, MaxSpans: 2},
+		},
+	})
+	require.NoError(t, err)
+	tsp := &tailSamplingSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    msp,
+		maxNumTraces:    maxSize,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(decisionWaitSeconds),
+		policies:        []*Policy{{Name: "span-count-policy", Evaluator: eval, ctx: context.TODO()}},
+		deleteChan:      make(chan pdata.TraceID, maxSize),
+		policyTicker:    mtt,
+	}
+
+	// the traces have 1, 2 and 3 spans, each span arriving in its own batch
+	traceIds, batches := generateIdsAndBatches(3)
+	for _, batch := range batches {
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), batch))
+	}
+
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	// only the traces with fewer or more spans than the range are sampled
+	require.EqualValues(t, 2, len(msp.AllTraces()))
+	require.NotNil(t, findTrace(msp.AllTraces(), traceIds[0]))
+	require.Nil(t, findTrace(msp.AllTraces(), traceIds[1]))
+	trace := findTrace(msp.AllTraces(), traceIds[2])
+	require.NotNil(t, trace)
+	require.EqualValues(t, 3, trace.SpanCount())
+}
+
+func TestLateSpansOfRemovedTracesUseCachedDecision(t *testing.T) {
+	views := SamplingProcessorMetricViews(configtelemetry.LevelNormal)
+	view.Unregister(views...)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	const maxSize = 100
+	const decisionWaitSeconds = 1
+	msp := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{}
+	decisionCache, err := lru.New(10)
+	require.NoError(t, err)
+	tsp := &tailSamplingSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    msp,
+		maxNumTraces:    maxSize,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(decisionWaitSeconds),
+		policies:        []*Policy{{Name: "mock-policy", Evaluator: mpe, ctx: context.TODO()}},
+		deleteChan:      make(chan pdata.TraceID, maxSize),
+		policyTicker:    &manualTTicker{},
+		decisionCache:   decisionCache,
+	}
+
+	// the first trace has a single span, the second one has two spans, each in its own batch
+	traceIds, batches := generateIdsAndBatches(2)
+	sampledID, notSampledID := traceIds[0], traceIds[1]
+
+	mpe.NextDecision = sampling.Sampled
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	mpe.NextDecision = sampling.NotSampled
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[1]))
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+	require.Equal(t, 1, msp.SpansCount())
+	require.Equal(t, 2, mpe.EvaluationCount)
+
+	// the traces are removed from memory, as if newer traces had taken their place
+	tsp.dropTrace(sampledID, time.Now())
+	tsp.dropTrace(notSampledID, time.Now())
+
+	// the late spans are handled according to the cached decisions, without starting new traces
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[2]))
+
+	assert.Equal(t, 2, msp.SpansCount())
+	_, ok := tsp.idToTrace.Load(sampledID)
+	assert.False(t, ok)
+	_, ok = tsp.idToTrace.Load(notSampledID)
+	assert.False(t, ok)
+
+	// no new decision is taken for them
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+	assert.Equal(t, 2, mpe.EvaluationCount)
+
+	rows, err := view.RetrieveData("processor/tail_sampling/" + statLateSpansOfRemovedTraces.Name())
+	require.NoError(t, err)
+	lateSpans := map[string]int64{}
+	for _, row := range rows {
+		lateSpans[tagValue(row.Tags, tagSampledKey)] += int64(row.Data.(*view.SumData).Value)
+	}
+	assert.Equal(t, map[string]int64{"true": 1, "false": 1}, lateSpans)
+}
+
+func TestLateSpansOfRemovedTracesWithoutDecisionCache(t *testing.T) {
+	const maxSize = 100
+	const decisionWaitSeconds = 1
+	msp := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	tsp := &tailSamplingSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    msp,
+		maxNumTraces:    maxSize,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(decisionWaitSeconds),
+		policies:        []*Policy{{Name: "mock-policy", Evaluator: mpe, ctx: context.TODO()}},
+		deleteChan:      make(chan pdata.TraceID, maxSize),
+		policyTicker:    &manualTTicker{},
+	}
+
+	traceIds, batches := generateIdsAndBatches(1)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+	tsp.dropTrace(traceIds[0], time.Now())
+
+	// the late span starts a new trace, as the previous decision is unknown
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+	_, ok := tsp.idToTrace.Load(traceIds[0])
+	assert.True(t, ok)
+}
+
+func TestDropPolicyVetoesSampling(t *testing.T) {
+	views := SamplingProcessorMetricViews(configtelemetry.LevelNormal)
+	view.Unregister(views...)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	const maxSize = 100
+	const decisionWaitSeconds = 1
+	msp := new(consumertest.TracesSink)
+	sampler := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	dropper := &mockPolicyEvaluator{NextDecision: sampling.Dropped}
+	dropCtx, err := tag.New(context.Background(), tag.Upsert(tagPolicyKey, "drop-policy"))
+	require.NoError(t, err)
+	tsp := &tailSamplingSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    msp,
+		maxNumTraces:    maxSize,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(decisionWaitSeconds),
+		policies: []*Policy{
+			{Name: "sample-policy", Evaluator: sampler, ctx: context.TODO()},
+			{Name: "drop-policy", Evaluator: dropper, ctx: dropCtx},
+		},
+		deleteChan:   make(chan pdata.TraceID, maxSize),
+		policyTicker: &manualTTicker{},
+	}
+
+	// the second trace has two spans, each in its own batch
+	_, batches := generateIdsAndBatches(2)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[1]))
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	assert.Equal(t, 0, msp.SpansCount(), "the dropped trace shouldn't have been forwarded")
+	assert.Equal(t, 1, sampler.EvaluationCount)
+	assert.Equal(t, 1, dropper.EvaluationCount)
+
+	// the late spans of the dropped trace aren't forwarded either
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[2]))
+	assert.Equal(t, 0, msp.SpansCount())
+	assert.Equal(t, 1, sampler.LateArrivingSpansCount)
+	assert.Equal(t, 1, dropper.LateArrivingSpansCount)
+
+	assert.Equal(t, map[string]int64{"drop-policy": 1}, sumsByPolicy(t, statCountTracesDropped.Name(), nil))
+}
+
 func collectSpanIds(trace *pdata.Traces) []pdata.SpanID {
 	spanIDs := make([]pdata.SpanID, 0)
 
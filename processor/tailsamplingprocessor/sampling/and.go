@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type and struct {
+	subPolicies []PolicyEvaluator
+	logger      *zap.Logger
+}
+
+var _ PolicyEvaluator = (*and)(nil)
+
+// NewAnd creates a policy evaluator that samples the traces sampled by all the given sub-policies.
+func NewAnd(logger *zap.Logger, subPolicies []PolicyEvaluator) PolicyEvaluator {
+	return &and{
+		subPolicies: subPolicies,
+		logger:      logger,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (a *and) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	a.logger.Debug("Triggering action for late arriving spans in and filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+// The sub-policies are evaluated in order, stopping at the first one not sampling the trace.
+func (a *and) Evaluate(traceID pdata.TraceID, trace *TraceData) (Decision, error) {
+	a.logger.Debug("Evaluating spans in and filter")
+	for _, sub := range a.subPolicies {
+		decision, err := sub.Evaluate(traceID, trace)
+		if err != nil {
+			return Unspecified, err
+		}
+		if decision != Sampled {
+			return NotSampled, nil
+		}
+	}
+	return Sampled, nil
+}
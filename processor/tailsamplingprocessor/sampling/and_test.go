@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// fixedEvaluator returns the same decision for all the traces, counting its evaluations
+type fixedEvaluator struct {
+	decision    Decision
+	err         error
+	evaluations int
+}
+
+func (f *fixedEvaluator) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	return nil
+}
+
+func (f *fixedEvaluator) Evaluate(pdata.TraceID, *TraceData) (Decision, error) {
+	f.evaluations++
+	return f.decision, f.err
+}
+
+func TestEvaluate_And(t *testing.T) {
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	errEvaluation := errors.New("evaluation failed")
+
+	for _, tt := range []struct {
+		name                string
+		subPolicies         []*fixedEvaluator
+		expectedDecision    Decision
+		expectedErr         error
+		expectedEvaluations []int
+	}{
+		{
+			name:                "all sampled",
+			subPolicies:         []*fixedEvaluator{{decision: Sampled}, {decision: Sampled}},
+			expectedDecision:    Sampled,
+			expectedEvaluations: []int{1, 1},
+		},
+		{
+			name:                "first not sampled short-circuits",
+			subPolicies:         []*fixedEvaluator{{decision: NotSampled}, {decision: Sampled}},
+			expectedDecision:    NotSampled,
+			expectedEvaluations: []int{1, 0},
+		},
+		{
+			name:                "last not sampled",
+			subPolicies:         []*fixedEvaluator{{decision: Sampled}, {decision: NotSampled}},
+			expectedDecision:    NotSampled,
+			expectedEvaluations: []int{1, 1},
+		},
+		{
+			name:                "any decision other than sampled isn't a match",
+			subPolicies:         []*fixedEvaluator{{decision: Sampled}, {decision: Pending}, {decision: Sampled}},
+			expectedDecision:    NotSampled,
+			expectedEvaluations: []int{1, 1, 0},
+		},
+		{
+			name:                "error short-circuits",
+			subPolicies:         []*fixedEvaluator{{decision: Unspecified, err: errEvaluation}, {decision: Sampled}},
+			expectedDecision:    Unspecified,
+			expectedErr:         errEvaluation,
+			expectedEvaluations: []int{1, 0},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var subPolicies []PolicyEvaluator
+			for _, sub := range tt.subPolicies {
+				subPolicies = append(subPolicies, sub)
+			}
+			and := NewAnd(zap.NewNop(), subPolicies)
+
+			decision, err := and.Evaluate(traceID, &TraceData{})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedDecision, decision)
+			for i, sub := range tt.subPolicies {
+				assert.Equal(t, tt.expectedEvaluations[i], sub.evaluations)
+			}
+		})
+	}
+}
+
+func TestEvaluate_AndWithAttributeFilters(t *testing.T) {
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	stringFilter, err := NewStringAttributeFilter(zap.NewNop(), "service.name", []string{"checkout"}, false, 0, false)
+	require.NoError(t, err)
+	and := NewAnd(zap.NewNop(), []PolicyEvaluator{
+		stringFilter,
+		NewNumericAttributeFilter(zap.NewNop(), "duration_ms", 500, 100000),
+	})
+
+	trace := newTraceWithAttributes(map[string]pdata.AttributeValue{
+		"service.name": pdata.NewAttributeValueString("checkout"),
+	}, map[string]pdata.AttributeValue{
+		"duration_ms": pdata.NewAttributeValueInt(700),
+	})
+	decision, err := and.Evaluate(traceID, trace)
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	trace = newTraceWithAttributes(map[string]pdata.AttributeValue{
+		"service.name": pdata.NewAttributeValueString("checkout"),
+	}, map[string]pdata.AttributeValue{
+		"duration_ms": pdata.NewAttributeValueInt(100),
+	})
+	decision, err = and.Evaluate(traceID, trace)
+	assert.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+}
+
+func TestOnLateArrivingSpans_And(t *testing.T) {
+	and := NewAnd(zap.NewNop(), nil)
+	err := and.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
+
+func newTraceWithAttributes(resourceAttrs, spanAttrs map[string]pdata.AttributeValue) *TraceData {
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	rs.Resource().Attributes().InitFromMap(resourceAttrs)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	ils.Spans().At(0).Attributes().InitFromMap(spanAttrs)
+	return &TraceData{
+		ReceivedBatches: []pdata.Traces{traces},
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type booleanAttributeFilter struct {
+	key               string
+	value             bool
+	allowStringValues bool
+	logger            *zap.Logger
+}
+
+var _ PolicyEvaluator = (*booleanAttributeFilter)(nil)
+
+// NewBooleanAttributeFilter creates a policy evaluator that samples all traces with
+// the given boolean attribute set to the given value. When allowStringValues is set,
+// the string attributes holding "true" or "false" are matched as well.
+func NewBooleanAttributeFilter(logger *zap.Logger, key string, value bool, allowStringValues bool) PolicyEvaluator {
+	return &booleanAttributeFilter{
+		key:               key,
+		value:             value,
+		allowStringValues: allowStringValues,
+		logger:            logger,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (baf *booleanAttributeFilter) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	baf.logger.Debug("Triggering action for late arriving spans in boolean-tag filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (baf *booleanAttributeFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	baf.logger.Debug("Evaluating spans in boolean-tag filter")
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.Unlock()
+	for _, batch := range batches {
+		rspans := batch.ResourceSpans()
+
+		for i := 0; i < rspans.Len(); i++ {
+			rs := rspans.At(i)
+			if v, ok := rs.Resource().Attributes().Get(baf.key); ok && baf.matches(v) {
+				return Sampled, nil
+			}
+
+			ilss := rs.InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				ils := ilss.At(j)
+				for k := 0; k < ils.Spans().Len(); k++ {
+					span := ils.Spans().At(k)
+					if v, ok := span.Attributes().Get(baf.key); ok && baf.matches(v) {
+						return Sampled, nil
+					}
+				}
+			}
+		}
+	}
+	return NotSampled, nil
+}
+
+// matches returns whether the attribute value is the expected one, ignoring the values of other types
+func (baf *booleanAttributeFilter) matches(v pdata.AttributeValue) bool {
+	switch v.Type() {
+	case pdata.AttributeValueBOOL:
+		return v.BoolVal() == baf.value
+	case pdata.AttributeValueSTRING:
+		return baf.allowStringValues && strings.EqualFold(v.StringVal(), strconv.FormatBool(baf.value))
+	default:
+		return false
+	}
+}
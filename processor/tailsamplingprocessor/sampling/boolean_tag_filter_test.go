@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestBooleanTagFilter(t *testing.T) {
+	var empty = map[string]pdata.AttributeValue{}
+	filter := NewBooleanAttributeFilter(zap.NewNop(), "app.debug", true, false)
+	stringsFilter := NewBooleanAttributeFilter(zap.NewNop(), "app.debug", true, true)
+
+	cases := []struct {
+		Desc                string
+		Trace               *TraceData
+		Decision            Decision
+		DecisionWithStrings Decision
+	}{
+		{
+			Desc:                "matching resource attribute",
+			Trace:               newTraceWithAttributes(map[string]pdata.AttributeValue{"app.debug": pdata.NewAttributeValueBool(true)}, empty),
+			Decision:            Sampled,
+			DecisionWithStrings: Sampled,
+		},
+		{
+			Desc:                "matching span attribute",
+			Trace:               newTraceWithAttributes(empty, map[string]pdata.AttributeValue{"app.debug": pdata.NewAttributeValueBool(true)}),
+			Decision:            Sampled,
+			DecisionWithStrings: Sampled,
+		},
+		{
+			Desc:                "nonmatching span attribute value",
+			Trace:               newTraceWithAttributes(empty, map[string]pdata.AttributeValue{"app.debug": pdata.NewAttributeValueBool(false)}),
+			Decision:            NotSampled,
+			DecisionWithStrings: NotSampled,
+		},
+		{
+			Desc:                "nonmatching span attribute key",
+			Trace:               newTraceWithAttributes(empty, map[string]pdata.AttributeValue{"other": pdata.NewAttributeValueBool(true)}),
+			Decision:            NotSampled,
+			DecisionWithStrings: NotSampled,
+		},
+		{
+			Desc:                "string span attribute",
+			Trace:               newTraceWithAttributes(empty, map[string]pdata.AttributeValue{"app.debug": pdata.NewAttributeValueString("True")}),
+			Decision:            NotSampled,
+			DecisionWithStrings: Sampled,
+		},
+		{
+			Desc:                "nonmatching string span attribute",
+			Trace:               newTraceWithAttributes(empty, map[string]pdata.AttributeValue{"app.debug": pdata.NewAttributeValueString("false")}),
+			Decision:            NotSampled,
+			DecisionWithStrings: NotSampled,
+		},
+		{
+			Desc:                "attributes of other types aren't coerced",
+			Trace:               newTraceWithAttributes(empty, map[string]pdata.AttributeValue{"app.debug": pdata.NewAttributeValueInt(1)}),
+			Decision:            NotSampled,
+			DecisionWithStrings: NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+			decision, err := filter.Evaluate(traceID, c.Trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+
+			decision, err = stringsFilter.Evaluate(traceID, c.Trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.DecisionWithStrings, decision)
+		})
+	}
+}
+
+func TestBooleanTagFilterFalseValue(t *testing.T) {
+	filter := NewBooleanAttributeFilter(zap.NewNop(), "app.cached", false, false)
+	trace := newTraceWithAttributes(map[string]pdata.AttributeValue{}, map[string]pdata.AttributeValue{"app.cached": pdata.NewAttributeValueBool(false)})
+
+	decision, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+}
+
+func TestOnLateArrivingSpans_BooleanAttribute(t *testing.T) {
+	filter := NewBooleanAttributeFilter(zap.NewNop(), "app.debug", true, false)
+	err := filter.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// SubPolicyEvalParams defines the evaluator and the throughput allocated to a sub-policy of a composite policy.
+type SubPolicyEvalParams struct {
+	// Name used to identify the sub-policy in metrics and logs.
+	Name string
+	// Evaluator that decides if a trace matches the sub-policy.
+	Evaluator PolicyEvaluator
+	// MaxSpansPerSecond is the budget allocated to the sub-policy, in spans per second.
+	MaxSpansPerSecond int64
+}
+
+// SubPolicyDecisionFunc is called with the decision taken for a trace by each of the evaluated sub-policies
+// of a composite policy.
+type SubPolicyDecisionFunc func(subPolicy string, decision Decision)
+
+type subPolicy struct {
+	SubPolicyEvalParams
+	// spansInCurrentSecond is the number of spans sampled by this sub-policy since the last budget reset.
+	spansInCurrentSecond int64
+}
+
+type composite struct {
+	// mutex guards the budget accounting, which is shared by all the goroutines evaluating traces.
+	mutex                sync.Mutex
+	subPolicies          []*subPolicy
+	maxTotalSPS          int64
+	spansInCurrentSecond int64
+
+	onDecision SubPolicyDecisionFunc
+	logger     *zap.Logger
+
+	resetInterval time.Duration
+	stopCh        chan struct{}
+	stopOnce      sync.Once
+	wg            sync.WaitGroup
+}
+
+var _ PolicyEvaluator = (*composite)(nil)
+
+// NewComposite creates a policy evaluator that walks the given sub-policies in order, sampling a trace only if the
+// first sub-policy matching it still has budget in the current second, and as long as the total budget for the
+// composite policy isn't exceeded. The budgets are reset every second, once Start is called.
+func NewComposite(logger *zap.Logger, maxTotalSpansPerSecond int64, subPolicyParams []SubPolicyEvalParams, onDecision SubPolicyDecisionFunc) PolicyEvaluator {
+	var subPolicies []*subPolicy
+	for _, params := range subPolicyParams {
+		subPolicies = append(subPolicies, &subPolicy{SubPolicyEvalParams: params})
+	}
+
+	if onDecision == nil {
+		onDecision = func(string, Decision) {}
+	}
+
+	return &composite{
+		subPolicies:   subPolicies,
+		maxTotalSPS:   maxTotalSpansPerSecond,
+		onDecision:    onDecision,
+		logger:        logger,
+		resetInterval: time.Second,
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Start starts the ticker resetting the budgets of the sub-policies every second.
+func (c *composite) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.resetInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.resetBudget()
+			case <-c.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the ticker resetting the budgets, returning only after it's done.
+func (c *composite) Shutdown() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+	c.wg.Wait()
+}
+
+func (c *composite) resetBudget() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.spansInCurrentSecond = 0
+	for _, sub := range c.subPolicies {
+		sub.spansInCurrentSecond = 0
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (c *composite) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	c.logger.Debug("Triggering action for late arriving spans in composite filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (c *composite) Evaluate(traceID pdata.TraceID, trace *TraceData) (Decision, error) {
+	c.logger.Debug("Evaluating spans in composite filter")
+	for _, sub := range c.subPolicies {
+		decision, err := sub.Evaluator.Evaluate(traceID, trace)
+		if err != nil {
+			return Unspecified, err
+		}
+
+		if decision != Sampled {
+			c.onDecision(sub.Name, NotSampled)
+			continue
+		}
+
+		// the first matching sub-policy takes the decision, based on the budget it has left
+		decision = c.spend(sub, trace.SpanCount)
+		c.onDecision(sub.Name, decision)
+		return decision, nil
+	}
+
+	return NotSampled, nil
+}
+
+// spend takes the given number of spans from the budgets of the sub-policy and of the composite policy,
+// returning NotSampled when any of them would be exceeded.
+func (c *composite) spend(sub *subPolicy, spanCount int64) Decision {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if sub.spansInCurrentSecond+spanCount > sub.MaxSpansPerSecond ||
+		c.spansInCurrentSecond+spanCount > c.maxTotalSPS {
+		return NotSampled
+	}
+
+	sub.spansInCurrentSecond += spanCount
+	c.spansInCurrentSecond += spanCount
+	return Sampled
+}
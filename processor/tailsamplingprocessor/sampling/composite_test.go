@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type decisionRecord struct {
+	subPolicy string
+	decision  Decision
+}
+
+func newTestComposite(t *testing.T, maxTotalSPS int64, records *[]decisionRecord) *composite {
+	errorsFilter, err := NewStringAttributeFilter(zap.NewNop(), "error", []string{"true"}, false, 0, false)
+	require.NoError(t, err)
+
+	onDecision := func(subPolicy string, decision Decision) {
+		*records = append(*records, decisionRecord{subPolicy, decision})
+	}
+	return NewComposite(zap.NewNop(), maxTotalSPS, []SubPolicyEvalParams{
+		{
+			Name:              "errors",
+			Evaluator:         errorsFilter,
+			MaxSpansPerSecond: 3,
+		},
+		{
+			Name:              "everything-else",
+			Evaluator:         NewAlwaysSample(zap.NewNop()),
+			MaxSpansPerSecond: 2,
+		},
+	}, onDecision).(*composite)
+}
+
+func TestCompositeEvaluatorFirstMatchingSubPolicyDecides(t *testing.T) {
+	var records []decisionRecord
+	c := newTestComposite(t, 10, &records)
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+	errorTrace := newTraceStringAttrs(map[string]pdata.AttributeValue{}, "error", "true")
+	errorTrace.SpanCount = 2
+	decision, err := c.Evaluate(traceID, errorTrace)
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// the errors sub-policy has a single span left in its budget
+	decision, err = c.Evaluate(traceID, errorTrace)
+	assert.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+
+	// the budget of the errors sub-policy doesn't affect the other sub-policies
+	otherTrace := newTraceStringAttrs(map[string]pdata.AttributeValue{}, "error", "false")
+	otherTrace.SpanCount = 2
+	decision, err = c.Evaluate(traceID, otherTrace)
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	assert.Equal(t, []decisionRecord{
+		{"errors", Sampled},
+		{"errors", NotSampled},
+		{"errors", NotSampled},
+		{"everything-else", Sampled},
+	}, records)
+}
+
+func TestCompositeEvaluatorTotalBudget(t *testing.T) {
+	var records []decisionRecord
+	c := newTestComposite(t, 3, &records)
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+	errorTrace := newTraceStringAttrs(map[string]pdata.AttributeValue{}, "error", "true")
+	errorTrace.SpanCount = 2
+	decision, err := c.Evaluate(traceID, errorTrace)
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// the sub-policy has budget left, but it would exceed the total for the composite policy
+	otherTrace := newTraceStringAttrs(map[string]pdata.AttributeValue{}, "error", "false")
+	otherTrace.SpanCount = 2
+	decision, err = c.Evaluate(traceID, otherTrace)
+	assert.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+}
+
+func TestCompositeEvaluatorBudgetIsReset(t *testing.T) {
+	var records []decisionRecord
+	c := newTestComposite(t, 10, &records)
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+	trace := newTraceStringAttrs(map[string]pdata.AttributeValue{}, "error", "true")
+	trace.SpanCount = 3
+	decision, err := c.Evaluate(traceID, trace)
+	require.NoError(t, err)
+	require.Equal(t, Sampled, decision)
+	decision, err = c.Evaluate(traceID, trace)
+	require.NoError(t, err)
+	require.Equal(t, NotSampled, decision)
+
+	c.resetInterval = time.Millisecond
+	c.Start()
+	defer c.Shutdown()
+
+	assert.Eventually(t, func() bool {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		return c.spansInCurrentSecond == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestCompositeEvaluatorConcurrentEvaluations(t *testing.T) {
+	c := NewComposite(zap.NewNop(), 1000, []SubPolicyEvalParams{
+		{
+			Name:              "everything",
+			Evaluator:         NewAlwaysSample(zap.NewNop()),
+			MaxSpansPerSecond: 100,
+		},
+	}, nil)
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+	var mutex sync.Mutex
+	sampled := 0
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			decision, err := c.Evaluate(traceID, &TraceData{SpanCount: 1})
+			assert.NoError(t, err)
+			if decision == Sampled {
+				mutex.Lock()
+				sampled++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// no more than the allocated budget is ever sampled
+	assert.Equal(t, 100, sampled)
+}
+
+func TestOnLateArrivingSpans_Composite(t *testing.T) {
+	c := NewComposite(zap.NewNop(), 10, nil, nil)
+	err := c.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
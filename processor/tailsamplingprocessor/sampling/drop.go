@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type drop struct {
+	matcher PolicyEvaluator
+	logger  *zap.Logger
+}
+
+var _ PolicyEvaluator = (*drop)(nil)
+
+// NewDrop creates a policy evaluator that drops the traces sampled by all the given sub-policies,
+// vetoing the decisions of the other policies to sample them.
+func NewDrop(logger *zap.Logger, subPolicies []PolicyEvaluator) PolicyEvaluator {
+	return &drop{
+		matcher: NewAnd(logger, subPolicies),
+		logger:  logger,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (d *drop) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	d.logger.Debug("Triggering action for late arriving spans in drop filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+// The trace is Dropped when all the sub-policies sample it, and NotSampled otherwise.
+func (d *drop) Evaluate(traceID pdata.TraceID, trace *TraceData) (Decision, error) {
+	d.logger.Debug("Evaluating spans in drop filter")
+	decision, err := d.matcher.Evaluate(traceID, trace)
+	if err != nil {
+		return Unspecified, err
+	}
+	if decision == Sampled {
+		return Dropped, nil
+	}
+	return NotSampled, nil
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestEvaluate_Drop(t *testing.T) {
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	errEvaluation := errors.New("evaluation failed")
+
+	for _, tt := range []struct {
+		name             string
+		subPolicies      []*fixedEvaluator
+		expectedDecision Decision
+		expectedErr      error
+	}{
+		{
+			name:             "all sub-policies matching",
+			subPolicies:      []*fixedEvaluator{{decision: Sampled}, {decision: Sampled}},
+			expectedDecision: Dropped,
+		},
+		{
+			name:             "a sub-policy not matching",
+			subPolicies:      []*fixedEvaluator{{decision: Sampled}, {decision: NotSampled}},
+			expectedDecision: NotSampled,
+		},
+		{
+			name:             "error",
+			subPolicies:      []*fixedEvaluator{{decision: Unspecified, err: errEvaluation}},
+			expectedDecision: Unspecified,
+			expectedErr:      errEvaluation,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var subPolicies []PolicyEvaluator
+			for _, sub := range tt.subPolicies {
+				subPolicies = append(subPolicies, sub)
+			}
+			drop := NewDrop(zap.NewNop(), subPolicies)
+
+			decision, err := drop.Evaluate(traceID, &TraceData{})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedDecision, decision)
+		})
+	}
+}
+
+func TestOnLateArrivingSpans_Drop(t *testing.T) {
+	drop := NewDrop(zap.NewNop(), nil)
+	err := drop.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
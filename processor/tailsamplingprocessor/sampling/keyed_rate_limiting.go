@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+// maxKeyPrefixLength is the length of the key prefixes reported for the rate limited traces,
+// bounding the size of the values reported for arbitrary attributes.
+const maxKeyPrefixLength = 32
+
+// RateLimitedFunc is called with the prefix of the key of each trace rejected by a keyed rate limiting policy.
+type RateLimitedFunc func(keyPrefix string)
+
+// tokenBucket allows up to tracesPerSecond traces per second, refilling continuously.
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+type keyedRateLimiting struct {
+	key             string
+	tracesPerSecond float64
+
+	// mutex guards the buckets, whose state is shared by all the goroutines evaluating traces.
+	mutex   sync.Mutex
+	buckets *lru.Cache
+
+	onRateLimited RateLimitedFunc
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+var _ PolicyEvaluator = (*keyedRateLimiting)(nil)
+
+// NewKeyedRateLimiting creates a policy evaluator that samples up to tracesPerSecond traces per second for each
+// distinct value of the given attribute, tracking the buckets of up to maxKeys values. The attribute is looked
+// up on the resources first, falling back to the first span having it. The traces without the attribute share
+// the same bucket.
+func NewKeyedRateLimiting(logger *zap.Logger, key string, tracesPerSecond int64, maxKeys int, onRateLimited RateLimitedFunc) (PolicyEvaluator, error) {
+	if key == "" {
+		return nil, errors.New("the attribute to rate limit traces by is required")
+	}
+	if tracesPerSecond <= 0 {
+		return nil, errors.New("the number of traces per second for each key must be positive")
+	}
+	if maxKeys <= 0 {
+		return nil, errors.New("the maximum number of keys must be positive")
+	}
+
+	buckets, err := lru.New(maxKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if onRateLimited == nil {
+		onRateLimited = func(string) {}
+	}
+
+	return &keyedRateLimiting{
+		key:             key,
+		tracesPerSecond: float64(tracesPerSecond),
+		buckets:         buckets,
+		onRateLimited:   onRateLimited,
+		now:             time.Now,
+		logger:          logger,
+	}, nil
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (krl *keyedRateLimiting) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	krl.logger.Debug("Triggering action for late arriving spans in keyed rate-limiting filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (krl *keyedRateLimiting) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	krl.logger.Debug("Evaluating spans in keyed rate-limiting filter")
+	key := krl.keyOf(trace)
+	if krl.take(key) {
+		return Sampled, nil
+	}
+
+	if len(key) > maxKeyPrefixLength {
+		key = key[:maxKeyPrefixLength]
+	}
+	krl.onRateLimited(key)
+	return NotSampled, nil
+}
+
+// take takes a token from the bucket for the given key, returning false when the bucket is empty.
+func (krl *keyedRateLimiting) take(key string) bool {
+	krl.mutex.Lock()
+	defer krl.mutex.Unlock()
+
+	now := krl.now()
+	var bucket *tokenBucket
+	if b, ok := krl.buckets.Get(key); ok {
+		bucket = b.(*tokenBucket)
+		bucket.tokens += now.Sub(bucket.lastRefill).Seconds() * krl.tracesPerSecond
+		if bucket.tokens > krl.tracesPerSecond {
+			bucket.tokens = krl.tracesPerSecond
+		}
+		bucket.lastRefill = now
+	} else {
+		bucket = &tokenBucket{tokens: krl.tracesPerSecond, lastRefill: now}
+		krl.buckets.Add(key, bucket)
+	}
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// keyOf returns the value of the attribute for the trace, preferring the resource attributes
+// and falling back to the first span with the attribute.
+func (krl *keyedRateLimiting) keyOf(trace *TraceData) string {
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.Unlock()
+
+	for _, batch := range batches {
+		rspans := batch.ResourceSpans()
+		for i := 0; i < rspans.Len(); i++ {
+			if v, ok := rspans.At(i).Resource().Attributes().Get(krl.key); ok {
+				return tracetranslator.AttributeValueToString(v, false)
+			}
+		}
+	}
+
+	for _, batch := range batches {
+		rspans := batch.ResourceSpans()
+		for i := 0; i < rspans.Len(); i++ {
+			ilss := rspans.At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					if v, ok := spans.At(k).Attributes().Get(krl.key); ok {
+						return tracetranslator.AttributeValueToString(v, false)
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestKeyedRateLimiter(t *testing.T) {
+	var rateLimited []string
+	filter, err := NewKeyedRateLimiting(zap.NewNop(), "service.name", 2, 10, func(keyPrefix string) {
+		rateLimited = append(rateLimited, keyPrefix)
+	})
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	filter.(*keyedRateLimiting).now = func() time.Time { return now }
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	noisy := newTraceWithAttributes(map[string]pdata.AttributeValue{"service.name": pdata.NewAttributeValueString("noisy")}, map[string]pdata.AttributeValue{})
+	quiet := newTraceWithAttributes(map[string]pdata.AttributeValue{"service.name": pdata.NewAttributeValueString("quiet")}, map[string]pdata.AttributeValue{})
+
+	// the noisy service uses up its own budget
+	for _, expected := range []Decision{Sampled, Sampled, NotSampled} {
+		decision, err := filter.Evaluate(traceID, noisy)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, decision)
+	}
+
+	// but not the budget of the other services
+	decision, err := filter.Evaluate(traceID, quiet)
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// the bucket is refilled as time passes
+	now = now.Add(500 * time.Millisecond)
+	decision, err = filter.Evaluate(traceID, noisy)
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+	decision, err = filter.Evaluate(traceID, noisy)
+	assert.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+
+	assert.Equal(t, []string{"noisy", "noisy"}, rateLimited)
+}
+
+func TestKeyedRateLimiterKeyExtraction(t *testing.T) {
+	filter, err := NewKeyedRateLimiting(zap.NewNop(), "service.name", 1, 10, nil)
+	require.NoError(t, err)
+	krl := filter.(*keyedRateLimiting)
+
+	// the resource attribute is preferred
+	trace := newTraceWithAttributes(
+		map[string]pdata.AttributeValue{"service.name": pdata.NewAttributeValueString("from-resource")},
+		map[string]pdata.AttributeValue{"service.name": pdata.NewAttributeValueString("from-span")},
+	)
+	assert.Equal(t, "from-resource", krl.keyOf(trace))
+
+	// falling back to the span attribute
+	trace = newTraceWithAttributes(
+		map[string]pdata.AttributeValue{},
+		map[string]pdata.AttributeValue{"service.name": pdata.NewAttributeValueString("from-span")},
+	)
+	assert.Equal(t, "from-span", krl.keyOf(trace))
+
+	// non-string attributes are used as well
+	trace = newTraceWithAttributes(
+		map[string]pdata.AttributeValue{},
+		map[string]pdata.AttributeValue{"service.name": pdata.NewAttributeValueInt(42)},
+	)
+	assert.Equal(t, "42", krl.keyOf(trace))
+
+	// the traces without the attribute share the same key
+	trace = newTraceWithAttributes(map[string]pdata.AttributeValue{}, map[string]pdata.AttributeValue{})
+	assert.Equal(t, "", krl.keyOf(trace))
+}
+
+func TestKeyedRateLimiterTracksBoundedNumberOfKeys(t *testing.T) {
+	filter, err := NewKeyedRateLimiting(zap.NewNop(), "service.name", 1, 2, nil)
+	require.NoError(t, err)
+	krl := filter.(*keyedRateLimiting)
+
+	for _, service := range []string{"a", "b", "c"} {
+		trace := newTraceWithAttributes(map[string]pdata.AttributeValue{"service.name": pdata.NewAttributeValueString(service)}, map[string]pdata.AttributeValue{})
+		_, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, krl.buckets.Len())
+	assert.False(t, krl.buckets.Contains("a"))
+}
+
+func TestKeyedRateLimiterReportsKeyPrefix(t *testing.T) {
+	var rateLimited []string
+	filter, err := NewKeyedRateLimiting(zap.NewNop(), "service.name", 1, 10, func(keyPrefix string) {
+		rateLimited = append(rateLimited, keyPrefix)
+	})
+	require.NoError(t, err)
+
+	longName := "a-service-with-a-name-longer-than-the-reported-prefix"
+	trace := newTraceWithAttributes(map[string]pdata.AttributeValue{"service.name": pdata.NewAttributeValueString(longName)}, map[string]pdata.AttributeValue{})
+	for i := 0; i < 2; i++ {
+		_, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), trace)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{longName[:maxKeyPrefixLength]}, rateLimited)
+}
+
+func TestKeyedRateLimiterInvalidConfig(t *testing.T) {
+	_, err := NewKeyedRateLimiting(zap.NewNop(), "", 1, 10, nil)
+	assert.Error(t, err)
+	_, err = NewKeyedRateLimiting(zap.NewNop(), "service.name", 0, 10, nil)
+	assert.Error(t, err)
+	_, err = NewKeyedRateLimiting(zap.NewNop(), "service.name", 1, 0, nil)
+	assert.Error(t, err)
+}
+
+func TestOnLateArrivingSpans_KeyedRateLimiter(t *testing.T) {
+	filter, err := NewKeyedRateLimiting(zap.NewNop(), "service.name", 1, 10, nil)
+	require.NoError(t, err)
+	err = filter.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
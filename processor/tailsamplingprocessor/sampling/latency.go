@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type latency struct {
+	lowerThreshold, upperThreshold time.Duration
+	logger                         *zap.Logger
+}
+
+var _ PolicyEvaluator = (*latency)(nil)
+
+// NewLatency creates a policy evaluator that samples the traces whose duration, from the earliest span start
+// to the latest span end, is within the [thresholdMs, upperThresholdMs] range.
+// A zero upperThresholdMs leaves the range without an upper bound, so that only the slow traces are sampled.
+func NewLatency(logger *zap.Logger, thresholdMs, upperThresholdMs int64) (PolicyEvaluator, error) {
+	if thresholdMs < 0 || upperThresholdMs < 0 {
+		return nil, fmt.Errorf("the latency thresholds can't be negative, got threshold_ms %d and upper_threshold_ms %d", thresholdMs, upperThresholdMs)
+	}
+	if upperThresholdMs != 0 && upperThresholdMs < thresholdMs {
+		return nil, fmt.Errorf("upper_threshold_ms %d is lower than threshold_ms %d", upperThresholdMs, thresholdMs)
+	}
+
+	return &latency{
+		lowerThreshold: time.Duration(thresholdMs) * time.Millisecond,
+		upperThreshold: time.Duration(upperThresholdMs) * time.Millisecond,
+		logger:         logger,
+	}, nil
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (l *latency) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	l.logger.Debug("Triggering action for late arriving spans in latency filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (l *latency) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	l.logger.Debug("Evaluating spans in latency filter")
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.Unlock()
+
+	var minStart, maxEnd pdata.TimestampUnixNano
+	found := false
+	for _, batch := range batches {
+		rspans := batch.ResourceSpans()
+		for i := 0; i < rspans.Len(); i++ {
+			ilss := rspans.At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					span := spans.At(k)
+					if !found || span.StartTime() < minStart {
+						minStart = span.StartTime()
+					}
+					if !found || span.EndTime() > maxEnd {
+						maxEnd = span.EndTime()
+					}
+					found = true
+				}
+			}
+		}
+	}
+	if !found || maxEnd < minStart {
+		return NotSampled, nil
+	}
+
+	duration := time.Duration(maxEnd - minStart)
+	if duration >= l.lowerThreshold && (l.upperThreshold == 0 || duration <= l.upperThreshold) {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
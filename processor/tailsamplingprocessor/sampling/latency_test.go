@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestEvaluate_Latency(t *testing.T) {
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		Desc             string
+		ThresholdMs      int64
+		UpperThresholdMs int64
+		Spans            []spanTimes
+		Decision         Decision
+	}{
+		{
+			Desc:        "trace shorter than the threshold",
+			ThresholdMs: 500,
+			Spans:       []spanTimes{{start, start.Add(499 * time.Millisecond)}},
+			Decision:    NotSampled,
+		},
+		{
+			Desc:        "trace as long as the threshold",
+			ThresholdMs: 500,
+			Spans:       []spanTimes{{start, start.Add(500 * time.Millisecond)}},
+			Decision:    Sampled,
+		},
+		{
+			Desc:        "duration computed across all the spans",
+			ThresholdMs: 500,
+			Spans: []spanTimes{
+				{start.Add(100 * time.Millisecond), start.Add(300 * time.Millisecond)},
+				{start, start.Add(200 * time.Millisecond)},
+				{start.Add(400 * time.Millisecond), start.Add(600 * time.Millisecond)},
+			},
+			Decision: Sampled,
+		},
+		{
+			Desc:             "trace within the range",
+			ThresholdMs:      0,
+			UpperThresholdMs: 10,
+			Spans:            []spanTimes{{start, start.Add(10 * time.Millisecond)}},
+			Decision:         Sampled,
+		},
+		{
+			Desc:             "trace longer than the upper threshold",
+			ThresholdMs:      0,
+			UpperThresholdMs: 10,
+			Spans:            []spanTimes{{start, start.Add(11 * time.Millisecond)}},
+			Decision:         NotSampled,
+		},
+		{
+			Desc:        "no spans",
+			ThresholdMs: 0,
+			Decision:    NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			filter, err := NewLatency(zap.NewNop(), c.ThresholdMs, c.UpperThresholdMs)
+			require.NoError(t, err)
+
+			decision, err := filter.Evaluate(traceID, newTraceWithSpanTimes(c.Spans))
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestNewLatency_InvalidThresholds(t *testing.T) {
+	_, err := NewLatency(zap.NewNop(), 100, 50)
+	assert.Error(t, err)
+
+	_, err = NewLatency(zap.NewNop(), -1, 0)
+	assert.Error(t, err)
+}
+
+func TestOnLateArrivingSpans_Latency(t *testing.T) {
+	filter, err := NewLatency(zap.NewNop(), 100, 0)
+	require.NoError(t, err)
+	assert.NoError(t, filter.OnLateArrivingSpans(Sampled, nil))
+}
+
+type spanTimes struct {
+	start, end time.Time
+}
+
+// newTraceWithSpanTimes returns trace data with one batch per span, each span having the given start and end times
+func newTraceWithSpanTimes(spans []spanTimes) *TraceData {
+	var batches []pdata.Traces
+	for _, times := range spans {
+		traces := pdata.NewTraces()
+		traces.ResourceSpans().Resize(1)
+		rs := traces.ResourceSpans().At(0)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		ils := rs.InstrumentationLibrarySpans().At(0)
+		ils.Spans().Resize(1)
+		span := ils.Spans().At(0)
+		span.SetStartTime(pdata.TimestampUnixNano(times.start.UnixNano()))
+		span.SetEndTime(pdata.TimestampUnixNano(times.end.UnixNano()))
+		batches = append(batches, traces)
+	}
+	return &TraceData{ReceivedBatches: batches}
+}
@@ -50,8 +50,8 @@ const (
 	// NotSampled is used to indicate that the decision was already taken
 	// to not sample the data.
 	NotSampled
-	// Dropped is used when data needs to be purged before the sampling policy
-	// had a chance to evaluate it.
+	// Dropped is used to indicate that the trace must not be sampled, regardless
+	// of the decisions of the other policies.
 	Dropped
 )
 
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+const (
+	// The constants below have the same values as the ones used by the probabilistic sampler processor,
+	// so that both make the same decisions for the same trace ID, sampling percentage and hash seed.
+	numHashBuckets        = 0x4000 // Using a power of 2 to avoid division.
+	bitMaskHashBuckets    = numHashBuckets - 1
+	percentageScaleFactor = numHashBuckets / 100.0
+)
+
+type probabilisticSampler struct {
+	scaledSamplingRate uint32
+	hashSeed           uint32
+	logger             *zap.Logger
+}
+
+var _ PolicyEvaluator = (*probabilisticSampler)(nil)
+
+// NewProbabilisticSampler creates a policy evaluator that samples the given percentage of the traces, based on
+// the hash of their trace IDs. With the same percentage and hash seed, it takes the same decisions as the
+// probabilistic sampler processor, so that head and tail sampling are consistent for the same trace.
+func NewProbabilisticSampler(logger *zap.Logger, hashSeed uint32, samplingPercentage float32) PolicyEvaluator {
+	return &probabilisticSampler{
+		scaledSamplingRate: uint32(samplingPercentage * percentageScaleFactor),
+		hashSeed:           hashSeed,
+		logger:             logger,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (ps *probabilisticSampler) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	ps.logger.Debug("Triggering action for late arriving spans in probabilistic filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+// The decision depends only on the trace ID.
+func (ps *probabilisticSampler) Evaluate(traceID pdata.TraceID, _ *TraceData) (Decision, error) {
+	ps.logger.Debug("Evaluating spans in probabilistic filter")
+	tidBytes := traceID.Bytes()
+	if hash(tidBytes[:], ps.hashSeed)&bitMaskHashBuckets < ps.scaledSamplingRate {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// hash is a murmur3 hash function, see http://en.wikipedia.org/wiki/MurmurHash
+// It must be kept identical to the one used by the probabilistic sampler processor, which isn't exported.
+func hash(key []byte, seed uint32) (hash uint32) {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+		c3 = 0x85ebca6b
+		c4 = 0xc2b2ae35
+		r1 = 15
+		r2 = 13
+		m  = 5
+		n  = 0xe6546b64
+	)
+
+	hash = seed
+	iByte := 0
+	for ; iByte+4 <= len(key); iByte += 4 {
+		k := uint32(key[iByte]) | uint32(key[iByte+1])<<8 | uint32(key[iByte+2])<<16 | uint32(key[iByte+3])<<24
+		k *= c1
+		k = (k << r1) | (k >> (32 - r1))
+		k *= c2
+		hash ^= k
+		hash = (hash << r2) | (hash >> (32 - r2))
+		hash = hash*m + n
+	}
+
+	// The trace IDs have a length that is a multiple of 4, so the code below is never expected to be hit
+	// when sampling traces, but it's kept to preserve a correct murmur3 implementation.
+	var remainingBytes uint32
+	switch len(key) - iByte {
+	case 3:
+		remainingBytes += uint32(key[iByte+2]) << 16
+		fallthrough
+	case 2:
+		remainingBytes += uint32(key[iByte+1]) << 8
+		fallthrough
+	case 1:
+		remainingBytes += uint32(key[iByte])
+		remainingBytes *= c1
+		remainingBytes = (remainingBytes << r1) | (remainingBytes >> (32 - r1))
+		remainingBytes *= c2
+		hash ^= remainingBytes
+	}
+
+	hash ^= uint32(len(key))
+	hash ^= hash >> 16
+	hash *= c3
+	hash ^= hash >> 13
+	hash *= c4
+	hash ^= hash >> 16
+	return
+}
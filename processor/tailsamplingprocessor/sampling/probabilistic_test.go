@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/samplingprocessor/probabilisticsamplerprocessor"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+func TestEvaluate_Probabilistic(t *testing.T) {
+	traceIDs := randomTraceIDs(1000)
+
+	for _, tt := range []struct {
+		name               string
+		samplingPercentage float32
+		minSampled         int
+		maxSampled         int
+	}{
+		{name: "nothing", samplingPercentage: 0, minSampled: 0, maxSampled: 0},
+		{name: "everything", samplingPercentage: 100, minSampled: 1000, maxSampled: 1000},
+		{name: "about a third", samplingPercentage: 33, minSampled: 280, maxSampled: 380},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewProbabilisticSampler(zap.NewNop(), 22, tt.samplingPercentage)
+
+			sampled := 0
+			for _, traceID := range traceIDs {
+				decision, err := filter.Evaluate(traceID, &TraceData{})
+				require.NoError(t, err)
+				if decision == Sampled {
+					sampled++
+				}
+			}
+			assert.GreaterOrEqual(t, sampled, tt.minSampled)
+			assert.LessOrEqual(t, sampled, tt.maxSampled)
+		})
+	}
+}
+
+func TestProbabilisticIsConsistentWithProbabilisticSamplerProcessor(t *testing.T) {
+	const samplingPercentage = 25
+	traceIDs := randomTraceIDs(1000)
+
+	for _, hashSeed := range []uint32{0, 22} {
+		// the trace IDs kept by the head sampler
+		factory := probabilisticsamplerprocessor.NewFactory()
+		cfg := factory.CreateDefaultConfig().(*probabilisticsamplerprocessor.Config)
+		cfg.SamplingPercentage = samplingPercentage
+		cfg.HashSeed = hashSeed
+		sink := new(consumertest.TracesSink)
+		headSampler, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, sink)
+		require.NoError(t, err)
+
+		for _, traceID := range traceIDs {
+			require.NoError(t, headSampler.ConsumeTraces(context.Background(), newTraceWithID(traceID)))
+		}
+
+		keptByHead := map[pdata.TraceID]bool{}
+		for _, td := range sink.AllTraces() {
+			rss := td.ResourceSpans()
+			for i := 0; i < rss.Len(); i++ {
+				ilss := rss.At(i).InstrumentationLibrarySpans()
+				for j := 0; j < ilss.Len(); j++ {
+					for k := 0; k < ilss.At(j).Spans().Len(); k++ {
+						keptByHead[ilss.At(j).Spans().At(k).TraceID()] = true
+					}
+				}
+			}
+		}
+
+		// the trace IDs kept by the tail sampling policy
+		filter := NewProbabilisticSampler(zap.NewNop(), hashSeed, samplingPercentage)
+		keptByTail := map[pdata.TraceID]bool{}
+		for _, traceID := range traceIDs {
+			decision, err := filter.Evaluate(traceID, &TraceData{})
+			require.NoError(t, err)
+			if decision == Sampled {
+				keptByTail[traceID] = true
+			}
+		}
+
+		assert.NotEmpty(t, keptByTail)
+		assert.Equal(t, keptByHead, keptByTail)
+	}
+}
+
+func TestOnLateArrivingSpans_Probabilistic(t *testing.T) {
+	filter := NewProbabilisticSampler(zap.NewNop(), 0, 10)
+	err := filter.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
+
+func randomTraceIDs(count int) []pdata.TraceID {
+	r := rand.New(rand.NewSource(1))
+	traceIDs := make([]pdata.TraceID, count)
+	for i := range traceIDs {
+		traceIDs[i] = tracetranslator.UInt64ToTraceID(r.Uint64(), r.Uint64())
+	}
+	return traceIDs
+}
+
+func newTraceWithID(traceID pdata.TraceID) pdata.Traces {
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(1)
+	spans.At(0).SetTraceID(traceID)
+	return traces
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type spanCount struct {
+	minSpans, maxSpans int64
+	logger             *zap.Logger
+}
+
+var _ PolicyEvaluator = (*spanCount)(nil)
+
+// NewSpanCount creates a policy evaluator that samples the traces whose number of spans is outside the
+// [minSpans, maxSpans] range, that is, the traces with fewer than minSpans spans or more than maxSpans spans.
+// A zero maxSpans leaves the range without an upper bound, so that only the small traces are sampled.
+func NewSpanCount(logger *zap.Logger, minSpans, maxSpans int64) (PolicyEvaluator, error) {
+	if minSpans < 0 || maxSpans < 0 {
+		return nil, fmt.Errorf("the span count bounds can't be negative, got min_spans %d and max_spans %d", minSpans, maxSpans)
+	}
+	if maxSpans != 0 && maxSpans < minSpans {
+		return nil, fmt.Errorf("max_spans %d is lower than min_spans %d", maxSpans, minSpans)
+	}
+
+	return &spanCount{
+		minSpans: minSpans,
+		maxSpans: maxSpans,
+		logger:   logger,
+	}, nil
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (sc *spanCount) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	sc.logger.Debug("Triggering action for late arriving spans in span-count filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+// The spans are counted as they are received for the trace, so the batches don't need to be inspected.
+func (sc *spanCount) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	sc.logger.Debug("Evaluating spans in span-count filter")
+	count := atomic.LoadInt64(&trace.SpanCount)
+	if count < sc.minSpans || (sc.maxSpans != 0 && count > sc.maxSpans) {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestEvaluate_SpanCount(t *testing.T) {
+	cases := []struct {
+		Desc      string
+		MinSpans  int64
+		MaxSpans  int64
+		SpanCount int64
+		Decision  Decision
+	}{
+		{
+			Desc:      "fewer spans than the minimum",
+			MinSpans:  2,
+			MaxSpans:  10,
+			SpanCount: 1,
+			Decision:  Sampled,
+		},
+		{
+			Desc:      "as many spans as the minimum",
+			MinSpans:  2,
+			MaxSpans:  10,
+			SpanCount: 2,
+			Decision:  NotSampled,
+		},
+		{
+			Desc:      "as many spans as the maximum",
+			MinSpans:  2,
+			MaxSpans:  10,
+			SpanCount: 10,
+			Decision:  NotSampled,
+		},
+		{
+			Desc:      "more spans than the maximum",
+			MinSpans:  2,
+			MaxSpans:  10,
+			SpanCount: 11,
+			Decision:  Sampled,
+		},
+		{
+			Desc:      "no upper bound",
+			MinSpans:  2,
+			SpanCount: 1000,
+			Decision:  NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			filter, err := NewSpanCount(zap.NewNop(), c.MinSpans, c.MaxSpans)
+			require.NoError(t, err)
+
+			decision, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}), &TraceData{SpanCount: c.SpanCount})
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestSpanCountInvalidBounds(t *testing.T) {
+	_, err := NewSpanCount(zap.NewNop(), 10, 2)
+	assert.Error(t, err)
+
+	_, err = NewSpanCount(zap.NewNop(), -1, 2)
+	assert.Error(t, err)
+}
+
+func TestOnLateArrivingSpans_SpanCount(t *testing.T) {
+	filter, err := NewSpanCount(zap.NewNop(), 1, 2)
+	require.NoError(t, err)
+	err = filter.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
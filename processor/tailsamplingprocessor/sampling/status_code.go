@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type statusCodeFilter struct {
+	statusCodes map[pdata.StatusCode]struct{}
+	logger      *zap.Logger
+}
+
+var _ PolicyEvaluator = (*statusCodeFilter)(nil)
+
+// NewStatusCodeFilter creates a policy evaluator that samples all traces with a span
+// having one of the given status codes, which can be ERROR, OK and UNSET.
+func NewStatusCodeFilter(logger *zap.Logger, statusCodeStrings []string) (PolicyEvaluator, error) {
+	if len(statusCodeStrings) == 0 {
+		return nil, errors.New("expected at least one status code to filter on")
+	}
+
+	statusCodes := make(map[pdata.StatusCode]struct{})
+	for _, statusCode := range statusCodeStrings {
+		switch statusCode {
+		case "ERROR":
+			statusCodes[pdata.StatusCodeError] = struct{}{}
+		case "OK":
+			statusCodes[pdata.StatusCodeOk] = struct{}{}
+		case "UNSET":
+			statusCodes[pdata.StatusCodeUnset] = struct{}{}
+		default:
+			return nil, fmt.Errorf("unknown status code %q, supported: ERROR, OK, UNSET", statusCode)
+		}
+	}
+
+	return &statusCodeFilter{
+		statusCodes: statusCodes,
+		logger:      logger,
+	}, nil
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (scf *statusCodeFilter) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	scf.logger.Debug("Triggering action for late arriving spans in status code filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (scf *statusCodeFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	scf.logger.Debug("Evaluating spans in status code filter")
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.Unlock()
+	for _, batch := range batches {
+		rspans := batch.ResourceSpans()
+		for i := 0; i < rspans.Len(); i++ {
+			ilss := rspans.At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					if _, ok := scf.statusCodes[spans.At(k).Status().Code()]; ok {
+						return Sampled, nil
+					}
+				}
+			}
+		}
+	}
+	return NotSampled, nil
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestStatusCodeFilter(t *testing.T) {
+	cases := []struct {
+		Desc        string
+		StatusCodes []string
+		Spans       []pdata.StatusCode
+		Decision    Decision
+	}{
+		{
+			Desc:        "span with an error",
+			StatusCodes: []string{"ERROR"},
+			Spans:       []pdata.StatusCode{pdata.StatusCodeOk, pdata.StatusCodeError},
+			Decision:    Sampled,
+		},
+		{
+			Desc:        "spans without an error",
+			StatusCodes: []string{"ERROR"},
+			Spans:       []pdata.StatusCode{pdata.StatusCodeOk, pdata.StatusCodeUnset},
+			Decision:    NotSampled,
+		},
+		{
+			Desc:        "span with one of the status codes",
+			StatusCodes: []string{"ERROR", "UNSET"},
+			Spans:       []pdata.StatusCode{pdata.StatusCodeOk, pdata.StatusCodeUnset},
+			Decision:    Sampled,
+		},
+		{
+			Desc:        "only ok spans",
+			StatusCodes: []string{"OK"},
+			Spans:       []pdata.StatusCode{pdata.StatusCodeOk},
+			Decision:    Sampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			filter, err := NewStatusCodeFilter(zap.NewNop(), c.StatusCodes)
+			require.NoError(t, err)
+
+			traces := pdata.NewTraces()
+			traces.ResourceSpans().Resize(1)
+			rs := traces.ResourceSpans().At(0)
+			rs.InstrumentationLibrarySpans().Resize(1)
+			spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+			spans.Resize(len(c.Spans))
+			for i, statusCode := range c.Spans {
+				spans.At(i).Status().SetCode(statusCode)
+			}
+			trace := &TraceData{ReceivedBatches: []pdata.Traces{traces}}
+
+			decision, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}), trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestStatusCodeFilterInvalidStatusCodes(t *testing.T) {
+	_, err := NewStatusCodeFilter(zap.NewNop(), []string{"ERROR", "FAILED"})
+	assert.Error(t, err)
+
+	_, err = NewStatusCodeFilter(zap.NewNop(), nil)
+	assert.Error(t, err)
+}
+
+func TestOnLateArrivingSpans_StatusCode(t *testing.T) {
+	filter, err := NewStatusCodeFilter(zap.NewNop(), []string{"ERROR"})
+	require.NoError(t, err)
+	err = filter.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
@@ -15,32 +15,74 @@
 package sampling
 
 import (
+	"fmt"
+	"regexp"
+
+	lru "github.com/hashicorp/golang-lru"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 )
 
+// defaultCacheMaxSize is the number of attribute values whose regex matching results are kept,
+// when no cache size is given.
+const defaultCacheMaxSize = 128
+
 type stringAttributeFilter struct {
 	key    string
 	values map[string]struct{}
-	logger *zap.Logger
+	// regexes replace the values when the regex matching is enabled.
+	regexes []*regexp.Regexp
+	// cache holds the matching results for the recently seen attribute values, so that
+	// the regexes don't have to be evaluated for each span.
+	cache       *lru.Cache
+	invertMatch bool
+	logger      *zap.Logger
 }
 
 var _ PolicyEvaluator = (*stringAttributeFilter)(nil)
 
 // NewStringAttributeFilter creates a policy evaluator that samples all traces with
-// the given attribute in the given numeric range.
-func NewStringAttributeFilter(logger *zap.Logger, key string, values []string) PolicyEvaluator {
-	valuesMap := make(map[string]struct{})
+// the given attribute matching one of the given values. When regexMatching is enabled, the values
+// are regular expressions, and the results of matching up to cacheMaxSize attribute values are cached.
+// When invertMatch is enabled, the traces with a matching attribute are the ones not sampled.
+func NewStringAttributeFilter(logger *zap.Logger, key string, values []string, regexMatching bool, cacheMaxSize int, invertMatch bool) (PolicyEvaluator, error) {
+	saf := &stringAttributeFilter{
+		key:         key,
+		invertMatch: invertMatch,
+		logger:      logger,
+	}
+
+	if !regexMatching {
+		saf.values = make(map[string]struct{})
+		for _, value := range values {
+			if value != "" {
+				saf.values[value] = struct{}{}
+			}
+		}
+		return saf, nil
+	}
+
 	for _, value := range values {
-		if value != "" {
-			valuesMap[value] = struct{}{}
+		if value == "" {
+			continue
+		}
+		regex, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q for attribute %s: %w", value, key, err)
 		}
+		saf.regexes = append(saf.regexes, regex)
+	}
+
+	if cacheMaxSize <= 0 {
+		cacheMaxSize = defaultCacheMaxSize
 	}
-	return &stringAttributeFilter{
-		key:    key,
-		values: valuesMap,
-		logger: logger,
+	cache, err := lru.New(cacheMaxSize)
+	if err != nil {
+		return nil, err
 	}
+	saf.cache = cache
+
+	return saf, nil
 }
 
 // OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
@@ -55,6 +97,14 @@ func (saf *stringAttributeFilter) OnLateArrivingSpans(Decision, []*pdata.Span) e
 // Evaluate looks at the trace data and returns a corresponding SamplingDecision.
 func (saf *stringAttributeFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
 	saf.logger.Debug("Evaluting spans in string-tag filter")
+	if saf.hasMatch(trace) != saf.invertMatch {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// hasMatch returns whether the trace has the attribute set to one of the values, either on its resources or spans.
+func (saf *stringAttributeFilter) hasMatch(trace *TraceData) bool {
 	trace.Lock()
 	batches := trace.ReceivedBatches
 	trace.Unlock()
@@ -65,8 +115,8 @@ func (saf *stringAttributeFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (D
 			rs := rspans.At(i)
 			resource := rs.Resource()
 			if v, ok := resource.Attributes().Get(saf.key); ok {
-				if _, ok := saf.values[v.StringVal()]; ok {
-					return Sampled, nil
+				if saf.matches(v.StringVal()) {
+					return true
 				}
 			}
 
@@ -78,8 +128,8 @@ func (saf *stringAttributeFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (D
 					if v, ok := span.Attributes().Get(saf.key); ok {
 						truncableStr := v.StringVal()
 						if len(truncableStr) > 0 {
-							if _, ok := saf.values[truncableStr]; ok {
-								return Sampled, nil
+							if saf.matches(truncableStr) {
+								return true
 							}
 						}
 					}
@@ -88,5 +138,26 @@ func (saf *stringAttributeFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (D
 			}
 		}
 	}
-	return NotSampled, nil
+	return false
+}
+
+func (saf *stringAttributeFilter) matches(value string) bool {
+	if saf.regexes == nil {
+		_, ok := saf.values[value]
+		return ok
+	}
+
+	if matched, ok := saf.cache.Get(value); ok {
+		return matched.(bool)
+	}
+
+	matched := false
+	for _, regex := range saf.regexes {
+		if regex.MatchString(value) {
+			matched = true
+			break
+		}
+	}
+	saf.cache.Add(value, matched)
+	return matched
 }
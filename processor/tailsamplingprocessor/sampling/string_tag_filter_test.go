@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 )
@@ -25,7 +26,8 @@ import (
 func TestStringTagFilter(t *testing.T) {
 
 	var empty = map[string]pdata.AttributeValue{}
-	filter := NewStringAttributeFilter(zap.NewNop(), "example", []string{"value"})
+	filter, err := NewStringAttributeFilter(zap.NewNop(), "example", []string{"value"}, false, 0, false)
+	require.NoError(t, err)
 
 	cases := []struct {
 		Desc     string
@@ -73,6 +75,96 @@ func TestStringTagFilter(t *testing.T) {
 	}
 }
 
+func TestStringTagFilterRegexMatching(t *testing.T) {
+	var empty = map[string]pdata.AttributeValue{}
+	filter, err := NewStringAttributeFilter(zap.NewNop(), "http.url", []string{"^/api/v[0-9]+/", "health$"}, true, 0, false)
+	require.NoError(t, err)
+
+	cases := []struct {
+		Desc     string
+		Trace    *TraceData
+		Decision Decision
+	}{
+		{
+			Desc:     "matching node attribute",
+			Trace:    newTraceStringAttrs(map[string]pdata.AttributeValue{"http.url": pdata.NewAttributeValueString("/api/v1/users")}, "", ""),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "matching span attribute",
+			Trace:    newTraceStringAttrs(empty, "http.url", "/status/health"),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "nonmatching span attribute",
+			Trace:    newTraceStringAttrs(empty, "http.url", "/static/v1/index.html"),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "values aren't matched exactly",
+			Trace:    newTraceStringAttrs(empty, "http.url", "^/api/v[0-9]+/"),
+			Decision: NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			// evaluate twice, so that the second decision comes from the cache
+			for i := 0; i < 2; i++ {
+				decision, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}), c.Trace)
+				assert.NoError(t, err)
+				assert.Equal(t, c.Decision, decision)
+			}
+		})
+	}
+}
+
+func TestStringTagFilterRegexCacheIsBounded(t *testing.T) {
+	filter, err := NewStringAttributeFilter(zap.NewNop(), "example", []string{"^value"}, true, 2, false)
+	require.NoError(t, err)
+
+	for _, value := range []string{"value-1", "value-2", "value-3", "other"} {
+		_, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), newTraceStringAttrs(map[string]pdata.AttributeValue{}, "example", value))
+		require.NoError(t, err)
+	}
+
+	cache := filter.(*stringAttributeFilter).cache
+	assert.Equal(t, 2, cache.Len())
+	assert.True(t, cache.Contains("other"))
+	assert.False(t, cache.Contains("value-1"))
+}
+
+func TestStringTagFilterInvertMatch(t *testing.T) {
+	var empty = map[string]pdata.AttributeValue{}
+
+	for _, regexMatching := range []bool{false, true} {
+		filter, err := NewStringAttributeFilter(zap.NewNop(), "http.url", []string{"/health"}, regexMatching, 0, true)
+		require.NoError(t, err)
+
+		decision, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), newTraceStringAttrs(empty, "http.url", "/health"))
+		assert.NoError(t, err)
+		assert.Equal(t, NotSampled, decision)
+
+		decision, err = filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), newTraceStringAttrs(empty, "http.url", "/checkout"))
+		assert.NoError(t, err)
+		assert.Equal(t, Sampled, decision)
+
+		// the traces without the attribute don't match either
+		decision, err = filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), newTraceStringAttrs(empty, "other", "/health"))
+		assert.NoError(t, err)
+		assert.Equal(t, Sampled, decision)
+	}
+}
+
+func TestStringTagFilterInvalidRegex(t *testing.T) {
+	_, err := NewStringAttributeFilter(zap.NewNop(), "example", []string{"valid", "(invalid"}, true, 0, false)
+	assert.Error(t, err)
+
+	// the values aren't compiled when the regex matching isn't enabled
+	_, err = NewStringAttributeFilter(zap.NewNop(), "example", []string{"(invalid"}, false, 0, false)
+	assert.NoError(t, err)
+}
+
 func newTraceStringAttrs(nodeAttrs map[string]pdata.AttributeValue, spanAttrKey string, spanAttrValue string) *TraceData {
 	var traceBatches []pdata.Traces
 	traces := pdata.NewTraces()
@@ -95,7 +187,8 @@ func newTraceStringAttrs(nodeAttrs map[string]pdata.AttributeValue, spanAttrKey
 }
 
 func TestOnLateArrivingSpans_StringAttribute(t *testing.T) {
-	filter := NewStringAttributeFilter(zap.NewNop(), "example", []string{"value"})
-	err := filter.OnLateArrivingSpans(NotSampled, nil)
+	filter, err := NewStringAttributeFilter(zap.NewNop(), "example", []string{"value"}, false, 0, false)
+	require.NoError(t, err)
+	err = filter.OnLateArrivingSpans(NotSampled, nil)
 	assert.Nil(t, err)
 }
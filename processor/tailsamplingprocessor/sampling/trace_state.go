@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type traceStateFilter struct {
+	key    string
+	values map[string]struct{}
+	logger *zap.Logger
+}
+
+var _ PolicyEvaluator = (*traceStateFilter)(nil)
+
+// NewTraceStateFilter creates a policy evaluator that samples all traces with a span whose W3C
+// tracestate has the given key set to one of the given values.
+func NewTraceStateFilter(logger *zap.Logger, key string, values []string) (PolicyEvaluator, error) {
+	if key == "" {
+		return nil, errors.New("expected a tracestate key to filter on")
+	}
+	if len(values) == 0 {
+		return nil, errors.New("expected at least one tracestate value to filter on")
+	}
+
+	valuesMap := make(map[string]struct{})
+	for _, value := range values {
+		valuesMap[value] = struct{}{}
+	}
+
+	return &traceStateFilter{
+		key:    key,
+		values: valuesMap,
+		logger: logger,
+	}, nil
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (tsf *traceStateFilter) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	tsf.logger.Debug("Triggering action for late arriving spans in trace state filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (tsf *traceStateFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	tsf.logger.Debug("Evaluating spans in trace state filter")
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.Unlock()
+	for _, batch := range batches {
+		rspans := batch.ResourceSpans()
+		for i := 0; i < rspans.Len(); i++ {
+			ilss := rspans.At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					if value, ok := traceStateValue(string(spans.At(k).TraceState()), tsf.key); ok {
+						if _, ok := tsf.values[value]; ok {
+							return Sampled, nil
+						}
+					}
+				}
+			}
+		}
+	}
+	return NotSampled, nil
+}
+
+// traceStateValue returns the value of the given key in the given W3C tracestate, that is, a comma-separated list
+// of key=value entries. The malformed entries are skipped.
+func traceStateValue(traceState, key string) (string, bool) {
+	for _, entry := range strings.Split(traceState, ",") {
+		entry = strings.TrimSpace(entry)
+		separator := strings.IndexByte(entry, '=')
+		if separator <= 0 {
+			// either empty, without a value or without a key
+			continue
+		}
+		if entry[:separator] == key {
+			return entry[separator+1:], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestEvaluate_TraceState(t *testing.T) {
+	cases := []struct {
+		Desc       string
+		TraceState string
+		Decision   Decision
+	}{
+		{
+			Desc:       "matching value",
+			TraceState: "ot=r:8",
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "matching value among other entries",
+			TraceState: "vendor1=abc, ot=r:4 ,vendor2=def",
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "value not listed",
+			TraceState: "ot=r:2",
+			Decision:   NotSampled,
+		},
+		{
+			Desc:       "key not present",
+			TraceState: "vendor1=r:8",
+			Decision:   NotSampled,
+		},
+		{
+			Desc:       "malformed entries are skipped",
+			TraceState: "garbage,,=r:8,ot,ot=r:8",
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "empty tracestate",
+			TraceState: "",
+			Decision:   NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			filter, err := NewTraceStateFilter(zap.NewNop(), "ot", []string{"r:8", "r:4"})
+			require.NoError(t, err)
+
+			decision, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}), newTraceWithTraceState(c.TraceState))
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestNewTraceStateFilter_InvalidConfig(t *testing.T) {
+	_, err := NewTraceStateFilter(zap.NewNop(), "", []string{"r:8"})
+	assert.Error(t, err)
+
+	_, err = NewTraceStateFilter(zap.NewNop(), "ot", nil)
+	assert.Error(t, err)
+}
+
+func TestOnLateArrivingSpans_TraceState(t *testing.T) {
+	filter, err := NewTraceStateFilter(zap.NewNop(), "ot", []string{"r:8"})
+	require.NoError(t, err)
+	err = filter.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
+
+func newTraceWithTraceState(traceState string) *TraceData {
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	ils.Spans().At(0).SetTraceState(pdata.TraceState(traceState))
+	return &TraceData{
+		ReceivedBatches: []pdata.Traces{traces},
+	}
+}
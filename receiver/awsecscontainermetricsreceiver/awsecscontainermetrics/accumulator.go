@@ -41,15 +41,15 @@ func (acc *metricDataAccumulator) getMetricsData(containerStatsMap map[string]*C
 
 		containerMetrics := getContainerMetrics(stats, logger)
 		if containerMetadata.Limits.Memory != nil {
-			containerMetrics.MemoryReserved = *containerMetadata.Limits.Memory
+			containerMetrics.MemoryReserved = containerMetadata.Limits.Memory
 		}
 
 		if containerMetadata.Limits.CPU != nil {
-			containerMetrics.CPUReserved = *containerMetadata.Limits.CPU
+			containerMetrics.CPUReserved = containerMetadata.Limits.CPU
 		}
 
-		if containerMetrics.CPUReserved > 0 {
-			containerMetrics.CPUUtilized = (containerMetrics.CPUUtilized / containerMetrics.CPUReserved)
+		if containerMetrics.CPUReserved != nil && *containerMetrics.CPUReserved > 0 && containerMetrics.CPUUtilized != nil {
+			containerMetrics.CPUUtilized = float64Ptr(*containerMetrics.CPUUtilized / *containerMetrics.CPUReserved)
 		}
 
 		containerResource := containerResource(containerMetadata)
@@ -64,22 +64,24 @@ func (acc *metricDataAccumulator) getMetricsData(containerStatsMap map[string]*C
 
 	// Overwrite Memory limit with task level limit
 	if metadata.Limits.Memory != nil {
-		taskMetrics.MemoryReserved = *metadata.Limits.Memory
+		taskMetrics.MemoryReserved = metadata.Limits.Memory
 	}
 
-	taskMetrics.CPUReserved = taskMetrics.CPUReserved / CPUsInVCpu
+	if taskMetrics.CPUReserved != nil {
+		taskMetrics.CPUReserved = float64Ptr(*taskMetrics.CPUReserved / CPUsInVCpu)
+	}
 
 	// Overwrite CPU limit with task level limit
 	if metadata.Limits.CPU != nil {
-		taskMetrics.CPUReserved = *metadata.Limits.CPU
+		taskMetrics.CPUReserved = metadata.Limits.CPU
 	}
 
 	// taskMetrics.CPUReserved cannot be zero. In ECS, user needs to set CPU limit
 	// at least in one place (either in task level or in container level). If the
 	// task level CPULimit is not present, we calculate it from the summation of
 	// all container CPU limits.
-	if taskMetrics.CPUReserved > 0 {
-		taskMetrics.CPUUtilized = ((taskMetrics.CPUUsageInVCPU / taskMetrics.CPUReserved) * 100)
+	if taskMetrics.CPUReserved != nil && *taskMetrics.CPUReserved > 0 && taskMetrics.CPUUsageInVCPU != nil {
+		taskMetrics.CPUUtilized = float64Ptr((*taskMetrics.CPUUsageInVCPU / *taskMetrics.CPUReserved) * 100)
 	}
 
 	acc.accumulate(convertToOTLPMetrics(TaskPrefix, taskMetrics, taskResource, timestamp))
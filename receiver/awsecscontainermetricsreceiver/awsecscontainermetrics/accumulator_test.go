@@ -214,3 +214,39 @@ func TestGetMetricsDataCpuReservedZero(t *testing.T) {
 	acc.getMetricsData(cstats, tm, logger)
 	require.Less(t, 0, len(acc.mds))
 }
+
+func TestGetMetricsDataMissingStorageStats(t *testing.T) {
+	fargateStats := ContainerStats{
+		Name:        "test",
+		ID:          "001",
+		Memory:      &mem,
+		Disk:        &DiskStats{},
+		Network:     net,
+		NetworkRate: &netRate,
+		CPU:         &cpuStats,
+	}
+	tm = TaskMetadata{
+		Cluster:    "cluster-1",
+		TaskARN:    "arn:aws:some-value/001",
+		Family:     "task-def-family-1",
+		Revision:   "task-def-version",
+		LaunchType: "FARGATE",
+		Containers: []ContainerMetadata{
+			{ContainerName: "container-1", DockerID: "001", DockerName: "docker-container-1", Limits: Limit{CPU: &f, Memory: &v}},
+		},
+		Limits: Limit{CPU: &f, Memory: &v},
+	}
+
+	fargateAcc := metricDataAccumulator{}
+	fargateAcc.getMetricsData(map[string]*ContainerStats{"001": &fargateStats}, tm, logger)
+	require.Equal(t, 2, len(fargateAcc.mds))
+
+	for _, md := range fargateAcc.mds {
+		ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+		require.Less(t, 0, ilms.Len())
+		for i := 0; i < ilms.Len(); i++ {
+			name := ilms.At(i).Metrics().At(0).Name()
+			require.NotContains(t, name, "storage")
+		}
+	}
+}
@@ -14,36 +14,37 @@
 
 package awsecscontainermetrics
 
-// ECSMetrics defines the structure container/task level metrics
+// ECSMetrics defines the structure container/task level metrics. The metrics whose stats are missing, like some of
+// the storage stats on Fargate, are nil and aren't emitted.
 type ECSMetrics struct {
-	MemoryUsage    uint64
-	MemoryMaxUsage uint64
-	MemoryLimit    uint64
-	MemoryUtilized uint64
-	MemoryReserved uint64
+	MemoryUsage    *uint64
+	MemoryMaxUsage *uint64
+	MemoryLimit    *uint64
+	MemoryUtilized *uint64
+	MemoryReserved *uint64
 
-	CPUTotalUsage        uint64
-	CPUUsageInKernelmode uint64
-	CPUUsageInUserMode   uint64
-	CPUOnlineCpus        uint64
-	SystemCPUUsage       uint64
-	NumOfCPUCores        uint64
-	CPUReserved          float64
-	CPUUtilized          float64
-	CPUUsageInVCPU       float64
+	CPUTotalUsage        *uint64
+	CPUUsageInKernelmode *uint64
+	CPUUsageInUserMode   *uint64
+	CPUOnlineCpus        *uint64
+	SystemCPUUsage       *uint64
+	NumOfCPUCores        *uint64
+	CPUReserved          *float64
+	CPUUtilized          *float64
+	CPUUsageInVCPU       *float64
 
-	NetworkRateRxBytesPerSecond float64
-	NetworkRateTxBytesPerSecond float64
+	NetworkRateRxBytesPerSecond *float64
+	NetworkRateTxBytesPerSecond *float64
 
-	NetworkRxBytes   uint64
-	NetworkRxPackets uint64
-	NetworkRxErrors  uint64
-	NetworkRxDropped uint64
-	NetworkTxBytes   uint64
-	NetworkTxPackets uint64
-	NetworkTxErrors  uint64
-	NetworkTxDropped uint64
+	NetworkRxBytes   *uint64
+	NetworkRxPackets *uint64
+	NetworkRxErrors  *uint64
+	NetworkRxDropped *uint64
+	NetworkTxBytes   *uint64
+	NetworkTxPackets *uint64
+	NetworkTxErrors  *uint64
+	NetworkTxDropped *uint64
 
-	StorageReadBytes  uint64
-	StorageWriteBytes uint64
+	StorageReadBytes  *uint64
+	StorageWriteBytes *uint64
 }
@@ -16,48 +16,54 @@ package awsecscontainermetrics
 
 import "go.uber.org/zap"
 
-// getContainerMetrics generate ECS Container metrics from Container stats
+// getContainerMetrics generate ECS Container metrics from Container stats. The metrics whose stats are missing are
+// left nil.
 func getContainerMetrics(stats *ContainerStats, logger *zap.Logger) ECSMetrics {
 	m := ECSMetrics{}
 
 	if stats.Memory != nil {
-		m.MemoryUsage = *stats.Memory.Usage
-		m.MemoryMaxUsage = *stats.Memory.MaxUsage
-		m.MemoryLimit = *stats.Memory.Limit
+		m.MemoryUsage = stats.Memory.Usage
+		m.MemoryMaxUsage = stats.Memory.MaxUsage
+		m.MemoryLimit = stats.Memory.Limit
 
-		if stats.Memory.Stats != nil {
-			m.MemoryUtilized = (*stats.Memory.Usage - stats.Memory.Stats["cache"]) / BytesInMiB
+		if stats.Memory.Usage != nil && stats.Memory.Stats != nil {
+			m.MemoryUtilized = uint64Ptr((*stats.Memory.Usage - stats.Memory.Stats["cache"]) / BytesInMiB)
 		}
 	} else {
 		logger.Debug("Nil memory stats found for docker container:" + stats.Name)
 	}
 
 	if stats.CPU != nil && stats.CPU.CPUUsage != nil {
-		numOfCores := (uint64)(len(stats.CPU.CPUUsage.PerCPUUsage))
-		timeDiffSinceLastRead := (float64)(stats.Read.Sub(stats.PreviousRead).Nanoseconds())
+		m.CPUTotalUsage = stats.CPU.CPUUsage.TotalUsage
+		m.CPUUsageInKernelmode = stats.CPU.CPUUsage.UsageInKernelmode
+		m.CPUUsageInUserMode = stats.CPU.CPUUsage.UsageInUserMode
+		m.CPUOnlineCpus = stats.CPU.OnlineCpus
+		m.SystemCPUUsage = stats.CPU.SystemCPUUsage
+
+		if len(stats.CPU.CPUUsage.PerCPUUsage) > 0 {
+			m.NumOfCPUCores = uint64Ptr((uint64)(len(stats.CPU.CPUUsage.PerCPUUsage)))
+		}
+
+		if stats.CPU.CPUUsage.TotalUsage != nil && stats.PreviousCPU != nil && stats.PreviousCPU.CPUUsage != nil &&
+			stats.PreviousCPU.CPUUsage.TotalUsage != nil {
+			timeDiffSinceLastRead := (float64)(stats.Read.Sub(stats.PreviousRead).Nanoseconds())
+
+			cpuUsageInVCpu := float64(0)
+			if timeDiffSinceLastRead > 0 {
+				cpuDelta := (float64)(*stats.CPU.CPUUsage.TotalUsage - *stats.PreviousCPU.CPUUsage.TotalUsage)
+				cpuUsageInVCpu = cpuDelta / timeDiffSinceLastRead
+			}
 
-		cpuUsageInVCpu := float64(0)
-		if timeDiffSinceLastRead > 0 {
-			cpuDelta := (float64)(*stats.CPU.CPUUsage.TotalUsage - *stats.PreviousCPU.CPUUsage.TotalUsage)
-			cpuUsageInVCpu = cpuDelta / timeDiffSinceLastRead
+			m.CPUUsageInVCPU = float64Ptr(cpuUsageInVCpu)
+			m.CPUUtilized = float64Ptr(cpuUsageInVCpu * 100)
 		}
-		cpuUtilized := cpuUsageInVCpu * 100
-
-		m.CPUTotalUsage = *stats.CPU.CPUUsage.TotalUsage
-		m.CPUUsageInKernelmode = *stats.CPU.CPUUsage.UsageInKernelmode
-		m.CPUUsageInUserMode = *stats.CPU.CPUUsage.UsageInUserMode
-		m.NumOfCPUCores = numOfCores
-		m.CPUOnlineCpus = *stats.CPU.OnlineCpus
-		m.SystemCPUUsage = *stats.CPU.SystemCPUUsage
-		m.CPUUsageInVCPU = cpuUsageInVCpu
-		m.CPUUtilized = cpuUtilized
 	} else {
 		logger.Debug("Nil CPUUsage stats found for docker container:" + stats.Name)
 	}
 
 	if stats.NetworkRate != nil {
-		m.NetworkRateRxBytesPerSecond = *stats.NetworkRate.RxBytesPerSecond
-		m.NetworkRateTxBytesPerSecond = *stats.NetworkRate.TxBytesPerSecond
+		m.NetworkRateRxBytesPerSecond = stats.NetworkRate.RxBytesPerSecond
+		m.NetworkRateTxBytesPerSecond = stats.NetworkRate.TxBytesPerSecond
 	} else {
 		logger.Debug("Nil NetworkRate stats found for docker container:" + stats.Name)
 	}
@@ -79,10 +85,9 @@ func getContainerMetrics(stats *ContainerStats, logger *zap.Logger) ECSMetrics {
 	}
 
 	if stats.Disk != nil {
-		storageReadBytes, storageWriteBytes := extractStorageUsage(stats.Disk)
-
-		m.StorageReadBytes = storageReadBytes
-		m.StorageWriteBytes = storageWriteBytes
+		m.StorageReadBytes, m.StorageWriteBytes = extractStorageUsage(stats.Disk)
+	} else {
+		logger.Debug("Nil Disk stats found for docker container:" + stats.Name)
 	}
 
 	return m
@@ -90,36 +95,38 @@ func getContainerMetrics(stats *ContainerStats, logger *zap.Logger) ECSMetrics {
 
 // Followed ECS Agent calculations
 // https://github.com/aws/amazon-ecs-agent/blob/1ebf0604c13013596cfd4eb239574a85890b13e8/agent/stats/utils.go#L30
-func getNetworkStats(stats map[string]NetworkStats) [8]uint64 {
-	var netStatArray [8]uint64
+// Each stat is nil when none of the networks report it.
+func getNetworkStats(stats map[string]NetworkStats) [8]*uint64 {
+	var netStatArray [8]*uint64
 	for _, netStat := range stats {
-		netStatArray[0] += *netStat.RxBytes
-		netStatArray[1] += *netStat.RxPackets
-		netStatArray[2] += *netStat.RxErrors
-		netStatArray[3] += *netStat.RxDropped
-
-		netStatArray[4] += *netStat.TxBytes
-		netStatArray[5] += *netStat.TxPackets
-		netStatArray[6] += *netStat.TxErrors
-		netStatArray[7] += *netStat.TxDropped
+		addUint64(&netStatArray[0], netStat.RxBytes)
+		addUint64(&netStatArray[1], netStat.RxPackets)
+		addUint64(&netStatArray[2], netStat.RxErrors)
+		addUint64(&netStatArray[3], netStat.RxDropped)
+
+		addUint64(&netStatArray[4], netStat.TxBytes)
+		addUint64(&netStatArray[5], netStat.TxPackets)
+		addUint64(&netStatArray[6], netStat.TxErrors)
+		addUint64(&netStatArray[7], netStat.TxDropped)
 	}
 	return netStatArray
 }
 
 // Followed ECS Agent calculations
 // https://github.com/aws/amazon-ecs-agent/blob/1ebf0604c13013596cfd4eb239574a85890b13e8/agent/stats/utils_unix.go#L48
-func extractStorageUsage(stats *DiskStats) (uint64, uint64) {
-	var readBytes, writeBytes uint64
+// The read or write bytes are nil when they aren't reported, as for some tasks on Fargate.
+func extractStorageUsage(stats *DiskStats) (*uint64, *uint64) {
+	var readBytes, writeBytes *uint64
 	if stats == nil {
-		return uint64(0), uint64(0)
+		return nil, nil
 	}
 
 	for _, blockStat := range stats.IoServiceBytesRecursives {
 		switch op := blockStat.Op; op {
 		case "Read":
-			readBytes = *blockStat.Value
+			readBytes = blockStat.Value
 		case "Write":
-			writeBytes = *blockStat.Value
+			writeBytes = blockStat.Value
 		default:
 			//ignoring "Async", "Total", "Sum", etc
 			continue
@@ -129,34 +136,64 @@ func extractStorageUsage(stats *DiskStats) (uint64, uint64) {
 }
 
 func aggregateTaskMetrics(taskMetrics *ECSMetrics, conMetrics ECSMetrics) {
-	taskMetrics.MemoryUsage += conMetrics.MemoryUsage
-	taskMetrics.MemoryMaxUsage += conMetrics.MemoryMaxUsage
-	taskMetrics.MemoryLimit += conMetrics.MemoryLimit
-	taskMetrics.MemoryReserved += conMetrics.MemoryReserved
-	taskMetrics.MemoryUtilized += conMetrics.MemoryUtilized
-
-	taskMetrics.CPUTotalUsage += conMetrics.CPUTotalUsage
-	taskMetrics.CPUUsageInKernelmode += conMetrics.CPUUsageInKernelmode
-	taskMetrics.CPUUsageInUserMode += conMetrics.CPUUsageInUserMode
-	taskMetrics.NumOfCPUCores += conMetrics.NumOfCPUCores
-	taskMetrics.CPUOnlineCpus += conMetrics.CPUOnlineCpus
-	taskMetrics.SystemCPUUsage += conMetrics.SystemCPUUsage
-	taskMetrics.CPUReserved += conMetrics.CPUReserved
-	taskMetrics.CPUUsageInVCPU += conMetrics.CPUUsageInVCPU
-
-	taskMetrics.NetworkRateRxBytesPerSecond += conMetrics.NetworkRateRxBytesPerSecond
-	taskMetrics.NetworkRateTxBytesPerSecond += conMetrics.NetworkRateTxBytesPerSecond
-
-	taskMetrics.NetworkRxBytes += conMetrics.NetworkRxBytes
-	taskMetrics.NetworkRxPackets += conMetrics.NetworkRxPackets
-	taskMetrics.NetworkRxErrors += conMetrics.NetworkRxErrors
-	taskMetrics.NetworkRxDropped += conMetrics.NetworkRxDropped
-
-	taskMetrics.NetworkTxBytes += conMetrics.NetworkTxBytes
-	taskMetrics.NetworkTxPackets += conMetrics.NetworkTxPackets
-	taskMetrics.NetworkTxErrors += conMetrics.NetworkTxErrors
-	taskMetrics.NetworkTxDropped += conMetrics.NetworkTxDropped
-
-	taskMetrics.StorageReadBytes += conMetrics.StorageReadBytes
-	taskMetrics.StorageWriteBytes += conMetrics.StorageWriteBytes
+	addUint64(&taskMetrics.MemoryUsage, conMetrics.MemoryUsage)
+	addUint64(&taskMetrics.MemoryMaxUsage, conMetrics.MemoryMaxUsage)
+	addUint64(&taskMetrics.MemoryLimit, conMetrics.MemoryLimit)
+	addUint64(&taskMetrics.MemoryReserved, conMetrics.MemoryReserved)
+	addUint64(&taskMetrics.MemoryUtilized, conMetrics.MemoryUtilized)
+
+	addUint64(&taskMetrics.CPUTotalUsage, conMetrics.CPUTotalUsage)
+	addUint64(&taskMetrics.CPUUsageInKernelmode, conMetrics.CPUUsageInKernelmode)
+	addUint64(&taskMetrics.CPUUsageInUserMode, conMetrics.CPUUsageInUserMode)
+	addUint64(&taskMetrics.NumOfCPUCores, conMetrics.NumOfCPUCores)
+	addUint64(&taskMetrics.CPUOnlineCpus, conMetrics.CPUOnlineCpus)
+	addUint64(&taskMetrics.SystemCPUUsage, conMetrics.SystemCPUUsage)
+	addFloat64(&taskMetrics.CPUReserved, conMetrics.CPUReserved)
+	addFloat64(&taskMetrics.CPUUsageInVCPU, conMetrics.CPUUsageInVCPU)
+
+	addFloat64(&taskMetrics.NetworkRateRxBytesPerSecond, conMetrics.NetworkRateRxBytesPerSecond)
+	addFloat64(&taskMetrics.NetworkRateTxBytesPerSecond, conMetrics.NetworkRateTxBytesPerSecond)
+
+	addUint64(&taskMetrics.NetworkRxBytes, conMetrics.NetworkRxBytes)
+	addUint64(&taskMetrics.NetworkRxPackets, conMetrics.NetworkRxPackets)
+	addUint64(&taskMetrics.NetworkRxErrors, conMetrics.NetworkRxErrors)
+	addUint64(&taskMetrics.NetworkRxDropped, conMetrics.NetworkRxDropped)
+
+	addUint64(&taskMetrics.NetworkTxBytes, conMetrics.NetworkTxBytes)
+	addUint64(&taskMetrics.NetworkTxPackets, conMetrics.NetworkTxPackets)
+	addUint64(&taskMetrics.NetworkTxErrors, conMetrics.NetworkTxErrors)
+	addUint64(&taskMetrics.NetworkTxDropped, conMetrics.NetworkTxDropped)
+
+	addUint64(&taskMetrics.StorageReadBytes, conMetrics.StorageReadBytes)
+	addUint64(&taskMetrics.StorageWriteBytes, conMetrics.StorageWriteBytes)
+}
+
+// addUint64 adds the given value to the given sum, which is initialized on first use. Nil values are ignored.
+func addUint64(sum **uint64, value *uint64) {
+	if value == nil {
+		return
+	}
+	if *sum == nil {
+		*sum = uint64Ptr(0)
+	}
+	**sum += *value
+}
+
+// addFloat64 adds the given value to the given sum, which is initialized on first use. Nil values are ignored.
+func addFloat64(sum **float64, value *float64) {
+	if value == nil {
+		return
+	}
+	if *sum == nil {
+		*sum = float64Ptr(0)
+	}
+	**sum += *value
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
 }
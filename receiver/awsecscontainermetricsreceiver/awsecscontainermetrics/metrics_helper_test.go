@@ -55,21 +55,21 @@ func TestGetContainerMetricsAllValid(t *testing.T) {
 	containerMetrics := getContainerMetrics(&containerStats, logger)
 	require.NotNil(t, containerMetrics)
 
-	require.EqualValues(t, v, containerMetrics.MemoryUsage)
-	require.EqualValues(t, floatZero, containerMetrics.MemoryUtilized)
+	require.EqualValues(t, v, *containerMetrics.MemoryUsage)
+	require.EqualValues(t, floatZero, *containerMetrics.MemoryUtilized)
 
-	require.EqualValues(t, v, containerMetrics.CPUTotalUsage)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInKernelmode)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInUserMode)
+	require.EqualValues(t, v, *containerMetrics.CPUTotalUsage)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInKernelmode)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInUserMode)
 
-	require.EqualValues(t, f, containerMetrics.NetworkRateRxBytesPerSecond)
-	require.EqualValues(t, f, containerMetrics.NetworkRateTxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateRxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateTxBytesPerSecond)
 
-	require.EqualValues(t, v, containerMetrics.NetworkRxBytes)
-	require.EqualValues(t, v, containerMetrics.NetworkTxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkRxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkTxBytes)
 
-	require.EqualValues(t, v, containerMetrics.StorageReadBytes)
-	require.EqualValues(t, v, containerMetrics.StorageWriteBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageReadBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageWriteBytes)
 }
 
 func TestGetContainerMetricsMissingMemory(t *testing.T) {
@@ -89,21 +89,21 @@ func TestGetContainerMetricsMissingMemory(t *testing.T) {
 	containerMetrics := getContainerMetrics(&containerStats, logger)
 	require.NotNil(t, containerMetrics)
 
-	require.EqualValues(t, 0, containerMetrics.MemoryUsage)
-	require.EqualValues(t, floatZero, containerMetrics.MemoryUtilized)
+	require.Nil(t, containerMetrics.MemoryUsage)
+	require.Nil(t, containerMetrics.MemoryUtilized)
 
-	require.EqualValues(t, v, containerMetrics.CPUTotalUsage)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInKernelmode)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInUserMode)
+	require.EqualValues(t, v, *containerMetrics.CPUTotalUsage)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInKernelmode)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInUserMode)
 
-	require.EqualValues(t, f, containerMetrics.NetworkRateRxBytesPerSecond)
-	require.EqualValues(t, f, containerMetrics.NetworkRateTxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateRxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateTxBytesPerSecond)
 
-	require.EqualValues(t, v, containerMetrics.NetworkRxBytes)
-	require.EqualValues(t, v, containerMetrics.NetworkTxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkRxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkTxBytes)
 
-	require.EqualValues(t, v, containerMetrics.StorageReadBytes)
-	require.EqualValues(t, v, containerMetrics.StorageWriteBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageReadBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageWriteBytes)
 }
 
 func TestGetContainerMetricsMissingCpu(t *testing.T) {
@@ -123,21 +123,21 @@ func TestGetContainerMetricsMissingCpu(t *testing.T) {
 	containerMetrics := getContainerMetrics(&containerStats, logger)
 	require.NotNil(t, containerMetrics)
 
-	require.EqualValues(t, v, containerMetrics.MemoryUsage)
-	require.EqualValues(t, floatZero, containerMetrics.MemoryUtilized)
+	require.EqualValues(t, v, *containerMetrics.MemoryUsage)
+	require.EqualValues(t, floatZero, *containerMetrics.MemoryUtilized)
 
-	require.EqualValues(t, 0, containerMetrics.CPUTotalUsage)
-	require.EqualValues(t, 0, containerMetrics.CPUUsageInKernelmode)
-	require.EqualValues(t, 0, containerMetrics.CPUUsageInUserMode)
+	require.Nil(t, containerMetrics.CPUTotalUsage)
+	require.Nil(t, containerMetrics.CPUUsageInKernelmode)
+	require.Nil(t, containerMetrics.CPUUsageInUserMode)
 
-	require.EqualValues(t, f, containerMetrics.NetworkRateRxBytesPerSecond)
-	require.EqualValues(t, f, containerMetrics.NetworkRateTxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateRxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateTxBytesPerSecond)
 
-	require.EqualValues(t, v, containerMetrics.NetworkRxBytes)
-	require.EqualValues(t, v, containerMetrics.NetworkTxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkRxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkTxBytes)
 
-	require.EqualValues(t, v, containerMetrics.StorageReadBytes)
-	require.EqualValues(t, v, containerMetrics.StorageWriteBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageReadBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageWriteBytes)
 }
 
 func TestGetContainerMetricsMissingNetworkRate(t *testing.T) {
@@ -157,21 +157,21 @@ func TestGetContainerMetricsMissingNetworkRate(t *testing.T) {
 	containerMetrics := getContainerMetrics(&containerStats, logger)
 	require.NotNil(t, containerMetrics)
 
-	require.EqualValues(t, v, containerMetrics.MemoryUsage)
-	require.EqualValues(t, floatZero, containerMetrics.MemoryUtilized)
+	require.EqualValues(t, v, *containerMetrics.MemoryUsage)
+	require.EqualValues(t, floatZero, *containerMetrics.MemoryUtilized)
 
-	require.EqualValues(t, v, containerMetrics.CPUTotalUsage)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInKernelmode)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInUserMode)
+	require.EqualValues(t, v, *containerMetrics.CPUTotalUsage)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInKernelmode)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInUserMode)
 
-	require.EqualValues(t, floatZero, containerMetrics.NetworkRateRxBytesPerSecond)
-	require.EqualValues(t, floatZero, containerMetrics.NetworkRateTxBytesPerSecond)
+	require.Nil(t, containerMetrics.NetworkRateRxBytesPerSecond)
+	require.Nil(t, containerMetrics.NetworkRateTxBytesPerSecond)
 
-	require.EqualValues(t, v, containerMetrics.NetworkRxBytes)
-	require.EqualValues(t, v, containerMetrics.NetworkTxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkRxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkTxBytes)
 
-	require.EqualValues(t, v, containerMetrics.StorageReadBytes)
-	require.EqualValues(t, v, containerMetrics.StorageWriteBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageReadBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageWriteBytes)
 }
 
 func TestGetContainerMetricsMissingNetworkAndDisk(t *testing.T) {
@@ -191,21 +191,21 @@ func TestGetContainerMetricsMissingNetworkAndDisk(t *testing.T) {
 	containerMetrics := getContainerMetrics(&containerStats, logger)
 	require.NotNil(t, containerMetrics)
 
-	require.EqualValues(t, v, containerMetrics.MemoryUsage)
-	require.EqualValues(t, floatZero, containerMetrics.MemoryUtilized)
+	require.EqualValues(t, v, *containerMetrics.MemoryUsage)
+	require.EqualValues(t, floatZero, *containerMetrics.MemoryUtilized)
 
-	require.EqualValues(t, v, containerMetrics.CPUTotalUsage)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInKernelmode)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInUserMode)
+	require.EqualValues(t, v, *containerMetrics.CPUTotalUsage)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInKernelmode)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInUserMode)
 
-	require.EqualValues(t, f, containerMetrics.NetworkRateRxBytesPerSecond)
-	require.EqualValues(t, f, containerMetrics.NetworkRateTxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateRxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateTxBytesPerSecond)
 
-	require.EqualValues(t, 0, containerMetrics.NetworkRxBytes)
-	require.EqualValues(t, 0, containerMetrics.NetworkTxBytes)
+	require.Nil(t, containerMetrics.NetworkRxBytes)
+	require.Nil(t, containerMetrics.NetworkTxBytes)
 
-	require.EqualValues(t, 0, containerMetrics.StorageReadBytes)
-	require.EqualValues(t, 0, containerMetrics.StorageWriteBytes)
+	require.Nil(t, containerMetrics.StorageReadBytes)
+	require.Nil(t, containerMetrics.StorageWriteBytes)
 }
 
 func TestGetContainerMetricsMissingMemoryStats(t *testing.T) {
@@ -227,21 +227,21 @@ func TestGetContainerMetricsMissingMemoryStats(t *testing.T) {
 	containerMetrics := getContainerMetrics(&containerStats, logger)
 	require.NotNil(t, containerMetrics)
 
-	require.EqualValues(t, v, containerMetrics.MemoryUsage)
-	require.EqualValues(t, floatZero, containerMetrics.MemoryUtilized)
+	require.EqualValues(t, v, *containerMetrics.MemoryUsage)
+	require.Nil(t, containerMetrics.MemoryUtilized)
 
-	require.EqualValues(t, v, containerMetrics.CPUTotalUsage)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInKernelmode)
-	require.EqualValues(t, v, containerMetrics.CPUUsageInUserMode)
+	require.EqualValues(t, v, *containerMetrics.CPUTotalUsage)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInKernelmode)
+	require.EqualValues(t, v, *containerMetrics.CPUUsageInUserMode)
 
-	require.EqualValues(t, f, containerMetrics.NetworkRateRxBytesPerSecond)
-	require.EqualValues(t, f, containerMetrics.NetworkRateTxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateRxBytesPerSecond)
+	require.EqualValues(t, f, *containerMetrics.NetworkRateTxBytesPerSecond)
 
-	require.EqualValues(t, v, containerMetrics.NetworkRxBytes)
-	require.EqualValues(t, v, containerMetrics.NetworkTxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkRxBytes)
+	require.EqualValues(t, v, *containerMetrics.NetworkTxBytes)
 
-	require.EqualValues(t, v, containerMetrics.StorageReadBytes)
-	require.EqualValues(t, v, containerMetrics.StorageWriteBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageReadBytes)
+	require.EqualValues(t, v, *containerMetrics.StorageWriteBytes)
 }
 
 func TestAggregateTaskMetrics(t *testing.T) {
@@ -263,9 +263,9 @@ func TestAggregateTaskMetrics(t *testing.T) {
 
 	taskMetrics := ECSMetrics{}
 	aggregateTaskMetrics(&taskMetrics, containerMetrics)
-	require.EqualValues(t, v, taskMetrics.MemoryUsage)
-	require.EqualValues(t, v, taskMetrics.MemoryMaxUsage)
-	require.EqualValues(t, v, taskMetrics.StorageReadBytes)
+	require.EqualValues(t, v, *taskMetrics.MemoryUsage)
+	require.EqualValues(t, v, *taskMetrics.MemoryMaxUsage)
+	require.EqualValues(t, v, *taskMetrics.StorageReadBytes)
 }
 
 func TestExtractStorageUsage(t *testing.T) {
@@ -279,13 +279,20 @@ func TestExtractStorageUsage(t *testing.T) {
 	}
 	read, write := extractStorageUsage(disk)
 
-	require.EqualValues(t, v, read)
-	require.EqualValues(t, v, write)
+	require.EqualValues(t, v, *read)
+	require.EqualValues(t, v, *write)
 
 	read, write = extractStorageUsage(nil)
-	v = uint64(0)
-	require.EqualValues(t, v, read)
-	require.EqualValues(t, v, write)
+	require.Nil(t, read)
+	require.Nil(t, write)
+
+	read, write = extractStorageUsage(&DiskStats{
+		IoServiceBytesRecursives: []IoServiceBytesRecursive{
+			{Op: "Total", Value: &v},
+		},
+	})
+	require.Nil(t, read)
+	require.Nil(t, write)
 }
 
 func TestGetNetworkStats(t *testing.T) {
@@ -306,7 +313,52 @@ func TestGetNetworkStats(t *testing.T) {
 
 	sum := uint64(0)
 	for _, v := range netArray {
-		sum += v
+		sum += *v
 	}
 	require.EqualValues(t, 800, sum)
+
+	stats["eth1"] = NetworkStats{RxBytes: &v}
+	netArray = getNetworkStats(stats)
+	require.EqualValues(t, 200, *netArray[0])
+	require.EqualValues(t, 100, *netArray[4])
+
+	netArray = getNetworkStats(map[string]NetworkStats{"eth0": {RxBytes: &v}})
+	require.EqualValues(t, 100, *netArray[0])
+	for _, v := range netArray[1:] {
+		require.Nil(t, v)
+	}
+}
+
+func TestGetContainerMetricsMissingFields(t *testing.T) {
+	usage := uint64(100)
+	containerStats := ContainerStats{
+		Name:         "test",
+		ID:           "001",
+		Read:         time.Now(),
+		PreviousRead: time.Now().Add(-10 * time.Second),
+		Memory:       &MemoryStats{Usage: &usage},
+		Disk:         &DiskStats{},
+		CPU: &CPUStats{
+			CPUUsage: &CPUUsage{TotalUsage: &usage},
+		},
+	}
+
+	containerMetrics := getContainerMetrics(&containerStats, logger)
+
+	require.EqualValues(t, usage, *containerMetrics.MemoryUsage)
+	require.Nil(t, containerMetrics.MemoryMaxUsage)
+	require.Nil(t, containerMetrics.MemoryLimit)
+	require.Nil(t, containerMetrics.MemoryUtilized)
+
+	require.EqualValues(t, usage, *containerMetrics.CPUTotalUsage)
+	require.Nil(t, containerMetrics.CPUUsageInKernelmode)
+	require.Nil(t, containerMetrics.CPUOnlineCpus)
+	require.Nil(t, containerMetrics.NumOfCPUCores)
+	require.Nil(t, containerMetrics.CPUUsageInVCPU)
+	require.Nil(t, containerMetrics.CPUUtilized)
+
+	require.Nil(t, containerMetrics.NetworkRateRxBytesPerSecond)
+	require.Nil(t, containerMetrics.NetworkRxBytes)
+	require.Nil(t, containerMetrics.StorageReadBytes)
+	require.Nil(t, containerMetrics.StorageWriteBytes)
 }
@@ -18,6 +18,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
+// convertToOTLPMetrics converts the given metrics, omitting the nil ones
 func convertToOTLPMetrics(prefix string, m ECSMetrics, r pdata.Resource, timestamp pdata.TimestampUnixNano) pdata.ResourceMetricsSlice {
 	rms := pdata.NewResourceMetricsSlice()
 	rms.Resize(1)
@@ -27,40 +28,58 @@ func convertToOTLPMetrics(prefix string, m ECSMetrics, r pdata.Resource, timesta
 
 	ilms := rm.InstrumentationLibraryMetrics()
 
-	ilms.Append(intGauge(prefix+AttributeMemoryUsage, UnitBytes, int64(m.MemoryUsage), timestamp))
-	ilms.Append(intGauge(prefix+AttributeMemoryMaxUsage, UnitBytes, int64(m.MemoryMaxUsage), timestamp))
-	ilms.Append(intGauge(prefix+AttributeMemoryLimit, UnitBytes, int64(m.MemoryLimit), timestamp))
-	ilms.Append(intGauge(prefix+AttributeMemoryUtilized, UnitMegaBytes, int64(m.MemoryUtilized), timestamp))
-	ilms.Append(intGauge(prefix+AttributeMemoryReserved, UnitMegaBytes, int64(m.MemoryReserved), timestamp))
-
-	ilms.Append(intSum(prefix+AttributeCPUTotalUsage, UnitNanoSecond, int64(m.CPUTotalUsage), timestamp))
-	ilms.Append(intSum(prefix+AttributeCPUKernelModeUsage, UnitNanoSecond, int64(m.CPUUsageInKernelmode), timestamp))
-	ilms.Append(intSum(prefix+AttributeCPUUserModeUsage, UnitNanoSecond, int64(m.CPUUsageInUserMode), timestamp))
-	ilms.Append(intGauge(prefix+AttributeCPUCores, UnitCount, int64(m.NumOfCPUCores), timestamp))
-	ilms.Append(intGauge(prefix+AttributeCPUOnlines, UnitCount, int64(m.CPUOnlineCpus), timestamp))
-	ilms.Append(intSum(prefix+AttributeCPUSystemUsage, UnitNanoSecond, int64(m.SystemCPUUsage), timestamp))
-	ilms.Append(doubleGauge(prefix+AttributeCPUUtilized, UnitPercent, m.CPUUtilized, timestamp))
-	ilms.Append(doubleGauge(prefix+AttributeCPUReserved, UnitVCpu, m.CPUReserved, timestamp))
-	ilms.Append(doubleGauge(prefix+AttributeCPUUsageInVCPU, UnitVCpu, m.CPUUsageInVCPU, timestamp))
-
-	ilms.Append(doubleGauge(prefix+AttributeNetworkRateRx, UnitBytesPerSec, m.NetworkRateRxBytesPerSecond, timestamp))
-	ilms.Append(doubleGauge(prefix+AttributeNetworkRateTx, UnitBytesPerSec, m.NetworkRateTxBytesPerSecond, timestamp))
-
-	ilms.Append(intSum(prefix+AttributeNetworkRxBytes, UnitBytes, int64(m.NetworkRxBytes), timestamp))
-	ilms.Append(intSum(prefix+AttributeNetworkRxPackets, UnitCount, int64(m.NetworkRxPackets), timestamp))
-	ilms.Append(intSum(prefix+AttributeNetworkRxErrors, UnitCount, int64(m.NetworkRxErrors), timestamp))
-	ilms.Append(intSum(prefix+AttributeNetworkRxDropped, UnitCount, int64(m.NetworkRxDropped), timestamp))
-	ilms.Append(intSum(prefix+AttributeNetworkTxBytes, UnitBytes, int64(m.NetworkTxBytes), timestamp))
-	ilms.Append(intSum(prefix+AttributeNetworkTxPackets, UnitCount, int64(m.NetworkTxPackets), timestamp))
-	ilms.Append(intSum(prefix+AttributeNetworkTxErrors, UnitCount, int64(m.NetworkTxErrors), timestamp))
-	ilms.Append(intSum(prefix+AttributeNetworkTxDropped, UnitCount, int64(m.NetworkTxDropped), timestamp))
-
-	ilms.Append(intSum(prefix+AttributeStorageRead, UnitBytes, int64(m.StorageReadBytes), timestamp))
-	ilms.Append(intSum(prefix+AttributeStorageWrite, UnitBytes, int64(m.StorageWriteBytes), timestamp))
+	appendIntGauge(ilms, prefix+AttributeMemoryUsage, UnitBytes, m.MemoryUsage, timestamp)
+	appendIntGauge(ilms, prefix+AttributeMemoryMaxUsage, UnitBytes, m.MemoryMaxUsage, timestamp)
+	appendIntGauge(ilms, prefix+AttributeMemoryLimit, UnitBytes, m.MemoryLimit, timestamp)
+	appendIntGauge(ilms, prefix+AttributeMemoryUtilized, UnitMegaBytes, m.MemoryUtilized, timestamp)
+	appendIntGauge(ilms, prefix+AttributeMemoryReserved, UnitMegaBytes, m.MemoryReserved, timestamp)
+
+	appendIntSum(ilms, prefix+AttributeCPUTotalUsage, UnitNanoSecond, m.CPUTotalUsage, timestamp)
+	appendIntSum(ilms, prefix+AttributeCPUKernelModeUsage, UnitNanoSecond, m.CPUUsageInKernelmode, timestamp)
+	appendIntSum(ilms, prefix+AttributeCPUUserModeUsage, UnitNanoSecond, m.CPUUsageInUserMode, timestamp)
+	appendIntGauge(ilms, prefix+AttributeCPUCores, UnitCount, m.NumOfCPUCores, timestamp)
+	appendIntGauge(ilms, prefix+AttributeCPUOnlines, UnitCount, m.CPUOnlineCpus, timestamp)
+	appendIntSum(ilms, prefix+AttributeCPUSystemUsage, UnitNanoSecond, m.SystemCPUUsage, timestamp)
+	appendDoubleGauge(ilms, prefix+AttributeCPUUtilized, UnitPercent, m.CPUUtilized, timestamp)
+	appendDoubleGauge(ilms, prefix+AttributeCPUReserved, UnitVCpu, m.CPUReserved, timestamp)
+	appendDoubleGauge(ilms, prefix+AttributeCPUUsageInVCPU, UnitVCpu, m.CPUUsageInVCPU, timestamp)
+
+	appendDoubleGauge(ilms, prefix+AttributeNetworkRateRx, UnitBytesPerSec, m.NetworkRateRxBytesPerSecond, timestamp)
+	appendDoubleGauge(ilms, prefix+AttributeNetworkRateTx, UnitBytesPerSec, m.NetworkRateTxBytesPerSecond, timestamp)
+
+	appendIntSum(ilms, prefix+AttributeNetworkRxBytes, UnitBytes, m.NetworkRxBytes, timestamp)
+	appendIntSum(ilms, prefix+AttributeNetworkRxPackets, UnitCount, m.NetworkRxPackets, timestamp)
+	appendIntSum(ilms, prefix+AttributeNetworkRxErrors, UnitCount, m.NetworkRxErrors, timestamp)
+	appendIntSum(ilms, prefix+AttributeNetworkRxDropped, UnitCount, m.NetworkRxDropped, timestamp)
+	appendIntSum(ilms, prefix+AttributeNetworkTxBytes, UnitBytes, m.NetworkTxBytes, timestamp)
+	appendIntSum(ilms, prefix+AttributeNetworkTxPackets, UnitCount, m.NetworkTxPackets, timestamp)
+	appendIntSum(ilms, prefix+AttributeNetworkTxErrors, UnitCount, m.NetworkTxErrors, timestamp)
+	appendIntSum(ilms, prefix+AttributeNetworkTxDropped, UnitCount, m.NetworkTxDropped, timestamp)
+
+	appendIntSum(ilms, prefix+AttributeStorageRead, UnitBytes, m.StorageReadBytes, timestamp)
+	appendIntSum(ilms, prefix+AttributeStorageWrite, UnitBytes, m.StorageWriteBytes, timestamp)
 
 	return rms
 }
 
+func appendIntGauge(ilms pdata.InstrumentationLibraryMetricsSlice, metricName string, unit string, value *uint64, ts pdata.TimestampUnixNano) {
+	if value != nil {
+		ilms.Append(intGauge(metricName, unit, int64(*value), ts))
+	}
+}
+
+func appendIntSum(ilms pdata.InstrumentationLibraryMetricsSlice, metricName string, unit string, value *uint64, ts pdata.TimestampUnixNano) {
+	if value != nil {
+		ilms.Append(intSum(metricName, unit, int64(*value), ts))
+	}
+}
+
+func appendDoubleGauge(ilms pdata.InstrumentationLibraryMetricsSlice, metricName string, unit string, value *float64, ts pdata.TimestampUnixNano) {
+	if value != nil {
+		ilms.Append(doubleGauge(metricName, unit, *value, ts))
+	}
+}
+
 func intGauge(metricName string, unit string, value int64, ts pdata.TimestampUnixNano) pdata.InstrumentationLibraryMetrics {
 	ilm := pdata.NewInstrumentationLibraryMetrics()
 
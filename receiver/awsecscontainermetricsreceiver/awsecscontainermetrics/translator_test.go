@@ -25,15 +25,23 @@ func TestConvertToOTMetrics(t *testing.T) {
 	timestamp := pdata.TimeToUnixNano(time.Now())
 	m := ECSMetrics{}
 
-	m.MemoryUsage = 100
-	m.MemoryMaxUsage = 100
-	m.MemoryUtilized = 100
-	m.MemoryReserved = 100
-	m.CPUTotalUsage = 100
+	v := uint64(100)
+	m.MemoryUsage = &v
+	m.MemoryMaxUsage = &v
+	m.MemoryUtilized = &v
+	m.MemoryReserved = &v
+	m.CPUTotalUsage = &v
 
 	resource := pdata.NewResource()
 	rms := convertToOTLPMetrics("container.", m, resource, timestamp)
-	require.EqualValues(t, 26, rms.At(0).InstrumentationLibraryMetrics().Len())
+	require.EqualValues(t, 5, rms.At(0).InstrumentationLibraryMetrics().Len())
+
+	f := 1.0
+	m.CPUUtilized = &f
+	m.StorageReadBytes = &v
+	m.StorageWriteBytes = &v
+	rms = convertToOTLPMetrics("container.", m, resource, timestamp)
+	require.EqualValues(t, 8, rms.At(0).InstrumentationLibraryMetrics().Len())
 }
 
 func TestIntGauge(t *testing.T) {
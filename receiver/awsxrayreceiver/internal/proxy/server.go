@@ -38,6 +38,13 @@ const (
 	connHeader = "Connection"
 )
 
+// samplingAPIPaths are the paths of the X-Ray APIs called by the SDKs for the centralized
+// sampling, GetSamplingRules and GetSamplingTargets, which are the only ones relayed
+var samplingAPIPaths = map[string]bool{
+	"/GetSamplingRules": true,
+	"/SamplingTargets":  true,
+}
+
 // Server represents HTTP server.
 type Server interface {
 	ListenAndServe() error
@@ -119,10 +126,23 @@ func NewServer(cfg *Config, logger *zap.Logger) (Server, error) {
 
 	return &http.Server{
 		Addr:    cfg.Endpoint,
-		Handler: handler,
+		Handler: samplingAPIsOnly(handler, logger),
 	}, nil
 }
 
+// samplingAPIsOnly wraps the given handler so that only the sampling API calls are relayed,
+// the proxy signing the requests with the collector's credentials.
+func samplingAPIsOnly(handler http.Handler, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !samplingAPIPaths[req.URL.Path] {
+			logger.Debug("Rejected request to an unsupported X-Ray API", zap.String("path", req.URL.Path))
+			http.Error(w, fmt.Sprintf("unsupported X-Ray API: %s", req.URL.Path), http.StatusNotFound)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
 // getServiceEndpoint returns X-Ray service endpoint.
 // It is guaranteed that awsCfg config instance is non-nil and the region value is non nil or non empty in awsCfg object.
 // Currently the caller takes care of it.
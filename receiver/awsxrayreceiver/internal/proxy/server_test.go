@@ -180,6 +180,32 @@ func TestHandlerSignerErrorsOut(t *testing.T) {
 		"NoCredentialProviders", "expected error")
 }
 
+func TestHandlerUnsupportedAPI(t *testing.T) {
+	logger, recordedLogs := logSetup()
+
+	env := stashEnv()
+	defer restoreEnv(env)
+	os.Setenv(regionEnvVarName, regionEnvVar)
+
+	cfg := DefaultConfig()
+	tcpAddr := testutil.GetAvailableLocalAddress(t)
+	cfg.TCPAddr.Endpoint = tcpAddr
+	srv, err := NewServer(cfg, logger)
+	assert.NoError(t, err, "NewServer should succeed")
+
+	handler := srv.(*http.Server).Handler.ServeHTTP
+	req := httptest.NewRequest("POST",
+		"https://xray.us-west-2.amazonaws.com/DeleteSamplingRule", strings.NewReader(`{"RuleName": "rule"}`))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Result().StatusCode)
+	logs := recordedLogs.All()
+	lastEntry := logs[len(logs)-1]
+	assert.Contains(t, lastEntry.Message, "Rejected request to an unsupported X-Ray API", "expected log message")
+	assert.Equal(t, "/DeleteSamplingRule", lastEntry.Context[0].String)
+}
+
 func TestTCPEndpointInvalid(t *testing.T) {
 	logger, _ := logSetup()
 
@@ -215,3 +215,28 @@ func buildMetric(
 		labelValues,
 		point)
 }
+
+func Benchmark_plaintextPathParser_ParsePath(b *testing.B) {
+	pp := &PlaintextPathParser{}
+
+	tests := []string{
+		"prod.us-east-1.web01.cpu.user",
+		"cpu.user;env=prod;region=us-east-1;host=web01",
+		"rpc.duration.seconds;svc=service_name;host=host01",
+		"no.tags;",
+	}
+
+	got := ParsedPath{}
+	var err error
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < len(tests); i++ {
+			err = pp.ParsePath(tests[i], &got)
+		}
+	}
+
+	res.name = got.MetricName
+	res.keys = got.LabelKeys
+	res.values = got.LabelValues
+	res.metricType = got.MetricType
+	res.err = err
+}
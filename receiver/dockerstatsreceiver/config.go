@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 var _ configmodels.Receiver = (*Config)(nil)
@@ -27,6 +28,9 @@ type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
 	// The URL of the docker server.  Default is "unix:///var/run/docker.sock"
 	Endpoint string `mapstructure:"endpoint"`
+	// The TLS settings to use when connecting to a docker daemon over tcp (with `tcp://` or `https://` endpoints).
+	// Not set by default, the connection being unencrypted.
+	TLSSetting *configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
 	// The time between each collection event.  Default is 10s.
 	CollectionInterval time.Duration `mapstructure:"collection_interval"`
 
@@ -24,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -38,7 +39,7 @@ func TestLoadConfig(t *testing.T) {
 
 	require.NoError(t, err)
 	require.NotNil(t, config)
-	assert.Equal(t, 2, len(config.Receivers))
+	assert.Equal(t, 3, len(config.Receivers))
 
 	defaultConfig := config.Receivers["docker_stats"]
 	assert.Equal(t, factory.CreateDefaultConfig(), defaultConfig)
@@ -54,6 +55,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.Nil(t, dcfg.EnvVarsToMetricLabels)
 
 	assert.False(t, dcfg.ProvidePerCoreCPUMetrics)
+	assert.Nil(t, dcfg.TLSSetting)
 
 	ascfg := config.Receivers["docker_stats/allsettings"].(*Config)
 	assert.Equal(t, "docker_stats/allsettings", ascfg.Name())
@@ -77,4 +79,14 @@ func TestLoadConfig(t *testing.T) {
 	}, ascfg.EnvVarsToMetricLabels)
 
 	assert.True(t, ascfg.ProvidePerCoreCPUMetrics)
+
+	tcfg := config.Receivers["docker_stats/tls"].(*Config)
+	assert.Equal(t, "tcp://example.com:2376", tcfg.Endpoint)
+	assert.Equal(t, &configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{
+			CAFile:   "/etc/docker/ca.pem",
+			CertFile: "/etc/docker/cert.pem",
+			KeyFile:  "/etc/docker/key.pem",
+		},
+	}, tcfg.TLSSetting)
 }
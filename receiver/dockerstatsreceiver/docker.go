@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -49,11 +50,25 @@ type dockerClient struct {
 }
 
 func newDockerClient(config *Config, logger *zap.Logger) (*dockerClient, error) {
-	client, err := docker.NewClientWithOpts(
+	var opts []docker.Opt
+	if config.TLSSetting != nil {
+		tlsConfig, err := config.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("could not load docker client TLS config: %w", err)
+		}
+		// the client needs to be set before the host, so that the transport is configured for it
+		opts = append(opts, docker.WithHTTPClient(&http.Client{
+			Transport:     &http.Transport{TLSClientConfig: tlsConfig},
+			CheckRedirect: docker.CheckRedirect,
+		}))
+	}
+	opts = append(opts,
 		docker.WithHost(config.Endpoint),
 		docker.WithVersion(dockerAPIVersion),
 		docker.WithHTTPHeaders(map[string]string{"User-Agent": userAgent}),
 	)
+
+	client, err := docker.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create docker client: %w", err)
 	}
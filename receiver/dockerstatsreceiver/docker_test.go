@@ -19,6 +19,7 @@ package dockerstatsreceiver
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -34,6 +35,7 @@ import (
 	dtypes "github.com/docker/docker/api/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
@@ -58,6 +60,53 @@ func TestInvalidExclude(t *testing.T) {
 	assert.Equal(t, "could not determine docker client excluded images: invalid glob item: unexpected end of input", err.Error())
 }
 
+func TestInvalidTLSSetting(t *testing.T) {
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Endpoint = "tcp://127.0.0.1:2376"
+	config.TLSSetting = &configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: "/nonexistent/ca.pem"},
+	}
+	cli, err := newDockerClient(config, zap.NewNop())
+	assert.Nil(t, cli)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not load docker client TLS config")
+}
+
+func TestTLSConnection(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if strings.Contains(req.URL.Path, "/containers/json") {
+			rw.Write([]byte("[]"))
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	caFile, err := ioutil.TempFile(os.TempDir(), "ca.pem")
+	require.NoError(t, err)
+	defer os.Remove(caFile.Name())
+	require.NoError(t, pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+	require.NoError(t, caFile.Close())
+
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Endpoint = strings.Replace(srv.URL, "https://", "tcp://", 1)
+	config.TLSSetting = &configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: caFile.Name()},
+	}
+	cli, err := newDockerClient(config, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, cli)
+
+	require.NoError(t, cli.LoadContainerList(context.Background()))
+	assert.Empty(t, cli.Containers())
+
+	// without the TLS settings, the server certificate can't be verified
+	config.TLSSetting = &configtls.TLSClientSetting{}
+	cli, err = newDockerClient(config, zap.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, cli.LoadContainerList(context.Background()))
+}
+
 func tmpSock(t *testing.T) (net.Listener, string) {
 	f, err := ioutil.TempFile(os.TempDir(), "testsock")
 	if err != nil {
@@ -70,8 +70,14 @@ type otlpExporterConfig struct {
 	Headers map[string]string `mapstructure:"headers"`
 }
 
+// The target systems whose groovy script is built into the metric gatherer
+var validTargetSystems = []string{"cassandra", "jvm", "kafka"}
+
 func (c *config) validate() error {
 	var missingFields []string
+	if c.JARPath == "" {
+		missingFields = append(missingFields, "`jar_path`")
+	}
 	if c.Endpoint == "" {
 		missingFields = append(missingFields, "`endpoint`")
 	}
@@ -86,6 +92,10 @@ func (c *config) validate() error {
 		return fmt.Errorf("%v: %v", baseMsg, strings.Join(missingFields, ", "))
 	}
 
+	if c.TargetSystem != "" && !isValidTargetSystem(c.TargetSystem) {
+		return fmt.Errorf("%v `target_system` must be one of %v: %v", c.Name(), strings.Join(validTargetSystems, ", "), c.TargetSystem)
+	}
+
 	if c.CollectionInterval < 0 {
 		return fmt.Errorf("%v `interval` must be positive: %vms", c.Name(), c.CollectionInterval.Milliseconds())
 	}
@@ -95,3 +105,12 @@ func (c *config) validate() error {
 	}
 	return nil
 }
+
+func isValidTargetSystem(targetSystem string) bool {
+	for _, valid := range validTargetSystems {
+		if targetSystem == valid {
+			return true
+		}
+	}
+	return false
+}
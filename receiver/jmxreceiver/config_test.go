@@ -39,7 +39,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 6)
+	assert.Equal(t, len(cfg.Receivers), 8)
 
 	r0 := cfg.Receivers["jmx"].(*config)
 	require.NoError(t, configcheck.ValidateConfig(r0))
@@ -171,4 +171,18 @@ func TestLoadConfig(t *testing.T) {
 	err = r5.validate()
 	require.Error(t, err)
 	assert.Equal(t, "jmx/invalidotlptimeout `otlp.timeout` must be positive: -100ms", err.Error())
+
+	r6 := cfg.Receivers["jmx/invalidtargetsystem"].(*config)
+	require.NoError(t, configcheck.ValidateConfig(r6))
+	assert.Equal(t, "mysql", r6.TargetSystem)
+	err = r6.validate()
+	require.Error(t, err)
+	assert.Equal(t, "jmx/invalidtargetsystem `target_system` must be one of cassandra, jvm, kafka: mysql", err.Error())
+
+	r7 := cfg.Receivers["jmx/missingjarpath"].(*config)
+	require.NoError(t, configcheck.ValidateConfig(r7))
+	assert.Equal(t, "", r7.JARPath)
+	err = r7.validate()
+	require.Error(t, err)
+	assert.Equal(t, "jmx/missingjarpath missing required field: `jar_path`", err.Error())
 }
@@ -71,6 +71,7 @@ func (jmx *jmxMetricReceiver) Start(ctx context.Context, host component.Host) (e
 		ExecutablePath: "java",
 		Args:           []string{"-Dorg.slf4j.simpleLogger.defaultLogLevel=debug", "-jar", jmx.config.JARPath, "-config", "-"},
 		StdInContents:  javaConfig,
+		RestartOnError: true,
 	}
 
 	jmx.subprocess = subprocess.NewSubprocess(&subprocessConfig, jmx.logger)
@@ -171,5 +172,33 @@ otel.exporter.otlp.metric.timeout = %v
 		javaConfig += fmt.Sprintf("otel.jmx.password = %v\n", jmx.config.Password)
 	}
 
+	if jmx.config.RemoteProfile != "" {
+		javaConfig += fmt.Sprintf("otel.jmx.remote.profile = %v\n", jmx.config.RemoteProfile)
+	}
+
+	if jmx.config.Realm != "" {
+		javaConfig += fmt.Sprintf("otel.jmx.realm = %v\n", jmx.config.Realm)
+	}
+
+	if jmx.config.KeystorePath != "" {
+		javaConfig += fmt.Sprintf("javax.net.ssl.keyStore = %v\n", jmx.config.KeystorePath)
+	}
+
+	if jmx.config.KeystorePassword != "" {
+		javaConfig += fmt.Sprintf("javax.net.ssl.keyStorePassword = %v\n", jmx.config.KeystorePassword)
+	}
+
+	if jmx.config.KeystoreType != "" {
+		javaConfig += fmt.Sprintf("javax.net.ssl.keyStoreType = %v\n", jmx.config.KeystoreType)
+	}
+
+	if jmx.config.TruststorePath != "" {
+		javaConfig += fmt.Sprintf("javax.net.ssl.trustStore = %v\n", jmx.config.TruststorePath)
+	}
+
+	if jmx.config.TruststorePassword != "" {
+		javaConfig += fmt.Sprintf("javax.net.ssl.trustStorePassword = %v\n", jmx.config.TruststorePassword)
+	}
+
 	return javaConfig, nil
 }
@@ -27,6 +27,8 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/testutil"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestReceiver(t *testing.T) {
@@ -47,6 +49,37 @@ func TestReceiver(t *testing.T) {
 	require.Nil(t, receiver.Shutdown(context.Background()))
 }
 
+func TestReceiverDoesntLogPasswords(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	params := component.ReceiverCreateParams{Logger: zap.New(observed)}
+	config := &config{
+		JARPath:            "/nonexistent/jmx-metrics.jar",
+		Endpoint:           "myhost:12345",
+		TargetSystem:       "jvm",
+		Password:           "mypassword",
+		KeystorePassword:   "mykeystorepassword",
+		TruststorePassword: "mytruststorepassword",
+		OTLPExporterConfig: otlpExporterConfig{
+			Endpoint: fmt.Sprintf("localhost:%d", testutil.GetAvailablePort(t)),
+		},
+	}
+
+	receiver := newJMXMetricReceiver(params, config, consumertest.NewMetricsNop())
+	require.Nil(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	// the subprocess either fails to start or exits, without a jar
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("subprocess died").Len() > 0
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Nil(t, receiver.Shutdown(context.Background()))
+
+	for _, entry := range logs.All() {
+		logged := fmt.Sprintf("%v %v", entry.Message, entry.ContextMap())
+		for _, password := range []string{"mypassword", "mykeystorepassword", "mytruststorepassword"} {
+			require.NotContains(t, logged, password)
+		}
+	}
+}
+
 func TestBuildJMXMetricGathererConfig(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -117,6 +150,45 @@ otel.jmx.target.system = mytargetsystem
 otel.exporter = otlp
 otel.exporter.otlp.endpoint = myotlpendpoint
 otel.exporter.otlp.metric.timeout = 234000
+`, "",
+		},
+		{
+			"uses security settings",
+			config{
+				Endpoint:           "myhost:12345",
+				TargetSystem:       "jvm",
+				CollectionInterval: 123 * time.Second,
+				OTLPExporterConfig: otlpExporterConfig{
+					Endpoint: "myotlpendpoint",
+					TimeoutSettings: exporterhelper.TimeoutSettings{
+						Timeout: 234 * time.Second,
+					},
+				},
+				Username:           "myusername",
+				Password:           "mypassword",
+				RemoteProfile:      "TLS SASL/PLAIN",
+				Realm:              "myrealm",
+				KeystorePath:       "mykeystorepath",
+				KeystorePassword:   "mykeystorepassword",
+				KeystoreType:       "PKCS12",
+				TruststorePath:     "mytruststorepath",
+				TruststorePassword: "mytruststorepassword",
+			},
+			`otel.jmx.service.url = service:jmx:rmi:///jndi/rmi://myhost:12345/jmxrmi
+otel.jmx.interval.milliseconds = 123000
+otel.jmx.target.system = jvm
+otel.exporter = otlp
+otel.exporter.otlp.endpoint = myotlpendpoint
+otel.exporter.otlp.metric.timeout = 234000
+otel.jmx.username = myusername
+otel.jmx.password = mypassword
+otel.jmx.remote.profile = TLS SASL/PLAIN
+otel.jmx.realm = myrealm
+javax.net.ssl.keyStore = mykeystorepath
+javax.net.ssl.keyStorePassword = mykeystorepassword
+javax.net.ssl.keyStoreType = PKCS12
+javax.net.ssl.trustStore = mytruststorepath
+javax.net.ssl.trustStorePassword = mytruststorepassword
 `, "",
 		},
 		{
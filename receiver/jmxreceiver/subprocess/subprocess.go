@@ -17,6 +17,7 @@ package subprocess
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -31,6 +32,7 @@ import (
 
 const (
 	defaultRestartDelay    = 5 * time.Second
+	defaultMaxRestartDelay = 5 * time.Minute
 	defaultShutdownTimeout = 5 * time.Second
 	noPid                  = -1
 )
@@ -43,7 +45,10 @@ type Config struct {
 	StdInContents        string            `mapstructure:"stdin_contents"`
 	RestartOnError       bool              `mapstructure:"restart_on_error"`
 	RestartDelay         *time.Duration    `mapstructure:"restart_delay"`
-	ShutdownTimeout      *time.Duration    `mapstructure:"shutdown_timeout"`
+	// The restart delay doubles on each consecutive restart, up to MaxRestartDelay. It's reset to RestartDelay
+	// once the subprocess has been running for MaxRestartDelay.
+	MaxRestartDelay *time.Duration `mapstructure:"max_restart_delay"`
+	ShutdownTimeout *time.Duration `mapstructure:"shutdown_timeout"`
 }
 
 // exported to be used by jmx metric receiver
@@ -90,6 +95,13 @@ func NewSubprocess(conf *Config, logger *zap.Logger) *Subprocess {
 		restartDelay := defaultRestartDelay
 		conf.RestartDelay = &restartDelay
 	}
+	if conf.MaxRestartDelay == nil {
+		maxRestartDelay := defaultMaxRestartDelay
+		if maxRestartDelay < *conf.RestartDelay {
+			maxRestartDelay = *conf.RestartDelay
+		}
+		conf.MaxRestartDelay = &maxRestartDelay
+	}
 	if conf.ShutdownTimeout == nil {
 		shutdownTimeout := defaultShutdownTimeout
 		conf.ShutdownTimeout = &shutdownTimeout
@@ -135,6 +147,7 @@ func (subprocess *Subprocess) Shutdown(ctx context.Context) error {
 	if subprocess.cancel == nil {
 		return fmt.Errorf("no subprocess.cancel().  Has it been started properly?")
 	}
+	subprocess.cancel()
 
 	timeout := defaultShutdownTimeout
 	if subprocess.config.ShutdownTimeout != nil {
@@ -198,6 +211,9 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 	// writer is signalWhenProcessReturned() and closer is this loop, so we need synchronization
 	processReturned := newProcessReturned()
 
+	restartDelay := *subprocess.config.RestartDelay
+	var startedAt time.Time
+
 	state := Starting
 	for {
 		subprocess.logger.Debug("subprocess changed state", zap.String("state", state))
@@ -219,6 +235,7 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 				continue
 			}
 			subprocess.pid.setPid(cmd.Process.Pid)
+			startedAt = time.Now()
 
 			go signalWhenProcessReturned(cmd, processReturned)
 
@@ -233,9 +250,13 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 
 			select {
 			case err = <-processReturned.ReturnedChan:
-				if err != nil && ctx.Err() == nil {
-					err = fmt.Errorf("unexpected shutdown: %w", err)
-					// We aren't supposed to shutdown yet so this is an error state.
+				if ctx.Err() == nil {
+					// We aren't supposed to shutdown yet so this is an error state, even on a successful exit.
+					if err != nil {
+						err = fmt.Errorf("unexpected shutdown: %w", err)
+					} else {
+						err = errors.New("unexpected shutdown: subprocess exited")
+					}
 					state = Errored
 					continue
 				}
@@ -266,8 +287,25 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 		case Restarting:
 			stdout.Close()
 			stdin.Close()
-			time.Sleep(*subprocess.config.RestartDelay)
-			state = Starting
+			// a subprocess which has been running long enough isn't crash looping anymore
+			if !startedAt.IsZero() && time.Since(startedAt) >= *subprocess.config.MaxRestartDelay {
+				restartDelay = *subprocess.config.RestartDelay
+			}
+			startedAt = time.Time{}
+			subprocess.logger.Info("restarting subprocess", zap.Duration("delay", restartDelay))
+
+			select {
+			case <-time.After(restartDelay):
+				state = Starting
+			case <-ctx.Done():
+				processReturned.close()
+				state = Stopped
+			}
+
+			restartDelay *= 2
+			if restartDelay > *subprocess.config.MaxRestartDelay {
+				restartDelay = *subprocess.config.MaxRestartDelay
+			}
 		case Stopped:
 			return
 		}
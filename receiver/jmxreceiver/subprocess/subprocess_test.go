@@ -21,6 +21,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestSubprocessAndConfig(t *testing.T) {
@@ -34,17 +36,50 @@ func TestSubprocessAndConfig(t *testing.T) {
 
 	require.Equal(t, *config.ShutdownTimeout, 5*time.Second)
 	require.Equal(t, *config.RestartDelay, 5*time.Second)
+	require.Equal(t, *config.MaxRestartDelay, 5*time.Minute)
 }
 
 func TestConfigDurations(t *testing.T) {
 	logger := zap.NewNop()
 	restartDelay := 100 * time.Second
 	shutdownTimeout := 200 * time.Second
-	config := &Config{RestartDelay: &restartDelay, ShutdownTimeout: &shutdownTimeout}
+	maxRestartDelay := 300 * time.Second
+	config := &Config{RestartDelay: &restartDelay, MaxRestartDelay: &maxRestartDelay, ShutdownTimeout: &shutdownTimeout}
 	subprocess := NewSubprocess(config, logger)
 	require.NotNil(t, subprocess)
 	require.Equal(t, *config.ShutdownTimeout, shutdownTimeout)
 	require.Equal(t, *config.RestartDelay, restartDelay)
+	require.Equal(t, *config.MaxRestartDelay, maxRestartDelay)
+}
+
+func TestRestartBackoff(t *testing.T) {
+	observed, logs := observer.New(zapcore.InfoLevel)
+	restartDelay := 10 * time.Millisecond
+	maxRestartDelay := 40 * time.Millisecond
+	config := &Config{
+		ExecutablePath:  "/nonexistent/executable",
+		RestartOnError:  true,
+		RestartDelay:    &restartDelay,
+		MaxRestartDelay: &maxRestartDelay,
+	}
+	subprocess := NewSubprocess(config, zap.New(observed))
+	require.NoError(t, subprocess.Start(context.Background()))
+
+	restarting := func() *observer.ObservedLogs {
+		return logs.FilterMessage("restarting subprocess")
+	}
+	require.Eventually(t, func() bool {
+		return restarting().Len() >= 5
+	}, 5*time.Second, 5*time.Millisecond)
+	require.NoError(t, subprocess.Shutdown(context.Background()))
+
+	var delays []time.Duration
+	for _, entry := range restarting().All()[:5] {
+		delays = append(delays, entry.ContextMap()["delay"].(time.Duration))
+	}
+	require.Equal(t, []time.Duration{
+		10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond,
+	}, delays)
 }
 
 func TestShutdownTimeout(t *testing.T) {
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+const (
+	// StartAtBeginning reads the journal from its oldest entry on first start.
+	StartAtBeginning = "beginning"
+	// StartAtEnd only reads the entries written after the first start.
+	StartAtEnd = "end"
+)
+
+// priorities are the journal priorities, from the most to the least severe, which can be given by name or by number
+var priorities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+var errDirectoryAndFiles = errors.New("only one of directory or files can be specified")
+
+// Config defines configuration for the journald receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// Directory is the directory of the journal files to read, the local journal being read when neither
+	// the directory nor the files are set.
+	Directory string `mapstructure:"directory"`
+
+	// Files are the journal files to read.
+	Files []string `mapstructure:"files"`
+
+	// Units are the systemd units to read the entries of, all the entries being read when not set.
+	Units []string `mapstructure:"units"`
+
+	// Priority is the least severe priority of the entries to read, or a range of priorities such as err..warning.
+	// All the entries are read when not set.
+	Priority string `mapstructure:"priority"`
+
+	// StartAt is where to start reading the journal from on first start, end (default) or beginning. Once
+	// entries have been read, the reading resumes after the last one.
+	StartAt string `mapstructure:"start_at"`
+
+	// OffsetsFile is the path to the file where the cursor of the last entry read is persisted.
+	// When not set, the reading starts at start_at on each start.
+	OffsetsFile string `mapstructure:"offsets_file"`
+}
+
+func (cfg *Config) validate() error {
+	if cfg.Directory != "" && len(cfg.Files) > 0 {
+		return errDirectoryAndFiles
+	}
+	if cfg.StartAt != StartAtBeginning && cfg.StartAt != StartAtEnd {
+		return fmt.Errorf("invalid start_at %q, must be one of %q or %q", cfg.StartAt, StartAtBeginning, StartAtEnd)
+	}
+	if cfg.Priority != "" {
+		for _, p := range strings.SplitN(cfg.Priority, "..", 2) {
+			if !isPriority(p) {
+				return fmt.Errorf("invalid priority %q, must be a priority from 0 to 7 or one of %q, or a range of priorities", cfg.Priority, priorities)
+			}
+		}
+	}
+	return nil
+}
+
+func isPriority(p string) bool {
+	for i, name := range priorities {
+		if p == name || p == fmt.Sprint(i) {
+			return true
+		}
+	}
+	return false
+}
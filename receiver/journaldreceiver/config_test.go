@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, 2, len(config.Receivers))
+
+	assert.Equal(t, factory.CreateDefaultConfig(), config.Receivers["journald"])
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: "journald",
+				NameVal: "journald/ssh",
+			},
+			Directory:   "/var/log/journal",
+			Units:       []string{"ssh", "sshd"},
+			Priority:    "err..warning",
+			StartAt:     StartAtBeginning,
+			OffsetsFile: "./offsets.db",
+		},
+		config.Receivers["journald/ssh"])
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:   "priority number",
+			modify: func(cfg *Config) { cfg.Priority = "4" },
+		},
+		{
+			name:   "priority range",
+			modify: func(cfg *Config) { cfg.Priority = "0..warning" },
+		},
+		{
+			name: "directory and files",
+			modify: func(cfg *Config) {
+				cfg.Directory = "/var/log/journal"
+				cfg.Files = []string{"/var/log/journal/system.journal"}
+			},
+			err: "only one of directory or files can be specified",
+		},
+		{
+			name:   "invalid start_at",
+			modify: func(cfg *Config) { cfg.StartAt = "oldest" },
+			err:    `invalid start_at "oldest", must be one of "beginning" or "end"`,
+		},
+		{
+			name:   "invalid priority",
+			modify: func(cfg *Config) { cfg.Priority = "error" },
+			err: `invalid priority "error", must be a priority from 0 to 7 or one of ` +
+				`["emerg" "alert" "crit" "err" "warning" "notice" "info" "debug"], or a range of priorities`,
+		},
+		{
+			name:   "invalid priority range",
+			modify: func(cfg *Config) { cfg.Priority = "err..8" },
+			err: `invalid priority "err..8", must be a priority from 0 to 7 or one of ` +
+				`["emerg" "alert" "crit" "err" "warning" "notice" "info" "debug"], or a range of priorities`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			tt.modify(cfg)
+
+			err := cfg.validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package journaldreceiver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/journaldreceiver/internal/journald"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/stanzareceiver"
+)
+
+// createLogsReceiver creates a logs receiver based on provided config, running a stanza pipeline made of
+// a single journald input operator.
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	rCfg := cfg.(*Config)
+	if err := rCfg.validate(); err != nil {
+		return nil, err
+	}
+
+	operator := map[string]interface{}{
+		"type":     journald.OperatorType,
+		"start_at": rCfg.StartAt,
+	}
+	if rCfg.Directory != "" {
+		operator["directory"] = rCfg.Directory
+	}
+	if len(rCfg.Files) > 0 {
+		operator["files"] = rCfg.Files
+	}
+	if len(rCfg.Units) > 0 {
+		operator["units"] = rCfg.Units
+	}
+	if rCfg.Priority != "" {
+		operator["priority"] = rCfg.Priority
+	}
+
+	return stanzareceiver.NewFactory().CreateLogsReceiver(ctx, params, &stanzareceiver.Config{
+		ReceiverSettings: rCfg.ReceiverSettings,
+		OffsetsFile:      rCfg.OffsetsFile,
+		Operators:        stanzareceiver.OperatorConfig{operator},
+	}, nextConsumer)
+}
@@ -0,0 +1,13 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/journaldreceiver
+
+go 1.14
+
+require (
+	github.com/observiq/stanza v0.13.9
+	github.com/open-telemetry/opentelemetry-collector-contrib/receiver/stanzareceiver v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/receiver/stanzareceiver => ../stanzareceiver
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journald
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+)
+
+const (
+	cursorField    = "__CURSOR"
+	timestampField = "__REALTIME_TIMESTAMP"
+	messageField   = "MESSAGE"
+	priorityField  = "PRIORITY"
+)
+
+// severities maps the journal priorities, from 0 (emerg) to 7 (debug), to the severities
+var severities = [...]entry.Severity{
+	entry.Emergency,
+	entry.Alert,
+	entry.Critical,
+	entry.Error,
+	entry.Warning,
+	entry.Notice,
+	entry.Info,
+	entry.Debug,
+}
+
+// severityTexts are the names of the journal priorities, from 0 (emerg) to 7 (debug)
+var severityTexts = [...]string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// journalEntry is an entry exported by journalctl in the JSON format, whose fields are strings, arrays of
+// bytes for the binary ones, or arrays of values for the ones present several times in the entry
+type journalEntry map[string]interface{}
+
+func parseJournalEntry(line []byte) (journalEntry, error) {
+	var e journalEntry
+	if err := json.Unmarshal(line, &e); err != nil {
+		return nil, fmt.Errorf("failed to parse journal entry: %w", err)
+	}
+	if _, ok := e[cursorField].(string); !ok {
+		return nil, errors.New("journal entry is missing the __CURSOR field")
+	}
+	return e, nil
+}
+
+// cursor returns the cursor of the entry, from which the reading can resume after it
+func (e journalEntry) cursor() string {
+	return e[cursorField].(string)
+}
+
+// timestamp returns the time at which the entry was written, in microseconds since the epoch in the journal
+func (e journalEntry) timestamp() (time.Time, error) {
+	usec, err := strconv.ParseInt(fieldValue(e[timestampField]), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid __REALTIME_TIMESTAMP: %w", err)
+	}
+	return time.Unix(0, usec*int64(time.Microsecond)), nil
+}
+
+// message returns the message of the entry, which is the body of the record
+func (e journalEntry) message() string {
+	return fieldValue(e[messageField])
+}
+
+// severity maps the priority of the entry to a severity
+func (e journalEntry) severity() (entry.Severity, string) {
+	priority, err := strconv.Atoi(fieldValue(e[priorityField]))
+	if err != nil || priority < 0 || priority >= len(severities) {
+		return entry.Default, ""
+	}
+	return severities[priority], severityTexts[priority]
+}
+
+// labels returns the fields of the entry other than the ones mapped to the record, named after the fields
+func (e journalEntry) labels() map[string]string {
+	labels := make(map[string]string, len(e))
+	for k, v := range e {
+		switch k {
+		case cursorField, timestampField, messageField, priorityField:
+		default:
+			labels[k] = fieldValue(v)
+		}
+	}
+	return labels
+}
+
+// toEntry fills the given entry with the fields of the journal entry
+func (e journalEntry) toEntry(ent *entry.Entry) {
+	if timestamp, err := e.timestamp(); err == nil {
+		ent.Timestamp = timestamp
+	}
+	ent.Severity, ent.SeverityText = e.severity()
+	for k, v := range e.labels() {
+		ent.AddLabel(k, v)
+	}
+}
+
+// fieldValue returns the value of a field as a string: the binary fields are decoded from their bytes, and
+// the values of the fields present several times are joined by new lines
+func fieldValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case []interface{}:
+		if b, ok := toBytes(value); ok {
+			return string(b)
+		}
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			values = append(values, fieldValue(item))
+		}
+		return strings.Join(values, "\n")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// toBytes returns the bytes of a binary field, exported as an array of numbers
+func toBytes(values []interface{}) ([]byte, bool) {
+	b := make([]byte, 0, len(values))
+	for _, v := range values {
+		n, ok := v.(float64)
+		if !ok || n < 0 || n > 255 {
+			return nil, false
+		}
+		b = append(b, byte(n))
+	}
+	return b, true
+}
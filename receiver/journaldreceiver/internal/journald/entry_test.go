@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journald
+
+import (
+	"testing"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const journalLine = `{"__CURSOR":"s=b1e7;i=1eed30","__REALTIME_TIMESTAMP":"1587047866229555","__MONOTONIC_TIMESTAMP":"685540311557",` +
+	`"_HOSTNAME":"myhostname","_PID":"13894","_SYSTEMD_UNIT":"user@1000.service","PRIORITY":"3",` +
+	`"SYSLOG_IDENTIFIER":"systemd","MESSAGE":"run-docker-netns.mount: Succeeded."}`
+
+func TestParseJournalEntry(t *testing.T) {
+	parsed, err := parseJournalEntry([]byte(journalLine))
+	require.NoError(t, err)
+
+	assert.Equal(t, "s=b1e7;i=1eed30", parsed.cursor())
+	assert.Equal(t, "run-docker-netns.mount: Succeeded.", parsed.message())
+
+	ent := entry.New()
+	parsed.toEntry(ent)
+	assert.Equal(t, time.Unix(1587047866, 229555000), ent.Timestamp)
+	assert.Equal(t, entry.Error, ent.Severity)
+	assert.Equal(t, "err", ent.SeverityText)
+	assert.Equal(t, map[string]string{
+		"__MONOTONIC_TIMESTAMP": "685540311557",
+		"_HOSTNAME":             "myhostname",
+		"_PID":                  "13894",
+		"_SYSTEMD_UNIT":         "user@1000.service",
+		"SYSLOG_IDENTIFIER":     "systemd",
+	}, ent.Labels)
+}
+
+func TestParseJournalEntryFieldValues(t *testing.T) {
+	parsed, err := parseJournalEntry([]byte(`{"__CURSOR":"c","MESSAGE":[104,105,10],"TAG":["a","b"],"EMPTY":null}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "hi\n", parsed.message())
+
+	ent := entry.New()
+	parsed.toEntry(ent)
+	assert.Equal(t, map[string]string{"TAG": "a\nb", "EMPTY": ""}, ent.Labels)
+	assert.Equal(t, entry.Default, ent.Severity)
+	assert.Equal(t, "", ent.SeverityText)
+}
+
+func TestParseJournalEntryErrors(t *testing.T) {
+	_, err := parseJournalEntry([]byte(`{"MESSAGE":`))
+	assert.EqualError(t, err, "failed to parse journal entry: unexpected end of JSON input")
+
+	_, err = parseJournalEntry([]byte(`{"MESSAGE":"hello"}`))
+	assert.EqualError(t, err, "journal entry is missing the __CURSOR field")
+}
+
+func TestSeverity(t *testing.T) {
+	expected := []struct {
+		severity entry.Severity
+		text     string
+	}{
+		{entry.Emergency, "emerg"},
+		{entry.Alert, "alert"},
+		{entry.Critical, "crit"},
+		{entry.Error, "err"},
+		{entry.Warning, "warning"},
+		{entry.Notice, "notice"},
+		{entry.Info, "info"},
+		{entry.Debug, "debug"},
+	}
+
+	for priority, e := range expected {
+		severity, text := journalEntry{priorityField: string(rune('0' + priority))}.severity()
+		assert.Equal(t, e.severity, severity)
+		assert.Equal(t, e.text, text)
+	}
+
+	severity, _ := journalEntry{priorityField: "8"}.severity()
+	assert.Equal(t, entry.Default, severity)
+}
@@ -0,0 +1,293 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package journald
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/observiq/stanza/operator"
+	"github.com/observiq/stanza/operator/helper"
+	"go.uber.org/zap"
+)
+
+const (
+	// OperatorType is the type of the stanza operator reading the journal.
+	OperatorType = "journald_input"
+
+	// cursorKey is the key of the persisted cursor of the last entry read
+	cursorKey = "cursor"
+
+	minRestartBackoff = time.Second
+	maxRestartBackoff = time.Minute
+
+	// maxStderrLength is the maximum length of the output of journalctl reported when it exits
+	maxStderrLength = 1024
+)
+
+func init() {
+	operator.Register(OperatorType, func() operator.Builder { return NewInputConfig("") })
+}
+
+// NewInputConfig creates the config of a journald input operator with the default values.
+func NewInputConfig(operatorID string) *InputConfig {
+	return &InputConfig{
+		InputConfig: helper.NewInputConfig(operatorID, OperatorType),
+		StartAt:     "end",
+	}
+}
+
+// InputConfig is the configuration of a journald input operator.
+type InputConfig struct {
+	helper.InputConfig `yaml:",inline"`
+
+	Directory string   `json:"directory,omitempty" yaml:"directory,omitempty"`
+	Files     []string `json:"files,omitempty"     yaml:"files,omitempty"`
+	Units     []string `json:"units,omitempty"     yaml:"units,omitempty"`
+	Priority  string   `json:"priority,omitempty"  yaml:"priority,omitempty"`
+	StartAt   string   `json:"start_at,omitempty"  yaml:"start_at,omitempty"`
+}
+
+// Build builds a journald input operator from the configuration.
+func (c InputConfig) Build(buildContext operator.BuildContext) ([]operator.Operator, error) {
+	inputOperator, err := c.InputConfig.Build(buildContext)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.StartAt != "end" && c.StartAt != "beginning" {
+		return nil, fmt.Errorf("invalid value '%s' for parameter 'start_at'", c.StartAt)
+	}
+
+	return []operator.Operator{&Input{
+		InputOperator: inputOperator,
+		persister:     helper.NewScopedDBPersister(buildContext.Database, c.ID()),
+		command:       "journalctl",
+		args:          c.args(),
+	}}, nil
+}
+
+// args returns the arguments of journalctl, following the journal in the JSON format, without the cursor
+func (c InputConfig) args() []string {
+	args := []string{"--utc", "--output=json", "--follow"}
+	if c.StartAt == "beginning" {
+		args = append(args, "--no-tail")
+	} else {
+		args = append(args, "--lines=0")
+	}
+
+	if c.Directory != "" {
+		args = append(args, "--directory", c.Directory)
+	}
+	for _, file := range c.Files {
+		args = append(args, "--file", file)
+	}
+	for _, unit := range c.Units {
+		args = append(args, "--unit", unit)
+	}
+	if c.Priority != "" {
+		args = append(args, "--priority", c.Priority)
+	}
+	return args
+}
+
+// Input is an operator reading the journal entries exported by journalctl, which is restarted with a backoff
+// when it exits. The cursor of the last entry read is persisted in the offsets database, from which the reading
+// resumes after a restart.
+type Input struct {
+	helper.InputOperator
+
+	persister helper.Persister
+	command   string
+	args      []string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start starts journalctl and reads its entries.
+func (i *Input) Start() error {
+	if err := i.persister.Load(); err != nil {
+		return fmt.Errorf("failed to load the cursor: %w", err)
+	}
+
+	// fail fast when journalctl isn't available at all, the next failures being retried
+	if _, err := exec.LookPath(i.command); err != nil {
+		return fmt.Errorf("failed to find journalctl: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i.cancel = cancel
+
+	i.wg.Add(2)
+	go i.run(ctx)
+	go i.syncCursor(ctx)
+	return nil
+}
+
+// Stop stops journalctl and persists the cursor of the last entry read.
+func (i *Input) Stop() error {
+	if i.cancel == nil {
+		return nil
+	}
+	i.cancel()
+	i.wg.Wait()
+	i.sync()
+	return nil
+}
+
+// run follows the journal until the context is done, restarting journalctl with an exponential backoff
+// when it exits, the backoff being reset once it ran for longer than the maximum backoff
+func (i *Input) run(ctx context.Context) {
+	defer i.wg.Done()
+
+	backoff := minRestartBackoff
+	for {
+		started := time.Now()
+		err := i.follow(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > maxRestartBackoff {
+			backoff = minRestartBackoff
+		}
+		i.Errorw("journalctl exited, restarting it", zap.Duration("backoff", backoff), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxRestartBackoff {
+			backoff = maxRestartBackoff
+		}
+	}
+}
+
+// follow starts journalctl after the last entry read, and writes its entries until it exits
+func (i *Input) follow(ctx context.Context) error {
+	args := i.args
+	if cursor := i.persister.Get(cursorKey); len(cursor) > 0 {
+		args = append(args[:len(args):len(args)], "--after-cursor", string(cursor))
+	}
+
+	cmd := exec.CommandContext(ctx, i.command, args...)
+	stderr := &limitedBuffer{limit: maxStderrLength}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get the journalctl stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start journalctl: %w", err)
+	}
+
+	i.read(ctx, stdout)
+
+	if err := cmd.Wait(); err != nil {
+		if output := strings.TrimSpace(stderr.String()); output != "" {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return err
+	}
+	return nil
+}
+
+// read writes the entries read from the given journalctl output
+func (i *Input) read(ctx context.Context, stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if err != io.EOF {
+				i.Errorw("Failed to read the journalctl output", zap.Error(err))
+			}
+			return
+		}
+
+		i.processLine(ctx, line)
+	}
+}
+
+// processLine writes the journal entry of the given line as an entry, and moves the cursor to it
+func (i *Input) processLine(ctx context.Context, line []byte) {
+	parsed, err := parseJournalEntry(line)
+	if err != nil {
+		i.Warnw("Failed to parse journal entry", zap.Error(err))
+		return
+	}
+	i.persister.Set(cursorKey, []byte(parsed.cursor()))
+
+	ent, err := i.NewEntry(parsed.message())
+	if err != nil {
+		i.Errorw("Failed to create entry", zap.Error(err))
+		return
+	}
+	parsed.toEntry(ent)
+	i.Write(ctx, ent)
+}
+
+// syncCursor persists the cursor every second until the context is done, so that the reading resumes after
+// the last entry read on restart
+func (i *Input) syncCursor(ctx context.Context) {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			i.sync()
+		}
+	}
+}
+
+func (i *Input) sync() {
+	if err := i.persister.Sync(); err != nil {
+		i.Errorw("Failed to sync the cursor", zap.Error(err))
+	}
+}
+
+// limitedBuffer keeps the first bytes written to it, up to its limit
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.Buffer.Write(p[:remaining])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
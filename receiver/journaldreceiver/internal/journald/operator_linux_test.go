@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package journald
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+	"github.com/observiq/stanza/operator"
+	"github.com/observiq/stanza/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputConfigArgs(t *testing.T) {
+	cfg := NewInputConfig("journald")
+	assert.Equal(t, []string{"--utc", "--output=json", "--follow", "--lines=0"}, cfg.args())
+
+	cfg.StartAt = "beginning"
+	cfg.Directory = "/var/log/journal"
+	cfg.Units = []string{"ssh", "docker"}
+	cfg.Priority = "err..warning"
+	assert.Equal(t, []string{
+		"--utc", "--output=json", "--follow", "--no-tail",
+		"--directory", "/var/log/journal",
+		"--unit", "ssh", "--unit", "docker",
+		"--priority", "err..warning",
+	}, cfg.args())
+
+	cfg = NewInputConfig("journald")
+	cfg.Files = []string{"/tmp/a.journal", "/tmp/b.journal"}
+	assert.Equal(t, []string{
+		"--utc", "--output=json", "--follow", "--lines=0",
+		"--file", "/tmp/a.journal", "--file", "/tmp/b.journal",
+	}, cfg.args())
+}
+
+func TestBuildInvalidStartAt(t *testing.T) {
+	cfg := NewInputConfig("journald")
+	cfg.StartAt = "oldest"
+	_, err := cfg.Build(testutil.NewBuildContext(t))
+	assert.EqualError(t, err, "invalid value 'oldest' for parameter 'start_at'")
+}
+
+func TestStartMissingJournalctl(t *testing.T) {
+	input, _ := newTestInput(t)
+	input.command = filepath.Join(testutil.NewTempDir(t), "journalctl")
+	assert.Contains(t, input.Start().Error(), "failed to find journalctl")
+}
+
+// TestInputRestartsJournalctl runs a fake journalctl exiting after each entry, which is restarted
+// after the cursor of the last entry read
+func TestInputRestartsJournalctl(t *testing.T) {
+	dir := testutil.NewTempDir(t)
+	argsFile := filepath.Join(dir, "args")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$@" >> %[1]s
+n=$(wc -l < %[1]s)
+echo '{"__CURSOR":"cursor-'$n'","__REALTIME_TIMESTAMP":"1587047866229555","PRIORITY":"6","MESSAGE":"message '$n'"}'
+echo "failed" >&2
+exit 1
+`, argsFile)
+	command := filepath.Join(dir, "journalctl")
+	require.NoError(t, ioutil.WriteFile(command, []byte(script), 0700))
+
+	input, output := newTestInput(t)
+	input.command = command
+	require.NoError(t, input.Start())
+	defer input.Stop()
+
+	for _, message := range []string{"message 1", "message 2"} {
+		select {
+		case ent := <-output.Received:
+			assert.Equal(t, message, ent.Record)
+			assert.Equal(t, entry.Info, ent.Severity)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "Timed out waiting for entry")
+		}
+	}
+	require.NoError(t, input.Stop())
+
+	args, err := ioutil.ReadFile(argsFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(args)), "\n")
+	require.True(t, len(lines) >= 2)
+	assert.Equal(t, "--utc --output=json --follow --lines=0", lines[0])
+	assert.Equal(t, "--utc --output=json --follow --lines=0 --after-cursor cursor-1", lines[1])
+	assert.Equal(t, "cursor-2", string(input.persister.Get(cursorKey)))
+}
+
+func newTestInput(t *testing.T) (*Input, *testutil.FakeOutput) {
+	cfg := NewInputConfig("journald")
+	cfg.OutputIDs = []string{"fake"}
+	ops, err := cfg.Build(testutil.NewBuildContext(t))
+	require.NoError(t, err)
+
+	input := ops[0].(*Input)
+	output := testutil.NewFakeOutput(t)
+	require.NoError(t, input.SetOutputs([]operator.Operator{output}))
+	return input, output
+}
@@ -34,6 +34,9 @@ type Config struct {
 	// Node condition types to report. See all condition types, see
 	// here: https://kubernetes.io/docs/concepts/architecture/nodes/#condition.
 	NodeConditionTypesToReport []string `mapstructure:"node_conditions_to_report"`
+	// Types of the Kubernetes events to emit as log records, when the receiver is
+	// used in a logs pipeline. See https://kubernetes.io/docs/reference/kubernetes-api/cluster-resources/event-v1/.
+	EventTypesToReport []string `mapstructure:"event_types_to_report"`
 	// List of exporters to which metadata from this receiver should be forwarded to.
 	MetadataExporters []string `mapstructure:"metadata_exporters"`
 
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+// Keys for the attributes of the log records emitted for the Kubernetes events.
+const (
+	eventNameKey            = "k8s.event.name"
+	eventUIDKey             = "k8s.event.uid"
+	eventReasonKey          = "k8s.event.reason"
+	eventActionKey          = "k8s.event.action"
+	eventCountKey           = "k8s.event.count"
+	eventSourceComponentKey = "k8s.event.source.component"
+	eventSourceHostKey      = "k8s.event.source.host"
+
+	objectKindKey            = "k8s.object.kind"
+	objectNameKey            = "k8s.object.name"
+	objectUIDKey             = "k8s.object.uid"
+	objectAPIVersionKey      = "k8s.object.api_version"
+	objectResourceVersionKey = "k8s.object.resource_version"
+	objectFieldPathKey       = "k8s.object.fieldpath"
+)
+
+var _ component.LogsReceiver = (*eventsReceiver)(nil)
+
+// eventsReceiver emits the Kubernetes events as log records.
+type eventsReceiver struct {
+	config     *Config
+	logger     *zap.Logger
+	consumer   consumer.LogsConsumer
+	client     kubernetes.Interface
+	eventTypes map[string]bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	// startTime is the time the receiver was started at: the events which last occurred before it
+	// are part of the initial listing of the informer, and aren't emitted
+	startTime time.Time
+}
+
+// newEventsReceiver creates the receiver emitting the Kubernetes events as logs with the given configuration.
+func newEventsReceiver(
+	logger *zap.Logger, config *Config, consumer consumer.LogsConsumer,
+	client kubernetes.Interface) (component.LogsReceiver, error) {
+	return &eventsReceiver{
+		config:     config,
+		logger:     logger,
+		consumer:   consumer,
+		client:     client,
+		eventTypes: utils.StringSliceToMap(config.EventTypesToReport),
+	}, nil
+}
+
+func (er *eventsReceiver) Start(ctx context.Context, _ component.Host) error {
+	er.ctx, er.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, er.config.Name(), transport))
+	er.startTime = time.Now()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(er.client, 0)
+	informer := factory.Core().V1().Events().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: er.onAdd,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Repeated events are updated in place, with their count incremented.
+			er.onAdd(newObj)
+		},
+	})
+
+	er.logger.Info("Starting the Kubernetes events informer.")
+	factory.Start(er.ctx.Done())
+	return nil
+}
+
+func (er *eventsReceiver) Shutdown(context.Context) error {
+	if er.cancel != nil {
+		er.cancel()
+	}
+	return nil
+}
+
+func (er *eventsReceiver) onAdd(obj interface{}) {
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+	if !er.eventTypes[ev.Type] {
+		return
+	}
+	if eventTimestamp(ev).Before(er.startTime) {
+		return
+	}
+
+	c := obsreport.StartLogsReceiveOp(er.ctx, typeStr, transport)
+	err := er.consumer.ConsumeLogs(c, eventToLogs(ev))
+	if err != nil {
+		er.logger.Error("Failed to consume the Kubernetes event", zap.String("event", ev.Name), zap.Error(err))
+	}
+	obsreport.EndLogsReceiveOp(c, typeStr, 1, err)
+}
+
+// eventToLogs converts the given Kubernetes event to a log record, with the
+// identifiers of the involved object as attributes.
+func eventToLogs(ev *corev1.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+
+	namespace := ev.InvolvedObject.Namespace
+	if namespace == "" {
+		namespace = ev.Namespace
+	}
+	if namespace != "" {
+		rl.Resource().Attributes().InsertString(conventions.AttributeK8sNamespace, namespace)
+	}
+
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(1)
+	lr := logs.At(0)
+
+	lr.SetName(ev.Reason)
+	lr.SetTimestamp(pdata.TimestampUnixNano(uint64(eventTimestamp(ev).UnixNano())))
+	lr.SetSeverityText(ev.Type)
+	if ev.Type == corev1.EventTypeWarning {
+		lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+	} else {
+		lr.SetSeverityNumber(pdata.SeverityNumberINFO)
+	}
+	lr.Body().SetStringVal(ev.Message)
+
+	attrs := lr.Attributes()
+	attrs.InsertString(eventNameKey, ev.Name)
+	attrs.InsertString(eventUIDKey, string(ev.UID))
+	attrs.InsertString(eventReasonKey, ev.Reason)
+	insertIfNotEmpty(attrs, eventActionKey, ev.Action)
+	attrs.InsertInt(eventCountKey, int64(ev.Count))
+	insertIfNotEmpty(attrs, eventSourceComponentKey, ev.Source.Component)
+	insertIfNotEmpty(attrs, eventSourceHostKey, ev.Source.Host)
+
+	obj := ev.InvolvedObject
+	attrs.InsertString(objectKindKey, obj.Kind)
+	attrs.InsertString(objectNameKey, obj.Name)
+	insertIfNotEmpty(attrs, objectUIDKey, string(obj.UID))
+	insertIfNotEmpty(attrs, objectAPIVersionKey, obj.APIVersion)
+	insertIfNotEmpty(attrs, objectResourceVersionKey, obj.ResourceVersion)
+	insertIfNotEmpty(attrs, objectFieldPathKey, obj.FieldPath)
+
+	return ld
+}
+
+// eventTimestamp returns the time the given event last occurred at, falling back to the
+// creation time of the event when it isn't set.
+func eventTimestamp(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.EventTime.IsZero():
+		if ev.Series != nil && !ev.Series.LastObservedTime.IsZero() {
+			return ev.Series.LastObservedTime.Time
+		}
+		return ev.EventTime.Time
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	}
+	return ev.CreationTimestamp.Time
+}
+
+func insertIfNotEmpty(attrs pdata.AttributeMap, key string, value string) {
+	if value != "" {
+		attrs.InsertString(key, value)
+	}
+}
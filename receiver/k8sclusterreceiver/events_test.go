@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEventsReceiver(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	consumer := new(consumertest.LogsSink)
+
+	// Events occurred before the receiver starts aren't emitted.
+	createEvent(t, client, "old", corev1.EventTypeWarning, time.Now().Add(-time.Hour))
+
+	r, err := newEventsReceiver(zap.NewNop(), &Config{EventTypesToReport: []string{"Warning"}}, consumer, client)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	defer r.Shutdown(ctx)
+
+	createEvent(t, client, "normal", corev1.EventTypeNormal, time.Now().Add(time.Second))
+	createEvent(t, client, "warning", corev1.EventTypeWarning, time.Now().Add(time.Second))
+
+	require.Eventually(t, func() bool {
+		return consumer.LogRecordsCount() == 1
+	}, 10*time.Second, 100*time.Millisecond,
+		"event not emitted")
+
+	lr := consumer.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	name, ok := lr.Attributes().Get(eventNameKey)
+	require.True(t, ok)
+	assert.Equal(t, "warning", name.StringVal())
+}
+
+func TestEventToLogs(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	ev := &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "pod1.16572ab1f1c3a3f0",
+			Namespace: "test",
+			UID:       types.UID("event1"),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:            "Pod",
+			Namespace:       "test",
+			Name:            "pod1",
+			UID:             types.UID("pod1"),
+			APIVersion:      "v1",
+			ResourceVersion: "1234",
+			FieldPath:       "spec.containers{container1}",
+		},
+		Reason:        "BackOff",
+		Message:       "Back-off restarting failed container",
+		Source:        corev1.EventSource{Component: "kubelet", Host: "node1"},
+		LastTimestamp: v1.NewTime(timestamp),
+		Count:         3,
+		Type:          corev1.EventTypeWarning,
+	}
+
+	ld := eventToLogs(ev)
+	require.Equal(t, 1, ld.LogRecordCount())
+
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"k8s.namespace.name": "test",
+	}, tracetranslator.AttributeMapToMap(rl.Resource().Attributes()))
+
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "BackOff", lr.Name())
+	assert.Equal(t, pdata.TimestampUnixNano(uint64(timestamp.UnixNano())), lr.Timestamp())
+	assert.Equal(t, "Warning", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, "Back-off restarting failed container", lr.Body().StringVal())
+	assert.Equal(t, map[string]interface{}{
+		"k8s.event.name":              "pod1.16572ab1f1c3a3f0",
+		"k8s.event.uid":               "event1",
+		"k8s.event.reason":            "BackOff",
+		"k8s.event.count":             int64(3),
+		"k8s.event.source.component":  "kubelet",
+		"k8s.event.source.host":       "node1",
+		"k8s.object.kind":             "Pod",
+		"k8s.object.name":             "pod1",
+		"k8s.object.uid":              "pod1",
+		"k8s.object.api_version":      "v1",
+		"k8s.object.resource_version": "1234",
+		"k8s.object.fieldpath":        "spec.containers{container1}",
+	}, tracetranslator.AttributeMapToMap(lr.Attributes()))
+}
+
+func TestEventTimestamp(t *testing.T) {
+	first := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	last := first.Add(time.Minute)
+	created := first.Add(-time.Second)
+
+	tests := []struct {
+		name string
+		ev   *corev1.Event
+		want time.Time
+	}{
+		{
+			name: "event_time",
+			ev:   &corev1.Event{EventTime: v1.NewMicroTime(first), LastTimestamp: v1.NewTime(last)},
+			want: first,
+		},
+		{
+			name: "series",
+			ev: &corev1.Event{
+				EventTime: v1.NewMicroTime(first),
+				Series:    &corev1.EventSeries{Count: 2, LastObservedTime: v1.NewMicroTime(last)},
+			},
+			want: last,
+		},
+		{
+			name: "last_timestamp",
+			ev:   &corev1.Event{FirstTimestamp: v1.NewTime(first), LastTimestamp: v1.NewTime(last)},
+			want: last,
+		},
+		{
+			name: "first_timestamp",
+			ev:   &corev1.Event{FirstTimestamp: v1.NewTime(first)},
+			want: first,
+		},
+		{
+			name: "creation_timestamp",
+			ev:   &corev1.Event{ObjectMeta: v1.ObjectMeta{CreationTimestamp: v1.NewTime(created)}},
+			want: created,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(eventTimestamp(tt.ev)))
+		})
+	}
+}
+
+func createEvent(t *testing.T, client *fake.Clientset, name string, eventType string, timestamp time.Time) {
+	ev := &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "test",
+			Name:      "pod1",
+		},
+		Reason:        "Reason",
+		LastTimestamp: v1.NewTime(timestamp),
+		Type:          eventType,
+	}
+
+	_, err := client.CoreV1().Events(ev.Namespace).Create(context.Background(), ev, v1.CreateOptions{})
+	require.NoError(t, err)
+}
@@ -34,7 +34,10 @@ const (
 	defaultCollectionInterval = 10 * time.Second
 )
 
-var defaultNodeConditionsToReport = []string{"Ready"}
+var (
+	defaultNodeConditionsToReport = []string{"Ready"}
+	defaultEventTypesToReport     = []string{"Warning"}
+)
 
 func createDefaultConfig() configmodels.Receiver {
 	return &Config{
@@ -44,6 +47,7 @@ func createDefaultConfig() configmodels.Receiver {
 		},
 		CollectionInterval:         defaultCollectionInterval,
 		NodeConditionTypesToReport: defaultNodeConditionsToReport,
+		EventTypesToReport:         defaultEventTypesToReport,
 		APIConfig: k8sconfig.APIConfig{
 			AuthType: k8sconfig.AuthTypeServiceAccount,
 		},
@@ -62,10 +66,23 @@ func createMetricsReceiver(
 	return newReceiver(params.Logger, rCfg, consumer, k8sClient)
 }
 
+func createLogsReceiver(
+	_ context.Context, params component.ReceiverCreateParams, cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer) (component.LogsReceiver, error) {
+	rCfg := cfg.(*Config)
+
+	k8sClient, err := rCfg.getK8sClient()
+	if err != nil {
+		return nil, err
+	}
+	return newEventsReceiver(params.Logger, rCfg, consumer, k8sClient)
+}
+
 // NewFactory creates a factory for k8s_cluster receiver.
 func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
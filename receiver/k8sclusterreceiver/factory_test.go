@@ -21,10 +21,12 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
@@ -44,6 +46,7 @@ func TestFactory(t *testing.T) {
 		},
 		CollectionInterval:         10 * time.Second,
 		NodeConditionTypesToReport: defaultNodeConditionsToReport,
+		EventTypesToReport:         defaultEventTypesToReport,
 		APIConfig: k8sconfig.APIConfig{
 			AuthType: k8sconfig.AuthTypeServiceAccount,
 		},
@@ -83,6 +86,34 @@ func TestFactory(t *testing.T) {
 	require.Error(t, r.Start(context.Background(), nopHostWithExporters{}))
 }
 
+func TestFactoryLogsReceiver(t *testing.T) {
+	f := NewFactory()
+	rCfg := f.CreateDefaultConfig().(*Config)
+
+	// Fails with bad K8s Config.
+	r, err := f.CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{},
+		rCfg, consumertest.NewLogsNop(),
+	)
+	require.Error(t, err)
+	require.Nil(t, r)
+
+	// Override for tests.
+	rCfg.makeClient = func(apiConf k8sconfig.APIConfig) (kubernetes.Interface, error) {
+		return fake.NewSimpleClientset(), nil
+	}
+	r, err = f.CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()},
+		rCfg, consumertest.NewLogsNop(),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	require.NoError(t, r.Shutdown(ctx))
+}
+
 // nopHostWithExporters mocks a receiver.ReceiverHost for test purposes.
 type nopHostWithExporters struct {
 }
@@ -17,6 +17,8 @@ package kubelet
 const (
 	labelNodeName                  = "k8s.node.name"
 	labelPersistentVolumeClaimName = "k8s.persistentvolumeclaim.name"
+	labelPersistentVolumeName      = "k8s.persistentvolume.name"
+	labelStorageClassName          = "k8s.storageclass.name"
 	labelVolumeName                = "k8s.volume.name"
 	labelVolumeType                = "k8s.volume.type"
 
@@ -113,6 +113,20 @@ func getLabelsFromVolume(volume v1.Volume, labels map[string]string) {
 	}
 }
 
+// GetPersistentVolumeMetadataLabels sets the name of the persistent volume bound to the given
+// claim and its storage class, falling back to the one requested by the claim, in the labels.
+func GetPersistentVolumeMetadataLabels(pvc *v1.PersistentVolumeClaim, pv *v1.PersistentVolume, labels map[string]string) {
+	labels[labelPersistentVolumeName] = pv.Name
+
+	storageClass := pv.Spec.StorageClassName
+	if storageClass == "" && pvc.Spec.StorageClassName != nil {
+		storageClass = *pvc.Spec.StorageClassName
+	}
+	if storageClass != "" {
+		labels[labelStorageClassName] = storageClass
+	}
+}
+
 func GetPersistentVolumeLabels(pv v1.PersistentVolumeSource, labels map[string]string) {
 	// TODO: Support more types
 	switch {
@@ -37,7 +37,12 @@ func getValidMockedObjects() []runtime.Object {
 
 var volumeClaim1 = getPVC("volume_claim_1", "kube-system", "storage-provisioner-token-qzlx6")
 var volumeClaim2 = getPVC("volume_claim_2", "kube-system", "kube-proxy")
-var volumeClaim3 = getPVC("volume_claim_3", "kube-system", "coredns-token-dzc5t")
+var volumeClaim3 = func() *v1.PersistentVolumeClaim {
+	pvc := getPVC("volume_claim_3", "kube-system", "coredns-token-dzc5t")
+	storageClass := "glusterfs-storage"
+	pvc.Spec.StorageClassName = &storageClass
+	return pvc
+}()
 
 func getPVC(claimName, namespace, volumeName string) *v1.PersistentVolumeClaim {
 	return &v1.PersistentVolumeClaim{
@@ -59,6 +64,7 @@ var awsPersistentVolume = func() *v1.PersistentVolume {
 			UID:  "volume_name_1",
 		},
 		Spec: v1.PersistentVolumeSpec{
+			StorageClassName: "gp2",
 			PersistentVolumeSource: v1.PersistentVolumeSource{
 				AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{
 					VolumeID:  "volume_id",
@@ -134,6 +134,7 @@ func (r *runnable) detailedPVCLabelsSetter() func(volCacheID, volumeClaim, names
 			}
 
 			labelsToCache := make(map[string]string)
+			kubelet.GetPersistentVolumeMetadataLabels(pvc, pv, labelsToCache)
 			kubelet.GetPersistentVolumeLabels(pv.Spec.PersistentVolumeSource, labelsToCache)
 
 			// Cache collected labels.
@@ -235,26 +235,31 @@ func TestRunnableWithPVCDetailedLabels(t *testing.T) {
 					name: "storage-provisioner-token-qzlx6",
 					typ:  "awsElasticBlockStore",
 					labels: map[string]string{
-						"aws.volume.id": "volume_id",
-						"fs.type":       "fs_type",
-						"partition":     "10",
+						"k8s.persistentvolume.name": "storage-provisioner-token-qzlx6",
+						"k8s.storageclass.name":     "gp2",
+						"aws.volume.id":             "volume_id",
+						"fs.type":                   "fs_type",
+						"partition":                 "10",
 					},
 				},
 				"volume_claim_2": {
 					name: "kube-proxy",
 					typ:  "gcePersistentDisk",
 					labels: map[string]string{
-						"gce.pd.name": "pd_name",
-						"fs.type":     "fs_type",
-						"partition":   "10",
+						"k8s.persistentvolume.name": "kube-proxy",
+						"gce.pd.name":               "pd_name",
+						"fs.type":                   "fs_type",
+						"partition":                 "10",
 					},
 				},
 				"volume_claim_3": {
 					name: "coredns-token-dzc5t",
 					typ:  "glusterfs",
 					labels: map[string]string{
-						"glusterfs.endpoints.name": "endpoints_name",
-						"glusterfs.path":           "path",
+						"k8s.persistentvolume.name": "coredns-token-dzc5t",
+						"k8s.storageclass.name":     "glusterfs-storage",
+						"glusterfs.endpoints.name":  "endpoints_name",
+						"glusterfs.path":            "path",
 					},
 				},
 			},
@@ -279,18 +284,21 @@ func TestRunnableWithPVCDetailedLabels(t *testing.T) {
 					name: "storage-provisioner-token-qzlx6",
 					typ:  "awsElasticBlockStore",
 					labels: map[string]string{
-						"aws.volume.id": "volume_id",
-						"fs.type":       "fs_type",
-						"partition":     "10",
+						"k8s.persistentvolume.name": "storage-provisioner-token-qzlx6",
+						"k8s.storageclass.name":     "gp2",
+						"aws.volume.id":             "volume_id",
+						"fs.type":                   "fs_type",
+						"partition":                 "10",
 					},
 				},
 				"volume_claim_2": {
 					name: "kube-proxy",
 					typ:  "gcePersistentDisk",
 					labels: map[string]string{
-						"gce.pd.name": "pd_name",
-						"fs.type":     "fs_type",
-						"partition":   "10",
+						"k8s.persistentvolume.name": "kube-proxy",
+						"gce.pd.name":               "pd_name",
+						"fs.type":                   "fs_type",
+						"partition":                 "10",
 					},
 				},
 			},
@@ -310,18 +318,21 @@ func TestRunnableWithPVCDetailedLabels(t *testing.T) {
 					name: "storage-provisioner-token-qzlx6",
 					typ:  "awsElasticBlockStore",
 					labels: map[string]string{
-						"aws.volume.id": "volume_id",
-						"fs.type":       "fs_type",
-						"partition":     "10",
+						"k8s.persistentvolume.name": "storage-provisioner-token-qzlx6",
+						"k8s.storageclass.name":     "gp2",
+						"aws.volume.id":             "volume_id",
+						"fs.type":                   "fs_type",
+						"partition":                 "10",
 					},
 				},
 				"volume_claim_2": {
 					name: "kube-proxy",
 					typ:  "gcePersistentDisk",
 					labels: map[string]string{
-						"gce.pd.name": "pd_name",
-						"fs.type":     "fs_type",
-						"partition":   "10",
+						"k8s.persistentvolume.name": "kube-proxy",
+						"gce.pd.name":               "pd_name",
+						"fs.type":                   "fs_type",
+						"partition":                 "10",
 					},
 				},
 			},
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbreceiver
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// client runs the diagnostic commands of the MongoDB servers
+type client interface {
+	listDatabaseNames(ctx context.Context) ([]string, error)
+	serverStatus(ctx context.Context) (bson.M, error)
+	dbStats(ctx context.Context, database string) (bson.M, error)
+	disconnect(ctx context.Context) error
+}
+
+type mongoDBClient struct {
+	client *mongo.Client
+}
+
+var _ client = (*mongoDBClient)(nil)
+
+// newMongoDBClient creates a client for the configured servers. The connections are opened when
+// the servers are queried.
+func newMongoDBClient(ctx context.Context, cfg *Config) (client, error) {
+	opts, err := clientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &mongoDBClient{client: c}, nil
+}
+
+// clientOptions returns the options of the driver for the given configuration
+func clientOptions(cfg *Config) (*options.ClientOptions, error) {
+	hosts := make([]string, 0, len(cfg.Hosts))
+	for _, h := range cfg.Hosts {
+		hosts = append(hosts, h.Endpoint)
+	}
+
+	opts := options.Client().SetHosts(hosts)
+	if cfg.ReplicaSet != "" {
+		opts.SetReplicaSet(cfg.ReplicaSet)
+	} else if len(hosts) == 1 {
+		// query the given server, whatever its role, rather than discovering the topology
+		opts.SetDirect(true)
+	}
+	if cfg.Username != "" {
+		// the SCRAM mechanism is negotiated with the server
+		opts.SetAuth(options.Credential{
+			Username: cfg.Username,
+			Password: cfg.Password,
+		})
+	}
+
+	tlsCfg, err := cfg.TLS.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts.SetTLSConfig(tlsCfg)
+	}
+
+	return opts, opts.Validate()
+}
+
+// listDatabaseNames returns the names of all the databases
+func (c *mongoDBClient) listDatabaseNames(ctx context.Context) ([]string, error) {
+	return c.client.ListDatabaseNames(ctx, bson.D{})
+}
+
+// serverStatus returns the output of the serverStatus command
+func (c *mongoDBClient) serverStatus(ctx context.Context) (bson.M, error) {
+	return c.runCommand(ctx, "admin", bson.D{{Key: "serverStatus", Value: 1}})
+}
+
+// dbStats returns the output of the dbStats command of the given database
+func (c *mongoDBClient) dbStats(ctx context.Context, database string) (bson.M, error) {
+	return c.runCommand(ctx, database, bson.D{{Key: "dbStats", Value: 1}})
+}
+
+func (c *mongoDBClient) runCommand(ctx context.Context, database string, command bson.D) (bson.M, error) {
+	var result bson.M
+	if err := c.client.Database(database).RunCommand(ctx, command).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *mongoDBClient) disconnect(ctx context.Context) error {
+	return c.client.Disconnect(ctx)
+}
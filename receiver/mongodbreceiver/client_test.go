@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestClientOptions(t *testing.T) {
+	opts, err := clientOptions(&Config{
+		Hosts:    []confignet.TCPAddr{{Endpoint: "localhost:27017"}},
+		Username: "otel",
+		Password: "secret",
+		TLS:      configtls.TLSClientSetting{Insecure: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:27017"}, opts.Hosts)
+	require.NotNil(t, opts.Direct)
+	assert.True(t, *opts.Direct)
+	assert.Nil(t, opts.ReplicaSet)
+	require.NotNil(t, opts.Auth)
+	assert.Equal(t, "otel", opts.Auth.Username)
+	assert.Equal(t, "secret", opts.Auth.Password)
+	assert.Nil(t, opts.TLSConfig)
+}
+
+func TestClientOptionsReplicaSet(t *testing.T) {
+	opts, err := clientOptions(&Config{
+		Hosts: []confignet.TCPAddr{
+			{Endpoint: "mongodb-0:27017"},
+			{Endpoint: "mongodb-1:27017"},
+		},
+		ReplicaSet: "rs0",
+		TLS:        configtls.TLSClientSetting{InsecureSkipVerify: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mongodb-0:27017", "mongodb-1:27017"}, opts.Hosts)
+	require.NotNil(t, opts.ReplicaSet)
+	assert.Equal(t, "rs0", *opts.ReplicaSet)
+	assert.Nil(t, opts.Direct)
+	assert.Nil(t, opts.Auth)
+	require.NotNil(t, opts.TLSConfig)
+	assert.True(t, opts.TLSConfig.InsecureSkipVerify)
+}
+
+func TestClientOptionsInvalidTLS(t *testing.T) {
+	_, err := clientOptions(&Config{
+		Hosts: []confignet.TCPAddr{{Endpoint: "localhost:27017"}},
+		TLS:   configtls.TLSClientSetting{TLSSetting: configtls.TLSSetting{CAFile: "/nonexistent/ca.pem"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := newMongoDBClient(context.Background(), &Config{
+		Hosts: []confignet.TCPAddr{{Endpoint: "localhost:27017"}},
+		TLS:   configtls.TLSClientSetting{Insecure: true},
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.disconnect(context.Background()))
+}
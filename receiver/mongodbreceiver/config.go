@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+// Config defines the configuration of the MongoDB receiver.
+type Config struct {
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
+
+	// Hosts are the addresses of the MongoDB servers, e.g. the members of a replica set.
+	Hosts []confignet.TCPAddr `mapstructure:"hosts"`
+	// ReplicaSet is the name of the replica set of the hosts, if any.
+	ReplicaSet string `mapstructure:"replica_set"`
+
+	// Username used to authenticate, using SCRAM, when set.
+	Username string `mapstructure:"username"`
+	// Password used to authenticate.
+	Password string `mapstructure:"password"`
+
+	// TLS settings of the connections. TLS is disabled by default, and enabled by setting insecure to false.
+	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+}
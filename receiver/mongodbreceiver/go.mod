@@ -0,0 +1,13 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mongodbreceiver
+
+go 1.14
+
+require (
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.6.1
+	go.mongodb.org/mongo-driver v1.4.4
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/common => ../../internal/common
@@ -0,0 +1,346 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Type is the component type name.
+const Type configmodels.Type = "mongodbreceiver"
+
+type metricIntf interface {
+	Name() string
+	New() pdata.Metric
+}
+
+// Intentionally not exposing this so that it is opaque and can change freely.
+type metricImpl struct {
+	name    string
+	newFunc func() pdata.Metric
+}
+
+func (m *metricImpl) Name() string {
+	return m.name
+}
+
+func (m *metricImpl) New() pdata.Metric {
+	return m.newFunc()
+}
+
+type metricStruct struct {
+	MongodbCacheUsage         metricIntf
+	MongodbCollections        metricIntf
+	MongodbConnections        metricIntf
+	MongodbDataSize           metricIntf
+	MongodbDocumentOperations metricIntf
+	MongodbGlobalLockTime     metricIntf
+	MongodbIndexSize          metricIntf
+	MongodbIndexes            metricIntf
+	MongodbMemoryUsage        metricIntf
+	MongodbObjects            metricIntf
+	MongodbOperations         metricIntf
+	MongodbStorageSize        metricIntf
+}
+
+// Names returns a list of all the metric name strings.
+func (m *metricStruct) Names() []string {
+	return []string{
+		"mongodb.cache.usage",
+		"mongodb.collections",
+		"mongodb.connections",
+		"mongodb.data.size",
+		"mongodb.document.operations",
+		"mongodb.global_lock.time",
+		"mongodb.index.size",
+		"mongodb.indexes",
+		"mongodb.memory.usage",
+		"mongodb.objects",
+		"mongodb.operations",
+		"mongodb.storage.size",
+	}
+}
+
+var metricsByName = map[string]metricIntf{
+	"mongodb.cache.usage":         Metrics.MongodbCacheUsage,
+	"mongodb.collections":         Metrics.MongodbCollections,
+	"mongodb.connections":         Metrics.MongodbConnections,
+	"mongodb.data.size":           Metrics.MongodbDataSize,
+	"mongodb.document.operations": Metrics.MongodbDocumentOperations,
+	"mongodb.global_lock.time":    Metrics.MongodbGlobalLockTime,
+	"mongodb.index.size":          Metrics.MongodbIndexSize,
+	"mongodb.indexes":             Metrics.MongodbIndexes,
+	"mongodb.memory.usage":        Metrics.MongodbMemoryUsage,
+	"mongodb.objects":             Metrics.MongodbObjects,
+	"mongodb.operations":          Metrics.MongodbOperations,
+	"mongodb.storage.size":        Metrics.MongodbStorageSize,
+}
+
+func (m *metricStruct) ByName(n string) metricIntf {
+	return metricsByName[n]
+}
+
+func (m *metricStruct) FactoriesByName() map[string]func() pdata.Metric {
+	return map[string]func() pdata.Metric{
+		Metrics.MongodbCacheUsage.Name():         Metrics.MongodbCacheUsage.New,
+		Metrics.MongodbCollections.Name():        Metrics.MongodbCollections.New,
+		Metrics.MongodbConnections.Name():        Metrics.MongodbConnections.New,
+		Metrics.MongodbDataSize.Name():           Metrics.MongodbDataSize.New,
+		Metrics.MongodbDocumentOperations.Name(): Metrics.MongodbDocumentOperations.New,
+		Metrics.MongodbGlobalLockTime.Name():     Metrics.MongodbGlobalLockTime.New,
+		Metrics.MongodbIndexSize.Name():          Metrics.MongodbIndexSize.New,
+		Metrics.MongodbIndexes.Name():            Metrics.MongodbIndexes.New,
+		Metrics.MongodbMemoryUsage.Name():        Metrics.MongodbMemoryUsage.New,
+		Metrics.MongodbObjects.Name():            Metrics.MongodbObjects.New,
+		Metrics.MongodbOperations.Name():         Metrics.MongodbOperations.New,
+		Metrics.MongodbStorageSize.Name():        Metrics.MongodbStorageSize.New,
+	}
+}
+
+// Metrics contains a set of methods for each metric that help with
+// manipulating those metrics.
+var Metrics = &metricStruct{
+	&metricImpl{
+		"mongodb.cache.usage",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.cache.usage")
+			metric.SetDescription("The size of the data currently in the WiredTiger cache")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.collections",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.collections")
+			metric.SetDescription("The number of collections of the database")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.connections",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.connections")
+			metric.SetDescription("The number of connections, by status")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.data.size",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.data.size")
+			metric.SetDescription("The size of the uncompressed data of the database")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.document.operations",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.document.operations")
+			metric.SetDescription("The number of document operations since the start of the server, by operation")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.global_lock.time",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.global_lock.time")
+			metric.SetDescription("The time the global lock has been held since the start of the server")
+			metric.SetUnit("ms")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.index.size",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.index.size")
+			metric.SetDescription("The storage allocated to the indexes of the database")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.indexes",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.indexes")
+			metric.SetDescription("The number of indexes of the database")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.memory.usage",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.memory.usage")
+			metric.SetDescription("The memory used by the server process, by type")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.objects",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.objects")
+			metric.SetDescription("The number of objects of the database")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.operations",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.operations")
+			metric.SetDescription("The number of operations received since the start of the server, by type")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mongodb.storage.size",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mongodb.storage.size")
+			metric.SetDescription("The storage allocated to the data of the database")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+}
+
+// M contains a set of methods for each metric that help with
+// manipulating those metrics. M is an alias for Metrics
+var M = Metrics
+
+// Labels contains the possible metric labels that can be used.
+var Labels = struct {
+	// ConnectionType (The status of the connection)
+	ConnectionType string
+	// Database (The name of the database)
+	Database string
+	// DocumentOperation (The operation on the documents)
+	DocumentOperation string
+	// MemoryType (The type of the memory)
+	MemoryType string
+	// Operation (The type of the operation)
+	Operation string
+}{
+	"type",
+	"database",
+	"operation",
+	"type",
+	"operation",
+}
+
+// L contains the possible metric labels that can be used. L is an alias for
+// Labels.
+var L = Labels
+
+// LabelConnectionType are the possible values that the label "connection_type" can have.
+var LabelConnectionType = struct {
+	Active    string
+	Available string
+	Current   string
+}{
+	"active",
+	"available",
+	"current",
+}
+
+// LabelDocumentOperation are the possible values that the label "document_operation" can have.
+var LabelDocumentOperation = struct {
+	Inserted string
+	Updated  string
+	Deleted  string
+	Returned string
+}{
+	"inserted",
+	"updated",
+	"deleted",
+	"returned",
+}
+
+// LabelMemoryType are the possible values that the label "memory_type" can have.
+var LabelMemoryType = struct {
+	Resident string
+	Virtual  string
+}{
+	"resident",
+	"virtual",
+}
+
+// LabelOperation are the possible values that the label "operation" can have.
+var LabelOperation = struct {
+	Insert  string
+	Query   string
+	Update  string
+	Delete  string
+	Getmore string
+	Command string
+}{
+	"insert",
+	"query",
+	"update",
+	"delete",
+	"getmore",
+	"command",
+}
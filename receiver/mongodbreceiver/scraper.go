@@ -0,0 +1,335 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/simple"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mongodbreceiver/internal/metadata"
+)
+
+const (
+	// wiredTiger is the name of the storage engine reporting the cache statistics
+	wiredTiger = "wiredTiger"
+
+	bytesPerMebibyte = 1024 * 1024
+)
+
+var (
+	errMissingHosts    = errors.New("at least one host must be specified")
+	errMissingEndpoint = errors.New("hosts must have an endpoint")
+)
+
+// version is the major, minor and patch version of a server
+type version [3]int
+
+// parseVersion parses the given version of a server, e.g. "4.4.3" or "4.2.0-rc1"
+func parseVersion(v string) (version, error) {
+	var parsed version
+	parts := strings.SplitN(strings.SplitN(v, "-", 2)[0], ".", 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return version{}, fmt.Errorf("invalid version %q", v)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+// less returns whether the version is older than the given one
+func (v version) less(other version) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] < other[i]
+		}
+	}
+	return false
+}
+
+// statusMetric records a field of the server status as a data point of a metric
+type statusMetric struct {
+	path   []string
+	metric string
+	sum    bool
+	labels map[string]string
+	// convert converts the value of the field to the unit of the metric, when they differ
+	convert func(int64) int64
+
+	// minVersion is the first version of the server reporting the field, which is skipped on the
+	// older ones
+	minVersion version
+	// storageEngine is the storage engine reporting the field, if specific to one
+	storageEngine string
+}
+
+// statusMetrics are the fields of the server status recorded as metrics
+var statusMetrics = newStatusMetrics()
+
+func newStatusMetrics() []statusMetric {
+	sm := []statusMetric{
+		{
+			path:          []string{"wiredTiger", "cache", "bytes currently in the cache"},
+			metric:        metadata.M.MongodbCacheUsage.Name(),
+			storageEngine: wiredTiger,
+		},
+		{
+			path:    []string{"globalLock", "totalTime"},
+			metric:  metadata.M.MongodbGlobalLockTime.Name(),
+			sum:     true,
+			convert: func(us int64) int64 { return us / 1000 },
+		},
+	}
+
+	for _, c := range []struct {
+		field      string
+		label      string
+		minVersion version
+	}{
+		{"active", metadata.LabelConnectionType.Active, version{4, 0, 7}},
+		{"available", metadata.LabelConnectionType.Available, version{}},
+		{"current", metadata.LabelConnectionType.Current, version{}},
+	} {
+		sm = append(sm, statusMetric{
+			path:       []string{"connections", c.field},
+			metric:     metadata.M.MongodbConnections.Name(),
+			labels:     map[string]string{metadata.L.ConnectionType: c.label},
+			minVersion: c.minVersion,
+		})
+	}
+	for _, op := range []string{
+		metadata.LabelOperation.Insert,
+		metadata.LabelOperation.Query,
+		metadata.LabelOperation.Update,
+		metadata.LabelOperation.Delete,
+		metadata.LabelOperation.Getmore,
+		metadata.LabelOperation.Command,
+	} {
+		sm = append(sm, statusMetric{
+			path:   []string{"opcounters", op},
+			metric: metadata.M.MongodbOperations.Name(),
+			sum:    true,
+			labels: map[string]string{metadata.L.Operation: op},
+		})
+	}
+	for _, op := range []string{
+		metadata.LabelDocumentOperation.Inserted,
+		metadata.LabelDocumentOperation.Updated,
+		metadata.LabelDocumentOperation.Deleted,
+		metadata.LabelDocumentOperation.Returned,
+	} {
+		sm = append(sm, statusMetric{
+			path:   []string{"metrics", "document", op},
+			metric: metadata.M.MongodbDocumentOperations.Name(),
+			sum:    true,
+			labels: map[string]string{metadata.L.DocumentOperation: op},
+		})
+	}
+	for _, t := range []string{metadata.LabelMemoryType.Resident, metadata.LabelMemoryType.Virtual} {
+		sm = append(sm, statusMetric{
+			path:    []string{"mem", t},
+			metric:  metadata.M.MongodbMemoryUsage.Name(),
+			labels:  map[string]string{metadata.L.MemoryType: t},
+			convert: func(mib int64) int64 { return mib * bytesPerMebibyte },
+		})
+	}
+	return sm
+}
+
+// dbStatsMetrics are the fields of the database statistics recorded as metrics
+var dbStatsMetrics = []struct {
+	field  string
+	metric string
+}{
+	{"collections", metadata.M.MongodbCollections.Name()},
+	{"indexes", metadata.M.MongodbIndexes.Name()},
+	{"objects", metadata.M.MongodbObjects.Name()},
+	{"dataSize", metadata.M.MongodbDataSize.Name()},
+	{"storageSize", metadata.M.MongodbStorageSize.Name()},
+	{"indexSize", metadata.M.MongodbIndexSize.Name()},
+}
+
+type mongoDBScraper struct {
+	logger *zap.Logger
+	config *Config
+
+	newClient func(ctx context.Context, cfg *Config) (client, error)
+	client    client
+}
+
+func newMongoDBScraper(logger *zap.Logger, config *Config) (*mongoDBScraper, error) {
+	if len(config.Hosts) == 0 {
+		return nil, errMissingHosts
+	}
+	for _, h := range config.Hosts {
+		if h.Endpoint == "" {
+			return nil, errMissingEndpoint
+		}
+	}
+
+	return &mongoDBScraper{
+		logger:    logger,
+		config:    config,
+		newClient: newMongoDBClient,
+	}, nil
+}
+
+func (m *mongoDBScraper) shutdown(ctx context.Context) error {
+	if m.client == nil {
+		return nil
+	}
+	return m.client.disconnect(ctx)
+}
+
+// scrape runs serverStatus, then dbStats on each database. When one of the commands fails, the
+// metrics of the other ones are still reported.
+func (m *mongoDBScraper) scrape(ctx context.Context) (pdata.ResourceMetricsSlice, error) {
+	// Init client in scrape method in case there are transient errors in the
+	// constructor.
+	if m.client == nil {
+		var err error
+		m.client, err = m.newClient(ctx, m.config)
+		if err != nil {
+			m.client = nil
+			return pdata.ResourceMetricsSlice{}, err
+		}
+	}
+
+	metrics := simple.Metrics{
+		Metrics:                    pdata.NewMetrics(),
+		Timestamp:                  time.Now(),
+		MetricFactoriesByName:      metadata.M.FactoriesByName(),
+		InstrumentationLibraryName: "otelcol/mongodb",
+	}
+
+	var errs []error
+	status, err := m.client.serverStatus(ctx)
+	if err != nil {
+		m.logger.Error("Failed to run the MongoDB serverStatus command", zap.Error(err))
+		errs = append(errs, consumererror.NewPartialScrapeError(
+			fmt.Errorf("failed to fetch the server status: %w", err), len(statusMetrics)))
+	} else {
+		errs = append(errs, recordServerStatus(&metrics, status)...)
+	}
+
+	databases, err := m.client.listDatabaseNames(ctx)
+	if err != nil {
+		m.logger.Error("Failed to list the MongoDB databases", zap.Error(err))
+		errs = append(errs, consumererror.NewPartialScrapeError(
+			fmt.Errorf("failed to list the databases: %w", err), len(dbStatsMetrics)))
+	}
+	for _, database := range databases {
+		stats, err := m.client.dbStats(ctx, database)
+		if err != nil {
+			m.logger.Error("Failed to run the MongoDB dbStats command", zap.String("database", database), zap.Error(err))
+			errs = append(errs, consumererror.NewPartialScrapeError(
+				fmt.Errorf("failed to fetch the statistics of database %q: %w", database, err), len(dbStatsMetrics)))
+			continue
+		}
+		errs = append(errs, recordDBStats(&metrics, database, stats)...)
+	}
+
+	return metrics.Metrics.ResourceMetrics(), scraperhelper.CombineScrapeErrors(errs)
+}
+
+// recordServerStatus records the metrics of the server status. The fields which don't exist in
+// the version or storage engine of the server are skipped.
+func recordServerStatus(metrics *simple.Metrics, status bson.M) []error {
+	rawVersion, _ := status["version"].(string)
+	v, err := parseVersion(rawVersion)
+	if err != nil {
+		return []error{consumererror.NewPartialScrapeError(err, len(statusMetrics))}
+	}
+	var storageEngine string
+	if engine, ok := status["storageEngine"].(bson.M); ok {
+		storageEngine, _ = engine["name"].(string)
+	}
+
+	var errs []error
+	for _, sm := range statusMetrics {
+		if v.less(sm.minVersion) || (sm.storageEngine != "" && sm.storageEngine != storageEngine) {
+			continue
+		}
+		value, err := digInt(status, sm.path...)
+		if err != nil {
+			errs = append(errs, consumererror.NewPartialScrapeError(err, 1))
+			continue
+		}
+		if sm.convert != nil {
+			value = sm.convert(value)
+		}
+
+		if sm.sum {
+			metrics.WithLabels(sm.labels).AddSumDataPoint(sm.metric, value)
+		} else {
+			metrics.WithLabels(sm.labels).AddGaugeDataPoint(sm.metric, value)
+		}
+	}
+	return errs
+}
+
+// recordDBStats records the metrics of the statistics of the given database
+func recordDBStats(metrics *simple.Metrics, database string, stats bson.M) []error {
+	m := metrics.WithLabels(map[string]string{metadata.L.Database: database})
+
+	var errs []error
+	for _, dm := range dbStatsMetrics {
+		value, err := digInt(stats, dm.field)
+		if err != nil {
+			errs = append(errs, consumererror.NewPartialScrapeError(
+				fmt.Errorf("invalid statistics of database %q: %w", database, err), 1))
+			continue
+		}
+		m.AddGaugeDataPoint(dm.metric, value)
+	}
+	return errs
+}
+
+// digInt returns the numeric field of the given document at the given path of nested documents
+func digInt(doc bson.M, path ...string) (int64, error) {
+	for i, key := range path[:len(path)-1] {
+		nested, ok := doc[key].(bson.M)
+		if !ok {
+			return 0, fmt.Errorf("missing field %s", strings.Join(path[:i+1], "."))
+		}
+		doc = nested
+	}
+
+	field := strings.Join(path, ".")
+	switch v := doc[path[len(path)-1]].(type) {
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case nil:
+		return 0, fmt.Errorf("missing field %s", field)
+	default:
+		return 0, fmt.Errorf("invalid value %v of field %s", v, field)
+	}
+}
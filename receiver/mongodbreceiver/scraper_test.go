@@ -0,0 +1,342 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type fakeClient struct {
+	status    bson.M
+	databases []string
+	stats     map[string]bson.M
+
+	statusErr    error
+	databasesErr error
+
+	disconnected bool
+}
+
+func (c *fakeClient) listDatabaseNames(context.Context) ([]string, error) {
+	return c.databases, c.databasesErr
+}
+
+func (c *fakeClient) serverStatus(context.Context) (bson.M, error) {
+	return c.status, c.statusErr
+}
+
+func (c *fakeClient) dbStats(_ context.Context, database string) (bson.M, error) {
+	stats, ok := c.stats[database]
+	if !ok {
+		return nil, errors.New("not authorized")
+	}
+	return stats, nil
+}
+
+func (c *fakeClient) disconnect(context.Context) error {
+	c.disconnected = true
+	return nil
+}
+
+// testStatus returns the server status of a server of the given version and storage engine, the
+// numbers being in the types returned by the server
+func testStatus(version string, storageEngine string) bson.M {
+	return bson.M{
+		"version":       version,
+		"storageEngine": bson.M{"name": storageEngine},
+		"wiredTiger": bson.M{
+			"cache": bson.M{"bytes currently in the cache": int64(8388608)},
+		},
+		"globalLock": bson.M{"totalTime": int64(123456789)},
+		"connections": bson.M{
+			"current":   int32(12),
+			"available": int32(838848),
+			"active":    int32(3),
+		},
+		"opcounters": bson.M{
+			"insert":  int64(100),
+			"query":   int64(200),
+			"update":  int64(30),
+			"delete":  int64(4),
+			"getmore": int64(5),
+			"command": int64(600),
+		},
+		"metrics": bson.M{
+			"document": bson.M{
+				"inserted": int64(100),
+				"updated":  int64(25),
+				"deleted":  int64(4),
+				"returned": int64(1000),
+			},
+		},
+		"mem": bson.M{"resident": int32(80), "virtual": int32(1500)},
+	}
+}
+
+var testDBStats = bson.M{
+	"db":          "app",
+	"collections": int32(4),
+	"indexes":     int32(7),
+	"objects":     int64(1000),
+	"dataSize":    float64(524288),
+	"storageSize": float64(1048576),
+	"indexSize":   float64(131072),
+}
+
+func newTestScraper(t *testing.T, fake *fakeClient) *mongoDBScraper {
+	s, err := newMongoDBScraper(zap.NewNop(), &Config{
+		Hosts: []confignet.TCPAddr{{Endpoint: "localhost:27017"}},
+	})
+	require.NoError(t, err)
+	s.newClient = func(context.Context, *Config) (client, error) {
+		return fake, nil
+	}
+	return s
+}
+
+// dataPoint is a data point of an int gauge or sum, identified by its metric and labels
+type dataPoint struct {
+	metric string
+	labels string
+}
+
+func intDataPoints(t *testing.T, rms pdata.ResourceMetricsSlice) map[dataPoint]int64 {
+	require.Equal(t, 1, rms.Len())
+	ilms := rms.At(0).InstrumentationLibraryMetrics()
+	require.Equal(t, 1, ilms.Len())
+	assert.Equal(t, "otelcol/mongodb", ilms.At(0).InstrumentationLibrary().Name())
+
+	points := map[dataPoint]int64{}
+	metrics := ilms.At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		metric := metrics.At(i)
+		var dps pdata.IntDataPointSlice
+		switch metric.DataType() {
+		case pdata.MetricDataTypeIntGauge:
+			dps = metric.IntGauge().DataPoints()
+		case pdata.MetricDataTypeIntSum:
+			sum := metric.IntSum()
+			assert.True(t, sum.IsMonotonic(), metric.Name())
+			assert.Equal(t, pdata.AggregationTemporalityCumulative, sum.AggregationTemporality(), metric.Name())
+			dps = sum.DataPoints()
+		default:
+			t.Fatalf("unexpected data type %v of %s", metric.DataType(), metric.Name())
+		}
+
+		for j := 0; j < dps.Len(); j++ {
+			labels := ""
+			dps.At(j).LabelsMap().ForEach(func(k string, v string) {
+				labels += k + "=" + v
+			})
+			points[dataPoint{metric.Name(), labels}] = dps.At(j).Value()
+		}
+	}
+	return points
+}
+
+var statusDataPoints = map[dataPoint]int64{
+	{"mongodb.cache.usage", ""}:                           8388608,
+	{"mongodb.global_lock.time", ""}:                      123456,
+	{"mongodb.connections", "type=active"}:                3,
+	{"mongodb.connections", "type=available"}:             838848,
+	{"mongodb.connections", "type=current"}:               12,
+	{"mongodb.operations", "operation=insert"}:            100,
+	{"mongodb.operations", "operation=query"}:             200,
+	{"mongodb.operations", "operation=update"}:            30,
+	{"mongodb.operations", "operation=delete"}:            4,
+	{"mongodb.operations", "operation=getmore"}:           5,
+	{"mongodb.operations", "operation=command"}:           600,
+	{"mongodb.document.operations", "operation=inserted"}: 100,
+	{"mongodb.document.operations", "operation=updated"}:  25,
+	{"mongodb.document.operations", "operation=deleted"}:  4,
+	{"mongodb.document.operations", "operation=returned"}: 1000,
+	{"mongodb.memory.usage", "type=resident"}:             83886080,
+	{"mongodb.memory.usage", "type=virtual"}:              1572864000,
+}
+
+var dbStatsDataPoints = map[dataPoint]int64{
+	{"mongodb.collections", "database=app"}:  4,
+	{"mongodb.indexes", "database=app"}:      7,
+	{"mongodb.objects", "database=app"}:      1000,
+	{"mongodb.data.size", "database=app"}:    524288,
+	{"mongodb.storage.size", "database=app"}: 1048576,
+	{"mongodb.index.size", "database=app"}:   131072,
+}
+
+func merge(dps ...map[dataPoint]int64) map[dataPoint]int64 {
+	merged := map[dataPoint]int64{}
+	for _, m := range dps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func TestScrape(t *testing.T) {
+	fake := &fakeClient{
+		status:    testStatus("4.4.3", "wiredTiger"),
+		databases: []string{"app"},
+		stats:     map[string]bson.M{"app": testDBStats},
+	}
+	s := newTestScraper(t, fake)
+
+	rms, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, merge(statusDataPoints, dbStatsDataPoints), intDataPoints(t, rms))
+
+	require.NoError(t, s.shutdown(context.Background()))
+	assert.True(t, fake.disconnected)
+}
+
+func TestScrapeOlderVersion(t *testing.T) {
+	status := testStatus("3.6.21", "mmapv1")
+	delete(status, "wiredTiger")
+	delete(status["connections"].(bson.M), "active")
+	fake := &fakeClient{status: status}
+	s := newTestScraper(t, fake)
+
+	rms, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	// the fields which don't exist in the version and storage engine of the server are skipped
+	expected := merge(statusDataPoints)
+	delete(expected, dataPoint{"mongodb.cache.usage", ""})
+	delete(expected, dataPoint{"mongodb.connections", "type=active"})
+	assert.Equal(t, expected, intDataPoints(t, rms))
+}
+
+func TestScrapeMissingField(t *testing.T) {
+	status := testStatus("4.2.0-rc1", "wiredTiger")
+	delete(status["connections"].(bson.M), "active")
+	delete(status, "globalLock")
+	fake := &fakeClient{status: status}
+	s := newTestScraper(t, fake)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "missing field connections.active")
+	assert.Contains(t, err.Error(), "missing field globalLock")
+
+	expected := merge(statusDataPoints)
+	delete(expected, dataPoint{"mongodb.global_lock.time", ""})
+	delete(expected, dataPoint{"mongodb.connections", "type=active"})
+	assert.Equal(t, expected, intDataPoints(t, rms))
+}
+
+func TestScrapeInvalidVersion(t *testing.T) {
+	fake := &fakeClient{
+		status:    bson.M{"version": "unknown"},
+		databases: []string{"app"},
+		stats:     map[string]bson.M{"app": testDBStats},
+	}
+	s := newTestScraper(t, fake)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), `invalid version "unknown"`)
+	assert.Equal(t, dbStatsDataPoints, intDataPoints(t, rms))
+}
+
+func TestScrapeServerStatusError(t *testing.T) {
+	fake := &fakeClient{
+		statusErr: errors.New("not authorized"),
+		databases: []string{"app"},
+		stats:     map[string]bson.M{"app": testDBStats},
+	}
+	s := newTestScraper(t, fake)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "failed to fetch the server status: not authorized")
+
+	// the metrics of the databases are still reported
+	assert.Equal(t, dbStatsDataPoints, intDataPoints(t, rms))
+}
+
+func TestScrapeDBStatsError(t *testing.T) {
+	fake := &fakeClient{
+		status:    testStatus("4.4.3", "wiredTiger"),
+		databases: []string{"admin", "app"},
+		stats:     map[string]bson.M{"app": testDBStats},
+	}
+	s := newTestScraper(t, fake)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), `failed to fetch the statistics of database "admin": not authorized`)
+
+	// the metrics of the other databases are still reported
+	assert.Equal(t, merge(statusDataPoints, dbStatsDataPoints), intDataPoints(t, rms))
+}
+
+func TestScrapeListDatabasesError(t *testing.T) {
+	fake := &fakeClient{
+		status:       testStatus("4.4.3", "wiredTiger"),
+		databasesErr: errors.New("not authorized"),
+	}
+	s := newTestScraper(t, fake)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "failed to list the databases: not authorized")
+	assert.Equal(t, statusDataPoints, intDataPoints(t, rms))
+}
+
+func TestScrapeClientError(t *testing.T) {
+	s := newTestScraper(t, nil)
+	s.newClient = func(context.Context, *Config) (client, error) {
+		return nil, errors.New("failed to load TLS config")
+	}
+
+	_, err := s.scrape(context.Background())
+	assert.EqualError(t, err, "failed to load TLS config")
+	assert.Nil(t, s.client)
+	require.NoError(t, s.shutdown(context.Background()))
+}
+
+func TestParseVersion(t *testing.T) {
+	for raw, expected := range map[string]version{
+		"4.4.3":     {4, 4, 3},
+		"4.2.0-rc1": {4, 2, 0},
+		"5.0":       {5, 0, 0},
+	} {
+		v, err := parseVersion(raw)
+		require.NoError(t, err)
+		assert.Equal(t, expected, v, raw)
+	}
+
+	_, err := parseVersion("")
+	assert.EqualError(t, err, `invalid version ""`)
+
+	assert.True(t, version{4, 0, 6}.less(version{4, 0, 7}))
+	assert.False(t, version{4, 0, 7}.less(version{4, 0, 7}))
+	assert.False(t, version{4, 4, 0}.less(version{4, 0, 7}))
+}
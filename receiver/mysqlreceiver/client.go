@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// globalVariables are the global variables reported by the receiver
+var globalVariables = []string{"innodb_buffer_pool_size", "max_connections"}
+
+// client queries the global status and variables of the MySQL server
+type client interface {
+	getGlobalStatus(ctx context.Context) (map[string]string, error)
+	getGlobalVariables(ctx context.Context) (map[string]string, error)
+	close() error
+}
+
+type mySQLClient struct {
+	db *sql.DB
+}
+
+var _ client = (*mySQLClient)(nil)
+
+// newMySQLClient creates a client for the configured server. The connections are opened when the
+// server is queried.
+func newMySQLClient(cfg *Config) (client, error) {
+	driverCfg := mysql.NewConfig()
+	driverCfg.User = cfg.Username
+	driverCfg.Passwd = cfg.Password
+	driverCfg.Net = cfg.Transport
+	driverCfg.Addr = cfg.Endpoint
+	driverCfg.DBName = cfg.Database
+
+	tlsCfg, err := cfg.TLS.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		// the driver looks the TLS configurations up by name
+		tlsName := "otelcol-" + cfg.Name()
+		if err := mysql.RegisterTLSConfig(tlsName, tlsCfg); err != nil {
+			return nil, fmt.Errorf("failed to register the TLS configuration: %w", err)
+		}
+		driverCfg.TLSConfig = tlsName
+	}
+
+	connector, err := mysql.NewConnector(driverCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &mySQLClient{db: sql.OpenDB(connector)}, nil
+}
+
+// getGlobalStatus returns the global status variables by name
+func (c *mySQLClient) getGlobalStatus(ctx context.Context) (map[string]string, error) {
+	return c.queryNameValues(ctx, "SHOW GLOBAL STATUS")
+}
+
+// getGlobalVariables returns the global variables reported by the receiver by name
+func (c *mySQLClient) getGlobalVariables(ctx context.Context) (map[string]string, error) {
+	return c.queryNameValues(ctx, "SHOW GLOBAL VARIABLES WHERE Variable_name IN ('"+strings.Join(globalVariables, "', '")+"')")
+}
+
+// queryNameValues runs the given query, returning the rows of variable names and values as a map
+func (c *mySQLClient) queryNameValues(ctx context.Context, query string) (map[string]string, error) {
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var name string
+		var value sql.NullString
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		if value.Valid {
+			values[name] = value.String
+		}
+	}
+	return values, rows.Err()
+}
+
+func (c *mySQLClient) close() error {
+	return c.db.Close()
+}
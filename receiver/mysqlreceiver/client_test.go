@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+func TestClientGetGlobalStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SHOW GLOBAL STATUS").WillReturnRows(
+		sqlmock.NewRows([]string{"Variable_name", "Value"}).
+			AddRow("Connections", "17").
+			AddRow("Threads_running", "2").
+			AddRow("Rpl_status", nil))
+	mock.ExpectClose()
+
+	c := &mySQLClient{db: db}
+	status, err := c.getGlobalStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Connections": "17", "Threads_running": "2"}, status)
+
+	require.NoError(t, c.close())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientGetGlobalVariables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		"SHOW GLOBAL VARIABLES WHERE Variable_name IN ('innodb_buffer_pool_size', 'max_connections')")).
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).
+			AddRow("innodb_buffer_pool_size", "134217728").
+			AddRow("max_connections", "151"))
+
+	c := &mySQLClient{db: db}
+	variables, err := c.getGlobalVariables(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"innodb_buffer_pool_size": "134217728", "max_connections": "151"}, variables)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientQueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery("SHOW GLOBAL STATUS").WillReturnError(sql.ErrConnDone)
+
+	c := &mySQLClient{db: db}
+	_, err = c.getGlobalStatus(context.Background())
+	assert.Equal(t, sql.ErrConnDone, err)
+}
+
+func TestNewClientTLS(t *testing.T) {
+	cfg := &Config{
+		ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+			ReceiverSettings: configmodels.ReceiverSettings{NameVal: "mysql/tls"},
+		},
+		NetAddr:  confignet.NetAddr{Endpoint: "localhost:3306", Transport: "tcp"},
+		Username: "otel",
+		TLS:      configtls.TLSClientSetting{Insecure: true},
+	}
+
+	c, err := newMySQLClient(cfg)
+	require.NoError(t, err)
+	require.NoError(t, c.close())
+
+	cfg.TLS = configtls.TLSClientSetting{Insecure: false}
+	c, err = newMySQLClient(cfg)
+	require.NoError(t, err)
+	require.NoError(t, c.close())
+
+	cfg.TLS = configtls.TLSClientSetting{TLSSetting: configtls.TLSSetting{CAFile: "/nonexistent/ca.pem"}}
+	_, err = newMySQLClient(cfg)
+	assert.Error(t, err)
+}
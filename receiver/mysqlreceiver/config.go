@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+// Config defines the configuration of the MySQL receiver.
+type Config struct {
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
+	// Endpoint and Transport of the MySQL server, the transport being either "tcp" or "unix".
+	confignet.NetAddr `mapstructure:",squash"`
+
+	// Username used to connect to the server.
+	Username string `mapstructure:"username"`
+	// Password used to connect to the server.
+	Password string `mapstructure:"password"`
+	// Database to connect to, reported as a resource attribute. Optional, as the global status
+	// and variables don't depend on the database.
+	Database string `mapstructure:"database"`
+
+	// TLS settings of the connection. TLS is disabled by default, and enabled by setting insecure to false.
+	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, 2, len(config.Receivers))
+
+	defaultConfig := factory.CreateDefaultConfig().(*Config)
+	defaultConfig.Username = "otel"
+	assert.Equal(t, defaultConfig, config.Receivers["mysql"])
+
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "mysql",
+					NameVal: "mysql/all_settings",
+				},
+				CollectionInterval: 30 * time.Second,
+			},
+			NetAddr: confignet.NetAddr{
+				Endpoint:  "mysql.example.com:3307",
+				Transport: "tcp",
+			},
+			Username: "otel",
+			Password: "secret",
+			Database: "app",
+			TLS: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{
+					CAFile: "/etc/ssl/mysql/ca.pem",
+				},
+				Insecure: false,
+			},
+		},
+		config.Receivers["mysql/all_settings"])
+}
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package mysqlreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testing/container"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver/internal/metadata"
+)
+
+func TestIntegration(t *testing.T) {
+	cs := container.New(t)
+	c := cs.StartImageWithEnv("mysql:8.0", []string{"MYSQL_ROOT_PASSWORD=otel"}, container.WithPortReady(3306))
+
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = c.AddrForPort(3306)
+	cfg.Username = "root"
+	cfg.Password = "otel"
+
+	consumer := new(consumertest.MetricsSink)
+	params := component.ReceiverCreateParams{Logger: zaptest.NewLogger(t)}
+
+	rcvr, err := f.CreateMetricsReceiver(context.Background(), params, cfg, consumer)
+	require.NoError(t, err, "failed creating metrics receiver")
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+
+	// the server may not accept the connections yet when its port is opened
+	require.Eventuallyf(t, func() bool {
+		return len(consumer.AllMetrics()) > 0 && consumer.AllMetrics()[len(consumer.AllMetrics())-1].MetricCount() > 0
+	}, 60*time.Second, 1*time.Second, "failed to receive metrics")
+
+	mds := consumer.AllMetrics()
+	md := mds[len(mds)-1]
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	require.Equal(t, 1, ilms.Len())
+
+	metrics := ilms.At(0).Metrics()
+	seen := map[string]bool{}
+	for i := 0; i < metrics.Len(); i++ {
+		seen[metrics.At(i).Name()] = true
+	}
+	for _, name := range metadata.Metrics.Names() {
+		assert.True(t, seen[name], "did not find metric %q", name)
+	}
+
+	assert.NoError(t, rcvr.Shutdown(context.Background()))
+}
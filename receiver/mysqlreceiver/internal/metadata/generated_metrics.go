@@ -0,0 +1,503 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Type is the component type name.
+const Type configmodels.Type = "mysqlreceiver"
+
+type metricIntf interface {
+	Name() string
+	New() pdata.Metric
+}
+
+// Intentionally not exposing this so that it is opaque and can change freely.
+type metricImpl struct {
+	name    string
+	newFunc func() pdata.Metric
+}
+
+func (m *metricImpl) Name() string {
+	return m.name
+}
+
+func (m *metricImpl) New() pdata.Metric {
+	return m.newFunc()
+}
+
+type metricStruct struct {
+	MysqlBufferPoolLimit       metricIntf
+	MysqlBufferPoolOperations  metricIntf
+	MysqlBufferPoolPageFlushes metricIntf
+	MysqlBufferPoolPages       metricIntf
+	MysqlBufferPoolUsage       metricIntf
+	MysqlCommands              metricIntf
+	MysqlConnectionErrors      metricIntf
+	MysqlConnectionMax         metricIntf
+	MysqlConnections           metricIntf
+	MysqlHandlers              metricIntf
+	MysqlRowLockTime           metricIntf
+	MysqlRowLockWaits          metricIntf
+	MysqlSorts                 metricIntf
+	MysqlThreads               metricIntf
+}
+
+// Names returns a list of all the metric name strings.
+func (m *metricStruct) Names() []string {
+	return []string{
+		"mysql.buffer_pool.limit",
+		"mysql.buffer_pool.operations",
+		"mysql.buffer_pool.page_flushes",
+		"mysql.buffer_pool.pages",
+		"mysql.buffer_pool.usage",
+		"mysql.commands",
+		"mysql.connection.errors",
+		"mysql.connection.max",
+		"mysql.connections",
+		"mysql.handlers",
+		"mysql.row_lock.time",
+		"mysql.row_lock.waits",
+		"mysql.sorts",
+		"mysql.threads",
+	}
+}
+
+var metricsByName = map[string]metricIntf{
+	"mysql.buffer_pool.limit":        Metrics.MysqlBufferPoolLimit,
+	"mysql.buffer_pool.operations":   Metrics.MysqlBufferPoolOperations,
+	"mysql.buffer_pool.page_flushes": Metrics.MysqlBufferPoolPageFlushes,
+	"mysql.buffer_pool.pages":        Metrics.MysqlBufferPoolPages,
+	"mysql.buffer_pool.usage":        Metrics.MysqlBufferPoolUsage,
+	"mysql.commands":                 Metrics.MysqlCommands,
+	"mysql.connection.errors":        Metrics.MysqlConnectionErrors,
+	"mysql.connection.max":           Metrics.MysqlConnectionMax,
+	"mysql.connections":              Metrics.MysqlConnections,
+	"mysql.handlers":                 Metrics.MysqlHandlers,
+	"mysql.row_lock.time":            Metrics.MysqlRowLockTime,
+	"mysql.row_lock.waits":           Metrics.MysqlRowLockWaits,
+	"mysql.sorts":                    Metrics.MysqlSorts,
+	"mysql.threads":                  Metrics.MysqlThreads,
+}
+
+func (m *metricStruct) ByName(n string) metricIntf {
+	return metricsByName[n]
+}
+
+func (m *metricStruct) FactoriesByName() map[string]func() pdata.Metric {
+	return map[string]func() pdata.Metric{
+		Metrics.MysqlBufferPoolLimit.Name():       Metrics.MysqlBufferPoolLimit.New,
+		Metrics.MysqlBufferPoolOperations.Name():  Metrics.MysqlBufferPoolOperations.New,
+		Metrics.MysqlBufferPoolPageFlushes.Name(): Metrics.MysqlBufferPoolPageFlushes.New,
+		Metrics.MysqlBufferPoolPages.Name():       Metrics.MysqlBufferPoolPages.New,
+		Metrics.MysqlBufferPoolUsage.Name():       Metrics.MysqlBufferPoolUsage.New,
+		Metrics.MysqlCommands.Name():              Metrics.MysqlCommands.New,
+		Metrics.MysqlConnectionErrors.Name():      Metrics.MysqlConnectionErrors.New,
+		Metrics.MysqlConnectionMax.Name():         Metrics.MysqlConnectionMax.New,
+		Metrics.MysqlConnections.Name():           Metrics.MysqlConnections.New,
+		Metrics.MysqlHandlers.Name():              Metrics.MysqlHandlers.New,
+		Metrics.MysqlRowLockTime.Name():           Metrics.MysqlRowLockTime.New,
+		Metrics.MysqlRowLockWaits.Name():          Metrics.MysqlRowLockWaits.New,
+		Metrics.MysqlSorts.Name():                 Metrics.MysqlSorts.New,
+		Metrics.MysqlThreads.Name():               Metrics.MysqlThreads.New,
+	}
+}
+
+// Metrics contains a set of methods for each metric that help with
+// manipulating those metrics.
+var Metrics = &metricStruct{
+	&metricImpl{
+		"mysql.buffer_pool.limit",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.buffer_pool.limit")
+			metric.SetDescription("The configured size of the InnoDB buffer pool")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.buffer_pool.operations",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.buffer_pool.operations")
+			metric.SetDescription("The number of operations on the InnoDB buffer pool, by operation")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.buffer_pool.page_flushes",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.buffer_pool.page_flushes")
+			metric.SetDescription("The number of requests to flush pages from the InnoDB buffer pool")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.buffer_pool.pages",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.buffer_pool.pages")
+			metric.SetDescription("The number of pages in the InnoDB buffer pool, by kind")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.buffer_pool.usage",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.buffer_pool.usage")
+			metric.SetDescription("The number of bytes of data in the InnoDB buffer pool, by status")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.commands",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.commands")
+			metric.SetDescription("The number of times each statement has been executed")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.connection.errors",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.connection.errors")
+			metric.SetDescription("The number of errors that occurred while accepting connections, by error")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.connection.max",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.connection.max")
+			metric.SetDescription("The maximum number of simultaneous client connections allowed")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.connections",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.connections")
+			metric.SetDescription("The number of connection attempts to the server, successful or not")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.handlers",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.handlers")
+			metric.SetDescription("The number of requests to the internal handlers, by kind")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.row_lock.time",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.row_lock.time")
+			metric.SetDescription("The total time spent acquiring row locks for InnoDB tables")
+			metric.SetUnit("ms")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.row_lock.waits",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.row_lock.waits")
+			metric.SetDescription("The number of times operations on InnoDB tables had to wait for a row lock")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.sorts",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.sorts")
+			metric.SetDescription("The number of sort operations, by kind")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"mysql.threads",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("mysql.threads")
+			metric.SetDescription("The current number of threads, by kind")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+}
+
+// M contains a set of methods for each metric that help with
+// manipulating those metrics. M is an alias for Metrics
+var M = Metrics
+
+// Labels contains the possible metric labels that can be used.
+var Labels = struct {
+	// BufferPoolData (The status of the buffer pool data)
+	BufferPoolData string
+	// BufferPoolOperations (The buffer pool operation)
+	BufferPoolOperations string
+	// BufferPoolPages (The kind of the buffer pool pages)
+	BufferPoolPages string
+	// Command (The statement executed)
+	Command string
+	// ConnectionError (The reason of the connection error)
+	ConnectionError string
+	// Handler (The kind of the internal handler operation)
+	Handler string
+	// Sorts (The kind of the sort operation)
+	Sorts string
+	// Threads (The kind of the threads)
+	Threads string
+}{
+	"status",
+	"operation",
+	"kind",
+	"command",
+	"error",
+	"kind",
+	"kind",
+	"kind",
+}
+
+// L contains the possible metric labels that can be used. L is an alias for
+// Labels.
+var L = Labels
+
+// LabelBufferPoolData are the possible values that the label "buffer_pool_data" can have.
+var LabelBufferPoolData = struct {
+	Clean string
+	Dirty string
+}{
+	"clean",
+	"dirty",
+}
+
+// LabelBufferPoolOperations are the possible values that the label "buffer_pool_operations" can have.
+var LabelBufferPoolOperations = struct {
+	ReadAheadRnd     string
+	ReadAhead        string
+	ReadAheadEvicted string
+	ReadRequests     string
+	Reads            string
+	WaitFree         string
+	WriteRequests    string
+}{
+	"read_ahead_rnd",
+	"read_ahead",
+	"read_ahead_evicted",
+	"read_requests",
+	"reads",
+	"wait_free",
+	"write_requests",
+}
+
+// LabelBufferPoolPages are the possible values that the label "buffer_pool_pages" can have.
+var LabelBufferPoolPages = struct {
+	Data  string
+	Dirty string
+	Free  string
+	Misc  string
+}{
+	"data",
+	"dirty",
+	"free",
+	"misc",
+}
+
+// LabelCommand are the possible values that the label "command" can have.
+var LabelCommand = struct {
+	Begin         string
+	Commit        string
+	Delete        string
+	DeleteMulti   string
+	Insert        string
+	InsertSelect  string
+	Load          string
+	Replace       string
+	ReplaceSelect string
+	Rollback      string
+	Select        string
+	Update        string
+	UpdateMulti   string
+}{
+	"begin",
+	"commit",
+	"delete",
+	"delete_multi",
+	"insert",
+	"insert_select",
+	"load",
+	"replace",
+	"replace_select",
+	"rollback",
+	"select",
+	"update",
+	"update_multi",
+}
+
+// LabelConnectionError are the possible values that the label "connection_error" can have.
+var LabelConnectionError = struct {
+	Accept         string
+	Internal       string
+	MaxConnections string
+	PeerAddress    string
+	Select         string
+	Tcpwrap        string
+}{
+	"accept",
+	"internal",
+	"max_connections",
+	"peer_address",
+	"select",
+	"tcpwrap",
+}
+
+// LabelHandler are the possible values that the label "handler" can have.
+var LabelHandler = struct {
+	Commit            string
+	Delete            string
+	Discover          string
+	ExternalLock      string
+	MrrInit           string
+	Prepare           string
+	ReadFirst         string
+	ReadKey           string
+	ReadLast          string
+	ReadNext          string
+	ReadPrev          string
+	ReadRnd           string
+	ReadRndNext       string
+	Rollback          string
+	Savepoint         string
+	SavepointRollback string
+	Update            string
+	Write             string
+}{
+	"commit",
+	"delete",
+	"discover",
+	"external_lock",
+	"mrr_init",
+	"prepare",
+	"read_first",
+	"read_key",
+	"read_last",
+	"read_next",
+	"read_prev",
+	"read_rnd",
+	"read_rnd_next",
+	"rollback",
+	"savepoint",
+	"savepoint_rollback",
+	"update",
+	"write",
+}
+
+// LabelSorts are the possible values that the label "sorts" can have.
+var LabelSorts = struct {
+	MergePasses string
+	Range       string
+	Rows        string
+	Scan        string
+}{
+	"merge_passes",
+	"range",
+	"rows",
+	"scan",
+}
+
+// LabelThreads are the possible values that the label "threads" can have.
+var LabelThreads = struct {
+	Cached    string
+	Connected string
+	Running   string
+}{
+	"cached",
+	"connected",
+	"running",
+}
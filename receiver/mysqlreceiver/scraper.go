@@ -0,0 +1,311 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/simple"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver/internal/metadata"
+)
+
+const (
+	// instanceEndpointKey is the resource attribute of the endpoint of the server
+	instanceEndpointKey = "mysql.instance.endpoint"
+	// databaseNameKey is the resource attribute of the configured database
+	databaseNameKey = "mysql.database.name"
+)
+
+var (
+	errMissingEndpoint = errors.New("endpoint must be specified")
+	errMissingUsername = errors.New("username must be specified")
+)
+
+// statusMetric records a global status variable as a data point of a metric
+type statusMetric struct {
+	status string
+	metric string
+	sum    bool
+	labels map[string]string
+}
+
+// statusMetrics are the global status variables recorded as is, in the order of the metrics
+var statusMetrics = newStatusMetrics()
+
+func newStatusMetrics() []statusMetric {
+	var sm []statusMetric
+	add := func(status string, metric string, sum bool) {
+		sm = append(sm, statusMetric{status: status, metric: metric, sum: sum})
+	}
+	addLabeled := func(statusPrefix string, metric string, sum bool, label string, values ...string) {
+		for _, value := range values {
+			sm = append(sm, statusMetric{
+				status: statusPrefix + value,
+				metric: metric,
+				sum:    sum,
+				labels: map[string]string{label: value},
+			})
+		}
+	}
+
+	addLabeled("Innodb_buffer_pool_pages_", metadata.M.MysqlBufferPoolPages.Name(), false,
+		metadata.L.BufferPoolPages,
+		metadata.LabelBufferPoolPages.Data,
+		metadata.LabelBufferPoolPages.Dirty,
+		metadata.LabelBufferPoolPages.Free,
+		metadata.LabelBufferPoolPages.Misc)
+	add("Innodb_buffer_pool_pages_flushed", metadata.M.MysqlBufferPoolPageFlushes.Name(), true)
+	addLabeled("Innodb_buffer_pool_", metadata.M.MysqlBufferPoolOperations.Name(), true,
+		metadata.L.BufferPoolOperations,
+		metadata.LabelBufferPoolOperations.ReadAheadRnd,
+		metadata.LabelBufferPoolOperations.ReadAhead,
+		metadata.LabelBufferPoolOperations.ReadAheadEvicted,
+		metadata.LabelBufferPoolOperations.ReadRequests,
+		metadata.LabelBufferPoolOperations.Reads,
+		metadata.LabelBufferPoolOperations.WaitFree,
+		metadata.LabelBufferPoolOperations.WriteRequests)
+	addLabeled("Com_", metadata.M.MysqlCommands.Name(), true,
+		metadata.L.Command,
+		metadata.LabelCommand.Begin,
+		metadata.LabelCommand.Commit,
+		metadata.LabelCommand.Delete,
+		metadata.LabelCommand.DeleteMulti,
+		metadata.LabelCommand.Insert,
+		metadata.LabelCommand.InsertSelect,
+		metadata.LabelCommand.Load,
+		metadata.LabelCommand.Replace,
+		metadata.LabelCommand.ReplaceSelect,
+		metadata.LabelCommand.Rollback,
+		metadata.LabelCommand.Select,
+		metadata.LabelCommand.Update,
+		metadata.LabelCommand.UpdateMulti)
+	addLabeled("Handler_", metadata.M.MysqlHandlers.Name(), true,
+		metadata.L.Handler,
+		metadata.LabelHandler.Commit,
+		metadata.LabelHandler.Delete,
+		metadata.LabelHandler.Discover,
+		metadata.LabelHandler.ExternalLock,
+		metadata.LabelHandler.MrrInit,
+		metadata.LabelHandler.Prepare,
+		metadata.LabelHandler.ReadFirst,
+		metadata.LabelHandler.ReadKey,
+		metadata.LabelHandler.ReadLast,
+		metadata.LabelHandler.ReadNext,
+		metadata.LabelHandler.ReadPrev,
+		metadata.LabelHandler.ReadRnd,
+		metadata.LabelHandler.ReadRndNext,
+		metadata.LabelHandler.Rollback,
+		metadata.LabelHandler.Savepoint,
+		metadata.LabelHandler.SavepointRollback,
+		metadata.LabelHandler.Update,
+		metadata.LabelHandler.Write)
+	add("Innodb_row_lock_waits", metadata.M.MysqlRowLockWaits.Name(), true)
+	add("Innodb_row_lock_time", metadata.M.MysqlRowLockTime.Name(), true)
+	addLabeled("Sort_", metadata.M.MysqlSorts.Name(), true,
+		metadata.L.Sorts,
+		metadata.LabelSorts.MergePasses,
+		metadata.LabelSorts.Range,
+		metadata.LabelSorts.Rows,
+		metadata.LabelSorts.Scan)
+	addLabeled("Threads_", metadata.M.MysqlThreads.Name(), false,
+		metadata.L.Threads,
+		metadata.LabelThreads.Cached,
+		metadata.LabelThreads.Connected,
+		metadata.LabelThreads.Running)
+	add("Connections", metadata.M.MysqlConnections.Name(), true)
+	addLabeled("Connection_errors_", metadata.M.MysqlConnectionErrors.Name(), true,
+		metadata.L.ConnectionError,
+		metadata.LabelConnectionError.Accept,
+		metadata.LabelConnectionError.Internal,
+		metadata.LabelConnectionError.MaxConnections,
+		metadata.LabelConnectionError.PeerAddress,
+		metadata.LabelConnectionError.Select,
+		metadata.LabelConnectionError.Tcpwrap)
+
+	return sm
+}
+
+type mySQLScraper struct {
+	logger *zap.Logger
+	config *Config
+
+	newClient func(cfg *Config) (client, error)
+	client    client
+
+	resourceAttributes map[string]string
+}
+
+func newMySQLScraper(logger *zap.Logger, config *Config) (*mySQLScraper, error) {
+	if config.Endpoint == "" {
+		return nil, errMissingEndpoint
+	}
+	switch config.Transport {
+	case "tcp", "unix":
+	default:
+		return nil, fmt.Errorf("invalid transport %q, must be one of %q or %q", config.Transport, "tcp", "unix")
+	}
+	if config.Username == "" {
+		return nil, errMissingUsername
+	}
+
+	resourceAttributes := map[string]string{instanceEndpointKey: config.Endpoint}
+	if config.Database != "" {
+		resourceAttributes[databaseNameKey] = config.Database
+	}
+
+	return &mySQLScraper{
+		logger:             logger,
+		config:             config,
+		newClient:          newMySQLClient,
+		resourceAttributes: resourceAttributes,
+	}, nil
+}
+
+func (m *mySQLScraper) shutdown(context.Context) error {
+	if m.client == nil {
+		return nil
+	}
+	return m.client.close()
+}
+
+// scrape queries the global status and variables of the server. When one of the queries fails,
+// e.g. for missing privileges, the metrics of the other one are still reported.
+func (m *mySQLScraper) scrape(ctx context.Context) (pdata.ResourceMetricsSlice, error) {
+	// Init client in scrape method in case there are transient errors in the
+	// constructor.
+	if m.client == nil {
+		var err error
+		m.client, err = m.newClient(m.config)
+		if err != nil {
+			m.client = nil
+			return pdata.ResourceMetricsSlice{}, err
+		}
+	}
+
+	metrics := simple.Metrics{
+		Metrics:                    pdata.NewMetrics(),
+		Timestamp:                  time.Now(),
+		MetricFactoriesByName:      metadata.M.FactoriesByName(),
+		InstrumentationLibraryName: "otelcol/mysql",
+		ResourceAttributes:         m.resourceAttributes,
+	}
+
+	var errs []error
+	status, err := m.client.getGlobalStatus(ctx)
+	if err != nil {
+		m.logger.Error("Failed to fetch the MySQL global status", zap.Error(err))
+		// the buffer pool usage is reported as two data points
+		errs = append(errs, consumererror.NewPartialScrapeError(
+			fmt.Errorf("failed to fetch the global status: %w", err), len(statusMetrics)+2))
+	} else {
+		errs = append(errs, recordStatus(&metrics, status)...)
+	}
+
+	variables, err := m.client.getGlobalVariables(ctx)
+	if err != nil {
+		m.logger.Error("Failed to fetch the MySQL global variables", zap.Error(err))
+		errs = append(errs, consumererror.NewPartialScrapeError(
+			fmt.Errorf("failed to fetch the global variables: %w", err), len(globalVariables)))
+	} else {
+		errs = append(errs, recordVariables(&metrics, variables)...)
+	}
+
+	return metrics.Metrics.ResourceMetrics(), scraperhelper.CombineScrapeErrors(errs)
+}
+
+// recordStatus records the metrics of the global status variables. The variables missing from
+// the status, depending on the version and configuration of the server, are skipped.
+func recordStatus(metrics *simple.Metrics, status map[string]string) []error {
+	var errs []error
+	for _, sm := range statusMetrics {
+		raw, ok := status[sm.status]
+		if !ok {
+			continue
+		}
+		value, err := parseInt(sm.status, raw)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if sm.sum {
+			metrics.WithLabels(sm.labels).AddSumDataPoint(sm.metric, value)
+		} else {
+			metrics.WithLabels(sm.labels).AddGaugeDataPoint(sm.metric, value)
+		}
+	}
+
+	// the bytes of data include the dirty ones
+	rawData, dataOK := status["Innodb_buffer_pool_bytes_data"]
+	rawDirty, dirtyOK := status["Innodb_buffer_pool_bytes_dirty"]
+	if !dataOK || !dirtyOK {
+		return errs
+	}
+	data, err := parseInt("Innodb_buffer_pool_bytes_data", rawData)
+	if err != nil {
+		return append(errs, err)
+	}
+	dirty, err := parseInt("Innodb_buffer_pool_bytes_dirty", rawDirty)
+	if err != nil {
+		return append(errs, err)
+	}
+	metrics.WithLabels(map[string]string{metadata.L.BufferPoolData: metadata.LabelBufferPoolData.Clean}).
+		AddGaugeDataPoint(metadata.M.MysqlBufferPoolUsage.Name(), data-dirty)
+	metrics.WithLabels(map[string]string{metadata.L.BufferPoolData: metadata.LabelBufferPoolData.Dirty}).
+		AddGaugeDataPoint(metadata.M.MysqlBufferPoolUsage.Name(), dirty)
+	return errs
+}
+
+// recordVariables records the metrics of the global variables
+func recordVariables(metrics *simple.Metrics, variables map[string]string) []error {
+	var errs []error
+	for _, v := range []struct {
+		variable string
+		metric   string
+	}{
+		{"innodb_buffer_pool_size", metadata.M.MysqlBufferPoolLimit.Name()},
+		{"max_connections", metadata.M.MysqlConnectionMax.Name()},
+	} {
+		raw, ok := variables[v.variable]
+		if !ok {
+			continue
+		}
+		value, err := parseInt(v.variable, raw)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		metrics.AddGaugeDataPoint(v.metric, value)
+	}
+	return errs
+}
+
+// parseInt parses the value of the given variable, returning a partial scrape error when invalid
+func parseInt(name string, value string) (int64, error) {
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, consumererror.NewPartialScrapeError(fmt.Errorf("invalid value %q of %s: %w", value, name, err), 1)
+	}
+	return i, nil
+}
@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type fakeClient struct {
+	status       map[string]string
+	variables    map[string]string
+	statusErr    error
+	variablesErr error
+	closed       bool
+}
+
+func (c *fakeClient) getGlobalStatus(context.Context) (map[string]string, error) {
+	return c.status, c.statusErr
+}
+
+func (c *fakeClient) getGlobalVariables(context.Context) (map[string]string, error) {
+	return c.variables, c.variablesErr
+}
+
+func (c *fakeClient) close() error {
+	c.closed = true
+	return nil
+}
+
+var testStatus = map[string]string{
+	"Innodb_buffer_pool_pages_data":     "100",
+	"Innodb_buffer_pool_pages_dirty":    "10",
+	"Innodb_buffer_pool_pages_free":     "900",
+	"Innodb_buffer_pool_pages_misc":     "2",
+	"Innodb_buffer_pool_pages_flushed":  "42",
+	"Innodb_buffer_pool_bytes_data":     "1638400",
+	"Innodb_buffer_pool_bytes_dirty":    "163840",
+	"Innodb_buffer_pool_read_requests":  "5000",
+	"Innodb_buffer_pool_reads":          "300",
+	"Com_select":                        "1234",
+	"Com_insert":                        "56",
+	"Handler_read_key":                  "789",
+	"Innodb_row_lock_waits":             "3",
+	"Innodb_row_lock_time":              "120",
+	"Sort_rows":                         "64",
+	"Threads_connected":                 "5",
+	"Threads_running":                   "2",
+	"Connections":                       "17",
+	"Connection_errors_max_connections": "1",
+	"Uptime":                            "3600",
+}
+
+var testVariables = map[string]string{
+	"innodb_buffer_pool_size": "134217728",
+	"max_connections":         "151",
+}
+
+func newTestScraper(t *testing.T, fake *fakeClient) *mySQLScraper {
+	s, err := newMySQLScraper(zap.NewNop(), &Config{
+		NetAddr:  confignet.NetAddr{Endpoint: "localhost:3306", Transport: "tcp"},
+		Username: "otel",
+		Database: "app",
+	})
+	require.NoError(t, err)
+	s.newClient = func(*Config) (client, error) {
+		return fake, nil
+	}
+	return s
+}
+
+// dataPoint is a data point of an int gauge or sum, identified by its metric and labels
+type dataPoint struct {
+	metric string
+	labels string
+}
+
+func intDataPoints(t *testing.T, rms pdata.ResourceMetricsSlice) map[dataPoint]int64 {
+	require.Equal(t, 1, rms.Len())
+	ilms := rms.At(0).InstrumentationLibraryMetrics()
+	require.Equal(t, 1, ilms.Len())
+	assert.Equal(t, "otelcol/mysql", ilms.At(0).InstrumentationLibrary().Name())
+
+	points := map[dataPoint]int64{}
+	metrics := ilms.At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		metric := metrics.At(i)
+		var dps pdata.IntDataPointSlice
+		switch metric.DataType() {
+		case pdata.MetricDataTypeIntGauge:
+			dps = metric.IntGauge().DataPoints()
+		case pdata.MetricDataTypeIntSum:
+			sum := metric.IntSum()
+			assert.True(t, sum.IsMonotonic(), metric.Name())
+			assert.Equal(t, pdata.AggregationTemporalityCumulative, sum.AggregationTemporality(), metric.Name())
+			dps = sum.DataPoints()
+		default:
+			t.Fatalf("unexpected data type %v of %s", metric.DataType(), metric.Name())
+		}
+
+		for j := 0; j < dps.Len(); j++ {
+			labels := ""
+			dps.At(j).LabelsMap().ForEach(func(k string, v string) {
+				labels += k + "=" + v
+			})
+			points[dataPoint{metric.Name(), labels}] = dps.At(j).Value()
+		}
+	}
+	return points
+}
+
+func TestScrape(t *testing.T) {
+	client := &fakeClient{status: testStatus, variables: testVariables}
+	s := newTestScraper(t, client)
+
+	rms, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"mysql.instance.endpoint": "localhost:3306",
+		"mysql.database.name":     "app",
+	}, attributes(rms.At(0).Resource().Attributes()))
+
+	assert.Equal(t, map[dataPoint]int64{
+		{"mysql.buffer_pool.pages", "kind=data"}:                    100,
+		{"mysql.buffer_pool.pages", "kind=dirty"}:                   10,
+		{"mysql.buffer_pool.pages", "kind=free"}:                    900,
+		{"mysql.buffer_pool.pages", "kind=misc"}:                    2,
+		{"mysql.buffer_pool.page_flushes", ""}:                      42,
+		{"mysql.buffer_pool.usage", "status=clean"}:                 1474560,
+		{"mysql.buffer_pool.usage", "status=dirty"}:                 163840,
+		{"mysql.buffer_pool.operations", "operation=read_requests"}: 5000,
+		{"mysql.buffer_pool.operations", "operation=reads"}:         300,
+		{"mysql.buffer_pool.limit", ""}:                             134217728,
+		{"mysql.commands", "command=select"}:                        1234,
+		{"mysql.commands", "command=insert"}:                        56,
+		{"mysql.handlers", "kind=read_key"}:                         789,
+		{"mysql.row_lock.waits", ""}:                                3,
+		{"mysql.row_lock.time", ""}:                                 120,
+		{"mysql.sorts", "kind=rows"}:                                64,
+		{"mysql.threads", "kind=connected"}:                         5,
+		{"mysql.threads", "kind=running"}:                           2,
+		{"mysql.connections", ""}:                                   17,
+		{"mysql.connection.errors", "error=max_connections"}:        1,
+		{"mysql.connection.max", ""}:                                151,
+	}, intDataPoints(t, rms))
+
+	require.NoError(t, s.shutdown(context.Background()))
+	assert.True(t, client.closed)
+}
+
+func TestScrapeStatusError(t *testing.T) {
+	client := &fakeClient{statusErr: errors.New("access denied"), variables: testVariables}
+	s := newTestScraper(t, client)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "failed to fetch the global status: access denied")
+
+	// the metrics of the global variables are still reported
+	assert.Equal(t, map[dataPoint]int64{
+		{"mysql.buffer_pool.limit", ""}: 134217728,
+		{"mysql.connection.max", ""}:    151,
+	}, intDataPoints(t, rms))
+}
+
+func TestScrapeVariablesError(t *testing.T) {
+	client := &fakeClient{
+		status:       map[string]string{"Connections": "17"},
+		variablesErr: errors.New("access denied"),
+	}
+	s := newTestScraper(t, client)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "failed to fetch the global variables: access denied")
+
+	assert.Equal(t, map[dataPoint]int64{
+		{"mysql.connections", ""}: 17,
+	}, intDataPoints(t, rms))
+}
+
+func TestScrapeInvalidValue(t *testing.T) {
+	client := &fakeClient{
+		status:    map[string]string{"Connections": "17", "Threads_running": "many"},
+		variables: map[string]string{},
+	}
+	s := newTestScraper(t, client)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), `invalid value "many" of Threads_running`)
+
+	assert.Equal(t, map[dataPoint]int64{
+		{"mysql.connections", ""}: 17,
+	}, intDataPoints(t, rms))
+}
+
+func TestScrapeClientError(t *testing.T) {
+	s := newTestScraper(t, nil)
+	s.newClient = func(*Config) (client, error) {
+		return nil, errors.New("failed to load TLS config")
+	}
+
+	_, err := s.scrape(context.Background())
+	assert.EqualError(t, err, "failed to load TLS config")
+	assert.Nil(t, s.client)
+	require.NoError(t, s.shutdown(context.Background()))
+}
+
+func attributes(attrs pdata.AttributeMap) map[string]interface{} {
+	out := map[string]interface{}{}
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		out[k] = v.StringVal()
+	})
+	return out
+}
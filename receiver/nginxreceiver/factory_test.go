@@ -21,7 +21,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 	"go.opentelemetry.io/collector/testbed/testbed"
 	"go.uber.org/zap"
@@ -54,3 +56,31 @@ func TestCreateMetricsReceiver(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, metricsReceiver)
 }
+
+func TestReceiverLifecycle(t *testing.T) {
+	nginxMock := newMockServer()
+	defer nginxMock.Close()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*config)
+	cfg.Endpoint = nginxMock.URL + "/status"
+	cfg.CollectionInterval = 10 * time.Millisecond
+
+	sink := new(consumertest.MetricsSink)
+	metricsReceiver, err := factory.CreateMetricsReceiver(
+		context.Background(),
+		component.ReceiverCreateParams{Logger: zap.NewNop()},
+		cfg,
+		sink,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, metricsReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return sink.MetricsCount() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, metricsReceiver.Shutdown(context.Background()))
+
+	rms := sink.AllMetrics()[0].ResourceMetrics()
+	require.Equal(t, 4, rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics().Len())
+}
@@ -45,11 +45,8 @@ func (m *metricImpl) New() pdata.Metric {
 
 type metricStruct struct {
 	NginxConnectionsAccepted metricIntf
-	NginxConnectionsActive   metricIntf
+	NginxConnectionsCurrent  metricIntf
 	NginxConnectionsHandled  metricIntf
-	NginxConnectionsReading  metricIntf
-	NginxConnectionsWaiting  metricIntf
-	NginxConnectionsWriting  metricIntf
 	NginxRequests            metricIntf
 }
 
@@ -57,22 +54,16 @@ type metricStruct struct {
 func (m *metricStruct) Names() []string {
 	return []string{
 		"nginx.connections_accepted",
-		"nginx.connections_active",
+		"nginx.connections_current",
 		"nginx.connections_handled",
-		"nginx.connections_reading",
-		"nginx.connections_waiting",
-		"nginx.connections_writing",
 		"nginx.requests",
 	}
 }
 
 var metricsByName = map[string]metricIntf{
 	"nginx.connections_accepted": Metrics.NginxConnectionsAccepted,
-	"nginx.connections_active":   Metrics.NginxConnectionsActive,
+	"nginx.connections_current":  Metrics.NginxConnectionsCurrent,
 	"nginx.connections_handled":  Metrics.NginxConnectionsHandled,
-	"nginx.connections_reading":  Metrics.NginxConnectionsReading,
-	"nginx.connections_waiting":  Metrics.NginxConnectionsWaiting,
-	"nginx.connections_writing":  Metrics.NginxConnectionsWriting,
 	"nginx.requests":             Metrics.NginxRequests,
 }
 
@@ -83,11 +74,8 @@ func (m *metricStruct) ByName(n string) metricIntf {
 func (m *metricStruct) FactoriesByName() map[string]func() pdata.Metric {
 	return map[string]func() pdata.Metric{
 		Metrics.NginxConnectionsAccepted.Name(): Metrics.NginxConnectionsAccepted.New,
-		Metrics.NginxConnectionsActive.Name():   Metrics.NginxConnectionsActive.New,
+		Metrics.NginxConnectionsCurrent.Name():  Metrics.NginxConnectionsCurrent.New,
 		Metrics.NginxConnectionsHandled.Name():  Metrics.NginxConnectionsHandled.New,
-		Metrics.NginxConnectionsReading.Name():  Metrics.NginxConnectionsReading.New,
-		Metrics.NginxConnectionsWaiting.Name():  Metrics.NginxConnectionsWaiting.New,
-		Metrics.NginxConnectionsWriting.Name():  Metrics.NginxConnectionsWriting.New,
 		Metrics.NginxRequests.Name():            Metrics.NginxRequests.New,
 	}
 }
@@ -110,11 +98,11 @@ var Metrics = &metricStruct{
 		},
 	},
 	&metricImpl{
-		"nginx.connections_active",
+		"nginx.connections_current",
 		func() pdata.Metric {
 			metric := pdata.NewMetric()
-			metric.SetName("nginx.connections_active")
-			metric.SetDescription("The current number of open connections")
+			metric.SetName("nginx.connections_current")
+			metric.SetDescription("The current number of nginx connections by state")
 			metric.SetUnit("connections")
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 
@@ -135,42 +123,6 @@ var Metrics = &metricStruct{
 			return metric
 		},
 	},
-	&metricImpl{
-		"nginx.connections_reading",
-		func() pdata.Metric {
-			metric := pdata.NewMetric()
-			metric.SetName("nginx.connections_reading")
-			metric.SetDescription("The current number of connections where nginx is reading the request headerhe current number of open connections")
-			metric.SetUnit("connections")
-			metric.SetDataType(pdata.MetricDataTypeIntGauge)
-
-			return metric
-		},
-	},
-	&metricImpl{
-		"nginx.connections_waiting",
-		func() pdata.Metric {
-			metric := pdata.NewMetric()
-			metric.SetName("nginx.connections_waiting")
-			metric.SetDescription("The current number of idle client connections waiting for a request.")
-			metric.SetUnit("connections")
-			metric.SetDataType(pdata.MetricDataTypeIntGauge)
-
-			return metric
-		},
-	},
-	&metricImpl{
-		"nginx.connections_writing",
-		func() pdata.Metric {
-			metric := pdata.NewMetric()
-			metric.SetName("nginx.connections_writing")
-			metric.SetDescription("The current number of connections where nginx is writing the response back to the client.")
-			metric.SetUnit("connections")
-			metric.SetDataType(pdata.MetricDataTypeIntGauge)
-
-			return metric
-		},
-	},
 	&metricImpl{
 		"nginx.requests",
 		func() pdata.Metric {
@@ -193,8 +145,25 @@ var M = Metrics
 
 // Labels contains the possible metric labels that can be used.
 var Labels = struct {
-}{}
+	// State (The state of a connection)
+	State string
+}{
+	"state",
+}
 
 // L contains the possible metric labels that can be used. L is an alias for
 // Labels.
 var L = Labels
+
+// LabelState are the possible values that the label "state" can have.
+var LabelState = struct {
+	Active  string
+	Reading string
+	Writing string
+	Waiting string
+}{
+	"active",
+	"reading",
+	"writing",
+	"waiting",
+}
@@ -73,12 +73,21 @@ func (r *nginxScraper) scrape(ctx context.Context) (pdata.ResourceMetricsSlice,
 	}
 
 	metrics.AddSumDataPoint(metadata.M.NginxRequests.Name(), stats.Requests)
-	metrics.AddGaugeDataPoint(metadata.M.NginxConnectionsActive.Name(), stats.Connections.Active)
 	metrics.AddSumDataPoint(metadata.M.NginxConnectionsAccepted.Name(), stats.Connections.Accepted)
 	metrics.AddSumDataPoint(metadata.M.NginxConnectionsHandled.Name(), stats.Connections.Handled)
-	metrics.AddGaugeDataPoint(metadata.M.NginxConnectionsReading.Name(), stats.Connections.Reading)
-	metrics.AddGaugeDataPoint(metadata.M.NginxConnectionsWriting.Name(), stats.Connections.Writing)
-	metrics.AddGaugeDataPoint(metadata.M.NginxConnectionsWaiting.Name(), stats.Connections.Waiting)
+
+	for _, conns := range []struct {
+		state string
+		value int64
+	}{
+		{metadata.LabelState.Active, stats.Connections.Active},
+		{metadata.LabelState.Reading, stats.Connections.Reading},
+		{metadata.LabelState.Writing, stats.Connections.Writing},
+		{metadata.LabelState.Waiting, stats.Connections.Waiting},
+	} {
+		metrics.WithLabels(map[string]string{metadata.L.State: conns.state}).
+			AddGaugeDataPoint(metadata.M.NginxConnectionsCurrent.Name(), conns.value)
+	}
 
 	return metrics.Metrics.ResourceMetrics(), nil
 }
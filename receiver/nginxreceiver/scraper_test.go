@@ -27,8 +27,9 @@ import (
 	"go.uber.org/zap"
 )
 
-func TestScraper(t *testing.T) {
-	nginxMock := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+// newMockServer returns a server answering with a canned nginx stub_status page on /status
+func newMockServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		if req.URL.Path == "/status" {
 			rw.WriteHeader(200)
 			_, _ = rw.Write([]byte(`Active connections: 291
@@ -40,6 +41,11 @@ Reading: 6 Writing: 179 Waiting: 106
 		}
 		rw.WriteHeader(404)
 	}))
+}
+
+func TestScraper(t *testing.T) {
+	nginxMock := newMockServer()
+	defer nginxMock.Close()
 	sc := newNginxScraper(zap.NewNop(), &config{
 		HTTPClientSettings: confighttp.HTTPClientSettings{
 			Endpoint: nginxMock.URL + "/status",
@@ -57,7 +63,7 @@ Reading: 6 Writing: 179 Waiting: 106
 	ilm := ilms.At(0)
 	ms := ilm.Metrics()
 
-	require.Equal(t, 7, ms.Len())
+	require.Equal(t, 4, ms.Len())
 
 	metricValues := make(map[string]int64, 7)
 
@@ -73,19 +79,29 @@ Reading: 6 Writing: 179 Waiting: 106
 			dps = m.IntSum().DataPoints()
 		}
 
+		if m.Name() == "nginx.connections_current" {
+			require.Equal(t, 4, dps.Len())
+			for j := 0; j < dps.Len(); j++ {
+				state, ok := dps.At(j).LabelsMap().Get("state")
+				require.True(t, ok)
+				metricValues[m.Name()+"/"+state] = dps.At(j).Value()
+			}
+			continue
+		}
+
 		require.Equal(t, 1, dps.Len())
 
 		metricValues[m.Name()] = dps.At(0).Value()
 	}
 
 	require.Equal(t, map[string]int64{
-		"nginx.connections_accepted": 16630948,
-		"nginx.connections_handled":  16630948,
-		"nginx.requests":             31070465,
-		"nginx.connections_active":   291,
-		"nginx.connections_reading":  6,
-		"nginx.connections_writing":  179,
-		"nginx.connections_waiting":  106,
+		"nginx.connections_accepted":        16630948,
+		"nginx.connections_handled":         16630948,
+		"nginx.requests":                    31070465,
+		"nginx.connections_current/active":  291,
+		"nginx.connections_current/reading": 6,
+		"nginx.connections_current/writing": 179,
+		"nginx.connections_current/waiting": 106,
 	}, metricValues)
 }
 
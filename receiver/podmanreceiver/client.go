@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podmanreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// rootfulSocket is the socket of the Podman service run by root
+	rootfulSocket = "/run/podman/podman.sock"
+
+	// minMajorVersion is the first major version of the Podman API whose container statistics are supported
+	minMajorVersion = 3
+
+	// maxErrorBodyLength is the maximum length of the body of the error responses reported in the errors
+	maxErrorBodyLength = 1024
+)
+
+// container is a running container listed by the Podman API
+type container struct {
+	ID    string   `json:"Id"`
+	Names []string `json:"Names"`
+	Image string   `json:"Image"`
+}
+
+// containerStats are the statistics of a container reported by the Podman API, the CPU percentage being
+// computed by Podman
+type containerStats struct {
+	ContainerID string   `json:"ContainerID"`
+	Name        string   `json:"Name"`
+	CPU         float64  `json:"CPU"`
+	CPUNano     uint64   `json:"CPUNano"`
+	PerCPU      []uint64 `json:"PerCPU"`
+	MemUsage    uint64   `json:"MemUsage"`
+	MemLimit    uint64   `json:"MemLimit"`
+	MemPerc     float64  `json:"MemPerc"`
+	NetInput    uint64   `json:"NetInput"`
+	NetOutput   uint64   `json:"NetOutput"`
+	BlockInput  uint64   `json:"BlockInput"`
+	BlockOutput uint64   `json:"BlockOutput"`
+}
+
+// containerStatsReport is the response of the statistics endpoint, whose error is null unless the
+// statistics couldn't be collected
+type containerStatsReport struct {
+	Error json.RawMessage  `json:"Error"`
+	Stats []containerStats `json:"Stats"`
+}
+
+// client queries the libpod endpoints of the Podman API
+type client interface {
+	listContainers(ctx context.Context) ([]container, error)
+	containerStats(ctx context.Context, id string) (*containerStats, error)
+}
+
+type podmanClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiVersion string
+}
+
+var _ client = (*podmanClient)(nil)
+
+// newPodmanClient creates a client for the configured endpoint, negotiating the version of the API with
+// the Podman service when it isn't configured.
+func newPodmanClient(ctx context.Context, cfg *Config) (client, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultEndpoint()
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	var baseURL string
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		// the host is ignored, the connections being made to the socket
+		baseURL = "http://podman"
+	case "tcp":
+		tlsCfg, err := cfg.TLS.LoadTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		baseURL = "http://" + u.Host
+		if tlsCfg != nil {
+			transport.TLSClientConfig = tlsCfg
+			baseURL = "https://" + u.Host
+		}
+	default:
+		return nil, fmt.Errorf("invalid endpoint %q, must be a unix:// or tcp:// endpoint", endpoint)
+	}
+
+	c := &podmanClient{
+		httpClient: &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		baseURL:    baseURL,
+		apiVersion: cfg.APIVersion,
+	}
+	if c.apiVersion == "" {
+		if c.apiVersion, err = c.negotiateAPIVersion(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// defaultEndpoint returns the socket of the rootful Podman service when run by root, or the one of the rootless
+// service of the user otherwise
+func defaultEndpoint() string {
+	uid := os.Geteuid()
+	if uid <= 0 {
+		return "unix://" + rootfulSocket
+	}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = filepath.Join("/run/user", strconv.Itoa(uid))
+	}
+	return "unix://" + filepath.Join(runtimeDir, "podman", "podman.sock")
+}
+
+// negotiateAPIVersion returns the version of the Podman service, which is the latest version of the API it supports
+func (c *podmanClient) negotiateAPIVersion(ctx context.Context) (string, error) {
+	var version struct {
+		Version string `json:"Version"`
+	}
+	// the unversioned endpoints are served by the latest version of the API
+	if err := c.get(ctx, c.baseURL+"/libpod/version", &version); err != nil {
+		return "", fmt.Errorf("failed to negotiate the API version: %w", err)
+	}
+	if err := validateAPIVersion(version.Version); err != nil {
+		return "", fmt.Errorf("failed to negotiate the API version: %w", err)
+	}
+	return version.Version, nil
+}
+
+// validateAPIVersion checks that the given version of the API, e.g. 3.4.0, is supported
+func validateAPIVersion(version string) error {
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return fmt.Errorf("invalid API version %q", version)
+	}
+	if major < minMajorVersion {
+		return fmt.Errorf("unsupported API version %q, must be %d.0.0 or later", version, minMajorVersion)
+	}
+	return nil
+}
+
+func (c *podmanClient) listContainers(ctx context.Context) ([]container, error) {
+	var containers []container
+	if err := c.get(ctx, c.libpodURL("/containers/json", nil), &containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+func (c *podmanClient) containerStats(ctx context.Context, id string) (*containerStats, error) {
+	query := url.Values{
+		"stream":     []string{"false"},
+		"containers": []string{id},
+	}
+	var report containerStatsReport
+	if err := c.get(ctx, c.libpodURL("/containers/stats", query), &report); err != nil {
+		return nil, err
+	}
+	if len(report.Error) > 0 && string(report.Error) != "null" {
+		return nil, fmt.Errorf("failed to collect the statistics: %s", report.Error)
+	}
+	if len(report.Stats) == 0 {
+		return nil, errors.New("no statistics reported")
+	}
+	return &report.Stats[0], nil
+}
+
+// libpodURL returns the URL of the given libpod endpoint, in the version of the API of the client
+func (c *podmanClient) libpodURL(path string, query url.Values) string {
+	u := c.baseURL + "/v" + c.apiVersion + "/libpod" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// get decodes the JSON response of the given URL, the error responses being returned as errors
+func (c *podmanClient) get(ctx context.Context, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podmanreceiver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	testContainers = `[{"Id":"a1b2c3","Names":["web"],"Image":"docker.io/library/nginx:latest"}]`
+
+	testStats = `{"Error":null,"Stats":[{"ContainerID":"a1b2c3","Name":"web","CPU":12.5,"CPUNano":1500000000,` +
+		`"PerCPU":[1000000000,500000000],"MemUsage":1048576,"MemLimit":4194304,"MemPerc":25,"NetInput":1024,` +
+		`"NetOutput":2048,"BlockInput":4096,"BlockOutput":8192}]}`
+)
+
+// newTestPodmanServer returns a handler serving the libpod API of the given version, and recording the
+// paths requested
+func newTestPodmanServer(version string, paths *[]string) http.Handler {
+	prefix := "/v" + version + "/libpod"
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/libpod/version":
+			fmt.Fprintf(w, `{"Version":%q}`, version)
+		case prefix + "/containers/json":
+			fmt.Fprint(w, testContainers)
+		case prefix + "/containers/stats":
+			if r.URL.Query().Get("containers") != "a1b2c3" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"cause":"no such container","message":"no container with name or ID found","response":404}`)
+				return
+			}
+			fmt.Fprint(w, testStats)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "not found")
+		}
+	})
+}
+
+// startUnixServer serves the handler on a unix socket in a temporary directory and returns its endpoint
+func startUnixServer(t *testing.T, handler http.Handler) string {
+	dir, err := ioutil.TempDir("", "podman")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "podman.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return "unix://" + socket
+}
+
+func TestPodmanClientUnixSocket(t *testing.T) {
+	for _, version := range []string{"3.4.4", "4.4.1"} {
+		t.Run(version, func(t *testing.T) {
+			var paths []string
+			endpoint := startUnixServer(t, newTestPodmanServer(version, &paths))
+
+			c, err := newPodmanClient(context.Background(), &Config{Endpoint: endpoint, Timeout: time.Second})
+			require.NoError(t, err)
+
+			containers, err := c.listContainers(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []container{{ID: "a1b2c3", Names: []string{"web"}, Image: "docker.io/library/nginx:latest"}}, containers)
+
+			stats, err := c.containerStats(context.Background(), "a1b2c3")
+			require.NoError(t, err)
+			assert.Equal(t, &containerStats{
+				ContainerID: "a1b2c3",
+				Name:        "web",
+				CPU:         12.5,
+				CPUNano:     1500000000,
+				PerCPU:      []uint64{1000000000, 500000000},
+				MemUsage:    1048576,
+				MemLimit:    4194304,
+				MemPerc:     25,
+				NetInput:    1024,
+				NetOutput:   2048,
+				BlockInput:  4096,
+				BlockOutput: 8192,
+			}, stats)
+
+			assert.Equal(t, []string{
+				"/libpod/version",
+				"/v" + version + "/libpod/containers/json",
+				"/v" + version + "/libpod/containers/stats?containers=a1b2c3&stream=false",
+			}, paths)
+		})
+	}
+}
+
+func TestPodmanClientTCP(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(newTestPodmanServer("4.0.0", &paths))
+	defer server.Close()
+
+	c, err := newPodmanClient(context.Background(), &Config{
+		Endpoint:   "tcp://" + strings.TrimPrefix(server.URL, "http://"),
+		APIVersion: "4.0.0",
+		Timeout:    time.Second,
+		TLS:        configtls.TLSClientSetting{Insecure: true},
+	})
+	require.NoError(t, err)
+
+	_, err = c.listContainers(context.Background())
+	require.NoError(t, err)
+	// the version is configured and not negotiated
+	assert.Equal(t, []string{"/v4.0.0/libpod/containers/json"}, paths)
+}
+
+func TestPodmanClientTLS(t *testing.T) {
+	var paths []string
+	server := httptest.NewTLSServer(newTestPodmanServer("4.0.0", &paths))
+	defer server.Close()
+
+	c, err := newPodmanClient(context.Background(), &Config{
+		Endpoint:   "tcp://" + strings.TrimPrefix(server.URL, "https://"),
+		APIVersion: "4.0.0",
+		Timeout:    time.Second,
+		TLS:        configtls.TLSClientSetting{InsecureSkipVerify: true},
+	})
+	require.NoError(t, err)
+
+	_, err = c.listContainers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/v4.0.0/libpod/containers/json"}, paths)
+}
+
+func TestPodmanClientUnsupportedVersion(t *testing.T) {
+	var paths []string
+	endpoint := startUnixServer(t, newTestPodmanServer("2.2.1", &paths))
+
+	_, err := newPodmanClient(context.Background(), &Config{Endpoint: endpoint, Timeout: time.Second})
+	assert.EqualError(t, err, `failed to negotiate the API version: unsupported API version "2.2.1", must be 3.0.0 or later`)
+}
+
+func TestPodmanClientErrors(t *testing.T) {
+	var paths []string
+	endpoint := startUnixServer(t, newTestPodmanServer("4.4.1", &paths))
+
+	c, err := newPodmanClient(context.Background(), &Config{Endpoint: endpoint, Timeout: time.Second})
+	require.NoError(t, err)
+
+	_, err = c.containerStats(context.Background(), "unknown")
+	assert.EqualError(t, err, "404 Not Found: no container with name or ID found")
+
+	c.(*podmanClient).apiVersion = "5.0.0"
+	_, err = c.listContainers(context.Background())
+	assert.EqualError(t, err, "404 Not Found: not found")
+}
+
+func TestPodmanClientStatsReportError(t *testing.T) {
+	endpoint := startUnixServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Error":{"message":"container is not running"},"Stats":null}`)
+	}))
+
+	c, err := newPodmanClient(context.Background(), &Config{Endpoint: endpoint, APIVersion: "4.0.0", Timeout: time.Second})
+	require.NoError(t, err)
+
+	_, err = c.containerStats(context.Background(), "a1b2c3")
+	assert.EqualError(t, err, `failed to collect the statistics: {"message":"container is not running"}`)
+}
+
+func TestPodmanClientInvalidEndpoint(t *testing.T) {
+	_, err := newPodmanClient(context.Background(), &Config{Endpoint: "http://localhost:8080"})
+	assert.EqualError(t, err, `invalid endpoint "http://localhost:8080", must be a unix:// or tcp:// endpoint`)
+}
+
+func TestDefaultEndpoint(t *testing.T) {
+	if os.Geteuid() == 0 {
+		assert.Equal(t, "unix:///run/podman/podman.sock", defaultEndpoint())
+		return
+	}
+
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	defer os.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+
+	os.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	assert.Equal(t, "unix:///run/user/1000/podman/podman.sock", defaultEndpoint())
+
+	os.Unsetenv("XDG_RUNTIME_DIR")
+	assert.Equal(t, fmt.Sprintf("unix:///run/user/%d/podman/podman.sock", os.Geteuid()), defaultEndpoint())
+}
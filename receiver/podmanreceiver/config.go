@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podmanreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+// Config defines the configuration of the Podman receiver.
+type Config struct {
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
+
+	// Endpoint of the Podman API, either unix:///path/to/podman.sock or tcp://host:port. Defaults to the
+	// socket of the Podman service of the user running the collector, rootless when the user isn't root.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// APIVersion is the version of the Podman API to use, e.g. 3.4.0 or 4.0.0. Negotiated with the service
+	// when not set.
+	APIVersion string `mapstructure:"api_version"`
+
+	// Timeout of the requests to the Podman API, 5s by default.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// TLS settings of the tcp endpoints. TLS is disabled by default, and enabled by setting insecure to false.
+	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+}
@@ -0,0 +1,258 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Type is the component type name.
+const Type configmodels.Type = "podmanreceiver"
+
+type metricIntf interface {
+	Name() string
+	New() pdata.Metric
+}
+
+// Intentionally not exposing this so that it is opaque and can change freely.
+type metricImpl struct {
+	name    string
+	newFunc func() pdata.Metric
+}
+
+func (m *metricImpl) Name() string {
+	return m.name
+}
+
+func (m *metricImpl) New() pdata.Metric {
+	return m.newFunc()
+}
+
+type metricStruct struct {
+	ContainerBlockioIoServiceBytesRecursiveRead  metricIntf
+	ContainerBlockioIoServiceBytesRecursiveWrite metricIntf
+	ContainerCPUPercent                          metricIntf
+	ContainerCPUUsagePercpu                      metricIntf
+	ContainerCPUUsageTotal                       metricIntf
+	ContainerMemoryPercent                       metricIntf
+	ContainerMemoryUsageLimit                    metricIntf
+	ContainerMemoryUsageTotal                    metricIntf
+	ContainerNetworkIoUsageRxBytes               metricIntf
+	ContainerNetworkIoUsageTxBytes               metricIntf
+}
+
+// Names returns a list of all the metric name strings.
+func (m *metricStruct) Names() []string {
+	return []string{
+		"container.blockio.io_service_bytes_recursive.read",
+		"container.blockio.io_service_bytes_recursive.write",
+		"container.cpu.percent",
+		"container.cpu.usage.percpu",
+		"container.cpu.usage.total",
+		"container.memory.percent",
+		"container.memory.usage.limit",
+		"container.memory.usage.total",
+		"container.network.io.usage.rx_bytes",
+		"container.network.io.usage.tx_bytes",
+	}
+}
+
+var metricsByName = map[string]metricIntf{
+	"container.blockio.io_service_bytes_recursive.read":  Metrics.ContainerBlockioIoServiceBytesRecursiveRead,
+	"container.blockio.io_service_bytes_recursive.write": Metrics.ContainerBlockioIoServiceBytesRecursiveWrite,
+	"container.cpu.percent":                              Metrics.ContainerCPUPercent,
+	"container.cpu.usage.percpu":                         Metrics.ContainerCPUUsagePercpu,
+	"container.cpu.usage.total":                          Metrics.ContainerCPUUsageTotal,
+	"container.memory.percent":                           Metrics.ContainerMemoryPercent,
+	"container.memory.usage.limit":                       Metrics.ContainerMemoryUsageLimit,
+	"container.memory.usage.total":                       Metrics.ContainerMemoryUsageTotal,
+	"container.network.io.usage.rx_bytes":                Metrics.ContainerNetworkIoUsageRxBytes,
+	"container.network.io.usage.tx_bytes":                Metrics.ContainerNetworkIoUsageTxBytes,
+}
+
+func (m *metricStruct) ByName(n string) metricIntf {
+	return metricsByName[n]
+}
+
+func (m *metricStruct) FactoriesByName() map[string]func() pdata.Metric {
+	return map[string]func() pdata.Metric{
+		Metrics.ContainerBlockioIoServiceBytesRecursiveRead.Name():  Metrics.ContainerBlockioIoServiceBytesRecursiveRead.New,
+		Metrics.ContainerBlockioIoServiceBytesRecursiveWrite.Name(): Metrics.ContainerBlockioIoServiceBytesRecursiveWrite.New,
+		Metrics.ContainerCPUPercent.Name():                          Metrics.ContainerCPUPercent.New,
+		Metrics.ContainerCPUUsagePercpu.Name():                      Metrics.ContainerCPUUsagePercpu.New,
+		Metrics.ContainerCPUUsageTotal.Name():                       Metrics.ContainerCPUUsageTotal.New,
+		Metrics.ContainerMemoryPercent.Name():                       Metrics.ContainerMemoryPercent.New,
+		Metrics.ContainerMemoryUsageLimit.Name():                    Metrics.ContainerMemoryUsageLimit.New,
+		Metrics.ContainerMemoryUsageTotal.Name():                    Metrics.ContainerMemoryUsageTotal.New,
+		Metrics.ContainerNetworkIoUsageRxBytes.Name():               Metrics.ContainerNetworkIoUsageRxBytes.New,
+		Metrics.ContainerNetworkIoUsageTxBytes.Name():               Metrics.ContainerNetworkIoUsageTxBytes.New,
+	}
+}
+
+// Metrics contains a set of methods for each metric that help with
+// manipulating those metrics.
+var Metrics = &metricStruct{
+	&metricImpl{
+		"container.blockio.io_service_bytes_recursive.read",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.blockio.io_service_bytes_recursive.read")
+			metric.SetDescription("The bytes read by the container from the block devices")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.blockio.io_service_bytes_recursive.write",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.blockio.io_service_bytes_recursive.write")
+			metric.SetDescription("The bytes written by the container to the block devices")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.cpu.percent",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.cpu.percent")
+			metric.SetDescription("The percentage of CPU used by the container since the previous collection")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.cpu.usage.percpu",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.cpu.usage.percpu")
+			metric.SetDescription("The CPU time consumed by the container, by core")
+			metric.SetUnit("ns")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.cpu.usage.total",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.cpu.usage.total")
+			metric.SetDescription("The total CPU time consumed by the container")
+			metric.SetUnit("ns")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.memory.percent",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.memory.percent")
+			metric.SetDescription("The percentage of the memory limit used by the container")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.memory.usage.limit",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.memory.usage.limit")
+			metric.SetDescription("The memory limit of the container")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.memory.usage.total",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.memory.usage.total")
+			metric.SetDescription("The memory used by the container")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.network.io.usage.rx_bytes",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.network.io.usage.rx_bytes")
+			metric.SetDescription("The bytes received by the container")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"container.network.io.usage.tx_bytes",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("container.network.io.usage.tx_bytes")
+			metric.SetDescription("The bytes sent by the container")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+}
+
+// M contains a set of methods for each metric that help with
+// manipulating those metrics. M is an alias for Metrics
+var M = Metrics
+
+// Labels contains the possible metric labels that can be used.
+var Labels = struct {
+	// Core (The CPU core number)
+	Core string
+}{
+	"core",
+}
+
+// L contains the possible metric labels that can be used. L is an alias for
+// Labels.
+var L = Labels
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podmanreceiver
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/simple"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/podmanreceiver/internal/metadata"
+)
+
+// metricsPerContainer is the number of metrics recorded for each container, reported as failed when
+// the statistics of the container can't be fetched
+const metricsPerContainer = 10
+
+type podmanScraper struct {
+	logger *zap.Logger
+	config *Config
+
+	newClient func(ctx context.Context, cfg *Config) (client, error)
+	client    client
+}
+
+func newPodmanScraper(logger *zap.Logger, config *Config) (*podmanScraper, error) {
+	if config.Endpoint != "" {
+		u, err := url.Parse(config.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", config.Endpoint, err)
+		}
+		if u.Scheme != "unix" && u.Scheme != "tcp" {
+			return nil, fmt.Errorf("invalid endpoint %q, must be a unix:// or tcp:// endpoint", config.Endpoint)
+		}
+	}
+	if config.APIVersion != "" {
+		if err := validateAPIVersion(config.APIVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	return &podmanScraper{
+		logger:    logger,
+		config:    config,
+		newClient: newPodmanClient,
+	}, nil
+}
+
+// scrape fetches the statistics of each running container, recorded as the metrics of a resource identifying
+// the container. When the statistics of a container can't be fetched, the metrics of the other ones are
+// still reported.
+func (p *podmanScraper) scrape(ctx context.Context) (pdata.ResourceMetricsSlice, error) {
+	// Init client in scrape method in case there are transient errors in the
+	// constructor.
+	if p.client == nil {
+		var err error
+		p.client, err = p.newClient(ctx, p.config)
+		if err != nil {
+			p.client = nil
+			return pdata.ResourceMetricsSlice{}, err
+		}
+	}
+
+	containers, err := p.client.listContainers(ctx)
+	if err != nil {
+		return pdata.ResourceMetricsSlice{}, fmt.Errorf("failed to list the containers: %w", err)
+	}
+
+	md := pdata.NewMetrics()
+	now := time.Now()
+	var errs []error
+	for _, c := range containers {
+		stats, err := p.client.containerStats(ctx, c.ID)
+		if err != nil {
+			p.logger.Error("Failed to fetch the statistics of the Podman container", zap.String("container_id", c.ID), zap.Error(err))
+			errs = append(errs, consumererror.NewPartialScrapeError(
+				fmt.Errorf("failed to fetch the statistics of container %q: %w", containerName(c), err), metricsPerContainer))
+			continue
+		}
+		recordContainerStats(md, now, c, stats)
+	}
+
+	return md.ResourceMetrics(), scraperhelper.CombineScrapeErrors(errs)
+}
+
+// recordContainerStats records the statistics of the given container as the metrics of a new resource
+func recordContainerStats(md pdata.Metrics, now time.Time, c container, stats *containerStats) {
+	metrics := &simple.Metrics{
+		Metrics:                    md,
+		Timestamp:                  now,
+		MetricFactoriesByName:      metadata.M.FactoriesByName(),
+		InstrumentationLibraryName: "otelcol/podman_stats",
+		ResourceAttributes: map[string]string{
+			conventions.AttributeContainerID:    c.ID,
+			conventions.AttributeContainerName:  containerName(c),
+			conventions.AttributeContainerImage: c.Image,
+		},
+	}
+
+	metrics.AddDGaugeDataPoint(metadata.M.ContainerCPUPercent.Name(), stats.CPU)
+	metrics.AddSumDataPoint(metadata.M.ContainerCPUUsageTotal.Name(), int64(stats.CPUNano))
+	for i, usage := range stats.PerCPU {
+		metrics.WithLabels(map[string]string{metadata.L.Core: fmt.Sprintf("cpu%d", i)}).
+			AddSumDataPoint(metadata.M.ContainerCPUUsagePercpu.Name(), int64(usage))
+	}
+	metrics.AddGaugeDataPoint(metadata.M.ContainerMemoryUsageTotal.Name(), int64(stats.MemUsage))
+	metrics.AddGaugeDataPoint(metadata.M.ContainerMemoryUsageLimit.Name(), int64(stats.MemLimit))
+	metrics.AddDGaugeDataPoint(metadata.M.ContainerMemoryPercent.Name(), stats.MemPerc)
+	metrics.AddSumDataPoint(metadata.M.ContainerNetworkIoUsageRxBytes.Name(), int64(stats.NetInput))
+	metrics.AddSumDataPoint(metadata.M.ContainerNetworkIoUsageTxBytes.Name(), int64(stats.NetOutput))
+	metrics.AddSumDataPoint(metadata.M.ContainerBlockioIoServiceBytesRecursiveRead.Name(), int64(stats.BlockInput))
+	metrics.AddSumDataPoint(metadata.M.ContainerBlockioIoServiceBytesRecursiveWrite.Name(), int64(stats.BlockOutput))
+}
+
+// containerName returns the name of the given container, or its ID when it has none
+func containerName(c container) string {
+	if len(c.Names) > 0 {
+		return c.Names[0]
+	}
+	return c.ID
+}
@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podmanreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+type fakeClient struct {
+	containers []container
+	stats      map[string]*containerStats
+
+	listErr error
+}
+
+func (c *fakeClient) listContainers(context.Context) ([]container, error) {
+	return c.containers, c.listErr
+}
+
+func (c *fakeClient) containerStats(_ context.Context, id string) (*containerStats, error) {
+	stats, ok := c.stats[id]
+	if !ok {
+		return nil, errors.New("no such container")
+	}
+	return stats, nil
+}
+
+func newTestScraper(t *testing.T, fake *fakeClient) *podmanScraper {
+	s, err := newPodmanScraper(zap.NewNop(), &Config{Endpoint: "unix:///run/podman/podman.sock"})
+	require.NoError(t, err)
+	s.newClient = func(context.Context, *Config) (client, error) {
+		return fake, nil
+	}
+	return s
+}
+
+// collectDataPoints returns the values of the data points of the metrics of a resource
+func collectDataPoints(t *testing.T, rm pdata.ResourceMetrics) map[string]interface{} {
+	values := map[string]interface{}{}
+	ilms := rm.InstrumentationLibraryMetrics()
+	for i := 0; i < ilms.Len(); i++ {
+		assert.Equal(t, "otelcol/podman_stats", ilms.At(i).InstrumentationLibrary().Name())
+		metrics := ilms.At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			m := metrics.At(j)
+			switch m.DataType() {
+			case pdata.MetricDataTypeIntGauge:
+				dps := m.IntGauge().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					values[key(m.Name(), dps.At(k).LabelsMap())] = dps.At(k).Value()
+				}
+			case pdata.MetricDataTypeIntSum:
+				assert.True(t, m.IntSum().IsMonotonic())
+				assert.Equal(t, pdata.AggregationTemporalityCumulative, m.IntSum().AggregationTemporality())
+				dps := m.IntSum().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					values[key(m.Name(), dps.At(k).LabelsMap())] = dps.At(k).Value()
+				}
+			case pdata.MetricDataTypeDoubleGauge:
+				dps := m.DoubleGauge().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					values[key(m.Name(), dps.At(k).LabelsMap())] = dps.At(k).Value()
+				}
+			default:
+				t.Errorf("unexpected data type %v of metric %s", m.DataType(), m.Name())
+			}
+		}
+	}
+	return values
+}
+
+func key(metric string, labels pdata.StringMap) string {
+	k := metric
+	labels.Sort().ForEach(func(name, value string) {
+		k += " " + name + "=" + value
+	})
+	return k
+}
+
+func TestScrape(t *testing.T) {
+	s := newTestScraper(t, &fakeClient{
+		containers: []container{
+			{ID: "a1b2c3", Names: []string{"web"}, Image: "docker.io/library/nginx:latest"},
+		},
+		stats: map[string]*containerStats{
+			"a1b2c3": {
+				CPU:         12.5,
+				CPUNano:     1500000000,
+				PerCPU:      []uint64{1000000000, 500000000},
+				MemUsage:    1048576,
+				MemLimit:    4194304,
+				MemPerc:     25,
+				NetInput:    1024,
+				NetOutput:   2048,
+				BlockInput:  4096,
+				BlockOutput: 8192,
+			},
+		},
+	})
+
+	rms, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, rms.Len())
+
+	attrs := rms.At(0).Resource().Attributes()
+	assert.Equal(t, 3, attrs.Len())
+	id, _ := attrs.Get(conventions.AttributeContainerID)
+	assert.Equal(t, "a1b2c3", id.StringVal())
+	name, _ := attrs.Get(conventions.AttributeContainerName)
+	assert.Equal(t, "web", name.StringVal())
+	image, _ := attrs.Get(conventions.AttributeContainerImage)
+	assert.Equal(t, "docker.io/library/nginx:latest", image.StringVal())
+
+	assert.Equal(t, map[string]interface{}{
+		"container.cpu.percent":                              12.5,
+		"container.cpu.usage.total":                          int64(1500000000),
+		"container.cpu.usage.percpu core=cpu0":               int64(1000000000),
+		"container.cpu.usage.percpu core=cpu1":               int64(500000000),
+		"container.memory.usage.total":                       int64(1048576),
+		"container.memory.usage.limit":                       int64(4194304),
+		"container.memory.percent":                           25.0,
+		"container.network.io.usage.rx_bytes":                int64(1024),
+		"container.network.io.usage.tx_bytes":                int64(2048),
+		"container.blockio.io_service_bytes_recursive.read":  int64(4096),
+		"container.blockio.io_service_bytes_recursive.write": int64(8192),
+	}, collectDataPoints(t, rms.At(0)))
+}
+
+func TestScrapeContainerError(t *testing.T) {
+	s := newTestScraper(t, &fakeClient{
+		containers: []container{
+			{ID: "a1b2c3", Names: []string{"web"}},
+			{ID: "d4e5f6", Names: []string{"db"}},
+		},
+		stats: map[string]*containerStats{
+			"d4e5f6": {CPUNano: 42},
+		},
+	})
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.EqualError(t, err, `failed to fetch the statistics of container "web": no such container`)
+	var partial consumererror.PartialScrapeError
+	require.True(t, errors.As(err, &partial))
+	assert.Equal(t, metricsPerContainer, partial.Failed)
+
+	// the metrics of the other containers are still reported
+	require.Equal(t, 1, rms.Len())
+	id, _ := rms.At(0).Resource().Attributes().Get(conventions.AttributeContainerID)
+	assert.Equal(t, "d4e5f6", id.StringVal())
+	assert.Equal(t, int64(42), collectDataPoints(t, rms.At(0))["container.cpu.usage.total"])
+}
+
+func TestScrapeListError(t *testing.T) {
+	s := newTestScraper(t, &fakeClient{listErr: errors.New("connection refused")})
+
+	_, err := s.scrape(context.Background())
+	assert.EqualError(t, err, "failed to list the containers: connection refused")
+}
+
+func TestScrapeClientError(t *testing.T) {
+	s := newTestScraper(t, &fakeClient{})
+	var attempts int
+	s.newClient = func(context.Context, *Config) (client, error) {
+		attempts++
+		return nil, errors.New("connection refused")
+	}
+
+	_, err := s.scrape(context.Background())
+	assert.EqualError(t, err, "connection refused")
+	// the client is created again on the next scrape
+	_, err = s.scrape(context.Background())
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 2, attempts)
+}
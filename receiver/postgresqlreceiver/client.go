@@ -0,0 +1,309 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresqlreceiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/postgresqlreceiver/internal/metadata"
+)
+
+// databaseStats are the statistics of a database
+type databaseStats struct {
+	database  string
+	commits   int64
+	rollbacks int64
+	size      int64
+}
+
+// connectionCount is the number of backend connections to a database in a given state
+type connectionCount struct {
+	database string
+	state    string
+	count    int64
+}
+
+// bgWriterStats are the statistics of the background writer of the server
+type bgWriterStats struct {
+	checkpointsTimed    int64
+	checkpointsReq      int64
+	buffersCheckpoint   int64
+	buffersClean        int64
+	buffersBackend      int64
+	buffersBackendFsync int64
+	buffersAlloc        int64
+	maxwrittenClean     int64
+}
+
+// tableStats are the statistics of a user table. The statistics of the indexes and TOAST data are
+// null when the table has none.
+type tableStats struct {
+	table         string
+	liveRows      int64
+	deadRows      int64
+	inserts       int64
+	updates       int64
+	deletes       int64
+	hotUpdates    int64
+	seqScans      int64
+	indexScans    sql.NullInt64
+	size          int64
+	heapBlksRead  int64
+	heapBlksHit   int64
+	idxBlksRead   sql.NullInt64
+	idxBlksHit    sql.NullInt64
+	toastBlksRead sql.NullInt64
+	toastBlksHit  sql.NullInt64
+	tidxBlksRead  sql.NullInt64
+	tidxBlksHit   sql.NullInt64
+}
+
+// client queries the statistics of the server through a connection to one of its databases. The
+// statistics of the server and of its databases can be queried from any database, while the
+// statistics of the tables are the ones of the connected database only.
+type client interface {
+	listDatabases(ctx context.Context) ([]string, error)
+	getDatabaseStats(ctx context.Context, databases []string) ([]databaseStats, error)
+	getConnectionCounts(ctx context.Context, databases []string) ([]connectionCount, error)
+	getBackgroundWriterStats(ctx context.Context) (*bgWriterStats, error)
+	getTableStats(ctx context.Context) ([]tableStats, error)
+	close() error
+}
+
+type postgreSQLClient struct {
+	db *sql.DB
+}
+
+var _ client = (*postgreSQLClient)(nil)
+
+// newPostgreSQLClient creates a client for the given database of the configured server. The
+// connections are opened when the database is queried.
+func newPostgreSQLClient(cfg *Config, database string) (client, error) {
+	dsn, err := dataSourceName(cfg, database)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &postgreSQLClient{db: sql.OpenDB(connector)}, nil
+}
+
+// dataSourceName returns the connection string of the given database of the configured server
+func dataSourceName(cfg *Config, database string) (string, error) {
+	params := map[string]string{
+		"dbname": database,
+		"user":   cfg.Username,
+	}
+	if cfg.Password != "" {
+		params["password"] = cfg.Password
+	}
+
+	switch cfg.Transport {
+	case "unix":
+		// the driver connects to the socket of the directory given as host
+		params["host"] = cfg.Endpoint
+	default:
+		host, port, err := net.SplitHostPort(cfg.Endpoint)
+		if err != nil {
+			return "", fmt.Errorf("invalid endpoint %q: %w", cfg.Endpoint, err)
+		}
+		params["host"] = host
+		params["port"] = port
+	}
+
+	switch {
+	case cfg.TLS.Insecure:
+		params["sslmode"] = "disable"
+	case cfg.TLS.InsecureSkipVerify:
+		params["sslmode"] = "require"
+	default:
+		params["sslmode"] = "verify-full"
+		if cfg.TLS.CAFile != "" {
+			params["sslrootcert"] = cfg.TLS.CAFile
+		}
+	}
+	if !cfg.TLS.Insecure {
+		if cfg.TLS.CertFile != "" {
+			params["sslcert"] = cfg.TLS.CertFile
+		}
+		if cfg.TLS.KeyFile != "" {
+			params["sslkey"] = cfg.TLS.KeyFile
+		}
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, k+"="+quoteDSNValue(params[k]))
+	}
+	return strings.Join(kvs, " "), nil
+}
+
+// quoteDSNValue quotes the given value of a connection string parameter, escaping its quotes and
+// backslashes
+func quoteDSNValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// listDatabases returns the databases accepting connections, except the templates
+func (c *postgreSQLClient) listDatabases(ctx context.Context) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, "SELECT datname FROM pg_database WHERE datallowconn AND NOT datistemplate")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, err
+		}
+		databases = append(databases, database)
+	}
+	return databases, rows.Err()
+}
+
+// getDatabaseStats returns the statistics of the given databases
+func (c *postgreSQLClient) getDatabaseStats(ctx context.Context, databases []string) ([]databaseStats, error) {
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT datname, xact_commit, xact_rollback, pg_database_size(datid) FROM pg_stat_database WHERE datname = ANY($1)",
+		pq.Array(databases))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []databaseStats
+	for rows.Next() {
+		var s databaseStats
+		if err := rows.Scan(&s.database, &s.commits, &s.rollbacks, &s.size); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// getConnectionCounts returns the number of backend connections to the given databases by state.
+// The states are the ones of the connection_state label, the unknown state standing for the
+// connections whose state isn't visible to the configured user.
+func (c *postgreSQLClient) getConnectionCounts(ctx context.Context, databases []string) ([]connectionCount, error) {
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT datname, state, count(*) FROM pg_stat_activity WHERE datname = ANY($1) GROUP BY datname, state",
+		pq.Array(databases))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// several states reported by the server may be normalized to the same one
+	indexes := make(map[[2]string]int)
+	var counts []connectionCount
+	for rows.Next() {
+		var database string
+		var state sql.NullString
+		var count int64
+		if err := rows.Scan(&database, &state, &count); err != nil {
+			return nil, err
+		}
+
+		key := [2]string{database, connectionState(state)}
+		if i, ok := indexes[key]; ok {
+			counts[i].count += count
+			continue
+		}
+		indexes[key] = len(counts)
+		counts = append(counts, connectionCount{database: key[0], state: key[1], count: count})
+	}
+	return counts, rows.Err()
+}
+
+// getBackgroundWriterStats returns the statistics of the background writer
+func (c *postgreSQLClient) getBackgroundWriterStats(ctx context.Context) (*bgWriterStats, error) {
+	row := c.db.QueryRowContext(ctx, `SELECT checkpoints_timed, checkpoints_req, buffers_checkpoint, buffers_clean,
+	buffers_backend, buffers_backend_fsync, buffers_alloc, maxwritten_clean FROM pg_stat_bgwriter`)
+
+	var s bgWriterStats
+	if err := row.Scan(&s.checkpointsTimed, &s.checkpointsReq, &s.buffersCheckpoint, &s.buffersClean,
+		&s.buffersBackend, &s.buffersBackendFsync, &s.buffersAlloc, &s.maxwrittenClean); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// getTableStats returns the statistics of the user tables of the connected database
+func (c *postgreSQLClient) getTableStats(ctx context.Context) ([]tableStats, error) {
+	rows, err := c.db.QueryContext(ctx, `SELECT t.schemaname || '.' || t.relname, t.n_live_tup, t.n_dead_tup,
+	t.n_tup_ins, t.n_tup_upd, t.n_tup_del, t.n_tup_hot_upd, t.seq_scan, t.idx_scan, pg_total_relation_size(t.relid),
+	io.heap_blks_read, io.heap_blks_hit, io.idx_blks_read, io.idx_blks_hit,
+	io.toast_blks_read, io.toast_blks_hit, io.tidx_blks_read, io.tidx_blks_hit
+	FROM pg_stat_user_tables t JOIN pg_statio_user_tables io ON t.relid = io.relid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []tableStats
+	for rows.Next() {
+		var s tableStats
+		if err := rows.Scan(&s.table, &s.liveRows, &s.deadRows,
+			&s.inserts, &s.updates, &s.deletes, &s.hotUpdates, &s.seqScans, &s.indexScans, &s.size,
+			&s.heapBlksRead, &s.heapBlksHit, &s.idxBlksRead, &s.idxBlksHit,
+			&s.toastBlksRead, &s.toastBlksHit, &s.tidxBlksRead, &s.tidxBlksHit); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+func (c *postgreSQLClient) close() error {
+	return c.db.Close()
+}
+
+// connectionStates maps the states of the backends reported by the server to the ones of the
+// connection_state label
+var connectionStates = map[string]string{
+	"active":                        metadata.LabelConnectionState.Active,
+	"idle":                          metadata.LabelConnectionState.Idle,
+	"idle in transaction":           metadata.LabelConnectionState.IdleInTransaction,
+	"idle in transaction (aborted)": metadata.LabelConnectionState.IdleInTransactionAborted,
+	"fastpath function call":        metadata.LabelConnectionState.FastpathFunctionCall,
+	"disabled":                      metadata.LabelConnectionState.Disabled,
+}
+
+// connectionState returns the connection_state label of the given state of a backend
+func connectionState(state sql.NullString) string {
+	if s, ok := connectionStates[state.String]; ok && state.Valid {
+		return s
+	}
+	return metadata.LabelConnectionState.Unknown
+}
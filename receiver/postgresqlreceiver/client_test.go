@@ -0,0 +1,211 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresqlreceiver
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestClientListDatabases(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT datname FROM pg_database WHERE datallowconn AND NOT datistemplate")).
+		WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("postgres").AddRow("app"))
+	mock.ExpectClose()
+
+	c := &postgreSQLClient{db: db}
+	databases, err := c.listDatabases(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"postgres", "app"}, databases)
+
+	require.NoError(t, c.close())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientGetDatabaseStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("FROM pg_stat_database WHERE datname = ANY").
+		WithArgs(`{"app","billing"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"datname", "xact_commit", "xact_rollback", "pg_database_size"}).
+			AddRow("app", 100, 2, 8192000).
+			AddRow("billing", 50, 1, 4096000))
+
+	c := &postgreSQLClient{db: db}
+	stats, err := c.getDatabaseStats(context.Background(), []string{"app", "billing"})
+	require.NoError(t, err)
+	assert.Equal(t, testDatabaseStats, stats)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientGetConnectionCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("FROM pg_stat_activity WHERE datname = ANY").
+		WithArgs(`{"app"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"datname", "state", "count"}).
+			AddRow("app", "active", 3).
+			AddRow("app", "idle in transaction (aborted)", 1).
+			AddRow("app", nil, 2).
+			AddRow("app", "starting", 1))
+
+	c := &postgreSQLClient{db: db}
+	counts, err := c.getConnectionCounts(context.Background(), []string{"app"})
+	require.NoError(t, err)
+	// the states without a label of their own are counted as unknown
+	assert.Equal(t, []connectionCount{
+		{database: "app", state: "active", count: 3},
+		{database: "app", state: "idle_in_transaction_aborted", count: 1},
+		{database: "app", state: "unknown", count: 3},
+	}, counts)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientGetBackgroundWriterStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("FROM pg_stat_bgwriter").
+		WillReturnRows(sqlmock.NewRows([]string{"checkpoints_timed", "checkpoints_req", "buffers_checkpoint",
+			"buffers_clean", "buffers_backend", "buffers_backend_fsync", "buffers_alloc", "maxwritten_clean"}).
+			AddRow(10, 2, 300, 40, 50, 0, 600, 1))
+
+	c := &postgreSQLClient{db: db}
+	stats, err := c.getBackgroundWriterStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testBgWriter, stats)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientGetTableStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("FROM pg_stat_user_tables t JOIN pg_statio_user_tables io").
+		WillReturnRows(sqlmock.NewRows([]string{"relname", "n_live_tup", "n_dead_tup",
+			"n_tup_ins", "n_tup_upd", "n_tup_del", "n_tup_hot_upd", "seq_scan", "idx_scan", "pg_total_relation_size",
+			"heap_blks_read", "heap_blks_hit", "idx_blks_read", "idx_blks_hit",
+			"toast_blks_read", "toast_blks_hit", "tidx_blks_read", "tidx_blks_hit"}).
+			AddRow("public.users", 1000, 10, 1200, 300, 200, 100, 7, 42, 65536, 20, 800, 5, 400, nil, nil, nil, nil))
+
+	c := &postgreSQLClient{db: db}
+	stats, err := c.getTableStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []tableStats{testTable("public.users")}, stats)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientQueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery("FROM pg_stat_bgwriter").WillReturnError(sql.ErrConnDone)
+
+	c := &postgreSQLClient{db: db}
+	_, err = c.getBackgroundWriterStats(context.Background())
+	assert.Equal(t, sql.ErrConnDone, err)
+}
+
+func TestDataSourceName(t *testing.T) {
+	tests := []struct {
+		name     string
+		netAddr  confignet.NetAddr
+		password string
+		tls      configtls.TLSClientSetting
+		dsn      string
+		err      string
+	}{
+		{
+			name:    "insecure",
+			netAddr: confignet.NetAddr{Endpoint: "localhost:5432", Transport: "tcp"},
+			tls:     configtls.TLSClientSetting{Insecure: true},
+			dsn:     "dbname='app' host='localhost' port='5432' sslmode='disable' user='otel'",
+		},
+		{
+			name:     "quoted password",
+			netAddr:  confignet.NetAddr{Endpoint: "localhost:5432", Transport: "tcp"},
+			password: `it's a \secret`,
+			tls:      configtls.TLSClientSetting{Insecure: true},
+			dsn:      `dbname='app' host='localhost' password='it\'s a \\secret' port='5432' sslmode='disable' user='otel'`,
+		},
+		{
+			name:    "unix socket",
+			netAddr: confignet.NetAddr{Endpoint: "/var/run/postgresql", Transport: "unix"},
+			tls:     configtls.TLSClientSetting{Insecure: true},
+			dsn:     "dbname='app' host='/var/run/postgresql' sslmode='disable' user='otel'",
+		},
+		{
+			name:    "skip verify",
+			netAddr: confignet.NetAddr{Endpoint: "localhost:5432", Transport: "tcp"},
+			tls:     configtls.TLSClientSetting{InsecureSkipVerify: true},
+			dsn:     "dbname='app' host='localhost' port='5432' sslmode='require' user='otel'",
+		},
+		{
+			name:    "verify",
+			netAddr: confignet.NetAddr{Endpoint: "localhost:5432", Transport: "tcp"},
+			tls: configtls.TLSClientSetting{TLSSetting: configtls.TLSSetting{
+				CAFile:   "/etc/ssl/ca.pem",
+				CertFile: "/etc/ssl/cert.pem",
+				KeyFile:  "/etc/ssl/key.pem",
+			}},
+			dsn: "dbname='app' host='localhost' port='5432' sslcert='/etc/ssl/cert.pem' sslkey='/etc/ssl/key.pem' " +
+				"sslmode='verify-full' sslrootcert='/etc/ssl/ca.pem' user='otel'",
+		},
+		{
+			name:    "invalid endpoint",
+			netAddr: confignet.NetAddr{Endpoint: "localhost", Transport: "tcp"},
+			err:     `invalid endpoint "localhost": address localhost: missing port in address`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn, err := dataSourceName(&Config{NetAddr: tt.netAddr, Username: "otel", Password: tt.password, TLS: tt.tls}, "app")
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.dsn, dsn)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	cfg := &Config{
+		NetAddr:  confignet.NetAddr{Endpoint: "localhost:5432", Transport: "tcp"},
+		Username: "otel",
+		TLS:      configtls.TLSClientSetting{Insecure: true},
+	}
+
+	c, err := newPostgreSQLClient(cfg, "app")
+	require.NoError(t, err)
+	require.NoError(t, c.close())
+
+	cfg.Endpoint = "localhost"
+	_, err = newPostgreSQLClient(cfg, "app")
+	assert.Error(t, err)
+}
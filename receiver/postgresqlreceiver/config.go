@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresqlreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+// Config defines the configuration of the PostgreSQL receiver.
+type Config struct {
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
+	// Endpoint and Transport of the PostgreSQL server, the transport being either "tcp" or "unix".
+	// With the "unix" transport, the endpoint is the directory of the socket, e.g. "/var/run/postgresql".
+	confignet.NetAddr `mapstructure:",squash"`
+
+	// Username used to connect to the server.
+	Username string `mapstructure:"username"`
+	// Password used to connect to the server.
+	Password string `mapstructure:"password"`
+	// Databases to collect the statistics of. All the databases accepting connections, except the
+	// templates, are collected when not set.
+	Databases []string `mapstructure:"databases"`
+
+	// TLS settings of the connections. TLS is disabled by default, and enabled by setting insecure to false.
+	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+}
@@ -0,0 +1,435 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Type is the component type name.
+const Type configmodels.Type = "postgresqlreceiver"
+
+type metricIntf interface {
+	Name() string
+	New() pdata.Metric
+}
+
+// Intentionally not exposing this so that it is opaque and can change freely.
+type metricImpl struct {
+	name    string
+	newFunc func() pdata.Metric
+}
+
+func (m *metricImpl) Name() string {
+	return m.name
+}
+
+func (m *metricImpl) New() pdata.Metric {
+	return m.newFunc()
+}
+
+type metricStruct struct {
+	PostgresqlBgwriterBuffersAllocated metricIntf
+	PostgresqlBgwriterBuffersWrites    metricIntf
+	PostgresqlBgwriterCheckpoints      metricIntf
+	PostgresqlBgwriterMaxwritten       metricIntf
+	PostgresqlBlocksRead               metricIntf
+	PostgresqlCommits                  metricIntf
+	PostgresqlConnections              metricIntf
+	PostgresqlDbSize                   metricIntf
+	PostgresqlIndexScans               metricIntf
+	PostgresqlOperations               metricIntf
+	PostgresqlRollbacks                metricIntf
+	PostgresqlRows                     metricIntf
+	PostgresqlSequentialScans          metricIntf
+	PostgresqlTableSize                metricIntf
+}
+
+// Names returns a list of all the metric name strings.
+func (m *metricStruct) Names() []string {
+	return []string{
+		"postgresql.bgwriter.buffers.allocated",
+		"postgresql.bgwriter.buffers.writes",
+		"postgresql.bgwriter.checkpoints",
+		"postgresql.bgwriter.maxwritten",
+		"postgresql.blocks_read",
+		"postgresql.commits",
+		"postgresql.connections",
+		"postgresql.db_size",
+		"postgresql.index.scans",
+		"postgresql.operations",
+		"postgresql.rollbacks",
+		"postgresql.rows",
+		"postgresql.sequential_scans",
+		"postgresql.table.size",
+	}
+}
+
+var metricsByName = map[string]metricIntf{
+	"postgresql.bgwriter.buffers.allocated": Metrics.PostgresqlBgwriterBuffersAllocated,
+	"postgresql.bgwriter.buffers.writes":    Metrics.PostgresqlBgwriterBuffersWrites,
+	"postgresql.bgwriter.checkpoints":       Metrics.PostgresqlBgwriterCheckpoints,
+	"postgresql.bgwriter.maxwritten":        Metrics.PostgresqlBgwriterMaxwritten,
+	"postgresql.blocks_read":                Metrics.PostgresqlBlocksRead,
+	"postgresql.commits":                    Metrics.PostgresqlCommits,
+	"postgresql.connections":                Metrics.PostgresqlConnections,
+	"postgresql.db_size":                    Metrics.PostgresqlDbSize,
+	"postgresql.index.scans":                Metrics.PostgresqlIndexScans,
+	"postgresql.operations":                 Metrics.PostgresqlOperations,
+	"postgresql.rollbacks":                  Metrics.PostgresqlRollbacks,
+	"postgresql.rows":                       Metrics.PostgresqlRows,
+	"postgresql.sequential_scans":           Metrics.PostgresqlSequentialScans,
+	"postgresql.table.size":                 Metrics.PostgresqlTableSize,
+}
+
+func (m *metricStruct) ByName(n string) metricIntf {
+	return metricsByName[n]
+}
+
+func (m *metricStruct) FactoriesByName() map[string]func() pdata.Metric {
+	return map[string]func() pdata.Metric{
+		Metrics.PostgresqlBgwriterBuffersAllocated.Name(): Metrics.PostgresqlBgwriterBuffersAllocated.New,
+		Metrics.PostgresqlBgwriterBuffersWrites.Name():    Metrics.PostgresqlBgwriterBuffersWrites.New,
+		Metrics.PostgresqlBgwriterCheckpoints.Name():      Metrics.PostgresqlBgwriterCheckpoints.New,
+		Metrics.PostgresqlBgwriterMaxwritten.Name():       Metrics.PostgresqlBgwriterMaxwritten.New,
+		Metrics.PostgresqlBlocksRead.Name():               Metrics.PostgresqlBlocksRead.New,
+		Metrics.PostgresqlCommits.Name():                  Metrics.PostgresqlCommits.New,
+		Metrics.PostgresqlConnections.Name():              Metrics.PostgresqlConnections.New,
+		Metrics.PostgresqlDbSize.Name():                   Metrics.PostgresqlDbSize.New,
+		Metrics.PostgresqlIndexScans.Name():               Metrics.PostgresqlIndexScans.New,
+		Metrics.PostgresqlOperations.Name():               Metrics.PostgresqlOperations.New,
+		Metrics.PostgresqlRollbacks.Name():                Metrics.PostgresqlRollbacks.New,
+		Metrics.PostgresqlRows.Name():                     Metrics.PostgresqlRows.New,
+		Metrics.PostgresqlSequentialScans.Name():          Metrics.PostgresqlSequentialScans.New,
+		Metrics.PostgresqlTableSize.Name():                Metrics.PostgresqlTableSize.New,
+	}
+}
+
+// Metrics contains a set of methods for each metric that help with
+// manipulating those metrics.
+var Metrics = &metricStruct{
+	&metricImpl{
+		"postgresql.bgwriter.buffers.allocated",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.bgwriter.buffers.allocated")
+			metric.SetDescription("The number of buffers allocated")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.bgwriter.buffers.writes",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.bgwriter.buffers.writes")
+			metric.SetDescription("The number of buffers written, by source")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.bgwriter.checkpoints",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.bgwriter.checkpoints")
+			metric.SetDescription("The number of checkpoints performed, by type")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.bgwriter.maxwritten",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.bgwriter.maxwritten")
+			metric.SetDescription("The number of times the background writer stopped a cleaning scan because it had written too many buffers")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.blocks_read",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.blocks_read")
+			metric.SetDescription("The number of blocks read from the table, by source")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.commits",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.commits")
+			metric.SetDescription("The number of commits in the database")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.connections",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.connections")
+			metric.SetDescription("The number of backend connections to the database, by state")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.db_size",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.db_size")
+			metric.SetDescription("The database disk usage")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.index.scans",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.index.scans")
+			metric.SetDescription("The number of index scans initiated on the table")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.operations",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.operations")
+			metric.SetDescription("The number of rows affected by the operations on the table, by operation")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.rollbacks",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.rollbacks")
+			metric.SetDescription("The number of rollbacks in the database")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.rows",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.rows")
+			metric.SetDescription("The number of rows in the table, by state")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.sequential_scans",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.sequential_scans")
+			metric.SetDescription("The number of sequential scans initiated on the table")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+			return metric
+		},
+	},
+	&metricImpl{
+		"postgresql.table.size",
+		func() pdata.Metric {
+			metric := pdata.NewMetric()
+			metric.SetName("postgresql.table.size")
+			metric.SetDescription("The disk usage of the table, including its indexes and TOAST data")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+
+			return metric
+		},
+	},
+}
+
+// M contains a set of methods for each metric that help with
+// manipulating those metrics. M is an alias for Metrics
+var M = Metrics
+
+// Labels contains the possible metric labels that can be used.
+var Labels = struct {
+	// BgBufferSource (The source of the buffer writes)
+	BgBufferSource string
+	// CheckpointType (The type of the checkpoint)
+	CheckpointType string
+	// ConnectionState (The state of the backend connection)
+	ConnectionState string
+	// Database (The name of the database)
+	Database string
+	// Operation (The database operation)
+	Operation string
+	// RowState (The tuple (row) state)
+	RowState string
+	// Source (The block read source type)
+	Source string
+	// Table (The schema-qualified name of the table)
+	Table string
+}{
+	"source",
+	"type",
+	"state",
+	"database",
+	"operation",
+	"state",
+	"source",
+	"table",
+}
+
+// L contains the possible metric labels that can be used. L is an alias for
+// Labels.
+var L = Labels
+
+// LabelBgBufferSource are the possible values that the label "bg_buffer_source" can have.
+var LabelBgBufferSource = struct {
+	Checkpoints  string
+	Bgwriter     string
+	Backend      string
+	BackendFsync string
+}{
+	"checkpoints",
+	"bgwriter",
+	"backend",
+	"backend_fsync",
+}
+
+// LabelCheckpointType are the possible values that the label "checkpoint_type" can have.
+var LabelCheckpointType = struct {
+	Scheduled string
+	Requested string
+}{
+	"scheduled",
+	"requested",
+}
+
+// LabelConnectionState are the possible values that the label "connection_state" can have.
+var LabelConnectionState = struct {
+	Active                   string
+	Idle                     string
+	IdleInTransaction        string
+	IdleInTransactionAborted string
+	FastpathFunctionCall     string
+	Disabled                 string
+	Unknown                  string
+}{
+	"active",
+	"idle",
+	"idle_in_transaction",
+	"idle_in_transaction_aborted",
+	"fastpath_function_call",
+	"disabled",
+	"unknown",
+}
+
+// LabelOperation are the possible values that the label "operation" can have.
+var LabelOperation = struct {
+	Ins    string
+	Upd    string
+	Del    string
+	HotUpd string
+}{
+	"ins",
+	"upd",
+	"del",
+	"hot_upd",
+}
+
+// LabelRowState are the possible values that the label "row_state" can have.
+var LabelRowState = struct {
+	Live string
+	Dead string
+}{
+	"live",
+	"dead",
+}
+
+// LabelSource are the possible values that the label "source" can have.
+var LabelSource = struct {
+	HeapRead  string
+	HeapHit   string
+	IdxRead   string
+	IdxHit    string
+	ToastRead string
+	ToastHit  string
+	TidxRead  string
+	TidxHit   string
+}{
+	"heap_read",
+	"heap_hit",
+	"idx_read",
+	"idx_hit",
+	"toast_read",
+	"toast_hit",
+	"tidx_read",
+	"tidx_hit",
+}
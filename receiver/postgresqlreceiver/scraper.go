@@ -0,0 +1,329 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresqlreceiver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/simple"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/postgresqlreceiver/internal/metadata"
+)
+
+const (
+	// instanceEndpointKey is the resource attribute of the endpoint of the server
+	instanceEndpointKey = "postgresql.instance.endpoint"
+
+	// defaultDatabase is the database connected to for the statistics of the server, when no
+	// databases are configured
+	defaultDatabase = "postgres"
+
+	// databaseMetricsCount is the number of metrics of each database queried from pg_stat_database
+	databaseMetricsCount = 3
+	// bgWriterMetricsCount is the number of metrics queried from pg_stat_bgwriter
+	bgWriterMetricsCount = 4
+	// tableMetricsCount is the number of metrics of each table
+	tableMetricsCount = 6
+)
+
+var (
+	errMissingEndpoint = errors.New("endpoint must be specified")
+	errMissingUsername = errors.New("username must be specified")
+)
+
+type postgreSQLScraper struct {
+	logger *zap.Logger
+	config *Config
+
+	newClient func(cfg *Config, database string) (client, error)
+	// serverDatabase is the database connected to for the statistics of the server and its databases
+	serverDatabase string
+	// clients are the clients of the databases by name, each database requiring its own connection
+	clients map[string]client
+
+	resourceAttributes map[string]string
+}
+
+func newPostgreSQLScraper(logger *zap.Logger, config *Config) (*postgreSQLScraper, error) {
+	if config.Endpoint == "" {
+		return nil, errMissingEndpoint
+	}
+	switch config.Transport {
+	case "tcp", "unix":
+	default:
+		return nil, fmt.Errorf("invalid transport %q, must be one of %q or %q", config.Transport, "tcp", "unix")
+	}
+	if config.Username == "" {
+		return nil, errMissingUsername
+	}
+
+	serverDatabase := defaultDatabase
+	if len(config.Databases) > 0 {
+		serverDatabase = config.Databases[0]
+	}
+
+	return &postgreSQLScraper{
+		logger:             logger,
+		config:             config,
+		newClient:          newPostgreSQLClient,
+		serverDatabase:     serverDatabase,
+		clients:            make(map[string]client),
+		resourceAttributes: map[string]string{instanceEndpointKey: config.Endpoint},
+	}, nil
+}
+
+func (p *postgreSQLScraper) shutdown(context.Context) error {
+	var errs []error
+	for database, c := range p.clients {
+		if err := c.close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(p.clients, database)
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+// client returns the client of the given database, creating it on first use
+func (p *postgreSQLScraper) client(database string) (client, error) {
+	if c, ok := p.clients[database]; ok {
+		return c, nil
+	}
+	c, err := p.newClient(p.config, database)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[database] = c
+	return c, nil
+}
+
+// scrape queries the statistics of the server and of its databases, then the statistics of the
+// tables of each database through its own connection. When one of the queries fails, e.g. for a
+// database that can't be connected to, the metrics of the other ones are still reported.
+func (p *postgreSQLScraper) scrape(ctx context.Context) (pdata.ResourceMetricsSlice, error) {
+	// Init client in scrape method in case there are transient errors in the
+	// constructor.
+	server, err := p.client(p.serverDatabase)
+	if err != nil {
+		return pdata.ResourceMetricsSlice{}, err
+	}
+
+	databases := p.config.Databases
+	if len(databases) == 0 {
+		databases, err = server.listDatabases(ctx)
+		if err != nil {
+			return pdata.ResourceMetricsSlice{}, fmt.Errorf("failed to list the databases: %w", err)
+		}
+	}
+
+	metrics := simple.Metrics{
+		Metrics:                    pdata.NewMetrics(),
+		Timestamp:                  time.Now(),
+		MetricFactoriesByName:      metadata.M.FactoriesByName(),
+		InstrumentationLibraryName: "otelcol/postgresql",
+		ResourceAttributes:         p.resourceAttributes,
+	}
+
+	var errs []error
+	if stats, err := server.getDatabaseStats(ctx, databases); err != nil {
+		p.logger.Error("Failed to fetch the PostgreSQL database statistics", zap.Error(err))
+		errs = append(errs, consumererror.NewPartialScrapeError(
+			fmt.Errorf("failed to fetch the database statistics: %w", err), databaseMetricsCount*len(databases)))
+	} else {
+		recordDatabaseStats(&metrics, stats)
+	}
+
+	if counts, err := server.getConnectionCounts(ctx, databases); err != nil {
+		p.logger.Error("Failed to fetch the PostgreSQL connections", zap.Error(err))
+		errs = append(errs, consumererror.NewPartialScrapeError(
+			fmt.Errorf("failed to fetch the connections: %w", err), len(databases)))
+	} else {
+		recordConnectionCounts(&metrics, counts)
+	}
+
+	if stats, err := server.getBackgroundWriterStats(ctx); err != nil {
+		p.logger.Error("Failed to fetch the PostgreSQL background writer statistics", zap.Error(err))
+		errs = append(errs, consumererror.NewPartialScrapeError(
+			fmt.Errorf("failed to fetch the background writer statistics: %w", err), bgWriterMetricsCount))
+	} else {
+		recordBackgroundWriterStats(&metrics, stats)
+	}
+
+	for _, database := range databases {
+		if err := p.scrapeTables(ctx, &metrics, database); err != nil {
+			p.logger.Error("Failed to fetch the PostgreSQL table statistics", zap.String("database", database), zap.Error(err))
+			errs = append(errs, consumererror.NewPartialScrapeError(
+				fmt.Errorf("failed to fetch the table statistics of database %q: %w", database, err), tableMetricsCount))
+		}
+	}
+	p.closeRemovedDatabases(databases)
+
+	return metrics.Metrics.ResourceMetrics(), scraperhelper.CombineScrapeErrors(errs)
+}
+
+// scrapeTables records the metrics of the tables of the given database
+func (p *postgreSQLScraper) scrapeTables(ctx context.Context, metrics *simple.Metrics, database string) error {
+	c, err := p.client(database)
+	if err != nil {
+		return err
+	}
+	stats, err := c.getTableStats(ctx)
+	if err != nil {
+		return err
+	}
+	recordTableStats(metrics, database, stats)
+	return nil
+}
+
+// closeRemovedDatabases closes the clients of the databases which aren't scraped anymore, as they
+// were dropped since the previous scrape
+func (p *postgreSQLScraper) closeRemovedDatabases(databases []string) {
+	scraped := make(map[string]bool, len(databases))
+	for _, database := range databases {
+		scraped[database] = true
+	}
+	for database, c := range p.clients {
+		if scraped[database] || database == p.serverDatabase {
+			continue
+		}
+		if err := c.close(); err != nil {
+			p.logger.Debug("Failed to close the client of a removed database", zap.String("database", database), zap.Error(err))
+		}
+		delete(p.clients, database)
+	}
+}
+
+func recordDatabaseStats(metrics *simple.Metrics, stats []databaseStats) {
+	for _, s := range stats {
+		m := metrics.WithLabels(map[string]string{metadata.L.Database: s.database})
+		m.AddSumDataPoint(metadata.M.PostgresqlCommits.Name(), s.commits)
+		m.AddSumDataPoint(metadata.M.PostgresqlRollbacks.Name(), s.rollbacks)
+		m.AddGaugeDataPoint(metadata.M.PostgresqlDbSize.Name(), s.size)
+	}
+}
+
+func recordConnectionCounts(metrics *simple.Metrics, counts []connectionCount) {
+	for _, c := range counts {
+		metrics.WithLabels(map[string]string{metadata.L.Database: c.database, metadata.L.ConnectionState: c.state}).
+			AddGaugeDataPoint(metadata.M.PostgresqlConnections.Name(), c.count)
+	}
+}
+
+func recordBackgroundWriterStats(metrics *simple.Metrics, s *bgWriterStats) {
+	for _, v := range []struct {
+		checkpointType string
+		value          int64
+	}{
+		{metadata.LabelCheckpointType.Scheduled, s.checkpointsTimed},
+		{metadata.LabelCheckpointType.Requested, s.checkpointsReq},
+	} {
+		metrics.WithLabels(map[string]string{metadata.L.CheckpointType: v.checkpointType}).
+			AddSumDataPoint(metadata.M.PostgresqlBgwriterCheckpoints.Name(), v.value)
+	}
+	for _, v := range []struct {
+		source string
+		value  int64
+	}{
+		{metadata.LabelBgBufferSource.Checkpoints, s.buffersCheckpoint},
+		{metadata.LabelBgBufferSource.Bgwriter, s.buffersClean},
+		{metadata.LabelBgBufferSource.Backend, s.buffersBackend},
+		{metadata.LabelBgBufferSource.BackendFsync, s.buffersBackendFsync},
+	} {
+		metrics.WithLabels(map[string]string{metadata.L.BgBufferSource: v.source}).
+			AddSumDataPoint(metadata.M.PostgresqlBgwriterBuffersWrites.Name(), v.value)
+	}
+	metrics.AddSumDataPoint(metadata.M.PostgresqlBgwriterBuffersAllocated.Name(), s.buffersAlloc)
+	metrics.AddSumDataPoint(metadata.M.PostgresqlBgwriterMaxwritten.Name(), s.maxwrittenClean)
+}
+
+// recordTableStats records the metrics of the tables of the given database. The statistics of the
+// indexes and TOAST data of the tables having none are skipped.
+func recordTableStats(metrics *simple.Metrics, database string, stats []tableStats) {
+	for _, s := range stats {
+		labels := map[string]string{metadata.L.Database: database, metadata.L.Table: s.table}
+		m := metrics.WithLabels(labels)
+		m.AddGaugeDataPoint(metadata.M.PostgresqlTableSize.Name(), s.size)
+		m.AddSumDataPoint(metadata.M.PostgresqlSequentialScans.Name(), s.seqScans)
+		if s.indexScans.Valid {
+			m.AddSumDataPoint(metadata.M.PostgresqlIndexScans.Name(), s.indexScans.Int64)
+		}
+
+		for _, v := range []struct {
+			state string
+			value int64
+		}{
+			{metadata.LabelRowState.Live, s.liveRows},
+			{metadata.LabelRowState.Dead, s.deadRows},
+		} {
+			metrics.WithLabels(withLabel(labels, metadata.L.RowState, v.state)).
+				AddGaugeDataPoint(metadata.M.PostgresqlRows.Name(), v.value)
+		}
+
+		for _, v := range []struct {
+			operation string
+			value     int64
+		}{
+			{metadata.LabelOperation.Ins, s.inserts},
+			{metadata.LabelOperation.Upd, s.updates},
+			{metadata.LabelOperation.Del, s.deletes},
+			{metadata.LabelOperation.HotUpd, s.hotUpdates},
+		} {
+			metrics.WithLabels(withLabel(labels, metadata.L.Operation, v.operation)).
+				AddSumDataPoint(metadata.M.PostgresqlOperations.Name(), v.value)
+		}
+
+		for _, v := range []struct {
+			source string
+			value  sql.NullInt64
+		}{
+			{metadata.LabelSource.HeapRead, validInt64(s.heapBlksRead)},
+			{metadata.LabelSource.HeapHit, validInt64(s.heapBlksHit)},
+			{metadata.LabelSource.IdxRead, s.idxBlksRead},
+			{metadata.LabelSource.IdxHit, s.idxBlksHit},
+			{metadata.LabelSource.ToastRead, s.toastBlksRead},
+			{metadata.LabelSource.ToastHit, s.toastBlksHit},
+			{metadata.LabelSource.TidxRead, s.tidxBlksRead},
+			{metadata.LabelSource.TidxHit, s.tidxBlksHit},
+		} {
+			if !v.value.Valid {
+				continue
+			}
+			metrics.WithLabels(withLabel(labels, metadata.L.Source, v.source)).
+				AddSumDataPoint(metadata.M.PostgresqlBlocksRead.Name(), v.value.Int64)
+		}
+	}
+}
+
+// withLabel returns a copy of the given labels with the given label added
+func withLabel(labels map[string]string, key string, value string) map[string]string {
+	l := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		l[k] = v
+	}
+	l[key] = value
+	return l
+}
+
+func validInt64(i int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: i, Valid: true}
+}
@@ -0,0 +1,438 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresqlreceiver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type fakeClient struct {
+	databases     []string
+	databaseStats []databaseStats
+	connections   []connectionCount
+	bgWriter      *bgWriterStats
+	tables        []tableStats
+
+	listErr        error
+	databaseErr    error
+	connectionsErr error
+	bgWriterErr    error
+	tablesErr      error
+
+	closed bool
+}
+
+func (c *fakeClient) listDatabases(context.Context) ([]string, error) {
+	return c.databases, c.listErr
+}
+
+func (c *fakeClient) getDatabaseStats(_ context.Context, databases []string) ([]databaseStats, error) {
+	if c.databaseErr != nil {
+		return nil, c.databaseErr
+	}
+	var stats []databaseStats
+	for _, s := range c.databaseStats {
+		if contains(databases, s.database) {
+			stats = append(stats, s)
+		}
+	}
+	return stats, nil
+}
+
+func (c *fakeClient) getConnectionCounts(_ context.Context, databases []string) ([]connectionCount, error) {
+	if c.connectionsErr != nil {
+		return nil, c.connectionsErr
+	}
+	var counts []connectionCount
+	for _, cc := range c.connections {
+		if contains(databases, cc.database) {
+			counts = append(counts, cc)
+		}
+	}
+	return counts, nil
+}
+
+func (c *fakeClient) getBackgroundWriterStats(context.Context) (*bgWriterStats, error) {
+	return c.bgWriter, c.bgWriterErr
+}
+
+func (c *fakeClient) getTableStats(context.Context) ([]tableStats, error) {
+	return c.tables, c.tablesErr
+}
+
+func (c *fakeClient) close() error {
+	c.closed = true
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	testDatabaseStats = []databaseStats{
+		{database: "app", commits: 100, rollbacks: 2, size: 8192000},
+		{database: "billing", commits: 50, rollbacks: 1, size: 4096000},
+	}
+	testConnections = []connectionCount{
+		{database: "app", state: "active", count: 3},
+		{database: "app", state: "idle", count: 5},
+		{database: "billing", state: "idle_in_transaction", count: 1},
+	}
+	testBgWriter = &bgWriterStats{
+		checkpointsTimed:    10,
+		checkpointsReq:      2,
+		buffersCheckpoint:   300,
+		buffersClean:        40,
+		buffersBackend:      50,
+		buffersBackendFsync: 0,
+		buffersAlloc:        600,
+		maxwrittenClean:     1,
+	}
+)
+
+// testTable returns the statistics of a table without TOAST data
+func testTable(table string) tableStats {
+	return tableStats{
+		table:        table,
+		liveRows:     1000,
+		deadRows:     10,
+		inserts:      1200,
+		updates:      300,
+		deletes:      200,
+		hotUpdates:   100,
+		seqScans:     7,
+		indexScans:   sql.NullInt64{Int64: 42, Valid: true},
+		size:         65536,
+		heapBlksRead: 20,
+		heapBlksHit:  800,
+		idxBlksRead:  sql.NullInt64{Int64: 5, Valid: true},
+		idxBlksHit:   sql.NullInt64{Int64: 400, Valid: true},
+	}
+}
+
+// fakeClients returns the clients of the databases by name, the server statistics being the same
+// from all of them
+func fakeClients(tables map[string][]tableStats) map[string]*fakeClient {
+	clients := map[string]*fakeClient{}
+	for database, t := range tables {
+		clients[database] = &fakeClient{
+			databases:     []string{"app", "billing"},
+			databaseStats: testDatabaseStats,
+			connections:   testConnections,
+			bgWriter:      testBgWriter,
+			tables:        t,
+		}
+	}
+	return clients
+}
+
+func newTestScraper(t *testing.T, databases []string, clients map[string]*fakeClient) *postgreSQLScraper {
+	s, err := newPostgreSQLScraper(zap.NewNop(), &Config{
+		NetAddr:   confignet.NetAddr{Endpoint: "localhost:5432", Transport: "tcp"},
+		Username:  "otel",
+		Databases: databases,
+	})
+	require.NoError(t, err)
+	s.newClient = func(_ *Config, database string) (client, error) {
+		c, ok := clients[database]
+		if !ok {
+			return nil, errors.New("database does not exist")
+		}
+		return c, nil
+	}
+	return s
+}
+
+// dataPoint is a data point of an int gauge or sum, identified by its metric and labels
+type dataPoint struct {
+	metric string
+	labels string
+}
+
+func intDataPoints(t *testing.T, rms pdata.ResourceMetricsSlice) map[dataPoint]int64 {
+	require.Equal(t, 1, rms.Len())
+	ilms := rms.At(0).InstrumentationLibraryMetrics()
+	require.Equal(t, 1, ilms.Len())
+	assert.Equal(t, "otelcol/postgresql", ilms.At(0).InstrumentationLibrary().Name())
+
+	points := map[dataPoint]int64{}
+	metrics := ilms.At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		metric := metrics.At(i)
+		var dps pdata.IntDataPointSlice
+		switch metric.DataType() {
+		case pdata.MetricDataTypeIntGauge:
+			dps = metric.IntGauge().DataPoints()
+		case pdata.MetricDataTypeIntSum:
+			sum := metric.IntSum()
+			assert.True(t, sum.IsMonotonic(), metric.Name())
+			assert.Equal(t, pdata.AggregationTemporalityCumulative, sum.AggregationTemporality(), metric.Name())
+			dps = sum.DataPoints()
+		default:
+			t.Fatalf("unexpected data type %v of %s", metric.DataType(), metric.Name())
+		}
+
+		for j := 0; j < dps.Len(); j++ {
+			var labels []string
+			dps.At(j).LabelsMap().ForEach(func(k string, v string) {
+				labels = append(labels, k+"="+v)
+			})
+			sort.Strings(labels)
+			points[dataPoint{metric.Name(), strings.Join(labels, ",")}] = dps.At(j).Value()
+		}
+	}
+	return points
+}
+
+// serverDataPoints are the data points of the server statistics of the app and billing databases
+var serverDataPoints = map[dataPoint]int64{
+	{"postgresql.commits", "database=app"}:                                   100,
+	{"postgresql.rollbacks", "database=app"}:                                 2,
+	{"postgresql.db_size", "database=app"}:                                   8192000,
+	{"postgresql.commits", "database=billing"}:                               50,
+	{"postgresql.rollbacks", "database=billing"}:                             1,
+	{"postgresql.db_size", "database=billing"}:                               4096000,
+	{"postgresql.connections", "database=app,state=active"}:                  3,
+	{"postgresql.connections", "database=app,state=idle"}:                    5,
+	{"postgresql.connections", "database=billing,state=idle_in_transaction"}: 1,
+	{"postgresql.bgwriter.checkpoints", "type=scheduled"}:                    10,
+	{"postgresql.bgwriter.checkpoints", "type=requested"}:                    2,
+	{"postgresql.bgwriter.buffers.writes", "source=checkpoints"}:             300,
+	{"postgresql.bgwriter.buffers.writes", "source=bgwriter"}:                40,
+	{"postgresql.bgwriter.buffers.writes", "source=backend"}:                 50,
+	{"postgresql.bgwriter.buffers.writes", "source=backend_fsync"}:           0,
+	{"postgresql.bgwriter.buffers.allocated", ""}:                            600,
+	{"postgresql.bgwriter.maxwritten", ""}:                                   1,
+}
+
+// tableDataPoints returns the data points of a table returned by testTable
+func tableDataPoints(database string, table string) map[dataPoint]int64 {
+	labels := "database=" + database + ",table=" + table
+	// the labels of the data points are sorted by key
+	with := func(label string) string {
+		return "database=" + database + "," + label + ",table=" + table
+	}
+	return map[dataPoint]int64{
+		{"postgresql.table.size", labels}:                    65536,
+		{"postgresql.sequential_scans", labels}:              7,
+		{"postgresql.index.scans", labels}:                   42,
+		{"postgresql.rows", with("state=live")}:              1000,
+		{"postgresql.rows", with("state=dead")}:              10,
+		{"postgresql.operations", with("operation=ins")}:     1200,
+		{"postgresql.operations", with("operation=upd")}:     300,
+		{"postgresql.operations", with("operation=del")}:     200,
+		{"postgresql.operations", with("operation=hot_upd")}: 100,
+		{"postgresql.blocks_read", with("source=heap_read")}: 20,
+		{"postgresql.blocks_read", with("source=heap_hit")}:  800,
+		{"postgresql.blocks_read", with("source=idx_read")}:  5,
+		{"postgresql.blocks_read", with("source=idx_hit")}:   400,
+	}
+}
+
+func merge(dps ...map[dataPoint]int64) map[dataPoint]int64 {
+	merged := map[dataPoint]int64{}
+	for _, m := range dps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func TestScrape(t *testing.T) {
+	clients := fakeClients(map[string][]tableStats{
+		"postgres": nil,
+		"app":      {testTable("public.users")},
+		"billing":  {testTable("public.invoices")},
+	})
+	s := newTestScraper(t, nil, clients)
+
+	rms, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"postgresql.instance.endpoint": "localhost:5432",
+	}, attributes(rms.At(0).Resource().Attributes()))
+
+	assert.Equal(t, merge(
+		serverDataPoints,
+		tableDataPoints("app", "public.users"),
+		tableDataPoints("billing", "public.invoices"),
+	), intDataPoints(t, rms))
+
+	require.NoError(t, s.shutdown(context.Background()))
+	for database, c := range clients {
+		assert.True(t, c.closed, database)
+	}
+}
+
+func TestScrapeConfiguredDatabases(t *testing.T) {
+	clients := fakeClients(map[string][]tableStats{
+		"app":     {testTable("public.users")},
+		"billing": {testTable("public.invoices")},
+	})
+	s := newTestScraper(t, []string{"app"}, clients)
+
+	rms, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	// the server statistics are queried through the first configured database
+	expected := merge(serverDataPoints, tableDataPoints("app", "public.users"))
+	delete(expected, dataPoint{"postgresql.commits", "database=billing"})
+	delete(expected, dataPoint{"postgresql.rollbacks", "database=billing"})
+	delete(expected, dataPoint{"postgresql.db_size", "database=billing"})
+	delete(expected, dataPoint{"postgresql.connections", "database=billing,state=idle_in_transaction"})
+	assert.Equal(t, expected, intDataPoints(t, rms))
+	assert.NotContains(t, s.clients, "billing")
+}
+
+func TestScrapeDatabaseError(t *testing.T) {
+	clients := fakeClients(map[string][]tableStats{
+		"postgres": nil,
+		"billing":  {testTable("public.invoices")},
+	})
+	clients["postgres"].databases = []string{"app", "billing"}
+	s := newTestScraper(t, nil, clients)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), `failed to fetch the table statistics of database "app": database does not exist`)
+
+	// the metrics of the other database are still reported
+	assert.Equal(t, merge(serverDataPoints, tableDataPoints("billing", "public.invoices")), intDataPoints(t, rms))
+}
+
+func TestScrapeTablesError(t *testing.T) {
+	clients := fakeClients(map[string][]tableStats{
+		"postgres": nil,
+		"app":      nil,
+		"billing":  {testTable("public.invoices")},
+	})
+	clients["app"].tablesErr = errors.New("permission denied")
+	s := newTestScraper(t, nil, clients)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), `failed to fetch the table statistics of database "app": permission denied`)
+
+	assert.Equal(t, merge(serverDataPoints, tableDataPoints("billing", "public.invoices")), intDataPoints(t, rms))
+}
+
+func TestScrapeServerStatsErrors(t *testing.T) {
+	clients := fakeClients(map[string][]tableStats{
+		"app": {testTable("public.users")},
+	})
+	clients["app"].databaseErr = errors.New("database stats denied")
+	clients["app"].connectionsErr = errors.New("connections denied")
+	clients["app"].bgWriterErr = errors.New("bgwriter denied")
+	s := newTestScraper(t, []string{"app"}, clients)
+
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "failed to fetch the database statistics: database stats denied")
+	assert.Contains(t, err.Error(), "failed to fetch the connections: connections denied")
+	assert.Contains(t, err.Error(), "failed to fetch the background writer statistics: bgwriter denied")
+
+	// the metrics of the tables are still reported
+	assert.Equal(t, tableDataPoints("app", "public.users"), intDataPoints(t, rms))
+}
+
+func TestScrapeTableWithoutIndexes(t *testing.T) {
+	table := testTable("public.events")
+	table.indexScans = sql.NullInt64{}
+	table.idxBlksRead = sql.NullInt64{}
+	table.idxBlksHit = sql.NullInt64{}
+	table.toastBlksRead = sql.NullInt64{Int64: 3, Valid: true}
+	table.toastBlksHit = sql.NullInt64{Int64: 9, Valid: true}
+	clients := fakeClients(map[string][]tableStats{"app": {table}})
+	s := newTestScraper(t, []string{"app"}, clients)
+
+	rms, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	dps := intDataPoints(t, rms)
+	assert.NotContains(t, dps, dataPoint{"postgresql.index.scans", "database=app,table=public.events"})
+	assert.NotContains(t, dps, dataPoint{"postgresql.blocks_read", "database=app,source=idx_read,table=public.events"})
+	assert.NotContains(t, dps, dataPoint{"postgresql.blocks_read", "database=app,source=tidx_read,table=public.events"})
+	assert.Equal(t, int64(3), dps[dataPoint{"postgresql.blocks_read", "database=app,source=toast_read,table=public.events"}])
+	assert.Equal(t, int64(9), dps[dataPoint{"postgresql.blocks_read", "database=app,source=toast_hit,table=public.events"}])
+}
+
+func TestScrapeListDatabasesError(t *testing.T) {
+	clients := fakeClients(map[string][]tableStats{"postgres": nil})
+	clients["postgres"].listErr = errors.New("connection refused")
+	s := newTestScraper(t, nil, clients)
+
+	_, err := s.scrape(context.Background())
+	assert.EqualError(t, err, "failed to list the databases: connection refused")
+}
+
+func TestScrapeClientError(t *testing.T) {
+	s := newTestScraper(t, nil, nil)
+
+	_, err := s.scrape(context.Background())
+	assert.EqualError(t, err, "database does not exist")
+	assert.Empty(t, s.clients)
+	require.NoError(t, s.shutdown(context.Background()))
+}
+
+func TestScrapeRemovedDatabase(t *testing.T) {
+	clients := fakeClients(map[string][]tableStats{
+		"postgres": nil,
+		"app":      {testTable("public.users")},
+		"billing":  {testTable("public.invoices")},
+	})
+	s := newTestScraper(t, nil, clients)
+
+	_, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	// the billing database is dropped
+	clients["postgres"].databases = []string{"app"}
+	_, err = s.scrape(context.Background())
+	require.NoError(t, err)
+	assert.True(t, clients["billing"].closed)
+	assert.NotContains(t, s.clients, "billing")
+	assert.False(t, clients["app"].closed)
+}
+
+func attributes(attrs pdata.AttributeMap) map[string]interface{} {
+	out := map[string]interface{}{}
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		out[k] = v.StringVal()
+	})
+	return out
+}
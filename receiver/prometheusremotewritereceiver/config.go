@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+const (
+	// histogramsReconstruct reconstructs the histograms from their _bucket, _sum and _count series
+	histogramsReconstruct = "reconstruct"
+	// histogramsGauges reports the series of the histograms as individual gauges
+	histogramsGauges = "gauges"
+)
+
+// Config defines configuration for the Prometheus remote write receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Path of the remote write endpoint, /api/v1/write by default.
+	Path string `mapstructure:"path"`
+
+	// Histograms is either reconstruct, the default, to translate the _bucket, _sum and _count series of the
+	// histograms into histogram metrics, or gauges to report them as individual gauges.
+	Histograms string `mapstructure:"histograms"`
+
+	// MaxConcurrentRequests is the number of requests handled concurrently, the requests above it being
+	// answered with 429 Too Many Requests for the senders to retry later. Unlimited when 0, the default.
+	MaxConcurrentRequests int `mapstructure:"max_concurrent_requests"`
+}
+
+func (cfg *Config) validate() error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint must be specified")
+	}
+	if !strings.HasPrefix(cfg.Path, "/") {
+		return fmt.Errorf("path %q must start with /", cfg.Path)
+	}
+	if cfg.Histograms != histogramsReconstruct && cfg.Histograms != histogramsGauges {
+		return fmt.Errorf("unsupported histograms %q, must be either %q or %q",
+			cfg.Histograms, histogramsReconstruct, histogramsGauges)
+	}
+	if cfg.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("max_concurrent_requests can't be negative: %d", cfg.MaxConcurrentRequests)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateMetricsReceiver(t *testing.T) {
+	factory := NewFactory()
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	receiver, err := factory.CreateMetricsReceiver(context.Background(), params, factory.CreateDefaultConfig(), consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, receiver)
+}
+
+func TestCreateMetricsReceiverInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "no endpoint",
+			modify: func(cfg *Config) { cfg.Endpoint = "" },
+			err:    "endpoint must be specified",
+		},
+		{
+			name:   "relative path",
+			modify: func(cfg *Config) { cfg.Path = "api/v1/write" },
+			err:    `path "api/v1/write" must start with /`,
+		},
+		{
+			name:   "invalid histograms",
+			modify: func(cfg *Config) { cfg.Histograms = "summaries" },
+			err:    `unsupported histograms "summaries", must be either "reconstruct" or "gauges"`,
+		},
+		{
+			name:   "negative max concurrent requests",
+			modify: func(cfg *Config) { cfg.MaxConcurrentRequests = -1 },
+			err:    "max_concurrent_requests can't be negative: -1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			tt.modify(cfg)
+
+			params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			_, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewMetricsNop())
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
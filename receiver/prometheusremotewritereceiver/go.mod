@@ -0,0 +1,12 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver
+
+go 1.14
+
+require (
+	github.com/golang/snappy v0.0.2
+	github.com/prometheus/prometheus v1.8.2-0.20201105135750-00f16d1ac3a4
+	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.5
+	go.opentelemetry.io/collector v0.18.0
+	go.uber.org/zap v1.16.0
+)
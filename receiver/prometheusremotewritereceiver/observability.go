@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func init() {
+	view.Register(
+		viewMalformedRequests,
+	)
+}
+
+var (
+	mMalformedRequests = stats.Int64("otelcol/prometheusremotewrite/malformed_requests", "Number of remote write requests which couldn't be decoded", "1")
+)
+
+var viewMalformedRequests = &view.View{
+	Name:        mMalformedRequests.Name(),
+	Description: mMalformedRequests.Description(),
+	Measure:     mMalformedRequests,
+	Aggregation: view.Sum(),
+}
+
+func recordMalformedRequest() {
+	stats.Record(context.Background(), mMalformedRequests.M(int64(1)))
+}
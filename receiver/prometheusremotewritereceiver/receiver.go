@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+const (
+	responseInvalidMethod      = "Only \"POST\" method is supported"
+	responseTooManyRequests    = "Too many concurrent requests"
+	responseErrReadBody        = "Failed to read message body"
+	responseErrDecompressBody  = "Failed to decompress message body, it must be snappy compressed"
+	responseErrUnmarshalBody   = "Failed to unmarshal message body"
+	responseErrTranslateBody   = "Failed to translate the time series"
+	responseErrNextConsumer    = "Service Unavailable"
+	responseErrPermanentReject = "The time series were rejected"
+)
+
+// prometheusRemoteWriteReceiver implements the component.MetricsReceiver for the Prometheus remote write protocol.
+type prometheusRemoteWriteReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	server       *http.Server
+
+	// inFlight limits the number of requests handled concurrently, unlimited when nil
+	inFlight chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ component.MetricsReceiver = (*prometheusRemoteWriteReceiver)(nil)
+
+func newReceiver(logger *zap.Logger, config *Config, nextConsumer consumer.MetricsConsumer) *prometheusRemoteWriteReceiver {
+	r := &prometheusRemoteWriteReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+	}
+	if config.MaxConcurrentRequests > 0 {
+		r.inFlight = make(chan struct{}, config.MaxConcurrentRequests)
+	}
+	return r
+}
+
+// Start starts the HTTP server serving the remote write endpoint.
+func (r *prometheusRemoteWriteReceiver) Start(_ context.Context, host component.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		ln, listenErr := r.config.HTTPServerSettings.ToListener()
+		if listenErr != nil {
+			err = fmt.Errorf("failed to bind to address %s: %w", r.config.Endpoint, listenErr)
+			return
+		}
+		err = nil
+
+		mux := http.NewServeMux()
+		mux.HandleFunc(r.config.Path, r.handleWrite)
+		r.server = r.config.HTTPServerSettings.ToServer(mux)
+
+		go func() {
+			if errHTTP := r.server.Serve(ln); errHTTP != nil && !errors.Is(errHTTP, http.ErrServerClosed) {
+				host.ReportFatalError(errHTTP)
+			}
+		}()
+	})
+	return err
+}
+
+// Shutdown stops the HTTP server.
+func (r *prometheusRemoteWriteReceiver) Shutdown(context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		if r.server != nil {
+			err = r.server.Close()
+		}
+	})
+	return err
+}
+
+// handleWrite handles a remote write request. The senders retry the requests answered with 5xx and, depending
+// on their configuration, 429, so the data rejected because of an error of the next consumer are answered as
+// unavailable unless the error is permanent, and the malformed requests as bad requests.
+func (r *prometheusRemoteWriteReceiver) handleWrite(resp http.ResponseWriter, req *http.Request) {
+	transport := "http"
+	if r.config.TLSSetting != nil {
+		transport = "https"
+	}
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+
+	if req.Method != http.MethodPost {
+		resp.Header().Set("Allow", http.MethodPost)
+		r.failRequest(ctx, resp, http.StatusMethodNotAllowed, responseInvalidMethod, nil)
+		return
+	}
+
+	if r.inFlight != nil {
+		select {
+		case r.inFlight <- struct{}{}:
+			defer func() { <-r.inFlight }()
+		default:
+			r.failRequest(ctx, resp, http.StatusTooManyRequests, responseTooManyRequests, nil)
+			return
+		}
+	}
+
+	compressed, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, responseErrReadBody, err)
+		return
+	}
+	body, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		recordMalformedRequest()
+		r.failRequest(ctx, resp, http.StatusBadRequest, responseErrDecompressBody, err)
+		return
+	}
+	var writeReq prompb.WriteRequest
+	if err = writeReq.Unmarshal(body); err != nil {
+		recordMalformedRequest()
+		r.failRequest(ctx, resp, http.StatusBadRequest, responseErrUnmarshalBody, err)
+		return
+	}
+
+	md, numPoints, err := writeRequestToMetrics(&writeReq, r.config.Histograms == histogramsReconstruct)
+	if err != nil {
+		recordMalformedRequest()
+		r.failRequest(ctx, resp, http.StatusBadRequest, responseErrTranslateBody, err)
+		return
+	}
+
+	if numPoints > 0 {
+		err = r.nextConsumer.ConsumeMetrics(ctx, md)
+	}
+	obsreport.EndMetricsReceiveOp(ctx, typeStr, numPoints, err)
+	if err != nil {
+		if consumererror.IsPermanent(err) {
+			r.writeError(resp, http.StatusBadRequest, responseErrPermanentReject, err)
+			return
+		}
+		r.writeError(resp, http.StatusServiceUnavailable, responseErrNextConsumer, err)
+		return
+	}
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+// failRequest answers a request which couldn't be handled, and ends its receive operation
+func (r *prometheusRemoteWriteReceiver) failRequest(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	httpStatusCode int,
+	msg string,
+	err error,
+) {
+	if err == nil {
+		err = errors.New(msg)
+	}
+	obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, err)
+	r.writeError(resp, httpStatusCode, msg, err)
+}
+
+func (r *prometheusRemoteWriteReceiver) writeError(resp http.ResponseWriter, httpStatusCode int, msg string, err error) {
+	http.Error(resp, msg, httpStatusCode)
+	r.logger.Debug(
+		"Prometheus remote write receiver request failed",
+		zap.Int("http_status_code", httpStatusCode),
+		zap.String("msg", msg),
+		zap.Error(err),
+		zap.String("receiver", r.config.Name()))
+}
@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func marshalWriteRequest(t *testing.T, req *prompb.WriteRequest) []byte {
+	body, err := req.Marshal()
+	require.NoError(t, err)
+	return snappy.Encode(nil, body)
+}
+
+func newTestReceiver(sink *consumertest.MetricsSink) *prometheusRemoteWriteReceiver {
+	return newReceiver(zap.NewNop(), createDefaultConfig().(*Config), sink)
+}
+
+func TestHandleWrite(t *testing.T) {
+	validBody := marshalWriteRequest(t, &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		timeSeries([]string{"__name__", "http_requests_total", "job", "api"}, 42, 1000),
+		timeSeries([]string{"__name__", "up", "job", "api"}, 1, 1000),
+	}})
+
+	tests := []struct {
+		name         string
+		method       string
+		body         []byte
+		consumeErr   error
+		wantStatus   int
+		wantResponse string
+		wantPoints   int
+	}{
+		{
+			name:       "accepted",
+			method:     http.MethodPost,
+			body:       validBody,
+			wantStatus: http.StatusNoContent,
+			wantPoints: 2,
+		},
+		{
+			name:       "empty request",
+			method:     http.MethodPost,
+			body:       marshalWriteRequest(t, &prompb.WriteRequest{}),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:         "invalid method",
+			method:       http.MethodGet,
+			wantStatus:   http.StatusMethodNotAllowed,
+			wantResponse: responseInvalidMethod,
+		},
+		{
+			name:         "not snappy compressed",
+			method:       http.MethodPost,
+			body:         []byte("not snappy"),
+			wantStatus:   http.StatusBadRequest,
+			wantResponse: responseErrDecompressBody,
+		},
+		{
+			name:         "not a write request",
+			method:       http.MethodPost,
+			body:         snappy.Encode(nil, []byte{0xff, 0xff}),
+			wantStatus:   http.StatusBadRequest,
+			wantResponse: responseErrUnmarshalBody,
+		},
+		{
+			name:   "series without name",
+			method: http.MethodPost,
+			body: marshalWriteRequest(t, &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+				timeSeries([]string{"job", "api"}, 1, 1000),
+			}}),
+			wantStatus:   http.StatusBadRequest,
+			wantResponse: responseErrTranslateBody,
+		},
+		{
+			name:         "next consumer error",
+			method:       http.MethodPost,
+			body:         validBody,
+			consumeErr:   errors.New("queue is full"),
+			wantStatus:   http.StatusServiceUnavailable,
+			wantResponse: responseErrNextConsumer,
+		},
+		{
+			name:         "permanent next consumer error",
+			method:       http.MethodPost,
+			body:         validBody,
+			consumeErr:   consumererror.Permanent(errors.New("invalid metric")),
+			wantStatus:   http.StatusBadRequest,
+			wantResponse: responseErrPermanentReject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			sink.SetConsumeError(tt.consumeErr)
+			r := newTestReceiver(sink)
+
+			req := httptest.NewRequest(tt.method, defaultPath, bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.handleWrite(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantResponse != "" {
+				assert.Equal(t, tt.wantResponse+"\n", w.Body.String())
+			}
+			if tt.wantPoints > 0 {
+				require.Len(t, sink.AllMetrics(), 1)
+				assert.Equal(t, tt.wantPoints, sink.AllMetrics()[0].MetricCount())
+			} else if tt.consumeErr == nil {
+				assert.Len(t, sink.AllMetrics(), 0)
+			}
+		})
+	}
+}
+
+func TestHandleWriteTooManyRequests(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	r := newTestReceiver(sink)
+	r.inFlight = make(chan struct{}, 1)
+	// a request is already being handled
+	r.inFlight <- struct{}{}
+
+	req := httptest.NewRequest(http.MethodPost, defaultPath, bytes.NewReader(marshalWriteRequest(t, &prompb.WriteRequest{})))
+	w := httptest.NewRecorder()
+	r.handleWrite(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	<-r.inFlight
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, defaultPath, bytes.NewReader(marshalWriteRequest(t, &prompb.WriteRequest{})))
+	r.handleWrite(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Len(t, r.inFlight, 0)
+}
+
+func TestReceiverStartShutdown(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	r := newReceiver(zap.NewNop(), cfg, sink)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	body := marshalWriteRequest(t, &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		timeSeries([]string{"__name__", "up", "job", "api"}, 1, 1000),
+	}})
+	req, err := http.NewRequest(http.MethodPost, "http://"+cfg.Endpoint+defaultPath, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 1, sink.MetricsCount())
+}
@@ -0,0 +1,402 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	counterSuffix = "_total"
+	bucketSuffix  = "_bucket"
+	sumSuffix     = "_sum"
+	countSuffix   = "_count"
+
+	jobLabel      = "job"
+	instanceLabel = "instance"
+)
+
+// series is a time series of a write request, its job and instance labels identifying its resource
+type series struct {
+	name     string
+	job      string
+	instance string
+	labels   map[string]string
+	samples  []prompb.Sample
+}
+
+func newSeries(ts *prompb.TimeSeries) (*series, error) {
+	s := &series{labels: make(map[string]string, len(ts.Labels)), samples: ts.Samples}
+	for _, l := range ts.Labels {
+		switch l.Name {
+		case labels.MetricName:
+			s.name = l.Value
+		case jobLabel:
+			s.job = l.Value
+		case instanceLabel:
+			s.instance = l.Value
+		default:
+			s.labels[l.Name] = l.Value
+		}
+	}
+	if s.name == "" {
+		return nil, fmt.Errorf("time series without the %s label", labels.MetricName)
+	}
+	return s, nil
+}
+
+func (s *series) resourceKey() string {
+	return s.job + "\xff" + s.instance
+}
+
+// histogramKey identifies the series of a histogram, i.e. its family and labels except le
+func (s *series) histogramKey(family string) string {
+	names := make([]string, 0, len(s.labels))
+	for name := range s.labels {
+		if name != labels.BucketLabel {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(family)
+	for _, name := range names {
+		b.WriteString("\xff" + name + "\xff" + s.labels[name])
+	}
+	return b.String()
+}
+
+// histogramFamily returns the family of the series if it's the _bucket, _sum or _count series of one of the
+// given histogram families
+func (s *series) histogramFamily(families map[string]bool) (string, bool) {
+	for _, suffix := range []string{bucketSuffix, sumSuffix, countSuffix} {
+		if family := strings.TrimSuffix(s.name, suffix); family != s.name && families[family] {
+			if suffix == bucketSuffix {
+				if _, ok := s.labels[labels.BucketLabel]; !ok {
+					return "", false
+				}
+			}
+			return family, true
+		}
+	}
+	return "", false
+}
+
+// histogram gathers the series of a histogram, translated as a histogram metric when they are consistent
+type histogram struct {
+	family string
+	labels map[string]string
+	series []*series
+}
+
+// histogramPoint is the value of a histogram at a timestamp
+type histogramPoint struct {
+	buckets  map[float64]float64
+	sum      float64
+	count    float64
+	hasCount bool
+}
+
+// resourceMetrics gathers the metrics of a resource, in the order of their first series
+type resourceMetrics struct {
+	rm         pdata.ResourceMetrics
+	metrics    map[string]pdata.Metric
+	histograms map[string]*histogram
+	order      []*histogram
+}
+
+// writeRequestToMetrics translates the time series of a write request into metrics, and returns them with
+// their number of data points. The series are grouped into resources by their job and instance, and are
+// translated into cumulative sums when their name ends with _total, into histograms when they are the
+// consistent _bucket, _sum and _count series of a histogram and reconstructHistograms is set, and into
+// gauges otherwise. The stale markers are skipped.
+func writeRequestToMetrics(req *prompb.WriteRequest, reconstructHistograms bool) (pdata.Metrics, int, error) {
+	all := make([]*series, 0, len(req.Timeseries))
+	// the histogram families of each resource, i.e. the families having _bucket series
+	families := map[string]map[string]bool{}
+	for i := range req.Timeseries {
+		s, err := newSeries(&req.Timeseries[i])
+		if err != nil {
+			return pdata.Metrics{}, 0, err
+		}
+		all = append(all, s)
+
+		if _, ok := s.labels[labels.BucketLabel]; ok && reconstructHistograms && strings.HasSuffix(s.name, bucketSuffix) {
+			if families[s.resourceKey()] == nil {
+				families[s.resourceKey()] = map[string]bool{}
+			}
+			families[s.resourceKey()][strings.TrimSuffix(s.name, bucketSuffix)] = true
+		}
+	}
+
+	md := pdata.NewMetrics()
+	resources := map[string]*resourceMetrics{}
+	var resourceOrder []*resourceMetrics
+	numPoints := 0
+	for _, s := range all {
+		res := resources[s.resourceKey()]
+		if res == nil {
+			res = newResourceMetrics(md, s.job, s.instance)
+			resources[s.resourceKey()] = res
+			resourceOrder = append(resourceOrder, res)
+		}
+
+		if family, ok := s.histogramFamily(families[s.resourceKey()]); ok {
+			res.addHistogramSeries(family, s)
+			continue
+		}
+		numPoints += res.addSeries(s)
+	}
+
+	for _, res := range resourceOrder {
+		for _, h := range res.order {
+			numPoints += res.addHistogram(h)
+		}
+	}
+	return md, numPoints, nil
+}
+
+func newResourceMetrics(md pdata.Metrics, job, instance string) *resourceMetrics {
+	rm := pdata.NewResourceMetrics()
+	attrs := rm.Resource().Attributes()
+	if job != "" {
+		attrs.InsertString(conventions.AttributeServiceName, job)
+	}
+	if instance != "" {
+		attrs.InsertString(conventions.AttributeServiceInstance, instance)
+	}
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	md.ResourceMetrics().Append(rm)
+
+	return &resourceMetrics{
+		rm:         rm,
+		metrics:    map[string]pdata.Metric{},
+		histograms: map[string]*histogram{},
+	}
+}
+
+// metric returns the metric of the given name and type, created when it doesn't exist yet
+func (res *resourceMetrics) metric(name string, dataType pdata.MetricDataType) pdata.Metric {
+	if m, ok := res.metrics[name]; ok && m.DataType() == dataType {
+		return m
+	}
+
+	m := pdata.NewMetric()
+	m.SetName(name)
+	m.SetDataType(dataType)
+	switch dataType {
+	case pdata.MetricDataTypeDoubleSum:
+		m.DoubleSum().SetIsMonotonic(true)
+		m.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	case pdata.MetricDataTypeDoubleHistogram:
+		m.DoubleHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	}
+	res.rm.InstrumentationLibraryMetrics().At(0).Metrics().Append(m)
+	res.metrics[name] = m
+	return m
+}
+
+// addSeries adds the samples of the series as the data points of a sum or gauge, and returns their number
+func (res *resourceMetrics) addSeries(s *series) int {
+	samples := make([]prompb.Sample, 0, len(s.samples))
+	for _, sample := range s.samples {
+		if !value.IsStaleNaN(sample.Value) {
+			samples = append(samples, sample)
+		}
+	}
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var dps pdata.DoubleDataPointSlice
+	if strings.HasSuffix(s.name, counterSuffix) {
+		dps = res.metric(s.name, pdata.MetricDataTypeDoubleSum).DoubleSum().DataPoints()
+	} else {
+		dps = res.metric(s.name, pdata.MetricDataTypeDoubleGauge).DoubleGauge().DataPoints()
+	}
+	for _, sample := range samples {
+		dp := pdata.NewDoubleDataPoint()
+		dp.LabelsMap().InitFromMap(s.labels)
+		dp.SetTimestamp(timestamp(sample.Timestamp))
+		dp.SetValue(sample.Value)
+		dps.Append(dp)
+	}
+	return len(samples)
+}
+
+func (res *resourceMetrics) addHistogramSeries(family string, s *series) {
+	key := s.histogramKey(family)
+	h := res.histograms[key]
+	if h == nil {
+		h = &histogram{family: family}
+		res.histograms[key] = h
+		res.order = append(res.order, h)
+	}
+	if h.labels == nil {
+		h.labels = make(map[string]string, len(s.labels))
+		for name, value := range s.labels {
+			if name != labels.BucketLabel {
+				h.labels[name] = value
+			}
+		}
+	}
+	h.series = append(h.series, s)
+}
+
+// addHistogram adds the samples of the series of the histogram as the data points of a histogram, or of
+// gauges when they aren't consistent, and returns their number
+func (res *resourceMetrics) addHistogram(h *histogram) int {
+	points, ok := h.points()
+	if !ok {
+		n := 0
+		for _, s := range h.series {
+			n += res.addSeries(s)
+		}
+		return n
+	}
+	if len(points) == 0 {
+		return 0
+	}
+
+	timestamps := make([]int64, 0, len(points))
+	for ts := range points {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	dps := res.metric(h.family, pdata.MetricDataTypeDoubleHistogram).DoubleHistogram().DataPoints()
+	for _, ts := range timestamps {
+		dp := pdata.NewDoubleHistogramDataPoint()
+		dp.LabelsMap().InitFromMap(h.labels)
+		dp.SetTimestamp(timestamp(ts))
+		points[ts].fill(dp)
+		dps.Append(dp)
+	}
+	return len(timestamps)
+}
+
+// points returns the values of the histogram at each timestamp, or false when some buckets are invalid
+func (h *histogram) points() (map[int64]*histogramPoint, bool) {
+	points := map[int64]*histogramPoint{}
+	point := func(ts int64) *histogramPoint {
+		p := points[ts]
+		if p == nil {
+			p = &histogramPoint{buckets: map[float64]float64{}}
+			points[ts] = p
+		}
+		return p
+	}
+
+	for _, s := range h.series {
+		var bound float64
+		if s.name == h.family+bucketSuffix {
+			var err error
+			if bound, err = strconv.ParseFloat(s.labels[labels.BucketLabel], 64); err != nil || math.IsNaN(bound) {
+				return nil, false
+			}
+		}
+		for _, sample := range s.samples {
+			if value.IsStaleNaN(sample.Value) {
+				continue
+			}
+			p := point(sample.Timestamp)
+			switch s.name {
+			case h.family + bucketSuffix:
+				p.buckets[bound] = sample.Value
+			case h.family + sumSuffix:
+				p.sum = sample.Value
+			case h.family + countSuffix:
+				p.count = sample.Value
+				p.hasCount = true
+			}
+		}
+	}
+
+	for _, p := range points {
+		if !p.valid() {
+			return nil, false
+		}
+	}
+	return points, true
+}
+
+// valid checks that the cumulative counts of the buckets don't decrease, and that the count of the histogram is
+// known, either as the +Inf bucket or as the _count series
+func (p *histogramPoint) valid() bool {
+	if len(p.buckets) == 0 {
+		return false
+	}
+
+	previous := 0.0
+	for _, bound := range p.bounds() {
+		if p.buckets[bound] < previous {
+			return false
+		}
+		previous = p.buckets[bound]
+	}
+	if _, ok := p.buckets[math.Inf(1)]; !ok {
+		return p.hasCount && p.count >= previous
+	}
+	return true
+}
+
+// bounds returns the upper bounds of the buckets, in increasing order
+func (p *histogramPoint) bounds() []float64 {
+	bounds := make([]float64, 0, len(p.buckets))
+	for bound := range p.buckets {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+	return bounds
+}
+
+// fill sets the count, sum and buckets of the data point, the +Inf bucket being the count when not reported
+func (p *histogramPoint) fill(dp pdata.DoubleHistogramDataPoint) {
+	bounds := p.bounds()
+	count := p.count
+	if inf, ok := p.buckets[math.Inf(1)]; ok {
+		count = inf
+		bounds = bounds[:len(bounds)-1]
+	}
+
+	bucketCounts := make([]uint64, 0, len(bounds)+1)
+	previous := 0.0
+	for _, bound := range bounds {
+		bucketCounts = append(bucketCounts, uint64(p.buckets[bound]-previous))
+		previous = p.buckets[bound]
+	}
+	bucketCounts = append(bucketCounts, uint64(count-previous))
+
+	dp.SetCount(uint64(count))
+	dp.SetSum(p.sum)
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(bucketCounts)
+}
+
+// timestamp converts a timestamp in milliseconds, the unit of the samples
+func timestamp(ms int64) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(ms * int64(1e6))
+}
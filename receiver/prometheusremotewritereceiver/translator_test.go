@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// timeSeries returns a time series of the given labels, as name and value pairs, with a sample of the
+// given value at each of the timestamps
+func timeSeries(labelPairs []string, v float64, timestamps ...int64) prompb.TimeSeries {
+	ts := prompb.TimeSeries{}
+	for i := 0; i < len(labelPairs); i += 2 {
+		ts.Labels = append(ts.Labels, prompb.Label{Name: labelPairs[i], Value: labelPairs[i+1]})
+	}
+	for _, t := range timestamps {
+		ts.Samples = append(ts.Samples, prompb.Sample{Value: v, Timestamp: t})
+	}
+	return ts
+}
+
+func TestWriteRequestToMetricsSumsAndGauges(t *testing.T) {
+	req := &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		timeSeries([]string{"__name__", "http_requests_total", "job", "api", "instance", "10.0.0.1:8080", "code", "200"}, 42, 1000, 2000),
+		timeSeries([]string{"__name__", "process_resident_memory_bytes", "job", "api", "instance", "10.0.0.1:8080"}, 1024, 1000),
+		timeSeries([]string{"__name__", "up"}, 1, 1000),
+	}}
+
+	md, numPoints, err := writeRequestToMetrics(req, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, numPoints)
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+
+	rm := md.ResourceMetrics().At(0)
+	assert.Equal(t, 2, rm.Resource().Attributes().Len())
+	job, _ := rm.Resource().Attributes().Get(conventions.AttributeServiceName)
+	assert.Equal(t, "api", job.StringVal())
+	instance, _ := rm.Resource().Attributes().Get(conventions.AttributeServiceInstance)
+	assert.Equal(t, "10.0.0.1:8080", instance.StringVal())
+
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	counter := metrics.At(0)
+	assert.Equal(t, "http_requests_total", counter.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleSum, counter.DataType())
+	assert.True(t, counter.DoubleSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, counter.DoubleSum().AggregationTemporality())
+	dps := counter.DoubleSum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, pdata.TimestampUnixNano(1000000000), dps.At(0).Timestamp())
+	assert.Equal(t, pdata.TimestampUnixNano(2000000000), dps.At(1).Timestamp())
+	assert.Equal(t, 42.0, dps.At(0).Value())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"code": "200"}), dps.At(0).LabelsMap())
+
+	gauge := metrics.At(1)
+	assert.Equal(t, "process_resident_memory_bytes", gauge.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, gauge.DataType())
+	require.Equal(t, 1, gauge.DoubleGauge().DataPoints().Len())
+	assert.Equal(t, 1024.0, gauge.DoubleGauge().DataPoints().At(0).Value())
+	assert.Equal(t, 0, gauge.DoubleGauge().DataPoints().At(0).LabelsMap().Len())
+
+	// the series without job and instance have a resource without attributes
+	rm = md.ResourceMetrics().At(1)
+	assert.Equal(t, 0, rm.Resource().Attributes().Len())
+	assert.Equal(t, "up", rm.InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
+}
+
+func histogramRequest(infBucket float64) *prompb.WriteRequest {
+	return &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		timeSeries([]string{"__name__", "request_duration_seconds_bucket", "job", "api", "le", "0.1", "path", "/"}, 2, 1000),
+		timeSeries([]string{"__name__", "request_duration_seconds_bucket", "job", "api", "le", "0.5", "path", "/"}, 5, 1000),
+		timeSeries([]string{"__name__", "request_duration_seconds_bucket", "job", "api", "le", "+Inf", "path", "/"}, infBucket, 1000),
+		timeSeries([]string{"__name__", "request_duration_seconds_sum", "job", "api", "path", "/"}, 3.5, 1000),
+		timeSeries([]string{"__name__", "request_duration_seconds_count", "job", "api", "path", "/"}, 7, 1000),
+	}}
+}
+
+func TestWriteRequestToMetricsHistograms(t *testing.T) {
+	md, numPoints, err := writeRequestToMetrics(histogramRequest(7), true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, numPoints)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	m := metrics.At(0)
+	assert.Equal(t, "request_duration_seconds", m.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleHistogram, m.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, m.DoubleHistogram().AggregationTemporality())
+
+	dps := m.DoubleHistogram().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	dp := dps.At(0)
+	assert.Equal(t, pdata.TimestampUnixNano(1000000000), dp.Timestamp())
+	assert.Equal(t, uint64(7), dp.Count())
+	assert.Equal(t, 3.5, dp.Sum())
+	assert.Equal(t, []float64{0.1, 0.5}, dp.ExplicitBounds())
+	assert.Equal(t, []uint64{2, 3, 2}, dp.BucketCounts())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"path": "/"}), dp.LabelsMap())
+}
+
+func TestWriteRequestToMetricsHistogramWithoutInfBucket(t *testing.T) {
+	req := histogramRequest(7)
+	req.Timeseries = append(req.Timeseries[:2], req.Timeseries[3:]...)
+
+	md, _, err := writeRequestToMetrics(req, true)
+	require.NoError(t, err)
+	dp := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, uint64(7), dp.Count())
+	assert.Equal(t, []float64{0.1, 0.5}, dp.ExplicitBounds())
+	assert.Equal(t, []uint64{2, 3, 2}, dp.BucketCounts())
+}
+
+func TestWriteRequestToMetricsHistogramsAsGauges(t *testing.T) {
+	tests := []struct {
+		name                  string
+		req                   *prompb.WriteRequest
+		reconstructHistograms bool
+	}{
+		{
+			name:                  "gauges",
+			req:                   histogramRequest(7),
+			reconstructHistograms: false,
+		},
+		{
+			name:                  "decreasing buckets",
+			req:                   histogramRequest(4),
+			reconstructHistograms: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, numPoints, err := writeRequestToMetrics(tt.req, tt.reconstructHistograms)
+			require.NoError(t, err)
+			assert.Equal(t, 5, numPoints)
+
+			metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			require.Equal(t, 3, metrics.Len())
+			for i, name := range []string{"request_duration_seconds_bucket", "request_duration_seconds_sum", "request_duration_seconds_count"} {
+				assert.Equal(t, name, metrics.At(i).Name())
+				assert.Equal(t, pdata.MetricDataTypeDoubleGauge, metrics.At(i).DataType())
+			}
+			dps := metrics.At(0).DoubleGauge().DataPoints()
+			require.Equal(t, 3, dps.Len())
+			le, _ := dps.At(2).LabelsMap().Get("le")
+			assert.Equal(t, "+Inf", le)
+		})
+	}
+}
+
+func TestWriteRequestToMetricsStaleMarkers(t *testing.T) {
+	req := &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		timeSeries([]string{"__name__", "up"}, math.Float64frombits(value.StaleNaN), 1000),
+		timeSeries([]string{"__name__", "temperature"}, 21.5, 1000),
+	}}
+
+	md, numPoints, err := writeRequestToMetrics(req, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, numPoints)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "temperature", metrics.At(0).Name())
+}
+
+func TestWriteRequestToMetricsWithoutName(t *testing.T) {
+	req := &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		timeSeries([]string{"job", "api"}, 1, 1000),
+	}}
+
+	_, _, err := writeRequestToMetrics(req, true)
+	assert.EqualError(t, err, "time series without the __name__ label")
+}
@@ -0,0 +1,95 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	otelconfig "go.opentelemetry.io/collector/config"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+const (
+	// discoveryAnnotationPrefix prefixes the pod annotations declaring the receiver to start against the pod ports.
+	// The annotations can be scoped to a single port by suffixing the prefix with the port number,
+	// e.g. io.opentelemetry.discovery.metrics.6379/receiver, the port annotations taking precedence over the pod ones.
+	discoveryAnnotationPrefix = "io.opentelemetry.discovery.metrics"
+	// enabledAnnotation is the annotation enabling the discovery of the pod ports.
+	enabledAnnotation = "enabled"
+	// receiverAnnotation is the annotation holding the full name of the receiver to start (ie <receiver type>/<id>).
+	receiverAnnotation = "receiver"
+	// configAnnotation is the annotation holding the YAML config of the receiver to start.
+	configAnnotation = "config"
+)
+
+// DiscoveryConfig defines the discovery of the receivers declared by the annotations of the observed pods.
+type DiscoveryConfig struct {
+	// Enabled is whether the receivers declared by the pod annotations are started.
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// annotationReceiverConfig returns the config of the receiver declared by the discovery annotations of the pod
+// owning the given port, or nil when the discovery isn't enabled for the port.
+func annotationReceiverConfig(port *observer.Port) (*receiverConfig, error) {
+	lookup := func(name string) (string, string, bool) {
+		key := fmt.Sprintf("%s.%d/%s", discoveryAnnotationPrefix, port.Port, name)
+		if value, ok := port.Pod.Annotations[key]; ok {
+			return key, value, true
+		}
+		key = discoveryAnnotationPrefix + "/" + name
+		value, ok := port.Pod.Annotations[key]
+		return key, value, ok
+	}
+
+	key, value, ok := lookup(enabledAnnotation)
+	if !ok {
+		return nil, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %q is invalid: %v", key, err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	key, value, ok = lookup(receiverAnnotation)
+	if !ok {
+		return nil, fmt.Errorf("annotation %q is missing", discoveryAnnotationPrefix+"/"+receiverAnnotation)
+	}
+	typeStr, fullName, err := otelconfig.DecodeTypeAndName(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %q is invalid: %v", key, err)
+	}
+
+	config := userConfigMap{}
+	if key, value, ok = lookup(configAnnotation); ok {
+		v := otelconfig.NewViper()
+		v.SetConfigType("yaml")
+		if err := v.ReadConfig(strings.NewReader(value)); err != nil {
+			return nil, fmt.Errorf("annotation %q is invalid: %v", key, err)
+		}
+		config = v.AllSettings()
+	}
+
+	return &receiverConfig{
+		fullName: fullName,
+		typeStr:  typeStr,
+		config:   config,
+	}, nil
+}
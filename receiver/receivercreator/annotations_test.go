@@ -0,0 +1,130 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func TestAnnotationReceiverConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *receiverConfig
+		wantErr     string
+	}{
+		{
+			name:        "no annotations",
+			annotations: map[string]string{"scrape": "true"},
+		},
+		{
+			name: "disabled",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/enabled":  "false",
+				"io.opentelemetry.discovery.metrics/receiver": "redis",
+			},
+		},
+		{
+			name: "pod annotations",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/enabled":  "true",
+				"io.opentelemetry.discovery.metrics/receiver": "redis/cache",
+				"io.opentelemetry.discovery.metrics/config":   "{collection_interval: 20s, password: secret}",
+			},
+			want: &receiverConfig{
+				fullName: "redis/cache",
+				typeStr:  "redis",
+				config:   userConfigMap{"collection_interval": "20s", "password": "secret"},
+			},
+		},
+		{
+			name: "port annotations take precedence",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/enabled":       "true",
+				"io.opentelemetry.discovery.metrics/receiver":      "redis",
+				"io.opentelemetry.discovery.metrics/config":        "password: secret",
+				"io.opentelemetry.discovery.metrics.1234/receiver": "prometheus_simple",
+				"io.opentelemetry.discovery.metrics.1234/config":   "metrics_path: /stats\nendpoint: '`endpoint`'",
+			},
+			want: &receiverConfig{
+				fullName: "prometheus_simple",
+				typeStr:  "prometheus_simple",
+				config:   userConfigMap{"metrics_path": "/stats", "endpoint": "`endpoint`"},
+			},
+		},
+		{
+			name: "disabled for the port",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/enabled":      "true",
+				"io.opentelemetry.discovery.metrics/receiver":     "redis",
+				"io.opentelemetry.discovery.metrics.1234/enabled": "false",
+			},
+		},
+		{
+			name: "invalid enabled",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/enabled": "yes",
+			},
+			wantErr: `annotation "io.opentelemetry.discovery.metrics/enabled" is invalid: strconv.ParseBool: parsing "yes": invalid syntax`,
+		},
+		{
+			name: "missing receiver",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/enabled": "true",
+			},
+			wantErr: `annotation "io.opentelemetry.discovery.metrics/receiver" is missing`,
+		},
+		{
+			name: "invalid receiver",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/enabled":  "true",
+				"io.opentelemetry.discovery.metrics/receiver": "/cache",
+			},
+			wantErr: `annotation "io.opentelemetry.discovery.metrics/receiver" is invalid: type/name key must have the type part`,
+		},
+		{
+			name: "invalid config",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/enabled":     "true",
+				"io.opentelemetry.discovery.metrics/receiver":    "redis",
+				"io.opentelemetry.discovery.metrics.1234/config": "password",
+			},
+			wantErr: `annotation "io.opentelemetry.discovery.metrics.1234/config" is invalid: While parsing config: yaml: unmarshal errors:
+  line 1: cannot unmarshal !!str ` + "`password`" + ` into map[string]interface {}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := &observer.Port{
+				Name: "http",
+				Pod:  observer.Pod{Name: "pod-1", Annotations: tt.annotations},
+				Port: 1234,
+			}
+			got, err := annotationReceiverConfig(port)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
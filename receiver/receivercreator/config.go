@@ -75,4 +75,6 @@ type Config struct {
 	receiverTemplates             map[string]receiverTemplate
 	// WatchObservers are the extensions to listen to endpoints from.
 	WatchObservers []configmodels.Type `mapstructure:"watch_observers"`
+	// Discovery defines the discovery of the receivers declared by the annotations of the observed pods.
+	Discovery DiscoveryConfig `mapstructure:"discovery"`
 }
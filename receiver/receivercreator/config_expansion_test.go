@@ -103,6 +103,16 @@ func Test_userConfigMap_resolve(t *testing.T) {
 				"endpoint": "localhost:6379",
 			}, false,
 		},
+		{
+			"nested variables", userConfigMap{
+				"service_name": "`pod.name`/`container.name`",
+			}, args{observer.EndpointEnv{
+				"pod":       map[string]interface{}{"name": "pod-1"},
+				"container": map[string]interface{}{"name": "redis"},
+			}}, map[string]interface{}{
+				"service_name": "pod-1/redis",
+			}, false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
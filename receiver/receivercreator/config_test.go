@@ -86,4 +86,5 @@ func TestLoadConfig(t *testing.T) {
 		endpointConfigKey: "localhost:12345",
 	}, r1.receiverTemplates["examplereceiver/1"].config)
 	assert.Equal(t, []configmodels.Type{"mock_observer"}, r1.WatchObservers)
+	assert.Equal(t, DiscoveryConfig{Enabled: true}, r1.Discovery)
 }
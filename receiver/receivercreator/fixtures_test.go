@@ -39,13 +39,44 @@ var portEndpoint = observer.Endpoint{
 	ID:     "port-1",
 	Target: "localhost:1234",
 	Details: &observer.Port{
-		Name:      "http",
-		Pod:       pod,
+		Name: "http",
+		Pod:  pod,
+		Container: observer.Container{
+			Name:  "redis",
+			Image: "redis:6.0",
+		},
 		Port:      1234,
 		Transport: observer.ProtocolTCP,
 	},
 }
 
+var hostPortEndpoint = observer.Endpoint{
+	ID:     "port-2",
+	Target: "localhost:6379",
+	Details: &observer.HostPort{
+		Name:      "redis-server",
+		Command:   "redis-server *:6379",
+		Port:      6379,
+		Transport: observer.ProtocolTCP,
+	},
+}
+
+var containerEndpoint = observer.Endpoint{
+	ID:     "container-1",
+	Target: "172.17.0.2:6379",
+	Details: &observer.ContainerPort{
+		Name:        "redis-1",
+		Image:       "redis:6.0",
+		ContainerID: "abc123",
+		Labels: map[string]string{
+			"service": "cache",
+		},
+		Port:      6379,
+		HostPort:  16379,
+		Transport: observer.ProtocolTCP,
+	},
+}
+
 var unsupportedEndpoint = observer.Endpoint{
 	ID:      "endpoint-1",
 	Target:  "localhost:1234",
@@ -19,6 +19,7 @@ import (
 	"sync"
 
 	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
@@ -32,6 +33,8 @@ type observerHandler struct {
 	logger *zap.Logger
 	// receiverTemplates maps receiver template full name to a receiverTemplate value.
 	receiverTemplates map[string]receiverTemplate
+	// discovery defines whether the receivers declared by the pod annotations are started.
+	discovery DiscoveryConfig
 	// receiversByEndpointID is a map of endpoint IDs to a receiver instance.
 	receiversByEndpointID receiverMap
 	// runner starts and stops receiver instances.
@@ -72,6 +75,9 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 			continue
 		}
 
+		// startedTypes tracks the receiver types started by the rules, which take precedence over the annotations.
+		startedTypes := map[configmodels.Type]bool{}
+
 		for _, template := range obs.receiverTemplates {
 			if matches, err := template.rule.eval(env); err != nil {
 				obs.logger.Error("failed matching rule", zap.String("rule", template.Rule), zap.Error(err))
@@ -80,49 +86,81 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 				continue
 			}
 
-			obs.logger.Info("starting receiver",
-				zap.String("name", template.fullName),
-				zap.String("type", string(template.typeStr)),
-				zap.String("endpoint", e.Target),
+			if obs.startReceiver(template.receiverConfig, e, env) {
+				startedTypes[template.typeStr] = true
+			}
+		}
+
+		if !obs.discovery.Enabled {
+			continue
+		}
+		port, ok := e.Details.(*observer.Port)
+		if !ok {
+			continue
+		}
+
+		annotated, err := annotationReceiverConfig(port)
+		if err != nil {
+			obs.logger.Error("invalid discovery annotations", zap.String("pod", port.Pod.Name), zap.String("endpoint_id", string(e.ID)), zap.Error(err))
+			continue
+		}
+		if annotated == nil {
+			continue
+		}
+		if startedTypes[annotated.typeStr] {
+			obs.logger.Info("ignoring discovery annotations, receiver already started by a rule",
+				zap.String("type", string(annotated.typeStr)),
 				zap.String("endpoint_id", string(e.ID)))
+			continue
+		}
 
-			resolvedConfig, err := expandMap(template.config, env)
-			if err != nil {
-				obs.logger.Error("unable to resolve template config", zap.String("receiver", template.fullName), zap.Error(err))
-				continue
-			}
+		obs.startReceiver(*annotated, e, env)
+	}
+}
 
-			discoveredConfig := userConfigMap{}
+// startReceiver starts the given receiver against the given endpoint, returning whether it was started.
+func (obs *observerHandler) startReceiver(template receiverConfig, e observer.Endpoint, env observer.EndpointEnv) bool {
+	obs.logger.Info("starting receiver",
+		zap.String("name", template.fullName),
+		zap.String("type", string(template.typeStr)),
+		zap.String("endpoint", e.Target),
+		zap.String("endpoint_id", string(e.ID)))
+
+	resolvedConfig, err := expandMap(template.config, env)
+	if err != nil {
+		obs.logger.Error("unable to resolve template config", zap.String("receiver", template.fullName), zap.Error(err))
+		return false
+	}
 
-			// If user didn't set endpoint set to default value.
-			if _, ok := resolvedConfig[endpointConfigKey]; !ok {
-				discoveredConfig[endpointConfigKey] = e.Target
-			}
+	discoveredConfig := userConfigMap{}
 
-			resolvedDiscoveredConfig, err := expandMap(discoveredConfig, env)
+	// If user didn't set endpoint set to default value.
+	if _, ok := resolvedConfig[endpointConfigKey]; !ok {
+		discoveredConfig[endpointConfigKey] = e.Target
+	}
 
-			if err != nil {
-				obs.logger.Error("unable to resolve discovered config", zap.String("receiver", template.fullName), zap.Error(err))
-				continue
-			}
+	resolvedDiscoveredConfig, err := expandMap(discoveredConfig, env)
 
-			rcvr, err := obs.runner.start(receiverConfig{
-				fullName: template.fullName,
-				typeStr:  template.typeStr,
-				config:   resolvedConfig,
-			}, resolvedDiscoveredConfig)
+	if err != nil {
+		obs.logger.Error("unable to resolve discovered config", zap.String("receiver", template.fullName), zap.Error(err))
+		return false
+	}
 
-			if err != nil {
-				obs.logger.Error("failed to start receiver", zap.String("receiver", template.fullName))
-				continue
-			}
+	rcvr, err := obs.runner.start(receiverConfig{
+		fullName: template.fullName,
+		typeStr:  template.typeStr,
+		config:   resolvedConfig,
+	}, resolvedDiscoveredConfig)
 
-			obs.receiversByEndpointID.Put(e.ID, rcvr)
-		}
+	if err != nil {
+		obs.logger.Error("failed to start receiver", zap.String("receiver", template.fullName))
+		return false
 	}
+
+	obs.receiversByEndpointID.Put(e.ID, rcvr)
+	return true
 }
 
-// OnRemove responds to endpoint removal notifications.
 func (obs *observerHandler) OnRemove(removed []observer.Endpoint) {
 	obs.Lock()
 	defer obs.Unlock()
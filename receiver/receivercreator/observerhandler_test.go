@@ -136,3 +136,81 @@ func TestDynamicConfig(t *testing.T) {
 
 	runner.AssertExpectations(t)
 }
+
+func TestOnAddAnnotations(t *testing.T) {
+	annotatedEndpoint := observer.Endpoint{
+		ID:     "port-1",
+		Target: "localhost:6379",
+		Details: &observer.Port{
+			Name: "redis",
+			Pod: observer.Pod{
+				Name: "pod-1",
+				Annotations: map[string]string{
+					"io.opentelemetry.discovery.metrics/enabled":  "true",
+					"io.opentelemetry.discovery.metrics/receiver": "name/annotated",
+					"io.opentelemetry.discovery.metrics/config":   "{collection_interval: 20s}",
+				},
+			},
+			Port:      6379,
+			Transport: observer.ProtocolTCP,
+		},
+	}
+	annotatedCfg := receiverConfig{
+		typeStr:  configmodels.Type("name"),
+		config:   userConfigMap{"collection_interval": "20s"},
+		fullName: "name/annotated",
+	}
+	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{"foo": "bar"}, fullName: "name/1"}
+
+	tests := []struct {
+		name      string
+		rule      string
+		discovery DiscoveryConfig
+		want      receiverConfig
+	}{
+		{
+			name:      "annotations",
+			rule:      `type.port && port == 1234`,
+			discovery: DiscoveryConfig{Enabled: true},
+			want:      annotatedCfg,
+		},
+		{
+			name:      "rule takes precedence",
+			rule:      `type.port && port == 6379`,
+			discovery: DiscoveryConfig{Enabled: true},
+			want:      rcvrCfg,
+		},
+		{
+			name: "discovery disabled",
+			rule: `type.port && port == 6379`,
+			want: rcvrCfg,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockRunner{}
+			rcvr := &componenttest.ExampleReceiverProducer{}
+			handler := &observerHandler{
+				logger: zap.NewNop(),
+				receiverTemplates: map[string]receiverTemplate{
+					"name/1": {rcvrCfg, tt.rule, newRuleOrPanic(tt.rule)},
+				},
+				discovery:             tt.discovery,
+				receiversByEndpointID: receiverMap{},
+				runner:                runner,
+			}
+
+			runner.On("start", tt.want, userConfigMap{endpointConfigKey: "localhost:6379"}).Return(rcvr, nil)
+			runner.On("shutdown", rcvr).Return(nil)
+
+			handler.OnAdd([]observer.Endpoint{annotatedEndpoint})
+			assert.Equal(t, 1, handler.receiversByEndpointID.Size())
+
+			handler.OnRemove([]observer.Endpoint{annotatedEndpoint})
+			assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+
+			runner.AssertExpectations(t)
+		})
+	}
+}
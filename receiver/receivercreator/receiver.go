@@ -73,6 +73,7 @@ func (rc *receiverCreator) Start(_ context.Context, host component.Host) error {
 	rc.observerHandler = observerHandler{
 		logger:                rc.params.Logger,
 		receiverTemplates:     rc.cfg.receiverTemplates,
+		discovery:             rc.cfg.Discovery,
 		receiversByEndpointID: receiverMap{},
 		runner: &receiverRunner{
 			params:       rc.params,
@@ -47,6 +47,12 @@ func Test_ruleEval(t *testing.T) {
 		{"basic port", args{`type.port && name == "http" && pod.labels["app"] == "redis"`, portEndpoint}, true, false},
 		{"basic pod", args{`type.pod && labels["region"] == "west-1"`, podEndpoint}, true, false},
 		{"annotations", args{`type.pod && annotations["scrape"] == "true"`, podEndpoint}, true, false},
+		{"container", args{`type.port && container.name == "redis" && container.image startsWith "redis:"`, portEndpoint}, true, false},
+		{"transport", args{`type.port && transport == "TCP"`, portEndpoint}, true, false},
+		{"basic hostport", args{`type.hostport && name == "redis-server" && command contains "6379"`, hostPortEndpoint}, true, false},
+		{"basic container", args{`type.container && image startsWith "redis" && labels["service"] == "cache"`, containerEndpoint}, true, false},
+		{"container host port", args{`type.container && host_port == 16379 && transport == "TCP"`, containerEndpoint}, true, false},
+		{"other type", args{`type.pod && name == "redis-server"`, hostPortEndpoint}, false, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -82,6 +88,8 @@ func Test_newRule(t *testing.T) {
 		{"does not start with type", args{"port == 1234"}, true},
 		{"invalid syntax", args{"port =="}, true},
 		{"valid", args{`type.port && port_name == "http"`}, false},
+		{"valid hostport", args{`type.hostport && port == 6379`}, false},
+		{"valid container", args{`type.container && port == 6379`}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
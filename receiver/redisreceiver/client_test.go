@@ -15,6 +15,7 @@
 package redisreceiver
 
 import (
+	"errors"
 	"io/ioutil"
 	"path"
 	"runtime"
@@ -26,12 +27,21 @@ import (
 
 var _ client = (*fakeClient)(nil)
 
-type fakeClient struct{}
+// fakeClient returns the INFO output of the given testdata file, or of
+// testdata/info.txt (Redis 5) when not set.
+type fakeClient struct {
+	infoFile string
+}
 
 func newFakeClient() *fakeClient {
 	return &fakeClient{}
 }
 
+// Returns a fake client for the INFO output of a Redis 6 replica.
+func newFakeReplicaClient() *fakeClient {
+	return &fakeClient{infoFile: "info_redis6_replica"}
+}
+
 func (c fakeClient) delimiter() string {
 	if runtime.GOOS == "windows" {
 		return "\r\n"
@@ -40,10 +50,24 @@ func (c fakeClient) delimiter() string {
 	return "\n"
 }
 
-func (fakeClient) retrieveInfo() (string, error) {
+func (c fakeClient) retrieveInfo() (string, error) {
+	if c.infoFile != "" {
+		return readFile(c.infoFile)
+	}
 	return readFile("info")
 }
 
+var _ client = (*unreachableClient)(nil)
+
+// unreachableClient fails to retrieve INFO, as when Redis is down.
+type unreachableClient struct {
+	fakeClient
+}
+
+func (unreachableClient) retrieveInfo() (string, error) {
+	return "", errors.New("dial tcp 127.0.0.1:6379: connect: connection refused")
+}
+
 func readFile(fname string) (string, error) {
 	file, err := ioutil.ReadFile(path.Join("testdata", fname+".txt"))
 	if err != nil {
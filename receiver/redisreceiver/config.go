@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 type config struct {
@@ -36,4 +37,8 @@ type config struct {
 	// Optional password. Must match the password specified in the
 	// requirepass server configuration option.
 	Password string `mapstructure:"password"`
+
+	// TLS settings to connect to Redis. Disabled by default, with insecure
+	// set to true.
+	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
 }
@@ -20,6 +20,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
@@ -43,6 +44,9 @@ func createDefaultConfig() configmodels.Receiver {
 			NameVal: typeStr,
 		},
 		CollectionInterval: 10 * time.Second,
+		TLS: configtls.TLSClientSetting{
+			Insecure: true,
+		},
 	}
 }
 
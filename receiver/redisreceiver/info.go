@@ -64,7 +64,8 @@ func (i info) buildKeyspaceProtoMetrics(t *timeBundle) (
 		key := "db" + strconv.Itoa(db)
 		str, ok := i[key]
 		if !ok {
-			break
+			// only the databases holding keys are listed, which aren't necessarily the first ones
+			continue
 		}
 		keyspace, parsingError := parseKeyspaceString(db, str)
 		if parsingError != nil {
@@ -77,6 +78,30 @@ func (i info) buildKeyspaceProtoMetrics(t *timeBundle) (
 	return protoMetrics, warnings
 }
 
+// Builds proto metrics from the replication section of Redis INFO, which are
+// only reported by replicas: e.g. "master_last_io_seconds_ago:2". Returns
+// proto metrics and parsing errors, to be treated as warnings, if there were any.
+func (i info) buildReplicationProtoMetrics(t *timeBundle) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	if i["role"] != "slave" {
+		return nil, nil
+	}
+	// the last interaction with the master is only known while the link is up:
+	// it's either missing or -1 otherwise
+	lag := replicationLag()
+	strVal, ok := i[lag.key]
+	if !ok || strVal == "-1" {
+		return nil, nil
+	}
+	protoMetric, parsingError := lag.parseMetric(strVal, t)
+	if parsingError != nil {
+		return nil, []error{parsingError}
+	}
+	return []*metricspb.Metric{protoMetric}, nil
+}
+
 func (i info) getUptimeInSeconds() (int, error) {
 	const uptimeKey = "uptime_in_seconds"
 	uptimeStr, ok := i[uptimeKey]
@@ -336,3 +336,13 @@ func masterReplOffset() *redisMetric {
 		desc:   "The server's current replication offset",
 	}
 }
+
+func replicationLag() *redisMetric {
+	return &redisMetric{
+		key:    "master_last_io_seconds_ago",
+		name:   "redis/replication/lag",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		units:  "s",
+		desc:   "Seconds since the last interaction of the replica with its master",
+	}
+}
@@ -15,6 +15,8 @@
 package redisreceiver
 
 import (
+	"time"
+
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
@@ -33,6 +35,28 @@ func newMetricsData(protoMetrics []*metricspb.Metric, serviceName string) consum
 	}
 }
 
+// Builds the metric reporting whether the last INFO query succeeded: 1 when
+// Redis could be reached, 0 otherwise.
+func buildUpMetric(up bool, now time.Time) *metricspb.Metric {
+	var val int64
+	if up {
+		val = 1
+	}
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        "redis/up",
+			Description: "Whether the Redis server could be reached",
+			Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		Timeseries: []*metricspb.TimeSeries{{
+			Points: []*metricspb.Point{{
+				Timestamp: timestamppb.New(now),
+				Value:     &metricspb.Point_Int64Value{Int64Value: val},
+			}},
+		}},
+	}
+}
+
 func buildKeyspaceTriplet(k *keyspace, t *timeBundle) []*metricspb.Metric {
 	return []*metricspb.Metric{
 		buildKeyspaceKeysMetric(k, t),
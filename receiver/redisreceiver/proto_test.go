@@ -112,6 +112,79 @@ func TestKeyspaceMetrics(t *testing.T) {
 	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 3}, metric.Timeseries[0].Points[0].Value)
 }
 
+func TestKeyspaceMetricsRedis6(t *testing.T) {
+	svc := newRedisSvc(newFakeReplicaClient())
+	info, _ := svc.info()
+	m, err := info.buildKeyspaceProtoMetrics(getDefaultTimeBundle())
+	require.Nil(t, err)
+	// db0 and db3 only, the empty databases not being listed
+	require.Equal(t, 6, len(m))
+
+	expected := []struct {
+		name string
+		db   string
+		val  int64
+	}{
+		{"redis/db/keys", "0", 12},
+		{"redis/db/expires", "0", 1},
+		{"redis/db/avg_ttl", "0", 57351},
+		{"redis/db/keys", "3", 2},
+		{"redis/db/expires", "3", 0},
+		{"redis/db/avg_ttl", "3", 0},
+	}
+	for i, e := range expected {
+		require.Equal(t, e.name, m[i].MetricDescriptor.Name)
+		require.Equal(t, e.db, m[i].Timeseries[0].LabelValues[0].Value)
+		require.Equal(t, &metricspb.Point_Int64Value{Int64Value: e.val}, m[i].Timeseries[0].Points[0].Value)
+	}
+}
+
+func TestAllMetricsRedis6(t *testing.T) {
+	svc := newRedisSvc(newFakeReplicaClient())
+	info, err := svc.info()
+	require.Nil(t, err)
+	redisMetrics := getDefaultRedisMetrics()
+	protoMetrics, warnings := info.buildFixedProtoMetrics(redisMetrics, getDefaultTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, len(redisMetrics), len(protoMetrics))
+}
+
+func TestReplicationMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeReplicaClient())
+	info, _ := svc.info()
+	m, warnings := info.buildReplicationProtoMetrics(getDefaultTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, 1, len(m))
+	require.Equal(t, "redis/replication/lag", m[0].MetricDescriptor.Name)
+	require.Equal(t, "s", m[0].MetricDescriptor.Unit)
+	requireIntPtEqual(t, 2, m[0])
+
+	// the link to the master being down
+	info["master_last_io_seconds_ago"] = "-1"
+	m, warnings = info.buildReplicationProtoMetrics(getDefaultTimeBundle())
+	require.Nil(t, warnings)
+	require.Nil(t, m)
+}
+
+func TestReplicationMetricsMaster(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, _ := svc.info()
+	m, warnings := info.buildReplicationProtoMetrics(getDefaultTimeBundle())
+	require.Nil(t, warnings)
+	require.Nil(t, m)
+}
+
+func TestUpMetric(t *testing.T) {
+	now := time.Unix(1000, 0)
+	metric := buildUpMetric(true, now)
+	require.Equal(t, "redis/up", metric.MetricDescriptor.Name)
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, metric.MetricDescriptor.Type)
+	require.Equal(t, timestamppb.New(now), metric.Timeseries[0].Points[0].Timestamp)
+	requireIntPtEqual(t, 1, metric)
+
+	requireIntPtEqual(t, 0, buildUpMetric(false, now))
+}
+
 func TestNewProtoMetric(t *testing.T) {
 	serverStartTime := timestamppb.New(time.Unix(900, 0))
 	tests := []struct {
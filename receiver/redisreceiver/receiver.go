@@ -46,9 +46,14 @@ func newRedisReceiver(
 
 // Set up and kick off the interval runner.
 func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
+	tlsConfig, err := r.config.TLS.LoadTLSConfig()
+	if err != nil {
+		return err
+	}
 	c := newRedisClient(&redis.Options{
-		Addr:     r.config.Endpoint,
-		Password: r.config.Password,
+		Addr:      r.config.Endpoint,
+		Password:  r.config.Password,
+		TLSConfig: tlsConfig,
 	})
 	redisRunnable := newRedisRunnable(ctx, c, r.config.ServiceName, r.consumer, r.logger)
 	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
@@ -63,6 +68,8 @@ func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
 }
 
 func (r *redisReceiver) Shutdown(ctx context.Context) error {
-	r.intervalRunner.Stop()
+	if r.intervalRunner != nil {
+		r.intervalRunner.Stop()
+	}
 	return nil
 }
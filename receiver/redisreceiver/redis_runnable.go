@@ -18,6 +18,7 @@ import (
 	"context"
 	"time"
 
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/internaldata"
@@ -67,7 +68,9 @@ func (r *redisRunnable) Setup() error {
 // the next consumer. First builds 'fixed' metrics (non-keyspace metrics)
 // defined at startup time. Then builds 'keyspace' metrics if there are any
 // keyspace lines returned by Redis. There should be one keyspace line per
-// active Redis database, of which there can be 16.
+// active Redis database, of which there can be 16. Finally builds the
+// replication metrics, when Redis is a replica. When Redis can't be reached,
+// only a redis/up metric set to 0 is sent.
 func (r *redisRunnable) Run() error {
 	const dataFormat = "redis"
 	const transport = "http" // todo verify this
@@ -75,6 +78,13 @@ func (r *redisRunnable) Run() error {
 
 	inf, err := r.redisSvc.info()
 	if err != nil {
+		// the receiver keeps running, reporting that Redis can't be reached until it can again
+		md := internaldata.OCToMetrics(newMetricsData(
+			[]*metricspb.Metric{buildUpMetric(false, time.Now())}, r.serviceName,
+		))
+		if consumeErr := r.metricsConsumer.ConsumeMetrics(r.ctx, md); consumeErr != nil {
+			r.logger.Error("failed to consume the redis/up metric", zap.Error(consumeErr))
+		}
 		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, err)
 		return nil
 	}
@@ -92,6 +102,7 @@ func (r *redisRunnable) Run() error {
 	}
 
 	metrics, warnings := inf.buildFixedProtoMetrics(r.redisMetrics, r.timeBundle)
+	metrics = append(metrics, buildUpMetric(true, r.timeBundle.current))
 	if warnings != nil {
 		r.logger.Warn(
 			"errors parsing redis string",
@@ -108,6 +119,15 @@ func (r *redisRunnable) Run() error {
 		)
 	}
 
+	replicationMetrics, warnings := inf.buildReplicationProtoMetrics(r.timeBundle)
+	metrics = append(metrics, replicationMetrics...)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing replication string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
+
 	md := internaldata.OCToMetrics(newMetricsData(metrics, r.serviceName))
 
 	err = r.metricsConsumer.ConsumeMetrics(r.ctx, md)
@@ -31,6 +31,29 @@ func TestRedisRunnable(t *testing.T) {
 	require.Nil(t, err)
 	err = runner.Run()
 	require.Nil(t, err)
-	// + 6 because there are two keyspace entries each of which has three metrics
-	require.Equal(t, len(getDefaultRedisMetrics())+6, consumer.MetricsCount())
+	// + 6 because there are two keyspace entries each of which has three metrics,
+	// + 1 for redis/up
+	require.Equal(t, len(getDefaultRedisMetrics())+7, consumer.MetricsCount())
+}
+
+func TestRedisRunnableReplica(t *testing.T) {
+	consumer := new(consumertest.MetricsSink)
+	runner := newRedisRunnable(context.Background(), newFakeReplicaClient(), "", consumer, zap.NewNop())
+	require.Nil(t, runner.Setup())
+	require.Nil(t, runner.Run())
+	// + 6 for the two keyspace entries, + 1 for redis/up and + 1 for the replication lag
+	require.Equal(t, len(getDefaultRedisMetrics())+8, consumer.MetricsCount())
+}
+
+func TestRedisRunnableUnreachable(t *testing.T) {
+	consumer := new(consumertest.MetricsSink)
+	runner := newRedisRunnable(context.Background(), unreachableClient{}, "", consumer, zap.NewNop())
+	require.Nil(t, runner.Setup())
+	// a connection failure doesn't stop the receiver
+	require.Nil(t, runner.Run())
+	require.Equal(t, 1, consumer.MetricsCount())
+
+	metric := consumer.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	require.Equal(t, "redis/up", metric.Name())
+	require.Equal(t, int64(0), metric.IntGauge().DataPoints().At(0).Value())
 }
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"sync"
@@ -46,17 +47,18 @@ const (
 
 	responseOK                      = "OK"
 	responseInvalidMethod           = "Only \"POST\" method is supported"
-	responseInvalidContentType      = "\"Content-Type\" must be \"application/x-protobuf\""
+	responseInvalidContentType      = "\"Content-Type\" must be \"application/x-protobuf\" or \"application/json\""
 	responseInvalidEncoding         = "\"Content-Encoding\" must be \"gzip\" or empty"
 	responseErrGzipReader           = "Error on gzip body"
 	responseErrReadBody             = "Failed to read message body"
 	responseErrUnmarshalBody        = "Failed to unmarshal message body"
-	responseErrNextConsumer         = "Internal Server Error"
+	responseErrNextConsumer         = "Service Unavailable"
 	responseErrLogsNotConfigured    = "Log pipeline has not been configured to handle events"
 	responseErrMetricsNotConfigured = "Metric pipeline has not been configured to handle datapoints"
 
 	// Centralizing some HTTP and related string constants.
 	protobufContentType       = "application/x-protobuf"
+	jsonContentType           = "application/json"
 	gzipEncoding              = "gzip"
 	httpContentTypeHeader     = "Content-Type"
 	httpContentEncodingHeader = "Content-Encoding"
@@ -177,21 +179,24 @@ func (r *sfxReceiver) Shutdown(context.Context) error {
 	return err
 }
 
-func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, req *http.Request) ([]byte, bool) {
+// readBody returns the body of the request along with its content type, either protobuf or JSON
+func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, req *http.Request) ([]byte, string, bool) {
 	if req.Method != http.MethodPost {
 		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
-	if req.Header.Get(httpContentTypeHeader) != protobufContentType {
+	// the content type may have parameters, e.g. the charset of the JSON payloads
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get(httpContentTypeHeader))
+	if contentType != protobufContentType && contentType != jsonContentType {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
 	encoding := req.Header.Get(httpContentEncodingHeader)
 	if encoding != "" && encoding != gzipEncoding {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
 	bodyReader := req.Body
@@ -200,21 +205,23 @@ func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, re
 		bodyReader, err = gzip.NewReader(bodyReader)
 		if err != nil {
 			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
-			return nil, false
+			return nil, "", false
 		}
 	}
 
 	body, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
-		return nil, false
+		return nil, "", false
 	}
-	return body, true
+	return body, contentType, true
 }
 
+// writeResponse answers the request once consumed, the data rejected by the next consumer being
+// answered as unavailable for the senders to retry
 func (r *sfxReceiver) writeResponse(ctx context.Context, resp http.ResponseWriter, err error) {
 	if err != nil {
-		r.failRequest(ctx, resp, http.StatusInternalServerError, errNextConsumerRespBody, err)
+		r.failRequest(ctx, resp, http.StatusServiceUnavailable, errNextConsumerRespBody, err)
 		return
 	}
 
@@ -236,13 +243,17 @@ func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Req
 		return
 	}
 
-	body, ok := r.readBody(ctx, resp, req)
+	body, contentType, ok := r.readBody(ctx, resp, req)
 	if !ok {
 		return
 	}
 
 	msg := &sfxpb.DataPointUploadMessage{}
-	if err := msg.Unmarshal(body); err != nil {
+	unmarshal := msg.Unmarshal
+	if contentType == jsonContentType {
+		unmarshal = func(body []byte) error { return unmarshalJSONDataPoints(body, msg) }
+	}
+	if err := unmarshal(body); err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
@@ -289,13 +300,17 @@ func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request
 		return
 	}
 
-	body, ok := r.readBody(ctx, resp, req)
+	body, contentType, ok := r.readBody(ctx, resp, req)
 	if !ok {
 		return
 	}
 
 	msg := &sfxpb.EventUploadMessage{}
-	if err := msg.Unmarshal(body); err != nil {
+	unmarshal := msg.Unmarshal
+	if contentType == jsonContentType {
+		unmarshal = func(body []byte) error { return unmarshalJSONEvents(body, msg) }
+	}
+	if err := unmarshal(body); err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
@@ -361,8 +376,11 @@ func (r *sfxReceiver) failRequest(
 	traceStatus := trace.Status{
 		Code: trace.StatusCodeInvalidArgument,
 	}
-	if httpStatusCode == http.StatusInternalServerError {
+	switch httpStatusCode {
+	case http.StatusInternalServerError:
 		traceStatus.Code = trace.StatusCodeInternal
+	case http.StatusServiceUnavailable:
+		traceStatus.Code = trace.StatusCodeUnavailable
 	}
 	if err != nil {
 		traceStatus.Message = err.Error()
@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -202,6 +203,7 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 		name             string
 		req              *http.Request
 		skipRegistration bool
+		consumeErr       error
 		assertResponse   func(t *testing.T, status int, body string)
 	}{
 		{
@@ -325,6 +327,47 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 				assert.Equal(t, responseOK, body)
 			},
 		},
+		{
+			name: "json_msg_accepted",
+			req: func() *http.Request {
+				body := `{"gauge":[{"metric":"cpu.utilization","value":13.5,"dimensions":{"host":"h1"},"timestamp":1000}],` +
+					`"counter":[{"metric":"requests","value":3}]}`
+				req := httptest.NewRequest("POST", "http://localhost", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json; charset=utf-8")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusOK, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "bad_json_in_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", strings.NewReader(`{"histogram":[]}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
+		{
+			name: "next_consumer_error",
+			req: func() *http.Request {
+				msgBytes, err := sFxMsg.Marshal()
+				require.NoError(t, err)
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(msgBytes))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			consumeErr: errors.New("consumer error"),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusServiceUnavailable, status)
+				assert.Equal(t, responseErrNextConsumer, body)
+			},
+		},
 		{
 			name: "bad_gzipped_msg",
 			req: func() *http.Request {
@@ -346,6 +389,7 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sink := new(consumertest.MetricsSink)
+			sink.SetConsumeError(tt.consumeErr)
 			rcv := newReceiver(zap.NewNop(), *config)
 			if !tt.skipRegistration {
 				rcv.RegisterMetricsConsumer(sink)
@@ -377,6 +421,7 @@ func Test_sfxReceiver_handleEventReq(t *testing.T) {
 		name             string
 		req              *http.Request
 		skipRegistration bool
+		consumeErr       error
 		assertResponse   func(t *testing.T, status int, body string)
 	}{
 		{
@@ -500,6 +545,47 @@ func Test_sfxReceiver_handleEventReq(t *testing.T) {
 				assert.Equal(t, responseOK, body)
 			},
 		},
+		{
+			name: "json_msg_accepted",
+			req: func() *http.Request {
+				body := `[{"category":"ALERT","eventType":"deployment","dimensions":{"host":"h1"},` +
+					`"properties":{"version":"1.2.3","canary":true},"timestamp":1000}]`
+				req := httptest.NewRequest("POST", "http://localhost", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json; charset=utf-8")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusOK, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "bad_json_in_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", strings.NewReader(`[{"category":"UNKNOWN","eventType":"deployment"}]`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
+		{
+			name: "next_consumer_error",
+			req: func() *http.Request {
+				msgBytes, err := sFxMsg.Marshal()
+				require.NoError(t, err)
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(msgBytes))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			consumeErr: errors.New("consumer error"),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusServiceUnavailable, status)
+				assert.Equal(t, responseErrNextConsumer, body)
+			},
+		},
 		{
 			name: "bad_gzipped_msg",
 			req: func() *http.Request {
@@ -521,6 +607,7 @@ func Test_sfxReceiver_handleEventReq(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sink := new(consumertest.LogsSink)
+			sink.SetConsumeError(tt.consumeErr)
 			rcv := newReceiver(zap.NewNop(), *config)
 			if !tt.skipRegistration {
 				rcv.RegisterLogsConsumer(sink)
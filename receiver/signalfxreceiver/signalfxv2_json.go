@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+// jsonMetricTypes are the metric types of the SignalFx JSON datapoints, keying the datapoints of each type
+var jsonMetricTypes = map[string]sfxpb.MetricType{
+	"gauge":              sfxpb.MetricType_GAUGE,
+	"counter":            sfxpb.MetricType_COUNTER,
+	"cumulative_counter": sfxpb.MetricType_CUMULATIVE_COUNTER,
+}
+
+// jsonDataPoint is a datapoint of the SignalFx JSON format, whose value is either a number or a string
+type jsonDataPoint struct {
+	Metric     string            `json:"metric"`
+	Value      interface{}       `json:"value"`
+	Dimensions map[string]string `json:"dimensions"`
+	Timestamp  int64             `json:"timestamp"`
+}
+
+// jsonEvent is an event of the SignalFx JSON format
+type jsonEvent struct {
+	Category   string                 `json:"category"`
+	EventType  string                 `json:"eventType"`
+	Dimensions map[string]string      `json:"dimensions"`
+	Properties map[string]interface{} `json:"properties"`
+	Timestamp  int64                  `json:"timestamp"`
+}
+
+// unmarshalJSONDataPoints unmarshals the datapoints of the SignalFx JSON format, keyed by metric type,
+// into the given message
+func unmarshalJSONDataPoints(body []byte, msg *sfxpb.DataPointUploadMessage) error {
+	var dataPointsByType map[string][]*jsonDataPoint
+	if err := newJSONDecoder(body).Decode(&dataPointsByType); err != nil {
+		return err
+	}
+
+	// the metric types are iterated in order for the datapoints to be translated deterministically
+	types := make([]string, 0, len(dataPointsByType))
+	for t := range dataPointsByType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		metricType, ok := jsonMetricTypes[t]
+		if !ok {
+			return fmt.Errorf("unsupported metric type %q", t)
+		}
+		for _, dp := range dataPointsByType[t] {
+			if dp == nil {
+				continue
+			}
+			value, err := jsonDatum(dp.Value)
+			if err != nil {
+				return fmt.Errorf("invalid value of datapoint %q: %w", dp.Metric, err)
+			}
+			msg.Datapoints = append(msg.Datapoints, &sfxpb.DataPoint{
+				Metric:     dp.Metric,
+				Timestamp:  dp.Timestamp,
+				Value:      value,
+				MetricType: metricType.Enum(),
+				Dimensions: jsonDimensions(dp.Dimensions),
+			})
+		}
+	}
+	return nil
+}
+
+// unmarshalJSONEvents unmarshals the events of the SignalFx JSON format into the given message. The
+// events without category are user defined ones.
+func unmarshalJSONEvents(body []byte, msg *sfxpb.EventUploadMessage) error {
+	var events []*jsonEvent
+	if err := newJSONDecoder(body).Decode(&events); err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		if e == nil {
+			continue
+		}
+		category := sfxpb.EventCategory_USER_DEFINED
+		if e.Category != "" {
+			value, ok := sfxpb.EventCategory_value[e.Category]
+			if !ok {
+				return fmt.Errorf("unsupported category %q of event %q", e.Category, e.EventType)
+			}
+			category = sfxpb.EventCategory(value)
+		}
+
+		properties := make([]*sfxpb.Property, 0, len(e.Properties))
+		for _, k := range sortedKeys(e.Properties) {
+			value, err := jsonPropertyValue(e.Properties[k])
+			if err != nil {
+				return fmt.Errorf("invalid property %q of event %q: %w", k, e.EventType, err)
+			}
+			properties = append(properties, &sfxpb.Property{Key: k, Value: value})
+		}
+
+		msg.Events = append(msg.Events, &sfxpb.Event{
+			EventType:  e.EventType,
+			Dimensions: jsonDimensions(e.Dimensions),
+			Properties: properties,
+			Category:   category.Enum(),
+			Timestamp:  e.Timestamp,
+		})
+	}
+	return nil
+}
+
+// newJSONDecoder returns a decoder keeping the numbers as is, for the integers to be told apart from the doubles
+func newJSONDecoder(body []byte) *json.Decoder {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	return dec
+}
+
+// jsonDatum returns the value of a datapoint, the integral numbers being kept as integers
+func jsonDatum(value interface{}) (sfxpb.Datum, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return sfxpb.Datum{IntValue: &i}, nil
+		}
+		d, err := v.Float64()
+		if err != nil {
+			return sfxpb.Datum{}, err
+		}
+		return sfxpb.Datum{DoubleValue: &d}, nil
+	case string:
+		return sfxpb.Datum{StrValue: &v}, nil
+	default:
+		return sfxpb.Datum{}, fmt.Errorf("unsupported value %v", value)
+	}
+}
+
+// jsonPropertyValue returns the value of the given property, the null properties being kept without value
+func jsonPropertyValue(value interface{}) (*sfxpb.PropertyValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return &sfxpb.PropertyValue{}, nil
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return &sfxpb.PropertyValue{IntValue: &i}, nil
+		}
+		d, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &sfxpb.PropertyValue{DoubleValue: &d}, nil
+	case string:
+		return &sfxpb.PropertyValue{StrValue: &v}, nil
+	case bool:
+		return &sfxpb.PropertyValue{BoolValue: &v}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value %v", value)
+	}
+}
+
+func jsonDimensions(dimensions map[string]string) []*sfxpb.Dimension {
+	if len(dimensions) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(dimensions))
+	for k := range dimensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	dims := make([]*sfxpb.Dimension, 0, len(keys))
+	for _, k := range keys {
+		dims = append(dims, &sfxpb.Dimension{Key: k, Value: dimensions[k]})
+	}
+	return dims
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_unmarshalJSONDataPoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []*sfxpb.DataPoint
+		wantErr string
+	}{
+		{
+			name: "all_types",
+			body: `{
+				"gauge": [{"metric": "cpu.utilization", "value": 13.5, "dimensions": {"k1": "v1", "k0": "v0"}, "timestamp": 1000}],
+				"counter": [{"metric": "requests", "value": 3, "timestamp": 2000}],
+				"cumulative_counter": [{"metric": "bytes", "value": 9007199254740993}, {"metric": "state", "value": "running"}]
+			}`,
+			want: []*sfxpb.DataPoint{
+				{
+					Metric:     "requests",
+					Timestamp:  2000,
+					Value:      sfxpb.Datum{IntValue: int64Ptr(3)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_COUNTER),
+				},
+				{
+					Metric:     "bytes",
+					Value:      sfxpb.Datum{IntValue: int64Ptr(9007199254740993)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+				},
+				{
+					Metric:     "state",
+					Value:      sfxpb.Datum{StrValue: strPtr("running")},
+					MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+				},
+				{
+					Metric:     "cpu.utilization",
+					Timestamp:  1000,
+					Value:      sfxpb.Datum{DoubleValue: float64Ptr(13.5)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+					Dimensions: buildNDimensions(2),
+				},
+			},
+		},
+		{
+			name:    "unsupported_metric_type",
+			body:    `{"histogram": [{"metric": "latency", "value": 1}]}`,
+			wantErr: `unsupported metric type "histogram"`,
+		},
+		{
+			name:    "unsupported_value",
+			body:    `{"gauge": [{"metric": "up", "value": true}]}`,
+			wantErr: `invalid value of datapoint "up": unsupported value true`,
+		},
+		{
+			name:    "invalid_json",
+			body:    `[1, 2, 3]`,
+			wantErr: "json: cannot unmarshal array into Go value of type map[string][]*signalfxreceiver.jsonDataPoint",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &sfxpb.DataPointUploadMessage{}
+			err := unmarshalJSONDataPoints([]byte(tt.body), msg)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, msg.Datapoints)
+		})
+	}
+}
+
+func Test_unmarshalJSONEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []*sfxpb.Event
+		wantErr string
+	}{
+		{
+			name: "events",
+			body: `[
+				{"eventType": "shutdown", "dimensions": {"k0": "v0"}, "timestamp": 1000},
+				{
+					"category": "ALERT",
+					"eventType": "deployment",
+					"properties": {"version": "1.2.3", "canary": true, "replicas": 3, "ratio": 0.5, "owner": null},
+					"timestamp": 2000
+				}
+			]`,
+			want: []*sfxpb.Event{
+				{
+					EventType:  "shutdown",
+					Dimensions: buildNDimensions(1),
+					Properties: []*sfxpb.Property{},
+					Category:   sfxCategoryPtr(sfxpb.EventCategory_USER_DEFINED),
+					Timestamp:  1000,
+				},
+				{
+					EventType: "deployment",
+					Properties: mapToEventProps(map[string]interface{}{
+						"version":  "1.2.3",
+						"canary":   true,
+						"replicas": 3,
+						"ratio":    0.5,
+						"owner":    nil,
+					}),
+					Category:  sfxCategoryPtr(sfxpb.EventCategory_ALERT),
+					Timestamp: 2000,
+				},
+			},
+		},
+		{
+			name:    "unsupported_category",
+			body:    `[{"category": "UNKNOWN", "eventType": "deployment"}]`,
+			wantErr: `unsupported category "UNKNOWN" of event "deployment"`,
+		},
+		{
+			name:    "unsupported_property",
+			body:    `[{"eventType": "deployment", "properties": {"hosts": ["h1"]}}]`,
+			wantErr: `invalid property "hosts" of event "deployment": unsupported value [h1]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &sfxpb.EventUploadMessage{}
+			err := unmarshalJSONEvents([]byte(tt.body), msg)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, msg.Events)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// channelAcks are the acks of the data received on a channel
+type channelAcks struct {
+	// nextID is the id of the next data received on the channel
+	nextID uint64
+	// acked are the ids of the data accepted by the next consumer, which weren't queried yet
+	acked map[uint64]bool
+	// oldest is the lowest id which may still be acked
+	oldest uint64
+
+	// lastSeen is the time the channel was last used, for the expiry of the channels not used anymore
+	lastSeen time.Time
+}
+
+// ackTracker tracks the acks of the data received on each channel until they're queried. The
+// number of channels and of acks per channel are bounded, the least recently used ones being
+// forgotten first, and the channels not used for longer than the TTL expire.
+type ackTracker struct {
+	sync.Mutex
+	maxAcksPerChannel int
+	channelTTL        time.Duration
+	now               func() time.Time
+
+	// channels holds the acks of each channel, keyed by the channel, with the least recently used
+	// channels being the first ones to be evicted
+	channels *lru.Cache
+}
+
+func newAckTracker(cfg AckConfig) (*ackTracker, error) {
+	channels, err := lru.New(cfg.MaxChannels)
+	if err != nil {
+		return nil, err
+	}
+	return &ackTracker{
+		maxAcksPerChannel: cfg.MaxAcksPerChannel,
+		channelTTL:        cfg.ChannelTTL,
+		now:               time.Now,
+		channels:          channels,
+	}, nil
+}
+
+// ack records the data received on the given channel as accepted by the next consumer, returning
+// its ack id
+func (t *ackTracker) ack(channel string) uint64 {
+	t.Lock()
+	defer t.Unlock()
+
+	acks := t.channel(channel, true)
+	id := acks.nextID
+	acks.nextID++
+	acks.acked[id] = true
+
+	// the ids are increasing, the oldest acks being the ones with the lowest ids
+	for len(acks.acked) > t.maxAcksPerChannel {
+		delete(acks.acked, acks.oldest)
+		acks.oldest++
+	}
+	return id
+}
+
+// query returns whether the data of the given ack ids, received on the given channel, was accepted
+// by the next consumer. The acks are forgotten once queried.
+func (t *ackTracker) query(channel string, ids []uint64) map[uint64]bool {
+	t.Lock()
+	defer t.Unlock()
+
+	acks := t.channel(channel, false)
+	statuses := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		if acks != nil && acks.acked[id] {
+			statuses[id] = true
+			delete(acks.acked, id)
+		} else {
+			statuses[id] = false
+		}
+	}
+	return statuses
+}
+
+// channel returns the acks of the given channel, creating them if requested. The channels which
+// expired are forgotten first.
+func (t *ackTracker) channel(channel string, create bool) *channelAcks {
+	now := t.now()
+	t.expireStaleChannels(now)
+
+	if acks, ok := t.channels.Get(channel); ok {
+		acks.(*channelAcks).lastSeen = now
+		return acks.(*channelAcks)
+	}
+	if !create {
+		return nil
+	}
+	acks := &channelAcks{acked: make(map[uint64]bool), lastSeen: now}
+	t.channels.Add(channel, acks)
+	return acks
+}
+
+// expireStaleChannels forgets the channels which haven't been used for longer than the TTL. As the
+// channels are refreshed on each use, the stale ones are always the least recently used.
+func (t *ackTracker) expireStaleChannels(now time.Time) {
+	for {
+		_, oldest, ok := t.channels.GetOldest()
+		if !ok || now.Sub(oldest.(*channelAcks).lastSeen) <= t.channelTTL {
+			return
+		}
+		t.channels.RemoveOldest()
+	}
+}
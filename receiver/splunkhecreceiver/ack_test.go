@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAckTracker(t *testing.T, maxChannels int, maxAcksPerChannel int) (*ackTracker, *time.Time) {
+	tracker, err := newAckTracker(AckConfig{
+		MaxChannels:       maxChannels,
+		MaxAcksPerChannel: maxAcksPerChannel,
+		ChannelTTL:        time.Minute,
+	})
+	require.NoError(t, err)
+	now := time.Unix(1600000000, 0)
+	tracker.now = func() time.Time { return now }
+	return tracker, &now
+}
+
+func TestAckTracker(t *testing.T) {
+	tracker, _ := newTestAckTracker(t, 10, 10)
+
+	assert.Equal(t, uint64(0), tracker.ack("channel-1"))
+	assert.Equal(t, uint64(1), tracker.ack("channel-1"))
+	assert.Equal(t, uint64(0), tracker.ack("channel-2"))
+
+	assert.Equal(t, map[uint64]bool{0: true, 1: true, 2: false}, tracker.query("channel-1", []uint64{0, 1, 2}))
+	// the acks are forgotten once queried
+	assert.Equal(t, map[uint64]bool{0: false, 1: false}, tracker.query("channel-1", []uint64{0, 1}))
+	assert.Equal(t, uint64(2), tracker.ack("channel-1"))
+
+	assert.Equal(t, map[uint64]bool{0: true}, tracker.query("channel-2", []uint64{0}))
+	assert.Equal(t, map[uint64]bool{0: false}, tracker.query("unknown", []uint64{0}))
+}
+
+func TestAckTrackerMaxAcksPerChannel(t *testing.T) {
+	tracker, _ := newTestAckTracker(t, 10, 2)
+
+	for i := 0; i < 4; i++ {
+		tracker.ack("channel")
+	}
+	// the oldest acks are forgotten first
+	assert.Equal(t, map[uint64]bool{0: false, 1: false, 2: true, 3: true},
+		tracker.query("channel", []uint64{0, 1, 2, 3}))
+}
+
+func TestAckTrackerMaxChannels(t *testing.T) {
+	tracker, _ := newTestAckTracker(t, 2, 10)
+
+	tracker.ack("channel-1")
+	tracker.ack("channel-2")
+	// the query refreshes the first channel, the second one being the least recently used
+	tracker.query("channel-1", nil)
+	tracker.ack("channel-3")
+
+	assert.Equal(t, map[uint64]bool{0: true}, tracker.query("channel-1", []uint64{0}))
+	assert.Equal(t, map[uint64]bool{0: false}, tracker.query("channel-2", []uint64{0}))
+	assert.Equal(t, map[uint64]bool{0: true}, tracker.query("channel-3", []uint64{0}))
+}
+
+func TestAckTrackerChannelTTL(t *testing.T) {
+	tracker, now := newTestAckTracker(t, 10, 10)
+
+	tracker.ack("channel-1")
+	*now = now.Add(45 * time.Second)
+	tracker.ack("channel-2")
+	*now = now.Add(30 * time.Second)
+
+	// the first channel hasn't been used for longer than the TTL
+	assert.Equal(t, map[uint64]bool{0: false}, tracker.query("channel-1", []uint64{0}))
+	assert.Equal(t, map[uint64]bool{0: true}, tracker.query("channel-2", []uint64{0}))
+	assert.Equal(t, 1, tracker.channels.Len())
+}
@@ -15,6 +15,8 @@
 package splunkhecreceiver
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 
@@ -24,6 +26,15 @@ import (
 const (
 	// hecPath is the default HEC path on the Splunk instance.
 	hecPath = "/services/collector"
+	// hecRawPath is the HEC path of the raw data, sent as is rather than as events.
+	hecRawPath = "/services/collector/raw"
+	// hecAckPath is the HEC path of the indexer acknowledgement queries.
+	hecAckPath = "/services/collector/ack"
+
+	// The ways of splitting the raw data into log records.
+	rawSplittingAuto = "auto"
+	rawSplittingLine = "line"
+	rawSplittingNone = "none"
 )
 
 // Config defines configuration for the SignalFx receiver.
@@ -32,4 +43,27 @@ type Config struct {
 	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
+
+	// RawSplitting is how the data sent to the raw endpoint is split into log records: "auto" for a
+	// record per line, the indented lines being continuations of the previous one, "line" for a
+	// record per line or "none" for a single record per request.
+	RawSplitting string `mapstructure:"raw_splitting"`
+
+	// Ack configures the indexer acknowledgement of the data received on a channel.
+	Ack AckConfig `mapstructure:"ack"`
+}
+
+// AckConfig defines the configuration of the indexer acknowledgement.
+type AckConfig struct {
+	// Enabled returns an ack id for the data received on a channel, which can be queried once the
+	// data is accepted by the next consumer.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxChannels is the maximum number of channels tracked, the least recently used ones being
+	// forgotten first.
+	MaxChannels int `mapstructure:"max_channels"`
+	// MaxAcksPerChannel is the maximum number of acks not queried yet tracked per channel, the
+	// oldest ones being forgotten first.
+	MaxAcksPerChannel int `mapstructure:"max_acks_per_channel"`
+	// ChannelTTL is the time after which a channel which isn't used is forgotten.
+	ChannelTTL time.Duration `mapstructure:"channel_ttl"`
 }
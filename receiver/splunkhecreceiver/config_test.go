@@ -17,6 +17,7 @@ package splunkhecreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -60,6 +61,13 @@ func TestLoadConfig(t *testing.T) {
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: true,
 			},
+			RawSplitting: "line",
+			Ack: AckConfig{
+				Enabled:           true,
+				MaxChannels:       100,
+				MaxAcksPerChannel: 500,
+				ChannelTTL:        5 * time.Minute,
+			},
 		})
 
 	r2 := cfg.Receivers["splunk_hec/tls"].(*Config)
@@ -81,5 +89,11 @@ func TestLoadConfig(t *testing.T) {
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: false,
 			},
+			RawSplitting: "auto",
+			Ack: AckConfig{
+				MaxChannels:       1000,
+				MaxAcksPerChannel: 1000,
+				ChannelTTL:        10 * time.Minute,
+			},
 		})
 }
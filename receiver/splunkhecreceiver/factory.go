@@ -16,9 +16,11 @@ package splunkhecreceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configerror"
@@ -38,6 +40,10 @@ const (
 
 	// Default endpoints to bind to.
 	defaultEndpoint = ":8088"
+
+	defaultMaxChannels       = 1000
+	defaultMaxAcksPerChannel = 1000
+	defaultChannelTTL        = 10 * time.Minute
 )
 
 // NewFactory creates a factory for SignalFx receiver.
@@ -61,6 +67,12 @@ func createDefaultConfig() configmodels.Receiver {
 			Endpoint: defaultEndpoint,
 		},
 		AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{},
+		RawSplitting:                 rawSplittingAuto,
+		Ack: AckConfig{
+			MaxChannels:       defaultMaxChannels,
+			MaxAcksPerChannel: defaultMaxAcksPerChannel,
+			ChannelTTL:        defaultChannelTTL,
+		},
 	}
 }
 
@@ -81,10 +93,31 @@ func extractPortFromEndpoint(endpoint string) (int, error) {
 	return int(port), nil
 }
 
-// verify that the configured port is not 0
+// verify that the configured port is not 0, and that the raw splitting and ack settings are valid
 func (rCfg *Config) validate() error {
-	_, err := extractPortFromEndpoint(rCfg.Endpoint)
-	return err
+	if _, err := extractPortFromEndpoint(rCfg.Endpoint); err != nil {
+		return err
+	}
+
+	switch rCfg.RawSplitting {
+	case rawSplittingAuto, rawSplittingLine, rawSplittingNone:
+	default:
+		return fmt.Errorf("invalid raw_splitting %q, must be one of %q, %q or %q",
+			rCfg.RawSplitting, rawSplittingAuto, rawSplittingLine, rawSplittingNone)
+	}
+
+	if rCfg.Ack.Enabled {
+		if rCfg.Ack.MaxChannels <= 0 {
+			return errors.New("ack.max_channels must be positive")
+		}
+		if rCfg.Ack.MaxAcksPerChannel <= 0 {
+			return errors.New("ack.max_acks_per_channel must be positive")
+		}
+		if rCfg.Ack.ChannelTTL <= 0 {
+			return errors.New("ack.channel_ttl must be positive")
+		}
+	}
+	return nil
 }
 
 // CreateTracesReceiver creates a trace receiver based on provided config.
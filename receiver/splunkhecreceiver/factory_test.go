@@ -93,6 +93,52 @@ func TestValidateBadEndpoint(t *testing.T) {
 	assert.EqualError(t, err, "endpoint port is not a number: strconv.ParseInt: parsing \"abr\": invalid syntax")
 }
 
+func TestValidateInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "invalid raw splitting",
+			modify: func(cfg *Config) { cfg.RawSplitting = "sentence" },
+			err:    `invalid raw_splitting "sentence", must be one of "auto", "line" or "none"`,
+		},
+		{
+			name: "no max channels",
+			modify: func(cfg *Config) {
+				cfg.Ack.Enabled = true
+				cfg.Ack.MaxChannels = 0
+			},
+			err: "ack.max_channels must be positive",
+		},
+		{
+			name: "no max acks per channel",
+			modify: func(cfg *Config) {
+				cfg.Ack.Enabled = true
+				cfg.Ack.MaxAcksPerChannel = -1
+			},
+			err: "ack.max_acks_per_channel must be positive",
+		},
+		{
+			name: "no channel ttl",
+			modify: func(cfg *Config) {
+				cfg.Ack.Enabled = true
+				cfg.Ack.ChannelTTL = 0
+			},
+			err: "ack.channel_ttl must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			tt.modify(config)
+			assert.EqualError(t, config.validate(), tt.err)
+		})
+	}
+}
+
 func TestCreateNilNextConsumerMetrics(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Endpoint = "localhost:1"
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"sync"
@@ -49,12 +50,16 @@ const (
 	responseErrInternalServerError    = "Internal Server Error"
 	responseErrUnsupportedMetricEvent = "Unsupported metric event"
 	responseErrUnsupportedLogEvent    = "Unsupported log event"
+	responseErrAckDisabled            = "ACK is disabled"
+	responseErrMissingChannel         = "Data channel is missing"
+	responseSuccess                   = "Success"
 
 	// Centralizing some HTTP and related string constants.
 	jsonContentType           = "application/json"
 	gzipEncoding              = "gzip"
 	httpContentTypeHeader     = "Content-Type"
 	httpContentEncodingHeader = "Content-Encoding"
+	httpChannelHeader         = "X-Splunk-Request-Channel"
 )
 
 var (
@@ -71,8 +76,29 @@ var (
 	errInternalServerError    = initJSONResponse(responseErrInternalServerError)
 	errUnsupportedMetricEvent = initJSONResponse(responseErrUnsupportedMetricEvent)
 	errUnsupportedLogEvent    = initJSONResponse(responseErrUnsupportedLogEvent)
+	errAckDisabled            = initJSONResponse(responseErrAckDisabled)
+	errMissingChannel         = initJSONResponse(responseErrMissingChannel)
 )
 
+// ackIDResponse is the response to the data received on a channel when the indexer acknowledgement
+// is enabled
+type ackIDResponse struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckID uint64 `json:"ackId"`
+}
+
+// ackQuery is the body of the indexer acknowledgement queries
+type ackQuery struct {
+	Acks []uint64 `json:"acks"`
+}
+
+// ackQueryResponse is the response to the indexer acknowledgement queries, with the status of each
+// ack id
+type ackQueryResponse struct {
+	Acks map[uint64]bool `json:"acks"`
+}
+
 // splunkReceiver implements the component.MetricsReceiver for Splunk HEC metric protocol.
 type splunkReceiver struct {
 	sync.Mutex
@@ -81,6 +107,9 @@ type splunkReceiver struct {
 	logsConsumer    consumer.LogsConsumer
 	metricsConsumer consumer.MetricsConsumer
 	server          *http.Server
+	// acks tracks the acks of the data received on each channel, when the indexer acknowledgement
+	// is enabled
+	acks *ackTracker
 }
 
 var _ component.MetricsReceiver = (*splunkReceiver)(nil)
@@ -112,6 +141,13 @@ func NewMetricsReceiver(
 		},
 	}
 
+	if config.Ack.Enabled {
+		var err error
+		if r.acks, err = newAckTracker(config.Ack); err != nil {
+			return nil, err
+		}
+	}
+
 	return r, nil
 }
 
@@ -142,6 +178,13 @@ func NewLogsReceiver(
 		},
 	}
 
+	if config.Ack.Enabled {
+		var err error
+		if r.acks, err = newAckTracker(config.Ack); err != nil {
+			return nil, err
+		}
+	}
+
 	return r, nil
 }
 
@@ -161,6 +204,8 @@ func (r *splunkReceiver) Start(_ context.Context, host component.Host) error {
 
 	mx := mux.NewRouter()
 	mx.HandleFunc(hecPath, r.handleReq)
+	mx.HandleFunc(hecRawPath, r.handleRawReq)
+	mx.HandleFunc(hecAckPath, r.handleAckReq)
 
 	r.server = r.config.HTTPServerSettings.ToServer(mx)
 
@@ -280,8 +325,7 @@ func (r *splunkReceiver) consumeMetrics(ctx context.Context, events []*splunk.Ev
 	if decodeErr != nil {
 		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, decodeErr)
 	} else {
-		resp.WriteHeader(http.StatusAccepted)
-		resp.Write(okRespBody)
+		r.writeAccepted(resp, req)
 	}
 }
 
@@ -297,9 +341,134 @@ func (r *splunkReceiver) consumeLogs(ctx context.Context, events []*splunk.Event
 	if decodeErr != nil {
 		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, decodeErr)
 	} else {
+		r.writeAccepted(resp, req)
+	}
+}
+
+// handleRawReq handles the raw data, split into log records along with the metadata given as query
+// parameters
+func (r *splunkReceiver) handleRawReq(resp http.ResponseWriter, req *http.Request) {
+	transport := "http"
+	if r.config.TLSSetting != nil {
+		transport = "https"
+	}
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport)
+
+	if req.Method != http.MethodPost {
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
+		return
+	}
+
+	if r.logsConsumer == nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnsupportedLogEvent, nil)
+		return
+	}
+
+	encoding := req.Header.Get(httpContentEncodingHeader)
+	if encoding != "" && encoding != gzipEncoding {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
+		return
+	}
+
+	bodyReader := req.Body
+	if encoding == gzipEncoding {
+		var err error
+		bodyReader, err = gzip.NewReader(bodyReader)
+		if err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
+			return
+		}
+	}
+
+	body, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+	records := splitRawData(string(body), r.config.RawSplitting)
+	if len(records) == 0 {
+		resp.Write(okRespBody)
+		return
+	}
+
+	query := req.URL.Query()
+	if channel := requestChannel(req); channel != "" {
+		query.Set(queryChannel, channel)
+	}
+	ld := splunkHecRawToLogData(records, query, r.createResourceCustomizer(req))
+
+	if consumeErr := r.logsConsumer.ConsumeLogs(ctx, ld); consumeErr != nil {
+		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, consumeErr)
+	} else {
+		r.writeAccepted(resp, req)
+	}
+}
+
+// handleAckReq answers the indexer acknowledgement queries, with the status of the ack ids of the
+// data received on the channel
+func (r *splunkReceiver) handleAckReq(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	if req.Method != http.MethodPost {
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
+		return
+	}
+
+	if r.acks == nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errAckDisabled, nil)
+		return
+	}
+
+	channel := requestChannel(req)
+	if channel == "" {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errMissingChannel, nil)
+		return
+	}
+
+	var query ackQuery
+	if err := json.NewDecoder(req.Body).Decode(&query); err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	r.writeJSON(resp, http.StatusOK, ackQueryResponse{Acks: r.acks.query(channel, query.Acks)})
+}
+
+// writeAccepted answers the data accepted by the next consumer, with the ack id of the data when
+// received on a channel and the indexer acknowledgement is enabled
+func (r *splunkReceiver) writeAccepted(resp http.ResponseWriter, req *http.Request) {
+	channel := requestChannel(req)
+	if r.acks == nil || channel == "" {
 		resp.WriteHeader(http.StatusAccepted)
 		resp.Write(okRespBody)
+		return
+	}
+
+	r.writeJSON(resp, http.StatusAccepted, ackIDResponse{Text: responseSuccess, AckID: r.acks.ack(channel)})
+}
+
+func (r *splunkReceiver) writeJSON(resp http.ResponseWriter, httpStatusCode int, body interface{}) {
+	respBody, err := json.Marshal(body)
+	if err != nil {
+		r.failRequest(context.Background(), resp, http.StatusInternalServerError, errInternalServerError, err)
+		return
+	}
+	resp.Header().Set(httpContentTypeHeader, jsonContentType)
+	resp.WriteHeader(httpStatusCode)
+	if _, err := resp.Write(respBody); err != nil {
+		r.logger.Warn(
+			"Error writing HTTP response message",
+			zap.Error(err),
+			zap.String("receiver", r.config.Name()))
+	}
+}
+
+// requestChannel returns the channel of the request, given either as header or query parameter
+func requestChannel(req *http.Request) string {
+	if channel := req.Header.Get(httpChannelHeader); channel != "" {
+		return channel
 	}
+	return req.URL.Query().Get(queryChannel)
 }
 
 func (r *splunkReceiver) failRequest(
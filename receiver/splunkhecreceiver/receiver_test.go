@@ -27,6 +27,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -740,6 +741,200 @@ func Test_Metrics_splunkhecReceiver_IndexSourceTypePassthrough(t *testing.T) {
 	}
 }
 
+func Test_splunkhecReceiver_handleRawReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		consumeErr     error
+		assertResponse func(t *testing.T, status int, body string)
+		records        []string
+	}{
+		{
+			name: "incorrect_method",
+			req:  httptest.NewRequest("GET", "http://localhost/services/collector/raw", nil),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseInvalidMethod, body)
+			},
+		},
+		{
+			name: "incorrect_content_encoding",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("line"))
+				req.Header.Set("Content-Encoding", "superzipper")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, status)
+				assert.Equal(t, responseInvalidEncoding, body)
+			},
+		},
+		{
+			name: "empty_body",
+			req:  httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("\n")),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusOK, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "msg_accepted",
+			req: httptest.NewRequest("POST", "http://localhost/services/collector/raw?sourcetype=syslog",
+				strings.NewReader("first line\nsecond line\n  continued\n")),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+			records: []string{"first line", "second line\n  continued"},
+		},
+		{
+			name: "msg_accepted_gzipped",
+			req: func() *http.Request {
+				var buf bytes.Buffer
+				gzipWriter := gzip.NewWriter(&buf)
+				_, err := gzipWriter.Write([]byte("first line\nsecond line"))
+				require.NoError(t, err)
+				require.NoError(t, gzipWriter.Close())
+
+				req := httptest.NewRequest("POST", "http://localhost/services/collector/raw", &buf)
+				req.Header.Set("Content-Encoding", "gzip")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+			records: []string{"first line", "second line"},
+		},
+		{
+			name:       "consumer_error",
+			req:        httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("line")),
+			consumeErr: errors.New("bad consumer"),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusInternalServerError, status)
+				assert.Equal(t, responseErrInternalServerError, body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.LogsSink)
+			sink.SetConsumeError(tt.consumeErr)
+			rcv, err := NewLogsReceiver(zap.NewNop(), *config, sink)
+			assert.NoError(t, err)
+
+			r := rcv.(*splunkReceiver)
+			w := httptest.NewRecorder()
+			r.handleRawReq(w, tt.req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			tt.assertResponse(t, resp.StatusCode, bodyStr)
+
+			var records []string
+			for _, ld := range sink.AllLogs() {
+				logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+				for i := 0; i < logs.Len(); i++ {
+					records = append(records, logs.At(i).Body().StringVal())
+				}
+			}
+			assert.Equal(t, tt.records, records)
+		})
+	}
+}
+
+func Test_splunkhecReceiver_handleRawReq_metrics(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	rcv, err := NewMetricsReceiver(zap.NewNop(), *config, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	rcv.(*splunkReceiver).handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("line")))
+
+	var bodyStr string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &bodyStr))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, responseErrUnsupportedLogEvent, bodyStr)
+}
+
+func Test_splunkhecReceiver_ack(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.Ack.Enabled = true
+	rcv, err := NewLogsReceiver(zap.NewNop(), *config, new(consumertest.LogsSink))
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	postEvent := func(channel string) *httptest.ResponseRecorder {
+		msgBytes, err := json.Marshal(buildSplunkHecMsg(float64(time.Now().Unix()), 1))
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "http://localhost/services/collector", bytes.NewReader(msgBytes))
+		req.Header.Set("Content-Type", "application/json")
+		if channel != "" {
+			req.Header.Set("X-Splunk-Request-Channel", channel)
+		}
+		w := httptest.NewRecorder()
+		r.handleReq(w, req)
+		return w
+	}
+	queryAcks := func(channel string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "http://localhost/services/collector/ack?channel="+channel, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		r.handleAckReq(w, req)
+		return w
+	}
+
+	// the data received outside of a channel isn't acked
+	w := postEvent("")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, `"OK"`, w.Body.String())
+
+	w = postEvent("channel-1")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"text": "Success", "code": 0, "ackId": 0}`, w.Body.String())
+
+	// the raw data is acked on the channel given as query parameter
+	req := httptest.NewRequest("POST", "http://localhost/services/collector/raw?channel=channel-1", strings.NewReader("line"))
+	w = httptest.NewRecorder()
+	r.handleRawReq(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"text": "Success", "code": 0, "ackId": 1}`, w.Body.String())
+
+	w = queryAcks("channel-1", `{"acks": [0, 1, 2]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"acks": {"0": true, "1": true, "2": false}}`, w.Body.String())
+
+	w = queryAcks("", `{"acks": [0]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `"Data channel is missing"`, w.Body.String())
+
+	w = queryAcks("channel-1", `{"acks": "all"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `"Failed to unmarshal message body"`, w.Body.String())
+}
+
+func Test_splunkhecReceiver_ack_disabled(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	rcv, err := NewLogsReceiver(zap.NewNop(), *config, new(consumertest.LogsSink))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "http://localhost/services/collector/ack?channel=channel-1", strings.NewReader(`{"acks": [0]}`))
+	w := httptest.NewRecorder()
+	rcv.(*splunkReceiver).handleAckReq(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `"ACK is disabled"`, w.Body.String())
+}
+
 func buildSplunkHecMetricsMsg(time float64, value int64, dimensions uint) *splunk.Event {
 	ev := &splunk.Event{
 		Time:  &time,
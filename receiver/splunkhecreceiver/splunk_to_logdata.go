@@ -16,7 +16,9 @@ package splunkhecreceiver
 
 import (
 	"errors"
+	"net/url"
 	"sort"
+	"strings"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
@@ -27,6 +29,16 @@ import (
 
 const (
 	cannotConvertValue = "cannot convert field value to attribute"
+
+	// hecChannelLabel is the attribute of the channel the raw data was received on
+	hecChannelLabel = "com.splunk.hec.channel"
+
+	// The query parameters of the raw endpoint.
+	queryChannel    = "channel"
+	querySourceType = "sourcetype"
+	queryIndex      = "index"
+	querySource     = "source"
+	queryHost       = "host"
 )
 
 // SplunkHecToLogData transforms splunk events into logs
@@ -89,6 +101,66 @@ func SplunkHecToLogData(logger *zap.Logger, events []*splunk.Event, resourceCust
 	return ld, nil
 }
 
+// splunkHecRawToLogData transforms the records of raw data into logs, with the metadata given as
+// query parameters as attributes
+func splunkHecRawToLogData(records []string, query url.Values, resourceCustomizer func(pdata.Resource)) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+	resourceCustomizer(rl.Resource())
+	rl.InstrumentationLibraryLogs().Resize(1)
+	ill := rl.InstrumentationLibraryLogs().At(0)
+
+	sourceType := query.Get(querySourceType)
+	for _, record := range records {
+		logRecord := pdata.NewLogRecord()
+		logRecord.SetName(sourceType)
+		logRecord.Body().SetStringVal(record)
+
+		insertIfNotEmpty(logRecord.Attributes(), conventions.AttributeHostName, query.Get(queryHost))
+		insertIfNotEmpty(logRecord.Attributes(), conventions.AttributeServiceName, query.Get(querySource))
+		insertIfNotEmpty(logRecord.Attributes(), splunk.SourcetypeLabel, sourceType)
+		insertIfNotEmpty(logRecord.Attributes(), splunk.IndexLabel, query.Get(queryIndex))
+		insertIfNotEmpty(logRecord.Attributes(), hecChannelLabel, query.Get(queryChannel))
+
+		ill.Logs().Append(logRecord)
+	}
+	return ld
+}
+
+// splitRawData splits the given raw data into records: a record per line, the indented lines being
+// continuations of the previous one, with the auto splitting, a record per line with the line
+// splitting, and a single record without splitting. The empty records are skipped.
+func splitRawData(data string, splitting string) []string {
+	if splitting == rawSplittingNone {
+		if data = strings.TrimRight(data, "\r\n"); data == "" {
+			return nil
+		}
+		return []string{data}
+	}
+
+	var records []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if splitting == rawSplittingAuto && len(records) > 0 && (line[0] == ' ' || line[0] == '\t') {
+			records[len(records)-1] += "\n" + line
+			continue
+		}
+		records = append(records, line)
+	}
+	return records
+}
+
+func insertIfNotEmpty(attrs pdata.AttributeMap, key string, value string) {
+	if value != "" {
+		attrs.InsertString(key, value)
+	}
+}
+
 func convertInterfaceToAttributeValue(logger *zap.Logger, originalValue interface{}) (pdata.AttributeValue, error) {
 	if originalValue == nil {
 		return pdata.NewAttributeValueNull(), nil
@@ -15,10 +15,13 @@
 package splunkhecreceiver
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
@@ -239,3 +242,62 @@ func Test_ConvertAttributeValueInvalidInArray(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, pdata.NewAttributeValueNull(), value)
 }
+
+func Test_splitRawData(t *testing.T) {
+	data := "first line\r\n\nsecond line\n  at frame 1\n\tat frame 2\nthird line\n"
+
+	tests := []struct {
+		splitting string
+		records   []string
+	}{
+		{
+			splitting: rawSplittingAuto,
+			records:   []string{"first line", "second line\n  at frame 1\n\tat frame 2", "third line"},
+		},
+		{
+			splitting: rawSplittingLine,
+			records:   []string{"first line", "second line", "  at frame 1", "\tat frame 2", "third line"},
+		},
+		{
+			splitting: rawSplittingNone,
+			records:   []string{"first line\r\n\nsecond line\n  at frame 1\n\tat frame 2\nthird line"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.splitting, func(t *testing.T) {
+			assert.Equal(t, tt.records, splitRawData(data, tt.splitting))
+			assert.Empty(t, splitRawData("\n\r\n", tt.splitting))
+		})
+	}
+}
+
+func Test_splunkHecRawToLogData(t *testing.T) {
+	query := url.Values{}
+	query.Set("sourcetype", "syslog")
+	query.Set("index", "main")
+	query.Set("source", "appliance")
+	query.Set("host", "fw-1")
+	query.Set("channel", "00000000-0000-0000-0000-000000000000")
+
+	ld := splunkHecRawToLogData([]string{"first", "second"}, query, func(resource pdata.Resource) {
+		resource.Attributes().InsertString("com.splunk.hec.access_token", "token")
+	})
+
+	require.Equal(t, 1, ld.ResourceLogs().Len())
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, 1, rl.Resource().Attributes().Len())
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 2, logs.Len())
+	for i, body := range []string{"first", "second"} {
+		lr := logs.At(i)
+		assert.Equal(t, "syslog", lr.Name())
+		assert.Equal(t, body, lr.Body().StringVal())
+		assert.Equal(t, map[string]interface{}{
+			"host.name":              "fw-1",
+			"service.name":           "appliance",
+			"com.splunk.sourcetype":  "syslog",
+			"com.splunk.index":       "main",
+			"com.splunk.hec.channel": "00000000-0000-0000-0000-000000000000",
+		}, tracetranslator.AttributeMapToMap(lr.Attributes()))
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"database/sql"
+
+	// the supported database drivers
+	_ "github.com/denisenkom/go-mssqldb"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+// row maps the columns of a row returned by a query to their values, NULL values being invalid
+type row map[string]sql.NullString
+
+// dbClient runs the queries against the database
+type dbClient interface {
+	queryRows(ctx context.Context, query string) ([]row, error)
+	close() error
+}
+
+type sqlClient struct {
+	db *sql.DB
+}
+
+var _ dbClient = (*sqlClient)(nil)
+
+// newSQLClient creates a client for the given database. The connections are opened when the queries are run.
+func newSQLClient(driver string, dataSource string) (dbClient, error) {
+	db, err := sql.Open(driver, dataSource)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlClient{db: db}, nil
+}
+
+// queryRows runs the given query, returning the rows with their values as strings
+func (c *sqlClient) queryRows(ctx context.Context, query string) ([]row, error) {
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []row
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		r := make(row, len(columns))
+		for i, column := range columns {
+			r[column] = values[i]
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+func (c *sqlClient) close() error {
+	return c.db.Close()
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLClientQueryRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT queue, count").WillReturnRows(
+		sqlmock.NewRows([]string{"queue", "pending", "oldest"}).
+			AddRow("emails", int64(12), 3.5).
+			AddRow(nil, int64(4), nil))
+	mock.ExpectClose()
+
+	client := &sqlClient{db: db}
+	rows, err := client.queryRows(context.Background(), "SELECT queue, count(*) AS pending, min(age) AS oldest FROM jobs")
+	require.NoError(t, err)
+	assert.Equal(t, []row{
+		{"queue": value("emails"), "pending": value("12"), "oldest": value("3.5")},
+		{"queue": sql.NullString{}, "pending": value("4"), "oldest": sql.NullString{}},
+	}, rows)
+
+	require.NoError(t, client.close())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLClientQueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery("SELECT").WillReturnError(sql.ErrConnDone)
+
+	client := &sqlClient{db: db}
+	_, err = client.queryRows(context.Background(), "SELECT 1")
+	assert.Equal(t, sql.ErrConnDone, err)
+}
+
+func TestNewSQLClientUnknownDriver(t *testing.T) {
+	_, err := newSQLClient("unknown", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+const (
+	// GaugeDataType reports the values of a column as a gauge.
+	GaugeDataType = "gauge"
+	// SumDataType reports the values of a column as a monotonic cumulative sum.
+	SumDataType = "sum"
+
+	// IntValueType parses the values of a column as integers.
+	IntValueType = "int"
+	// DoubleValueType parses the values of a column as floating point numbers.
+	DoubleValueType = "double"
+)
+
+// Config defines configuration for the SQL query receiver.
+type Config struct {
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
+
+	// Driver is the database/sql driver used to connect to the database: postgres, mysql or sqlserver.
+	Driver string `mapstructure:"driver"`
+
+	// DataSource is the driver specific data source name of the database. As it usually includes the
+	// credentials, they are redacted from the logs and errors of the receiver.
+	DataSource string `mapstructure:"datasource"`
+
+	// Queries are the queries run on each collection interval.
+	Queries []Query `mapstructure:"queries"`
+}
+
+// Query defines a query and the metrics built from its results.
+type Query struct {
+	// SQL is the query to run.
+	SQL string `mapstructure:"sql"`
+
+	// Timeout is the maximum duration of the query, 10s by default.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Metrics are the metrics built from the rows returned by the query.
+	Metrics []MetricConfig `mapstructure:"metrics"`
+}
+
+// MetricConfig defines a metric built from the rows returned by a query, each row being a data point.
+type MetricConfig struct {
+	// MetricName is the name of the metric.
+	MetricName string `mapstructure:"metric_name"`
+
+	// Description is the description of the metric.
+	Description string `mapstructure:"description"`
+
+	// Unit is the unit of the metric.
+	Unit string `mapstructure:"unit"`
+
+	// ValueColumn is the column holding the value of the data points.
+	ValueColumn string `mapstructure:"value_column"`
+
+	// AttributeColumns are the columns whose values become the labels of the data points,
+	// named after the columns.
+	AttributeColumns []string `mapstructure:"attribute_columns"`
+
+	// DataType is the type of the metric, gauge (default) or sum.
+	DataType string `mapstructure:"data_type"`
+
+	// ValueType is the type of the values, double (default) or int.
+	ValueType string `mapstructure:"value_type"`
+}
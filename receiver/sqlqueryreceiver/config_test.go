@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, 2, len(config.Receivers))
+
+	assert.Equal(t, factory.CreateDefaultConfig(), config.Receivers["sqlquery"])
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "sqlquery",
+					NameVal: "sqlquery/postgres",
+				},
+				CollectionInterval: 30 * time.Second,
+			},
+			Driver:     "postgres",
+			DataSource: "host=localhost port=5432 user=otel password=secret dbname=jobs sslmode=disable",
+			Queries: []Query{
+				{
+					SQL:     "SELECT queue, count(*) AS pending FROM jobs WHERE state = 'pending' GROUP BY queue",
+					Timeout: 5 * time.Second,
+					Metrics: []MetricConfig{
+						{
+							MetricName:       "jobs.pending",
+							Description:      "The number of pending jobs.",
+							Unit:             "{jobs}",
+							ValueColumn:      "pending",
+							AttributeColumns: []string{"queue"},
+							ValueType:        IntValueType,
+						},
+					},
+				},
+				{
+					SQL: "SELECT sum(processed) AS processed FROM job_stats",
+					Metrics: []MetricConfig{
+						{
+							MetricName:  "jobs.processed",
+							ValueColumn: "processed",
+							DataType:    SumDataType,
+						},
+					},
+				},
+			},
+		},
+		config.Receivers["sqlquery/postgres"])
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted = "xxxxx"
+
+var (
+	// keyValuePasswordRegexp matches the passwords of the key/value data sources, such as
+	// "host=localhost password=secret" for postgres or "server=localhost;password=secret" for sqlserver
+	keyValuePasswordRegexp = regexp.MustCompile(`(?i)\b(?:password|pwd)\s*=\s*('(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*"|[^\s;&]*)`)
+
+	// mysqlPasswordRegexp matches the password of the mysql data sources, such as "user:secret@tcp(localhost)/db",
+	// the driver splitting the credentials at the last '@'
+	mysqlPasswordRegexp = regexp.MustCompile(`^[^:/@]*:(.*)@[^@]*$`)
+)
+
+// redactor removes the credentials of a data source from the strings
+type redactor struct {
+	replacer *strings.Replacer
+}
+
+func newRedactor(dataSource string) *redactor {
+	var oldnew []string
+	for _, secret := range dataSourceSecrets(dataSource) {
+		if secret != "" {
+			oldnew = append(oldnew, secret, redacted)
+		}
+	}
+	return &redactor{replacer: strings.NewReplacer(oldnew...)}
+}
+
+func (r *redactor) redact(s string) string {
+	return r.replacer.Replace(s)
+}
+
+// redactError returns an error with the message of the given one, without the credentials
+func (r *redactor) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if redactedMsg := r.redact(msg); redactedMsg != msg {
+		return errors.New(redactedMsg)
+	}
+	return err
+}
+
+// dataSourceSecrets returns the passwords found in the given data source, which may be a URL, a list of key/value
+// pairs or a mysql data source name
+func dataSourceSecrets(dataSource string) []string {
+	var secrets []string
+	if u, err := url.Parse(dataSource); err == nil && u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			secrets = append(secrets, password, url.QueryEscape(password), url.PathEscape(password))
+		}
+	}
+	for _, match := range keyValuePasswordRegexp.FindAllStringSubmatch(dataSource, -1) {
+		secrets = append(secrets, match[1], strings.Trim(match[1], `'"`))
+	}
+	if !strings.Contains(dataSource, "://") {
+		if match := mysqlPasswordRegexp.FindStringSubmatch(dataSource); match != nil {
+			secrets = append(secrets, match[1])
+		}
+	}
+	return secrets
+}
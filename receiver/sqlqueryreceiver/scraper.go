@@ -0,0 +1,293 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/zap"
+)
+
+var (
+	errMissingDataSource = errors.New("datasource must be specified")
+	errMissingQueries    = errors.New("at least one query must be specified")
+)
+
+// supportedDrivers are the database/sql drivers registered by the receiver
+var supportedDrivers = []string{"postgres", "mysql", "sqlserver"}
+
+type sqlQueryScraper struct {
+	logger   *zap.Logger
+	config   *Config
+	redactor *redactor
+
+	newClient func(driver string, dataSource string) (dbClient, error)
+	client    dbClient
+	now       func() time.Time
+
+	// startTime is the start time of the cumulative sums
+	startTime pdata.TimestampUnixNano
+}
+
+func newSQLQueryScraper(logger *zap.Logger, config *Config) (*sqlQueryScraper, error) {
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	return &sqlQueryScraper{
+		logger:    logger,
+		config:    config,
+		redactor:  newRedactor(config.DataSource),
+		newClient: newSQLClient,
+		now:       time.Now,
+	}, nil
+}
+
+func validate(config *Config) error {
+	if !isSupportedDriver(config.Driver) {
+		return fmt.Errorf("invalid driver %q, must be one of %q", config.Driver, supportedDrivers)
+	}
+	if config.DataSource == "" {
+		return errMissingDataSource
+	}
+	if len(config.Queries) == 0 {
+		return errMissingQueries
+	}
+
+	for i, query := range config.Queries {
+		if query.SQL == "" {
+			return fmt.Errorf("query %d: sql must be specified", i)
+		}
+		if query.Timeout < 0 {
+			return fmt.Errorf("query %d: timeout can't be negative", i)
+		}
+		if len(query.Metrics) == 0 {
+			return fmt.Errorf("query %d: at least one metric must be specified", i)
+		}
+
+		for _, metric := range query.Metrics {
+			if metric.MetricName == "" {
+				return fmt.Errorf("query %d: metric_name must be specified", i)
+			}
+			if metric.ValueColumn == "" {
+				return fmt.Errorf("query %d: metric %q: value_column must be specified", i, metric.MetricName)
+			}
+			switch metric.DataType {
+			case "", GaugeDataType, SumDataType:
+			default:
+				return fmt.Errorf("query %d: metric %q: invalid data_type %q, must be one of %q or %q",
+					i, metric.MetricName, metric.DataType, GaugeDataType, SumDataType)
+			}
+			switch metric.ValueType {
+			case "", IntValueType, DoubleValueType:
+			default:
+				return fmt.Errorf("query %d: metric %q: invalid value_type %q, must be one of %q or %q",
+					i, metric.MetricName, metric.ValueType, IntValueType, DoubleValueType)
+			}
+		}
+	}
+	return nil
+}
+
+func isSupportedDriver(driver string) bool {
+	for _, supported := range supportedDrivers {
+		if driver == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sqlQueryScraper) start(context.Context, component.Host) error {
+	client, err := s.newClient(s.config.Driver, s.config.DataSource)
+	if err != nil {
+		return s.redactor.redactError(err)
+	}
+	s.client = client
+	s.startTime = pdata.TimestampUnixNano(uint64(s.now().UnixNano()))
+	return nil
+}
+
+func (s *sqlQueryScraper) shutdown(context.Context) error {
+	if s.client == nil {
+		return nil
+	}
+	return s.redactor.redactError(s.client.close())
+}
+
+// scrape runs the queries and builds the metrics from their results. A failing query doesn't prevent
+// the metrics of the other ones from being reported.
+func (s *sqlQueryScraper) scrape(ctx context.Context) (pdata.ResourceMetricsSlice, error) {
+	rms := pdata.NewResourceMetricsSlice()
+	rms.Resize(1)
+	ilms := rms.At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	ilm := ilms.At(0)
+	ilm.InstrumentationLibrary().SetName("otelcol/sqlquery")
+	metrics := ilm.Metrics()
+
+	var errs []error
+	for _, query := range s.config.Queries {
+		timeout := query.Timeout
+		if timeout == 0 {
+			timeout = defaultQueryTimeout
+		}
+
+		queryCtx, cancel := context.WithTimeout(ctx, timeout)
+		rows, err := s.client.queryRows(queryCtx, query.SQL)
+		cancel()
+		if err != nil {
+			err = fmt.Errorf("query %q failed: %w", query.SQL, err)
+			errs = append(errs, consumererror.NewPartialScrapeError(s.redactor.redactError(err), len(query.Metrics)))
+			continue
+		}
+
+		timestamp := pdata.TimestampUnixNano(uint64(s.now().UnixNano()))
+		for _, metricCfg := range query.Metrics {
+			metric, err := s.buildMetric(query, metricCfg, rows, timestamp)
+			if err != nil {
+				errs = append(errs, consumererror.NewPartialScrapeError(s.redactor.redactError(err), 1))
+				continue
+			}
+			metrics.Append(metric)
+		}
+	}
+
+	return rms, scraperhelper.CombineScrapeErrors(errs)
+}
+
+// buildMetric builds the given metric out of the given rows, skipping the rows with NULL values
+func (s *sqlQueryScraper) buildMetric(
+	query Query,
+	metricCfg MetricConfig,
+	rows []row,
+	timestamp pdata.TimestampUnixNano,
+) (pdata.Metric, error) {
+	metric := pdata.NewMetric()
+	metric.SetName(metricCfg.MetricName)
+	metric.SetDescription(metricCfg.Description)
+	metric.SetUnit(metricCfg.Unit)
+
+	isInt := metricCfg.ValueType == IntValueType
+	isSum := metricCfg.DataType == SumDataType
+	switch {
+	case isInt && isSum:
+		metric.SetDataType(pdata.MetricDataTypeIntSum)
+		metric.IntSum().SetIsMonotonic(true)
+		metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	case isInt:
+		metric.SetDataType(pdata.MetricDataTypeIntGauge)
+	case isSum:
+		metric.SetDataType(pdata.MetricDataTypeDoubleSum)
+		metric.DoubleSum().SetIsMonotonic(true)
+		metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	default:
+		metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	}
+
+rows:
+	for _, r := range rows {
+		value, ok := r[metricCfg.ValueColumn]
+		if !ok {
+			return pdata.Metric{}, fmt.Errorf("metric %q: column %q not found in the results of query %q",
+				metricCfg.MetricName, metricCfg.ValueColumn, query.SQL)
+		}
+		if !value.Valid {
+			s.logger.Warn("Skipping row with NULL value",
+				zap.String("metric", metricCfg.MetricName),
+				zap.String("column", metricCfg.ValueColumn),
+				zap.String("query", query.SQL))
+			continue
+		}
+
+		labels := make(map[string]string, len(metricCfg.AttributeColumns))
+		for _, column := range metricCfg.AttributeColumns {
+			attr, ok := r[column]
+			if !ok {
+				return pdata.Metric{}, fmt.Errorf("metric %q: column %q not found in the results of query %q",
+					metricCfg.MetricName, column, query.SQL)
+			}
+			if !attr.Valid {
+				s.logger.Warn("Skipping row with NULL attribute",
+					zap.String("metric", metricCfg.MetricName),
+					zap.String("column", column),
+					zap.String("query", query.SQL))
+				continue rows
+			}
+			labels[column] = attr.String
+		}
+
+		if err := addDataPoint(metric, value.String, labels, s.startTime, timestamp); err != nil {
+			return pdata.Metric{}, fmt.Errorf("metric %q: invalid value of column %q: %w",
+				metricCfg.MetricName, metricCfg.ValueColumn, err)
+		}
+	}
+
+	return metric, nil
+}
+
+// addDataPoint parses the given value according to the type of the metric, and adds it as a new data point
+func addDataPoint(
+	metric pdata.Metric,
+	value string,
+	labels map[string]string,
+	startTime pdata.TimestampUnixNano,
+	timestamp pdata.TimestampUnixNano,
+) error {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge, pdata.MetricDataTypeIntSum:
+		intValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		dp := pdata.NewIntDataPoint()
+		dp.SetValue(intValue)
+		dp.SetTimestamp(timestamp)
+		dp.LabelsMap().InitFromMap(labels)
+		if metric.DataType() == pdata.MetricDataTypeIntSum {
+			dp.SetStartTime(startTime)
+			metric.IntSum().DataPoints().Append(dp)
+		} else {
+			metric.IntGauge().DataPoints().Append(dp)
+		}
+
+	default:
+		doubleValue, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+
+		dp := pdata.NewDoubleDataPoint()
+		dp.SetValue(doubleValue)
+		dp.SetTimestamp(timestamp)
+		dp.LabelsMap().InitFromMap(labels)
+		if metric.DataType() == pdata.MetricDataTypeDoubleSum {
+			dp.SetStartTime(startTime)
+			metric.DoubleSum().DataPoints().Append(dp)
+		} else {
+			metric.DoubleGauge().DataPoints().Append(dp)
+		}
+	}
+	return nil
+}
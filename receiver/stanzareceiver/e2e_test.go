@@ -225,3 +225,47 @@ func newTempDir(t *testing.T) string {
 
 	return tempDir
 }
+
+func TestRecombineMultilineFiles(t *testing.T) {
+	t.Parallel()
+
+	f := NewFactory()
+	sink := new(consumertest.LogsSink)
+	params := component.ReceiverCreateParams{Logger: zaptest.NewLogger(t)}
+
+	tempDir := newTempDir(t)
+	for _, name := range []string{"first.log", "second.log"} {
+		content := fmt.Sprintf("2020-08-25 %s failed\n\tat Main.run(Main.java:10)\n\tat Main.main(Main.java:5)\n2020-08-25 %s done\n", name, name)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, name), []byte(content), 0600))
+	}
+
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Operators = unmarshalConfig(t, fmt.Sprintf(`
+  - type: file_input
+    include: [%s/*]
+    start_at: beginning
+    poll_interval: 10ms
+  - type: recombine
+    combine_field: $record
+    is_first_entry: $record matches "^\\d{4}-\\d{2}-\\d{2}"
+    force_flush_period: 100ms`, tempDir))
+
+	rcvr, err := f.CreateLogsReceiver(context.Background(), params, cfg, sink)
+	require.NoError(t, err, "failed to create receiver")
+	require.NoError(t, rcvr.Start(context.Background(), &testHost{t: t}))
+
+	// each file is recombined independently, the last group of each file being flushed after the force flush period
+	require.Eventually(t, expectNLogs(sink, 4), 2*time.Second, time.Millisecond)
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		lr := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+		bodies = append(bodies, lr.Body().StringVal())
+	}
+	require.ElementsMatch(t, []string{
+		"2020-08-25 first.log failed\n\tat Main.run(Main.java:10)\n\tat Main.main(Main.java:5)",
+		"2020-08-25 first.log done",
+		"2020-08-25 second.log failed\n\tat Main.run(Main.java:10)\n\tat Main.main(Main.java:5)",
+		"2020-08-25 second.log done",
+	}, bodies)
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+}
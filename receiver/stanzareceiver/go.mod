@@ -3,6 +3,7 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/stanza
 go 1.14
 
 require (
+	github.com/antonmedv/expr v1.8.9
 	github.com/observiq/nanojack v0.0.0-20201106172433-343928847ebc
 	github.com/observiq/stanza v0.13.9
 	github.com/stretchr/testify v1.6.1
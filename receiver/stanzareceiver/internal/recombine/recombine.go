@@ -0,0 +1,294 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recombine provides a stanza operator combining consecutive entries of the same source into single ones,
+// like the lines of multiline logs.
+package recombine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+	"github.com/observiq/stanza/entry"
+	"github.com/observiq/stanza/operator"
+	"github.com/observiq/stanza/operator/helper"
+)
+
+const (
+	operatorType = "recombine"
+
+	defaultMaxBatchSize     = 1000
+	defaultCombineWith      = "\n"
+	defaultForceFlushPeriod = 5 * time.Second
+)
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewRecombineOperatorConfig("") })
+}
+
+// NewRecombineOperatorConfig creates a new recombine config with default values
+func NewRecombineOperatorConfig(operatorID string) *RecombineOperatorConfig {
+	return &RecombineOperatorConfig{
+		TransformerConfig: helper.NewTransformerConfig(operatorID, operatorType),
+		MaxBatchSize:      defaultMaxBatchSize,
+		CombineWith:       defaultCombineWith,
+		SourceIdentifier:  entry.NewLabelField("file_name"),
+		ForceFlushPeriod:  helper.NewDuration(defaultForceFlushPeriod),
+	}
+}
+
+// RecombineOperatorConfig is the configuration of a recombine operator
+type RecombineOperatorConfig struct {
+	helper.TransformerConfig `yaml:",inline"`
+
+	// IsFirstEntry is the expression matching the first entry of each combined entry
+	IsFirstEntry string `json:"is_first_entry" yaml:"is_first_entry"`
+
+	// IsLastEntry is the expression matching the last entry of each combined entry
+	IsLastEntry string `json:"is_last_entry" yaml:"is_last_entry"`
+
+	// CombineField is the field whose values are combined
+	CombineField entry.Field `json:"combine_field" yaml:"combine_field"`
+
+	// CombineWith is the delimiter the values are joined with
+	CombineWith string `json:"combine_with" yaml:"combine_with"`
+
+	// MaxBatchSize is the maximum number of entries combined into a single one
+	MaxBatchSize int `json:"max_batch_size" yaml:"max_batch_size"`
+
+	// SourceIdentifier is the field identifying the source of the entries, the entries of each source
+	// being combined independently
+	SourceIdentifier entry.Field `json:"source_identifier" yaml:"source_identifier"`
+
+	// ForceFlushPeriod is the time after which the entries of a source are combined when no new entry is received
+	ForceFlushPeriod helper.Duration `json:"force_flush_period" yaml:"force_flush_period"`
+}
+
+// Build creates a new RecombineOperator from a config
+func (c *RecombineOperatorConfig) Build(bc operator.BuildContext) ([]operator.Operator, error) {
+	transformer, err := c.TransformerConfig.Build(bc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transformer config: %w", err)
+	}
+
+	if c.IsLastEntry != "" && c.IsFirstEntry != "" {
+		return nil, fmt.Errorf("only one of is_first_entry and is_last_entry can be set")
+	}
+	if c.IsLastEntry == "" && c.IsFirstEntry == "" {
+		return nil, fmt.Errorf("one of is_first_entry and is_last_entry must be set")
+	}
+
+	matchFirstEntry := c.IsFirstEntry != ""
+	exprString, exprName := c.IsLastEntry, "is_last_entry"
+	if matchFirstEntry {
+		exprString, exprName = c.IsFirstEntry, "is_first_entry"
+	}
+	prog, err := expr.Compile(exprString, expr.AsBool(), expr.AllowUndefinedVariables())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile %s: %w", exprName, err)
+	}
+
+	if c.CombineField.FieldInterface == nil {
+		return nil, fmt.Errorf("missing required argument 'combine_field'")
+	}
+	if c.MaxBatchSize <= 0 {
+		return nil, fmt.Errorf("max_batch_size must be positive")
+	}
+	if c.ForceFlushPeriod.Raw() <= 0 {
+		return nil, fmt.Errorf("force_flush_period must be positive")
+	}
+
+	recombine := &RecombineOperator{
+		TransformerOperator: transformer,
+		matchFirstEntry:     matchFirstEntry,
+		prog:                prog,
+		combineField:        c.CombineField,
+		combineWith:         c.CombineWith,
+		maxBatchSize:        c.MaxBatchSize,
+		sourceIdentifier:    c.SourceIdentifier,
+		forceFlushPeriod:    c.ForceFlushPeriod.Raw(),
+		batches:             map[string]*sourceBatch{},
+		now:                 time.Now,
+	}
+	return []operator.Operator{recombine}, nil
+}
+
+// RecombineOperator combines the field of consecutive entries of the same source into a single entry,
+// which keeps the other fields of the first entry, like its timestamp and its labels
+type RecombineOperator struct {
+	helper.TransformerOperator
+
+	matchFirstEntry  bool
+	prog             *vm.Program
+	combineField     entry.Field
+	combineWith      string
+	maxBatchSize     int
+	sourceIdentifier entry.Field
+	forceFlushPeriod time.Duration
+	now              func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	sync.Mutex
+	batches map[string]*sourceBatch
+}
+
+// sourceBatch holds the entries of a source waiting to be combined
+type sourceBatch struct {
+	entries     []*entry.Entry
+	lastUpdated time.Time
+}
+
+// Start starts flushing the batches of the sources which haven't received any entry
+// during the force flush period
+func (r *RecombineOperator) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.forceFlushPeriod / 5)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.flushStale(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop combines the entries left in the batches, so that they aren't lost
+func (r *RecombineOperator) Stop() error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	r.Lock()
+	defer r.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for source := range r.batches {
+		r.flush(ctx, source)
+	}
+	return nil
+}
+
+// Process adds the given entry to the batch of its source, combining the batch when complete
+func (r *RecombineOperator) Process(ctx context.Context, e *entry.Entry) error {
+	// the batches can't be changed concurrently
+	r.Lock()
+	defer r.Unlock()
+
+	env := helper.GetExprEnv(e)
+	defer helper.PutExprEnv(env)
+
+	m, err := expr.Run(r.prog, env)
+	if err != nil {
+		return r.HandleEntryError(ctx, e, err)
+	}
+	// this is guaranteed to be a boolean because of expr.AsBool
+	matches := m.(bool)
+
+	// the entries without a source identifier are combined together
+	var source string
+	_ = e.Read(r.sourceIdentifier, &source)
+
+	switch {
+	case matches && r.matchFirstEntry:
+		// the entry starts a new batch
+		r.flush(ctx, source)
+		r.addToBatch(ctx, source, e)
+	case matches:
+		// the entry completes the batch
+		r.addToBatch(ctx, source, e)
+		r.flush(ctx, source)
+	default:
+		r.addToBatch(ctx, source, e)
+	}
+	return nil
+}
+
+// addToBatch adds the given entry to the batch of the given source, combining the batch first when full
+func (r *RecombineOperator) addToBatch(ctx context.Context, source string, e *entry.Entry) {
+	batch, ok := r.batches[source]
+	if ok && len(batch.entries) >= r.maxBatchSize {
+		r.Debugw("Batch reached the max batch size, combining it", "source", source)
+		r.flush(ctx, source)
+		ok = false
+	}
+	if !ok {
+		batch = &sourceBatch{}
+		r.batches[source] = batch
+	}
+
+	batch.entries = append(batch.entries, e)
+	batch.lastUpdated = r.now()
+}
+
+// flushStale combines the batches which haven't received any entry during the force flush period
+func (r *RecombineOperator) flushStale(ctx context.Context) {
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.now()
+	for source, batch := range r.batches {
+		if now.Sub(batch.lastUpdated) >= r.forceFlushPeriod {
+			r.flush(ctx, source)
+		}
+	}
+}
+
+// flush combines the entries of the batch of the given source into the first one, then sends it to the next
+// operator. The entries without the combine field are dropped.
+func (r *RecombineOperator) flush(ctx context.Context, source string) {
+	batch, ok := r.batches[source]
+	if !ok {
+		return
+	}
+	delete(r.batches, source)
+	if len(batch.entries) == 0 {
+		return
+	}
+
+	values := make([]string, 0, len(batch.entries))
+	for _, e := range batch.entries {
+		var s string
+		if err := e.Read(r.combineField, &s); err != nil {
+			r.Errorw("Entry does not contain the combine_field, so is being dropped", "source", source)
+			continue
+		}
+		values = append(values, s)
+	}
+
+	base := batch.entries[0]
+	if err := base.Set(r.combineField, strings.Join(values, r.combineWith)); err != nil {
+		r.Errorw("Failed to set the combine_field of the combined entry", "error", err)
+		return
+	}
+	r.Write(ctx, base)
+}
@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recombine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+	"github.com/observiq/stanza/operator"
+	"github.com/observiq/stanza/operator/helper"
+	"github.com/observiq/stanza/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	t1 = time.Date(2020, time.April, 11, 21, 34, 01, 0, time.UTC)
+	t2 = time.Date(2020, time.April, 11, 21, 34, 02, 0, time.UTC)
+)
+
+func TestRecombineOperator(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   func(cfg *RecombineOperatorConfig)
+		input    []*entry.Entry
+		expected []*entry.Entry
+	}{
+		{
+			name: "first entry",
+			config: func(cfg *RecombineOperatorConfig) {
+				cfg.IsFirstEntry = `$record matches "^\\d{4}-\\d{2}-\\d{2}"`
+			},
+			input: []*entry.Entry{
+				newEntry(t1, "file1", "2020-04-11 java.lang.NullPointerException"),
+				newEntry(t2, "file1", "\tat Main.main(Main.java:5)"),
+				newEntry(t2, "file1", "2020-04-11 done"),
+			},
+			expected: []*entry.Entry{
+				// the last entry waits for the next one, or for the force flush period
+				newEntry(t1, "file1", "2020-04-11 java.lang.NullPointerException\n\tat Main.main(Main.java:5)"),
+			},
+		},
+		{
+			name: "last entry with delimiter",
+			config: func(cfg *RecombineOperatorConfig) {
+				cfg.IsLastEntry = `$record endsWith "."`
+				cfg.CombineWith = " "
+			},
+			input: []*entry.Entry{
+				newEntry(t1, "file1", "a sentence"),
+				newEntry(t2, "file1", "over two lines."),
+				newEntry(t2, "file1", "another one."),
+			},
+			expected: []*entry.Entry{
+				newEntry(t1, "file1", "a sentence over two lines."),
+				newEntry(t2, "file1", "another one."),
+			},
+		},
+		{
+			name: "interleaved sources",
+			config: func(cfg *RecombineOperatorConfig) {
+				cfg.IsFirstEntry = `$record matches "^\\d{4}-\\d{2}-\\d{2}"`
+			},
+			input: []*entry.Entry{
+				newEntry(t1, "file1", "2020-04-11 first"),
+				newEntry(t1, "file2", "2020-04-11 second"),
+				newEntry(t2, "file1", "first continued"),
+				newEntry(t2, "file2", "second continued"),
+				newEntry(t2, "file1", "2020-04-11 third"),
+				newEntry(t2, "file2", "2020-04-11 fourth"),
+			},
+			expected: []*entry.Entry{
+				newEntry(t1, "file1", "2020-04-11 first\nfirst continued"),
+				newEntry(t1, "file2", "2020-04-11 second\nsecond continued"),
+			},
+		},
+		{
+			name: "max batch size",
+			config: func(cfg *RecombineOperatorConfig) {
+				cfg.IsLastEntry = `$record == "end"`
+				cfg.MaxBatchSize = 2
+			},
+			input: []*entry.Entry{
+				newEntry(t1, "file1", "a"),
+				newEntry(t1, "file1", "b"),
+				newEntry(t2, "file1", "c"),
+				newEntry(t2, "file1", "end"),
+			},
+			expected: []*entry.Entry{
+				newEntry(t1, "file1", "a\nb"),
+				newEntry(t2, "file1", "c\nend"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// prepare
+			cfg := NewRecombineOperatorConfig("")
+			cfg.CombineField = entry.NewRecordField()
+			cfg.OutputIDs = []string{"fake"}
+			tc.config(cfg)
+			recombine, fake := buildOperator(t, cfg)
+
+			// test
+			for _, e := range tc.input {
+				require.NoError(t, recombine.Process(context.Background(), e))
+			}
+
+			// verify
+			for _, expected := range tc.expected {
+				fake.ExpectEntry(t, expected)
+			}
+			select {
+			case e := <-fake.Received:
+				require.FailNow(t, "unexpected entry", e)
+			default:
+			}
+		})
+	}
+}
+
+func TestForceFlush(t *testing.T) {
+	// prepare
+	cfg := NewRecombineOperatorConfig("")
+	cfg.CombineField = entry.NewRecordField()
+	cfg.OutputIDs = []string{"fake"}
+	cfg.IsFirstEntry = `$record matches "^\\d{4}-\\d{2}-\\d{2}"`
+	cfg.ForceFlushPeriod = helper.NewDuration(100 * time.Millisecond)
+	recombine, fake := buildOperator(t, cfg)
+	require.NoError(t, recombine.Start())
+	defer func() {
+		assert.NoError(t, recombine.Stop())
+	}()
+
+	// test
+	require.NoError(t, recombine.Process(context.Background(), newEntry(t1, "file1", "2020-04-11 partial")))
+	require.NoError(t, recombine.Process(context.Background(), newEntry(t2, "file1", "group")))
+
+	// verify
+	// the trailing group is combined without waiting for the start of the next one
+	fake.ExpectEntry(t, newEntry(t1, "file1", "2020-04-11 partial\ngroup"))
+}
+
+func TestStopFlushesBatches(t *testing.T) {
+	// prepare
+	cfg := NewRecombineOperatorConfig("")
+	cfg.CombineField = entry.NewRecordField()
+	cfg.OutputIDs = []string{"fake"}
+	cfg.IsFirstEntry = "true"
+	cfg.ForceFlushPeriod = helper.NewDuration(time.Hour)
+	recombine, fake := buildOperator(t, cfg)
+	require.NoError(t, recombine.Start())
+	require.NoError(t, recombine.Process(context.Background(), newEntry(t1, "file1", "pending")))
+
+	// test
+	require.NoError(t, recombine.Stop())
+
+	// verify
+	fake.ExpectEntry(t, newEntry(t1, "file1", "pending"))
+}
+
+func TestBuildWithInvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config func(cfg *RecombineOperatorConfig)
+	}{
+		{"no expression", func(cfg *RecombineOperatorConfig) {}},
+		{"both expressions", func(cfg *RecombineOperatorConfig) {
+			cfg.IsFirstEntry = "true"
+			cfg.IsLastEntry = "true"
+		}},
+		{"invalid expression", func(cfg *RecombineOperatorConfig) { cfg.IsFirstEntry = "$record ==" }},
+		{"no combine field", func(cfg *RecombineOperatorConfig) {
+			cfg.IsFirstEntry = "true"
+			cfg.CombineField = entry.Field{}
+		}},
+		{"invalid max batch size", func(cfg *RecombineOperatorConfig) {
+			cfg.IsFirstEntry = "true"
+			cfg.MaxBatchSize = 0
+		}},
+		{"invalid force flush period", func(cfg *RecombineOperatorConfig) {
+			cfg.IsFirstEntry = "true"
+			cfg.ForceFlushPeriod = helper.NewDuration(0)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewRecombineOperatorConfig("")
+			cfg.CombineField = entry.NewRecordField()
+			cfg.OutputIDs = []string{"fake"}
+			tc.config(cfg)
+
+			_, err := cfg.Build(testutil.NewBuildContext(t))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func buildOperator(t *testing.T, cfg *RecombineOperatorConfig) (operator.Operator, *testutil.FakeOutput) {
+	ops, err := cfg.Build(testutil.NewBuildContext(t))
+	require.NoError(t, err)
+	recombine := ops[0]
+
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, recombine.SetOutputs([]operator.Operator{fake}))
+	return recombine, fake
+}
+
+func newEntry(ts time.Time, source string, record interface{}) *entry.Entry {
+	e := entry.New()
+	e.Timestamp = ts
+	e.Record = record
+	e.Labels = map[string]string{"file_name": source}
+	return e
+}
@@ -19,4 +19,6 @@ import (
 	_ "github.com/observiq/stanza/operator/builtin/input/file"
 	_ "github.com/observiq/stanza/operator/builtin/parser/json"
 	_ "github.com/observiq/stanza/operator/builtin/parser/regex"
+
+	_ "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/stanzareceiver/internal/recombine"
 )
@@ -19,6 +19,8 @@ import (
 
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 )
 
 // Config defines configuration for StatsD receiver.
@@ -26,4 +28,7 @@ type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
 	NetAddr                       confignet.NetAddr `mapstructure:",squash"`
 	AggregationInterval           time.Duration     `mapstructure:"aggregation_interval"`
+	// TimerHistogramMapping defines how the timer and histogram observations are converted,
+	// each of them being converted to a gauge by default.
+	TimerHistogramMapping []protocol.TimerHistogramMapping `mapstructure:"timer_histogram_mapping"`
 }
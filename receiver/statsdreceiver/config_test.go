@@ -25,6 +25,8 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -56,5 +58,9 @@ func TestLoadConfig(t *testing.T) {
 			Transport: "custom_transport",
 		},
 		AggregationInterval: 70 * time.Second,
+		TimerHistogramMapping: []protocol.TimerHistogramMapping{
+			{StatsdType: "histogram", ObserverType: "gauge"},
+			{StatsdType: "timer", ObserverType: "summary"},
+		},
 	}, r1)
 }
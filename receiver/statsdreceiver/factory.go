@@ -23,6 +23,8 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 )
 
 const (
@@ -53,6 +55,10 @@ func createDefaultConfig() configmodels.Receiver {
 			Transport: defaultTransport,
 		},
 		AggregationInterval: defaultAggregationInterval,
+		TimerHistogramMapping: []protocol.TimerHistogramMapping{
+			{StatsdType: protocol.TimerStatsdType, ObserverType: protocol.GaugeObserverType},
+			{StatsdType: protocol.HistogramStatsdType, ObserverType: protocol.GaugeObserverType},
+		},
 	}
 }
 
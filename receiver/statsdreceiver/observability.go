@@ -0,0 +1,43 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func init() {
+	view.Register(
+		viewMalformedLines,
+	)
+}
+
+var (
+	mMalformedLines = stats.Int64("otelcol/statsd/malformed_lines", "Number of StatsD lines which couldn't be parsed", "1")
+)
+
+var viewMalformedLines = &view.View{
+	Name:        mMalformedLines.Name(),
+	Description: mMalformedLines.Description(),
+	Measure:     mMalformedLines,
+	Aggregation: view.Sum(),
+}
+
+func recordMalformedLine() {
+	stats.Record(context.Background(), mMalformedLines.M(int64(1)))
+}
@@ -17,6 +17,8 @@ package protocol
 import (
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +26,7 @@ import (
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"go.opentelemetry.io/otel/label"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 var (
@@ -31,14 +34,51 @@ var (
 	errEmptyMetricValue = errors.New("empty metric value")
 )
 
+const (
+	// TimerStatsdType is the statsd_type of the timer lines, of type "ms", in the timer and histogram mapping.
+	TimerStatsdType = "timer"
+	// HistogramStatsdType is the statsd_type of the histogram lines, of type "h", in the timer and histogram mapping.
+	HistogramStatsdType = "histogram"
+
+	// GaugeObserverType converts each timer or histogram observation to a gauge.
+	GaugeObserverType = "gauge"
+	// SummaryObserverType aggregates the timer or histogram observations to a summary.
+	SummaryObserverType = "summary"
+)
+
+// summaryPercentiles are the percentiles of the summaries aggregated from the timer and histogram observations.
+var summaryPercentiles = []float64{0, 10, 50, 90, 95, 100}
+
 func getSupportedTypes() []string {
-	return []string{"c", "g"}
+	return []string{"c", "g", "ms", "h"}
+}
+
+// TimerHistogramMapping defines how the timer or histogram observations are converted.
+type TimerHistogramMapping struct {
+	// StatsdType is the type of the lines to convert: <timer|histogram>.
+	StatsdType string `mapstructure:"statsd_type"`
+
+	// ObserverType is the way the observations are converted: <gauge|summary>.
+	ObserverType string `mapstructure:"observer_type"`
 }
 
 // StatsDParser supports the Parse method for parsing StatsD messages with Tags.
 type StatsDParser struct {
-	gauges   map[statsDMetricdescription]*metricspb.Metric
-	counters map[statsDMetricdescription]*metricspb.Metric
+	gauges                 map[statsDMetricdescription]*metricspb.Metric
+	counters               map[statsDMetricdescription]*metricspb.Metric
+	timersAndDistributions []*metricspb.Metric
+	summaries              map[statsDMetricdescription]*summaryMetric
+
+	// observerTypes holds the timer and histogram observer types, by statsd metric type
+	observerTypes map[string]string
+}
+
+// summaryMetric holds the timer or histogram observations aggregated to a summary
+type summaryMetric struct {
+	metric statsDMetric
+	values []float64
+	count  float64
+	sum    float64
 }
 
 type statsDMetric struct {
@@ -60,9 +100,53 @@ type statsDMetricdescription struct {
 	labels           label.Distinct
 }
 
-func (p *StatsDParser) Initialize() error {
+// ValidateTimerHistogramMapping returns an error when the given mapping has unknown types,
+// or more than one entry for the same statsd type.
+func ValidateTimerHistogramMapping(timerHistogramMapping []TimerHistogramMapping) error {
+	seen := map[string]bool{}
+	for _, mapping := range timerHistogramMapping {
+		switch mapping.StatsdType {
+		case TimerStatsdType, HistogramStatsdType:
+		default:
+			return fmt.Errorf("invalid statsd_type %q, must be one of %q or %q", mapping.StatsdType, TimerStatsdType, HistogramStatsdType)
+		}
+		switch mapping.ObserverType {
+		case GaugeObserverType, SummaryObserverType:
+		default:
+			return fmt.Errorf("invalid observer_type %q, must be one of %q or %q", mapping.ObserverType, GaugeObserverType, SummaryObserverType)
+		}
+		if seen[mapping.StatsdType] {
+			return fmt.Errorf("duplicate statsd_type %q", mapping.StatsdType)
+		}
+		seen[mapping.StatsdType] = true
+	}
+	return nil
+}
+
+// Initialize resets the state of the parser. The timers and the histograms for which there's no mapping
+// are converted to gauges.
+func (p *StatsDParser) Initialize(timerHistogramMapping []TimerHistogramMapping) error {
+	if err := ValidateTimerHistogramMapping(timerHistogramMapping); err != nil {
+		return err
+	}
+
 	p.gauges = make(map[statsDMetricdescription]*metricspb.Metric)
 	p.counters = make(map[statsDMetricdescription]*metricspb.Metric)
+	p.timersAndDistributions = nil
+	p.summaries = make(map[statsDMetricdescription]*summaryMetric)
+
+	p.observerTypes = map[string]string{
+		"ms": GaugeObserverType,
+		"h":  GaugeObserverType,
+	}
+	for _, mapping := range timerHistogramMapping {
+		switch mapping.StatsdType {
+		case TimerStatsdType:
+			p.observerTypes["ms"] = mapping.ObserverType
+		case HistogramStatsdType:
+			p.observerTypes["h"] = mapping.ObserverType
+		}
+	}
 	return nil
 }
 
@@ -78,8 +162,16 @@ func (p *StatsDParser) GetMetrics() []*metricspb.Metric {
 		metrics = append(metrics, metric)
 	}
 
+	metrics = append(metrics, p.timersAndDistributions...)
+
+	for _, summary := range p.summaries {
+		metrics = append(metrics, buildSummaryMetric(summary))
+	}
+
 	p.gauges = make(map[statsDMetricdescription]*metricspb.Metric)
 	p.counters = make(map[statsDMetricdescription]*metricspb.Metric)
+	p.timersAndDistributions = nil
+	p.summaries = make(map[statsDMetricdescription]*summaryMetric)
 
 	return metrics
 }
@@ -123,6 +215,27 @@ func (p *StatsDParser) Aggregate(line string) error {
 			metricPoint := buildPoint(parsedMetric)
 			p.counters[parsedMetric.description] = buildMetric(parsedMetric, metricPoint)
 		}
+
+	case "ms", "h":
+		switch p.observerTypes[parsedMetric.description.statsdMetricType] {
+		case SummaryObserverType:
+			summary, ok := p.summaries[parsedMetric.description]
+			if !ok {
+				summary = &summaryMetric{metric: parsedMetric}
+				p.summaries[parsedMetric.description] = summary
+			}
+			// each observation stands for 1/<sample-rate> observations
+			weight := 1.0
+			if 0 < parsedMetric.sampleRate && parsedMetric.sampleRate < 1 {
+				weight = 1 / parsedMetric.sampleRate
+			}
+			summary.values = append(summary.values, parsedMetric.floatvalue)
+			summary.count += weight
+			summary.sum += parsedMetric.floatvalue * weight
+		default:
+			metricPoint := buildPoint(parsedMetric)
+			p.timersAndDistributions = append(p.timersAndDistributions, buildMetric(parsedMetric, metricPoint))
+		}
 	}
 
 	return nil
@@ -218,6 +331,16 @@ func parseMessageToMetric(line string) (statsDMetric, error) {
 		}
 		result.intvalue = i
 		result.metricType = metricspb.MetricDescriptor_GAUGE_INT64
+	case "ms", "h":
+		f, err := strconv.ParseFloat(result.value, 64)
+		if err != nil {
+			return result, fmt.Errorf("timer or histogram: parse metric value string: %s", result.value)
+		}
+		result.floatvalue = f
+		result.metricType = metricspb.MetricDescriptor_GAUGE_DOUBLE
+		if result.description.statsdMetricType == "ms" {
+			result.unit = "ms"
+		}
 	}
 
 	return result, nil
@@ -259,7 +382,7 @@ func buildPoint(parsedMetric statsDMetric) *metricspb.Point {
 	switch parsedMetric.description.statsdMetricType {
 	case "c":
 		return buildCounterPoint(parsedMetric, now)
-	case "g":
+	case "g", "ms", "h":
 		return buildGaugePoint(parsedMetric, now)
 	}
 
@@ -285,3 +408,45 @@ func buildGaugePoint(parsedMetric statsDMetric, now *timestamppb.Timestamp) *met
 	}
 	return point
 }
+
+// buildSummaryMetric returns the summary of the given observations, considering their sample rates for the count
+// and the sum, while the percentiles are computed from the observations themselves
+func buildSummaryMetric(summary *summaryMetric) *metricspb.Metric {
+	values := summary.values
+	sort.Float64s(values)
+
+	percentiles := make([]*metricspb.SummaryValue_Snapshot_ValueAtPercentile, 0, len(summaryPercentiles))
+	for _, percentile := range summaryPercentiles {
+		percentiles = append(percentiles, &metricspb.SummaryValue_Snapshot_ValueAtPercentile{
+			Percentile: percentile,
+			Value:      nearestRank(values, percentile),
+		})
+	}
+
+	metric := summary.metric
+	metric.metricType = metricspb.MetricDescriptor_SUMMARY
+	point := &metricspb.Point{
+		Timestamp: &timestamppb.Timestamp{
+			Seconds: timeNowFunc(),
+		},
+		Value: &metricspb.Point_SummaryValue{
+			SummaryValue: &metricspb.SummaryValue{
+				Count: wrapperspb.Int64(int64(math.Round(summary.count))),
+				Sum:   wrapperspb.Double(summary.sum),
+				Snapshot: &metricspb.SummaryValue_Snapshot{
+					PercentileValues: percentiles,
+				},
+			},
+		},
+	}
+	return buildMetric(metric, point)
+}
+
+// nearestRank returns the given percentile of the given sorted values, using the nearest-rank method
+func nearestRank(sorted []float64, percentile float64) float64 {
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -20,6 +20,7 @@ import (
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/label"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -281,6 +282,36 @@ func Test_ParseMessageToMetric(t *testing.T) {
 				true,
 				"g", 2, 0, nil, nil),
 		},
+		{
+			name:  "timer",
+			input: "test.metric:12.5|ms|@0.5",
+			wantMetric: statsDMetric{
+				description: statsDMetricdescription{
+					name:             "test.metric",
+					statsdMetricType: "ms",
+				},
+				value:      "12.5",
+				floatvalue: 12.5,
+				unit:       "ms",
+				metricType: metricspb.MetricDescriptor_GAUGE_DOUBLE,
+				sampleRate: 0.5,
+			},
+		},
+		{
+			name:  "histogram",
+			input: "test.metric:42|h",
+			wantMetric: testStatsDMetric(
+				"42",
+				0,
+				42,
+				false,
+				"h", 2, 0, nil, nil),
+		},
+		{
+			name:  "invalid histogram metric value",
+			input: "test.metric:42.abc|h",
+			err:   errors.New("timer or histogram: parse metric value string: 42.abc"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -822,7 +853,7 @@ func TestStatsDParser_Aggregate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			p.Initialize()
+			p.Initialize(nil)
 			for _, line := range tt.input {
 				err = p.Aggregate(line)
 			}
@@ -902,9 +933,84 @@ func Test_contains(t *testing.T) {
 	}
 }
 
+func TestStatsDParser_AggregateTimersAndHistograms(t *testing.T) {
+	timeNowFunc = func() int64 {
+		return 0
+	}
+
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize([]TimerHistogramMapping{
+		{StatsdType: TimerStatsdType, ObserverType: SummaryObserverType},
+	}))
+	for _, line := range []string{
+		"statsdTestMetric1:10|ms|#mykey:myvalue",
+		"statsdTestMetric1:40|ms|#mykey:myvalue",
+		"statsdTestMetric1:20|ms|@0.5|#mykey:myvalue",
+		"statsdTestMetric1:30|ms|#mykey:myvalue",
+		"statsdTestMetric2:5|h",
+		"statsdTestMetric2:6|h",
+	} {
+		assert.NoError(t, p.Aggregate(line))
+	}
+
+	// the histograms are converted to gauges by default, one for each observation
+	assert.Equal(t, []*metricspb.Metric{
+		testMetric("statsdTestMetric2", metricspb.MetricDescriptor_GAUGE_DOUBLE, nil, nil, &metricspb.Point{
+			Timestamp: &timestamppb.Timestamp{},
+			Value:     &metricspb.Point_DoubleValue{DoubleValue: 5},
+		}),
+		testMetric("statsdTestMetric2", metricspb.MetricDescriptor_GAUGE_DOUBLE, nil, nil, &metricspb.Point{
+			Timestamp: &timestamppb.Timestamp{},
+			Value:     &metricspb.Point_DoubleValue{DoubleValue: 6},
+		}),
+	}, p.timersAndDistributions)
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 3)
+	summary := metrics[2]
+	assert.Equal(t, "statsdTestMetric1", summary.GetMetricDescriptor().GetName())
+	assert.Equal(t, metricspb.MetricDescriptor_SUMMARY, summary.GetMetricDescriptor().GetType())
+	assert.Equal(t, "ms", summary.GetMetricDescriptor().GetUnit())
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "mykey"}}, summary.GetMetricDescriptor().GetLabelKeys())
+	require.Len(t, summary.GetTimeseries(), 1)
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "myvalue", HasValue: true}}, summary.GetTimeseries()[0].GetLabelValues())
+
+	// the observation with a sample rate of 0.5 stands for 2 of them
+	value := summary.GetTimeseries()[0].GetPoints()[0].GetSummaryValue()
+	assert.EqualValues(t, 5, value.GetCount().GetValue())
+	assert.EqualValues(t, 120, value.GetSum().GetValue())
+	var percentiles []float64
+	for _, percentile := range value.GetSnapshot().GetPercentileValues() {
+		percentiles = append(percentiles, percentile.GetValue())
+	}
+	assert.Equal(t, []float64{10, 10, 20, 40, 40, 40}, percentiles)
+
+	// the state is reset after each flush
+	assert.Empty(t, p.GetMetrics())
+}
+
+func TestStatsDParser_InitializeWithInvalidMapping(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		mapping []TimerHistogramMapping
+	}{
+		{"invalid statsd type", []TimerHistogramMapping{{StatsdType: "distribution", ObserverType: GaugeObserverType}}},
+		{"invalid observer type", []TimerHistogramMapping{{StatsdType: TimerStatsdType, ObserverType: "histogram"}}},
+		{"duplicate statsd type", []TimerHistogramMapping{
+			{StatsdType: TimerStatsdType, ObserverType: GaugeObserverType},
+			{StatsdType: TimerStatsdType, ObserverType: SummaryObserverType},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StatsDParser{}
+			assert.Error(t, p.Initialize(tt.mapping))
+		})
+	}
+}
+
 func TestStatsDParser_Initialize(t *testing.T) {
 	p := &StatsDParser{}
-	p.Initialize()
+	p.Initialize(nil)
 	labels := label.Distinct{}
 	teststatsdDMetricdescription := statsDMetricdescription{
 		name:             "test",
@@ -918,7 +1024,7 @@ func TestStatsDParser_Initialize(t *testing.T) {
 
 func TestStatsDParser_GetMetrics(t *testing.T) {
 	p := &StatsDParser{}
-	p.Initialize()
+	p.Initialize(nil)
 	p.gauges[testDescription("statsdTestMetric1", "g",
 		[]string{"mykey"}, []string{"myvalue"})] = testMetric("testGauge1",
 		metricspb.MetricDescriptor_GAUGE_DOUBLE,
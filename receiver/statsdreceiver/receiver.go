@@ -65,6 +65,10 @@ func New(
 		config.NetAddr.Endpoint = "localhost:8125"
 	}
 
+	if err := protocol.ValidateTimerHistogramMapping(config.TimerHistogramMapping); err != nil {
+		return nil, err
+	}
+
 	server, err := buildTransportServer(config)
 	if err != nil {
 		return nil, err
@@ -100,8 +104,10 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 		ctx, r.cancel = context.WithCancel(ctx)
 		var transferChan = make(chan string, 10)
 		ticker := time.NewTicker(r.config.AggregationInterval)
-		err = nil
-		r.parser.Initialize()
+		err = r.parser.Initialize(r.config.TimerHistogramMapping)
+		if err != nil {
+			return
+		}
 		go func() {
 			err = r.server.ListenAndServe(r.parser, r.nextConsumer, r.reporter, transferChan)
 			if err != nil {
@@ -117,7 +123,11 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 						r.Flush(ctx, metrics, r.nextConsumer)
 					}
 				case rawMetric := <-transferChan:
-					r.parser.Aggregate(rawMetric)
+					// a malformed line is only reported, without affecting the other ones
+					if err := r.parser.Aggregate(rawMetric); err != nil {
+						r.reporter.OnTranslationError(ctx, err)
+						recordMalformedLine()
+					}
 				case <-ctx.Done():
 					ticker.Stop()
 					return
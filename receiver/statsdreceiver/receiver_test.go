@@ -35,6 +35,7 @@ import (
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport/client"
 )
@@ -71,6 +72,20 @@ func Test_statsdreceiver_New(t *testing.T) {
 			},
 			wantErr: errors.New("unsupported transport \"unknown\" for receiver \"statsd\""),
 		},
+		{
+			name: "invalid timer histogram mapping",
+			args: args{
+				config: Config{
+					ReceiverSettings: defaultConfig.ReceiverSettings,
+					NetAddr:          defaultConfig.NetAddr,
+					TimerHistogramMapping: []protocol.TimerHistogramMapping{
+						{StatsdType: "timer", ObserverType: "histogram"},
+					},
+				},
+				nextConsumer: consumertest.NewMetricsNop(),
+			},
+			wantErr: errors.New("invalid observer_type \"histogram\", must be one of \"gauge\" or \"summary\""),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -144,6 +159,14 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 
 			statsdClient := tt.clientFn(t)
 
+			// a malformed line doesn't prevent the next ones from being received
+			err = statsdClient.SendMetric(client.Metric{
+				Name:  "test.malformed",
+				Value: "42",
+				Type:  "unknown",
+			})
+			require.NoError(t, err)
+
 			statsdMetric := client.Metric{
 				Name:  "test.metric",
 				Value: "42",
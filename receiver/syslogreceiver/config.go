@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	// RFC5424 is the protocol of the syslog messages as defined in https://tools.ietf.org/html/rfc5424.
+	RFC5424 = "rfc5424"
+	// RFC3164 is the protocol of the BSD syslog messages as defined in https://tools.ietf.org/html/rfc3164.
+	RFC3164 = "rfc3164"
+
+	// NewlineFraming separates the messages received over TCP by newlines (non-transparent framing).
+	NewlineFraming = "newline"
+	// OctetCountingFraming prefixes each message received over TCP by its length, as defined in
+	// https://tools.ietf.org/html/rfc6587#section-3.4.1.
+	OctetCountingFraming = "octet_counting"
+)
+
+// Config defines configuration for the syslog receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// Protocol is the format of the syslog messages, either rfc5424 (default) or rfc3164.
+	Protocol string `mapstructure:"protocol"`
+
+	// Location is the time zone of the RFC 3164 timestamps, which don't include it. UTC by default.
+	Location string `mapstructure:"location"`
+
+	// TCP configures the reception of the messages over TCP. At least one of TCP and UDP must be set.
+	TCP *TCPConfig `mapstructure:"tcp"`
+
+	// UDP configures the reception of the messages over UDP. At least one of TCP and UDP must be set.
+	UDP *UDPConfig `mapstructure:"udp"`
+}
+
+// TCPConfig defines the settings of the TCP listener.
+type TCPConfig struct {
+	// ListenAddress is the address to listen on, as host:port.
+	ListenAddress string `mapstructure:"listen_address"`
+
+	// TLSSetting enables TLS when set.
+	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls"`
+
+	// Framing separates the messages, with newline (default) or octet_counting.
+	Framing string `mapstructure:"framing"`
+
+	// MaxLogSize is the maximum size of a message in bytes, 1MiB by default.
+	MaxLogSize int `mapstructure:"max_log_size"`
+}
+
+// UDPConfig defines the settings of the UDP listener.
+type UDPConfig struct {
+	// ListenAddress is the address to listen on, as host:port.
+	ListenAddress string `mapstructure:"listen_address"`
+}
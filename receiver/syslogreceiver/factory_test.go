@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateLogsReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.UDP = &UDPConfig{ListenAddress: "localhost:0"}
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	receiver, err := factory.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, receiver)
+}
+
+func TestCreateLogsReceiverInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "no transport",
+			modify: func(cfg *Config) {},
+			err:    "at least one of tcp or udp must be configured",
+		},
+		{
+			name: "invalid protocol",
+			modify: func(cfg *Config) {
+				cfg.Protocol = "rfc1234"
+				cfg.UDP = &UDPConfig{ListenAddress: "localhost:0"}
+			},
+			err: `unsupported protocol "rfc1234", must be one of "rfc5424" or "rfc3164"`,
+		},
+		{
+			name: "invalid location",
+			modify: func(cfg *Config) {
+				cfg.Location = "Nowhere/Somewhere"
+				cfg.UDP = &UDPConfig{ListenAddress: "localhost:0"}
+			},
+			err: `invalid location "Nowhere/Somewhere": unknown time zone Nowhere/Somewhere`,
+		},
+		{
+			name: "invalid framing",
+			modify: func(cfg *Config) {
+				cfg.TCP = &TCPConfig{ListenAddress: "localhost:0", Framing: "length"}
+			},
+			err: `unsupported tcp.framing "length", must be one of "newline" or "octet_counting"`,
+		},
+		{
+			name: "missing tcp listen address",
+			modify: func(cfg *Config) {
+				cfg.TCP = &TCPConfig{}
+			},
+			err: "tcp.listen_address must be specified",
+		},
+		{
+			name: "missing udp listen address",
+			modify: func(cfg *Config) {
+				cfg.UDP = &UDPConfig{}
+			},
+			err: "udp.listen_address must be specified",
+		},
+		{
+			name: "negative max log size",
+			modify: func(cfg *Config) {
+				cfg.TCP = &TCPConfig{ListenAddress: "localhost:0", MaxLogSize: -1}
+			},
+			err: "tcp.max_log_size can't be negative: -1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			tt.modify(cfg)
+
+			params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			_, err := factory.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewLogsNop())
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
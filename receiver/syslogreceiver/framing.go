@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// maxOctetCountDigits is the maximum length of the MSG-LEN prefix of the octet counted messages
+const maxOctetCountDigits = 10
+
+var errMissingOctetCount = errors.New("octet counted message without a length")
+
+// splitOctetCounting returns a split function for the messages framed with octet counting,
+// each message being prefixed by its length in bytes and a space: "MSG-LEN SP SYSLOG-MSG"
+func splitOctetCounting(maxLogSize int) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		// some senders separate the frames with newlines too
+		skipped := 0
+		for skipped < len(data) && (data[skipped] == '\n' || data[skipped] == '\r') {
+			skipped++
+		}
+		data = data[skipped:]
+
+		sp := bytes.IndexByte(data, ' ')
+		if sp < 0 {
+			if len(data) > maxOctetCountDigits || (atEOF && len(data) > 0) {
+				return 0, nil, errMissingOctetCount
+			}
+			return skipped, nil, nil
+		}
+
+		length, err := strconv.Atoi(string(data[:sp]))
+		if err != nil || length <= 0 {
+			return 0, nil, fmt.Errorf("invalid octet count %q", data[:sp])
+		}
+		if length > maxLogSize {
+			return 0, nil, fmt.Errorf("octet counted message of %d bytes exceeds the maximum size of %d bytes", length, maxLogSize)
+		}
+
+		end := sp + 1 + length
+		if len(data) < end {
+			if atEOF {
+				return 0, nil, fmt.Errorf("octet counted message truncated to %d of its %d bytes", len(data)-sp-1, length)
+			}
+			return skipped, nil, nil
+		}
+		return skipped + end, data[sp+1 : end], nil
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scanOctetCounted(input string, maxLogSize int) ([]string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	scanner.Split(splitOctetCounting(maxLogSize))
+	var msgs []string
+	for scanner.Scan() {
+		msgs = append(msgs, scanner.Text())
+	}
+	return msgs, scanner.Err()
+}
+
+func TestSplitOctetCounting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"single message", "11 <13>1 - - -", []string{"<13>1 - - -"}},
+		{"several messages", "5 first6 second", []string{"first", "second"}},
+		{"newlines in messages", "6 a\nb c\n4 d\ne\n", []string{"a\nb c\n", "d\ne\n"}},
+		{"newlines between frames", "5 first\n\r\n6 second\n", []string{"first", "second"}},
+		{"empty input", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := scanOctetCounted(tt.input, 1024)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, msgs)
+		})
+	}
+}
+
+func TestSplitOctetCountingErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		err      string
+	}{
+		{"missing length", "5 first<13>1 - - -", []string{"first"}, `invalid octet count "<13>1"`},
+		{"invalid length", "-5 first", nil, `invalid octet count "-5"`},
+		{"too long", "2048 " + strings.Repeat("a", 2048), nil, "octet counted message of 2048 bytes exceeds the maximum size of 1024 bytes"},
+		{"truncated", "10 short", nil, "octet counted message truncated to 5 of its 10 bytes"},
+		{"no space", "5 first12345678901", []string{"first"}, errMissingOctetCount.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := scanOctetCounted(tt.input, 1024)
+			assert.EqualError(t, err, tt.err)
+			assert.Equal(t, tt.expected, msgs)
+		})
+	}
+}
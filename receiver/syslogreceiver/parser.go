@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/go-syslog/v3"
+	"github.com/influxdata/go-syslog/v3/rfc3164"
+	"github.com/influxdata/go-syslog/v3/rfc5424"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The attributes of the log records
+const (
+	attributeFacility       = "syslog.facility"
+	attributeHostname       = "syslog.hostname"
+	attributeAppname        = "syslog.appname"
+	attributeProcID         = "syslog.proc_id"
+	attributeMsgID          = "syslog.msg_id"
+	attributeVersion        = "syslog.version"
+	attributeStructuredData = "syslog.structured_data"
+	attributeParseError     = "syslog.parse_error"
+)
+
+// severityNumbers maps the syslog severities, from 0 (emergency) to 7 (debug), to the OpenTelemetry severity numbers
+var severityNumbers = [...]pdata.SeverityNumber{
+	pdata.SeverityNumberFATAL4,
+	pdata.SeverityNumberFATAL3,
+	pdata.SeverityNumberFATAL2,
+	pdata.SeverityNumberERROR,
+	pdata.SeverityNumberWARN,
+	pdata.SeverityNumberINFO2,
+	pdata.SeverityNumberINFO,
+	pdata.SeverityNumberDEBUG,
+}
+
+// parser converts the syslog messages to log records. It isn't safe for concurrent use, as the underlying
+// machine keeps the state of the message being parsed.
+type parser struct {
+	machine  syslog.Machine
+	location *time.Location
+	now      func() time.Time
+}
+
+func newParser(protocol string, location *time.Location) (*parser, error) {
+	var machine syslog.Machine
+	switch protocol {
+	case RFC5424:
+		machine = rfc5424.NewParser()
+	case RFC3164:
+		machine = rfc3164.NewParser(rfc3164.WithYear(rfc3164.CurrentYear{}))
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q, must be one of %q or %q", protocol, RFC5424, RFC3164)
+	}
+	return &parser{machine: machine, location: location, now: time.Now}, nil
+}
+
+// parse converts the given message into the given log record. A message which can't be parsed isn't dropped, but
+// kept as the body of the log record, along with a syslog.parse_error attribute.
+func (p *parser) parse(raw []byte, lr pdata.LogRecord) {
+	msg, err := p.machine.Parse(raw)
+	if err != nil {
+		lr.SetTimestamp(pdata.TimestampUnixNano(p.now().UnixNano()))
+		lr.Body().SetStringVal(string(raw))
+		lr.Attributes().InsertString(attributeParseError, err.Error())
+		return
+	}
+
+	var base *syslog.Base
+	timestamp := p.now()
+	attrs := lr.Attributes()
+	switch m := msg.(type) {
+	case *rfc5424.SyslogMessage:
+		base = &m.Base
+		if base.Timestamp != nil {
+			timestamp = *base.Timestamp
+		}
+		attrs.InsertInt(attributeVersion, int64(m.Version))
+		if m.StructuredData != nil {
+			insertStructuredData(attrs, *m.StructuredData)
+		}
+	case *rfc3164.SyslogMessage:
+		base = &m.Base
+		if t := base.Timestamp; t != nil {
+			// the timestamp doesn't include its time zone, and is parsed as UTC
+			timestamp = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), p.location)
+		}
+	}
+	lr.SetTimestamp(pdata.TimestampUnixNano(timestamp.UnixNano()))
+
+	if base.Severity != nil && int(*base.Severity) < len(severityNumbers) {
+		lr.SetSeverityNumber(severityNumbers[*base.Severity])
+		lr.SetSeverityText(*base.SeverityShortLevel())
+	}
+	if base.Facility != nil {
+		attrs.InsertInt(attributeFacility, int64(*base.Facility))
+	}
+	insertOptionalString(attrs, attributeHostname, base.Hostname)
+	insertOptionalString(attrs, attributeAppname, base.Appname)
+	insertOptionalString(attrs, attributeProcID, base.ProcID)
+	insertOptionalString(attrs, attributeMsgID, base.MsgID)
+
+	if base.Message != nil {
+		lr.Body().SetStringVal(*base.Message)
+	}
+}
+
+func insertOptionalString(attrs pdata.AttributeMap, key string, value *string) {
+	if value != nil {
+		attrs.InsertString(key, *value)
+	}
+}
+
+// insertStructuredData flattens the structured data elements into syslog.structured_data.<SD-ID>.<PARAM-NAME>
+// attributes, in order
+func insertStructuredData(attrs pdata.AttributeMap, data map[string]map[string]string) {
+	ids := make([]string, 0, len(data))
+	for id := range data {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		params := data[id]
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			attrs.InsertString(attributeStructuredData+"."+id+"."+name, params[name])
+		}
+	}
+}
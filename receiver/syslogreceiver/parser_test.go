@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func newTestParser(t *testing.T, protocol string) *parser {
+	p, err := newParser(protocol, time.UTC)
+	require.NoError(t, err)
+	p.now = func() time.Time {
+		return time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	return p
+}
+
+func parseRecord(p *parser, msg string) pdata.LogRecord {
+	lr := pdata.NewLogRecord()
+	p.parse([]byte(msg), lr)
+	return lr
+}
+
+func TestParseRFC5424(t *testing.T) {
+	p := newTestParser(t, RFC5424)
+	lr := parseRecord(p, `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 1234 ID47 `+
+		`[exampleSDID@32473 iut="3" eventSource="Application"][examplePriority@32473 class="high"] An application event`)
+
+	assert.Equal(t, pdata.TimestampUnixNano(time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC).UnixNano()), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberINFO2, lr.SeverityNumber())
+	assert.Equal(t, "notice", lr.SeverityText())
+	assert.Equal(t, "An application event", lr.Body().StringVal())
+
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"syslog.facility": pdata.NewAttributeValueInt(20),
+		"syslog.version":  pdata.NewAttributeValueInt(1),
+		"syslog.hostname": pdata.NewAttributeValueString("mymachine.example.com"),
+		"syslog.appname":  pdata.NewAttributeValueString("evntslog"),
+		"syslog.proc_id":  pdata.NewAttributeValueString("1234"),
+		"syslog.msg_id":   pdata.NewAttributeValueString("ID47"),
+		"syslog.structured_data.exampleSDID@32473.iut":         pdata.NewAttributeValueString("3"),
+		"syslog.structured_data.exampleSDID@32473.eventSource": pdata.NewAttributeValueString("Application"),
+		"syslog.structured_data.examplePriority@32473.class":   pdata.NewAttributeValueString("high"),
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestParseRFC5424NilValues(t *testing.T) {
+	p := newTestParser(t, RFC5424)
+	lr := parseRecord(p, `<11>1 - - - - - -`)
+
+	assert.Equal(t, pdata.TimestampUnixNano(p.now().UnixNano()), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "err", lr.SeverityText())
+	assert.Equal(t, pdata.AttributeValueNULL, lr.Body().Type())
+
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"syslog.facility": pdata.NewAttributeValueInt(1),
+		"syslog.version":  pdata.NewAttributeValueInt(1),
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestParseRFC3164(t *testing.T) {
+	p := newTestParser(t, RFC3164)
+	lr := parseRecord(p, `<34>Oct 11 22:14:15 mymachine su[42]: 'su root' failed for lonvick on /dev/pts/8`)
+
+	year := time.Now().Year()
+	assert.Equal(t, pdata.TimestampUnixNano(time.Date(year, 10, 11, 22, 14, 15, 0, time.UTC).UnixNano()), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberFATAL2, lr.SeverityNumber())
+	assert.Equal(t, "crit", lr.SeverityText())
+	assert.Equal(t, "'su root' failed for lonvick on /dev/pts/8", lr.Body().StringVal())
+
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"syslog.facility": pdata.NewAttributeValueInt(4),
+		"syslog.hostname": pdata.NewAttributeValueString("mymachine"),
+		"syslog.appname":  pdata.NewAttributeValueString("su"),
+		"syslog.proc_id":  pdata.NewAttributeValueString("42"),
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestParseRFC3164Location(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	p, err := newParser(RFC3164, location)
+	require.NoError(t, err)
+
+	lr := parseRecord(p, `<34>Jan 11 22:14:15 mymachine su: failed`)
+	year := time.Now().Year()
+	assert.Equal(t, pdata.TimestampUnixNano(time.Date(year, 1, 11, 22, 14, 15, 0, location).UnixNano()), lr.Timestamp())
+}
+
+func TestSeverities(t *testing.T) {
+	expected := []struct {
+		number pdata.SeverityNumber
+		text   string
+	}{
+		{pdata.SeverityNumberFATAL4, "emerg"},
+		{pdata.SeverityNumberFATAL3, "alert"},
+		{pdata.SeverityNumberFATAL2, "crit"},
+		{pdata.SeverityNumberERROR, "err"},
+		{pdata.SeverityNumberWARN, "warning"},
+		{pdata.SeverityNumberINFO2, "notice"},
+		{pdata.SeverityNumberINFO, "info"},
+		{pdata.SeverityNumberDEBUG, "debug"},
+	}
+
+	p := newTestParser(t, RFC5424)
+	for severity, e := range expected {
+		// facility 1 (user-level messages)
+		lr := parseRecord(p, fmt.Sprintf("<%d>1 - - - - - - msg", 8+severity))
+		assert.Equal(t, e.number, lr.SeverityNumber(), e.text)
+		assert.Equal(t, e.text, lr.SeverityText())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		msg      string
+	}{
+		{"rfc5424 without version", RFC5424, "<34>Oct 11 22:14:15 mymachine su: failed"},
+		{"rfc5424 without priority", RFC5424, "not a syslog message"},
+		{"rfc3164 without priority", RFC3164, "not a syslog message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParser(t, tt.protocol)
+			lr := parseRecord(p, tt.msg)
+
+			assert.Equal(t, pdata.TimestampUnixNano(p.now().UnixNano()), lr.Timestamp())
+			assert.Equal(t, pdata.SeverityNumberUNDEFINED, lr.SeverityNumber())
+			assert.Equal(t, tt.msg, lr.Body().StringVal())
+			assert.Equal(t, 1, lr.Attributes().Len())
+			parseErr, ok := lr.Attributes().Get(attributeParseError)
+			require.True(t, ok)
+			assert.NotEmpty(t, parseErr.StringVal())
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+const (
+	tcpTransport = "tcp"
+	udpTransport = "udp"
+
+	dataFormat = "syslog"
+
+	// maxUDPPacketSize is the largest payload of a UDP datagram
+	maxUDPPacketSize = 65535
+)
+
+var errMissingTransport = errors.New("at least one of tcp or udp must be configured")
+
+// syslogReceiver receives syslog messages over TCP and UDP and converts them to log records.
+type syslogReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	location     *time.Location
+	nextConsumer consumer.LogsConsumer
+
+	tcpListener net.Listener
+	udpConn     net.PacketConn
+
+	wg        sync.WaitGroup
+	connsLock sync.Mutex
+	conns     map[net.Conn]struct{}
+	stopOnce  sync.Once
+}
+
+var _ component.LogsReceiver = (*syslogReceiver)(nil)
+
+func newSyslogReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.LogsConsumer,
+) (*syslogReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if config.TCP == nil && config.UDP == nil {
+		return nil, errMissingTransport
+	}
+
+	location, err := time.LoadLocation(config.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location %q: %w", config.Location, err)
+	}
+	if _, err = newParser(config.Protocol, location); err != nil {
+		return nil, err
+	}
+
+	if config.TCP != nil {
+		if config.TCP.ListenAddress == "" {
+			return nil, errors.New("tcp.listen_address must be specified")
+		}
+		switch config.TCP.Framing {
+		case "":
+			config.TCP.Framing = NewlineFraming
+		case NewlineFraming, OctetCountingFraming:
+		default:
+			return nil, fmt.Errorf("unsupported tcp.framing %q, must be one of %q or %q",
+				config.TCP.Framing, NewlineFraming, OctetCountingFraming)
+		}
+		if config.TCP.MaxLogSize < 0 {
+			return nil, fmt.Errorf("tcp.max_log_size can't be negative: %d", config.TCP.MaxLogSize)
+		}
+		if config.TCP.MaxLogSize == 0 {
+			config.TCP.MaxLogSize = defaultMaxLogSize
+		}
+	}
+	if config.UDP != nil && config.UDP.ListenAddress == "" {
+		return nil, errors.New("udp.listen_address must be specified")
+	}
+
+	return &syslogReceiver{
+		logger:       logger,
+		config:       config,
+		location:     location,
+		nextConsumer: nextConsumer,
+		conns:        make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Start starts listening on the configured TCP and UDP addresses.
+func (r *syslogReceiver) Start(_ context.Context, _ component.Host) error {
+	if r.config.TCP != nil {
+		listener, err := net.Listen("tcp", r.config.TCP.ListenAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s over tcp: %w", r.config.TCP.ListenAddress, err)
+		}
+		if r.config.TCP.TLSSetting != nil {
+			var tlsConfig *tls.Config
+			tlsConfig, err = r.config.TCP.TLSSetting.LoadTLSConfig()
+			if err != nil {
+				listener.Close()
+				return err
+			}
+			listener = tls.NewListener(listener, tlsConfig)
+		}
+		r.tcpListener = listener
+
+		r.wg.Add(1)
+		go r.acceptTCP()
+	}
+
+	if r.config.UDP != nil {
+		conn, err := net.ListenPacket("udp", r.config.UDP.ListenAddress)
+		if err != nil {
+			if r.tcpListener != nil {
+				r.tcpListener.Close()
+			}
+			return fmt.Errorf("failed to listen on %s over udp: %w", r.config.UDP.ListenAddress, err)
+		}
+		r.udpConn = conn
+
+		r.wg.Add(1)
+		go r.readUDP()
+	}
+
+	return nil
+}
+
+// Shutdown stops listening and closes the open connections.
+func (r *syslogReceiver) Shutdown(context.Context) error {
+	var err error
+	r.stopOnce.Do(func() {
+		if r.tcpListener != nil {
+			err = r.tcpListener.Close()
+		}
+		if r.udpConn != nil {
+			if udpErr := r.udpConn.Close(); udpErr != nil {
+				err = udpErr
+			}
+		}
+
+		r.connsLock.Lock()
+		for conn := range r.conns {
+			conn.Close()
+		}
+		r.connsLock.Unlock()
+
+		r.wg.Wait()
+	})
+	return err
+}
+
+func (r *syslogReceiver) acceptTCP() {
+	defer r.wg.Done()
+
+	for {
+		conn, err := r.tcpListener.Accept()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				r.logger.Debug("temporary error accepting a syslog connection", zap.Error(err))
+				continue
+			}
+			// the listener was closed
+			return
+		}
+
+		r.connsLock.Lock()
+		r.conns[conn] = struct{}{}
+		r.connsLock.Unlock()
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.handleTCPConnection(conn)
+
+			r.connsLock.Lock()
+			delete(r.conns, conn)
+			r.connsLock.Unlock()
+		}()
+	}
+}
+
+// handleTCPConnection reads the messages of the given connection until it's closed. As the framing can't be
+// recovered from once broken, the connection is closed on framing errors.
+func (r *syslogReceiver) handleTCPConnection(conn net.Conn) {
+	defer conn.Close()
+
+	// the parser can't be shared among connections
+	p, _ := newParser(r.config.Protocol, r.location)
+
+	scanner := bufio.NewScanner(conn)
+	maxLogSize := r.config.TCP.MaxLogSize
+	scanner.Buffer(make([]byte, 0, 4096), maxLogSize+maxOctetCountDigits+1)
+	if r.config.TCP.Framing == OctetCountingFraming {
+		scanner.Split(splitOctetCounting(maxLogSize))
+	}
+
+	for scanner.Scan() {
+		msg := bytes.TrimRight(scanner.Bytes(), "\r\n")
+		if len(msg) == 0 {
+			continue
+		}
+		r.consume(p, msg, tcpTransport)
+	}
+
+	if err := scanner.Err(); err != nil {
+		r.logger.Warn("closing syslog connection",
+			zap.String("remote_address", conn.RemoteAddr().String()), zap.Error(err))
+	}
+}
+
+// readUDP reads the messages until the connection is closed, each datagram holding a single message.
+func (r *syslogReceiver) readUDP() {
+	defer r.wg.Done()
+
+	p, _ := newParser(r.config.Protocol, r.location)
+	buf := make([]byte, maxUDPPacketSize)
+	for {
+		n, _, err := r.udpConn.ReadFrom(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				r.logger.Debug("temporary error reading a syslog datagram", zap.Error(err))
+				continue
+			}
+			// the connection was closed
+			return
+		}
+
+		msg := bytes.TrimRight(buf[:n], "\r\n")
+		if len(msg) == 0 {
+			continue
+		}
+		r.consume(p, msg, udpTransport)
+	}
+}
+
+// consume converts the given message to a log record and sends it to the next consumer
+func (r *syslogReceiver) consume(p *parser, msg []byte, transport string) {
+	logs := pdata.NewLogs()
+	rls := logs.ResourceLogs()
+	rls.Resize(1)
+	ills := rls.At(0).InstrumentationLibraryLogs()
+	ills.Resize(1)
+	lrs := ills.At(0).Logs()
+	lrs.Resize(1)
+	p.parse(msg, lrs.At(0))
+
+	ctx := obsreport.StartLogsReceiveOp(context.Background(), r.config.Name(), transport)
+	err := r.nextConsumer.ConsumeLogs(ctx, logs)
+	obsreport.EndLogsReceiveOp(ctx, dataFormat, 1, err)
+	if err != nil {
+		r.logger.Debug("failed to consume syslog message", zap.Error(err))
+	}
+}
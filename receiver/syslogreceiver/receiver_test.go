@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+const (
+	testMessage1 = `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"] first event`
+	testMessage2 = `<11>1 2003-10-11T22:14:16.003Z mymachine.example.com evntslog - ID48 - second event`
+)
+
+func startReceiver(t *testing.T, cfg *Config) (*syslogReceiver, *consumertest.LogsSink) {
+	sink := new(consumertest.LogsSink)
+	r, err := newSyslogReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r, sink
+}
+
+// bodies returns the bodies of the received log records, waiting for the expected number of them
+func bodies(t *testing.T, sink *consumertest.LogsSink, expected int) []string {
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() >= expected
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		rls := logs.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			ills := rls.At(i).InstrumentationLibraryLogs()
+			for j := 0; j < ills.Len(); j++ {
+				lrs := ills.At(j).Logs()
+				for k := 0; k < lrs.Len(); k++ {
+					bodies = append(bodies, lrs.At(k).Body().StringVal())
+				}
+			}
+		}
+	}
+	return bodies
+}
+
+func TestTCPNewlineFraming(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{ListenAddress: testutil.GetAvailableLocalAddress(t)}
+	_, sink := startReceiver(t, cfg)
+
+	conn, err := net.Dial("tcp", cfg.TCP.ListenAddress)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "%s\n%s\r\nnot a syslog message\n", testMessage1, testMessage2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first event", "second event", "not a syslog message"}, bodies(t, sink, 3))
+
+	lr := sink.AllLogs()[2].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	_, ok := lr.Attributes().Get(attributeParseError)
+	assert.True(t, ok)
+}
+
+func TestTCPOctetCountingFraming(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{ListenAddress: testutil.GetAvailableLocalAddress(t), Framing: OctetCountingFraming}
+	_, sink := startReceiver(t, cfg)
+
+	conn, err := net.Dial("tcp", cfg.TCP.ListenAddress)
+	require.NoError(t, err)
+	defer conn.Close()
+	multiline := testMessage2 + "\nwith a second line"
+	_, err = fmt.Fprintf(conn, "%d %s%d %s", len(testMessage1), testMessage1, len(multiline), multiline)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first event", "second event\nwith a second line"}, bodies(t, sink, 2))
+
+	lr := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	sd, ok := lr.Attributes().Get("syslog.structured_data.exampleSDID@32473.iut")
+	require.True(t, ok)
+	assert.Equal(t, "3", sd.StringVal())
+	assert.Equal(t, pdata.SeverityNumberINFO2, lr.SeverityNumber())
+}
+
+func TestTCPFramingErrorClosesConnection(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{ListenAddress: testutil.GetAvailableLocalAddress(t), Framing: OctetCountingFraming}
+	_, sink := startReceiver(t, cfg)
+
+	conn, err := net.Dial("tcp", cfg.TCP.ListenAddress)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "%d %s%s", len(testMessage1), testMessage1, testMessage2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first event"}, bodies(t, sink, 1))
+
+	// the receiver closes the connection
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
+
+func TestUDP(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = RFC3164
+	cfg.UDP = &UDPConfig{ListenAddress: testutil.GetAvailableLocalAddress(t)}
+	_, sink := startReceiver(t, cfg)
+
+	conn, err := net.Dial("udp", cfg.UDP.ListenAddress)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"'su root' failed"}, bodies(t, sink, 1))
+
+	lr := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	hostname, ok := lr.Attributes().Get(attributeHostname)
+	require.True(t, ok)
+	assert.Equal(t, "mymachine", hostname.StringVal())
+}
+
+func TestTCPWithTLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{
+		ListenAddress: testutil.GetAvailableLocalAddress(t),
+		TLSSetting: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{CertFile: certFile, KeyFile: keyFile},
+		},
+	}
+	_, sink := startReceiver(t, cfg)
+
+	pemCert, err := ioutil.ReadFile(certFile)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(pemCert))
+
+	conn, err := tls.Dial("tcp", cfg.TCP.ListenAddress, &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "%s\n", testMessage1)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first event"}, bodies(t, sink, 1))
+}
+
+func TestStartInvalidTLS(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{
+		ListenAddress: testutil.GetAvailableLocalAddress(t),
+		TLSSetting: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{CertFile: "/nonexistent/cert.pem", KeyFile: "/nonexistent/key.pem"},
+		},
+	}
+	r, err := newSyslogReceiver(zap.NewNop(), cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+// writeCertificate writes a self-signed certificate for localhost and its key, returning their paths
+func writeCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "syslogreceiver")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	certFile := path.Join(dir, "cert.pem")
+	keyFile := path.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowseventlogreceiver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+const (
+	// StartAtBeginning reads the channel from its oldest event on first start.
+	StartAtBeginning = "beginning"
+	// StartAtEnd only reads the events logged after the first start.
+	StartAtEnd = "end"
+)
+
+var errMissingChannel = errors.New("channel must be specified")
+
+// Config defines configuration for the Windows Event Log receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// Channel is the channel to read the events of, such as Application, System or Security.
+	Channel string `mapstructure:"channel"`
+
+	// StartAt is where to start reading the channel from on first start, end (default) or beginning. Once
+	// events have been read, the reading resumes after the last one.
+	StartAt string `mapstructure:"start_at"`
+
+	// MaxReads is the maximum number of events read at once, 100 by default.
+	MaxReads int `mapstructure:"max_reads"`
+
+	// PollInterval is the interval at which the channel is checked for new events, 1s by default.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	// OffsetsFile is the path to the file where the position of the last event read is persisted.
+	// When not set, the reading starts at start_at on each start.
+	OffsetsFile string `mapstructure:"offsets_file"`
+}
+
+func (cfg *Config) validate() error {
+	if cfg.Channel == "" {
+		return errMissingChannel
+	}
+	if cfg.StartAt != StartAtBeginning && cfg.StartAt != StartAtEnd {
+		return fmt.Errorf("invalid start_at %q, must be one of %q or %q", cfg.StartAt, StartAtBeginning, StartAtEnd)
+	}
+	if cfg.MaxReads <= 0 {
+		return fmt.Errorf("max_reads must be positive: %d", cfg.MaxReads)
+	}
+	if cfg.PollInterval <= 0 {
+		return fmt.Errorf("poll_interval must be positive: %s", cfg.PollInterval)
+	}
+	return nil
+}
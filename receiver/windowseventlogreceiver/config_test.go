@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowseventlogreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, 2, len(config.Receivers))
+
+	assert.Equal(t, factory.CreateDefaultConfig(), config.Receivers["windowseventlog"])
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: "windowseventlog",
+				NameVal: "windowseventlog/security",
+			},
+			Channel:      "Security",
+			StartAt:      StartAtBeginning,
+			MaxReads:     500,
+			PollInterval: 5 * time.Second,
+			OffsetsFile:  "./offsets.db",
+		},
+		config.Receivers["windowseventlog/security"])
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:   "missing channel",
+			modify: func(cfg *Config) { cfg.Channel = "" },
+			err:    "channel must be specified",
+		},
+		{
+			name:   "invalid start_at",
+			modify: func(cfg *Config) { cfg.StartAt = "oldest" },
+			err:    `invalid start_at "oldest", must be one of "beginning" or "end"`,
+		},
+		{
+			name:   "invalid max_reads",
+			modify: func(cfg *Config) { cfg.MaxReads = 0 },
+			err:    "max_reads must be positive: 0",
+		},
+		{
+			name:   "invalid poll_interval",
+			modify: func(cfg *Config) { cfg.PollInterval = 0 },
+			err:    "poll_interval must be positive: 0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Channel = "Application"
+			tt.modify(cfg)
+
+			err := cfg.validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
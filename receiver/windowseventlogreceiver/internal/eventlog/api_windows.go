@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build windows
+
+package eventlog
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	api = windows.NewLazySystemDLL("wevtapi.dll")
+
+	subscribeProc             = api.NewProc("EvtSubscribe")
+	nextProc                  = api.NewProc("EvtNext")
+	renderProc                = api.NewProc("EvtRender")
+	closeProc                 = api.NewProc("EvtClose")
+	createBookmarkProc        = api.NewProc("EvtCreateBookmark")
+	updateBookmarkProc        = api.NewProc("EvtUpdateBookmark")
+	openPublisherMetadataProc = api.NewProc("EvtOpenPublisherMetadata")
+	formatMessageProc         = api.NewProc("EvtFormatMessage")
+)
+
+const (
+	// EVT_SUBSCRIBE_FLAGS
+	evtSubscribeToFutureEvents      = 1
+	evtSubscribeStartAtOldestRecord = 2
+	evtSubscribeStartAfterBookmark  = 3
+
+	// EVT_RENDER_FLAGS
+	evtRenderEventXML = 1
+	evtRenderBookmark = 2
+
+	// EVT_FORMAT_MESSAGE_FLAGS
+	evtFormatMessageXML = 9
+)
+
+var (
+	errNoMoreItems        = syscall.Errno(259)
+	errInsufficientBuffer = syscall.Errno(122)
+	errInvalidOperation   = syscall.Errno(4317)
+	errEmptyRendering     = errors.New("unexpected empty rendering")
+)
+
+// evtSubscribe subscribes to the events of the given channel, the given event being signaled when new events
+// are available
+func evtSubscribe(signalEvent windows.Handle, channel string, bookmark uintptr, flags uint32) (uintptr, error) {
+	channelPtr, err := syscall.UTF16PtrFromString(channel)
+	if err != nil {
+		return 0, err
+	}
+
+	handle, _, err := subscribeProc.Call(
+		0, uintptr(signalEvent), uintptr(unsafe.Pointer(channelPtr)), 0, bookmark, 0, 0, uintptr(flags))
+	if handle == 0 {
+		return 0, err
+	}
+	return handle, nil
+}
+
+// evtNext reads up to len(events) events from the given subscription. It returns errNoMoreItems when
+// there's no event to read.
+func evtNext(subscription uintptr, events []uintptr) (int, error) {
+	var returned uint32
+	ok, _, err := nextProc.Call(
+		subscription, uintptr(len(events)), uintptr(unsafe.Pointer(&events[0])), 0, 0,
+		uintptr(unsafe.Pointer(&returned)))
+	if ok == 0 {
+		if err == errInvalidOperation {
+			// returned instead of errNoMoreItems by some channels when they don't have any event left
+			return 0, errNoMoreItems
+		}
+		return 0, err
+	}
+	return int(returned), nil
+}
+
+// evtRender renders the given event or bookmark as XML
+func evtRender(fragment uintptr, flags uint32) (string, error) {
+	var bufferUsed, propertyCount uint32
+	ok, _, err := renderProc.Call(
+		0, fragment, uintptr(flags), 0, 0,
+		uintptr(unsafe.Pointer(&bufferUsed)), uintptr(unsafe.Pointer(&propertyCount)))
+	if ok != 0 {
+		return "", errEmptyRendering
+	}
+	if err != errInsufficientBuffer {
+		return "", err
+	}
+
+	// the buffer size is in bytes
+	buffer := make([]uint16, (bufferUsed+1)/2)
+	ok, _, err = renderProc.Call(
+		0, fragment, uintptr(flags), uintptr(len(buffer)*2), uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(unsafe.Pointer(&bufferUsed)), uintptr(unsafe.Pointer(&propertyCount)))
+	if ok == 0 {
+		return "", err
+	}
+	return windows.UTF16ToString(buffer), nil
+}
+
+// evtFormatMessage renders the given event as XML, including the rendering info resolved from the metadata
+// of its publisher
+func evtFormatMessage(publisherMetadata uintptr, event uintptr) (string, error) {
+	var bufferUsed uint32
+	ok, _, err := formatMessageProc.Call(
+		publisherMetadata, event, 0, 0, 0, evtFormatMessageXML, 0, 0, uintptr(unsafe.Pointer(&bufferUsed)))
+	if ok != 0 {
+		return "", errEmptyRendering
+	}
+	if err != errInsufficientBuffer {
+		return "", err
+	}
+
+	// the buffer size is in characters
+	buffer := make([]uint16, bufferUsed)
+	ok, _, err = formatMessageProc.Call(
+		publisherMetadata, event, 0, 0, 0, evtFormatMessageXML, uintptr(len(buffer)),
+		uintptr(unsafe.Pointer(&buffer[0])), uintptr(unsafe.Pointer(&bufferUsed)))
+	if ok == 0 {
+		return "", err
+	}
+	return windows.UTF16ToString(buffer), nil
+}
+
+// evtOpenPublisherMetadata opens the metadata of the given publisher, used to format its events
+func evtOpenPublisherMetadata(publisher string) (uintptr, error) {
+	publisherPtr, err := syscall.UTF16PtrFromString(publisher)
+	if err != nil {
+		return 0, err
+	}
+
+	handle, _, err := openPublisherMetadataProc.Call(0, uintptr(unsafe.Pointer(publisherPtr)), 0, 0, 0)
+	if handle == 0 {
+		return 0, err
+	}
+	return handle, nil
+}
+
+// evtCreateBookmark creates a bookmark from its XML rendering, or an empty one when the XML is empty
+func evtCreateBookmark(bookmarkXML string) (uintptr, error) {
+	var xmlPtr *uint16
+	if bookmarkXML != "" {
+		var err error
+		if xmlPtr, err = syscall.UTF16PtrFromString(bookmarkXML); err != nil {
+			return 0, err
+		}
+	}
+
+	handle, _, err := createBookmarkProc.Call(uintptr(unsafe.Pointer(xmlPtr)))
+	if handle == 0 {
+		return 0, err
+	}
+	return handle, nil
+}
+
+// evtUpdateBookmark moves the given bookmark to the given event
+func evtUpdateBookmark(bookmark uintptr, event uintptr) error {
+	ok, _, err := updateBookmarkProc.Call(bookmark, event)
+	if ok == 0 {
+		return err
+	}
+	return nil
+}
+
+// evtClose closes the given handle, ignoring the null ones
+func evtClose(handle uintptr) error {
+	if handle == 0 {
+		return nil
+	}
+	ok, _, err := closeProc.Call(handle)
+	if ok == 0 {
+		return err
+	}
+	return nil
+}
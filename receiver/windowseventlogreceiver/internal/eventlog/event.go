@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventlog
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+)
+
+// eventXML is the XML rendering of an event, as defined by the event schema. The rendering info is only
+// present when the event is formatted with the metadata of its publisher.
+type eventXML struct {
+	Provider    provider    `xml:"System>Provider"`
+	EventID     eventID     `xml:"System>EventID"`
+	Level       string      `xml:"System>Level"`
+	Task        string      `xml:"System>Task"`
+	Opcode      string      `xml:"System>Opcode"`
+	Keywords    string      `xml:"System>Keywords"`
+	TimeCreated timeCreated `xml:"System>TimeCreated"`
+	RecordID    uint64      `xml:"System>EventRecordID"`
+	Channel     string      `xml:"System>Channel"`
+	Computer    string      `xml:"System>Computer"`
+	EventData   []data      `xml:"EventData>Data"`
+
+	RenderedLevel    string   `xml:"RenderingInfo>Level"`
+	RenderedTask     string   `xml:"RenderingInfo>Task"`
+	RenderedOpcode   string   `xml:"RenderingInfo>Opcode"`
+	RenderedKeywords []string `xml:"RenderingInfo>Keywords>Keyword"`
+	Message          string   `xml:"RenderingInfo>Message"`
+}
+
+type provider struct {
+	Name            string `xml:"Name,attr"`
+	GUID            string `xml:"Guid,attr"`
+	EventSourceName string `xml:"EventSourceName,attr"`
+}
+
+type eventID struct {
+	Qualifiers uint16 `xml:"Qualifiers,attr"`
+	ID         uint32 `xml:",chardata"`
+}
+
+type timeCreated struct {
+	SystemTime string `xml:"SystemTime,attr"`
+}
+
+type data struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:",chardata"`
+}
+
+func parseEventXML(s string) (*eventXML, error) {
+	var event eventXML
+	if err := xml.Unmarshal([]byte(s), &event); err != nil {
+		return nil, fmt.Errorf("failed to parse event XML: %w", err)
+	}
+	return &event, nil
+}
+
+// record returns the structured record of the event
+func (e *eventXML) record() map[string]interface{} {
+	record := map[string]interface{}{
+		"provider": map[string]interface{}{
+			"name":         e.Provider.Name,
+			"guid":         e.Provider.GUID,
+			"event_source": e.Provider.EventSourceName,
+		},
+		"event_id": map[string]interface{}{
+			"id":         int64(e.EventID.ID),
+			"qualifiers": int64(e.EventID.Qualifiers),
+		},
+		"system_time": e.TimeCreated.SystemTime,
+		"computer":    e.Computer,
+		"channel":     e.Channel,
+		"record_id":   int64(e.RecordID),
+		"level":       rendered(e.RenderedLevel, e.Level),
+		"task":        rendered(e.RenderedTask, e.Task),
+		"opcode":      rendered(e.RenderedOpcode, e.Opcode),
+	}
+
+	keywords := make([]interface{}, 0, len(e.RenderedKeywords))
+	for _, keyword := range e.RenderedKeywords {
+		keywords = append(keywords, keyword)
+	}
+	if len(keywords) == 0 && e.Keywords != "" {
+		keywords = append(keywords, e.Keywords)
+	}
+	record["keywords"] = keywords
+
+	if e.Message != "" {
+		record["message"] = e.Message
+	}
+	return record
+}
+
+// rendered returns the rendered name of a value when the event was formatted, its raw value otherwise
+func rendered(name string, raw string) string {
+	if name != "" {
+		return name
+	}
+	return raw
+}
+
+// severity maps the level of the event to a severity
+func (e *eventXML) severity() entry.Severity {
+	level, err := strconv.Atoi(e.Level)
+	if err != nil {
+		return entry.Default
+	}
+
+	switch level {
+	case 0:
+		// LogAlways, the level of the audit events of the Security channel
+		return entry.Info
+	case 1:
+		return entry.Critical
+	case 2:
+		return entry.Error
+	case 3:
+		return entry.Warning
+	case 4:
+		return entry.Info
+	case 5:
+		return entry.Debug
+	default:
+		return entry.Default
+	}
+}
+
+// timestamp returns the creation time of the event
+func (e *eventXML) timestamp() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, e.TimeCreated.SystemTime)
+}
+
+// eventDataLabels returns the fields of the event data, named after their names, or after their positions
+// (param1, param2...) when they're unnamed
+func (e *eventXML) eventDataLabels() map[string]string {
+	labels := make(map[string]string, len(e.EventData))
+	for i, d := range e.EventData {
+		name := d.Name
+		if name == "" {
+			name = "param" + strconv.Itoa(i+1)
+		}
+		labels[name] = d.Value
+	}
+	return labels
+}
+
+// toEntry fills the given entry with the fields of the event
+func (e *eventXML) toEntry(ent *entry.Entry) {
+	if timestamp, err := e.timestamp(); err == nil {
+		ent.Timestamp = timestamp
+	}
+	ent.Severity = e.severity()
+	ent.SeverityText = e.RenderedLevel
+	for k, v := range e.eventDataLabels() {
+		ent.AddLabel(k, v)
+	}
+}
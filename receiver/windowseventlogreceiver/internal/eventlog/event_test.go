@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const formattedEvent = `<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'>
+  <System>
+    <Provider Name='Microsoft-Windows-Security-Auditing' Guid='{54849625-5478-4994-a5ba-3e3b0328c30d}'/>
+    <EventID>4624</EventID>
+    <Version>2</Version>
+    <Level>0</Level>
+    <Task>12544</Task>
+    <Opcode>0</Opcode>
+    <Keywords>0x8020000000000000</Keywords>
+    <TimeCreated SystemTime='2021-01-12T16:10:17.6567267Z'/>
+    <EventRecordID>77642</EventRecordID>
+    <Channel>Security</Channel>
+    <Computer>WIN-SERVER</Computer>
+  </System>
+  <EventData>
+    <Data Name='SubjectUserName'>WIN-SERVER$</Data>
+    <Data Name='LogonType'>5</Data>
+  </EventData>
+  <RenderingInfo Culture='en-US'>
+    <Message>An account was successfully logged on.</Message>
+    <Level>Information</Level>
+    <Task>Logon</Task>
+    <Opcode>Info</Opcode>
+    <Keywords>
+      <Keyword>Audit Success</Keyword>
+    </Keywords>
+  </RenderingInfo>
+</Event>`
+
+const rawEvent = `<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'>
+  <System>
+    <Provider Name='Application Error' EventSourceName='Application Error'/>
+    <EventID Qualifiers='16384'>1000</EventID>
+    <Level>2</Level>
+    <Task>100</Task>
+    <Keywords>0x80000000000000</Keywords>
+    <TimeCreated SystemTime='2021-01-12T16:10:17.6567267Z'/>
+    <EventRecordID>12</EventRecordID>
+    <Channel>Application</Channel>
+    <Computer>WIN-SERVER</Computer>
+  </System>
+  <EventData>
+    <Data>app.exe</Data>
+    <Data>1.0.0</Data>
+  </EventData>
+</Event>`
+
+func TestFormattedEvent(t *testing.T) {
+	event, err := parseEventXML(formattedEvent)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"provider": map[string]interface{}{
+			"name":         "Microsoft-Windows-Security-Auditing",
+			"guid":         "{54849625-5478-4994-a5ba-3e3b0328c30d}",
+			"event_source": "",
+		},
+		"event_id": map[string]interface{}{
+			"id":         int64(4624),
+			"qualifiers": int64(0),
+		},
+		"system_time": "2021-01-12T16:10:17.6567267Z",
+		"computer":    "WIN-SERVER",
+		"channel":     "Security",
+		"record_id":   int64(77642),
+		"level":       "Information",
+		"task":        "Logon",
+		"opcode":      "Info",
+		"keywords":    []interface{}{"Audit Success"},
+		"message":     "An account was successfully logged on.",
+	}, event.record())
+
+	ent := entry.New()
+	event.toEntry(ent)
+	assert.Equal(t, time.Date(2021, 1, 12, 16, 10, 17, 656726700, time.UTC), ent.Timestamp)
+	assert.Equal(t, entry.Info, ent.Severity)
+	assert.Equal(t, "Information", ent.SeverityText)
+	assert.Equal(t, map[string]string{"SubjectUserName": "WIN-SERVER$", "LogonType": "5"}, ent.Labels)
+}
+
+func TestRawEvent(t *testing.T) {
+	event, err := parseEventXML(rawEvent)
+	require.NoError(t, err)
+
+	record := event.record()
+	assert.Equal(t, map[string]interface{}{
+		"name":         "Application Error",
+		"guid":         "",
+		"event_source": "Application Error",
+	}, record["provider"])
+	assert.Equal(t, map[string]interface{}{
+		"id":         int64(1000),
+		"qualifiers": int64(16384),
+	}, record["event_id"])
+	assert.Equal(t, "2", record["level"])
+	assert.Equal(t, "100", record["task"])
+	assert.Equal(t, "", record["opcode"])
+	assert.Equal(t, []interface{}{"0x80000000000000"}, record["keywords"])
+	assert.NotContains(t, record, "message")
+
+	ent := entry.New()
+	event.toEntry(ent)
+	assert.Equal(t, entry.Error, ent.Severity)
+	assert.Equal(t, "", ent.SeverityText)
+	assert.Equal(t, map[string]string{"param1": "app.exe", "param2": "1.0.0"}, ent.Labels)
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level    string
+		severity entry.Severity
+	}{
+		{"0", entry.Info},
+		{"1", entry.Critical},
+		{"2", entry.Error},
+		{"3", entry.Warning},
+		{"4", entry.Info},
+		{"5", entry.Debug},
+		{"16", entry.Default},
+		{"", entry.Default},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			event := &eventXML{Level: tt.level}
+			assert.Equal(t, tt.severity, event.severity())
+		})
+	}
+}
+
+func TestInvalidEvent(t *testing.T) {
+	_, err := parseEventXML("<Event>")
+	assert.Error(t, err)
+}
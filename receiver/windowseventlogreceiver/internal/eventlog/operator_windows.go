@@ -0,0 +1,301 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build windows
+
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/observiq/stanza/operator"
+	"github.com/observiq/stanza/operator/helper"
+	"go.uber.org/zap"
+	"golang.org/x/sys/windows"
+)
+
+// OperatorType is the type of the stanza operator reading the Windows Event Log.
+const OperatorType = "windows_eventlog_input"
+
+func init() {
+	operator.Register(OperatorType, func() operator.Builder { return NewInputConfig("") })
+}
+
+// NewInputConfig creates the config of a Windows Event Log input operator with the default values.
+func NewInputConfig(operatorID string) *InputConfig {
+	return &InputConfig{
+		InputConfig:  helper.NewInputConfig(operatorID, OperatorType),
+		MaxReads:     100,
+		StartAt:      "end",
+		PollInterval: helper.NewDuration(time.Second),
+	}
+}
+
+// InputConfig is the configuration of a Windows Event Log input operator.
+type InputConfig struct {
+	helper.InputConfig `yaml:",inline"`
+
+	Channel      string          `json:"channel"                 yaml:"channel"`
+	MaxReads     int             `json:"max_reads,omitempty"     yaml:"max_reads,omitempty"`
+	StartAt      string          `json:"start_at,omitempty"      yaml:"start_at,omitempty"`
+	PollInterval helper.Duration `json:"poll_interval,omitempty" yaml:"poll_interval,omitempty"`
+}
+
+// Build builds a Windows Event Log input operator from the configuration.
+func (c InputConfig) Build(buildContext operator.BuildContext) ([]operator.Operator, error) {
+	inputOperator, err := c.InputConfig.Build(buildContext)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Channel == "" {
+		return nil, fmt.Errorf("missing required parameter 'channel'")
+	}
+	if c.MaxReads < 1 {
+		return nil, fmt.Errorf("the 'max_reads' parameter must be greater than 0")
+	}
+	if c.StartAt != "end" && c.StartAt != "beginning" {
+		return nil, fmt.Errorf("invalid value '%s' for parameter 'start_at'", c.StartAt)
+	}
+
+	return []operator.Operator{&Input{
+		InputOperator: inputOperator,
+		persister:     helper.NewScopedDBPersister(buildContext.Database, c.ID()),
+		channel:       c.Channel,
+		maxReads:      c.MaxReads,
+		startAt:       c.StartAt,
+		pollInterval:  c.PollInterval.Raw(),
+		publishers:    make(map[string]uintptr),
+	}}, nil
+}
+
+// Input is an operator reading the events of a Windows Event Log channel. The position of the last event read
+// is kept in a bookmark, persisted in the offsets database, from which the reading resumes after a restart.
+type Input struct {
+	helper.InputOperator
+
+	persister    helper.Persister
+	channel      string
+	maxReads     int
+	startAt      string
+	pollInterval time.Duration
+
+	signalEvent  windows.Handle
+	subscription uintptr
+	bookmark     uintptr
+
+	// publishers caches the metadata of the publishers of the events, a null handle meaning
+	// the metadata isn't available
+	publishers map[string]uintptr
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start subscribes to the channel and starts reading its events.
+func (i *Input) Start() error {
+	if err := i.persister.Load(); err != nil {
+		return fmt.Errorf("failed to load the bookmark: %w", err)
+	}
+
+	flags := uint32(evtSubscribeToFutureEvents)
+	if i.startAt == "beginning" {
+		flags = evtSubscribeStartAtOldestRecord
+	}
+
+	var err error
+	if bookmarkXML := i.persister.Get(i.channel); len(bookmarkXML) > 0 {
+		if i.bookmark, err = evtCreateBookmark(string(bookmarkXML)); err != nil {
+			i.Warnw("Failed to restore the bookmark, reading from start_at", zap.Error(err))
+		} else {
+			flags = evtSubscribeStartAfterBookmark
+		}
+	}
+	if i.bookmark == 0 {
+		if i.bookmark, err = evtCreateBookmark(""); err != nil {
+			return fmt.Errorf("failed to create a bookmark: %w", err)
+		}
+	}
+
+	if i.signalEvent, err = windows.CreateEvent(nil, 0, 0, nil); err != nil {
+		i.closeHandles()
+		return fmt.Errorf("failed to create the signal event: %w", err)
+	}
+
+	subscriptionBookmark := uintptr(0)
+	if flags == evtSubscribeStartAfterBookmark {
+		subscriptionBookmark = i.bookmark
+	}
+	if i.subscription, err = evtSubscribe(i.signalEvent, i.channel, subscriptionBookmark, flags); err != nil {
+		i.closeHandles()
+		return fmt.Errorf("failed to subscribe to channel %q: %w", i.channel, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i.cancel = cancel
+
+	i.wg.Add(1)
+	go i.poll(ctx)
+	return nil
+}
+
+// Stop stops reading the events and closes the subscription.
+func (i *Input) Stop() error {
+	if i.cancel != nil {
+		i.cancel()
+	}
+	i.wg.Wait()
+	i.closeHandles()
+	return nil
+}
+
+func (i *Input) closeHandles() {
+	for name, publisher := range i.publishers {
+		_ = evtClose(publisher)
+		delete(i.publishers, name)
+	}
+	_ = evtClose(i.subscription)
+	i.subscription = 0
+	_ = evtClose(i.bookmark)
+	i.bookmark = 0
+	if i.signalEvent != 0 {
+		_ = windows.CloseHandle(i.signalEvent)
+		i.signalEvent = 0
+	}
+}
+
+// poll reads the available events on each poll interval
+func (i *Input) poll(ctx context.Context) {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(i.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		i.readAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readAll reads the events until none is left
+func (i *Input) readAll(ctx context.Context) {
+	events := make([]uintptr, i.maxReads)
+	for ctx.Err() == nil {
+		n, err := evtNext(i.subscription, events)
+		if err == errNoMoreItems {
+			return
+		}
+		if err != nil {
+			i.Errorw("Failed to read events", zap.Error(err))
+			return
+		}
+
+		for _, event := range events[:n] {
+			i.processEvent(ctx, event)
+			_ = evtClose(event)
+		}
+		i.saveBookmark()
+	}
+}
+
+// processEvent writes the given event as an entry, and moves the bookmark to it
+func (i *Input) processEvent(ctx context.Context, event uintptr) {
+	defer func() {
+		if err := evtUpdateBookmark(i.bookmark, event); err != nil {
+			i.Errorw("Failed to update the bookmark", zap.Error(err))
+		}
+	}()
+
+	rendered, err := i.render(event)
+	if err != nil {
+		i.Errorw("Failed to render event", zap.Error(err))
+		return
+	}
+
+	parsed, err := parseEventXML(rendered)
+	if err != nil {
+		i.Errorw("Failed to parse event", zap.Error(err))
+		return
+	}
+
+	ent, err := i.NewEntry(parsed.record())
+	if err != nil {
+		i.Errorw("Failed to create entry", zap.Error(err))
+		return
+	}
+	parsed.toEntry(ent)
+	i.Write(ctx, ent)
+}
+
+// render renders the given event as XML, formatted with the metadata of its publisher when available
+func (i *Input) render(event uintptr) (string, error) {
+	rendered, err := evtRender(event, evtRenderEventXML)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := parseEventXML(rendered)
+	if err != nil || parsed.Provider.Name == "" {
+		return rendered, nil
+	}
+
+	publisher := i.publisherMetadata(parsed.Provider.Name)
+	if publisher == 0 {
+		return rendered, nil
+	}
+	formatted, err := evtFormatMessage(publisher, event)
+	if err != nil {
+		i.Debugw("Failed to format event, using its raw rendering",
+			zap.String("provider", parsed.Provider.Name), zap.Error(err))
+		return rendered, nil
+	}
+	return formatted, nil
+}
+
+// publisherMetadata returns the cached metadata of the given publisher, opening it on first use
+func (i *Input) publisherMetadata(name string) uintptr {
+	if publisher, ok := i.publishers[name]; ok {
+		return publisher
+	}
+
+	publisher, err := evtOpenPublisherMetadata(name)
+	if err != nil {
+		i.Debugw("Failed to open publisher metadata", zap.String("provider", name), zap.Error(err))
+		publisher = 0
+	}
+	i.publishers[name] = publisher
+	return publisher
+}
+
+// saveBookmark persists the bookmark, so that the reading resumes after the last event read on restart
+func (i *Input) saveBookmark() {
+	bookmarkXML, err := evtRender(i.bookmark, evtRenderBookmark)
+	if err != nil {
+		i.Errorw("Failed to render the bookmark", zap.Error(err))
+		return
+	}
+
+	i.persister.Set(i.channel, []byte(bookmarkXML))
+	if err := i.persister.Sync(); err != nil {
+		i.Errorw("Failed to sync the bookmark", zap.Error(err))
+	}
+}